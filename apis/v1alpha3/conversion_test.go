@@ -80,6 +80,13 @@ func overrideVSphereClusterSpecFieldsFuncs(runtimeserializer.CodecFactory) []int
 			in.ClusterModules = nil
 			in.FailureDomainSelector = nil
 			in.DisableClusterModule = false
+			in.ClusterModuleLifecycle = nil
+			in.HostEvacuation = nil
+			in.Proxy = nil
+			in.TLSConfig = nil
+			in.CustomAttributes = nil
+			in.FolderNamingStrategy = nil
+			in.ControlPlaneEndpointManagement = nil
 		},
 	}
 }
@@ -89,6 +96,9 @@ func overrideVSphereClusterStatusFieldsFuncs(runtimeserializer.CodecFactory) []i
 		func(in *infrav1.VSphereClusterStatus, c fuzz.Continue) {
 			c.FuzzNoCustom(in)
 			in.VCenterVersion = ""
+			in.ResourceUsage = nil
+			in.DeprecatedFieldUsage = nil
+			in.ControlPlaneEndpoint = nil
 		},
 	}
 }
@@ -38,6 +38,7 @@ func (src *VSphereFailureDomain) ConvertTo(dstRaw conversion.Hub) error {
 	}
 
 	dst.Spec.Topology.NetworkConfigurations = restored.Spec.Topology.NetworkConfigurations
+	dst.Spec.Topology.VSAN = restored.Spec.Topology.VSAN
 
 	return nil
 }
@@ -43,13 +43,36 @@ func (src *VSphereMachineTemplate) ConvertTo(dstRaw conversion.Hub) error {
 	dst.Spec.Template.Spec.AdditionalDisksGiB = restored.Spec.Template.Spec.AdditionalDisksGiB
 	dst.Spec.Template.Spec.PowerOffMode = restored.Spec.Template.Spec.PowerOffMode
 	dst.Spec.Template.Spec.GuestSoftPowerOffTimeout = restored.Spec.Template.Spec.GuestSoftPowerOffTimeout
+	dst.Spec.Template.Spec.GuestReadinessGates = restored.Spec.Template.Spec.GuestReadinessGates
+	dst.Spec.Template.Spec.Encryption = restored.Spec.Template.Spec.Encryption
+	dst.Spec.Template.Spec.Network.NTPServers = restored.Spec.Template.Spec.Network.NTPServers
+	dst.Spec.Template.Spec.Network.Proxy = restored.Spec.Template.Spec.Network.Proxy
 	for i := range dst.Spec.Template.Spec.Network.Devices {
 		dst.Spec.Template.Spec.Network.Devices[i].AddressesFromPools = restored.Spec.Template.Spec.Network.Devices[i].AddressesFromPools
 		dst.Spec.Template.Spec.Network.Devices[i].DHCP4Overrides = restored.Spec.Template.Spec.Network.Devices[i].DHCP4Overrides
 		dst.Spec.Template.Spec.Network.Devices[i].DHCP6Overrides = restored.Spec.Template.Spec.Network.Devices[i].DHCP6Overrides
 		dst.Spec.Template.Spec.Network.Devices[i].SkipIPAllocation = restored.Spec.Template.Spec.Network.Devices[i].SkipIPAllocation
+		dst.Spec.Template.Spec.Network.Devices[i].Bond = restored.Spec.Template.Spec.Network.Devices[i].Bond
+		dst.Spec.Template.Spec.Network.Devices[i].VLANs = restored.Spec.Template.Spec.Network.Devices[i].VLANs
+		dst.Spec.Template.Spec.Network.Devices[i].SRIOV = restored.Spec.Template.Spec.Network.Devices[i].SRIOV
+		dst.Spec.Template.Spec.Network.Devices[i].AddressFromNetworkProtocolProfile = restored.Spec.Template.Spec.Network.Devices[i].AddressFromNetworkProtocolProfile
 	}
 	dst.Spec.Template.Spec.DataDisks = restored.Spec.Template.Spec.DataDisks
+	dst.Spec.Template.Spec.CloudInitCustomizationRef = restored.Spec.Template.Spec.CloudInitCustomizationRef
+	dst.Spec.Template.Spec.VAppConfig = restored.Spec.Template.Spec.VAppConfig
+	dst.Spec.Template.Spec.DriftPolicy = restored.Spec.Template.Spec.DriftPolicy
+	dst.Spec.Template.Spec.CustomAttributes = restored.Spec.Template.Spec.CustomAttributes
+	dst.Spec.Template.Spec.AllowInPlaceResize = restored.Spec.Template.Spec.AllowInPlaceResize
+	dst.Spec.Template.Spec.QuestionAnswers = restored.Spec.Template.Spec.QuestionAnswers
+	dst.Spec.Template.Spec.NetworkDeviceHotPlugPolicy = restored.Spec.Template.Spec.NetworkDeviceHotPlugPolicy
+	dst.Spec.Template.Spec.BootstrapDataDelivery = restored.Spec.Template.Spec.BootstrapDataDelivery
+	dst.Spec.Template.Spec.GuestCrashDetection = restored.Spec.Template.Spec.GuestCrashDetection
+	dst.Spec.Template.Spec.ChildResourcePool = restored.Spec.Template.Spec.ChildResourcePool
+	dst.Spec.Template.Spec.Resources = restored.Spec.Template.Spec.Resources
+	dst.Spec.Template.Spec.NamingStrategy = restored.Spec.Template.Spec.NamingStrategy
+	dst.Spec.Template.Spec.TemplateSource = restored.Spec.Template.Spec.TemplateSource
+	dst.Spec.Template.Spec.ImageRef = restored.Spec.Template.Spec.ImageRef
+	dst.Status = restored.Status
 
 	return nil
 }
@@ -78,6 +101,10 @@ func (dst *VSphereMachineTemplateList) ConvertFrom(srcRaw conversion.Hub) error
 	return Convert_v1beta1_VSphereMachineTemplateList_To_v1alpha3_VSphereMachineTemplateList(src, dst, nil)
 }
 
+func Convert_v1beta1_VSphereMachineTemplate_To_v1alpha3_VSphereMachineTemplate(in *infrav1.VSphereMachineTemplate, out *VSphereMachineTemplate, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_VSphereMachineTemplate_To_v1alpha3_VSphereMachineTemplate(in, out, s)
+}
+
 func Convert_v1alpha3_ObjectMeta_To_v1beta1_ObjectMeta(in *ObjectMeta, out *clusterv1.ObjectMeta, s apiconversion.Scope) error {
 	// wrapping the conversion func to avoid having compile errors due to compileErrorOnMissingConversion()
 	// more details at https://github.com/kubernetes/kubernetes/issues/98380
@@ -39,14 +39,39 @@ func (src *VSphereVM) ConvertTo(dstRaw conversion.Hub) error {
 	dst.Spec.AdditionalDisksGiB = restored.Spec.AdditionalDisksGiB
 	dst.Spec.PowerOffMode = restored.Spec.PowerOffMode
 	dst.Spec.GuestSoftPowerOffTimeout = restored.Spec.GuestSoftPowerOffTimeout
+	dst.Spec.GuestReadinessGates = restored.Spec.GuestReadinessGates
+	dst.Spec.Encryption = restored.Spec.Encryption
 	dst.Status.Host = restored.Status.Host
+	dst.Status.InstanceUUID = restored.Status.InstanceUUID
+	dst.Spec.Network.NTPServers = restored.Spec.Network.NTPServers
+	dst.Spec.Network.Proxy = restored.Spec.Network.Proxy
 	for i := range dst.Spec.Network.Devices {
 		dst.Spec.Network.Devices[i].AddressesFromPools = restored.Spec.Network.Devices[i].AddressesFromPools
 		dst.Spec.Network.Devices[i].DHCP4Overrides = restored.Spec.Network.Devices[i].DHCP4Overrides
 		dst.Spec.Network.Devices[i].DHCP6Overrides = restored.Spec.Network.Devices[i].DHCP6Overrides
 		dst.Spec.Network.Devices[i].SkipIPAllocation = restored.Spec.Network.Devices[i].SkipIPAllocation
+		dst.Spec.Network.Devices[i].Bond = restored.Spec.Network.Devices[i].Bond
+		dst.Spec.Network.Devices[i].VLANs = restored.Spec.Network.Devices[i].VLANs
+		dst.Spec.Network.Devices[i].SRIOV = restored.Spec.Network.Devices[i].SRIOV
+		dst.Spec.Network.Devices[i].AddressFromNetworkProtocolProfile = restored.Spec.Network.Devices[i].AddressFromNetworkProtocolProfile
 	}
 	dst.Spec.DataDisks = restored.Spec.DataDisks
+	dst.Spec.CloudInitCustomizationRef = restored.Spec.CloudInitCustomizationRef
+	dst.Spec.VAppConfig = restored.Spec.VAppConfig
+	dst.Spec.DriftPolicy = restored.Spec.DriftPolicy
+	dst.Spec.CustomAttributes = restored.Spec.CustomAttributes
+	dst.Spec.AllowInPlaceResize = restored.Spec.AllowInPlaceResize
+	dst.Spec.QuestionAnswers = restored.Spec.QuestionAnswers
+	dst.Spec.NetworkDeviceHotPlugPolicy = restored.Spec.NetworkDeviceHotPlugPolicy
+	dst.Spec.BootstrapDataDelivery = restored.Spec.BootstrapDataDelivery
+	dst.Spec.GuestCrashDetection = restored.Spec.GuestCrashDetection
+	dst.Spec.ChildResourcePool = restored.Spec.ChildResourcePool
+	dst.Spec.Resources = restored.Spec.Resources
+	dst.Spec.TemplateSource = restored.Spec.TemplateSource
+	dst.Spec.ImageRef = restored.Spec.ImageRef
+	dst.Spec.RelocateTo = restored.Spec.RelocateTo
+	dst.Spec.PowerState = restored.Spec.PowerState
+	dst.Status.Relocation = restored.Status.Relocation
 
 	return nil
 }
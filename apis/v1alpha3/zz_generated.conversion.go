@@ -110,11 +110,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.NetworkSpec)(nil), (*NetworkSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_NetworkSpec_To_v1alpha3_NetworkSpec(a.(*v1beta1.NetworkSpec), b.(*NetworkSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*NetworkStatus)(nil), (*v1beta1.NetworkStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_NetworkStatus_To_v1beta1_NetworkStatus(a.(*NetworkStatus), b.(*v1beta1.NetworkStatus), scope)
 	}); err != nil {
@@ -190,11 +185,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.VSphereClusterIdentitySpec)(nil), (*VSphereClusterIdentitySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_VSphereClusterIdentitySpec_To_v1alpha3_VSphereClusterIdentitySpec(a.(*v1beta1.VSphereClusterIdentitySpec), b.(*VSphereClusterIdentitySpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*VSphereClusterIdentityStatus)(nil), (*v1beta1.VSphereClusterIdentityStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_VSphereClusterIdentityStatus_To_v1beta1_VSphereClusterIdentityStatus(a.(*VSphereClusterIdentityStatus), b.(*v1beta1.VSphereClusterIdentityStatus), scope)
 	}); err != nil {
@@ -330,21 +320,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.VSphereMachineStatus)(nil), (*VSphereMachineStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_VSphereMachineStatus_To_v1alpha3_VSphereMachineStatus(a.(*v1beta1.VSphereMachineStatus), b.(*VSphereMachineStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*VSphereMachineTemplate)(nil), (*v1beta1.VSphereMachineTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_VSphereMachineTemplate_To_v1beta1_VSphereMachineTemplate(a.(*VSphereMachineTemplate), b.(*v1beta1.VSphereMachineTemplate), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.VSphereMachineTemplate)(nil), (*VSphereMachineTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_VSphereMachineTemplate_To_v1alpha3_VSphereMachineTemplate(a.(*v1beta1.VSphereMachineTemplate), b.(*VSphereMachineTemplate), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*VSphereMachineTemplateList)(nil), (*v1beta1.VSphereMachineTemplateList)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_VSphereMachineTemplateList_To_v1beta1_VSphereMachineTemplateList(a.(*VSphereMachineTemplateList), b.(*v1beta1.VSphereMachineTemplateList), scope)
 	}); err != nil {
@@ -410,11 +390,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.VirtualMachine)(nil), (*VirtualMachine)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_VirtualMachine_To_v1alpha3_VirtualMachine(a.(*v1beta1.VirtualMachine), b.(*VirtualMachine), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*VirtualMachineCloneSpec)(nil), (*v1beta1.VirtualMachineCloneSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_VirtualMachineCloneSpec_To_v1beta1_VirtualMachineCloneSpec(a.(*VirtualMachineCloneSpec), b.(*v1beta1.VirtualMachineCloneSpec), scope)
 	}); err != nil {
@@ -450,6 +425,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta1.NetworkSpec)(nil), (*NetworkSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NetworkSpec_To_v1alpha3_NetworkSpec(a.(*v1beta1.NetworkSpec), b.(*NetworkSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*apiv1beta1.ObjectMeta)(nil), (*ObjectMeta)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_ObjectMeta_To_v1alpha3_ObjectMeta(a.(*apiv1beta1.ObjectMeta), b.(*ObjectMeta), scope)
 	}); err != nil {
@@ -460,6 +440,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta1.VSphereClusterIdentitySpec)(nil), (*VSphereClusterIdentitySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_VSphereClusterIdentitySpec_To_v1alpha3_VSphereClusterIdentitySpec(a.(*v1beta1.VSphereClusterIdentitySpec), b.(*VSphereClusterIdentitySpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta1.VSphereClusterSpec)(nil), (*VSphereClusterSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_VSphereClusterSpec_To_v1alpha3_VSphereClusterSpec(a.(*v1beta1.VSphereClusterSpec), b.(*VSphereClusterSpec), scope)
 	}); err != nil {
@@ -475,6 +460,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta1.VSphereMachineStatus)(nil), (*VSphereMachineStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_VSphereMachineStatus_To_v1alpha3_VSphereMachineStatus(a.(*v1beta1.VSphereMachineStatus), b.(*VSphereMachineStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta1.VSphereMachineTemplate)(nil), (*VSphereMachineTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_VSphereMachineTemplate_To_v1alpha3_VSphereMachineTemplate(a.(*v1beta1.VSphereMachineTemplate), b.(*VSphereMachineTemplate), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta1.VSphereVMSpec)(nil), (*VSphereVMSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_VSphereVMSpec_To_v1alpha3_VSphereVMSpec(a.(*v1beta1.VSphereVMSpec), b.(*VSphereVMSpec), scope)
 	}); err != nil {
@@ -490,6 +485,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta1.VirtualMachine)(nil), (*VirtualMachine)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_VirtualMachine_To_v1alpha3_VirtualMachine(a.(*v1beta1.VirtualMachine), b.(*VirtualMachine), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -665,6 +665,10 @@ func autoConvert_v1beta1_NetworkDeviceSpec_To_v1alpha3_NetworkDeviceSpec(in *v1b
 	// WARNING: in.DHCP4Overrides requires manual conversion: does not exist in peer-type
 	// WARNING: in.DHCP6Overrides requires manual conversion: does not exist in peer-type
 	// WARNING: in.SkipIPAllocation requires manual conversion: does not exist in peer-type
+	// WARNING: in.Bond requires manual conversion: does not exist in peer-type
+	// WARNING: in.VLANs requires manual conversion: does not exist in peer-type
+	// WARNING: in.SRIOV requires manual conversion: does not exist in peer-type
+	// WARNING: in.AddressFromNetworkProtocolProfile requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -728,14 +732,11 @@ func autoConvert_v1beta1_NetworkSpec_To_v1alpha3_NetworkSpec(in *v1beta1.Network
 	}
 	out.Routes = *(*[]NetworkRouteSpec)(unsafe.Pointer(&in.Routes))
 	out.PreferredAPIServerCIDR = in.PreferredAPIServerCIDR
+	// WARNING: in.NTPServers requires manual conversion: does not exist in peer-type
+	// WARNING: in.Proxy requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_NetworkSpec_To_v1alpha3_NetworkSpec is an autogenerated conversion function.
-func Convert_v1beta1_NetworkSpec_To_v1alpha3_NetworkSpec(in *v1beta1.NetworkSpec, out *NetworkSpec, s conversion.Scope) error {
-	return autoConvert_v1beta1_NetworkSpec_To_v1alpha3_NetworkSpec(in, out, s)
-}
-
 func autoConvert_v1alpha3_NetworkStatus_To_v1beta1_NetworkStatus(in *NetworkStatus, out *v1beta1.NetworkStatus, s conversion.Scope) error {
 	out.Connected = in.Connected
 	out.IPAddrs = *(*[]string)(unsafe.Pointer(&in.IPAddrs))
@@ -861,6 +862,7 @@ func autoConvert_v1beta1_Topology_To_v1alpha3_Topology(in *v1beta1.Topology, out
 	out.Networks = *(*[]string)(unsafe.Pointer(&in.Networks))
 	// WARNING: in.NetworkConfigurations requires manual conversion: does not exist in peer-type
 	out.Datastore = in.Datastore
+	// WARNING: in.VSAN requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -930,7 +932,17 @@ func Convert_v1beta1_VSphereClusterIdentity_To_v1alpha3_VSphereClusterIdentity(i
 
 func autoConvert_v1alpha3_VSphereClusterIdentityList_To_v1beta1_VSphereClusterIdentityList(in *VSphereClusterIdentityList, out *v1beta1.VSphereClusterIdentityList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]v1beta1.VSphereClusterIdentity)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1beta1.VSphereClusterIdentity, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha3_VSphereClusterIdentity_To_v1beta1_VSphereClusterIdentity(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -941,7 +953,17 @@ func Convert_v1alpha3_VSphereClusterIdentityList_To_v1beta1_VSphereClusterIdenti
 
 func autoConvert_v1beta1_VSphereClusterIdentityList_To_v1alpha3_VSphereClusterIdentityList(in *v1beta1.VSphereClusterIdentityList, out *VSphereClusterIdentityList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]VSphereClusterIdentity)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VSphereClusterIdentity, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta1_VSphereClusterIdentity_To_v1alpha3_VSphereClusterIdentity(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -963,15 +985,13 @@ func Convert_v1alpha3_VSphereClusterIdentitySpec_To_v1beta1_VSphereClusterIdenti
 
 func autoConvert_v1beta1_VSphereClusterIdentitySpec_To_v1alpha3_VSphereClusterIdentitySpec(in *v1beta1.VSphereClusterIdentitySpec, out *VSphereClusterIdentitySpec, s conversion.Scope) error {
 	out.SecretName = in.SecretName
+	// WARNING: in.TokenExchange requires manual conversion: does not exist in peer-type
 	out.AllowedNamespaces = (*AllowedNamespaces)(unsafe.Pointer(in.AllowedNamespaces))
+	// WARNING: in.AllowedTargets requires manual conversion: does not exist in peer-type
+	// WARNING: in.TLSConfig requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_VSphereClusterIdentitySpec_To_v1alpha3_VSphereClusterIdentitySpec is an autogenerated conversion function.
-func Convert_v1beta1_VSphereClusterIdentitySpec_To_v1alpha3_VSphereClusterIdentitySpec(in *v1beta1.VSphereClusterIdentitySpec, out *VSphereClusterIdentitySpec, s conversion.Scope) error {
-	return autoConvert_v1beta1_VSphereClusterIdentitySpec_To_v1alpha3_VSphereClusterIdentitySpec(in, out, s)
-}
-
 func autoConvert_v1alpha3_VSphereClusterIdentityStatus_To_v1beta1_VSphereClusterIdentityStatus(in *VSphereClusterIdentityStatus, out *v1beta1.VSphereClusterIdentityStatus, s conversion.Scope) error {
 	out.Ready = in.Ready
 	out.Conditions = *(*apiv1beta1.Conditions)(unsafe.Pointer(&in.Conditions))
@@ -1058,7 +1078,14 @@ func autoConvert_v1beta1_VSphereClusterSpec_To_v1alpha3_VSphereClusterSpec(in *v
 	out.IdentityRef = (*VSphereIdentityReference)(unsafe.Pointer(in.IdentityRef))
 	// WARNING: in.ClusterModules requires manual conversion: does not exist in peer-type
 	// WARNING: in.DisableClusterModule requires manual conversion: does not exist in peer-type
+	// WARNING: in.ClusterModuleLifecycle requires manual conversion: does not exist in peer-type
 	// WARNING: in.FailureDomainSelector requires manual conversion: does not exist in peer-type
+	// WARNING: in.HostEvacuation requires manual conversion: does not exist in peer-type
+	// WARNING: in.Proxy requires manual conversion: does not exist in peer-type
+	// WARNING: in.TLSConfig requires manual conversion: does not exist in peer-type
+	// WARNING: in.CustomAttributes requires manual conversion: does not exist in peer-type
+	// WARNING: in.FolderNamingStrategy requires manual conversion: does not exist in peer-type
+	// WARNING: in.ControlPlaneEndpointManagement requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -1079,6 +1106,9 @@ func autoConvert_v1beta1_VSphereClusterStatus_To_v1alpha3_VSphereClusterStatus(i
 	out.Conditions = *(*Conditions)(unsafe.Pointer(&in.Conditions))
 	out.FailureDomains = *(*FailureDomains)(unsafe.Pointer(&in.FailureDomains))
 	// WARNING: in.VCenterVersion requires manual conversion: does not exist in peer-type
+	// WARNING: in.ResourceUsage requires manual conversion: does not exist in peer-type
+	// WARNING: in.ControlPlaneEndpoint requires manual conversion: does not exist in peer-type
+	// WARNING: in.DeprecatedFieldUsage requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -1410,6 +1440,8 @@ func autoConvert_v1beta1_VSphereMachineSpec_To_v1alpha3_VSphereMachineSpec(in *v
 	out.FailureDomain = (*string)(unsafe.Pointer(in.FailureDomain))
 	// WARNING: in.PowerOffMode requires manual conversion: does not exist in peer-type
 	// WARNING: in.GuestSoftPowerOffTimeout requires manual conversion: does not exist in peer-type
+	// WARNING: in.GuestReadinessGates requires manual conversion: does not exist in peer-type
+	// WARNING: in.NamingStrategy requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -1432,17 +1464,13 @@ func autoConvert_v1beta1_VSphereMachineStatus_To_v1alpha3_VSphereMachineStatus(i
 	out.Ready = in.Ready
 	out.Addresses = *(*[]MachineAddress)(unsafe.Pointer(&in.Addresses))
 	out.Network = *(*[]NetworkStatus)(unsafe.Pointer(&in.Network))
+	// WARNING: in.FailureDomain requires manual conversion: does not exist in peer-type
 	out.FailureReason = (*errors.MachineStatusError)(unsafe.Pointer(in.FailureReason))
 	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
 	out.Conditions = *(*Conditions)(unsafe.Pointer(&in.Conditions))
 	return nil
 }
 
-// Convert_v1beta1_VSphereMachineStatus_To_v1alpha3_VSphereMachineStatus is an autogenerated conversion function.
-func Convert_v1beta1_VSphereMachineStatus_To_v1alpha3_VSphereMachineStatus(in *v1beta1.VSphereMachineStatus, out *VSphereMachineStatus, s conversion.Scope) error {
-	return autoConvert_v1beta1_VSphereMachineStatus_To_v1alpha3_VSphereMachineStatus(in, out, s)
-}
-
 func autoConvert_v1alpha3_VSphereMachineTemplate_To_v1beta1_VSphereMachineTemplate(in *VSphereMachineTemplate, out *v1beta1.VSphereMachineTemplate, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha3_VSphereMachineTemplateSpec_To_v1beta1_VSphereMachineTemplateSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -1461,14 +1489,10 @@ func autoConvert_v1beta1_VSphereMachineTemplate_To_v1alpha3_VSphereMachineTempla
 	if err := Convert_v1beta1_VSphereMachineTemplateSpec_To_v1alpha3_VSphereMachineTemplateSpec(&in.Spec, &out.Spec, s); err != nil {
 		return err
 	}
+	// WARNING: in.Status requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_VSphereMachineTemplate_To_v1alpha3_VSphereMachineTemplate is an autogenerated conversion function.
-func Convert_v1beta1_VSphereMachineTemplate_To_v1alpha3_VSphereMachineTemplate(in *v1beta1.VSphereMachineTemplate, out *VSphereMachineTemplate, s conversion.Scope) error {
-	return autoConvert_v1beta1_VSphereMachineTemplate_To_v1alpha3_VSphereMachineTemplate(in, out, s)
-}
-
 func autoConvert_v1alpha3_VSphereMachineTemplateList_To_v1beta1_VSphereMachineTemplateList(in *VSphereMachineTemplateList, out *v1beta1.VSphereMachineTemplateList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
@@ -1661,6 +1685,9 @@ func autoConvert_v1beta1_VSphereVMSpec_To_v1alpha3_VSphereVMSpec(in *v1beta1.VSp
 	out.BiosUUID = in.BiosUUID
 	// WARNING: in.PowerOffMode requires manual conversion: does not exist in peer-type
 	// WARNING: in.GuestSoftPowerOffTimeout requires manual conversion: does not exist in peer-type
+	// WARNING: in.GuestReadinessGates requires manual conversion: does not exist in peer-type
+	// WARNING: in.RelocateTo requires manual conversion: does not exist in peer-type
+	// WARNING: in.PowerState requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -1697,6 +1724,8 @@ func autoConvert_v1beta1_VSphereVMStatus_To_v1alpha3_VSphereVMStatus(in *v1beta1
 	out.Conditions = *(*Conditions)(unsafe.Pointer(&in.Conditions))
 	// WARNING: in.ModuleUUID requires manual conversion: does not exist in peer-type
 	// WARNING: in.VMRef requires manual conversion: does not exist in peer-type
+	// WARNING: in.InstanceUUID requires manual conversion: does not exist in peer-type
+	// WARNING: in.Relocation requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -1717,17 +1746,13 @@ func Convert_v1alpha3_VirtualMachine_To_v1beta1_VirtualMachine(in *VirtualMachin
 func autoConvert_v1beta1_VirtualMachine_To_v1alpha3_VirtualMachine(in *v1beta1.VirtualMachine, out *VirtualMachine, s conversion.Scope) error {
 	out.Name = in.Name
 	out.BiosUUID = in.BiosUUID
+	// WARNING: in.InstanceUUID requires manual conversion: does not exist in peer-type
 	out.State = VirtualMachineState(in.State)
 	out.Network = *(*[]NetworkStatus)(unsafe.Pointer(&in.Network))
 	out.VMRef = in.VMRef
 	return nil
 }
 
-// Convert_v1beta1_VirtualMachine_To_v1alpha3_VirtualMachine is an autogenerated conversion function.
-func Convert_v1beta1_VirtualMachine_To_v1alpha3_VirtualMachine(in *v1beta1.VirtualMachine, out *VirtualMachine, s conversion.Scope) error {
-	return autoConvert_v1beta1_VirtualMachine_To_v1alpha3_VirtualMachine(in, out, s)
-}
-
 func autoConvert_v1alpha3_VirtualMachineCloneSpec_To_v1beta1_VirtualMachineCloneSpec(in *VirtualMachineCloneSpec, out *v1beta1.VirtualMachineCloneSpec, s conversion.Scope) error {
 	out.Template = in.Template
 	out.CloneMode = v1beta1.CloneMode(in.CloneMode)
@@ -1757,6 +1782,8 @@ func Convert_v1alpha3_VirtualMachineCloneSpec_To_v1beta1_VirtualMachineCloneSpec
 
 func autoConvert_v1beta1_VirtualMachineCloneSpec_To_v1alpha3_VirtualMachineCloneSpec(in *v1beta1.VirtualMachineCloneSpec, out *VirtualMachineCloneSpec, s conversion.Scope) error {
 	out.Template = in.Template
+	// WARNING: in.ImageRef requires manual conversion: does not exist in peer-type
+	// WARNING: in.TemplateSource requires manual conversion: does not exist in peer-type
 	out.CloneMode = CloneMode(in.CloneMode)
 	out.Snapshot = in.Snapshot
 	out.Server = in.Server
@@ -1765,13 +1792,16 @@ func autoConvert_v1beta1_VirtualMachineCloneSpec_To_v1alpha3_VirtualMachineClone
 	out.Folder = in.Folder
 	out.Datastore = in.Datastore
 	out.StoragePolicyName = in.StoragePolicyName
+	// WARNING: in.Encryption requires manual conversion: does not exist in peer-type
 	out.ResourcePool = in.ResourcePool
+	// WARNING: in.ChildResourcePool requires manual conversion: does not exist in peer-type
 	if err := Convert_v1beta1_NetworkSpec_To_v1alpha3_NetworkSpec(&in.Network, &out.Network, s); err != nil {
 		return err
 	}
 	out.NumCPUs = in.NumCPUs
 	out.NumCoresPerSocket = in.NumCoresPerSocket
 	out.MemoryMiB = in.MemoryMiB
+	// WARNING: in.Resources requires manual conversion: does not exist in peer-type
 	out.DiskGiB = in.DiskGiB
 	// WARNING: in.AdditionalDisksGiB requires manual conversion: does not exist in peer-type
 	out.CustomVMXKeys = *(*map[string]string)(unsafe.Pointer(&in.CustomVMXKeys))
@@ -1780,5 +1810,14 @@ func autoConvert_v1beta1_VirtualMachineCloneSpec_To_v1alpha3_VirtualMachineClone
 	// WARNING: in.OS requires manual conversion: does not exist in peer-type
 	// WARNING: in.HardwareVersion requires manual conversion: does not exist in peer-type
 	// WARNING: in.DataDisks requires manual conversion: does not exist in peer-type
+	// WARNING: in.CloudInitCustomizationRef requires manual conversion: does not exist in peer-type
+	// WARNING: in.VAppConfig requires manual conversion: does not exist in peer-type
+	// WARNING: in.DriftPolicy requires manual conversion: does not exist in peer-type
+	// WARNING: in.CustomAttributes requires manual conversion: does not exist in peer-type
+	// WARNING: in.AllowInPlaceResize requires manual conversion: does not exist in peer-type
+	// WARNING: in.QuestionAnswers requires manual conversion: does not exist in peer-type
+	// WARNING: in.NetworkDeviceHotPlugPolicy requires manual conversion: does not exist in peer-type
+	// WARNING: in.BootstrapDataDelivery requires manual conversion: does not exist in peer-type
+	// WARNING: in.GuestCrashDetection requires manual conversion: does not exist in peer-type
 	return nil
 }
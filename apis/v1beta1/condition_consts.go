@@ -83,15 +83,80 @@ const (
 	// are automatically re-tried by the controller.
 	PoweringOnFailedReason = "PoweringOnFailed"
 
+	// PoweringOffReason documents (Severity=Info) a VSphereMachine/VSphereVM currently executing the power off
+	// or suspend sequence requested by the power state of the VSphereVM.
+	PoweringOffReason = "PoweringOff"
+
+	// PoweredOffReason (Severity=Info) documents a VSphereMachine/VSphereVM whose VM is intentionally
+	// powered off, as requested by the power state of the VSphereVM.
+	PoweredOffReason = "PoweredOff"
+
+	// SuspendedReason (Severity=Info) documents a VSphereMachine/VSphereVM whose VM is intentionally
+	// suspended, as requested by the power state of the VSphereVM.
+	SuspendedReason = "Suspended"
+
 	// NotFoundByBIOSUUIDReason (Severity=Warning) documents a VSphereVM which can't be found by BIOS UUID.
 	// Those kind of errors could be transient sometimes and failed VSphereVM are automatically
 	// reconciled by the controller.
 	NotFoundByBIOSUUIDReason = "NotFoundByBIOSUUID"
 
+	// VMToAdoptNotFoundReason (Severity=Warning) documents a VSphereVM with the AdoptVMAnnotation or AdoptVMInstanceUUIDAnnotation
+	// whose pre-existing VM can't be found. The VSphereVM is not cloned instead.
+	VMToAdoptNotFoundReason = "VMToAdoptNotFound"
+
+	// InfrastructurePausedReason (Severity=Info) documents a VSphereMachine/VSphereVM whose vSphere
+	// mutating operations are halted because the infrastructure is paused via the
+	// PausedInfraAnnotation.
+	InfrastructurePausedReason = "InfrastructurePaused"
+
+	// DryRunReason (Severity=Info) documents a VSphereMachine/VSphereVM whose vSphere
+	// mutating operations are logged instead of being executed because of the dry-run
+	// mode of the controller manager or the DryRunAnnotation.
+	DryRunReason = "DryRun"
+
+	// WaitingForClusterTeardownReason (Severity=Info) documents a VSphereVM of a deleted cluster whose VM
+	// is not destroyed yet, because the worker VMs of the cluster are destroyed first or because the
+	// number of VMs destroyed at the same time is limited.
+	WaitingForClusterTeardownReason = "WaitingForClusterTeardown"
+
+	// InsufficientResourcesReason (Severity=Warning) documents a VSphereMachine/VSphereVM operation failing
+	// because vCenter reported insufficient CPU, memory, storage or host capacity.
+	InsufficientResourcesReason = "InsufficientResources"
+
+	// DuplicateNameReason (Severity=Warning) documents a VSphereMachine/VSphereVM operation failing
+	// because an object with the same name already exists in vCenter.
+	DuplicateNameReason = "DuplicateName"
+
+	// InvalidLoginReason (Severity=Warning) documents a VSphereMachine/VSphereVM operation failing
+	// because vCenter rejected the credentials.
+	InvalidLoginReason = "InvalidLogin"
+
+	// NoPermissionReason (Severity=Warning) documents a VSphereMachine/VSphereVM operation failing
+	// because the vCenter user lacks a required privilege.
+	NoPermissionReason = "NoPermission"
+
+	// InvalidStateReason (Severity=Warning) documents a VSphereMachine/VSphereVM operation failing
+	// because the VM or another vCenter object is not in a state which allows the operation.
+	InvalidStateReason = "InvalidState"
+
+	// VMQuestionPendingReason (Severity=Warning) documents a VSphereMachine/VSphereVM whose VM is blocked
+	// on a question asked by vCenter which is not answered by the questionAnswers of its spec; a user
+	// intervention is required to answer the question in vCenter.
+	VMQuestionPendingReason = "VMQuestionPending"
+
+	// DatastoreOutOfSpaceReason (Severity=Warning) documents a VSphereMachine/VSphereVM whose VM is blocked
+	// because a datastore it is using ran out of space.
+	DatastoreOutOfSpaceReason = "DatastoreOutOfSpace"
+
 	// TaskFailure (Severity=Warning) documents a VSphereMachine/VSphere task failure; the reconcile look will automatically
 	// retry the operation, but a user intervention might be required to fix the problem.
 	TaskFailure = "TaskFailure"
 
+	// TaskTimedOutReason (Severity=Warning) documents a VSphereVM whose clone or reconfigure task did not
+	// complete within the VM task timeout of the controller manager; the task is canceled and the
+	// operation is automatically re-tried by the controller.
+	TaskTimedOutReason = "TaskTimedOut"
+
 	// WaitingForNetworkAddressesReason (Severity=Info) documents a VSphereMachine waiting for the machine network
 	// settings to be reported after machine being powered on.
 	//
@@ -101,6 +166,10 @@ const (
 	// TagsAttachmentFailedReason (Severity=Error) documents a VSphereMachine/VSphereVM tags attachment failure.
 	TagsAttachmentFailedReason = "TagsAttachmentFailed"
 
+	// CustomAttributesSyncFailedReason (Severity=Error) documents a VSphereMachine/VSphereVM failure
+	// to set the custom attributes of the virtual machine.
+	CustomAttributesSyncFailedReason = "CustomAttributesSyncFailed"
+
 	// PCIDevicesDetachedCondition documents the status of the attached PCI devices on the VSphereVM.
 	// It is a negative condition to notify the user that the device(s) is no longer attached to
 	// the underlying VM and would require manual intervention to fix the situation.
@@ -123,6 +192,11 @@ const (
 	// VCenterUnreachableReason (Severity=Error) documents a controller detecting
 	// issues with VCenter reachability.
 	VCenterUnreachableReason = "VCenterUnreachable"
+
+	// ForceDeletedReason (Severity=Warning) documents a deleted VSphereVM or VSphereCluster whose finalizer
+	// is removed without cleaning up vSphere, as allowed by a VSphereForceDeletePolicy, because vCenter
+	// has been unreachable for longer than the timeout of the policy.
+	ForceDeletedReason = "ForceDeleted"
 )
 
 const (
@@ -141,6 +215,34 @@ const (
 	// issues when setting up anti-affinity constraints via cluster modules for objects
 	// belonging to the cluster.
 	ClusterModuleSetupFailedReason = "ClusterModuleSetupFailed"
+
+	// ClusterModuleMembershipFailedReason (Severity=Warning) documents a controller detecting
+	// issues when adding the existing VMs of the cluster to their cluster modules.
+	ClusterModuleMembershipFailedReason = "ClusterModuleMembershipFailed"
+)
+
+const (
+	// ControlPlaneEndpointReadyCondition documents the allocation of the control plane endpoint
+	// managed by CAPV for the VSphereCluster object.
+	ControlPlaneEndpointReadyCondition clusterv1.ConditionType = "ControlPlaneEndpointReady"
+
+	// ControlPlaneEndpointAllocationFailedReason (Severity=Error) documents a controller detecting
+	// issues when allocating the IP address of the control plane endpoint, or an allocated IP address
+	// which does not match the control plane endpoint already set.
+	ControlPlaneEndpointAllocationFailedReason = "ControlPlaneEndpointAllocationFailed"
+)
+
+const (
+	// VSphereClusterInfraHealthyCondition documents whether critical vCenter alarms are triggered on the
+	// hosts, resource pools, folders or datastores of the VMs of a VSphereCluster.
+	VSphereClusterInfraHealthyCondition clusterv1.ConditionType = "VSphereClusterInfraHealthy"
+
+	// CriticalAlarmsTriggeredReason (Severity=Warning) documents critical vCenter alarms which are triggered
+	// on the vSphere infrastructure used by the VMs of the cluster and have not been acknowledged.
+	CriticalAlarmsTriggeredReason = "CriticalAlarmsTriggered"
+
+	// AlarmsUnavailableReason documents a controller failing to get the triggered alarms from vCenter.
+	AlarmsUnavailableReason = "AlarmsUnavailable"
 )
 
 const (
@@ -156,6 +258,14 @@ const (
 
 	// SecretAlreadyInUseReason is used when another VSphereClusterIdentity is using the secret.
 	SecretAlreadyInUseReason = "SecretInUse"
+
+	// ServiceAccountNotAvailableReason is used when the ServiceAccount whose tokens are exchanged
+	// by the VSphereClusterIdentity cannot be found.
+	ServiceAccountNotAvailableReason = "ServiceAccountNotAvailable"
+
+	// InvalidCredentialsSourceReason is used when the VSphereClusterIdentity sets neither or both of
+	// a secret and a token exchange.
+	InvalidCredentialsSourceReason = "InvalidCredentialsSource"
 )
 
 const (
@@ -164,10 +274,12 @@ const (
 
 	// ResourcePoolNotFoundReason (Severity=Error) documents that the resource pool in the placement constraint
 	// associated to the VSphereDeploymentZone is misconfigured.
+	// It is also used for VSphereMachine/VSphereVM operations failing because the object cannot be found in vCenter.
 	ResourcePoolNotFoundReason = "ResourcePoolNotFound"
 
 	// FolderNotFoundReason (Severity=Error) documents that the folder in the placement constraint
 	// associated to the VSphereDeploymentZone is misconfigured.
+	// It is also used for VSphereMachine/VSphereVM operations failing because the object cannot be found in vCenter.
 	FolderNotFoundReason = "FolderNotFound"
 )
 
@@ -191,16 +303,61 @@ const (
 	// associated to the VSphereDeploymentZone are misconfigured.
 	HostsMisconfiguredReason = "HostsMisconfigured"
 
+	// HostGroupNotFoundReason (Severity=Error) documents that the host group of the VM & Host Group details for
+	// the Failure Domain associated to the VSphereDeploymentZone cannot be found in the compute cluster.
+	HostGroupNotFoundReason = "HostGroupNotFound"
+
+	// VMGroupNotFoundReason (Severity=Error) documents that the VM group of the VM & Host Group details for
+	// the Failure Domain associated to the VSphereDeploymentZone cannot be found in the compute cluster.
+	VMGroupNotFoundReason = "VMGroupNotFound"
+
 	// HostsAffinityMisconfiguredReason (Severity=Warning) documents that the VM & Host Group affinity rule for the FailureDomain is disabled.
 	HostsAffinityMisconfiguredReason = "HostsAffinityMisconfigured"
 
 	// NetworkNotFoundReason (Severity=Error) documents that the networks in the topology for the Failure Domain
 	// associated to the VSphereDeploymentZone are misconfigured.
+	// It is also used for VSphereMachine/VSphereVM operations failing because the object cannot be found in vCenter.
 	NetworkNotFoundReason = "NetworkNotFound"
 
 	// DatastoreNotFoundReason (Severity=Error) documents that the datastore in the topology for the Failure Domain
 	// associated to the VSphereDeploymentZone is misconfigured.
+	// It is also used for VSphereMachine/VSphereVM operations failing because the object cannot be found in vCenter.
 	DatastoreNotFoundReason = "DatastoreNotFound"
+
+	// VSANMisconfiguredReason (Severity=Error) documents that the vSAN topology for the Failure Domain
+	// associated to the VSphereDeploymentZone does not match the hosts, the VM & Host Group affinity rule
+	// or the datastore of the Failure Domain.
+	VSANMisconfiguredReason = "VSANMisconfigured"
+)
+
+const (
+	// VSANDatastoreHealthyCondition documents the health of the vSAN datastore of the Failure Domain
+	// associated to the VSphereDeploymentZone, as reported by vCenter.
+	// It is only set for Failure Domains with a vSAN topology and a datastore.
+	VSANDatastoreHealthyCondition clusterv1.ConditionType = "VSANDatastoreHealthy"
+
+	// VSANDatastoreUnhealthyReason (Severity=Warning or Error) documents a vSAN datastore whose
+	// overall status is yellow, respectively red.
+	VSANDatastoreUnhealthyReason = "VSANDatastoreUnhealthy"
+
+	// VSANDatastoreHealthUnknownReason (Severity=Info) documents a vSAN datastore whose overall status
+	// is unknown to vCenter.
+	VSANDatastoreHealthUnknownReason = "VSANDatastoreHealthUnknown"
+)
+
+const (
+	// VCenterPrivilegesVerifiedCondition documents whether the vCenter credentials used for the
+	// VSphereDeploymentZone have the minimum privileges on the objects of its Failure Domain and
+	// placement constraint.
+	VCenterPrivilegesVerifiedCondition clusterv1.ConditionType = "VCenterPrivilegesVerified"
+
+	// MissingPrivilegesReason (Severity=Warning) documents vCenter credentials missing privileges
+	// on some of the objects. The message lists the missing privileges per object.
+	MissingPrivilegesReason = "MissingPrivileges"
+
+	// PrivilegesCheckFailedReason (Severity=Info) documents a failure to check the privileges of the
+	// vCenter credentials.
+	PrivilegesCheckFailedReason = "PrivilegesCheckFailed"
 )
 
 const (
@@ -212,7 +369,7 @@ const (
 	// IP addresses required by the VSphereVM are being created.
 	IPAddressClaimsBeingCreatedReason = "IPAddressClaimsBeingCreated"
 
-	// WaitingForIPAddressReason (Severity=Info) documents that the VSphereVM is
+	// WaitingForIPAddressReason (Severity=Info) documents that the VSphereVM or VSphereCluster is
 	// currently waiting for an IP address to be provisioned.
 	WaitingForIPAddressReason = "WaitingForIPAddress"
 
@@ -223,6 +380,11 @@ const (
 	// IPAddressClaimNotFoundReason (Severity=Error) documents that the IPAddressClaim
 	// cannot be found.
 	IPAddressClaimNotFoundReason = "IPAddressClaimNotFound"
+
+	// IPAddressPoolExhaustedReason (Severity=Warning) documents that IPAddressClaims of the
+	// VSphereVM are not fulfilled because their IPAM provider reports that the pool has no
+	// IP address available.
+	IPAddressPoolExhaustedReason = "IPAddressPoolExhausted"
 )
 
 const (
@@ -238,3 +400,185 @@ const (
 	// shutdown request fails.
 	GuestSoftPowerOffFailedReason = "GuestSoftPowerOffFailed"
 )
+
+const (
+	// GuestToolsRunningCondition documents whether VMware Tools is running in the guest
+	// of the VSphereVM.
+	//
+	// NOTE: This condition does not apply to VSphereMachine.
+	GuestToolsRunningCondition clusterv1.ConditionType = "GuestToolsRunning"
+
+	// GuestToolsNotRunningReason (Severity=Info) documents that VMware Tools is either not
+	// installed or not yet started in the guest.
+	GuestToolsNotRunningReason = "GuestToolsNotRunning"
+
+	// GuestHeartbeatGreenCondition documents whether the guest heartbeat reported by
+	// VMware Tools for the VSphereVM is green.
+	//
+	// NOTE: This condition does not apply to VSphereMachine.
+	GuestHeartbeatGreenCondition clusterv1.ConditionType = "GuestHeartbeatGreen"
+
+	// GuestHeartbeatNotGreenReason (Severity=Info) documents that the guest heartbeat is
+	// gray, yellow or red.
+	GuestHeartbeatNotGreenReason = "GuestHeartbeatNotGreen"
+
+	// GuestOSHealthyCondition documents whether the guest operating system of the VSphereVM
+	// is not crashing repeatedly, according to spec.guestCrashDetection. The condition is
+	// also set on the Node of the VSphereVM, for use by a MachineHealthCheck.
+	//
+	// NOTE: This condition does not apply to VSphereMachine.
+	GuestOSHealthyCondition clusterv1.ConditionType = "GuestOSHealthy"
+
+	// GuestOSCrashLoopingReason (Severity=Warning) documents that the guest operating system
+	// crashed at least spec.guestCrashDetection.threshold times within the window.
+	GuestOSCrashLoopingReason = "GuestOSCrashLooping"
+
+	// GuestCrashEventsUnavailableReason (Severity=Info) documents that the vCenter events of
+	// the VM could not be queried to detect guest crashes.
+	GuestCrashEventsUnavailableReason = "GuestCrashEventsUnavailable"
+
+	// WaitingForGuestReadinessGatesReason (Severity=Info) documents a VSphereMachine/VSphereVM
+	// waiting for the guest readiness gates to be satisfied after the VM has been powered on.
+	WaitingForGuestReadinessGatesReason = "WaitingForGuestReadinessGates"
+)
+
+const (
+	// VMDeletedCondition documents the deletion of the VM of a deleted VSphereVM.
+	// It is only set when destroying the VM failed.
+	//
+	// NOTE: This condition does not apply to VSphereMachine.
+	VMDeletedCondition clusterv1.ConditionType = "VMDeleted"
+
+	// VMDeletionFailedReason (Severity=Warning) documents a deleted VSphereVM whose VM could not be destroyed.
+	// Destroying the VM is retried.
+	VMDeletionFailedReason = "VMDeletionFailed"
+
+	// VMStillExistsReason (Severity=Error) documents a deleted VSphereVM whose VM could not be destroyed
+	// within the VM deletion failure timeout and is still found in vCenter by its UUID.
+	// The VM has to be deleted manually.
+	VMStillExistsReason = "VMStillExists"
+
+	// VMForceDeletedReason (Severity=Warning) documents a deleted VSphereVM whose VM could not be destroyed
+	// within the VM deletion failure timeout but is no longer found in vCenter by its UUID, e.g. because it
+	// is orphaned. The finalizer of the VSphereVM is removed without destroying the VM.
+	VMForceDeletedReason = "VMForceDeleted"
+)
+
+const (
+	// VMRelocatedCondition documents the relocation of a VSphereVM to the targets defined in spec.relocateTo.
+	//
+	// NOTE: This condition does not apply to VSphereMachine.
+	VMRelocatedCondition clusterv1.ConditionType = "VMRelocated"
+
+	// RelocatingReason (Severity=Info) documents a VSphereVM currently being relocated.
+	RelocatingReason = "Relocating"
+
+	// RelocationFailedReason (Severity=Warning) documents a VSphereVM whose relocation failed,
+	// e.g. because a target could not be found.
+	RelocationFailedReason = "RelocationFailed"
+)
+
+const (
+	// VMConfigurationSyncedCondition documents whether the configuration of a VSphereVM
+	// matches its spec. It is only set if a drift policy is configured.
+	//
+	// NOTE: This condition does not apply to VSphereMachine.
+	VMConfigurationSyncedCondition clusterv1.ConditionType = "VMConfigurationSynced"
+
+	// ConfigurationDriftedReason (Severity=Warning) documents a VSphereVM whose configuration
+	// was changed out-of-band and does not match its spec.
+	ConfigurationDriftedReason = "ConfigurationDrifted"
+
+	// RevertingConfigurationDriftReason (Severity=Info) documents a VSphereVM whose configuration
+	// is being reconfigured to match its spec.
+	RevertingConfigurationDriftReason = "RevertingConfigurationDrift"
+)
+
+const (
+	// VMResizedCondition documents whether the CPU and memory of a VSphereVM match its spec.
+	// It is only set if in-place resize is allowed, and is mirrored to the VSphereMachine.
+	VMResizedCondition clusterv1.ConditionType = "VMResized"
+
+	// ResizingReason (Severity=Info) documents a VSphereVM whose CPU and memory are being
+	// reconfigured to match its spec.
+	ResizingReason = "Resizing"
+
+	// ResizeNotSupportedReason (Severity=Warning) documents a VSphereVM whose CPU and memory
+	// can't be resized in place, e.g. because hot plug is not enabled for the powered on VM.
+	ResizeNotSupportedReason = "ResizeNotSupported"
+)
+
+const (
+	// VMNetworkDevicesSyncedCondition documents whether the NICs of a VSphereVM match the network
+	// devices of its spec. It is only set once network devices have been appended to the spec of an
+	// existing VSphereVM, and is mirrored to the VSphereMachine.
+	VMNetworkDevicesSyncedCondition clusterv1.ConditionType = "NetworkDevicesSynced"
+
+	// HotAddingNetworkDevicesReason (Severity=Info) documents a VSphereVM whose NICs for the network
+	// devices appended to its spec are being hot-added, or whose guest is waiting to be rebooted to
+	// pick them up.
+	HotAddingNetworkDevicesReason = "HotAddingNetworkDevices"
+
+	// NetworkDeviceRemovalNotSupportedReason (Severity=Warning) documents a VSphereVM with more NICs
+	// than network devices in its spec, as NICs are not removed from existing VMs.
+	NetworkDeviceRemovalNotSupportedReason = "NetworkDeviceRemovalNotSupported"
+
+	// SRIOVNetworkDeviceHotPlugNotSupportedReason (Severity=Warning) documents a VSphereVM with SR-IOV
+	// network devices appended to its spec, as SR-IOV NICs cannot be hot-added to existing VMs.
+	SRIOVNetworkDeviceHotPlugNotSupportedReason = "SRIOVNetworkDeviceHotPlugNotSupported"
+)
+
+const (
+	// AttachingFirstClassDiskReason (Severity=Info) documents a VSphereVM whose existing First Class
+	// Disks referenced by its data disks are being attached. It is used with the VMProvisioned condition.
+	AttachingFirstClassDiskReason = "AttachingFirstClassDisk"
+
+	// FirstClassDiskAttachFailedReason (Severity=Warning) documents a VSphereVM whose existing First
+	// Class Disks referenced by its data disks can't be attached, e.g. because they don't exist or are
+	// attached to another VM. It is used with the VMProvisioned condition.
+	FirstClassDiskAttachFailedReason = "FirstClassDiskAttachFailed"
+)
+
+const (
+	// PreTerminateDeleteHookTimedOutReason (Severity=Warning) documents a VSphereMachine or VSphereVM
+	// which is deleted although its pre-terminate delete hooks have not been removed within the
+	// pre-terminate hook timeout. It is used with the PreTerminateDeleteHookSucceeded condition
+	// defined by Cluster API.
+	PreTerminateDeleteHookTimedOutReason = "PreTerminateDeleteHookTimedOut"
+)
+
+// Conditions and condition Reasons for the VSphereMachinePool object.
+
+const (
+	// InstancesReadyCondition documents whether the desired number of up-to-date instances of a
+	// VSphereMachinePool are ready.
+	//
+	// NOTE: In addition to the reasons below, this condition uses WaitingForClusterInfrastructureReason,
+	// WaitingForBootstrapDataReason and the ScalingUp/ScalingDown reasons defined by Cluster API.
+	InstancesReadyCondition clusterv1.ConditionType = "InstancesReady"
+
+	// RollingUpdateInProgressReason (Severity=Info) documents a VSphereMachinePool replacing instances
+	// created from an outdated instance template.
+	RollingUpdateInProgressReason = "RollingUpdateInProgress"
+
+	// WaitingForInstancesReadyReason (Severity=Info) documents a VSphereMachinePool waiting for its
+	// instances to become ready.
+	WaitingForInstancesReadyReason = "WaitingForInstancesReady"
+)
+
+// Conditions and condition Reasons for the VSphereVMImage object.
+
+const (
+	// VMImageVerifiedCondition documents whether the VM template of a VSphereVMImage has been found
+	// in vCenter and matches the checksums of the image.
+	VMImageVerifiedCondition clusterv1.ConditionType = "ImageVerified"
+
+	// VMImageVerificationFailedReason (Severity=Error) documents a VSphereVMImage whose VM template
+	// can't be found in vCenter or doesn't match the checksums of the image.
+	VMImageVerificationFailedReason = "ImageVerificationFailed"
+
+	// WaitingForVMImageReason (Severity=Info) documents a VSphereMachine waiting for the
+	// VSphereVMImage it references to be verified before its VSphereVM is created.
+	// It is used with the VMProvisionedCondition.
+	WaitingForVMImageReason = "WaitingForImage"
+)
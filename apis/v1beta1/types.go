@@ -17,9 +17,11 @@ limitations under the License.
 package v1beta1
 
 import (
-	"fmt"
+	"net"
+	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
@@ -34,6 +36,46 @@ const (
 
 	// ValueReady is the ready value for *Ready annotations.
 	ValueReady = "true"
+
+	// PausedInfraAnnotation can be set on a Cluster, VSphereCluster or VSphereVM to
+	// halt all the vSphere mutating operations (clone, reconfigure, power, delete) for
+	// the VMs it applies to, while still reconciling their status.
+	// This is intended to freeze the infrastructure e.g. during a vCenter upgrade
+	// without pausing the entire Cluster.
+	PausedInfraAnnotation = "capv.cluster.x-k8s.io/paused-infra"
+
+	// DryRunAnnotation can be set on a Cluster, VSphereCluster or VSphereVM to log
+	// the vSphere mutating operations (clone, reconfigure, power, delete) for the VMs
+	// it applies to instead of executing them, while still reconciling their status.
+	// This is intended to validate changes e.g. to VM templates before rolling them out.
+	DryRunAnnotation = "capv.cluster.x-k8s.io/dry-run"
+
+	// HostEvacuationDrainedAnnotation is set on a workload cluster Node which has been cordoned
+	// and drained because its VM is being evacuated from its ESXi host, and on its VSphereVM.
+	// The value is the name of the host. The annotation is removed when the Node is uncordoned.
+	HostEvacuationDrainedAnnotation = "capv.cluster.x-k8s.io/host-evacuation-drained"
+
+	// IPAddressRoutesAnnotation can be set on an IPAddress by an IPAM provider to add
+	// static routes to the network device the address is assigned to.
+	// The value is a JSON list of routes, e.g. `[{"to":"10.20.0.0/16","via":"10.10.0.1","metric":100}]`.
+	IPAddressRoutesAnnotation = "capv.cluster.x-k8s.io/routes"
+
+	// AdoptVMAnnotation can be set on a VSphereMachine or VSphereVM to adopt a pre-existing VM
+	// instead of cloning a new one, e.g. when migrating existing VMs into CAPV.
+	// The value is the BIOS UUID of the VM to adopt; if empty, the VM with the name of the
+	// VSphereVM in its folder is adopted. A VSphereVM with this annotation is never cloned
+	// and the bootstrap data of the adopted VM is not changed.
+	AdoptVMAnnotation = "capv.cluster.x-k8s.io/adopt-vm"
+
+	// AdoptVMInstanceUUIDAnnotation can be set on a VSphereMachine or VSphereVM to adopt a pre-existing VM
+	// identified by its instance UUID, which is unique within a vCenter unlike the BIOS UUID of VMs
+	// copied outside of vCenter. It behaves like the AdoptVMAnnotation otherwise.
+	AdoptVMInstanceUUIDAnnotation = "capv.cluster.x-k8s.io/adopt-vm-instance-uuid"
+
+	// DeleteProtectionAnnotation can be set on a Cluster, VSphereCluster, VSphereMachine or VSphereVM
+	// to keep the VMs it applies to in vCenter when their VSphereVMs are deleted. The VMs are
+	// neither powered off nor destroyed, but left orphaned, and their Nodes are not deleted.
+	DeleteProtectionAnnotation = "capv.cluster.x-k8s.io/delete-protection"
 )
 
 // CloneMode is the type of clone operation used to clone a VM from a template.
@@ -91,12 +133,109 @@ const (
 	VirtualMachinePowerOpModeTrySoft VirtualMachinePowerOpMode = "trySoft"
 )
 
+// VirtualMachineTemplateSource is the OVA or OVF a template is imported from.
+type VirtualMachineTemplateSource struct {
+	// URL is the HTTP(S) URL of the OVA or OVF file, which is identified by the .ova
+	// or .ovf extension of the path. OVF files must be accompanied by a manifest file
+	// with the same name and the .mf extension listing the checksums of the OVF file
+	// and of all files it references.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// Checksum is the checksum of the OVA file, or of the manifest file of the OVF file,
+	// in the format <algorithm>:<hex digest>. Supported algorithms are sha256 and sha512.
+	// The template is not imported if the checksum doesn't match.
+	// +kubebuilder:validation:Pattern=`^(sha256|sha512):[a-fA-F0-9]+$`
+	Checksum string `json:"checksum"`
+}
+
+// VirtualMachineDriftPolicy describes how drift of the configuration of a virtual
+// machine from its spec is handled.
+// +kubebuilder:validation:Enum=report;enforce
+type VirtualMachineDriftPolicy string
+
+const (
+	// VirtualMachineDriftPolicyReport reports drift in the VMConfigurationSynced
+	// condition without changing the virtual machine.
+	VirtualMachineDriftPolicyReport VirtualMachineDriftPolicy = "report"
+
+	// VirtualMachineDriftPolicyEnforce reports drift in the VMConfigurationSynced
+	// condition and reverts out-of-band changes by reconfiguring the virtual machine.
+	// Note: CPU and memory changes can only be reverted on powered on virtual machines
+	// if CPU and memory hot plug are enabled.
+	VirtualMachineDriftPolicyEnforce VirtualMachineDriftPolicy = "enforce"
+)
+
+// NetworkDeviceHotPlugPolicy describes how the guest of a virtual machine picks up
+// the network devices hot-added to it.
+// +kubebuilder:validation:Enum=none;reboot
+type NetworkDeviceHotPlugPolicy string
+
+const (
+	// NetworkDeviceHotPlugPolicyNone only hot-adds the network devices and updates the
+	// network configuration in the metadata of the virtual machine, which is applied by
+	// the guest, e.g. by cloud-init on hotplug events.
+	NetworkDeviceHotPlugPolicyNone NetworkDeviceHotPlugPolicy = "none"
+
+	// NetworkDeviceHotPlugPolicyReboot reboots the guest after the network devices have
+	// been hot-added and the network configuration in the metadata has been updated, so
+	// that it is applied on boot. The guest is rebooted through VMware Tools.
+	NetworkDeviceHotPlugPolicyReboot NetworkDeviceHotPlugPolicy = "reboot"
+)
+
+// BootstrapDataDelivery describes how the bootstrap data and the metadata are
+// delivered to a virtual machine.
+// +kubebuilder:validation:Enum=guestInfo;noCloudISO
+type BootstrapDataDelivery string
+
+const (
+	// BootstrapDataDeliveryGuestInfo sets the bootstrap data and the metadata in the
+	// guestinfo of the virtual machine, where they are read by the VMware datasource of
+	// cloud-init or by Ignition.
+	BootstrapDataDeliveryGuestInfo BootstrapDataDelivery = "guestInfo"
+
+	// BootstrapDataDeliveryNoCloudISO writes the bootstrap data and the metadata to a
+	// NoCloud ISO image labelled cidata, which is uploaded next to the files of the
+	// virtual machine and attached to it as a CD-ROM before it is powered on for the
+	// first time. It is meant for guests which can't read the guestinfo.
+	// Only cloud-config bootstrap data is supported.
+	BootstrapDataDeliveryNoCloudISO BootstrapDataDelivery = "noCloudISO"
+)
+
+// GuestReadinessGate is a guest condition which has to be true before a
+// virtual machine is considered provisioned.
+// +kubebuilder:validation:Enum=GuestToolsRunning;GuestHeartbeatGreen
+type GuestReadinessGate string
+
+const (
+	// GuestReadinessGateToolsRunning waits for VMware Tools to be running in
+	// the guest.
+	GuestReadinessGateToolsRunning GuestReadinessGate = GuestReadinessGate(GuestToolsRunningCondition)
+
+	// GuestReadinessGateHeartbeatGreen waits for the guest heartbeat reported
+	// by VMware Tools to be green.
+	GuestReadinessGateHeartbeatGreen GuestReadinessGate = GuestReadinessGate(GuestHeartbeatGreenCondition)
+)
+
 // VirtualMachineCloneSpec is information used to clone a virtual machine.
 type VirtualMachineCloneSpec struct {
 	// Template is the name, inventory path, managed object reference or the managed
 	// object ID of the template used to clone the virtual machine.
+	// Either Template or ImageRef must be set.
+	// +optional
 	// +kubebuilder:validation:MinLength=1
-	Template string `json:"template"`
+	Template string `json:"template,omitempty"`
+
+	// ImageRef references a VSphereVMImage in the same namespace whose VM template is used
+	// to clone the virtual machine. The template is resolved when the VSphereVM is created.
+	// +optional
+	ImageRef *VSphereVMImageReference `json:"imageRef,omitempty"`
+
+	// TemplateSource is the source the template is imported from if it doesn't exist.
+	// The template is imported with the name of Template into Datastore, Folder
+	// and ResourcePool and then marked as template.
+	// +optional
+	TemplateSource *VirtualMachineTemplateSource `json:"templateSource,omitempty"`
 
 	// CloneMode specifies the type of clone operation.
 	// The LinkedClone mode is only support for templates that have at least
@@ -148,11 +287,22 @@ type VirtualMachineCloneSpec struct {
 	// +optional
 	StoragePolicyName string `json:"storagePolicyName,omitempty"`
 
+	// Encryption configures the encryption at rest of the virtual machine and its disks.
+	// Encrypted virtual machines are always created using a full clone.
+	// +optional
+	Encryption *VirtualMachineEncryptionSpec `json:"encryption,omitempty"`
+
 	// ResourcePool is the name, inventory path, managed object reference or the managed
 	// object ID in which the virtual machine is created/located.
 	// +optional
 	ResourcePool string `json:"resourcePool,omitempty"`
 
+	// ChildResourcePool is a resource pool which is created in ResourcePool if it doesn't
+	// exist yet, and in which the virtual machine is created, e.g. to isolate the resources
+	// of the machines of a MachineDeployment from the other machines of the cluster.
+	// +optional
+	ChildResourcePool *ChildResourcePoolSpec `json:"childResourcePool,omitempty"`
+
 	// Network is the network configuration for this machine's VM.
 	Network NetworkSpec `json:"network"`
 
@@ -172,6 +322,11 @@ type VirtualMachineCloneSpec struct {
 	// virtual machine is cloned.
 	// +optional
 	MemoryMiB int64 `json:"memoryMiB,omitempty"`
+	// Resources configures the allocation of CPU and memory resources to the virtual machine,
+	// e.g. to guarantee resources to latency-sensitive workloads.
+	// Defaults to the allocation of the template from which the virtual machine is cloned.
+	// +optional
+	Resources *VirtualMachineResources `json:"resources,omitempty"`
 	// DiskGiB is the size of a virtual machine's disk, in GiB.
 	// Defaults to the eponymous property value in the template from which the
 	// virtual machine is cloned.
@@ -184,6 +339,10 @@ type VirtualMachineCloneSpec struct {
 	AdditionalDisksGiB []int32 `json:"additionalDisksGiB,omitempty"`
 	// CustomVMXKeys is a dictionary of advanced VMX options that can be set on VM
 	// Defaults to empty map
+	// The options are set in the extraConfig of the VM when it is cloned and kept in sync
+	// afterwards, unless the driftPolicy is report. Options removed from the map are left
+	// untouched. The guestinfo keys of the bootstrap data and the metadata are managed by
+	// CAPV and cannot be set.
 	// +optional
 	CustomVMXKeys map[string]string `json:"customVMXKeys,omitempty"`
 	// TagIDs is an optional set of tags to add to an instance. Specified tagIDs
@@ -209,6 +368,100 @@ type VirtualMachineCloneSpec struct {
 	// +listMapKey=name
 	// +kubebuilder:validation:MaxItems=29
 	DataDisks []VSphereDisk `json:"dataDisks,omitempty"`
+	// CloudInitCustomizationRef is a reference to a ConfigMap in the namespace of the machine with
+	// additional cloud-init configuration, e.g. NIC renaming rules or multipath configuration, which
+	// is merged into the cloud-config bootstrap data when the virtual machine is created.
+	// The ConfigMap may contain the keys "writeFiles", "preCommands" and "postCommands", each holding
+	// a YAML list. Files are appended to the write_files of the bootstrap data, while the commands are
+	// run before respectively after the runcmd commands of the bootstrap data.
+	// Not supported with Ignition bootstrap data.
+	// +optional
+	CloudInitCustomizationRef *corev1.LocalObjectReference `json:"cloudInitCustomizationRef,omitempty"`
+	// VAppConfig is a map of OVF/vApp property IDs to values which are set on the
+	// virtual machine when it is cloned. Properties defined in the template are updated,
+	// all other properties are added as string properties.
+	// If set, the vApp configuration of the template is kept instead of being removed,
+	// which may cause cloud-init to prefer the OVF datasource over the VMware datasource.
+	// +optional
+	VAppConfig map[string]string `json:"vAppConfig,omitempty"`
+	// DriftPolicy defines how drift of the configuration of the virtual machine,
+	// i.e. the CPU, memory, primary disk size, network backings and custom VMX keys,
+	// from the spec is handled. The configuration is compared to the spec on every
+	// reconcile, including the periodic resyncs.
+	// If omitted, drift is not detected.
+	// +optional
+	DriftPolicy VirtualMachineDriftPolicy `json:"driftPolicy,omitempty"`
+	// CustomAttributes is a map of vSphere custom attribute names to values which are
+	// set on the virtual machine, e.g. for the integration with chargeback tooling.
+	// Custom attributes which don't exist yet are created for virtual machines.
+	// The values are kept in sync with the spec, custom attributes which are not in
+	// the spec are left untouched.
+	// Values set for the same custom attribute in the VSphereCluster are overridden.
+	// +optional
+	CustomAttributes map[string]string `json:"customAttributes,omitempty"`
+	// AllowInPlaceResize allows changing numCPUs and memoryMiB of existing machines. The
+	// changes are applied by reconfiguring the virtual machine instead of requiring the
+	// machine to be replaced, and out-of-band changes of the CPU and memory are reverted.
+	// Resizing a powered on virtual machine requires CPU respectively memory hot plug to be
+	// enabled in the template, and memory can't be decreased while it is powered on.
+	// The resize is reflected in the VMResized condition of the VSphereVM.
+	// +optional
+	AllowInPlaceResize bool `json:"allowInPlaceResize,omitempty"`
+	// QuestionAnswers are the answers to questions vCenter may ask about the virtual machine,
+	// e.g. whether it was moved or copied, which are answered automatically instead of blocking
+	// the virtual machine. Questions which are not answered are reported in the VMProvisioned
+	// condition of the VSphereVM.
+	// +optional
+	// +listType=map
+	// +listMapKey=messageID
+	QuestionAnswers []VirtualMachineQuestionAnswer `json:"questionAnswers,omitempty"`
+	// NetworkDeviceHotPlugPolicy defines how the guest picks up the network devices appended
+	// to network.devices of existing machines, which are hot-added to the virtual machine
+	// instead of requiring the machine to be replaced. Removing network devices from existing
+	// machines is not supported.
+	// The hot plug is reflected in the NetworkDevicesSynced condition of the VSphereVM.
+	// If omitted, defaults to none.
+	// +optional
+	NetworkDeviceHotPlugPolicy NetworkDeviceHotPlugPolicy `json:"networkDeviceHotPlugPolicy,omitempty"`
+	// BootstrapDataDelivery defines how the bootstrap data and the metadata are delivered to
+	// the virtual machine. The noCloudISO delivery attaches them as a NoCloud ISO image for
+	// guests which can't read the guestinfo, and doesn't support Ignition bootstrap data.
+	// If omitted, defaults to guestInfo.
+	// +optional
+	BootstrapDataDelivery BootstrapDataDelivery `json:"bootstrapDataDelivery,omitempty"`
+	// GuestCrashDetection enables the detection of repeated crashes of the guest operating
+	// system, e.g. kernel panics, using the crashes reported by VMware Tools and the resets of
+	// vSphere HA VM monitoring in the vCenter events of the virtual machine.
+	// Repeated crashes are reflected in the GuestOSHealthy condition of the VSphereVM and of the
+	// Node, which can be used in the unhealthyConditions of a MachineHealthCheck.
+	// +optional
+	GuestCrashDetection *GuestCrashDetection `json:"guestCrashDetection,omitempty"`
+}
+
+// GuestCrashDetection defines when the guest operating system of a virtual machine is
+// considered to be crashing repeatedly.
+type GuestCrashDetection struct {
+	// Threshold is the number of guest crashes within the window at which the guest
+	// operating system is considered unhealthy.
+	// If omitted, defaults to 3.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Threshold int32 `json:"threshold,omitempty"`
+	// Window is the period of time in which the guest crashes are counted.
+	// If omitted, defaults to 1h.
+	// +optional
+	Window *metav1.Duration `json:"window,omitempty"`
+}
+
+// VirtualMachineQuestionAnswer is the answer to a question vCenter may ask about a virtual machine.
+type VirtualMachineQuestionAnswer struct {
+	// MessageID is the ID of a message of the question to answer, e.g. "msg.uuid.altered"
+	// for the question asked when a virtual machine was moved or copied.
+	// +kubebuilder:validation:MinLength=1
+	MessageID string `json:"messageID"`
+	// Choice is the key or the label of the choice answering the question, e.g. "button.uuid.movedTheVM".
+	// +kubebuilder:validation:MinLength=1
+	Choice string `json:"choice"`
 }
 
 // VSphereDisk is an additional disk to add to the VM that is not part of the VM OVA template.
@@ -218,8 +471,31 @@ type VSphereDisk struct {
 	// +kubebuilder:validation:Required
 	Name string `json:"name,omitempty"`
 	// SizeGiB is the size of the disk in GiB.
-	// +kubebuilder:validation:Required
+	// SizeGiB is required unless ExistingFCD is set, in which case the disk keeps the size of the First Class Disk.
+	// +optional
 	SizeGiB int32 `json:"sizeGiB"`
+	// ExistingFCD is a reference to an existing First Class Disk which is attached to the VM instead of
+	// creating a new disk. The First Class Disk is attached before the VM is powered on for the first time,
+	// and detached before the VM is destroyed, so that it is retained when the machine is deleted.
+	// +optional
+	ExistingFCD *FirstClassDiskReference `json:"existingFCD,omitempty"`
+}
+
+// VSphereVMImageReference references a VSphereVMImage in the same namespace.
+type VSphereVMImageReference struct {
+	// Name is the name of the VSphereVMImage.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// FirstClassDiskReference is a reference to an existing First Class Disk.
+type FirstClassDiskReference struct {
+	// ID is the ID of the First Class Disk.
+	// +kubebuilder:validation:MinLength=1
+	ID string `json:"id"`
+	// Datastore is the name or inventory path of the datastore of the First Class Disk.
+	// +kubebuilder:validation:MinLength=1
+	Datastore string `json:"datastore"`
 }
 
 // VSphereMachineTemplateResource describes the data needed to create a VSphereMachine from a template.
@@ -246,7 +522,8 @@ const (
 
 // APIEndpoint represents a reachable Kubernetes API endpoint.
 type APIEndpoint struct {
-	// The hostname on which the API server is serving.
+	// The hostname or IP address on which the API server is serving.
+	// IPv6 addresses must be specified without enclosing square brackets.
 	Host string `json:"host"`
 
 	// The port on which the API server is serving.
@@ -259,8 +536,137 @@ func (v APIEndpoint) IsZero() bool {
 }
 
 // String returns a formatted version HOST:PORT of this APIEndpoint.
+// IPv6 hosts are enclosed in square brackets, e.g. [fd00::1]:6443.
 func (v APIEndpoint) String() string {
-	return fmt.Sprintf("%s:%d", v.Host, v.Port)
+	return net.JoinHostPort(v.Host, strconv.Itoa(int(v.Port)))
+}
+
+// VirtualMachineEncryptionSpec defines the encryption configuration of a virtual machine.
+type VirtualMachineEncryptionSpec struct {
+	// StoragePolicyName is the name of a storage policy with an encryption rule,
+	// e.g. "VM Encryption Policy". It is applied to the virtual machine home and its disks.
+	// +kubebuilder:validation:MinLength=1
+	StoragePolicyName string `json:"storagePolicyName"`
+
+	// KeyProviderID is the ID of the key provider used by vCenter to generate the encryption key.
+	// Defaults to the default key provider configured in vCenter.
+	// The key provider is resolved when the virtual machine is cloned, as the webhooks
+	// cannot connect to vCenter.
+	// +optional
+	KeyProviderID string `json:"keyProviderID,omitempty"`
+}
+
+// ChildResourcePoolSpec defines a resource pool created in the resource pool of a virtual machine.
+type ChildResourcePoolSpec struct {
+	// Name is the name of the resource pool. Virtual machines with the same ResourcePool and
+	// child resource pool name share the resource pool.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=80
+	// +kubebuilder:validation:Pattern=`^[^/]+$`
+	Name string `json:"name"`
+
+	// CPU is the allocation of CPU resources to the resource pool, in MHz.
+	// +optional
+	CPU *ResourcePoolAllocation `json:"cpu,omitempty"`
+
+	// Memory is the allocation of memory resources to the resource pool, in MiB.
+	// +optional
+	Memory *ResourcePoolAllocation `json:"memory,omitempty"`
+}
+
+// ResourcePoolSharesLevel is the level of the shares of a resource pool.
+type ResourcePoolSharesLevel string
+
+const (
+	// ResourcePoolSharesLevelLow allocates a low number of shares to the resource pool.
+	ResourcePoolSharesLevelLow ResourcePoolSharesLevel = "low"
+
+	// ResourcePoolSharesLevelNormal allocates a normal number of shares to the resource pool.
+	ResourcePoolSharesLevelNormal ResourcePoolSharesLevel = "normal"
+
+	// ResourcePoolSharesLevelHigh allocates a high number of shares to the resource pool.
+	ResourcePoolSharesLevelHigh ResourcePoolSharesLevel = "high"
+)
+
+// ResourcePoolAllocation defines the allocation of a resource to a resource pool.
+type ResourcePoolAllocation struct {
+	// Shares is the relative priority of the resource pool among its siblings when the
+	// resource is contended.
+	// If omitted, defaults to normal.
+	// +kubebuilder:validation:Enum=low;normal;high
+	// +optional
+	Shares ResourcePoolSharesLevel `json:"shares,omitempty"`
+
+	// Reservation is the amount of the resource guaranteed to the resource pool.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Reservation int64 `json:"reservation,omitempty"`
+
+	// Limit is the maximum amount of the resource the resource pool can use.
+	// If omitted, the usage is not limited.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Limit *int64 `json:"limit,omitempty"`
+}
+
+// VirtualMachineSwapPlacement is the placement of the swap file of a virtual machine.
+type VirtualMachineSwapPlacement string
+
+const (
+	// VirtualMachineSwapPlacementInherit places the swap file as configured on the
+	// cluster or host the virtual machine runs on.
+	VirtualMachineSwapPlacementInherit VirtualMachineSwapPlacement = "inherit"
+
+	// VirtualMachineSwapPlacementVMDirectory places the swap file in the directory of the virtual machine.
+	VirtualMachineSwapPlacementVMDirectory VirtualMachineSwapPlacement = "vmDirectory"
+
+	// VirtualMachineSwapPlacementHostLocal places the swap file in the datastore configured
+	// for swap files on the host the virtual machine runs on.
+	VirtualMachineSwapPlacementHostLocal VirtualMachineSwapPlacement = "hostLocal"
+)
+
+// VirtualMachineResources defines the allocation of CPU and memory resources to a virtual machine.
+type VirtualMachineResources struct {
+	// CPU is the allocation of CPU resources to the virtual machine, in MHz.
+	// +optional
+	CPU *VirtualMachineResourceAllocation `json:"cpu,omitempty"`
+
+	// Memory is the allocation of memory resources to the virtual machine, in MiB.
+	// +optional
+	Memory *VirtualMachineResourceAllocation `json:"memory,omitempty"`
+
+	// MemoryReservationLockedToMax reserves all memory of the virtual machine, so that its
+	// memory is never swapped. It cannot be set together with memory.reservation.
+	// It is always enabled for virtual machines with PCI devices or SR-IOV network devices.
+	// +optional
+	MemoryReservationLockedToMax bool `json:"memoryReservationLockedToMax,omitempty"`
+
+	// SwapPlacement is the placement of the swap file of the virtual machine.
+	// Defaults to the placement of the template from which the virtual machine is cloned.
+	// +kubebuilder:validation:Enum=inherit;vmDirectory;hostLocal
+	// +optional
+	SwapPlacement VirtualMachineSwapPlacement `json:"swapPlacement,omitempty"`
+}
+
+// VirtualMachineResourceAllocation defines the allocation of a resource to a virtual machine.
+type VirtualMachineResourceAllocation struct {
+	// Shares is the relative priority of the virtual machine among its siblings when the
+	// resource is contended.
+	// If omitted, defaults to normal.
+	// +kubebuilder:validation:Enum=low;normal;high
+	// +optional
+	Shares ResourcePoolSharesLevel `json:"shares,omitempty"`
+
+	// Reservation is the amount of the resource guaranteed to the virtual machine.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Reservation int64 `json:"reservation,omitempty"`
+
+	// Limit is the maximum amount of the resource the virtual machine can use.
+	// If omitted, the usage is not limited.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Limit *int64 `json:"limit,omitempty"`
 }
 
 // PCIDeviceSpec defines virtual machine's PCI configuration.
@@ -311,6 +717,36 @@ type NetworkSpec struct {
 	//
 	// Deprecated: This field is going to be removed in a future release.
 	PreferredAPIServerCIDR string `json:"preferredAPIServerCidr,omitempty"`
+
+	// NTPServers is a list of NTP servers the guest synchronizes its time with,
+	// e.g. for nodes in isolated networks without access to public NTP servers.
+	// The servers are rendered into the cloud-init bootstrap data.
+	// +optional
+	NTPServers []string `json:"ntpServers,omitempty"`
+
+	// Proxy is the HTTP proxy used by the container runtime of the guest to pull images.
+	// The proxy is rendered into the cloud-init bootstrap data.
+	// +optional
+	Proxy *GuestProxySpec `json:"proxy,omitempty"`
+}
+
+// GuestProxySpec defines the HTTP proxy used by a guest.
+type GuestProxySpec struct {
+	// HTTPProxy is the URL of the proxy for HTTP connections,
+	// e.g. http://proxy.example.com:3128.
+	// +optional
+	HTTPProxy string `json:"httpProxy,omitempty"`
+
+	// HTTPSProxy is the URL of the proxy for HTTPS connections,
+	// e.g. http://proxy.example.com:3128.
+	// +optional
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+
+	// NoProxy is a list of hosts, domains (e.g. .example.com), IP addresses and CIDRs
+	// which are connected to directly instead of through the proxy. The pod and service
+	// CIDRs of the cluster usually have to be added.
+	// +optional
+	NoProxy []string `json:"noProxy,omitempty"`
 }
 
 // NetworkDeviceSpec defines the network configuration for a virtual machine's
@@ -409,6 +845,176 @@ type NetworkDeviceSpec struct {
 	// If true, CAPV will not verify IP address allocation.
 	// +optional
 	SkipIPAllocation bool `json:"skipIPAllocation,omitempty"`
+
+	// Bond adds the device to a bonded interface in the guest operating system.
+	// Devices with the same bond name are members of the same bond, which uses
+	// the bond parameters and the IP configuration, e.g. DHCP4, IPAddrs or
+	// AddressesFromPools, of its first member device. The other member devices
+	// must not have IP configuration.
+	// +optional
+	Bond *NetworkBondSpec `json:"bond,omitempty"`
+
+	// VLANs is a list of VLAN tagged sub-interfaces of the device.
+	// If the device is a member of a bond, the VLAN sub-interfaces are created
+	// on the bond instead.
+	// +optional
+	// +listType=map
+	// +listMapKey=id
+	VLANs []NetworkVLANSpec `json:"vlans,omitempty"`
+
+	// SRIOV connects the device as an SR-IOV passthrough NIC, backed by a
+	// virtual function of a physical NIC of the ESXi host.
+	// SR-IOV NICs require the memory of the VM to be reserved and cannot be
+	// hot-added to existing VMs. If MTU is set, the guest operating system is
+	// allowed to change the MTU of the virtual function.
+	// +optional
+	SRIOV *NetworkDeviceSRIOVSpec `json:"sriov,omitempty"`
+
+	// AddressFromNetworkProtocolProfile allocates an IPv4 address for the
+	// device from the IP pool of a vSphere Network Protocol Profile, as an
+	// alternative to DHCP4 or IPAddressPools. The gateway, netmask and DNS
+	// configuration of the IP pool are used for the device.
+	// The address is released when the VM is destroyed.
+	// +optional
+	AddressFromNetworkProtocolProfile *NetworkProtocolProfileAllocation `json:"addressFromNetworkProtocolProfile,omitempty"`
+}
+
+// NetworkProtocolProfileAllocation defines the vSphere Network Protocol Profile
+// an address of a network device is allocated from.
+type NetworkProtocolProfileAllocation struct {
+	// IPPool is the name of the IP pool of the Network Protocol Profile.
+	// If not set, the IP pool associated with the network of the device in
+	// the datacenter of the VM is used.
+	// +optional
+	IPPool string `json:"ipPool,omitempty"`
+}
+
+// NetworkDeviceSRIOVSpec defines the physical function backing an SR-IOV network device.
+type NetworkDeviceSRIOVSpec struct {
+	// PhysicalFunction is the PCI ID of the physical function of the ESXi host's NIC
+	// which provides the virtual function, e.g. 0000:3b:00.0.
+	// As the PCI ID is specific to a host, the VM must be placed on hosts which
+	// have a physical function with this ID, e.g. by using a VSphereDeploymentZone
+	// with a host group.
+	// If not set, a physical function of the SR-IOV device pool of the network
+	// is assigned when the VM is powered on.
+	// +kubebuilder:validation:Pattern=`^[0-9a-fA-F]{4}:[0-9a-fA-F]{2}:[0-9a-fA-F]{2}\.[0-7]$`
+	// +optional
+	PhysicalFunction string `json:"physicalFunction,omitempty"`
+}
+
+// NetworkBondMode is the bonding mode of a bonded interface.
+// +kubebuilder:validation:Enum=balance-rr;active-backup;balance-xor;broadcast;"802.3ad";balance-tlb;balance-alb
+type NetworkBondMode string
+
+const (
+	// NetworkBondModeBalanceRR transmits packets in sequential order over the bond members.
+	NetworkBondModeBalanceRR NetworkBondMode = "balance-rr"
+
+	// NetworkBondModeActiveBackup uses only one bond member at a time.
+	NetworkBondModeActiveBackup NetworkBondMode = "active-backup"
+
+	// NetworkBondModeBalanceXOR selects the bond member based on the transmit hash policy.
+	NetworkBondModeBalanceXOR NetworkBondMode = "balance-xor"
+
+	// NetworkBondModeBroadcast transmits all packets on all bond members.
+	NetworkBondModeBroadcast NetworkBondMode = "broadcast"
+
+	// NetworkBondMode8023AD uses IEEE 802.3ad dynamic link aggregation (LACP).
+	NetworkBondMode8023AD NetworkBondMode = "802.3ad"
+
+	// NetworkBondModeBalanceTLB uses adaptive transmit load balancing.
+	NetworkBondModeBalanceTLB NetworkBondMode = "balance-tlb"
+
+	// NetworkBondModeBalanceALB uses adaptive load balancing.
+	NetworkBondModeBalanceALB NetworkBondMode = "balance-alb"
+)
+
+// NetworkBondSpec defines the bonded interface a network device is a member of.
+// For more information see the netplan reference (https://netplan.io/reference#properties-for-device-type-bonds)
+type NetworkBondSpec struct {
+	// Name is the name of the bonded interface in the guest operating system, e.g. bond0.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=15
+	Name string `json:"name"`
+
+	// Mode is the bonding mode.
+	// Defaults to the distribution default, usually balance-rr.
+	// +optional
+	Mode NetworkBondMode `json:"mode,omitempty"`
+
+	// LACPRate is the rate at which LACPDUs are transmitted when using the 802.3ad mode.
+	// +kubebuilder:validation:Enum=slow;fast
+	// +optional
+	LACPRate string `json:"lacpRate,omitempty"`
+
+	// MIIMonitorInterval is the interval in milliseconds at which the link state
+	// of the bond members is checked.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MIIMonitorInterval *int32 `json:"miiMonitorInterval,omitempty"`
+
+	// TransmitHashPolicy is the transmit hash policy used to select the bond member
+	// when using the balance-xor, 802.3ad and balance-tlb modes.
+	// +kubebuilder:validation:Enum=layer2;"layer3+4";"layer2+3";"encap2+3";"encap3+4"
+	// +optional
+	TransmitHashPolicy string `json:"transmitHashPolicy,omitempty"`
+}
+
+// NetworkVLANSpec defines a VLAN tagged sub-interface of a network device.
+type NetworkVLANSpec struct {
+	// ID is the VLAN ID.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=4094
+	ID int32 `json:"id"`
+
+	// Name is the name of the sub-interface in the guest operating system.
+	// Defaults to <device name>.<id>, e.g. eth0.100.
+	// +kubebuilder:validation:MaxLength=15
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// DHCP4 is a flag that indicates whether or not to use DHCP for IPv4
+	// on this sub-interface.
+	// +optional
+	DHCP4 bool `json:"dhcp4,omitempty"`
+
+	// DHCP6 is a flag that indicates whether or not to use DHCP for IPv6
+	// on this sub-interface.
+	// +optional
+	DHCP6 bool `json:"dhcp6,omitempty"`
+
+	// Gateway4 is the IPv4 gateway used by this sub-interface.
+	// +optional
+	Gateway4 string `json:"gateway4,omitempty"`
+
+	// Gateway6 is the IPv6 gateway used by this sub-interface.
+	// +optional
+	Gateway6 string `json:"gateway6,omitempty"`
+
+	// IPAddrs is a list of one or more IPv4 and/or IPv6 addresses to assign
+	// to this sub-interface. IP addresses must also specify the segment length in
+	// CIDR notation.
+	// +optional
+	IPAddrs []string `json:"ipAddrs,omitempty"`
+
+	// MTU is the sub-interface's Maximum Transmission Unit size in bytes.
+	// +optional
+	MTU *int64 `json:"mtu,omitempty"`
+
+	// Nameservers is a list of IPv4 and/or IPv6 addresses used as DNS
+	// nameservers.
+	// +optional
+	Nameservers []string `json:"nameservers,omitempty"`
+
+	// Routes is a list of optional, static routes applied to the sub-interface.
+	// +optional
+	Routes []NetworkRouteSpec `json:"routes,omitempty"`
+
+	// SearchDomains is a list of search domains used when resolving IP
+	// addresses with DNS.
+	// +optional
+	SearchDomains []string `json:"searchDomains,omitempty"`
 }
 
 // DHCPOverrides allows for the control over several DHCP behaviors.
@@ -523,6 +1129,9 @@ type VirtualMachine struct {
 	// BiosUUID is the VM's BIOS UUID.
 	BiosUUID string `json:"biosUUID"`
 
+	// InstanceUUID is the VM's instance UUID.
+	InstanceUUID string `json:"instanceUUID,omitempty"`
+
 	// State is the VM's state.
 	State VirtualMachineState `json:"state"`
 
@@ -17,6 +17,8 @@ limitations under the License.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
@@ -26,6 +28,10 @@ const (
 	// resources associated with VSphereCluster before removing it from the
 	// API server.
 	ClusterFinalizer = "vspherecluster.infrastructure.cluster.x-k8s.io"
+
+	// ControlPlaneEndpointIPAddressClaimFinalizer prevents the IPAddressClaim of the control plane
+	// endpoint managed by CAPV from being deleted before the VSphereCluster.
+	ControlPlaneEndpointIPAddressClaimFinalizer = "vspherecluster.infrastructure.cluster.x-k8s.io/ip-claim-protection"
 )
 
 // VCenterVersion conveys the API version of the vCenter instance.
@@ -42,6 +48,8 @@ type VSphereClusterSpec struct {
 	Server string `json:"server,omitempty"`
 
 	// Thumbprint is the colon-separated SHA-1 checksum of the given vCenter server's host certificate
+	// Consider using TLSConfig instead, which does not require updating the thumbprint
+	// when the certificate of vCenter is rotated.
 	// +optional
 	Thumbprint string `json:"thumbprint,omitempty"`
 
@@ -65,6 +73,13 @@ type VSphereClusterSpec struct {
 	// +optional
 	DisableClusterModule bool `json:"disableClusterModule,omitempty"`
 
+	// ClusterModuleLifecycle configures how cluster modules are named, shared between
+	// MachineDeployments and garbage collected once they are no longer used.
+	// If not set, every KubeadmControlPlane and MachineDeployment gets its own cluster module,
+	// which is deleted as soon as the object is deleted.
+	// +optional
+	ClusterModuleLifecycle *ClusterModuleLifecycle `json:"clusterModuleLifecycle,omitempty"`
+
 	// FailureDomainSelector is the label selector to use for failure domain selection
 	// for the control plane nodes of the cluster.
 	// If not set (`nil`), selecting failure domains will be disabled.
@@ -72,6 +87,162 @@ type VSphereClusterSpec struct {
 	// A valid selector will select all failure domains which match the selector.
 	// +optional
 	FailureDomainSelector *metav1.LabelSelector `json:"failureDomainSelector,omitempty"`
+
+	// HostEvacuation defines how the Nodes of the cluster are handled when their VMs
+	// are about to be moved off their ESXi host, e.g. because of DRS or host maintenance.
+	// +optional
+	HostEvacuation *HostEvacuationSpec `json:"hostEvacuation,omitempty"`
+
+	// Proxy is the proxy used for the connections to the vSphere endpoint.
+	// If not set, the proxy configured for the controller manager is used.
+	// +optional
+	Proxy *VCenterProxySpec `json:"proxy,omitempty"`
+
+	// TLSConfig is the TLS configuration used for the connections to the vSphere endpoint.
+	// If not set, the TLS configuration of the referenced VSphereClusterIdentity is used.
+	// +optional
+	TLSConfig *VCenterTLSConfig `json:"tlsConfig,omitempty"`
+
+	// CustomAttributes is a map of vSphere custom attribute names to values which are
+	// set on all virtual machines of the cluster, e.g. owner or cost center.
+	// Values set for the same custom attribute in the VSphereMachine take precedence.
+	// +optional
+	CustomAttributes map[string]string `json:"customAttributes,omitempty"`
+
+	// FolderNamingStrategy allows placing the virtual machines of the cluster in a folder of
+	// their own, which is created in the folder of the virtual machines if it doesn't exist.
+	// If not set, the virtual machines are placed directly in their folder.
+	// The strategy only applies to virtual machines created after it is set.
+	// +optional
+	FolderNamingStrategy *VSphereFolderNamingStrategy `json:"folderNamingStrategy,omitempty"`
+
+	// ControlPlaneEndpointManagement configures CAPV to manage the control plane endpoint of the cluster:
+	// an IP address is allocated from an IPAM pool, set as the control plane endpoint and served by
+	// kube-vip, which is added to the bootstrap data of the control plane machines.
+	// If set, ControlPlaneEndpoint is set by CAPV and must not be set.
+	// +optional
+	ControlPlaneEndpointManagement *ControlPlaneEndpointManagementSpec `json:"controlPlaneEndpointManagement,omitempty"`
+}
+
+// ControlPlaneEndpointManagementSpec configures the control plane endpoint managed by CAPV.
+type ControlPlaneEndpointManagementSpec struct {
+	// AddressesFromPool is a reference to the IPAM pool the IP address of the control plane endpoint
+	// is allocated from, e.g. an InClusterIPPool.
+	AddressesFromPool corev1.TypedLocalObjectReference `json:"addressesFromPool"`
+
+	// Port is the port of the control plane endpoint.
+	// Defaults to 6443.
+	// +kubebuilder:default=6443
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// KubeVIP configures the kube-vip static pod serving the control plane endpoint.
+	// +optional
+	KubeVIP KubeVIPSpec `json:"kubeVIP,omitempty"`
+}
+
+// KubeVIPSpec configures the kube-vip static pod added to the control plane machines.
+type KubeVIPSpec struct {
+	// Image is the kube-vip image.
+	// If not set, the image of the kube-vip manifest shipped with CAPV is used.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Interface is the network interface of the control plane machines the IP address is announced on.
+	// If not set, kube-vip uses the interface of the default route.
+	// +optional
+	Interface string `json:"interface,omitempty"`
+}
+
+// VSphereFolderNamingStrategy defines the naming strategy for the folder of the virtual machines of a cluster.
+type VSphereFolderNamingStrategy struct {
+	// Template defines the template to use for generating the name of the folder.
+	// The templating has the following data available:
+	// * `.cluster.name`: The name of the Cluster object.
+	// * `.cluster.namespace`: The namespace of the Cluster object.
+	// The templating also has the following funcs available:
+	// * `trimSuffix`: same as strings.TrimSuffix
+	// * `trunc`: truncates a string, e.g. `trunc 2 "hello"` or `trunc -2 "hello"`
+	// Names are automatically truncated at 80 characters, and must not contain a slash.
+	// +kubebuilder:validation:MinLength=1
+	Template string `json:"template"`
+}
+
+// VCenterProxySpec defines the proxy used to connect to the vSphere endpoint.
+type VCenterProxySpec struct {
+	// URL is the URL of the HTTP or SOCKS5 proxy,
+	// e.g. http://proxy.example.com:3128 or socks5://proxy.example.com:1080.
+	// +kubebuilder:validation:Pattern=`^(http|socks5)://.+`
+	URL string `json:"url"`
+
+	// NoProxy is a list of hosts, domains (e.g. .example.com), IP addresses and CIDRs
+	// which are connected to directly instead of through the proxy.
+	// +optional
+	NoProxy []string `json:"noProxy,omitempty"`
+
+	// CABundle is a PEM encoded bundle of CA certificates used to verify the certificate of
+	// the vSphere endpoint, e.g. when the proxy intercepts TLS connections.
+	// The certificates are trusted in addition to the ones referenced by the TLS configuration.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+}
+
+// TLSVersion is a TLS protocol version.
+// +kubebuilder:validation:Enum="1.2";"1.3"
+type TLSVersion string
+
+const (
+	// TLSVersion12 is TLS 1.2.
+	TLSVersion12 = TLSVersion("1.2")
+	// TLSVersion13 is TLS 1.3.
+	TLSVersion13 = TLSVersion("1.3")
+)
+
+// VCenterTLSConfig is the TLS configuration used for the connections to vCenter.
+// If set, the certificate of vCenter is verified using the CA certificates of CASecretRef
+// or the system root CAs, unless InsecureSkipVerify is set. If a thumbprint is set as well,
+// certificates which are not trusted by the CA certificates are accepted if they match the thumbprint.
+type VCenterTLSConfig struct {
+	// CASecretRef references a Secret containing the PEM encoded CA certificates used to verify
+	// the certificate of vCenter. The certificates are trusted in addition to the CA bundle of
+	// the proxy. If neither is set, the system root CAs are used.
+	// +optional
+	CASecretRef *CASecretReference `json:"caSecretRef,omitempty"`
+
+	// InsecureSkipVerify disables the verification of the certificate of vCenter.
+	// This should only be used for testing.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+
+	// MinVersion is the minimum TLS version used for the connections to vCenter.
+	// If not set, TLS 1.2 is used.
+	// +optional
+	MinVersion TLSVersion `json:"minVersion,omitempty"`
+}
+
+// CASecretReference references a key of a Secret containing PEM encoded CA certificates.
+type CASecretReference struct {
+	// Name of the Secret. The Secret must be in the namespace of the VSphereCluster or, if referenced
+	// by a VSphereClusterIdentity, in the namespace of the controller manager.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Key of the Secret containing the CA certificates.
+	// If not set, ca.crt is used.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// HostEvacuationSpec defines how Nodes are handled when their VMs are evacuated from an ESXi host.
+type HostEvacuationSpec struct {
+	// DrainNodesWithLocalStorage, if true, cordons and drains the Node of a VM which uses local
+	// (not shared) storage before the VM gets migrated by DRS or its host enters maintenance mode.
+	// The Node is uncordoned once the evacuation is completed.
+	// Requires the HostEvacuation feature gate.
+	// +optional
+	DrainNodesWithLocalStorage bool `json:"drainNodesWithLocalStorage,omitempty"`
 }
 
 // ClusterModule holds the anti affinity construct `ClusterModule` identifier
@@ -88,6 +259,40 @@ type ClusterModule struct {
 
 	// ModuleUUID is the unique identifier of the `ClusterModule` used by the object.
 	ModuleUUID string `json:"moduleUUID"`
+
+	// Name is the name of the cluster module rendered from the naming template.
+	// Objects with the same name share the `ClusterModule`.
+	// If empty, the TargetObjectName is used as the name.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// UnusedSince is the time since when the `ClusterModule` is no longer used by any object.
+	// The `ClusterModule` is deleted once the deletion grace period has passed.
+	// +optional
+	UnusedSince *metav1.Time `json:"unusedSince,omitempty"`
+}
+
+// ClusterModuleLifecycle configures the lifecycle of the cluster modules of a cluster.
+type ClusterModuleLifecycle struct {
+	// NameTemplate is the Go template used to generate the name of the cluster module
+	// of a MachineDeployment. MachineDeployments for which the same name is generated share
+	// a single cluster module, so their VMs are spread across hosts together.
+	// MachineDeployments sharing a cluster module must use the same compute cluster.
+	// The following variables can be used in the template:
+	// `.cluster.name`, `.machineDeployment.name` and `.machineDeployment.labels`.
+	// The control plane always uses a dedicated cluster module.
+	// Defaults to `{{ .machineDeployment.name }}`, which creates one cluster module per MachineDeployment.
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	NameTemplate *string `json:"nameTemplate,omitempty"`
+
+	// DeletionGracePeriod is the duration for which a cluster module which is no longer
+	// used by any object is kept before it is deleted. Objects created within the grace
+	// period for which the same name is generated reuse the cluster module.
+	// Cluster modules are always deleted immediately when the cluster is deleted.
+	// Defaults to 0, which deletes unused cluster modules immediately.
+	// +optional
+	DeletionGracePeriod *metav1.Duration `json:"deletionGracePeriod,omitempty"`
 }
 
 // VSphereClusterStatus defines the observed state of VSphereClusterSpec.
@@ -104,6 +309,54 @@ type VSphereClusterStatus struct {
 
 	// VCenterVersion defines the version of the vCenter server defined in the spec.
 	VCenterVersion VCenterVersion `json:"vCenterVersion,omitempty"`
+
+	// ResourceUsage is the vSphere resource usage of the VMs of the cluster.
+	// It is refreshed periodically.
+	// +optional
+	ResourceUsage *VSphereClusterResourceUsage `json:"resourceUsage,omitempty"`
+
+	// ControlPlaneEndpoint is the control plane endpoint allocated when ControlPlaneEndpointManagement is set.
+	// +optional
+	ControlPlaneEndpoint *APIEndpoint `json:"controlPlaneEndpoint,omitempty"`
+
+	// DeprecatedFieldUsage summarizes the deprecated fields set on the VSphereMachines and
+	// VSphereVMs of the cluster, along with how to migrate away from them.
+	// +optional
+	// +listType=atomic
+	DeprecatedFieldUsage []VSphereClusterDeprecatedFieldUsage `json:"deprecatedFieldUsage,omitempty"`
+}
+
+// VSphereClusterDeprecatedFieldUsage is the usage of a deprecated field by the objects of a cluster.
+type VSphereClusterDeprecatedFieldUsage struct {
+	// Kind is the kind of the objects setting the deprecated field.
+	Kind string `json:"kind"`
+
+	// Field is the path of the deprecated field.
+	Field string `json:"field"`
+
+	// Count is the number of objects setting the deprecated field.
+	Count int32 `json:"count"`
+
+	// Migration describes how to stop using the deprecated field.
+	Migration string `json:"migration"`
+}
+
+// VSphereClusterResourceUsage is the vSphere resource usage aggregated over the VMs of a cluster.
+type VSphereClusterResourceUsage struct {
+	// Machines is the number of VMs the resource usage is aggregated from.
+	Machines int32 `json:"machines"`
+
+	// CPU is the number of virtual CPUs of the VMs.
+	CPU resource.Quantity `json:"cpu"`
+
+	// Memory is the memory of the VMs.
+	Memory resource.Quantity `json:"memory"`
+
+	// Storage is the storage committed by the VMs on the datastores.
+	Storage resource.Quantity `json:"storage"`
+
+	// LastUpdated is the time the resource usage was last refreshed.
+	LastUpdated metav1.Time `json:"lastUpdated"`
 }
 
 // +kubebuilder:object:root=true
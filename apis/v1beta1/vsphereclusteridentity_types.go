@@ -30,15 +30,50 @@ const (
 
 // VSphereClusterIdentitySpec contains a secret reference and a group of allowed namespaces.
 type VSphereClusterIdentitySpec struct {
-	// SecretName references a Secret inside the controller namespace with the credentials to use
+	// SecretName references a Secret inside the controller namespace with the credentials to use.
+	// Either secretName or tokenExchange must be set.
 	// +kubebuilder:validation:MinLength=1
 	SecretName string `json:"secretName,omitempty"`
 
+	// TokenExchange configures logging in to vCenter with tokens of a ServiceAccount instead of
+	// the credentials of a Secret. The tokens are exchanged for SAML tokens by the vCenter single
+	// sign-on service, which requires an identity provider trusting the ServiceAccount tokens
+	// of the management cluster to be configured in vCenter.
+	// Either secretName or tokenExchange must be set.
+	// +optional
+	TokenExchange *VSphereTokenExchange `json:"tokenExchange,omitempty"`
+
 	// AllowedNamespaces is used to identify which namespaces are allowed to use this account.
 	// Namespaces can be selected with a label selector.
 	// If this object is nil, no namespaces will be allowed
 	// +optional
 	AllowedNamespaces *AllowedNamespaces `json:"allowedNamespaces,omitempty"`
+
+	// AllowedTargets restricts the parts of the vCenter inventory the VSphereClusters using this
+	// identity can operate on, independent of the privileges of its vCenter user.
+	// If this object is nil, the whole inventory is allowed.
+	// +optional
+	AllowedTargets *AllowedTargets `json:"allowedTargets,omitempty"`
+
+	// TLSConfig is the TLS configuration used for the connections to vCenter by the
+	// VSphereClusters using this identity, unless they set their own TLS configuration.
+	// +optional
+	TLSConfig *VCenterTLSConfig `json:"tlsConfig,omitempty"`
+}
+
+// VSphereTokenExchange configures the ServiceAccount whose tokens are exchanged for SAML tokens
+// to log in to vCenter.
+type VSphereTokenExchange struct {
+	// ServiceAccountName is the name of the ServiceAccount inside the controller namespace
+	// whose tokens are exchanged.
+	// +kubebuilder:validation:MinLength=1
+	ServiceAccountName string `json:"serviceAccountName"`
+
+	// Audience is the audience of the ServiceAccount tokens, which has to match the audience
+	// configured for the identity provider in vCenter.
+	// Defaults to "vcenter".
+	// +optional
+	Audience string `json:"audience,omitempty"`
 }
 
 // VSphereClusterIdentityStatus contains the status of the VSphereClusterIdentity.
@@ -58,6 +93,22 @@ type AllowedNamespaces struct {
 	Selector metav1.LabelSelector `json:"selector"`
 }
 
+// AllowedTargets restricts the vCenter inventory this VSphereClusterIdentity can be used for.
+type AllowedTargets struct {
+	// Datacenters are the names or inventory paths of the datacenters sessions using this
+	// identity can be created for. All datacenters are allowed if empty.
+	// +optional
+	// +kubebuilder:validation:items:MinLength=1
+	Datacenters []string `json:"datacenters,omitempty"`
+
+	// ResourcePools are the inventory paths of the resource pools VMs using this identity
+	// can be created in, including their descendant resource pools, e.g.
+	// /dc0/host/cluster0/Resources/tenant-a. All resource pools are allowed if empty.
+	// +optional
+	// +kubebuilder:validation:items:Pattern=`^/`
+	ResourcePools []string `json:"resourcePools,omitempty"`
+}
+
 // VSphereIdentityKind is the kind of mechanism used to handle credentials for the VCenter API.
 type VSphereIdentityKind string
 
@@ -91,6 +142,7 @@ func (c *VSphereClusterIdentity) SetConditions(conditions clusterv1.Conditions)
 
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=vsphereclusteridentities,scope=Cluster,categories=cluster-api
+// +kubebuilder:metadata:labels="clusterctl.cluster.x-k8s.io/move-hierarchy=true"
 // +kubebuilder:storageversion
 // +kubebuilder:subresource:status
 
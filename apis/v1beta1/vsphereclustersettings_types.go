@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VSphereClusterSettingsSpec defines the default values applied to the VSphereClusters,
+// VSphereMachineTemplates and VSphereVMs of a namespace.
+type VSphereClusterSettingsSpec struct {
+	// ClusterName is the name of the Cluster the settings apply to.
+	// If empty, the settings apply to all the Clusters in the namespace of the
+	// VSphereClusterSettings. Settings of a Cluster take precedence over the settings
+	// of the namespace.
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// Datastore is the datastore set on VSphereMachineTemplates and VSphereVMs
+	// which don't define a datastore.
+	// +optional
+	Datastore string `json:"datastore,omitempty"`
+
+	// Folder is the folder set on VSphereMachineTemplates and VSphereVMs
+	// which don't define a folder.
+	// +optional
+	Folder string `json:"folder,omitempty"`
+
+	// NetworkName is the network set on the network devices of VSphereMachineTemplates
+	// and VSphereVMs which don't define a network.
+	// +optional
+	NetworkName string `json:"networkName,omitempty"`
+
+	// IdentityRef is the identity set on VSphereClusters which don't define an identity.
+	// +optional
+	IdentityRef *VSphereIdentityReference `json:"identityRef,omitempty"`
+
+	// Hardware is the minimum hardware of the VMs of VSphereMachineTemplates and VSphereVMs.
+	// +optional
+	Hardware *VSphereClusterHardwareMinimums `json:"hardware,omitempty"`
+}
+
+// VSphereClusterHardwareMinimums defines the minimum hardware of VMs. VSphereMachineTemplates
+// and VSphereVMs which don't define the hardware or define less are raised to the minimums.
+type VSphereClusterHardwareMinimums struct {
+	// NumCPUs is the minimum number of virtual processors of a VM.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	NumCPUs int32 `json:"numCPUs,omitempty"`
+
+	// MemoryMiB is the minimum size of the memory of a VM, in MiB.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MemoryMiB int64 `json:"memoryMiB,omitempty"`
+
+	// DiskGiB is the minimum size of the disk of a VM, in GiB.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	DiskGiB int32 `json:"diskGiB,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=vsphereclustersettings,scope=Namespaced,categories=cluster-api
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterName",description="Cluster the settings apply to, all the Clusters of the namespace if empty"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of VSphereClusterSettings"
+
+// VSphereClusterSettings is the Schema for the vsphereclustersettings API.
+// It defines default values which are applied by the webhooks to the VSphereClusters,
+// VSphereMachineTemplates and VSphereVMs created in its namespace when they don't set them.
+type VSphereClusterSettings struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec VSphereClusterSettingsSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VSphereClusterSettingsList contains a list of VSphereClusterSettings.
+type VSphereClusterSettingsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VSphereClusterSettings `json:"items"`
+}
+
+func init() {
+	objectTypes = append(objectTypes, &VSphereClusterSettings{}, &VSphereClusterSettingsList{})
+}
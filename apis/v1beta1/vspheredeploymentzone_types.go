@@ -88,6 +88,7 @@ type VSphereDeploymentZoneStatus struct {
 // +kubebuilder:object:root=true
 // +kubebuilder:storageversion
 // +kubebuilder:resource:path=vspheredeploymentzones,scope=Cluster,categories=cluster-api
+// +kubebuilder:metadata:labels="clusterctl.cluster.x-k8s.io/move-hierarchy=true"
 // +kubebuilder:subresource:status
 
 // VSphereDeploymentZone is the Schema for the vspheredeploymentzones API.
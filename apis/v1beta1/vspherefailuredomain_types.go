@@ -92,6 +92,38 @@ type Topology struct {
 	// virtual machine is created/located.
 	// +optional
 	Datastore string `json:"datastore,omitempty"`
+
+	// VSAN describes the site of a vSAN stretched cluster the failure domain is placed in.
+	// It requires Hosts, whose host group must only contain hosts of the site.
+	// +optional
+	VSAN *VSANTopology `json:"vsan,omitempty"`
+}
+
+// VSANSiteAffinity defines how strictly the VMs of a failure domain are kept in its vSAN site.
+type VSANSiteAffinity string
+
+const (
+	// VSANSiteAffinityPreferred places the VMs in the site, but lets vSphere HA restart them in the
+	// other site if the site fails. The VM-Host affinity rule must be a "should run on" rule.
+	VSANSiteAffinityPreferred VSANSiteAffinity = "Preferred"
+	// VSANSiteAffinityRequired keeps the VMs in the site, even if the site fails.
+	// The VM-Host affinity rule must be a "must run on" rule.
+	VSANSiteAffinityRequired VSANSiteAffinity = "Required"
+)
+
+// VSANTopology describes the site of a vSAN stretched cluster.
+type VSANTopology struct {
+	// FaultDomain is the name of the vSAN fault domain, i.e. the site of the stretched cluster,
+	// the hosts of the failure domain belong to.
+	// +kubebuilder:validation:MinLength=1
+	FaultDomain string `json:"faultDomain"`
+
+	// SiteAffinity defines how strictly the VMs are kept in the site, either "Preferred" or "Required".
+	// Defaults to "Preferred".
+	// +kubebuilder:validation:Enum=Preferred;Required
+	// +kubebuilder:default=Preferred
+	// +optional
+	SiteAffinity VSANSiteAffinity `json:"siteAffinity,omitempty"`
 }
 
 // NetworkConfiguration defines a network configuration that should be used when consuming
@@ -157,6 +189,7 @@ type FailureDomainHosts struct {
 // +kubebuilder:object:root=true
 // +kubebuilder:storageversion
 // +kubebuilder:resource:path=vspherefailuredomains,scope=Cluster,categories=cluster-api
+// +kubebuilder:metadata:labels="clusterctl.cluster.x-k8s.io/move-hierarchy=true"
 
 // VSphereFailureDomain is the Schema for the vspherefailuredomains API.
 type VSphereFailureDomain struct {
@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VSphereForceDeletePolicySpec defines when the finalizers of the deleted VSphereVMs and VSphereClusters of the
+// selected namespaces and clusters may be removed without cleaning up vSphere.
+type VSphereForceDeletePolicySpec struct {
+	// NamespaceSelector selects the namespaces the policy applies to.
+	// An empty selector selects all namespaces.
+	// +optional
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// ClusterSelector selects the Clusters the policy applies to by their labels.
+	// An empty selector selects all Clusters, as well as objects which don't belong to a Cluster.
+	// +optional
+	ClusterSelector metav1.LabelSelector `json:"clusterSelector,omitempty"`
+
+	// VCenterUnreachableTimeout is how long vCenter has to be unreachable while an object is deleted
+	// before its finalizer is removed without cleaning up vSphere, e.g. without destroying its VM.
+	// The time is measured from the later of the deletion of the object and the moment vCenter
+	// became unreachable, as reported by its VCenterAvailable condition.
+	VCenterUnreachableTimeout metav1.Duration `json:"vCenterUnreachableTimeout"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=vsphereforcedeletepolicies,scope=Cluster,categories=cluster-api
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Timeout",type="string",JSONPath=".spec.vCenterUnreachableTimeout",description="Time vCenter has to be unreachable before finalizers are removed"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of VSphereForceDeletePolicy"
+
+// VSphereForceDeletePolicy is the Schema for the vsphereforcedeletepolicies API.
+// It allows to remove the finalizers of the objects of the selected namespaces and clusters once vCenter
+// has been unreachable for too long, so that deleting them doesn't block the deletion of their namespace
+// during a vCenter outage. The vSphere objects, e.g. VMs, are left behind and have to be cleaned up manually.
+// Objects selected by multiple policies are force deleted once the shortest timeout expires.
+type VSphereForceDeletePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec VSphereForceDeletePolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VSphereForceDeletePolicyList contains a list of VSphereForceDeletePolicy.
+type VSphereForceDeletePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VSphereForceDeletePolicy `json:"items"`
+}
+
+func init() {
+	objectTypes = append(objectTypes, &VSphereForceDeletePolicy{}, &VSphereForceDeletePolicyList{})
+}
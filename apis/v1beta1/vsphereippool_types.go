@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// IPPoolAllocationFinalizer allows the VSphereIPPool controller to release the
+	// IP address allocated in vCenter for an IPAddressClaim before it is removed.
+	IPPoolAllocationFinalizer = "vsphereippool.infrastructure.cluster.x-k8s.io/ip-allocation"
+
+	// VSphereIPPoolKind is the kind of the VSphereIPPool, which is set in the poolRef
+	// of the IPAddressClaims allocating IP addresses from a vCenter IP pool.
+	VSphereIPPoolKind = "VSphereIPPool"
+)
+
+// VSphereIPPoolSpec defines the vCenter IP pool IP addresses are allocated from.
+type VSphereIPPoolSpec struct {
+	// Server is the IP address or FQDN of the vCenter server of the IP pool.
+	// +kubebuilder:validation:MinLength=1
+	Server string `json:"server"`
+
+	// Thumbprint is the colon-separated SHA-1 checksum of the given vCenter server's host certificate.
+	// +optional
+	Thumbprint string `json:"thumbprint,omitempty"`
+
+	// Datacenter is the name of the datacenter of the IP pool.
+	// +kubebuilder:validation:MinLength=1
+	Datacenter string `json:"datacenter"`
+
+	// PoolName is the name of the IP pool in vCenter.
+	// +kubebuilder:validation:MinLength=1
+	PoolName string `json:"poolName"`
+
+	// IdentityRef is the identity used to connect to vCenter.
+	// If not set, the credentials provided to the controller manager are used.
+	// +optional
+	IdentityRef *VSphereIdentityReference `json:"identityRef,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=vsphereippools,scope=Namespaced,categories=cluster-api
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Server",type="string",JSONPath=".spec.server",description="Server is the address of the vSphere endpoint"
+// +kubebuilder:printcolumn:name="Pool",type="string",JSONPath=".spec.poolName",description="Name of the IP pool in vCenter"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of VSphereIPPool"
+
+// VSphereIPPool is the Schema for the vsphereippools API.
+// It implements the Cluster API IPAM provider contract: the IPAddressClaims referencing it
+// get IP addresses allocated from the vCenter IP pool.
+type VSphereIPPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec VSphereIPPoolSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VSphereIPPoolList contains a list of VSphereIPPool.
+type VSphereIPPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VSphereIPPool `json:"items"`
+}
+
+func init() {
+	objectTypes = append(objectTypes, &VSphereIPPool{}, &VSphereIPPoolList{})
+}
@@ -68,6 +68,38 @@ type VSphereMachineSpec struct {
 	//
 	// +optional
 	GuestSoftPowerOffTimeout *metav1.Duration `json:"guestSoftPowerOffTimeout,omitempty"`
+
+	// GuestReadinessGates is a list of guest conditions which must be true
+	// before the VM is marked as provisioned. By default a VM is marked as
+	// provisioned as soon as it is powered on and reports IP addresses,
+	// regardless of the state of the guest agent.
+	// +optional
+	// +listType=set
+	GuestReadinessGates []GuestReadinessGate `json:"guestReadinessGates,omitempty"`
+
+	// NamingStrategy allows configuring the naming strategy used when calculating the name of the VSphereVM
+	// and of the virtual machine in vCenter.
+	// +optional
+	NamingStrategy *VSphereVMNamingStrategy `json:"namingStrategy,omitempty"`
+}
+
+// VSphereVMNamingStrategy defines the naming strategy for the VSphereVMs of VSphereMachines.
+type VSphereVMNamingStrategy struct {
+	// Template defines the template to use for generating the name of the VSphereVM object.
+	// If not defined, it will fall back to `{{ .machine.name }}`.
+	// The templating has the following data available:
+	// * `.machine.name`: The name of the Machine object.
+	// The templating also has the following funcs available:
+	// * `trimSuffix`: same as strings.TrimSuffix
+	// * `trunc`: truncates a string, e.g. `trunc 2 "hello"` or `trunc -2 "hello"`
+	// Notes:
+	// * Generated names must be valid Kubernetes names as they are used to create a VSphereVM object
+	//   and usually also as the name of the virtual machine and of the Node object.
+	// * Names are automatically truncated at 63 characters. Please note that this can lead to name conflicts,
+	//   so we highly recommend to use a template which leads to a name shorter than 63 characters.
+	// * Names of Windows machines are additionally shortened to 15 characters.
+	// +optional
+	Template *string `json:"template,omitempty"`
 }
 
 // VSphereMachineStatus defines the observed state of VSphereMachine.
@@ -84,6 +116,13 @@ type VSphereMachineStatus struct {
 	// +optional
 	Network []NetworkStatus `json:"network,omitempty"`
 
+	// FailureDomain is the name of the VSphereDeploymentZone the control plane machine was
+	// placed in, if the Machine does not define a failure domain.
+	// Control plane machines without a failure domain are spread across the ready
+	// VSphereDeploymentZones suitable for control plane machines.
+	// +optional
+	FailureDomain *string `json:"failureDomain,omitempty"`
+
 	// FailureReason will be set in the event that there is a terminal problem
 	// reconciling the Machine and will contain a succinct value suitable
 	// for machine interpretation.
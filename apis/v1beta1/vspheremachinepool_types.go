@@ -0,0 +1,268 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/errors"
+)
+
+const (
+	// MachinePoolFinalizer allows ReconcileVSphereMachinePool to clean up the
+	// VSphereVMs associated with VSphereMachinePool before removing it from the
+	// API Server.
+	MachinePoolFinalizer = "vspheremachinepool.infrastructure.cluster.x-k8s.io"
+
+	// MachinePoolNameLabel is the label set on the VSphereVMs which are instances
+	// of a VSphereMachinePool.
+	MachinePoolNameLabel = "vspheremachinepool.infrastructure.cluster.x-k8s.io/name"
+
+	// MachinePoolTemplateHashLabel is the label set on the VSphereVMs of a VSphereMachinePool
+	// with the hash of the instance template the VSphereVM has been created from.
+	MachinePoolTemplateHashLabel = "vspheremachinepool.infrastructure.cluster.x-k8s.io/template-hash"
+)
+
+// VSphereMachinePoolStrategyType is the type of the strategy used to replace
+// the instances of a VSphereMachinePool.
+type VSphereMachinePoolStrategyType string
+
+const (
+	// RollingUpdateVSphereMachinePoolStrategyType replaces outdated instances
+	// with new ones gradually, respecting MaxSurge and MaxUnavailable.
+	RollingUpdateVSphereMachinePoolStrategyType VSphereMachinePoolStrategyType = "RollingUpdate"
+)
+
+// VSphereMachinePoolDeletePolicy defines the order in which the instances of a
+// VSphereMachinePool are deleted.
+type VSphereMachinePoolDeletePolicy string
+
+const (
+	// OldestVSphereMachinePoolDeletePolicy deletes the oldest instances first.
+	OldestVSphereMachinePoolDeletePolicy VSphereMachinePoolDeletePolicy = "Oldest"
+
+	// NewestVSphereMachinePoolDeletePolicy deletes the newest instances first.
+	NewestVSphereMachinePoolDeletePolicy VSphereMachinePoolDeletePolicy = "Newest"
+
+	// RandomVSphereMachinePoolDeletePolicy deletes random instances.
+	RandomVSphereMachinePoolDeletePolicy VSphereMachinePoolDeletePolicy = "Random"
+)
+
+// VSphereMachinePoolSpec defines the desired state of VSphereMachinePool.
+type VSphereMachinePoolSpec struct {
+	// ProviderIDList are the identification IDs of machine instances provided by the provider.
+	// This field must match the provider IDs as seen on the node objects corresponding to a machine pool's machine instances.
+	// +optional
+	ProviderIDList []string `json:"providerIDList,omitempty"`
+
+	// Template describes the virtual machines created for the instances of the pool.
+	Template VSphereMachinePoolInstanceTemplate `json:"template"`
+
+	// NamingStrategy allows configuring the naming strategy used when calculating
+	// the name of the VSphereVMs created for the instances of the pool.
+	// +optional
+	NamingStrategy *VSphereMachinePoolNamingStrategy `json:"namingStrategy,omitempty"`
+
+	// Strategy defines how the instances of the pool are replaced after the
+	// instance template has changed and which instances are deleted when scaling down.
+	// +optional
+	Strategy VSphereMachinePoolStrategy `json:"strategy,omitempty"`
+}
+
+// VSphereMachinePoolInstanceTemplate describes the virtual machines created for
+// the instances of a VSphereMachinePool.
+type VSphereMachinePoolInstanceTemplate struct {
+	VirtualMachineCloneSpec `json:",inline"`
+
+	// PowerOffMode describes the desired behavior when powering off a VM.
+	// Refer to VSphereMachineSpec.PowerOffMode for the supported modes.
+	//
+	// If omitted, the mode defaults to hard.
+	//
+	// +optional
+	// +kubebuilder:default=hard
+	PowerOffMode VirtualMachinePowerOpMode `json:"powerOffMode,omitempty"`
+
+	// GuestSoftPowerOffTimeout sets the wait timeout for shutdown in the VM guest.
+	// The VM will be powered off forcibly after the timeout if the VM is still
+	// up and running when the PowerOffMode is set to trySoft.
+	//
+	// This parameter only applies when the PowerOffMode is set to trySoft.
+	//
+	// If omitted, the timeout defaults to 5 minutes.
+	//
+	// +optional
+	GuestSoftPowerOffTimeout *metav1.Duration `json:"guestSoftPowerOffTimeout,omitempty"`
+
+	// GuestReadinessGates is a list of guest conditions which must be true
+	// before the VM is marked as provisioned.
+	// +optional
+	// +listType=set
+	GuestReadinessGates []GuestReadinessGate `json:"guestReadinessGates,omitempty"`
+}
+
+// VSphereMachinePoolNamingStrategy defines the naming strategy for the VSphereVMs
+// of a VSphereMachinePool.
+type VSphereMachinePoolNamingStrategy struct {
+	// Template defines the template to use for generating the names of the VSphereVMs.
+	// If not defined, it will fall back to `{{ .machinePool.name }}-{{ .random }}`.
+	// If the generated name string exceeds 63 characters, it will be trimmed to 58 characters and will
+	// get concatenated with a random suffix of length 5.
+	// The templating mechanism provides the following arguments:
+	// * `.machinePool.name`: The name of the VSphereMachinePool.
+	// * `.random`: A random alphanumeric string, without vowels, of length 5.
+	// +optional
+	Template *string `json:"template,omitempty"`
+}
+
+// VSphereMachinePoolStrategy defines how the instances of a VSphereMachinePool are replaced.
+type VSphereMachinePoolStrategy struct {
+	// Type of the strategy. Currently the only supported strategy is RollingUpdate.
+	// +kubebuilder:validation:Enum=RollingUpdate
+	// +optional
+	// +kubebuilder:default=RollingUpdate
+	Type VSphereMachinePoolStrategyType `json:"type,omitempty"`
+
+	// RollingUpdate configures the rolling update of the instances.
+	// +optional
+	RollingUpdate *VSphereMachinePoolRollingUpdate `json:"rollingUpdate,omitempty"`
+}
+
+// VSphereMachinePoolRollingUpdate configures the rolling update of the instances
+// of a VSphereMachinePool.
+type VSphereMachinePoolRollingUpdate struct {
+	// MaxUnavailable is the maximum number of instances that can be unavailable during the update.
+	// Value can be an absolute number (ex: 5) or a percentage of desired instances (ex: 10%).
+	// Absolute number is calculated from percentage by rounding down.
+	// This can not be 0 if MaxSurge is 0.
+	// Defaults to 0.
+	// +optional
+	// +kubebuilder:default:=0
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// MaxSurge is the maximum number of instances that can be created above the desired number of instances.
+	// Value can be an absolute number (ex: 5) or a percentage of desired instances (ex: 10%).
+	// Absolute number is calculated from percentage by rounding up.
+	// This can not be 0 if MaxUnavailable is 0.
+	// Defaults to 1.
+	// +optional
+	// +kubebuilder:default:=1
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+
+	// DeletePolicy defines the policy used to identify the instances to delete when
+	// scaling down or replacing outdated instances. Instances which are not ready are
+	// always deleted first.
+	// Valid values are "Oldest", "Newest" and "Random". Defaults to "Oldest".
+	// +kubebuilder:validation:Enum=Oldest;Newest;Random
+	// +optional
+	// +kubebuilder:default=Oldest
+	DeletePolicy VSphereMachinePoolDeletePolicy `json:"deletePolicy,omitempty"`
+}
+
+// VSphereMachinePoolStatus defines the observed state of VSphereMachinePool.
+type VSphereMachinePoolStatus struct {
+	// Ready is true once the initial instances of the VSphereMachinePool are ready.
+	// It stays true while the VSphereMachinePool is scaled or its instances are replaced.
+	// +optional
+	Ready bool `json:"ready"`
+
+	// Replicas is the most recently observed number of ready instances.
+	// +optional
+	Replicas int32 `json:"replicas"`
+
+	// Instances contains the status of the instances of the pool.
+	// +optional
+	Instances []VSphereMachinePoolInstanceStatus `json:"instances,omitempty"`
+
+	// FailureReason will be set in the event that there is a terminal problem
+	// reconciling the VSphereMachinePool and will contain a succinct value suitable
+	// for machine interpretation.
+	// +optional
+	FailureReason *errors.MachinePoolStatusFailure `json:"failureReason,omitempty"`
+
+	// FailureMessage will be set in the event that there is a terminal problem
+	// reconciling the VSphereMachinePool and will contain a more verbose string suitable
+	// for logging and human consumption.
+	// +optional
+	FailureMessage *string `json:"failureMessage,omitempty"`
+
+	// Conditions defines current service state of the VSphereMachinePool.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// VSphereMachinePoolInstanceStatus describes an instance of a VSphereMachinePool.
+type VSphereMachinePoolInstanceStatus struct {
+	// Name is the name of the VSphereVM of the instance.
+	Name string `json:"name"`
+
+	// ProviderID is the provider ID of the instance, formatted as
+	// vsphere://12345678-1234-1234-1234-123456789abc
+	// +optional
+	ProviderID string `json:"providerID,omitempty"`
+
+	// Ready is true when the VSphereVM of the instance is ready.
+	// +optional
+	Ready bool `json:"ready"`
+
+	// UpToDate is true when the instance has been created from the current instance template.
+	// +optional
+	UpToDate bool `json:"upToDate"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=vspheremachinepools,scope=Namespaced,categories=cluster-api
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".metadata.labels.cluster\\.x-k8s\\.io/cluster-name",description="Cluster to which this VSphereMachinePool belongs"
+// +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".status.replicas",description="Number of ready instances"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready",description="Machine pool ready status"
+// +kubebuilder:printcolumn:name="MachinePool",type="string",JSONPath=".metadata.ownerReferences[?(@.kind==\"MachinePool\")].name",description="MachinePool object which owns with this VSphereMachinePool",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of VSphereMachinePool"
+
+// VSphereMachinePool is the Schema for the vspheremachinepools API.
+type VSphereMachinePool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VSphereMachinePoolSpec   `json:"spec,omitempty"`
+	Status VSphereMachinePoolStatus `json:"status,omitempty"`
+}
+
+// GetConditions returns the conditions for a VSphereMachinePool.
+func (m *VSphereMachinePool) GetConditions() clusterv1.Conditions {
+	return m.Status.Conditions
+}
+
+// SetConditions sets the conditions on a VSphereMachinePool.
+func (m *VSphereMachinePool) SetConditions(conditions clusterv1.Conditions) {
+	m.Status.Conditions = conditions
+}
+
+// +kubebuilder:object:root=true
+
+// VSphereMachinePoolList contains a list of VSphereMachinePool.
+type VSphereMachinePoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VSphereMachinePool `json:"items"`
+}
+
+func init() {
+	objectTypes = append(objectTypes, &VSphereMachinePool{}, &VSphereMachinePoolList{})
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VSpherePlacementPolicySpec defines the vSphere inventory objects which the VSphereMachineTemplates,
+// VSphereMachines, VSphereMachinePools and VSphereVMs of the selected namespaces may reference.
+//
+// Each list restricts one kind of inventory object; an empty list does not restrict it. The entries are
+// matched against the values of the objects as written, e.g. a name or an inventory path, and may contain
+// the wildcards of shell file name patterns, e.g. /dc0/datastore/tenant-a-*. If a kind is restricted, the
+// objects must set it explicitly, so that the default chosen by vCenter can't be used to bypass the policy.
+type VSpherePlacementPolicySpec struct {
+	// NamespaceSelector selects the namespaces the policy applies to.
+	// An empty selector selects all namespaces.
+	// +optional
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Datastores are the datastores which may be used for VMs and their data disks.
+	// +optional
+	Datastores []string `json:"datastores,omitempty"`
+
+	// Networks are the networks, e.g. port groups, network devices may be connected to.
+	// +optional
+	Networks []string `json:"networks,omitempty"`
+
+	// Folders are the folders VMs may be created in.
+	// +optional
+	Folders []string `json:"folders,omitempty"`
+
+	// ResourcePools are the resource pools VMs may be created in.
+	// +optional
+	ResourcePools []string `json:"resourcePools,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=vsphereplacementpolicies,scope=Cluster,categories=cluster-api
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of VSpherePlacementPolicy"
+
+// VSpherePlacementPolicy is the Schema for the vsphereplacementpolicies API.
+// It restricts the vSphere inventory objects which the machines of the selected namespaces may use,
+// and is enforced by the validating webhooks when the machines are created. Objects in a namespace
+// selected by multiple policies must comply with all of them.
+type VSpherePlacementPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec VSpherePlacementPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VSpherePlacementPolicyList contains a list of VSpherePlacementPolicy.
+type VSpherePlacementPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VSpherePlacementPolicy `json:"items"`
+}
+
+func init() {
+	objectTypes = append(objectTypes, &VSpherePlacementPolicy{}, &VSpherePlacementPolicyList{})
+}
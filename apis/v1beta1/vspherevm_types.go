@@ -84,6 +84,66 @@ type VSphereVMSpec struct {
 	//
 	// +optional
 	GuestSoftPowerOffTimeout *metav1.Duration `json:"guestSoftPowerOffTimeout,omitempty"`
+
+	// GuestReadinessGates is a list of guest conditions which must be true
+	// before the VM is marked as provisioned. By default a VM is marked as
+	// provisioned as soon as it is powered on and reports IP addresses,
+	// regardless of the state of the guest agent.
+	// +optional
+	// +listType=set
+	GuestReadinessGates []GuestReadinessGate `json:"guestReadinessGates,omitempty"`
+
+	// RelocateTo triggers a storage and/or compute vMotion of the VM to the
+	// given datastore, host and resource pool, e.g. to evacuate a datastore
+	// without recreating the VM.
+	// The VM is relocated whenever it is not located at the given targets.
+	// +optional
+	RelocateTo *VSphereVMRelocateTo `json:"relocateTo,omitempty"`
+
+	// PowerState is the desired power state of the VM. It allows to temporarily
+	// power off or suspend the VM, e.g. to save costs in a lab, without deleting it.
+	// The VM is powered off according to the PowerOffMode.
+	// A VM which is intentionally not powered on is reported with the PoweredOff
+	// or Suspended reason on the VMProvisioned condition.
+	//
+	// If omitted, the VM is powered on.
+	//
+	// +optional
+	// +kubebuilder:validation:Enum=poweredOn;poweredOff;suspended
+	PowerState VirtualMachinePowerState `json:"powerState,omitempty"`
+}
+
+// VSphereVMRelocateTo describes the targets a VSphereVM is relocated to.
+// At least one of the targets must be set; targets which are not set are
+// left unchanged.
+type VSphereVMRelocateTo struct {
+	// Datastore is the name or inventory path of the datastore the VM home
+	// and all the disks of the VM are moved to.
+	// +optional
+	Datastore string `json:"datastore,omitempty"`
+
+	// Host is the name or inventory path of the ESXi host the VM is moved to.
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// ResourcePool is the name or inventory path of the resource pool the VM
+	// is moved to.
+	// +optional
+	ResourcePool string `json:"resourcePool,omitempty"`
+}
+
+// VSphereVMRelocationStatus describes the progress of the relocation of a VSphereVM.
+type VSphereVMRelocationStatus struct {
+	// Target is the target of the in-flight or of the last completed relocation.
+	Target VSphereVMRelocateTo `json:"target"`
+
+	// Progress is the progress of the relocation in percent.
+	// +optional
+	Progress int32 `json:"progress,omitempty"`
+
+	// CompletionTime is the time the relocation was completed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
 }
 
 // VSphereVMStatus defines the observed state of VSphereVM.
@@ -178,6 +238,18 @@ type VSphereVMStatus struct {
 	// This field is set once the machine is created and should not be changed
 	// +optional
 	VMRef string `json:"vmRef,omitempty"`
+
+	// InstanceUUID is the instance UUID of the VM, which is unique within a vCenter.
+	// With the InstanceUUIDLookup feature gate, the VM is looked up by its instance UUID
+	// once it is set, so it is found even if it has been renamed or another VM has the
+	// same BIOS UUID.
+	// +optional
+	InstanceUUID string `json:"instanceUUID,omitempty"`
+
+	// Relocation describes the progress of the relocation requested via
+	// spec.relocateTo.
+	// +optional
+	Relocation *VSphereVMRelocationStatus `json:"relocation,omitempty"`
 }
 
 // +kubebuilder:object:root=true
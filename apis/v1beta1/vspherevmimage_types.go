@@ -0,0 +1,162 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// ChecksumAlgorithm is the algorithm of a checksum of a content library item file.
+// +kubebuilder:validation:Enum=MD5;SHA1;SHA256;SHA512
+type ChecksumAlgorithm string
+
+const (
+	// ChecksumAlgorithmMD5 is the MD5 checksum algorithm.
+	ChecksumAlgorithmMD5 ChecksumAlgorithm = "MD5"
+
+	// ChecksumAlgorithmSHA1 is the SHA1 checksum algorithm.
+	ChecksumAlgorithmSHA1 ChecksumAlgorithm = "SHA1"
+
+	// ChecksumAlgorithmSHA256 is the SHA256 checksum algorithm.
+	ChecksumAlgorithmSHA256 ChecksumAlgorithm = "SHA256"
+
+	// ChecksumAlgorithmSHA512 is the SHA512 checksum algorithm.
+	ChecksumAlgorithmSHA512 ChecksumAlgorithm = "SHA512"
+)
+
+// VSphereVMImageSpec defines the VM template a node image is stored as in vCenter.
+// Exactly one of Template and ContentLibraryItem must be set.
+type VSphereVMImageSpec struct {
+	// Server is the IP address or FQDN of the vCenter server the image is stored on.
+	// +kubebuilder:validation:MinLength=1
+	Server string `json:"server"`
+
+	// Thumbprint is the colon-separated SHA-1 checksum of the vCenter server's host certificate.
+	// +optional
+	Thumbprint string `json:"thumbprint,omitempty"`
+
+	// Datacenter is the name, inventory path, managed object reference or the managed
+	// object ID of the datacenter the template is found in.
+	// Defaults to the default datacenter of the vCenter server.
+	// +optional
+	Datacenter string `json:"datacenter,omitempty"`
+
+	// Template is the name, inventory path, managed object reference or the managed
+	// object ID of the VM template.
+	// +optional
+	Template string `json:"template,omitempty"`
+
+	// ContentLibraryItem is the content library item of type vm-template containing the VM template.
+	// +optional
+	ContentLibraryItem *ContentLibraryItemReference `json:"contentLibraryItem,omitempty"`
+
+	// KubernetesVersion is the version of Kubernetes installed in the image.
+	// +optional
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+
+	// Checksums are checksums the files of the content library item must match.
+	// Each checksum must match one of the files of the item.
+	// They can only be set with ContentLibraryItem.
+	// +optional
+	// +listType=atomic
+	Checksums []VSphereVMImageChecksum `json:"checksums,omitempty"`
+}
+
+// ContentLibraryItemReference references an item of a content library.
+type ContentLibraryItemReference struct {
+	// Library is the name of the content library.
+	// +kubebuilder:validation:MinLength=1
+	Library string `json:"library"`
+
+	// Name is the name of the item in the content library.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// VSphereVMImageChecksum is a checksum of a file of a content library item.
+type VSphereVMImageChecksum struct {
+	// Algorithm is the algorithm of the checksum.
+	Algorithm ChecksumAlgorithm `json:"algorithm"`
+
+	// Value is the checksum in hexadecimal.
+	// +kubebuilder:validation:MinLength=1
+	Value string `json:"value"`
+}
+
+// VSphereVMImageStatus defines the observed state of VSphereVMImage.
+type VSphereVMImageStatus struct {
+	// Ready is true when the VM template of the image has been found in vCenter and verified.
+	// +optional
+	Ready bool `json:"ready,omitempty"`
+
+	// Template is the managed object ID of the VM template of the image, which is used
+	// to clone the VMs of the VSphereMachines referencing the image.
+	// +optional
+	Template string `json:"template,omitempty"`
+
+	// LastVerified is the time the image was last verified in vCenter.
+	// +optional
+	LastVerified *metav1.Time `json:"lastVerified,omitempty"`
+
+	// Conditions defines current service state of the VSphereVMImage.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=vspherevmimages,scope=Namespaced,categories=cluster-api
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Kubernetes Version",type="string",JSONPath=".spec.kubernetesVersion",description="Kubernetes version installed in the image"
+// +kubebuilder:printcolumn:name="Template",type="string",JSONPath=".status.template",description="Managed object ID of the VM template"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready",description="Image is verified in vCenter"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of VSphereVMImage"
+
+// VSphereVMImage is the Schema for the vspherevmimages API.
+// It represents a node image stored as a VM template in vCenter, which VSphereMachineTemplates
+// and VSphereMachines in the same namespace can reference instead of a template name.
+type VSphereVMImage struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VSphereVMImageSpec   `json:"spec,omitempty"`
+	Status VSphereVMImageStatus `json:"status,omitempty"`
+}
+
+// GetConditions returns the conditions for the VSphereVMImage.
+func (i *VSphereVMImage) GetConditions() clusterv1.Conditions {
+	return i.Status.Conditions
+}
+
+// SetConditions sets the conditions on the VSphereVMImage.
+func (i *VSphereVMImage) SetConditions(conditions clusterv1.Conditions) {
+	i.Status.Conditions = conditions
+}
+
+// +kubebuilder:object:root=true
+
+// VSphereVMImageList contains a list of VSphereVMImage.
+type VSphereVMImageList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VSphereVMImage `json:"items"`
+}
+
+func init() {
+	objectTypes = append(objectTypes, &VSphereVMImage{}, &VSphereVMImageList{})
+}
@@ -24,6 +24,7 @@ import (
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	apiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/errors"
 )
@@ -59,9 +60,78 @@ func (in *AllowedNamespaces) DeepCopy() *AllowedNamespaces {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AllowedTargets) DeepCopyInto(out *AllowedTargets) {
+	*out = *in
+	if in.Datacenters != nil {
+		in, out := &in.Datacenters, &out.Datacenters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResourcePools != nil {
+		in, out := &in.ResourcePools, &out.ResourcePools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AllowedTargets.
+func (in *AllowedTargets) DeepCopy() *AllowedTargets {
+	if in == nil {
+		return nil
+	}
+	out := new(AllowedTargets)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CASecretReference) DeepCopyInto(out *CASecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CASecretReference.
+func (in *CASecretReference) DeepCopy() *CASecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CASecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChildResourcePoolSpec) DeepCopyInto(out *ChildResourcePoolSpec) {
+	*out = *in
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		*out = new(ResourcePoolAllocation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		*out = new(ResourcePoolAllocation)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChildResourcePoolSpec.
+func (in *ChildResourcePoolSpec) DeepCopy() *ChildResourcePoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ChildResourcePoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterModule) DeepCopyInto(out *ClusterModule) {
 	*out = *in
+	if in.UnusedSince != nil {
+		in, out := &in.UnusedSince, &out.UnusedSince
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterModule.
@@ -74,6 +144,63 @@ func (in *ClusterModule) DeepCopy() *ClusterModule {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterModuleLifecycle) DeepCopyInto(out *ClusterModuleLifecycle) {
+	*out = *in
+	if in.NameTemplate != nil {
+		in, out := &in.NameTemplate, &out.NameTemplate
+		*out = new(string)
+		**out = **in
+	}
+	if in.DeletionGracePeriod != nil {
+		in, out := &in.DeletionGracePeriod, &out.DeletionGracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterModuleLifecycle.
+func (in *ClusterModuleLifecycle) DeepCopy() *ClusterModuleLifecycle {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterModuleLifecycle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContentLibraryItemReference) DeepCopyInto(out *ContentLibraryItemReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContentLibraryItemReference.
+func (in *ContentLibraryItemReference) DeepCopy() *ContentLibraryItemReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ContentLibraryItemReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneEndpointManagementSpec) DeepCopyInto(out *ControlPlaneEndpointManagementSpec) {
+	*out = *in
+	in.AddressesFromPool.DeepCopyInto(&out.AddressesFromPool)
+	out.KubeVIP = in.KubeVIP
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneEndpointManagementSpec.
+func (in *ControlPlaneEndpointManagementSpec) DeepCopy() *ControlPlaneEndpointManagementSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneEndpointManagementSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DHCPOverrides) DeepCopyInto(out *DHCPOverrides) {
 	*out = *in
@@ -169,6 +296,91 @@ func (in *FailureDomainHosts) DeepCopy() *FailureDomainHosts {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirstClassDiskReference) DeepCopyInto(out *FirstClassDiskReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirstClassDiskReference.
+func (in *FirstClassDiskReference) DeepCopy() *FirstClassDiskReference {
+	if in == nil {
+		return nil
+	}
+	out := new(FirstClassDiskReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuestCrashDetection) DeepCopyInto(out *GuestCrashDetection) {
+	*out = *in
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuestCrashDetection.
+func (in *GuestCrashDetection) DeepCopy() *GuestCrashDetection {
+	if in == nil {
+		return nil
+	}
+	out := new(GuestCrashDetection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuestProxySpec) DeepCopyInto(out *GuestProxySpec) {
+	*out = *in
+	if in.NoProxy != nil {
+		in, out := &in.NoProxy, &out.NoProxy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuestProxySpec.
+func (in *GuestProxySpec) DeepCopy() *GuestProxySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GuestProxySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostEvacuationSpec) DeepCopyInto(out *HostEvacuationSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostEvacuationSpec.
+func (in *HostEvacuationSpec) DeepCopy() *HostEvacuationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HostEvacuationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeVIPSpec) DeepCopyInto(out *KubeVIPSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeVIPSpec.
+func (in *KubeVIPSpec) DeepCopy() *KubeVIPSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeVIPSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Network) DeepCopyInto(out *Network) {
 	*out = *in
@@ -194,6 +406,26 @@ func (in *Network) DeepCopy() *Network {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkBondSpec) DeepCopyInto(out *NetworkBondSpec) {
+	*out = *in
+	if in.MIIMonitorInterval != nil {
+		in, out := &in.MIIMonitorInterval, &out.MIIMonitorInterval
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkBondSpec.
+func (in *NetworkBondSpec) DeepCopy() *NetworkBondSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkBondSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkConfiguration) DeepCopyInto(out *NetworkConfiguration) {
 	*out = *in
@@ -246,6 +478,21 @@ func (in *NetworkConfiguration) DeepCopy() *NetworkConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkDeviceSRIOVSpec) DeepCopyInto(out *NetworkDeviceSRIOVSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkDeviceSRIOVSpec.
+func (in *NetworkDeviceSRIOVSpec) DeepCopy() *NetworkDeviceSRIOVSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkDeviceSRIOVSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkDeviceSpec) DeepCopyInto(out *NetworkDeviceSpec) {
 	*out = *in
@@ -291,6 +538,28 @@ func (in *NetworkDeviceSpec) DeepCopyInto(out *NetworkDeviceSpec) {
 		*out = new(DHCPOverrides)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Bond != nil {
+		in, out := &in.Bond, &out.Bond
+		*out = new(NetworkBondSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VLANs != nil {
+		in, out := &in.VLANs, &out.VLANs
+		*out = make([]NetworkVLANSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SRIOV != nil {
+		in, out := &in.SRIOV, &out.SRIOV
+		*out = new(NetworkDeviceSRIOVSpec)
+		**out = **in
+	}
+	if in.AddressFromNetworkProtocolProfile != nil {
+		in, out := &in.AddressFromNetworkProtocolProfile, &out.AddressFromNetworkProtocolProfile
+		*out = new(NetworkProtocolProfileAllocation)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkDeviceSpec.
@@ -303,6 +572,21 @@ func (in *NetworkDeviceSpec) DeepCopy() *NetworkDeviceSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkProtocolProfileAllocation) DeepCopyInto(out *NetworkProtocolProfileAllocation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkProtocolProfileAllocation.
+func (in *NetworkProtocolProfileAllocation) DeepCopy() *NetworkProtocolProfileAllocation {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkProtocolProfileAllocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkRouteSpec) DeepCopyInto(out *NetworkRouteSpec) {
 	*out = *in
@@ -333,6 +617,16 @@ func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
 		*out = make([]NetworkRouteSpec, len(*in))
 		copy(*out, *in)
 	}
+	if in.NTPServers != nil {
+		in, out := &in.NTPServers, &out.NTPServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(GuestProxySpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkSpec.
@@ -365,6 +659,46 @@ func (in *NetworkStatus) DeepCopy() *NetworkStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkVLANSpec) DeepCopyInto(out *NetworkVLANSpec) {
+	*out = *in
+	if in.IPAddrs != nil {
+		in, out := &in.IPAddrs, &out.IPAddrs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MTU != nil {
+		in, out := &in.MTU, &out.MTU
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Nameservers != nil {
+		in, out := &in.Nameservers, &out.Nameservers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]NetworkRouteSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.SearchDomains != nil {
+		in, out := &in.SearchDomains, &out.SearchDomains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkVLANSpec.
+func (in *NetworkVLANSpec) DeepCopy() *NetworkVLANSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkVLANSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PCIDeviceSpec) DeepCopyInto(out *PCIDeviceSpec) {
 	*out = *in
@@ -405,6 +739,26 @@ func (in *PlacementConstraint) DeepCopy() *PlacementConstraint {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourcePoolAllocation) DeepCopyInto(out *ResourcePoolAllocation) {
+	*out = *in
+	if in.Limit != nil {
+		in, out := &in.Limit, &out.Limit
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourcePoolAllocation.
+func (in *ResourcePoolAllocation) DeepCopy() *ResourcePoolAllocation {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourcePoolAllocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SSHUser) DeepCopyInto(out *SSHUser) {
 	*out = *in
@@ -450,6 +804,11 @@ func (in *Topology) DeepCopyInto(out *Topology) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.VSAN != nil {
+		in, out := &in.VSAN, &out.VSAN
+		*out = new(VSANTopology)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Topology.
@@ -463,16 +822,76 @@ func (in *Topology) DeepCopy() *Topology {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VSphereCluster) DeepCopyInto(out *VSphereCluster) {
+func (in *VCenterProxySpec) DeepCopyInto(out *VCenterProxySpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereCluster.
-func (in *VSphereCluster) DeepCopy() *VSphereCluster {
+	if in.NoProxy != nil {
+		in, out := &in.NoProxy, &out.NoProxy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VCenterProxySpec.
+func (in *VCenterProxySpec) DeepCopy() *VCenterProxySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VCenterProxySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VCenterTLSConfig) DeepCopyInto(out *VCenterTLSConfig) {
+	*out = *in
+	if in.CASecretRef != nil {
+		in, out := &in.CASecretRef, &out.CASecretRef
+		*out = new(CASecretReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VCenterTLSConfig.
+func (in *VCenterTLSConfig) DeepCopy() *VCenterTLSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(VCenterTLSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSANTopology) DeepCopyInto(out *VSANTopology) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSANTopology.
+func (in *VSANTopology) DeepCopy() *VSANTopology {
+	if in == nil {
+		return nil
+	}
+	out := new(VSANTopology)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereCluster) DeepCopyInto(out *VSphereCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereCluster.
+func (in *VSphereCluster) DeepCopy() *VSphereCluster {
 	if in == nil {
 		return nil
 	}
@@ -489,6 +908,36 @@ func (in *VSphereCluster) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereClusterDeprecatedFieldUsage) DeepCopyInto(out *VSphereClusterDeprecatedFieldUsage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereClusterDeprecatedFieldUsage.
+func (in *VSphereClusterDeprecatedFieldUsage) DeepCopy() *VSphereClusterDeprecatedFieldUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereClusterDeprecatedFieldUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereClusterHardwareMinimums) DeepCopyInto(out *VSphereClusterHardwareMinimums) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereClusterHardwareMinimums.
+func (in *VSphereClusterHardwareMinimums) DeepCopy() *VSphereClusterHardwareMinimums {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereClusterHardwareMinimums)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VSphereClusterIdentity) DeepCopyInto(out *VSphereClusterIdentity) {
 	*out = *in
@@ -551,11 +1000,26 @@ func (in *VSphereClusterIdentityList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VSphereClusterIdentitySpec) DeepCopyInto(out *VSphereClusterIdentitySpec) {
 	*out = *in
+	if in.TokenExchange != nil {
+		in, out := &in.TokenExchange, &out.TokenExchange
+		*out = new(VSphereTokenExchange)
+		**out = **in
+	}
 	if in.AllowedNamespaces != nil {
 		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
 		*out = new(AllowedNamespaces)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AllowedTargets != nil {
+		in, out := &in.AllowedTargets, &out.AllowedTargets
+		*out = new(AllowedTargets)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLSConfig != nil {
+		in, out := &in.TLSConfig, &out.TLSConfig
+		*out = new(VCenterTLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereClusterIdentitySpec.
@@ -622,6 +1086,108 @@ func (in *VSphereClusterList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereClusterResourceUsage) DeepCopyInto(out *VSphereClusterResourceUsage) {
+	*out = *in
+	out.CPU = in.CPU.DeepCopy()
+	out.Memory = in.Memory.DeepCopy()
+	out.Storage = in.Storage.DeepCopy()
+	in.LastUpdated.DeepCopyInto(&out.LastUpdated)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereClusterResourceUsage.
+func (in *VSphereClusterResourceUsage) DeepCopy() *VSphereClusterResourceUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereClusterResourceUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereClusterSettings) DeepCopyInto(out *VSphereClusterSettings) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereClusterSettings.
+func (in *VSphereClusterSettings) DeepCopy() *VSphereClusterSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereClusterSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VSphereClusterSettings) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereClusterSettingsList) DeepCopyInto(out *VSphereClusterSettingsList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VSphereClusterSettings, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereClusterSettingsList.
+func (in *VSphereClusterSettingsList) DeepCopy() *VSphereClusterSettingsList {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereClusterSettingsList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VSphereClusterSettingsList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereClusterSettingsSpec) DeepCopyInto(out *VSphereClusterSettingsSpec) {
+	*out = *in
+	if in.IdentityRef != nil {
+		in, out := &in.IdentityRef, &out.IdentityRef
+		*out = new(VSphereIdentityReference)
+		**out = **in
+	}
+	if in.Hardware != nil {
+		in, out := &in.Hardware, &out.Hardware
+		*out = new(VSphereClusterHardwareMinimums)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereClusterSettingsSpec.
+func (in *VSphereClusterSettingsSpec) DeepCopy() *VSphereClusterSettingsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereClusterSettingsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VSphereClusterSpec) DeepCopyInto(out *VSphereClusterSpec) {
 	*out = *in
@@ -634,13 +1200,52 @@ func (in *VSphereClusterSpec) DeepCopyInto(out *VSphereClusterSpec) {
 	if in.ClusterModules != nil {
 		in, out := &in.ClusterModules, &out.ClusterModules
 		*out = make([]ClusterModule, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ClusterModuleLifecycle != nil {
+		in, out := &in.ClusterModuleLifecycle, &out.ClusterModuleLifecycle
+		*out = new(ClusterModuleLifecycle)
+		(*in).DeepCopyInto(*out)
 	}
 	if in.FailureDomainSelector != nil {
 		in, out := &in.FailureDomainSelector, &out.FailureDomainSelector
 		*out = new(metav1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HostEvacuation != nil {
+		in, out := &in.HostEvacuation, &out.HostEvacuation
+		*out = new(HostEvacuationSpec)
+		**out = **in
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(VCenterProxySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLSConfig != nil {
+		in, out := &in.TLSConfig, &out.TLSConfig
+		*out = new(VCenterTLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CustomAttributes != nil {
+		in, out := &in.CustomAttributes, &out.CustomAttributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.FolderNamingStrategy != nil {
+		in, out := &in.FolderNamingStrategy, &out.FolderNamingStrategy
+		*out = new(VSphereFolderNamingStrategy)
+		**out = **in
+	}
+	if in.ControlPlaneEndpointManagement != nil {
+		in, out := &in.ControlPlaneEndpointManagement, &out.ControlPlaneEndpointManagement
+		*out = new(ControlPlaneEndpointManagementSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereClusterSpec.
@@ -670,6 +1275,21 @@ func (in *VSphereClusterStatus) DeepCopyInto(out *VSphereClusterStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.ResourceUsage != nil {
+		in, out := &in.ResourceUsage, &out.ResourceUsage
+		*out = new(VSphereClusterResourceUsage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ControlPlaneEndpoint != nil {
+		in, out := &in.ControlPlaneEndpoint, &out.ControlPlaneEndpoint
+		*out = new(APIEndpoint)
+		**out = **in
+	}
+	if in.DeprecatedFieldUsage != nil {
+		in, out := &in.DeprecatedFieldUsage, &out.DeprecatedFieldUsage
+		*out = make([]VSphereClusterDeprecatedFieldUsage, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereClusterStatus.
@@ -882,6 +1502,11 @@ func (in *VSphereDeploymentZoneStatus) DeepCopy() *VSphereDeploymentZoneStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VSphereDisk) DeepCopyInto(out *VSphereDisk) {
 	*out = *in
+	if in.ExistingFCD != nil {
+		in, out := &in.ExistingFCD, &out.ExistingFCD
+		*out = new(FirstClassDiskReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereDisk.
@@ -971,41 +1596,40 @@ func (in *VSphereFailureDomainSpec) DeepCopy() *VSphereFailureDomainSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VSphereIdentityReference) DeepCopyInto(out *VSphereIdentityReference) {
+func (in *VSphereFolderNamingStrategy) DeepCopyInto(out *VSphereFolderNamingStrategy) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereIdentityReference.
-func (in *VSphereIdentityReference) DeepCopy() *VSphereIdentityReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereFolderNamingStrategy.
+func (in *VSphereFolderNamingStrategy) DeepCopy() *VSphereFolderNamingStrategy {
 	if in == nil {
 		return nil
 	}
-	out := new(VSphereIdentityReference)
+	out := new(VSphereFolderNamingStrategy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VSphereMachine) DeepCopyInto(out *VSphereMachine) {
+func (in *VSphereForceDeletePolicy) DeepCopyInto(out *VSphereForceDeletePolicy) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachine.
-func (in *VSphereMachine) DeepCopy() *VSphereMachine {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereForceDeletePolicy.
+func (in *VSphereForceDeletePolicy) DeepCopy() *VSphereForceDeletePolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(VSphereMachine)
+	out := new(VSphereForceDeletePolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VSphereMachine) DeepCopyObject() runtime.Object {
+func (in *VSphereForceDeletePolicy) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1013,31 +1637,31 @@ func (in *VSphereMachine) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VSphereMachineList) DeepCopyInto(out *VSphereMachineList) {
+func (in *VSphereForceDeletePolicyList) DeepCopyInto(out *VSphereForceDeletePolicyList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]VSphereMachine, len(*in))
+		*out = make([]VSphereForceDeletePolicy, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachineList.
-func (in *VSphereMachineList) DeepCopy() *VSphereMachineList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereForceDeletePolicyList.
+func (in *VSphereForceDeletePolicyList) DeepCopy() *VSphereForceDeletePolicyList {
 	if in == nil {
 		return nil
 	}
-	out := new(VSphereMachineList)
+	out := new(VSphereForceDeletePolicyList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VSphereMachineList) DeepCopyObject() runtime.Object {
+func (in *VSphereForceDeletePolicyList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1045,100 +1669,738 @@ func (in *VSphereMachineList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VSphereMachineSpec) DeepCopyInto(out *VSphereMachineSpec) {
+func (in *VSphereForceDeletePolicySpec) DeepCopyInto(out *VSphereForceDeletePolicySpec) {
 	*out = *in
-	in.VirtualMachineCloneSpec.DeepCopyInto(&out.VirtualMachineCloneSpec)
-	if in.ProviderID != nil {
-		in, out := &in.ProviderID, &out.ProviderID
-		*out = new(string)
-		**out = **in
-	}
-	if in.FailureDomain != nil {
-		in, out := &in.FailureDomain, &out.FailureDomain
-		*out = new(string)
-		**out = **in
-	}
-	if in.GuestSoftPowerOffTimeout != nil {
-		in, out := &in.GuestSoftPowerOffTimeout, &out.GuestSoftPowerOffTimeout
-		*out = new(metav1.Duration)
-		**out = **in
-	}
+	in.NamespaceSelector.DeepCopyInto(&out.NamespaceSelector)
+	in.ClusterSelector.DeepCopyInto(&out.ClusterSelector)
+	out.VCenterUnreachableTimeout = in.VCenterUnreachableTimeout
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachineSpec.
-func (in *VSphereMachineSpec) DeepCopy() *VSphereMachineSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereForceDeletePolicySpec.
+func (in *VSphereForceDeletePolicySpec) DeepCopy() *VSphereForceDeletePolicySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(VSphereMachineSpec)
+	out := new(VSphereForceDeletePolicySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VSphereMachineStatus) DeepCopyInto(out *VSphereMachineStatus) {
+func (in *VSphereIPPool) DeepCopyInto(out *VSphereIPPool) {
 	*out = *in
-	if in.Addresses != nil {
-		in, out := &in.Addresses, &out.Addresses
-		*out = make([]apiv1beta1.MachineAddress, len(*in))
-		copy(*out, *in)
-	}
-	if in.Network != nil {
-		in, out := &in.Network, &out.Network
-		*out = make([]NetworkStatus, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.FailureReason != nil {
-		in, out := &in.FailureReason, &out.FailureReason
-		*out = new(errors.MachineStatusError)
-		**out = **in
-	}
-	if in.FailureMessage != nil {
-		in, out := &in.FailureMessage, &out.FailureMessage
-		*out = new(string)
-		**out = **in
-	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make(apiv1beta1.Conditions, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachineStatus.
-func (in *VSphereMachineStatus) DeepCopy() *VSphereMachineStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereIPPool.
+func (in *VSphereIPPool) DeepCopy() *VSphereIPPool {
 	if in == nil {
 		return nil
 	}
-	out := new(VSphereMachineStatus)
+	out := new(VSphereIPPool)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VSphereMachineTemplate) DeepCopyInto(out *VSphereMachineTemplate) {
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VSphereIPPool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereIPPoolList) DeepCopyInto(out *VSphereIPPoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VSphereIPPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereIPPoolList.
+func (in *VSphereIPPoolList) DeepCopy() *VSphereIPPoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereIPPoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VSphereIPPoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereIPPoolSpec) DeepCopyInto(out *VSphereIPPoolSpec) {
+	*out = *in
+	if in.IdentityRef != nil {
+		in, out := &in.IdentityRef, &out.IdentityRef
+		*out = new(VSphereIdentityReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereIPPoolSpec.
+func (in *VSphereIPPoolSpec) DeepCopy() *VSphereIPPoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereIPPoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereIdentityReference) DeepCopyInto(out *VSphereIdentityReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereIdentityReference.
+func (in *VSphereIdentityReference) DeepCopy() *VSphereIdentityReference {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereIdentityReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereMachine) DeepCopyInto(out *VSphereMachine) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachine.
+func (in *VSphereMachine) DeepCopy() *VSphereMachine {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereMachine)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VSphereMachine) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereMachineList) DeepCopyInto(out *VSphereMachineList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VSphereMachine, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachineList.
+func (in *VSphereMachineList) DeepCopy() *VSphereMachineList {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereMachineList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VSphereMachineList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereMachinePool) DeepCopyInto(out *VSphereMachinePool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachinePool.
+func (in *VSphereMachinePool) DeepCopy() *VSphereMachinePool {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereMachinePool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VSphereMachinePool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereMachinePoolInstanceStatus) DeepCopyInto(out *VSphereMachinePoolInstanceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachinePoolInstanceStatus.
+func (in *VSphereMachinePoolInstanceStatus) DeepCopy() *VSphereMachinePoolInstanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereMachinePoolInstanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereMachinePoolInstanceTemplate) DeepCopyInto(out *VSphereMachinePoolInstanceTemplate) {
+	*out = *in
+	in.VirtualMachineCloneSpec.DeepCopyInto(&out.VirtualMachineCloneSpec)
+	if in.GuestSoftPowerOffTimeout != nil {
+		in, out := &in.GuestSoftPowerOffTimeout, &out.GuestSoftPowerOffTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.GuestReadinessGates != nil {
+		in, out := &in.GuestReadinessGates, &out.GuestReadinessGates
+		*out = make([]GuestReadinessGate, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachinePoolInstanceTemplate.
+func (in *VSphereMachinePoolInstanceTemplate) DeepCopy() *VSphereMachinePoolInstanceTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereMachinePoolInstanceTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereMachinePoolList) DeepCopyInto(out *VSphereMachinePoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VSphereMachinePool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachinePoolList.
+func (in *VSphereMachinePoolList) DeepCopy() *VSphereMachinePoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereMachinePoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VSphereMachinePoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereMachinePoolNamingStrategy) DeepCopyInto(out *VSphereMachinePoolNamingStrategy) {
+	*out = *in
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachinePoolNamingStrategy.
+func (in *VSphereMachinePoolNamingStrategy) DeepCopy() *VSphereMachinePoolNamingStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereMachinePoolNamingStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereMachinePoolRollingUpdate) DeepCopyInto(out *VSphereMachinePoolRollingUpdate) {
+	*out = *in
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachinePoolRollingUpdate.
+func (in *VSphereMachinePoolRollingUpdate) DeepCopy() *VSphereMachinePoolRollingUpdate {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereMachinePoolRollingUpdate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereMachinePoolSpec) DeepCopyInto(out *VSphereMachinePoolSpec) {
+	*out = *in
+	if in.ProviderIDList != nil {
+		in, out := &in.ProviderIDList, &out.ProviderIDList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Template.DeepCopyInto(&out.Template)
+	if in.NamingStrategy != nil {
+		in, out := &in.NamingStrategy, &out.NamingStrategy
+		*out = new(VSphereMachinePoolNamingStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Strategy.DeepCopyInto(&out.Strategy)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachinePoolSpec.
+func (in *VSphereMachinePoolSpec) DeepCopy() *VSphereMachinePoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereMachinePoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereMachinePoolStatus) DeepCopyInto(out *VSphereMachinePoolStatus) {
+	*out = *in
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]VSphereMachinePoolInstanceStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(errors.MachinePoolStatusFailure)
+		**out = **in
+	}
+	if in.FailureMessage != nil {
+		in, out := &in.FailureMessage, &out.FailureMessage
+		*out = new(string)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(apiv1beta1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachinePoolStatus.
+func (in *VSphereMachinePoolStatus) DeepCopy() *VSphereMachinePoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereMachinePoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereMachinePoolStrategy) DeepCopyInto(out *VSphereMachinePoolStrategy) {
+	*out = *in
+	if in.RollingUpdate != nil {
+		in, out := &in.RollingUpdate, &out.RollingUpdate
+		*out = new(VSphereMachinePoolRollingUpdate)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachinePoolStrategy.
+func (in *VSphereMachinePoolStrategy) DeepCopy() *VSphereMachinePoolStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereMachinePoolStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereMachineSpec) DeepCopyInto(out *VSphereMachineSpec) {
+	*out = *in
+	in.VirtualMachineCloneSpec.DeepCopyInto(&out.VirtualMachineCloneSpec)
+	if in.ProviderID != nil {
+		in, out := &in.ProviderID, &out.ProviderID
+		*out = new(string)
+		**out = **in
+	}
+	if in.FailureDomain != nil {
+		in, out := &in.FailureDomain, &out.FailureDomain
+		*out = new(string)
+		**out = **in
+	}
+	if in.GuestSoftPowerOffTimeout != nil {
+		in, out := &in.GuestSoftPowerOffTimeout, &out.GuestSoftPowerOffTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.GuestReadinessGates != nil {
+		in, out := &in.GuestReadinessGates, &out.GuestReadinessGates
+		*out = make([]GuestReadinessGate, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamingStrategy != nil {
+		in, out := &in.NamingStrategy, &out.NamingStrategy
+		*out = new(VSphereVMNamingStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachineSpec.
+func (in *VSphereMachineSpec) DeepCopy() *VSphereMachineSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereMachineSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereMachineStatus) DeepCopyInto(out *VSphereMachineStatus) {
+	*out = *in
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]apiv1beta1.MachineAddress, len(*in))
+		copy(*out, *in)
+	}
+	if in.Network != nil {
+		in, out := &in.Network, &out.Network
+		*out = make([]NetworkStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FailureDomain != nil {
+		in, out := &in.FailureDomain, &out.FailureDomain
+		*out = new(string)
+		**out = **in
+	}
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(errors.MachineStatusError)
+		**out = **in
+	}
+	if in.FailureMessage != nil {
+		in, out := &in.FailureMessage, &out.FailureMessage
+		*out = new(string)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(apiv1beta1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachineStatus.
+func (in *VSphereMachineStatus) DeepCopy() *VSphereMachineStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereMachineStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereMachineTemplate) DeepCopyInto(out *VSphereMachineTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachineTemplate.
+func (in *VSphereMachineTemplate) DeepCopy() *VSphereMachineTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereMachineTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VSphereMachineTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereMachineTemplateList) DeepCopyInto(out *VSphereMachineTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VSphereMachineTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachineTemplateList.
+func (in *VSphereMachineTemplateList) DeepCopy() *VSphereMachineTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereMachineTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VSphereMachineTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereMachineTemplateResource) DeepCopyInto(out *VSphereMachineTemplateResource) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachineTemplateResource.
+func (in *VSphereMachineTemplateResource) DeepCopy() *VSphereMachineTemplateResource {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereMachineTemplateResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereMachineTemplateSpec) DeepCopyInto(out *VSphereMachineTemplateSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachineTemplateSpec.
+func (in *VSphereMachineTemplateSpec) DeepCopy() *VSphereMachineTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereMachineTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereMachineTemplateStatus) DeepCopyInto(out *VSphereMachineTemplateStatus) {
+	*out = *in
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachineTemplateStatus.
+func (in *VSphereMachineTemplateStatus) DeepCopy() *VSphereMachineTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereMachineTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSpherePlacementPolicy) DeepCopyInto(out *VSpherePlacementPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSpherePlacementPolicy.
+func (in *VSpherePlacementPolicy) DeepCopy() *VSpherePlacementPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(VSpherePlacementPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VSpherePlacementPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSpherePlacementPolicyList) DeepCopyInto(out *VSpherePlacementPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VSpherePlacementPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSpherePlacementPolicyList.
+func (in *VSpherePlacementPolicyList) DeepCopy() *VSpherePlacementPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(VSpherePlacementPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VSpherePlacementPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSpherePlacementPolicySpec) DeepCopyInto(out *VSpherePlacementPolicySpec) {
+	*out = *in
+	in.NamespaceSelector.DeepCopyInto(&out.NamespaceSelector)
+	if in.Datastores != nil {
+		in, out := &in.Datastores, &out.Datastores
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Networks != nil {
+		in, out := &in.Networks, &out.Networks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Folders != nil {
+		in, out := &in.Folders, &out.Folders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResourcePools != nil {
+		in, out := &in.ResourcePools, &out.ResourcePools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSpherePlacementPolicySpec.
+func (in *VSpherePlacementPolicySpec) DeepCopy() *VSpherePlacementPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VSpherePlacementPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereTokenExchange) DeepCopyInto(out *VSphereTokenExchange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereTokenExchange.
+func (in *VSphereTokenExchange) DeepCopy() *VSphereTokenExchange {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereTokenExchange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereVM) DeepCopyInto(out *VSphereVM) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachineTemplate.
-func (in *VSphereMachineTemplate) DeepCopy() *VSphereMachineTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereVM.
+func (in *VSphereVM) DeepCopy() *VSphereVM {
 	if in == nil {
 		return nil
 	}
-	out := new(VSphereMachineTemplate)
+	out := new(VSphereVM)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VSphereMachineTemplate) DeepCopyObject() runtime.Object {
+func (in *VSphereVM) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1146,31 +2408,73 @@ func (in *VSphereMachineTemplate) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VSphereMachineTemplateList) DeepCopyInto(out *VSphereMachineTemplateList) {
+func (in *VSphereVMImage) DeepCopyInto(out *VSphereVMImage) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereVMImage.
+func (in *VSphereVMImage) DeepCopy() *VSphereVMImage {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereVMImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VSphereVMImage) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereVMImageChecksum) DeepCopyInto(out *VSphereVMImageChecksum) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereVMImageChecksum.
+func (in *VSphereVMImageChecksum) DeepCopy() *VSphereVMImageChecksum {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereVMImageChecksum)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereVMImageList) DeepCopyInto(out *VSphereVMImageList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]VSphereMachineTemplate, len(*in))
+		*out = make([]VSphereVMImage, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachineTemplateList.
-func (in *VSphereMachineTemplateList) DeepCopy() *VSphereMachineTemplateList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereVMImageList.
+func (in *VSphereVMImageList) DeepCopy() *VSphereVMImageList {
 	if in == nil {
 		return nil
 	}
-	out := new(VSphereMachineTemplateList)
+	out := new(VSphereVMImageList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VSphereMachineTemplateList) DeepCopyObject() runtime.Object {
+func (in *VSphereVMImageList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1178,65 +2482,71 @@ func (in *VSphereMachineTemplateList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VSphereMachineTemplateResource) DeepCopyInto(out *VSphereMachineTemplateResource) {
+func (in *VSphereVMImageReference) DeepCopyInto(out *VSphereVMImageReference) {
 	*out = *in
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachineTemplateResource.
-func (in *VSphereMachineTemplateResource) DeepCopy() *VSphereMachineTemplateResource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereVMImageReference.
+func (in *VSphereVMImageReference) DeepCopy() *VSphereVMImageReference {
 	if in == nil {
 		return nil
 	}
-	out := new(VSphereMachineTemplateResource)
+	out := new(VSphereVMImageReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VSphereMachineTemplateSpec) DeepCopyInto(out *VSphereMachineTemplateSpec) {
+func (in *VSphereVMImageSpec) DeepCopyInto(out *VSphereVMImageSpec) {
 	*out = *in
-	in.Template.DeepCopyInto(&out.Template)
+	if in.ContentLibraryItem != nil {
+		in, out := &in.ContentLibraryItem, &out.ContentLibraryItem
+		*out = new(ContentLibraryItemReference)
+		**out = **in
+	}
+	if in.Checksums != nil {
+		in, out := &in.Checksums, &out.Checksums
+		*out = make([]VSphereVMImageChecksum, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachineTemplateSpec.
-func (in *VSphereMachineTemplateSpec) DeepCopy() *VSphereMachineTemplateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereVMImageSpec.
+func (in *VSphereVMImageSpec) DeepCopy() *VSphereVMImageSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(VSphereMachineTemplateSpec)
+	out := new(VSphereVMImageSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VSphereVM) DeepCopyInto(out *VSphereVM) {
+func (in *VSphereVMImageStatus) DeepCopyInto(out *VSphereVMImageStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.LastVerified != nil {
+		in, out := &in.LastVerified, &out.LastVerified
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(apiv1beta1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereVM.
-func (in *VSphereVM) DeepCopy() *VSphereVM {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereVMImageStatus.
+func (in *VSphereVMImageStatus) DeepCopy() *VSphereVMImageStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VSphereVM)
+	out := new(VSphereVMImageStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VSphereVM) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VSphereVMList) DeepCopyInto(out *VSphereVMList) {
 	*out = *in
@@ -1269,6 +2579,61 @@ func (in *VSphereVMList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereVMNamingStrategy) DeepCopyInto(out *VSphereVMNamingStrategy) {
+	*out = *in
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereVMNamingStrategy.
+func (in *VSphereVMNamingStrategy) DeepCopy() *VSphereVMNamingStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereVMNamingStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereVMRelocateTo) DeepCopyInto(out *VSphereVMRelocateTo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereVMRelocateTo.
+func (in *VSphereVMRelocateTo) DeepCopy() *VSphereVMRelocateTo {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereVMRelocateTo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereVMRelocationStatus) DeepCopyInto(out *VSphereVMRelocationStatus) {
+	*out = *in
+	out.Target = in.Target
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereVMRelocationStatus.
+func (in *VSphereVMRelocationStatus) DeepCopy() *VSphereVMRelocationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereVMRelocationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VSphereVMSpec) DeepCopyInto(out *VSphereVMSpec) {
 	*out = *in
@@ -1283,6 +2648,16 @@ func (in *VSphereVMSpec) DeepCopyInto(out *VSphereVMSpec) {
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.GuestReadinessGates != nil {
+		in, out := &in.GuestReadinessGates, &out.GuestReadinessGates
+		*out = make([]GuestReadinessGate, len(*in))
+		copy(*out, *in)
+	}
+	if in.RelocateTo != nil {
+		in, out := &in.RelocateTo, &out.RelocateTo
+		*out = new(VSphereVMRelocateTo)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereVMSpec.
@@ -1333,6 +2708,11 @@ func (in *VSphereVMStatus) DeepCopyInto(out *VSphereVMStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Relocation != nil {
+		in, out := &in.Relocation, &out.Relocation
+		*out = new(VSphereVMRelocationStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereVMStatus.
@@ -1370,7 +2750,32 @@ func (in *VirtualMachine) DeepCopy() *VirtualMachine {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineCloneSpec) DeepCopyInto(out *VirtualMachineCloneSpec) {
 	*out = *in
+	if in.ImageRef != nil {
+		in, out := &in.ImageRef, &out.ImageRef
+		*out = new(VSphereVMImageReference)
+		**out = **in
+	}
+	if in.TemplateSource != nil {
+		in, out := &in.TemplateSource, &out.TemplateSource
+		*out = new(VirtualMachineTemplateSource)
+		**out = **in
+	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(VirtualMachineEncryptionSpec)
+		**out = **in
+	}
+	if in.ChildResourcePool != nil {
+		in, out := &in.ChildResourcePool, &out.ChildResourcePool
+		*out = new(ChildResourcePoolSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Network.DeepCopyInto(&out.Network)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(VirtualMachineResources)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.AdditionalDisksGiB != nil {
 		in, out := &in.AdditionalDisksGiB, &out.AdditionalDisksGiB
 		*out = make([]int32, len(*in))
@@ -1398,8 +2803,39 @@ func (in *VirtualMachineCloneSpec) DeepCopyInto(out *VirtualMachineCloneSpec) {
 	if in.DataDisks != nil {
 		in, out := &in.DataDisks, &out.DataDisks
 		*out = make([]VSphereDisk, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CloudInitCustomizationRef != nil {
+		in, out := &in.CloudInitCustomizationRef, &out.CloudInitCustomizationRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.VAppConfig != nil {
+		in, out := &in.VAppConfig, &out.VAppConfig
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CustomAttributes != nil {
+		in, out := &in.CustomAttributes, &out.CustomAttributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.QuestionAnswers != nil {
+		in, out := &in.QuestionAnswers, &out.QuestionAnswers
+		*out = make([]VirtualMachineQuestionAnswer, len(*in))
 		copy(*out, *in)
 	}
+	if in.GuestCrashDetection != nil {
+		in, out := &in.GuestCrashDetection, &out.GuestCrashDetection
+		*out = new(GuestCrashDetection)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineCloneSpec.
@@ -1411,3 +2847,93 @@ func (in *VirtualMachineCloneSpec) DeepCopy() *VirtualMachineCloneSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineEncryptionSpec) DeepCopyInto(out *VirtualMachineEncryptionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineEncryptionSpec.
+func (in *VirtualMachineEncryptionSpec) DeepCopy() *VirtualMachineEncryptionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineEncryptionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineQuestionAnswer) DeepCopyInto(out *VirtualMachineQuestionAnswer) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineQuestionAnswer.
+func (in *VirtualMachineQuestionAnswer) DeepCopy() *VirtualMachineQuestionAnswer {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineQuestionAnswer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineResourceAllocation) DeepCopyInto(out *VirtualMachineResourceAllocation) {
+	*out = *in
+	if in.Limit != nil {
+		in, out := &in.Limit, &out.Limit
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineResourceAllocation.
+func (in *VirtualMachineResourceAllocation) DeepCopy() *VirtualMachineResourceAllocation {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineResourceAllocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineResources) DeepCopyInto(out *VirtualMachineResources) {
+	*out = *in
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		*out = new(VirtualMachineResourceAllocation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		*out = new(VirtualMachineResourceAllocation)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineResources.
+func (in *VirtualMachineResources) DeepCopy() *VirtualMachineResources {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineTemplateSource) DeepCopyInto(out *VirtualMachineTemplateSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineTemplateSource.
+func (in *VirtualMachineTemplateSource) DeepCopy() *VirtualMachineTemplateSource {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineTemplateSource)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apimachineryconversion "k8s.io/apimachinery/pkg/conversion"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+// Convert_v1beta1_NetworkSpec_To_v1beta2_NetworkSpec is a conversion function.
+func Convert_v1beta1_NetworkSpec_To_v1beta2_NetworkSpec(in *infrav1.NetworkSpec, out *NetworkSpec, s apimachineryconversion.Scope) error {
+	// PreferredAPIServerCIDR has been removed in v1beta2.
+	return autoConvert_v1beta1_NetworkSpec_To_v1beta2_NetworkSpec(in, out, s)
+}
+
+// Convert_v1beta1_VSphereClusterStatus_To_v1beta2_VSphereClusterStatus is a conversion function.
+func Convert_v1beta1_VSphereClusterStatus_To_v1beta2_VSphereClusterStatus(in *infrav1.VSphereClusterStatus, out *VSphereClusterStatus, s apimachineryconversion.Scope) error {
+	if err := autoConvert_v1beta1_VSphereClusterStatus_To_v1beta2_VSphereClusterStatus(in, out, s); err != nil {
+		return err
+	}
+
+	// v1beta1 conditions are moved to the deprecated status, v1beta2 conditions are not set by the v1beta1 controllers.
+	out.Conditions = nil
+	if in.Conditions != nil {
+		out.Deprecated = &VSphereClusterDeprecatedStatus{
+			V1Beta1: &VSphereClusterV1Beta1DeprecatedStatus{
+				Conditions: in.Conditions,
+			},
+		}
+	}
+
+	if in.Ready {
+		out.Initialization = &VSphereClusterInitializationStatus{
+			Provisioned: ptr.To(true),
+		}
+	}
+
+	out.FailureDomains = convertFailureDomainsToV1Beta2(in.FailureDomains)
+	return nil
+}
+
+// Convert_v1beta2_VSphereClusterStatus_To_v1beta1_VSphereClusterStatus is a conversion function.
+func Convert_v1beta2_VSphereClusterStatus_To_v1beta1_VSphereClusterStatus(in *VSphereClusterStatus, out *infrav1.VSphereClusterStatus, s apimachineryconversion.Scope) error {
+	if err := autoConvert_v1beta2_VSphereClusterStatus_To_v1beta1_VSphereClusterStatus(in, out, s); err != nil {
+		return err
+	}
+
+	out.Conditions = nil
+	if in.Deprecated != nil && in.Deprecated.V1Beta1 != nil {
+		out.Conditions = in.Deprecated.V1Beta1.Conditions
+	}
+
+	if in.Initialization != nil {
+		out.Ready = ptr.Deref(in.Initialization.Provisioned, false)
+	}
+
+	out.FailureDomains = convertFailureDomainsToV1Beta1(in.FailureDomains)
+	return nil
+}
+
+// Convert_v1beta1_VSphereMachineStatus_To_v1beta2_VSphereMachineStatus is a conversion function.
+func Convert_v1beta1_VSphereMachineStatus_To_v1beta2_VSphereMachineStatus(in *infrav1.VSphereMachineStatus, out *VSphereMachineStatus, s apimachineryconversion.Scope) error {
+	if err := autoConvert_v1beta1_VSphereMachineStatus_To_v1beta2_VSphereMachineStatus(in, out, s); err != nil {
+		return err
+	}
+
+	out.Conditions = nil
+	if in.Conditions != nil || in.FailureReason != nil || in.FailureMessage != nil {
+		out.Deprecated = &VSphereMachineDeprecatedStatus{
+			V1Beta1: &VSphereMachineV1Beta1DeprecatedStatus{
+				Conditions:     in.Conditions,
+				FailureReason:  in.FailureReason,
+				FailureMessage: in.FailureMessage,
+			},
+		}
+	}
+
+	if in.Ready {
+		out.Initialization = &VSphereMachineInitializationStatus{
+			Provisioned: ptr.To(true),
+		}
+	}
+	return nil
+}
+
+// Convert_v1beta2_VSphereMachineStatus_To_v1beta1_VSphereMachineStatus is a conversion function.
+func Convert_v1beta2_VSphereMachineStatus_To_v1beta1_VSphereMachineStatus(in *VSphereMachineStatus, out *infrav1.VSphereMachineStatus, s apimachineryconversion.Scope) error {
+	if err := autoConvert_v1beta2_VSphereMachineStatus_To_v1beta1_VSphereMachineStatus(in, out, s); err != nil {
+		return err
+	}
+
+	out.Conditions = nil
+	if in.Deprecated != nil && in.Deprecated.V1Beta1 != nil {
+		out.Conditions = in.Deprecated.V1Beta1.Conditions
+		out.FailureReason = in.Deprecated.V1Beta1.FailureReason
+		out.FailureMessage = in.Deprecated.V1Beta1.FailureMessage
+	}
+
+	if in.Initialization != nil {
+		out.Ready = ptr.Deref(in.Initialization.Provisioned, false)
+	}
+	return nil
+}
+
+// Convert_v1beta1_VSphereVMStatus_To_v1beta2_VSphereVMStatus is a conversion function.
+func Convert_v1beta1_VSphereVMStatus_To_v1beta2_VSphereVMStatus(in *infrav1.VSphereVMStatus, out *VSphereVMStatus, s apimachineryconversion.Scope) error {
+	if err := autoConvert_v1beta1_VSphereVMStatus_To_v1beta2_VSphereVMStatus(in, out, s); err != nil {
+		return err
+	}
+
+	out.Conditions = nil
+	if in.Conditions != nil || in.FailureReason != nil || in.FailureMessage != nil {
+		out.Deprecated = &VSphereVMDeprecatedStatus{
+			V1Beta1: &VSphereVMV1Beta1DeprecatedStatus{
+				Conditions:     in.Conditions,
+				FailureReason:  in.FailureReason,
+				FailureMessage: in.FailureMessage,
+			},
+		}
+	}
+
+	if in.Ready {
+		out.Initialization = &VSphereVMInitializationStatus{
+			Provisioned: ptr.To(true),
+		}
+	}
+	return nil
+}
+
+// Convert_v1beta2_VSphereVMStatus_To_v1beta1_VSphereVMStatus is a conversion function.
+func Convert_v1beta2_VSphereVMStatus_To_v1beta1_VSphereVMStatus(in *VSphereVMStatus, out *infrav1.VSphereVMStatus, s apimachineryconversion.Scope) error {
+	if err := autoConvert_v1beta2_VSphereVMStatus_To_v1beta1_VSphereVMStatus(in, out, s); err != nil {
+		return err
+	}
+
+	out.Conditions = nil
+	if in.Deprecated != nil && in.Deprecated.V1Beta1 != nil {
+		out.Conditions = in.Deprecated.V1Beta1.Conditions
+		out.FailureReason = in.Deprecated.V1Beta1.FailureReason
+		out.FailureMessage = in.Deprecated.V1Beta1.FailureMessage
+	}
+
+	if in.Initialization != nil {
+		out.Ready = ptr.Deref(in.Initialization.Provisioned, false)
+	}
+	return nil
+}
+
+// Convert_v1beta1_Condition_To_v1_Condition is a conversion function.
+func Convert_v1beta1_Condition_To_v1_Condition(_ *clusterv1.Condition, _ *metav1.Condition, _ apimachineryconversion.Scope) error {
+	// NOTE: v1beta1 conditions are moved to the deprecated status and not converted into v1beta2 conditions.
+	return nil
+}
+
+// Convert_v1_Condition_To_v1beta1_Condition is a conversion function.
+func Convert_v1_Condition_To_v1beta1_Condition(_ *metav1.Condition, _ *clusterv1.Condition, _ apimachineryconversion.Scope) error {
+	// NOTE: v1beta2 conditions are not converted into v1beta1 conditions.
+	return nil
+}
+
+// convertFailureDomainsToV1Beta2 converts the failure domains map to a list sorted by name.
+func convertFailureDomainsToV1Beta2(in clusterv1.FailureDomains) []VSphereClusterFailureDomain {
+	if in == nil {
+		return nil
+	}
+	out := make([]VSphereClusterFailureDomain, 0, len(in))
+	for name, fd := range in {
+		out = append(out, VSphereClusterFailureDomain{
+			Name:         name,
+			ControlPlane: ptr.To(fd.ControlPlane),
+			Attributes:   fd.Attributes,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// convertFailureDomainsToV1Beta1 converts the failure domains list to a map keyed by name.
+func convertFailureDomainsToV1Beta1(in []VSphereClusterFailureDomain) clusterv1.FailureDomains {
+	if in == nil {
+		return nil
+	}
+	out := make(clusterv1.FailureDomains, len(in))
+	for _, fd := range in {
+		out[fd.Name] = clusterv1.FailureDomainSpec{
+			ControlPlane: ptr.Deref(fd.ControlPlane, false),
+			Attributes:   fd.Attributes,
+		}
+	}
+	return out
+}
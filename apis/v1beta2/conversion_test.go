@@ -0,0 +1,126 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	fuzz "github.com/google/gofuzz"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	runtimeserializer "k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/utils/ptr"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func TestFuzzyConversion(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(AddToScheme(scheme)).To(Succeed())
+	g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+
+	t.Run("for VSphereCluster", utilconversion.FuzzTestFunc(utilconversion.FuzzTestFuncInput{
+		Scheme:           scheme,
+		Hub:              &infrav1.VSphereCluster{},
+		Spoke:            &VSphereCluster{},
+		HubAfterMutation: dropDataAnnotation,
+		FuzzerFuncs:      []fuzzer.FuzzerFuncs{spokeStatusFuzzFuncs},
+	}))
+	t.Run("for VSphereMachine", utilconversion.FuzzTestFunc(utilconversion.FuzzTestFuncInput{
+		Scheme:           scheme,
+		Hub:              &infrav1.VSphereMachine{},
+		Spoke:            &VSphereMachine{},
+		HubAfterMutation: dropDataAnnotation,
+		FuzzerFuncs:      []fuzzer.FuzzerFuncs{spokeStatusFuzzFuncs},
+	}))
+	t.Run("for VSphereVM", utilconversion.FuzzTestFunc(utilconversion.FuzzTestFuncInput{
+		Scheme:           scheme,
+		Hub:              &infrav1.VSphereVM{},
+		Spoke:            &VSphereVM{},
+		HubAfterMutation: dropDataAnnotation,
+		FuzzerFuncs:      []fuzzer.FuzzerFuncs{spokeStatusFuzzFuncs},
+	}))
+}
+
+// dropDataAnnotation removes the v1beta2 data preserved by ConvertTo for avoiding false negatives in hub-spoke-hub round trips.
+func dropDataAnnotation(hub conversion.Hub) {
+	delete(hub.(metav1.Object).GetAnnotations(), utilconversion.DataAnnotation)
+}
+
+// spokeStatusFuzzFuncs normalizes the v1beta2 status fields which have multiple representations
+// of the same v1beta1 value, e.g. an initialization without provisioned and no initialization.
+func spokeStatusFuzzFuncs(runtimeserializer.CodecFactory) []interface{} {
+	return []interface{}{
+		func(in *VSphereClusterStatus, c fuzz.Continue) {
+			c.FuzzNoCustom(in)
+			if in.Initialization != nil && !ptr.Deref(in.Initialization.Provisioned, false) {
+				in.Initialization = nil
+			}
+			if in.Deprecated != nil && (in.Deprecated.V1Beta1 == nil || in.Deprecated.V1Beta1.Conditions == nil) {
+				in.Deprecated = nil
+			}
+			in.FailureDomains = normalizeFailureDomains(in.FailureDomains)
+		},
+		func(in *VSphereMachineStatus, c fuzz.Continue) {
+			c.FuzzNoCustom(in)
+			if in.Initialization != nil && !ptr.Deref(in.Initialization.Provisioned, false) {
+				in.Initialization = nil
+			}
+			if in.Deprecated != nil && (in.Deprecated.V1Beta1 == nil || reflect.DeepEqual(in.Deprecated.V1Beta1, &VSphereMachineV1Beta1DeprecatedStatus{})) {
+				in.Deprecated = nil
+			}
+		},
+		func(in *VSphereVMStatus, c fuzz.Continue) {
+			c.FuzzNoCustom(in)
+			if in.Initialization != nil && !ptr.Deref(in.Initialization.Provisioned, false) {
+				in.Initialization = nil
+			}
+			if in.Deprecated != nil && (in.Deprecated.V1Beta1 == nil || reflect.DeepEqual(in.Deprecated.V1Beta1, &VSphereVMV1Beta1DeprecatedStatus{})) {
+				in.Deprecated = nil
+			}
+		},
+	}
+}
+
+// normalizeFailureDomains sorts the failure domains by name, drops duplicates and defaults controlPlane
+// like the conversion from the v1beta1 failure domains map does.
+func normalizeFailureDomains(in []VSphereClusterFailureDomain) []VSphereClusterFailureDomain {
+	if in == nil {
+		return nil
+	}
+	seen := map[string]bool{}
+	out := []VSphereClusterFailureDomain{}
+	for _, fd := range in {
+		if seen[fd.Name] {
+			continue
+		}
+		seen[fd.Name] = true
+		fd.ControlPlane = ptr.To(ptr.Deref(fd.ControlPlane, false))
+		out = append(out, fd)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
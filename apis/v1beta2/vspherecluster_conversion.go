@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+// ConvertTo converts this VSphereCluster to the Hub version (v1beta1).
+func (src *VSphereCluster) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*infrav1.VSphereCluster)
+	if err := Convert_v1beta2_VSphereCluster_To_v1beta1_VSphereCluster(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Drop Hub data preserved on up-conversion, VSphereCluster has no v1beta1 only fields.
+	if _, err := utilconversion.UnmarshalData(src, &infrav1.VSphereCluster{}); err != nil {
+		return err
+	}
+
+	// Preserve v1beta2 data on down-conversion except for metadata
+	return utilconversion.MarshalData(src, dst)
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this VSphereCluster.
+func (dst *VSphereCluster) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*infrav1.VSphereCluster)
+
+	// Manually restore data.
+	restored := &VSphereCluster{}
+	if _, err := utilconversion.UnmarshalData(src, restored); err != nil {
+		return err
+	}
+
+	if err := Convert_v1beta1_VSphereCluster_To_v1beta2_VSphereCluster(src, dst, nil); err != nil {
+		return err
+	}
+
+	// v1beta2 conditions can't be represented in v1beta1.
+	dst.Status.Conditions = restored.Status.Conditions
+
+	// Preserve Hub data on up-conversion except for metadata
+	return utilconversion.MarshalData(src, dst)
+}
+
+// ConvertTo converts this VSphereClusterList to the Hub version (v1beta1).
+func (src *VSphereClusterList) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*infrav1.VSphereClusterList)
+	return Convert_v1beta2_VSphereClusterList_To_v1beta1_VSphereClusterList(src, dst, nil)
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this VSphereClusterList.
+func (dst *VSphereClusterList) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*infrav1.VSphereClusterList)
+	return Convert_v1beta1_VSphereClusterList_To_v1beta2_VSphereClusterList(src, dst, nil)
+}
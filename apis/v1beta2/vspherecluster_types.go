@@ -0,0 +1,474 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// ClusterFinalizer allows ReconcileVSphereCluster to clean up vSphere
+	// resources associated with VSphereCluster before removing it from the
+	// API server.
+	ClusterFinalizer = "vspherecluster.infrastructure.cluster.x-k8s.io"
+
+	// ControlPlaneEndpointIPAddressClaimFinalizer prevents the IPAddressClaim of the control plane
+	// endpoint managed by CAPV from being deleted before the VSphereCluster.
+	ControlPlaneEndpointIPAddressClaimFinalizer = "vspherecluster.infrastructure.cluster.x-k8s.io/ip-claim-protection"
+)
+
+// VCenterVersion conveys the API version of the vCenter instance.
+type VCenterVersion string
+
+// NewVCenterVersion returns a VCenterVersion for the passed string.
+func NewVCenterVersion(version string) VCenterVersion {
+	return VCenterVersion(version)
+}
+
+// VSphereClusterSpec defines the desired state of VSphereCluster.
+type VSphereClusterSpec struct {
+	// Server is the address of the vSphere endpoint.
+	Server string `json:"server,omitempty"`
+
+	// Thumbprint is the colon-separated SHA-1 checksum of the given vCenter server's host certificate
+	// Consider using TLSConfig instead, which does not require updating the thumbprint
+	// when the certificate of vCenter is rotated.
+	// +optional
+	Thumbprint string `json:"thumbprint,omitempty"`
+
+	// ControlPlaneEndpoint represents the endpoint used to communicate with the control plane.
+	// +optional
+	ControlPlaneEndpoint APIEndpoint `json:"controlPlaneEndpoint"`
+
+	// IdentityRef is a reference to either a Secret or VSphereClusterIdentity that contains
+	// the identity to use when reconciling the cluster.
+	// +optional
+	IdentityRef *VSphereIdentityReference `json:"identityRef,omitempty"`
+
+	// ClusterModules hosts information regarding the anti-affinity vSphere constructs
+	// for each of the objects responsible for creation of VM objects belonging to the cluster.
+	// +optional
+	ClusterModules []ClusterModule `json:"clusterModules,omitempty"`
+
+	// DisableClusterModule is used to explicitly turn off the ClusterModule feature.
+	// This should work along side NodeAntiAffinity feature flag.
+	// If the NodeAntiAffinity feature flag is turned off, this will be disregarded.
+	// +optional
+	DisableClusterModule bool `json:"disableClusterModule,omitempty"`
+
+	// ClusterModuleLifecycle configures how cluster modules are named, shared between
+	// MachineDeployments and garbage collected once they are no longer used.
+	// If not set, every KubeadmControlPlane and MachineDeployment gets its own cluster module,
+	// which is deleted as soon as the object is deleted.
+	// +optional
+	ClusterModuleLifecycle *ClusterModuleLifecycle `json:"clusterModuleLifecycle,omitempty"`
+
+	// FailureDomainSelector is the label selector to use for failure domain selection
+	// for the control plane nodes of the cluster.
+	// If not set (`nil`), selecting failure domains will be disabled.
+	// An empty value (`{}`) selects all existing failure domains.
+	// A valid selector will select all failure domains which match the selector.
+	// +optional
+	FailureDomainSelector *metav1.LabelSelector `json:"failureDomainSelector,omitempty"`
+
+	// HostEvacuation defines how the Nodes of the cluster are handled when their VMs
+	// are about to be moved off their ESXi host, e.g. because of DRS or host maintenance.
+	// +optional
+	HostEvacuation *HostEvacuationSpec `json:"hostEvacuation,omitempty"`
+
+	// Proxy is the proxy used for the connections to the vSphere endpoint.
+	// If not set, the proxy configured for the controller manager is used.
+	// +optional
+	Proxy *VCenterProxySpec `json:"proxy,omitempty"`
+
+	// TLSConfig is the TLS configuration used for the connections to the vSphere endpoint.
+	// If not set, the TLS configuration of the referenced VSphereClusterIdentity is used.
+	// +optional
+	TLSConfig *VCenterTLSConfig `json:"tlsConfig,omitempty"`
+
+	// CustomAttributes is a map of vSphere custom attribute names to values which are
+	// set on all virtual machines of the cluster, e.g. owner or cost center.
+	// Values set for the same custom attribute in the VSphereMachine take precedence.
+	// +optional
+	CustomAttributes map[string]string `json:"customAttributes,omitempty"`
+
+	// FolderNamingStrategy allows placing the virtual machines of the cluster in a folder of
+	// their own, which is created in the folder of the virtual machines if it doesn't exist.
+	// If not set, the virtual machines are placed directly in their folder.
+	// The strategy only applies to virtual machines created after it is set.
+	// +optional
+	FolderNamingStrategy *VSphereFolderNamingStrategy `json:"folderNamingStrategy,omitempty"`
+
+	// ControlPlaneEndpointManagement configures CAPV to manage the control plane endpoint of the cluster:
+	// an IP address is allocated from an IPAM pool, set as the control plane endpoint and served by
+	// kube-vip, which is added to the bootstrap data of the control plane machines.
+	// If set, ControlPlaneEndpoint is set by CAPV and must not be set.
+	// +optional
+	ControlPlaneEndpointManagement *ControlPlaneEndpointManagementSpec `json:"controlPlaneEndpointManagement,omitempty"`
+}
+
+// ControlPlaneEndpointManagementSpec configures the control plane endpoint managed by CAPV.
+type ControlPlaneEndpointManagementSpec struct {
+	// AddressesFromPool is a reference to the IPAM pool the IP address of the control plane endpoint
+	// is allocated from, e.g. an InClusterIPPool.
+	AddressesFromPool corev1.TypedLocalObjectReference `json:"addressesFromPool"`
+
+	// Port is the port of the control plane endpoint.
+	// Defaults to 6443.
+	// +kubebuilder:default=6443
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// KubeVIP configures the kube-vip static pod serving the control plane endpoint.
+	// +optional
+	KubeVIP KubeVIPSpec `json:"kubeVIP,omitempty"`
+}
+
+// KubeVIPSpec configures the kube-vip static pod added to the control plane machines.
+type KubeVIPSpec struct {
+	// Image is the kube-vip image.
+	// If not set, the image of the kube-vip manifest shipped with CAPV is used.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Interface is the network interface of the control plane machines the IP address is announced on.
+	// If not set, kube-vip uses the interface of the default route.
+	// +optional
+	Interface string `json:"interface,omitempty"`
+}
+
+// VSphereFolderNamingStrategy defines the naming strategy for the folder of the virtual machines of a cluster.
+type VSphereFolderNamingStrategy struct {
+	// Template defines the template to use for generating the name of the folder.
+	// The templating has the following data available:
+	// * `.cluster.name`: The name of the Cluster object.
+	// * `.cluster.namespace`: The namespace of the Cluster object.
+	// The templating also has the following funcs available:
+	// * `trimSuffix`: same as strings.TrimSuffix
+	// * `trunc`: truncates a string, e.g. `trunc 2 "hello"` or `trunc -2 "hello"`
+	// Names are automatically truncated at 80 characters, and must not contain a slash.
+	// +kubebuilder:validation:MinLength=1
+	Template string `json:"template"`
+}
+
+// VCenterProxySpec defines the proxy used to connect to the vSphere endpoint.
+type VCenterProxySpec struct {
+	// URL is the URL of the HTTP or SOCKS5 proxy,
+	// e.g. http://proxy.example.com:3128 or socks5://proxy.example.com:1080.
+	// +kubebuilder:validation:Pattern=`^(http|socks5)://.+`
+	URL string `json:"url"`
+
+	// NoProxy is a list of hosts, domains (e.g. .example.com), IP addresses and CIDRs
+	// which are connected to directly instead of through the proxy.
+	// +optional
+	NoProxy []string `json:"noProxy,omitempty"`
+
+	// CABundle is a PEM encoded bundle of CA certificates used to verify the certificate of
+	// the vSphere endpoint, e.g. when the proxy intercepts TLS connections.
+	// The certificates are trusted in addition to the ones referenced by the TLS configuration.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+}
+
+// TLSVersion is a TLS protocol version.
+// +kubebuilder:validation:Enum="1.2";"1.3"
+type TLSVersion string
+
+const (
+	// TLSVersion12 is TLS 1.2.
+	TLSVersion12 = TLSVersion("1.2")
+	// TLSVersion13 is TLS 1.3.
+	TLSVersion13 = TLSVersion("1.3")
+)
+
+// VCenterTLSConfig is the TLS configuration used for the connections to vCenter.
+// If set, the certificate of vCenter is verified using the CA certificates of CASecretRef
+// or the system root CAs, unless InsecureSkipVerify is set. If a thumbprint is set as well,
+// certificates which are not trusted by the CA certificates are accepted if they match the thumbprint.
+type VCenterTLSConfig struct {
+	// CASecretRef references a Secret containing the PEM encoded CA certificates used to verify
+	// the certificate of vCenter. The certificates are trusted in addition to the CA bundle of
+	// the proxy. If neither is set, the system root CAs are used.
+	// +optional
+	CASecretRef *CASecretReference `json:"caSecretRef,omitempty"`
+
+	// InsecureSkipVerify disables the verification of the certificate of vCenter.
+	// This should only be used for testing.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+
+	// MinVersion is the minimum TLS version used for the connections to vCenter.
+	// If not set, TLS 1.2 is used.
+	// +optional
+	MinVersion TLSVersion `json:"minVersion,omitempty"`
+}
+
+// CASecretReference references a key of a Secret containing PEM encoded CA certificates.
+type CASecretReference struct {
+	// Name of the Secret. The Secret must be in the namespace of the VSphereCluster or, if referenced
+	// by a VSphereClusterIdentity, in the namespace of the controller manager.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Key of the Secret containing the CA certificates.
+	// If not set, ca.crt is used.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// HostEvacuationSpec defines how Nodes are handled when their VMs are evacuated from an ESXi host.
+type HostEvacuationSpec struct {
+	// DrainNodesWithLocalStorage, if true, cordons and drains the Node of a VM which uses local
+	// (not shared) storage before the VM gets migrated by DRS or its host enters maintenance mode.
+	// The Node is uncordoned once the evacuation is completed.
+	// Requires the HostEvacuation feature gate.
+	// +optional
+	DrainNodesWithLocalStorage bool `json:"drainNodesWithLocalStorage,omitempty"`
+}
+
+// ClusterModule holds the anti affinity construct `ClusterModule` identifier
+// in use by the VMs owned by the object referred by the TargetObjectName field.
+type ClusterModule struct {
+	// ControlPlane indicates whether the referred object is responsible for control plane nodes.
+	// Currently, only the KubeadmControlPlane objects have this flag set to true.
+	// Only a single object in the slice can have this value set to true.
+	ControlPlane bool `json:"controlPlane"`
+
+	// TargetObjectName points to the object that uses the Cluster Module information to enforce
+	// anti-affinity amongst its descendant VM objects.
+	TargetObjectName string `json:"targetObjectName"`
+
+	// ModuleUUID is the unique identifier of the `ClusterModule` used by the object.
+	ModuleUUID string `json:"moduleUUID"`
+
+	// Name is the name of the cluster module rendered from the naming template.
+	// Objects with the same name share the `ClusterModule`.
+	// If empty, the TargetObjectName is used as the name.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// UnusedSince is the time since when the `ClusterModule` is no longer used by any object.
+	// The `ClusterModule` is deleted once the deletion grace period has passed.
+	// +optional
+	UnusedSince *metav1.Time `json:"unusedSince,omitempty"`
+}
+
+// ClusterModuleLifecycle configures the lifecycle of the cluster modules of a cluster.
+type ClusterModuleLifecycle struct {
+	// NameTemplate is the Go template used to generate the name of the cluster module
+	// of a MachineDeployment. MachineDeployments for which the same name is generated share
+	// a single cluster module, so their VMs are spread across hosts together.
+	// MachineDeployments sharing a cluster module must use the same compute cluster.
+	// The following variables can be used in the template:
+	// `.cluster.name`, `.machineDeployment.name` and `.machineDeployment.labels`.
+	// The control plane always uses a dedicated cluster module.
+	// Defaults to `{{ .machineDeployment.name }}`, which creates one cluster module per MachineDeployment.
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	NameTemplate *string `json:"nameTemplate,omitempty"`
+
+	// DeletionGracePeriod is the duration for which a cluster module which is no longer
+	// used by any object is kept before it is deleted. Objects created within the grace
+	// period for which the same name is generated reuse the cluster module.
+	// Cluster modules are always deleted immediately when the cluster is deleted.
+	// Defaults to 0, which deletes unused cluster modules immediately.
+	// +optional
+	DeletionGracePeriod *metav1.Duration `json:"deletionGracePeriod,omitempty"`
+}
+
+// VSphereClusterStatus defines the observed state of VSphereClusterSpec.
+type VSphereClusterStatus struct {
+	// Conditions represents the observations of a VSphereCluster's current state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +kubebuilder:validation:MaxItems=32
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Initialization provides observations of the VSphereCluster initialization process.
+	// NOTE: Fields in this struct are part of the Cluster API contract and are used to orchestrate initial Cluster provisioning.
+	// +optional
+	Initialization *VSphereClusterInitializationStatus `json:"initialization,omitempty"`
+
+	// FailureDomains is a list of failure domain objects synced from the infrastructure provider.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=100
+	FailureDomains []VSphereClusterFailureDomain `json:"failureDomains,omitempty"`
+
+	// VCenterVersion defines the version of the vCenter server defined in the spec.
+	VCenterVersion VCenterVersion `json:"vCenterVersion,omitempty"`
+
+	// ResourceUsage is the vSphere resource usage of the VMs of the cluster.
+	// It is refreshed periodically.
+	// +optional
+	ResourceUsage *VSphereClusterResourceUsage `json:"resourceUsage,omitempty"`
+
+	// ControlPlaneEndpoint is the control plane endpoint allocated when ControlPlaneEndpointManagement is set.
+	// +optional
+	ControlPlaneEndpoint *APIEndpoint `json:"controlPlaneEndpoint,omitempty"`
+
+	// DeprecatedFieldUsage summarizes the deprecated fields set on the VSphereMachines and
+	// VSphereVMs of the cluster, along with how to migrate away from them.
+	// +optional
+	// +listType=atomic
+	DeprecatedFieldUsage []VSphereClusterDeprecatedFieldUsage `json:"deprecatedFieldUsage,omitempty"`
+
+	// Deprecated groups all the status fields that are deprecated and will be removed when all the nested field are removed.
+	// +optional
+	Deprecated *VSphereClusterDeprecatedStatus `json:"deprecated,omitempty"`
+}
+
+// VSphereClusterDeprecatedFieldUsage is the usage of a deprecated field by the objects of a cluster.
+type VSphereClusterDeprecatedFieldUsage struct {
+	// Kind is the kind of the objects setting the deprecated field.
+	Kind string `json:"kind"`
+
+	// Field is the path of the deprecated field.
+	Field string `json:"field"`
+
+	// Count is the number of objects setting the deprecated field.
+	Count int32 `json:"count"`
+
+	// Migration describes how to stop using the deprecated field.
+	Migration string `json:"migration"`
+}
+
+// VSphereClusterInitializationStatus provides observations of the VSphereCluster initialization process.
+type VSphereClusterInitializationStatus struct {
+	// Provisioned is true when the infrastructure provider reports that the Cluster's infrastructure is fully provisioned.
+	// NOTE: this field is part of the Cluster API contract, and it is used to orchestrate initial Cluster provisioning.
+	// +optional
+	Provisioned *bool `json:"provisioned,omitempty"`
+}
+
+// VSphereClusterDeprecatedStatus groups all the status fields that are deprecated and will be removed in a future version.
+type VSphereClusterDeprecatedStatus struct {
+	// V1Beta1 groups all the status fields that are deprecated and will be removed when support for v1beta1 will be dropped.
+	// +optional
+	V1Beta1 *VSphereClusterV1Beta1DeprecatedStatus `json:"v1beta1,omitempty"`
+}
+
+// VSphereClusterV1Beta1DeprecatedStatus groups all the status fields that are deprecated and will be removed when support for v1beta1 will be dropped.
+type VSphereClusterV1Beta1DeprecatedStatus struct {
+	// Conditions defines current service state of the VSphereCluster.
+	//
+	// Deprecated: This field is deprecated and is going to be removed when support for v1beta1 will be dropped.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// VSphereClusterFailureDomain is the Schema for Cluster API failure domains.
+// It allows controllers to understand how many failure domains a cluster can optionally span across.
+type VSphereClusterFailureDomain struct {
+	// Name is the name of the failure domain.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=256
+	Name string `json:"name"`
+
+	// ControlPlane determines if this failure domain is suitable for use by control plane machines.
+	// +optional
+	ControlPlane *bool `json:"controlPlane,omitempty"`
+
+	// Attributes is a free form map of attributes an infrastructure provider might use or require.
+	// +optional
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// VSphereClusterResourceUsage is the vSphere resource usage aggregated over the VMs of a cluster.
+type VSphereClusterResourceUsage struct {
+	// Machines is the number of VMs the resource usage is aggregated from.
+	Machines int32 `json:"machines"`
+
+	// CPU is the number of virtual CPUs of the VMs.
+	CPU resource.Quantity `json:"cpu"`
+
+	// Memory is the memory of the VMs.
+	Memory resource.Quantity `json:"memory"`
+
+	// Storage is the storage committed by the VMs on the datastores.
+	Storage resource.Quantity `json:"storage"`
+
+	// LastUpdated is the time the resource usage was last refreshed.
+	LastUpdated metav1.Time `json:"lastUpdated"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=vsphereclusters,scope=Namespaced,categories=cluster-api
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Provisioned",type="string",JSONPath=".status.initialization.provisioned",description="Cluster infrastructure is provisioned for VSphereMachine"
+// +kubebuilder:printcolumn:name="Server",type="string",JSONPath=".spec.server",description="Server is the address of the vSphere endpoint."
+// +kubebuilder:printcolumn:name="ControlPlaneEndpoint",type="string",JSONPath=".spec.controlPlaneEndpoint[0]",description="API Endpoint",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of Machine"
+
+// VSphereCluster is the Schema for the vsphereclusters API.
+type VSphereCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VSphereClusterSpec   `json:"spec,omitempty"`
+	Status VSphereClusterStatus `json:"status,omitempty"`
+}
+
+// GetV1Beta1Conditions returns the set of conditions for this object.
+func (c *VSphereCluster) GetV1Beta1Conditions() clusterv1.Conditions {
+	if c.Status.Deprecated == nil || c.Status.Deprecated.V1Beta1 == nil {
+		return nil
+	}
+	return c.Status.Deprecated.V1Beta1.Conditions
+}
+
+// SetV1Beta1Conditions sets the conditions on this object.
+func (c *VSphereCluster) SetV1Beta1Conditions(conditions clusterv1.Conditions) {
+	if c.Status.Deprecated == nil {
+		c.Status.Deprecated = &VSphereClusterDeprecatedStatus{}
+	}
+	if c.Status.Deprecated.V1Beta1 == nil {
+		c.Status.Deprecated.V1Beta1 = &VSphereClusterV1Beta1DeprecatedStatus{}
+	}
+	c.Status.Deprecated.V1Beta1.Conditions = conditions
+}
+
+// GetConditions returns the set of conditions for this object.
+func (c *VSphereCluster) GetConditions() []metav1.Condition {
+	return c.Status.Conditions
+}
+
+// SetConditions sets conditions for an API object.
+func (c *VSphereCluster) SetConditions(conditions []metav1.Condition) {
+	c.Status.Conditions = conditions
+}
+
+// +kubebuilder:object:root=true
+
+// VSphereClusterList contains a list of VSphereCluster.
+type VSphereClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VSphereCluster `json:"items"`
+}
+
+func init() {
+	objectTypes = append(objectTypes, &VSphereCluster{}, &VSphereClusterList{})
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/errors"
+)
+
+const (
+	// MachineFinalizer allows ReconcileVSphereMachine to clean up VSphere
+	// resources associated with VSphereMachine before removing it from the
+	// API Server.
+	MachineFinalizer = "vspheremachine.infrastructure.cluster.x-k8s.io"
+)
+
+// VSphereMachineSpec defines the desired state of VSphereMachine.
+type VSphereMachineSpec struct {
+	VirtualMachineCloneSpec `json:",inline"`
+
+	// ProviderID is the virtual machine's BIOS UUID formated as
+	// vsphere://12345678-1234-1234-1234-123456789abc
+	// +optional
+	ProviderID *string `json:"providerID,omitempty"`
+
+	// FailureDomain is the failure domain unique identifier this Machine should be attached to, as defined in Cluster API.
+	// For this infrastructure provider, the name is equivalent to the name of the VSphereDeploymentZone.
+	FailureDomain *string `json:"failureDomain,omitempty"`
+
+	// PowerOffMode describes the desired behavior when powering off a VM.
+	//
+	// There are three, supported power off modes: hard, soft, and
+	// trySoft. The first mode, hard, is the equivalent of a physical
+	// system's power cord being ripped from the wall. The soft mode
+	// requires the VM's guest to have VM Tools installed and attempts to
+	// gracefully shut down the VM. Its variant, trySoft, first attempts
+	// a graceful shutdown, and if that fails or the VM is not in a powered off
+	// state after reaching the GuestSoftPowerOffTimeout, the VM is halted.
+	//
+	// If omitted, the mode defaults to hard.
+	//
+	// +optional
+	// +kubebuilder:default=hard
+	PowerOffMode VirtualMachinePowerOpMode `json:"powerOffMode,omitempty"`
+
+	// GuestSoftPowerOffTimeout sets the wait timeout for shutdown in the VM guest.
+	// The VM will be powered off forcibly after the timeout if the VM is still
+	// up and running when the PowerOffMode is set to trySoft.
+	//
+	// This parameter only applies when the PowerOffMode is set to trySoft.
+	//
+	// If omitted, the timeout defaults to 5 minutes.
+	//
+	// +optional
+	GuestSoftPowerOffTimeout *metav1.Duration `json:"guestSoftPowerOffTimeout,omitempty"`
+
+	// GuestReadinessGates is a list of guest conditions which must be true
+	// before the VM is marked as provisioned. By default a VM is marked as
+	// provisioned as soon as it is powered on and reports IP addresses,
+	// regardless of the state of the guest agent.
+	// +optional
+	// +listType=set
+	GuestReadinessGates []GuestReadinessGate `json:"guestReadinessGates,omitempty"`
+
+	// NamingStrategy allows configuring the naming strategy used when calculating the name of the VSphereVM
+	// and of the virtual machine in vCenter.
+	// +optional
+	NamingStrategy *VSphereVMNamingStrategy `json:"namingStrategy,omitempty"`
+}
+
+// VSphereVMNamingStrategy defines the naming strategy for the VSphereVMs of VSphereMachines.
+type VSphereVMNamingStrategy struct {
+	// Template defines the template to use for generating the name of the VSphereVM object.
+	// If not defined, it will fall back to `{{ .machine.name }}`.
+	// The templating has the following data available:
+	// * `.machine.name`: The name of the Machine object.
+	// The templating also has the following funcs available:
+	// * `trimSuffix`: same as strings.TrimSuffix
+	// * `trunc`: truncates a string, e.g. `trunc 2 "hello"` or `trunc -2 "hello"`
+	// Notes:
+	// * Generated names must be valid Kubernetes names as they are used to create a VSphereVM object
+	//   and usually also as the name of the virtual machine and of the Node object.
+	// * Names are automatically truncated at 63 characters. Please note that this can lead to name conflicts,
+	//   so we highly recommend to use a template which leads to a name shorter than 63 characters.
+	// * Names of Windows machines are additionally shortened to 15 characters.
+	// +optional
+	Template *string `json:"template,omitempty"`
+}
+
+// VSphereMachineStatus defines the observed state of VSphereMachine.
+type VSphereMachineStatus struct {
+	// Conditions represents the observations of a VSphereMachine's current state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +kubebuilder:validation:MaxItems=32
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Initialization provides observations of the VSphereMachine initialization process.
+	// NOTE: Fields in this struct are part of the Cluster API contract and are used to orchestrate initial Machine provisioning.
+	// +optional
+	Initialization *VSphereMachineInitializationStatus `json:"initialization,omitempty"`
+
+	// Addresses contains the VSphere instance associated addresses.
+	Addresses []clusterv1.MachineAddress `json:"addresses,omitempty"`
+
+	// Network returns the network status for each of the machine's configured
+	// network interfaces.
+	// +optional
+	Network []NetworkStatus `json:"network,omitempty"`
+
+	// FailureDomain is the name of the VSphereDeploymentZone the control plane machine was
+	// placed in, if the Machine does not define a failure domain.
+	// Control plane machines without a failure domain are spread across the ready
+	// VSphereDeploymentZones suitable for control plane machines.
+	// +optional
+	FailureDomain *string `json:"failureDomain,omitempty"`
+
+	// Deprecated groups all the status fields that are deprecated and will be removed when all the nested field are removed.
+	// +optional
+	Deprecated *VSphereMachineDeprecatedStatus `json:"deprecated,omitempty"`
+}
+
+// VSphereMachineInitializationStatus provides observations of the VSphereMachine initialization process.
+type VSphereMachineInitializationStatus struct {
+	// Provisioned is true when the infrastructure provider reports that the Machine's infrastructure is fully provisioned.
+	// NOTE: this field is part of the Cluster API contract, and it is used to orchestrate initial Machine provisioning.
+	// +optional
+	Provisioned *bool `json:"provisioned,omitempty"`
+}
+
+// VSphereMachineDeprecatedStatus groups all the status fields that are deprecated and will be removed in a future version.
+type VSphereMachineDeprecatedStatus struct {
+	// V1Beta1 groups all the status fields that are deprecated and will be removed when support for v1beta1 will be dropped.
+	// +optional
+	V1Beta1 *VSphereMachineV1Beta1DeprecatedStatus `json:"v1beta1,omitempty"`
+}
+
+// VSphereMachineV1Beta1DeprecatedStatus groups all the status fields that are deprecated and will be removed when support for v1beta1 will be dropped.
+type VSphereMachineV1Beta1DeprecatedStatus struct {
+	// Conditions defines current service state of the VSphereMachine.
+	//
+	// Deprecated: This field is deprecated and is going to be removed when support for v1beta1 will be dropped.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+
+	// FailureReason will be set in the event that there is a terminal problem
+	// reconciling the Machine and will contain a succinct value suitable
+	// for machine interpretation.
+	//
+	// This field should not be set for transitive errors that a controller
+	// faces that are expected to be fixed automatically over
+	// time (like service outages), but instead indicate that something is
+	// fundamentally wrong with the Machine's spec or the configuration of
+	// the controller, and that manual intervention is required. Examples
+	// of terminal errors would be invalid combinations of settings in the
+	// spec, values that are unsupported by the controller, or the
+	// responsible controller itself being critically misconfigured.
+	//
+	// Any transient errors that occur during the reconciliation of Machines
+	// can be added as events to the Machine object and/or logged in the
+	// controller's output.
+	//
+	// Deprecated: This field is deprecated and is going to be removed when support for v1beta1 will be dropped.
+	// +optional
+	FailureReason *errors.MachineStatusError `json:"failureReason,omitempty"`
+
+	// FailureMessage will be set in the event that there is a terminal problem
+	// reconciling the Machine and will contain a more verbose string suitable
+	// for logging and human consumption.
+	//
+	// This field should not be set for transitive errors that a controller
+	// faces that are expected to be fixed automatically over
+	// time (like service outages), but instead indicate that something is
+	// fundamentally wrong with the Machine's spec or the configuration of
+	// the controller, and that manual intervention is required. Examples
+	// of terminal errors would be invalid combinations of settings in the
+	// spec, values that are unsupported by the controller, or the
+	// responsible controller itself being critically misconfigured.
+	//
+	// Any transient errors that occur during the reconciliation of Machines
+	// can be added as events to the Machine object and/or logged in the
+	// controller's output.
+	//
+	// Deprecated: This field is deprecated and is going to be removed when support for v1beta1 will be dropped.
+	// +optional
+	FailureMessage *string `json:"failureMessage,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=vspheremachines,scope=Namespaced,categories=cluster-api
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".metadata.labels.cluster\\.x-k8s\\.io/cluster-name",description="Cluster to which this VSphereMachine belongs"
+// +kubebuilder:printcolumn:name="Provisioned",type="string",JSONPath=".status.initialization.provisioned",description="Machine provisioned status"
+// +kubebuilder:printcolumn:name="ProviderID",type="string",JSONPath=".spec.providerID",description="VSphereMachine instance ID"
+// +kubebuilder:printcolumn:name="Machine",type="string",JSONPath=".metadata.ownerReferences[?(@.kind==\"Machine\")].name",description="Machine object which owns with this VSphereMachine",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of Machine"
+
+// VSphereMachine is the Schema for the vspheremachines API.
+type VSphereMachine struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VSphereMachineSpec   `json:"spec,omitempty"`
+	Status VSphereMachineStatus `json:"status,omitempty"`
+}
+
+// GetV1Beta1Conditions returns the set of conditions for this object.
+func (m *VSphereMachine) GetV1Beta1Conditions() clusterv1.Conditions {
+	if m.Status.Deprecated == nil || m.Status.Deprecated.V1Beta1 == nil {
+		return nil
+	}
+	return m.Status.Deprecated.V1Beta1.Conditions
+}
+
+// SetV1Beta1Conditions sets the conditions on this object.
+func (m *VSphereMachine) SetV1Beta1Conditions(conditions clusterv1.Conditions) {
+	if m.Status.Deprecated == nil {
+		m.Status.Deprecated = &VSphereMachineDeprecatedStatus{}
+	}
+	if m.Status.Deprecated.V1Beta1 == nil {
+		m.Status.Deprecated.V1Beta1 = &VSphereMachineV1Beta1DeprecatedStatus{}
+	}
+	m.Status.Deprecated.V1Beta1.Conditions = conditions
+}
+
+// GetConditions returns the set of conditions for this object.
+func (m *VSphereMachine) GetConditions() []metav1.Condition {
+	return m.Status.Conditions
+}
+
+// SetConditions sets conditions for an API object.
+func (m *VSphereMachine) SetConditions(conditions []metav1.Condition) {
+	m.Status.Conditions = conditions
+}
+
+// +kubebuilder:object:root=true
+
+// VSphereMachineList contains a list of VSphereMachine.
+type VSphereMachineList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VSphereMachine `json:"items"`
+}
+
+func init() {
+	objectTypes = append(objectTypes, &VSphereMachine{}, &VSphereMachineList{})
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+// ConvertTo converts this VSphereVM to the Hub version (v1beta1).
+func (src *VSphereVM) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*infrav1.VSphereVM)
+	if err := Convert_v1beta2_VSphereVM_To_v1beta1_VSphereVM(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &infrav1.VSphereVM{}
+	ok, err := utilconversion.UnmarshalData(src, restored)
+	if err != nil {
+		return err
+	}
+	if ok {
+		dst.Spec.Network.PreferredAPIServerCIDR = restored.Spec.Network.PreferredAPIServerCIDR
+	}
+
+	// Preserve v1beta2 data on down-conversion except for metadata
+	return utilconversion.MarshalData(src, dst)
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this VSphereVM.
+func (dst *VSphereVM) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*infrav1.VSphereVM)
+
+	// Manually restore data.
+	restored := &VSphereVM{}
+	if _, err := utilconversion.UnmarshalData(src, restored); err != nil {
+		return err
+	}
+
+	if err := Convert_v1beta1_VSphereVM_To_v1beta2_VSphereVM(src, dst, nil); err != nil {
+		return err
+	}
+
+	// v1beta2 conditions can't be represented in v1beta1.
+	dst.Status.Conditions = restored.Status.Conditions
+
+	// Preserve Hub data on up-conversion except for metadata
+	return utilconversion.MarshalData(src, dst)
+}
+
+// ConvertTo converts this VSphereVMList to the Hub version (v1beta1).
+func (src *VSphereVMList) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*infrav1.VSphereVMList)
+	return Convert_v1beta2_VSphereVMList_To_v1beta1_VSphereVMList(src, dst, nil)
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this VSphereVMList.
+func (dst *VSphereVMList) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*infrav1.VSphereVMList)
+	return Convert_v1beta1_VSphereVMList_To_v1beta2_VSphereVMList(src, dst, nil)
+}
@@ -0,0 +1,342 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/errors"
+)
+
+const (
+	// VMFinalizer allows the reconciler to clean up resources associated
+	// with a VSphereVM before removing it from the API Server.
+	VMFinalizer = "vspherevm.infrastructure.cluster.x-k8s.io"
+
+	// IPAddressClaimFinalizer allows the reconciler to prevent deletion of an
+	// IPAddressClaim that is in use.
+	IPAddressClaimFinalizer = "vspherevm.infrastructure.cluster.x-k8s.io/ip-claim-protection"
+
+	// GuestSoftPowerOffDefaultTimeout is the default timeout to wait for
+	// shutdown finishes in the guest VM before powering off the VM forcibly
+	// Only effective when the powerOffMode is set to trySoft.
+	GuestSoftPowerOffDefaultTimeout = 5 * time.Minute
+)
+
+// VSphereVMSpec defines the desired state of VSphereVM.
+type VSphereVMSpec struct {
+	VirtualMachineCloneSpec `json:",inline"`
+
+	// BootstrapRef is a reference to a bootstrap provider-specific resource
+	// that holds configuration details.
+	// This field is optional in case no bootstrap data is required to create
+	// a VM.
+	// +optional
+	BootstrapRef *corev1.ObjectReference `json:"bootstrapRef,omitempty"`
+
+	// BiosUUID is the VM's BIOS UUID that is assigned at runtime after
+	// the VM has been created.
+	// This field is required at runtime for other controllers that read
+	// this CRD as unstructured data.
+	// +optional
+	BiosUUID string `json:"biosUUID,omitempty"`
+
+	// PowerOffMode describes the desired behavior when powering off a VM.
+	//
+	// There are three, supported power off modes: hard, soft, and
+	// trySoft. The first mode, hard, is the equivalent of a physical
+	// system's power cord being ripped from the wall. The soft mode
+	// requires the VM's guest to have VM Tools installed and attempts to
+	// gracefully shut down the VM. Its variant, trySoft, first attempts
+	// a graceful shutdown, and if that fails or the VM is not in a powered off
+	// state after reaching the GuestSoftPowerOffTimeout, the VM is halted.
+	//
+	// If omitted, the mode defaults to hard.
+	//
+	// +optional
+	// +kubebuilder:default=hard
+	PowerOffMode VirtualMachinePowerOpMode `json:"powerOffMode,omitempty"`
+
+	// GuestSoftPowerOffTimeout sets the wait timeout for shutdown in the VM guest.
+	// The VM will be powered off forcibly after the timeout if the VM is still
+	// up and running when the PowerOffMode is set to trySoft.
+	//
+	// This parameter only applies when the PowerOffMode is set to trySoft.
+	//
+	// If omitted, the timeout defaults to 5 minutes.
+	//
+	// +optional
+	GuestSoftPowerOffTimeout *metav1.Duration `json:"guestSoftPowerOffTimeout,omitempty"`
+
+	// GuestReadinessGates is a list of guest conditions which must be true
+	// before the VM is marked as provisioned. By default a VM is marked as
+	// provisioned as soon as it is powered on and reports IP addresses,
+	// regardless of the state of the guest agent.
+	// +optional
+	// +listType=set
+	GuestReadinessGates []GuestReadinessGate `json:"guestReadinessGates,omitempty"`
+
+	// RelocateTo triggers a storage and/or compute vMotion of the VM to the
+	// given datastore, host and resource pool, e.g. to evacuate a datastore
+	// without recreating the VM.
+	// The VM is relocated whenever it is not located at the given targets.
+	// +optional
+	RelocateTo *VSphereVMRelocateTo `json:"relocateTo,omitempty"`
+
+	// PowerState is the desired power state of the VM. It allows to temporarily
+	// power off or suspend the VM, e.g. to save costs in a lab, without deleting it.
+	// The VM is powered off according to the PowerOffMode.
+	// A VM which is intentionally not powered on is reported with the PoweredOff
+	// or Suspended reason on the VMProvisioned condition.
+	//
+	// If omitted, the VM is powered on.
+	//
+	// +optional
+	// +kubebuilder:validation:Enum=poweredOn;poweredOff;suspended
+	PowerState VirtualMachinePowerState `json:"powerState,omitempty"`
+}
+
+// VSphereVMRelocateTo describes the targets a VSphereVM is relocated to.
+// At least one of the targets must be set; targets which are not set are
+// left unchanged.
+type VSphereVMRelocateTo struct {
+	// Datastore is the name or inventory path of the datastore the VM home
+	// and all the disks of the VM are moved to.
+	// +optional
+	Datastore string `json:"datastore,omitempty"`
+
+	// Host is the name or inventory path of the ESXi host the VM is moved to.
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// ResourcePool is the name or inventory path of the resource pool the VM
+	// is moved to.
+	// +optional
+	ResourcePool string `json:"resourcePool,omitempty"`
+}
+
+// VSphereVMRelocationStatus describes the progress of the relocation of a VSphereVM.
+type VSphereVMRelocationStatus struct {
+	// Target is the target of the in-flight or of the last completed relocation.
+	Target VSphereVMRelocateTo `json:"target"`
+
+	// Progress is the progress of the relocation in percent.
+	// +optional
+	Progress int32 `json:"progress,omitempty"`
+
+	// CompletionTime is the time the relocation was completed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// VSphereVMStatus defines the observed state of VSphereVM.
+type VSphereVMStatus struct {
+	// Conditions represents the observations of a VSphereVM's current state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +kubebuilder:validation:MaxItems=32
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Initialization provides observations of the VSphereVM initialization process.
+	// +optional
+	Initialization *VSphereVMInitializationStatus `json:"initialization,omitempty"`
+
+	// Host describes the hostname or IP address of the infrastructure host
+	// that the VSphereVM is residing on.
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// Addresses is a list of the VM's IP addresses.
+	// This field is required at runtime for other controllers that read
+	// this CRD as unstructured data.
+	// +optional
+	Addresses []string `json:"addresses,omitempty"`
+
+	// CloneMode is the type of clone operation used to clone this VM. Since
+	// LinkedMode is the default but fails gracefully if the source of the
+	// clone has no snapshots, this field may be used to determine the actual
+	// type of clone operation used to create this VM.
+	// +optional
+	CloneMode CloneMode `json:"cloneMode,omitempty"`
+
+	// Snapshot is the name of the snapshot from which the VM was cloned if
+	// LinkedMode is enabled.
+	// +optional
+	Snapshot string `json:"snapshot,omitempty"`
+
+	// RetryAfter tracks the time we can retry queueing a task
+	// +optional
+	RetryAfter metav1.Time `json:"retryAfter,omitempty"`
+
+	// TaskRef is a managed object reference to a Task related to the machine.
+	// This value is set automatically at runtime and should not be set or
+	// modified by users.
+	// +optional
+	TaskRef string `json:"taskRef,omitempty"`
+
+	// Network returns the network status for each of the machine's configured
+	// network interfaces.
+	// +optional
+	Network []NetworkStatus `json:"network,omitempty"`
+
+	// ModuleUUID is the unique identifier for the vCenter cluster module construct
+	// which is used to configure anti-affinity. Objects with the same ModuleUUID
+	// will be anti-affined, meaning that the vCenter DRS will best effort schedule
+	// the VMs on separate hosts.
+	// +optional
+	ModuleUUID *string `json:"moduleUUID,omitempty"`
+
+	// VMRef is the VM's Managed Object Reference on vSphere. It can be used by consumers
+	// to programatically get this VM representation on vSphere in case of the need to retrieve informations.
+	// This field is set once the machine is created and should not be changed
+	// +optional
+	VMRef string `json:"vmRef,omitempty"`
+
+	// InstanceUUID is the instance UUID of the VM, which is unique within a vCenter.
+	// With the InstanceUUIDLookup feature gate, the VM is looked up by its instance UUID
+	// once it is set, so it is found even if it has been renamed or another VM has the
+	// same BIOS UUID.
+	// +optional
+	InstanceUUID string `json:"instanceUUID,omitempty"`
+
+	// Relocation describes the progress of the relocation requested via
+	// spec.relocateTo.
+	// +optional
+	Relocation *VSphereVMRelocationStatus `json:"relocation,omitempty"`
+
+	// Deprecated groups all the status fields that are deprecated and will be removed when all the nested field are removed.
+	// +optional
+	Deprecated *VSphereVMDeprecatedStatus `json:"deprecated,omitempty"`
+}
+
+// VSphereVMInitializationStatus provides observations of the VSphereVM initialization process.
+type VSphereVMInitializationStatus struct {
+	// Provisioned is true when the VM is fully provisioned.
+	// This field is required at runtime for other controllers that read
+	// this CRD as unstructured data.
+	// +optional
+	Provisioned *bool `json:"provisioned,omitempty"`
+}
+
+// VSphereVMDeprecatedStatus groups all the status fields that are deprecated and will be removed in a future version.
+type VSphereVMDeprecatedStatus struct {
+	// V1Beta1 groups all the status fields that are deprecated and will be removed when support for v1beta1 will be dropped.
+	// +optional
+	V1Beta1 *VSphereVMV1Beta1DeprecatedStatus `json:"v1beta1,omitempty"`
+}
+
+// VSphereVMV1Beta1DeprecatedStatus groups all the status fields that are deprecated and will be removed when support for v1beta1 will be dropped.
+type VSphereVMV1Beta1DeprecatedStatus struct {
+	// Conditions defines current service state of the VSphereVM.
+	//
+	// Deprecated: This field is deprecated and is going to be removed when support for v1beta1 will be dropped.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+
+	// FailureReason will be set in the event that there is a terminal problem
+	// reconciling the vspherevm and will contain a succinct value suitable
+	// for vm interpretation.
+	//
+	// This field should not be set for transitive errors that a controller
+	// faces that are expected to be fixed automatically over
+	// time (like service outages), but instead indicate that something is
+	// fundamentally wrong with the vm.
+	//
+	// Any transient errors that occur during the reconciliation of vspherevms
+	// can be added as events to the vspherevm object and/or logged in the
+	// controller's output.
+	//
+	// Deprecated: This field is deprecated and is going to be removed when support for v1beta1 will be dropped.
+	// +optional
+	FailureReason *errors.MachineStatusError `json:"failureReason,omitempty"`
+
+	// FailureMessage will be set in the event that there is a terminal problem
+	// reconciling the vspherevm and will contain a more verbose string suitable
+	// for logging and human consumption.
+	//
+	// This field should not be set for transitive errors that a controller
+	// faces that are expected to be fixed automatically over
+	// time (like service outages), but instead indicate that something is
+	// fundamentally wrong with the vm.
+	//
+	// Any transient errors that occur during the reconciliation of vspherevms
+	// can be added as events to the vspherevm object and/or logged in the
+	// controller's output.
+	//
+	// Deprecated: This field is deprecated and is going to be removed when support for v1beta1 will be dropped.
+	// +optional
+	FailureMessage *string `json:"failureMessage,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=vspherevms,scope=Namespaced,categories=cluster-api
+// +kubebuilder:subresource:status
+
+// VSphereVM is the Schema for the vspherevms API.
+type VSphereVM struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VSphereVMSpec   `json:"spec,omitempty"`
+	Status VSphereVMStatus `json:"status,omitempty"`
+}
+
+// GetV1Beta1Conditions returns the set of conditions for this object.
+func (r *VSphereVM) GetV1Beta1Conditions() clusterv1.Conditions {
+	if r.Status.Deprecated == nil || r.Status.Deprecated.V1Beta1 == nil {
+		return nil
+	}
+	return r.Status.Deprecated.V1Beta1.Conditions
+}
+
+// SetV1Beta1Conditions sets the conditions on this object.
+func (r *VSphereVM) SetV1Beta1Conditions(conditions clusterv1.Conditions) {
+	if r.Status.Deprecated == nil {
+		r.Status.Deprecated = &VSphereVMDeprecatedStatus{}
+	}
+	if r.Status.Deprecated.V1Beta1 == nil {
+		r.Status.Deprecated.V1Beta1 = &VSphereVMV1Beta1DeprecatedStatus{}
+	}
+	r.Status.Deprecated.V1Beta1.Conditions = conditions
+}
+
+// GetConditions returns the set of conditions for this object.
+func (r *VSphereVM) GetConditions() []metav1.Condition {
+	return r.Status.Conditions
+}
+
+// SetConditions sets conditions for an API object.
+func (r *VSphereVM) SetConditions(conditions []metav1.Condition) {
+	r.Status.Conditions = conditions
+}
+
+// +kubebuilder:object:root=true
+
+// VSphereVMList contains a list of VSphereVM.
+type VSphereVMList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VSphereVM `json:"items"`
+}
+
+func init() {
+	objectTypes = append(objectTypes, &VSphereVM{}, &VSphereVMList{})
+}
@@ -52,6 +52,20 @@ const (
 	// WaitingForLoadBalancerIPReason is used when waiting for load
 	// balancer IP to exist.
 	WaitingForLoadBalancerIPReason = "WaitingForLoadBalancerIP"
+
+	// ControlPlaneEndpointDNSReadyCondition reports the successful registration and propagation of
+	// the DNS record for the control plane endpoint.
+	ControlPlaneEndpointDNSReadyCondition clusterv1.ConditionType = "ControlPlaneEndpointDNSReady"
+
+	// DNSProviderNotConfiguredReason (Severity=Warning) documents a DNS record for the control plane endpoint
+	// requested while no DNS provider is configured on the controller manager.
+	DNSProviderNotConfiguredReason = "DNSProviderNotConfigured"
+	// DNSRecordRegistrationFailedReason (Severity=Warning) documents a failure registering the DNS record
+	// for the control plane endpoint.
+	DNSRecordRegistrationFailedReason = "DNSRecordRegistrationFailed"
+	// WaitingForDNSPropagationReason (Severity=Info) documents a DNS record for the control plane endpoint
+	// which has been registered but is not resolvable yet.
+	WaitingForDNSPropagationReason = "WaitingForDNSPropagation"
 )
 
 // Conditions and condition Reasons for VSphereMachine.
@@ -70,6 +84,56 @@ const (
 	WaitingForBIOSUUIDReason = "WaitingForBIOSUUID"
 )
 
+const (
+	// VolumesAttachedCondition documents whether the PVCs in VSphereMachineSpec.Volumes are attached
+	// to the VirtualMachine. It is only set if the VSphereMachine has volumes.
+	VolumesAttachedCondition clusterv1.ConditionType = "VolumesAttached"
+
+	// WaitingForVolumeAttachmentReason (Severity=Info) documents a VSphereMachine waiting for its PVCs
+	// to be attached to the VirtualMachine.
+	WaitingForVolumeAttachmentReason = "WaitingForVolumeAttachment"
+
+	// VolumeAttachmentFailedReason (Severity=Warning) documents a VSphereMachine with PVCs
+	// which failed to be attached to the VirtualMachine.
+	VolumeAttachmentFailedReason = "VolumeAttachmentFailed"
+)
+
+// V1Beta2 conditions for VSphereMachine mirrored from the vm-operator VirtualMachine.
+// The condition reason and message are copied from the vm-operator VirtualMachine condition.
+const (
+	// VSphereMachineVirtualMachineClassReadyV1Beta2Condition mirrors the VirtualMachineClassReady condition
+	// of the vm-operator VirtualMachine.
+	VSphereMachineVirtualMachineClassReadyV1Beta2Condition = "VirtualMachineClassReady"
+
+	// VSphereMachineVirtualMachineImageReadyV1Beta2Condition mirrors the VirtualMachineImageReady condition
+	// of the vm-operator VirtualMachine.
+	VSphereMachineVirtualMachineImageReadyV1Beta2Condition = "VirtualMachineImageReady"
+
+	// VSphereMachineVirtualMachineSetResourcePolicyReadyV1Beta2Condition mirrors the VirtualMachineSetResourcePolicyReady
+	// condition of the vm-operator VirtualMachine.
+	VSphereMachineVirtualMachineSetResourcePolicyReadyV1Beta2Condition = "VirtualMachineSetResourcePolicyReady"
+
+	// VSphereMachineVirtualMachineBootstrapReadyV1Beta2Condition mirrors the VirtualMachineBootstrapReady condition
+	// of the vm-operator VirtualMachine.
+	VSphereMachineVirtualMachineBootstrapReadyV1Beta2Condition = "VirtualMachineBootstrapReady"
+
+	// VSphereMachineVirtualMachineStorageReadyV1Beta2Condition mirrors the VirtualMachineStorageReady condition
+	// of the vm-operator VirtualMachine.
+	VSphereMachineVirtualMachineStorageReadyV1Beta2Condition = "VirtualMachineStorageReady"
+
+	// VSphereMachineVirtualMachineNetworkReadyV1Beta2Condition mirrors the VirtualMachineNetworkReady condition
+	// of the vm-operator VirtualMachine.
+	VSphereMachineVirtualMachineNetworkReadyV1Beta2Condition = "VirtualMachineNetworkReady"
+
+	// VSphereMachineVirtualMachinePlacementReadyV1Beta2Condition mirrors the VirtualMachinePlacementReady condition
+	// of the vm-operator VirtualMachine.
+	VSphereMachineVirtualMachinePlacementReadyV1Beta2Condition = "VirtualMachinePlacementReady"
+
+	// VSphereMachineVirtualMachineCreatedV1Beta2Condition mirrors the VirtualMachineCreated condition
+	// of the vm-operator VirtualMachine.
+	VSphereMachineVirtualMachineCreatedV1Beta2Condition = "VirtualMachineCreated"
+)
+
 const (
 	// ProviderServiceAccountsReadyCondition documents the status of provider service accounts
 	// and related Roles, RoleBindings and Secrets are created.
@@ -77,6 +141,10 @@ const (
 
 	// ProviderServiceAccountsReconciliationFailedReason reports that provider service accounts related resources reconciliation failed.
 	ProviderServiceAccountsReconciliationFailedReason = "ProviderServiceAccountsReconciliationFailed"
+
+	// WaitingForServiceAccountTokenReason (Severity=Info) documents that provider service accounts are waiting
+	// for the token controller to populate their ServiceAccount token secrets, e.g. after a token has been rotated.
+	WaitingForServiceAccountTokenReason = "WaitingForServiceAccountToken"
 )
 
 const (
@@ -22,6 +22,13 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// ProviderServiceAccountTargetSecretLabel is the label set on the secrets which are synced to the workload cluster
+	// for ProviderServiceAccounts. It is used to garbage collect the secrets of ProviderServiceAccounts which have been
+	// deleted or which changed their target.
+	ProviderServiceAccountTargetSecretLabel = "vmware.infrastructure.cluster.x-k8s.io/provider-serviceaccount"
+)
+
 // ProviderServiceAccountSpec defines the desired state of ProviderServiceAccount.
 type ProviderServiceAccountSpec struct {
 	// Ref specifies the reference to the VSphereCluster for which the ProviderServiceAccount needs to be realized.
@@ -38,6 +38,27 @@ const (
 type VSphereClusterSpec struct {
 	// +optional
 	ControlPlaneEndpoint clusterv1.APIEndpoint `json:"controlPlaneEndpoint"`
+
+	// ControlPlaneEndpointDNS configures the registration of a DNS record for the control plane endpoint.
+	// When set, the control plane endpoint is published using the DNS provider configured on the
+	// controller manager, and the control plane endpoint of the VSphereCluster is set to the DNS
+	// hostname only after the DNS record has propagated.
+	// +optional
+	ControlPlaneEndpointDNS *ControlPlaneEndpointDNS `json:"controlPlaneEndpointDNS,omitempty"`
+}
+
+// ControlPlaneEndpointDNS defines the DNS record to be registered for the control plane endpoint.
+type ControlPlaneEndpointDNS struct {
+	// Hostname is the fully qualified DNS name to be registered for the control plane endpoint.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	Hostname string `json:"hostname"`
+
+	// TTL is the time to live in seconds of the DNS record.
+	// If not set, the default TTL of the DNS provider is used.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TTL *int64 `json:"ttl,omitempty"`
 }
 
 // VSphereClusterStatus defines the observed state of VSphereClusterSpec.
@@ -32,6 +32,30 @@ type VSphereMachineVolume struct {
 	// StorageClass defaults to VSphereMachineSpec.StorageClass
 	// +optional
 	StorageClass string `json:"storageClass,omitempty"`
+	// AccessModes are the access modes of the PVC.
+	// Defaults to ReadWriteOnce.
+	// +optional
+	AccessModes []corev1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+	// BootDiskAntiAffinity requires the PVC to be placed on a different datastore than the
+	// boot disk of the VSphereMachine. The datastore of a PVC is chosen based on the storage
+	// policy of its storage class, so StorageClass must be set to a storage class different
+	// from VSphereMachineSpec.StorageClass whose storage policy selects other datastores.
+	// +optional
+	BootDiskAntiAffinity bool `json:"bootDiskAntiAffinity,omitempty"`
+}
+
+// VSphereMachineVolumeStatus is the attach status of a PVC of a VSphereMachine.
+type VSphereMachineVolumeStatus struct {
+	// Name is the name of the volume in VSphereMachineSpec.Volumes.
+	Name string `json:"name"`
+	// ClaimName is the name of the PVC.
+	ClaimName string `json:"claimName"`
+	// Attached is true if the PVC is attached to the virtual machine.
+	// +optional
+	Attached bool `json:"attached,omitempty"`
+	// Error is the last error attaching or detaching the PVC.
+	// +optional
+	Error string `json:"error,omitempty"`
 }
 
 // VSphereMachineSpec defines the desired state of VSphereMachine.
@@ -44,6 +68,7 @@ type VSphereMachineSpec struct {
 
 	// FailureDomain is the failure domain the machine will be created in.
 	// Must match a key in the FailureDomains map stored on the cluster object.
+	// The failure domain of the Machine takes precedence, if set.
 	// +optional
 	FailureDomain *string `json:"failureDomain,omitempty"`
 
@@ -91,6 +116,102 @@ type VSphereMachineSpec struct {
 	// NamingStrategy allows configuring the naming strategy used when calculating the name of the VirtualMachine.
 	// +optional
 	NamingStrategy *VirtualMachineNamingStrategy `json:"namingStrategy,omitempty"`
+
+	// ResourcePolicyName is the name of an existing VirtualMachineSetResourcePolicy
+	// in the namespace of the VSphereMachine, to be used instead of the resource
+	// policy of the VSphereCluster. This allows e.g. different MachineDeployments
+	// to place their VirtualMachines in different resource pools.
+	// The resource policy should define the same cluster module groups as the
+	// resource policy of the VSphereCluster for anti-affinity to be enforced.
+	// +optional
+	ResourcePolicyName string `json:"resourcePolicyName,omitempty"`
+
+	// ReadinessProbe configures the readiness probe of the VirtualMachine of a control plane
+	// machine, which determines when the VirtualMachine is added to the endpoints of the
+	// control plane VirtualMachineService.
+	// The readiness probe is only set once the control plane is ready and if the network
+	// provider supports readiness probes. It defaults to a TCP probe on the API server bind port.
+	// +optional
+	ReadinessProbe *VirtualMachineReadinessProbe `json:"readinessProbe,omitempty"`
+
+	// VirtualMachineMetadata is the metadata propagated to the VirtualMachine of the
+	// machine and kept in sync, e.g. for backup or monitoring agents which select
+	// VirtualMachines by their labels or annotations.
+	// Labels and annotations managed by CAPV take precedence.
+	// +optional
+	VirtualMachineMetadata *VirtualMachineMetadata `json:"virtualMachineMetadata,omitempty"`
+}
+
+// VirtualMachineMetadata is the metadata of a VirtualMachine.
+type VirtualMachineMetadata struct {
+	// Labels is a map of labels set on the VirtualMachine.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations is a map of annotations set on the VirtualMachine.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// VirtualMachineReadinessProbeType is the type of the readiness probe of a VirtualMachine.
+// +kubebuilder:validation:Enum=TCP;GuestHeartbeat
+type VirtualMachineReadinessProbeType string
+
+const (
+	// VirtualMachineReadinessProbeTypeTCP probes the readiness of a VirtualMachine by opening
+	// a TCP connection to a port of the VirtualMachine.
+	VirtualMachineReadinessProbeTypeTCP VirtualMachineReadinessProbeType = "TCP"
+
+	// VirtualMachineReadinessProbeTypeGuestHeartbeat probes the readiness of a VirtualMachine
+	// using the heartbeat of the guest reported by VMware Tools.
+	VirtualMachineReadinessProbeTypeGuestHeartbeat VirtualMachineReadinessProbeType = "GuestHeartbeat"
+)
+
+// VirtualMachineGuestHeartbeatStatus is the heartbeat status of the guest of a VirtualMachine.
+// +kubebuilder:validation:Enum=yellow;green
+type VirtualMachineGuestHeartbeatStatus string
+
+const (
+	// VirtualMachineGuestHeartbeatStatusYellow means an intermittent heartbeat, e.g. due to guest load.
+	VirtualMachineGuestHeartbeatStatusYellow VirtualMachineGuestHeartbeatStatus = "yellow"
+
+	// VirtualMachineGuestHeartbeatStatusGreen means the guest operating system is responding normally.
+	VirtualMachineGuestHeartbeatStatusGreen VirtualMachineGuestHeartbeatStatus = "green"
+)
+
+// VirtualMachineReadinessProbe defines the readiness probe of a VirtualMachine.
+type VirtualMachineReadinessProbe struct {
+	// Type is the type of the readiness probe.
+	// Defaults to TCP.
+	// +optional
+	// +kubebuilder:default=TCP
+	Type VirtualMachineReadinessProbeType `json:"type,omitempty"`
+
+	// Port is the port probed by a TCP readiness probe.
+	// Defaults to the API server bind port 6443.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port *int32 `json:"port,omitempty"`
+
+	// GuestHeartbeatThreshold is the heartbeat status the guest must be at or above for a
+	// GuestHeartbeat readiness probe to succeed.
+	// Defaults to green.
+	// +optional
+	GuestHeartbeatThreshold VirtualMachineGuestHeartbeatStatus `json:"guestHeartbeatThreshold,omitempty"`
+
+	// PeriodSeconds is how often, in seconds, the readiness probe is performed.
+	// Defaults to 10 seconds.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	PeriodSeconds int32 `json:"periodSeconds,omitempty"`
+
+	// TimeoutSeconds is the number of seconds after which the readiness probe times out.
+	// Defaults to 10 seconds.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=60
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
 }
 
 // VirtualMachineNamingStrategy defines the naming strategy for the VirtualMachines.
@@ -173,9 +294,28 @@ type VSphereMachineStatus struct {
 	// +optional
 	VMStatus VirtualMachineState `json:"vmstatus,omitempty"`
 
+	// Volumes is the attach status of the PVCs in VSphereMachineSpec.Volumes.
+	// +optional
+	Volumes []VSphereMachineVolumeStatus `json:"volumes,omitempty"`
+
 	// Conditions defines current service state of the VSphereMachine.
 	// +optional
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+
+	// V1Beta2 groups all the fields that will be added or modified in VSphereMachine's status with the V1Beta2 version.
+	// +optional
+	V1Beta2 *VSphereMachineV1Beta2Status `json:"v1beta2,omitempty"`
+}
+
+// VSphereMachineV1Beta2Status groups all the fields that will be added or modified in VSphereMachineStatus with the V1Beta2 version.
+// See https://github.com/kubernetes-sigs/cluster-api/blob/main/docs/proposals/20240916-improve-status-in-CAPI-resources.md for more context.
+type VSphereMachineV1Beta2Status struct {
+	// Conditions represents the observations of a VSphereMachine's current state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +kubebuilder:validation:MaxItems=32
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // VSphereMachine is the Schema for the vspheremachines API
@@ -214,6 +354,22 @@ func (r *VSphereMachine) SetConditions(conditions clusterv1.Conditions) {
 	r.Status.Conditions = conditions
 }
 
+// GetV1Beta2Conditions returns the v1beta2 conditions for the VSphereMachine.
+func (r *VSphereMachine) GetV1Beta2Conditions() []metav1.Condition {
+	if r.Status.V1Beta2 == nil {
+		return nil
+	}
+	return r.Status.V1Beta2.Conditions
+}
+
+// SetV1Beta2Conditions sets v1beta2 conditions on the VSphereMachine.
+func (r *VSphereMachine) SetV1Beta2Conditions(conditions []metav1.Condition) {
+	if r.Status.V1Beta2 == nil {
+		r.Status.V1Beta2 = &VSphereMachineV1Beta2Status{}
+	}
+	r.Status.V1Beta2.Conditions = conditions
+}
+
 func init() {
 	objectTypes = append(objectTypes, &VSphereMachine{}, &VSphereMachineList{})
 }
@@ -23,11 +23,32 @@ package v1beta1
 import (
 	"k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	apiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/errors"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneEndpointDNS) DeepCopyInto(out *ControlPlaneEndpointDNS) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneEndpointDNS.
+func (in *ControlPlaneEndpointDNS) DeepCopy() *ControlPlaneEndpointDNS {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneEndpointDNS)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProviderServiceAccount) DeepCopyInto(out *ProviderServiceAccount) {
 	*out = *in
@@ -133,7 +154,7 @@ func (in *VSphereCluster) DeepCopyInto(out *VSphereCluster) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -191,6 +212,11 @@ func (in *VSphereClusterList) DeepCopyObject() runtime.Object {
 func (in *VSphereClusterSpec) DeepCopyInto(out *VSphereClusterSpec) {
 	*out = *in
 	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
+	if in.ControlPlaneEndpointDNS != nil {
+		in, out := &in.ControlPlaneEndpointDNS, &out.ControlPlaneEndpointDNS
+		*out = new(ControlPlaneEndpointDNS)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereClusterSpec.
@@ -237,7 +263,7 @@ func (in *VSphereClusterTemplate) DeepCopyInto(out *VSphereClusterTemplate) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereClusterTemplate.
@@ -293,7 +319,7 @@ func (in *VSphereClusterTemplateList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VSphereClusterTemplateResource) DeepCopyInto(out *VSphereClusterTemplateResource) {
 	*out = *in
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereClusterTemplateResource.
@@ -309,7 +335,7 @@ func (in *VSphereClusterTemplateResource) DeepCopy() *VSphereClusterTemplateReso
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VSphereClusterTemplateSpec) DeepCopyInto(out *VSphereClusterTemplateSpec) {
 	*out = *in
-	out.Template = in.Template
+	in.Template.DeepCopyInto(&out.Template)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereClusterTemplateSpec.
@@ -406,6 +432,16 @@ func (in *VSphereMachineSpec) DeepCopyInto(out *VSphereMachineSpec) {
 		*out = new(VirtualMachineNamingStrategy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(VirtualMachineReadinessProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VirtualMachineMetadata != nil {
+		in, out := &in.VirtualMachineMetadata, &out.VirtualMachineMetadata
+		*out = new(VirtualMachineMetadata)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachineSpec.
@@ -441,6 +477,11 @@ func (in *VSphereMachineStatus) DeepCopyInto(out *VSphereMachineStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]VSphereMachineVolumeStatus, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(apiv1beta1.Conditions, len(*in))
@@ -448,6 +489,11 @@ func (in *VSphereMachineStatus) DeepCopyInto(out *VSphereMachineStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.V1Beta2 != nil {
+		in, out := &in.V1Beta2, &out.V1Beta2
+		*out = new(VSphereMachineV1Beta2Status)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachineStatus.
@@ -573,6 +619,28 @@ func (in *VSphereMachineTemplateStatus) DeepCopy() *VSphereMachineTemplateStatus
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereMachineV1Beta2Status) DeepCopyInto(out *VSphereMachineV1Beta2Status) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachineV1Beta2Status.
+func (in *VSphereMachineV1Beta2Status) DeepCopy() *VSphereMachineV1Beta2Status {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereMachineV1Beta2Status)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VSphereMachineVolume) DeepCopyInto(out *VSphereMachineVolume) {
 	*out = *in
@@ -583,6 +651,11 @@ func (in *VSphereMachineVolume) DeepCopyInto(out *VSphereMachineVolume) {
 			(*out)[key] = val.DeepCopy()
 		}
 	}
+	if in.AccessModes != nil {
+		in, out := &in.AccessModes, &out.AccessModes
+		*out = make([]v1.PersistentVolumeAccessMode, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachineVolume.
@@ -595,6 +668,50 @@ func (in *VSphereMachineVolume) DeepCopy() *VSphereMachineVolume {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereMachineVolumeStatus) DeepCopyInto(out *VSphereMachineVolumeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereMachineVolumeStatus.
+func (in *VSphereMachineVolumeStatus) DeepCopy() *VSphereMachineVolumeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereMachineVolumeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineMetadata) DeepCopyInto(out *VirtualMachineMetadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineMetadata.
+func (in *VirtualMachineMetadata) DeepCopy() *VirtualMachineMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineNamingStrategy) DeepCopyInto(out *VirtualMachineNamingStrategy) {
 	*out = *in
@@ -614,3 +731,23 @@ func (in *VirtualMachineNamingStrategy) DeepCopy() *VirtualMachineNamingStrategy
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineReadinessProbe) DeepCopyInto(out *VirtualMachineReadinessProbe) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineReadinessProbe.
+func (in *VirtualMachineReadinessProbe) DeepCopy() *VirtualMachineReadinessProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineReadinessProbe)
+	in.DeepCopyInto(out)
+	return out
+}
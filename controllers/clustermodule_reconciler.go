@@ -19,10 +19,15 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/vim25/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
@@ -41,18 +46,24 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/clustermodule"
 	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+	infrautilv1 "sigs.k8s.io/cluster-api-provider-vsphere/pkg/util"
 )
 
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinedeployments,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines,verbs=get;list;watch
 // +kubebuilder:rbac:groups=controlplane.cluster.x-k8s.io,resources=kubeadmcontrolplanes,verbs=get;list;watch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=vsphereclusters,verbs=get;list;watch;patch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=vspheremachinetemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=vspherevms,verbs=get;list;watch
 
 // Reconciler reconciles changes for ClusterModules.
 type Reconciler struct {
 	Client client.Client
 
 	ClusterModuleService clustermodule.Service
+
+	// DryRun is true if the VMs of the cluster are not added to their cluster modules.
+	DryRun bool
 }
 
 // NewReconciler creates a Cluster Module Reconciler with a Client and ClusterModuleService.
@@ -60,6 +71,7 @@ func NewReconciler(controllerManagerCtx *capvcontext.ControllerManagerContext) R
 	return Reconciler{
 		Client:               controllerManagerCtx.Client,
 		ClusterModuleService: clustermodule.NewService(controllerManagerCtx, controllerManagerCtx.Client),
+		DryRun:               controllerManagerCtx.DryRun,
 	}
 }
 
@@ -69,7 +81,7 @@ func (r Reconciler) Reconcile(ctx context.Context, clusterCtx *capvcontext.Clust
 	if !clustermodule.IsClusterCompatible(clusterCtx) {
 		conditions.MarkFalse(clusterCtx.VSphereCluster, infrav1.ClusterModulesAvailableCondition, infrav1.VCenterVersionIncompatibleReason, clusterv1.ConditionSeverityInfo,
 			"vCenter version %s does not support cluster modules", clusterCtx.VSphereCluster.Status.VCenterVersion)
-		log.V(5).Info(fmt.Sprintf("vCenter version %s does not support cluster modules to implement anti affinity (vCenter >= 7 required)", clusterCtx.VSphereCluster.Status.VCenterVersion))
+		log.V(5).Info("vCenter version does not support cluster modules to implement anti affinity (vCenter >= 7 required)", "vCenterVersion", clusterCtx.VSphereCluster.Status.VCenterVersion)
 		return reconcile.Result{}, nil
 	}
 
@@ -79,82 +91,148 @@ func (r Reconciler) Reconcile(ctx context.Context, clusterCtx *capvcontext.Clust
 	}
 
 	modErrs := []clusterModError{}
+	memberErrs := []clusterModError{}
+	isDeleting := !clusterCtx.VSphereCluster.DeletionTimestamp.IsZero()
 
-	clusterModuleSpecs := []infrav1.ClusterModule{}
+	// Existing cluster modules are looked up by their name, so objects for which the same name
+	// is generated share a cluster module and recreated objects reuse unused cluster modules.
+	existingModules := map[moduleKey]infrav1.ClusterModule{}
+	existingModulesByObject := map[string][]infrav1.ClusterModule{}
 	for _, mod := range clusterCtx.VSphereCluster.Spec.ClusterModules {
-		// Note: We have to use := here to not overwrite log & ctx outside the for loop.
-		log := log
-		// It is safe to infer KubeadmControlPlane or MachineDeployment from .ControlPlane as modules
-		// are only implemented for these types.
-		if mod.ControlPlane {
-			log = log.WithValues("KubeadmControlPlane", klog.KRef(clusterCtx.VSphereCluster.Namespace, mod.TargetObjectName), "moduleUUID", mod.ModuleUUID)
-		} else {
-			log = log.WithValues("MachineDeployment", klog.KRef(clusterCtx.VSphereCluster.Namespace, mod.TargetObjectName), "moduleUUID", mod.ModuleUUID)
+		key := moduleKey{controlPlane: mod.ControlPlane, name: clustermodule.GetName(mod)}
+		if _, ok := existingModules[key]; !ok || mod.UnusedSince == nil {
+			existingModules[key] = mod
 		}
-		ctx := ctrl.LoggerInto(ctx, log)
-
-		curr := mod.TargetObjectName
-		if mod.ControlPlane {
-			curr = appendKCPKey(curr)
-		}
-		if obj, ok := objectMap[curr]; !ok {
-			// Delete the cluster module as the object is marked for deletion or already deleted.
-			if err := r.ClusterModuleService.Remove(ctx, clusterCtx, mod.ModuleUUID); err != nil {
-				log.Error(err, "Failed to delete cluster module for object")
-			}
-			delete(objectMap, curr)
-		} else {
-			// Verify the cluster module
-			exists, err := r.ClusterModuleService.DoesExist(ctx, clusterCtx, obj, mod.ModuleUUID)
-			if err != nil {
-				modErrs = append(modErrs, clusterModError{obj.GetName(), errors.Wrapf(err, "failed to check if cluster module %q exists", mod.ModuleUUID)})
-				log.Error(err, "Failed to check if cluster module for object exists")
-				// Append the module and remove it from objectMap to not create new ones instead.
-				clusterModuleSpecs = append(clusterModuleSpecs, infrav1.ClusterModule{
-					ControlPlane:     obj.IsControlPlane(),
-					TargetObjectName: obj.GetName(),
-					ModuleUUID:       mod.ModuleUUID,
-				})
-				delete(objectMap, curr)
-				continue
-			}
-
-			// Append the module and object info to the VSphereCluster object
-			// and remove it from the object map since no new cluster module
-			// needs to be created.
-			if exists {
-				clusterModuleSpecs = append(clusterModuleSpecs, infrav1.ClusterModule{
-					ControlPlane:     obj.IsControlPlane(),
-					TargetObjectName: obj.GetName(),
-					ModuleUUID:       mod.ModuleUUID,
-				})
-				delete(objectMap, curr)
-			} else {
-				log.V(4).Info("Module for object not found (will be created)")
+		if mod.UnusedSince == nil {
+			objKey := mod.TargetObjectName
+			if mod.ControlPlane {
+				objKey = appendKCPKey(objKey)
 			}
+			existingModulesByObject[objKey] = append(existingModulesByObject[objKey], mod)
 		}
 	}
 
-	for _, obj := range objectMap {
+	objKeys := make([]string, 0, len(objectMap))
+	for objKey := range objectMap {
+		objKeys = append(objKeys, objKey)
+	}
+	sort.Strings(objKeys)
+
+	clusterModuleSpecs := []infrav1.ClusterModule{}
+	resolvedModules := map[moduleKey]string{}
+	missingModules := sets.Set[string]{}
+	for _, objKey := range objKeys {
+		obj := objectMap[objKey]
 		// Note: We have to use := here to create a new variable and not overwrite log & ctx outside the for loop.
 		log := log.WithValues(obj.GetObjectKind().GroupVersionKind().Kind, klog.KObj(obj))
 		ctx := ctrl.LoggerInto(ctx, log)
 
-		moduleUUID, err := r.ClusterModuleService.Create(ctx, clusterCtx, obj)
+		name, err := clustermodule.GenerateName(clusterCtx.VSphereCluster, obj)
 		if err != nil {
-			modErrs = append(modErrs, clusterModError{obj.GetName(), errors.Wrapf(err, "failed to create cluster module")})
-			log.Error(err, "Failed to create cluster module for object")
+			modErrs = append(modErrs, clusterModError{obj.GetName(), err})
+			log.Error(err, "Failed to generate cluster module name for object")
+			// Keep the cluster modules of the object to not garbage collect them.
+			clusterModuleSpecs = append(clusterModuleSpecs, existingModulesByObject[objKey]...)
 			continue
 		}
-		// module creation was skipped
-		if moduleUUID == "" {
-			continue
+		key := moduleKey{controlPlane: obj.IsControlPlane(), name: name}
+		log = log.WithValues("moduleName", name)
+		ctx = ctrl.LoggerInto(ctx, log)
+
+		moduleUUID, ok := resolvedModules[key]
+		if !ok {
+			existing, hasExisting := existingModules[key]
+			if hasExisting {
+				// Verify the cluster module
+				exists, err := r.ClusterModuleService.DoesExist(ctx, clusterCtx, obj, existing.ModuleUUID)
+				if err != nil {
+					modErrs = append(modErrs, clusterModError{obj.GetName(), errors.Wrapf(err, "failed to check if cluster module %q exists", existing.ModuleUUID)})
+					log.Error(err, "Failed to check if cluster module for object exists", "moduleUUID", existing.ModuleUUID)
+				}
+				if err != nil || exists {
+					// Keep using the module to not create a new one instead.
+					moduleUUID = existing.ModuleUUID
+					if existing.UnusedSince != nil {
+						log.Info("Reusing unused cluster module for object", "moduleUUID", moduleUUID)
+					}
+				} else {
+					log.V(4).Info("Module for object not found (will be created)", "moduleUUID", existing.ModuleUUID)
+					missingModules.Insert(existing.ModuleUUID)
+				}
+			}
+
+			if moduleUUID == "" {
+				moduleUUID, err = r.ClusterModuleService.Create(ctx, clusterCtx, obj)
+				if err != nil {
+					modErrs = append(modErrs, clusterModError{obj.GetName(), errors.Wrapf(err, "failed to create cluster module")})
+					log.Error(err, "Failed to create cluster module for object")
+					continue
+				}
+				// module creation was skipped
+				if moduleUUID == "" {
+					continue
+				}
+			}
+			resolvedModules[key] = moduleUUID
 		}
+
 		clusterModuleSpecs = append(clusterModuleSpecs, infrav1.ClusterModule{
 			ControlPlane:     obj.IsControlPlane(),
 			TargetObjectName: obj.GetName(),
 			ModuleUUID:       moduleUUID,
+			Name:             name,
 		})
+
+		// Add the VMs of the object which are not members of the cluster module yet, e.g. because they were
+		// created while the cluster was paused or the cluster module was recreated.
+		if !isDeleting {
+			if err := r.addMissingMembers(ctx, clusterCtx, obj, moduleUUID); err != nil {
+				memberErrs = append(memberErrs, clusterModError{obj.GetName(), err})
+				log.Error(err, "Failed to add VMs of object to cluster module", "moduleUUID", moduleUUID)
+			}
+		}
+	}
+	usedModules := len(clusterModuleSpecs)
+
+	// Garbage collect the cluster modules which are no longer used by any object.
+	// Unused cluster modules are kept for the deletion grace period so recreated objects
+	// can reuse them, unless the cluster is being deleted.
+	inUse := sets.Set[string]{}
+	for _, mod := range clusterModuleSpecs {
+		inUse.Insert(mod.ModuleUUID)
+	}
+	gracePeriod := clustermodule.DeletionGracePeriod(clusterCtx.VSphereCluster)
+	now := time.Now()
+	var requeueAfter time.Duration
+	for _, mod := range clusterCtx.VSphereCluster.Spec.ClusterModules {
+		if inUse.Has(mod.ModuleUUID) || missingModules.Has(mod.ModuleUUID) {
+			continue
+		}
+		inUse.Insert(mod.ModuleUUID)
+
+		// Note: We have to use := here to not overwrite log & ctx outside the for loop.
+		log := log.WithValues("moduleName", clustermodule.GetName(mod), "moduleUUID", mod.ModuleUUID)
+		ctx := ctrl.LoggerInto(ctx, log)
+
+		unusedSince := now
+		if mod.UnusedSince != nil {
+			unusedSince = mod.UnusedSince.Time
+		}
+		if remaining := gracePeriod - now.Sub(unusedSince); !isDeleting && remaining > 0 {
+			log.V(4).Info("Keeping unused cluster module until the deletion grace period has passed", "remaining", remaining)
+			mod.Name = clustermodule.GetName(mod)
+			mod.UnusedSince = &metav1.Time{Time: unusedSince}
+			clusterModuleSpecs = append(clusterModuleSpecs, mod)
+			if requeueAfter == 0 || remaining < requeueAfter {
+				requeueAfter = remaining
+			}
+			continue
+		}
+
+		// Delete the cluster module as it is no longer used by any object.
+		if err := r.ClusterModuleService.Remove(ctx, clusterCtx, mod.ModuleUUID); err != nil {
+			log.Error(err, "Failed to delete cluster module")
+		}
 	}
 	clusterCtx.VSphereCluster.Spec.ClusterModules = clusterModuleSpecs
 
@@ -170,12 +248,81 @@ func (r Reconciler) Reconcile(ctx context.Context, clusterCtx *capvcontext.Clust
 		}
 		conditions.MarkFalse(clusterCtx.VSphereCluster, infrav1.ClusterModulesAvailableCondition, infrav1.ClusterModuleSetupFailedReason,
 			clusterv1.ConditionSeverityWarning, generateClusterModuleErrorMessage(modErrs))
-	case len(modErrs) == 0 && len(clusterModuleSpecs) > 0:
+	case len(memberErrs) > 0:
+		err = errors.New(generateClusterModuleErrorMessage(memberErrs))
+		conditions.MarkFalse(clusterCtx.VSphereCluster, infrav1.ClusterModulesAvailableCondition, infrav1.ClusterModuleMembershipFailedReason,
+			clusterv1.ConditionSeverityWarning, generateClusterModuleErrorMessage(memberErrs))
+	case len(modErrs) == 0 && usedModules > 0:
 		conditions.MarkTrue(clusterCtx.VSphereCluster, infrav1.ClusterModulesAvailableCondition)
 	default:
 		conditions.Delete(clusterCtx.VSphereCluster, infrav1.ClusterModulesAvailableCondition)
 	}
-	return reconcile.Result{}, err
+	return reconcile.Result{RequeueAfter: requeueAfter}, err
+}
+
+// addMissingMembers adds the VMs of the Machines of the object to the cluster module if they are not
+// members yet. The VSphereVM controller adds VMs when they are created, but VMs whose VSphereVMs were not
+// reconciled since, e.g. because the cluster was paused, would otherwise never be added.
+func (r Reconciler) addMissingMembers(ctx context.Context, clusterCtx *capvcontext.ClusterContext, obj clustermodule.Wrapper, moduleUUID string) error {
+	vmRefs, err := r.fetchVMRefs(ctx, clusterCtx, obj)
+	if err != nil {
+		return err
+	}
+	if len(vmRefs) == 0 {
+		return nil
+	}
+
+	added, err := r.ClusterModuleService.AddMissingMembers(ctx, clusterCtx, obj, moduleUUID, vmRefs)
+	if err != nil {
+		return err
+	}
+	if added > 0 {
+		ctrl.LoggerFrom(ctx).Info("Added missing VMs to cluster module", "moduleUUID", moduleUUID, "count", added)
+	}
+	return nil
+}
+
+// fetchVMRefs returns the references of the VMs of the Machines owned by the object.
+// VMs which are being deleted, run on a different vCenter or are reconciled in dry-run mode are skipped.
+func (r Reconciler) fetchVMRefs(ctx context.Context, clusterCtx *capvcontext.ClusterContext, obj clustermodule.Wrapper) ([]types.ManagedObjectReference, error) {
+	if r.DryRun {
+		return nil, nil
+	}
+
+	ownerLabel := clusterv1.MachineDeploymentNameLabel
+	if obj.IsControlPlane() {
+		ownerLabel = clusterv1.MachineControlPlaneNameLabel
+	}
+	machines := &clusterv1.MachineList{}
+	if err := r.Client.List(ctx, machines, client.InNamespace(obj.GetNamespace()), client.MatchingLabels{
+		clusterv1.ClusterNameLabel: clusterCtx.Cluster.Name,
+		ownerLabel:                 obj.GetName(),
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to list Machines of %s", obj.GetName())
+	}
+
+	vmRefs := []types.ManagedObjectReference{}
+	for _, machine := range machines.Items {
+		infraRef := machine.Spec.InfrastructureRef
+		if !machine.DeletionTimestamp.IsZero() || infraRef.Kind != "VSphereMachine" {
+			continue
+		}
+		// VSphereVMs are named after their VSphereMachines.
+		vsphereVM := &infrav1.VSphereVM{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: machine.Namespace, Name: infraRef.Name}, vsphereVM); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to get VSphereVM %s", infraRef.Name)
+		}
+		if !vsphereVM.DeletionTimestamp.IsZero() || vsphereVM.Status.VMRef == "" ||
+			vsphereVM.Spec.Server != clusterCtx.VSphereCluster.Spec.Server ||
+			infrautilv1.IsDryRun(clusterCtx.Cluster, clusterCtx.VSphereCluster, vsphereVM) {
+			continue
+		}
+		vmRefs = append(vmRefs, types.ManagedObjectReference{Type: "VirtualMachine", Value: vsphereVM.Status.VMRef})
+	}
+	return vmRefs, nil
 }
 
 func toAffinityInput[T client.Object](c client.Client) handler.TypedMapFunc[T, ctrl.Request] {
@@ -312,6 +459,13 @@ func incompatibleOwnerErrors(errList []clusterModError) []clusterModError {
 	return toReport
 }
 
+// moduleKey identifies a cluster module by its name. The control plane and
+// the MachineDeployments never share cluster modules.
+type moduleKey struct {
+	controlPlane bool
+	name         string
+}
+
 type clusterModError struct {
 	name string
 	err  error
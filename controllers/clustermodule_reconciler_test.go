@@ -24,11 +24,15 @@ import (
 	"github.com/onsi/gomega"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/mock"
+	"github.com/vmware/govmomi/vim25/types"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/clustermodule"
@@ -436,6 +440,176 @@ func TestReconciler_Reconcile(t *testing.T) {
 	}
 }
 
+func TestReconciler_ReconcileLifecycle(t *testing.T) {
+	kcpUUID, mdUUID := uuid.New().String(), uuid.New().String()
+	workersTemplate := "{{ .cluster.name }}-workers"
+	workersName := fake.Clusterv1a2Name + "-workers"
+	wrapperNamed := func(name string) interface{} {
+		return mock.MatchedBy(func(w clustermodule.Wrapper) bool { return w.GetName() == name })
+	}
+
+	tests := []struct {
+		name           string
+		initObjs       []client.Object
+		lifecycle      *infrav1.ClusterModuleLifecycle
+		isDeleting     bool
+		clusterModules []infrav1.ClusterModule
+		setupMocks     func(*cmodfake.CMService)
+		customAssert   func(*gomega.WithT, *capvcontext.ClusterContext, reconcile.Result)
+	}{
+		{
+			name: "when machine deployments share a cluster module",
+			initObjs: []client.Object{
+				controlPlane("kcp", metav1.NamespaceDefault, fake.Clusterv1a2Name),
+				machineDeployment("md-a", metav1.NamespaceDefault, fake.Clusterv1a2Name),
+				machineDeployment("md-b", metav1.NamespaceDefault, fake.Clusterv1a2Name),
+			},
+			lifecycle:      &infrav1.ClusterModuleLifecycle{NameTemplate: &workersTemplate},
+			clusterModules: []infrav1.ClusterModule{},
+			setupMocks: func(svc *cmodfake.CMService) {
+				svc.On("Create", mock.Anything, mock.Anything, wrapperNamed("kcp")).Return(kcpUUID, nil)
+				svc.On("Create", mock.Anything, mock.Anything, wrapperNamed("md-a")).Return(mdUUID, nil).Once()
+			},
+			customAssert: func(g *gomega.WithT, clusterCtx *capvcontext.ClusterContext, _ reconcile.Result) {
+				g.Expect(clusterCtx.VSphereCluster.Spec.ClusterModules).To(gomega.ConsistOf(
+					infrav1.ClusterModule{ControlPlane: true, TargetObjectName: "kcp", ModuleUUID: kcpUUID, Name: "kcp"},
+					infrav1.ClusterModule{TargetObjectName: "md-a", ModuleUUID: mdUUID, Name: workersName},
+					infrav1.ClusterModule{TargetObjectName: "md-b", ModuleUUID: mdUUID, Name: workersName},
+				))
+				g.Expect(conditions.IsTrue(clusterCtx.VSphereCluster, infrav1.ClusterModulesAvailableCondition)).To(gomega.BeTrue())
+			},
+		},
+		{
+			name: "when the cluster module is still used by another machine deployment",
+			initObjs: []client.Object{
+				machineDeployment("md-a", metav1.NamespaceDefault, fake.Clusterv1a2Name),
+			},
+			lifecycle: &infrav1.ClusterModuleLifecycle{NameTemplate: &workersTemplate},
+			clusterModules: []infrav1.ClusterModule{
+				{TargetObjectName: "md-a", ModuleUUID: mdUUID, Name: workersName},
+				{TargetObjectName: "md-b", ModuleUUID: mdUUID, Name: workersName},
+			},
+			setupMocks: func(svc *cmodfake.CMService) {
+				svc.On("DoesExist", mock.Anything, mock.Anything, mock.Anything, mdUUID).Return(true, nil)
+			},
+			customAssert: func(g *gomega.WithT, clusterCtx *capvcontext.ClusterContext, _ reconcile.Result) {
+				g.Expect(clusterCtx.VSphereCluster.Spec.ClusterModules).To(gomega.ConsistOf(
+					infrav1.ClusterModule{TargetObjectName: "md-a", ModuleUUID: mdUUID, Name: workersName},
+				))
+			},
+		},
+		{
+			name:      "when an unused cluster module is within the deletion grace period",
+			lifecycle: &infrav1.ClusterModuleLifecycle{DeletionGracePeriod: &metav1.Duration{Duration: time.Hour}},
+			clusterModules: []infrav1.ClusterModule{
+				{TargetObjectName: "md", ModuleUUID: mdUUID},
+			},
+			customAssert: func(g *gomega.WithT, clusterCtx *capvcontext.ClusterContext, result reconcile.Result) {
+				g.Expect(clusterCtx.VSphereCluster.Spec.ClusterModules).To(gomega.HaveLen(1))
+				g.Expect(clusterCtx.VSphereCluster.Spec.ClusterModules[0].ModuleUUID).To(gomega.Equal(mdUUID))
+				g.Expect(clusterCtx.VSphereCluster.Spec.ClusterModules[0].Name).To(gomega.Equal("md"))
+				g.Expect(clusterCtx.VSphereCluster.Spec.ClusterModules[0].UnusedSince).ToNot(gomega.BeNil())
+				g.Expect(result.RequeueAfter).To(gomega.BeNumerically("~", time.Hour, time.Minute))
+				g.Expect(conditions.Has(clusterCtx.VSphereCluster, infrav1.ClusterModulesAvailableCondition)).To(gomega.BeFalse())
+			},
+		},
+		{
+			name:      "when the deletion grace period of an unused cluster module has passed",
+			lifecycle: &infrav1.ClusterModuleLifecycle{DeletionGracePeriod: &metav1.Duration{Duration: time.Hour}},
+			clusterModules: []infrav1.ClusterModule{
+				{TargetObjectName: "md", ModuleUUID: mdUUID, Name: "md", UnusedSince: &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}},
+			},
+			setupMocks: func(svc *cmodfake.CMService) {
+				svc.On("Remove", mock.Anything, mock.Anything, mdUUID).Return(nil)
+			},
+			customAssert: func(g *gomega.WithT, clusterCtx *capvcontext.ClusterContext, result reconcile.Result) {
+				g.Expect(clusterCtx.VSphereCluster.Spec.ClusterModules).To(gomega.BeEmpty())
+				g.Expect(result.IsZero()).To(gomega.BeTrue())
+			},
+		},
+		{
+			name:       "when the cluster is deleted unused cluster modules are deleted immediately",
+			lifecycle:  &infrav1.ClusterModuleLifecycle{DeletionGracePeriod: &metav1.Duration{Duration: time.Hour}},
+			isDeleting: true,
+			clusterModules: []infrav1.ClusterModule{
+				{TargetObjectName: "md", ModuleUUID: mdUUID, Name: "md", UnusedSince: &metav1.Time{Time: time.Now()}},
+			},
+			setupMocks: func(svc *cmodfake.CMService) {
+				svc.On("Remove", mock.Anything, mock.Anything, mdUUID).Return(nil)
+			},
+			customAssert: func(g *gomega.WithT, clusterCtx *capvcontext.ClusterContext, _ reconcile.Result) {
+				g.Expect(clusterCtx.VSphereCluster.Spec.ClusterModules).To(gomega.BeEmpty())
+			},
+		},
+		{
+			name: "when a recreated machine deployment reuses an unused cluster module",
+			initObjs: []client.Object{
+				machineDeployment("md-new", metav1.NamespaceDefault, fake.Clusterv1a2Name),
+			},
+			lifecycle: &infrav1.ClusterModuleLifecycle{
+				NameTemplate:        &workersTemplate,
+				DeletionGracePeriod: &metav1.Duration{Duration: time.Hour},
+			},
+			clusterModules: []infrav1.ClusterModule{
+				{TargetObjectName: "md-old", ModuleUUID: mdUUID, Name: workersName, UnusedSince: &metav1.Time{Time: time.Now()}},
+			},
+			setupMocks: func(svc *cmodfake.CMService) {
+				svc.On("DoesExist", mock.Anything, mock.Anything, wrapperNamed("md-new"), mdUUID).Return(true, nil)
+			},
+			customAssert: func(g *gomega.WithT, clusterCtx *capvcontext.ClusterContext, result reconcile.Result) {
+				g.Expect(clusterCtx.VSphereCluster.Spec.ClusterModules).To(gomega.ConsistOf(
+					infrav1.ClusterModule{TargetObjectName: "md-new", ModuleUUID: mdUUID, Name: workersName},
+				))
+				g.Expect(result.IsZero()).To(gomega.BeTrue())
+			},
+		},
+		{
+			name: "when the name template is invalid",
+			initObjs: []client.Object{
+				machineDeployment("md", metav1.NamespaceDefault, fake.Clusterv1a2Name),
+			},
+			lifecycle: &infrav1.ClusterModuleLifecycle{NameTemplate: ptr.To("{{ .machine.name }}")},
+			clusterModules: []infrav1.ClusterModule{
+				{TargetObjectName: "md", ModuleUUID: mdUUID},
+			},
+			customAssert: func(g *gomega.WithT, clusterCtx *capvcontext.ClusterContext, _ reconcile.Result) {
+				// The cluster module is kept as it is still used by the MachineDeployment.
+				g.Expect(clusterCtx.VSphereCluster.Spec.ClusterModules).To(gomega.ConsistOf(
+					infrav1.ClusterModule{TargetObjectName: "md", ModuleUUID: mdUUID},
+				))
+				g.Expect(conditions.IsFalse(clusterCtx.VSphereCluster, infrav1.ClusterModulesAvailableCondition)).To(gomega.BeTrue())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			controllerManagerContext := fake.NewControllerManagerContext(tt.initObjs...)
+			clusterCtx := fake.NewClusterContext(ctx, controllerManagerContext)
+			clusterCtx.VSphereCluster.Spec.ClusterModules = tt.clusterModules
+			clusterCtx.VSphereCluster.Spec.ClusterModuleLifecycle = tt.lifecycle
+			clusterCtx.VSphereCluster.Status = infrav1.VSphereClusterStatus{VCenterVersion: infrav1.NewVCenterVersion("7.0.0")}
+			if tt.isDeleting {
+				clusterCtx.VSphereCluster.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			}
+
+			svc := new(cmodfake.CMService)
+			if tt.setupMocks != nil {
+				tt.setupMocks(svc)
+			}
+
+			r := Reconciler{
+				Client:               controllerManagerContext.Client,
+				ClusterModuleService: svc,
+			}
+			result, _ := r.Reconcile(ctx, clusterCtx)
+			tt.customAssert(g, clusterCtx, result)
+
+			svc.AssertExpectations(t)
+		})
+	}
+}
+
 func TestReconciler_fetchMachineOwnerObjects(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -518,6 +692,123 @@ func TestReconciler_fetchMachineOwnerObjects(t *testing.T) {
 	})
 }
 
+func TestReconciler_AddMissingMembers(t *testing.T) {
+	kcpUUID, mdUUID := uuid.New().String(), uuid.New().String()
+	clusterModules := []infrav1.ClusterModule{
+		{ControlPlane: true, TargetObjectName: "kcp", ModuleUUID: kcpUUID},
+		{ControlPlane: false, TargetObjectName: "md", ModuleUUID: mdUUID},
+	}
+	vmRef := func(value string) []types.ManagedObjectReference {
+		return []types.ManagedObjectReference{{Type: "VirtualMachine", Value: value}}
+	}
+
+	tests := []struct {
+		name         string
+		dryRun       bool
+		setupMocks   func(*cmodfake.CMService)
+		haveError    bool
+		customAssert func(*gomega.WithT, *capvcontext.ClusterContext)
+	}{
+		{
+			name: "adds the VMs of the Machines to their cluster modules",
+			setupMocks: func(svc *cmodfake.CMService) {
+				svc.On("AddMissingMembers", mock.Anything, mock.Anything, mock.Anything, kcpUUID, vmRef("vm-3")).Return(0, nil)
+				svc.On("AddMissingMembers", mock.Anything, mock.Anything, mock.Anything, mdUUID, vmRef("vm-1")).Return(1, nil)
+			},
+			customAssert: func(g *gomega.WithT, clusterCtx *capvcontext.ClusterContext) {
+				g.Expect(conditions.IsTrue(clusterCtx.VSphereCluster, infrav1.ClusterModulesAvailableCondition)).To(gomega.BeTrue())
+			},
+		},
+		{
+			name: "reports VMs which can't be added to their cluster modules",
+			setupMocks: func(svc *cmodfake.CMService) {
+				svc.On("AddMissingMembers", mock.Anything, mock.Anything, mock.Anything, kcpUUID, vmRef("vm-3")).Return(0, nil)
+				svc.On("AddMissingMembers", mock.Anything, mock.Anything, mock.Anything, mdUUID, vmRef("vm-1")).Return(0, errors.New("500 Internal Server Error"))
+			},
+			haveError: true,
+			customAssert: func(g *gomega.WithT, clusterCtx *capvcontext.ClusterContext) {
+				g.Expect(conditions.IsFalse(clusterCtx.VSphereCluster, infrav1.ClusterModulesAvailableCondition)).To(gomega.BeTrue())
+				g.Expect(conditions.GetReason(clusterCtx.VSphereCluster, infrav1.ClusterModulesAvailableCondition)).To(gomega.Equal(infrav1.ClusterModuleMembershipFailedReason))
+				g.Expect(clusterCtx.VSphereCluster.Spec.ClusterModules).To(gomega.HaveLen(2))
+			},
+		},
+		{
+			name:   "does not add VMs in dry-run mode",
+			dryRun: true,
+			customAssert: func(g *gomega.WithT, clusterCtx *capvcontext.ClusterContext) {
+				g.Expect(conditions.IsTrue(clusterCtx.VSphereCluster, infrav1.ClusterModulesAvailableCondition)).To(gomega.BeTrue())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			kcp := controlPlane("kcp", metav1.NamespaceDefault, fake.Clusterv1a2Name)
+			md := machineDeployment("md", metav1.NamespaceDefault, fake.Clusterv1a2Name)
+			objects := []client.Object{kcp, md}
+			for _, m := range []struct {
+				name, ownerLabel, owner, vmRef, server string
+			}{
+				{name: "m-1", ownerLabel: clusterv1.MachineDeploymentNameLabel, owner: "md", vmRef: "vm-1", server: fake.VCenterURL},
+				// The VM has not been created yet.
+				{name: "m-2", ownerLabel: clusterv1.MachineDeploymentNameLabel, owner: "md", server: fake.VCenterURL},
+				{name: "m-3", ownerLabel: clusterv1.MachineControlPlaneNameLabel, owner: "kcp", vmRef: "vm-3", server: fake.VCenterURL},
+				// The VM runs on a different vCenter.
+				{name: "m-4", ownerLabel: clusterv1.MachineDeploymentNameLabel, owner: "md", vmRef: "vm-4", server: "other.vcenter"},
+			} {
+				objects = append(objects,
+					&clusterv1.Machine{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      m.name,
+							Namespace: metav1.NamespaceDefault,
+							Labels:    map[string]string{clusterv1.ClusterNameLabel: fake.Clusterv1a2Name, m.ownerLabel: m.owner},
+						},
+						Spec: clusterv1.MachineSpec{
+							ClusterName:       fake.Clusterv1a2Name,
+							InfrastructureRef: corev1.ObjectReference{Kind: "VSphereMachine", Name: m.name},
+						},
+					},
+					&infrav1.VSphereVM{
+						ObjectMeta: metav1.ObjectMeta{Name: m.name, Namespace: metav1.NamespaceDefault},
+						Spec: infrav1.VSphereVMSpec{
+							VirtualMachineCloneSpec: infrav1.VirtualMachineCloneSpec{Server: m.server},
+						},
+						Status: infrav1.VSphereVMStatus{VMRef: m.vmRef},
+					},
+				)
+			}
+
+			controllerManagerContext := fake.NewControllerManagerContext(objects...)
+			clusterCtx := fake.NewClusterContext(ctx, controllerManagerContext)
+			clusterCtx.VSphereCluster.Spec.ClusterModules = clusterModules
+			clusterCtx.VSphereCluster.Status = infrav1.VSphereClusterStatus{VCenterVersion: infrav1.NewVCenterVersion("7.0.0")}
+
+			svc := new(cmodfake.CMService)
+			svc.On("DoesExist", mock.Anything, mock.Anything, mock.Anything, kcpUUID).Return(true, nil)
+			svc.On("DoesExist", mock.Anything, mock.Anything, mock.Anything, mdUUID).Return(true, nil)
+			if tt.setupMocks != nil {
+				tt.setupMocks(svc)
+			}
+
+			r := Reconciler{
+				Client:               controllerManagerContext.Client,
+				ClusterModuleService: svc,
+				DryRun:               tt.dryRun,
+			}
+			_, err := r.Reconcile(ctx, clusterCtx)
+			if tt.haveError {
+				g.Expect(err).To(gomega.HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(gomega.HaveOccurred())
+			}
+			tt.customAssert(g, clusterCtx)
+
+			svc.AssertExpectations(t)
+		})
+	}
+}
+
 func machineDeployment(name, namespace, cluster string) *clusterv1.MachineDeployment {
 	return &clusterv1.MachineDeployment{
 		TypeMeta: metav1.TypeMeta{
@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+)
+
+// controlPlaneControllerSuffix is appended to the name of the controllers which
+// reconcile the control plane objects in a separate work queue.
+const controlPlaneControllerSuffix = "-controlplane"
+
+// addControllersWithControlPlanePriority adds the controller named name built by addController
+// to the manager. If ControlPlaneConcurrency is set, two controllers are added instead: one named
+// name with the control plane suffix which reconciles only control plane objects with the given
+// concurrency, and one named name which reconciles all other objects with the given options.
+// Each controller has its own work queue, so control plane objects are reconciled without
+// waiting for the requests of worker objects, e.g. during large scale-ups of MachineDeployments.
+func addControllersWithControlPlanePriority(ctx context.Context, controllerManagerCtx *capvcontext.ControllerManagerContext, mgr manager.Manager, name string, newObj func() ctrlclient.Object, options controller.Options, addController func(name string, options controller.Options) error) error {
+	if controllerManagerCtx.ControlPlaneConcurrency <= 0 {
+		return addController(name, options)
+	}
+
+	queues := &controlPlanePriorityQueues{
+		ctx:    ctx,
+		reader: mgr.GetClient(),
+		newObj: newObj,
+	}
+	controlPlaneOptions := queues.withQueue(true, options)
+	controlPlaneOptions.MaxConcurrentReconciles = controllerManagerCtx.ControlPlaneConcurrency
+	if err := addController(name+controlPlaneControllerSuffix, controlPlaneOptions); err != nil {
+		return err
+	}
+	return addController(name, queues.withQueue(false, options))
+}
+
+// controlPlanePriorityQueues are the work queues of the controllers reconciling the
+// control plane objects and all other objects of a kind. Requests added to the queue of
+// one controller are routed to the queue of the controller reconciling the object, so
+// events received only by one of the controllers, e.g. from a shared GenericEvent channel,
+// are not lost.
+type controlPlanePriorityQueues struct {
+	ctx    context.Context
+	reader ctrlclient.Reader
+	newObj func() ctrlclient.Object
+
+	lock         sync.RWMutex
+	controlPlane workqueue.TypedRateLimitingInterface[reconcile.Request]
+	workers      workqueue.TypedRateLimitingInterface[reconcile.Request]
+}
+
+// withQueue returns the options with a work queue routing the requests of control plane
+// objects to the control plane controller and all other requests to the worker controller.
+func (q *controlPlanePriorityQueues) withQueue(controlPlane bool, options controller.Options) controller.Options {
+	newQueue := options.NewQueue
+	options.NewQueue = func(controllerName string, rateLimiter workqueue.TypedRateLimiter[reconcile.Request]) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+		var queue workqueue.TypedRateLimitingInterface[reconcile.Request]
+		if newQueue != nil {
+			queue = newQueue(controllerName, rateLimiter)
+		} else {
+			queue = workqueue.NewTypedRateLimitingQueueWithConfig(rateLimiter, workqueue.TypedRateLimitingQueueConfig[reconcile.Request]{
+				Name: controllerName,
+			})
+		}
+
+		q.lock.Lock()
+		defer q.lock.Unlock()
+		if controlPlane {
+			q.controlPlane = queue
+		} else {
+			q.workers = queue
+		}
+		return &routingQueue{TypedRateLimitingInterface: queue, queues: q}
+	}
+	return options
+}
+
+// queueFor returns the queue of the controller reconciling the object of the request,
+// or the given queue if the other controller hasn't been started yet.
+func (q *controlPlanePriorityQueues) queueFor(req reconcile.Request, queue workqueue.TypedRateLimitingInterface[reconcile.Request]) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+	target := q.workers
+	if q.isControlPlane(req) {
+		target = q.controlPlane
+	}
+	if target == nil {
+		return queue
+	}
+	return target
+}
+
+func (q *controlPlanePriorityQueues) isControlPlane(req reconcile.Request) bool {
+	obj := q.newObj()
+	if err := q.reader.Get(q.ctx, req.NamespacedName, obj); err != nil {
+		// Objects which can't be read, e.g. because they have been deleted,
+		// are left to the worker controller.
+		return false
+	}
+	_, ok := obj.GetLabels()[clusterv1.MachineControlPlaneLabel]
+	return ok
+}
+
+// routingQueue is the work queue of one of the controllers of a kind which adds
+// requests to the queue of the controller reconciling the object.
+type routingQueue struct {
+	workqueue.TypedRateLimitingInterface[reconcile.Request]
+
+	queues *controlPlanePriorityQueues
+}
+
+// Add adds the request to the queue of the controller reconciling the object.
+func (q *routingQueue) Add(req reconcile.Request) {
+	q.target(req).Add(req)
+}
+
+// AddAfter adds the request to the queue of the controller reconciling the object
+// after the given duration.
+func (q *routingQueue) AddAfter(req reconcile.Request, duration time.Duration) {
+	q.target(req).AddAfter(req, duration)
+}
+
+// AddRateLimited adds the request to the queue of the controller reconciling the
+// object once the rate limiter of that queue allows it.
+func (q *routingQueue) AddRateLimited(req reconcile.Request) {
+	q.target(req).AddRateLimited(req)
+}
+
+func (q *routingQueue) target(req reconcile.Request) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+	q.queues.lock.RLock()
+	defer q.queues.lock.RUnlock()
+	return q.queues.queueFor(req, q.TypedRateLimitingInterface)
+}
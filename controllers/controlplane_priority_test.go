@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/context/fake"
+)
+
+func Test_controlPlanePriorityQueues(t *testing.T) {
+	newVSphereVM := func(name string, controlPlane bool) *infrav1.VSphereVM {
+		vsphereVM := &infrav1.VSphereVM{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "test",
+				Labels:    map[string]string{clusterv1.ClusterNameLabel: "cluster"},
+			},
+		}
+		if controlPlane {
+			vsphereVM.Labels[clusterv1.MachineControlPlaneLabel] = ""
+		}
+		return vsphereVM
+	}
+	request := func(name string) reconcile.Request {
+		return reconcile.Request{NamespacedName: apitypes.NamespacedName{Namespace: "test", Name: name}}
+	}
+	newQueue := func(options controller.Options, name string) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+		return options.NewQueue(name, workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	}
+
+	controllerManagerCtx := fake.NewControllerManagerContext(newVSphereVM("control-plane", true), newVSphereVM("worker", false))
+	queues := &controlPlanePriorityQueues{
+		ctx:    context.Background(),
+		reader: controllerManagerCtx.Client,
+		newObj: func() client.Object { return &infrav1.VSphereVM{} },
+	}
+
+	t.Run("keeps requests in the queue they are added to until the other controller is started", func(t *testing.T) {
+		g := NewWithT(t)
+
+		workers := newQueue(queues.withQueue(false, controller.Options{}), "vspherevm")
+		defer workers.ShutDown()
+
+		workers.Add(request("control-plane"))
+		g.Expect(workers.Len()).To(Equal(1))
+
+		req, _ := workers.Get()
+		workers.Done(req)
+		workers.Forget(req)
+		g.Expect(workers.Len()).To(Equal(0))
+	})
+
+	t.Run("routes requests to the queue of the controller reconciling the object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		controlPlane := newQueue(queues.withQueue(true, controller.Options{}), "vspherevm"+controlPlaneControllerSuffix)
+		workers := newQueue(queues.withQueue(false, controller.Options{}), "vspherevm")
+		defer controlPlane.ShutDown()
+		defer workers.ShutDown()
+
+		workers.Add(request("control-plane"))
+		g.Expect(controlPlane.Len()).To(Equal(1))
+		g.Expect(workers.Len()).To(Equal(0))
+
+		controlPlane.Add(request("worker"))
+		g.Expect(controlPlane.Len()).To(Equal(1))
+		g.Expect(workers.Len()).To(Equal(1))
+
+		// Requests of objects which can't be read are left to the worker controller.
+		controlPlane.Add(request("deleted"))
+		g.Expect(controlPlane.Len()).To(Equal(1))
+		g.Expect(workers.Len()).To(Equal(2))
+
+		req, _ := controlPlane.Get()
+		g.Expect(req).To(Equal(request("control-plane")))
+	})
+}
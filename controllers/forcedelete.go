@@ -0,0 +1,106 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=vsphereforcedeletepolicies,verbs=get;list;watch
+
+// getForceDeletePolicy returns the VSphereForceDeletePolicy which allows to remove the finalizer of a deleted
+// object without cleaning up vSphere, because vCenter has been unreachable for longer than the timeout of the
+// policy. It returns nil if vCenter is reachable according to the VCenterAvailable condition of the object,
+// or if no policy selecting the object has expired yet.
+func getForceDeletePolicy(ctx context.Context, c ctrlclient.Client, obj conditions.Getter, cluster *clusterv1.Cluster, now time.Time) (*infrav1.VSphereForceDeletePolicy, error) {
+	unreachableSince, ok := vCenterUnreachableSince(obj)
+	if !ok {
+		return nil, nil
+	}
+
+	policyList := &infrav1.VSphereForceDeletePolicyList{}
+	if err := c.List(ctx, policyList); err != nil {
+		return nil, errors.Wrap(err, "failed to list VSphereForceDeletePolicies")
+	}
+	if len(policyList.Items) == 0 {
+		return nil, nil
+	}
+
+	ns := &corev1.Namespace{}
+	if err := c.Get(ctx, ctrlclient.ObjectKey{Name: obj.GetNamespace()}, ns); err != nil {
+		return nil, errors.Wrapf(err, "failed to get namespace %s", obj.GetNamespace())
+	}
+
+	var policy *infrav1.VSphereForceDeletePolicy
+	for i := range policyList.Items {
+		p := &policyList.Items[i]
+		if now.Sub(unreachableSince) < p.Spec.VCenterUnreachableTimeout.Duration {
+			continue
+		}
+		nsSelector, err := metav1.LabelSelectorAsSelector(&p.Spec.NamespaceSelector)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid namespace selector of VSphereForceDeletePolicy %s", p.Name)
+		}
+		if !nsSelector.Matches(labels.Set(ns.Labels)) {
+			continue
+		}
+		clusterSelector, err := metav1.LabelSelectorAsSelector(&p.Spec.ClusterSelector)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid cluster selector of VSphereForceDeletePolicy %s", p.Name)
+		}
+		// Objects which don't belong to a Cluster are only selected by policies selecting all Clusters.
+		if cluster == nil {
+			if !clusterSelector.Empty() {
+				continue
+			}
+		} else if !clusterSelector.Matches(labels.Set(cluster.Labels)) {
+			continue
+		}
+		if policy == nil || p.Spec.VCenterUnreachableTimeout.Duration < policy.Spec.VCenterUnreachableTimeout.Duration {
+			policy = p
+		}
+	}
+	return policy, nil
+}
+
+// vCenterUnreachableSince returns the time since which vCenter has been unreachable while the object is
+// deleted, i.e. the later of its deletion and the last transition of its VCenterAvailable condition.
+func vCenterUnreachableSince(obj conditions.Getter) (time.Time, bool) {
+	deletionTimestamp := obj.GetDeletionTimestamp()
+	if deletionTimestamp.IsZero() {
+		return time.Time{}, false
+	}
+	condition := conditions.Get(obj, infrav1.VCenterAvailableCondition)
+	if condition == nil || condition.Status != corev1.ConditionFalse || condition.Reason != infrav1.VCenterUnreachableReason {
+		return time.Time{}, false
+	}
+	if condition.LastTransitionTime.Before(deletionTimestamp) {
+		return deletionTimestamp.Time, true
+	}
+	return condition.LastTransitionTime.Time, true
+}
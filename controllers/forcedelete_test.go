@@ -0,0 +1,182 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/context/fake"
+)
+
+func Test_getForceDeletePolicy(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"team": "a"}}}
+	newPolicy := func(name string, timeout time.Duration, clusterLabels map[string]string) *infrav1.VSphereForceDeletePolicy {
+		return &infrav1.VSphereForceDeletePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: infrav1.VSphereForceDeletePolicySpec{
+				NamespaceSelector:         metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+				ClusterSelector:           metav1.LabelSelector{MatchLabels: clusterLabels},
+				VCenterUnreachableTimeout: metav1.Duration{Duration: timeout},
+			},
+		}
+	}
+	newVSphereVM := func(deletedAgo, unreachableAgo time.Duration) *infrav1.VSphereVM {
+		vsphereVM := &infrav1.VSphereVM{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "vm",
+				Namespace:         namespace.Name,
+				DeletionTimestamp: &metav1.Time{Time: now.Add(-deletedAgo)},
+				Finalizers:        []string{infrav1.VMFinalizer},
+			},
+		}
+		vsphereVM.SetConditions(clusterv1.Conditions{vCenterUnreachableCondition(now.Add(-unreachableAgo))})
+		return vsphereVM
+	}
+	devCluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "dev", Labels: map[string]string{"env": "dev"}}}
+
+	tests := []struct {
+		name       string
+		policies   []client.Object
+		vsphereVM  *infrav1.VSphereVM
+		cluster    *clusterv1.Cluster
+		wantPolicy string
+	}{
+		{
+			name:      "no policy",
+			vsphereVM: newVSphereVM(2*time.Hour, 2*time.Hour),
+		},
+		{
+			name:       "policy with expired timeout",
+			policies:   []client.Object{newPolicy("policy", time.Hour, nil)},
+			vsphereVM:  newVSphereVM(2*time.Hour, 2*time.Hour),
+			wantPolicy: "policy",
+		},
+		{
+			name:      "timeout measured from the deletion",
+			policies:  []client.Object{newPolicy("policy", time.Hour, nil)},
+			vsphereVM: newVSphereVM(30*time.Minute, 2*time.Hour),
+		},
+		{
+			name:      "timeout measured from vCenter becoming unreachable",
+			policies:  []client.Object{newPolicy("policy", time.Hour, nil)},
+			vsphereVM: newVSphereVM(2*time.Hour, 30*time.Minute),
+		},
+		{
+			name:       "shortest timeout wins",
+			policies:   []client.Object{newPolicy("long", 90*time.Minute, nil), newPolicy("short", time.Hour, nil)},
+			vsphereVM:  newVSphereVM(2*time.Hour, 2*time.Hour),
+			wantPolicy: "short",
+		},
+		{
+			name:       "cluster selected by labels",
+			policies:   []client.Object{newPolicy("dev", time.Hour, map[string]string{"env": "dev"})},
+			vsphereVM:  newVSphereVM(2*time.Hour, 2*time.Hour),
+			cluster:    devCluster,
+			wantPolicy: "dev",
+		},
+		{
+			name:      "objects without a cluster are not selected by a cluster selector",
+			policies:  []client.Object{newPolicy("dev", time.Hour, map[string]string{"env": "dev"})},
+			vsphereVM: newVSphereVM(2*time.Hour, 2*time.Hour),
+		},
+		{
+			name:      "other namespaces are not selected",
+			policies:  []client.Object{&infrav1.VSphereForceDeletePolicy{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}, Spec: infrav1.VSphereForceDeletePolicySpec{NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "b"}}, VCenterUnreachableTimeout: metav1.Duration{Duration: time.Hour}}}},
+			vsphereVM: newVSphereVM(2*time.Hour, 2*time.Hour),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			controllerManagerCtx := fake.NewControllerManagerContext(append(tt.policies, namespace)...)
+			policy, err := getForceDeletePolicy(context.Background(), controllerManagerCtx.Client, tt.vsphereVM, tt.cluster, now)
+			g.Expect(err).ToNot(HaveOccurred())
+			if tt.wantPolicy == "" {
+				g.Expect(policy).To(BeNil())
+			} else {
+				g.Expect(policy).ToNot(BeNil())
+				g.Expect(policy.Name).To(Equal(tt.wantPolicy))
+			}
+		})
+	}
+}
+
+func Test_vmReconciler_reconcileDeleteVCenterUnreachable(t *testing.T) {
+	g := NewWithT(t)
+
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+	policy := &infrav1.VSphereForceDeletePolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+		Spec:       infrav1.VSphereForceDeletePolicySpec{VCenterUnreachableTimeout: metav1.Duration{Duration: time.Hour}},
+	}
+	vsphereVM := &infrav1.VSphereVM{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "vm",
+			Namespace:         namespace.Name,
+			DeletionTimestamp: &metav1.Time{Time: time.Now().Add(-2 * time.Hour)},
+			Finalizers:        []string{infrav1.VMFinalizer},
+		},
+	}
+	controllerManagerCtx := fake.NewControllerManagerContext(namespace, policy, vsphereVM)
+	recorder := record.NewFakeRecorder(10)
+	r := vmReconciler{ControllerManagerContext: controllerManagerCtx, Recorder: recorder}
+	vCenterErr := errors.New("connection refused")
+
+	// vCenter just became unreachable, the VSphereVM is not force deleted yet.
+	patchHelper, err := patch.NewHelper(vsphereVM, controllerManagerCtx.Client)
+	g.Expect(err).ToNot(HaveOccurred())
+	conditions.MarkFalse(vsphereVM, infrav1.VCenterAvailableCondition, infrav1.VCenterUnreachableReason, clusterv1.ConditionSeverityError, vCenterErr.Error())
+	_, err = r.reconcileDeleteVCenterUnreachable(context.Background(), vsphereVM, nil, patchHelper, vCenterErr)
+	g.Expect(err).To(MatchError(vCenterErr))
+	g.Expect(vsphereVM.Finalizers).To(ContainElement(infrav1.VMFinalizer))
+
+	// Once vCenter has been unreachable for longer than the timeout, the finalizer is removed.
+	g.Expect(controllerManagerCtx.Client.Get(context.Background(), client.ObjectKeyFromObject(vsphereVM), vsphereVM)).To(Succeed())
+	patchHelper, err = patch.NewHelper(vsphereVM, controllerManagerCtx.Client)
+	g.Expect(err).ToNot(HaveOccurred())
+	vsphereVM.SetConditions(clusterv1.Conditions{vCenterUnreachableCondition(time.Now().Add(-90 * time.Minute))})
+	_, err = r.reconcileDeleteVCenterUnreachable(context.Background(), vsphereVM, nil, patchHelper, vCenterErr)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(vsphereVM.Finalizers).To(BeEmpty())
+	g.Expect(conditions.GetReason(vsphereVM, infrav1.VMDeletedCondition)).To(Equal(infrav1.ForceDeletedReason))
+	g.Expect(recorder.Events).To(Receive(ContainSubstring("VSphereForceDeletePolicy policy")))
+}
+
+func vCenterUnreachableCondition(since time.Time) clusterv1.Condition {
+	return clusterv1.Condition{
+		Type:               infrav1.VCenterAvailableCondition,
+		Status:             corev1.ConditionFalse,
+		Severity:           clusterv1.ConditionSeverityError,
+		Reason:             infrav1.VCenterUnreachableReason,
+		LastTransitionTime: metav1.Time{Time: since},
+	}
+}
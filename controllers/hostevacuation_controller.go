@@ -0,0 +1,356 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cluster-api/controllers/clustercache"
+	clusterutilv1 "sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/govmomi"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/util"
+)
+
+const (
+	// hostEvacuationSyncPeriod is the interval in which the hosts of the VMs
+	// with local storage are checked for evacuations.
+	hostEvacuationSyncPeriod = 1 * time.Minute
+
+	// hostEvacuationCacheTTL is the duration for which the state of a host is shared
+	// between the VMs running on it.
+	hostEvacuationCacheTTL = 30 * time.Second
+
+	// hostEvacuationDrainRetryPeriod is the interval in which a drain which is not completed yet is retried.
+	hostEvacuationDrainRetryPeriod = 20 * time.Second
+)
+
+// AddHostEvacuationControllerToManager adds the host evacuation controller to the provided manager.
+// The controller cordons and drains the Node of a VSphereVM which uses local storage when the VM is
+// getting migrated by DRS or its host enters maintenance mode, if enabled in the VSphereCluster.
+func AddHostEvacuationControllerToManager(ctx context.Context, controllerManagerCtx *capvcontext.ControllerManagerContext, mgr manager.Manager, clusterCache clustercache.ClusterCache, options controller.Options) error {
+	r := hostEvacuationReconciler{
+		ControllerManagerContext: controllerManagerCtx,
+		Recorder:                 mgr.GetEventRecorderFor("hostevacuation-controller"),
+		clusterCache:             clusterCache,
+		hosts:                    govmomi.NewHostEvacuationCache(hostEvacuationCacheTTL),
+	}
+	predicateLog := ctrl.LoggerFrom(ctx).WithValues("controller", "hostevacuation")
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("hostevacuation").
+		For(&infrav1.VSphereVM{}).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(mgr.GetScheme(), predicateLog, controllerManagerCtx.WatchFilterValue)).
+		Watches(
+			&infrav1.VSphereCluster{},
+			handler.EnqueueRequestsFromMapFunc(vmReconciler{ControllerManagerContext: controllerManagerCtx}.vsphereClusterToVSphereVMs),
+		).
+		Complete(r)
+}
+
+type hostEvacuationReconciler struct {
+	Recorder record.EventRecorder
+	*capvcontext.ControllerManagerContext
+	clusterCache clustercache.ClusterCache
+	hosts        *govmomi.HostEvacuationCache
+}
+
+// Reconcile cordons and drains the Node of the VSphereVM while its host is being evacuated
+// and uncordons the Node once the evacuation is completed.
+func (r hostEvacuationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (reconcile.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	vsphereVM := &infrav1.VSphereVM{}
+	if err := r.Client.Get(ctx, req.NamespacedName, vsphereVM); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	// Deletion of the VM is handled by CAPI, which drains the Node itself.
+	if !vsphereVM.DeletionTimestamp.IsZero() || !vsphereVM.Status.Ready {
+		return reconcile.Result{}, nil
+	}
+
+	cluster, err := clusterutilv1.GetClusterFromMetadata(ctx, r.Client, vsphereVM.ObjectMeta)
+	if err != nil {
+		log.V(4).Info("Skipping host evacuation check, VSphereVM is missing cluster label or cluster does not exist")
+		return reconcile.Result{}, nil
+	}
+	log = log.WithValues("Cluster", klog.KObj(cluster))
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	if annotations.IsPaused(cluster, vsphereVM) || !cluster.DeletionTimestamp.IsZero() || cluster.Spec.InfrastructureRef == nil {
+		return reconcile.Result{}, nil
+	}
+
+	vsphereCluster := &infrav1.VSphereCluster{}
+	vsphereClusterKey := ctrlclient.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Spec.InfrastructureRef.Name}
+	if err := r.Client.Get(ctx, vsphereClusterKey, vsphereCluster); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to get VSphereCluster %s", vsphereClusterKey)
+	}
+
+	// Without host evacuation, only Nodes cordoned before it got disabled have to be uncordoned.
+	enabled := vsphereCluster.Spec.HostEvacuation != nil && vsphereCluster.Spec.HostEvacuation.DrainNodesWithLocalStorage
+	_, cordoned := vsphereVM.Annotations[infrav1.HostEvacuationDrainedAnnotation]
+	if !enabled && !cordoned {
+		return reconcile.Result{}, nil
+	}
+
+	// The Node is resolved from the Machine, as its name does not have to match the name of the VM.
+	nodeName, err := r.getNodeName(ctx, vsphereVM)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if nodeName == "" {
+		log.V(4).Info("Skipping host evacuation check, Machine has no NodeRef yet")
+		return reconcile.Result{}, nil
+	}
+
+	clusterClient, err := r.clusterCache.GetClient(ctx, ctrlclient.ObjectKeyFromObject(cluster))
+	if err != nil {
+		if errors.Is(err, clustercache.ErrClusterNotConnected) {
+			log.V(2).Info("Skipping host evacuation check because connection to the workload cluster is down")
+			return reconcile.Result{RequeueAfter: hostEvacuationSyncPeriod}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	node := &corev1.Node{}
+	if err := clusterClient.Get(ctx, ctrlclient.ObjectKey{Name: nodeName}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, r.setCordonedAnnotation(ctx, vsphereVM, "")
+		}
+		return reconcile.Result{}, errors.Wrapf(err, "failed to get Node %s", nodeName)
+	}
+	log = log.WithValues("Node", klog.KObj(node))
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	if !enabled {
+		// Uncordon Nodes drained before the feature got disabled.
+		return reconcile.Result{}, r.uncordonNode(ctx, clusterClient, vsphereVM, node)
+	}
+
+	authSession, err := vmReconciler{ControllerManagerContext: r.ControllerManagerContext}.retrieveVcenterSession(ctx, vsphereVM)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to get vCenter session")
+	}
+	vmCtx := &capvcontext.VMContext{
+		ControllerManagerContext: r.ControllerManagerContext,
+		VSphereVM:                vsphereVM,
+		Session:                  authSession,
+	}
+
+	status, err := govmomi.GetHostEvacuationStatus(ctx, vmCtx, r.hosts)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to get host evacuation status for %s", vmCtx)
+	}
+
+	// VMs on shared storage are moved by vMotion without any disruption, so they don't
+	// have to be checked again unless the VSphereVM or the VSphereCluster change.
+	if !status.LocalStorage {
+		return reconcile.Result{}, r.uncordonNode(ctx, clusterClient, vsphereVM, node)
+	}
+	if !status.InProgress {
+		if err := r.uncordonNode(ctx, clusterClient, vsphereVM, node); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{RequeueAfter: hostEvacuationSyncPeriod}, nil
+	}
+
+	if err := r.cordonNode(ctx, clusterClient, vsphereVM, node, status.Host); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	drained, err := drainNode(ctx, clusterClient, node.Name)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to drain Node %s", node.Name)
+	}
+	if !drained {
+		log.Info("Waiting for Node to be drained before host evacuation", "host", status.Host)
+		return reconcile.Result{RequeueAfter: hostEvacuationDrainRetryPeriod}, nil
+	}
+
+	return reconcile.Result{RequeueAfter: hostEvacuationSyncPeriod}, nil
+}
+
+// getNodeName returns the name of the Node of the VSphereVM from the NodeRef of its Machine.
+// It returns an empty name if the VSphereVM has no Machine, e.g. because it belongs to a
+// VSphereMachinePool, or if the NodeRef is not set yet.
+func (r hostEvacuationReconciler) getNodeName(ctx context.Context, vsphereVM *infrav1.VSphereVM) (string, error) {
+	vsphereMachine, err := util.GetOwnerVSphereMachine(ctx, r.Client, vsphereVM.ObjectMeta)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get VSphereMachine for VSphereVM")
+	}
+	if vsphereMachine == nil {
+		return "", nil
+	}
+	machine, err := clusterutilv1.GetOwnerMachine(ctx, r.Client, vsphereMachine.ObjectMeta)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get Machine for VSphereMachine")
+	}
+	if machine == nil || machine.Status.NodeRef == nil {
+		return "", nil
+	}
+	return machine.Status.NodeRef.Name, nil
+}
+
+// cordonNode marks the Node as unschedulable and records the evacuated host in an annotation
+// on the Node and on the VSphereVM, so that the Node can be uncordoned without looking it up
+// for every VSphereVM. Nodes which have already been cordoned by someone else are not annotated,
+// so they are not uncordoned after the evacuation.
+func (r hostEvacuationReconciler) cordonNode(ctx context.Context, c ctrlclient.Client, vsphereVM *infrav1.VSphereVM, node *corev1.Node, host string) error {
+	if node.Spec.Unschedulable {
+		return nil
+	}
+
+	log := ctrl.LoggerFrom(ctx)
+	log.Info("Cordoning Node because of host evacuation", "host", host)
+
+	// The VSphereVM is annotated first, so that the Node is never left cordoned untracked.
+	if err := r.setCordonedAnnotation(ctx, vsphereVM, host); err != nil {
+		return err
+	}
+
+	patchBase := ctrlclient.MergeFrom(node.DeepCopy())
+	node.Spec.Unschedulable = true
+	if node.Annotations == nil {
+		node.Annotations = map[string]string{}
+	}
+	node.Annotations[infrav1.HostEvacuationDrainedAnnotation] = host
+	if err := c.Patch(ctx, node, patchBase); err != nil {
+		return errors.Wrapf(err, "failed to cordon Node %s", node.Name)
+	}
+	r.Recorder.Eventf(node, corev1.EventTypeNormal, "HostEvacuation", "Cordoned Node because host %s is being evacuated", host)
+	return nil
+}
+
+// uncordonNode marks the Node as schedulable again if it has been cordoned by cordonNode.
+func (r hostEvacuationReconciler) uncordonNode(ctx context.Context, c ctrlclient.Client, vsphereVM *infrav1.VSphereVM, node *corev1.Node) error {
+	if host, ok := node.Annotations[infrav1.HostEvacuationDrainedAnnotation]; ok {
+		log := ctrl.LoggerFrom(ctx)
+		log.Info("Uncordoning Node because host evacuation is completed", "host", host)
+
+		patchBase := ctrlclient.MergeFrom(node.DeepCopy())
+		node.Spec.Unschedulable = false
+		delete(node.Annotations, infrav1.HostEvacuationDrainedAnnotation)
+		if err := c.Patch(ctx, node, patchBase); err != nil {
+			return errors.Wrapf(err, "failed to uncordon Node %s", node.Name)
+		}
+		r.Recorder.Eventf(node, corev1.EventTypeNormal, "HostEvacuation", "Uncordoned Node because evacuation of host %s is completed", host)
+	}
+	return r.setCordonedAnnotation(ctx, vsphereVM, "")
+}
+
+// setCordonedAnnotation sets the HostEvacuationDrainedAnnotation on the VSphereVM to the given host,
+// or removes it if host is empty.
+func (r hostEvacuationReconciler) setCordonedAnnotation(ctx context.Context, vsphereVM *infrav1.VSphereVM, host string) error {
+	current, ok := vsphereVM.Annotations[infrav1.HostEvacuationDrainedAnnotation]
+	if (host == "" && !ok) || (host != "" && ok && current == host) {
+		return nil
+	}
+
+	patchBase := ctrlclient.MergeFrom(vsphereVM.DeepCopy())
+	if host == "" {
+		delete(vsphereVM.Annotations, infrav1.HostEvacuationDrainedAnnotation)
+	} else {
+		if vsphereVM.Annotations == nil {
+			vsphereVM.Annotations = map[string]string{}
+		}
+		vsphereVM.Annotations[infrav1.HostEvacuationDrainedAnnotation] = host
+	}
+	if err := r.Client.Patch(ctx, vsphereVM, patchBase); err != nil {
+		return errors.Wrapf(err, "failed to patch VSphereVM %s", klog.KObj(vsphereVM))
+	}
+	return nil
+}
+
+// drainNode evicts all Pods from the Node, except for DaemonSet and mirror Pods.
+// It returns true once there are no Pods left on the Node which have to be evicted.
+// Evictions blocked by PodDisruptionBudgets are retried on the next call.
+func drainNode(ctx context.Context, c ctrlclient.Client, nodeName string) (bool, error) {
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, ctrlclient.MatchingFields{"spec.nodeName": nodeName}); err != nil {
+		return false, errors.Wrap(err, "failed to list Pods")
+	}
+
+	errList := []error{}
+	drained := true
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if skipEviction(pod) {
+			continue
+		}
+		drained = false
+
+		// Wait for Pods which are already getting deleted.
+		if !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		eviction := &policyv1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      pod.Name,
+				Namespace: pod.Namespace,
+			},
+		}
+		if err := c.SubResource("eviction").Create(ctx, pod, eviction); err != nil {
+			// TooManyRequests is returned if the eviction is blocked by a PodDisruptionBudget.
+			if apierrors.IsNotFound(err) || apierrors.IsTooManyRequests(err) {
+				continue
+			}
+			errList = append(errList, errors.Wrapf(err, "failed to evict Pod %s", klog.KObj(pod)))
+		}
+	}
+
+	return drained, kerrors.NewAggregate(errList)
+}
+
+// skipEviction returns true for Pods which do not have to be evicted when draining a Node.
+func skipEviction(pod *corev1.Pod) bool {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return true
+	}
+	if _, ok := pod.Annotations[corev1.MirrorPodAnnotationKey]; ok {
+		return true
+	}
+	if controllerRef := metav1.GetControllerOf(pod); controllerRef != nil && controllerRef.Kind == "DaemonSet" {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/context/fake"
+)
+
+func Test_hostEvacuationReconciler_cordonAndUncordonNode(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctx := context.Background()
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node"}}
+	cordonedNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "cordoned-node"}, Spec: corev1.NodeSpec{Unschedulable: true}}
+	c := ctrlfake.NewClientBuilder().WithObjects(node, cordonedNode).Build()
+	vsphereVM := &infrav1.VSphereVM{ObjectMeta: metav1.ObjectMeta{Name: "vm", Namespace: "default"}}
+	r := hostEvacuationReconciler{
+		Recorder:                 record.NewFakeRecorder(10),
+		ControllerManagerContext: fake.NewControllerManagerContext(vsphereVM),
+	}
+
+	// Cordoning a schedulable Node sets the annotation on the Node and the VSphereVM.
+	g.Expect(r.cordonNode(ctx, c, vsphereVM, node, "host-0")).To(gomega.Succeed())
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(node), node)).To(gomega.Succeed())
+	g.Expect(node.Spec.Unschedulable).To(gomega.BeTrue())
+	g.Expect(node.Annotations).To(gomega.HaveKeyWithValue(infrav1.HostEvacuationDrainedAnnotation, "host-0"))
+	g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(vsphereVM), vsphereVM)).To(gomega.Succeed())
+	g.Expect(vsphereVM.Annotations).To(gomega.HaveKeyWithValue(infrav1.HostEvacuationDrainedAnnotation, "host-0"))
+
+	// Nodes cordoned by someone else are neither annotated nor uncordoned.
+	otherVM := &infrav1.VSphereVM{ObjectMeta: metav1.ObjectMeta{Name: "other-vm", Namespace: "default"}}
+	g.Expect(r.Client.Create(ctx, otherVM)).To(gomega.Succeed())
+	g.Expect(r.cordonNode(ctx, c, otherVM, cordonedNode, "host-0")).To(gomega.Succeed())
+	g.Expect(r.uncordonNode(ctx, c, otherVM, cordonedNode)).To(gomega.Succeed())
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(cordonedNode), cordonedNode)).To(gomega.Succeed())
+	g.Expect(cordonedNode.Spec.Unschedulable).To(gomega.BeTrue())
+	g.Expect(cordonedNode.Annotations).ToNot(gomega.HaveKey(infrav1.HostEvacuationDrainedAnnotation))
+	g.Expect(otherVM.Annotations).ToNot(gomega.HaveKey(infrav1.HostEvacuationDrainedAnnotation))
+
+	// Uncordoning removes the annotations.
+	g.Expect(r.uncordonNode(ctx, c, vsphereVM, node)).To(gomega.Succeed())
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(node), node)).To(gomega.Succeed())
+	g.Expect(node.Spec.Unschedulable).To(gomega.BeFalse())
+	g.Expect(node.Annotations).ToNot(gomega.HaveKey(infrav1.HostEvacuationDrainedAnnotation))
+	g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(vsphereVM), vsphereVM)).To(gomega.Succeed())
+	g.Expect(vsphereVM.Annotations).ToNot(gomega.HaveKey(infrav1.HostEvacuationDrainedAnnotation))
+}
+
+func Test_hostEvacuationReconciler_getNodeName(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctx := context.Background()
+
+	machine := &clusterv1.Machine{
+		TypeMeta:   metav1.TypeMeta{APIVersion: clusterv1.GroupVersion.String(), Kind: "Machine"},
+		ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "default"},
+	}
+	vsphereMachine := &infrav1.VSphereMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "vsphere-machine",
+			Namespace:       "default",
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: clusterv1.GroupVersion.String(), Kind: "Machine", Name: "machine"}},
+		},
+	}
+	vsphereVM := &infrav1.VSphereVM{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "vm",
+			Namespace:       "default",
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: infrav1.GroupVersion.String(), Kind: "VSphereMachine", Name: "vsphere-machine"}},
+		},
+	}
+	r := hostEvacuationReconciler{ControllerManagerContext: fake.NewControllerManagerContext(machine, vsphereMachine, vsphereVM)}
+
+	// The Node is skipped while the Machine has no NodeRef.
+	nodeName, err := r.getNodeName(ctx, vsphereVM)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(nodeName).To(gomega.BeEmpty())
+
+	// The name of the Node does not have to match the name of the VM.
+	machine.Status.NodeRef = &corev1.ObjectReference{Kind: "Node", Name: "node-0"}
+	g.Expect(r.Client.Update(ctx, machine)).To(gomega.Succeed())
+	nodeName, err = r.getNodeName(ctx, vsphereVM)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(nodeName).To(gomega.Equal("node-0"))
+}
+
+func Test_hostEvacuationReconciler_Reconcile_Disabled(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctx := context.Background()
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+		Spec: clusterv1.ClusterSpec{
+			InfrastructureRef: &corev1.ObjectReference{Kind: "VSphereCluster", Name: "vsphere-cluster"},
+		},
+	}
+	vsphereCluster := &infrav1.VSphereCluster{ObjectMeta: metav1.ObjectMeta{Name: "vsphere-cluster", Namespace: "default"}}
+	vsphereVM := &infrav1.VSphereVM{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "vm",
+			Namespace: "default",
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: "cluster"},
+		},
+		Status: infrav1.VSphereVMStatus{Ready: true},
+	}
+
+	// Without a cluster cache, the reconciler would panic if it accessed the workload cluster.
+	r := hostEvacuationReconciler{ControllerManagerContext: fake.NewControllerManagerContext(cluster, vsphereCluster, vsphereVM)}
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(vsphereVM)})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(result).To(gomega.Equal(reconcile.Result{}))
+}
+
+func Test_drainNode(t *testing.T) {
+	pod := func(name, nodeName string, modify ...func(*corev1.Pod)) *corev1.Pod {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+		}
+		for _, m := range modify {
+			m(p)
+		}
+		return p
+	}
+	daemonSetPod := func(p *corev1.Pod) {
+		p.OwnerReferences = []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "DaemonSet", Name: "ds", UID: "uid", Controller: ptr.To(true)}}
+	}
+	mirrorPod := func(p *corev1.Pod) {
+		p.Annotations = map[string]string{corev1.MirrorPodAnnotationKey: "hash"}
+	}
+	completedPod := func(p *corev1.Pod) {
+		p.Status.Phase = corev1.PodSucceeded
+	}
+
+	tests := []struct {
+		name        string
+		objects     []client.Object
+		wantDrained bool
+		wantPods    []string
+	}{
+		{
+			name:        "no Pods on the Node",
+			objects:     []client.Object{pod("other", "other-node")},
+			wantDrained: true,
+			wantPods:    []string{"other"},
+		},
+		{
+			name: "only Pods which are not evicted",
+			objects: []client.Object{
+				pod("daemonset", "node", daemonSetPod),
+				pod("mirror", "node", mirrorPod),
+				pod("completed", "node", completedPod),
+			},
+			wantDrained: true,
+			wantPods:    []string{"daemonset", "mirror", "completed"},
+		},
+		{
+			name: "Pods get evicted",
+			objects: []client.Object{
+				pod("workload", "node"),
+				pod("daemonset", "node", daemonSetPod),
+				pod("other", "other-node"),
+			},
+			wantDrained: false,
+			wantPods:    []string{"daemonset", "other"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			ctx := context.Background()
+
+			c := ctrlfake.NewClientBuilder().
+				WithObjects(tt.objects...).
+				WithIndex(&corev1.Pod{}, "spec.nodeName", func(o client.Object) []string {
+					return []string{o.(*corev1.Pod).Spec.NodeName}
+				}).
+				Build()
+
+			drained, err := drainNode(ctx, c, "node")
+			g.Expect(err).ToNot(gomega.HaveOccurred())
+			g.Expect(drained).To(gomega.Equal(tt.wantDrained))
+
+			pods := &corev1.PodList{}
+			g.Expect(c.List(ctx, pods)).To(gomega.Succeed())
+			podNames := []string{}
+			for _, p := range pods.Items {
+				podNames = append(podNames, p.Name)
+			}
+			g.Expect(podNames).To(gomega.ConsistOf(tt.wantPods))
+		})
+	}
+}
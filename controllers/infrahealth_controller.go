@@ -0,0 +1,218 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/vim25/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterutilv1 "sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/govmomi"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/session"
+)
+
+const (
+	// infraHealthSyncPeriod is the interval in which the triggered alarms are checked.
+	infraHealthSyncPeriod = 1 * time.Minute
+
+	// maxReportedAlarms is the maximum number of alarms listed in the message of the condition.
+	maxReportedAlarms = 5
+)
+
+// AddInfraHealthControllerToManager adds the infrastructure health controller to the provided manager.
+// The controller periodically checks the critical vCenter alarms triggered on the hosts, resource pools,
+// folders and datastores of the VMs of a VSphereCluster and summarizes them in the
+// VSphereClusterInfraHealthy condition.
+func AddInfraHealthControllerToManager(ctx context.Context, controllerManagerCtx *capvcontext.ControllerManagerContext, mgr manager.Manager, options controller.Options) error {
+	r := infraHealthReconciler{
+		ControllerManagerContext: controllerManagerCtx,
+	}
+	predicateLog := ctrl.LoggerFrom(ctx).WithValues("controller", "infrahealth")
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("infrahealth").
+		// Status updates are ignored, the alarms are checked periodically.
+		For(&infrav1.VSphereCluster{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(mgr.GetScheme(), predicateLog, controllerManagerCtx.WatchFilterValue)).
+		Complete(r)
+}
+
+type infraHealthReconciler struct {
+	*capvcontext.ControllerManagerContext
+}
+
+// Reconcile sets the VSphereClusterInfraHealthy condition of the VSphereCluster according to the
+// critical alarms triggered on the vSphere infrastructure used by its VMs.
+func (r infraHealthReconciler) Reconcile(ctx context.Context, req ctrl.Request) (reconcile.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	vsphereCluster := &infrav1.VSphereCluster{}
+	if err := r.Client.Get(ctx, req.NamespacedName, vsphereCluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if !vsphereCluster.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	cluster, err := clusterutilv1.GetOwnerCluster(ctx, r.Client, vsphereCluster.ObjectMeta)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if cluster == nil {
+		log.V(4).Info("Waiting for Cluster Controller to set OwnerRef on VSphereCluster")
+		return reconcile.Result{}, nil
+	}
+	log = log.WithValues("Cluster", klog.KObj(cluster))
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	if annotations.IsPaused(cluster, vsphereCluster) {
+		return reconcile.Result{}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(vsphereCluster, r.Client)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	alarms, reterr := r.getCriticalAlarms(ctx, cluster)
+	switch {
+	case reterr != nil:
+		conditions.MarkUnknown(vsphereCluster, infrav1.VSphereClusterInfraHealthyCondition, infrav1.AlarmsUnavailableReason, "%v", reterr)
+	case len(alarms) > 0:
+		conditions.MarkFalse(vsphereCluster, infrav1.VSphereClusterInfraHealthyCondition, infrav1.CriticalAlarmsTriggeredReason, clusterv1.ConditionSeverityWarning, "%s", criticalAlarmsMessage(alarms))
+	default:
+		conditions.MarkTrue(vsphereCluster, infrav1.VSphereClusterInfraHealthyCondition)
+	}
+
+	if err := patchHelper.Patch(ctx, vsphereCluster, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+		infrav1.VSphereClusterInfraHealthyCondition,
+	}}); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to patch VSphereCluster %s", klog.KObj(vsphereCluster))
+	}
+	if reterr != nil {
+		return reconcile.Result{}, reterr
+	}
+	return reconcile.Result{RequeueAfter: infraHealthSyncPeriod}, nil
+}
+
+// getCriticalAlarms returns the critical alarms triggered on the hosts, resource pools, folders
+// and datastores of the VMs of the cluster.
+func (r infraHealthReconciler) getCriticalAlarms(ctx context.Context, cluster *clusterv1.Cluster) ([]govmomi.TriggeredAlarm, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	vms := &infrav1.VSphereVMList{}
+	if err := r.Client.List(ctx, vms, ctrlclient.InNamespace(cluster.Namespace), ctrlclient.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name}); err != nil {
+		return nil, errors.Wrapf(err, "failed to list VSphereVMs of Cluster %s", klog.KObj(cluster))
+	}
+
+	// Sessions are shared by the VMs using the same vCenter and datacenter.
+	scopes := map[*session.Session][]types.ManagedObjectReference{}
+	seen := map[*session.Session]map[types.ManagedObjectReference]bool{}
+	for i := range vms.Items {
+		vsphereVM := &vms.Items[i]
+		if !vsphereVM.DeletionTimestamp.IsZero() || vsphereVM.Spec.BiosUUID == "" {
+			continue
+		}
+
+		authSession, err := vmReconciler{ControllerManagerContext: r.ControllerManagerContext}.retrieveVcenterSession(ctx, vsphereVM)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get vCenter session")
+		}
+		vmCtx := &capvcontext.VMContext{
+			ControllerManagerContext: r.ControllerManagerContext,
+			VSphereVM:                vsphereVM,
+			Session:                  authSession,
+		}
+		scope, err := govmomi.GetAlarmScope(ctx, vmCtx)
+		if err != nil {
+			log.V(4).Info("Skipping alarms of VSphereVM, failed to get the inventory objects of the VM", "VSphereVM", klog.KObj(vsphereVM), "err", err.Error())
+			continue
+		}
+
+		if seen[authSession] == nil {
+			seen[authSession] = map[types.ManagedObjectReference]bool{}
+		}
+		for _, ref := range scope {
+			if !seen[authSession][ref] {
+				seen[authSession][ref] = true
+				scopes[authSession] = append(scopes[authSession], ref)
+			}
+		}
+	}
+
+	alarms := []govmomi.TriggeredAlarm{}
+	reported := map[govmomi.TriggeredAlarm]bool{}
+	for authSession, scope := range scopes {
+		triggered, err := govmomi.GetCriticalAlarms(ctx, authSession, scope)
+		if err != nil {
+			return nil, err
+		}
+		for _, alarm := range triggered {
+			if !reported[alarm] {
+				reported[alarm] = true
+				alarms = append(alarms, alarm)
+			}
+		}
+	}
+	// Sort the alarms to keep the message of the condition stable.
+	sort.Slice(alarms, func(i, j int) bool {
+		if alarms[i].Entity != alarms[j].Entity {
+			return alarms[i].Entity < alarms[j].Entity
+		}
+		return alarms[i].Name < alarms[j].Name
+	})
+	return alarms, nil
+}
+
+// criticalAlarmsMessage returns the message of the VSphereClusterInfraHealthy condition
+// listing the given alarms.
+func criticalAlarmsMessage(alarms []govmomi.TriggeredAlarm) string {
+	listed := []string{}
+	for i := 0; i < len(alarms) && i < maxReportedAlarms; i++ {
+		listed = append(listed, fmt.Sprintf("%q on %s", alarms[i].Name, alarms[i].Entity))
+	}
+	if len(alarms) > maxReportedAlarms {
+		listed = append(listed, fmt.Sprintf("and %d more", len(alarms)-maxReportedAlarms))
+	}
+	return fmt.Sprintf("%d critical alarm(s) triggered: %s", len(alarms), strings.Join(listed, ", "))
+}
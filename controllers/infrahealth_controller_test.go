@@ -0,0 +1,45 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/govmomi"
+)
+
+func Test_criticalAlarmsMessage(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	alarms := []govmomi.TriggeredAlarm{
+		{Name: "Datastore usage on disk", Entity: "ds-0"},
+		{Name: "Host connection and power state", Entity: "esx-0"},
+	}
+	g.Expect(criticalAlarmsMessage(alarms)).To(gomega.Equal(
+		`2 critical alarm(s) triggered: "Datastore usage on disk" on ds-0, "Host connection and power state" on esx-0`))
+
+	alarms = nil
+	for i := 0; i < 7; i++ {
+		alarms = append(alarms, govmomi.TriggeredAlarm{Name: "Host hardware health", Entity: fmt.Sprintf("esx-%d", i)})
+	}
+	g.Expect(criticalAlarmsMessage(alarms)).To(gomega.Equal(`7 critical alarm(s) triggered: "Host hardware health" on esx-0, ` +
+		`"Host hardware health" on esx-1, "Host hardware health" on esx-2, "Host hardware health" on esx-3, ` +
+		`"Host hardware health" on esx-4, and 2 more`))
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/vim25/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterutilv1 "sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/govmomi"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/session"
+)
+
+// AddResourceUsageControllerToManager adds the resource usage controller to the provided manager.
+// The controller periodically aggregates the CPU, memory and storage of the VMs of a VSphereCluster
+// into its status.resourceUsage.
+func AddResourceUsageControllerToManager(ctx context.Context, controllerManagerCtx *capvcontext.ControllerManagerContext, mgr manager.Manager, options controller.Options) error {
+	r := resourceUsageReconciler{
+		ControllerManagerContext: controllerManagerCtx,
+	}
+	predicateLog := ctrl.LoggerFrom(ctx).WithValues("controller", "resourceusage")
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("resourceusage").
+		// Status updates are ignored, the resource usage is refreshed periodically.
+		For(&infrav1.VSphereCluster{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(mgr.GetScheme(), predicateLog, controllerManagerCtx.WatchFilterValue)).
+		Complete(r)
+}
+
+type resourceUsageReconciler struct {
+	*capvcontext.ControllerManagerContext
+}
+
+// Reconcile refreshes the resource usage of the VSphereCluster if it is older than the refresh interval.
+func (r resourceUsageReconciler) Reconcile(ctx context.Context, req ctrl.Request) (reconcile.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	vsphereCluster := &infrav1.VSphereCluster{}
+	if err := r.Client.Get(ctx, req.NamespacedName, vsphereCluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if !vsphereCluster.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	cluster, err := clusterutilv1.GetOwnerCluster(ctx, r.Client, vsphereCluster.ObjectMeta)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if cluster == nil {
+		log.V(4).Info("Waiting for Cluster Controller to set OwnerRef on VSphereCluster")
+		return reconcile.Result{}, nil
+	}
+	log = log.WithValues("Cluster", klog.KObj(cluster))
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	if annotations.IsPaused(cluster, vsphereCluster) {
+		return reconcile.Result{}, nil
+	}
+
+	// The VSphereCluster is also reconciled on spec changes, which must not refresh the resource usage
+	// more often than the refresh interval.
+	if requeueAfter := timeUntilResourceUsageRefresh(vsphereCluster.Status.ResourceUsage, r.ResourceUsageRefreshInterval, time.Now()); requeueAfter > 0 {
+		return reconcile.Result{RequeueAfter: requeueAfter}, nil
+	}
+
+	usage, err := r.getResourceUsage(ctx, cluster)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	patchHelper, err := patch.NewHelper(vsphereCluster, r.Client)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	vsphereCluster.Status.ResourceUsage = newResourceUsageStatus(usage, metav1.Now())
+	if err := patchHelper.Patch(ctx, vsphereCluster); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to patch VSphereCluster %s", klog.KObj(vsphereCluster))
+	}
+	return reconcile.Result{RequeueAfter: r.ResourceUsageRefreshInterval}, nil
+}
+
+// getResourceUsage returns the resource usage aggregated over the VMs of the cluster.
+func (r resourceUsageReconciler) getResourceUsage(ctx context.Context, cluster *clusterv1.Cluster) (govmomi.ResourceUsage, error) {
+	vms := &infrav1.VSphereVMList{}
+	if err := r.Client.List(ctx, vms, ctrlclient.InNamespace(cluster.Namespace), ctrlclient.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name}); err != nil {
+		return govmomi.ResourceUsage{}, errors.Wrapf(err, "failed to list VSphereVMs of Cluster %s", klog.KObj(cluster))
+	}
+
+	// Sessions are shared by the VMs using the same vCenter and datacenter.
+	refs := map[*session.Session][]types.ManagedObjectReference{}
+	for i := range vms.Items {
+		vsphereVM := &vms.Items[i]
+		if !vsphereVM.DeletionTimestamp.IsZero() || vsphereVM.Status.VMRef == "" {
+			continue
+		}
+
+		authSession, err := vmReconciler{ControllerManagerContext: r.ControllerManagerContext}.retrieveVcenterSession(ctx, vsphereVM)
+		if err != nil {
+			return govmomi.ResourceUsage{}, errors.Wrap(err, "failed to get vCenter session")
+		}
+		refs[authSession] = append(refs[authSession], types.ManagedObjectReference{Type: "VirtualMachine", Value: vsphereVM.Status.VMRef})
+	}
+
+	usage := govmomi.ResourceUsage{}
+	for authSession, vmRefs := range refs {
+		sessionUsage, err := govmomi.GetResourceUsage(ctx, authSession, vmRefs)
+		if err != nil {
+			return govmomi.ResourceUsage{}, err
+		}
+		usage.Add(sessionUsage)
+	}
+	return usage, nil
+}
+
+// timeUntilResourceUsageRefresh returns the time until the resource usage has to be refreshed,
+// or 0 if it has to be refreshed now.
+func timeUntilResourceUsageRefresh(usage *infrav1.VSphereClusterResourceUsage, interval time.Duration, now time.Time) time.Duration {
+	if usage == nil {
+		return 0
+	}
+	if next := usage.LastUpdated.Add(interval); now.Before(next) {
+		return next.Sub(now)
+	}
+	return 0
+}
+
+// newResourceUsageStatus returns the status.resourceUsage of a VSphereCluster for the given resource usage.
+func newResourceUsageStatus(usage govmomi.ResourceUsage, now metav1.Time) *infrav1.VSphereClusterResourceUsage {
+	return &infrav1.VSphereClusterResourceUsage{
+		Machines:    usage.VMs,
+		CPU:         *resource.NewQuantity(usage.NumCPUs, resource.DecimalSI),
+		Memory:      *resource.NewQuantity(usage.MemoryMiB*1024*1024, resource.BinarySI),
+		Storage:     *resource.NewQuantity(usage.StorageCommitted, resource.BinarySI),
+		LastUpdated: now,
+	}
+}
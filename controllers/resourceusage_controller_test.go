@@ -0,0 +1,59 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/govmomi"
+)
+
+func Test_timeUntilResourceUsageRefresh(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	now := time.Now()
+	g.Expect(timeUntilResourceUsageRefresh(nil, 5*time.Minute, now)).To(gomega.BeZero())
+
+	usage := &infrav1.VSphereClusterResourceUsage{LastUpdated: metav1.NewTime(now.Add(-2 * time.Minute))}
+	g.Expect(timeUntilResourceUsageRefresh(usage, 5*time.Minute, now)).To(gomega.Equal(3 * time.Minute))
+
+	usage = &infrav1.VSphereClusterResourceUsage{LastUpdated: metav1.NewTime(now.Add(-5 * time.Minute))}
+	g.Expect(timeUntilResourceUsageRefresh(usage, 5*time.Minute, now)).To(gomega.BeZero())
+}
+
+func Test_newResourceUsageStatus(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	now := metav1.Now()
+	status := newResourceUsageStatus(govmomi.ResourceUsage{
+		VMs:              3,
+		NumCPUs:          12,
+		MemoryMiB:        3 * 8192,
+		StorageCommitted: 3 * 40 * 1024 * 1024 * 1024,
+	}, now)
+	g.Expect(status.Machines).To(gomega.Equal(int32(3)))
+	g.Expect(status.CPU.Equal(resource.MustParse("12"))).To(gomega.BeTrue())
+	g.Expect(status.Memory.Equal(resource.MustParse("24Gi"))).To(gomega.BeTrue())
+	g.Expect(status.Storage.Equal(resource.MustParse("120Gi"))).To(gomega.BeTrue())
+	g.Expect(status.LastUpdated).To(gomega.Equal(now))
+}
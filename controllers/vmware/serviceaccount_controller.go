@@ -18,6 +18,8 @@ package vmware
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -29,6 +31,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -55,11 +58,19 @@ import (
 // +kubebuilder:rbac:groups=vmware.infrastructure.cluster.x-k8s.io,resources=providerserviceaccounts,verbs=get;list;watch;
 // +kubebuilder:rbac:groups=vmware.infrastructure.cluster.x-k8s.io,resources=providerserviceaccounts/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups="",resources=secrets,verbs=get
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;delete
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;list;watch;create;update;patch;delete
 
 const (
 	kindProviderServiceAccount = "ProviderServiceAccount"
+
+	// legacyTokenInvalidSinceLabel is set by the kube-controller-manager on ServiceAccount token secrets
+	// when the token they contain has been invalidated.
+	legacyTokenInvalidSinceLabel = "kubernetes.io/legacy-token-invalid-since"
+
+	// serviceAccountTokenRenewBefore is the duration before the expiry of a ServiceAccount token at which it is
+	// rotated, if the token does not include the time it has been issued at.
+	serviceAccountTokenRenewBefore = 10 * time.Minute
 )
 
 // AddServiceAccountProviderControllerToManager adds this controller to the provided manager.
@@ -194,8 +205,6 @@ func (r *ServiceAccountReconciler) reconcileNormal(ctx context.Context, guestClu
 		if reterr != nil {
 			conditions.MarkFalse(guestClusterCtx.VSphereCluster, vmwarev1.ProviderServiceAccountsReadyCondition, vmwarev1.ProviderServiceAccountsReconciliationFailedReason,
 				clusterv1.ConditionSeverityWarning, reterr.Error())
-		} else {
-			conditions.MarkTrue(guestClusterCtx.VSphereCluster, vmwarev1.ProviderServiceAccountsReadyCondition)
 		}
 	}()
 
@@ -204,23 +213,42 @@ func (r *ServiceAccountReconciler) reconcileNormal(ctx context.Context, guestClu
 		return reconcile.Result{}, errors.Wrapf(err, "failed to get ProviderServiceAccounts")
 	}
 
-	err = r.ensureProviderServiceAccounts(ctx, guestClusterCtx, pSvcAccounts)
+	waitingForToken, requeueAfter, err := r.ensureProviderServiceAccounts(ctx, guestClusterCtx, pSvcAccounts)
 	if err != nil {
 		return reconcile.Result{}, errors.Wrapf(err, "failed to ensure ProviderServiceAccounts")
 	}
 
-	return reconcile.Result{}, nil
+	if err := r.deleteStaleTargetSecrets(ctx, guestClusterCtx, pSvcAccounts); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to delete stale ProviderServiceAccount secrets in workload cluster")
+	}
+
+	if len(waitingForToken) > 0 {
+		// Note: We don't have to requeue here because we have a watch on the secrets and the cluster is reconciled
+		// when the token controller populated them.
+		conditions.MarkFalse(guestClusterCtx.VSphereCluster, vmwarev1.ProviderServiceAccountsReadyCondition, vmwarev1.WaitingForServiceAccountTokenReason,
+			clusterv1.ConditionSeverityInfo, "Waiting for ServiceAccount token of ProviderServiceAccounts %s", strings.Join(waitingForToken, ", "))
+	} else {
+		conditions.MarkTrue(guestClusterCtx.VSphereCluster, vmwarev1.ProviderServiceAccountsReadyCondition)
+	}
+
+	// Requeue to rotate the ServiceAccount tokens before they expire.
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
 }
 
 // Ensure service accounts from provider spec is created.
-func (r *ServiceAccountReconciler) ensureProviderServiceAccounts(ctx context.Context, guestClusterCtx *vmwarecontext.GuestClusterContext, pSvcAccounts []vmwarev1.ProviderServiceAccount) error {
+// It returns the names of the ProviderServiceAccounts which are waiting for their ServiceAccount token and the
+// duration after which the first ServiceAccount token has to be rotated.
+func (r *ServiceAccountReconciler) ensureProviderServiceAccounts(ctx context.Context, guestClusterCtx *vmwarecontext.GuestClusterContext, pSvcAccounts []vmwarev1.ProviderServiceAccount) ([]string, time.Duration, error) {
 	log := ctrl.LoggerFrom(ctx)
 
+	var waitingForToken []string
+	var requeueAfter time.Duration
+
 	pSvcAccountNames := []string{}
 	for _, pSvcAccount := range pSvcAccounts {
 		pSvcAccountNames = append(pSvcAccountNames, pSvcAccount.Name)
 	}
-	log.V(5).Info(fmt.Sprintf("Reconcile ProviderServiceAccounts: %v", strings.Join(pSvcAccountNames, ",")))
+	log.V(5).Info("Reconciling ProviderServiceAccounts", "ProviderServiceAccounts", pSvcAccountNames)
 
 	for i, pSvcAccount := range pSvcAccounts {
 		// Note: We have to use := here to not overwrite log & ctx outside the for loop.
@@ -234,30 +262,43 @@ func (r *ServiceAccountReconciler) ensureProviderServiceAccounts(ctx context.Con
 
 		// 1. Ensure ServiceAccount in the mgmt cluster with the same name as the ProviderServiceAccount
 		if err := r.ensureServiceAccount(ctx, pSvcAccount); err != nil {
-			return errors.Wrapf(err, "failed to ensure ServiceAccount %s", pSvcAccount.Name)
+			return nil, 0, errors.Wrapf(err, "failed to ensure ServiceAccount %s", pSvcAccount.Name)
 		}
 
 		// 2. Ensure secret of ServiceAccountToken type for the ServiceAccount
 		if err := r.ensureServiceAccountSecret(ctx, pSvcAccount); err != nil {
-			return errors.Wrapf(err, "failed to ensure ServiceAcountToken secret %s", getServiceAccountSecretName(pSvcAccount))
+			return nil, 0, errors.Wrapf(err, "failed to ensure ServiceAcountToken secret %s", getServiceAccountSecretName(pSvcAccount))
 		}
 
 		// 3. Ensure the associated Role for the ServiceAccount
 		if err := r.ensureRole(ctx, pSvcAccount); err != nil {
-			return errors.Wrapf(err, "failed to ensure Role for ServiceAccount %s", pSvcAccount.Name)
+			return nil, 0, errors.Wrapf(err, "failed to ensure Role for ServiceAccount %s", pSvcAccount.Name)
 		}
 
 		// 4. Ensure the associated RoleBinding for the ServiceAccount
 		if err := r.ensureRoleBinding(ctx, pSvcAccount); err != nil {
-			return errors.Wrapf(err, "failed to ensure RoleBinding for ServiceAccount %s", pSvcAccount.Name)
+			return nil, 0, errors.Wrapf(err, "failed to ensure RoleBinding for ServiceAccount %s", pSvcAccount.Name)
 		}
 
-		// 5. Sync the ServiceAccount secret to the workload cluster
-		if err := r.syncServiceAccountSecret(ctx, guestClusterCtx, pSvcAccount); err != nil {
-			return errors.Wrapf(err, "failed to sync secret for ProviderServiceAccount %s to workload cluster", pSvcAccount.Name)
+		// 5. Ensure the ServiceAccount token is valid and rotate it before it expires
+		svcAccountTokenSecret, rotateAfter, err := r.ensureServiceAccountToken(ctx, pSvcAccount)
+		if err != nil {
+			return nil, 0, errors.Wrapf(err, "failed to ensure ServiceAccount token for ProviderServiceAccount %s", pSvcAccount.Name)
+		}
+		if svcAccountTokenSecret == nil {
+			waitingForToken = append(waitingForToken, pSvcAccount.Name)
+			continue
+		}
+		if rotateAfter > 0 && (requeueAfter == 0 || rotateAfter < requeueAfter) {
+			requeueAfter = rotateAfter
+		}
+
+		// 6. Sync the ServiceAccount secret to the workload cluster
+		if err := r.syncServiceAccountSecret(ctx, guestClusterCtx, pSvcAccount, svcAccountTokenSecret); err != nil {
+			return nil, 0, errors.Wrapf(err, "failed to sync secret for ProviderServiceAccount %s to workload cluster", pSvcAccount.Name)
 		}
 	}
-	return nil
+	return waitingForToken, requeueAfter, nil
 }
 
 func (r *ServiceAccountReconciler) ensureServiceAccount(ctx context.Context, pSvcAccount vmwarev1.ProviderServiceAccount) error {
@@ -416,28 +457,91 @@ func (r *ServiceAccountReconciler) ensureRoleBinding(ctx context.Context, pSvcAc
 	return nil
 }
 
-func (r *ServiceAccountReconciler) syncServiceAccountSecret(ctx context.Context, guestClusterCtx *vmwarecontext.GuestClusterContext, pSvcAccount vmwarev1.ProviderServiceAccount) error {
+// ensureServiceAccountToken returns the ServiceAccount token secret of the ProviderServiceAccount and the duration
+// after which its token has to be rotated. Tokens which have been invalidated or which are about to expire are
+// rotated by deleting and re-creating the secret, so the token controller issues a new token.
+// It returns a nil secret if the secret does not contain a valid token yet.
+func (r *ServiceAccountReconciler) ensureServiceAccountToken(ctx context.Context, pSvcAccount vmwarev1.ProviderServiceAccount) (*corev1.Secret, time.Duration, error) {
 	log := ctrl.LoggerFrom(ctx)
 
 	secretName := getServiceAccountSecretName(pSvcAccount)
-	log = log.WithValues("SourceSecret", klog.KRef(pSvcAccount.Namespace, secretName))
+	log = log.WithValues("Secret", klog.KRef(pSvcAccount.Namespace, secretName))
 	ctx = ctrl.LoggerInto(ctx, log)
 
-	log.V(4).Info("Attempting to sync ServiceAccount token secret for ProviderServiceAccount")
+	svcAccountTokenSecret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: secretName, Namespace: pSvcAccount.Namespace}, svcAccountTokenSecret); err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to get ServiceAccount token secret %s", klog.KRef(pSvcAccount.Namespace, secretName))
+	}
+	// Check if token data exists
+	token := svcAccountTokenSecret.Data[corev1.ServiceAccountTokenKey]
+	if len(token) == 0 {
+		log.Info("Waiting for ServiceAccount token secret to be populated by the token controller")
+		return nil, 0, nil
+	}
+
+	rotationTime := getServiceAccountTokenRotationTime(token)
+	if _, invalid := svcAccountTokenSecret.Labels[legacyTokenInvalidSinceLabel]; !invalid {
+		if rotationTime.IsZero() {
+			return svcAccountTokenSecret, 0, nil
+		}
+		if rotateAfter := time.Until(rotationTime); rotateAfter > 0 {
+			return svcAccountTokenSecret, rotateAfter, nil
+		}
+	}
+
+	// Note: The target secret in the workload cluster keeps the current token until the token controller
+	// populated the new secret.
+	if invalidSince, ok := svcAccountTokenSecret.Labels[legacyTokenInvalidSinceLabel]; ok {
+		log.Info(fmt.Sprintf("Rotating ServiceAccount token: token is invalid since %s", invalidSince))
+	} else {
+		log.Info(fmt.Sprintf("Rotating ServiceAccount token: token is due for rotation since %s", rotationTime.Format(time.RFC3339)))
+	}
+	if err := r.Client.Delete(ctx, svcAccountTokenSecret); err != nil && !apierrors.IsNotFound(err) {
+		return nil, 0, errors.Wrapf(err, "failed to delete ServiceAccount token secret %s", klog.KObj(svcAccountTokenSecret))
+	}
+	if err := r.ensureServiceAccountSecret(ctx, pSvcAccount); err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to re-create ServiceAccount token secret %s", klog.KObj(svcAccountTokenSecret))
+	}
+	return nil, 0, nil
+}
 
-	var svcAccountTokenSecret corev1.Secret
-	err := r.Client.Get(ctx, types.NamespacedName{Name: secretName, Namespace: pSvcAccount.Namespace}, &svcAccountTokenSecret)
+// getServiceAccountTokenRotationTime returns the time at which a ServiceAccount token has to be rotated.
+// It returns the zero time if the token does not expire, which is the case for legacy ServiceAccount tokens.
+// Note: The token is not verified, as it is only used to schedule the rotation.
+func getServiceAccountTokenRotationTime(token []byte) time.Time {
+	parts := strings.Split(string(token), ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
 	if err != nil {
-		return errors.Wrapf(err, "failed to get ServiceAccount token secret %s", klog.KRef(pSvcAccount.Namespace, secretName))
+		return time.Time{}
 	}
-	// Check if token data exists
-	if len(svcAccountTokenSecret.Data) == 0 {
-		// Note: We don't have to requeue here because we have a watch on the secret and the cluster should be reconciled
-		// when a secret has token data populated.
-		log.Info("Skipping sync secret for ProviderServiceAccount: ServiceAccount token secret has no data")
-		return nil
+	claims := struct {
+		IssuedAt int64 `json:"iat"`
+		Expiry   int64 `json:"exp"`
+	}{}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Expiry == 0 {
+		return time.Time{}
 	}
 
+	expiry := time.Unix(claims.Expiry, 0)
+	if claims.IssuedAt == 0 || claims.IssuedAt >= claims.Expiry {
+		return expiry.Add(-serviceAccountTokenRenewBefore)
+	}
+	// Rotate the token after 80% of its lifetime, like the kubelet does for projected ServiceAccount tokens.
+	issuedAt := time.Unix(claims.IssuedAt, 0)
+	return issuedAt.Add(expiry.Sub(issuedAt) * 4 / 5)
+}
+
+func (r *ServiceAccountReconciler) syncServiceAccountSecret(ctx context.Context, guestClusterCtx *vmwarecontext.GuestClusterContext, pSvcAccount vmwarev1.ProviderServiceAccount, svcAccountTokenSecret *corev1.Secret) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	log = log.WithValues("SourceSecret", klog.KObj(svcAccountTokenSecret))
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	log.V(4).Info("Attempting to sync ServiceAccount token secret for ProviderServiceAccount")
+
 	// Create the target namespace if it is not existing
 	targetNamespace := &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
@@ -445,10 +549,9 @@ func (r *ServiceAccountReconciler) syncServiceAccountSecret(ctx context.Context,
 		},
 	}
 
-	if err = guestClusterCtx.GuestClient.Get(ctx, client.ObjectKey{Name: pSvcAccount.Spec.TargetNamespace}, targetNamespace); err != nil {
+	if err := guestClusterCtx.GuestClient.Get(ctx, client.ObjectKey{Name: pSvcAccount.Spec.TargetNamespace}, targetNamespace); err != nil {
 		if apierrors.IsNotFound(err) {
-			err = guestClusterCtx.GuestClient.Create(ctx, targetNamespace)
-			if err != nil {
+			if err := guestClusterCtx.GuestClient.Create(ctx, targetNamespace); err != nil {
 				return errors.Wrapf(err, "failed to create Namespace %s in workload cluster", targetNamespace.Name)
 			}
 		} else {
@@ -463,13 +566,46 @@ func (r *ServiceAccountReconciler) syncServiceAccountSecret(ctx context.Context,
 		},
 	}
 	log.Info("Creating or patching Secret in workload cluster", "TargetSecret", klog.KObj(targetSecret))
-	_, err = controllerutil.CreateOrPatch(ctx, guestClusterCtx.GuestClient, targetSecret, func() error {
+	_, err := controllerutil.CreateOrPatch(ctx, guestClusterCtx.GuestClient, targetSecret, func() error {
+		if targetSecret.Labels == nil {
+			targetSecret.Labels = map[string]string{}
+		}
+		targetSecret.Labels[vmwarev1.ProviderServiceAccountTargetSecretLabel] = ""
 		targetSecret.Data = svcAccountTokenSecret.Data
 		return nil
 	})
 	return err
 }
 
+// deleteStaleTargetSecrets deletes the secrets in the workload cluster which have been synced for
+// ProviderServiceAccounts which have been deleted or which changed their target.
+func (r *ServiceAccountReconciler) deleteStaleTargetSecrets(ctx context.Context, guestClusterCtx *vmwarecontext.GuestClusterContext, pSvcAccounts []vmwarev1.ProviderServiceAccount) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	targetSecrets := sets.New[string]()
+	for _, pSvcAccount := range pSvcAccounts {
+		targetSecrets.Insert(types.NamespacedName{Namespace: pSvcAccount.Spec.TargetNamespace, Name: pSvcAccount.Spec.TargetSecretName}.String())
+	}
+
+	secretList := &corev1.SecretList{}
+	if err := guestClusterCtx.GuestClient.List(ctx, secretList, client.HasLabels{vmwarev1.ProviderServiceAccountTargetSecretLabel}); err != nil {
+		return errors.Wrapf(err, "failed to list ProviderServiceAccount secrets in workload cluster")
+	}
+
+	var errs []error
+	for i := range secretList.Items {
+		secret := &secretList.Items[i]
+		if targetSecrets.Has(client.ObjectKeyFromObject(secret).String()) {
+			continue
+		}
+		log.Info("Deleting stale ProviderServiceAccount secret in workload cluster", "TargetSecret", klog.KObj(secret))
+		if err := guestClusterCtx.GuestClient.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, errors.Wrapf(err, "failed to delete Secret %s in workload cluster", klog.KObj(secret)))
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
 func (r *ServiceAccountReconciler) getProviderServiceAccounts(ctx context.Context, clusterCtx *vmwarecontext.ClusterContext) ([]vmwarev1.ProviderServiceAccount, error) {
 	var pSvcAccounts []vmwarev1.ProviderServiceAccount
 
@@ -479,9 +615,9 @@ func (r *ServiceAccountReconciler) getProviderServiceAccounts(ctx context.Contex
 	}
 
 	for _, pSvcAccount := range pSvcAccountList.Items {
-		// step to clean up the target secret in the guest cluster. Note: when the provider service account is deleted
-		// all the associated serviceaccounts are deleted. Hence, the bearer token in the target
-		// secret will be rendered invalid. Still, it's a good practice to delete the secret that we created.
+		// Note: when the provider service account is deleted all the associated serviceaccounts are deleted.
+		// Hence, the bearer token in the target secret will be rendered invalid. The target secret in the
+		// guest cluster is cleaned up in deleteStaleTargetSecrets.
 		if pSvcAccount.DeletionTimestamp != nil {
 			continue
 		}
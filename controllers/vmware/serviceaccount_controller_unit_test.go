@@ -18,11 +18,16 @@ package vmware
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capiutil "sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -84,6 +89,10 @@ func unitTestsReconcileNormal() {
 
 			secret := &corev1.Secret{}
 			assertEventuallyExistsInNamespace(ctx, controllerCtx.ControllerManagerContext.Client, namespace, fmt.Sprintf("%s-secret", vsphereCluster.GetName()), secret)
+
+			By("Waiting for the token controller to populate the secret")
+			assertProviderServiceAccountsCondition(controllerCtx.VSphereCluster, corev1.ConditionFalse, vsphereCluster.GetName(),
+				vmwarev1.WaitingForServiceAccountTokenReason, clusterv1.ConditionSeverityInfo)
 		})
 		Context("When serviceaccount secret is created", func() {
 			It("Should reconcile", func() {
@@ -105,13 +114,59 @@ func unitTestsReconcileNormal() {
 				assertProviderServiceAccountsCondition(controllerCtx.VSphereCluster, corev1.ConditionTrue, "", "", "")
 			})
 		})
+		Context("When serviceaccount token is invalidated", func() {
+			It("Should rotate the token", func() {
+				updateServiceAccountSecretAndReconcileNormal(ctx, controllerCtx, reconciler, vsphereCluster)
+				assertTargetSecret(ctx, controllerCtx.GuestClient, testTargetNS, testTargetSecret)
+
+				secret := &corev1.Secret{}
+				assertEventuallyExistsInNamespace(ctx, controllerCtx.ControllerManagerContext.Client, namespace, fmt.Sprintf("%s-secret", vsphereCluster.GetName()), secret)
+				secret.Labels = map[string]string{legacyTokenInvalidSinceLabel: "2024-01-01"}
+				Expect(controllerCtx.ControllerManagerContext.Client.Update(ctx, secret)).To(Succeed())
+
+				_, err := reconciler.reconcileNormal(ctx, controllerCtx.GuestClusterContext)
+				Expect(err).NotTo(HaveOccurred())
+
+				By("Re-creating the serviceaccount secret")
+				secret = &corev1.Secret{}
+				assertEventuallyExistsInNamespace(ctx, controllerCtx.ControllerManagerContext.Client, namespace, fmt.Sprintf("%s-secret", vsphereCluster.GetName()), secret)
+				Expect(secret.Labels).NotTo(HaveKey(legacyTokenInvalidSinceLabel))
+				Expect(secret.Data).To(BeEmpty())
+
+				By("Keeping the target secret until the new token is populated")
+				assertTargetSecret(ctx, controllerCtx.GuestClient, testTargetNS, testTargetSecret)
+				assertProviderServiceAccountsCondition(controllerCtx.VSphereCluster, corev1.ConditionFalse, vsphereCluster.GetName(),
+					vmwarev1.WaitingForServiceAccountTokenReason, clusterv1.ConditionSeverityInfo)
+			})
+		})
+		Context("When the target secret is changed", func() {
+			It("Should delete the stale target secret", func() {
+				updateServiceAccountSecretAndReconcileNormal(ctx, controllerCtx, reconciler, vsphereCluster)
+				assertTargetSecret(ctx, controllerCtx.GuestClient, testTargetNS, testTargetSecret)
+
+				pSvcAccount := &vmwarev1.ProviderServiceAccount{}
+				assertEventuallyExistsInNamespace(ctx, controllerCtx.ControllerManagerContext.Client, namespace, vsphereCluster.GetName(), pSvcAccount)
+				pSvcAccount.Spec.TargetSecretName = "new-" + testTargetSecret
+				Expect(controllerCtx.ControllerManagerContext.Client.Update(ctx, pSvcAccount)).To(Succeed())
+
+				_, err := reconciler.reconcileNormal(ctx, controllerCtx.GuestClusterContext)
+				Expect(err).NotTo(HaveOccurred())
+
+				By("Creating the new target secret and deleting the stale one")
+				assertTargetSecret(ctx, controllerCtx.GuestClient, testTargetNS, "new-"+testTargetSecret)
+				err = controllerCtx.GuestClient.Get(ctx, client.ObjectKey{Namespace: testTargetNS, Name: testTargetSecret}, &corev1.Secret{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+				assertProviderServiceAccountsCondition(controllerCtx.VSphereCluster, corev1.ConditionTrue, "", "", "")
+			})
+		})
 		Context("When invalid role exists", func() {
 			BeforeEach(func() {
 				initObjects = append(initObjects, getTestRoleWithGetPod(namespace, vsphereCluster.GetName()))
 			})
 			It("Should update role", func() {
 				assertRoleWithGetPVC(ctx, controllerCtx.ControllerManagerContext.Client, namespace, vsphereCluster.GetName())
-				assertProviderServiceAccountsCondition(controllerCtx.VSphereCluster, corev1.ConditionTrue, "", "", "")
+				assertProviderServiceAccountsCondition(controllerCtx.VSphereCluster, corev1.ConditionFalse, vsphereCluster.GetName(),
+					vmwarev1.WaitingForServiceAccountTokenReason, clusterv1.ConditionSeverityInfo)
 			})
 		})
 		Context("When invalid rolebinding exists", func() {
@@ -120,7 +175,8 @@ func unitTestsReconcileNormal() {
 			})
 			It("Should update rolebinding", func() {
 				assertRoleBinding(ctx, controllerCtx.ControllerManagerContext.Client, namespace, vsphereCluster.GetName())
-				assertProviderServiceAccountsCondition(controllerCtx.VSphereCluster, corev1.ConditionTrue, "", "", "")
+				assertProviderServiceAccountsCondition(controllerCtx.VSphereCluster, corev1.ConditionFalse, vsphereCluster.GetName(),
+					vmwarev1.WaitingForServiceAccountTokenReason, clusterv1.ConditionSeverityInfo)
 			})
 		})
 	})
@@ -133,3 +189,43 @@ func updateServiceAccountSecretAndReconcileNormal(ctx context.Context, controlle
 	_, err := reconciler.reconcileNormal(ctx, controllerCtx.GuestClusterContext)
 	Expect(err).NotTo(HaveOccurred())
 }
+
+func Test_getServiceAccountTokenRotationTime(t *testing.T) {
+	jwt := func(payload string) []byte {
+		return []byte("e30." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".c2lnbmF0dXJl")
+	}
+	issuedAt := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name  string
+		token []byte
+		want  time.Time
+	}{
+		{
+			name:  "token which is not a JWT",
+			token: []byte(testSecretToken),
+			want:  time.Time{},
+		},
+		{
+			name:  "legacy token without expiry",
+			token: jwt(`{"sub":"system:serviceaccount:default:test"}`),
+			want:  time.Time{},
+		},
+		{
+			name:  "token with issue and expiry time",
+			token: jwt(fmt.Sprintf(`{"iat":%d,"exp":%d}`, issuedAt.Unix(), issuedAt.Add(10*time.Hour).Unix())),
+			want:  issuedAt.Add(8 * time.Hour),
+		},
+		{
+			name:  "token with expiry time only",
+			token: jwt(fmt.Sprintf(`{"exp":%d}`, issuedAt.Unix())),
+			want:  issuedAt.Add(-serviceAccountTokenRenewBefore),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(getServiceAccountTokenRotationTime(tt.token)).To(Equal(tt.want))
+		})
+	}
+}
@@ -80,6 +80,8 @@ func AddServiceDiscoveryControllerToManager(ctx context.Context, controllerManag
 		Client:       controllerManagerCtx.Client,
 		Recorder:     mgr.GetEventRecorderFor("servicediscovery/vspherecluster-controller"),
 		clusterCache: clusterCache,
+
+		supervisorAPIServerEndpoints: controllerManagerCtx.SupervisorAPIServerEndpoints,
 	}
 	predicateLog := ctrl.LoggerFrom(ctx).WithValues("controller", "servicediscovery/vspherecluster")
 
@@ -150,6 +152,10 @@ type serviceDiscoveryReconciler struct {
 	Recorder record.EventRecorder
 
 	clusterCache clustercache.ClusterCache
+
+	// supervisorAPIServerEndpoints are advertised to the workload clusters instead
+	// of the discovered Supervisor API server endpoint, if set.
+	supervisorAPIServerEndpoints []string
 }
 
 func (r *serviceDiscoveryReconciler) Reconcile(ctx context.Context, req reconcile.Request) (_ reconcile.Result, reterr error) {
@@ -259,7 +265,7 @@ func (r *serviceDiscoveryReconciler) reconcileSupervisorHeadlessService(ctx cont
 		}
 	}
 
-	supervisorHost, err := r.getSupervisorAPIServerAddress(ctx)
+	supervisorHosts, err := r.getSupervisorAPIServerAddresses(ctx)
 	if err != nil {
 		// Note: We have watches on the LB Svc (VIP) & the cluster-info configmap (FIP).
 		// There is no need to return an error to keep re-trying.
@@ -268,10 +274,10 @@ func (r *serviceDiscoveryReconciler) reconcileSupervisorHeadlessService(ctx cont
 		return nil
 	}
 
-	log.Info("Discovered supervisor API server endpoint", "host", supervisorHost, "port", supervisorPort)
-	// CreateOrPatch the newEndpoints with the discovered supervisor api server address
+	log.Info("Discovered supervisor API server endpoints", "hosts", supervisorHosts, "port", supervisorPort)
+	// CreateOrPatch the newEndpoints with the discovered supervisor api server addresses
 	newEndpoints := newSupervisorHeadlessServiceEndpoints(
-		supervisorHost,
+		supervisorHosts,
 		supervisorPort,
 	)
 	endpointsKey := types.NamespacedName{
@@ -317,21 +323,26 @@ func (r *serviceDiscoveryReconciler) reconcileSupervisorHeadlessService(ctx cont
 	return nil
 }
 
-func (r *serviceDiscoveryReconciler) getSupervisorAPIServerAddress(ctx context.Context) (string, error) {
-	// Discover the supervisor api server address
-	// 1. Check if a k8s service "kube-system/kube-apiserver-lb-svc" is available, if so, fetch the loadbalancer IP.
+func (r *serviceDiscoveryReconciler) getSupervisorAPIServerAddresses(ctx context.Context) ([]string, error) {
+	// Use the supervisor api server endpoints configured for the controller, e.g. a VIP or an external DNS name.
+	if len(r.supervisorAPIServerEndpoints) > 0 {
+		return r.supervisorAPIServerEndpoints, nil
+	}
+
+	// Discover the supervisor api server addresses
+	// 1. Check if a k8s service "kube-system/kube-apiserver-lb-svc" is available, if so, fetch the loadbalancer IPs.
 	// 2. If not, get the Supervisor Cluster Management Network Floating IP (FIP) from the cluster-info configmap. This is
 	// to support non-NSX-T development use cases only. If we are unable to find the cluster-info configmap for some reason,
 	// we log the error.
-	supervisorHost, vipErr := getSupervisorAPIServerVIP(ctx, r.Client)
+	supervisorHosts, vipErr := getSupervisorAPIServerVIPs(ctx, r.Client)
 	if vipErr != nil {
-		var fipErr error
-		supervisorHost, fipErr = getSupervisorAPIServerFIP(ctx, r.Client)
+		supervisorHost, fipErr := getSupervisorAPIServerFIP(ctx, r.Client)
 		if fipErr != nil {
-			return "", errors.Wrapf(kerrors.NewAggregate([]error{vipErr, fipErr}), "Failed to discover supervisor API server endpoint")
+			return nil, errors.Wrapf(kerrors.NewAggregate([]error{vipErr, fipErr}), "Failed to discover supervisor API server endpoint")
 		}
+		supervisorHosts = []string{supervisorHost}
 	}
-	return supervisorHost, nil
+	return supervisorHosts, nil
 }
 
 // newSupervisorHeadlessService returns a new Supervisor headless service.
@@ -354,13 +365,17 @@ func newSupervisorHeadlessService(port, targetPort int) *corev1.Service {
 	}
 }
 
-// newSupervisorHeadlessServiceEndpoints returns Kubernetes Endpoints for the supervisor apiserver address.
-func newSupervisorHeadlessServiceEndpoints(targetHost string, targetPort int) *corev1.Endpoints {
-	var endpointAddr corev1.EndpointAddress
-	if ip := net.ParseIP(targetHost); ip != nil {
-		endpointAddr.IP = ip.String()
-	} else {
-		endpointAddr.Hostname = targetHost
+// newSupervisorHeadlessServiceEndpoints returns Kubernetes Endpoints for the supervisor apiserver addresses.
+func newSupervisorHeadlessServiceEndpoints(targetHosts []string, targetPort int) *corev1.Endpoints {
+	endpointAddrs := make([]corev1.EndpointAddress, 0, len(targetHosts))
+	for _, targetHost := range targetHosts {
+		var endpointAddr corev1.EndpointAddress
+		if ip := net.ParseIP(targetHost); ip != nil {
+			endpointAddr.IP = ip.String()
+		} else {
+			endpointAddr.Hostname = targetHost
+		}
+		endpointAddrs = append(endpointAddrs, endpointAddr)
 	}
 	return &corev1.Endpoints{
 		ObjectMeta: metav1.ObjectMeta{
@@ -369,9 +384,7 @@ func newSupervisorHeadlessServiceEndpoints(targetHost string, targetPort int) *c
 		},
 		Subsets: []corev1.EndpointSubset{
 			{
-				Addresses: []corev1.EndpointAddress{
-					endpointAddr,
-				},
+				Addresses: endpointAddrs,
 				Ports: []corev1.EndpointPort{
 					{
 						Port: int32(targetPort),
@@ -382,21 +395,26 @@ func newSupervisorHeadlessServiceEndpoints(targetHost string, targetPort int) *c
 	}
 }
 
-// getSupervisorAPIServerVIP finds the load balancer IP of the Supervisor APIServer.
-func getSupervisorAPIServerVIP(ctx context.Context, client client.Client) (string, error) {
+// getSupervisorAPIServerVIPs finds the load balancer IPs of the Supervisor APIServer.
+// Highly available Supervisors can have multiple load balancer ingress points.
+func getSupervisorAPIServerVIPs(ctx context.Context, client client.Client) ([]string, error) {
 	svc := &corev1.Service{}
 	svcKey := types.NamespacedName{Name: vmwarev1.SupervisorLoadBalancerSvcName, Namespace: vmwarev1.SupervisorLoadBalancerSvcNamespace}
 	if err := client.Get(ctx, svcKey, svc); err != nil {
-		return "", errors.Wrapf(err, "unable to get supervisor loadbalancer Service %s", svcKey)
+		return nil, errors.Wrapf(err, "unable to get supervisor loadbalancer Service %s", svcKey)
 	}
-	if len(svc.Status.LoadBalancer.Ingress) > 0 {
-		ingress := svc.Status.LoadBalancer.Ingress[0]
+	var vips []string
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
 		if ipAddr := ingress.IP; ipAddr != "" {
-			return ipAddr, nil
+			vips = append(vips, ipAddr)
+		} else if ingress.Hostname != "" {
+			vips = append(vips, ingress.Hostname)
 		}
-		return ingress.Hostname, nil
 	}
-	return "", errors.Errorf("no VIP found in the supervisor loadbalancer Service %s", svcKey)
+	if len(vips) == 0 {
+		return nil, errors.Errorf("no VIP found in the supervisor loadbalancer Service %s", svcKey)
+	}
+	return vips, nil
 }
 
 // getSupervisorAPIServerFIP finds the floating ip of the Supervisor APIServer.
@@ -96,6 +96,18 @@ func assertHeadlessSvcWithVIPEndpoints(ctx context.Context, guestClient client.C
 	Expect(headlessEndpoints.Subsets[0].Ports[0].Port).To(Equal(int32(supervisorAPIServerPort)))
 }
 
+func assertHeadlessSvcWithEndpointIPs(ctx context.Context, guestClient client.Client, namespace, name string, ips ...string) {
+	assertHeadlessSvc(ctx, guestClient, namespace, name)
+	headlessEndpoints := &corev1.Endpoints{}
+	assertEventuallyExistsInNamespace(ctx, guestClient, namespace, name, headlessEndpoints)
+	Expect(headlessEndpoints.Subsets).To(HaveLen(1))
+	Expect(headlessEndpoints.Subsets[0].Addresses).To(HaveLen(len(ips)))
+	for i, ip := range ips {
+		Expect(headlessEndpoints.Subsets[0].Addresses[i].IP).To(Equal(ip))
+	}
+	Expect(headlessEndpoints.Subsets[0].Ports[0].Port).To(Equal(int32(supervisorAPIServerPort)))
+}
+
 func assertHeadlessSvcWithVIPHostnameEndpoints(ctx context.Context, guestClient client.Client, namespace, name string) {
 	assertHeadlessSvc(ctx, guestClient, namespace, name)
 	headlessEndpoints := &corev1.Endpoints{}
@@ -160,6 +172,23 @@ func newTestSupervisorLBServiceWithIPStatus() *corev1.Service {
 	return svc
 }
 
+func newTestSupervisorLBServiceWithMultipleIPsStatus() *corev1.Service {
+	svc := newTestSupervisorLBService()
+	svc.Status = corev1.ServiceStatus{
+		LoadBalancer: corev1.LoadBalancerStatus{
+			Ingress: []corev1.LoadBalancerIngress{
+				{
+					IP: testSupervisorAPIServerVIP,
+				},
+				{
+					IP: testSupervisorAPIServerVIP2,
+				},
+			},
+		},
+	}
+	return svc
+}
+
 func newTestSupervisorLBServiceWithHostnameStatus() *corev1.Service {
 	svc := newTestSupervisorLBService()
 	svc.Status = corev1.ServiceStatus{
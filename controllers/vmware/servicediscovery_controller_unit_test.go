@@ -34,17 +34,19 @@ var _ = Describe("ServiceDiscoveryReconciler reconcileNormal", serviceDiscoveryU
 
 func serviceDiscoveryUnitTestsReconcileNormal() {
 	var (
-		controllerCtx  *helpers.UnitTestContextForController
-		vsphereCluster vmwarev1.VSphereCluster
-		initObjects    []client.Object
-		reconciler     serviceDiscoveryReconciler
+		controllerCtx                *helpers.UnitTestContextForController
+		vsphereCluster               vmwarev1.VSphereCluster
+		initObjects                  []client.Object
+		supervisorAPIServerEndpoints []string
+		reconciler                   serviceDiscoveryReconciler
 	)
 	namespace := capiutil.RandomString(6)
 	JustBeforeEach(func() {
 		vsphereCluster = fake.NewVSphereCluster(namespace)
 		controllerCtx = helpers.NewUnitTestContextForController(ctx, namespace, &vsphereCluster, false, initObjects, nil)
 		reconciler = serviceDiscoveryReconciler{
-			Client: controllerCtx.ControllerManagerContext.Client,
+			Client:                       controllerCtx.ControllerManagerContext.Client,
+			supervisorAPIServerEndpoints: supervisorAPIServerEndpoints,
 		}
 		err := reconciler.reconcileNormal(ctx, controllerCtx.GuestClusterContext)
 		Expect(err).NotTo(HaveOccurred())
@@ -76,9 +78,39 @@ func serviceDiscoveryUnitTestsReconcileNormal() {
 			r := &serviceDiscoveryReconciler{
 				Client: controllerCtx.ControllerManagerContext.Client,
 			}
-			supervisorEndpointIP, err := r.getSupervisorAPIServerAddress(ctx)
+			supervisorEndpointIPs, err := r.getSupervisorAPIServerAddresses(ctx)
 			Expect(err).ShouldNot(HaveOccurred())
-			Expect(supervisorEndpointIP).To(Equal(testSupervisorAPIServerVIP))
+			Expect(supervisorEndpointIPs).To(Equal([]string{testSupervisorAPIServerVIP}))
+		})
+	})
+	Context("When multiple VIPs are available", func() {
+		BeforeEach(func() {
+			initObjects = []client.Object{
+				newTestSupervisorLBServiceWithMultipleIPsStatus(),
+			}
+		})
+		It("Should reconcile headless svc", func() {
+			By("creating a service and endpoints using all VIPs in the guest cluster")
+			assertHeadlessSvcWithEndpointIPs(ctx, controllerCtx.GuestClient, supervisorHeadlessSvcNamespace, supervisorHeadlessSvcName,
+				testSupervisorAPIServerVIP, testSupervisorAPIServerVIP2)
+			assertServiceDiscoveryCondition(controllerCtx.VSphereCluster, corev1.ConditionTrue, "", "", "")
+		})
+	})
+	Context("When supervisor API server endpoints are configured", func() {
+		BeforeEach(func() {
+			initObjects = []client.Object{
+				newTestSupervisorLBServiceWithIPStatus(),
+			}
+			supervisorAPIServerEndpoints = []string{testSupervisorAPIServerFIP, testSupervisorAPIServerVIP2}
+		})
+		AfterEach(func() {
+			supervisorAPIServerEndpoints = nil
+		})
+		It("Should reconcile headless svc", func() {
+			By("creating a service and endpoints using the configured endpoints instead of the VIP in the guest cluster")
+			assertHeadlessSvcWithEndpointIPs(ctx, controllerCtx.GuestClient, supervisorHeadlessSvcNamespace, supervisorHeadlessSvcName,
+				testSupervisorAPIServerFIP, testSupervisorAPIServerVIP2)
+			assertServiceDiscoveryCondition(controllerCtx.VSphereCluster, corev1.ConditionTrue, "", "", "")
 		})
 	})
 	Context("When FIP is available", func() {
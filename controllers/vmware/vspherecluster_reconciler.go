@@ -20,6 +20,7 @@ package vmware
 import (
 	"context"
 	"fmt"
+	"net"
 
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -27,6 +28,7 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	clusterutilv1 "sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
@@ -43,6 +45,7 @@ import (
 	topologyv1 "sigs.k8s.io/cluster-api-provider-vsphere/internal/apis/topology/v1alpha1"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/context/vmware"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/dns"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/util"
 )
 
@@ -57,6 +60,11 @@ type ClusterReconciler struct {
 	NetworkProvider       services.NetworkProvider
 	ControlPlaneService   services.ControlPlaneEndpointService
 	ResourcePolicyService services.ResourcePolicyService
+
+	// DNSProvider publishes the DNS records requested for control plane endpoints; nil if not configured.
+	DNSProvider services.DNSProvider
+	// DNSResolver is used to check the propagation of the DNS records; defaults to net.DefaultResolver.
+	DNSResolver dns.Resolver
 }
 
 // +kubebuilder:rbac:groups=vmware.infrastructure.cluster.x-k8s.io,resources=vsphereclusters,verbs=get;list;watch;create;update;patch;delete
@@ -67,7 +75,7 @@ type ClusterReconciler struct {
 // +kubebuilder:rbac:groups=vmoperator.vmware.com,resources=virtualmachinesetresourcepolicies;virtualmachinesetresourcepolicies/status,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=vmoperator.vmware.com,resources=virtualmachineservices;virtualmachineservices/status,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=netoperator.vmware.com,resources=networks,verbs=get;list;watch
-// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;update;create;delete
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;update;create;patch;delete
 // +kubebuilder:rbac:groups="",resources=persistentvolumeclaims/status,verbs=get;update;patch
 
 func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
@@ -122,8 +130,7 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_
 
 	// Handle deleted clusters
 	if !vsphereCluster.DeletionTimestamp.IsZero() {
-		r.reconcileDelete(clusterContext)
-		return ctrl.Result{}, nil
+		return ctrl.Result{}, r.reconcileDelete(ctx, clusterContext)
 	}
 
 	if cluster == nil {
@@ -142,11 +149,12 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_
 	return ctrl.Result{}, r.reconcileNormal(ctx, clusterContext)
 }
 
-func (r *ClusterReconciler) reconcileDelete(clusterCtx *vmware.ClusterContext) {
+func (r *ClusterReconciler) reconcileDelete(ctx context.Context, clusterCtx *vmware.ClusterContext) error {
 	deletingConditionTypes := []clusterv1.ConditionType{
 		vmwarev1.ResourcePolicyReadyCondition,
 		vmwarev1.ClusterNetworkReadyCondition,
 		vmwarev1.LoadBalancerReadyCondition,
+		vmwarev1.ControlPlaneEndpointDNSReadyCondition,
 	}
 
 	for _, t := range deletingConditionTypes {
@@ -155,8 +163,17 @@ func (r *ClusterReconciler) reconcileDelete(clusterCtx *vmware.ClusterContext) {
 		}
 	}
 
+	// Delete the DNS record published for the control plane endpoint, if any.
+	if dnsSpec := clusterCtx.VSphereCluster.Spec.ControlPlaneEndpointDNS; dnsSpec != nil && r.DNSProvider != nil {
+		if err := r.DNSProvider.DeleteRecord(ctx, dnsSpec.Hostname); err != nil {
+			return errors.Wrapf(err, "failed to delete DNS record %s for VSphereCluster %s/%s",
+				dnsSpec.Hostname, clusterCtx.VSphereCluster.Namespace, clusterCtx.VSphereCluster.Name)
+		}
+	}
+
 	// Cluster is deleted so remove the finalizer.
 	controllerutil.RemoveFinalizer(clusterCtx.VSphereCluster, vmwarev1.ClusterFinalizer)
+	return nil
 }
 
 func (r *ClusterReconciler) reconcileNormal(ctx context.Context, clusterCtx *vmware.ClusterContext) error {
@@ -259,9 +276,15 @@ func (r *ClusterReconciler) reconcileLoadBalancedEndpoint(ctx context.Context, c
 			clusterCtx.VSphereCluster.Namespace, clusterCtx.VSphereCluster.Name)
 	}
 
-	// If we've got here and we have a cpEndpoint, we're done.
-	clusterCtx.VSphereCluster.Spec.ControlPlaneEndpoint = *cpEndpoint
 	log.V(4).Info("Found API endpoint via virtual machine service", "host", cpEndpoint.Host, "port", cpEndpoint.Port)
+
+	endpoint, err := r.reconcileControlPlaneEndpointDNS(ctx, clusterCtx, *cpEndpoint)
+	if err != nil {
+		return err
+	}
+
+	// If we've got here and we have a cpEndpoint, we're done.
+	clusterCtx.VSphereCluster.Spec.ControlPlaneEndpoint = endpoint
 	return nil
 }
 
@@ -325,12 +348,66 @@ func (r *ClusterReconciler) reconcileAPIEndpoints(ctx context.Context, clusterCt
 			clusterCtx.VSphereCluster.Namespace, clusterCtx.VSphereCluster.Name)
 	}
 
+	endpoint, err := r.reconcileControlPlaneEndpointDNS(ctx, clusterCtx, apiEndpointList[0])
+	if err != nil {
+		return err
+	}
+
 	// Update the VSphereCluster's list of APIEndpoints.
-	clusterCtx.VSphereCluster.Spec.ControlPlaneEndpoint = apiEndpointList[0]
+	clusterCtx.VSphereCluster.Spec.ControlPlaneEndpoint = endpoint
 
 	return nil
 }
 
+// reconcileControlPlaneEndpointDNS publishes the DNS record requested for the control plane endpoint, if any,
+// and returns the endpoint to be set on the VSphereCluster. When a DNS record is requested, the returned endpoint
+// uses the DNS hostname, and it is returned only after the DNS record has propagated.
+func (r *ClusterReconciler) reconcileControlPlaneEndpointDNS(ctx context.Context, clusterCtx *vmware.ClusterContext, endpoint clusterv1.APIEndpoint) (clusterv1.APIEndpoint, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	dnsSpec := clusterCtx.VSphereCluster.Spec.ControlPlaneEndpointDNS
+	if dnsSpec == nil {
+		return endpoint, nil
+	}
+
+	if r.DNSProvider == nil {
+		conditions.MarkFalse(clusterCtx.VSphereCluster, vmwarev1.ControlPlaneEndpointDNSReadyCondition, vmwarev1.DNSProviderNotConfiguredReason, clusterv1.ConditionSeverityWarning,
+			"DNS record %s requested but no DNS provider is configured", dnsSpec.Hostname)
+		return clusterv1.APIEndpoint{}, errors.Errorf("failed to publish DNS record %s: no DNS provider configured", dnsSpec.Hostname)
+	}
+
+	record := services.DNSRecord{
+		Hostname: dnsSpec.Hostname,
+		Target:   endpoint.Host,
+		TTL:      ptr.Deref(dnsSpec.TTL, 0),
+	}
+	if err := r.DNSProvider.EnsureRecord(ctx, record); err != nil {
+		conditions.MarkFalse(clusterCtx.VSphereCluster, vmwarev1.ControlPlaneEndpointDNSReadyCondition, vmwarev1.DNSRecordRegistrationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return clusterv1.APIEndpoint{}, errors.Wrapf(err, "failed to publish DNS record %s", record.Hostname)
+	}
+
+	var resolver dns.Resolver = net.DefaultResolver
+	if r.DNSResolver != nil {
+		resolver = r.DNSResolver
+	}
+	propagated, err := dns.IsPropagated(ctx, resolver, record)
+	if err != nil {
+		return clusterv1.APIEndpoint{}, errors.Wrapf(err, "failed to check propagation of DNS record %s", record.Hostname)
+	}
+	if !propagated {
+		conditions.MarkFalse(clusterCtx.VSphereCluster, vmwarev1.ControlPlaneEndpointDNSReadyCondition, vmwarev1.WaitingForDNSPropagationReason, clusterv1.ConditionSeverityInfo,
+			"Waiting for DNS record %s pointing to %s to propagate", record.Hostname, record.Target)
+		return clusterv1.APIEndpoint{}, errors.Errorf("DNS record %s pointing to %s not propagated yet", record.Hostname, record.Target)
+	}
+
+	conditions.MarkTrue(clusterCtx.VSphereCluster, vmwarev1.ControlPlaneEndpointDNSReadyCondition)
+	log.Info("DNS record for the control plane endpoint propagated", "hostname", record.Hostname, "target", record.Target)
+	return clusterv1.APIEndpoint{
+		Host: record.Hostname,
+		Port: endpoint.Port,
+	}, nil
+}
+
 // VSphereMachineToCluster adds reconcile requests for a Cluster when one of its control plane machines has an event.
 func (r *ClusterReconciler) VSphereMachineToCluster(ctx context.Context, o client.Object) []reconcile.Request {
 	log := ctrl.LoggerFrom(ctx)
@@ -18,11 +18,13 @@ package vmware
 
 import (
 	"context"
+	"net"
 	"reflect"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -39,6 +41,7 @@ import (
 	topologyv1 "sigs.k8s.io/cluster-api-provider-vsphere/internal/apis/topology/v1alpha1"
 	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/context/vmware"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/network"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/vmoperator"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/util"
@@ -103,7 +106,7 @@ var _ = Describe("Cluster Controller Tests", func() {
 		It("should mark specific resources to be in deleting conditions", func() {
 			clusterCtx.VSphereCluster.Status.Conditions = append(clusterCtx.VSphereCluster.Status.Conditions,
 				clusterv1.Condition{Type: vmwarev1.ResourcePolicyReadyCondition, Status: corev1.ConditionTrue})
-			reconciler.reconcileDelete(clusterCtx)
+			Expect(reconciler.reconcileDelete(ctx, clusterCtx)).To(Succeed())
 			c := conditions.Get(clusterCtx.VSphereCluster, vmwarev1.ResourcePolicyReadyCondition)
 			Expect(c).NotTo(BeNil())
 			Expect(c.Status).To(Equal(corev1.ConditionFalse))
@@ -114,7 +117,7 @@ var _ = Describe("Cluster Controller Tests", func() {
 			otherReady := clusterv1.ConditionType("OtherReady")
 			clusterCtx.VSphereCluster.Status.Conditions = append(clusterCtx.VSphereCluster.Status.Conditions,
 				clusterv1.Condition{Type: otherReady, Status: corev1.ConditionTrue})
-			reconciler.reconcileDelete(clusterCtx)
+			Expect(reconciler.reconcileDelete(ctx, clusterCtx)).To(Succeed())
 			c := conditions.Get(clusterCtx.VSphereCluster, otherReady)
 			Expect(c).NotTo(BeNil())
 			Expect(c.Status).NotTo(Equal(corev1.ConditionFalse))
@@ -235,6 +238,110 @@ func TestClusterReconciler_getFailureDomains(t *testing.T) {
 	}
 }
 
+type fakeDNSProvider struct {
+	records map[string]string
+	err     error
+}
+
+func (p *fakeDNSProvider) EnsureRecord(_ context.Context, record services.DNSRecord) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.records[record.Hostname] = record.Target
+	return nil
+}
+
+func (p *fakeDNSProvider) DeleteRecord(_ context.Context, hostname string) error {
+	delete(p.records, hostname)
+	return nil
+}
+
+// fakeDNSResolver resolves the records of a fakeDNSProvider once propagated is set.
+type fakeDNSResolver struct {
+	provider   *fakeDNSProvider
+	propagated bool
+}
+
+func (r *fakeDNSResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if target, ok := r.provider.records[host]; ok && r.propagated {
+		return []string{target}, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func TestClusterReconciler_reconcileControlPlaneEndpointDNS(t *testing.T) {
+	ctx := context.Background()
+	endpoint := clusterv1.APIEndpoint{Host: "10.0.0.1", Port: 6443}
+
+	t.Run("returns the endpoint if no DNS record is requested", func(t *testing.T) {
+		g := NewWithT(t)
+
+		clusterCtx := &vmware.ClusterContext{VSphereCluster: &vmwarev1.VSphereCluster{}}
+		r := &ClusterReconciler{}
+
+		got, err := r.reconcileControlPlaneEndpointDNS(ctx, clusterCtx, endpoint)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).To(Equal(endpoint))
+		g.Expect(conditions.Has(clusterCtx.VSphereCluster, vmwarev1.ControlPlaneEndpointDNSReadyCondition)).To(BeFalse())
+	})
+
+	t.Run("fails if no DNS provider is configured", func(t *testing.T) {
+		g := NewWithT(t)
+
+		clusterCtx := &vmware.ClusterContext{VSphereCluster: &vmwarev1.VSphereCluster{
+			Spec: vmwarev1.VSphereClusterSpec{ControlPlaneEndpointDNS: &vmwarev1.ControlPlaneEndpointDNS{Hostname: "cp.example.com"}},
+		}}
+		r := &ClusterReconciler{}
+
+		_, err := r.reconcileControlPlaneEndpointDNS(ctx, clusterCtx, endpoint)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(conditions.GetReason(clusterCtx.VSphereCluster, vmwarev1.ControlPlaneEndpointDNSReadyCondition)).To(Equal(vmwarev1.DNSProviderNotConfiguredReason))
+	})
+
+	t.Run("publishes the DNS record and waits for propagation", func(t *testing.T) {
+		g := NewWithT(t)
+
+		clusterCtx := &vmware.ClusterContext{VSphereCluster: &vmwarev1.VSphereCluster{
+			Spec: vmwarev1.VSphereClusterSpec{ControlPlaneEndpointDNS: &vmwarev1.ControlPlaneEndpointDNS{Hostname: "cp.example.com"}},
+		}}
+		provider := &fakeDNSProvider{records: map[string]string{}}
+		resolver := &fakeDNSResolver{provider: provider}
+		r := &ClusterReconciler{
+			DNSProvider: provider,
+			DNSResolver: resolver,
+		}
+
+		_, err := r.reconcileControlPlaneEndpointDNS(ctx, clusterCtx, endpoint)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(provider.records).To(HaveKeyWithValue("cp.example.com", "10.0.0.1"))
+		g.Expect(conditions.GetReason(clusterCtx.VSphereCluster, vmwarev1.ControlPlaneEndpointDNSReadyCondition)).To(Equal(vmwarev1.WaitingForDNSPropagationReason))
+
+		resolver.propagated = true
+		got, err := r.reconcileControlPlaneEndpointDNS(ctx, clusterCtx, endpoint)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).To(Equal(clusterv1.APIEndpoint{Host: "cp.example.com", Port: 6443}))
+		g.Expect(conditions.IsTrue(clusterCtx.VSphereCluster, vmwarev1.ControlPlaneEndpointDNSReadyCondition)).To(BeTrue())
+
+		g.Expect(r.reconcileDelete(ctx, clusterCtx)).To(Succeed())
+		g.Expect(provider.records).To(BeEmpty())
+	})
+
+	t.Run("fails if the DNS record can't be published", func(t *testing.T) {
+		g := NewWithT(t)
+
+		clusterCtx := &vmware.ClusterContext{VSphereCluster: &vmwarev1.VSphereCluster{
+			Spec: vmwarev1.VSphereClusterSpec{ControlPlaneEndpointDNS: &vmwarev1.ControlPlaneEndpointDNS{Hostname: "cp.example.com"}},
+		}}
+		r := &ClusterReconciler{
+			DNSProvider: &fakeDNSProvider{err: errors.New("boom")},
+		}
+
+		_, err := r.reconcileControlPlaneEndpointDNS(ctx, clusterCtx, endpoint)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(conditions.GetReason(clusterCtx.VSphereCluster, vmwarev1.ControlPlaneEndpointDNSReadyCondition)).To(Equal(vmwarev1.DNSRecordRegistrationFailedReason))
+	})
+}
+
 func availabilityZone(name string) *topologyv1.AvailabilityZone {
 	return &topologyv1.AvailabilityZone{
 		ObjectMeta: metav1.ObjectMeta{
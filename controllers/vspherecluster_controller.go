@@ -23,6 +23,7 @@ import (
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
 	clusterutilv1 "sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/predicates"
@@ -41,6 +42,7 @@ import (
 	topologyv1 "sigs.k8s.io/cluster-api-provider-vsphere/internal/apis/topology/v1alpha1"
 	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
 	inframanager "sigs.k8s.io/cluster-api-provider-vsphere/pkg/manager"
+	capvrecord "sigs.k8s.io/cluster-api-provider-vsphere/pkg/record"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/vmoperator"
 )
@@ -65,6 +67,10 @@ func AddClusterControllerToManager(ctx context.Context, controllerManagerCtx *ca
 		if err != nil {
 			return errors.Wrap(err, "failed to create a network provider")
 		}
+		dnsProvider, err := inframanager.GetControlPlaneEndpointDNSProvider(ctx, controllerManagerCtx.ControlPlaneEndpointDNSProvider, controllerManagerCtx.ControlPlaneEndpointDNSWebhookURL)
+		if err != nil {
+			return errors.Wrap(err, "failed to create a DNS provider")
+		}
 		reconciler := &vmware.ClusterReconciler{
 			Client:   controllerManagerCtx.Client,
 			Recorder: mgr.GetEventRecorderFor("vspherecluster-controller"),
@@ -75,6 +81,7 @@ func AddClusterControllerToManager(ctx context.Context, controllerManagerCtx *ca
 				Client: controllerManagerCtx.Client,
 			},
 			NetworkProvider: networkProvider,
+			DNSProvider:     dnsProvider,
 		}
 		builder := ctrl.NewControllerManagedBy(mgr).
 			For(&vmwarev1.VSphereCluster{}).
@@ -99,6 +106,7 @@ func AddClusterControllerToManager(ctx context.Context, controllerManagerCtx *ca
 	reconciler := &clusterReconciler{
 		ControllerManagerContext: controllerManagerCtx,
 		Client:                   controllerManagerCtx.Client,
+		Recorder:                 capvrecord.NewAggregatingRecorder(mgr.GetEventRecorderFor("vspherecluster-controller"), controllerManagerCtx.EventAggregationWindow),
 		clusterModuleReconciler:  NewReconciler(controllerManagerCtx),
 		vmService:                services.VimMachineService{Client: controllerManagerCtx.Client},
 	}
@@ -146,6 +154,12 @@ func AddClusterControllerToManager(ctx context.Context, controllerManagerCtx *ca
 			&infrav1.VSphereDeploymentZone{},
 			handler.EnqueueRequestsFromMapFunc(reconciler.deploymentZoneToCluster),
 		).
+		// Watch the IPAddressClaim of the control plane endpoint, to set the
+		// control plane endpoint once the IP address is allocated.
+		Watches(
+			&ipamv1.IPAddressClaim{},
+			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &infrav1.VSphereCluster{}),
+		).
 		// Watch a GenericEvent channel for the controlled resource.
 		//
 		// This is useful when there are events outside of Kubernetes that
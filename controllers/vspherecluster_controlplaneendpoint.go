@@ -0,0 +1,188 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	clusterutilv1 "sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/internal/kubevip"
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+	infrautilv1 "sigs.k8s.io/cluster-api-provider-vsphere/pkg/util"
+)
+
+// defaultControlPlaneEndpointPort is the port of the managed control plane endpoint if none is set.
+const defaultControlPlaneEndpointPort = 6443
+
+// controlPlaneEndpointClaimName returns the name of the IPAddressClaim of the control plane endpoint
+// managed for a VSphereCluster.
+func controlPlaneEndpointClaimName(vsphereCluster *infrav1.VSphereCluster) string {
+	return fmt.Sprintf("%s-control-plane-endpoint", vsphereCluster.Name)
+}
+
+// reconcileControlPlaneEndpoint allocates the IP address of the control plane endpoint from the IPAM pool
+// configured in .spec.controlPlaneEndpointManagement and sets it as the control plane endpoint of the
+// VSphereCluster. It returns true once the control plane endpoint is set, or if it is not managed.
+func (r *clusterReconciler) reconcileControlPlaneEndpoint(ctx context.Context, clusterCtx *capvcontext.ClusterContext) (bool, error) {
+	vsphereCluster := clusterCtx.VSphereCluster
+	management := vsphereCluster.Spec.ControlPlaneEndpointManagement
+	if management == nil {
+		return true, nil
+	}
+
+	claim, err := r.createOrPatchControlPlaneEndpointClaim(ctx, clusterCtx, management.AddressesFromPool)
+	if err != nil {
+		conditions.MarkFalse(vsphereCluster, infrav1.ControlPlaneEndpointReadyCondition, infrav1.ControlPlaneEndpointAllocationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		return false, err
+	}
+	if claim.Status.AddressRef.Name == "" {
+		conditions.MarkFalse(vsphereCluster, infrav1.ControlPlaneEndpointReadyCondition, infrav1.WaitingForIPAddressReason, clusterv1.ConditionSeverityInfo,
+			"Waiting for IPAddressClaim %s to be fulfilled", claim.Name)
+		return false, nil
+	}
+
+	address := &ipamv1.IPAddress{}
+	addressKey := client.ObjectKey{Namespace: claim.Namespace, Name: claim.Status.AddressRef.Name}
+	if err := r.Client.Get(ctx, addressKey, address); err != nil {
+		err = errors.Wrapf(err, "failed to get IPAddress %s", klog.KRef(addressKey.Namespace, addressKey.Name))
+		conditions.MarkFalse(vsphereCluster, infrav1.ControlPlaneEndpointReadyCondition, infrav1.ControlPlaneEndpointAllocationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		return false, err
+	}
+	if net.ParseIP(address.Spec.Address) == nil {
+		conditions.MarkFalse(vsphereCluster, infrav1.ControlPlaneEndpointReadyCondition, infrav1.IPAddressInvalidReason, clusterv1.ConditionSeverityError,
+			"IPAddress %s has an invalid address %q", address.Name, address.Spec.Address)
+		return false, errors.Errorf("IPAddress %s has an invalid address %q", klog.KObj(address), address.Spec.Address)
+	}
+
+	port := management.Port
+	if port == 0 {
+		port = defaultControlPlaneEndpointPort
+	}
+	endpoint := infrav1.APIEndpoint{Host: address.Spec.Address, Port: port}
+
+	// The control plane endpoint can't change once set.
+	if vsphereCluster.Spec.ControlPlaneEndpoint.IsZero() {
+		vsphereCluster.Spec.ControlPlaneEndpoint = endpoint
+	} else if vsphereCluster.Spec.ControlPlaneEndpoint != endpoint {
+		conditions.MarkFalse(vsphereCluster, infrav1.ControlPlaneEndpointReadyCondition, infrav1.ControlPlaneEndpointAllocationFailedReason, clusterv1.ConditionSeverityError,
+			"The control plane endpoint %s does not match the allocated endpoint %s", vsphereCluster.Spec.ControlPlaneEndpoint.String(), endpoint.String())
+		return false, nil
+	}
+	vsphereCluster.Status.ControlPlaneEndpoint = &endpoint
+	conditions.MarkTrue(vsphereCluster, infrav1.ControlPlaneEndpointReadyCondition)
+	return true, nil
+}
+
+// createOrPatchControlPlaneEndpointClaim creates or patches the IPAddressClaim of the control plane endpoint.
+// The claim is owned by the VSphereCluster and protected by a finalizer, so the IP address is not released
+// while the VSphereCluster exists.
+func (r *clusterReconciler) createOrPatchControlPlaneEndpointClaim(ctx context.Context, clusterCtx *capvcontext.ClusterContext, poolRef corev1.TypedLocalObjectReference) (*ipamv1.IPAddressClaim, error) {
+	vsphereCluster := clusterCtx.VSphereCluster
+	claim := &ipamv1.IPAddressClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controlPlaneEndpointClaimName(vsphereCluster),
+			Namespace: vsphereCluster.Namespace,
+		},
+	}
+	log := ctrl.LoggerFrom(ctx, "IPAddressClaim", klog.KObj(claim))
+
+	result, err := ctrlutil.CreateOrPatch(ctx, r.Client, claim, func() error {
+		claim.SetOwnerReferences(clusterutilv1.EnsureOwnerRef(
+			claim.OwnerReferences,
+			metav1.OwnerReference{
+				APIVersion: infrav1.GroupVersion.String(),
+				Kind:       "VSphereCluster",
+				Name:       vsphereCluster.Name,
+				UID:        vsphereCluster.UID,
+			}))
+
+		ctrlutil.AddFinalizer(claim, infrav1.ControlPlaneEndpointIPAddressClaimFinalizer)
+
+		if claim.Labels == nil {
+			claim.Labels = make(map[string]string)
+		}
+		claim.Labels[clusterv1.ClusterNameLabel] = clusterCtx.Cluster.Name
+		claim.Spec.ClusterName = clusterCtx.Cluster.Name
+
+		claim.Spec.PoolRef.APIGroup = poolRef.APIGroup
+		claim.Spec.PoolRef.Kind = poolRef.Kind
+		claim.Spec.PoolRef.Name = poolRef.Name
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create or patch IPAddressClaim %s", klog.KObj(claim))
+	}
+	if result == ctrlutil.OperationResultCreated {
+		log.Info("Created IPAddressClaim for the control plane endpoint")
+	}
+	return claim, nil
+}
+
+// deleteControlPlaneEndpointClaim removes the finalizer from the IPAddressClaim of the control plane endpoint,
+// so it is garbage collected together with the VSphereCluster and the IP address is released.
+func (r *clusterReconciler) deleteControlPlaneEndpointClaim(ctx context.Context, clusterCtx *capvcontext.ClusterContext) error {
+	claim := &ipamv1.IPAddressClaim{}
+	claimKey := client.ObjectKey{
+		Namespace: clusterCtx.VSphereCluster.Namespace,
+		Name:      controlPlaneEndpointClaimName(clusterCtx.VSphereCluster),
+	}
+	if err := r.Client.Get(ctx, claimKey, claim); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get IPAddressClaim %s to remove the finalizer", klog.KRef(claimKey.Namespace, claimKey.Name))
+	}
+
+	if ctrlutil.RemoveFinalizer(claim, infrav1.ControlPlaneEndpointIPAddressClaimFinalizer) {
+		ctrl.LoggerFrom(ctx).Info(fmt.Sprintf("Removing finalizer %s", infrav1.ControlPlaneEndpointIPAddressClaimFinalizer), "IPAddressClaim", klog.KObj(claim))
+		if err := r.Client.Update(ctx, claim); err != nil {
+			return errors.Wrapf(err, "failed to update IPAddressClaim %s", klog.KObj(claim))
+		}
+	}
+	return nil
+}
+
+// kubeVIPConfig returns the configuration of the kube-vip static pod added to the bootstrap data of a
+// control plane VM, or nil if the control plane endpoint of the cluster is not managed by CAPV.
+func kubeVIPConfig(vsphereCluster *infrav1.VSphereCluster, vsphereVM *infrav1.VSphereVM) *kubevip.Config {
+	management := vsphereCluster.Spec.ControlPlaneEndpointManagement
+	endpoint := vsphereCluster.Status.ControlPlaneEndpoint
+	if management == nil || endpoint == nil || !infrautilv1.IsControlPlaneMachine(vsphereVM) {
+		return nil
+	}
+	return &kubevip.Config{
+		Address:   endpoint.Host,
+		Port:      endpoint.Port,
+		Interface: management.KubeVIP.Interface,
+		Image:     management.KubeVIP.Image,
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/internal/kubevip"
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/context/fake"
+)
+
+func Test_clusterReconciler_reconcileControlPlaneEndpoint(t *testing.T) {
+	ctx := context.Background()
+	namespace := "my-namespace"
+
+	setup := func(endpoint infrav1.APIEndpoint) (*clusterReconciler, *capvcontext.ClusterContext) {
+		cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: namespace}}
+		vsphereCluster := &infrav1.VSphereCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-vsphere-cluster", Namespace: namespace, UID: "uid"},
+			Spec: infrav1.VSphereClusterSpec{
+				ControlPlaneEndpoint: endpoint,
+				ControlPlaneEndpointManagement: &infrav1.ControlPlaneEndpointManagementSpec{
+					AddressesFromPool: poolRef("my-pool"),
+				},
+			},
+		}
+		controllerManagerCtx := fake.NewControllerManagerContext()
+		r := &clusterReconciler{ControllerManagerContext: controllerManagerCtx, Client: controllerManagerCtx.Client}
+		return r, &capvcontext.ClusterContext{Cluster: cluster, VSphereCluster: vsphereCluster}
+	}
+
+	fulfillClaim := func(g *gomega.WithT, c client.Client, address string) {
+		ipAddress := &ipamv1.IPAddress{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-address", Namespace: namespace},
+			Spec:       ipamv1.IPAddressSpec{Address: address, Prefix: 24},
+		}
+		g.Expect(c.Create(ctx, ipAddress)).To(gomega.Succeed())
+		claim := &ipamv1.IPAddressClaim{}
+		g.Expect(c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: "my-vsphere-cluster-control-plane-endpoint"}, claim)).To(gomega.Succeed())
+		claim.Status.AddressRef.Name = ipAddress.Name
+		g.Expect(c.Status().Update(ctx, claim)).To(gomega.Succeed())
+	}
+
+	t.Run("does nothing if the control plane endpoint is not managed", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+		r, clusterCtx := setup(infrav1.APIEndpoint{})
+		clusterCtx.VSphereCluster.Spec.ControlPlaneEndpointManagement = nil
+
+		ok, err := r.reconcileControlPlaneEndpoint(ctx, clusterCtx)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		g.Expect(ok).To(gomega.BeTrue())
+
+		claims := &ipamv1.IPAddressClaimList{}
+		g.Expect(r.Client.List(ctx, claims)).To(gomega.Succeed())
+		g.Expect(claims.Items).To(gomega.BeEmpty())
+	})
+
+	t.Run("allocates the control plane endpoint from the pool", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+		r, clusterCtx := setup(infrav1.APIEndpoint{})
+
+		ok, err := r.reconcileControlPlaneEndpoint(ctx, clusterCtx)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		g.Expect(ok).To(gomega.BeFalse())
+		g.Expect(conditions.GetReason(clusterCtx.VSphereCluster, infrav1.ControlPlaneEndpointReadyCondition)).To(gomega.Equal(infrav1.WaitingForIPAddressReason))
+
+		claim := &ipamv1.IPAddressClaim{}
+		g.Expect(r.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: "my-vsphere-cluster-control-plane-endpoint"}, claim)).To(gomega.Succeed())
+		g.Expect(claim.Spec.PoolRef.Name).To(gomega.Equal("my-pool"))
+		g.Expect(claim.Spec.ClusterName).To(gomega.Equal("my-cluster"))
+		g.Expect(claim.Finalizers).To(gomega.ConsistOf(infrav1.ControlPlaneEndpointIPAddressClaimFinalizer))
+		g.Expect(claim.OwnerReferences).To(gomega.HaveLen(1))
+		g.Expect(claim.OwnerReferences[0].Kind).To(gomega.Equal("VSphereCluster"))
+
+		fulfillClaim(g, r.Client, "10.0.0.10")
+
+		ok, err = r.reconcileControlPlaneEndpoint(ctx, clusterCtx)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		g.Expect(ok).To(gomega.BeTrue())
+		expected := infrav1.APIEndpoint{Host: "10.0.0.10", Port: 6443}
+		g.Expect(clusterCtx.VSphereCluster.Spec.ControlPlaneEndpoint).To(gomega.Equal(expected))
+		g.Expect(clusterCtx.VSphereCluster.Status.ControlPlaneEndpoint).To(gomega.Equal(&expected))
+		g.Expect(conditions.IsTrue(clusterCtx.VSphereCluster, infrav1.ControlPlaneEndpointReadyCondition)).To(gomega.BeTrue())
+
+		g.Expect(r.deleteControlPlaneEndpointClaim(ctx, clusterCtx)).To(gomega.Succeed())
+		g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(claim), claim)).To(gomega.Succeed())
+		g.Expect(claim.Finalizers).To(gomega.BeEmpty())
+	})
+
+	t.Run("does not change a control plane endpoint which is already set", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+		r, clusterCtx := setup(infrav1.APIEndpoint{Host: "10.0.0.20", Port: 6443})
+
+		_, err := r.reconcileControlPlaneEndpoint(ctx, clusterCtx)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		fulfillClaim(g, r.Client, "10.0.0.10")
+
+		ok, err := r.reconcileControlPlaneEndpoint(ctx, clusterCtx)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		g.Expect(ok).To(gomega.BeFalse())
+		g.Expect(clusterCtx.VSphereCluster.Spec.ControlPlaneEndpoint.Host).To(gomega.Equal("10.0.0.20"))
+		g.Expect(conditions.GetReason(clusterCtx.VSphereCluster, infrav1.ControlPlaneEndpointReadyCondition)).To(gomega.Equal(infrav1.ControlPlaneEndpointAllocationFailedReason))
+	})
+}
+
+func Test_kubeVIPConfig(t *testing.T) {
+	vsphereCluster := &infrav1.VSphereCluster{
+		Spec: infrav1.VSphereClusterSpec{
+			ControlPlaneEndpointManagement: &infrav1.ControlPlaneEndpointManagementSpec{
+				AddressesFromPool: corev1.TypedLocalObjectReference{Kind: "InClusterIPPool", Name: "my-pool"},
+				KubeVIP:           infrav1.KubeVIPSpec{Interface: "eth0"},
+			},
+		},
+		Status: infrav1.VSphereClusterStatus{
+			ControlPlaneEndpoint: &infrav1.APIEndpoint{Host: "10.0.0.10", Port: 6443},
+		},
+	}
+	controlPlaneVM := &infrav1.VSphereVM{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{clusterv1.MachineControlPlaneLabel: ""}}}
+	workerVM := &infrav1.VSphereVM{}
+
+	g := gomega.NewWithT(t)
+	g.Expect(kubeVIPConfig(vsphereCluster, controlPlaneVM)).To(gomega.Equal(&kubevip.Config{Address: "10.0.0.10", Port: 6443, Interface: "eth0"}))
+	g.Expect(kubeVIPConfig(vsphereCluster, workerVM)).To(gomega.BeNil())
+	g.Expect(kubeVIPConfig(&infrav1.VSphereCluster{}, controlPlaneVM)).To(gomega.BeNil())
+}
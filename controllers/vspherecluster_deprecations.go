@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+
+	pkgerrors "github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+	infrautilv1 "sigs.k8s.io/cluster-api-provider-vsphere/pkg/util"
+)
+
+// reconcileDeprecatedFieldUsage summarizes the deprecated fields set on the VSphereMachines and
+// VSphereVMs of the cluster into the status of the VSphereCluster.
+func (r *clusterReconciler) reconcileDeprecatedFieldUsage(ctx context.Context, clusterCtx *capvcontext.ClusterContext) error {
+	listOpts := []client.ListOption{
+		client.InNamespace(clusterCtx.Cluster.Namespace),
+		client.MatchingLabels{clusterv1.ClusterNameLabel: clusterCtx.Cluster.Name},
+	}
+	networkPath := field.NewPath("spec", "network")
+
+	usage := deprecatedFieldUsage{}
+	vsphereMachines := &infrav1.VSphereMachineList{}
+	if err := r.Client.List(ctx, vsphereMachines, listOpts...); err != nil {
+		return pkgerrors.Wrapf(err, "failed to list VSphereMachines of Cluster %s", klog.KObj(clusterCtx.Cluster))
+	}
+	for _, vsphereMachine := range vsphereMachines.Items {
+		usage.add("VSphereMachine", infrautilv1.NetworkSpecDeprecatedFields(networkPath, vsphereMachine.Spec.Network))
+	}
+
+	vsphereVMs := &infrav1.VSphereVMList{}
+	if err := r.Client.List(ctx, vsphereVMs, listOpts...); err != nil {
+		return pkgerrors.Wrapf(err, "failed to list VSphereVMs of Cluster %s", klog.KObj(clusterCtx.Cluster))
+	}
+	for _, vsphereVM := range vsphereVMs.Items {
+		usage.add("VSphereVM", infrautilv1.NetworkSpecDeprecatedFields(networkPath, vsphereVM.Spec.Network))
+	}
+
+	clusterCtx.VSphereCluster.Status.DeprecatedFieldUsage = usage.list()
+	return nil
+}
+
+// deprecatedFieldUsage counts the usage of deprecated fields by kind and field path.
+type deprecatedFieldUsage map[[2]string]*infrav1.VSphereClusterDeprecatedFieldUsage
+
+func (u deprecatedFieldUsage) add(kind string, deprecated []infrautilv1.DeprecatedField) {
+	for _, d := range deprecated {
+		key := [2]string{kind, d.Path.String()}
+		if _, ok := u[key]; !ok {
+			u[key] = &infrav1.VSphereClusterDeprecatedFieldUsage{
+				Kind:      kind,
+				Field:     d.Path.String(),
+				Migration: d.Migration,
+			}
+		}
+		u[key].Count++
+	}
+}
+
+// list returns the usage sorted by kind and field path, so the status is stable across reconciles.
+func (u deprecatedFieldUsage) list() []infrav1.VSphereClusterDeprecatedFieldUsage {
+	if len(u) == 0 {
+		return nil
+	}
+	list := make([]infrav1.VSphereClusterDeprecatedFieldUsage, 0, len(u))
+	for _, usage := range u {
+		list = append(list, *usage)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Kind != list[j].Kind {
+			return list[i].Kind < list[j].Kind
+		}
+		return list[i].Field < list[j].Field
+	})
+	return list
+}
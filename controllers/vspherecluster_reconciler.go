@@ -29,6 +29,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -45,14 +46,17 @@ import (
 	"sigs.k8s.io/cluster-api-provider-vsphere/feature"
 	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/identity"
+	capvrecord "sigs.k8s.io/cluster-api-provider-vsphere/pkg/record"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/session"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/tracing"
 	infrautilv1 "sigs.k8s.io/cluster-api-provider-vsphere/pkg/util"
 )
 
 type clusterReconciler struct {
 	ControllerManagerContext *capvcontext.ControllerManagerContext
 	Client                   client.Client
+	Recorder                 record.EventRecorder
 
 	vmService               services.VimMachineService
 	clusterModuleReconciler Reconciler
@@ -71,6 +75,11 @@ func (r *clusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_
 		return reconcile.Result{}, err
 	}
 
+	ctx, span := tracing.StartSpan(ctx, "VSphereCluster.Reconcile", vsphereCluster)
+	defer func() {
+		tracing.EndSpan(span, reterr)
+	}()
+
 	// Fetch the CAPI Cluster.
 	cluster, err := clusterutilv1.GetOwnerCluster(ctx, r.Client, vsphereCluster.ObjectMeta)
 	if err != nil {
@@ -139,12 +148,39 @@ func (r *clusterReconciler) reconcileDelete(ctx context.Context, clusterCtx *cap
 		return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
 	}
 
+	// Cluster modules and the cluster folder can only be removed once they are
+	// empty, so wait for all the VSphereVMs of the cluster, including the ones
+	// not owned by a VSphereMachine, to be deleted.
+	vsphereVMs := &infrav1.VSphereVMList{}
+	if err := r.Client.List(ctx, vsphereVMs,
+		client.InNamespace(clusterCtx.Cluster.Namespace),
+		client.MatchingLabels{clusterv1.ClusterNameLabel: clusterCtx.Cluster.Name}); err != nil {
+		return reconcile.Result{}, pkgerrors.Wrapf(err,
+			"unable to list VSphereVMs part of VSphereCluster %s/%s", clusterCtx.VSphereCluster.Namespace, clusterCtx.VSphereCluster.Name)
+	}
+
+	if len(vsphereVMs.Items) > 0 {
+		log.Info("Waiting for VSphereVMs to be deleted", "count", len(vsphereVMs.Items))
+		return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
 	// The cluster module info needs to be reconciled before the secret deletion
 	// since it needs access to the vCenter instance to be able to perform LCM operations
 	// on the cluster modules.
 	affinityReconcileResult, err := r.reconcileClusterModules(ctx, clusterCtx)
 	if err != nil {
-		return affinityReconcileResult, err
+		forceDeleted, forceErr := r.reconcileClusterModulesDeletionFailure(ctx, clusterCtx, err)
+		if forceErr != nil {
+			return reconcile.Result{}, kerrors.NewAggregate([]error{err, forceErr})
+		}
+		if !forceDeleted {
+			return affinityReconcileResult, err
+		}
+	}
+
+	// Release the IP address of the control plane endpoint once all the VMs of the cluster are gone.
+	if err := r.deleteControlPlaneEndpointClaim(ctx, clusterCtx); err != nil {
+		return reconcile.Result{}, err
 	}
 
 	// Remove finalizer on Identity Secret
@@ -183,6 +219,32 @@ func (r *clusterReconciler) reconcileDelete(ctx context.Context, clusterCtx *cap
 	return reconcile.Result{}, nil
 }
 
+// reconcileClusterModulesDeletionFailure handles cluster modules which could not be deleted. If vCenter is
+// unreachable for longer than a VSphereForceDeletePolicy allows, it returns true so the deletion of the
+// VSphereCluster continues and the cluster modules are left behind in vCenter.
+func (r *clusterReconciler) reconcileClusterModulesDeletionFailure(ctx context.Context, clusterCtx *capvcontext.ClusterContext, deleteErr error) (bool, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	_, err := r.reconcileVCenterConnectivity(ctx, clusterCtx)
+	if err == nil {
+		conditions.MarkTrue(clusterCtx.VSphereCluster, infrav1.VCenterAvailableCondition)
+		return false, nil
+	}
+	conditions.MarkFalse(clusterCtx.VSphereCluster, infrav1.VCenterAvailableCondition, infrav1.VCenterUnreachableReason, clusterv1.ConditionSeverityError, err.Error())
+
+	policy, err := getForceDeletePolicy(ctx, r.Client, clusterCtx.VSphereCluster, clusterCtx.Cluster, time.Now())
+	if err != nil || policy == nil {
+		return false, err
+	}
+
+	timeout := policy.Spec.VCenterUnreachableTimeout.Duration
+	log.Info("vCenter is unreachable, deleting the VSphereCluster without deleting its cluster modules as allowed by the VSphereForceDeletePolicy",
+		"VSphereForceDeletePolicy", policy.Name, "timeout", timeout.String(), "error", deleteErr.Error())
+	r.Recorder.Eventf(clusterCtx.VSphereCluster, corev1.EventTypeWarning, infrav1.ForceDeletedReason,
+		"vCenter has been unreachable for more than %s, removing the finalizer without deleting the cluster modules as allowed by VSphereForceDeletePolicy %s: %v", timeout, policy.Name, deleteErr)
+	return true, nil
+}
+
 func (r *clusterReconciler) reconcileNormal(ctx context.Context, clusterCtx *capvcontext.ClusterContext) (reconcile.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
 
@@ -195,6 +257,10 @@ func (r *clusterReconciler) reconcileNormal(ctx context.Context, clusterCtx *cap
 		return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
 	}
 
+	if err := r.reconcileDeprecatedFieldUsage(ctx, clusterCtx); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	if err := r.reconcileIdentitySecret(ctx, clusterCtx); err != nil {
 		conditions.MarkFalse(clusterCtx.VSphereCluster, infrav1.VCenterAvailableCondition, infrav1.VCenterUnreachableReason, clusterv1.ConditionSeverityError, err.Error())
 		return reconcile.Result{}, err
@@ -203,6 +269,7 @@ func (r *clusterReconciler) reconcileNormal(ctx context.Context, clusterCtx *cap
 	vcenterSession, err := r.reconcileVCenterConnectivity(ctx, clusterCtx)
 	if err != nil {
 		conditions.MarkFalse(clusterCtx.VSphereCluster, infrav1.VCenterAvailableCondition, infrav1.VCenterUnreachableReason, clusterv1.ConditionSeverityError, err.Error())
+		r.Recorder.Eventf(clusterCtx.VSphereCluster, corev1.EventTypeWarning, capvrecord.ReasonForError(err, capvrecord.VCenterUnreachableReason), "Failed to connect to vCenter: %v", err)
 		return reconcile.Result{}, pkgerrors.Wrapf(err,
 			"unexpected error while probing vcenter for %s", clusterCtx)
 	}
@@ -217,12 +284,23 @@ func (r *clusterReconciler) reconcileNormal(ctx context.Context, clusterCtx *cap
 	affinityReconcileResult, err := r.reconcileClusterModules(ctx, clusterCtx)
 	if err != nil {
 		conditions.MarkFalse(clusterCtx.VSphereCluster, infrav1.ClusterModulesAvailableCondition, infrav1.ClusterModuleSetupFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		r.Recorder.Eventf(clusterCtx.VSphereCluster, corev1.EventTypeWarning, capvrecord.ReasonForError(err, capvrecord.ReconcileFailedReason), "Failed to reconcile cluster modules: %v", err)
 		return affinityReconcileResult, err
 	}
 
+	ok, err = r.reconcileControlPlaneEndpoint(ctx, clusterCtx)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !ok {
+		log.Info("Waiting for the control plane endpoint to be allocated")
+		return affinityReconcileResult, nil
+	}
+
 	clusterCtx.VSphereCluster.Status.Ready = true
 
-	return reconcile.Result{}, nil
+	// Requeue to garbage collect unused cluster modules once their deletion grace period has passed.
+	return affinityReconcileResult, nil
 }
 
 func (r *clusterReconciler) reconcileIdentitySecret(ctx context.Context, clusterCtx *capvcontext.ClusterContext) error {
@@ -271,7 +349,14 @@ func (r *clusterReconciler) reconcileIdentitySecret(ctx context.Context, cluster
 func (r *clusterReconciler) reconcileVCenterConnectivity(ctx context.Context, clusterCtx *capvcontext.ClusterContext) (*session.Session, error) {
 	params := session.NewParams().
 		WithServer(clusterCtx.VSphereCluster.Spec.Server).
-		WithThumbprint(clusterCtx.VSphereCluster.Spec.Thumbprint)
+		WithThumbprint(clusterCtx.VSphereCluster.Spec.Thumbprint).
+		WithProxy(session.ProxyForCluster(clusterCtx.VSphereCluster, r.ControllerManagerContext.VCenterProxy))
+
+	tlsConfig, err := identity.GetTLSConfig(ctx, r.Client, clusterCtx.VSphereCluster, r.ControllerManagerContext.Namespace)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "failed to get TLS configuration")
+	}
+	params = params.WithTLSConfig(tlsConfig)
 
 	if clusterCtx.VSphereCluster.Spec.IdentityRef != nil {
 		creds, err := identity.GetCredentials(ctx, r.Client, clusterCtx.VSphereCluster, r.ControllerManagerContext.Namespace)
@@ -279,7 +364,7 @@ func (r *clusterReconciler) reconcileVCenterConnectivity(ctx context.Context, cl
 			return nil, pkgerrors.Wrap(err, "failed to get credentials from IdentityRef")
 		}
 
-		params = params.WithUserInfo(creds.Username, creds.Password)
+		params = params.WithUserInfo(creds.Username, creds.Password).WithTokenSource(creds.TokenSource).WithAllowedTargets(creds.AllowedTargets)
 		return session.GetOrCreate(ctx, params)
 	}
 
@@ -442,6 +442,45 @@ var _ = Describe("VIM based VSphere ClusterReconciler", func() {
 	})
 })
 
+func TestClusterReconciler_ReconcileDeprecatedFieldUsage(t *testing.T) {
+	g := NewWithT(t)
+
+	objectMeta := func(name, clusterName string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Name:      name,
+			Namespace: fake.Namespace,
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: clusterName},
+		}
+	}
+	withPreferredAPIServerCIDR := func(cidr string) infrav1.VirtualMachineCloneSpec {
+		return infrav1.VirtualMachineCloneSpec{Network: infrav1.NetworkSpec{PreferredAPIServerCIDR: cidr}}
+	}
+
+	controllerManagerContext := fake.NewControllerManagerContext(
+		&infrav1.VSphereMachine{ObjectMeta: objectMeta("machine-1", fake.Clusterv1a2Name), Spec: infrav1.VSphereMachineSpec{VirtualMachineCloneSpec: withPreferredAPIServerCIDR("10.0.0.0/24")}},
+		&infrav1.VSphereMachine{ObjectMeta: objectMeta("machine-2", fake.Clusterv1a2Name), Spec: infrav1.VSphereMachineSpec{VirtualMachineCloneSpec: withPreferredAPIServerCIDR("10.0.0.0/24")}},
+		&infrav1.VSphereMachine{ObjectMeta: objectMeta("machine-3", fake.Clusterv1a2Name)},
+		&infrav1.VSphereMachine{ObjectMeta: objectMeta("machine-4", "other-cluster"), Spec: infrav1.VSphereMachineSpec{VirtualMachineCloneSpec: withPreferredAPIServerCIDR("10.0.0.0/24")}},
+		&infrav1.VSphereVM{ObjectMeta: objectMeta("vm-1", fake.Clusterv1a2Name), Spec: infrav1.VSphereVMSpec{VirtualMachineCloneSpec: withPreferredAPIServerCIDR("10.0.0.0/24")}},
+	)
+	clusterCtx := fake.NewClusterContext(ctx, controllerManagerContext)
+
+	r := clusterReconciler{
+		ControllerManagerContext: controllerManagerContext,
+		Client:                   controllerManagerContext.Client,
+	}
+	g.Expect(r.reconcileDeprecatedFieldUsage(ctx, clusterCtx)).To(Succeed())
+
+	usage := clusterCtx.VSphereCluster.Status.DeprecatedFieldUsage
+	g.Expect(usage).To(HaveLen(2))
+	g.Expect(usage[0].Kind).To(Equal("VSphereMachine"))
+	g.Expect(usage[0].Field).To(Equal("spec.network.preferredAPIServerCidr"))
+	g.Expect(usage[0].Count).To(Equal(int32(2)))
+	g.Expect(usage[0].Migration).NotTo(BeEmpty())
+	g.Expect(usage[1].Kind).To(Equal("VSphereVM"))
+	g.Expect(usage[1].Count).To(Equal(int32(1)))
+}
+
 func TestClusterReconciler_ReconcileDeploymentZones(t *testing.T) {
 	server := "vcenter123.foo.com"
 
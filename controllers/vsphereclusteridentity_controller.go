@@ -48,6 +48,8 @@ import (
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=vsphereclusteridentities,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=vsphereclusteridentities/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;patch;update;delete
+// +kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=serviceaccounts/token,verbs=create
 
 // AddVsphereClusterIdentityControllerToManager adds a VSphereClusterIdentity controller to the controller manager.
 func AddVsphereClusterIdentityControllerToManager(ctx context.Context, controllerManagerCtx *capvcontext.ControllerManagerContext, mgr manager.Manager, options controller.Options) error {
@@ -112,6 +114,15 @@ func (r clusterIdentityReconciler) Reconcile(ctx context.Context, req reconcile.
 		return reconcile.Result{}, nil
 	}
 
+	if (identity.Spec.SecretName == "") == (identity.Spec.TokenExchange == nil) {
+		conditions.MarkFalse(identity, infrav1.CredentialsAvailableCondidtion, infrav1.InvalidCredentialsSourceReason, clusterv1.ConditionSeverityError, "exactly one of secretName or tokenExchange must be set")
+		identity.Status.Ready = false
+		return reconcile.Result{}, nil
+	}
+	if identity.Spec.TokenExchange != nil {
+		return reconcile.Result{}, r.reconcileTokenExchange(ctx, identity)
+	}
+
 	// fetch secret
 	secret := &corev1.Secret{}
 	secretKey := client.ObjectKey{
@@ -154,8 +165,32 @@ func (r clusterIdentityReconciler) Reconcile(ctx context.Context, req reconcile.
 	return reconcile.Result{}, nil
 }
 
+// reconcileTokenExchange ensures the ServiceAccount whose tokens are exchanged for SAML tokens exists.
+func (r clusterIdentityReconciler) reconcileTokenExchange(ctx context.Context, identity *infrav1.VSphereClusterIdentity) error {
+	serviceAccount := &corev1.ServiceAccount{}
+	serviceAccountKey := client.ObjectKey{
+		Namespace: r.ControllerManagerCtx.Namespace,
+		Name:      identity.Spec.TokenExchange.ServiceAccountName,
+	}
+	if err := r.Client.Get(ctx, serviceAccountKey, serviceAccount); err != nil {
+		conditions.MarkFalse(identity, infrav1.CredentialsAvailableCondidtion, infrav1.ServiceAccountNotAvailableReason, clusterv1.ConditionSeverityWarning, err.Error())
+		identity.Status.Ready = false
+		return errors.Wrapf(err, "failed to get ServiceAccount %s", klog.KRef(serviceAccountKey.Namespace, serviceAccountKey.Name))
+	}
+
+	conditions.MarkTrue(identity, infrav1.CredentialsAvailableCondidtion)
+	identity.Status.Ready = true
+	return nil
+}
+
 func (r clusterIdentityReconciler) reconcileDelete(ctx context.Context, identity *infrav1.VSphereClusterIdentity) error {
 	log := ctrl.LoggerFrom(ctx)
+
+	// The ServiceAccount of a token exchange is not owned by the identity and therefore kept.
+	if identity.Spec.SecretName == "" {
+		ctrlutil.RemoveFinalizer(identity, infrav1.VSphereClusterIdentityFinalizer)
+		return nil
+	}
 	secret := &corev1.Secret{}
 	secretKey := client.ObjectKey{
 		Namespace: r.ControllerManagerCtx.Namespace,
@@ -187,5 +187,58 @@ var _ = Describe("VSphereClusterIdentity Reconciler", func() {
 				return false
 			}, timeout).Should(BeTrue())
 		})
+
+		It("should set Ready condition if the ServiceAccount of the token exchange exists", func() {
+			serviceAccount := &corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{
+					GenerateName: "serviceaccount-",
+					Namespace:    controllerNamespace,
+				},
+			}
+			Expect(testEnv.Create(ctx, serviceAccount)).To(Succeed())
+
+			identity := &infrav1.VSphereClusterIdentity{
+				ObjectMeta: metav1.ObjectMeta{
+					GenerateName: "identity-",
+				},
+				Spec: infrav1.VSphereClusterIdentitySpec{
+					TokenExchange: &infrav1.VSphereTokenExchange{
+						ServiceAccountName: serviceAccount.Name,
+					},
+				},
+			}
+			Expect(testEnv.Create(ctx, identity)).To(Succeed())
+
+			Eventually(func() bool {
+				i := &infrav1.VSphereClusterIdentity{}
+				if err := testEnv.Get(ctx, client.ObjectKey{Name: identity.Name}, i); err != nil {
+					return false
+				}
+				return i.Status.Ready && conditions.IsTrue(i, infrav1.CredentialsAvailableCondidtion)
+			}, timeout).Should(BeTrue())
+		})
+
+		It("should error if both a secret and a token exchange are set", func() {
+			identity := &infrav1.VSphereClusterIdentity{
+				ObjectMeta: metav1.ObjectMeta{
+					GenerateName: "identity-",
+				},
+				Spec: infrav1.VSphereClusterIdentitySpec{
+					SecretName: "secret",
+					TokenExchange: &infrav1.VSphereTokenExchange{
+						ServiceAccountName: "serviceaccount",
+					},
+				},
+			}
+			Expect(testEnv.Create(ctx, identity)).To(Succeed())
+
+			Eventually(func() bool {
+				i := &infrav1.VSphereClusterIdentity{}
+				if err := testEnv.Get(ctx, client.ObjectKey{Name: identity.Name}, i); err != nil {
+					return false
+				}
+				return !i.Status.Ready && conditions.GetReason(i, infrav1.CredentialsAvailableCondidtion) == infrav1.InvalidCredentialsSourceReason
+			}, timeout).Should(BeTrue())
+		})
 	})
 })
@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -82,6 +83,15 @@ func AddVSphereDeploymentZoneControllerToManager(ctx context.Context, controller
 		Complete(reconciler)
 }
 
+const (
+	// vsanDatastoreHealthRefreshInterval is the interval in which the health of the vSAN datastore of a failure domain is refreshed.
+	vsanDatastoreHealthRefreshInterval = 5 * time.Minute
+
+	// validationRefreshInterval is the interval in which the placement constraint and the failure domain are validated
+	// against vCenter, so that objects renamed or removed in vCenter are detected.
+	validationRefreshInterval = 10 * time.Minute
+)
+
 type vsphereDeploymentZoneReconciler struct {
 	*capvcontext.ControllerManagerContext
 }
@@ -133,7 +143,16 @@ func (r vsphereDeploymentZoneReconciler) Reconcile(ctx context.Context, request
 		return ctrl.Result{}, nil
 	}
 
-	return ctrl.Result{}, r.reconcileNormal(ctx, vsphereDeploymentZoneContext)
+	if err := r.reconcileNormal(ctx, vsphereDeploymentZoneContext); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// The health of the vSAN datastore of the failure domain is refreshed periodically.
+	if conditions.Has(vsphereDeploymentZone, infrav1.VSANDatastoreHealthyCondition) {
+		return ctrl.Result{RequeueAfter: vsanDatastoreHealthRefreshInterval}, nil
+	}
+	// The vCenter objects referenced by the deployment zone can be renamed or removed at any time.
+	return ctrl.Result{RequeueAfter: validationRefreshInterval}, nil
 }
 
 func (r vsphereDeploymentZoneReconciler) reconcileNormal(ctx context.Context, deploymentZoneCtx *capvcontext.VSphereDeploymentZoneContext) error {
@@ -164,6 +183,8 @@ func (r vsphereDeploymentZoneReconciler) reconcileNormal(ctx context.Context, de
 		return err
 	}
 
+	r.reconcilePrivileges(ctx, deploymentZoneCtx, failureDomain)
+
 	// Mark the deployment zone as ready.
 	deploymentZoneCtx.VSphereDeploymentZone.Status.Ready = ptr.To(true)
 	return nil
@@ -194,7 +215,8 @@ func (r vsphereDeploymentZoneReconciler) getVCenterSession(ctx context.Context,
 	params := session.NewParams().
 		WithServer(deploymentZoneCtx.VSphereDeploymentZone.Spec.Server).
 		WithDatacenter(datacenter).
-		WithUserInfo(r.ControllerManagerContext.Username, r.ControllerManagerContext.Password)
+		WithUserInfo(r.ControllerManagerContext.Username, r.ControllerManagerContext.Password).
+		WithProxy(r.ControllerManagerContext.VCenterProxy)
 
 	clusterList := &infrav1.VSphereClusterList{}
 	if err := r.Client.List(ctx, clusterList); err != nil {
@@ -210,15 +232,24 @@ func (r vsphereDeploymentZoneReconciler) getVCenterSession(ctx context.Context,
 		log := log.WithValues("VSphereCluster", klog.KRef(vsphereCluster.Namespace, vsphereCluster.Name))
 		ctx := ctrl.LoggerInto(ctx, log)
 
-		params = params.WithThumbprint(vsphereCluster.Spec.Thumbprint)
+		params = params.WithThumbprint(vsphereCluster.Spec.Thumbprint).
+			WithProxy(session.ProxyForCluster(&vsphereCluster, r.ControllerManagerContext.VCenterProxy))
 		vsphereCluster := vsphereCluster
 		creds, err := identity.GetCredentials(ctx, r.Client, &vsphereCluster, r.Namespace)
 		if err != nil {
 			log.Error(err, "error retrieving credentials from IdentityRef")
 			continue
 		}
+		tlsConfig, err := identity.GetTLSConfig(ctx, r.Client, &vsphereCluster, r.Namespace)
+		if err != nil {
+			log.Error(err, "error retrieving TLS configuration")
+			continue
+		}
 		log.V(4).Info("Using credentials from VSphereCluster IdentityRef to create the authenticated session")
-		params = params.WithUserInfo(creds.Username, creds.Password)
+		params = params.WithUserInfo(creds.Username, creds.Password).
+			WithTokenSource(creds.TokenSource).
+			WithAllowedTargets(creds.AllowedTargets).
+			WithTLSConfig(tlsConfig)
 		return session.GetOrCreate(ctx, params)
 	}
 
@@ -246,6 +277,17 @@ func (r vsphereDeploymentZoneReconciler) reconcileDelete(ctx context.Context, de
 		return errors.Errorf("blocking VSphereDeploymentZone deletion: currently in use by Machines %s", machineNamesStr)
 	}
 
+	// Control plane machines without a failure domain may have been assigned to the VSphereDeploymentZone.
+	vsphereMachines := &infrav1.VSphereMachineList{}
+	if err := r.Client.List(ctx, vsphereMachines); err != nil {
+		return errors.Wrapf(err, "failed to list VSphereMachines")
+	}
+	for _, vsphereMachine := range vsphereMachines.Items {
+		if vsphereMachine.DeletionTimestamp.IsZero() && ptr.Deref(vsphereMachine.Status.FailureDomain, "") == deploymentZoneCtx.VSphereDeploymentZone.Name {
+			return errors.Errorf("blocking VSphereDeploymentZone deletion: currently in use by VSphereMachine %s", klog.KObj(&vsphereMachine))
+		}
+	}
+
 	failureDomain := &infrav1.VSphereFailureDomain{}
 	failureDomainKey := client.ObjectKey{Name: deploymentZoneCtx.VSphereDeploymentZone.Spec.FailureDomain}
 	// Return an error if the FailureDomain can not be retrieved.
@@ -20,6 +20,8 @@ import (
 	"context"
 
 	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -108,6 +110,15 @@ func (r vsphereDeploymentZoneReconciler) reconcileTopology(ctx context.Context,
 	}
 
 	if hostPlacementInfo := topology.Hosts; hostPlacementInfo != nil {
+		if _, err := cluster.FindHostGroup(ctx, deploymentZoneCtx, *topology.ComputeCluster, hostPlacementInfo.HostGroupName); err != nil {
+			conditions.MarkFalse(deploymentZoneCtx.VSphereDeploymentZone, infrav1.VSphereFailureDomainValidatedCondition, infrav1.HostGroupNotFoundReason, clusterv1.ConditionSeverityError, "host group %s is not found", hostPlacementInfo.HostGroupName)
+			return errors.Wrapf(err, "unable to find host group %s", hostPlacementInfo.HostGroupName)
+		}
+		if _, err := cluster.FindVMGroup(ctx, deploymentZoneCtx, *topology.ComputeCluster, hostPlacementInfo.VMGroupName); err != nil {
+			conditions.MarkFalse(deploymentZoneCtx.VSphereDeploymentZone, infrav1.VSphereFailureDomainValidatedCondition, infrav1.VMGroupNotFoundReason, clusterv1.ConditionSeverityError, "vm group %s is not found", hostPlacementInfo.VMGroupName)
+			return errors.Wrapf(err, "unable to find vm group %s", hostPlacementInfo.VMGroupName)
+		}
+
 		rule, err := cluster.VerifyAffinityRule(ctx, deploymentZoneCtx, *topology.ComputeCluster, hostPlacementInfo.HostGroupName, hostPlacementInfo.VMGroupName)
 		switch {
 		case err != nil:
@@ -119,6 +130,65 @@ func (r vsphereDeploymentZoneReconciler) reconcileTopology(ctx context.Context,
 		default:
 			conditions.MarkTrue(deploymentZoneCtx.VSphereDeploymentZone, infrav1.VSphereFailureDomainValidatedCondition)
 		}
+
+		if topology.VSAN != nil {
+			return r.reconcileVSAN(ctx, deploymentZoneCtx, vsphereFailureDomain, rule)
+		}
+	}
+	return nil
+}
+
+// reconcileVSAN verifies that the hosts and the VM & Host Group affinity rule of the failure domain match
+// its site of the vSAN stretched cluster, and reports the health of the vSAN datastore of the failure domain.
+func (r vsphereDeploymentZoneReconciler) reconcileVSAN(ctx context.Context, deploymentZoneCtx *capvcontext.VSphereDeploymentZoneContext, vsphereFailureDomain *infrav1.VSphereFailureDomain, rule cluster.Rule) error {
+	topology := vsphereFailureDomain.Spec.Topology
+	vsan := topology.VSAN
+
+	if err := cluster.VerifyVSANFaultDomain(ctx, deploymentZoneCtx, *topology.ComputeCluster, topology.Hosts.HostGroupName, vsan.FaultDomain); err != nil {
+		conditions.MarkFalse(deploymentZoneCtx.VSphereDeploymentZone, infrav1.VSphereFailureDomainValidatedCondition, infrav1.VSANMisconfiguredReason, clusterv1.ConditionSeverityError, "hosts are not in vSAN fault domain %s", vsan.FaultDomain)
+		return errors.Wrapf(err, "failed to verify vSAN fault domain %s", vsan.FaultDomain)
+	}
+
+	// The site affinity is enforced by vSphere DRS using the VM & Host Group affinity rule.
+	if required := vsan.SiteAffinity == infrav1.VSANSiteAffinityRequired; rule.IsMandatory() != required {
+		conditions.MarkFalse(deploymentZoneCtx.VSphereDeploymentZone, infrav1.VSphereFailureDomainValidatedCondition, infrav1.VSANMisconfiguredReason, clusterv1.ConditionSeverityError, "vm host affinity does not match vSAN site affinity")
+		if required {
+			return errors.Errorf("vm host affinity rule must be mandatory for vSAN site affinity %s", infrav1.VSANSiteAffinityRequired)
+		}
+		return errors.Errorf("vm host affinity rule must not be mandatory for vSAN site affinity %s", infrav1.VSANSiteAffinityPreferred)
+	}
+
+	if topology.Datastore == "" {
+		return nil
+	}
+	return r.reconcileVSANDatastoreHealth(ctx, deploymentZoneCtx, topology.Datastore)
+}
+
+// reconcileVSANDatastoreHealth sets the VSANDatastoreHealthy condition from the overall status of the datastore.
+func (r vsphereDeploymentZoneReconciler) reconcileVSANDatastoreHealth(ctx context.Context, deploymentZoneCtx *capvcontext.VSphereDeploymentZoneContext, datastore string) error {
+	ds, err := deploymentZoneCtx.AuthSession.Finder.Datastore(ctx, datastore)
+	if err != nil {
+		return errors.Wrapf(err, "unable to find datastore %s", datastore)
+	}
+	var moDatastore mo.Datastore
+	if err := ds.Properties(ctx, ds.Reference(), []string{"summary.type", "overallStatus"}, &moDatastore); err != nil {
+		return errors.Wrapf(err, "unable to get properties of datastore %s", datastore)
+	}
+
+	if moDatastore.Summary.Type != string(types.HostFileSystemVolumeFileSystemTypeVsan) {
+		conditions.MarkFalse(deploymentZoneCtx.VSphereDeploymentZone, infrav1.VSphereFailureDomainValidatedCondition, infrav1.VSANMisconfiguredReason, clusterv1.ConditionSeverityError, "datastore %s is not a vSAN datastore", datastore)
+		return errors.Errorf("datastore %s is of type %s instead of vsan", datastore, moDatastore.Summary.Type)
+	}
+
+	switch moDatastore.OverallStatus {
+	case types.ManagedEntityStatusGreen:
+		conditions.MarkTrue(deploymentZoneCtx.VSphereDeploymentZone, infrav1.VSANDatastoreHealthyCondition)
+	case types.ManagedEntityStatusYellow:
+		conditions.MarkFalse(deploymentZoneCtx.VSphereDeploymentZone, infrav1.VSANDatastoreHealthyCondition, infrav1.VSANDatastoreUnhealthyReason, clusterv1.ConditionSeverityWarning, "vSAN datastore %s is degraded", datastore)
+	case types.ManagedEntityStatusRed:
+		conditions.MarkFalse(deploymentZoneCtx.VSphereDeploymentZone, infrav1.VSANDatastoreHealthyCondition, infrav1.VSANDatastoreUnhealthyReason, clusterv1.ConditionSeverityError, "vSAN datastore %s is unhealthy", datastore)
+	default:
+		conditions.MarkFalse(deploymentZoneCtx.VSphereDeploymentZone, infrav1.VSANDatastoreHealthyCondition, infrav1.VSANDatastoreHealthUnknownReason, clusterv1.ConditionSeverityInfo, "")
 	}
 	return nil
 }
@@ -138,6 +208,7 @@ func (r vsphereDeploymentZoneReconciler) reconcileComputeCluster(ctx context.Con
 	if resourcePool := deploymentZoneCtx.VSphereDeploymentZone.Spec.PlacementConstraint.ResourcePool; resourcePool != "" {
 		rp, err := deploymentZoneCtx.AuthSession.Finder.ResourcePool(ctx, resourcePool)
 		if err != nil {
+			conditions.MarkFalse(deploymentZoneCtx.VSphereDeploymentZone, infrav1.VSphereFailureDomainValidatedCondition, infrav1.ResourcePoolNotFoundReason, clusterv1.ConditionSeverityError, "resource pool %s is misconfigured", resourcePool)
 			return errors.Wrapf(err, "unable to find resource pool")
 		}
 
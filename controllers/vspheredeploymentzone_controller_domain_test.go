@@ -22,7 +22,10 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gbytes"
 	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25/types"
 	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-vsphere/internal/test/helpers/vcsim"
@@ -322,3 +325,239 @@ func TestVsphereDeploymentZoneReconciler_Reconcile_CreateAndAttachMetadata(t *te
 		g.Expect(stdout).To(gbytes.Say("HostSystem"))
 	})
 }
+
+func TestVsphereDeploymentZoneReconciler_ReconcileVSAN(t *testing.T) {
+	g := NewWithT(t)
+
+	simr, err := vcsim.NewBuilder().Build()
+	if err != nil {
+		t.Fatalf("failed to create VC simulator %s", err)
+	}
+	t.Cleanup(simr.Destroy)
+
+	controllerManagerContext := fake.NewControllerManagerContext()
+	params := session.NewParams().
+		WithServer(simr.ServerURL().Host).
+		WithUserInfo(simr.Username(), simr.Password()).
+		WithDatacenter("*")
+	authSession, err := session.GetOrCreate(ctx, params)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// The hosts of the host group are in the preferred site of the stretched cluster.
+	ccr, err := authSession.Finder.ClusterComputeResource(ctx, "DC0_C0")
+	g.Expect(err).NotTo(HaveOccurred())
+	hosts, err := ccr.Hosts(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+	for _, host := range hosts[:2] {
+		simulator.Map.Get(host.Reference()).(*simulator.HostSystem).Config.VsanHostConfig = &types.VsanHostConfigInfo{
+			FaultDomainInfo: &types.VsanHostFaultDomainInfo{Name: "preferred"},
+		}
+	}
+	task, err := ccr.Reconfigure(ctx, &types.ClusterConfigSpecEx{
+		GroupSpec: []types.ClusterGroupSpec{
+			{
+				ArrayUpdateSpec: types.ArrayUpdateSpec{Operation: types.ArrayUpdateOperationAdd},
+				Info: &types.ClusterHostGroup{
+					ClusterGroupInfo: types.ClusterGroupInfo{Name: "preferred-hosts"},
+					Host:             []types.ManagedObjectReference{hosts[0].Reference(), hosts[1].Reference()},
+				},
+			},
+			{
+				ArrayUpdateSpec: types.ArrayUpdateSpec{Operation: types.ArrayUpdateOperationAdd},
+				Info:            &types.ClusterVmGroup{ClusterGroupInfo: types.ClusterGroupInfo{Name: "preferred-vms"}},
+			},
+		},
+		RulesSpec: []types.ClusterRuleSpec{{
+			ArrayUpdateSpec: types.ArrayUpdateSpec{Operation: types.ArrayUpdateOperationAdd},
+			Info: &types.ClusterVmHostRuleInfo{
+				ClusterRuleInfo:     types.ClusterRuleInfo{Name: "preferred-rule", Enabled: ptr.To(true), Mandatory: ptr.To(false)},
+				VmGroupName:         "preferred-vms",
+				AffineHostGroupName: "preferred-hosts",
+			},
+		}},
+	}, true)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(task.Wait(ctx)).To(Succeed())
+
+	ds, err := authSession.Finder.Datastore(ctx, "LocalDS_0")
+	g.Expect(err).NotTo(HaveOccurred())
+	simulatorDatastore := simulator.Map.Get(ds.Reference()).(*simulator.Datastore)
+
+	reconciler := vsphereDeploymentZoneReconciler{controllerManagerContext}
+	newFailureDomain := func(vsan infrav1.VSANTopology, datastore string) *infrav1.VSphereFailureDomain {
+		return &infrav1.VSphereFailureDomain{Spec: infrav1.VSphereFailureDomainSpec{
+			Topology: infrav1.Topology{
+				Datacenter:     "DC0",
+				ComputeCluster: ptr.To("DC0_C0"),
+				Hosts: &infrav1.FailureDomainHosts{
+					HostGroupName: "preferred-hosts",
+					VMGroupName:   "preferred-vms",
+				},
+				Datastore: datastore,
+				VSAN:      &vsan,
+			},
+		}}
+	}
+	newDeploymentZoneCtx := func() *capvcontext.VSphereDeploymentZoneContext {
+		return &capvcontext.VSphereDeploymentZoneContext{
+			ControllerManagerContext: controllerManagerContext,
+			VSphereDeploymentZone:    &infrav1.VSphereDeploymentZone{},
+			AuthSession:              authSession,
+		}
+	}
+
+	t.Run("hosts are in the fault domain", func(t *testing.T) {
+		g := NewWithT(t)
+		deploymentZoneCtx := newDeploymentZoneCtx()
+		g.Expect(reconciler.reconcileTopology(ctx, deploymentZoneCtx, newFailureDomain(infrav1.VSANTopology{FaultDomain: "preferred"}, ""))).To(Succeed())
+		g.Expect(conditions.IsTrue(deploymentZoneCtx.VSphereDeploymentZone, infrav1.VSphereFailureDomainValidatedCondition)).To(BeTrue())
+		g.Expect(conditions.Has(deploymentZoneCtx.VSphereDeploymentZone, infrav1.VSANDatastoreHealthyCondition)).To(BeFalse())
+	})
+
+	t.Run("hosts are not in the fault domain", func(t *testing.T) {
+		g := NewWithT(t)
+		deploymentZoneCtx := newDeploymentZoneCtx()
+		g.Expect(reconciler.reconcileTopology(ctx, deploymentZoneCtx, newFailureDomain(infrav1.VSANTopology{FaultDomain: "secondary"}, ""))).NotTo(Succeed())
+		g.Expect(conditions.GetReason(deploymentZoneCtx.VSphereDeploymentZone, infrav1.VSphereFailureDomainValidatedCondition)).To(Equal(infrav1.VSANMisconfiguredReason))
+	})
+
+	t.Run("affinity rule does not match the site affinity", func(t *testing.T) {
+		g := NewWithT(t)
+		deploymentZoneCtx := newDeploymentZoneCtx()
+		vsan := infrav1.VSANTopology{FaultDomain: "preferred", SiteAffinity: infrav1.VSANSiteAffinityRequired}
+		g.Expect(reconciler.reconcileTopology(ctx, deploymentZoneCtx, newFailureDomain(vsan, ""))).To(MatchError(ContainSubstring("must be mandatory")))
+		g.Expect(conditions.GetReason(deploymentZoneCtx.VSphereDeploymentZone, infrav1.VSphereFailureDomainValidatedCondition)).To(Equal(infrav1.VSANMisconfiguredReason))
+	})
+
+	t.Run("datastore is not a vSAN datastore", func(t *testing.T) {
+		g := NewWithT(t)
+		deploymentZoneCtx := newDeploymentZoneCtx()
+		g.Expect(reconciler.reconcileTopology(ctx, deploymentZoneCtx, newFailureDomain(infrav1.VSANTopology{FaultDomain: "preferred"}, "LocalDS_0"))).NotTo(Succeed())
+		g.Expect(conditions.GetReason(deploymentZoneCtx.VSphereDeploymentZone, infrav1.VSphereFailureDomainValidatedCondition)).To(Equal(infrav1.VSANMisconfiguredReason))
+	})
+
+	t.Run("vSAN datastore health is reported", func(t *testing.T) {
+		simulatorDatastore.Summary.Type = string(types.HostFileSystemVolumeFileSystemTypeVsan)
+		failureDomain := newFailureDomain(infrav1.VSANTopology{FaultDomain: "preferred"}, "LocalDS_0")
+
+		for _, tt := range []struct {
+			status   types.ManagedEntityStatus
+			healthy  bool
+			severity clusterv1.ConditionSeverity
+		}{
+			{status: types.ManagedEntityStatusGreen, healthy: true},
+			{status: types.ManagedEntityStatusYellow, severity: clusterv1.ConditionSeverityWarning},
+			{status: types.ManagedEntityStatusRed, severity: clusterv1.ConditionSeverityError},
+			{status: types.ManagedEntityStatusGray, severity: clusterv1.ConditionSeverityInfo},
+		} {
+			t.Run(string(tt.status), func(t *testing.T) {
+				g := NewWithT(t)
+				simulatorDatastore.OverallStatus = tt.status
+				deploymentZoneCtx := newDeploymentZoneCtx()
+				g.Expect(reconciler.reconcileTopology(ctx, deploymentZoneCtx, failureDomain)).To(Succeed())
+				g.Expect(conditions.IsTrue(deploymentZoneCtx.VSphereDeploymentZone, infrav1.VSANDatastoreHealthyCondition)).To(Equal(tt.healthy))
+				if !tt.healthy {
+					g.Expect(*conditions.GetSeverity(deploymentZoneCtx.VSphereDeploymentZone, infrav1.VSANDatastoreHealthyCondition)).To(Equal(tt.severity))
+				}
+			})
+		}
+	})
+}
+
+func TestVsphereDeploymentZoneReconciler_ReconcileTopology_RemovedObjects(t *testing.T) {
+	g := NewWithT(t)
+
+	simr, err := vcsim.NewBuilder().Build()
+	if err != nil {
+		t.Fatalf("failed to create VC simulator %s", err)
+	}
+	t.Cleanup(simr.Destroy)
+
+	controllerManagerContext := fake.NewControllerManagerContext()
+	params := session.NewParams().
+		WithServer(simr.ServerURL().Host).
+		WithUserInfo(simr.Username(), simr.Password()).
+		WithDatacenter("*")
+	authSession, err := session.GetOrCreate(ctx, params)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ccr, err := authSession.Finder.ClusterComputeResource(ctx, "DC0_C0")
+	g.Expect(err).NotTo(HaveOccurred())
+	hosts, err := ccr.Hosts(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+	task, err := ccr.Reconfigure(ctx, &types.ClusterConfigSpecEx{
+		GroupSpec: []types.ClusterGroupSpec{
+			{
+				ArrayUpdateSpec: types.ArrayUpdateSpec{Operation: types.ArrayUpdateOperationAdd},
+				Info: &types.ClusterHostGroup{
+					ClusterGroupInfo: types.ClusterGroupInfo{Name: "test-hosts"},
+					Host:             []types.ManagedObjectReference{hosts[0].Reference()},
+				},
+			},
+			{
+				ArrayUpdateSpec: types.ArrayUpdateSpec{Operation: types.ArrayUpdateOperationAdd},
+				Info:            &types.ClusterVmGroup{ClusterGroupInfo: types.ClusterGroupInfo{Name: "test-vms"}},
+			},
+		},
+		RulesSpec: []types.ClusterRuleSpec{{
+			ArrayUpdateSpec: types.ArrayUpdateSpec{Operation: types.ArrayUpdateOperationAdd},
+			Info: &types.ClusterVmHostRuleInfo{
+				ClusterRuleInfo:     types.ClusterRuleInfo{Name: "test-rule", Enabled: ptr.To(true)},
+				VmGroupName:         "test-vms",
+				AffineHostGroupName: "test-hosts",
+			},
+		}},
+	}, true)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(task.Wait(ctx)).To(Succeed())
+
+	reconciler := vsphereDeploymentZoneReconciler{controllerManagerContext}
+	newFailureDomain := func(hostGroup, vmGroup string) *infrav1.VSphereFailureDomain {
+		return &infrav1.VSphereFailureDomain{Spec: infrav1.VSphereFailureDomainSpec{
+			Topology: infrav1.Topology{
+				Datacenter:     "DC0",
+				ComputeCluster: ptr.To("DC0_C0"),
+				Hosts: &infrav1.FailureDomainHosts{
+					HostGroupName: hostGroup,
+					VMGroupName:   vmGroup,
+				},
+			},
+		}}
+	}
+	newDeploymentZoneCtx := func() *capvcontext.VSphereDeploymentZoneContext {
+		return &capvcontext.VSphereDeploymentZoneContext{
+			ControllerManagerContext: controllerManagerContext,
+			VSphereDeploymentZone:    &infrav1.VSphereDeploymentZone{},
+			AuthSession:              authSession,
+		}
+	}
+
+	t.Run("host and vm groups exist", func(t *testing.T) {
+		g := NewWithT(t)
+		deploymentZoneCtx := newDeploymentZoneCtx()
+		g.Expect(reconciler.reconcileTopology(ctx, deploymentZoneCtx, newFailureDomain("test-hosts", "test-vms"))).To(Succeed())
+		g.Expect(conditions.IsTrue(deploymentZoneCtx.VSphereDeploymentZone, infrav1.VSphereFailureDomainValidatedCondition)).To(BeTrue())
+	})
+
+	t.Run("host group does not exist", func(t *testing.T) {
+		g := NewWithT(t)
+		deploymentZoneCtx := newDeploymentZoneCtx()
+		g.Expect(reconciler.reconcileTopology(ctx, deploymentZoneCtx, newFailureDomain("renamed-hosts", "test-vms"))).NotTo(Succeed())
+		g.Expect(conditions.GetReason(deploymentZoneCtx.VSphereDeploymentZone, infrav1.VSphereFailureDomainValidatedCondition)).To(Equal(infrav1.HostGroupNotFoundReason))
+	})
+
+	t.Run("vm group does not exist", func(t *testing.T) {
+		g := NewWithT(t)
+		deploymentZoneCtx := newDeploymentZoneCtx()
+		g.Expect(reconciler.reconcileTopology(ctx, deploymentZoneCtx, newFailureDomain("test-hosts", "renamed-vms"))).NotTo(Succeed())
+		g.Expect(conditions.GetReason(deploymentZoneCtx.VSphereDeploymentZone, infrav1.VSphereFailureDomainValidatedCondition)).To(Equal(infrav1.VMGroupNotFoundReason))
+	})
+
+	t.Run("resource pool of the placement constraint does not exist", func(t *testing.T) {
+		g := NewWithT(t)
+		deploymentZoneCtx := newDeploymentZoneCtx()
+		deploymentZoneCtx.VSphereDeploymentZone.Spec.PlacementConstraint.ResourcePool = "renamed-pool"
+		g.Expect(reconciler.reconcileComputeCluster(ctx, deploymentZoneCtx, newFailureDomain("test-hosts", "test-vms"))).NotTo(Succeed())
+		g.Expect(conditions.GetReason(deploymentZoneCtx.VSphereDeploymentZone, infrav1.VSphereFailureDomainValidatedCondition)).To(Equal(infrav1.ResourcePoolNotFoundReason))
+	})
+}
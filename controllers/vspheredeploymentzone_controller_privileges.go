@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/object"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/govmomi/privileges"
+)
+
+// reconcilePrivileges verifies that the vCenter credentials have the minimum privileges on the objects
+// of the failure domain and the placement constraint, to report missing privileges before VMs fail to be
+// created with NoPermission faults. Missing privileges don't prevent the deployment zone from being ready,
+// as privileges can also be granted on the VMs, templates or child objects only.
+func (r vsphereDeploymentZoneReconciler) reconcilePrivileges(ctx context.Context, deploymentZoneCtx *capvcontext.VSphereDeploymentZoneContext, vsphereFailureDomain *infrav1.VSphereFailureDomain) {
+	log := ctrl.LoggerFrom(ctx)
+
+	entities, err := r.privilegeEntities(ctx, deploymentZoneCtx, vsphereFailureDomain)
+	if err != nil {
+		log.Error(err, "Failed to check vCenter privileges")
+		conditions.MarkFalse(deploymentZoneCtx.VSphereDeploymentZone, infrav1.VCenterPrivilegesVerifiedCondition, infrav1.PrivilegesCheckFailedReason, clusterv1.ConditionSeverityInfo, err.Error())
+		return
+	}
+	missing, err := privileges.Check(ctx, deploymentZoneCtx.AuthSession, entities)
+	if err != nil {
+		log.Error(err, "Failed to check vCenter privileges")
+		conditions.MarkFalse(deploymentZoneCtx.VSphereDeploymentZone, infrav1.VCenterPrivilegesVerifiedCondition, infrav1.PrivilegesCheckFailedReason, clusterv1.ConditionSeverityInfo, err.Error())
+		return
+	}
+	if len(missing) > 0 {
+		report := privileges.Report(missing)
+		log.Info("vCenter credentials are missing privileges", "missingPrivileges", report)
+		conditions.MarkFalse(deploymentZoneCtx.VSphereDeploymentZone, infrav1.VCenterPrivilegesVerifiedCondition, infrav1.MissingPrivilegesReason, clusterv1.ConditionSeverityWarning, "missing privileges: %s", report)
+		return
+	}
+	conditions.MarkTrue(deploymentZoneCtx.VSphereDeploymentZone, infrav1.VCenterPrivilegesVerifiedCondition)
+}
+
+// privilegeEntities returns the vCenter objects of the failure domain and the placement constraint
+// on which privileges are required.
+func (r vsphereDeploymentZoneReconciler) privilegeEntities(ctx context.Context, deploymentZoneCtx *capvcontext.VSphereDeploymentZoneContext, vsphereFailureDomain *infrav1.VSphereFailureDomain) ([]privileges.Entity, error) {
+	finder := deploymentZoneCtx.AuthSession.Finder
+	topology := vsphereFailureDomain.Spec.Topology
+	placementConstraint := deploymentZoneCtx.VSphereDeploymentZone.Spec.PlacementConstraint
+
+	var entities []privileges.Entity
+	add := func(kind privileges.Kind, obj object.Reference, path string) {
+		entities = append(entities, privileges.Entity{Kind: kind, Path: path, Ref: obj.Reference()})
+	}
+
+	datacenter, err := finder.Datacenter(ctx, topology.Datacenter)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to find datacenter %s", topology.Datacenter)
+	}
+	add(privileges.Datacenter, datacenter, datacenter.InventoryPath)
+
+	if computeCluster := topology.ComputeCluster; computeCluster != nil {
+		ccr, err := finder.ClusterComputeResource(ctx, *computeCluster)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to find compute cluster %s", *computeCluster)
+		}
+		add(privileges.ComputeCluster, ccr, ccr.InventoryPath)
+	}
+	if resourcePool := placementConstraint.ResourcePool; resourcePool != "" {
+		rp, err := finder.ResourcePool(ctx, resourcePool)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to find resource pool %s", resourcePool)
+		}
+		add(privileges.ResourcePool, rp, rp.InventoryPath)
+	}
+	if folder := placementConstraint.Folder; folder != "" {
+		f, err := finder.Folder(ctx, folder)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to find folder %s", folder)
+		}
+		add(privileges.Folder, f, f.InventoryPath)
+	}
+	if datastore := topology.Datastore; datastore != "" {
+		ds, err := finder.Datastore(ctx, datastore)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to find datastore %s", datastore)
+		}
+		add(privileges.Datastore, ds, ds.InventoryPath)
+	}
+
+	networks := append([]string{}, topology.Networks...)
+	for _, networkConfig := range topology.NetworkConfigurations {
+		networks = append(networks, networkConfig.NetworkName)
+	}
+	for _, network := range networks {
+		n, err := finder.Network(ctx, network)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to find network %s", network)
+		}
+		add(privileges.Network, n, n.GetInventoryPath())
+	}
+	return entities, nil
+}
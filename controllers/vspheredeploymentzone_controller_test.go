@@ -383,7 +383,8 @@ func TestVSphereDeploymentZone_Reconcile(t *testing.T) {
 			}
 			return conditions.IsTrue(vsphereDeploymentZone, infrav1.VCenterAvailableCondition) &&
 				conditions.IsTrue(vsphereDeploymentZone, infrav1.PlacementConstraintMetCondition) &&
-				conditions.IsTrue(vsphereDeploymentZone, infrav1.VSphereFailureDomainValidatedCondition)
+				conditions.IsTrue(vsphereDeploymentZone, infrav1.VSphereFailureDomainValidatedCondition) &&
+				conditions.IsTrue(vsphereDeploymentZone, infrav1.VCenterPrivilegesVerifiedCondition)
 		}, timeout).Should(BeTrue())
 
 		g.Expect(testEnv.Get(ctx, client.ObjectKeyFromObject(vsphereFailureDomain), vsphereFailureDomain)).To(Succeed())
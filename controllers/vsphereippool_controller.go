@@ -0,0 +1,320 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/identity"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/govmomi/ippool"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/session"
+)
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=vsphereippools,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddressclaims,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddressclaims/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddresses,verbs=get;list;watch;create;delete
+
+// AddVSphereIPPoolControllerToManager adds the controller allocating the IP addresses of the
+// IPAddressClaims referencing a VSphereIPPool to the provided manager.
+func AddVSphereIPPoolControllerToManager(ctx context.Context, controllerManagerCtx *capvcontext.ControllerManagerContext, mgr manager.Manager, options controller.Options) error {
+	r := &vsphereIPPoolReconciler{
+		ControllerManagerContext: controllerManagerCtx,
+		Client:                   controllerManagerCtx.Client,
+	}
+	predicateLog := ctrl.LoggerFrom(ctx).WithValues("controller", "vsphereippool")
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("vsphereippool").
+		For(&ipamv1.IPAddressClaim{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(o client.Object) bool {
+			return isVSphereIPPoolRef(o.(*ipamv1.IPAddressClaim).Spec.PoolRef)
+		}))).
+		WithOptions(options).
+		Watches(
+			&infrav1.VSphereIPPool{},
+			handler.EnqueueRequestsFromMapFunc(r.vsphereIPPoolToIPAddressClaims),
+		).
+		Owns(&ipamv1.IPAddress{}).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(mgr.GetScheme(), predicateLog, controllerManagerCtx.WatchFilterValue)).
+		Complete(r)
+}
+
+type vsphereIPPoolReconciler struct {
+	*capvcontext.ControllerManagerContext
+	Client client.Client
+}
+
+// Reconcile allocates an IP address from the vCenter IP pool of the VSphereIPPool referenced
+// by an IPAddressClaim, and releases it when the IPAddressClaim is deleted.
+func (r *vsphereIPPoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	claim := &ipamv1.IPAddressClaim{}
+	if err := r.Client.Get(ctx, req.NamespacedName, claim); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if !isVSphereIPPoolRef(claim.Spec.PoolRef) {
+		return reconcile.Result{}, nil
+	}
+
+	if claim.Spec.ClusterName != "" {
+		cluster := &clusterv1.Cluster{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: claim.Namespace, Name: claim.Spec.ClusterName}, cluster); err != nil && !apierrors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		} else if err == nil && annotations.IsPaused(cluster, claim) {
+			log.Info("Reconciliation is paused for this object")
+			return reconcile.Result{}, nil
+		}
+	} else if annotations.HasPaused(claim) {
+		log.Info("Reconciliation is paused for this object")
+		return reconcile.Result{}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(claim, r.Client)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	defer func() {
+		if err := patchHelper.Patch(ctx, claim); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	vsphereIPPool := &infrav1.VSphereIPPool{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: claim.Namespace, Name: claim.Spec.PoolRef.Name}, vsphereIPPool); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		vsphereIPPool = nil
+	}
+
+	if !claim.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, r.reconcileDelete(ctx, claim, vsphereIPPool)
+	}
+	return reconcile.Result{}, r.reconcileNormal(ctx, claim, vsphereIPPool)
+}
+
+func (r *vsphereIPPoolReconciler) reconcileNormal(ctx context.Context, claim *ipamv1.IPAddressClaim, vsphereIPPool *infrav1.VSphereIPPool) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	if vsphereIPPool == nil {
+		conditions.MarkFalse(claim, clusterv1.ReadyCondition, ipamv1.PoolNotReadyReason, clusterv1.ConditionSeverityError,
+			"VSphereIPPool %s not found", claim.Spec.PoolRef.Name)
+		return nil
+	}
+
+	// The finalizer is persisted before allocating, so the IP address is always released.
+	if ctrlutil.AddFinalizer(claim, infrav1.IPPoolAllocationFinalizer) {
+		return nil
+	}
+
+	address := &ipamv1.IPAddress{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: claim.Namespace, Name: claim.Name}, address); err == nil {
+		claim.Status.AddressRef = corev1.LocalObjectReference{Name: address.Name}
+		conditions.MarkTrue(claim, clusterv1.ReadyCondition)
+		return nil
+	} else if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	authSession, err := r.getVCenterSession(ctx, vsphereIPPool)
+	if err != nil {
+		conditions.MarkFalse(claim, clusterv1.ReadyCondition, ipamv1.PoolNotReadyReason, clusterv1.ConditionSeverityError, err.Error())
+		return err
+	}
+	pool, err := ippool.Find(ctx, authSession, vsphereIPPool.Spec.Datacenter, vsphereIPPool.Spec.PoolName)
+	if err != nil {
+		conditions.MarkFalse(claim, clusterv1.ReadyCondition, ipamv1.PoolNotReadyReason, clusterv1.ConditionSeverityError, err.Error())
+		return err
+	}
+
+	// The UID of the claim is used as allocation ID, so allocating again for the claim,
+	// e.g. if the IPAddress failed to be created, returns the same IP address.
+	allocated, err := pool.Allocate(ctx, authSession, string(claim.UID))
+	if err != nil {
+		reason := ipamv1.AllocationFailedReason
+		if errors.Is(err, ippool.ErrPoolExhausted) {
+			reason = ipamv1.PoolExhaustedReason
+		}
+		conditions.MarkFalse(claim, clusterv1.ReadyCondition, reason, clusterv1.ConditionSeverityError, err.Error())
+		return err
+	}
+
+	address = &ipamv1.IPAddress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      claim.Name,
+			Namespace: claim.Namespace,
+			Labels:    claim.Labels,
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion:         ipamv1.GroupVersion.String(),
+					Kind:               "IPAddressClaim",
+					Name:               claim.Name,
+					UID:                claim.UID,
+					Controller:         ptr.To(true),
+					BlockOwnerDeletion: ptr.To(true),
+				},
+			},
+		},
+		Spec: ipamv1.IPAddressSpec{
+			ClaimRef: corev1.LocalObjectReference{Name: claim.Name},
+			PoolRef:  claim.Spec.PoolRef,
+			Address:  allocated.Address,
+			Prefix:   allocated.Prefix,
+			Gateway:  allocated.Gateway,
+		},
+	}
+	if err := r.Client.Create(ctx, address); err != nil {
+		return errors.Wrapf(err, "failed to create IPAddress %s", klog.KObj(address))
+	}
+	log.Info("Allocated IP address", "IPAddress", klog.KObj(address), "address", allocated.Address)
+
+	claim.Status.AddressRef = corev1.LocalObjectReference{Name: address.Name}
+	conditions.MarkTrue(claim, clusterv1.ReadyCondition)
+	return nil
+}
+
+func (r *vsphereIPPoolReconciler) reconcileDelete(ctx context.Context, claim *ipamv1.IPAddressClaim, vsphereIPPool *infrav1.VSphereIPPool) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	if !ctrlutil.ContainsFinalizer(claim, infrav1.IPPoolAllocationFinalizer) {
+		return nil
+	}
+
+	// The IP address can't be released if the VSphereIPPool was deleted first.
+	if vsphereIPPool != nil {
+		authSession, err := r.getVCenterSession(ctx, vsphereIPPool)
+		if err != nil {
+			return err
+		}
+		pool, err := ippool.Find(ctx, authSession, vsphereIPPool.Spec.Datacenter, vsphereIPPool.Spec.PoolName)
+		switch {
+		case errors.Is(err, ippool.ErrPoolNotFound):
+			// The IP address was released together with the vCenter IP pool.
+			log.Info("vCenter IP pool not found, skipping the release of the IP address", "poolName", vsphereIPPool.Spec.PoolName)
+		case err != nil:
+			return err
+		default:
+			if err := pool.Release(ctx, authSession, string(claim.UID)); err != nil {
+				return err
+			}
+		}
+	} else {
+		log.Info("VSphereIPPool not found, skipping the release of the IP address", "VSphereIPPool", claim.Spec.PoolRef.Name)
+	}
+
+	address := &ipamv1.IPAddress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      claim.Name,
+			Namespace: claim.Namespace,
+		},
+	}
+	if err := r.Client.Delete(ctx, address); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete IPAddress %s", klog.KObj(address))
+	}
+
+	ctrlutil.RemoveFinalizer(claim, infrav1.IPPoolAllocationFinalizer)
+	return nil
+}
+
+// getVCenterSession returns a session to the vCenter of the VSphereIPPool, using its
+// identity or the credentials provided to the manager.
+func (r *vsphereIPPoolReconciler) getVCenterSession(ctx context.Context, vsphereIPPool *infrav1.VSphereIPPool) (*session.Session, error) {
+	params := session.NewParams().
+		WithServer(vsphereIPPool.Spec.Server).
+		WithDatacenter(vsphereIPPool.Spec.Datacenter).
+		WithThumbprint(vsphereIPPool.Spec.Thumbprint).
+		WithUserInfo(r.ControllerManagerContext.Username, r.ControllerManagerContext.Password).
+		WithProxy(r.ControllerManagerContext.VCenterProxy)
+
+	if vsphereIPPool.Spec.IdentityRef == nil {
+		return session.GetOrCreate(ctx, params)
+	}
+
+	// The identity of a VSphereIPPool is resolved like the identity of a VSphereCluster
+	// in the same namespace.
+	vsphereCluster := &infrav1.VSphereCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: vsphereIPPool.Namespace, Name: vsphereIPPool.Name},
+		Spec: infrav1.VSphereClusterSpec{
+			Server:      vsphereIPPool.Spec.Server,
+			Thumbprint:  vsphereIPPool.Spec.Thumbprint,
+			IdentityRef: vsphereIPPool.Spec.IdentityRef,
+		},
+	}
+	creds, err := identity.GetCredentials(ctx, r.Client, vsphereCluster, r.ControllerManagerContext.Namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get credentials from IdentityRef")
+	}
+	tlsConfig, err := identity.GetTLSConfig(ctx, r.Client, vsphereCluster, r.ControllerManagerContext.Namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get TLS configuration")
+	}
+	params = params.WithUserInfo(creds.Username, creds.Password).
+		WithTokenSource(creds.TokenSource).
+		WithAllowedTargets(creds.AllowedTargets).
+		WithTLSConfig(tlsConfig)
+	return session.GetOrCreate(ctx, params)
+}
+
+// vsphereIPPoolToIPAddressClaims maps a VSphereIPPool to the IPAddressClaims referencing it.
+func (r *vsphereIPPoolReconciler) vsphereIPPoolToIPAddressClaims(ctx context.Context, o client.Object) []reconcile.Request {
+	claims := &ipamv1.IPAddressClaimList{}
+	if err := r.Client.List(ctx, claims, client.InNamespace(o.GetNamespace())); err != nil {
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, claim := range claims.Items {
+		if isVSphereIPPoolRef(claim.Spec.PoolRef) && claim.Spec.PoolRef.Name == o.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&claim)})
+		}
+	}
+	return requests
+}
+
+// isVSphereIPPoolRef returns true if the pool reference is a VSphereIPPool.
+func isVSphereIPPoolRef(ref corev1.TypedLocalObjectReference) bool {
+	return ref.Kind == infrav1.VSphereIPPoolKind && ptr.Deref(ref.APIGroup, "") == infrav1.GroupVersion.Group
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/types"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/internal/test/helpers/vcsim"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/context/fake"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/session"
+)
+
+func TestVSphereIPPoolReconciler(t *testing.T) {
+	g := NewWithT(t)
+
+	simr, err := vcsim.NewBuilder().Build()
+	if err != nil {
+		t.Fatalf("failed to create VC simulator %s", err)
+	}
+	t.Cleanup(simr.Destroy)
+
+	// Create an IP pool with a valid netmask in the datacenter.
+	authSession, err := session.GetOrCreate(ctx, session.NewParams().
+		WithServer(simr.ServerURL().Host).
+		WithUserInfo(simr.Username(), simr.Password()).
+		WithDatacenter("DC0"))
+	g.Expect(err).ToNot(HaveOccurred())
+	dc, err := authSession.Finder.Datacenter(ctx, "DC0")
+	g.Expect(err).ToNot(HaveOccurred())
+	pool := types.IpPool{
+		Name:                   "pool",
+		AvailableIpv4Addresses: 5,
+		Ipv4Config: &types.IpPoolIpPoolConfigInfo{
+			SubnetAddress: "192.168.10.0",
+			Netmask:       "255.255.255.0",
+			Gateway:       "192.168.10.1",
+			Range:         "192.168.10.10#5",
+			IpPoolEnabled: ptr.To(true),
+		},
+	}
+	res, err := methods.CreateIpPool(ctx, authSession.Client.Client, &types.CreateIpPool{
+		This: *authSession.Client.ServiceContent.IpPoolManager,
+		Dc:   dc.Reference(),
+		Pool: pool,
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	// vcsim doesn't set the ID in the configuration of created IP pools.
+	pool.Id = res.Returnval
+	_, err = methods.UpdateIpPool(ctx, authSession.Client.Client, &types.UpdateIpPool{
+		This: *authSession.Client.ServiceContent.IpPoolManager,
+		Dc:   dc.Reference(),
+		Pool: pool,
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	vsphereIPPool := &infrav1.VSphereIPPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "vsphere-ip-pool", Namespace: "test"},
+		Spec: infrav1.VSphereIPPoolSpec{
+			Server:     simr.ServerURL().Host,
+			Datacenter: "DC0",
+			PoolName:   "pool",
+		},
+	}
+	newClaim := func(poolName string) *ipamv1.IPAddressClaim {
+		return &ipamv1.IPAddressClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "test", UID: "9c4c5c5e-0e5b-4a4e-8d6c-3c3b8e0a8e1f"},
+			Spec: ipamv1.IPAddressClaimSpec{
+				PoolRef: corev1.TypedLocalObjectReference{
+					APIGroup: ptr.To(infrav1.GroupVersion.Group),
+					Kind:     infrav1.VSphereIPPoolKind,
+					Name:     poolName,
+				},
+			},
+		}
+	}
+	setup := func(objs ...client.Object) *vsphereIPPoolReconciler {
+		controllerManagerCtx := fake.NewControllerManagerContext(objs...)
+		controllerManagerCtx.Username = simr.Username()
+		controllerManagerCtx.Password = simr.Password()
+		return &vsphereIPPoolReconciler{
+			ControllerManagerContext: controllerManagerCtx,
+			Client:                   controllerManagerCtx.Client,
+		}
+	}
+	req := ctrl.Request{NamespacedName: client.ObjectKey{Namespace: "test", Name: "claim"}}
+
+	t.Run("allocates and releases an IP address", func(t *testing.T) {
+		g := NewWithT(t)
+		r := setup(vsphereIPPool.DeepCopy(), newClaim(vsphereIPPool.Name))
+
+		// The first reconcile adds the finalizer, the second one allocates the IP address.
+		for range 2 {
+			_, err := r.Reconcile(ctx, req)
+			g.Expect(err).ToNot(HaveOccurred())
+		}
+
+		claim := &ipamv1.IPAddressClaim{}
+		g.Expect(r.Client.Get(ctx, req.NamespacedName, claim)).To(Succeed())
+		g.Expect(claim.Finalizers).To(ContainElement(infrav1.IPPoolAllocationFinalizer))
+		g.Expect(claim.Status.AddressRef.Name).To(Equal("claim"))
+		g.Expect(conditions.IsTrue(claim, clusterv1.ReadyCondition)).To(BeTrue())
+
+		address := &ipamv1.IPAddress{}
+		g.Expect(r.Client.Get(ctx, req.NamespacedName, address)).To(Succeed())
+		g.Expect(address.Spec.Address).To(HavePrefix("192.168.10."))
+		g.Expect(address.Spec.Prefix).To(Equal(24))
+		g.Expect(address.Spec.Gateway).To(Equal("192.168.10.1"))
+		g.Expect(address.Spec.ClaimRef.Name).To(Equal("claim"))
+		g.Expect(address.Spec.PoolRef).To(Equal(claim.Spec.PoolRef))
+
+		// Reconciling again keeps the same IP address.
+		_, err = r.Reconcile(ctx, req)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(r.Client.Get(ctx, req.NamespacedName, address)).To(Succeed())
+		g.Expect(address.Spec.Address).To(HavePrefix("192.168.10."))
+
+		g.Expect(r.Client.Delete(ctx, claim)).To(Succeed())
+		_, err = r.Reconcile(ctx, req)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(apierrors.IsNotFound(r.Client.Get(ctx, req.NamespacedName, claim))).To(BeTrue())
+		g.Expect(apierrors.IsNotFound(r.Client.Get(ctx, req.NamespacedName, address))).To(BeTrue())
+	})
+
+	t.Run("reports a missing VSphereIPPool", func(t *testing.T) {
+		g := NewWithT(t)
+		r := setup(newClaim("missing"))
+
+		_, err := r.Reconcile(ctx, req)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		claim := &ipamv1.IPAddressClaim{}
+		g.Expect(r.Client.Get(ctx, req.NamespacedName, claim)).To(Succeed())
+		g.Expect(claim.Finalizers).To(BeEmpty())
+		g.Expect(conditions.GetReason(claim, clusterv1.ReadyCondition)).To(Equal(ipamv1.PoolNotReadyReason))
+	})
+
+	t.Run("reports a missing vCenter IP pool", func(t *testing.T) {
+		g := NewWithT(t)
+		pool := vsphereIPPool.DeepCopy()
+		pool.Spec.PoolName = "missing"
+		r := setup(pool, newClaim(pool.Name))
+
+		_, err := r.Reconcile(ctx, req)
+		g.Expect(err).ToNot(HaveOccurred())
+		_, err = r.Reconcile(ctx, req)
+		g.Expect(err).To(HaveOccurred())
+
+		claim := &ipamv1.IPAddressClaim{}
+		g.Expect(r.Client.Get(ctx, req.NamespacedName, claim)).To(Succeed())
+		g.Expect(conditions.GetReason(claim, clusterv1.ReadyCondition)).To(Equal(ipamv1.PoolNotReadyReason))
+	})
+
+	t.Run("removes the finalizer if the vCenter IP pool was deleted", func(t *testing.T) {
+		g := NewWithT(t)
+		pool := vsphereIPPool.DeepCopy()
+		pool.Spec.PoolName = "missing"
+		claim := newClaim(pool.Name)
+		claim.Finalizers = []string{infrav1.IPPoolAllocationFinalizer}
+		r := setup(pool, claim)
+
+		g.Expect(r.Client.Delete(ctx, claim)).To(Succeed())
+		_, err := r.Reconcile(ctx, req)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(apierrors.IsNotFound(r.Client.Get(ctx, req.NamespacedName, claim))).To(BeTrue())
+	})
+
+	t.Run("ignores IPAddressClaims of other pools", func(t *testing.T) {
+		g := NewWithT(t)
+		claim := newClaim(vsphereIPPool.Name)
+		claim.Spec.PoolRef.Kind = "InClusterIPPool"
+		claim.Spec.PoolRef.APIGroup = ptr.To("ipam.cluster.x-k8s.io")
+		r := setup(vsphereIPPool.DeepCopy(), claim)
+
+		_, err := r.Reconcile(ctx, req)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(r.Client.Get(ctx, req.NamespacedName, claim)).To(Succeed())
+		g.Expect(claim.Finalizers).To(BeEmpty())
+	})
+}
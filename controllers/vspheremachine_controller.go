@@ -56,9 +56,11 @@ import (
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/constants"
 	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/context/vmware"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/diagnostics"
 	inframanager "sigs.k8s.io/cluster-api-provider-vsphere/pkg/manager"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/vmoperator"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/tracing"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/util"
 )
 
@@ -87,6 +89,8 @@ func AddMachineControllerToManager(ctx context.Context, controllerManagerContext
 		Recorder:        mgr.GetEventRecorderFor("vspheremachine-controller"),
 		VMService:       &services.VimMachineService{Client: controllerManagerContext.Client},
 		supervisorBased: supervisorBased,
+
+		preTerminateHookTimeout: controllerManagerContext.PreTerminateHookTimeout,
 	}
 	predicateLog := ctrl.LoggerFrom(ctx).WithValues("controller", "vspheremachine")
 
@@ -98,21 +102,52 @@ func AddMachineControllerToManager(ctx context.Context, controllerManagerContext
 		r.networkProvider = networkProvider
 		r.VMService = &vmoperator.VmopMachineService{Client: controllerManagerContext.Client, ConfigureControlPlaneVMReadinessProbe: r.networkProvider.SupportsVMReadinessProbe()}
 
+		return addControllersWithControlPlanePriority(ctx, controllerManagerContext, mgr, "vspheremachine", func() client.Object { return &vmwarev1.VSphereMachine{} }, options, func(name string, options controller.Options) error {
+			return ctrl.NewControllerManagedBy(mgr).
+				Named(name).
+				// Watch the controlled, infrastructure resource.
+				For(&vmwarev1.VSphereMachine{}).
+				WithOptions(options).
+				// Watch the CAPI resource that owns this infrastructure resource.
+				Watches(
+					&clusterv1.Machine{},
+					handler.EnqueueRequestsFromMapFunc(clusterutilv1.MachineToInfrastructureMapFunc(vmwarev1.GroupVersion.WithKind("VSphereMachine"))),
+				).
+				Watches(
+					&clusterv1.Cluster{},
+					handler.EnqueueRequestsFromMapFunc(r.enqueueClusterToMachineRequests),
+					ctrlbldr.WithPredicates(
+						predicates.ClusterUnpausedAndInfrastructureReady(mgr.GetScheme(), predicateLog),
+					),
+				).
+				// Watch a GenericEvent channel for the controlled resource.
+				//
+				// This is useful when there are events outside of Kubernetes that
+				// should cause a resource to be synchronized, such as a goroutine
+				// waiting on some asynchronous, external task to complete.
+				WatchesRawSource(
+					source.Channel(
+						controllerManagerContext.GetGenericEventChannelFor(vmwarev1.GroupVersion.WithKind("VSphereMachine")),
+						&handler.EnqueueRequestForObject{},
+					),
+				).
+				WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(mgr.GetScheme(), predicateLog, controllerManagerContext.WatchFilterValue)).
+				// Watch any VirtualMachine resources owned by this VSphereMachine
+				Owns(&vmoprv1.VirtualMachine{}).
+				Complete(diagnostics.ObserveReconciler(name, r))
+		})
+	}
+
+	return addControllersWithControlPlanePriority(ctx, controllerManagerContext, mgr, "vspheremachine", func() client.Object { return &infrav1.VSphereMachine{} }, options, func(name string, options controller.Options) error {
 		return ctrl.NewControllerManagedBy(mgr).
+			Named(name).
 			// Watch the controlled, infrastructure resource.
-			For(&vmwarev1.VSphereMachine{}).
+			For(&infrav1.VSphereMachine{}).
 			WithOptions(options).
 			// Watch the CAPI resource that owns this infrastructure resource.
 			Watches(
 				&clusterv1.Machine{},
-				handler.EnqueueRequestsFromMapFunc(clusterutilv1.MachineToInfrastructureMapFunc(vmwarev1.GroupVersion.WithKind("VSphereMachine"))),
-			).
-			Watches(
-				&clusterv1.Cluster{},
-				handler.EnqueueRequestsFromMapFunc(r.enqueueClusterToMachineRequests),
-				ctrlbldr.WithPredicates(
-					predicates.ClusterUnpausedAndInfrastructureReady(mgr.GetScheme(), predicateLog),
-				),
+				handler.EnqueueRequestsFromMapFunc(clusterutilv1.MachineToInfrastructureMapFunc(infrav1.GroupVersion.WithKind("VSphereMachine"))),
 			).
 			// Watch a GenericEvent channel for the controlled resource.
 			//
@@ -121,56 +156,31 @@ func AddMachineControllerToManager(ctx context.Context, controllerManagerContext
 			// waiting on some asynchronous, external task to complete.
 			WatchesRawSource(
 				source.Channel(
-					controllerManagerContext.GetGenericEventChannelFor(vmwarev1.GroupVersion.WithKind("VSphereMachine")),
+					controllerManagerContext.GetGenericEventChannelFor(infrav1.GroupVersion.WithKind("VSphereMachine")),
 					&handler.EnqueueRequestForObject{},
 				),
 			).
 			WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(mgr.GetScheme(), predicateLog, controllerManagerContext.WatchFilterValue)).
-			// Watch any VirtualMachine resources owned by this VSphereMachine
-			Owns(&vmoprv1.VirtualMachine{}).
-			Complete(r)
-	}
-
-	return ctrl.NewControllerManagedBy(mgr).
-		// Watch the controlled, infrastructure resource.
-		For(&infrav1.VSphereMachine{}).
-		WithOptions(options).
-		// Watch the CAPI resource that owns this infrastructure resource.
-		Watches(
-			&clusterv1.Machine{},
-			handler.EnqueueRequestsFromMapFunc(clusterutilv1.MachineToInfrastructureMapFunc(infrav1.GroupVersion.WithKind("VSphereMachine"))),
-		).
-		// Watch a GenericEvent channel for the controlled resource.
-		//
-		// This is useful when there are events outside of Kubernetes that
-		// should cause a resource to be synchronized, such as a goroutine
-		// waiting on some asynchronous, external task to complete.
-		WatchesRawSource(
-			source.Channel(
-				controllerManagerContext.GetGenericEventChannelFor(infrav1.GroupVersion.WithKind("VSphereMachine")),
-				&handler.EnqueueRequestForObject{},
-			),
-		).
-		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(mgr.GetScheme(), predicateLog, controllerManagerContext.WatchFilterValue)).
-		// Watch any VSphereVM resources owned by the controlled type.
-		Watches(
-			&infrav1.VSphereVM{},
-			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &infrav1.VSphereMachine{}),
-			ctrlbldr.WithPredicates(predicate.Funcs{
-				// ignore creation events since this controller is responsible for
-				// the creation of the type.
-				CreateFunc: func(event.CreateEvent) bool {
-					return false
-				},
-			}),
-		).
-		Watches(
-			&clusterv1.Cluster{},
-			handler.EnqueueRequestsFromMapFunc(r.enqueueClusterToMachineRequests),
-			ctrlbldr.WithPredicates(
-				predicates.ClusterUnpausedAndInfrastructureReady(mgr.GetScheme(), predicateLog),
-			),
-		).Complete(r)
+			// Watch any VSphereVM resources owned by the controlled type.
+			Watches(
+				&infrav1.VSphereVM{},
+				handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &infrav1.VSphereMachine{}),
+				ctrlbldr.WithPredicates(predicate.Funcs{
+					// ignore creation events since this controller is responsible for
+					// the creation of the type.
+					CreateFunc: func(event.CreateEvent) bool {
+						return false
+					},
+				}),
+			).
+			Watches(
+				&clusterv1.Cluster{},
+				handler.EnqueueRequestsFromMapFunc(r.enqueueClusterToMachineRequests),
+				ctrlbldr.WithPredicates(
+					predicates.ClusterUnpausedAndInfrastructureReady(mgr.GetScheme(), predicateLog),
+				),
+			).Complete(diagnostics.ObserveReconciler(name, r))
+	})
 }
 
 type machineReconciler struct {
@@ -179,6 +189,10 @@ type machineReconciler struct {
 	VMService       services.VSphereMachineService
 	networkProvider services.NetworkProvider
 	supervisorBased bool
+
+	// preTerminateHookTimeout is the time after which the VM of a deleted
+	// VSphereMachine is deleted even if pre-terminate delete hooks remain.
+	preTerminateHookTimeout time.Duration
 }
 
 // Reconcile ensures the back-end state reflects the Kubernetes resource state intent.
@@ -194,6 +208,11 @@ func (r *machineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_
 		return reconcile.Result{}, err
 	}
 
+	ctx, span := tracing.StartSpan(ctx, "VSphereMachine.Reconcile", machineContext.GetVSphereMachine())
+	defer func() {
+		tracing.EndSpan(span, reterr)
+	}()
+
 	// Fetch the CAPI Machine.
 	machine, err := clusterutilv1.GetOwnerMachine(ctx, r.Client, machineContext.GetObjectMeta())
 	if err != nil {
@@ -308,6 +327,11 @@ func (r *machineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_
 func (r *machineReconciler) reconcileDelete(ctx context.Context, machineCtx capvcontext.MachineContext) (reconcile.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
 
+	// The VM is not deleted until external systems removed their pre-terminate delete hooks.
+	if wait, requeueAfter := util.ReconcilePreTerminateDeleteHooks(ctx, machineCtx.GetVSphereMachine(), r.preTerminateHookTimeout, time.Now()); wait {
+		return reconcile.Result{RequeueAfter: requeueAfter}, nil
+	}
+
 	conditions.MarkFalse(machineCtx.GetVSphereMachine(), infrav1.VMProvisionedCondition, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, "")
 
 	if err := r.VMService.ReconcileDelete(ctx, machineCtx); err != nil {
@@ -0,0 +1,220 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	exputil "sigs.k8s.io/cluster-api/exp/util"
+	clusterutilv1 "sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services"
+)
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=vspheremachinepools,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=vspheremachinepools/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinepools,verbs=get;list;watch
+
+// AddVSphereMachinePoolControllerToManager adds the machine pool controller to the provided
+// manager.
+func AddVSphereMachinePoolControllerToManager(ctx context.Context, controllerManagerCtx *capvcontext.ControllerManagerContext, mgr manager.Manager, options controller.Options) error {
+	r := &vsphereMachinePoolReconciler{
+		Client:    controllerManagerCtx.Client,
+		VMService: &services.VimMachinePoolService{Client: controllerManagerCtx.Client},
+	}
+	predicateLog := ctrl.LoggerFrom(ctx).WithValues("controller", "vspheremachinepool")
+
+	return ctrl.NewControllerManagedBy(mgr).
+		// Watch the controlled, infrastructure resource.
+		For(&infrav1.VSphereMachinePool{}).
+		WithOptions(options).
+		// Watch the CAPI resource that owns this infrastructure resource.
+		Watches(
+			&expv1.MachinePool{},
+			handler.EnqueueRequestsFromMapFunc(exputil.MachinePoolToInfrastructureMapFunc(ctx, infrav1.GroupVersion.WithKind("VSphereMachinePool"))),
+		).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(mgr.GetScheme(), predicateLog, controllerManagerCtx.WatchFilterValue)).
+		// Watch the VSphereVMs which are the instances of the VSphereMachinePool.
+		Owns(&infrav1.VSphereVM{}).
+		Complete(r)
+}
+
+type vsphereMachinePoolReconciler struct {
+	Client    client.Client
+	VMService *services.VimMachinePoolService
+}
+
+// Reconcile ensures the instances of a VSphereMachinePool reflect the replicas and
+// the instance template of the VSphereMachinePool and its MachinePool.
+func (r *vsphereMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	vsphereMachinePool := &infrav1.VSphereMachinePool{}
+	if err := r.Client.Get(ctx, req.NamespacedName, vsphereMachinePool); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	// Fetch the CAPI MachinePool.
+	machinePool, err := exputil.GetOwnerMachinePool(ctx, r.Client, vsphereMachinePool.ObjectMeta)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to get MachinePool for VSphereMachinePool")
+	}
+	if machinePool == nil {
+		log.Info("Waiting for MachinePool controller to set OwnerRef on VSphereMachinePool")
+		return reconcile.Result{}, nil
+	}
+	log = log.WithValues("MachinePool", klog.KObj(machinePool))
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	cluster, err := clusterutilv1.GetClusterFromMetadata(ctx, r.Client, machinePool.ObjectMeta)
+	if err != nil {
+		log.Error(err, "Failed to get Cluster from MachinePool: MachinePool is missing cluster label or cluster does not exist")
+	}
+	if cluster != nil {
+		log = log.WithValues("Cluster", klog.KObj(cluster))
+		ctx = ctrl.LoggerInto(ctx, log)
+
+		if annotations.IsPaused(cluster, vsphereMachinePool) {
+			log.Info("Reconciliation is paused for this object")
+			return reconcile.Result{}, nil
+		}
+	} else if annotations.HasPaused(vsphereMachinePool) {
+		log.Info("Reconciliation is paused for this object")
+		return reconcile.Result{}, nil
+	}
+
+	// Create the patch helper.
+	patchHelper, err := patch.NewHelper(vsphereMachinePool, r.Client)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	poolCtx := &capvcontext.VIMMachinePoolContext{
+		Cluster:            cluster,
+		MachinePool:        machinePool,
+		VSphereMachinePool: vsphereMachinePool,
+		PatchHelper:        patchHelper,
+	}
+	// always patch the VSphereMachinePool object
+	defer func() {
+		// always update the readyCondition.
+		conditions.SetSummary(vsphereMachinePool,
+			conditions.WithConditions(
+				infrav1.InstancesReadyCondition,
+			),
+		)
+
+		// Patch the VSphereMachinePool resource.
+		if err := poolCtx.Patch(ctx); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	if !vsphereMachinePool.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, poolCtx)
+	}
+
+	// Checking whether cluster is nil here as we still want to run reconcileDelete above even if cluster is not found.
+	if cluster == nil {
+		log.Info("Failed to get Cluster")
+		return reconcile.Result{}, nil
+	}
+
+	if cluster.Spec.InfrastructureRef == nil {
+		log.Info("Cluster.spec.infrastructureRef is not yet set")
+		return reconcile.Result{}, nil
+	}
+
+	if !cluster.Status.InfrastructureReady {
+		log.Info("Cluster infrastructure is not ready yet")
+		conditions.MarkFalse(vsphereMachinePool, infrav1.InstancesReadyCondition, infrav1.WaitingForClusterInfrastructureReason, clusterv1.ConditionSeverityInfo, "")
+		return reconcile.Result{}, nil
+	}
+
+	if machinePool.Spec.Template.Spec.Bootstrap.DataSecretName == nil {
+		log.Info("Waiting for bootstrap data to be available")
+		conditions.MarkFalse(vsphereMachinePool, infrav1.InstancesReadyCondition, infrav1.WaitingForBootstrapDataReason, clusterv1.ConditionSeverityInfo, "")
+		return reconcile.Result{}, nil
+	}
+
+	// Fetch the VSphereCluster.
+	vsphereCluster := &infrav1.VSphereCluster{}
+	vsphereClusterKey := client.ObjectKey{
+		Namespace: vsphereMachinePool.Namespace,
+		Name:      cluster.Spec.InfrastructureRef.Name,
+	}
+	if err := r.Client.Get(ctx, vsphereClusterKey, vsphereCluster); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to get VSphereCluster")
+	}
+	poolCtx.VSphereCluster = vsphereCluster
+
+	// If the VSphereMachinePool doesn't have our finalizer, add it.
+	// Requeue immediately after adding finalizer to avoid the race condition between init and delete
+	if !ctrlutil.ContainsFinalizer(vsphereMachinePool, infrav1.MachinePoolFinalizer) {
+		ctrlutil.AddFinalizer(vsphereMachinePool, infrav1.MachinePoolFinalizer)
+		return reconcile.Result{}, nil
+	}
+
+	// Handle non-deleted machine pools
+	return reconcile.Result{}, r.VMService.ReconcileNormal(ctx, poolCtx)
+}
+
+func (r *vsphereMachinePoolReconciler) reconcileDelete(ctx context.Context, poolCtx *capvcontext.VIMMachinePoolContext) (reconcile.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	conditions.MarkFalse(poolCtx.VSphereMachinePool, infrav1.InstancesReadyCondition, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, "")
+
+	remaining, err := r.VMService.ReconcileDelete(ctx, poolCtx)
+	if err != nil {
+		conditions.MarkFalse(poolCtx.VSphereMachinePool, infrav1.InstancesReadyCondition, clusterv1.DeletionFailedReason, clusterv1.ConditionSeverityWarning, "")
+		return reconcile.Result{}, err
+	}
+
+	// VSphereVMs are being deleted
+	if remaining {
+		return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	// All VSphereVMs are deleted so remove the finalizer.
+	if ctrlutil.RemoveFinalizer(poolCtx.VSphereMachinePool, infrav1.MachinePoolFinalizer) {
+		log.Info(fmt.Sprintf("Removing finalizer %s", infrav1.MachinePoolFinalizer))
+	}
+	return reconcile.Result{}, nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+)
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=vspheremachinetemplates,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=vspheremachinetemplates/status,verbs=get;update;patch
+
+// AddVSphereMachineTemplateControllerToManager adds the machine template controller to the provided
+// manager.
+func AddVSphereMachineTemplateControllerToManager(ctx context.Context, controllerManagerCtx *capvcontext.ControllerManagerContext, mgr manager.Manager, options controller.Options) error {
+	r := &vsphereMachineTemplateReconciler{
+		Client: controllerManagerCtx.Client,
+	}
+	predicateLog := ctrl.LoggerFrom(ctx).WithValues("controller", "vspheremachinetemplate")
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&infrav1.VSphereMachineTemplate{}).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(mgr.GetScheme(), predicateLog, controllerManagerCtx.WatchFilterValue)).
+		Complete(r)
+}
+
+type vsphereMachineTemplateReconciler struct {
+	Client client.Client
+}
+
+// Reconcile publishes the capacity of the machines created from a VSphereMachineTemplate
+// in its status, so cluster-autoscaler can scale MachineDeployments from zero.
+func (r *vsphereMachineTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	vsphereMachineTemplate := &infrav1.VSphereMachineTemplate{}
+	if err := r.Client.Get(ctx, req.NamespacedName, vsphereMachineTemplate); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	patchHelper, err := patch.NewHelper(vsphereMachineTemplate, r.Client)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	vsphereMachineTemplate.Status.Capacity = capacityForMachineSpec(vsphereMachineTemplate.Spec.Template.Spec)
+
+	return reconcile.Result{}, patchHelper.Patch(ctx, vsphereMachineTemplate)
+}
+
+// capacityForMachineSpec returns the resources of a virtual machine created from the given spec.
+// Resources which default to the values of the vSphere template are omitted, as they cannot be
+// derived from the spec.
+func capacityForMachineSpec(spec infrav1.VSphereMachineSpec) corev1.ResourceList {
+	capacity := corev1.ResourceList{}
+	if spec.NumCPUs > 0 {
+		capacity[corev1.ResourceCPU] = *resource.NewQuantity(int64(spec.NumCPUs), resource.DecimalSI)
+	}
+	if spec.MemoryMiB > 0 {
+		capacity[corev1.ResourceMemory] = *resource.NewQuantity(spec.MemoryMiB*1024*1024, resource.BinarySI)
+	}
+
+	var gpus int64
+	for _, device := range spec.PciDevices {
+		// vGPU profiles are only provided by NVIDIA.
+		if device.VGPUProfile != "" || (device.VendorID != nil && *device.VendorID == infrav1.NvidiaVendorID) {
+			gpus++
+		}
+	}
+	if gpus > 0 {
+		capacity[infrav1.VSphereResourceNvidiaGPU] = *resource.NewQuantity(gpus, resource.DecimalSI)
+	}
+
+	if len(capacity) == 0 {
+		return nil
+	}
+	return capacity
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func Test_vsphereMachineTemplateReconciler_Reconcile(t *testing.T) {
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	NewWithT(t).Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+
+	tests := []struct {
+		name       string
+		spec       *infrav1.VSphereMachineSpec
+		status     infrav1.VSphereMachineTemplateStatus
+		wantStatus infrav1.VSphereMachineTemplateStatus
+	}{
+		{
+			name: "object does not exist",
+		},
+		{
+			name:       "cpu and memory default to the vSphere template",
+			spec:       &infrav1.VSphereMachineSpec{},
+			wantStatus: infrav1.VSphereMachineTemplateStatus{},
+		},
+		{
+			name: "cpu and memory set",
+			spec: &infrav1.VSphereMachineSpec{
+				VirtualMachineCloneSpec: infrav1.VirtualMachineCloneSpec{NumCPUs: 2, MemoryMiB: 4096},
+			},
+			wantStatus: infrav1.VSphereMachineTemplateStatus{
+				Capacity: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("2"),
+					corev1.ResourceMemory: resource.MustParse("4Gi"),
+				},
+			},
+		},
+		{
+			name: "NVIDIA GPUs via PCI passthrough and vGPU",
+			spec: &infrav1.VSphereMachineSpec{
+				VirtualMachineCloneSpec: infrav1.VirtualMachineCloneSpec{
+					NumCPUs: 4,
+					PciDevices: []infrav1.PCIDeviceSpec{
+						{DeviceID: ptr.To[int32](0x1eb8), VendorID: ptr.To(infrav1.NvidiaVendorID)},
+						{VGPUProfile: "grid_t4-4q"},
+						{DeviceID: ptr.To[int32](0x1234), VendorID: ptr.To[int32](0x8086)},
+					},
+				},
+			},
+			wantStatus: infrav1.VSphereMachineTemplateStatus{
+				Capacity: corev1.ResourceList{
+					corev1.ResourceCPU:               resource.MustParse("4"),
+					infrav1.VSphereResourceNvidiaGPU: resource.MustParse("2"),
+				},
+			},
+		},
+		{
+			name: "capacity is updated",
+			spec: &infrav1.VSphereMachineSpec{
+				VirtualMachineCloneSpec: infrav1.VirtualMachineCloneSpec{NumCPUs: 8},
+			},
+			status: infrav1.VSphereMachineTemplateStatus{
+				Capacity: corev1.ResourceList{
+					corev1.ResourceCPU:               resource.MustParse("2"),
+					corev1.ResourceMemory:            resource.MustParse("4Gi"),
+					infrav1.VSphereResourceNvidiaGPU: resource.MustParse("1"),
+				},
+			},
+			wantStatus: infrav1.VSphereMachineTemplateStatus{
+				Capacity: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("8"),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			fakeClientBuilder := fake.NewClientBuilder().WithScheme(scheme)
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "test-namespace", Name: "test-template"}}
+			if tt.spec != nil {
+				vsphereMachineTemplate := &infrav1.VSphereMachineTemplate{
+					ObjectMeta: metav1.ObjectMeta{Namespace: req.Namespace, Name: req.Name},
+					Spec: infrav1.VSphereMachineTemplateSpec{
+						Template: infrav1.VSphereMachineTemplateResource{Spec: *tt.spec},
+					},
+					Status: tt.status,
+				}
+				fakeClientBuilder = fakeClientBuilder.
+					WithObjects(vsphereMachineTemplate).
+					WithStatusSubresource(vsphereMachineTemplate)
+			}
+
+			r := &vsphereMachineTemplateReconciler{
+				Client: fakeClientBuilder.Build(),
+			}
+
+			_, err := r.Reconcile(ctx, req)
+			g.Expect(err).ToNot(HaveOccurred())
+			if tt.spec == nil {
+				return
+			}
+
+			vsphereMachineTemplate := &infrav1.VSphereMachineTemplate{}
+			g.Expect(r.Client.Get(ctx, req.NamespacedName, vsphereMachineTemplate)).To(Succeed())
+			g.Expect(vsphereMachineTemplate.Status.Capacity).To(HaveLen(len(tt.wantStatus.Capacity)))
+			for name, want := range tt.wantStatus.Capacity {
+				got := vsphereMachineTemplate.Status.Capacity[name]
+				g.Expect(got.Cmp(want)).To(Equal(0), "capacity %s: got %s, want %s", name, got.String(), want.String())
+			}
+		})
+	}
+}
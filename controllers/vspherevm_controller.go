@@ -56,13 +56,21 @@ import (
 	"sigs.k8s.io/cluster-api-provider-vsphere/feature"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/clustermodule"
 	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/diagnostics"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/identity"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/metrics"
+	capvrecord "sigs.k8s.io/cluster-api-provider-vsphere/pkg/record"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/govmomi"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/session"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/tracing"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/util"
 )
 
+// guestCrashDetectionSyncPeriod is the interval in which the guest crashes of VSphereVMs with
+// spec.guestCrashDetection are checked.
+const guestCrashDetectionSyncPeriod = 2 * time.Minute
+
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=vspherevms,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=vspherevms/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinedeployments;machinesets,verbs=get;list;watch
@@ -74,66 +82,73 @@ import (
 func AddVMControllerToManager(ctx context.Context, controllerManagerCtx *capvcontext.ControllerManagerContext, mgr manager.Manager, clusterCache clustercache.ClusterCache, options controller.Options) error {
 	r := vmReconciler{
 		ControllerManagerContext: controllerManagerCtx,
-		Recorder:                 mgr.GetEventRecorderFor("vspherevm-controller"),
+		Recorder:                 capvrecord.NewAggregatingRecorder(mgr.GetEventRecorderFor("vspherevm-controller"), controllerManagerCtx.EventAggregationWindow),
 		VMService:                &govmomi.VMService{},
 		clusterCache:             clusterCache,
 	}
 	predicateLog := ctrl.LoggerFrom(ctx).WithValues("controller", "vspherevm")
 
-	return ctrl.NewControllerManagedBy(mgr).
-		// Watch the controlled, infrastructure resource.
-		For(&infrav1.VSphereVM{}).
-		WithOptions(options).
-		// Watch a GenericEvent channel for the controlled resource.
-		//
-		// This is useful when there are events outside of Kubernetes that
-		// should cause a resource to be synchronized, such as a goroutine
-		// waiting on some asynchronous, external task to complete.
-		WatchesRawSource(
-			source.Channel(
-				controllerManagerCtx.GetGenericEventChannelFor(infrav1.GroupVersion.WithKind("VSphereVM")),
-				&handler.EnqueueRequestForObject{},
-			),
-		).
-		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(mgr.GetScheme(), predicateLog, controllerManagerCtx.WatchFilterValue)).
-		Watches(
-			&clusterv1.Cluster{},
-			handler.EnqueueRequestsFromMapFunc(r.clusterToVSphereVMs),
-			ctrlbldr.WithPredicates(
-				predicate.Funcs{
-					UpdateFunc: func(e event.UpdateEvent) bool {
-						newCluster := e.ObjectNew.(*clusterv1.Cluster)
-						// check whether cluster has either spec.paused or pasued annotation
-						return !annotations.IsPaused(newCluster, newCluster)
-					},
-					CreateFunc: func(e event.CreateEvent) bool {
-						cluster := e.Object.(*clusterv1.Cluster)
-						// check whether cluster has either spec.paused or pasued annotation
-						return annotations.IsPaused(cluster, cluster)
-					},
-				}),
-		).
-		Watches(
-			&infrav1.VSphereCluster{},
-			handler.EnqueueRequestsFromMapFunc(r.vsphereClusterToVSphereVMs),
-			ctrlbldr.WithPredicates(
-				predicate.Funcs{
-					UpdateFunc: func(e event.UpdateEvent) bool {
-						oldCluster := e.ObjectOld.(*infrav1.VSphereCluster)
-						newCluster := e.ObjectNew.(*infrav1.VSphereCluster)
-						return !clustermodule.Compare(oldCluster.Spec.ClusterModules, newCluster.Spec.ClusterModules)
-					},
-					CreateFunc:  func(event.CreateEvent) bool { return false },
-					DeleteFunc:  func(event.DeleteEvent) bool { return false },
-					GenericFunc: func(event.GenericEvent) bool { return false },
-				}),
-		).
-		Watches(
-			&ipamv1.IPAddressClaim{},
-			handler.EnqueueRequestsFromMapFunc(r.ipAddressClaimToVSphereVM),
-		).
-		WatchesRawSource(r.clusterCache.GetClusterSource("vspherevm", r.clusterToVSphereVMs)).
-		Complete(r)
+	if err := metrics.RegisterIPAddressClaimsCollector(mgr.GetClient()); err != nil {
+		return err
+	}
+
+	return addControllersWithControlPlanePriority(ctx, controllerManagerCtx, mgr, "vspherevm", func() ctrlclient.Object { return &infrav1.VSphereVM{} }, options, func(name string, options controller.Options) error {
+		return ctrl.NewControllerManagedBy(mgr).
+			Named(name).
+			// Watch the controlled, infrastructure resource.
+			For(&infrav1.VSphereVM{}).
+			WithOptions(options).
+			// Watch a GenericEvent channel for the controlled resource.
+			//
+			// This is useful when there are events outside of Kubernetes that
+			// should cause a resource to be synchronized, such as a goroutine
+			// waiting on some asynchronous, external task to complete.
+			WatchesRawSource(
+				source.Channel(
+					controllerManagerCtx.GetGenericEventChannelFor(infrav1.GroupVersion.WithKind("VSphereVM")),
+					&handler.EnqueueRequestForObject{},
+				),
+			).
+			WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(mgr.GetScheme(), predicateLog, controllerManagerCtx.WatchFilterValue)).
+			Watches(
+				&clusterv1.Cluster{},
+				handler.EnqueueRequestsFromMapFunc(r.clusterToVSphereVMs),
+				ctrlbldr.WithPredicates(
+					predicate.Funcs{
+						UpdateFunc: func(e event.UpdateEvent) bool {
+							newCluster := e.ObjectNew.(*clusterv1.Cluster)
+							// check whether cluster has either spec.paused or pasued annotation
+							return !annotations.IsPaused(newCluster, newCluster)
+						},
+						CreateFunc: func(e event.CreateEvent) bool {
+							cluster := e.Object.(*clusterv1.Cluster)
+							// check whether cluster has either spec.paused or pasued annotation
+							return annotations.IsPaused(cluster, cluster)
+						},
+					}),
+			).
+			Watches(
+				&infrav1.VSphereCluster{},
+				handler.EnqueueRequestsFromMapFunc(r.vsphereClusterToVSphereVMs),
+				ctrlbldr.WithPredicates(
+					predicate.Funcs{
+						UpdateFunc: func(e event.UpdateEvent) bool {
+							oldCluster := e.ObjectOld.(*infrav1.VSphereCluster)
+							newCluster := e.ObjectNew.(*infrav1.VSphereCluster)
+							return !clustermodule.Compare(oldCluster.Spec.ClusterModules, newCluster.Spec.ClusterModules)
+						},
+						CreateFunc:  func(event.CreateEvent) bool { return false },
+						DeleteFunc:  func(event.DeleteEvent) bool { return false },
+						GenericFunc: func(event.GenericEvent) bool { return false },
+					}),
+			).
+			Watches(
+				&ipamv1.IPAddressClaim{},
+				handler.EnqueueRequestsFromMapFunc(r.ipAddressClaimToVSphereVM),
+			).
+			WatchesRawSource(r.clusterCache.GetClusterSource(name, r.clusterToVSphereVMs)).
+			Complete(diagnostics.ObserveReconciler(name, r))
+	})
 }
 
 type vmReconciler struct {
@@ -156,6 +171,11 @@ func (r vmReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.R
 		return reconcile.Result{}, err
 	}
 
+	ctx, span := tracing.StartSpan(ctx, "VSphereVM.Reconcile", vsphereVM)
+	defer func() {
+		tracing.EndSpan(span, reterr)
+	}()
+
 	cluster, err := clusterutilv1.GetClusterFromMetadata(ctx, r.Client, vsphereVM.ObjectMeta)
 	if err != nil {
 		log.Error(err, "Failed to get Cluster from VSphereVM: Machine is missing cluster label or cluster does not exist")
@@ -182,6 +202,10 @@ func (r vmReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.R
 	authSession, err := r.retrieveVcenterSession(ctx, vsphereVM)
 	if err != nil {
 		conditions.MarkFalse(vsphereVM, infrav1.VCenterAvailableCondition, infrav1.VCenterUnreachableReason, clusterv1.ConditionSeverityError, err.Error())
+		r.Recorder.Eventf(vsphereVM, corev1.EventTypeWarning, capvrecord.ReasonForError(err, capvrecord.VCenterUnreachableReason), "Failed to connect to vCenter: %v", err)
+		if !vsphereVM.DeletionTimestamp.IsZero() {
+			return r.reconcileDeleteVCenterUnreachable(ctx, vsphereVM, cluster, patchHelper, err)
+		}
 		return reconcile.Result{}, err
 	}
 	conditions.MarkTrue(vsphereVM, infrav1.VCenterAvailableCondition)
@@ -195,43 +219,77 @@ func (r vmReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.R
 	if err != nil {
 		return reconcile.Result{}, errors.Wrapf(err, "failed to get VSphereMachine for VSphereVM")
 	}
-	if vsphereMachine == nil {
-		log.Info("Waiting for VSphereMachine controller to set OwnerRef on VSphereVM")
-		return reconcile.Result{}, nil
-	}
 
-	log = log.WithValues("VSphereMachine", klog.KObj(vsphereMachine))
-	ctx = ctrl.LoggerInto(ctx, log)
+	var (
+		vsphereCluster *infrav1.VSphereCluster
+		machine        *clusterv1.Machine
+	)
+	if vsphereMachine != nil {
+		log = log.WithValues("VSphereMachine", klog.KObj(vsphereMachine))
+		ctx = ctrl.LoggerInto(ctx, log)
 
-	vsphereCluster, err := util.GetVSphereClusterFromVSphereMachine(ctx, r.Client, vsphereMachine)
-	if err != nil || vsphereCluster == nil {
-		return reconcile.Result{}, errors.Wrapf(err, "failed to get VSphereCluster from VSphereMachine")
-	}
+		vsphereCluster, err = util.GetVSphereClusterFromVSphereMachine(ctx, r.Client, vsphereMachine)
+		if err != nil || vsphereCluster == nil {
+			return reconcile.Result{}, errors.Wrapf(err, "failed to get VSphereCluster from VSphereMachine")
+		}
 
-	log = log.WithValues("VSphereCluster", klog.KObj(vsphereCluster))
-	ctx = ctrl.LoggerInto(ctx, log)
+		log = log.WithValues("VSphereCluster", klog.KObj(vsphereCluster))
+		ctx = ctrl.LoggerInto(ctx, log)
 
-	// Fetch the CAPI Machine.
-	machine, err := clusterutilv1.GetOwnerMachine(ctx, r.Client, vsphereMachine.ObjectMeta)
-	if err != nil {
-		return reconcile.Result{}, errors.Wrapf(err, "failed to get Machine for VSphereMachine")
-	}
-	if machine == nil {
-		log.Info("Waiting for Machine controller to set OwnerRef on VSphereMachine")
-		return reconcile.Result{}, nil
+		// Fetch the CAPI Machine.
+		machine, err = clusterutilv1.GetOwnerMachine(ctx, r.Client, vsphereMachine.ObjectMeta)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, "failed to get Machine for VSphereMachine")
+		}
+		if machine == nil {
+			log.Info("Waiting for Machine controller to set OwnerRef on VSphereMachine")
+			return reconcile.Result{}, nil
+		}
+		log = log.WithValues("Machine", klog.KObj(machine))
+		ctx = ctrl.LoggerInto(ctx, log)
+
+		// AddOwners adds the owners of Machine as k/v pairs to the logger.
+		// Specifically, it will add KubeadmControlPlane, MachineSet and MachineDeployment.
+		ctx, log, err = clog.AddOwners(ctx, r.Client, machine)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	} else {
+		// VSphereVMs of a VSphereMachinePool are owned by the VSphereMachinePool
+		// instead of a VSphereMachine and have no corresponding CAPI Machine.
+		vsphereMachinePool, err := util.GetOwnerVSphereMachinePool(ctx, r.Client, vsphereVM.ObjectMeta)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, "failed to get VSphereMachinePool for VSphereVM")
+		}
+		if vsphereMachinePool == nil {
+			log.Info("Waiting for VSphereMachinePool controller to set OwnerRef on VSphereVM")
+			return reconcile.Result{}, nil
+		}
+
+		log = log.WithValues("VSphereMachinePool", klog.KObj(vsphereMachinePool))
+		ctx = ctrl.LoggerInto(ctx, log)
+
+		vsphereCluster, err = util.GetVSphereClusterFromVSphereMachinePool(ctx, r.Client, vsphereMachinePool)
+		if err != nil || vsphereCluster == nil {
+			return reconcile.Result{}, errors.Wrapf(err, "failed to get VSphereCluster from VSphereMachinePool")
+		}
+
+		log = log.WithValues("VSphereCluster", klog.KObj(vsphereCluster))
+		ctx = ctrl.LoggerInto(ctx, log)
 	}
-	log = log.WithValues("Machine", klog.KObj(machine))
-	ctx = ctrl.LoggerInto(ctx, log)
 
-	// AddOwners adds the owners of Machine as k/v pairs to the logger.
-	// Specifically, it will add KubeadmControlPlane, MachineSet and MachineDeployment.
-	ctx, log, err = clog.AddOwners(ctx, r.Client, machine)
-	if err != nil {
-		return ctrl.Result{}, err
+	// The failure domain is either set on the Machine or assigned to the VSphereMachine
+	// of a control plane machine without a failure domain.
+	var failureDomain *string
+	if machine != nil {
+		failureDomain = machine.Spec.FailureDomain
+		if failureDomain == nil {
+			failureDomain = vsphereMachine.Status.FailureDomain
+		}
 	}
 
 	var vsphereFailureDomain *infrav1.VSphereFailureDomain
-	if failureDomain := machine.Spec.FailureDomain; failureDomain != nil {
+	if failureDomain != nil {
 		vsphereDeploymentZone := &infrav1.VSphereDeploymentZone{}
 		if err := r.Client.Get(ctx, apitypes.NamespacedName{Name: *failureDomain}, vsphereDeploymentZone); err != nil {
 			return reconcile.Result{}, errors.Wrapf(err, "failed to get VSphereDeploymentZone %s", *failureDomain)
@@ -243,13 +301,25 @@ func (r vmReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.R
 		}
 	}
 
+	// The folder generated by the folder naming strategy of the VSphereCluster is created on demand.
+	clusterFolder, err := util.GenerateClusterFolderName(vsphereCluster)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
 	// Create the VM context for this request.
 	vmContext := &capvcontext.VMContext{
 		ControllerManagerContext: r.ControllerManagerContext,
 		VSphereVM:                vsphereVM,
 		VSphereFailureDomain:     vsphereFailureDomain,
+		ClusterFolder:            clusterFolder,
+		KubeVIP:                  kubeVIPConfig(vsphereCluster, vsphereVM),
 		Session:                  authSession,
 		PatchHelper:              patchHelper,
+		InfraPaused:              util.IsInfraPaused(cluster, vsphereCluster, vsphereVM),
+		DryRun:                   r.ControllerManagerContext.DryRun || util.IsDryRun(cluster, vsphereCluster, vsphereVM),
+		DeleteProtected:          util.IsDeleteProtected(cluster, vsphereCluster, vsphereVM),
+		ClusterDeleting:          cluster != nil && !cluster.DeletionTimestamp.IsZero(),
 	}
 
 	// Print the task-ref upon entry and upon exit.
@@ -338,11 +408,38 @@ func (r vmReconciler) reconcile(ctx context.Context, vmCtx *capvcontext.VMContex
 func (r vmReconciler) reconcileDelete(ctx context.Context, vmCtx *capvcontext.VMContext) (reconcile.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
 
+	// The VM is not destroyed until external systems removed their pre-terminate delete hooks.
+	if wait, requeueAfter := util.ReconcilePreTerminateDeleteHooks(ctx, vmCtx.VSphereVM, r.PreTerminateHookTimeout, time.Now()); wait {
+		return reconcile.Result{RequeueAfter: requeueAfter}, nil
+	}
+
+	// When the whole cluster is torn down, VMs are destroyed in order and only a
+	// limited number at a time to avoid flooding vCenter with tasks.
+	if vmCtx.ClusterDeleting {
+		wait, err := r.waitForClusterTeardown(ctx, vmCtx)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if wait {
+			return reconcile.Result{RequeueAfter: clusterTeardownRequeueAfter}, nil
+		}
+	}
+
 	conditions.MarkFalse(vmCtx.VSphereVM, infrav1.VMProvisionedCondition, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, "")
 	result, vm, err := r.VMService.DestroyVM(ctx, vmCtx)
 	if err != nil {
 		conditions.MarkFalse(vmCtx.VSphereVM, infrav1.VMProvisionedCondition, "DeletionFailed", clusterv1.ConditionSeverityWarning, err.Error())
-		return reconcile.Result{}, errors.Wrapf(err, "failed to destroy VM")
+		r.Recorder.Eventf(vmCtx.VSphereVM, corev1.EventTypeWarning, capvrecord.ReasonForError(err, capvrecord.DeleteFailedReason), "Failed to destroy VM: %v", err)
+
+		forceDeleted, forceErr := r.reconcileDeletionFailure(ctx, vmCtx, err)
+		if forceErr != nil {
+			return reconcile.Result{}, kerrors.NewAggregate([]error{errors.Wrapf(err, "failed to destroy VM"), forceErr})
+		}
+		if !forceDeleted {
+			return reconcile.Result{}, errors.Wrapf(err, "failed to destroy VM")
+		}
+		// The VM is gone from vCenter's point of view, continue with the cleanup of the Node and the IP addresses.
+		result, vm = reconcile.Result{}, infrav1.VirtualMachine{Name: vmCtx.VSphereVM.Name, State: infrav1.VirtualMachineStateNotFound}
 	}
 
 	if !result.IsZero() {
@@ -352,20 +449,31 @@ func (r vmReconciler) reconcileDelete(ctx context.Context, vmCtx *capvcontext.VM
 
 	// Requeue the operation until the VM is "notfound".
 	if vm.State != infrav1.VirtualMachineStateNotFound {
-		log.Info(fmt.Sprintf("VM state is %q, waiting for %q", vm.State, infrav1.VirtualMachineStateNotFound))
+		log.Info("Waiting for VM to be deleted", "vmState", vm.State)
 		return reconcile.Result{}, nil
 	}
 
-	// Attempt to delete the node corresponding to the vsphere VM
-	err = r.deleteNode(ctx, vmCtx, vm.Name)
-	if err != nil {
-		log.Error(err, "Failed to delete Node (best-effort)")
+	// Attempt to delete the node corresponding to the vsphere VM, unless the VM
+	// has been left orphaned because it is delete protected.
+	if !vmCtx.DeleteProtected {
+		if err := r.deleteNode(ctx, vmCtx, vm.Name); err != nil {
+			log.Error(err, "Failed to delete Node (best-effort)")
+		}
 	}
 
 	if err := r.deleteIPAddressClaims(ctx, vmCtx); err != nil {
 		return reconcile.Result{}, err
 	}
 
+	if vmCtx.ClusterDeleting {
+		if err := deleteClusterFolderIfEmpty(ctx, vmCtx); err != nil {
+			return reconcile.Result{}, err
+		}
+		if err := deleteChildResourcePoolIfEmpty(ctx, vmCtx); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
 	// The VM is deleted so remove the finalizer.
 	if ctrlutil.RemoveFinalizer(vmCtx.VSphereVM, infrav1.VMFinalizer) {
 		log.Info(fmt.Sprintf("Removing finalizer %s", infrav1.VMFinalizer))
@@ -374,6 +482,70 @@ func (r vmReconciler) reconcileDelete(ctx context.Context, vmCtx *capvcontext.VM
 	return reconcile.Result{}, nil
 }
 
+// reconcileDeletionFailure handles a VM which could not be destroyed. Once the VSphereVM has been deleted
+// for longer than the VM deletion failure timeout, the VM is looked up by its UUID: if it is no longer found,
+// e.g. because the VM is orphaned or its files are gone, it returns true so the VSphereVM is deleted without
+// destroying the VM. Otherwise the VM has to be deleted manually and destroying it is retried.
+func (r vmReconciler) reconcileDeletionFailure(ctx context.Context, vmCtx *capvcontext.VMContext, deleteErr error) (bool, error) {
+	log := ctrl.LoggerFrom(ctx)
+	vsphereVM := vmCtx.VSphereVM
+
+	timeout := r.VMDeletionFailureTimeout
+	if timeout <= 0 || time.Since(vsphereVM.DeletionTimestamp.Time) < timeout {
+		conditions.MarkFalse(vsphereVM, infrav1.VMDeletedCondition, infrav1.VMDeletionFailedReason, clusterv1.ConditionSeverityWarning, deleteErr.Error())
+		return false, nil
+	}
+
+	exists, err := r.VMService.VMExists(ctx, vmCtx)
+	if err != nil {
+		conditions.MarkFalse(vsphereVM, infrav1.VMDeletedCondition, infrav1.VMDeletionFailedReason, clusterv1.ConditionSeverityWarning, deleteErr.Error())
+		return false, errors.Wrapf(err, "failed to check if VM exists")
+	}
+	if exists {
+		conditions.MarkFalse(vsphereVM, infrav1.VMDeletedCondition, infrav1.VMStillExistsReason, clusterv1.ConditionSeverityError,
+			"VM could not be destroyed within %s and still exists, it has to be deleted manually: %v", timeout, deleteErr)
+		return false, nil
+	}
+
+	log.Info("VM could not be destroyed but is no longer found by its UUID, deleting the VSphereVM without destroying the VM", "error", deleteErr.Error())
+	conditions.MarkFalse(vsphereVM, infrav1.VMDeletedCondition, infrav1.VMForceDeletedReason, clusterv1.ConditionSeverityWarning,
+		"VM could not be destroyed within %s but is no longer found by its UUID: %v", timeout, deleteErr)
+	r.Recorder.Eventf(vsphereVM, corev1.EventTypeWarning, infrav1.VMForceDeletedReason,
+		"VM could not be destroyed within %s but is no longer found by its UUID, removing the finalizer: %v", timeout, deleteErr)
+	return true, nil
+}
+
+// reconcileDeleteVCenterUnreachable handles a deleted VSphereVM whose vCenter is unreachable. The VCenterAvailable
+// condition is persisted to track for how long vCenter has been unreachable, and once a VSphereForceDeletePolicy
+// allows it, the finalizer is removed without destroying the VM, which is left behind in vCenter.
+func (r vmReconciler) reconcileDeleteVCenterUnreachable(ctx context.Context, vsphereVM *infrav1.VSphereVM, cluster *clusterv1.Cluster, patchHelper *patch.Helper, vCenterErr error) (_ reconcile.Result, reterr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	defer func() {
+		if err := patchHelper.Patch(ctx, vsphereVM); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	policy, err := getForceDeletePolicy(ctx, r.Client, vsphereVM, cluster, time.Now())
+	if err != nil {
+		return reconcile.Result{}, kerrors.NewAggregate([]error{vCenterErr, err})
+	}
+	if policy == nil {
+		return reconcile.Result{}, vCenterErr
+	}
+
+	timeout := policy.Spec.VCenterUnreachableTimeout.Duration
+	log.Info("vCenter is unreachable, removing the finalizer without destroying the VM as allowed by the VSphereForceDeletePolicy",
+		"VSphereForceDeletePolicy", policy.Name, "timeout", timeout.String(), "error", vCenterErr.Error())
+	conditions.MarkFalse(vsphereVM, infrav1.VMDeletedCondition, infrav1.ForceDeletedReason, clusterv1.ConditionSeverityWarning,
+		"vCenter has been unreachable for more than %s, the VM has not been destroyed as allowed by VSphereForceDeletePolicy %s: %v", timeout, policy.Name, vCenterErr)
+	r.Recorder.Eventf(vsphereVM, corev1.EventTypeWarning, infrav1.ForceDeletedReason,
+		"vCenter has been unreachable for more than %s, removing the finalizer without destroying the VM as allowed by VSphereForceDeletePolicy %s: %v", timeout, policy.Name, vCenterErr)
+	ctrlutil.RemoveFinalizer(vsphereVM, infrav1.VMFinalizer)
+	return reconcile.Result{}, nil
+}
+
 // deleteNode attempts to find and best effort delete the node corresponding to the VM
 // This is necessary since CAPI does not surface the nodeRef field on the owner Machine object
 // until the node moves to Ready state. Hence, on Machine deletion it is unable to delete
@@ -410,6 +582,68 @@ func (r vmReconciler) deleteNode(ctx context.Context, vmCtx *capvcontext.VMConte
 	return clusterClient.Delete(ctx, node)
 }
 
+// reconcileNodeGuestOSHealth best effort mirrors the GuestOSHealthy condition of the VSphereVM
+// to the Node of the VM in the workload cluster, where it can be used in the unhealthyConditions
+// of a MachineHealthCheck, which only checks the conditions of Nodes.
+func (r vmReconciler) reconcileNodeGuestOSHealth(ctx context.Context, vmCtx *capvcontext.VMContext) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	condition := conditions.Get(vmCtx.VSphereVM, infrav1.GuestOSHealthyCondition)
+	if condition == nil || condition.Status == corev1.ConditionUnknown {
+		return nil
+	}
+
+	cluster, err := clusterutilv1.GetClusterFromMetadata(ctx, r.Client, vmCtx.VSphereVM.ObjectMeta)
+	if err != nil {
+		return err
+	}
+
+	clusterClient, err := r.clusterCache.GetClient(ctx, ctrlclient.ObjectKeyFromObject(cluster))
+	if err != nil {
+		if errors.Is(err, clustercache.ErrClusterNotConnected) {
+			log.V(2).Info("Skipping update of the GuestOSHealthy condition of the Node because connection to the workload cluster is down")
+			return nil
+		}
+		return err
+	}
+
+	// The Node has the name of the VM, it doesn't exist until the VM joined the cluster.
+	node := &corev1.Node{}
+	if err := clusterClient.Get(ctx, ctrlclient.ObjectKey{Name: vmCtx.VSphereVM.Name}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	nodeCondition := corev1.NodeCondition{
+		Type:               corev1.NodeConditionType(infrav1.GuestOSHealthyCondition),
+		Status:             condition.Status,
+		LastHeartbeatTime:  metav1.Now(),
+		LastTransitionTime: condition.LastTransitionTime,
+		Reason:             condition.Reason,
+		Message:            condition.Message,
+	}
+	original := node.DeepCopy()
+	found := false
+	for i, c := range node.Status.Conditions {
+		if c.Type != nodeCondition.Type {
+			continue
+		}
+		if c.Status == nodeCondition.Status && c.Reason == nodeCondition.Reason && c.Message == nodeCondition.Message {
+			return nil
+		}
+		node.Status.Conditions[i] = nodeCondition
+		found = true
+	}
+	if !found {
+		node.Status.Conditions = append(node.Status.Conditions, nodeCondition)
+	}
+
+	log.Info("Updating GuestOSHealthy condition of Node", "Node", klog.KObj(node), "status", nodeCondition.Status)
+	return clusterClient.Status().Patch(ctx, node, ctrlclient.StrategicMergeFrom(original))
+}
+
 func (r vmReconciler) reconcileNormal(ctx context.Context, vmCtx *capvcontext.VMContext) (reconcile.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
 
@@ -431,15 +665,37 @@ func (r vmReconciler) reconcileNormal(ctx context.Context, vmCtx *capvcontext.VM
 	// Get or create the VM.
 	vm, err := r.VMService.ReconcileVM(ctx, vmCtx)
 	if err != nil {
+		r.Recorder.Eventf(vmCtx.VSphereVM, corev1.EventTypeWarning, capvrecord.ReasonForError(err, capvrecord.ReconcileFailedReason), "Failed to reconcile VM: %v", err)
 		return reconcile.Result{}, errors.Wrapf(err, "failed to reconcile VM")
 	}
 
 	// Do not proceed until the backend VM is marked ready.
 	if vm.State != infrav1.VirtualMachineStateReady {
-		log.Info(fmt.Sprintf("VM state is %q, waiting for %q", vm.State, infrav1.VirtualMachineStateReady))
+		switch conditions.GetReason(vmCtx.VSphereVM, infrav1.VMProvisionedCondition) {
+		case infrav1.PoweredOffReason, infrav1.SuspendedReason:
+			// The VM is intentionally not powered on, which is not reported as a failure.
+			log.Info("VM is not powered on as requested", "powerState", vmCtx.VSphereVM.Spec.PowerState)
+			vmCtx.VSphereVM.Status.Ready = false
+			return reconcile.Result{}, nil
+		case infrav1.PoweringOffReason:
+			// A guest shutdown is not tracked by a task which triggers a reconcile once it completes.
+			log.Info("Waiting for VM to be powered off")
+			return reconcile.Result{RequeueAfter: 20 * time.Second}, nil
+		}
+		log.Info("Waiting for VM to be ready", "vmState", vm.State)
 		return reconcile.Result{}, nil
 	}
 
+	// The guest crashes are detected using the vCenter events of the VM, which don't trigger
+	// a reconcile, so they are checked periodically.
+	result := reconcile.Result{}
+	if vmCtx.VSphereVM.Spec.GuestCrashDetection != nil {
+		if err := r.reconcileNodeGuestOSHealth(ctx, vmCtx); err != nil {
+			log.Error(err, "Failed to update GuestOSHealthy condition of Node (best-effort)")
+		}
+		result.RequeueAfter = guestCrashDetectionSyncPeriod
+	}
+
 	// Update the VSphereVM's BIOS UUID.
 	// Defensive check to ensure we are not removing the biosUUID
 	if vm.BiosUUID != "" {
@@ -451,6 +707,13 @@ func (r vmReconciler) reconcileNormal(ctx context.Context, vmCtx *capvcontext.VM
 		return reconcile.Result{}, errors.Errorf("biosUUID is empty while VM is ready")
 	}
 
+	// Update the VSphereVM's instance UUID, it is used to look up the VM with the
+	// InstanceUUIDLookup feature gate.
+	if vm.InstanceUUID != "" && vmCtx.VSphereVM.Status.InstanceUUID != vm.InstanceUUID {
+		log.Info("Update VM instanceUUID", "instanceUUID", vm.InstanceUUID)
+		vmCtx.VSphereVM.Status.InstanceUUID = vm.InstanceUUID
+	}
+
 	// VMRef should be set just once. It is not supposed to change!
 	if vm.VMRef != "" && vmCtx.VSphereVM.Status.VMRef == "" {
 		log.Info("Update VM vmRef", "vmRef", vm.VMRef)
@@ -466,31 +729,57 @@ func (r vmReconciler) reconcileNormal(ctx context.Context, vmCtx *capvcontext.VM
 		return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
 	}
 
+	// Wait for the guest readiness gates, if any, before considering the VM ready.
+	if gate, ok := r.firstUnsatisfiedGuestReadinessGate(vmCtx); !ok {
+		log.Info("VM is waiting for guest readiness gate", "gate", gate)
+		conditions.MarkFalse(vmCtx.VSphereVM, infrav1.VMProvisionedCondition, infrav1.WaitingForGuestReadinessGatesReason, clusterv1.ConditionSeverityInfo,
+			"waiting for %s", gate)
+		return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
 	// Once the network is online the VM is considered ready.
 	vmCtx.VSphereVM.Status.Ready = true
 	conditions.MarkTrue(vmCtx.VSphereVM, infrav1.VMProvisionedCondition)
 	log.Info("VSphereVM is ready")
-	return reconcile.Result{}, nil
+	return result, nil
+}
+
+// firstUnsatisfiedGuestReadinessGate returns the first guest readiness gate of the
+// VSphereVM whose corresponding condition is not true; it returns true if all the
+// gates are satisfied.
+func (r vmReconciler) firstUnsatisfiedGuestReadinessGate(vmCtx *capvcontext.VMContext) (infrav1.GuestReadinessGate, bool) {
+	for _, gate := range vmCtx.VSphereVM.Spec.GuestReadinessGates {
+		if !conditions.IsTrue(vmCtx.VSphereVM, clusterv1.ConditionType(gate)) {
+			return gate, false
+		}
+	}
+	return "", true
 }
 
 // isWaitingForStaticIPAllocation checks whether the VM should wait for a static IP
 // to be allocated.
 // It checks the state of both DHCP4 and DHCP6 for all the network devices and if
-// any static IP addresses or IPAM Pools are specified.
+// any static IP addresses, IPAM Pools or Network Protocol Profiles are specified.
 func (r vmReconciler) isWaitingForStaticIPAllocation(vmCtx *capvcontext.VMContext) bool {
 	devices := vmCtx.VSphereVM.Spec.Network.Devices
-	for _, dev := range devices {
+	for i, dev := range devices {
 		// Ignore device if SkipIPAllocation is set.
 		if dev.SkipIPAllocation {
 			continue
 		}
 
+		// Ignore device if it is a bond member other than the first one, which
+		// holds the IP configuration of the bond.
+		if util.IsSecondaryBondMember(devices, i) {
+			continue
+		}
+
 		// Ignore device if it is configured to use DHCP.
 		if dev.DHCP4 || dev.DHCP6 {
 			continue
 		}
 
-		if len(dev.IPAddrs) == 0 && len(dev.AddressesFromPools) == 0 {
+		if len(dev.IPAddrs) == 0 && len(dev.AddressesFromPools) == 0 && dev.AddressFromNetworkProtocolProfile == nil {
 			// One or more IPs are expected for the device but are not set yet.
 			return true
 		}
@@ -594,7 +883,8 @@ func (r vmReconciler) retrieveVcenterSession(ctx context.Context, vsphereVM *inf
 		WithServer(vsphereVM.Spec.Server).
 		WithDatacenter(vsphereVM.Spec.Datacenter).
 		WithUserInfo(r.ControllerManagerContext.Username, r.ControllerManagerContext.Password).
-		WithThumbprint(vsphereVM.Spec.Thumbprint)
+		WithThumbprint(vsphereVM.Spec.Thumbprint).
+		WithProxy(r.ControllerManagerContext.VCenterProxy)
 
 	cluster, err := clusterutilv1.GetClusterFromMetadata(ctx, r.Client, vsphereVM.ObjectMeta)
 	if err != nil {
@@ -615,13 +905,20 @@ func (r vmReconciler) retrieveVcenterSession(ctx context.Context, vsphereVM *inf
 		log.V(4).Info("Using credentials provided to the manager to create the authenticated session, failed to get VSphereCluster")
 		return session.GetOrCreate(ctx, params)
 	}
+	params = params.WithProxy(session.ProxyForCluster(vsphereCluster, r.ControllerManagerContext.VCenterProxy))
+
+	tlsConfig, err := identity.GetTLSConfig(ctx, r.Client, vsphereCluster, r.ControllerManagerContext.Namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get TLS configuration")
+	}
+	params = params.WithTLSConfig(tlsConfig)
 
 	if vsphereCluster.Spec.IdentityRef != nil {
 		creds, err := identity.GetCredentials(ctx, r.Client, vsphereCluster, r.ControllerManagerContext.Namespace)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to get credentials from IdentityRef")
 		}
-		params = params.WithUserInfo(creds.Username, creds.Password)
+		params = params.WithUserInfo(creds.Username, creds.Password).WithTokenSource(creds.TokenSource).WithAllowedTargets(creds.AllowedTargets)
 		return session.GetOrCreate(ctx, params)
 	}
 
@@ -637,6 +934,10 @@ func (r vmReconciler) fetchClusterModuleInfo(ctx context.Context, clusterModInpu
 	)
 	log := ctrl.LoggerFrom(ctx)
 	machine := clusterModInput.Machine
+	// VSphereVMs of a VSphereMachinePool have no Machine and are not part of a cluster module.
+	if machine == nil {
+		return nil, nil
+	}
 
 	input := util.FetchObjectInput{
 		Client: r.Client,
@@ -658,7 +959,8 @@ func (r vmReconciler) fetchClusterModuleInfo(ctx context.Context, clusterModInpu
 	}
 
 	for _, mod := range clusterModInput.VSphereCluster.Spec.ClusterModules {
-		if mod.TargetObjectName == owner.GetName() {
+		// Unused cluster modules are kept for a deletion grace period and are not used by the owner anymore.
+		if mod.TargetObjectName == owner.GetName() && mod.UnusedSince == nil {
 			log.V(4).Info("Cluster module found", "moduleUUID", mod.ModuleUUID)
 			return ptr.To(mod.ModuleUUID), nil
 		}
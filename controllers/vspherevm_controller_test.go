@@ -18,6 +18,7 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -355,6 +356,22 @@ func TestVmReconciler_WaitingForStaticIPAllocation(t *testing.T) {
 			},
 			shouldWait: true,
 		},
+		{
+			name: "for bonded n/w devices with IP addresses set for the first member",
+			devices: []infrav1.NetworkDeviceSpec{
+				{NetworkName: "nw-1", IPAddrs: []string{"192.168.1.2/32"}, Bond: &infrav1.NetworkBondSpec{Name: "bond0"}},
+				{NetworkName: "nw-2", Bond: &infrav1.NetworkBondSpec{Name: "bond0"}},
+			},
+			shouldWait: false,
+		},
+		{
+			name: "for bonded n/w devices without IP addresses set",
+			devices: []infrav1.NetworkDeviceSpec{
+				{NetworkName: "nw-1", Bond: &infrav1.NetworkBondSpec{Name: "bond0"}},
+				{NetworkName: "nw-2", Bond: &infrav1.NetworkBondSpec{Name: "bond0"}},
+			},
+			shouldWait: true,
+		},
 	}
 
 	controllerManagerCtx := fake.NewControllerManagerContext()
@@ -669,6 +686,193 @@ func Test_reconcile(t *testing.T) {
 			// Assertion to verify that cluster module info is not mandatory
 			g.Expect(err).NotTo(HaveOccurred())
 		})
+
+		t.Run("when pre-terminate delete hooks remain", func(t *testing.T) {
+			hookedVM := vsphereVM.DeepCopy()
+			hookedVM.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			hookedVM.Annotations = map[string]string{clusterv1.PreTerminateDeleteHookAnnotationPrefix + "/cmdb": ""}
+
+			// DestroyVM is not expected to be called.
+			r := setupReconciler(new(fake_svc.VMService), vsphereCluster, machine, hookedVM)
+			_, err := r.reconcile(ctx, &capvcontext.VMContext{
+				ControllerManagerContext: r.ControllerManagerContext,
+				VSphereVM:                hookedVM,
+			}, fetchClusterModuleInput{
+				VSphereCluster: vsphereCluster,
+				Machine:        machine,
+			})
+
+			g := NewWithT(t)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(conditions.GetReason(hookedVM, clusterv1.PreTerminateDeleteHookSucceededCondition)).To(Equal(clusterv1.WaitingExternalHookReason))
+			g.Expect(hookedVM.Finalizers).To(ContainElement(infrav1.VMFinalizer))
+		})
+
+		t.Run("when the VM cannot be destroyed", func(t *testing.T) {
+			tests := []struct {
+				name              string
+				timeout           time.Duration
+				vmExists          bool
+				expectErr         bool
+				expectReason      string
+				expectFinalizer   bool
+				expectVMExistCall bool
+			}{
+				{
+					name:            "without a VM deletion failure timeout",
+					expectErr:       true,
+					expectReason:    infrav1.VMDeletionFailedReason,
+					expectFinalizer: true,
+				},
+				{
+					name:            "before the VM deletion failure timeout",
+					timeout:         time.Hour,
+					expectErr:       true,
+					expectReason:    infrav1.VMDeletionFailedReason,
+					expectFinalizer: true,
+				},
+				{
+					name:              "after the VM deletion failure timeout when the VM still exists",
+					timeout:           time.Minute,
+					vmExists:          true,
+					expectErr:         true,
+					expectReason:      infrav1.VMStillExistsReason,
+					expectFinalizer:   true,
+					expectVMExistCall: true,
+				},
+				{
+					name:              "after the VM deletion failure timeout when the VM is gone",
+					timeout:           time.Minute,
+					expectReason:      infrav1.VMForceDeletedReason,
+					expectVMExistCall: true,
+				},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					g := NewWithT(t)
+					failedVM := vsphereVM.DeepCopy()
+					failedVM.DeletionTimestamp = &metav1.Time{Time: time.Now().Add(-10 * time.Minute)}
+					failedVM.Finalizers = append(failedVM.Finalizers, "keep-this-for-the-test")
+
+					fakeVMSvc := new(fake_svc.VMService)
+					fakeVMSvc.On("DestroyVM", mock.Anything).Return(reconcile.Result{}, infrav1.VirtualMachine{
+						Name:  failedVM.Name,
+						State: infrav1.VirtualMachineStatePending,
+					}, errors.New("InvalidState: the VM is orphaned"))
+					fakeVMSvc.On("VMExists", mock.Anything).Return(tt.vmExists, nil)
+
+					r := setupReconciler(fakeVMSvc, vsphereCluster, machine, failedVM)
+					r.VMDeletionFailureTimeout = tt.timeout
+					_, err := r.reconcile(ctx, &capvcontext.VMContext{
+						ControllerManagerContext: r.ControllerManagerContext,
+						VSphereVM:                failedVM,
+					}, fetchClusterModuleInput{
+						VSphereCluster: vsphereCluster,
+						Machine:        machine,
+					})
+
+					if tt.expectErr {
+						g.Expect(err).To(HaveOccurred())
+					} else {
+						g.Expect(err).NotTo(HaveOccurred())
+					}
+					g.Expect(conditions.GetReason(failedVM, infrav1.VMDeletedCondition)).To(Equal(tt.expectReason))
+					if tt.expectFinalizer {
+						g.Expect(failedVM.Finalizers).To(ContainElement(infrav1.VMFinalizer))
+					} else {
+						g.Expect(failedVM.Finalizers).NotTo(ContainElement(infrav1.VMFinalizer))
+					}
+					if tt.expectVMExistCall {
+						fakeVMSvc.AssertCalled(t, "VMExists", mock.Anything)
+					} else {
+						fakeVMSvc.AssertNotCalled(t, "VMExists", mock.Anything)
+					}
+				})
+			}
+		})
+	})
+}
+
+func TestVmReconciler_reconcileNodeGuestOSHealth(t *testing.T) {
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "valid-cluster",
+			Namespace: "test",
+		},
+	}
+	newVSphereVM := func() *infrav1.VSphereVM {
+		return &infrav1.VSphereVM{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "test",
+				Labels: map[string]string{
+					clusterv1.ClusterNameLabel: cluster.Name,
+				},
+			},
+		}
+	}
+	getNodeCondition := func(g *WithT, c client.Client) *corev1.NodeCondition {
+		node := &corev1.Node{}
+		g.Expect(c.Get(ctx, client.ObjectKey{Name: "foo"}, node)).To(Succeed())
+		for i := range node.Status.Conditions {
+			if node.Status.Conditions[i].Type == corev1.NodeConditionType(infrav1.GuestOSHealthyCondition) {
+				return &node.Status.Conditions[i]
+			}
+		}
+		return nil
+	}
+
+	setupReconciler := func(initObjs ...client.Object) vmReconciler {
+		controllerMgrContext := fake.NewControllerManagerContext(append(initObjs, cluster)...)
+		return vmReconciler{
+			ControllerManagerContext: controllerMgrContext,
+			clusterCache:             clustercache.NewFakeClusterCache(controllerMgrContext.Client, client.ObjectKeyFromObject(cluster)),
+		}
+	}
+
+	t.Run("ignores VMs whose Node does not exist", func(t *testing.T) {
+		g := NewWithT(t)
+		vsphereVM := newVSphereVM()
+		conditions.MarkTrue(vsphereVM, infrav1.GuestOSHealthyCondition)
+
+		r := setupReconciler()
+		g.Expect(r.reconcileNodeGuestOSHealth(ctx, &capvcontext.VMContext{VSphereVM: vsphereVM})).To(Succeed())
+	})
+
+	t.Run("does not set an unknown condition", func(t *testing.T) {
+		g := NewWithT(t)
+		vsphereVM := newVSphereVM()
+		conditions.MarkUnknown(vsphereVM, infrav1.GuestOSHealthyCondition, infrav1.GuestCrashEventsUnavailableReason, "")
+
+		r := setupReconciler(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "foo"}})
+		g.Expect(r.reconcileNodeGuestOSHealth(ctx, &capvcontext.VMContext{VSphereVM: vsphereVM})).To(Succeed())
+		g.Expect(getNodeCondition(g, r.Client)).To(BeNil())
+	})
+
+	t.Run("mirrors the condition to the Node", func(t *testing.T) {
+		g := NewWithT(t)
+		vsphereVM := newVSphereVM()
+		conditions.MarkTrue(vsphereVM, infrav1.GuestOSHealthyCondition)
+
+		r := setupReconciler(&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+			},
+		})
+		g.Expect(r.reconcileNodeGuestOSHealth(ctx, &capvcontext.VMContext{VSphereVM: vsphereVM})).To(Succeed())
+		g.Expect(getNodeCondition(g, r.Client).Status).To(Equal(corev1.ConditionTrue))
+
+		conditions.MarkFalse(vsphereVM, infrav1.GuestOSHealthyCondition, infrav1.GuestOSCrashLoopingReason, clusterv1.ConditionSeverityWarning, "crashed")
+		g.Expect(r.reconcileNodeGuestOSHealth(ctx, &capvcontext.VMContext{VSphereVM: vsphereVM})).To(Succeed())
+		nodeCondition := getNodeCondition(g, r.Client)
+		g.Expect(nodeCondition.Status).To(Equal(corev1.ConditionFalse))
+		g.Expect(nodeCondition.Reason).To(Equal(infrav1.GuestOSCrashLoopingReason))
+		g.Expect(nodeCondition.Message).To(Equal("crashed"))
+
+		node := &corev1.Node{}
+		g.Expect(r.Client.Get(ctx, client.ObjectKey{Name: "foo"}, node)).To(Succeed())
+		g.Expect(node.Status.Conditions).To(HaveLen(2))
 	})
 }
 
@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -36,6 +37,7 @@ import (
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
 	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/metrics"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/util"
 )
 
@@ -50,9 +52,13 @@ func (r vmReconciler) reconcileIPAddressClaims(ctx context.Context, vmCtx *capvc
 	log := ctrl.LoggerFrom(ctx)
 
 	var (
-		claims  []conditions.Getter
-		errList []error
+		claims         []conditions.Getter
+		errList        []error
+		exhaustedPools []string
 	)
+	// The bind duration of the claims is only observed while the VSphereVM is waiting for them,
+	// so that it isn't observed again for claims bound before the controller manager restarted.
+	waitingForClaims := !conditions.IsTrue(vmCtx.VSphereVM, infrav1.IPAddressClaimedCondition)
 
 	for devIdx, device := range vmCtx.VSphereVM.Spec.Network.Devices {
 		for poolRefIdx, poolRef := range device.AddressesFromPools {
@@ -82,6 +88,12 @@ func (r vmReconciler) reconcileIPAddressClaims(ctx context.Context, vmCtx *capvc
 			}
 			if ipAddrClaim.Status.AddressRef.Name != "" {
 				claimsFulfilled++
+				if waitingForClaims {
+					metrics.ObserveIPAddressClaimBound(ipAddrClaim)
+				}
+			}
+			if metrics.IsPoolExhausted(ipAddrClaim) {
+				exhaustedPools = append(exhaustedPools, fmt.Sprintf("%s %s", poolRef.Kind, poolRef.Name))
 			}
 
 			// Since this is eventually used to calculate the status of the
@@ -102,6 +114,17 @@ func (r vmReconciler) reconcileIPAddressClaims(ctx context.Context, vmCtx *capvc
 		return aggregatedErr
 	}
 
+	// Surface exhausted pools explicitly, as new VMs requesting addresses from them stall until
+	// addresses are released or added to the pools.
+	if len(exhaustedPools) > 0 {
+		conditions.MarkFalse(vmCtx.VSphereVM,
+			infrav1.IPAddressClaimedCondition,
+			infrav1.IPAddressPoolExhaustedReason,
+			clusterv1.ConditionSeverityWarning,
+			"%d/%d claims waiting for exhausted pools: %s", len(exhaustedPools), totalClaims, strings.Join(exhaustedPools, ", "))
+		return nil
+	}
+
 	// Calculating the IPAddressClaimedCondition from the Ready Condition of the individual IPAddressClaims.
 	// This will not work if the IPAM provider does not set the Ready condition on the IPAddressClaim.
 	// To correctly calculate the status of the condition, we would want all the IPAddressClaim objects
@@ -158,6 +181,7 @@ func createOrPatchIPAddressClaim(ctx context.Context, vmCtx *capvcontext.VMConte
 			claim.Labels = make(map[string]string)
 		}
 		claim.Labels[clusterv1.ClusterNameLabel] = vmCtx.VSphereVM.Labels[clusterv1.ClusterNameLabel]
+		claim.Spec.ClusterName = vmCtx.VSphereVM.Labels[clusterv1.ClusterNameLabel]
 
 		claim.Spec.PoolRef.APIGroup = poolRef.APIGroup
 		claim.Spec.PoolRef.Kind = poolRef.Kind
@@ -208,6 +232,7 @@ func (r vmReconciler) deleteIPAddressClaims(ctx context.Context, vmCtx *capvcont
 					return errors.Wrapf(err, fmt.Sprintf("failed to update IPAddressClaim %s", klog.KObj(ipAddrClaim)))
 				}
 			}
+			metrics.ForgetIPAddressClaim(ipAddrClaim)
 		}
 	}
 	return nil
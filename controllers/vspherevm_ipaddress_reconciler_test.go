@@ -93,6 +93,7 @@ func Test_vmReconciler_reconcileIPAddressClaims(t *testing.T) {
 				g.Expect(claim.OwnerReferences).To(gomega.HaveLen(1))
 				g.Expect(claim.OwnerReferences[0].Name).To(gomega.Equal(vsphereVM.Name))
 				g.Expect(claim.Labels).To(gomega.HaveKeyWithValue(clusterv1.ClusterNameLabel, "my-cluster"))
+				g.Expect(claim.Spec.ClusterName).To(gomega.Equal("my-cluster"))
 			}
 
 			claimedCondition := conditions.Get(testCtx.VSphereVM, infrav1.IPAddressClaimedCondition)
@@ -141,6 +142,7 @@ func Test_vmReconciler_reconcileIPAddressClaims(t *testing.T) {
 				g.Expect(claim.OwnerReferences).To(gomega.HaveLen(1))
 				g.Expect(claim.OwnerReferences[0].Name).To(gomega.Equal(vsphereVM.Name))
 				g.Expect(claim.Labels).To(gomega.HaveKeyWithValue(clusterv1.ClusterNameLabel, "my-cluster"))
+				g.Expect(claim.Spec.ClusterName).To(gomega.Equal("my-cluster"))
 			}
 		})
 
@@ -175,6 +177,7 @@ func Test_vmReconciler_reconcileIPAddressClaims(t *testing.T) {
 				g.Expect(claim.OwnerReferences).To(gomega.HaveLen(1))
 				g.Expect(claim.OwnerReferences[0].Name).To(gomega.Equal(vsphereVM.Name))
 				g.Expect(claim.Labels).To(gomega.HaveKeyWithValue(clusterv1.ClusterNameLabel, "my-cluster"))
+				g.Expect(claim.Spec.ClusterName).To(gomega.Equal("my-cluster"))
 			}
 		})
 
@@ -239,6 +242,33 @@ func Test_vmReconciler_reconcileIPAddressClaims(t *testing.T) {
 			g.Expect(claimedCondition.Reason).To(gomega.Equal(infrav1.WaitingForIPAddressReason))
 			g.Expect(claimedCondition.Message).To(gomega.Equal("2/3 claims being processed"))
 		})
+
+		t.Run("when a pool of an existing claim is exhausted", func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			realizedIPAddrClaim := ipAddrClaim(util.IPAddressClaimName(name, 0, 0), "my-pool-1")
+			realizedIPAddrClaim.Status.AddressRef.Name = "blah-one"
+
+			exhaustedIPAddrClaim := ipAddrClaim(util.IPAddressClaimName(name, 1, 0), "my-pool-2")
+			exhaustedIPAddrClaim.Status.Conditions = clusterv1.Conditions{
+				*conditions.FalseCondition(clusterv1.ReadyCondition, ipamv1.PoolExhaustedReason, clusterv1.ConditionSeverityError, "no address available"),
+			}
+
+			testCtx := setup(vsphereVM,
+				realizedIPAddrClaim,
+				exhaustedIPAddrClaim,
+				ipAddrClaim(util.IPAddressClaimName(name, 1, 1), "my-pool-3"),
+			)
+			err := vmReconciler{}.reconcileIPAddressClaims(ctx, testCtx)
+			g.Expect(err).ToNot(gomega.HaveOccurred())
+
+			claimedCondition := conditions.Get(testCtx.VSphereVM, infrav1.IPAddressClaimedCondition)
+			g.Expect(claimedCondition).NotTo(gomega.BeNil())
+			g.Expect(claimedCondition.Status).To(gomega.Equal(corev1.ConditionFalse))
+			g.Expect(claimedCondition.Reason).To(gomega.Equal(infrav1.IPAddressPoolExhaustedReason))
+			g.Expect(claimedCondition.Severity).To(gomega.Equal(clusterv1.ConditionSeverityWarning))
+			g.Expect(claimedCondition.Message).To(gomega.Equal("1/3 claims waiting for exhausted pools: my-pool-kind my-pool-2"))
+		})
 	})
 }
 
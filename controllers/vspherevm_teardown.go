@@ -0,0 +1,235 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/fault"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/govmomi/vcenter"
+)
+
+// clusterTeardownRequeueAfter is the time after which a VSphereVM waiting for
+// its turn in the teardown of its cluster is reconciled again.
+const clusterTeardownRequeueAfter = 10 * time.Second
+
+// waitForClusterTeardown returns true if the VM of a VSphereVM of a deleted cluster
+// can't be destroyed yet. The worker VMs of the cluster are destroyed before its control
+// plane VMs, and at most ClusterTeardownConcurrency VMs are destroyed at the same time,
+// in the order the VSphereVMs have been deleted.
+func (r vmReconciler) waitForClusterTeardown(ctx context.Context, vmCtx *capvcontext.VMContext) (bool, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// A VM whose destruction is in progress is never held back.
+	if isClusterTeardownStarted(vmCtx.VSphereVM) {
+		return false, nil
+	}
+
+	vsphereVMs := &infrav1.VSphereVMList{}
+	if err := r.Client.List(ctx, vsphereVMs,
+		ctrlclient.InNamespace(vmCtx.VSphereVM.Namespace),
+		ctrlclient.MatchingLabels{clusterv1.ClusterNameLabel: vmCtx.VSphereVM.Labels[clusterv1.ClusterNameLabel]}); err != nil {
+		return false, errors.Wrapf(err, "failed to list VSphereVMs of cluster %s", vmCtx.VSphereVM.Labels[clusterv1.ClusterNameLabel])
+	}
+
+	if isControlPlaneVSphereVM(vmCtx.VSphereVM) {
+		workers := 0
+		for i := range vsphereVMs.Items {
+			if !isControlPlaneVSphereVM(&vsphereVMs.Items[i]) {
+				workers++
+			}
+		}
+		if workers > 0 {
+			log.Info("Waiting for the worker VMs of the cluster to be deleted before deleting the control plane VM", "count", workers)
+			conditions.MarkFalse(vmCtx.VSphereVM, infrav1.VMProvisionedCondition, infrav1.WaitingForClusterTeardownReason, clusterv1.ConditionSeverityInfo,
+				"Waiting for %d worker VMs to be deleted", workers)
+			return true, nil
+		}
+	}
+
+	if vmCtx.ClusterTeardownConcurrency <= 0 {
+		return false, nil
+	}
+
+	inFlight := 0
+	pending := []*infrav1.VSphereVM{}
+	for i := range vsphereVMs.Items {
+		vsphereVM := &vsphereVMs.Items[i]
+		if vsphereVM.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if isClusterTeardownStarted(vsphereVM) {
+			inFlight++
+			continue
+		}
+		pending = append(pending, vsphereVM)
+	}
+
+	// VSphereVMs are destroyed in the order they have been deleted. The name breaks
+	// ties, so that all the VSphereVMs agree on the order.
+	sort.Slice(pending, func(i, j int) bool {
+		if !pending[i].DeletionTimestamp.Equal(pending[j].DeletionTimestamp) {
+			return pending[i].DeletionTimestamp.Before(pending[j].DeletionTimestamp)
+		}
+		return pending[i].Name < pending[j].Name
+	})
+
+	position := 0
+	for _, vsphereVM := range pending {
+		if vsphereVM.Name == vmCtx.VSphereVM.Name {
+			break
+		}
+		// Control plane VMs do not take a slot from worker VMs.
+		if isControlPlaneVSphereVM(vsphereVM) == isControlPlaneVSphereVM(vmCtx.VSphereVM) {
+			position++
+		}
+	}
+
+	if inFlight+position >= vmCtx.ClusterTeardownConcurrency {
+		log.Info("Waiting for other VMs of the cluster to be deleted", "inFlight", inFlight, "position", position)
+		conditions.MarkFalse(vmCtx.VSphereVM, infrav1.VMProvisionedCondition, infrav1.WaitingForClusterTeardownReason, clusterv1.ConditionSeverityInfo,
+			"Waiting for %d VMs to be deleted first", inFlight+position)
+		return true, nil
+	}
+	return false, nil
+}
+
+// isClusterTeardownStarted returns true if the destruction of the VM of the
+// VSphereVM has been started, i.e. it is past the cluster teardown checks.
+func isClusterTeardownStarted(vsphereVM *infrav1.VSphereVM) bool {
+	if vsphereVM.Status.TaskRef != "" {
+		return true
+	}
+	switch conditions.GetReason(vsphereVM, infrav1.VMProvisionedCondition) {
+	case clusterv1.DeletingReason, "DeletionFailed":
+		return true
+	}
+	return false
+}
+
+// isControlPlaneVSphereVM returns true if the VSphereVM is the VM of a control plane machine.
+func isControlPlaneVSphereVM(vsphereVM *infrav1.VSphereVM) bool {
+	_, ok := vsphereVM.Labels[clusterv1.MachineControlPlaneLabel]
+	return ok
+}
+
+// deleteClusterFolderIfEmpty deletes the folder generated by the folder naming strategy
+// of the VSphereCluster once the last VM in it has been destroyed. A folder which still
+// contains other objects is left in place.
+func deleteClusterFolderIfEmpty(ctx context.Context, vmCtx *capvcontext.VMContext) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	folderPath := vmCtx.VSphereVM.Spec.Folder
+	if vmCtx.ClusterFolder == "" || vmCtx.DryRun || path.Base(folderPath) != vmCtx.ClusterFolder {
+		return nil
+	}
+
+	folder, err := vmCtx.Session.Finder.Folder(ctx, folderPath)
+	if err != nil {
+		if _, ok := err.(*find.NotFoundError); ok {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to find cluster folder %s", folderPath)
+	}
+
+	children, err := folder.Children(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the children of cluster folder %s", folderPath)
+	}
+	if len(children) > 0 {
+		log.V(4).Info("Cluster folder is not empty, skipping its deletion", "folder", folderPath, "children", len(children))
+		return nil
+	}
+
+	log.Info("Deleting empty cluster folder", "folder", folderPath)
+	task, err := folder.Destroy(ctx)
+	if err == nil {
+		err = task.Wait(ctx)
+	}
+	// The folder was deleted concurrently for another VM of the cluster.
+	if err != nil && !fault.Is(err, &types.ManagedObjectNotFound{}) {
+		return errors.Wrapf(err, "failed to delete cluster folder %s", folderPath)
+	}
+	return nil
+}
+
+// deleteChildResourcePoolIfEmpty deletes the child resource pool of the VSphereVM once the
+// last VM in it has been destroyed. A resource pool which still contains VMs or other
+// resource pools is left in place, as destroying it would move them to its parent.
+func deleteChildResourcePoolIfEmpty(ctx context.Context, vmCtx *capvcontext.VMContext) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	childSpec := vmCtx.VSphereVM.Spec.ChildResourcePool
+	if childSpec == nil || vmCtx.DryRun {
+		return nil
+	}
+
+	parent, err := vmCtx.Session.Finder.ResourcePoolOrDefault(ctx, vmCtx.VSphereVM.Spec.ResourcePool)
+	if err != nil {
+		if _, ok := err.(*find.NotFoundError); ok {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to find resource pool %s", vmCtx.VSphereVM.Spec.ResourcePool)
+	}
+	poolPath := path.Join(parent.InventoryPath, childSpec.Name)
+	pool, err := vmCtx.Session.Finder.ResourcePool(ctx, poolPath)
+	if err != nil {
+		if _, ok := err.(*find.NotFoundError); ok {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to find child resource pool %s", poolPath)
+	}
+
+	var obj mo.ResourcePool
+	if err := pool.Properties(ctx, pool.Reference(), []string{"vm", "resourcePool"}, &obj); err != nil {
+		return errors.Wrapf(err, "failed to list the children of child resource pool %s", poolPath)
+	}
+	// vCLS system VMs don't prevent the deletion, vCenter moves them to the parent resource pool.
+	vms, err := vcenter.NonVCLSVMs(ctx, vmCtx.Session.Client, obj.Vm)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the VMs of child resource pool %s", poolPath)
+	}
+	if len(vms) > 0 || len(obj.ResourcePool) > 0 {
+		log.V(4).Info("Child resource pool is not empty, skipping its deletion", "resourcePool", poolPath, "vms", len(vms))
+		return nil
+	}
+
+	log.Info("Deleting empty child resource pool", "resourcePool", poolPath)
+	task, err := pool.Destroy(ctx)
+	if err == nil {
+		err = task.Wait(ctx)
+	}
+	// The resource pool was deleted concurrently for another VM of the cluster.
+	if err != nil && !fault.Is(err, &types.ManagedObjectNotFound{}) {
+		return errors.Wrapf(err, "failed to delete child resource pool %s", poolPath)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/context/fake"
+)
+
+func Test_vmReconciler_waitForClusterTeardown(t *testing.T) {
+	now := time.Now()
+	newVSphereVM := func(name string, controlPlane bool, deletedAt *time.Time, taskRef string) *infrav1.VSphereVM {
+		vsphereVM := &infrav1.VSphereVM{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       name,
+				Namespace:  "test",
+				Labels:     map[string]string{clusterv1.ClusterNameLabel: "cluster"},
+				Finalizers: []string{infrav1.VMFinalizer},
+			},
+			Status: infrav1.VSphereVMStatus{TaskRef: taskRef},
+		}
+		if controlPlane {
+			vsphereVM.Labels[clusterv1.MachineControlPlaneLabel] = ""
+		}
+		if deletedAt != nil {
+			vsphereVM.DeletionTimestamp = &metav1.Time{Time: *deletedAt}
+		}
+		return vsphereVM
+	}
+	at := func(offset time.Duration) *time.Time {
+		t := now.Add(offset).Truncate(time.Second)
+		return &t
+	}
+
+	tests := []struct {
+		name        string
+		concurrency int
+		vsphereVMs  []*infrav1.VSphereVM
+		wantWait    bool
+	}{
+		{
+			name:        "worker VM proceeds with unlimited concurrency",
+			concurrency: 0,
+			vsphereVMs: []*infrav1.VSphereVM{
+				newVSphereVM("vm", false, at(0), ""),
+				newVSphereVM("worker-1", false, at(0), "task-1"),
+				newVSphereVM("worker-2", false, at(0), "task-2"),
+			},
+			wantWait: false,
+		},
+		{
+			name:        "control plane VM waits for worker VMs",
+			concurrency: 0,
+			vsphereVMs: []*infrav1.VSphereVM{
+				newVSphereVM("vm", true, at(0), ""),
+				newVSphereVM("worker-1", false, at(0), "task-1"),
+			},
+			wantWait: true,
+		},
+		{
+			name:        "control plane VM proceeds once worker VMs are deleted",
+			concurrency: 0,
+			vsphereVMs: []*infrav1.VSphereVM{
+				newVSphereVM("vm", true, at(0), ""),
+				newVSphereVM("control-plane-1", true, at(0), ""),
+			},
+			wantWait: false,
+		},
+		{
+			name:        "VM waits when the concurrency limit is reached",
+			concurrency: 2,
+			vsphereVMs: []*infrav1.VSphereVM{
+				newVSphereVM("vm", false, at(0), ""),
+				newVSphereVM("worker-1", false, at(0), "task-1"),
+				newVSphereVM("worker-2", false, at(0), "task-2"),
+			},
+			wantWait: true,
+		},
+		{
+			name:        "VM waits for the VMs deleted before it",
+			concurrency: 2,
+			vsphereVMs: []*infrav1.VSphereVM{
+				newVSphereVM("vm", false, at(0), ""),
+				newVSphereVM("worker-1", false, at(0), "task-1"),
+				newVSphereVM("worker-2", false, at(-time.Minute), ""),
+			},
+			wantWait: true,
+		},
+		{
+			name:        "VM proceeds when deleted before the other pending VMs",
+			concurrency: 2,
+			vsphereVMs: []*infrav1.VSphereVM{
+				newVSphereVM("vm", false, at(-time.Minute), ""),
+				newVSphereVM("worker-1", false, at(0), "task-1"),
+				newVSphereVM("worker-2", false, at(0), ""),
+			},
+			wantWait: false,
+		},
+		{
+			name:        "VM whose destruction is in progress proceeds when the limit is exceeded",
+			concurrency: 1,
+			vsphereVMs: []*infrav1.VSphereVM{
+				newVSphereVM("vm", false, at(0), "task"),
+				newVSphereVM("worker-1", false, at(0), "task-1"),
+			},
+			wantWait: false,
+		},
+		{
+			name:        "VMs which are not deleted are ignored",
+			concurrency: 1,
+			vsphereVMs: []*infrav1.VSphereVM{
+				newVSphereVM("vm", false, at(0), ""),
+				newVSphereVM("worker-1", false, nil, ""),
+			},
+			wantWait: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			objs := []client.Object{}
+			for _, vsphereVM := range tt.vsphereVMs {
+				objs = append(objs, vsphereVM)
+			}
+			controllerManagerCtx := fake.NewControllerManagerContext(objs...)
+			controllerManagerCtx.ClusterTeardownConcurrency = tt.concurrency
+			r := vmReconciler{ControllerManagerContext: controllerManagerCtx}
+
+			vmCtx := &capvcontext.VMContext{
+				ControllerManagerContext: controllerManagerCtx,
+				VSphereVM:                tt.vsphereVMs[0],
+				ClusterDeleting:          true,
+			}
+			wait, err := r.waitForClusterTeardown(context.Background(), vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(wait).To(Equal(tt.wantWait))
+			if tt.wantWait {
+				g.Expect(conditions.GetReason(vmCtx.VSphereVM, infrav1.VMProvisionedCondition)).To(Equal(infrav1.WaitingForClusterTeardownReason))
+			}
+		})
+	}
+}
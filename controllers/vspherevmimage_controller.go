@@ -0,0 +1,178 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/identity"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/govmomi/template"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/session"
+)
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=vspherevmimages,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=vspherevmimages/status,verbs=get;update;patch
+
+// AddVSphereVMImageControllerToManager adds the VSphereVMImage controller to the provided manager.
+// The controller verifies that the VM templates of the VSphereVMImages exist in vCenter.
+func AddVSphereVMImageControllerToManager(ctx context.Context, controllerManagerCtx *capvcontext.ControllerManagerContext, mgr manager.Manager, options controller.Options) error {
+	r := vsphereVMImageReconciler{
+		ControllerManagerContext: controllerManagerCtx,
+	}
+	predicateLog := ctrl.LoggerFrom(ctx).WithValues("controller", "vspherevmimage")
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&infrav1.VSphereVMImage{}).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(mgr.GetScheme(), predicateLog, controllerManagerCtx.WatchFilterValue)).
+		Complete(r)
+}
+
+type vsphereVMImageReconciler struct {
+	*capvcontext.ControllerManagerContext
+}
+
+// Reconcile verifies the VM template of the VSphereVMImage and records it in the status.
+func (r vsphereVMImageReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ reconcile.Result, reterr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	image := &infrav1.VSphereVMImage{}
+	if err := r.Client.Get(ctx, req.NamespacedName, image); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if !image.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+	if annotations.HasPaused(image) {
+		log.Info("Reconciliation is paused for this object")
+		return reconcile.Result{}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(image, r.Client)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	defer func() {
+		if err := patchHelper.Patch(ctx, image); err != nil {
+			reterr = errors.Wrapf(err, "failed to patch VSphereVMImage %s", klog.KObj(image))
+		}
+	}()
+
+	s, err := r.getVCenterSession(ctx, image)
+	if err != nil {
+		conditions.MarkFalse(image, infrav1.VMImageVerifiedCondition, infrav1.VCenterUnreachableReason, clusterv1.ConditionSeverityError, err.Error())
+		return reconcile.Result{}, errors.Wrapf(err, "failed to get vCenter session for VSphereVMImage %s", klog.KObj(image))
+	}
+
+	tpl, err := template.ResolveImage(ctx, s, image.Spec)
+	if err != nil {
+		// The image stays usable by the VSphereVMs already cloned from it, only new VSphereVMs wait for it.
+		image.Status.Ready = false
+		conditions.MarkFalse(image, infrav1.VMImageVerifiedCondition, infrav1.VMImageVerificationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		log.Error(err, "Failed to verify VSphereVMImage")
+		return reconcile.Result{RequeueAfter: validationRefreshInterval}, nil
+	}
+
+	if image.Status.Template != "" && image.Status.Template != tpl {
+		log.Info("VM template of VSphereVMImage changed", "oldTemplate", image.Status.Template, "template", tpl)
+	}
+	image.Status.Template = tpl
+	image.Status.Ready = true
+	image.Status.LastVerified = ptr.To(metav1.Now())
+	conditions.MarkTrue(image, infrav1.VMImageVerifiedCondition)
+
+	// The VM template can be renamed or removed in vCenter at any time.
+	return reconcile.Result{RequeueAfter: validationRefreshInterval}, nil
+}
+
+// getVCenterSession returns a session for the vCenter of the image. It uses the identity of a
+// VSphereCluster of the same namespace and vCenter if any, and the credentials of the manager otherwise.
+func (r vsphereVMImageReconciler) getVCenterSession(ctx context.Context, image *infrav1.VSphereVMImage) (*session.Session, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	datacenter := image.Spec.Datacenter
+	if datacenter == "" {
+		datacenter = "*"
+	}
+	params := session.NewParams().
+		WithServer(image.Spec.Server).
+		WithDatacenter(datacenter).
+		WithThumbprint(image.Spec.Thumbprint).
+		WithUserInfo(r.ControllerManagerContext.Username, r.ControllerManagerContext.Password).
+		WithProxy(r.ControllerManagerContext.VCenterProxy)
+
+	clusterList := &infrav1.VSphereClusterList{}
+	if err := r.Client.List(ctx, clusterList, client.InNamespace(image.Namespace)); err != nil {
+		return nil, errors.Wrapf(err, "failed to list VSphereClusters")
+	}
+	for _, vsphereCluster := range clusterList.Items {
+		if image.Spec.Server != vsphereCluster.Spec.Server || vsphereCluster.Spec.IdentityRef == nil {
+			continue
+		}
+
+		// Note: We have to use := here to not overwrite log & ctx outside the for loop.
+		log := log.WithValues("VSphereCluster", klog.KRef(vsphereCluster.Namespace, vsphereCluster.Name))
+		ctx := ctrl.LoggerInto(ctx, log)
+
+		if image.Spec.Thumbprint == "" {
+			params = params.WithThumbprint(vsphereCluster.Spec.Thumbprint)
+		}
+		params = params.WithProxy(session.ProxyForCluster(&vsphereCluster, r.ControllerManagerContext.VCenterProxy))
+		vsphereCluster := vsphereCluster
+		creds, err := identity.GetCredentials(ctx, r.Client, &vsphereCluster, r.Namespace)
+		if err != nil {
+			log.Error(err, "error retrieving credentials from IdentityRef")
+			continue
+		}
+		tlsConfig, err := identity.GetTLSConfig(ctx, r.Client, &vsphereCluster, r.Namespace)
+		if err != nil {
+			log.Error(err, "error retrieving TLS configuration")
+			continue
+		}
+		log.V(4).Info("Using credentials from VSphereCluster IdentityRef to create the authenticated session")
+		params = params.WithUserInfo(creds.Username, creds.Password).
+			WithTokenSource(creds.TokenSource).
+			WithAllowedTargets(creds.AllowedTargets).
+			WithTLSConfig(tlsConfig)
+		return session.GetOrCreate(ctx, params)
+	}
+
+	// Fallback to using credentials provided to the manager
+	log.V(4).Info("Using credentials provided to the manager to create the authenticated session")
+	return session.GetOrCreate(ctx, params)
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/simulator"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/internal/test/helpers/vcsim"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/context/fake"
+)
+
+func TestVSphereVMImageReconciler_Reconcile(t *testing.T) {
+	simr, err := vcsim.NewBuilder().
+		WithModel(simulator.VPX()).
+		Build()
+	if err != nil {
+		t.Fatalf("unable to create simulator %s", err)
+	}
+	defer simr.Destroy()
+
+	tests := []struct {
+		name          string
+		template      string
+		wantReady     bool
+		wantCondition bool
+	}{
+		{
+			name:          "image with an existing template",
+			template:      "DC0_H0_VM0",
+			wantReady:     true,
+			wantCondition: true,
+		},
+		{
+			name:     "image with a missing template",
+			template: "missing",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			image := &infrav1.VSphereVMImage{
+				ObjectMeta: metav1.ObjectMeta{Namespace: fake.Namespace, Name: "node-image"},
+				Spec: infrav1.VSphereVMImageSpec{
+					Server:     simr.ServerURL().Host,
+					Datacenter: "DC0",
+					Template:   tt.template,
+				},
+			}
+			controllerManagerContext := fake.NewControllerManagerContext(image)
+			controllerManagerContext.Username = simr.ServerURL().User.Username()
+			pass, _ := simr.ServerURL().User.Password()
+			controllerManagerContext.Password = pass
+
+			r := vsphereVMImageReconciler{controllerManagerContext}
+			result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(image)})
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(result.RequeueAfter).To(Equal(validationRefreshInterval))
+
+			g.Expect(controllerManagerContext.Client.Get(ctx, client.ObjectKeyFromObject(image), image)).To(Succeed())
+			g.Expect(image.Status.Ready).To(Equal(tt.wantReady))
+			g.Expect(conditions.IsTrue(image, infrav1.VMImageVerifiedCondition)).To(Equal(tt.wantCondition))
+			if tt.wantReady {
+				g.Expect(image.Status.Template).To(HavePrefix("vm-"))
+				g.Expect(image.Status.LastVerified).NotTo(BeNil())
+			} else {
+				g.Expect(conditions.GetReason(image, infrav1.VMImageVerifiedCondition)).To(Equal(infrav1.VMImageVerificationFailedReason))
+			}
+		})
+	}
+}
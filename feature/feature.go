@@ -38,6 +38,40 @@ const (
 	//
 	// alpha: v1.11
 	NamespaceScopedZones featuregate.Feature = "NamespaceScopedZones"
+
+	// MachinePool is a feature gate for the VSphereMachinePool functionality in govmomi mode.
+	//
+	// alpha: v1.13
+	MachinePool featuregate.Feature = "MachinePool"
+
+	// TemplateSnapshotManagement is a feature gate for managing the base snapshots of templates used for linked clones.
+	//
+	// alpha: v1.13
+	TemplateSnapshotManagement featuregate.Feature = "TemplateSnapshotManagement"
+
+	// BatchedVCenterCalls is a feature gate for sharing a PropertyCollector between the VMs of a vCenter session
+	// and batching their power on operations in govmomi mode.
+	//
+	// alpha: v1.13
+	BatchedVCenterCalls featuregate.Feature = "BatchedVCenterCalls"
+
+	// VSphereIPPool is a feature gate for the IPAM provider allocating the IP addresses of
+	// IPAddressClaims from vCenter IP pools in govmomi mode.
+	//
+	// alpha: v1.13
+	VSphereIPPool featuregate.Feature = "VSphereIPPool"
+
+	// InstanceUUIDLookup is a feature gate for looking up the VMs of VSphereVMs by the
+	// instance UUID recorded in their status instead of their BIOS UUID in govmomi mode.
+	//
+	// alpha: v1.13
+	InstanceUUIDLookup featuregate.Feature = "InstanceUUIDLookup"
+
+	// HostEvacuation is a feature gate for draining the Nodes of VMs with local storage
+	// when their ESXi host is evacuated in govmomi mode.
+	//
+	// alpha: v1.13
+	HostEvacuation featuregate.Feature = "HostEvacuation"
 )
 
 func init() {
@@ -48,6 +82,12 @@ func init() {
 // To add a new feature, define a key for it above and add it here.
 var defaultCAPVFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
 	// Every feature should be initiated here:
-	NodeAntiAffinity:     {Default: false, PreRelease: featuregate.Alpha},
-	NamespaceScopedZones: {Default: false, PreRelease: featuregate.Alpha},
+	NodeAntiAffinity:           {Default: false, PreRelease: featuregate.Alpha},
+	NamespaceScopedZones:       {Default: false, PreRelease: featuregate.Alpha},
+	MachinePool:                {Default: false, PreRelease: featuregate.Alpha},
+	TemplateSnapshotManagement: {Default: false, PreRelease: featuregate.Alpha},
+	BatchedVCenterCalls:        {Default: false, PreRelease: featuregate.Alpha},
+	VSphereIPPool:              {Default: false, PreRelease: featuregate.Alpha},
+	InstanceUUIDLookup:         {Default: false, PreRelease: featuregate.Alpha},
+	HostEvacuation:             {Default: false, PreRelease: featuregate.Alpha},
 }
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"net/netip"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -43,12 +44,16 @@ var (
 	resourceOwner        string
 	resourceType         string
 	resourceName         string
+	resourceNames        []string
+	resourceCount        int
+	outputFormat         string
 	vSphereUsername      string
 	vSpherePassword      string
 	vSphereServer        string
 	vSphereTLSThumbprint string
 	vSphereFolder        string
 	vSphereResourcePool  string
+	vSphereTagPrefixes   []string
 )
 
 func main() {
@@ -83,6 +88,8 @@ func setupCommands(ctx context.Context) *cobra.Command {
 		RunE: runCmd(ctx),
 	}
 	acquireCmd.PersistentFlags().StringVar(&resourceType, "resource-type", "", "Type of the resource. Should be one of: vsphere-project-cluster-api-provider, vsphere-project-cloud-provider, vsphere-project-image-builder")
+	acquireCmd.PersistentFlags().IntVar(&resourceCount, "count", 1, "Number of resources to acquire. If more than one resource is acquired, the env vars are suffixed with the index of the resource, e.g. BOSKOS_RESOURCE_NAME_0.")
+	acquireCmd.PersistentFlags().StringVar(&outputFormat, "output", outputFormatEnv, fmt.Sprintf("Output format. Should be one of: %s, %s", outputFormatEnv, outputFormatJSON))
 	rootCmd.AddCommand(acquireCmd)
 
 	// heartbeat command
@@ -91,7 +98,7 @@ func setupCommands(ctx context.Context) *cobra.Command {
 		Args: cobra.NoArgs,
 		RunE: runCmd(ctx),
 	}
-	heartbeatCmd.PersistentFlags().StringVar(&resourceName, "resource-name", "", "Name of the resource.")
+	heartbeatCmd.PersistentFlags().StringSliceVar(&resourceNames, "resource-name", nil, "Name of the resource. Can be repeated or comma-separated to send heartbeats for multiple resources.")
 	rootCmd.AddCommand(heartbeatCmd)
 
 	// release command
@@ -107,6 +114,7 @@ func setupCommands(ctx context.Context) *cobra.Command {
 	releaseCmd.PersistentFlags().StringVar(&vSphereTLSThumbprint, "vsphere-tls-thumbprint", "", "vSphere TLS thumbprint of the resource, required for cleanup before release")
 	releaseCmd.PersistentFlags().StringVar(&vSphereFolder, "vsphere-folder", "", "vSphere folder of the resource, required for cleanup before release")
 	releaseCmd.PersistentFlags().StringVar(&vSphereResourcePool, "vsphere-resource-pool", "", "vSphere resource pool of the resource, required for cleanup before release")
+	releaseCmd.PersistentFlags().StringSliceVar(&vSphereTagPrefixes, "vsphere-tag-prefix", nil, "Name prefixes of vSphere tags, tag categories and custom attributes which get deleted before release if they are not in use anymore. Only objects with a name starting with the prefix followed by the resource name are deleted.")
 	rootCmd.AddCommand(releaseCmd)
 
 	return rootCmd
@@ -143,18 +151,24 @@ func runCmd(ctx context.Context) func(cmd *cobra.Command, _ []string) error {
 			if resourceType == "" {
 				return fmt.Errorf("--resource-type must be set")
 			}
+			if resourceCount < 1 {
+				return fmt.Errorf("--count must be at least 1")
+			}
+			if outputFormat != outputFormatEnv && outputFormat != outputFormatJSON {
+				return fmt.Errorf("--output must be one of: %s, %s", outputFormatEnv, outputFormatJSON)
+			}
 			log := log.WithValues("resourceType", resourceType)
 			ctx := ctrl.LoggerInto(ctx, log)
 
-			return acquire(ctx, client, resourceType)
+			return acquire(ctx, client, resourceType, resourceCount, outputFormat)
 		case "heartbeat":
-			if resourceName == "" {
+			if len(resourceNames) == 0 {
 				return fmt.Errorf("--resource-name must be set")
 			}
-			log := log.WithValues("resourceName", resourceName)
+			log := log.WithValues("resourceNames", resourceNames)
 			ctx := ctrl.LoggerInto(ctx, log)
 
-			return heartbeat(ctx, client, resourceName)
+			return heartbeat(ctx, client, resourceNames)
 		case "release":
 			if resourceName == "" {
 				return fmt.Errorf("--resource-name must be set")
@@ -187,54 +201,177 @@ func runCmd(ctx context.Context) func(cmd *cobra.Command, _ []string) error {
 			log := log.WithValues("resourceName", resourceName, "vSphereServer", vSphereServer, "vSphereFolder", vSphereFolder, "vSphereResourcePool", vSphereResourcePool)
 			ctx := ctrl.LoggerInto(ctx, log)
 
-			return release(ctx, client, resourceName, vSphereUsername, vSpherePassword, vSphereServer, vSphereTLSThumbprint, vSphereFolder, vSphereResourcePool)
+			return release(ctx, client, resourceName, vSphereUsername, vSpherePassword, vSphereServer, vSphereTLSThumbprint, vSphereFolder, vSphereResourcePool, vSphereTagPrefixes)
+		}
+
+		return nil
+	}
+}
+
+const (
+	outputFormatEnv  = "env"
+	outputFormatJSON = "json"
+)
+
+// acquiredResource is the machine-readable representation of an acquired resource.
+type acquiredResource struct {
+	Name         string          `json:"name"`
+	Folder       string          `json:"folder"`
+	ResourcePool string          `json:"resourcePool"`
+	IPPool       *acquiredIPPool `json:"ipPool,omitempty"`
+
+	// rawIPPool is the IP pool as stored in the user data of the resource.
+	rawIPPool string
+}
+
+// acquiredIPPool is the IP pool of an acquired resource, including all the
+// IP addresses calculated from the pool.
+type acquiredIPPool struct {
+	inClusterIPPoolSpec
+	IPs []string `json:"ips"`
+}
+
+func acquire(ctx context.Context, client *boskos.Client, resourceType string, count int, output string) error {
+	resources := make([]*acquiredResource, 0, count)
+	for i := 0; i < count; i++ {
+		res, err := acquireResource(ctx, client, resourceType)
+		if err != nil {
+			// Release the resources acquired up until now, so they don't become stale.
+			return releaseResources(ctx, client, resources, err)
 		}
+		resources = append(resources, res)
+	}
 
+	if output == outputFormatJSON {
+		out, err := json.MarshalIndent(resources, "", "  ")
+		if err != nil {
+			return releaseResources(ctx, client, resources, errors.Wrapf(err, "failed to marshal acquired resources"))
+		}
+		fmt.Println(string(out))
 		return nil
 	}
+
+	var sb strings.Builder
+	for i, res := range resources {
+		suffix := ""
+		if count > 1 {
+			suffix = fmt.Sprintf("_%d", i)
+		}
+		envVars, err := res.envVars()
+		if err != nil {
+			return releaseResources(ctx, client, resources, err)
+		}
+		keys := make([]string, 0, len(envVars))
+		for k := range envVars {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			sb.WriteString(fmt.Sprintf("export %s%s=%s\n", k, suffix, envVars[k]))
+		}
+	}
+
+	fmt.Println(sb.String())
+
+	return nil
+}
+
+// releaseResources releases the given resources as free and returns err aggregated with the release errors.
+func releaseResources(ctx context.Context, client *boskos.Client, resources []*acquiredResource, err error) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	errs := []error{err}
+	for _, r := range resources {
+		log.Info(fmt.Sprintf("Releasing resource %q as free", r.Name))
+		if releaseErr := client.Release(r.Name, boskos.Free); releaseErr != nil {
+			errs = append(errs, errors.Wrapf(releaseErr, "failed to release resource %q", r.Name))
+		}
+	}
+	return kerrors.NewAggregate(errs)
 }
 
-func acquire(ctx context.Context, client *boskos.Client, resourceType string) error {
+func acquireResource(ctx context.Context, client *boskos.Client, resourceType string) (*acquiredResource, error) {
 	log := ctrl.LoggerFrom(ctx)
 	log.Info("Acquiring resource")
 	res, err := client.Acquire(resourceType, boskos.Free, boskos.Busy)
 	if err != nil {
-		return errors.Wrapf(err, "failed to acquire resource of type %s", resourceType)
+		return nil, errors.Wrapf(err, "failed to acquire resource of type %s", resourceType)
 	}
 	log.Info(fmt.Sprintf("Acquired resource %q", res.Name))
 
+	acquired, err := newAcquiredResource(res)
+	if err != nil {
+		// Release the resource, so it doesn't stay busy.
+		return nil, releaseResources(ctx, client, []*acquiredResource{{Name: res.Name}}, err)
+	}
+	return acquired, nil
+}
+
+// newAcquiredResource validates the user data of the resource and returns the corresponding acquiredResource.
+func newAcquiredResource(res *boskos.Resource) (*acquiredResource, error) {
 	if res.UserData == nil {
-		return errors.Errorf("failed to get user data, resource %q is missing user data", res.Name)
+		return nil, errors.Errorf("failed to get user data, resource %q is missing user data", res.Name)
 	}
 
-	folder, hasFolder := res.UserData.Load("folder")
-	if !hasFolder {
-		return errors.Errorf("failed to get user data, resource %q is missing \"folder\" key", res.Name)
+	folder, err := getUserDataString(res, "folder")
+	if err != nil {
+		return nil, err
 	}
-	resourcePool, hasResourcePool := res.UserData.Load("resourcePool")
-	if !hasResourcePool {
-		return errors.Errorf("failed to get user data, resource %q is missing \"resourcePool\" key", res.Name)
+	resourcePool, err := getUserDataString(res, "resourcePool")
+	if err != nil {
+		return nil, err
 	}
-	ipPool, hasIPPool := res.UserData.Load("ipPool")
 
-	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("export BOSKOS_RESOURCE_NAME=%s\n", res.Name))
-	sb.WriteString(fmt.Sprintf("export BOSKOS_RESOURCE_FOLDER=%s\n", folder))
-	sb.WriteString(fmt.Sprintf("export BOSKOS_RESOURCE_POOL=%s\n", resourcePool))
+	acquired := &acquiredResource{
+		Name:         res.Name,
+		Folder:       folder,
+		ResourcePool: resourcePool,
+	}
 
-	if hasIPPool {
-		envVars, err := getIPPoolEnvVars(ipPool.(string))
+	if _, hasIPPool := res.UserData.Load("ipPool"); hasIPPool {
+		acquired.rawIPPool, err = getUserDataString(res, "ipPool")
 		if err != nil {
-			return errors.Wrapf(err, "failed to calculate IP pool env vars")
+			return nil, err
 		}
-		for k, v := range envVars {
-			sb.WriteString(fmt.Sprintf("export %s=%s\n", k, v))
+		acquired.IPPool, err = parseIPPool(acquired.rawIPPool)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse IP pool of resource %q", res.Name)
 		}
 	}
+	return acquired, nil
+}
 
-	fmt.Println(sb.String())
+// getUserDataString returns the string value of key in the user data of the resource.
+func getUserDataString(res *boskos.Resource, key string) (string, error) {
+	value, ok := res.UserData.Load(key)
+	if !ok {
+		return "", errors.Errorf("failed to get user data, resource %q is missing %q key", res.Name, key)
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", errors.Errorf("failed to get user data, %q key of resource %q is a %T, not a string", key, res.Name, value)
+	}
+	return s, nil
+}
 
-	return nil
+// envVars returns the env vars for the acquired resource.
+func (r *acquiredResource) envVars() (map[string]string, error) {
+	envVars := map[string]string{
+		"BOSKOS_RESOURCE_NAME":   r.Name,
+		"BOSKOS_RESOURCE_FOLDER": r.Folder,
+		"BOSKOS_RESOURCE_POOL":   r.ResourcePool,
+	}
+
+	if r.rawIPPool != "" {
+		ipPoolEnvVars, err := getIPPoolEnvVars(r.rawIPPool)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to calculate IP pool env vars")
+		}
+		for k, v := range ipPoolEnvVars {
+			envVars[k] = v
+		}
+	}
+	return envVars, nil
 }
 
 // inClusterIPPoolSpec defines the desired state of InClusterIPPool.
@@ -254,9 +391,8 @@ type inClusterIPPoolSpec struct {
 	Gateway string `json:"gateway,omitempty"`
 }
 
-// getIPPoolEnvVars calculates env vars based on the ipPool string.
-// Note: It's easier to calculate these env vars here in Go compared to if consumers of boskosctl have to do it in bash.
-func getIPPoolEnvVars(ipPool string) (map[string]string, error) {
+// parseIPPool parses the ipPool string and calculates all its IP addresses.
+func parseIPPool(ipPool string) (*acquiredIPPool, error) {
 	ipPoolSpec := inClusterIPPoolSpec{}
 	if err := json.Unmarshal([]byte(ipPool), &ipPoolSpec); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal IP pool configuration")
@@ -267,14 +403,29 @@ func getIPPoolEnvVars(ipPool string) (map[string]string, error) {
 		return nil, errors.Wrapf(err, "failed to calculate IP addresses")
 	}
 
+	ips := make([]string, 0, len(ipSet))
+	for _, ip := range ipSet {
+		ips = append(ips, ip.String())
+	}
+	return &acquiredIPPool{inClusterIPPoolSpec: ipPoolSpec, IPs: ips}, nil
+}
+
+// getIPPoolEnvVars calculates env vars based on the ipPool string.
+// Note: It's easier to calculate these env vars here in Go compared to if consumers of boskosctl have to do it in bash.
+func getIPPoolEnvVars(ipPool string) (map[string]string, error) {
+	pool, err := parseIPPool(ipPool)
+	if err != nil {
+		return nil, err
+	}
+
 	envVars := map[string]string{
 		// We need surrounding '' so the JSON string is preserved correctly.
 		"BOSKOS_RESOURCE_IP_POOL":         fmt.Sprintf("'%s'", ipPool),
-		"BOSKOS_RESOURCE_IP_POOL_PREFIX":  strconv.Itoa(ipPoolSpec.Prefix),
-		"BOSKOS_RESOURCE_IP_POOL_GATEWAY": ipPoolSpec.Gateway,
+		"BOSKOS_RESOURCE_IP_POOL_PREFIX":  strconv.Itoa(pool.Prefix),
+		"BOSKOS_RESOURCE_IP_POOL_GATEWAY": pool.Gateway,
 	}
-	for i, ip := range ipSet {
-		envVars[fmt.Sprintf("BOSKOS_RESOURCE_IP_POOL_IP_%d", i)] = ip.String()
+	for i, ip := range pool.IPs {
+		envVars[fmt.Sprintf("BOSKOS_RESOURCE_IP_POOL_IP_%d", i)] = ip
 	}
 	return envVars, nil
 }
@@ -324,22 +475,25 @@ func allIPs(addressesArray []string) ([]netip.Addr, error) {
 	return allIPs, nil
 }
 
-func heartbeat(ctx context.Context, client *boskos.Client, resourceName string) error {
+func heartbeat(ctx context.Context, client *boskos.Client, resourceNames []string) error {
 	log := ctrl.LoggerFrom(ctx)
 	for {
-		log.Info("Sending heartbeat")
+		for _, resourceName := range resourceNames {
+			log := log.WithValues("resourceName", resourceName)
+			log.Info("Sending heartbeat")
 
-		if err := client.Update(resourceName, boskos.Busy, nil); err != nil {
-			log.Error(err, "Sending heartbeat failed")
-		} else {
-			log.Error(err, "Sending heartbeat succeeded")
+			if err := client.Update(resourceName, boskos.Busy, nil); err != nil {
+				log.Error(err, "Sending heartbeat failed")
+			} else {
+				log.Info("Sending heartbeat succeeded")
+			}
 		}
 
 		time.Sleep(1 * time.Minute)
 	}
 }
 
-func release(ctx context.Context, client *boskos.Client, resourceName, vSphereUsername, vSpherePassword, vSphereServer, vSphereTLSThumbprint, vSphereFolder, vSphereResourcePool string) error {
+func release(ctx context.Context, client *boskos.Client, resourceName, vSphereUsername, vSpherePassword, vSphereServer, vSphereTLSThumbprint, vSphereFolder, vSphereResourcePool string, vSphereTagPrefixes []string) error {
 	log := ctrl.LoggerFrom(ctx)
 	ctx = ctrl.LoggerInto(ctx, log)
 
@@ -364,7 +518,7 @@ func release(ctx context.Context, client *boskos.Client, resourceName, vSphereUs
 	log.Info("Cleaning up vSphere")
 	// Note: We intentionally want to skip clusterModule cleanup. If we run this too often we might hit race conditions
 	// when other tests are creating cluster modules in parallel.
-	if err := j.CleanupVSphere(ctx, []string{vSphereFolder}, []string{vSphereResourcePool}, []string{vSphereFolder}, true); err != nil {
+	if err := j.CleanupVSphere(ctx, []string{vSphereFolder}, []string{vSphereResourcePool}, []string{vSphereFolder}, nil, janitor.StaleObjectsFilter{}, true); err != nil {
 		log.Info("Cleaning up vSphere failed")
 
 		// Try to release resource as dirty.
@@ -378,6 +532,13 @@ func release(ctx context.Context, client *boskos.Client, resourceName, vSphereUs
 	}
 	log.Info("Cleaning up vSphere succeeded")
 
+	// Note: We intentionally only clean up tags, tag categories and custom attributes named after this resource.
+	// Deleting all unused ones would race with other tests which did not attach them yet.
+	// A failed cleanup does not make the resource dirty, the janitor will retry the cleanup later.
+	if err := j.CleanupTagsAndCustomAttributes(ctx, janitor.ResourceNamePrefixes(vSphereTagPrefixes, resourceName)); err != nil {
+		log.Error(err, "Cleaning up vSphere tags and custom attributes failed")
+	}
+
 	// Try to release resource as free.
 	log.Info("Releasing resource as free")
 	if releaseErr := client.Release(resourceName, boskos.Free); releaseErr != nil {
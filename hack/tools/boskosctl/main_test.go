@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-vsphere/hack/tools/pkg/boskos"
+)
+
+func Test_acquiredResource(t *testing.T) {
+	g := NewWithT(t)
+
+	ipPool := `{"addresses":["192.168.0.1-192.168.0.2","192.168.0.10"],"prefix":24,"gateway":"192.168.0.254"}`
+	pool, err := parseIPPool(ipPool)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	res := &acquiredResource{
+		Name:         "resource-1",
+		Folder:       "folder-1",
+		ResourcePool: "rp-1",
+		IPPool:       pool,
+		rawIPPool:    ipPool,
+	}
+
+	envVars, err := res.envVars()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(envVars).To(Equal(map[string]string{
+		"BOSKOS_RESOURCE_NAME":            "resource-1",
+		"BOSKOS_RESOURCE_FOLDER":          "folder-1",
+		"BOSKOS_RESOURCE_POOL":            "rp-1",
+		"BOSKOS_RESOURCE_IP_POOL":         "'" + ipPool + "'",
+		"BOSKOS_RESOURCE_IP_POOL_PREFIX":  "24",
+		"BOSKOS_RESOURCE_IP_POOL_GATEWAY": "192.168.0.254",
+		"BOSKOS_RESOURCE_IP_POOL_IP_0":    "192.168.0.1",
+		"BOSKOS_RESOURCE_IP_POOL_IP_1":    "192.168.0.2",
+		"BOSKOS_RESOURCE_IP_POOL_IP_2":    "192.168.0.10",
+	}))
+
+	out, err := json.Marshal(res)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(out)).To(Equal(`{"name":"resource-1","folder":"folder-1","resourcePool":"rp-1",` +
+		`"ipPool":{"addresses":["192.168.0.1-192.168.0.2","192.168.0.10"],"prefix":24,"gateway":"192.168.0.254",` +
+		`"ips":["192.168.0.1","192.168.0.2","192.168.0.10"]}}`))
+}
+
+func Test_newAcquiredResource(t *testing.T) {
+	newResource := func(userData map[string]interface{}) *boskos.Resource {
+		res := &boskos.Resource{Name: "resource-1", UserData: &boskos.UserData{}}
+		for k, v := range userData {
+			res.UserData.Store(k, v)
+		}
+		return res
+	}
+
+	tests := []struct {
+		name    string
+		res     *boskos.Resource
+		want    *acquiredResource
+		wantErr string
+	}{
+		{
+			name: "valid user data",
+			res:  newResource(map[string]interface{}{"folder": "folder-1", "resourcePool": "rp-1"}),
+			want: &acquiredResource{Name: "resource-1", Folder: "folder-1", ResourcePool: "rp-1"},
+		},
+		{
+			name:    "missing user data",
+			res:     &boskos.Resource{Name: "resource-1"},
+			wantErr: "resource \"resource-1\" is missing user data",
+		},
+		{
+			name:    "missing resource pool",
+			res:     newResource(map[string]interface{}{"folder": "folder-1"}),
+			wantErr: "resource \"resource-1\" is missing \"resourcePool\" key",
+		},
+		{
+			name:    "folder is not a string",
+			res:     newResource(map[string]interface{}{"folder": 1, "resourcePool": "rp-1"}),
+			wantErr: "\"folder\" key of resource \"resource-1\" is a int, not a string",
+		},
+		{
+			name:    "IP pool is not a string",
+			res:     newResource(map[string]interface{}{"folder": "folder-1", "resourcePool": "rp-1", "ipPool": true}),
+			wantErr: "\"ipPool\" key of resource \"resource-1\" is a bool, not a string",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := newAcquiredResource(tt.res)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,431 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package main is the main package for capvctl.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	vmwarev1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/vmware/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/hack/tools/pkg/adopt"
+	"sigs.k8s.io/cluster-api-provider-vsphere/hack/tools/pkg/bundle"
+	"sigs.k8s.io/cluster-api-provider-vsphere/hack/tools/pkg/migrate"
+	"sigs.k8s.io/cluster-api-provider-vsphere/hack/tools/pkg/move"
+)
+
+var (
+	kubeconfig          string
+	namespace           string
+	controllerNamespace string
+	machineNamespace    string
+	bundleOutput        string
+	clusterNamespace    string
+	migrateOptions      migrate.Options
+	adoptOptions        adopt.Options
+	adoptEndpoint       string
+	adoptOutput         string
+)
+
+func main() {
+	log := klog.Background()
+	ctx := ctrl.LoggerInto(context.Background(), log)
+	// Just setting this to avoid that CR is complaining about a missing logger.
+	ctrl.SetLogger(log)
+
+	rootCmd := setupCommands(ctx)
+
+	if err := rootCmd.Execute(); err != nil {
+		log.Error(err, "Failed running capvctl")
+		os.Exit(1)
+	}
+}
+
+func setupCommands(ctx context.Context) *cobra.Command {
+	// Root command
+	rootCmd := &cobra.Command{
+		Use:          "capvctl",
+		SilenceUsage: true,
+		Short:        "capvctl contains utilities for operating clusters managed by CAPV",
+	}
+	rootCmd.PersistentFlags().StringVar(&kubeconfig, "kubeconfig", "", "Path to the kubeconfig of the management cluster. If not set, the default loading rules of kubectl are used.")
+
+	// validate-move command
+	validateMoveCmd := &cobra.Command{
+		Use:   "validate-move",
+		Short: "Reports objects referenced by CAPV clusters which would be left behind by clusterctl move",
+		Args:  cobra.NoArgs,
+		RunE:  runValidateMove(ctx),
+	}
+	validateMoveCmd.PersistentFlags().StringVarP(&namespace, "namespace", "n", "", "Namespace of the clusters to validate. If not set, the clusters of all namespaces are validated.")
+	validateMoveCmd.PersistentFlags().StringVar(&controllerNamespace, "capv-namespace", "capv-system", "Namespace of the CAPV controller manager.")
+	rootCmd.AddCommand(validateMoveCmd)
+
+	// support-bundle command
+	supportBundleCmd := &cobra.Command{
+		Use:   "support-bundle MACHINE",
+		Short: "Collects the reconcile state of a machine into a support bundle for issue reports",
+		Long: "Collects the Machine, its infrastructure machine, the matching VSphereVM or VM Operator VirtualMachine and their conditions " +
+			"into a gzipped tarball. For VSphereVMs the vCenter-side VM properties, the vCenter session and the last tasks of the VM are " +
+			"collected as well, using the credentials set in the GOVC_USERNAME and GOVC_PASSWORD environment variables.",
+		Args: cobra.ExactArgs(1),
+		RunE: runSupportBundle(ctx),
+	}
+	supportBundleCmd.PersistentFlags().StringVarP(&machineNamespace, "namespace", "n", "default", "Namespace of the machine.")
+	supportBundleCmd.PersistentFlags().StringVarP(&bundleOutput, "output", "o", "", "Path of the support bundle. Defaults to <machine>-support-bundle.tar.gz.")
+	rootCmd.AddCommand(supportBundleCmd)
+
+	// migrate-to-supervisor command
+	migrateCmd := &cobra.Command{
+		Use:   "migrate-to-supervisor",
+		Short: "Migrates a govmomi-mode cluster to supervisor mode in stages which can be rolled back",
+		Long: "Migrates a govmomi-mode cluster to supervisor mode. The stages have to be run in order: prepare creates the " +
+			"supervisor-mode VSphereCluster and VSphereMachineTemplates, switch-machines rolls out the machines as VM Operator " +
+			"VirtualMachines, finalize points the Cluster to the supervisor-mode VSphereCluster and cleanup deletes the govmomi-mode " +
+			"objects. rollback reverts the last completed stage until cleanup has been run.",
+	}
+	migrateCmd.PersistentFlags().StringVarP(&clusterNamespace, "namespace", "n", "default", "Namespace of the cluster.")
+	migratePlanCmd := &cobra.Command{
+		Use:   "plan CLUSTER",
+		Short: "Reports the stage, the templates and the blockers of the migration without changing any object",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runMigratePlan(ctx),
+	}
+	migratePrepareCmd := &cobra.Command{
+		Use:   "prepare CLUSTER",
+		Short: "Creates the supervisor-mode VSphereCluster and VSphereMachineTemplates",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runMigrateStage(ctx, (*migrate.Migrator).Prepare, "prepared"),
+	}
+	migratePrepareCmd.Flags().StringVar(&migrateOptions.ClassName, "class-name", "", "VirtualMachineClass of the migrated machines.")
+	migratePrepareCmd.Flags().StringVar(&migrateOptions.ImageName, "image-name", "", "VirtualMachineImage of the migrated machines.")
+	migratePrepareCmd.Flags().StringVar(&migrateOptions.StorageClass, "storage-class", "", "StorageClass of the migrated machines.")
+	migrateCmd.AddCommand(
+		migratePlanCmd,
+		migratePrepareCmd,
+		&cobra.Command{
+			Use:   "switch-machines CLUSTER",
+			Short: "Points the control plane and the MachineDeployments to the supervisor-mode VSphereMachineTemplates",
+			Args:  cobra.ExactArgs(1),
+			RunE:  runMigrateStage(ctx, (*migrate.Migrator).SwitchMachines, "switched to the supervisor-mode VSphereMachineTemplates"),
+		},
+		&cobra.Command{
+			Use:   "finalize CLUSTER",
+			Short: "Points the Cluster to the supervisor-mode VSphereCluster once all govmomi-mode machines are gone",
+			Args:  cobra.ExactArgs(1),
+			RunE:  runMigrateStage(ctx, (*migrate.Migrator).Finalize, "finalized"),
+		},
+		&cobra.Command{
+			Use:   "cleanup CLUSTER",
+			Short: "Deletes the govmomi-mode VSphereCluster and VSphereMachineTemplates, this cannot be rolled back",
+			Args:  cobra.ExactArgs(1),
+			RunE:  runMigrateStage(ctx, (*migrate.Migrator).Cleanup, "cleaned up"),
+		},
+		&cobra.Command{
+			Use:   "rollback CLUSTER",
+			Short: "Reverts the last completed stage of the migration",
+			Args:  cobra.ExactArgs(1),
+			RunE:  runMigrateRollback(ctx),
+		},
+	)
+	rootCmd.AddCommand(migrateCmd)
+
+	// adopt command
+	adoptCmd := &cobra.Command{
+		Use:   "adopt CLUSTER",
+		Short: "Generates the objects adopting existing vSphere VMs as a cluster managed by CAPV",
+		Long: "Generates a Cluster, a VSphereCluster and a Machine and a VSphereMachine for every given VM, which adopt the VMs " +
+			"by their instance UUID instead of cloning new ones. The VMs are looked up in vCenter using the credentials set in the " +
+			"GOVC_USERNAME and GOVC_PASSWORD environment variables. The objects are written as YAML to be reviewed and applied, " +
+			"no object is created in the management cluster.",
+		Args: cobra.ExactArgs(1),
+		RunE: runAdopt(ctx),
+	}
+	adoptCmd.Flags().StringVarP(&adoptOptions.Namespace, "namespace", "n", "default", "Namespace of the cluster.")
+	adoptCmd.Flags().StringVar(&adoptOptions.KubernetesVersion, "kubernetes-version", "", "Kubernetes version running on the VMs.")
+	adoptCmd.Flags().StringVar(&adoptEndpoint, "control-plane-endpoint", "", "Endpoint of the API server of the cluster as host:port.")
+	adoptCmd.Flags().StringVar(&adoptOptions.Server, "server", "", "Address of the vCenter of the VMs.")
+	adoptCmd.Flags().StringVar(&adoptOptions.Thumbprint, "thumbprint", "", "TLS thumbprint of the vCenter. If not set, the certificate of the vCenter is not verified.")
+	adoptCmd.Flags().StringVar(&adoptOptions.Datacenter, "datacenter", "", "Datacenter of the VMs. If not set, the default datacenter is used.")
+	adoptCmd.Flags().StringVar(&adoptOptions.IdentitySecretName, "identity-secret", "", "Secret with the vCenter credentials of the cluster. If not set, the credentials of CAPV are used.")
+	adoptCmd.Flags().StringSliceVar(&adoptOptions.ControlPlaneVMs, "control-plane-vm", nil, "Name or inventory path of a control plane VM, can be repeated.")
+	adoptCmd.Flags().StringSliceVar(&adoptOptions.WorkerVMs, "worker-vm", nil, "Name or inventory path of a worker VM, can be repeated.")
+	adoptCmd.Flags().BoolVar(&adoptOptions.DeleteProtection, "delete-protection", true, "Keep the VMs in vCenter when their machines are deleted.")
+	adoptCmd.Flags().StringVarP(&adoptOutput, "output", "o", "", "Path of the generated YAML. If not set, the YAML is written to stdout.")
+	rootCmd.AddCommand(adoptCmd)
+
+	return rootCmd
+}
+
+func runValidateMove(ctx context.Context) func(cmd *cobra.Command, _ []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
+
+		findings, err := move.NewValidator(c, controllerNamespace).Validate(ctx, namespace)
+		if err != nil {
+			return errors.Wrap(err, "failed to validate move")
+		}
+		for _, finding := range findings {
+			fmt.Fprintln(cmd.OutOrStdout(), finding.String())
+		}
+		if len(findings) > 0 {
+			return errors.Errorf("%d objects would be left behind by clusterctl move", len(findings))
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), "No objects would be left behind by clusterctl move")
+		return nil
+	}
+}
+
+func runSupportBundle(ctx context.Context) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
+
+		var vCenterClient bundle.VCenterClientFunc
+		if os.Getenv("GOVC_USERNAME") != "" {
+			vCenterClient = newVCenterClient
+		} else {
+			fmt.Fprintln(cmd.ErrOrStderr(), "GOVC_USERNAME is not set, the vCenter-side state of the machine is not collected")
+		}
+
+		b, err := bundle.NewCollector(c, vCenterClient).Collect(ctx, machineNamespace, args[0])
+		if err != nil {
+			return errors.Wrap(err, "failed to collect support bundle")
+		}
+		for _, err := range b.Errors {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err)
+		}
+
+		output := bundleOutput
+		if output == "" {
+			output = fmt.Sprintf("%s-support-bundle.tar.gz", args[0])
+		}
+		f, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create %s", output)
+		}
+		defer f.Close()
+		if err := b.Write(f); err != nil {
+			return errors.Wrapf(err, "failed to write %s", output)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Support bundle written to %s\n", output)
+		return nil
+	}
+}
+
+func runMigratePlan(ctx context.Context) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
+
+		plan, err := migrate.NewMigrator(c, migrateOptions).Plan(ctx, client.ObjectKey{Namespace: clusterNamespace, Name: args[0]})
+		if err != nil {
+			return errors.Wrap(err, "failed to plan migration")
+		}
+		phase := string(plan.Phase)
+		if phase == "" {
+			phase = "not started"
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Migration phase: %s\n", phase)
+		for _, t := range plan.Templates {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s %s: VSphereMachineTemplate %s is replaced by %s\n", t.Owner.Kind, t.Owner.Name, t.From.Name, t.To.Name)
+		}
+		for _, warning := range plan.Warnings {
+			fmt.Fprintf(cmd.OutOrStdout(), "Warning: %s\n", warning)
+		}
+		for _, blocker := range plan.Blockers {
+			fmt.Fprintf(cmd.OutOrStdout(), "Blocker: %s\n", blocker)
+		}
+		if len(plan.Blockers) > 0 {
+			return errors.Errorf("%d blockers prevent the migration", len(plan.Blockers))
+		}
+		return nil
+	}
+}
+
+func runMigrateStage(ctx context.Context, stage func(*migrate.Migrator, context.Context, client.ObjectKey) error, done string) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
+
+		if err := stage(migrate.NewMigrator(c, migrateOptions), ctx, client.ObjectKey{Namespace: clusterNamespace, Name: args[0]}); err != nil {
+			return errors.Wrap(err, "failed to migrate cluster")
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Cluster %s/%s %s\n", clusterNamespace, args[0], done)
+		return nil
+	}
+}
+
+func runMigrateRollback(ctx context.Context) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
+
+		phase, err := migrate.NewMigrator(c, migrateOptions).Rollback(ctx, client.ObjectKey{Namespace: clusterNamespace, Name: args[0]})
+		if err != nil {
+			return errors.Wrap(err, "failed to roll back migration")
+		}
+		if phase == migrate.PhaseNone {
+			fmt.Fprintf(cmd.OutOrStdout(), "Cluster %s/%s rolled back, the migration has been removed\n", clusterNamespace, args[0])
+			return nil
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Cluster %s/%s rolled back to migration phase %s\n", clusterNamespace, args[0], phase)
+		return nil
+	}
+}
+
+func runAdopt(ctx context.Context) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		adoptOptions.ClusterName = args[0]
+		if adoptEndpoint != "" {
+			host, port, err := net.SplitHostPort(adoptEndpoint)
+			if err != nil {
+				return errors.Wrapf(err, "invalid control plane endpoint %s", adoptEndpoint)
+			}
+			p, err := strconv.ParseInt(port, 10, 32)
+			if err != nil {
+				return errors.Wrapf(err, "invalid control plane endpoint port %s", port)
+			}
+			adoptOptions.ControlPlaneEndpoint = infrav1.APIEndpoint{Host: host, Port: int32(p)}
+		}
+		if adoptOptions.Server == "" {
+			return errors.New("--server must be set")
+		}
+
+		c, err := newVCenterClient(ctx, adoptOptions.Server, adoptOptions.Thumbprint)
+		if err != nil {
+			return errors.Wrap(err, "failed to connect to vCenter")
+		}
+		defer func() {
+			_ = c.Logout(ctx)
+		}()
+
+		objs, err := adopt.NewGenerator(c.Client, adoptOptions).Generate(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to generate adoption objects")
+		}
+
+		var w io.Writer = cmd.OutOrStdout()
+		if adoptOutput != "" {
+			f, err := os.OpenFile(adoptOutput, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+			if err != nil {
+				return errors.Wrapf(err, "failed to create %s", adoptOutput)
+			}
+			defer f.Close()
+			w = f
+		}
+		if err := adopt.Write(w, objs); err != nil {
+			return errors.Wrap(err, "failed to write adoption objects")
+		}
+		if adoptOutput != "" {
+			fmt.Fprintf(cmd.OutOrStdout(), "Objects adopting %d VMs written to %s\n", len(adoptOptions.ControlPlaneVMs)+len(adoptOptions.WorkerVMs), adoptOutput)
+		}
+		return nil
+	}
+}
+
+func newVCenterClient(ctx context.Context, server, thumbprint string) (*govmomi.Client, error) {
+	serverURL, err := soap.ParseURL(server)
+	if err != nil {
+		return nil, err
+	}
+	credentials := url.UserPassword(os.Getenv("GOVC_USERNAME"), os.Getenv("GOVC_PASSWORD"))
+	serverURL.User = credentials
+
+	soapClient := soap.NewClient(serverURL, thumbprint == "")
+	if thumbprint != "" {
+		soapClient.SetThumbprint(serverURL.Host, thumbprint)
+	}
+	soapClient.UserAgent = "capvctl"
+
+	vimClient, err := vim25.NewClient(ctx, soapClient)
+	if err != nil {
+		return nil, err
+	}
+	c := &govmomi.Client{
+		Client:         vimClient,
+		SessionManager: session.NewManager(vimClient),
+	}
+	if err := c.Login(ctx, credentials); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func newClient() (client.Client, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load kubeconfig")
+	}
+
+	scheme := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{
+		corev1.AddToScheme,
+		apiextensionsv1.AddToScheme,
+		clusterv1.AddToScheme,
+		controlplanev1.AddToScheme,
+		ipamv1.AddToScheme,
+		infrav1.AddToScheme,
+		vmwarev1.AddToScheme,
+	} {
+		if err := addToScheme(scheme); err != nil {
+			return nil, err
+		}
+	}
+
+	c, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create client")
+	}
+	return c, nil
+}
@@ -22,6 +22,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/pflag"
@@ -43,10 +44,14 @@ func init() {
 }
 
 var (
-	dryRun        bool
-	boskosHost    string
-	resourceOwner string
-	resourceTypes []string
+	dryRun              bool
+	boskosHost          string
+	resourceOwner       string
+	resourceTypes       []string
+	contentLibraries    []string
+	staleObjectPrefixes []string
+	staleObjectMaxAge   time.Duration
+	tagPrefixes         []string
 )
 
 func initFlags(fs *pflag.FlagSet) {
@@ -55,6 +60,10 @@ func initFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&resourceOwner, "resource-owner", "vsphere-janitor", "Owner for the resource during cleanup.")
 	fs.StringArrayVar(&resourceTypes, "resource-type", []string{"vsphere-project-cluster-api-provider", "vsphere-project-cloud-provider", "vsphere-project-image-builder"}, "Types of the resources")
 	fs.BoolVar(&dryRun, "dry-run", false, "dry-run results in not deleting anything but printing the actions.")
+	fs.StringArrayVar(&contentLibraries, "content-library", []string{}, "Content libraries to cleanup stale items from.")
+	fs.StringArrayVar(&staleObjectPrefixes, "stale-object-prefix", []string{}, "Name prefixes of VM templates and content library items which should be deleted once they are older than --stale-object-max-age.")
+	fs.StringArrayVar(&tagPrefixes, "tag-prefix", []string{}, "Name prefixes of tags, tag categories and custom attributes which should be deleted once they are not in use anymore. Only objects with a name starting with the prefix followed by the name of the cleaned up resource are deleted.")
+	fs.DurationVar(&staleObjectMaxAge, "stale-object-max-age", 24*time.Hour, "Minimum age of VM templates and content library items before they get deleted.")
 }
 
 func main() {
@@ -77,7 +86,7 @@ func main() {
 
 func run(ctx context.Context) error {
 	log := ctrl.LoggerFrom(ctx)
-	log.Info("Configured settings", "dry-run", dryRun)
+	log.Info("Configured settings", "dry-run", dryRun, "content-library", contentLibraries, "stale-object-prefix", staleObjectPrefixes, "stale-object-max-age", staleObjectMaxAge, "tag-prefix", tagPrefixes)
 
 	if boskosHost == "" {
 		return fmt.Errorf("--boskos-host must be set")
@@ -110,6 +119,11 @@ func run(ctx context.Context) error {
 		return err
 	}
 
+	staleObjects := janitor.StaleObjectsFilter{
+		NamePrefixes: staleObjectPrefixes,
+		MaxAge:       staleObjectMaxAge,
+	}
+
 	var allErrs []error
 	for _, resourceType := range resourceTypes {
 		log := log.WithValues("resourceType", resourceType)
@@ -162,7 +176,7 @@ func run(ctx context.Context) error {
 			j := janitor.NewJanitor(vSphereClients, false)
 
 			log.Info("Cleaning up vSphere")
-			if err := j.CleanupVSphere(ctx, []string{folder.(string)}, []string{resourcePool.(string)}, []string{folder.(string)}, false); err != nil {
+			if err := j.CleanupVSphere(ctx, []string{folder.(string)}, []string{resourcePool.(string)}, []string{folder.(string)}, contentLibraries, staleObjects, false); err != nil {
 				log.Info("Cleaning up vSphere failed")
 
 				// Intentionally keep this resource in cleaning state. The reaper will move it from cleaning to dirty
@@ -173,6 +187,13 @@ func run(ctx context.Context) error {
 			}
 			log.Info("Cleaning up vSphere succeeded")
 
+			// Tags, tag categories and custom attributes are only cleaned up for the resource in cleaning state.
+			// Deleting all unused ones would race with tests which did not attach them yet.
+			log.Info("Cleaning up vSphere tags and custom attributes")
+			if err := janitor.NewJanitor(vSphereClients, dryRun).CleanupTagsAndCustomAttributes(ctx, janitor.ResourceNamePrefixes(tagPrefixes, res.Name)); err != nil {
+				allErrs = append(allErrs, errors.Wrapf(err, "cleaning up vSphere tags and custom attributes for resource %q", res.Name))
+			}
+
 			// Try to release resource as free.
 			log.Info("Releasing resource as free")
 			if releaseErr := client.Release(res.Name, boskos.Free); releaseErr != nil {
@@ -188,6 +209,7 @@ func run(ctx context.Context) error {
 			log.Info("State after cleanup", "resourceOwners", metrics.Owners, "resourceStates", metrics.Current)
 		}
 	}
+
 	if len(allErrs) > 0 {
 		return errors.Wrap(kerrors.NewAggregate(allErrs), "cleaning up Boskos resources")
 	}
@@ -0,0 +1,395 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package adopt generates the objects adopting existing vSphere VMs as a cluster managed by CAPV.
+//
+// Every VM is adopted by a Machine and a VSphereMachine with the AdoptVMInstanceUUIDAnnotation,
+// so the VMs are neither cloned nor re-bootstrapped. The control plane VMs become Machines
+// with the control plane label, which are not owned by a control plane provider.
+package adopt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+// Options are the options of the generated cluster.
+type Options struct {
+	// ClusterName is the name of the Cluster and the VSphereCluster.
+	ClusterName string
+	// Namespace is the namespace of the generated objects.
+	Namespace string
+	// KubernetesVersion is the Kubernetes version running on the VMs.
+	KubernetesVersion string
+	// ControlPlaneEndpoint is the endpoint of the API server of the cluster.
+	ControlPlaneEndpoint infrav1.APIEndpoint
+	// Server is the address of the vCenter of the VMs.
+	Server string
+	// Thumbprint is the TLS thumbprint of the vCenter.
+	Thumbprint string
+	// Datacenter is the datacenter of the VMs. If empty, the default datacenter is used.
+	Datacenter string
+	// IdentitySecretName is the name of the Secret with the vCenter credentials of the cluster.
+	// If empty, the credentials of the controller manager are used.
+	IdentitySecretName string
+	// ControlPlaneVMs are the names or inventory paths of the control plane VMs.
+	ControlPlaneVMs []string
+	// WorkerVMs are the names or inventory paths of the worker VMs.
+	WorkerVMs []string
+	// DeleteProtection keeps the VMs in vCenter when their Machines are deleted.
+	DeleteProtection bool
+}
+
+func (o Options) validate() error {
+	switch {
+	case o.ClusterName == "":
+		return errors.New("cluster name must be set")
+	case o.KubernetesVersion == "":
+		return errors.New("Kubernetes version must be set")
+	case o.ControlPlaneEndpoint.Host == "" || o.ControlPlaneEndpoint.Port == 0:
+		return errors.New("control plane endpoint must be set")
+	case o.Server == "":
+		return errors.New("vCenter server must be set")
+	case len(o.ControlPlaneVMs) == 0:
+		return errors.New("at least one control plane VM must be set")
+	}
+	if errs := validation.IsDNS1123Subdomain(o.ClusterName); len(errs) > 0 {
+		return errors.Errorf("invalid cluster name %q: %s", o.ClusterName, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// Generator generates the objects adopting existing VMs as a cluster.
+type Generator struct {
+	client  *vim25.Client
+	options Options
+}
+
+// NewGenerator returns a Generator looking up the VMs with the given vCenter client.
+func NewGenerator(c *vim25.Client, options Options) *Generator {
+	return &Generator{
+		client:  c,
+		options: options,
+	}
+}
+
+// Generate returns the Cluster, the VSphereCluster, the bootstrap data Secret and a Machine
+// and a VSphereMachine for every VM. The objects are not created.
+func (g *Generator) Generate(ctx context.Context) ([]client.Object, error) {
+	if err := g.options.validate(); err != nil {
+		return nil, err
+	}
+
+	finder := find.NewFinder(g.client, true)
+	datacenter, err := finder.DatacenterOrDefault(ctx, g.options.Datacenter)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find datacenter")
+	}
+	finder.SetDatacenter(datacenter)
+
+	objs := []client.Object{
+		g.cluster(),
+		g.vsphereCluster(),
+		g.bootstrapDataSecret(),
+	}
+
+	names := map[string]string{}
+	for _, vms := range []struct {
+		paths        []string
+		controlPlane bool
+	}{
+		{paths: g.options.ControlPlaneVMs, controlPlane: true},
+		{paths: g.options.WorkerVMs},
+	} {
+		for _, vmPath := range vms.paths {
+			vm, err := finder.VirtualMachine(ctx, vmPath)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to find VM %s", vmPath)
+			}
+			vsphereMachine, err := g.vsphereMachine(ctx, datacenter, vm)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to adopt VM %s", vm.InventoryPath)
+			}
+			if other, ok := names[vsphereMachine.Name]; ok {
+				return nil, errors.Errorf("VMs %s and %s map to the same machine name %s", other, vm.InventoryPath, vsphereMachine.Name)
+			}
+			names[vsphereMachine.Name] = vm.InventoryPath
+			objs = append(objs, g.machine(vsphereMachine.Name, vms.controlPlane), vsphereMachine)
+		}
+	}
+	return objs, nil
+}
+
+func (g *Generator) cluster() *clusterv1.Cluster {
+	return &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      g.options.ClusterName,
+			Namespace: g.options.Namespace,
+		},
+		Spec: clusterv1.ClusterSpec{
+			ControlPlaneEndpoint: clusterv1.APIEndpoint{
+				Host: g.options.ControlPlaneEndpoint.Host,
+				Port: g.options.ControlPlaneEndpoint.Port,
+			},
+			InfrastructureRef: &corev1.ObjectReference{
+				APIVersion: infrav1.GroupVersion.String(),
+				Kind:       "VSphereCluster",
+				Name:       g.options.ClusterName,
+			},
+		},
+	}
+}
+
+func (g *Generator) vsphereCluster() *infrav1.VSphereCluster {
+	vsphereCluster := &infrav1.VSphereCluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: infrav1.GroupVersion.String(),
+			Kind:       "VSphereCluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      g.options.ClusterName,
+			Namespace: g.options.Namespace,
+		},
+		Spec: infrav1.VSphereClusterSpec{
+			Server:               g.options.Server,
+			Thumbprint:           g.options.Thumbprint,
+			ControlPlaneEndpoint: g.options.ControlPlaneEndpoint,
+		},
+	}
+	if g.options.IdentitySecretName != "" {
+		vsphereCluster.Spec.IdentityRef = &infrav1.VSphereIdentityReference{
+			Kind: infrav1.SecretKind,
+			Name: g.options.IdentitySecretName,
+		}
+	}
+	return vsphereCluster
+}
+
+// bootstrapDataSecret returns the Secret referenced as bootstrap data by all Machines.
+// The bootstrap data is empty, as the bootstrap data of adopted VMs is not changed.
+func (g *Generator) bootstrapDataSecret() *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      g.bootstrapDataSecretName(),
+			Namespace: g.options.Namespace,
+			Labels: map[string]string{
+				clusterv1.ClusterNameLabel: g.options.ClusterName,
+			},
+		},
+		Type: clusterv1.ClusterSecretType,
+		StringData: map[string]string{
+			"value":  "",
+			"format": string(bootstrapv1.CloudConfig),
+		},
+	}
+}
+
+func (g *Generator) bootstrapDataSecretName() string {
+	return g.options.ClusterName + "-adopted-bootstrap"
+}
+
+func (g *Generator) machine(name string, controlPlane bool) *clusterv1.Machine {
+	machine := &clusterv1.Machine{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Machine",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: g.options.Namespace,
+			Labels: map[string]string{
+				clusterv1.ClusterNameLabel: g.options.ClusterName,
+			},
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: g.options.ClusterName,
+			Bootstrap: clusterv1.Bootstrap{
+				DataSecretName: ptr.To(g.bootstrapDataSecretName()),
+			},
+			InfrastructureRef: corev1.ObjectReference{
+				APIVersion: infrav1.GroupVersion.String(),
+				Kind:       "VSphereMachine",
+				Name:       name,
+			},
+			Version: ptr.To(g.options.KubernetesVersion),
+		},
+	}
+	if controlPlane {
+		machine.Labels[clusterv1.MachineControlPlaneLabel] = ""
+	}
+	return machine
+}
+
+// vsphereMachine returns a VSphereMachine adopting the VM by its instance UUID. The spec
+// reflects the current configuration of the VM, so the VM is not reconfigured on adoption.
+func (g *Generator) vsphereMachine(ctx context.Context, datacenter *object.Datacenter, vm *object.VirtualMachine) (*infrav1.VSphereMachine, error) {
+	var moVM mo.VirtualMachine
+	if err := vm.Properties(ctx, vm.Reference(), []string{"name", "config"}, &moVM); err != nil {
+		return nil, errors.Wrap(err, "failed to get VM properties")
+	}
+	if moVM.Config == nil {
+		return nil, errors.New("VM has no configuration")
+	}
+	if moVM.Config.Template {
+		return nil, errors.New("VM is a template")
+	}
+
+	name := machineName(moVM.Name)
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return nil, errors.Errorf("VM name %q is not a valid machine name: %s", moVM.Name, strings.Join(errs, ", "))
+	}
+
+	devices := []infrav1.NetworkDeviceSpec{}
+	deviceList := object.VirtualDeviceList(moVM.Config.Hardware.Device)
+	for _, device := range deviceList.SelectByType((*types.VirtualEthernetCard)(nil)) {
+		card := device.(types.BaseVirtualEthernetCard).GetVirtualEthernetCard()
+		networkName, err := g.networkName(ctx, card)
+		if err != nil {
+			return nil, err
+		}
+		// The network devices of adopted VMs are not reconfigured, DHCP4 avoids
+		// waiting for the allocation of static IP addresses.
+		devices = append(devices, infrav1.NetworkDeviceSpec{
+			NetworkName: networkName,
+			MACAddr:     card.MacAddress,
+			DHCP4:       true,
+		})
+	}
+	if len(devices) == 0 {
+		return nil, errors.New("VM has no network devices")
+	}
+
+	var diskGiB int32
+	for _, device := range moVM.Config.Hardware.Device {
+		if disk, ok := device.(*types.VirtualDisk); ok {
+			diskGiB = int32(disk.CapacityInKB / 1024 / 1024) //nolint:gosec // Disk sizes fit into int32.
+			break
+		}
+	}
+
+	annotations := map[string]string{
+		infrav1.AdoptVMInstanceUUIDAnnotation: moVM.Config.InstanceUuid,
+	}
+	if g.options.DeleteProtection {
+		annotations[infrav1.DeleteProtectionAnnotation] = ""
+	}
+
+	return &infrav1.VSphereMachine{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: infrav1.GroupVersion.String(),
+			Kind:       "VSphereMachine",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   g.options.Namespace,
+			Annotations: annotations,
+			Labels: map[string]string{
+				clusterv1.ClusterNameLabel: g.options.ClusterName,
+			},
+		},
+		Spec: infrav1.VSphereMachineSpec{
+			VirtualMachineCloneSpec: infrav1.VirtualMachineCloneSpec{
+				// The VM is never cloned, the template only records where the VM comes from.
+				Template:          vm.InventoryPath,
+				CloneMode:         infrav1.FullClone,
+				Server:            g.options.Server,
+				Thumbprint:        g.options.Thumbprint,
+				Datacenter:        datacenter.InventoryPath,
+				Folder:            path.Dir(vm.InventoryPath),
+				NumCPUs:           moVM.Config.Hardware.NumCPU,
+				NumCoresPerSocket: moVM.Config.Hardware.NumCoresPerSocket,
+				MemoryMiB:         int64(moVM.Config.Hardware.MemoryMB),
+				DiskGiB:           diskGiB,
+				Network: infrav1.NetworkSpec{
+					Devices: devices,
+				},
+			},
+		},
+	}, nil
+}
+
+// networkName returns the name of the network backing the network device.
+func (g *Generator) networkName(ctx context.Context, card *types.VirtualEthernetCard) (string, error) {
+	switch backing := card.Backing.(type) {
+	case *types.VirtualEthernetCardNetworkBackingInfo:
+		return backing.DeviceName, nil
+	case *types.VirtualEthernetCardDistributedVirtualPortBackingInfo:
+		var portgroup mo.DistributedVirtualPortgroup
+		ref := types.ManagedObjectReference{Type: "DistributedVirtualPortgroup", Value: backing.Port.PortgroupKey}
+		if err := object.NewCommon(g.client, ref).Properties(ctx, ref, []string{"name"}, &portgroup); err != nil {
+			return "", errors.Wrapf(err, "failed to get distributed port group %s", backing.Port.PortgroupKey)
+		}
+		return portgroup.Name, nil
+	default:
+		return "", errors.Errorf("network device %d has an unsupported backing %T", card.Key, card.Backing)
+	}
+}
+
+// machineName returns the name of the machines adopting the VM with the given name,
+// the VM name in lower case with the characters invalid in object names replaced by dashes.
+func machineName(vmName string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		default:
+			return '-'
+		}
+	}, strings.ToLower(vmName))
+	return strings.Trim(name, "-.")
+}
+
+// Write writes the objects as a multi-document YAML.
+func Write(w io.Writer, objs []client.Object) error {
+	for _, obj := range objs {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal %s %s", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName())
+		}
+		if _, err := fmt.Fprintf(w, "---\n%s", data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adopt
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func TestGenerator_Generate(t *testing.T) {
+	options := Options{
+		ClusterName:          "brownfield",
+		Namespace:            "default",
+		KubernetesVersion:    "v1.31.0",
+		ControlPlaneEndpoint: infrav1.APIEndpoint{Host: "10.0.0.10", Port: 6443},
+		Server:               "vcenter.example.com",
+		ControlPlaneVMs:      []string{"DC0_H0_VM0"},
+		WorkerVMs:            []string{"DC0_H0_VM1"},
+		DeleteProtection:     true,
+	}
+
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		g := NewWithT(t)
+
+		vm, err := find.NewFinder(c).VirtualMachine(ctx, "DC0_H0_VM0")
+		g.Expect(err).ToNot(HaveOccurred())
+		var moVM mo.VirtualMachine
+		g.Expect(vm.Properties(ctx, vm.Reference(), []string{"config"}, &moVM)).To(Succeed())
+
+		objs, err := NewGenerator(c, options).Generate(ctx)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objs).To(HaveLen(7))
+
+		cluster := objs[0].(*clusterv1.Cluster)
+		g.Expect(cluster.Spec.InfrastructureRef.Name).To(Equal("brownfield"))
+		g.Expect(cluster.Spec.ControlPlaneRef).To(BeNil())
+
+		controlPlaneMachine := objs[3].(*clusterv1.Machine)
+		g.Expect(controlPlaneMachine.Name).To(Equal("dc0-h0-vm0"))
+		g.Expect(controlPlaneMachine.Labels).To(HaveKey(clusterv1.MachineControlPlaneLabel))
+		g.Expect(*controlPlaneMachine.Spec.Bootstrap.DataSecretName).To(Equal(objs[2].GetName()))
+
+		vsphereMachine := objs[4].(*infrav1.VSphereMachine)
+		g.Expect(vsphereMachine.Annotations).To(HaveKeyWithValue(infrav1.AdoptVMInstanceUUIDAnnotation, moVM.Config.InstanceUuid))
+		g.Expect(vsphereMachine.Annotations).To(HaveKey(infrav1.DeleteProtectionAnnotation))
+		g.Expect(vsphereMachine.Spec.Template).To(Equal("/DC0/vm/DC0_H0_VM0"))
+		g.Expect(vsphereMachine.Spec.Folder).To(Equal("/DC0/vm"))
+		g.Expect(vsphereMachine.Spec.NumCPUs).To(Equal(moVM.Config.Hardware.NumCPU))
+		g.Expect(vsphereMachine.Spec.MemoryMiB).To(Equal(int64(moVM.Config.Hardware.MemoryMB)))
+		g.Expect(vsphereMachine.Spec.Network.Devices).ToNot(BeEmpty())
+
+		workerMachine := objs[5].(*clusterv1.Machine)
+		g.Expect(workerMachine.Labels).ToNot(HaveKey(clusterv1.MachineControlPlaneLabel))
+
+		var buf bytes.Buffer
+		g.Expect(Write(&buf, objs)).To(Succeed())
+		g.Expect(buf.String()).To(ContainSubstring("kind: VSphereMachine"))
+		g.Expect(buf.String()).To(ContainSubstring(infrav1.AdoptVMInstanceUUIDAnnotation))
+	})
+}
+
+func TestGenerator_GenerateErrors(t *testing.T) {
+	valid := Options{
+		ClusterName:          "brownfield",
+		KubernetesVersion:    "v1.31.0",
+		ControlPlaneEndpoint: infrav1.APIEndpoint{Host: "10.0.0.10", Port: 6443},
+		Server:               "vcenter.example.com",
+		ControlPlaneVMs:      []string{"DC0_H0_VM0"},
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*Options)
+		wantErr string
+	}{
+		{
+			name:    "no control plane VMs",
+			modify:  func(o *Options) { o.ControlPlaneVMs = nil },
+			wantErr: "at least one control plane VM",
+		},
+		{
+			name:    "unknown VM",
+			modify:  func(o *Options) { o.WorkerVMs = []string{"does-not-exist"} },
+			wantErr: "failed to find VM does-not-exist",
+		},
+		{
+			name:    "same VM twice",
+			modify:  func(o *Options) { o.WorkerVMs = []string{"DC0_H0_VM0"} },
+			wantErr: "map to the same machine name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			simulator.Test(func(ctx context.Context, c *vim25.Client) {
+				g := NewWithT(t)
+
+				options := valid
+				tt.modify(&options)
+				_, err := NewGenerator(c, options).Generate(ctx)
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+			})
+		})
+	}
+}
@@ -0,0 +1,393 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bundle collects the reconcile state of a CAPV machine into a support bundle.
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/task"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	vmwarev1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/vmware/v1beta1"
+)
+
+var (
+	machineGVK                  = schema.GroupVersionKind{Group: "cluster.x-k8s.io", Version: "v1beta1", Kind: "Machine"}
+	vsphereVMGVK                = infrav1.GroupVersion.WithKind("VSphereVM")
+	vmOperatorVirtualMachineGVK = schema.GroupVersionKind{Group: "vmoperator.vmware.com", Version: "v1alpha2", Kind: "VirtualMachine"}
+
+	// vmProperties are the properties of the vCenter VM added to the bundle.
+	// The extraConfig is not collected as it contains the bootstrap data of the machine.
+	vmProperties = []string{
+		"name",
+		"summary",
+		"runtime",
+		"guest",
+		"config.uuid",
+		"config.instanceUuid",
+		"config.version",
+		"config.template",
+		"config.hardware",
+		"resourcePool",
+		"datastore",
+		"network",
+		"customValue",
+		"triggeredAlarmState",
+	}
+)
+
+// VCenterClientFunc returns a logged in client for the vCenter with the given server and thumbprint.
+type VCenterClientFunc func(ctx context.Context, server, thumbprint string) (*govmomi.Client, error)
+
+// Bundle is a set of files describing the reconcile state of a machine.
+type Bundle struct {
+	// Files maps the name of the files of the bundle to their content.
+	Files map[string][]byte
+
+	// Errors are the errors which occurred while collecting the bundle.
+	// Collecting the bundle continues on errors to gather as much information as possible.
+	Errors []error
+}
+
+// Collector collects support bundles.
+type Collector struct {
+	client        client.Client
+	vCenterClient VCenterClientFunc
+}
+
+// NewCollector returns a new Collector. If vCenterClient is nil, the vCenter-side
+// state of the machine is not collected.
+func NewCollector(c client.Client, vCenterClient VCenterClientFunc) *Collector {
+	return &Collector{
+		client:        c,
+		vCenterClient: vCenterClient,
+	}
+}
+
+// Collect returns a bundle with the Machine with the given name, its infrastructure machine,
+// the matching VSphereVM or VM Operator VirtualMachine, their conditions and, for VSphereVMs,
+// the vCenter-side VM properties, the vCenter session and the last tasks of the VM.
+func (c *Collector) Collect(ctx context.Context, namespace, name string) (*Bundle, error) {
+	b := &Bundle{Files: map[string][]byte{}}
+
+	machine, err := c.get(ctx, machineGVK, namespace, name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get Machine %s/%s", namespace, name)
+	}
+	b.addObject(machine)
+	objects := []*unstructured.Unstructured{machine}
+
+	infraRef, _, _ := unstructured.NestedMap(machine.Object, "spec", "infrastructureRef")
+	infraAPIVersion, _, _ := unstructured.NestedString(infraRef, "apiVersion")
+	infraKind, _, _ := unstructured.NestedString(infraRef, "kind")
+	infraName, _, _ := unstructured.NestedString(infraRef, "name")
+	if infraKind == "" || infraName == "" {
+		b.Errors = append(b.Errors, errors.Errorf("Machine %s/%s has no infrastructureRef", namespace, name))
+		b.addConditions(objects)
+		return b, nil
+	}
+
+	infraMachine, err := c.get(ctx, schema.FromAPIVersionAndKind(infraAPIVersion, infraKind), namespace, infraName)
+	if err != nil {
+		b.Errors = append(b.Errors, errors.Wrapf(err, "failed to get %s %s/%s", infraKind, namespace, infraName))
+		b.addConditions(objects)
+		return b, nil
+	}
+	b.addObject(infraMachine)
+	objects = append(objects, infraMachine)
+
+	switch infraMachine.GroupVersionKind().Group {
+	case infrav1.GroupVersion.Group:
+		// The VSphereVM has the same name as the VSphereMachine.
+		vsphereVM, err := c.get(ctx, vsphereVMGVK, namespace, infraName)
+		if err != nil {
+			b.Errors = append(b.Errors, errors.Wrapf(err, "failed to get VSphereVM %s/%s", namespace, infraName))
+			break
+		}
+		b.addObject(vsphereVM)
+		objects = append(objects, vsphereVM)
+		c.collectVCenter(ctx, b, vsphereVM)
+	case vmwarev1.GroupVersion.Group:
+		virtualMachines, err := c.listOwned(ctx, vmOperatorVirtualMachineGVK, infraMachine)
+		if err != nil {
+			b.Errors = append(b.Errors, errors.Wrapf(err, "failed to list VirtualMachines in namespace %s", namespace))
+			break
+		}
+		for _, virtualMachine := range virtualMachines {
+			b.addObject(virtualMachine)
+			objects = append(objects, virtualMachine)
+		}
+	}
+
+	b.addConditions(objects)
+	return b, nil
+}
+
+// collectVCenter adds the vCenter-side state of a VSphereVM to the bundle.
+func (c *Collector) collectVCenter(ctx context.Context, b *Bundle, vsphereVM *unstructured.Unstructured) {
+	if c.vCenterClient == nil {
+		return
+	}
+
+	server, _, _ := unstructured.NestedString(vsphereVM.Object, "spec", "server")
+	thumbprint, _, _ := unstructured.NestedString(vsphereVM.Object, "spec", "thumbprint")
+	biosUUID, _, _ := unstructured.NestedString(vsphereVM.Object, "spec", "biosUUID")
+	vmRef, _, _ := unstructured.NestedString(vsphereVM.Object, "status", "vmRef")
+	taskRef, _, _ := unstructured.NestedString(vsphereVM.Object, "status", "taskRef")
+
+	vCenterClient, err := c.vCenterClient(ctx, server, thumbprint)
+	if err != nil {
+		b.Errors = append(b.Errors, errors.Wrapf(err, "failed to connect to vCenter %s", server))
+		return
+	}
+	defer func() {
+		_ = vCenterClient.Logout(ctx)
+	}()
+
+	b.addYAML("vcenter-session.yaml", func() (interface{}, error) {
+		userSession, err := vCenterClient.SessionManager.UserSession(ctx)
+		if err != nil {
+			return nil, err
+		}
+		session := map[string]interface{}{
+			"server": server,
+			"about":  vCenterClient.ServiceContent.About,
+		}
+		if userSession != nil {
+			// The session key is not collected as it can be used to impersonate the session.
+			session["userName"] = userSession.UserName
+			session["loginTime"] = userSession.LoginTime
+			session["lastActiveTime"] = userSession.LastActiveTime
+		}
+		return session, nil
+	})
+
+	var ref *types.ManagedObjectReference
+	switch {
+	case vmRef != "":
+		ref = &types.ManagedObjectReference{Type: "VirtualMachine", Value: vmRef}
+	case biosUUID != "":
+		searchRef, err := object.NewSearchIndex(vCenterClient.Client).FindByUuid(ctx, nil, biosUUID, true, nil)
+		if err != nil {
+			b.Errors = append(b.Errors, errors.Wrapf(err, "failed to find VM with BIOS UUID %s", biosUUID))
+			return
+		}
+		if searchRef != nil {
+			r := searchRef.Reference()
+			ref = &r
+		}
+	}
+	if ref == nil {
+		b.Errors = append(b.Errors, errors.New("VSphereVM has neither a vmRef nor a biosUUID of an existing VM"))
+	} else {
+		b.addYAML("vcenter-vm.yaml", func() (interface{}, error) {
+			var vm mo.VirtualMachine
+			if err := vCenterClient.RetrieveOne(ctx, *ref, vmProperties, &vm); err != nil {
+				return nil, err
+			}
+			return vm, nil
+		})
+	}
+
+	b.addYAML("vcenter-tasks.yaml", func() (interface{}, error) {
+		return getTasks(ctx, vCenterClient, ref, taskRef)
+	})
+}
+
+// getTasks returns the latest tasks of the VM with the given reference and the task with the given
+// reference, sorted by queue time.
+func getTasks(ctx context.Context, vCenterClient *govmomi.Client, vmRef *types.ManagedObjectReference, taskRef string) ([]types.TaskInfo, error) {
+	var tasks []types.TaskInfo
+	if vmRef != nil {
+		collector, err := task.NewManager(vCenterClient.Client).CreateCollectorForTasks(ctx, types.TaskFilterSpec{
+			Entity: &types.TaskFilterSpecByEntity{
+				Entity:    *vmRef,
+				Recursion: types.TaskFilterSpecRecursionOptionSelf,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			_ = collector.Destroy(ctx)
+		}()
+		tasks, err = collector.LatestPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if taskRef != "" && !containsTask(tasks, taskRef) {
+		var t mo.Task
+		if err := vCenterClient.RetrieveOne(ctx, types.ManagedObjectReference{Type: "Task", Value: taskRef}, []string{"info"}, &t); err != nil {
+			return nil, errors.Wrapf(err, "failed to get task %s", taskRef)
+		}
+		tasks = append(tasks, t.Info)
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].QueueTime.Before(tasks[j].QueueTime)
+	})
+	return tasks, nil
+}
+
+func containsTask(tasks []types.TaskInfo, taskRef string) bool {
+	for _, t := range tasks {
+		if t.Task.Value == taskRef {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Collector) get(ctx context.Context, gvk schema.GroupVersionKind, namespace, name string) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	if err := c.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// listOwned returns the objects of the given kind owned by owner.
+func (c *Collector) listOwned(ctx context.Context, gvk schema.GroupVersionKind, owner *unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := c.client.List(ctx, list, client.InNamespace(owner.GetNamespace())); err != nil {
+		return nil, err
+	}
+
+	var owned []*unstructured.Unstructured
+	for i := range list.Items {
+		for _, ownerRef := range list.Items[i].GetOwnerReferences() {
+			if ownerRef.UID == owner.GetUID() {
+				owned = append(owned, &list.Items[i])
+				break
+			}
+		}
+	}
+	return owned, nil
+}
+
+// addObject adds an object to the bundle. Managed fields are dropped as they are not useful for troubleshooting.
+func (b *Bundle) addObject(obj *unstructured.Unstructured) {
+	obj = obj.DeepCopy()
+	obj.SetManagedFields(nil)
+	b.addYAML(fmt.Sprintf("%s-%s.yaml", strings.ToLower(obj.GetKind()), obj.GetName()), func() (interface{}, error) {
+		return obj.Object, nil
+	})
+}
+
+// addYAML adds the YAML representation of the object returned by get to the bundle.
+func (b *Bundle) addYAML(fileName string, get func() (interface{}, error)) {
+	obj, err := get()
+	if err != nil {
+		b.Errors = append(b.Errors, errors.Wrapf(err, "failed to collect %s", fileName))
+		return
+	}
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		b.Errors = append(b.Errors, errors.Wrapf(err, "failed to marshal %s", fileName))
+		return
+	}
+	b.Files[fileName] = data
+}
+
+// addConditions adds a summary of the conditions of the given objects to the bundle.
+func (b *Bundle) addConditions(objects []*unstructured.Unstructured) {
+	var sb strings.Builder
+	for _, obj := range objects {
+		fmt.Fprintf(&sb, "%s %s/%s\n", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+		conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+		for _, c := range conditions {
+			condition, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(&sb, "  %v=%v", condition["type"], condition["status"])
+			if reason, ok := condition["reason"]; ok && reason != "" {
+				fmt.Fprintf(&sb, " %v", reason)
+			}
+			if message, ok := condition["message"]; ok && message != "" {
+				fmt.Fprintf(&sb, ": %v", message)
+			}
+			sb.WriteString("\n")
+		}
+	}
+	b.Files["conditions.txt"] = []byte(sb.String())
+}
+
+// Write writes the bundle as a gzipped tarball to w. Errors which occurred
+// while collecting the bundle are written to errors.txt.
+func (b *Bundle) Write(w io.Writer) error {
+	files := make(map[string][]byte, len(b.Files)+1)
+	for name, data := range b.Files {
+		files[name] = data
+	}
+	if len(b.Errors) > 0 {
+		var sb strings.Builder
+		for _, err := range b.Errors {
+			sb.WriteString(err.Error())
+			sb.WriteString("\n")
+		}
+		files["errors.txt"] = []byte(sb.String())
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+	now := time.Now()
+	for _, name := range names {
+		if err := tarWriter.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0o600,
+			Size:    int64(len(files[name])),
+			ModTime: now,
+		}); err != nil {
+			return errors.Wrapf(err, "failed to write header of %s", name)
+		}
+		if _, err := tarWriter.Write(files[name]); err != nil {
+			return errors.Wrapf(err, "failed to write %s", name)
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	return gzipWriter.Close()
+}
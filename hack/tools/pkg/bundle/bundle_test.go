@@ -0,0 +1,193 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	vmoprv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	vmwarev1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/vmware/v1beta1"
+)
+
+const namespace = "default"
+
+func newFakeClient(g *WithT, objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(vmwarev1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(vmoprv1.AddToScheme(scheme)).To(Succeed())
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func machine(infraRef corev1.ObjectReference) *clusterv1.Machine {
+	return &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: namespace},
+		Spec: clusterv1.MachineSpec{
+			ClusterName:       "cluster",
+			InfrastructureRef: infraRef,
+		},
+		Status: clusterv1.MachineStatus{
+			Conditions: clusterv1.Conditions{
+				{Type: clusterv1.InfrastructureReadyCondition, Status: corev1.ConditionFalse, Reason: "Cloning", Message: "cloning VM"},
+			},
+		},
+	}
+}
+
+func TestCollector_Collect(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("collects a govmomi machine with its vCenter-side state", func(t *testing.T) {
+		g := NewWithT(t)
+
+		model := simulator.VPX()
+		g.Expect(model.Create()).To(Succeed())
+		defer model.Remove()
+		server := model.Service.NewServer()
+		defer server.Close()
+
+		vCenterClient := func(ctx context.Context, _, _ string) (*govmomi.Client, error) {
+			return govmomi.NewClient(ctx, server.URL, true)
+		}
+
+		c, err := vCenterClient(ctx, "", "")
+		g.Expect(err).ToNot(HaveOccurred())
+		vm, err := find.NewFinder(c.Client).VirtualMachine(ctx, "DC0_H0_VM0")
+		g.Expect(err).ToNot(HaveOccurred())
+		powerOffTask, err := vm.PowerOff(ctx)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(powerOffTask.Wait(ctx)).To(Succeed())
+
+		vsphereMachine := &infrav1.VSphereMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "vspheremachine", Namespace: namespace},
+		}
+		vsphereVM := &infrav1.VSphereVM{
+			ObjectMeta: metav1.ObjectMeta{Name: "vspheremachine", Namespace: namespace},
+			Spec: infrav1.VSphereVMSpec{
+				VirtualMachineCloneSpec: infrav1.VirtualMachineCloneSpec{Server: server.URL.Host},
+			},
+			Status: infrav1.VSphereVMStatus{
+				VMRef:   vm.Reference().Value,
+				TaskRef: powerOffTask.Reference().Value,
+			},
+		}
+		k8sClient := newFakeClient(g,
+			machine(corev1.ObjectReference{APIVersion: infrav1.GroupVersion.String(), Kind: "VSphereMachine", Name: "vspheremachine"}),
+			vsphereMachine,
+			vsphereVM,
+		)
+
+		b, err := NewCollector(k8sClient, vCenterClient).Collect(ctx, namespace, "machine")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(b.Errors).To(BeEmpty())
+		g.Expect(b.Files).To(HaveKey("machine-machine.yaml"))
+		g.Expect(b.Files).To(HaveKey("vspheremachine-vspheremachine.yaml"))
+		g.Expect(b.Files).To(HaveKey("vspherevm-vspheremachine.yaml"))
+		g.Expect(string(b.Files["conditions.txt"])).To(ContainSubstring("InfrastructureReady=False Cloning: cloning VM"))
+		g.Expect(string(b.Files["vcenter-vm.yaml"])).To(ContainSubstring("DC0_H0_VM0"))
+		g.Expect(string(b.Files["vcenter-session.yaml"])).To(ContainSubstring("userName"))
+		g.Expect(string(b.Files["vcenter-tasks.yaml"])).To(ContainSubstring("VirtualMachine.powerOff"))
+	})
+
+	t.Run("collects a supervisor machine with its VirtualMachine", func(t *testing.T) {
+		g := NewWithT(t)
+
+		vsphereMachine := &vmwarev1.VSphereMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "vspheremachine", Namespace: namespace, UID: "vspheremachine-uid"},
+		}
+		ownerRefs := []metav1.OwnerReference{{APIVersion: vmwarev1.GroupVersion.String(), Kind: "VSphereMachine", Name: "vspheremachine", UID: "vspheremachine-uid"}}
+		k8sClient := newFakeClient(g,
+			machine(corev1.ObjectReference{APIVersion: vmwarev1.GroupVersion.String(), Kind: "VSphereMachine", Name: "vspheremachine"}),
+			vsphereMachine,
+			&vmoprv1.VirtualMachine{ObjectMeta: metav1.ObjectMeta{Name: "owned-vm", Namespace: namespace, OwnerReferences: ownerRefs}},
+			&vmoprv1.VirtualMachine{ObjectMeta: metav1.ObjectMeta{Name: "other-vm", Namespace: namespace}},
+		)
+
+		b, err := NewCollector(k8sClient, nil).Collect(ctx, namespace, "machine")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(b.Errors).To(BeEmpty())
+		g.Expect(b.Files).To(HaveKey("virtualmachine-owned-vm.yaml"))
+		g.Expect(b.Files).ToNot(HaveKey("virtualmachine-other-vm.yaml"))
+		g.Expect(b.Files).ToNot(HaveKey("vcenter-vm.yaml"))
+	})
+
+	t.Run("records missing objects as errors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		k8sClient := newFakeClient(g,
+			machine(corev1.ObjectReference{APIVersion: infrav1.GroupVersion.String(), Kind: "VSphereMachine", Name: "vspheremachine"}),
+		)
+
+		b, err := NewCollector(k8sClient, nil).Collect(ctx, namespace, "machine")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(b.Errors).To(HaveLen(1))
+		g.Expect(b.Files).To(HaveKey("machine-machine.yaml"))
+		g.Expect(b.Files).To(HaveKey("conditions.txt"))
+
+		_, err = NewCollector(k8sClient, nil).Collect(ctx, namespace, "does-not-exist")
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+func TestBundle_Write(t *testing.T) {
+	g := NewWithT(t)
+
+	b := &Bundle{
+		Files:  map[string][]byte{"conditions.txt": []byte("conditions")},
+		Errors: []error{io.ErrUnexpectedEOF},
+	}
+	buf := &bytes.Buffer{}
+	g.Expect(b.Write(buf)).To(Succeed())
+
+	gzipReader, err := gzip.NewReader(buf)
+	g.Expect(err).ToNot(HaveOccurred())
+	tarReader := tar.NewReader(gzipReader)
+	files := map[string]string{}
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		g.Expect(err).ToNot(HaveOccurred())
+		data, err := io.ReadAll(tarReader)
+		g.Expect(err).ToNot(HaveOccurred())
+		files[header.Name] = string(data)
+	}
+	g.Expect(files).To(Equal(map[string]string{
+		"conditions.txt": "conditions",
+		"errors.txt":     "unexpected EOF\n",
+	}))
+}
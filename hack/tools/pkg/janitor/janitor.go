@@ -23,14 +23,18 @@ import (
 	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/vmware/govmomi/object"
 	govmomicluster "github.com/vmware/govmomi/vapi/cluster"
+	"github.com/vmware/govmomi/vapi/library"
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
+
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/govmomi/vcenter"
 )
 
 // NewJanitor creates a new Janitor.
@@ -47,18 +51,38 @@ type Janitor struct {
 	vSphereClients *VSphereClients
 }
 
+// StaleObjectsFilter defines which VM templates and content library items are
+// considered stale and will be deleted by the janitor.
+type StaleObjectsFilter struct {
+	// NamePrefixes restricts deletion to objects whose name starts with one of the prefixes.
+	// If empty, no VM templates or content library items get deleted.
+	NamePrefixes []string
+
+	// MaxAge is the minimum age of an object before it gets deleted.
+	MaxAge time.Duration
+}
+
+// isStale returns true if an object with the given name and creation time matches the filter.
+func (f StaleObjectsFilter) isStale(name string, creationTime *time.Time, now time.Time) bool {
+	if creationTime == nil || now.Sub(*creationTime) < f.MaxAge {
+		return false
+	}
+	return hasAnyPrefix(name, f.NamePrefixes)
+}
+
 type virtualMachine struct {
 	managedObject mo.VirtualMachine
 	object        *object.VirtualMachine
 }
 
 // CleanupVSphere cleans up vSphere VMs, folders and resource pools.
-func (s *Janitor) CleanupVSphere(ctx context.Context, folders, resourcePools, vmFolders []string, skipClusterModule bool) error {
+// VM templates in vmFolders and items in contentLibraries are only deleted if they match staleObjects.
+func (s *Janitor) CleanupVSphere(ctx context.Context, folders, resourcePools, vmFolders, contentLibraries []string, staleObjects StaleObjectsFilter, skipClusterModule bool) error {
 	errList := []error{}
 
 	// Delete vms to cleanup folders and resource pools.
 	for _, folder := range vmFolders {
-		if err := s.deleteVSphereVMs(ctx, folder); err != nil {
+		if err := s.deleteVSphereVMs(ctx, folder, staleObjects); err != nil {
 			errList = append(errList, errors.Wrapf(err, "cleaning up vSphereVMs for folder %q", folder))
 		}
 	}
@@ -86,6 +110,16 @@ func (s *Janitor) CleanupVSphere(ctx context.Context, folders, resourcePools, vm
 		return errors.Wrap(err, "cleaning up folders")
 	}
 
+	// Delete stale content library items.
+	for _, contentLibrary := range contentLibraries {
+		if err := s.deleteContentLibraryItems(ctx, contentLibrary, staleObjects); err != nil {
+			errList = append(errList, errors.Wrapf(err, "cleaning up items for content library %q", contentLibrary))
+		}
+	}
+	if err := kerrors.NewAggregate(errList); err != nil {
+		return errors.Wrap(err, "cleaning up content library items")
+	}
+
 	if skipClusterModule {
 		return nil
 	}
@@ -99,7 +133,8 @@ func (s *Janitor) CleanupVSphere(ctx context.Context, folders, resourcePools, vm
 }
 
 // deleteVSphereVMs deletes all VSphereVMs in a given folder in vSphere.
-func (s *Janitor) deleteVSphereVMs(ctx context.Context, folder string) error {
+// VM templates are only deleted if they match the staleTemplates filter.
+func (s *Janitor) deleteVSphereVMs(ctx context.Context, folder string, staleTemplates StaleObjectsFilter) error {
 	log := ctrl.LoggerFrom(ctx).WithName("vSphereVMs").WithValues("folder", folder)
 	ctx = ctrl.LoggerInto(ctx, log)
 
@@ -125,7 +160,7 @@ func (s *Janitor) deleteVSphereVMs(ctx context.Context, folder string) error {
 		managedObjectReferences = append(managedObjectReferences, obj.Object.Reference())
 	}
 	var managedObjectVMs []mo.VirtualMachine
-	if err := s.vSphereClients.Govmomi.Retrieve(ctx, managedObjectReferences, []string{"config", "summary.runtime.powerState", "summary.config.template"}, &managedObjectVMs); err != nil {
+	if err := s.vSphereClients.Govmomi.Retrieve(ctx, managedObjectReferences, append([]string{"config", "summary.runtime.powerState", "summary.config.template"}, vcenter.VCLSVMProperties...), &managedObjectVMs); err != nil {
 		return err
 	}
 
@@ -133,12 +168,21 @@ func (s *Janitor) deleteVSphereVMs(ctx context.Context, folder string) error {
 	vmsToDelete := []*virtualMachine{}
 
 	// Figure out which VMs to delete and which to power off and delete.
+	now := time.Now()
 	for _, managedObjectVM := range managedObjectVMs {
-		if managedObjectVM.Summary.Config.Template {
-			// Skip templates for deletion.
+		// Skip vCLS system VMs, they are owned by vCenter and can't be deleted.
+		if vcenter.IsVCLSVM(managedObjectVM) {
+			log.Info("Skipping deletion of vCLS VM", "vm", managedObjectVM.Summary.Config.Name)
 			continue
 		}
 
+		if managedObjectVM.Summary.Config.Template {
+			// Skip templates for deletion unless they are stale.
+			if managedObjectVM.Config == nil || !staleTemplates.isStale(managedObjectVM.Config.Name, managedObjectVM.Config.CreateDate, now) {
+				continue
+			}
+		}
+
 		vm := &virtualMachine{
 			managedObject: managedObjectVM,
 			object:        object.NewVirtualMachine(s.vSphereClients.Vim, managedObjectVM.Reference()),
@@ -247,6 +291,12 @@ func (s *Janitor) deleteObjectChildren(ctx context.Context, inventoryPath string
 	for i := range managedEntities {
 		managedEntity := managedEntities[i]
 
+		// Filter out the folder of the vCLS VMs, it is managed by vCenter.
+		if vcenter.IsVCLSInventoryPath(managedEntity.element.Path) {
+			log.Info("Skipping deletion of object: object is managed by vSphere Cluster Services", "inventoryPath", managedEntity.element.Path)
+			continue
+		}
+
 		// Filter out objects which have children.
 		if hasChildren[managedEntity.element.Path] {
 			log.Info("Skipping deletion of object: object has child objects of a different type", "inventoryPath", managedEntity.element.Path)
@@ -328,3 +378,49 @@ func (s *Janitor) deleteVSphereClusterModules(ctx context.Context) error {
 
 	return kerrors.NewAggregate(errList)
 }
+
+// deleteContentLibraryItems deletes all items of a content library which match the staleItems filter.
+func (s *Janitor) deleteContentLibraryItems(ctx context.Context, contentLibrary string, staleItems StaleObjectsFilter) error {
+	log := ctrl.LoggerFrom(ctx).WithName("contentLibraryItems").WithValues("contentLibrary", contentLibrary)
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	if contentLibrary == "" {
+		return fmt.Errorf("cannot use empty string as content library")
+	}
+
+	log.Info("Deleting stale content library items")
+
+	manager := library.NewManager(s.vSphereClients.Rest)
+
+	lib, err := manager.GetLibraryByName(ctx, contentLibrary)
+	if err != nil {
+		return err
+	}
+
+	items, err := manager.GetLibraryItems(ctx, lib.ID)
+	if err != nil {
+		return err
+	}
+
+	errList := []error{}
+	now := time.Now()
+	for i := range items {
+		item := items[i]
+		if !staleItems.isStale(item.Name, item.CreationTime, now) {
+			continue
+		}
+
+		log.Info("Deleting content library item", "item", item.Name)
+
+		if s.dryRun {
+			// Skipping actual deletion on dryRun.
+			continue
+		}
+
+		if err := manager.DeleteLibraryItem(ctx, &item); err != nil {
+			errList = append(errList, errors.Wrapf(err, "deleting content library item %q", item.Name))
+		}
+	}
+
+	return kerrors.NewAggregate(errList)
+}
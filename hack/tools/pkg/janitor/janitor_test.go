@@ -23,6 +23,7 @@ import (
 	"path"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/onsi/gomega"
 	"github.com/onsi/gomega/gbytes"
@@ -162,7 +163,7 @@ func Test_janitor_deleteVSphereVMs(t *testing.T) {
 			// use folder created for this test case as inventoryPath
 			inventoryPath := vcsimFolder("").Path(relativePath)
 
-			err := s.deleteVSphereVMs(ctx, inventoryPath)
+			err := s.deleteVSphereVMs(ctx, inventoryPath, StaleObjectsFilter{})
 			if tt.wantErr {
 				g.Expect(err).To(gomega.HaveOccurred())
 			} else {
@@ -384,7 +385,7 @@ func Test_janitor_CleanupVSphere(t *testing.T) {
 			folders := []string{folder}
 			resourcePools := []string{resourcePool}
 
-			g.Expect(s.CleanupVSphere(ctx, folders, resourcePools, folders, false)).To(gomega.Succeed())
+			g.Expect(s.CleanupVSphere(ctx, folders, resourcePools, folders, nil, StaleObjectsFilter{}, false)).To(gomega.Succeed())
 			existingObjects, err := recursiveListFoldersAndResourcePools(ctx, relativePath, clients.Govmomi, clients.Finder, clients.ViewManager)
 			g.Expect(err).ToNot(gomega.HaveOccurred())
 			g.Expect(existingObjects).To(gomega.BeEquivalentTo(tt.want))
@@ -396,6 +397,62 @@ func Test_janitor_CleanupVSphere(t *testing.T) {
 	}
 }
 
+func Test_StaleObjectsFilter_isStale(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-2 * time.Hour)
+	recent := now.Add(-time.Minute)
+
+	tests := []struct {
+		name         string
+		filter       StaleObjectsFilter
+		objectName   string
+		creationTime *time.Time
+		want         bool
+	}{
+		{
+			name:         "no prefixes",
+			filter:       StaleObjectsFilter{MaxAge: time.Hour},
+			objectName:   "capv-e2e-template",
+			creationTime: &old,
+			want:         false,
+		},
+		{
+			name:         "matching prefix and old enough",
+			filter:       StaleObjectsFilter{NamePrefixes: []string{"capv-e2e-"}, MaxAge: time.Hour},
+			objectName:   "capv-e2e-template",
+			creationTime: &old,
+			want:         true,
+		},
+		{
+			name:         "matching prefix but too recent",
+			filter:       StaleObjectsFilter{NamePrefixes: []string{"capv-e2e-"}, MaxAge: time.Hour},
+			objectName:   "capv-e2e-template",
+			creationTime: &recent,
+			want:         false,
+		},
+		{
+			name:         "not matching prefix",
+			filter:       StaleObjectsFilter{NamePrefixes: []string{"capv-e2e-"}, MaxAge: time.Hour},
+			objectName:   "ubuntu-2204-kube-v1.30.0",
+			creationTime: &old,
+			want:         false,
+		},
+		{
+			name:         "unknown creation time",
+			filter:       StaleObjectsFilter{NamePrefixes: []string{"capv-e2e-"}, MaxAge: time.Hour},
+			objectName:   "capv-e2e-template",
+			creationTime: nil,
+			want:         false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			g.Expect(tt.filter.isStale(tt.objectName, tt.creationTime, now)).To(gomega.Equal(tt.want))
+		})
+	}
+}
+
 func assertObjectExists(ctx context.Context, g *gomega.WithT, finder *find.Finder, inventoryPath string) {
 	g.THelper()
 
@@ -0,0 +1,214 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package janitor
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25/mo"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// CleanupTagsAndCustomAttributes deletes vSphere tags, tag categories and custom attribute
+// definitions which have a name starting with one of the given prefixes and which are not in use anymore.
+// vSphere does not track the creation time of these objects, so an object is considered stale if:
+// * a tag is not attached to any object
+// * a tag category does not contain any tag
+// * a custom attribute definition does not have a value set on any managed entity.
+// Because an object created by a concurrently running test is unused until it gets attached, callers
+// should scope the prefixes to a resource which is not in use, see ResourceNamePrefixes.
+func (s *Janitor) CleanupTagsAndCustomAttributes(ctx context.Context, namePrefixes []string) error {
+	if len(namePrefixes) == 0 {
+		return nil
+	}
+
+	if err := s.deleteTagsAndCategories(ctx, namePrefixes); err != nil {
+		return errors.Wrap(err, "cleaning up tags and tag categories")
+	}
+
+	if err := s.deleteCustomAttributes(ctx, namePrefixes); err != nil {
+		return errors.Wrap(err, "cleaning up custom attributes")
+	}
+
+	return nil
+}
+
+// deleteTagsAndCategories deletes unused tags and empty tag categories matching the given prefixes.
+func (s *Janitor) deleteTagsAndCategories(ctx context.Context, namePrefixes []string) error {
+	log := ctrl.LoggerFrom(ctx).WithName("tags")
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	log.Info("Deleting unused tags and tag categories")
+
+	manager := tags.NewManager(s.vSphereClients.Rest)
+
+	allTags, err := manager.GetTags(ctx)
+	if err != nil {
+		return err
+	}
+
+	errList := []error{}
+	// remainingTags counts the tags per category which are not getting deleted.
+	remainingTags := map[string]int{}
+	for i := range allTags {
+		tag := allTags[i]
+		remainingTags[tag.CategoryID]++
+
+		if !hasAnyPrefix(tag.Name, namePrefixes) {
+			continue
+		}
+
+		attachedObjects, err := manager.ListAttachedObjects(ctx, tag.ID)
+		if err != nil {
+			errList = append(errList, errors.Wrapf(err, "listing attached objects for tag %q", tag.Name))
+			continue
+		}
+		// Do not attempt to delete if the tag is still attached to objects.
+		if len(attachedObjects) > 0 {
+			continue
+		}
+
+		log.Info("Deleting unused tag", "tag", tag.Name)
+		remainingTags[tag.CategoryID]--
+
+		if s.dryRun {
+			// Skipping actual deletion on dryRun.
+			continue
+		}
+
+		if err := manager.DeleteTag(ctx, &tag); err != nil {
+			remainingTags[tag.CategoryID]++
+			errList = append(errList, errors.Wrapf(err, "deleting tag %q", tag.Name))
+		}
+	}
+
+	categories, err := manager.GetCategories(ctx)
+	if err != nil {
+		return kerrors.NewAggregate(append(errList, err))
+	}
+
+	for i := range categories {
+		category := categories[i]
+		if !hasAnyPrefix(category.Name, namePrefixes) {
+			continue
+		}
+
+		// Do not attempt to delete if the category still contains tags.
+		if remainingTags[category.ID] > 0 {
+			continue
+		}
+
+		log.Info("Deleting empty tag category", "category", category.Name)
+
+		if s.dryRun {
+			// Skipping actual deletion on dryRun.
+			continue
+		}
+
+		if err := manager.DeleteCategory(ctx, &category); err != nil {
+			errList = append(errList, errors.Wrapf(err, "deleting tag category %q", category.Name))
+		}
+	}
+
+	return kerrors.NewAggregate(errList)
+}
+
+// deleteCustomAttributes deletes unused custom attribute definitions matching the given prefixes.
+func (s *Janitor) deleteCustomAttributes(ctx context.Context, namePrefixes []string) error {
+	log := ctrl.LoggerFrom(ctx).WithName("customAttributes")
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	log.Info("Deleting unused custom attributes")
+
+	fields, err := s.vSphereClients.FieldsManager.Field(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Collect the keys of all custom attributes which have a value set on any managed entity.
+	v, err := s.vSphereClients.ViewManager.CreateContainerView(ctx, s.vSphereClients.Vim.ServiceContent.RootFolder, []string{"ManagedEntity"}, true)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = v.Destroy(ctx)
+	}()
+
+	var entities []mo.ManagedEntity
+	if err := v.Retrieve(ctx, []string{"ManagedEntity"}, []string{"customValue"}, &entities); err != nil {
+		return err
+	}
+
+	usedKeys := map[int32]bool{}
+	for _, entity := range entities {
+		for _, value := range entity.CustomValue {
+			usedKeys[value.GetCustomFieldValue().Key] = true
+		}
+	}
+
+	errList := []error{}
+	for _, field := range fields {
+		if !hasAnyPrefix(field.Name, namePrefixes) {
+			continue
+		}
+
+		// Do not attempt to delete if the custom attribute is still in use.
+		if usedKeys[field.Key] {
+			continue
+		}
+
+		log.Info("Deleting unused custom attribute", "customAttribute", field.Name)
+
+		if s.dryRun {
+			// Skipping actual deletion on dryRun.
+			continue
+		}
+
+		if err := s.vSphereClients.FieldsManager.Remove(ctx, field.Key); err != nil {
+			errList = append(errList, errors.Wrapf(err, "deleting custom attribute %q", field.Name))
+		}
+	}
+
+	return kerrors.NewAggregate(errList)
+}
+
+// ResourceNamePrefixes scopes the given name prefixes to a Boskos resource by appending the resource name,
+// so only tags, tag categories and custom attributes created for that resource get cleaned up.
+func ResourceNamePrefixes(namePrefixes []string, resourceName string) []string {
+	resourcePrefixes := []string{}
+	for _, prefix := range namePrefixes {
+		if prefix == "" {
+			continue
+		}
+		resourcePrefixes = append(resourcePrefixes, prefix+resourceName)
+	}
+	return resourcePrefixes
+}
+
+// hasAnyPrefix returns true if name starts with one of the non-empty prefixes.
+func hasAnyPrefix(name string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if prefix != "" && strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
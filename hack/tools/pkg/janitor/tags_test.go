@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package janitor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	_ "github.com/vmware/govmomi/vapi/simulator"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/view"
+	"github.com/vmware/govmomi/vim25"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func Test_janitor_CleanupTagsAndCustomAttributes(t *testing.T) {
+	for _, dryRun := range []bool{false, true} {
+		t.Run(map[bool]string{false: "delete", true: "dryRun"}[dryRun], func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			simulator.Test(func(ctx context.Context, c *vim25.Client) {
+				ctx = ctrl.LoggerInto(ctx, klog.Background())
+
+				restClient := rest.NewClient(c)
+				g.Expect(restClient.Login(ctx, simulator.DefaultLogin)).To(gomega.Succeed())
+				fieldsManager, err := object.GetCustomFieldsManager(c)
+				g.Expect(err).ToNot(gomega.HaveOccurred())
+
+				s := &Janitor{
+					dryRun: dryRun,
+					vSphereClients: &VSphereClients{
+						Vim:           c,
+						Govmomi:       &govmomi.Client{Client: c, SessionManager: session.NewManager(c)},
+						Rest:          restClient,
+						FieldsManager: fieldsManager,
+						Finder:        find.NewFinder(c),
+						ViewManager:   view.NewManager(c),
+					},
+				}
+
+				vm, err := find.NewFinder(c).VirtualMachine(ctx, "DC0_H0_VM0")
+				g.Expect(err).ToNot(gomega.HaveOccurred())
+
+				// Create tags and categories: one unused and one attached tag with the prefix,
+				// and one unused tag without the prefix.
+				manager := tags.NewManager(restClient)
+				staleCategoryID, err := manager.CreateCategory(ctx, &tags.Category{Name: "capv-e2e-stale", Cardinality: "MULTIPLE"})
+				g.Expect(err).ToNot(gomega.HaveOccurred())
+				usedCategoryID, err := manager.CreateCategory(ctx, &tags.Category{Name: "capv-e2e-used", Cardinality: "MULTIPLE"})
+				g.Expect(err).ToNot(gomega.HaveOccurred())
+				otherCategoryID, err := manager.CreateCategory(ctx, &tags.Category{Name: "other", Cardinality: "MULTIPLE"})
+				g.Expect(err).ToNot(gomega.HaveOccurred())
+				_, err = manager.CreateTag(ctx, &tags.Tag{Name: "capv-e2e-stale", CategoryID: staleCategoryID})
+				g.Expect(err).ToNot(gomega.HaveOccurred())
+				usedTagID, err := manager.CreateTag(ctx, &tags.Tag{Name: "capv-e2e-used", CategoryID: usedCategoryID})
+				g.Expect(err).ToNot(gomega.HaveOccurred())
+				g.Expect(manager.AttachTag(ctx, usedTagID, vm.Reference())).To(gomega.Succeed())
+				_, err = manager.CreateTag(ctx, &tags.Tag{Name: "other", CategoryID: otherCategoryID})
+				g.Expect(err).ToNot(gomega.HaveOccurred())
+
+				// Create custom attributes: one unused and one used with the prefix.
+				_, err = fieldsManager.Add(ctx, "capv-e2e-stale", "VirtualMachine", nil, nil)
+				g.Expect(err).ToNot(gomega.HaveOccurred())
+				usedField, err := fieldsManager.Add(ctx, "capv-e2e-used", "VirtualMachine", nil, nil)
+				g.Expect(err).ToNot(gomega.HaveOccurred())
+				g.Expect(fieldsManager.Set(ctx, vm.Reference(), usedField.Key, "value")).To(gomega.Succeed())
+
+				g.Expect(s.CleanupTagsAndCustomAttributes(ctx, []string{"capv-e2e-"})).To(gomega.Succeed())
+
+				wantTags := []string{"capv-e2e-used", "other"}
+				wantCategories := []string{"capv-e2e-used", "other"}
+				wantFields := []string{"capv-e2e-used"}
+				if dryRun {
+					wantTags = append(wantTags, "capv-e2e-stale")
+					wantCategories = append(wantCategories, "capv-e2e-stale")
+					wantFields = append(wantFields, "capv-e2e-stale")
+				}
+
+				existingTags, err := manager.GetTags(ctx)
+				g.Expect(err).ToNot(gomega.HaveOccurred())
+				tagNames := []string{}
+				for _, tag := range existingTags {
+					tagNames = append(tagNames, tag.Name)
+				}
+				g.Expect(tagNames).To(gomega.ConsistOf(wantTags))
+
+				existingCategories, err := manager.GetCategories(ctx)
+				g.Expect(err).ToNot(gomega.HaveOccurred())
+				categoryNames := []string{}
+				for _, category := range existingCategories {
+					categoryNames = append(categoryNames, category.Name)
+				}
+				g.Expect(categoryNames).To(gomega.ConsistOf(wantCategories))
+
+				existingFields, err := fieldsManager.Field(ctx)
+				g.Expect(err).ToNot(gomega.HaveOccurred())
+				fieldNames := []string{}
+				for _, field := range existingFields {
+					fieldNames = append(fieldNames, field.Name)
+				}
+				g.Expect(fieldNames).To(gomega.ContainElements(wantFields))
+				if !dryRun {
+					g.Expect(fieldNames).ToNot(gomega.ContainElement("capv-e2e-stale"))
+				}
+			})
+		})
+	}
+}
+
+func Test_ResourceNamePrefixes(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	g.Expect(ResourceNamePrefixes(nil, "vsphere-1")).To(gomega.BeEmpty())
+	g.Expect(ResourceNamePrefixes([]string{"capv-e2e-", ""}, "vsphere-1")).To(gomega.Equal([]string{"capv-e2e-vsphere-1"}))
+}
@@ -0,0 +1,643 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package migrate migrates clusters managed in govmomi mode to supervisor mode.
+//
+// The migration is staged, every stage can be rolled back until the cluster is cleaned up:
+//   - Prepare creates a supervisor-mode VSphereCluster with the same name as the govmomi-mode
+//     VSphereCluster and a supervisor-mode VSphereMachineTemplate for every VSphereMachineTemplate
+//     used by the control plane and the MachineDeployments of the cluster.
+//   - SwitchMachines points the control plane and the MachineDeployments to the supervisor-mode
+//     VSphereMachineTemplates, which rolls out the machines as VM Operator VirtualMachines.
+//   - Finalize points the Cluster to the supervisor-mode VSphereCluster once all govmomi-mode
+//     VSphereMachines are gone.
+//   - Cleanup deletes the govmomi-mode VSphereCluster and VSphereMachineTemplates.
+package migrate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	vmwarev1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/vmware/v1beta1"
+)
+
+const (
+	// PhaseAnnotation is set on the Cluster and records the last completed stage of the migration.
+	PhaseAnnotation = "vmware.infrastructure.cluster.x-k8s.io/migration-phase"
+
+	// PreviousInfrastructureRefAnnotation is set on the Cluster, the KubeadmControlPlane and the
+	// MachineDeployments and records the infrastructure reference to restore on rollback.
+	PreviousInfrastructureRefAnnotation = "vmware.infrastructure.cluster.x-k8s.io/migration-previous-infrastructure-ref"
+
+	// TemplateSuffix is appended to the name of a govmomi-mode VSphereMachineTemplate to get
+	// the name of the supervisor-mode VSphereMachineTemplate created for it.
+	TemplateSuffix = "-supervisor"
+)
+
+// Phase is a stage of the migration.
+type Phase string
+
+const (
+	// PhaseNone is the phase of a cluster which is not being migrated.
+	PhaseNone Phase = ""
+	// PhasePrepared is the phase after the supervisor-mode objects have been created.
+	PhasePrepared Phase = "Prepared"
+	// PhaseMachinesSwitched is the phase after the control plane and the MachineDeployments
+	// have been pointed to the supervisor-mode VSphereMachineTemplates.
+	PhaseMachinesSwitched Phase = "MachinesSwitched"
+	// PhaseFinalized is the phase after the Cluster has been pointed to the supervisor-mode VSphereCluster.
+	PhaseFinalized Phase = "Finalized"
+)
+
+var (
+	govmomiClusterGVK  = infrav1.GroupVersion.WithKind("VSphereCluster")
+	govmomiTemplateGVK = infrav1.GroupVersion.WithKind("VSphereMachineTemplate")
+	vmwareClusterGVK   = vmwarev1.GroupVersion.WithKind("VSphereCluster")
+	vmwareTemplateGVK  = vmwarev1.GroupVersion.WithKind("VSphereMachineTemplate")
+
+	// requiredCRDs are the CRDs which have to be installed in the management cluster to run supervisor-mode clusters.
+	requiredCRDs = []string{
+		"vsphereclusters.vmware.infrastructure.cluster.x-k8s.io",
+		"vspheremachines.vmware.infrastructure.cluster.x-k8s.io",
+		"vspheremachinetemplates.vmware.infrastructure.cluster.x-k8s.io",
+		"virtualmachines.vmoperator.vmware.com",
+	}
+)
+
+// Options are the settings of the supervisor-mode VSphereMachineTemplates created by Prepare.
+type Options struct {
+	// ClassName is the VM Operator VirtualMachineClass of the migrated machines.
+	ClassName string
+
+	// ImageName is the VM Operator VirtualMachineImage of the migrated machines.
+	ImageName string
+
+	// StorageClass is the StorageClass of the migrated machines.
+	StorageClass string
+}
+
+// TemplateMigration is a VSphereMachineTemplate which is replaced by the migration.
+type TemplateMigration struct {
+	// Owner is the KubeadmControlPlane or MachineDeployment using the template.
+	Owner corev1.ObjectReference
+
+	// From is the govmomi-mode VSphereMachineTemplate.
+	From corev1.ObjectReference
+
+	// To is the supervisor-mode VSphereMachineTemplate.
+	To corev1.ObjectReference
+}
+
+// Plan describes the migration of a cluster.
+type Plan struct {
+	// Cluster is the cluster to migrate.
+	Cluster *clusterv1.Cluster
+
+	// Phase is the last completed stage of the migration.
+	Phase Phase
+
+	// Templates are the VSphereMachineTemplates replaced by the migration.
+	Templates []TemplateMigration
+
+	// Blockers are the reasons which prevent the migration.
+	Blockers []string
+
+	// Warnings are the settings of the cluster which are not carried over by the migration.
+	Warnings []string
+}
+
+// Migrator migrates clusters from govmomi mode to supervisor mode.
+type Migrator struct {
+	client  client.Client
+	options Options
+}
+
+// NewMigrator creates a new Migrator.
+func NewMigrator(c client.Client, options Options) *Migrator {
+	return &Migrator{
+		client:  c,
+		options: options,
+	}
+}
+
+// Plan returns the migration plan of the cluster without changing any object.
+func (m *Migrator) Plan(ctx context.Context, key client.ObjectKey) (*Plan, error) {
+	cluster := &clusterv1.Cluster{}
+	if err := m.client.Get(ctx, key, cluster); err != nil {
+		return nil, errors.Wrapf(err, "failed to get Cluster %s", key)
+	}
+	plan := &Plan{
+		Cluster: cluster,
+		Phase:   Phase(cluster.Annotations[PhaseAnnotation]),
+	}
+
+	for _, name := range requiredCRDs {
+		if err := m.client.Get(ctx, client.ObjectKey{Name: name}, &apiextensionsv1.CustomResourceDefinition{}); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, errors.Wrapf(err, "failed to get CustomResourceDefinition %s", name)
+			}
+			plan.Blockers = append(plan.Blockers, fmt.Sprintf("CustomResourceDefinition %s is not installed", name))
+		}
+	}
+	if cluster.Spec.Topology != nil {
+		plan.Blockers = append(plan.Blockers, "clusters using a ClusterClass are not supported, the topology controller would revert the infrastructure references")
+	}
+	if ref := cluster.Spec.InfrastructureRef; plan.Phase != PhaseFinalized && (ref == nil || ref.GroupVersionKind().GroupKind() != govmomiClusterGVK.GroupKind()) {
+		plan.Blockers = append(plan.Blockers, "Cluster does not reference a govmomi-mode VSphereCluster")
+	}
+
+	kcp, err := m.getControlPlane(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+	if kcp == nil {
+		plan.Blockers = append(plan.Blockers, "only clusters with a KubeadmControlPlane are supported")
+	} else {
+		if err := m.addTemplate(ctx, plan, kcp, kcp.Spec.MachineTemplate.InfrastructureRef); err != nil {
+			return nil, err
+		}
+	}
+
+	machineDeployments, err := m.getMachineDeployments(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+	for i := range machineDeployments {
+		md := &machineDeployments[i]
+		if md.Spec.Template.Spec.FailureDomain != nil {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("MachineDeployment %s: failure domain %s is a VSphereDeploymentZone and has to be replaced with a vSphere Zone of the Supervisor after the migration", md.Name, *md.Spec.Template.Spec.FailureDomain))
+		}
+		if err := m.addTemplate(ctx, plan, md, md.Spec.Template.Spec.InfrastructureRef); err != nil {
+			return nil, err
+		}
+	}
+	plan.Warnings = append(plan.Warnings, "the KubeadmConfigSpec of the control plane and the KubeadmConfigTemplates are not converted, review them for govmomi-specific settings like the control plane endpoint provider")
+	return plan, nil
+}
+
+// addTemplate adds the migration of the VSphereMachineTemplate referenced by owner to the plan.
+func (m *Migrator) addTemplate(ctx context.Context, plan *Plan, owner client.Object, ref corev1.ObjectReference) error {
+	ownerRef := objectReference(owner)
+	from := ref
+	if plan.Phase == PhaseMachinesSwitched || plan.Phase == PhaseFinalized {
+		previous, err := previousInfrastructureRef(owner)
+		if err != nil {
+			return err
+		}
+		if previous == nil {
+			plan.Blockers = append(plan.Blockers, fmt.Sprintf("%s %s does not record its previous infrastructure reference", ownerRef.Kind, ownerRef.Name))
+			return nil
+		}
+		from = *previous
+	}
+	if from.GroupVersionKind().GroupKind() != govmomiTemplateGVK.GroupKind() {
+		plan.Blockers = append(plan.Blockers, fmt.Sprintf("%s %s does not reference a govmomi-mode VSphereMachineTemplate", ownerRef.Kind, ownerRef.Name))
+		return nil
+	}
+	plan.Templates = append(plan.Templates, TemplateMigration{
+		Owner: ownerRef,
+		From:  from,
+		To: corev1.ObjectReference{
+			APIVersion: vmwareTemplateGVK.GroupVersion().String(),
+			Kind:       vmwareTemplateGVK.Kind,
+			Namespace:  from.Namespace,
+			Name:       from.Name + TemplateSuffix,
+		},
+	})
+
+	template := &infrav1.VSphereMachineTemplate{}
+	if err := m.client.Get(ctx, client.ObjectKey{Namespace: plan.Cluster.Namespace, Name: from.Name}, template); err != nil {
+		if apierrors.IsNotFound(err) && plan.Phase == PhaseFinalized {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get VSphereMachineTemplate %s", from.Name)
+	}
+	for _, field := range unconvertedFields(&template.Spec.Template.Spec) {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("VSphereMachineTemplate %s: %s is not carried over", template.Name, field))
+	}
+	return nil
+}
+
+// Prepare creates the supervisor-mode VSphereCluster and VSphereMachineTemplates of the cluster.
+func (m *Migrator) Prepare(ctx context.Context, key client.ObjectKey) error {
+	plan, err := m.planForPhase(ctx, key, PhaseNone, PhasePrepared)
+	if err != nil {
+		return err
+	}
+	if m.options.ClassName == "" || m.options.ImageName == "" {
+		return errors.New("the VirtualMachineClass and the VirtualMachineImage of the migrated machines are required")
+	}
+	cluster := plan.Cluster
+	clusterOwnerRef := metav1.OwnerReference{
+		APIVersion: clusterv1.GroupVersion.String(),
+		Kind:       "Cluster",
+		Name:       cluster.Name,
+		UID:        cluster.UID,
+	}
+	labels := map[string]string{clusterv1.ClusterNameLabel: cluster.Name}
+
+	vsphereCluster := &vmwarev1.VSphereCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       cluster.Namespace,
+			Name:            cluster.Spec.InfrastructureRef.Name,
+			Labels:          labels,
+			OwnerReferences: []metav1.OwnerReference{clusterOwnerRef},
+		},
+	}
+	if err := m.create(ctx, vsphereCluster); err != nil {
+		return err
+	}
+
+	for _, t := range plan.Templates {
+		from := &infrav1.VSphereMachineTemplate{}
+		if err := m.client.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: t.From.Name}, from); err != nil {
+			return errors.Wrapf(err, "failed to get VSphereMachineTemplate %s", t.From.Name)
+		}
+		if err := m.create(ctx, m.convertTemplate(from, t.To.Name, labels, clusterOwnerRef)); err != nil {
+			return err
+		}
+	}
+
+	return m.setPhase(ctx, cluster, PhasePrepared)
+}
+
+// SwitchMachines points the control plane and the MachineDeployments of the cluster to the
+// supervisor-mode VSphereMachineTemplates.
+func (m *Migrator) SwitchMachines(ctx context.Context, key client.ObjectKey) error {
+	plan, err := m.planForPhase(ctx, key, PhasePrepared, PhaseMachinesSwitched)
+	if err != nil {
+		return err
+	}
+	for _, t := range plan.Templates {
+		if err := m.setInfrastructureRef(ctx, t.Owner, t.To); err != nil {
+			return err
+		}
+	}
+	return m.setPhase(ctx, plan.Cluster, PhaseMachinesSwitched)
+}
+
+// Finalize points the Cluster to the supervisor-mode VSphereCluster. It fails as long as
+// govmomi-mode VSphereMachines of the cluster exist.
+func (m *Migrator) Finalize(ctx context.Context, key client.ObjectKey) error {
+	plan, err := m.planForPhase(ctx, key, PhaseMachinesSwitched, PhaseFinalized)
+	if err != nil {
+		return err
+	}
+	cluster := plan.Cluster
+
+	vsphereMachines := &infrav1.VSphereMachineList{}
+	if err := m.client.List(ctx, vsphereMachines, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name}); err != nil {
+		return errors.Wrap(err, "failed to list VSphereMachines")
+	}
+	if len(vsphereMachines.Items) > 0 {
+		return errors.Errorf("%d govmomi-mode VSphereMachines of the cluster still exist, wait for the rollout of the machines to complete", len(vsphereMachines.Items))
+	}
+
+	ref := *cluster.Spec.InfrastructureRef
+	ref.APIVersion = vmwareClusterGVK.GroupVersion().String()
+	if err := m.setInfrastructureRef(ctx, objectReference(cluster), ref); err != nil {
+		return err
+	}
+	return m.setPhase(ctx, cluster, PhaseFinalized)
+}
+
+// Cleanup deletes the govmomi-mode VSphereCluster and VSphereMachineTemplates of a finalized
+// migration and removes the migration annotations. It cannot be rolled back.
+func (m *Migrator) Cleanup(ctx context.Context, key client.ObjectKey) error {
+	plan, err := m.planForPhase(ctx, key, PhaseFinalized, PhaseNone)
+	if err != nil {
+		return err
+	}
+	cluster := plan.Cluster
+
+	previous, err := previousInfrastructureRef(cluster)
+	if err != nil {
+		return err
+	}
+	if previous != nil {
+		if err := m.delete(ctx, &infrav1.VSphereCluster{ObjectMeta: metav1.ObjectMeta{Namespace: cluster.Namespace, Name: previous.Name}}); err != nil {
+			return err
+		}
+	}
+	for _, t := range plan.Templates {
+		if err := m.delete(ctx, &infrav1.VSphereMachineTemplate{ObjectMeta: metav1.ObjectMeta{Namespace: cluster.Namespace, Name: t.From.Name}}); err != nil {
+			return err
+		}
+		if err := m.removeAnnotations(ctx, t.Owner); err != nil {
+			return err
+		}
+	}
+	return m.removeAnnotations(ctx, objectReference(cluster))
+}
+
+// Rollback reverts the last completed stage of the migration and returns the phase the
+// migration has been rolled back to.
+func (m *Migrator) Rollback(ctx context.Context, key client.ObjectKey) (Phase, error) {
+	plan, err := m.Plan(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	cluster := plan.Cluster
+
+	switch plan.Phase {
+	case PhaseFinalized:
+		previous, err := previousInfrastructureRef(cluster)
+		if err != nil {
+			return "", err
+		}
+		if previous == nil {
+			return "", errors.New("Cluster does not record its previous infrastructure reference")
+		}
+		if err := m.setInfrastructureRef(ctx, objectReference(cluster), *previous); err != nil {
+			return "", err
+		}
+		return PhaseMachinesSwitched, m.setPhase(ctx, cluster, PhaseMachinesSwitched)
+	case PhaseMachinesSwitched:
+		for _, t := range plan.Templates {
+			if err := m.setInfrastructureRef(ctx, t.Owner, t.From); err != nil {
+				return "", err
+			}
+		}
+		return PhasePrepared, m.setPhase(ctx, cluster, PhasePrepared)
+	case PhasePrepared:
+		vsphereMachines := &vmwarev1.VSphereMachineList{}
+		if err := m.client.List(ctx, vsphereMachines, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name}); err != nil {
+			return "", errors.Wrap(err, "failed to list VSphereMachines")
+		}
+		if len(vsphereMachines.Items) > 0 {
+			return "", errors.Errorf("%d supervisor-mode VSphereMachines of the cluster still exist, wait for the rollout of the machines to complete", len(vsphereMachines.Items))
+		}
+		for _, t := range plan.Templates {
+			if err := m.delete(ctx, &vmwarev1.VSphereMachineTemplate{ObjectMeta: metav1.ObjectMeta{Namespace: cluster.Namespace, Name: t.To.Name}}); err != nil {
+				return "", err
+			}
+			if err := m.removeAnnotations(ctx, t.Owner); err != nil {
+				return "", err
+			}
+		}
+		if err := m.delete(ctx, &vmwarev1.VSphereCluster{ObjectMeta: metav1.ObjectMeta{Namespace: cluster.Namespace, Name: cluster.Spec.InfrastructureRef.Name}}); err != nil {
+			return "", err
+		}
+		return PhaseNone, m.removeAnnotations(ctx, objectReference(cluster))
+	default:
+		return "", errors.Errorf("Cluster %s is not being migrated", key)
+	}
+}
+
+// planForPhase returns the migration plan of the cluster and verifies that the migration
+// can move from the phase to the next phase.
+func (m *Migrator) planForPhase(ctx context.Context, key client.ObjectKey, phase, next Phase) (*Plan, error) {
+	plan, err := m.Plan(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Phase == next && next != PhaseNone {
+		return nil, errors.Errorf("Cluster %s is already in migration phase %q", key, next)
+	}
+	if plan.Phase != phase {
+		return nil, errors.Errorf("Cluster %s is in migration phase %q, expected %q", key, plan.Phase, phase)
+	}
+	if len(plan.Blockers) > 0 {
+		return nil, errors.Errorf("Cluster %s cannot be migrated: %v", key, plan.Blockers)
+	}
+	return plan, nil
+}
+
+// convertTemplate converts a govmomi-mode VSphereMachineTemplate to a supervisor-mode VSphereMachineTemplate.
+func (m *Migrator) convertTemplate(from *infrav1.VSphereMachineTemplate, name string, labels map[string]string, ownerRef metav1.OwnerReference) *vmwarev1.VSphereMachineTemplate {
+	spec := from.Spec.Template.Spec
+	to := &vmwarev1.VSphereMachineTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       from.Namespace,
+			Name:            name,
+			Labels:          labels,
+			OwnerReferences: []metav1.OwnerReference{ownerRef},
+		},
+		Spec: vmwarev1.VSphereMachineTemplateSpec{
+			Template: vmwarev1.VSphereMachineTemplateResource{
+				Spec: vmwarev1.VSphereMachineSpec{
+					ClassName:          m.options.ClassName,
+					ImageName:          m.options.ImageName,
+					StorageClass:       m.options.StorageClass,
+					PowerOffMode:       vmwarev1.VirtualMachinePowerOpMode(spec.PowerOffMode),
+					MinHardwareVersion: spec.HardwareVersion,
+				},
+			},
+		},
+	}
+	if to.Spec.Template.Spec.PowerOffMode == "" {
+		to.Spec.Template.Spec.PowerOffMode = vmwarev1.VirtualMachinePowerOpModeHard
+	}
+	if spec.NamingStrategy != nil {
+		to.Spec.Template.Spec.NamingStrategy = &vmwarev1.VirtualMachineNamingStrategy{Template: spec.NamingStrategy.Template}
+	}
+	return to
+}
+
+// unconvertedFields returns the fields of a govmomi-mode VSphereMachineSpec which have no
+// equivalent in supervisor mode.
+func unconvertedFields(spec *infrav1.VSphereMachineSpec) []string {
+	fields := []string{}
+	if len(spec.Network.Devices) > 1 {
+		fields = append(fields, "network devices other than the first one")
+	}
+	for _, device := range spec.Network.Devices {
+		if len(device.IPAddrs) > 0 || len(device.AddressesFromPools) > 0 {
+			fields = append(fields, "static IP addresses")
+			break
+		}
+	}
+	for name, isSet := range map[string]bool{
+		"additionalDisksGiB":        len(spec.AdditionalDisksGiB) > 0,
+		"dataDisks":                 len(spec.DataDisks) > 0,
+		"pciDevices":                len(spec.PciDevices) > 0,
+		"customVMXKeys":             len(spec.CustomVMXKeys) > 0,
+		"vAppConfig":                len(spec.VAppConfig) > 0,
+		"customAttributes":          len(spec.CustomAttributes) > 0,
+		"tagIDs":                    len(spec.TagIDs) > 0,
+		"encryption":                spec.Encryption != nil,
+		"cloudInitCustomizationRef": spec.CloudInitCustomizationRef != nil,
+		"guestReadinessGates":       len(spec.GuestReadinessGates) > 0,
+	} {
+		if isSet {
+			fields = append(fields, name)
+		}
+	}
+	// Sort the fields of the map for a stable output.
+	sort.Strings(fields)
+	return fields
+}
+
+func (m *Migrator) getControlPlane(ctx context.Context, cluster *clusterv1.Cluster) (*controlplanev1.KubeadmControlPlane, error) {
+	ref := cluster.Spec.ControlPlaneRef
+	if ref == nil || ref.GroupVersionKind().GroupKind() != controlplanev1.GroupVersion.WithKind("KubeadmControlPlane").GroupKind() {
+		return nil, nil
+	}
+	kcp := &controlplanev1.KubeadmControlPlane{}
+	if err := m.client.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: ref.Name}, kcp); err != nil {
+		return nil, errors.Wrapf(err, "failed to get KubeadmControlPlane %s", ref.Name)
+	}
+	return kcp, nil
+}
+
+func (m *Migrator) getMachineDeployments(ctx context.Context, cluster *clusterv1.Cluster) ([]clusterv1.MachineDeployment, error) {
+	machineDeployments := &clusterv1.MachineDeploymentList{}
+	if err := m.client.List(ctx, machineDeployments, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name}); err != nil {
+		return nil, errors.Wrap(err, "failed to list MachineDeployments")
+	}
+	return machineDeployments.Items, nil
+}
+
+// setInfrastructureRef sets the infrastructure reference of the Cluster, KubeadmControlPlane or
+// MachineDeployment and records the previous reference in an annotation.
+func (m *Migrator) setInfrastructureRef(ctx context.Context, owner corev1.ObjectReference, ref corev1.ObjectReference) error {
+	obj, err := m.getOwner(ctx, owner)
+	if err != nil {
+		return err
+	}
+	patchBase := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+
+	var current *corev1.ObjectReference
+	switch o := obj.(type) {
+	case *clusterv1.Cluster:
+		if o.Spec.InfrastructureRef == nil {
+			o.Spec.InfrastructureRef = &corev1.ObjectReference{}
+		}
+		current = o.Spec.InfrastructureRef
+	case *controlplanev1.KubeadmControlPlane:
+		current = &o.Spec.MachineTemplate.InfrastructureRef
+	case *clusterv1.MachineDeployment:
+		current = &o.Spec.Template.Spec.InfrastructureRef
+	}
+	previous, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+	*current = ref
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[PreviousInfrastructureRefAnnotation] = string(previous)
+	obj.SetAnnotations(annotations)
+
+	if err := m.client.Patch(ctx, obj, patchBase); err != nil {
+		return errors.Wrapf(err, "failed to patch %s %s", owner.Kind, owner.Name)
+	}
+	return nil
+}
+
+func (m *Migrator) getOwner(ctx context.Context, owner corev1.ObjectReference) (client.Object, error) {
+	var obj client.Object
+	switch owner.Kind {
+	case "Cluster":
+		obj = &clusterv1.Cluster{}
+	case "KubeadmControlPlane":
+		obj = &controlplanev1.KubeadmControlPlane{}
+	case "MachineDeployment":
+		obj = &clusterv1.MachineDeployment{}
+	default:
+		return nil, errors.Errorf("unexpected kind %s", owner.Kind)
+	}
+	if err := m.client.Get(ctx, client.ObjectKey{Namespace: owner.Namespace, Name: owner.Name}, obj); err != nil {
+		return nil, errors.Wrapf(err, "failed to get %s %s", owner.Kind, owner.Name)
+	}
+	return obj, nil
+}
+
+func (m *Migrator) setPhase(ctx context.Context, cluster *clusterv1.Cluster, phase Phase) error {
+	latest := &clusterv1.Cluster{}
+	if err := m.client.Get(ctx, client.ObjectKeyFromObject(cluster), latest); err != nil {
+		return errors.Wrapf(err, "failed to get Cluster %s", cluster.Name)
+	}
+	patchBase := client.MergeFrom(latest.DeepCopy())
+	if latest.Annotations == nil {
+		latest.Annotations = map[string]string{}
+	}
+	latest.Annotations[PhaseAnnotation] = string(phase)
+	if err := m.client.Patch(ctx, latest, patchBase); err != nil {
+		return errors.Wrapf(err, "failed to patch Cluster %s", cluster.Name)
+	}
+	return nil
+}
+
+func (m *Migrator) removeAnnotations(ctx context.Context, owner corev1.ObjectReference) error {
+	obj, err := m.getOwner(ctx, owner)
+	if err != nil {
+		return err
+	}
+	patchBase := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	annotations := obj.GetAnnotations()
+	delete(annotations, PhaseAnnotation)
+	delete(annotations, PreviousInfrastructureRefAnnotation)
+	obj.SetAnnotations(annotations)
+	if err := m.client.Patch(ctx, obj, patchBase); err != nil {
+		return errors.Wrapf(err, "failed to patch %s %s", owner.Kind, owner.Name)
+	}
+	return nil
+}
+
+func (m *Migrator) create(ctx context.Context, obj client.Object) error {
+	if err := m.client.Create(ctx, obj); err != nil && !apierrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "failed to create %T %s", obj, obj.GetName())
+	}
+	return nil
+}
+
+func (m *Migrator) delete(ctx context.Context, obj client.Object) error {
+	if err := m.client.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete %T %s", obj, obj.GetName())
+	}
+	return nil
+}
+
+func previousInfrastructureRef(obj client.Object) (*corev1.ObjectReference, error) {
+	value, ok := obj.GetAnnotations()[PreviousInfrastructureRefAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	ref := &corev1.ObjectReference{}
+	if err := json.Unmarshal([]byte(value), ref); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse annotation %s of %s", PreviousInfrastructureRefAnnotation, obj.GetName())
+	}
+	return ref, nil
+}
+
+func objectReference(obj client.Object) corev1.ObjectReference {
+	ref := corev1.ObjectReference{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+	switch obj.(type) {
+	case *clusterv1.Cluster:
+		ref.Kind = "Cluster"
+	case *controlplanev1.KubeadmControlPlane:
+		ref.Kind = "KubeadmControlPlane"
+	case *clusterv1.MachineDeployment:
+		ref.Kind = "MachineDeployment"
+	}
+	return ref
+}
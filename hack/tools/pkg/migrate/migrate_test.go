@@ -0,0 +1,277 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrate
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	vmwarev1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/vmware/v1beta1"
+)
+
+const namespace = "default"
+
+func TestMigrator_Plan(t *testing.T) {
+	tests := []struct {
+		name         string
+		modify       func(*clusterv1.Cluster, *infrav1.VSphereMachineTemplate)
+		withoutCRDs  bool
+		wantBlockers []string
+		wantWarnings []string
+	}{
+		{
+			name: "cluster without blockers",
+		},
+		{
+			name:        "missing CRDs",
+			withoutCRDs: true,
+			wantBlockers: []string{
+				"CustomResourceDefinition vsphereclusters.vmware.infrastructure.cluster.x-k8s.io is not installed",
+				"CustomResourceDefinition vspheremachines.vmware.infrastructure.cluster.x-k8s.io is not installed",
+				"CustomResourceDefinition vspheremachinetemplates.vmware.infrastructure.cluster.x-k8s.io is not installed",
+				"CustomResourceDefinition virtualmachines.vmoperator.vmware.com is not installed",
+			},
+		},
+		{
+			name: "cluster with topology",
+			modify: func(cluster *clusterv1.Cluster, _ *infrav1.VSphereMachineTemplate) {
+				cluster.Spec.Topology = &clusterv1.Topology{Class: "class"}
+			},
+			wantBlockers: []string{"clusters using a ClusterClass are not supported, the topology controller would revert the infrastructure references"},
+		},
+		{
+			name: "template with unconverted fields",
+			modify: func(_ *clusterv1.Cluster, template *infrav1.VSphereMachineTemplate) {
+				template.Spec.Template.Spec.Network.Devices = []infrav1.NetworkDeviceSpec{{NetworkName: "net", IPAddrs: []string{"10.0.0.10/24"}}}
+				template.Spec.Template.Spec.AdditionalDisksGiB = []int32{10}
+				template.Spec.Template.Spec.PciDevices = []infrav1.PCIDeviceSpec{{}}
+			},
+			wantWarnings: []string{
+				"VSphereMachineTemplate cp: static IP addresses is not carried over",
+				"VSphereMachineTemplate cp: additionalDisksGiB is not carried over",
+				"VSphereMachineTemplate cp: pciDevices is not carried over",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			objects := newObjects()
+			if tt.modify != nil {
+				tt.modify(objects.cluster, objects.cpTemplate)
+			}
+			if tt.withoutCRDs {
+				objects.crds = nil
+			}
+
+			plan, err := NewMigrator(newFakeClient(g, objects.list()...), Options{}).Plan(context.Background(), client.ObjectKeyFromObject(objects.cluster))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(plan.Phase).To(Equal(PhaseNone))
+			g.Expect(plan.Blockers).To(ConsistOf(tt.wantBlockers))
+			// The KubeadmConfigSpecs are never converted.
+			g.Expect(plan.Warnings).To(ConsistOf(append(tt.wantWarnings, "the KubeadmConfigSpec of the control plane and the KubeadmConfigTemplates are not converted, review them for govmomi-specific settings like the control plane endpoint provider")))
+			g.Expect(plan.Templates).To(ConsistOf(
+				TemplateMigration{
+					Owner: corev1.ObjectReference{Kind: "KubeadmControlPlane", Namespace: namespace, Name: "kcp"},
+					From:  templateRef(infrav1.GroupVersion.String(), "cp"),
+					To:    templateRef(vmwarev1.GroupVersion.String(), "cp-supervisor"),
+				},
+				TemplateMigration{
+					Owner: corev1.ObjectReference{Kind: "MachineDeployment", Namespace: namespace, Name: "md"},
+					From:  templateRef(infrav1.GroupVersion.String(), "worker"),
+					To:    templateRef(vmwarev1.GroupVersion.String(), "worker-supervisor"),
+				},
+			))
+		})
+	}
+}
+
+func TestMigrator_Migrate(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	objects := newObjects()
+	objects.cpTemplate.Spec.Template.Spec.PowerOffMode = infrav1.VirtualMachinePowerOpModeTrySoft
+	vsphereMachine := &infrav1.VSphereMachine{ObjectMeta: metav1.ObjectMeta{
+		Namespace: namespace, Name: "machine", Labels: map[string]string{clusterv1.ClusterNameLabel: "cluster"},
+	}}
+	c := newFakeClient(g, append(objects.list(), vsphereMachine)...)
+	key := client.ObjectKeyFromObject(objects.cluster)
+	m := NewMigrator(c, Options{ClassName: "best-effort-small", ImageName: "ubuntu", StorageClass: "sc"})
+
+	kcp := &controlplanev1.KubeadmControlPlane{}
+	md := &clusterv1.MachineDeployment{}
+	cluster := &clusterv1.Cluster{}
+	get := func() {
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(objects.kcp), kcp)).To(Succeed())
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(objects.md), md)).To(Succeed())
+		g.Expect(c.Get(ctx, key, cluster)).To(Succeed())
+	}
+
+	// Stages can only be run in order.
+	g.Expect(m.SwitchMachines(ctx, key)).ToNot(Succeed())
+	g.Expect(m.Finalize(ctx, key)).ToNot(Succeed())
+	g.Expect(NewMigrator(c, Options{}).Prepare(ctx, key)).ToNot(Succeed())
+
+	// Prepare
+	g.Expect(m.Prepare(ctx, key)).To(Succeed())
+	get()
+	g.Expect(cluster.Annotations).To(HaveKeyWithValue(PhaseAnnotation, string(PhasePrepared)))
+	g.Expect(c.Get(ctx, key, &vmwarev1.VSphereCluster{})).To(Succeed())
+	cpTemplate := &vmwarev1.VSphereMachineTemplate{}
+	g.Expect(c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: "cp-supervisor"}, cpTemplate)).To(Succeed())
+	g.Expect(cpTemplate.Spec.Template.Spec).To(Equal(vmwarev1.VSphereMachineSpec{
+		ClassName:    "best-effort-small",
+		ImageName:    "ubuntu",
+		StorageClass: "sc",
+		PowerOffMode: vmwarev1.VirtualMachinePowerOpModeTrySoft,
+	}))
+	g.Expect(c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: "worker-supervisor"}, &vmwarev1.VSphereMachineTemplate{})).To(Succeed())
+	g.Expect(m.Prepare(ctx, key)).ToNot(Succeed())
+
+	// SwitchMachines
+	g.Expect(m.SwitchMachines(ctx, key)).To(Succeed())
+	get()
+	g.Expect(cluster.Annotations).To(HaveKeyWithValue(PhaseAnnotation, string(PhaseMachinesSwitched)))
+	g.Expect(kcp.Spec.MachineTemplate.InfrastructureRef).To(Equal(templateRef(vmwarev1.GroupVersion.String(), "cp-supervisor")))
+	g.Expect(md.Spec.Template.Spec.InfrastructureRef).To(Equal(templateRef(vmwarev1.GroupVersion.String(), "worker-supervisor")))
+
+	// Finalize waits for the govmomi-mode VSphereMachines to be gone.
+	g.Expect(m.Finalize(ctx, key)).To(MatchError(ContainSubstring("1 govmomi-mode VSphereMachines of the cluster still exist")))
+	g.Expect(c.Delete(ctx, vsphereMachine)).To(Succeed())
+	g.Expect(m.Finalize(ctx, key)).To(Succeed())
+	get()
+	g.Expect(cluster.Annotations).To(HaveKeyWithValue(PhaseAnnotation, string(PhaseFinalized)))
+	g.Expect(cluster.Spec.InfrastructureRef.APIVersion).To(Equal(vmwarev1.GroupVersion.String()))
+
+	// Rollback reverts one stage at a time.
+	phase, err := m.Rollback(ctx, key)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(phase).To(Equal(PhaseMachinesSwitched))
+	get()
+	g.Expect(cluster.Spec.InfrastructureRef.APIVersion).To(Equal(infrav1.GroupVersion.String()))
+
+	phase, err = m.Rollback(ctx, key)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(phase).To(Equal(PhasePrepared))
+	get()
+	g.Expect(kcp.Spec.MachineTemplate.InfrastructureRef).To(Equal(templateRef(infrav1.GroupVersion.String(), "cp")))
+	g.Expect(md.Spec.Template.Spec.InfrastructureRef).To(Equal(templateRef(infrav1.GroupVersion.String(), "worker")))
+
+	phase, err = m.Rollback(ctx, key)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(phase).To(Equal(PhaseNone))
+	get()
+	g.Expect(cluster.Annotations).ToNot(HaveKey(PhaseAnnotation))
+	g.Expect(kcp.Annotations).ToNot(HaveKey(PreviousInfrastructureRefAnnotation))
+	g.Expect(apierrors.IsNotFound(c.Get(ctx, key, &vmwarev1.VSphereCluster{}))).To(BeTrue())
+	g.Expect(apierrors.IsNotFound(c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: "cp-supervisor"}, &vmwarev1.VSphereMachineTemplate{}))).To(BeTrue())
+	_, err = m.Rollback(ctx, key)
+	g.Expect(err).To(HaveOccurred())
+
+	// Cleanup deletes the govmomi-mode objects.
+	g.Expect(m.Prepare(ctx, key)).To(Succeed())
+	g.Expect(m.SwitchMachines(ctx, key)).To(Succeed())
+	g.Expect(m.Finalize(ctx, key)).To(Succeed())
+	g.Expect(m.Cleanup(ctx, key)).To(Succeed())
+	get()
+	g.Expect(cluster.Annotations).ToNot(HaveKey(PhaseAnnotation))
+	g.Expect(cluster.Annotations).ToNot(HaveKey(PreviousInfrastructureRefAnnotation))
+	g.Expect(md.Annotations).ToNot(HaveKey(PreviousInfrastructureRefAnnotation))
+	g.Expect(cluster.Spec.InfrastructureRef.APIVersion).To(Equal(vmwarev1.GroupVersion.String()))
+	g.Expect(apierrors.IsNotFound(c.Get(ctx, key, &infrav1.VSphereCluster{}))).To(BeTrue())
+	g.Expect(apierrors.IsNotFound(c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: "cp"}, &infrav1.VSphereMachineTemplate{}))).To(BeTrue())
+	g.Expect(apierrors.IsNotFound(c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: "worker"}, &infrav1.VSphereMachineTemplate{}))).To(BeTrue())
+	g.Expect(c.Get(ctx, key, &vmwarev1.VSphereCluster{})).To(Succeed())
+}
+
+type testObjects struct {
+	cluster        *clusterv1.Cluster
+	vsphereCluster *infrav1.VSphereCluster
+	kcp            *controlplanev1.KubeadmControlPlane
+	md             *clusterv1.MachineDeployment
+	cpTemplate     *infrav1.VSphereMachineTemplate
+	workerTemplate *infrav1.VSphereMachineTemplate
+	crds           []client.Object
+}
+
+func newObjects() *testObjects {
+	o := &testObjects{
+		cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "cluster", UID: "cluster-uid"},
+			Spec: clusterv1.ClusterSpec{
+				InfrastructureRef: &corev1.ObjectReference{APIVersion: infrav1.GroupVersion.String(), Kind: "VSphereCluster", Namespace: namespace, Name: "cluster"},
+				ControlPlaneRef:   &corev1.ObjectReference{APIVersion: controlplanev1.GroupVersion.String(), Kind: "KubeadmControlPlane", Namespace: namespace, Name: "kcp"},
+			},
+		},
+		vsphereCluster: &infrav1.VSphereCluster{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "cluster"}},
+		kcp: &controlplanev1.KubeadmControlPlane{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "kcp"},
+			Spec: controlplanev1.KubeadmControlPlaneSpec{
+				MachineTemplate: controlplanev1.KubeadmControlPlaneMachineTemplate{InfrastructureRef: templateRef(infrav1.GroupVersion.String(), "cp")},
+			},
+		},
+		md: &clusterv1.MachineDeployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "md", Labels: map[string]string{clusterv1.ClusterNameLabel: "cluster"}},
+			Spec: clusterv1.MachineDeploymentSpec{
+				ClusterName: "cluster",
+				Template: clusterv1.MachineTemplateSpec{Spec: clusterv1.MachineSpec{
+					ClusterName:       "cluster",
+					InfrastructureRef: templateRef(infrav1.GroupVersion.String(), "worker"),
+				}},
+			},
+		},
+		cpTemplate:     &infrav1.VSphereMachineTemplate{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "cp"}},
+		workerTemplate: &infrav1.VSphereMachineTemplate{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "worker"}},
+	}
+	for _, name := range requiredCRDs {
+		o.crds = append(o.crds, &apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+	return o
+}
+
+func (o *testObjects) list() []client.Object {
+	return append([]client.Object{o.cluster, o.vsphereCluster, o.kcp, o.md, o.cpTemplate, o.workerTemplate}, o.crds...)
+}
+
+func newFakeClient(g *WithT, objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(controlplanev1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(vmwarev1.AddToScheme(scheme)).To(Succeed())
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func templateRef(apiVersion, name string) corev1.ObjectReference {
+	return corev1.ObjectReference{APIVersion: apiVersion, Kind: "VSphereMachineTemplate", Namespace: namespace, Name: name}
+}
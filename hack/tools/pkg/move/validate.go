@@ -0,0 +1,398 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package move validates that clusterctl move transfers all objects required by CAPV clusters.
+package move
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	secretutil "sigs.k8s.io/cluster-api/util/secret"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+const (
+	// NotFoundReason is used when a referenced object does not exist.
+	NotFoundReason = "NotFound"
+	// NotMovedReason is used when a referenced object would not be moved by clusterctl move.
+	NotMovedReason = "NotMoved"
+)
+
+// Finding is an object referenced by a CAPV cluster which would be left behind by clusterctl move.
+type Finding struct {
+	// Object is the object which would be left behind.
+	Object corev1.ObjectReference
+
+	// ReferencedBy is the object referencing Object. It is equal to Object
+	// for objects which are checked themselves, e.g. VSphereClusters.
+	ReferencedBy corev1.ObjectReference
+
+	// Reason is either NotFoundReason or NotMovedReason.
+	Reason string
+}
+
+// String returns a human-readable description of the Finding.
+func (f Finding) String() string {
+	if f.ReferencedBy == f.Object {
+		return fmt.Sprintf("%s %s: %s", f.Object.Kind, objectKey(f.Object), f.Reason)
+	}
+	return fmt.Sprintf("%s %s referenced by %s %s: %s", f.Object.Kind, objectKey(f.Object), f.ReferencedBy.Kind, objectKey(f.ReferencedBy), f.Reason)
+}
+
+// Validator checks which objects referenced by CAPV clusters would be left behind by clusterctl move.
+// It follows the same rules as clusterctl move: an object is moved if its kind is discovered by clusterctl
+// and it is either labeled for move (at object or CRD level) or part of the hierarchy of a Cluster,
+// ClusterClass, ClusterResourceSet or of an object labeled for move-hierarchy.
+type Validator struct {
+	client              client.Client
+	controllerNamespace string
+
+	crds map[schema.GroupKind]*apiextensionsv1.CustomResourceDefinition
+}
+
+// NewValidator creates a new Validator. The controllerNamespace is the namespace of the
+// CAPV controller manager, which contains the Secrets referenced by VSphereClusterIdentities.
+func NewValidator(c client.Client, controllerNamespace string) *Validator {
+	return &Validator{
+		client:              c,
+		controllerNamespace: controllerNamespace,
+	}
+}
+
+// Validate returns the objects referenced by the VSphereClusters, VSphereMachines and IPAddressClaims
+// in the namespace, or in all namespaces if namespace is empty, which would not be moved by clusterctl move.
+func (v *Validator) Validate(ctx context.Context, namespace string) ([]Finding, error) {
+	if err := v.loadCRDs(ctx); err != nil {
+		return nil, err
+	}
+
+	findings := []Finding{}
+	check := func(referencedBy client.Object, gvk schema.GroupVersionKind, key client.ObjectKey) error {
+		finding, err := v.check(ctx, referencedBy, gvk, key)
+		if err != nil {
+			return err
+		}
+		if finding != nil {
+			findings = append(findings, *finding)
+		}
+		return nil
+	}
+
+	vsphereClusters := &infrav1.VSphereClusterList{}
+	if err := v.client.List(ctx, vsphereClusters, client.InNamespace(namespace)); err != nil {
+		return nil, errors.Wrap(err, "failed to list VSphereClusters")
+	}
+	for i := range vsphereClusters.Items {
+		vsphereCluster := &vsphereClusters.Items[i]
+		if err := check(vsphereCluster, infrav1.GroupVersion.WithKind("VSphereCluster"), client.ObjectKeyFromObject(vsphereCluster)); err != nil {
+			return nil, err
+		}
+		if tlsConfig := vsphereCluster.Spec.TLSConfig; tlsConfig != nil && tlsConfig.CASecretRef != nil {
+			if err := check(vsphereCluster, corev1.SchemeGroupVersion.WithKind("Secret"), client.ObjectKey{Namespace: vsphereCluster.Namespace, Name: tlsConfig.CASecretRef.Name}); err != nil {
+				return nil, err
+			}
+		}
+
+		ref := vsphereCluster.Spec.IdentityRef
+		if ref == nil {
+			continue
+		}
+		switch ref.Kind {
+		case infrav1.SecretKind:
+			if err := check(vsphereCluster, corev1.SchemeGroupVersion.WithKind("Secret"), client.ObjectKey{Namespace: vsphereCluster.Namespace, Name: ref.Name}); err != nil {
+				return nil, err
+			}
+		case infrav1.VSphereClusterIdentityKind:
+			if err := check(vsphereCluster, infrav1.GroupVersion.WithKind("VSphereClusterIdentity"), client.ObjectKey{Name: ref.Name}); err != nil {
+				return nil, err
+			}
+			clusterIdentity := &infrav1.VSphereClusterIdentity{}
+			if err := v.client.Get(ctx, client.ObjectKey{Name: ref.Name}, clusterIdentity); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return nil, errors.Wrapf(err, "failed to get VSphereClusterIdentity %s", ref.Name)
+			}
+			if err := check(clusterIdentity, corev1.SchemeGroupVersion.WithKind("Secret"), client.ObjectKey{Namespace: v.controllerNamespace, Name: clusterIdentity.Spec.SecretName}); err != nil {
+				return nil, err
+			}
+			if tlsConfig := clusterIdentity.Spec.TLSConfig; tlsConfig != nil && tlsConfig.CASecretRef != nil {
+				if err := check(clusterIdentity, corev1.SchemeGroupVersion.WithKind("Secret"), client.ObjectKey{Namespace: v.controllerNamespace, Name: tlsConfig.CASecretRef.Name}); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	vsphereMachines := &infrav1.VSphereMachineList{}
+	if err := v.client.List(ctx, vsphereMachines, client.InNamespace(namespace)); err != nil {
+		return nil, errors.Wrap(err, "failed to list VSphereMachines")
+	}
+	checkedDeploymentZones := map[string]bool{}
+	for i := range vsphereMachines.Items {
+		vsphereMachine := &vsphereMachines.Items[i]
+		if vsphereMachine.Spec.FailureDomain == nil || checkedDeploymentZones[*vsphereMachine.Spec.FailureDomain] {
+			continue
+		}
+		checkedDeploymentZones[*vsphereMachine.Spec.FailureDomain] = true
+
+		if err := check(vsphereMachine, infrav1.GroupVersion.WithKind("VSphereDeploymentZone"), client.ObjectKey{Name: *vsphereMachine.Spec.FailureDomain}); err != nil {
+			return nil, err
+		}
+		deploymentZone := &infrav1.VSphereDeploymentZone{}
+		if err := v.client.Get(ctx, client.ObjectKey{Name: *vsphereMachine.Spec.FailureDomain}, deploymentZone); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to get VSphereDeploymentZone %s", *vsphereMachine.Spec.FailureDomain)
+		}
+		if err := check(deploymentZone, infrav1.GroupVersion.WithKind("VSphereFailureDomain"), client.ObjectKey{Name: deploymentZone.Spec.FailureDomain}); err != nil {
+			return nil, err
+		}
+	}
+
+	ipAddressClaims := &ipamv1.IPAddressClaimList{}
+	if err := v.client.List(ctx, ipAddressClaims, client.InNamespace(namespace)); err != nil {
+		return nil, errors.Wrap(err, "failed to list IPAddressClaims")
+	}
+	for i := range ipAddressClaims.Items {
+		claim := &ipAddressClaims.Items[i]
+		if err := check(claim, ipamv1.GroupVersion.WithKind("IPAddressClaim"), client.ObjectKeyFromObject(claim)); err != nil {
+			return nil, err
+		}
+		poolRef := claim.Spec.PoolRef
+		group := ""
+		if poolRef.APIGroup != nil {
+			group = *poolRef.APIGroup
+		}
+		poolGVK, err := v.gvkForKind(schema.GroupKind{Group: group, Kind: poolRef.Kind})
+		if err != nil {
+			return nil, err
+		}
+		if err := check(claim, poolGVK, client.ObjectKey{Namespace: claim.Namespace, Name: poolRef.Name}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Objects like VSphereClusterIdentities can be referenced by more than one cluster.
+	sort.Slice(findings, func(i, j int) bool {
+		return findings[i].String() < findings[j].String()
+	})
+	return slices.CompactFunc(findings, func(a, b Finding) bool {
+		return a.String() == b.String()
+	}), nil
+}
+
+// loadCRDs loads the CRDs installed in the cluster.
+func (v *Validator) loadCRDs(ctx context.Context) error {
+	crdList := &apiextensionsv1.CustomResourceDefinitionList{}
+	if err := v.client.List(ctx, crdList); err != nil {
+		return errors.Wrap(err, "failed to list CustomResourceDefinitions")
+	}
+	v.crds = map[schema.GroupKind]*apiextensionsv1.CustomResourceDefinition{}
+	for i := range crdList.Items {
+		crd := &crdList.Items[i]
+		v.crds[schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}] = crd
+	}
+	return nil
+}
+
+// gvkForKind returns the GroupVersionKind using the storage version of the CRD of the given kind.
+func (v *Validator) gvkForKind(gk schema.GroupKind) (schema.GroupVersionKind, error) {
+	crd, ok := v.crds[gk]
+	if !ok {
+		return schema.GroupVersionKind{}, errors.Errorf("failed to find CustomResourceDefinition for %s", gk)
+	}
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			return gk.WithVersion(version.Name), nil
+		}
+	}
+	return schema.GroupVersionKind{}, errors.Errorf("failed to find storage version of CustomResourceDefinition %s", crd.Name)
+}
+
+// check returns a Finding if the object does not exist or would not be moved.
+func (v *Validator) check(ctx context.Context, referencedBy client.Object, gvk schema.GroupVersionKind, key client.ObjectKey) (*Finding, error) {
+	referencedByGVK, err := v.client.GroupVersionKindFor(referencedBy)
+	if err != nil {
+		return nil, err
+	}
+	finding := &Finding{
+		Object: corev1.ObjectReference{
+			APIVersion: gvk.GroupVersion().String(),
+			Kind:       gvk.Kind,
+			Namespace:  key.Namespace,
+			Name:       key.Name,
+		},
+		ReferencedBy: corev1.ObjectReference{
+			APIVersion: referencedByGVK.GroupVersion().String(),
+			Kind:       referencedByGVK.Kind,
+			Namespace:  referencedBy.GetNamespace(),
+			Name:       referencedBy.GetName(),
+		},
+	}
+
+	obj, err := v.get(ctx, gvk, key)
+	if err != nil {
+		return nil, err
+	}
+	if obj == nil {
+		finding.Reason = NotFoundReason
+		return finding, nil
+	}
+
+	moved, err := v.isMoved(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	if moved {
+		return nil, nil
+	}
+	finding.Reason = NotMovedReason
+	return finding, nil
+}
+
+// get returns the object or nil if it does not exist.
+func (v *Validator) get(ctx context.Context, gvk schema.GroupVersionKind, key client.ObjectKey) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	if err := v.client.Get(ctx, key, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get %s %s", gvk.Kind, key)
+	}
+	return obj, nil
+}
+
+// isMoved returns true if clusterctl move would move the object.
+func (v *Validator) isMoved(ctx context.Context, obj *unstructured.Unstructured) (bool, error) {
+	if !v.isDiscovered(obj) {
+		return false, nil
+	}
+	if v.hasLabel(obj, clusterctlv1.ClusterctlMoveLabel) {
+		return true, nil
+	}
+	return v.isInHierarchy(ctx, obj, map[string]bool{})
+}
+
+// isDiscovered returns true if clusterctl move considers objects of the kind of obj,
+// i.e. Secrets, ConfigMaps and the kinds defined by CRDs installed by clusterctl.
+func (v *Validator) isDiscovered(obj *unstructured.Unstructured) bool {
+	gk := obj.GroupVersionKind().GroupKind()
+	if gk.Group == "" {
+		return gk.Kind == "Secret" || gk.Kind == "ConfigMap"
+	}
+	crd, ok := v.crds[gk]
+	if !ok {
+		return false
+	}
+	_, ok = crd.Labels[clusterctlv1.ClusterctlLabel]
+	return ok
+}
+
+// hasLabel returns true if either obj or the CRD of its kind has the label.
+func (v *Validator) hasLabel(obj *unstructured.Unstructured, label string) bool {
+	if _, ok := obj.GetLabels()[label]; ok {
+		return true
+	}
+	if crd, ok := v.crds[obj.GroupVersionKind().GroupKind()]; ok {
+		if _, ok := crd.Labels[label]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isInHierarchy returns true if the object is the root or part of a hierarchy moved by clusterctl move.
+func (v *Validator) isInHierarchy(ctx context.Context, obj *unstructured.Unstructured, visited map[string]bool) (bool, error) {
+	key := fmt.Sprintf("%s %s/%s", obj.GroupVersionKind().GroupKind(), obj.GetNamespace(), obj.GetName())
+	if visited[key] {
+		return false, nil
+	}
+	visited[key] = true
+
+	switch obj.GroupVersionKind().GroupKind() {
+	case clusterv1.GroupVersion.WithKind("Cluster").GroupKind(),
+		clusterv1.GroupVersion.WithKind("ClusterClass").GroupKind(),
+		addonsv1.GroupVersion.WithKind("ClusterResourceSet").GroupKind():
+		return true, nil
+	}
+	if v.hasLabel(obj, clusterctlv1.ClusterctlMoveHierarchyLabel) {
+		return true, nil
+	}
+
+	for _, ownerRef := range obj.GetOwnerReferences() {
+		ownerGVK := schema.FromAPIVersionAndKind(ownerRef.APIVersion, ownerRef.Kind)
+		// Owners are either in the same namespace or cluster-scoped.
+		owner, err := v.get(ctx, ownerGVK, client.ObjectKey{Namespace: obj.GetNamespace(), Name: ownerRef.Name})
+		if err != nil {
+			return false, err
+		}
+		if owner == nil && obj.GetNamespace() != "" {
+			if owner, err = v.get(ctx, ownerGVK, client.ObjectKey{Name: ownerRef.Name}); err != nil {
+				return false, err
+			}
+		}
+		if owner == nil || owner.GetUID() != ownerRef.UID || !v.isDiscovered(owner) {
+			continue
+		}
+		inHierarchy, err := v.isInHierarchy(ctx, owner, visited)
+		if err != nil {
+			return false, err
+		}
+		if inHierarchy {
+			return true, nil
+		}
+	}
+
+	// Secrets without owners are soft owned by a Cluster if they follow the naming
+	// convention of Cluster API secrets, e.g. <cluster>-kubeconfig.
+	if obj.GetKind() == "Secret" && len(obj.GetOwnerReferences()) == 0 {
+		clusterName, _, err := secretutil.ParseSecretName(obj.GetName())
+		if err != nil {
+			return false, nil
+		}
+		cluster, err := v.get(ctx, clusterv1.GroupVersion.WithKind("Cluster"), client.ObjectKey{Namespace: obj.GetNamespace(), Name: clusterName})
+		if err != nil {
+			return false, err
+		}
+		return cluster != nil, nil
+	}
+	return false, nil
+}
+
+func objectKey(ref corev1.ObjectReference) string {
+	if ref.Namespace == "" {
+		return ref.Name
+	}
+	return ref.Namespace + "/" + ref.Name
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+const (
+	namespace           = "default"
+	controllerNamespace = "capv-system"
+)
+
+func TestValidator_Validate(t *testing.T) {
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: namespace, UID: "cluster-uid"}}
+	clusterOwnerRef := ownerRef(clusterv1.GroupVersion.String(), "Cluster", cluster.ObjectMeta)
+
+	vsphereCluster := func(modify func(*infrav1.VSphereCluster)) *infrav1.VSphereCluster {
+		c := &infrav1.VSphereCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: namespace, UID: "vspherecluster-uid", OwnerReferences: []metav1.OwnerReference{clusterOwnerRef}},
+		}
+		if modify != nil {
+			modify(c)
+		}
+		return c
+	}
+	clusterIdentity := &infrav1.VSphereClusterIdentity{
+		ObjectMeta: metav1.ObjectMeta{Name: "identity", UID: "identity-uid"},
+		Spec:       infrav1.VSphereClusterIdentitySpec{SecretName: "identity-secret"},
+	}
+	identitySecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name: "identity-secret", Namespace: controllerNamespace,
+		OwnerReferences: []metav1.OwnerReference{ownerRef(infrav1.GroupVersion.String(), "VSphereClusterIdentity", clusterIdentity.ObjectMeta)},
+	}}
+	deploymentZone := &infrav1.VSphereDeploymentZone{
+		ObjectMeta: metav1.ObjectMeta{Name: "zone", UID: "zone-uid"},
+		Spec:       infrav1.VSphereDeploymentZoneSpec{FailureDomain: "fd"},
+	}
+	failureDomain := &infrav1.VSphereFailureDomain{ObjectMeta: metav1.ObjectMeta{
+		Name: "fd", UID: "fd-uid",
+		OwnerReferences: []metav1.OwnerReference{ownerRef(infrav1.GroupVersion.String(), "VSphereDeploymentZone", deploymentZone.ObjectMeta)},
+	}}
+	vsphereMachine := &infrav1.VSphereMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: namespace, OwnerReferences: []metav1.OwnerReference{clusterOwnerRef}},
+		Spec:       infrav1.VSphereMachineSpec{FailureDomain: ptr.To("zone")},
+	}
+	vsphereVM := &infrav1.VSphereVM{ObjectMeta: metav1.ObjectMeta{
+		Name: "vm", Namespace: namespace, UID: "vm-uid", OwnerReferences: []metav1.OwnerReference{clusterOwnerRef},
+	}}
+	ipAddressClaim := &ipamv1.IPAddressClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "claim", Namespace: namespace,
+			OwnerReferences: []metav1.OwnerReference{ownerRef(infrav1.GroupVersion.String(), "VSphereVM", vsphereVM.ObjectMeta)},
+		},
+		Spec: ipamv1.IPAddressClaimSpec{PoolRef: corev1.TypedLocalObjectReference{APIGroup: ptr.To("ipam.cluster.x-k8s.io"), Kind: "IPAddress", Name: "pool"}},
+	}
+	// Note: IPAddress is used as the kind of the pool as it is in the scheme of the fake client.
+	pool := &ipamv1.IPAddress{ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: namespace}}
+
+	capvCRDLabels := map[string]string{clusterctlv1.ClusterctlLabel: ""}
+	globalCRDLabels := map[string]string{clusterctlv1.ClusterctlLabel: "", clusterctlv1.ClusterctlMoveHierarchyLabel: "true"}
+	crds := func(globalLabels map[string]string) []client.Object {
+		return []client.Object{
+			crd(clusterv1.GroupVersion, "Cluster", capvCRDLabels),
+			crd(infrav1.GroupVersion, "VSphereCluster", capvCRDLabels),
+			crd(infrav1.GroupVersion, "VSphereMachine", capvCRDLabels),
+			crd(infrav1.GroupVersion, "VSphereVM", capvCRDLabels),
+			crd(infrav1.GroupVersion, "VSphereClusterIdentity", globalLabels),
+			crd(infrav1.GroupVersion, "VSphereDeploymentZone", globalLabels),
+			crd(infrav1.GroupVersion, "VSphereFailureDomain", globalLabels),
+			crd(ipamv1.GroupVersion, "IPAddressClaim", capvCRDLabels),
+			crd(ipamv1.GroupVersion, "IPAddress", capvCRDLabels),
+		}
+	}
+
+	tests := []struct {
+		name         string
+		objects      []client.Object
+		wantFindings []string
+	}{
+		{
+			name: "all objects are moved",
+			objects: append(crds(globalCRDLabels),
+				cluster,
+				vsphereCluster(func(c *infrav1.VSphereCluster) {
+					c.Spec.IdentityRef = &infrav1.VSphereIdentityReference{Kind: infrav1.VSphereClusterIdentityKind, Name: "identity"}
+				}),
+				clusterIdentity, identitySecret,
+				deploymentZone, failureDomain, vsphereMachine,
+			),
+			wantFindings: []string{},
+		},
+		{
+			name: "global objects are not moved without move-hierarchy label",
+			objects: append(crds(capvCRDLabels),
+				cluster,
+				vsphereCluster(func(c *infrav1.VSphereCluster) {
+					c.Spec.IdentityRef = &infrav1.VSphereIdentityReference{Kind: infrav1.VSphereClusterIdentityKind, Name: "identity"}
+				}),
+				clusterIdentity, identitySecret,
+				deploymentZone, failureDomain, vsphereMachine,
+			),
+			wantFindings: []string{
+				"Secret capv-system/identity-secret referenced by VSphereClusterIdentity identity: NotMoved",
+				"VSphereClusterIdentity identity referenced by VSphereCluster default/cluster: NotMoved",
+				"VSphereDeploymentZone zone referenced by VSphereMachine default/machine: NotMoved",
+				"VSphereFailureDomain fd referenced by VSphereDeploymentZone zone: NotMoved",
+			},
+		},
+		{
+			name: "VSphereCluster not owned by a Cluster",
+			objects: append(crds(globalCRDLabels),
+				vsphereCluster(func(c *infrav1.VSphereCluster) { c.OwnerReferences = nil }),
+			),
+			wantFindings: []string{
+				"VSphereCluster default/cluster: NotMoved",
+			},
+		},
+		{
+			name: "identity and CA secrets",
+			objects: append(crds(globalCRDLabels),
+				cluster,
+				vsphereCluster(func(c *infrav1.VSphereCluster) {
+					c.Spec.IdentityRef = &infrav1.VSphereIdentityReference{Kind: infrav1.SecretKind, Name: "credentials"}
+					c.Spec.TLSConfig = &infrav1.VCenterTLSConfig{CASecretRef: &infrav1.CASecretReference{Name: "vcenter-ca"}}
+				}),
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+					Name: "credentials", Namespace: namespace,
+					OwnerReferences: []metav1.OwnerReference{ownerRef(infrav1.GroupVersion.String(), "VSphereCluster", vsphereCluster(nil).ObjectMeta)},
+				}},
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "vcenter-ca", Namespace: namespace}},
+			),
+			wantFindings: []string{
+				"Secret default/vcenter-ca referenced by VSphereCluster default/cluster: NotMoved",
+			},
+		},
+		{
+			name: "secrets labeled for move or soft owned by the Cluster are moved",
+			objects: append(crds(globalCRDLabels),
+				cluster,
+				vsphereCluster(func(c *infrav1.VSphereCluster) {
+					c.Spec.IdentityRef = &infrav1.VSphereIdentityReference{Kind: infrav1.SecretKind, Name: "cluster-kubeconfig"}
+					c.Spec.TLSConfig = &infrav1.VCenterTLSConfig{CASecretRef: &infrav1.CASecretReference{Name: "vcenter-ca"}}
+				}),
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "cluster-kubeconfig", Namespace: namespace}},
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "vcenter-ca", Namespace: namespace, Labels: map[string]string{clusterctlv1.ClusterctlMoveLabel: ""}}},
+			),
+			wantFindings: []string{},
+		},
+		{
+			name: "missing objects",
+			objects: append(crds(globalCRDLabels),
+				cluster,
+				vsphereCluster(func(c *infrav1.VSphereCluster) {
+					c.Spec.IdentityRef = &infrav1.VSphereIdentityReference{Kind: infrav1.VSphereClusterIdentityKind, Name: "identity"}
+				}),
+				vsphereMachine,
+			),
+			wantFindings: []string{
+				"VSphereClusterIdentity identity referenced by VSphereCluster default/cluster: NotFound",
+				"VSphereDeploymentZone zone referenced by VSphereMachine default/machine: NotFound",
+			},
+		},
+		{
+			name: "IPAddressClaims are moved with the VSphereVM but not their pool",
+			objects: append(crds(globalCRDLabels),
+				cluster, vsphereVM, ipAddressClaim, pool,
+			),
+			wantFindings: []string{
+				"IPAddress default/pool referenced by IPAddressClaim default/claim: NotMoved",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme := runtime.NewScheme()
+			g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			g.Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+			g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+			g.Expect(ipamv1.AddToScheme(scheme)).To(Succeed())
+			g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build()
+
+			findings, err := NewValidator(c, controllerNamespace).Validate(context.Background(), namespace)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			got := []string{}
+			for _, f := range findings {
+				got = append(got, f.String())
+			}
+			g.Expect(got).To(Equal(tt.wantFindings))
+		})
+	}
+}
+
+func ownerRef(apiVersion, kind string, owner metav1.ObjectMeta) metav1.OwnerReference {
+	return metav1.OwnerReference{APIVersion: apiVersion, Kind: kind, Name: owner.Name, UID: owner.UID}
+}
+
+func crd(gv schema.GroupVersion, kind string, labels map[string]string) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: kind + "." + gv.Group, Labels: labels},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group:    gv.Group,
+			Names:    apiextensionsv1.CustomResourceDefinitionNames{Kind: kind},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: gv.Version, Storage: true}},
+		},
+	}
+}
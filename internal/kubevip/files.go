@@ -39,6 +39,23 @@ var (
 	kubeVipPodRaw string
 )
 
+// PrepareScriptPath is the path of the script which has to run before kubeadm on the control plane nodes.
+const PrepareScriptPath = "/etc/pre-kubeadm-commands/50-kube-vip-prepare.sh"
+
+// Config is the configuration of kube-vip for a control plane endpoint known upfront,
+// e.g. allocated from an IPAM pool.
+type Config struct {
+	// Address is the IP address of the control plane endpoint.
+	Address string
+	// Port is the port of the control plane endpoint.
+	Port int32
+	// Interface is the network interface the address is announced on.
+	// If empty, kube-vip uses the interface of the default route.
+	Interface string
+	// Image is the kube-vip image. If empty, the image of the embedded manifest is used.
+	Image string
+}
+
 // Files returns the files required for a control plane node to run kube-vip.
 func Files() []bootstrapv1.File {
 	return []bootstrapv1.File{
@@ -58,15 +75,48 @@ func Files() []bootstrapv1.File {
 		// This file is part of the workaround for https://github.com/kube-vip/kube-vip/issues/684
 		{
 			Owner:       "root:root",
-			Path:        "/etc/pre-kubeadm-commands/50-kube-vip-prepare.sh",
+			Path:        PrepareScriptPath,
 			Permissions: "0700",
 			Content:     kubeVipPrepare,
 		},
 	}
 }
 
+// FilesFor returns the files required for a control plane node to run kube-vip
+// with the given configuration.
+func FilesFor(config Config) []bootstrapv1.File {
+	files := Files()
+	files[0].Content = marshalPod(configuredPod(config))
+	return files
+}
+
 // PodYAML returns the static pod manifest required to run kube-vip.
 func PodYAML() string {
+	return marshalPod(pod())
+}
+
+// configuredPod returns the kube-vip static pod with the variables of the manifest
+// replaced by the values of the configuration.
+func configuredPod(config Config) *corev1.Pod {
+	pod := pod()
+	container := &pod.Spec.Containers[0]
+	if config.Image != "" {
+		container.Image = config.Image
+	}
+	for i := range container.Env {
+		switch container.Env[i].Name {
+		case "address":
+			container.Env[i].Value = config.Address
+		case "port":
+			container.Env[i].Value = fmt.Sprintf("%d", config.Port)
+		case "vip_interface":
+			container.Env[i].Value = config.Interface
+		}
+	}
+	return pod
+}
+
+func pod() *corev1.Pod {
 	pod := &corev1.Pod{}
 
 	if err := yaml.Unmarshal([]byte(kubeVipPodRaw), pod); err != nil {
@@ -101,6 +151,10 @@ func PodYAML() string {
 		},
 	)
 
+	return pod
+}
+
+func marshalPod(pod *corev1.Pod) string {
 	out, err := yaml.Marshal(pod)
 	if err != nil {
 		panic(err)
@@ -188,19 +188,23 @@ func NewTestEnvironment(ctx context.Context) *TestEnvironment {
 		Password:   simr.Password(),
 	}
 	managerOpts.AddToManager = func(_ context.Context, _ *capvcontext.ControllerManagerContext, mgr ctrlmgr.Manager) error {
+		if err := (&webhooks.VSphereClusterWebhook{Client: mgr.GetClient()}).SetupWebhookWithManager(mgr); err != nil {
+			return err
+		}
+
 		if err := (&webhooks.VSphereClusterTemplateWebhook{}).SetupWebhookWithManager(mgr); err != nil {
 			return err
 		}
 
-		if err := (&webhooks.VSphereMachineWebhook{}).SetupWebhookWithManager(mgr); err != nil {
+		if err := (&webhooks.VSphereMachineWebhook{Client: mgr.GetClient()}).SetupWebhookWithManager(mgr); err != nil {
 			return err
 		}
 
-		if err := (&webhooks.VSphereMachineTemplateWebhook{}).SetupWebhookWithManager(mgr); err != nil {
+		if err := (&webhooks.VSphereMachineTemplateWebhook{Client: mgr.GetClient()}).SetupWebhookWithManager(mgr); err != nil {
 			return err
 		}
 
-		if err := (&webhooks.VSphereVMWebhook{}).SetupWebhookWithManager(mgr); err != nil {
+		if err := (&webhooks.VSphereVMWebhook{Client: mgr.GetClient()}).SetupWebhookWithManager(mgr); err != nil {
 			return err
 		}
 
@@ -208,7 +212,19 @@ func NewTestEnvironment(ctx context.Context) *TestEnvironment {
 			return err
 		}
 
-		return (&webhooks.VSphereFailureDomainWebhook{}).SetupWebhookWithManager(mgr)
+		if err := (&webhooks.VSphereFailureDomainWebhook{}).SetupWebhookWithManager(mgr); err != nil {
+			return err
+		}
+
+		if err := (&webhooks.VSpherePlacementPolicyWebhook{}).SetupWebhookWithManager(mgr); err != nil {
+			return err
+		}
+
+		if err := (&webhooks.VSphereForceDeletePolicyWebhook{}).SetupWebhookWithManager(mgr); err != nil {
+			return err
+		}
+
+		return (&webhooks.VSphereVMImageWebhook{}).SetupWebhookWithManager(mgr)
 	}
 
 	mgr, err := manager.New(ctx, managerOpts)
@@ -58,6 +58,26 @@ func ClusterPath(datacenter, cluster int) string {
 	return fmt.Sprintf("/%s/host/%s", DatacenterName(datacenter), ClusterName(datacenter, cluster))
 }
 
+// HostName provide a function to compute vcsim host names given its index and the index of a datacenter and a cluster.
+func HostName(datacenter, cluster, host int) string {
+	return fmt.Sprintf("%s_H%d", ClusterName(datacenter, cluster), host)
+}
+
+// HostGroupName provide a function to compute vcsim host group names given its index and the index of a datacenter and a cluster.
+func HostGroupName(datacenter, cluster, hostGroup int) string {
+	return fmt.Sprintf("%s_HG%d", ClusterName(datacenter, cluster), hostGroup)
+}
+
+// VMGroupName provide a function to compute vcsim VM group names given its index and the index of a datacenter and a cluster.
+func VMGroupName(datacenter, cluster, vmGroup int) string {
+	return fmt.Sprintf("%s_VMG%d", ClusterName(datacenter, cluster), vmGroup)
+}
+
+// VMHostRuleName provide a function to compute vcsim VM-Host affinity rule names given its index and the index of a datacenter and a cluster.
+func VMHostRuleName(datacenter, cluster, rule int) string {
+	return fmt.Sprintf("%s_R%d", ClusterName(datacenter, cluster), rule)
+}
+
 // DatastoreName provide a function to compute vcsim datastore names given its index.
 func DatastoreName(datastore int) string {
 	return fmt.Sprintf("LocalDS_%d", datastore)
@@ -20,6 +20,9 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/util"
 )
 
 // AggregateObjErrors aggregates a list of field errors into a single Invalid API error.
@@ -34,3 +37,12 @@ func AggregateObjErrors(gk schema.GroupKind, name string, allErrs field.ErrorLis
 		allErrs,
 	)
 }
+
+// DeprecationWarnings returns an admission warning for each deprecated field.
+func DeprecationWarnings(deprecated []util.DeprecatedField) admission.Warnings {
+	var warnings admission.Warnings
+	for _, d := range deprecated {
+		warnings = append(warnings, d.Warning())
+	}
+	return warnings
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmware
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	vmwarev1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/vmware/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/internal/webhooks"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-vmware-infrastructure-cluster-x-k8s-io-v1beta1-vspherecluster,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=vmware.infrastructure.cluster.x-k8s.io,resources=vsphereclusters,versions=v1beta1,name=validation.vspherecluster.vmware.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
+
+// VSphereClusterWebhook implements a validation webhook for VSphereCluster.
+type VSphereClusterWebhook struct{}
+
+var _ webhook.CustomValidator = &VSphereClusterWebhook{}
+
+func (webhook *VSphereClusterWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&vmwarev1.VSphereCluster{}).
+		WithValidator(webhook).
+		Complete()
+}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (webhook *VSphereClusterWebhook) ValidateCreate(_ context.Context, raw runtime.Object) (admission.Warnings, error) {
+	typed, ok := raw.(*vmwarev1.VSphereCluster)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a VSphereCluster but got a %T", raw))
+	}
+
+	allErrs := validateVSphereClusterSpec(field.NewPath("spec"), typed.Spec)
+	return nil, webhooks.AggregateObjErrors(typed.GroupVersionKind().GroupKind(), typed.Name, allErrs)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (webhook *VSphereClusterWebhook) ValidateUpdate(_ context.Context, oldRaw runtime.Object, newRaw runtime.Object) (admission.Warnings, error) {
+	newTyped, ok := newRaw.(*vmwarev1.VSphereCluster)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a VSphereCluster but got a %T", newRaw))
+	}
+	oldTyped, ok := oldRaw.(*vmwarev1.VSphereCluster)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a VSphereCluster but got a %T", oldRaw))
+	}
+
+	allErrs := validateVSphereClusterSpec(field.NewPath("spec"), newTyped.Spec)
+
+	// The DNS record is deleted using the hostname in the spec, so changing or removing the
+	// hostname once set would leave the record of the previous hostname behind.
+	if oldDNS := oldTyped.Spec.ControlPlaneEndpointDNS; oldDNS != nil {
+		newDNS := newTyped.Spec.ControlPlaneEndpointDNS
+		if newDNS == nil {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "controlPlaneEndpointDNS"), "cannot be removed once set"))
+		} else if newDNS.Hostname != oldDNS.Hostname {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "controlPlaneEndpointDNS", "hostname"), "cannot be modified"))
+		}
+	}
+
+	return nil, webhooks.AggregateObjErrors(newTyped.GroupVersionKind().GroupKind(), newTyped.Name, allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (webhook *VSphereClusterWebhook) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateVSphereClusterSpec validates the control plane endpoint and its DNS record.
+func validateVSphereClusterSpec(fldPath *field.Path, spec vmwarev1.VSphereClusterSpec) field.ErrorList {
+	var allErrs field.ErrorList
+	if port := spec.ControlPlaneEndpoint.Port; port != 0 {
+		for _, msg := range validation.IsValidPortNum(int(port)) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("controlPlaneEndpoint", "port"), port, msg))
+		}
+	}
+	if dns := spec.ControlPlaneEndpointDNS; dns != nil {
+		for _, msg := range validation.IsDNS1123Subdomain(dns.Hostname) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("controlPlaneEndpointDNS", "hostname"), dns.Hostname, msg))
+		}
+	}
+	return allErrs
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmware
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	vmwarev1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/vmware/v1beta1"
+)
+
+func TestVSphereCluster_ValidateCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    vmwarev1.VSphereClusterSpec
+		wantErr bool
+	}{
+		{
+			name:    "empty spec",
+			wantErr: false,
+		},
+		{
+			name:    "control plane endpoint with a valid port",
+			spec:    vmwarev1.VSphereClusterSpec{ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "10.0.0.1", Port: 6443}},
+			wantErr: false,
+		},
+		{
+			name:    "control plane endpoint with an invalid port",
+			spec:    vmwarev1.VSphereClusterSpec{ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "10.0.0.1", Port: 70000}},
+			wantErr: true,
+		},
+		{
+			name:    "valid DNS hostname",
+			spec:    vmwarev1.VSphereClusterSpec{ControlPlaneEndpointDNS: &vmwarev1.ControlPlaneEndpointDNS{Hostname: "api.cluster.example.com"}},
+			wantErr: false,
+		},
+		{
+			name:    "invalid DNS hostname",
+			spec:    vmwarev1.VSphereClusterSpec{ControlPlaneEndpointDNS: &vmwarev1.ControlPlaneEndpointDNS{Hostname: "api_cluster.example.com"}},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			webhook := &VSphereClusterWebhook{}
+			_, err := webhook.ValidateCreate(context.Background(), &vmwarev1.VSphereCluster{Spec: tc.spec})
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestVSphereCluster_ValidateUpdate(t *testing.T) {
+	dns := func(hostname string) *vmwarev1.ControlPlaneEndpointDNS {
+		return &vmwarev1.ControlPlaneEndpointDNS{Hostname: hostname}
+	}
+	tests := []struct {
+		name    string
+		oldSpec vmwarev1.VSphereClusterSpec
+		newSpec vmwarev1.VSphereClusterSpec
+		wantErr bool
+	}{
+		{
+			name:    "setting the control plane endpoint can be done",
+			newSpec: vmwarev1.VSphereClusterSpec{ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "10.0.0.1", Port: 6443}},
+			wantErr: false,
+		},
+		{
+			name:    "setting the DNS hostname can be done",
+			newSpec: vmwarev1.VSphereClusterSpec{ControlPlaneEndpointDNS: dns("api.cluster.example.com")},
+			wantErr: false,
+		},
+		{
+			name:    "updating the DNS hostname cannot be done",
+			oldSpec: vmwarev1.VSphereClusterSpec{ControlPlaneEndpointDNS: dns("api.cluster.example.com")},
+			newSpec: vmwarev1.VSphereClusterSpec{ControlPlaneEndpointDNS: dns("api.other.example.com")},
+			wantErr: true,
+		},
+		{
+			name:    "removing the DNS record cannot be done",
+			oldSpec: vmwarev1.VSphereClusterSpec{ControlPlaneEndpointDNS: dns("api.cluster.example.com")},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			webhook := &VSphereClusterWebhook{}
+			_, err := webhook.ValidateUpdate(context.Background(), &vmwarev1.VSphereCluster{Spec: tc.oldSpec}, &vmwarev1.VSphereCluster{Spec: tc.newSpec})
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -20,9 +20,16 @@ package vmware
 import (
 	"context"
 	"fmt"
+	"reflect"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -30,6 +37,7 @@ import (
 
 	vmwarev1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/vmware/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-vsphere/internal/webhooks"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/vmoperator"
 )
 
 // +kubebuilder:webhook:verbs=create;update,path=/validate-vmware-infrastructure-cluster-x-k8s-io-v1beta1-vspheremachine,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=vmware.infrastructure.cluster.x-k8s.io,resources=vspheremachines,versions=v1beta1,name=validation.vspheremachine.vmware.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
@@ -50,13 +58,27 @@ func (webhook *VSphereMachineWebhook) SetupWebhookWithManager(mgr ctrl.Manager)
 }
 
 // Default implements webhook.Defaulter so a webhook will be registered for the type.
-func (webhook *VSphereMachineWebhook) Default(_ context.Context, _ runtime.Object) error {
+func (webhook *VSphereMachineWebhook) Default(_ context.Context, obj runtime.Object) error {
+	typed, ok := obj.(*vmwarev1.VSphereMachine)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a VSphereMachine but got a %T", obj))
+	}
+	defaultVolumes(typed.Spec.Volumes)
 	return nil
 }
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
-func (webhook *VSphereMachineWebhook) ValidateCreate(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
-	return nil, nil
+func (webhook *VSphereMachineWebhook) ValidateCreate(_ context.Context, raw runtime.Object) (admission.Warnings, error) {
+	typed, ok := raw.(*vmwarev1.VSphereMachine)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a VSphereMachine but got a %T", raw))
+	}
+
+	allErrs := validateNamingStrategy(field.NewPath("spec", "namingStrategy"), typed.Spec.NamingStrategy)
+	allErrs = append(allErrs, validateVolumes(field.NewPath("spec", "volumes"), typed.Spec)...)
+	allErrs = append(allErrs, validateReadinessProbe(field.NewPath("spec", "readinessProbe"), typed.Spec.ReadinessProbe)...)
+	allErrs = append(allErrs, validateVirtualMachineMetadata(field.NewPath("spec", "virtualMachineMetadata"), typed.Spec.VirtualMachineMetadata)...)
+	return nil, webhooks.AggregateObjErrors(typed.GroupVersionKind().GroupKind(), typed.Name, allErrs)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
@@ -80,6 +102,8 @@ func (webhook *VSphereMachineWebhook) ValidateUpdate(_ context.Context, oldRaw r
 	// - ClassName
 	// - StorageClass
 	// - MinHardwareVersion
+	// - ResourcePolicyName
+	// The NamingStrategy is immutable as well, as the name of the VirtualMachine is derived from it.
 	if newSpec.ImageName != oldSpec.ImageName {
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "imageName"), "cannot be modified"))
 	}
@@ -96,6 +120,18 @@ func (webhook *VSphereMachineWebhook) ValidateUpdate(_ context.Context, oldRaw r
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "minHardwareVersion"), "cannot be modified"))
 	}
 
+	if newSpec.ResourcePolicyName != oldSpec.ResourcePolicyName {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "resourcePolicyName"), "cannot be modified"))
+	}
+
+	if !reflect.DeepEqual(newSpec.NamingStrategy, oldSpec.NamingStrategy) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "namingStrategy"), "cannot be modified"))
+	}
+
+	allErrs = append(allErrs, validateVolumes(field.NewPath("spec", "volumes"), newSpec)...)
+	allErrs = append(allErrs, validateReadinessProbe(field.NewPath("spec", "readinessProbe"), newSpec.ReadinessProbe)...)
+	allErrs = append(allErrs, validateVirtualMachineMetadata(field.NewPath("spec", "virtualMachineMetadata"), newSpec.VirtualMachineMetadata)...)
+
 	return nil, webhooks.AggregateObjErrors(newTyped.GroupVersionKind().GroupKind(), newTyped.Name, allErrs)
 }
 
@@ -103,3 +139,107 @@ func (webhook *VSphereMachineWebhook) ValidateUpdate(_ context.Context, oldRaw r
 func (webhook *VSphereMachineWebhook) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
 	return nil, nil
 }
+
+// defaultVolumes sets the access modes the PVCs of the volumes are created with if not set.
+func defaultVolumes(volumes []vmwarev1.VSphereMachineVolume) {
+	for i := range volumes {
+		if len(volumes[i].AccessModes) == 0 {
+			volumes[i].AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+		}
+	}
+}
+
+// validateNamingStrategy validates that the naming strategy template renders a valid VirtualMachine name.
+func validateNamingStrategy(fldPath *field.Path, namingStrategy *vmwarev1.VirtualMachineNamingStrategy) field.ErrorList {
+	var allErrs field.ErrorList
+	if namingStrategy == nil || namingStrategy.Template == nil {
+		return allErrs
+	}
+
+	templateFldPath := fldPath.Child("template")
+	name, err := vmoperator.GenerateVirtualMachineName("machine", namingStrategy)
+	if err != nil {
+		return append(allErrs, field.Invalid(templateFldPath, *namingStrategy.Template, fmt.Sprintf("invalid VirtualMachine name template: %v", err)))
+	}
+	// Note: This validates that the resulting name is a valid Kubernetes object name.
+	for _, err := range validation.IsDNS1123Subdomain(name) {
+		allErrs = append(allErrs, field.Invalid(templateFldPath, *namingStrategy.Template,
+			fmt.Sprintf("invalid VirtualMachine name template, generated name is not a valid Kubernetes object name: %v", err)))
+	}
+	return allErrs
+}
+
+// validateVolumes validates the volumes of a VSphereMachineSpec.
+func validateVolumes(fldPath *field.Path, spec vmwarev1.VSphereMachineSpec) field.ErrorList {
+	var allErrs field.ErrorList
+	volumeNames := sets.Set[string]{}
+	for i, volume := range spec.Volumes {
+		// The volume name is used as suffix of the name of the PVC.
+		for _, msg := range validation.IsDNS1123Subdomain(volume.Name) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("name"), volume.Name, msg))
+		}
+		if volumeNames.Has(volume.Name) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("name"), volume.Name))
+		}
+		volumeNames.Insert(volume.Name)
+
+		allErrs = append(allErrs, validateVolumeCapacity(fldPath.Index(i).Child("capacity"), volume.Capacity)...)
+		if volume.BootDiskAntiAffinity && (volume.StorageClass == "" || volume.StorageClass == spec.StorageClass) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("storageClass"), volume.StorageClass,
+				"must be set to a storage class different from the storage class of the boot disk if bootDiskAntiAffinity is true"))
+		}
+		for j, accessMode := range volume.AccessModes {
+			switch accessMode {
+			case corev1.ReadWriteOnce, corev1.ReadOnlyMany, corev1.ReadWriteMany, corev1.ReadWriteOncePod:
+			default:
+				allErrs = append(allErrs, field.NotSupported(fldPath.Index(i).Child("accessModes").Index(j), accessMode,
+					[]corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce, corev1.ReadOnlyMany, corev1.ReadWriteMany, corev1.ReadWriteOncePod}))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateVolumeCapacity validates that the capacity requests a positive amount of storage,
+// which is the only resource which can be requested for a PVC.
+func validateVolumeCapacity(fldPath *field.Path, capacity corev1.ResourceList) field.ErrorList {
+	var allErrs field.ErrorList
+	storage, ok := capacity[corev1.ResourceStorage]
+	if !ok {
+		allErrs = append(allErrs, field.Required(fldPath.Key(string(corev1.ResourceStorage)), "must be set"))
+	} else if storage.Cmp(resource.Quantity{}) <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Key(string(corev1.ResourceStorage)), storage.String(), "must be greater than zero"))
+	}
+	for name := range capacity {
+		if name != corev1.ResourceStorage {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Key(string(name)), name, []corev1.ResourceName{corev1.ResourceStorage}))
+		}
+	}
+	return allErrs
+}
+
+// validateReadinessProbe validates that the readiness probe only sets the fields of its type.
+func validateReadinessProbe(fldPath *field.Path, probe *vmwarev1.VirtualMachineReadinessProbe) field.ErrorList {
+	var allErrs field.ErrorList
+	if probe == nil {
+		return allErrs
+	}
+	if probe.Type == vmwarev1.VirtualMachineReadinessProbeTypeGuestHeartbeat && probe.Port != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("port"), "can only be set for readiness probes of type TCP"))
+	}
+	if probe.Type != vmwarev1.VirtualMachineReadinessProbeTypeGuestHeartbeat && probe.GuestHeartbeatThreshold != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("guestHeartbeatThreshold"), "can only be set for readiness probes of type GuestHeartbeat"))
+	}
+	return allErrs
+}
+
+// validateVirtualMachineMetadata validates the labels and annotations propagated to the VirtualMachine.
+func validateVirtualMachineMetadata(fldPath *field.Path, metadata *vmwarev1.VirtualMachineMetadata) field.ErrorList {
+	var allErrs field.ErrorList
+	if metadata == nil {
+		return allErrs
+	}
+	allErrs = append(allErrs, metav1validation.ValidateLabels(metadata.Labels, fldPath.Child("labels"))...)
+	allErrs = append(allErrs, apivalidation.ValidateAnnotations(metadata.Annotations, fldPath.Child("annotations"))...)
+	return allErrs
+}
@@ -21,10 +21,141 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
 
 	vmwarev1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/vmware/v1beta1"
 )
 
+func TestVSphereMachine_ValidateCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		volumes []vmwarev1.VSphereMachineVolume
+		probe   *vmwarev1.VirtualMachineReadinessProbe
+		vmMeta  *vmwarev1.VirtualMachineMetadata
+		nsTmpl  *string
+		wantErr bool
+	}{
+		{
+			name:    "volume with access modes",
+			volumes: []vmwarev1.VSphereMachineVolume{{Name: "etcd", Capacity: storageCapacity("4Gi"), AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOncePod}}},
+			wantErr: false,
+		},
+		{
+			name:    "volume with unsupported access mode",
+			volumes: []vmwarev1.VSphereMachineVolume{{Name: "etcd", Capacity: storageCapacity("4Gi"), AccessModes: []corev1.PersistentVolumeAccessMode{"ReadWriteSometimes"}}},
+			wantErr: true,
+		},
+		{
+			name:    "volume with boot disk anti-affinity and a different storage class",
+			volumes: []vmwarev1.VSphereMachineVolume{{Name: "etcd", Capacity: storageCapacity("4Gi"), StorageClass: "other-storageprofile", BootDiskAntiAffinity: true}},
+			wantErr: false,
+		},
+		{
+			name:    "volume with boot disk anti-affinity and the default storage class",
+			volumes: []vmwarev1.VSphereMachineVolume{{Name: "etcd", Capacity: storageCapacity("4Gi"), BootDiskAntiAffinity: true}},
+			wantErr: true,
+		},
+		{
+			name:    "volume with boot disk anti-affinity and the storage class of the boot disk",
+			volumes: []vmwarev1.VSphereMachineVolume{{Name: "etcd", Capacity: storageCapacity("4Gi"), StorageClass: "wcpglobalstorageprofile", BootDiskAntiAffinity: true}},
+			wantErr: true,
+		},
+		{
+			name:    "volume without a storage capacity",
+			volumes: []vmwarev1.VSphereMachineVolume{{Name: "etcd"}},
+			wantErr: true,
+		},
+		{
+			name:    "volume with a zero storage capacity",
+			volumes: []vmwarev1.VSphereMachineVolume{{Name: "etcd", Capacity: storageCapacity("0")}},
+			wantErr: true,
+		},
+		{
+			name:    "volume with an unsupported capacity resource",
+			volumes: []vmwarev1.VSphereMachineVolume{{Name: "etcd", Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("4Gi"), corev1.ResourceCPU: resource.MustParse("1")}}},
+			wantErr: true,
+		},
+		{
+			name:    "volume with an invalid name",
+			volumes: []vmwarev1.VSphereMachineVolume{{Name: "Etcd_Data", Capacity: storageCapacity("4Gi")}},
+			wantErr: true,
+		},
+		{
+			name:    "volumes with duplicate names",
+			volumes: []vmwarev1.VSphereMachineVolume{{Name: "etcd", Capacity: storageCapacity("4Gi")}, {Name: "etcd", Capacity: storageCapacity("8Gi")}},
+			wantErr: true,
+		},
+		{
+			name:    "valid naming strategy template",
+			nsTmpl:  ptr.To("{{ .machine.name }}-vm"),
+			wantErr: false,
+		},
+		{
+			name:    "naming strategy template rendering an invalid name",
+			nsTmpl:  ptr.To("-{{ .machine.name }}"),
+			wantErr: true,
+		},
+		{
+			name:    "TCP readiness probe with a port",
+			probe:   &vmwarev1.VirtualMachineReadinessProbe{Type: vmwarev1.VirtualMachineReadinessProbeTypeTCP, Port: ptr.To[int32](8443), PeriodSeconds: 30},
+			wantErr: false,
+		},
+		{
+			name:    "TCP readiness probe with a guest heartbeat threshold",
+			probe:   &vmwarev1.VirtualMachineReadinessProbe{Type: vmwarev1.VirtualMachineReadinessProbeTypeTCP, GuestHeartbeatThreshold: vmwarev1.VirtualMachineGuestHeartbeatStatusYellow},
+			wantErr: true,
+		},
+		{
+			name:    "GuestHeartbeat readiness probe with a guest heartbeat threshold",
+			probe:   &vmwarev1.VirtualMachineReadinessProbe{Type: vmwarev1.VirtualMachineReadinessProbeTypeGuestHeartbeat, GuestHeartbeatThreshold: vmwarev1.VirtualMachineGuestHeartbeatStatusYellow},
+			wantErr: false,
+		},
+		{
+			name:    "GuestHeartbeat readiness probe with a port",
+			probe:   &vmwarev1.VirtualMachineReadinessProbe{Type: vmwarev1.VirtualMachineReadinessProbeTypeGuestHeartbeat, Port: ptr.To[int32](8443)},
+			wantErr: true,
+		},
+		{
+			name:    "valid VirtualMachine labels and annotations",
+			vmMeta:  &vmwarev1.VirtualMachineMetadata{Labels: map[string]string{"backup.example.com/policy": "daily"}, Annotations: map[string]string{"monitoring.example.com/config": "{\"scrape\": true}"}},
+			wantErr: false,
+		},
+		{
+			name:    "invalid VirtualMachine label value",
+			vmMeta:  &vmwarev1.VirtualMachineMetadata{Labels: map[string]string{"backup.example.com/policy": "daily backup"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid VirtualMachine annotation key",
+			vmMeta:  &vmwarev1.VirtualMachineMetadata{Annotations: map[string]string{"monitoring.example.com/scrape/port": "9100"}},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			vsphereMachine := createVSphereMachine(nil, "tkgs-imagename", "best-effort-xsmall", "wcpglobalstorageprofile", "vmx-15")
+			vsphereMachine.Spec.Volumes = tc.volumes
+			vsphereMachine.Spec.ReadinessProbe = tc.probe
+			vsphereMachine.Spec.VirtualMachineMetadata = tc.vmMeta
+			if tc.nsTmpl != nil {
+				vsphereMachine.Spec.NamingStrategy = &vmwarev1.VirtualMachineNamingStrategy{Template: tc.nsTmpl}
+			}
+
+			webhook := &VSphereMachineWebhook{}
+			_, err := webhook.ValidateCreate(context.Background(), vsphereMachine)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
 func TestVSphereMachine_ValidateUpdate(t *testing.T) {
 	fakeProviderID := "fake-000000"
 	tests := []struct {
@@ -63,6 +194,24 @@ func TestVSphereMachine_ValidateUpdate(t *testing.T) {
 			vsphereMachine:    createVSphereMachine(nil, "tkgs-imagename", "best-effort-xsmall", "wcpglobalstorageprofile", "vmx-16"),
 			wantErr:           true,
 		},
+		{
+			name:              "updating ResourcePolicyName cannot be done",
+			oldVSphereMachine: createVSphereMachine(nil, "tkgs-imagename", "best-effort-xsmall", "wcpglobalstorageprofile", "vmx-15"),
+			vsphereMachine:    withResourcePolicyName(createVSphereMachine(nil, "tkgs-imagename", "best-effort-xsmall", "wcpglobalstorageprofile", "vmx-15"), "guaranteed"),
+			wantErr:           true,
+		},
+		{
+			name:              "updating VirtualMachineMetadata can be done",
+			oldVSphereMachine: createVSphereMachine(nil, "tkgs-imagename", "best-effort-xsmall", "wcpglobalstorageprofile", "vmx-15"),
+			vsphereMachine:    withVirtualMachineMetadata(createVSphereMachine(nil, "tkgs-imagename", "best-effort-xsmall", "wcpglobalstorageprofile", "vmx-15"), map[string]string{"backup.example.com/policy": "daily"}),
+			wantErr:           false,
+		},
+		{
+			name:              "updating NamingStrategy cannot be done",
+			oldVSphereMachine: createVSphereMachine(nil, "tkgs-imagename", "best-effort-xsmall", "wcpglobalstorageprofile", "vmx-15"),
+			vsphereMachine:    withNamingStrategy(createVSphereMachine(nil, "tkgs-imagename", "best-effort-xsmall", "wcpglobalstorageprofile", "vmx-15"), "{{ .machine.name }}-vm"),
+			wantErr:           true,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -92,3 +241,37 @@ func createVSphereMachine(providerID *string, imageName, className, storageClass
 
 	return vSphereMachine
 }
+
+func withResourcePolicyName(vsphereMachine *vmwarev1.VSphereMachine, resourcePolicyName string) *vmwarev1.VSphereMachine {
+	vsphereMachine.Spec.ResourcePolicyName = resourcePolicyName
+	return vsphereMachine
+}
+
+func withVirtualMachineMetadata(vsphereMachine *vmwarev1.VSphereMachine, labels map[string]string) *vmwarev1.VSphereMachine {
+	vsphereMachine.Spec.VirtualMachineMetadata = &vmwarev1.VirtualMachineMetadata{Labels: labels}
+	return vsphereMachine
+}
+
+func withNamingStrategy(vsphereMachine *vmwarev1.VSphereMachine, template string) *vmwarev1.VSphereMachine {
+	vsphereMachine.Spec.NamingStrategy = &vmwarev1.VirtualMachineNamingStrategy{Template: ptr.To(template)}
+	return vsphereMachine
+}
+
+func storageCapacity(quantity string) corev1.ResourceList {
+	return corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(quantity)}
+}
+
+func TestVSphereMachine_Default(t *testing.T) {
+	g := NewWithT(t)
+
+	vsphereMachine := createVSphereMachine(nil, "tkgs-imagename", "best-effort-xsmall", "wcpglobalstorageprofile", "vmx-15")
+	vsphereMachine.Spec.Volumes = []vmwarev1.VSphereMachineVolume{
+		{Name: "etcd", Capacity: storageCapacity("4Gi")},
+		{Name: "data", Capacity: storageCapacity("4Gi"), AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOncePod}},
+	}
+
+	webhook := &VSphereMachineWebhook{}
+	g.Expect(webhook.Default(context.Background(), vsphereMachine)).To(Succeed())
+	g.Expect(vsphereMachine.Spec.Volumes[0].AccessModes).To(ConsistOf(corev1.ReadWriteOnce))
+	g.Expect(vsphereMachine.Spec.Volumes[1].AccessModes).To(ConsistOf(corev1.ReadWriteOncePod))
+}
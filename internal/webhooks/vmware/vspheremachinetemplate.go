@@ -20,21 +20,23 @@ package vmware
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/cluster-api/util/topology"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	vmwarev1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/vmware/v1beta1"
-	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/vmoperator"
 )
 
 // +kubebuilder:webhook:verbs=create;update,path=/validate-vmware-infrastructure-cluster-x-k8s-io-v1beta1-vspheremachinetemplate,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=vmware.infrastructure.cluster.x-k8s.io,resources=vspheremachinetemplates,versions=v1beta1,name=validation.vspheremachinetemplate.vmware.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 
+const machineTemplateImmutableMsg = "VSphereMachineTemplate spec.template.spec field is immutable. Please create a new resource instead."
+
 // VSphereMachineTemplateWebhook implements a validation webhook for VSphereMachineTemplate.
 type VSphereMachineTemplateWebhook struct{}
 
@@ -57,45 +59,39 @@ func (webhook *VSphereMachineTemplateWebhook) ValidateCreate(ctx context.Context
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
-func (webhook *VSphereMachineTemplateWebhook) ValidateUpdate(ctx context.Context, _, newRaw runtime.Object) (admission.Warnings, error) {
+func (webhook *VSphereMachineTemplateWebhook) ValidateUpdate(ctx context.Context, oldRaw, newRaw runtime.Object) (admission.Warnings, error) {
+	oldVSphereMachineTemplate, ok := oldRaw.(*vmwarev1.VSphereMachineTemplate)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a VSphereMachineTemplate but got a %T", oldRaw))
+	}
 	vSphereMachineTemplate, ok := newRaw.(*vmwarev1.VSphereMachineTemplate)
 	if !ok {
 		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a VSphereMachineTemplate but got a %T", newRaw))
 	}
-	return webhook.validate(ctx, nil, vSphereMachineTemplate)
+	return webhook.validate(ctx, oldVSphereMachineTemplate, vSphereMachineTemplate)
 }
 
-func (webhook *VSphereMachineTemplateWebhook) validate(_ context.Context, _, newVSphereMachineTemplate *vmwarev1.VSphereMachineTemplate) (admission.Warnings, error) {
+func (webhook *VSphereMachineTemplateWebhook) validate(ctx context.Context, oldVSphereMachineTemplate, newVSphereMachineTemplate *vmwarev1.VSphereMachineTemplate) (admission.Warnings, error) {
 	var allErrs field.ErrorList
 
-	// Validate namingStrategy
-	namingStrategy := newVSphereMachineTemplate.Spec.Template.Spec.NamingStrategy
-	if namingStrategy != nil &&
-		namingStrategy.Template != nil {
-		name, err := vmoperator.GenerateVirtualMachineName("machine", namingStrategy)
-		templateFldPath := field.NewPath("spec", "template", "spec", "namingStrategy", "template")
+	// Machines are not updated in place when their template changes, so like in govmomi mode
+	// the spec of the template is immutable, except for the changes applied by ClusterClass.
+	if oldVSphereMachineTemplate != nil {
+		req, err := admission.RequestFromContext(ctx)
 		if err != nil {
-			allErrs = append(allErrs,
-				field.Invalid(
-					templateFldPath,
-					*namingStrategy.Template,
-					fmt.Sprintf("invalid VirtualMachine name template: %v", err),
-				),
-			)
-		} else {
-			// Note: This validates that the resulting name is a valid Kubernetes object name.
-			for _, err := range validation.IsDNS1123Subdomain(name) {
-				allErrs = append(allErrs,
-					field.Invalid(
-						templateFldPath,
-						*namingStrategy.Template,
-						fmt.Sprintf("invalid VirtualMachine name template, generated name is not a valid Kubernetes object name: %v", err),
-					),
-				)
-			}
+			return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a admission.Request inside context: %v", err))
+		}
+		if !topology.ShouldSkipImmutabilityChecks(req, newVSphereMachineTemplate) &&
+			!reflect.DeepEqual(newVSphereMachineTemplate.Spec.Template.Spec, oldVSphereMachineTemplate.Spec.Template.Spec) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "template", "spec"), newVSphereMachineTemplate, machineTemplateImmutableMsg))
 		}
 	}
 
+	allErrs = append(allErrs, validateNamingStrategy(field.NewPath("spec", "template", "spec", "namingStrategy"), newVSphereMachineTemplate.Spec.Template.Spec.NamingStrategy)...)
+	allErrs = append(allErrs, validateVolumes(field.NewPath("spec", "template", "spec", "volumes"), newVSphereMachineTemplate.Spec.Template.Spec)...)
+	allErrs = append(allErrs, validateReadinessProbe(field.NewPath("spec", "template", "spec", "readinessProbe"), newVSphereMachineTemplate.Spec.Template.Spec.ReadinessProbe)...)
+	allErrs = append(allErrs, validateVirtualMachineMetadata(field.NewPath("spec", "template", "spec", "virtualMachineMetadata"), newVSphereMachineTemplate.Spec.Template.Spec.VirtualMachineMetadata)...)
+
 	if len(allErrs) > 0 {
 		return nil, apierrors.NewInvalid(vmwarev1.GroupVersion.WithKind("VSphereMachineTemplate").GroupKind(), newVSphereMachineTemplate.Name, allErrs)
 	}
@@ -21,7 +21,10 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
 	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	vmwarev1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/vmware/v1beta1"
 )
@@ -97,3 +100,70 @@ func TestVSphereMachineTemplate_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestVSphereMachineTemplate_ValidateUpdate(t *testing.T) {
+	tests := []struct {
+		name        string
+		oldTemplate *vmwarev1.VSphereMachineTemplate
+		newTemplate *vmwarev1.VSphereMachineTemplate
+		req         *admission.Request
+		wantErr     bool
+	}{
+		{
+			name:        "unchanged spec can be updated",
+			oldTemplate: createVSphereMachineTemplate("best-effort-xsmall"),
+			newTemplate: createVSphereMachineTemplate("best-effort-xsmall"),
+			req:         &admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{DryRun: ptr.To(false)}},
+			wantErr:     false,
+		},
+		{
+			name:        "changing the spec cannot be done",
+			oldTemplate: createVSphereMachineTemplate("best-effort-xsmall"),
+			newTemplate: createVSphereMachineTemplate("best-effort-small"),
+			req:         &admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{DryRun: ptr.To(false)}},
+			wantErr:     true,
+		},
+		{
+			name:        "changing the spec is allowed for dry run requests by the topology controller",
+			oldTemplate: createVSphereMachineTemplate("best-effort-xsmall"),
+			newTemplate: withTopologyDryRunAnnotation(createVSphereMachineTemplate("best-effort-small")),
+			req:         &admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{DryRun: ptr.To(true)}},
+			wantErr:     false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ctx := context.Background()
+			if tc.req != nil {
+				ctx = admission.NewContextWithRequest(ctx, *tc.req)
+			}
+			webhook := &VSphereMachineTemplateWebhook{}
+			_, err := webhook.ValidateUpdate(ctx, tc.oldTemplate, tc.newTemplate)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func createVSphereMachineTemplate(className string) *vmwarev1.VSphereMachineTemplate {
+	return &vmwarev1.VSphereMachineTemplate{
+		Spec: vmwarev1.VSphereMachineTemplateSpec{
+			Template: vmwarev1.VSphereMachineTemplateResource{
+				Spec: vmwarev1.VSphereMachineSpec{
+					ImageName: "tkgs-imagename",
+					ClassName: className,
+				},
+			},
+		},
+	}
+}
+
+func withTopologyDryRunAnnotation(template *vmwarev1.VSphereMachineTemplate) *vmwarev1.VSphereMachineTemplate {
+	template.Annotations = map[string]string{clusterv1.TopologyDryRunAnnotation: ""}
+	return template
+}
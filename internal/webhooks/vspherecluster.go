@@ -0,0 +1,64 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+// +kubebuilder:webhook:verbs=create,path=/mutate-infrastructure-cluster-x-k8s-io-v1beta1-vspherecluster,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=vsphereclusters,versions=v1beta1,name=default.vspherecluster.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
+
+// VSphereClusterWebhook implements a defaulting webhook for VSphereCluster.
+type VSphereClusterWebhook struct {
+	// Client is used to read the VSphereClusterSettings applied to new VSphereClusters.
+	// If nil, no VSphereClusterSettings are applied.
+	Client client.Reader
+}
+
+var _ webhook.CustomDefaulter = &VSphereClusterWebhook{}
+
+func (webhook *VSphereClusterWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&infrav1.VSphereCluster{}).
+		WithDefaulter(webhook).
+		Complete()
+}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type.
+func (webhook *VSphereClusterWebhook) Default(ctx context.Context, obj runtime.Object) error {
+	typedObj, ok := obj.(*infrav1.VSphereCluster)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a VSphereCluster but got a %T", obj))
+	}
+	settings, err := getClusterSettings(ctx, webhook.Client, typedObj)
+	if err != nil {
+		return err
+	}
+	if settings != nil && typedObj.Spec.IdentityRef == nil && settings.IdentityRef != nil {
+		typedObj.Spec.IdentityRef = settings.IdentityRef.DeepCopy()
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+// getClusterSettings returns the VSphereClusterSettings applying to a new object, merged into a single spec.
+// The settings are only applied when objects are created, because most of the fields they default are immutable.
+// It returns nil if the object is not being created or no settings apply to it.
+func getClusterSettings(ctx context.Context, c client.Reader, obj client.Object) (*infrav1.VSphereClusterSettingsSpec, error) {
+	if c == nil {
+		return nil, nil
+	}
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a admission.Request inside context: %v", err))
+	}
+	if req.Operation != admissionv1.Create {
+		return nil, nil
+	}
+
+	settingsList := &infrav1.VSphereClusterSettingsList{}
+	if err := c.List(ctx, settingsList, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil, errors.Wrapf(err, "failed to list VSphereClusterSettings in namespace %s", obj.GetNamespace())
+	}
+
+	// Settings of the Cluster take precedence over the settings of the namespace.
+	clusterName := obj.GetLabels()[clusterv1.ClusterNameLabel]
+	var clusterSettings, namespaceSettings []infrav1.VSphereClusterSettings
+	for _, settings := range settingsList.Items {
+		switch {
+		case settings.Spec.ClusterName == "":
+			namespaceSettings = append(namespaceSettings, settings)
+		case settings.Spec.ClusterName == clusterName:
+			clusterSettings = append(clusterSettings, settings)
+		}
+	}
+	applicable := slices.Concat(sortSettingsByName(clusterSettings), sortSettingsByName(namespaceSettings))
+	if len(applicable) == 0 {
+		return nil, nil
+	}
+
+	merged := &infrav1.VSphereClusterSettingsSpec{}
+	for _, settings := range applicable {
+		mergeClusterSettings(merged, &settings.Spec)
+	}
+	return merged, nil
+}
+
+func sortSettingsByName(settings []infrav1.VSphereClusterSettings) []infrav1.VSphereClusterSettings {
+	sort.Slice(settings, func(i, j int) bool {
+		return settings[i].Name < settings[j].Name
+	})
+	return settings
+}
+
+// mergeClusterSettings sets the fields of dst which are not set yet from src.
+func mergeClusterSettings(dst, src *infrav1.VSphereClusterSettingsSpec) {
+	if dst.Datastore == "" {
+		dst.Datastore = src.Datastore
+	}
+	if dst.Folder == "" {
+		dst.Folder = src.Folder
+	}
+	if dst.NetworkName == "" {
+		dst.NetworkName = src.NetworkName
+	}
+	if dst.IdentityRef == nil && src.IdentityRef != nil {
+		dst.IdentityRef = src.IdentityRef.DeepCopy()
+	}
+	if src.Hardware == nil {
+		return
+	}
+	if dst.Hardware == nil {
+		dst.Hardware = &infrav1.VSphereClusterHardwareMinimums{}
+	}
+	if dst.Hardware.NumCPUs == 0 {
+		dst.Hardware.NumCPUs = src.Hardware.NumCPUs
+	}
+	if dst.Hardware.MemoryMiB == 0 {
+		dst.Hardware.MemoryMiB = src.Hardware.MemoryMiB
+	}
+	if dst.Hardware.DiskGiB == 0 {
+		dst.Hardware.DiskGiB = src.Hardware.DiskGiB
+	}
+}
+
+// defaultVirtualMachineCloneSpec sets the fields of a VirtualMachineCloneSpec which are not set from the settings,
+// and raises the hardware to the minimums of the settings.
+func defaultVirtualMachineCloneSpec(spec *infrav1.VirtualMachineCloneSpec, settings *infrav1.VSphereClusterSettingsSpec) {
+	if spec.Datastore == "" {
+		spec.Datastore = settings.Datastore
+	}
+	if spec.Folder == "" {
+		spec.Folder = settings.Folder
+	}
+	if settings.NetworkName != "" {
+		for i := range spec.Network.Devices {
+			if spec.Network.Devices[i].NetworkName == "" {
+				spec.Network.Devices[i].NetworkName = settings.NetworkName
+			}
+		}
+	}
+	if settings.Hardware != nil {
+		spec.NumCPUs = max(spec.NumCPUs, settings.Hardware.NumCPUs)
+		spec.MemoryMiB = max(spec.MemoryMiB, settings.Hardware.MemoryMiB)
+		spec.DiskGiB = max(spec.DiskGiB, settings.Hardware.DiskGiB)
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func TestVSphereClusterSettings_Default(t *testing.T) {
+	namespaceSettings := &infrav1.VSphereClusterSettings{
+		ObjectMeta: metav1.ObjectMeta{Name: "defaults", Namespace: "ns"},
+		Spec: infrav1.VSphereClusterSettingsSpec{
+			Datastore:   "ns-datastore",
+			Folder:      "ns-folder",
+			NetworkName: "ns-network",
+			IdentityRef: &infrav1.VSphereIdentityReference{Kind: infrav1.SecretKind, Name: "ns-credentials"},
+			Hardware:    &infrav1.VSphereClusterHardwareMinimums{NumCPUs: 2, MemoryMiB: 4096},
+		},
+	}
+	clusterSettings := &infrav1.VSphereClusterSettings{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-1", Namespace: "ns"},
+		Spec: infrav1.VSphereClusterSettingsSpec{
+			ClusterName: "cluster-1",
+			Datastore:   "cluster-1-datastore",
+			Hardware:    &infrav1.VSphereClusterHardwareMinimums{NumCPUs: 4, DiskGiB: 40},
+		},
+	}
+	otherNamespaceSettings := &infrav1.VSphereClusterSettings{
+		ObjectMeta: metav1.ObjectMeta{Name: "defaults", Namespace: "other"},
+		Spec: infrav1.VSphereClusterSettingsSpec{
+			Datastore: "other-datastore",
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	g := NewWithT(t)
+	g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(namespaceSettings, clusterSettings, otherNamespaceSettings).Build()
+
+	createCtx := admission.NewContextWithRequest(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Create}})
+	updateCtx := admission.NewContextWithRequest(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Update}})
+
+	newVM := func(clusterName string, mutate func(*infrav1.VSphereVM)) *infrav1.VSphereVM {
+		vm := &infrav1.VSphereVM{
+			ObjectMeta: metav1.ObjectMeta{Name: "vm", Namespace: "ns", Labels: map[string]string{}},
+			Spec: infrav1.VSphereVMSpec{
+				VirtualMachineCloneSpec: infrav1.VirtualMachineCloneSpec{
+					OS: infrav1.Linux,
+					Network: infrav1.NetworkSpec{
+						Devices: []infrav1.NetworkDeviceSpec{{}, {NetworkName: "network"}},
+					},
+				},
+			},
+		}
+		if clusterName != "" {
+			vm.Labels[clusterv1.ClusterNameLabel] = clusterName
+		}
+		if mutate != nil {
+			mutate(vm)
+		}
+		return vm
+	}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		client client.Reader
+		vm     *infrav1.VSphereVM
+		want   infrav1.VirtualMachineCloneSpec
+	}{
+		{
+			name:   "no client does not apply settings",
+			ctx:    createCtx,
+			client: nil,
+			vm:     newVM("cluster-1", nil),
+			want:   newVM("cluster-1", nil).Spec.VirtualMachineCloneSpec,
+		},
+		{
+			name:   "update does not apply settings",
+			ctx:    updateCtx,
+			client: c,
+			vm:     newVM("cluster-1", nil),
+			want:   newVM("cluster-1", nil).Spec.VirtualMachineCloneSpec,
+		},
+		{
+			name:   "namespace settings apply to VMs without cluster settings",
+			ctx:    createCtx,
+			client: c,
+			vm:     newVM("cluster-2", nil),
+			want: newVM("", func(vm *infrav1.VSphereVM) {
+				vm.Spec.Datastore = "ns-datastore"
+				vm.Spec.Folder = "ns-folder"
+				vm.Spec.Network.Devices[0].NetworkName = "ns-network"
+				vm.Spec.NumCPUs = 2
+				vm.Spec.MemoryMiB = 4096
+			}).Spec.VirtualMachineCloneSpec,
+		},
+		{
+			name:   "cluster settings take precedence over namespace settings",
+			ctx:    createCtx,
+			client: c,
+			vm:     newVM("cluster-1", nil),
+			want: newVM("", func(vm *infrav1.VSphereVM) {
+				vm.Spec.Datastore = "cluster-1-datastore"
+				vm.Spec.Folder = "ns-folder"
+				vm.Spec.Network.Devices[0].NetworkName = "ns-network"
+				vm.Spec.NumCPUs = 4
+				vm.Spec.MemoryMiB = 4096
+				vm.Spec.DiskGiB = 40
+			}).Spec.VirtualMachineCloneSpec,
+		},
+		{
+			name:   "fields which are set are not changed, hardware is raised to the minimums",
+			ctx:    createCtx,
+			client: c,
+			vm: newVM("cluster-1", func(vm *infrav1.VSphereVM) {
+				vm.Spec.Datastore = "datastore"
+				vm.Spec.NumCPUs = 8
+				vm.Spec.MemoryMiB = 2048
+			}),
+			want: newVM("", func(vm *infrav1.VSphereVM) {
+				vm.Spec.Datastore = "datastore"
+				vm.Spec.Folder = "ns-folder"
+				vm.Spec.Network.Devices[0].NetworkName = "ns-network"
+				vm.Spec.NumCPUs = 8
+				vm.Spec.MemoryMiB = 4096
+				vm.Spec.DiskGiB = 40
+			}).Spec.VirtualMachineCloneSpec,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			webhook := &VSphereVMWebhook{Client: tt.client}
+			g.Expect(webhook.Default(tt.ctx, tt.vm)).To(Succeed())
+			g.Expect(tt.vm.Spec.VirtualMachineCloneSpec).To(Equal(tt.want))
+		})
+	}
+
+	t.Run("VSphereMachineTemplate", func(t *testing.T) {
+		g := NewWithT(t)
+		template := &infrav1.VSphereMachineTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "template", Namespace: "ns", Labels: map[string]string{clusterv1.ClusterNameLabel: "cluster-1"}},
+		}
+		webhook := &VSphereMachineTemplateWebhook{Client: c}
+		g.Expect(webhook.Default(createCtx, template)).To(Succeed())
+		g.Expect(template.Spec.Template.Spec.Datastore).To(Equal("cluster-1-datastore"))
+		g.Expect(template.Spec.Template.Spec.Folder).To(Equal("ns-folder"))
+		g.Expect(template.Spec.Template.Spec.NumCPUs).To(Equal(int32(4)))
+	})
+
+	t.Run("VSphereCluster", func(t *testing.T) {
+		g := NewWithT(t)
+		cluster := &infrav1.VSphereCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-1", Namespace: "ns"},
+		}
+		webhook := &VSphereClusterWebhook{Client: c}
+		g.Expect(webhook.Default(createCtx, cluster)).To(Succeed())
+		g.Expect(cluster.Spec.IdentityRef).To(Equal(namespaceSettings.Spec.IdentityRef))
+
+		cluster = &infrav1.VSphereCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-1", Namespace: "other"},
+		}
+		g.Expect(webhook.Default(createCtx, cluster)).To(Succeed())
+		g.Expect(cluster.Spec.IdentityRef).To(BeNil())
+	})
+}
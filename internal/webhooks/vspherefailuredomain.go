@@ -30,6 +30,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/util"
 )
 
 // +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-vspherefailuredomain,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=vspherefailuredomains,versions=v1beta1,name=validation.vspherefailuredomain.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
@@ -81,13 +82,17 @@ func (webhook *VSphereFailureDomainWebhook) ValidateCreate(_ context.Context, ra
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "topology", "networks"), "cannot be set if spec.topology.networkConfigurations is already set"))
 	}
 
+	if obj.Spec.Topology.VSAN != nil && obj.Spec.Topology.Hosts == nil {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec", "topology", "hosts"), "must be set if spec.topology.vsan is set"))
+	}
+
 	for i, networkConfig := range obj.Spec.Topology.NetworkConfigurations {
 		if networkConfig.NetworkName == "" {
 			allErrs = append(allErrs, field.Required(field.NewPath("spec", "topology", "networkConfigurations").Index(i).Child("networkName"), "cannot be empty"))
 		}
 	}
 
-	return nil, AggregateObjErrors(obj.GroupVersionKind().GroupKind(), obj.Name, allErrs)
+	return DeprecationWarnings(util.VSphereFailureDomainDeprecatedFields(obj)), AggregateObjErrors(obj.GroupVersionKind().GroupKind(), obj.Name, allErrs)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
@@ -103,7 +108,7 @@ func (webhook *VSphereFailureDomainWebhook) ValidateUpdate(_ context.Context, ol
 	if !reflect.DeepEqual(newTyped.Spec, oldTyped.Spec) {
 		return nil, field.Forbidden(field.NewPath("spec"), "VSphereFailureDomainSpec is immutable")
 	}
-	return nil, nil
+	return DeprecationWarnings(util.VSphereFailureDomainDeprecatedFields(newTyped)), nil
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
@@ -153,6 +153,51 @@ func TestVSphereFailureDomain_ValidateCreate(t *testing.T) {
 				},
 			}},
 		},
+		{
+			name: "topology.vsan set but topology.hosts is not set",
+			failureDomain: infrav1.VSphereFailureDomain{Spec: infrav1.VSphereFailureDomainSpec{
+				Region: infrav1.FailureDomain{
+					Name:        "foo",
+					Type:        infrav1.DatacenterFailureDomain,
+					TagCategory: "k8s-bar",
+				},
+				Zone: infrav1.FailureDomain{
+					Name:        "foo",
+					Type:        infrav1.ComputeClusterFailureDomain,
+					TagCategory: "k8s-bar",
+				},
+				Topology: infrav1.Topology{
+					Datacenter:     "/blah",
+					ComputeCluster: ptr.To("blah2"),
+					VSAN:           &infrav1.VSANTopology{FaultDomain: "preferred"},
+				},
+			}},
+		},
+		{
+			name: "topology.vsan and topology.hosts set",
+			failureDomain: infrav1.VSphereFailureDomain{Spec: infrav1.VSphereFailureDomainSpec{
+				Region: infrav1.FailureDomain{
+					Name:        "foo",
+					Type:        infrav1.DatacenterFailureDomain,
+					TagCategory: "k8s-bar",
+				},
+				Zone: infrav1.FailureDomain{
+					Name:        "foo",
+					Type:        infrav1.HostGroupFailureDomain,
+					TagCategory: "k8s-bar",
+				},
+				Topology: infrav1.Topology{
+					Datacenter:     "/blah",
+					ComputeCluster: ptr.To("blah2"),
+					Hosts: &infrav1.FailureDomainHosts{
+						VMGroupName:   "vm-foo",
+						HostGroupName: "host-foo",
+					},
+					VSAN: &infrav1.VSANTopology{FaultDomain: "preferred"},
+				},
+			}},
+			errExpected: ptr.To(false),
+		},
 	}
 
 	for _, tt := range tests {
@@ -169,3 +214,41 @@ func TestVSphereFailureDomain_ValidateCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestVSphereFailureDomain_DeprecationWarnings(t *testing.T) {
+	g := NewWithT(t)
+
+	failureDomain := &infrav1.VSphereFailureDomain{Spec: infrav1.VSphereFailureDomainSpec{
+		Region: infrav1.FailureDomain{
+			Name:          "foo",
+			Type:          infrav1.DatacenterFailureDomain,
+			TagCategory:   "k8s-region",
+			AutoConfigure: ptr.To(true),
+		},
+		Zone: infrav1.FailureDomain{
+			Name:          "bar",
+			Type:          infrav1.ComputeClusterFailureDomain,
+			TagCategory:   "k8s-zone",
+			AutoConfigure: ptr.To(false),
+		},
+		Topology: infrav1.Topology{
+			Datacenter:     "/blah",
+			ComputeCluster: ptr.To("blah2"),
+		},
+	}}
+
+	webhook := &VSphereFailureDomainWebhook{}
+	warnings, err := webhook.ValidateCreate(context.Background(), failureDomain)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(warnings).To(HaveLen(1))
+	g.Expect(warnings[0]).To(ContainSubstring("spec.region.autoConfigure is deprecated"))
+
+	warnings, err = webhook.ValidateUpdate(context.Background(), failureDomain, failureDomain.DeepCopy())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(warnings).To(HaveLen(1))
+
+	failureDomain.Spec.Region.AutoConfigure = nil
+	warnings, err = webhook.ValidateCreate(context.Background(), failureDomain)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(warnings).To(BeEmpty())
+}
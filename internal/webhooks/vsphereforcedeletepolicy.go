@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-vsphereforcedeletepolicy,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=vsphereforcedeletepolicies,versions=v1beta1,name=validation.vsphereforcedeletepolicy.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
+
+// VSphereForceDeletePolicyWebhook implements a validation webhook for VSphereForceDeletePolicy.
+type VSphereForceDeletePolicyWebhook struct{}
+
+var _ webhook.CustomValidator = &VSphereForceDeletePolicyWebhook{}
+
+func (webhook *VSphereForceDeletePolicyWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&infrav1.VSphereForceDeletePolicy{}).
+		WithValidator(webhook).
+		Complete()
+}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (webhook *VSphereForceDeletePolicyWebhook) ValidateCreate(_ context.Context, raw runtime.Object) (admission.Warnings, error) {
+	obj, ok := raw.(*infrav1.VSphereForceDeletePolicy)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a VSphereForceDeletePolicy but got a %T", raw))
+	}
+	return nil, AggregateObjErrors(obj.GroupVersionKind().GroupKind(), obj.Name, validateForceDeletePolicy(obj))
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (webhook *VSphereForceDeletePolicyWebhook) ValidateUpdate(_ context.Context, _ runtime.Object, newRaw runtime.Object) (admission.Warnings, error) {
+	newTyped, ok := newRaw.(*infrav1.VSphereForceDeletePolicy)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a VSphereForceDeletePolicy but got a %T", newRaw))
+	}
+	return nil, AggregateObjErrors(newTyped.GroupVersionKind().GroupKind(), newTyped.Name, validateForceDeletePolicy(newTyped))
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (webhook *VSphereForceDeletePolicyWebhook) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateForceDeletePolicy(obj *infrav1.VSphereForceDeletePolicy) field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if _, err := metav1.LabelSelectorAsSelector(&obj.Spec.NamespaceSelector); err != nil {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("namespaceSelector"), obj.Spec.NamespaceSelector, err.Error()))
+	}
+	if _, err := metav1.LabelSelectorAsSelector(&obj.Spec.ClusterSelector); err != nil {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("clusterSelector"), obj.Spec.ClusterSelector, err.Error()))
+	}
+	if obj.Spec.VCenterUnreachableTimeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("vCenterUnreachableTimeout"), obj.Spec.VCenterUnreachableTimeout.Duration.String(), "must be greater than 0"))
+	}
+	return allErrs
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func TestVSphereForceDeletePolicy_ValidateCreate(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      infrav1.VSphereForceDeletePolicySpec
+		wantError bool
+	}{
+		{
+			name: "valid policy",
+			spec: infrav1.VSphereForceDeletePolicySpec{
+				NamespaceSelector:         metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+				ClusterSelector:           metav1.LabelSelector{MatchLabels: map[string]string{"env": "dev"}},
+				VCenterUnreachableTimeout: metav1.Duration{Duration: 4 * time.Hour},
+			},
+		},
+		{
+			name: "invalid cluster selector",
+			spec: infrav1.VSphereForceDeletePolicySpec{
+				ClusterSelector:           metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "env", Operator: "Foo"}}},
+				VCenterUnreachableTimeout: metav1.Duration{Duration: 4 * time.Hour},
+			},
+			wantError: true,
+		},
+		{
+			name:      "missing timeout",
+			spec:      infrav1.VSphereForceDeletePolicySpec{},
+			wantError: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			policy := &infrav1.VSphereForceDeletePolicy{ObjectMeta: metav1.ObjectMeta{Name: "policy"}, Spec: tc.spec}
+			webhook := &VSphereForceDeletePolicyWebhook{}
+			_, err := webhook.ValidateCreate(context.Background(), policy)
+			if tc.wantError {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
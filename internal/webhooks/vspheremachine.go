@@ -21,23 +21,32 @@ import (
 	"fmt"
 	"net"
 	"reflect"
+	"sort"
 
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/govmomi/extra"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/util"
 )
 
 // +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-vspheremachine,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=vspheremachines,versions=v1beta1,name=validation.vspheremachine.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 // +kubebuilder:webhook:verbs=create;update,path=/mutate-infrastructure-cluster-x-k8s-io-v1beta1-vspheremachine,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=vspheremachines,versions=v1beta1,name=default.vspheremachine.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 
 // VSphereMachineWebhook implements a validation and defaulting webhook for VSphereMachine.
-type VSphereMachineWebhook struct{}
+type VSphereMachineWebhook struct {
+	// Client is used to read the VSpherePlacementPolicies VSphereMachines are validated against.
+	// If nil, no VSpherePlacementPolicies are enforced.
+	Client client.Reader
+}
 
 var _ webhook.CustomValidator = &VSphereMachineWebhook{}
 var _ webhook.CustomDefaulter = &VSphereMachineWebhook{}
@@ -63,7 +72,7 @@ func (webhook *VSphereMachineWebhook) Default(_ context.Context, obj runtime.Obj
 }
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
-func (webhook *VSphereMachineWebhook) ValidateCreate(_ context.Context, raw runtime.Object) (admission.Warnings, error) {
+func (webhook *VSphereMachineWebhook) ValidateCreate(ctx context.Context, raw runtime.Object) (admission.Warnings, error) {
 	var allErrs field.ErrorList
 
 	obj, ok := raw.(*infrav1.VSphereMachine)
@@ -83,6 +92,8 @@ func (webhook *VSphereMachineWebhook) ValidateCreate(_ context.Context, raw runt
 			}
 		}
 	}
+	allErrs = append(allErrs, validateNetworkBondsAndVLANs(field.NewPath("spec", "network", "devices"), spec.Network.Devices)...)
+	allErrs = append(allErrs, validateGuestNetworkSettings(field.NewPath("spec", "network"), spec.Network)...)
 
 	if spec.GuestSoftPowerOffTimeout != nil {
 		if spec.PowerOffMode != infrav1.VirtualMachinePowerOpModeTrySoft {
@@ -92,16 +103,34 @@ func (webhook *VSphereMachineWebhook) ValidateCreate(_ context.Context, raw runt
 			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "guestSoftPowerOffTimeout"), spec.GuestSoftPowerOffTimeout, "should be greater than 0"))
 		}
 	}
+	if spec.Encryption != nil && spec.CloneMode == infrav1.LinkedClone {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "encryption"), spec.Encryption, "cannot be set when cloneMode is linkedClone"))
+	}
 	pciErrs := validatePCIDevices(spec.PciDevices)
 	allErrs = append(allErrs, pciErrs...)
+	allErrs = append(allErrs, validateDataDisks(field.NewPath("spec", "dataDisks"), spec.DataDisks)...)
+	allErrs = append(allErrs, validateVirtualMachineResources(field.NewPath("spec", "resources"), &spec.VirtualMachineCloneSpec)...)
+	allErrs = append(allErrs, validateCustomVMXKeys(field.NewPath("spec", "customVMXKeys"), spec.CustomVMXKeys)...)
+	allErrs = append(allErrs, validateVSphereVMNamingStrategy(field.NewPath("spec", "namingStrategy"), spec.NamingStrategy)...)
+	allErrs = append(allErrs, validateImageRef(field.NewPath("spec"), &spec.VirtualMachineCloneSpec)...)
+
+	placementErrs, err := validatePlacementPolicies(ctx, webhook.Client, obj.Namespace, field.NewPath("spec"), &spec.VirtualMachineCloneSpec, nil)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	allErrs = append(allErrs, placementErrs...)
 
 	return nil, AggregateObjErrors(obj.GroupVersionKind().GroupKind(), obj.Name, allErrs)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
-func (webhook *VSphereMachineWebhook) ValidateUpdate(_ context.Context, oldRaw runtime.Object, newRaw runtime.Object) (admission.Warnings, error) {
+func (webhook *VSphereMachineWebhook) ValidateUpdate(ctx context.Context, oldRaw runtime.Object, newRaw runtime.Object) (admission.Warnings, error) {
 	var allErrs field.ErrorList
 
+	oldTyped, ok := oldRaw.(*infrav1.VSphereMachine)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a VSphereMachine but got a %T", oldRaw))
+	}
 	newTyped, ok := newRaw.(*infrav1.VSphereMachine)
 	if !ok {
 		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a VSphereMachine but got a %T", newRaw))
@@ -128,7 +157,11 @@ func (webhook *VSphereMachineWebhook) ValidateUpdate(_ context.Context, oldRaw r
 	newVSphereMachineSpec := newVSphereMachine["spec"].(map[string]interface{})
 	oldVSphereMachineSpec := oldVSphereMachine["spec"].(map[string]interface{})
 
-	allowChangeKeys := []string{"providerID", "powerOffMode", "guestSoftPowerOffTimeout"}
+	allowChangeKeys := []string{"providerID", "powerOffMode", "guestSoftPowerOffTimeout", "customAttributes", "allowInPlaceResize", "questionAnswers", "networkDeviceHotPlugPolicy", "customVMXKeys"}
+	// Allow changes to the CPU and memory if they can be resized in place.
+	if newTyped.Spec.AllowInPlaceResize {
+		allowChangeKeys = append(allowChangeKeys, "numCPUs", "memoryMiB")
+	}
 	for _, key := range allowChangeKeys {
 		delete(oldVSphereMachineSpec, key)
 		delete(newVSphereMachineSpec, key)
@@ -151,11 +184,24 @@ func (webhook *VSphereMachineWebhook) ValidateUpdate(_ context.Context, oldRaw r
 		}
 	}
 
+	// Keys managed by CAPV which were set before they were rejected are tolerated until changed.
+	if !reflect.DeepEqual(oldTyped.Spec.CustomVMXKeys, newTyped.Spec.CustomVMXKeys) {
+		allErrs = append(allErrs, validateCustomVMXKeys(field.NewPath("spec", "customVMXKeys"), newTyped.Spec.CustomVMXKeys)...)
+	}
+
 	if !reflect.DeepEqual(oldVSphereMachineSpec, newVSphereMachineSpec) {
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "cannot be modified"))
 	}
 
-	return nil, AggregateObjErrors(newTyped.GroupVersionKind().GroupKind(), newTyped.Name, allErrs)
+	// Network devices may be appended, which have to comply with the VSpherePlacementPolicies.
+	placementErrs, err := validatePlacementPolicies(ctx, webhook.Client, newTyped.Namespace, field.NewPath("spec"), &newTyped.Spec.VirtualMachineCloneSpec, &oldTyped.Spec.VirtualMachineCloneSpec)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	allErrs = append(allErrs, placementErrs...)
+
+	warnings := DeprecationWarnings(util.NetworkSpecDeprecatedFields(field.NewPath("spec", "network"), newTyped.Spec.Network))
+	return warnings, AggregateObjErrors(newTyped.GroupVersionKind().GroupKind(), newTyped.Name, allErrs)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
@@ -163,6 +209,22 @@ func (webhook *VSphereMachineWebhook) ValidateDelete(_ context.Context, _ runtim
 	return nil, nil
 }
 
+// validateVSphereVMNamingStrategy validates that the template of the naming strategy generates a valid VSphereVM name.
+func validateVSphereVMNamingStrategy(fldPath *field.Path, namingStrategy *infrav1.VSphereVMNamingStrategy) field.ErrorList {
+	if namingStrategy == nil || namingStrategy.Template == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	name, err := util.GenerateVSphereVMName("machine", namingStrategy)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("template"), *namingStrategy.Template, fmt.Sprintf("invalid VSphereVM name template: %v", err)))
+	} else if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("template"), *namingStrategy.Template, fmt.Sprintf("invalid VSphereVM name template, generated name %q is not a valid DNS-1123 subdomain: %v", name, errs)))
+	}
+	return allErrs
+}
+
 func validatePCIDevices(devices []infrav1.PCIDeviceSpec) field.ErrorList {
 	var allErrs field.ErrorList
 
@@ -179,3 +241,75 @@ func validatePCIDevices(devices []infrav1.PCIDeviceSpec) field.ErrorList {
 	}
 	return allErrs
 }
+
+// validateCustomVMXKeys validates that the custom VMX keys don't override the keys of the
+// extraConfig managed by CAPV, e.g. the bootstrap data.
+func validateCustomVMXKeys(fldPath *field.Path, customVMXKeys map[string]string) field.ErrorList {
+	var allErrs field.ErrorList
+
+	keys := make([]string, 0, len(customVMXKeys))
+	for key := range customVMXKeys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if extra.IsReservedKey(key) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Key(key), "is managed by CAPV and cannot be set"))
+		}
+	}
+	return allErrs
+}
+
+// validateDataDisks validates that every data disk either has a size or references an
+// existing First Class Disk, and that no First Class Disk is referenced twice.
+func validateDataDisks(fldPath *field.Path, disks []infrav1.VSphereDisk) field.ErrorList {
+	var allErrs field.ErrorList
+
+	fcdIDs := map[string]bool{}
+	for i, disk := range disks {
+		if disk.ExistingFCD == nil {
+			if disk.SizeGiB <= 0 {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("sizeGiB"), disk.SizeGiB, "should be greater than 0 unless existingFCD is set"))
+			}
+			continue
+		}
+		if fcdIDs[disk.ExistingFCD.ID] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("existingFCD", "id"), disk.ExistingFCD.ID))
+		}
+		fcdIDs[disk.ExistingFCD.ID] = true
+	}
+	return allErrs
+}
+
+// validateVirtualMachineResources validates that the resource allocation of the virtual machine
+// can be applied, as vCenter only rejects inconsistent allocations when the VM is cloned.
+func validateVirtualMachineResources(fldPath *field.Path, spec *infrav1.VirtualMachineCloneSpec) field.ErrorList {
+	var allErrs field.ErrorList
+	resources := spec.Resources
+	if resources == nil {
+		return allErrs
+	}
+
+	allErrs = append(allErrs, validateVirtualMachineResourceAllocation(fldPath.Child("cpu"), resources.CPU)...)
+	allErrs = append(allErrs, validateVirtualMachineResourceAllocation(fldPath.Child("memory"), resources.Memory)...)
+	if resources.Memory != nil && resources.Memory.Reservation > 0 {
+		if resources.MemoryReservationLockedToMax {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("memory", "reservation"), resources.Memory.Reservation, "cannot be set when memoryReservationLockedToMax is true"))
+		}
+		if spec.MemoryMiB > 0 && resources.Memory.Reservation > spec.MemoryMiB {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("memory", "reservation"), resources.Memory.Reservation, "cannot be greater than memoryMiB"))
+		}
+	}
+	return allErrs
+}
+
+func validateVirtualMachineResourceAllocation(fldPath *field.Path, allocation *infrav1.VirtualMachineResourceAllocation) field.ErrorList {
+	var allErrs field.ErrorList
+	if allocation == nil || allocation.Limit == nil {
+		return allErrs
+	}
+	if allocation.Reservation > *allocation.Limit {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("reservation"), allocation.Reservation, "cannot be greater than limit"))
+	}
+	return allErrs
+}
@@ -111,6 +111,69 @@ func TestVSphereMachine_ValidateCreate(t *testing.T) {
 			name:           "successful VSphereMachine creation with vgpu",
 			vsphereMachine: createVSphereMachine("foo.com", nil, "", []string{"192.168.0.1/32", "192.168.0.3/32"}, infrav1.VirtualMachinePowerOpModeTrySoft, nil, []infrav1.PCIDeviceSpec{{VGPUProfile: "vgpu"}}),
 		},
+		{
+			name: "successful VSphereMachine creation with imageRef",
+			vsphereMachine: func() *infrav1.VSphereMachine {
+				m := createVSphereMachine("foo.com", nil, "", []string{"192.168.0.1/32"}, infrav1.VirtualMachinePowerOpModeTrySoft, nil, nil)
+				m.Spec.Template = ""
+				m.Spec.ImageRef = &infrav1.VSphereVMImageReference{Name: "ubuntu-2204-kube-v1.31.0"}
+				return m
+			}(),
+		},
+		{
+			name: "neither template nor imageRef set",
+			vsphereMachine: func() *infrav1.VSphereMachine {
+				m := createVSphereMachine("foo.com", nil, "", []string{"192.168.0.1/32"}, infrav1.VirtualMachinePowerOpModeTrySoft, nil, nil)
+				m.Spec.Template = ""
+				return m
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "both template and imageRef set",
+			vsphereMachine: func() *infrav1.VSphereMachine {
+				m := createVSphereMachine("foo.com", nil, "", []string{"192.168.0.1/32"}, infrav1.VirtualMachinePowerOpModeTrySoft, nil, nil)
+				m.Spec.ImageRef = &infrav1.VSphereVMImageReference{Name: "ubuntu-2204-kube-v1.31.0"}
+				return m
+			}(),
+			wantErr: true,
+		},
+		{
+			name:           "successful VSphereMachine creation with customVMXKeys",
+			vsphereMachine: withMachineCustomVMXKeys(createVSphereMachine("foo.com", nil, "", []string{"192.168.0.1/32"}, infrav1.VirtualMachinePowerOpModeTrySoft, nil, nil), map[string]string{"disk.EnableUUID": "TRUE"}),
+		},
+		{
+			name:           "customVMXKeys overriding the bootstrap data",
+			vsphereMachine: withMachineCustomVMXKeys(createVSphereMachine("foo.com", nil, "", []string{"192.168.0.1/32"}, infrav1.VirtualMachinePowerOpModeTrySoft, nil, nil), map[string]string{"guestinfo.userdata": "foo"}),
+			wantErr:        true,
+		},
+		{
+			name: "encryption set with cloneMode linkedClone",
+			vsphereMachine: func() *infrav1.VSphereMachine {
+				m := createVSphereMachine("foo.com", nil, "", []string{"192.168.0.1/32"}, infrav1.VirtualMachinePowerOpModeTrySoft, nil, nil)
+				m.Spec.CloneMode = infrav1.LinkedClone
+				m.Spec.Encryption = &infrav1.VirtualMachineEncryptionSpec{StoragePolicyName: "vm-encryption-policy"}
+				return m
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "VLAN IPs are not in CIDR format",
+			vsphereMachine: func() *infrav1.VSphereMachine {
+				m := createVSphereMachine("foo.com", nil, "", []string{"192.168.0.1/32"}, infrav1.VirtualMachinePowerOpModeTrySoft, nil, nil)
+				m.Spec.Network.Devices[0].VLANs = []infrav1.NetworkVLANSpec{{ID: 100, IPAddrs: []string{"10.0.100.10"}}}
+				return m
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "successful VSphereMachine creation with encryption",
+			vsphereMachine: func() *infrav1.VSphereMachine {
+				m := createVSphereMachine("foo.com", nil, "", []string{"192.168.0.1/32"}, infrav1.VirtualMachinePowerOpModeTrySoft, nil, nil)
+				m.Spec.Encryption = &infrav1.VirtualMachineEncryptionSpec{StoragePolicyName: "vm-encryption-policy"}
+				return m
+			}(),
+		},
 		{
 			name:           "successful VSphereMachine creation",
 			vsphereMachine: createVSphereMachine("foo.com", nil, "", []string{"192.168.0.1/32", "192.168.0.3/32"}, infrav1.VirtualMachinePowerOpModeTrySoft, nil, nil),
@@ -131,6 +194,70 @@ func TestVSphereMachine_ValidateCreate(t *testing.T) {
 			vsphereMachine: createVSphereMachine("foo.com", nil, "", []string{"192.168.0.1/32", "192.168.0.3/32"}, infrav1.VirtualMachinePowerOpModeTrySoft, &metav1.Duration{Duration: 1234}, nil),
 			wantErr:        false,
 		},
+		{
+			name:           "successful VSphereMachine creation with naming strategy",
+			vsphereMachine: withMachineNamingStrategy(createVSphereMachine("foo.com", nil, "", []string{}, infrav1.VirtualMachinePowerOpModeHard, nil, nil), "{{ .machine.name }}-prod"),
+			wantErr:        false,
+		},
+		{
+			name:           "naming strategy with invalid template",
+			vsphereMachine: withMachineNamingStrategy(createVSphereMachine("foo.com", nil, "", []string{}, infrav1.VirtualMachinePowerOpModeHard, nil, nil), "{{ .machine.name "),
+			wantErr:        true,
+		},
+		{
+			name:           "naming strategy generating an invalid name",
+			vsphereMachine: withMachineNamingStrategy(createVSphereMachine("foo.com", nil, "", []string{}, infrav1.VirtualMachinePowerOpModeHard, nil, nil), "{{ .machine.name }}_prod"),
+			wantErr:        true,
+		},
+		{
+			name: "successful VSphereMachine creation with existing FCD data disk",
+			vsphereMachine: withMachineDataDisks(createVSphereMachine("foo.com", nil, "", []string{}, infrav1.VirtualMachinePowerOpModeHard, nil, nil),
+				infrav1.VSphereDisk{Name: "data", SizeGiB: 10},
+				infrav1.VSphereDisk{Name: "fcd", ExistingFCD: &infrav1.FirstClassDiskReference{ID: "fcd-1", Datastore: "ds"}}),
+			wantErr: false,
+		},
+		{
+			name:           "data disk without size and existing FCD",
+			vsphereMachine: withMachineDataDisks(createVSphereMachine("foo.com", nil, "", []string{}, infrav1.VirtualMachinePowerOpModeHard, nil, nil), infrav1.VSphereDisk{Name: "data"}),
+			wantErr:        true,
+		},
+		{
+			name: "existing FCD referenced twice",
+			vsphereMachine: withMachineDataDisks(createVSphereMachine("foo.com", nil, "", []string{}, infrav1.VirtualMachinePowerOpModeHard, nil, nil),
+				infrav1.VSphereDisk{Name: "fcd", ExistingFCD: &infrav1.FirstClassDiskReference{ID: "fcd-1", Datastore: "ds"}},
+				infrav1.VSphereDisk{Name: "fcd-again", ExistingFCD: &infrav1.FirstClassDiskReference{ID: "fcd-1", Datastore: "ds"}}),
+			wantErr: true,
+		},
+		{
+			name: "successful VSphereMachine creation with resource allocations",
+			vsphereMachine: withMachineResources(createVSphereMachine("foo.com", nil, "", []string{}, infrav1.VirtualMachinePowerOpModeHard, nil, nil), 4096, &infrav1.VirtualMachineResources{
+				CPU:    &infrav1.VirtualMachineResourceAllocation{Shares: infrav1.ResourcePoolSharesLevelHigh, Reservation: 2000, Limit: ptr.To[int64](4000)},
+				Memory: &infrav1.VirtualMachineResourceAllocation{Reservation: 4096},
+			}),
+			wantErr: false,
+		},
+		{
+			name: "CPU reservation greater than the limit",
+			vsphereMachine: withMachineResources(createVSphereMachine("foo.com", nil, "", []string{}, infrav1.VirtualMachinePowerOpModeHard, nil, nil), 4096, &infrav1.VirtualMachineResources{
+				CPU: &infrav1.VirtualMachineResourceAllocation{Reservation: 4000, Limit: ptr.To[int64](2000)},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "memory reservation greater than the memory",
+			vsphereMachine: withMachineResources(createVSphereMachine("foo.com", nil, "", []string{}, infrav1.VirtualMachinePowerOpModeHard, nil, nil), 4096, &infrav1.VirtualMachineResources{
+				Memory: &infrav1.VirtualMachineResourceAllocation{Reservation: 8192},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "memory reservation together with memoryReservationLockedToMax",
+			vsphereMachine: withMachineResources(createVSphereMachine("foo.com", nil, "", []string{}, infrav1.VirtualMachinePowerOpModeHard, nil, nil), 4096, &infrav1.VirtualMachineResources{
+				Memory:                       &infrav1.VirtualMachineResourceAllocation{Reservation: 2048},
+				MemoryReservationLockedToMax: true,
+			}),
+			wantErr: true,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(*testing.T) {
@@ -208,6 +335,36 @@ func TestVSphereMachine_ValidateUpdate(t *testing.T) {
 			vsphereMachine:    createVSphereMachine("foo.com", &someProviderID, "", []string{"192.168.0.1/32"}, infrav1.VirtualMachinePowerOpModeSoft, nil, nil),
 			wantErr:           false,
 		},
+		{
+			name:              "numCPUs and memoryMiB can be updated if in-place resize is allowed",
+			oldVSphereMachine: withMachineInPlaceResize(createVSphereMachine("foo.com", &someProviderID, "", []string{"192.168.0.1/32"}, infrav1.VirtualMachinePowerOpModeSoft, nil, nil), true, 2, 2048),
+			vsphereMachine:    withMachineInPlaceResize(createVSphereMachine("foo.com", &someProviderID, "", []string{"192.168.0.1/32"}, infrav1.VirtualMachinePowerOpModeSoft, nil, nil), true, 4, 4096),
+			wantErr:           false,
+		},
+		{
+			name:              "customVMXKeys can be updated",
+			oldVSphereMachine: createVSphereMachine("foo.com", &someProviderID, "", []string{"192.168.0.1/32"}, infrav1.VirtualMachinePowerOpModeSoft, nil, nil),
+			vsphereMachine:    withMachineCustomVMXKeys(createVSphereMachine("foo.com", &someProviderID, "", []string{"192.168.0.1/32"}, infrav1.VirtualMachinePowerOpModeSoft, nil, nil), map[string]string{"disk.EnableUUID": "TRUE"}),
+			wantErr:           false,
+		},
+		{
+			name:              "customVMXKeys cannot be updated to override the metadata",
+			oldVSphereMachine: createVSphereMachine("foo.com", &someProviderID, "", []string{"192.168.0.1/32"}, infrav1.VirtualMachinePowerOpModeSoft, nil, nil),
+			vsphereMachine:    withMachineCustomVMXKeys(createVSphereMachine("foo.com", &someProviderID, "", []string{"192.168.0.1/32"}, infrav1.VirtualMachinePowerOpModeSoft, nil, nil), map[string]string{"guestinfo.metadata": "foo"}),
+			wantErr:           true,
+		},
+		{
+			name:              "unchanged customVMXKeys overriding the metadata are tolerated",
+			oldVSphereMachine: withMachineCustomVMXKeys(createVSphereMachine("foo.com", nil, "", []string{"192.168.0.1/32"}, infrav1.VirtualMachinePowerOpModeSoft, nil, nil), map[string]string{"guestinfo.metadata": "foo"}),
+			vsphereMachine:    withMachineCustomVMXKeys(createVSphereMachine("foo.com", &someProviderID, "", []string{"192.168.0.1/32"}, infrav1.VirtualMachinePowerOpModeSoft, nil, nil), map[string]string{"guestinfo.metadata": "foo"}),
+			wantErr:           false,
+		},
+		{
+			name:              "numCPUs cannot be updated if in-place resize is not allowed",
+			oldVSphereMachine: withMachineInPlaceResize(createVSphereMachine("foo.com", &someProviderID, "", []string{"192.168.0.1/32"}, infrav1.VirtualMachinePowerOpModeSoft, nil, nil), false, 2, 2048),
+			vsphereMachine:    withMachineInPlaceResize(createVSphereMachine("foo.com", &someProviderID, "", []string{"192.168.0.1/32"}, infrav1.VirtualMachinePowerOpModeSoft, nil, nil), false, 4, 2048),
+			wantErr:           true,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(*testing.T) {
@@ -222,6 +379,25 @@ func TestVSphereMachine_ValidateUpdate(t *testing.T) {
 	}
 }
 
+func TestVSphereMachine_ValidateUpdate_DeprecationWarnings(t *testing.T) {
+	g := NewWithT(t)
+
+	webhook := &VSphereMachineWebhook{}
+	oldVSphereMachine := createVSphereMachine("foo.com", nil, "", []string{"192.168.0.1/32"}, infrav1.VirtualMachinePowerOpModeSoft, nil, nil)
+	vsphereMachine := createVSphereMachine("foo.com", &someProviderID, "", []string{"192.168.0.1/32"}, infrav1.VirtualMachinePowerOpModeSoft, nil, nil)
+	warnings, err := webhook.ValidateUpdate(context.Background(), oldVSphereMachine, vsphereMachine)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(warnings).To(BeEmpty())
+
+	// VSphereMachines created before the field was rejected keep it set.
+	oldVSphereMachine = createVSphereMachine("foo.com", nil, "192.168.0.0/24", []string{"192.168.0.1/32"}, infrav1.VirtualMachinePowerOpModeSoft, nil, nil)
+	vsphereMachine = createVSphereMachine("foo.com", &someProviderID, "192.168.0.0/24", []string{"192.168.0.1/32"}, infrav1.VirtualMachinePowerOpModeSoft, nil, nil)
+	warnings, err = webhook.ValidateUpdate(context.Background(), oldVSphereMachine, vsphereMachine)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(warnings).To(HaveLen(1))
+	g.Expect(warnings[0]).To(ContainSubstring("spec.network.preferredAPIServerCidr is deprecated"))
+}
+
 func createVSphereMachine(server string, providerID *string, preferredAPIServerCIDR string, ips []string, powerOffMode infrav1.VirtualMachinePowerOpMode, guestSoftPowerOffTimeout *metav1.Duration, pciDevices []infrav1.PCIDeviceSpec) *infrav1.VSphereMachine {
 	VSphereMachine := &infrav1.VSphereMachine{
 		Spec: infrav1.VSphereMachineSpec{
@@ -232,6 +408,7 @@ func createVSphereMachine(server string, providerID *string, preferredAPIServerC
 					Devices:                []infrav1.NetworkDeviceSpec{},
 				},
 				PciDevices: pciDevices,
+				Template:   "ubuntu-2204-kube-v1.31.0",
 			},
 			ProviderID:               providerID,
 			PowerOffMode:             powerOffMode,
@@ -245,3 +422,31 @@ func createVSphereMachine(server string, providerID *string, preferredAPIServerC
 	}
 	return VSphereMachine
 }
+
+func withMachineResources(vsphereMachine *infrav1.VSphereMachine, memoryMiB int64, resources *infrav1.VirtualMachineResources) *infrav1.VSphereMachine {
+	vsphereMachine.Spec.MemoryMiB = memoryMiB
+	vsphereMachine.Spec.Resources = resources
+	return vsphereMachine
+}
+
+func withMachineInPlaceResize(vsphereMachine *infrav1.VSphereMachine, allowInPlaceResize bool, numCPUs int32, memoryMiB int64) *infrav1.VSphereMachine {
+	vsphereMachine.Spec.AllowInPlaceResize = allowInPlaceResize
+	vsphereMachine.Spec.NumCPUs = numCPUs
+	vsphereMachine.Spec.MemoryMiB = memoryMiB
+	return vsphereMachine
+}
+
+func withMachineCustomVMXKeys(vsphereMachine *infrav1.VSphereMachine, customVMXKeys map[string]string) *infrav1.VSphereMachine {
+	vsphereMachine.Spec.CustomVMXKeys = customVMXKeys
+	return vsphereMachine
+}
+
+func withMachineDataDisks(vsphereMachine *infrav1.VSphereMachine, dataDisks ...infrav1.VSphereDisk) *infrav1.VSphereMachine {
+	vsphereMachine.Spec.DataDisks = dataDisks
+	return vsphereMachine
+}
+
+func withMachineNamingStrategy(vsphereMachine *infrav1.VSphereMachine, template string) *infrav1.VSphereMachine {
+	vsphereMachine.Spec.NamingStrategy = &infrav1.VSphereVMNamingStrategy{Template: &template}
+	return vsphereMachine
+}
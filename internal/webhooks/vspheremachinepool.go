@@ -0,0 +1,190 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/util"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-vspheremachinepool,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=vspheremachinepools,versions=v1beta1,name=validation.vspheremachinepool.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
+// +kubebuilder:webhook:verbs=create;update,path=/mutate-infrastructure-cluster-x-k8s-io-v1beta1-vspheremachinepool,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=vspheremachinepools,versions=v1beta1,name=default.vspheremachinepool.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
+
+// VSphereMachinePoolWebhook implements a validation and defaulting webhook for VSphereMachinePool.
+type VSphereMachinePoolWebhook struct {
+	// Client is used to read the VSpherePlacementPolicies VSphereMachinePools are validated against.
+	// If nil, no VSpherePlacementPolicies are enforced.
+	Client client.Reader
+}
+
+var _ webhook.CustomValidator = &VSphereMachinePoolWebhook{}
+var _ webhook.CustomDefaulter = &VSphereMachinePoolWebhook{}
+
+func (webhook *VSphereMachinePoolWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&infrav1.VSphereMachinePool{}).
+		WithValidator(webhook).
+		WithDefaulter(webhook).
+		Complete()
+}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type.
+func (webhook *VSphereMachinePoolWebhook) Default(_ context.Context, obj runtime.Object) error {
+	objValue, ok := obj.(*infrav1.VSphereMachinePool)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a VSphereMachinePool but got a %T", obj))
+	}
+	if objValue.Spec.Template.Datacenter == "" {
+		objValue.Spec.Template.Datacenter = "*"
+	}
+	if objValue.Spec.Strategy.Type == "" {
+		objValue.Spec.Strategy.Type = infrav1.RollingUpdateVSphereMachinePoolStrategyType
+	}
+	return nil
+}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (webhook *VSphereMachinePoolWebhook) ValidateCreate(ctx context.Context, raw runtime.Object) (admission.Warnings, error) {
+	obj, ok := raw.(*infrav1.VSphereMachinePool)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a VSphereMachinePool but got a %T", raw))
+	}
+	allErrs := validateVSphereMachinePool(obj)
+	placementErrs, err := validatePlacementPolicies(ctx, webhook.Client, obj.Namespace, field.NewPath("spec", "template"), &obj.Spec.Template.VirtualMachineCloneSpec, nil)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	allErrs = append(allErrs, placementErrs...)
+	return nil, AggregateObjErrors(obj.GroupVersionKind().GroupKind(), obj.Name, allErrs)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+// In contrast to VSphereMachines the instance template may be modified, which triggers
+// a rolling update of the instances of the VSphereMachinePool.
+func (webhook *VSphereMachinePoolWebhook) ValidateUpdate(ctx context.Context, oldRaw runtime.Object, newRaw runtime.Object) (admission.Warnings, error) {
+	oldTyped, ok := oldRaw.(*infrav1.VSphereMachinePool)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a VSphereMachinePool but got a %T", oldRaw))
+	}
+	newTyped, ok := newRaw.(*infrav1.VSphereMachinePool)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a VSphereMachinePool but got a %T", newRaw))
+	}
+	allErrs := validateVSphereMachinePool(newTyped)
+	placementErrs, err := validatePlacementPolicies(ctx, webhook.Client, newTyped.Namespace, field.NewPath("spec", "template"), &newTyped.Spec.Template.VirtualMachineCloneSpec, &oldTyped.Spec.Template.VirtualMachineCloneSpec)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	allErrs = append(allErrs, placementErrs...)
+	return nil, AggregateObjErrors(newTyped.GroupVersionKind().GroupKind(), newTyped.Name, allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (webhook *VSphereMachinePoolWebhook) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateVSphereMachinePool(obj *infrav1.VSphereMachinePool) field.ErrorList {
+	var allErrs field.ErrorList
+	spec := obj.Spec
+	templatePath := field.NewPath("spec", "template")
+
+	if spec.Template.Template == "" {
+		allErrs = append(allErrs, field.Required(templatePath.Child("template"), "must be set"))
+	}
+	if spec.Template.ImageRef != nil {
+		allErrs = append(allErrs, field.Forbidden(templatePath.Child("imageRef"), "is not supported for VSphereMachinePools"))
+	}
+
+	// Instances share the template, so static IP addresses would be assigned to multiple VMs.
+	for i, device := range spec.Template.Network.Devices {
+		if len(device.IPAddrs) > 0 {
+			allErrs = append(allErrs, field.Forbidden(templatePath.Child("network", "devices").Index(i).Child("ipAddrs"), "cannot be set as the template is shared by all instances"))
+		}
+	}
+
+	if spec.Template.GuestSoftPowerOffTimeout != nil {
+		if spec.Template.PowerOffMode != infrav1.VirtualMachinePowerOpModeTrySoft {
+			allErrs = append(allErrs, field.Invalid(templatePath.Child("guestSoftPowerOffTimeout"), spec.Template.GuestSoftPowerOffTimeout, "should not be set in templates unless the powerOffMode is trySoft"))
+		}
+		if spec.Template.GuestSoftPowerOffTimeout.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(templatePath.Child("guestSoftPowerOffTimeout"), spec.Template.GuestSoftPowerOffTimeout, "should be greater than 0"))
+		}
+	}
+	if spec.Template.Encryption != nil && spec.Template.CloneMode == infrav1.LinkedClone {
+		allErrs = append(allErrs, field.Invalid(templatePath.Child("encryption"), spec.Template.Encryption, "cannot be set when cloneMode is linkedClone"))
+	}
+	allErrs = append(allErrs, validatePCIDevices(spec.Template.PciDevices)...)
+	allErrs = append(allErrs, validateVirtualMachineResources(templatePath.Child("resources"), &spec.Template.VirtualMachineCloneSpec)...)
+	allErrs = append(allErrs, validateCustomVMXKeys(templatePath.Child("customVMXKeys"), spec.Template.CustomVMXKeys)...)
+
+	if spec.NamingStrategy != nil && spec.NamingStrategy.Template != nil {
+		name, err := util.GenerateMachinePoolInstanceName(obj)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "namingStrategy", "template"), *spec.NamingStrategy.Template, fmt.Sprintf("invalid VSphereVM name template: %v", err)))
+		} else if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "namingStrategy", "template"), *spec.NamingStrategy.Template, fmt.Sprintf("invalid VSphereVM name template, generated name %q is not a valid DNS-1123 subdomain: %v", name, errs)))
+		}
+	}
+
+	if rollingUpdate := spec.Strategy.RollingUpdate; rollingUpdate != nil {
+		rollingUpdatePath := field.NewPath("spec", "strategy", "rollingUpdate")
+		allErrs = append(allErrs, validateIntOrPercent(rollingUpdatePath.Child("maxSurge"), rollingUpdate.MaxSurge)...)
+		allErrs = append(allErrs, validateIntOrPercent(rollingUpdatePath.Child("maxUnavailable"), rollingUpdate.MaxUnavailable)...)
+		if isZero(rollingUpdate.MaxSurge) && isZero(rollingUpdate.MaxUnavailable) {
+			allErrs = append(allErrs, field.Invalid(rollingUpdatePath, rollingUpdate, "maxSurge and maxUnavailable cannot both be 0"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateIntOrPercent(fldPath *field.Path, value *intstr.IntOrString) field.ErrorList {
+	if value == nil {
+		return nil
+	}
+	// Any positive number is valid, percentages are resolved against the number of replicas.
+	scaled, err := intstr.GetScaledValueFromIntOrPercent(value, 100, true)
+	if err != nil {
+		return field.ErrorList{field.Invalid(fldPath, value.String(), err.Error())}
+	}
+	if scaled < 0 {
+		return field.ErrorList{field.Invalid(fldPath, value.String(), "must not be negative")}
+	}
+	return nil
+}
+
+func isZero(value *intstr.IntOrString) bool {
+	if value == nil {
+		return false
+	}
+	scaled, err := intstr.GetScaledValueFromIntOrPercent(value, 100, true)
+	return err == nil && scaled == 0
+}
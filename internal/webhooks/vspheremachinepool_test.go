@@ -0,0 +1,173 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func TestVSphereMachinePool_Default(t *testing.T) {
+	g := NewWithT(t)
+	m := &infrav1.VSphereMachinePool{}
+	webhook := &VSphereMachinePoolWebhook{}
+	g.Expect(webhook.Default(context.Background(), m)).ToNot(HaveOccurred())
+
+	g.Expect(m.Spec.Template.Datacenter).To(Equal("*"))
+	g.Expect(m.Spec.Strategy.Type).To(Equal(infrav1.RollingUpdateVSphereMachinePoolStrategyType))
+}
+
+func TestVSphereMachinePool_ValidateCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*infrav1.VSphereMachinePool)
+		wantErr bool
+	}{
+		{
+			name:   "valid VSphereMachinePool",
+			modify: func(*infrav1.VSphereMachinePool) {},
+		},
+		{
+			name: "static IP addresses are not allowed",
+			modify: func(m *infrav1.VSphereMachinePool) {
+				m.Spec.Template.Network.Devices[0].IPAddrs = []string{"192.168.0.1/32"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "guestSoftPowerOffTimeout should not be set with powerOffMode set to hard",
+			modify: func(m *infrav1.VSphereMachinePool) {
+				m.Spec.Template.PowerOffMode = infrav1.VirtualMachinePowerOpModeHard
+				m.Spec.Template.GuestSoftPowerOffTimeout = &metav1.Duration{Duration: infrav1.GuestSoftPowerOffDefaultTimeout}
+			},
+			wantErr: true,
+		},
+		{
+			name: "encryption cannot be set with linked clones",
+			modify: func(m *infrav1.VSphereMachinePool) {
+				m.Spec.Template.CloneMode = infrav1.LinkedClone
+				m.Spec.Template.Encryption = &infrav1.VirtualMachineEncryptionSpec{StoragePolicyName: "vm-encryption-policy"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid naming strategy",
+			modify: func(m *infrav1.VSphereMachinePool) {
+				m.Spec.NamingStrategy = &infrav1.VSphereMachinePoolNamingStrategy{Template: ptr.To("{{ .machinePool.name }}-vm-{{ .random }}")}
+			},
+		},
+		{
+			name: "naming strategy generating an invalid name",
+			modify: func(m *infrav1.VSphereMachinePool) {
+				m.Spec.NamingStrategy = &infrav1.VSphereMachinePoolNamingStrategy{Template: ptr.To("{{ .machinePool.name }}_{{ .random }}")}
+			},
+			wantErr: true,
+		},
+		{
+			name: "naming strategy with an unknown key",
+			modify: func(m *infrav1.VSphereMachinePool) {
+				m.Spec.NamingStrategy = &infrav1.VSphereMachinePoolNamingStrategy{Template: ptr.To("{{ .machine.name }}")}
+			},
+			wantErr: true,
+		},
+		{
+			name: "maxSurge and maxUnavailable are both 0",
+			modify: func(m *infrav1.VSphereMachinePool) {
+				m.Spec.Strategy.RollingUpdate = &infrav1.VSphereMachinePoolRollingUpdate{
+					MaxSurge:       ptr.To(intstr.FromInt32(0)),
+					MaxUnavailable: ptr.To(intstr.FromString("0%")),
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "maxSurge is negative",
+			modify: func(m *infrav1.VSphereMachinePool) {
+				m.Spec.Strategy.RollingUpdate = &infrav1.VSphereMachinePoolRollingUpdate{
+					MaxSurge: ptr.To(intstr.FromInt32(-1)),
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "maxUnavailable is not a valid percentage",
+			modify: func(m *infrav1.VSphereMachinePool) {
+				m.Spec.Strategy.RollingUpdate = &infrav1.VSphereMachinePoolRollingUpdate{
+					MaxUnavailable: ptr.To(intstr.FromString("one")),
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			vsphereMachinePool := createVSphereMachinePool()
+			tc.modify(vsphereMachinePool)
+
+			webhook := &VSphereMachinePoolWebhook{}
+			_, err := webhook.ValidateCreate(context.Background(), vsphereMachinePool)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestVSphereMachinePool_ValidateUpdate(t *testing.T) {
+	g := NewWithT(t)
+
+	oldVSphereMachinePool := createVSphereMachinePool()
+	newVSphereMachinePool := createVSphereMachinePool()
+	newVSphereMachinePool.Spec.Template.Template = "another-template"
+	newVSphereMachinePool.Spec.Template.NumCPUs = 4
+
+	webhook := &VSphereMachinePoolWebhook{}
+	_, err := webhook.ValidateUpdate(context.Background(), oldVSphereMachinePool, newVSphereMachinePool)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	newVSphereMachinePool.Spec.Template.Network.Devices[0].IPAddrs = []string{"192.168.0.1/32"}
+	_, err = webhook.ValidateUpdate(context.Background(), oldVSphereMachinePool, newVSphereMachinePool)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func createVSphereMachinePool() *infrav1.VSphereMachinePool {
+	return &infrav1.VSphereMachinePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool"},
+		Spec: infrav1.VSphereMachinePoolSpec{
+			Template: infrav1.VSphereMachinePoolInstanceTemplate{
+				VirtualMachineCloneSpec: infrav1.VirtualMachineCloneSpec{
+					Template: "ubuntu",
+					Network: infrav1.NetworkSpec{
+						Devices: []infrav1.NetworkDeviceSpec{
+							{NetworkName: "network", DHCP4: true},
+						},
+					},
+				},
+			},
+		},
+	}
+}
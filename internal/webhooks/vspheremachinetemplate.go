@@ -19,38 +19,68 @@ package webhooks
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"reflect"
 	"regexp"
+	"strings"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	"net"
 	"sigs.k8s.io/cluster-api/util/topology"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/util"
 )
 
 const machineTemplateImmutableMsg = "VSphereMachineTemplate spec.template.spec field is immutable. Please create a new resource instead."
 
 // +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-vspheremachinetemplate,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=vspheremachinetemplates,versions=v1beta1,name=validation.vspheremachinetemplate.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
+// +kubebuilder:webhook:verbs=create,path=/mutate-infrastructure-cluster-x-k8s-io-v1beta1-vspheremachinetemplate,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=vspheremachinetemplates,versions=v1beta1,name=default.vspheremachinetemplate.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 
 // VSphereMachineTemplateWebhook implements a validation and defaulting webhook for VSphereMachineTemplate.
-type VSphereMachineTemplateWebhook struct{}
+type VSphereMachineTemplateWebhook struct {
+	// Client is used to read the VSphereClusterSettings applied to new VSphereMachineTemplates
+	// and the VSpherePlacementPolicies they are validated against.
+	// If nil, no VSphereClusterSettings are applied and no VSpherePlacementPolicies are enforced.
+	Client client.Reader
+}
 
 var _ webhook.CustomValidator = &VSphereMachineTemplateWebhook{}
+var _ webhook.CustomDefaulter = &VSphereMachineTemplateWebhook{}
 
 func (webhook *VSphereMachineTemplateWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&infrav1.VSphereMachineTemplate{}).
 		WithValidator(webhook).
+		WithDefaulter(webhook).
 		Complete()
 }
 
+// Default implements webhook.Defaulter so a webhook will be registered for the type.
+func (webhook *VSphereMachineTemplateWebhook) Default(ctx context.Context, obj runtime.Object) error {
+	typedObj, ok := obj.(*infrav1.VSphereMachineTemplate)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a VSphereMachineTemplate but got a %T", obj))
+	}
+	settings, err := getClusterSettings(ctx, webhook.Client, typedObj)
+	if err != nil {
+		return err
+	}
+	if settings != nil {
+		defaultVirtualMachineCloneSpec(&typedObj.Spec.Template.Spec.VirtualMachineCloneSpec, settings)
+	}
+	return nil
+}
+
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
-func (webhook *VSphereMachineTemplateWebhook) ValidateCreate(_ context.Context, raw runtime.Object) (admission.Warnings, error) {
+func (webhook *VSphereMachineTemplateWebhook) ValidateCreate(ctx context.Context, raw runtime.Object) (admission.Warnings, error) {
 	obj, ok := raw.(*infrav1.VSphereMachineTemplate)
 	if !ok {
 		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a VSphereMachineTemplate but got a %T", raw))
@@ -69,6 +99,11 @@ func (webhook *VSphereMachineTemplateWebhook) ValidateCreate(_ context.Context,
 		if len(device.IPAddrs) != 0 {
 			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "template", "spec", "network", "devices", "ipAddrs"), "cannot be set in templates"))
 		}
+		for _, vlan := range device.VLANs {
+			if len(vlan.IPAddrs) != 0 {
+				allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "template", "spec", "network", "devices", "vlans", "ipAddrs"), "cannot be set in templates"))
+			}
+		}
 	}
 	if spec.HardwareVersion != "" {
 		r := regexp.MustCompile("^vmx-[1-9][0-9]?$")
@@ -84,8 +119,30 @@ func (webhook *VSphereMachineTemplateWebhook) ValidateCreate(_ context.Context,
 			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "template", "spec", "guestSoftPowerOffTimeout"), spec.GuestSoftPowerOffTimeout, "should be greater than 0"))
 		}
 	}
+	if spec.Encryption != nil && spec.CloneMode == infrav1.LinkedClone {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "template", "spec", "encryption"), spec.Encryption, "cannot be set when cloneMode is linkedClone"))
+	}
+	for i, disk := range spec.DataDisks {
+		if disk.ExistingFCD != nil {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "template", "spec", "dataDisks").Index(i).Child("existingFCD"), "cannot be set in templates"))
+		}
+	}
+	allErrs = append(allErrs, validateDataDisks(field.NewPath("spec", "template", "spec", "dataDisks"), spec.DataDisks)...)
+	allErrs = append(allErrs, validateVirtualMachineResources(field.NewPath("spec", "template", "spec", "resources"), &spec.VirtualMachineCloneSpec)...)
+	allErrs = append(allErrs, validateCustomVMXKeys(field.NewPath("spec", "template", "spec", "customVMXKeys"), spec.CustomVMXKeys)...)
 	pciErrs := validatePCIDevices(spec.PciDevices)
 	allErrs = append(allErrs, pciErrs...)
+	allErrs = append(allErrs, validateNetworkDevices(field.NewPath("spec", "template", "spec", "network", "devices"), spec.Network.Devices)...)
+	allErrs = append(allErrs, validateNetworkBondsAndVLANs(field.NewPath("spec", "template", "spec", "network", "devices"), spec.Network.Devices)...)
+	allErrs = append(allErrs, validateGuestNetworkSettings(field.NewPath("spec", "template", "spec", "network"), spec.Network)...)
+	allErrs = append(allErrs, validateVSphereVMNamingStrategy(field.NewPath("spec", "template", "spec", "namingStrategy"), spec.NamingStrategy)...)
+	allErrs = append(allErrs, validateImageRef(field.NewPath("spec", "template", "spec"), &spec.VirtualMachineCloneSpec)...)
+
+	placementErrs, err := validatePlacementPolicies(ctx, webhook.Client, obj.Namespace, field.NewPath("spec", "template", "spec"), &spec.VirtualMachineCloneSpec, nil)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	allErrs = append(allErrs, placementErrs...)
 
 	return nil, AggregateObjErrors(obj.GroupVersionKind().GroupKind(), obj.Name, allErrs)
 }
@@ -111,10 +168,173 @@ func (webhook *VSphereMachineTemplateWebhook) ValidateUpdate(ctx context.Context
 		!reflect.DeepEqual(newTyped.Spec.Template.Spec, oldTyped.Spec.Template.Spec) {
 		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "template", "spec"), newTyped, machineTemplateImmutableMsg))
 	}
-	return nil, AggregateObjErrors(newTyped.GroupVersionKind().GroupKind(), newTyped.Name, allErrs)
+	warnings := DeprecationWarnings(util.NetworkSpecDeprecatedFields(field.NewPath("spec", "template", "spec", "network"), newTyped.Spec.Template.Spec.Network))
+	return warnings, AggregateObjErrors(newTyped.GroupVersionKind().GroupKind(), newTyped.Name, allErrs)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
 func (webhook *VSphereMachineTemplateWebhook) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
 	return nil, nil
 }
+
+// validateNetworkDevices catches misconfigurations across the network devices of a template
+// which would otherwise only surface when the machines fail to bootstrap.
+func validateNetworkDevices(fldPath *field.Path, devices []infrav1.NetworkDeviceSpec) field.ErrorList {
+	var allErrs field.ErrorList
+	deviceNames := map[string]bool{}
+	var gateway4Device, gateway6Device *int
+	for i, device := range devices {
+		devicePath := fldPath.Index(i)
+
+		if device.DeviceName != "" {
+			if deviceNames[device.DeviceName] {
+				allErrs = append(allErrs, field.Duplicate(devicePath.Child("deviceName"), device.DeviceName))
+			}
+			deviceNames[device.DeviceName] = true
+		}
+
+		if device.SkipIPAllocation && (device.DHCP4 || device.DHCP6 || len(device.AddressesFromPools) > 0 || device.AddressFromNetworkProtocolProfile != nil) {
+			allErrs = append(allErrs, field.Invalid(devicePath.Child("skipIPAllocation"), device.SkipIPAllocation, "cannot be set together with dhcp4, dhcp6, addressesFromPools or addressFromNetworkProtocolProfile"))
+		}
+		if device.DHCP4 && device.AddressFromNetworkProtocolProfile != nil {
+			allErrs = append(allErrs, field.Invalid(devicePath.Child("addressFromNetworkProtocolProfile"), device.AddressFromNetworkProtocolProfile, "cannot be set when dhcp4 is true, as DHCP already provides the IPv4 address"))
+		}
+		if device.DHCP4 && device.DHCP6 && len(device.AddressesFromPools) > 0 {
+			allErrs = append(allErrs, field.Invalid(devicePath.Child("addressesFromPools"), device.AddressesFromPools, "cannot be set when both dhcp4 and dhcp6 are true, as DHCP already provides the addresses of both IP families"))
+		}
+
+		for j, pool := range device.AddressesFromPools {
+			poolPath := devicePath.Child("addressesFromPools").Index(j)
+			if pool.APIGroup == nil || *pool.APIGroup == "" {
+				allErrs = append(allErrs, field.Required(poolPath.Child("apiGroup"), "must be set to the API group of the IP pool"))
+			}
+			if pool.Kind == "" {
+				allErrs = append(allErrs, field.Required(poolPath.Child("kind"), "must be set to the kind of the IP pool"))
+			}
+			if pool.Name == "" {
+				allErrs = append(allErrs, field.Required(poolPath.Child("name"), "must be set to the name of the IP pool"))
+			}
+		}
+
+		// Each gateway results in a default route, the guest can only use the default route of one device per IP family.
+		if device.Gateway4 != "" {
+			if gateway4Device != nil {
+				allErrs = append(allErrs, field.Invalid(devicePath.Child("gateway4"), device.Gateway4, fmt.Sprintf("cannot be set on more than one device, already set on device %d", *gateway4Device)))
+			} else {
+				gateway4Device = ptr.To(i)
+			}
+		}
+		if device.Gateway6 != "" {
+			if gateway6Device != nil {
+				allErrs = append(allErrs, field.Invalid(devicePath.Child("gateway6"), device.Gateway6, fmt.Sprintf("cannot be set on more than one device, already set on device %d", *gateway6Device)))
+			} else {
+				gateway6Device = ptr.To(i)
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateGuestNetworkSettings validates the NTP servers and the proxy rendered into the bootstrap data.
+func validateGuestNetworkSettings(fldPath *field.Path, network infrav1.NetworkSpec) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, server := range network.NTPServers {
+		if strings.TrimSpace(server) == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ntpServers").Index(i), server, "must not be empty"))
+		}
+	}
+	if proxy := network.Proxy; proxy != nil {
+		proxyPath := fldPath.Child("proxy")
+		if proxy.HTTPProxy == "" && proxy.HTTPSProxy == "" {
+			allErrs = append(allErrs, field.Required(proxyPath, "either httpProxy or httpsProxy must be set"))
+		}
+		for name, proxyURL := range map[string]string{"httpProxy": proxy.HTTPProxy, "httpsProxy": proxy.HTTPSProxy} {
+			if proxyURL == "" {
+				continue
+			}
+			if u, err := url.Parse(proxyURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				allErrs = append(allErrs, field.Invalid(proxyPath.Child(name), proxyURL, "must be an http or https URL, e.g. http://proxy.example.com:3128"))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateNetworkBondsAndVLANs validates the bonds and the VLAN sub-interfaces of the network devices.
+// The network devices with the same bond name form a bond, which uses the bond parameters and the
+// IP configuration of its first member, so the other members must not have an IP configuration.
+func validateNetworkBondsAndVLANs(fldPath *field.Path, devices []infrav1.NetworkDeviceSpec) field.ErrorList {
+	var allErrs field.ErrorList
+	interfaceNames := map[string]bool{}
+	for i, device := range devices {
+		name := device.DeviceName
+		if name == "" {
+			name = fmt.Sprintf("eth%d", i)
+		}
+		interfaceNames[name] = true
+	}
+
+	bondMembers := map[string]int{}
+	for i, device := range devices {
+		devicePath := fldPath.Index(i)
+		name := device.DeviceName
+		if name == "" {
+			name = fmt.Sprintf("eth%d", i)
+		}
+
+		if device.Bond != nil {
+			bondPath := devicePath.Child("bond")
+			first, ok := bondMembers[device.Bond.Name]
+			if !ok {
+				bondMembers[device.Bond.Name] = i
+				if interfaceNames[device.Bond.Name] {
+					allErrs = append(allErrs, field.Invalid(bondPath.Child("name"), device.Bond.Name, "cannot be the same as the name of a network device"))
+				}
+				interfaceNames[device.Bond.Name] = true
+				name = device.Bond.Name
+			} else {
+				if !reflect.DeepEqual(device.Bond, devices[first].Bond) {
+					allErrs = append(allErrs, field.Invalid(bondPath, device.Bond, fmt.Sprintf("must be the same for all the members of bond %s, see device %d", device.Bond.Name, first)))
+				}
+				if hasNetworkConfig(device) {
+					allErrs = append(allErrs, field.Forbidden(devicePath, fmt.Sprintf("cannot have an IP configuration or VLANs, as bond %s uses the configuration of its first member, device %d", device.Bond.Name, first)))
+				}
+			}
+		}
+
+		vlanIDs := map[int32]bool{}
+		for j, vlan := range device.VLANs {
+			vlanPath := devicePath.Child("vlans").Index(j)
+			if vlanIDs[vlan.ID] {
+				allErrs = append(allErrs, field.Duplicate(vlanPath.Child("id"), vlan.ID))
+			}
+			vlanIDs[vlan.ID] = true
+
+			vlanName := vlan.Name
+			if vlanName == "" {
+				vlanName = fmt.Sprintf("%s.%d", name, vlan.ID)
+			}
+			if interfaceNames[vlanName] {
+				allErrs = append(allErrs, field.Duplicate(vlanPath.Child("name"), vlanName))
+			}
+			interfaceNames[vlanName] = true
+
+			for k, ip := range vlan.IPAddrs {
+				if _, _, err := net.ParseCIDR(ip); err != nil {
+					allErrs = append(allErrs, field.Invalid(vlanPath.Child("ipAddrs").Index(k), ip, "ip addresses should be in the CIDR format"))
+				}
+			}
+		}
+	}
+	return allErrs
+}
+
+// hasNetworkConfig returns true if the network device has an IP configuration or VLANs.
+func hasNetworkConfig(device infrav1.NetworkDeviceSpec) bool {
+	return device.DHCP4 || device.DHCP6 ||
+		device.DHCP4Overrides != nil || device.DHCP6Overrides != nil ||
+		len(device.IPAddrs) > 0 || len(device.AddressesFromPools) > 0 || device.AddressFromNetworkProtocolProfile != nil ||
+		device.Gateway4 != "" || device.Gateway6 != "" ||
+		len(device.Nameservers) > 0 || len(device.SearchDomains) > 0 ||
+		len(device.Routes) > 0 || len(device.VLANs) > 0
+}
@@ -22,6 +22,7 @@ import (
 
 	. "github.com/onsi/gomega"
 	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
@@ -55,6 +56,16 @@ func TestVSphereMachineTemplate_ValidateCreate(t *testing.T) {
 			vsphereMachine: createVSphereMachineTemplate("foo.com", "", nil, "", []string{"192.168.0.1/32", "192.168.0.3/32"}, nil),
 			wantErr:        true,
 		},
+		{
+			name: "encryption set with cloneMode linkedClone",
+			vsphereMachine: func() *infrav1.VSphereMachineTemplate {
+				m := createVSphereMachineTemplate("foo.com", "", nil, "", []string{}, nil)
+				m.Spec.Template.Spec.CloneMode = infrav1.LinkedClone
+				m.Spec.Template.Spec.Encryption = &infrav1.VirtualMachineEncryptionSpec{StoragePolicyName: "vm-encryption-policy"}
+				return m
+			}(),
+			wantErr: true,
+		},
 		{
 			name:           "incomplete hardware version",
 			vsphereMachine: createVSphereMachineTemplate("foo.com", "vmx-", nil, "", []string{"192.168.0.1/32", "192.168.0.3/32"}, nil),
@@ -98,10 +109,156 @@ func TestVSphereMachineTemplate_ValidateCreate(t *testing.T) {
 			name:           "successful VSphereMachine creation with vgpu",
 			vsphereMachine: createVSphereMachineTemplate("foo.com", "vmx-17", nil, "", []string{}, []infrav1.PCIDeviceSpec{{VGPUProfile: "vgpu"}}),
 		},
+		{
+			name: "duplicate device names",
+			vsphereMachine: withNetworkDevices(createVSphereMachineTemplate("foo.com", "", nil, "", []string{}, nil),
+				infrav1.NetworkDeviceSpec{NetworkName: "network-1", DeviceName: "eth0", DHCP4: true},
+				infrav1.NetworkDeviceSpec{NetworkName: "network-2", DeviceName: "eth0", DHCP4: true},
+			),
+			wantErr: true,
+		},
+		{
+			name: "skipIPAllocation set together with dhcp4",
+			vsphereMachine: withNetworkDevices(createVSphereMachineTemplate("foo.com", "", nil, "", []string{}, nil),
+				infrav1.NetworkDeviceSpec{NetworkName: "network-1", DHCP4: true, SkipIPAllocation: true},
+			),
+			wantErr: true,
+		},
+		{
+			name: "addressFromNetworkProtocolProfile",
+			vsphereMachine: withNetworkDevices(createVSphereMachineTemplate("foo.com", "", nil, "", []string{}, nil),
+				infrav1.NetworkDeviceSpec{NetworkName: "network-1", DHCP6: true, AddressFromNetworkProtocolProfile: &infrav1.NetworkProtocolProfileAllocation{}},
+			),
+			wantErr: false,
+		},
+		{
+			name: "addressFromNetworkProtocolProfile set together with dhcp4",
+			vsphereMachine: withNetworkDevices(createVSphereMachineTemplate("foo.com", "", nil, "", []string{}, nil),
+				infrav1.NetworkDeviceSpec{NetworkName: "network-1", DHCP4: true, AddressFromNetworkProtocolProfile: &infrav1.NetworkProtocolProfileAllocation{}},
+			),
+			wantErr: true,
+		},
+		{
+			name: "NTP servers and proxy",
+			vsphereMachine: withGuestNetworkSettings(createVSphereMachineTemplate("foo.com", "", nil, "", []string{}, nil),
+				[]string{"ntp.example.com"}, &infrav1.GuestProxySpec{HTTPProxy: "http://proxy.example.com:3128", NoProxy: []string{".example.com"}}),
+			wantErr: false,
+		},
+		{
+			name: "empty NTP server",
+			vsphereMachine: withGuestNetworkSettings(createVSphereMachineTemplate("foo.com", "", nil, "", []string{}, nil),
+				[]string{""}, nil),
+			wantErr: true,
+		},
+		{
+			name: "proxy without URL",
+			vsphereMachine: withGuestNetworkSettings(createVSphereMachineTemplate("foo.com", "", nil, "", []string{}, nil),
+				nil, &infrav1.GuestProxySpec{NoProxy: []string{".example.com"}}),
+			wantErr: true,
+		},
+		{
+			name: "proxy with invalid URL",
+			vsphereMachine: withGuestNetworkSettings(createVSphereMachineTemplate("foo.com", "", nil, "", []string{}, nil),
+				nil, &infrav1.GuestProxySpec{HTTPSProxy: "proxy.example.com:3128"}),
+			wantErr: true,
+		},
+		{
+			name: "addressesFromPools set together with dhcp4 and dhcp6",
+			vsphereMachine: withNetworkDevices(createVSphereMachineTemplate("foo.com", "", nil, "", []string{}, nil),
+				infrav1.NetworkDeviceSpec{NetworkName: "network-1", DHCP4: true, DHCP6: true, AddressesFromPools: []corev1.TypedLocalObjectReference{
+					{APIGroup: ptr.To("ipam.cluster.x-k8s.io"), Kind: "InClusterIPPool", Name: "pool"},
+				}},
+			),
+			wantErr: true,
+		},
+		{
+			name: "addressesFromPools without apiGroup",
+			vsphereMachine: withNetworkDevices(createVSphereMachineTemplate("foo.com", "", nil, "", []string{}, nil),
+				infrav1.NetworkDeviceSpec{NetworkName: "network-1", AddressesFromPools: []corev1.TypedLocalObjectReference{
+					{Kind: "InClusterIPPool", Name: "pool"},
+				}},
+			),
+			wantErr: true,
+		},
+		{
+			name: "gateway4 set on more than one device",
+			vsphereMachine: withNetworkDevices(createVSphereMachineTemplate("foo.com", "", nil, "", []string{}, nil),
+				infrav1.NetworkDeviceSpec{NetworkName: "network-1", Gateway4: "10.0.0.1"},
+				infrav1.NetworkDeviceSpec{NetworkName: "network-2", Gateway4: "10.0.1.1"},
+			),
+			wantErr: true,
+		},
+		{
+			name: "bond members with different bond parameters",
+			vsphereMachine: withNetworkDevices(createVSphereMachineTemplate("foo.com", "", nil, "", []string{}, nil),
+				infrav1.NetworkDeviceSpec{NetworkName: "network-1", DHCP4: true, Bond: &infrav1.NetworkBondSpec{Name: "bond0", Mode: infrav1.NetworkBondModeActiveBackup}},
+				infrav1.NetworkDeviceSpec{NetworkName: "network-2", Bond: &infrav1.NetworkBondSpec{Name: "bond0", Mode: infrav1.NetworkBondMode8023AD}},
+			),
+			wantErr: true,
+		},
+		{
+			name: "bond member other than the first one with an IP configuration",
+			vsphereMachine: withNetworkDevices(createVSphereMachineTemplate("foo.com", "", nil, "", []string{}, nil),
+				infrav1.NetworkDeviceSpec{NetworkName: "network-1", DHCP4: true, Bond: &infrav1.NetworkBondSpec{Name: "bond0"}},
+				infrav1.NetworkDeviceSpec{NetworkName: "network-2", DHCP6: true, Bond: &infrav1.NetworkBondSpec{Name: "bond0"}},
+			),
+			wantErr: true,
+		},
+		{
+			name: "bond name clashing with a device name",
+			vsphereMachine: withNetworkDevices(createVSphereMachineTemplate("foo.com", "", nil, "", []string{}, nil),
+				infrav1.NetworkDeviceSpec{NetworkName: "network-1", DHCP4: true, Bond: &infrav1.NetworkBondSpec{Name: "eth1"}},
+				infrav1.NetworkDeviceSpec{NetworkName: "network-2", DHCP4: true},
+			),
+			wantErr: true,
+		},
+		{
+			name: "duplicate VLAN IDs",
+			vsphereMachine: withNetworkDevices(createVSphereMachineTemplate("foo.com", "", nil, "", []string{}, nil),
+				infrav1.NetworkDeviceSpec{NetworkName: "network-1", DHCP4: true, VLANs: []infrav1.NetworkVLANSpec{{ID: 100, DHCP4: true}, {ID: 100, DHCP6: true}}},
+			),
+			wantErr: true,
+		},
+		{
+			name: "VLAN with static IP addresses",
+			vsphereMachine: withNetworkDevices(createVSphereMachineTemplate("foo.com", "", nil, "", []string{}, nil),
+				infrav1.NetworkDeviceSpec{NetworkName: "network-1", DHCP4: true, VLANs: []infrav1.NetworkVLANSpec{{ID: 100, IPAddrs: []string{"10.0.100.10/24"}}}},
+			),
+			wantErr: true,
+		},
+		{
+			name: "successful VSphereMachine creation with a bond and VLANs",
+			vsphereMachine: withNetworkDevices(createVSphereMachineTemplate("foo.com", "", nil, "", []string{}, nil),
+				infrav1.NetworkDeviceSpec{NetworkName: "network-1", DHCP4: true, Bond: &infrav1.NetworkBondSpec{Name: "bond0", Mode: infrav1.NetworkBondModeActiveBackup}, VLANs: []infrav1.NetworkVLANSpec{{ID: 100, DHCP4: true}}},
+				infrav1.NetworkDeviceSpec{NetworkName: "network-2", Bond: &infrav1.NetworkBondSpec{Name: "bond0", Mode: infrav1.NetworkBondModeActiveBackup}},
+				infrav1.NetworkDeviceSpec{NetworkName: "network-3", DHCP6: true, VLANs: []infrav1.NetworkVLANSpec{{ID: 100, DHCP6: true}, {ID: 200, Name: "storage", DHCP6: true}}},
+			),
+		},
+		{
+			name: "successful VSphereMachine creation with multiple network devices",
+			vsphereMachine: withNetworkDevices(createVSphereMachineTemplate("foo.com", "", nil, "", []string{}, nil),
+				infrav1.NetworkDeviceSpec{NetworkName: "network-1", DeviceName: "eth0", DHCP4: true, AddressesFromPools: []corev1.TypedLocalObjectReference{
+					{APIGroup: ptr.To("ipam.cluster.x-k8s.io"), Kind: "InClusterIPPool", Name: "ipv6-pool"},
+				}},
+				infrav1.NetworkDeviceSpec{NetworkName: "network-2", DeviceName: "eth1", Gateway4: "10.0.1.1", AddressesFromPools: []corev1.TypedLocalObjectReference{
+					{APIGroup: ptr.To("ipam.cluster.x-k8s.io"), Kind: "InClusterIPPool", Name: "ipv4-pool"},
+				}},
+				infrav1.NetworkDeviceSpec{NetworkName: "network-3", SkipIPAllocation: true},
+			),
+		},
 		{
 			name:           "successful VSphereMachine creation with hardware version set",
 			vsphereMachine: createVSphereMachineTemplate("foo.com", "vmx-17", nil, "", []string{}, nil),
 		},
+		{
+			name:           "successful VSphereMachine creation with naming strategy",
+			vsphereMachine: withNamingStrategy(createVSphereMachineTemplate("foo.com", "", nil, "", []string{}, nil), "vm-{{ .machine.name }}"),
+		},
+		{
+			name:           "naming strategy generating an invalid name",
+			vsphereMachine: withNamingStrategy(createVSphereMachineTemplate("foo.com", "", nil, "", []string{}, nil), "{{ .machine.name | printf \"%s.\" }}"),
+			wantErr:        true,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(*testing.T) {
@@ -199,6 +356,7 @@ func createVSphereMachineTemplate(server, hwVersion string, providerID *string,
 						},
 						HardwareVersion: hwVersion,
 						PciDevices:      pciDevices,
+						Template:        "ubuntu-2204-kube-v1.31.0",
 					},
 				},
 			},
@@ -211,3 +369,19 @@ func createVSphereMachineTemplate(server, hwVersion string, providerID *string,
 	}
 	return vsphereMachineTemplate
 }
+
+func withNetworkDevices(vsphereMachineTemplate *infrav1.VSphereMachineTemplate, devices ...infrav1.NetworkDeviceSpec) *infrav1.VSphereMachineTemplate {
+	vsphereMachineTemplate.Spec.Template.Spec.Network.Devices = devices
+	return vsphereMachineTemplate
+}
+
+func withNamingStrategy(vsphereMachineTemplate *infrav1.VSphereMachineTemplate, template string) *infrav1.VSphereMachineTemplate {
+	vsphereMachineTemplate.Spec.Template.Spec.NamingStrategy = &infrav1.VSphereVMNamingStrategy{Template: &template}
+	return vsphereMachineTemplate
+}
+
+func withGuestNetworkSettings(template *infrav1.VSphereMachineTemplate, ntpServers []string, proxy *infrav1.GuestProxySpec) *infrav1.VSphereMachineTemplate {
+	template.Spec.Template.Spec.Network.NTPServers = ntpServers
+	template.Spec.Template.Spec.Network.Proxy = proxy
+	return template
+}
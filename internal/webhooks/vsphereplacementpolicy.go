@@ -0,0 +1,218 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-vsphereplacementpolicy,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=vsphereplacementpolicies,versions=v1beta1,name=validation.vsphereplacementpolicy.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
+
+// VSpherePlacementPolicyWebhook implements a validation webhook for VSpherePlacementPolicy.
+type VSpherePlacementPolicyWebhook struct{}
+
+var _ webhook.CustomValidator = &VSpherePlacementPolicyWebhook{}
+
+func (webhook *VSpherePlacementPolicyWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&infrav1.VSpherePlacementPolicy{}).
+		WithValidator(webhook).
+		Complete()
+}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (webhook *VSpherePlacementPolicyWebhook) ValidateCreate(_ context.Context, raw runtime.Object) (admission.Warnings, error) {
+	obj, ok := raw.(*infrav1.VSpherePlacementPolicy)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a VSpherePlacementPolicy but got a %T", raw))
+	}
+	return nil, AggregateObjErrors(obj.GroupVersionKind().GroupKind(), obj.Name, validatePlacementPolicy(obj))
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (webhook *VSpherePlacementPolicyWebhook) ValidateUpdate(_ context.Context, _ runtime.Object, newRaw runtime.Object) (admission.Warnings, error) {
+	newTyped, ok := newRaw.(*infrav1.VSpherePlacementPolicy)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a VSpherePlacementPolicy but got a %T", newRaw))
+	}
+	return nil, AggregateObjErrors(newTyped.GroupVersionKind().GroupKind(), newTyped.Name, validatePlacementPolicy(newTyped))
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (webhook *VSpherePlacementPolicyWebhook) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validatePlacementPolicy(obj *infrav1.VSpherePlacementPolicy) field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if _, err := metav1.LabelSelectorAsSelector(&obj.Spec.NamespaceSelector); err != nil {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("namespaceSelector"), obj.Spec.NamespaceSelector, err.Error()))
+	}
+	for _, list := range []struct {
+		name     string
+		patterns []string
+	}{
+		{"datastores", obj.Spec.Datastores},
+		{"networks", obj.Spec.Networks},
+		{"folders", obj.Spec.Folders},
+		{"resourcePools", obj.Spec.ResourcePools},
+	} {
+		for i, pattern := range list.patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				allErrs = append(allErrs, field.Invalid(specPath.Child(list.name).Index(i), pattern, "must be a valid shell file name pattern"))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validatePlacementPolicies returns the fields of a VirtualMachineCloneSpec which reference vSphere inventory
+// objects not allowed by the VSpherePlacementPolicies of the namespace. On updates, only the fields changed
+// compared to oldSpec are validated, so that existing objects are not locked by policies created later on.
+// It returns no errors if the client is nil.
+func validatePlacementPolicies(ctx context.Context, c client.Reader, namespace string, fldPath *field.Path, spec, oldSpec *infrav1.VirtualMachineCloneSpec) (field.ErrorList, error) {
+	if c == nil {
+		return nil, nil
+	}
+	policies, err := getPlacementPolicies(ctx, c, namespace)
+	if err != nil || len(policies) == 0 {
+		return nil, err
+	}
+	// On create all the fields are validated.
+	isCreate := oldSpec == nil
+	changed := func(value, oldValue string) bool { return isCreate || value != oldValue }
+	if oldSpec == nil {
+		oldSpec = &infrav1.VirtualMachineCloneSpec{}
+	}
+
+	var allErrs field.ErrorList
+	for _, policy := range policies {
+		if changed(spec.Datastore, oldSpec.Datastore) {
+			allErrs = append(allErrs, checkPlacement(policy, fldPath.Child("datastore"), spec.Datastore, policy.Spec.Datastores)...)
+		}
+		if changed(spec.Folder, oldSpec.Folder) {
+			allErrs = append(allErrs, checkPlacement(policy, fldPath.Child("folder"), spec.Folder, policy.Spec.Folders)...)
+		}
+		if changed(spec.ResourcePool, oldSpec.ResourcePool) {
+			allErrs = append(allErrs, checkPlacement(policy, fldPath.Child("resourcePool"), spec.ResourcePool, policy.Spec.ResourcePools)...)
+		}
+		for i, device := range spec.Network.Devices {
+			if i < len(oldSpec.Network.Devices) && oldSpec.Network.Devices[i].NetworkName == device.NetworkName {
+				continue
+			}
+			allErrs = append(allErrs, checkPlacement(policy, fldPath.Child("network", "devices").Index(i).Child("networkName"), device.NetworkName, policy.Spec.Networks)...)
+		}
+		for i, disk := range spec.DataDisks {
+			if disk.ExistingFCD == nil {
+				continue
+			}
+			if i < len(oldSpec.DataDisks) && oldSpec.DataDisks[i].ExistingFCD != nil && oldSpec.DataDisks[i].ExistingFCD.Datastore == disk.ExistingFCD.Datastore {
+				continue
+			}
+			allErrs = append(allErrs, checkPlacement(policy, fldPath.Child("dataDisks").Index(i).Child("existingFCD", "datastore"), disk.ExistingFCD.Datastore, policy.Spec.Datastores)...)
+		}
+	}
+	return allErrs, nil
+}
+
+// validateRelocateToPlacementPolicies returns the fields of the relocation target of a VSphereVM which
+// reference vSphere inventory objects not allowed by the VSpherePlacementPolicies of the namespace.
+// Fields which are not set keep the current placement of the VM and are not validated.
+func validateRelocateToPlacementPolicies(ctx context.Context, c client.Reader, namespace string, relocateTo, oldRelocateTo *infrav1.VSphereVMRelocateTo) (field.ErrorList, error) {
+	if c == nil || relocateTo == nil || (oldRelocateTo != nil && *relocateTo == *oldRelocateTo) {
+		return nil, nil
+	}
+	policies, err := getPlacementPolicies(ctx, c, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("spec", "relocateTo")
+	for _, policy := range policies {
+		if relocateTo.Datastore != "" {
+			allErrs = append(allErrs, checkPlacement(policy, fldPath.Child("datastore"), relocateTo.Datastore, policy.Spec.Datastores)...)
+		}
+		if relocateTo.ResourcePool != "" {
+			allErrs = append(allErrs, checkPlacement(policy, fldPath.Child("resourcePool"), relocateTo.ResourcePool, policy.Spec.ResourcePools)...)
+		}
+	}
+	return allErrs, nil
+}
+
+// getPlacementPolicies returns the VSpherePlacementPolicies whose namespace selector selects the namespace.
+func getPlacementPolicies(ctx context.Context, c client.Reader, namespace string) ([]infrav1.VSpherePlacementPolicy, error) {
+	policyList := &infrav1.VSpherePlacementPolicyList{}
+	if err := c.List(ctx, policyList); err != nil {
+		return nil, errors.Wrap(err, "failed to list VSpherePlacementPolicies")
+	}
+	if len(policyList.Items) == 0 {
+		return nil, nil
+	}
+
+	ns := &corev1.Namespace{}
+	if err := c.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		return nil, errors.Wrapf(err, "failed to get namespace %s", namespace)
+	}
+	var policies []infrav1.VSpherePlacementPolicy
+	for _, policy := range policyList.Items {
+		selector, err := metav1.LabelSelectorAsSelector(&policy.Spec.NamespaceSelector)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid namespace selector of VSpherePlacementPolicy %s", policy.Name)
+		}
+		if selector.Matches(labels.Set(ns.Labels)) {
+			policies = append(policies, policy)
+		}
+	}
+	return policies, nil
+}
+
+// checkPlacement returns an error if the value is not matched by one of the allowed patterns.
+// An empty list of patterns allows any value.
+func checkPlacement(policy infrav1.VSpherePlacementPolicy, fldPath *field.Path, value string, allowed []string) field.ErrorList {
+	if len(allowed) == 0 {
+		return nil
+	}
+	if value == "" {
+		return field.ErrorList{field.Required(fldPath, fmt.Sprintf("must be set to one of %s, as required by VSpherePlacementPolicy %s", strings.Join(allowed, ", "), policy.Name))}
+	}
+	for _, pattern := range allowed {
+		if ok, _ := path.Match(pattern, value); ok {
+			return nil
+		}
+	}
+	return field.ErrorList{field.Forbidden(fldPath, fmt.Sprintf("%s is not allowed by VSpherePlacementPolicy %s, allowed values are %s", value, policy.Name, strings.Join(allowed, ", ")))}
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func TestVSpherePlacementPolicy_ValidateCreate(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      infrav1.VSpherePlacementPolicySpec
+		wantError bool
+	}{
+		{
+			name: "valid policy",
+			spec: infrav1.VSpherePlacementPolicySpec{
+				NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+				Datastores:        []string{"/dc0/datastore/team-a-*"},
+				Networks:          []string{"team-a"},
+			},
+		},
+		{
+			name: "invalid namespace selector",
+			spec: infrav1.VSpherePlacementPolicySpec{
+				NamespaceSelector: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Foo"}}},
+			},
+			wantError: true,
+		},
+		{
+			name: "invalid pattern",
+			spec: infrav1.VSpherePlacementPolicySpec{
+				ResourcePools: []string{"/dc0/host/cluster0/Resources/["},
+			},
+			wantError: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			policy := &infrav1.VSpherePlacementPolicy{ObjectMeta: metav1.ObjectMeta{Name: "policy"}, Spec: tc.spec}
+			webhook := &VSpherePlacementPolicyWebhook{}
+			_, err := webhook.ValidateCreate(context.Background(), policy)
+			if tc.wantError {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestValidatePlacementPolicies(t *testing.T) {
+	teamA := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"team": "a"}}}
+	unrestricted := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "unrestricted"}}
+	policy := &infrav1.VSpherePlacementPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+		Spec: infrav1.VSpherePlacementPolicySpec{
+			NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+			Datastores:        []string{"team-a-*"},
+			Networks:          []string{"team-a"},
+			ResourcePools:     []string{"/dc0/host/cluster0/Resources/team-a"},
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	g := NewWithT(t)
+	g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(teamA, unrestricted, policy).Build()
+
+	allowedSpec := func() *infrav1.VirtualMachineCloneSpec {
+		return &infrav1.VirtualMachineCloneSpec{
+			Datastore:    "team-a-ssd",
+			ResourcePool: "/dc0/host/cluster0/Resources/team-a",
+			Network: infrav1.NetworkSpec{
+				Devices: []infrav1.NetworkDeviceSpec{{NetworkName: "team-a"}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		namespace  string
+		spec       func(*infrav1.VirtualMachineCloneSpec)
+		oldSpec    *infrav1.VirtualMachineCloneSpec
+		wantFields []string
+	}{
+		{
+			name:      "allowed placement",
+			namespace: "team-a",
+		},
+		{
+			name:      "namespace not selected by the policy",
+			namespace: "unrestricted",
+			spec: func(spec *infrav1.VirtualMachineCloneSpec) {
+				spec.Datastore = "shared"
+				spec.Network.Devices[0].NetworkName = "VM Network"
+			},
+		},
+		{
+			name:      "forbidden datastore and network",
+			namespace: "team-a",
+			spec: func(spec *infrav1.VirtualMachineCloneSpec) {
+				spec.Datastore = "shared"
+				spec.Network.Devices = append(spec.Network.Devices, infrav1.NetworkDeviceSpec{NetworkName: "VM Network"})
+			},
+			wantFields: []string{"spec.datastore", "spec.network.devices[1].networkName"},
+		},
+		{
+			name:      "restricted resource pool not set",
+			namespace: "team-a",
+			spec: func(spec *infrav1.VirtualMachineCloneSpec) {
+				spec.ResourcePool = ""
+			},
+			wantFields: []string{"spec.resourcePool"},
+		},
+		{
+			name:      "forbidden existing First Class Disk datastore",
+			namespace: "team-a",
+			spec: func(spec *infrav1.VirtualMachineCloneSpec) {
+				spec.DataDisks = []infrav1.VSphereDisk{{Name: "data", ExistingFCD: &infrav1.FirstClassDiskReference{ID: "fcd", Datastore: "shared"}}}
+			},
+			wantFields: []string{"spec.dataDisks[0].existingFCD.datastore"},
+		},
+		{
+			name:      "unchanged fields are not validated on update",
+			namespace: "team-a",
+			spec: func(spec *infrav1.VirtualMachineCloneSpec) {
+				spec.Datastore = "shared"
+			},
+			oldSpec: &infrav1.VirtualMachineCloneSpec{Datastore: "shared"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			spec := allowedSpec()
+			if tc.spec != nil {
+				tc.spec(spec)
+			}
+			oldSpec := tc.oldSpec
+			if oldSpec != nil {
+				merged := allowedSpec()
+				merged.Datastore = oldSpec.Datastore
+				oldSpec = merged
+			}
+			allErrs, err := validatePlacementPolicies(context.Background(), c, tc.namespace, field.NewPath("spec"), spec, oldSpec)
+			g.Expect(err).ToNot(HaveOccurred())
+			fields := make([]string, 0, len(allErrs))
+			for _, e := range allErrs {
+				fields = append(fields, e.Field)
+			}
+			g.Expect(fields).To(ConsistOf(tc.wantFields))
+		})
+	}
+}
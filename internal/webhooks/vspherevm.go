@@ -27,17 +27,24 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/util"
 )
 
 // +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-vspherevm,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=vspherevms,versions=v1beta1,name=validation.vspherevm.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 // +kubebuilder:webhook:verbs=create;update,path=/mutate-infrastructure-cluster-x-k8s-io-v1beta1-vspherevm,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=vspherevms,versions=v1beta1,name=default.vspherevm.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 
 // VSphereVMWebhook implements a validation and defaulting webhook for VSphereVM.
-type VSphereVMWebhook struct{}
+type VSphereVMWebhook struct {
+	// Client is used to read the VSphereClusterSettings applied to new VSphereVMs
+	// and the VSpherePlacementPolicies they are validated against.
+	// If nil, no VSphereClusterSettings are applied and no VSpherePlacementPolicies are enforced.
+	Client client.Reader
+}
 
 var _ webhook.CustomValidator = &VSphereVMWebhook{}
 var _ webhook.CustomDefaulter = &VSphereVMWebhook{}
@@ -51,11 +58,18 @@ func (webhook *VSphereVMWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error
 }
 
 // Default implements webhook.Defaulter so a webhook will be registered for the type.
-func (webhook *VSphereVMWebhook) Default(_ context.Context, obj runtime.Object) error {
+func (webhook *VSphereVMWebhook) Default(ctx context.Context, obj runtime.Object) error {
 	typedObj, ok := obj.(*infrav1.VSphereVM)
 	if !ok {
 		return apierrors.NewBadRequest(fmt.Sprintf("expected a VSphereVM but got a %T", obj))
 	}
+	settings, err := getClusterSettings(ctx, webhook.Client, typedObj)
+	if err != nil {
+		return err
+	}
+	if settings != nil {
+		defaultVirtualMachineCloneSpec(&typedObj.Spec.VirtualMachineCloneSpec, settings)
+	}
 	// Set Linux as default OS value
 	if typedObj.Spec.OS == "" {
 		typedObj.Spec.OS = infrav1.Linux
@@ -64,7 +78,7 @@ func (webhook *VSphereVMWebhook) Default(_ context.Context, obj runtime.Object)
 }
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
-func (webhook *VSphereVMWebhook) ValidateCreate(_ context.Context, raw runtime.Object) (admission.Warnings, error) {
+func (webhook *VSphereVMWebhook) ValidateCreate(ctx context.Context, raw runtime.Object) (admission.Warnings, error) {
 	var allErrs field.ErrorList
 	objValue, ok := raw.(*infrav1.VSphereVM)
 	if !ok {
@@ -83,6 +97,8 @@ func (webhook *VSphereVMWebhook) ValidateCreate(_ context.Context, raw runtime.O
 			}
 		}
 	}
+	allErrs = append(allErrs, validateNetworkBondsAndVLANs(field.NewPath("spec", "network", "devices"), spec.Network.Devices)...)
+	allErrs = append(allErrs, validateGuestNetworkSettings(field.NewPath("spec", "network"), spec.Network)...)
 
 	if objValue.Spec.OS == infrav1.Windows && len(objValue.Name) > 15 {
 		allErrs = append(allErrs, field.Invalid(field.NewPath("name"), objValue.Name, "name has to be less than 16 characters for Windows VM"))
@@ -95,11 +111,28 @@ func (webhook *VSphereVMWebhook) ValidateCreate(_ context.Context, raw runtime.O
 			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "guestSoftPowerOffTimeout"), spec.GuestSoftPowerOffTimeout, "should be greater than 0"))
 		}
 	}
+	if spec.Encryption != nil && spec.CloneMode == infrav1.LinkedClone {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "encryption"), spec.Encryption, "cannot be set when cloneMode is linkedClone"))
+	}
+	allErrs = append(allErrs, validateRelocateTo(spec.RelocateTo)...)
+	allErrs = append(allErrs, validateDataDisks(field.NewPath("spec", "dataDisks"), spec.DataDisks)...)
+	allErrs = append(allErrs, validateVirtualMachineResources(field.NewPath("spec", "resources"), &spec.VirtualMachineCloneSpec)...)
+	allErrs = append(allErrs, validateCustomVMXKeys(field.NewPath("spec", "customVMXKeys"), spec.CustomVMXKeys)...)
+	// The template of the VSphereVMImage referenced by the VSphereMachine is resolved when the VSphereVM is created.
+	if spec.Template == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec", "template"), "must be set"))
+	}
+
+	placementErrs, err := validatePlacementPolicies(ctx, webhook.Client, objValue.Namespace, field.NewPath("spec"), &spec.VirtualMachineCloneSpec, nil)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	allErrs = append(allErrs, placementErrs...)
 	return nil, AggregateObjErrors(objValue.GroupVersionKind().GroupKind(), objValue.Name, allErrs)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
-func (webhook *VSphereVMWebhook) ValidateUpdate(_ context.Context, oldRaw runtime.Object, newRaw runtime.Object) (admission.Warnings, error) {
+func (webhook *VSphereVMWebhook) ValidateUpdate(ctx context.Context, oldRaw runtime.Object, newRaw runtime.Object) (admission.Warnings, error) {
 	var allErrs field.ErrorList
 
 	oldTyped, ok := oldRaw.(*infrav1.VSphereVM)
@@ -118,6 +151,7 @@ func (webhook *VSphereVMWebhook) ValidateUpdate(_ context.Context, oldRaw runtim
 			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "guestSoftPowerOffTimeout"), newTyped.Spec.GuestSoftPowerOffTimeout, "should be greater than 0"))
 		}
 	}
+	allErrs = append(allErrs, validateRelocateTo(newTyped.Spec.RelocateTo)...)
 
 	newVSphereVM, err := runtime.DefaultUnstructuredConverter.ToUnstructured(newTyped)
 	if err != nil {
@@ -131,8 +165,17 @@ func (webhook *VSphereVMWebhook) ValidateUpdate(_ context.Context, oldRaw runtim
 	newVSphereVMSpec := newVSphereVM["spec"].(map[string]interface{})
 	oldVSphereVMSpec := oldVSphereVM["spec"].(map[string]interface{})
 
-	// Allow changes to bootstrapRef, thumbprint, powerOffMode, guestSoftPowerOffTimeout.
-	keys := []string{"bootstrapRef", "thumbprint", "powerOffMode", "guestSoftPowerOffTimeout"}
+	// Keys managed by CAPV which were set before they were rejected are tolerated until changed.
+	if !reflect.DeepEqual(oldTyped.Spec.CustomVMXKeys, newTyped.Spec.CustomVMXKeys) {
+		allErrs = append(allErrs, validateCustomVMXKeys(field.NewPath("spec", "customVMXKeys"), newTyped.Spec.CustomVMXKeys)...)
+	}
+
+	// Allow changes to bootstrapRef, thumbprint, powerOffMode, guestSoftPowerOffTimeout, relocateTo, powerState, customAttributes, allowInPlaceResize, questionAnswers, networkDeviceHotPlugPolicy, customVMXKeys.
+	keys := []string{"bootstrapRef", "thumbprint", "powerOffMode", "guestSoftPowerOffTimeout", "relocateTo", "powerState", "customAttributes", "allowInPlaceResize", "questionAnswers", "networkDeviceHotPlugPolicy", "customVMXKeys"}
+	// Allow changes to the CPU and memory if they can be resized in place.
+	if newTyped.Spec.AllowInPlaceResize {
+		keys = append(keys, "numCPUs", "memoryMiB")
+	}
 	// Allow changes to os only if the old spec has empty OS field.
 	if oldTyped.Spec.OS == "" {
 		keys = append(keys, "os")
@@ -156,7 +199,30 @@ func (webhook *VSphereVMWebhook) ValidateUpdate(_ context.Context, oldRaw runtim
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "cannot be modified"))
 	}
 
-	return nil, AggregateObjErrors(newTyped.GroupVersionKind().GroupKind(), newTyped.Name, allErrs)
+	// Network devices may be appended and the VM may be relocated, which has to comply with the VSpherePlacementPolicies.
+	placementErrs, err := validatePlacementPolicies(ctx, webhook.Client, newTyped.Namespace, field.NewPath("spec"), &newTyped.Spec.VirtualMachineCloneSpec, &oldTyped.Spec.VirtualMachineCloneSpec)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	allErrs = append(allErrs, placementErrs...)
+	relocateErrs, err := validateRelocateToPlacementPolicies(ctx, webhook.Client, newTyped.Namespace, newTyped.Spec.RelocateTo, oldTyped.Spec.RelocateTo)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	allErrs = append(allErrs, relocateErrs...)
+
+	warnings := DeprecationWarnings(util.NetworkSpecDeprecatedFields(field.NewPath("spec", "network"), newTyped.Spec.Network))
+	return warnings, AggregateObjErrors(newTyped.GroupVersionKind().GroupKind(), newTyped.Name, allErrs)
+}
+
+func validateRelocateTo(relocateTo *infrav1.VSphereVMRelocateTo) field.ErrorList {
+	if relocateTo == nil {
+		return nil
+	}
+	if relocateTo.Datastore == "" && relocateTo.Host == "" && relocateTo.ResourcePool == "" {
+		return field.ErrorList{field.Required(field.NewPath("spec", "relocateTo"), "at least one of datastore, host or resourcePool must be set")}
+	}
+	return nil
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
@@ -114,6 +114,12 @@ func TestVSphereVM_ValidateCreate(t *testing.T) {
 			vSphereVM: createVSphereVM(linuxVMName, "foo.com", "", "", "", []string{"192.168.0.1/32", "192.168.0.3/32"}, nil, infrav1.Linux, infrav1.VirtualMachinePowerOpModeTrySoft, &metav1.Duration{Duration: -1234}),
 			wantErr:   true,
 		},
+		{
+			name: "customVMXKeys overriding the bootstrap data",
+			vSphereVM: withCustomVMXKeys(createVSphereVM("vsphere-vm-1", "foo.com", "", "", "", []string{"192.168.0.1/32"}, nil, infrav1.Linux, infrav1.VirtualMachinePowerOpModeTrySoft, nil),
+				map[string]string{"disk.EnableUUID": "TRUE", "guestinfo.ignition.config.data": "foo"}),
+			wantErr: true,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(*testing.T) {
@@ -203,6 +209,69 @@ func TestVSphereVM_ValidateUpdate(t *testing.T) {
 			vSphereVM:    createVSphereVM("vsphere-vm-1", "foo.com", biosUUID, "", "AA:BB:CC:DD:EE", []string{"192.168.0.1/32"}, nil, infrav1.Linux, infrav1.VirtualMachinePowerOpModeTrySoft, nil),
 			wantErr:      false,
 		},
+		{
+			name:         "relocateTo can be set",
+			oldVSphereVM: createVSphereVM("vsphere-vm-1", "foo.com", biosUUID, "", "AA:BB:CC:DD:EE", []string{"192.168.0.1/32"}, nil, infrav1.Linux, infrav1.VirtualMachinePowerOpModeTrySoft, nil),
+			vSphereVM: withRelocateTo(createVSphereVM("vsphere-vm-1", "foo.com", biosUUID, "", "AA:BB:CC:DD:EE", []string{"192.168.0.1/32"}, nil, infrav1.Linux, infrav1.VirtualMachinePowerOpModeTrySoft, nil),
+				&infrav1.VSphereVMRelocateTo{Datastore: "ds-2"}),
+			wantErr: false,
+		},
+		{
+			name:         "relocateTo cannot be set without a target",
+			oldVSphereVM: createVSphereVM("vsphere-vm-1", "foo.com", biosUUID, "", "AA:BB:CC:DD:EE", []string{"192.168.0.1/32"}, nil, infrav1.Linux, infrav1.VirtualMachinePowerOpModeTrySoft, nil),
+			vSphereVM: withRelocateTo(createVSphereVM("vsphere-vm-1", "foo.com", biosUUID, "", "AA:BB:CC:DD:EE", []string{"192.168.0.1/32"}, nil, infrav1.Linux, infrav1.VirtualMachinePowerOpModeTrySoft, nil),
+				&infrav1.VSphereVMRelocateTo{}),
+			wantErr: true,
+		},
+		{
+			name:         "powerState can be changed",
+			oldVSphereVM: createVSphereVM("vsphere-vm-1", "foo.com", biosUUID, "", "AA:BB:CC:DD:EE", []string{"192.168.0.1/32"}, nil, infrav1.Linux, infrav1.VirtualMachinePowerOpModeTrySoft, nil),
+			vSphereVM: withPowerState(createVSphereVM("vsphere-vm-1", "foo.com", biosUUID, "", "AA:BB:CC:DD:EE", []string{"192.168.0.1/32"}, nil, infrav1.Linux, infrav1.VirtualMachinePowerOpModeTrySoft, nil),
+				infrav1.VirtualMachinePowerStatePoweredOff),
+			wantErr: false,
+		},
+		{
+			name:         "customVMXKeys can be updated",
+			oldVSphereVM: createVSphereVM("vsphere-vm-1", "foo.com", biosUUID, "", "AA:BB:CC:DD:EE", []string{"192.168.0.1/32"}, nil, infrav1.Linux, infrav1.VirtualMachinePowerOpModeTrySoft, nil),
+			vSphereVM: withCustomVMXKeys(createVSphereVM("vsphere-vm-1", "foo.com", biosUUID, "", "AA:BB:CC:DD:EE", []string{"192.168.0.1/32"}, nil, infrav1.Linux, infrav1.VirtualMachinePowerOpModeTrySoft, nil),
+				map[string]string{"disk.EnableUUID": "TRUE"}),
+			wantErr: false,
+		},
+		{
+			name:         "customVMXKeys cannot be updated to override the bootstrap data",
+			oldVSphereVM: createVSphereVM("vsphere-vm-1", "foo.com", biosUUID, "", "AA:BB:CC:DD:EE", []string{"192.168.0.1/32"}, nil, infrav1.Linux, infrav1.VirtualMachinePowerOpModeTrySoft, nil),
+			vSphereVM: withCustomVMXKeys(createVSphereVM("vsphere-vm-1", "foo.com", biosUUID, "", "AA:BB:CC:DD:EE", []string{"192.168.0.1/32"}, nil, infrav1.Linux, infrav1.VirtualMachinePowerOpModeTrySoft, nil),
+				map[string]string{"guestinfo.userdata.encoding": "gzip+base64"}),
+			wantErr: true,
+		},
+		{
+			name:         "customAttributes can be updated",
+			oldVSphereVM: createVSphereVM("vsphere-vm-1", "foo.com", biosUUID, "", "AA:BB:CC:DD:EE", []string{"192.168.0.1/32"}, nil, infrav1.Linux, infrav1.VirtualMachinePowerOpModeTrySoft, nil),
+			vSphereVM: withCustomAttributes(createVSphereVM("vsphere-vm-1", "foo.com", biosUUID, "", "AA:BB:CC:DD:EE", []string{"192.168.0.1/32"}, nil, infrav1.Linux, infrav1.VirtualMachinePowerOpModeTrySoft, nil),
+				map[string]string{"cost-center": "1234"}),
+			wantErr: false,
+		},
+		{
+			name:         "questionAnswers can be updated",
+			oldVSphereVM: createVSphereVM("vsphere-vm-1", "foo.com", biosUUID, "", "AA:BB:CC:DD:EE", []string{"192.168.0.1/32"}, nil, infrav1.Linux, infrav1.VirtualMachinePowerOpModeTrySoft, nil),
+			vSphereVM: withQuestionAnswers(createVSphereVM("vsphere-vm-1", "foo.com", biosUUID, "", "AA:BB:CC:DD:EE", []string{"192.168.0.1/32"}, nil, infrav1.Linux, infrav1.VirtualMachinePowerOpModeTrySoft, nil),
+				[]infrav1.VirtualMachineQuestionAnswer{{MessageID: "msg.uuid.altered", Choice: "button.uuid.movedTheVM"}}),
+			wantErr: false,
+		},
+		{
+			name:         "numCPUs and memoryMiB can be updated if in-place resize is allowed",
+			oldVSphereVM: createVSphereVM("vsphere-vm-1", "foo.com", biosUUID, "", "AA:BB:CC:DD:EE", []string{"192.168.0.1/32"}, nil, infrav1.Linux, infrav1.VirtualMachinePowerOpModeTrySoft, nil),
+			vSphereVM: withInPlaceResize(createVSphereVM("vsphere-vm-1", "foo.com", biosUUID, "", "AA:BB:CC:DD:EE", []string{"192.168.0.1/32"}, nil, infrav1.Linux, infrav1.VirtualMachinePowerOpModeTrySoft, nil),
+				true, 4, 4096),
+			wantErr: false,
+		},
+		{
+			name:         "numCPUs cannot be updated if in-place resize is not allowed",
+			oldVSphereVM: createVSphereVM("vsphere-vm-1", "foo.com", biosUUID, "", "AA:BB:CC:DD:EE", []string{"192.168.0.1/32"}, nil, infrav1.Linux, infrav1.VirtualMachinePowerOpModeTrySoft, nil),
+			vSphereVM: withInPlaceResize(createVSphereVM("vsphere-vm-1", "foo.com", biosUUID, "", "AA:BB:CC:DD:EE", []string{"192.168.0.1/32"}, nil, infrav1.Linux, infrav1.VirtualMachinePowerOpModeTrySoft, nil),
+				false, 4, 0),
+			wantErr: true,
+		},
 		{
 			name:         "biosUUID cannot be updated to a different value",
 			oldVSphereVM: createVSphereVM("vsphere-vm-1", "foo.com", "old-uuid", "", "AA:BB:CC:DD:EE", []string{"192.168.0.1/32"}, nil, infrav1.Linux, infrav1.VirtualMachinePowerOpModeTrySoft, nil),
@@ -236,6 +305,7 @@ func createVSphereVM(name, server, biosUUID, preferredAPIServerCIDR, thumbprint
 					Devices:                []infrav1.NetworkDeviceSpec{},
 				},
 				Thumbprint: thumbprint,
+				Template:   "ubuntu-2204-kube-v1.31.0",
 			},
 			BootstrapRef:             bootstrapRef,
 			BiosUUID:                 biosUUID,
@@ -254,3 +324,35 @@ func createVSphereVM(name, server, biosUUID, preferredAPIServerCIDR, thumbprint
 	}
 	return VSphereVM
 }
+
+func withRelocateTo(vsphereVM *infrav1.VSphereVM, relocateTo *infrav1.VSphereVMRelocateTo) *infrav1.VSphereVM {
+	vsphereVM.Spec.RelocateTo = relocateTo
+	return vsphereVM
+}
+
+func withPowerState(vsphereVM *infrav1.VSphereVM, powerState infrav1.VirtualMachinePowerState) *infrav1.VSphereVM {
+	vsphereVM.Spec.PowerState = powerState
+	return vsphereVM
+}
+
+func withCustomVMXKeys(vsphereVM *infrav1.VSphereVM, customVMXKeys map[string]string) *infrav1.VSphereVM {
+	vsphereVM.Spec.CustomVMXKeys = customVMXKeys
+	return vsphereVM
+}
+
+func withCustomAttributes(vsphereVM *infrav1.VSphereVM, customAttributes map[string]string) *infrav1.VSphereVM {
+	vsphereVM.Spec.CustomAttributes = customAttributes
+	return vsphereVM
+}
+
+func withQuestionAnswers(vsphereVM *infrav1.VSphereVM, questionAnswers []infrav1.VirtualMachineQuestionAnswer) *infrav1.VSphereVM {
+	vsphereVM.Spec.QuestionAnswers = questionAnswers
+	return vsphereVM
+}
+
+func withInPlaceResize(vsphereVM *infrav1.VSphereVM, allowInPlaceResize bool, numCPUs int32, memoryMiB int64) *infrav1.VSphereVM {
+	vsphereVM.Spec.AllowInPlaceResize = allowInPlaceResize
+	vsphereVM.Spec.NumCPUs = numCPUs
+	vsphereVM.Spec.MemoryMiB = memoryMiB
+	return vsphereVM
+}
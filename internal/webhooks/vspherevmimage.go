@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-vspherevmimage,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=vspherevmimages,versions=v1beta1,name=validation.vspherevmimage.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
+
+// VSphereVMImageWebhook implements a validation webhook for VSphereVMImage.
+type VSphereVMImageWebhook struct{}
+
+var _ webhook.CustomValidator = &VSphereVMImageWebhook{}
+
+func (webhook *VSphereVMImageWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&infrav1.VSphereVMImage{}).
+		WithValidator(webhook).
+		Complete()
+}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (webhook *VSphereVMImageWebhook) ValidateCreate(_ context.Context, raw runtime.Object) (admission.Warnings, error) {
+	obj, ok := raw.(*infrav1.VSphereVMImage)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a VSphereVMImage but got a %T", raw))
+	}
+	return nil, AggregateObjErrors(obj.GroupVersionKind().GroupKind(), obj.Name, validateVMImage(obj))
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (webhook *VSphereVMImageWebhook) ValidateUpdate(_ context.Context, oldRaw runtime.Object, newRaw runtime.Object) (admission.Warnings, error) {
+	oldTyped, ok := oldRaw.(*infrav1.VSphereVMImage)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a VSphereVMImage but got a %T", oldRaw))
+	}
+	newTyped, ok := newRaw.(*infrav1.VSphereVMImage)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a VSphereVMImage but got a %T", newRaw))
+	}
+
+	allErrs := validateVMImage(newTyped)
+	// An image is promoted by referencing another VSphereVMImage, so only the thumbprint can be changed.
+	oldSpec, newSpec := oldTyped.Spec.DeepCopy(), newTyped.Spec.DeepCopy()
+	oldSpec.Thumbprint, newSpec.Thumbprint = "", ""
+	if !reflect.DeepEqual(oldSpec, newSpec) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "cannot be modified except for thumbprint"))
+	}
+	return nil, AggregateObjErrors(newTyped.GroupVersionKind().GroupKind(), newTyped.Name, allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (webhook *VSphereVMImageWebhook) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateVMImage(obj *infrav1.VSphereVMImage) field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	switch {
+	case obj.Spec.Template == "" && obj.Spec.ContentLibraryItem == nil:
+		allErrs = append(allErrs, field.Required(specPath.Child("template"), "either template or contentLibraryItem must be set"))
+	case obj.Spec.Template != "" && obj.Spec.ContentLibraryItem != nil:
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("contentLibraryItem"), "cannot be set if template is set"))
+	}
+	if len(obj.Spec.Checksums) > 0 && obj.Spec.ContentLibraryItem == nil {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("checksums"), "can only be set if contentLibraryItem is set"))
+	}
+	return allErrs
+}
+
+// validateImageRef validates that exactly one of the template and the image reference of a
+// VirtualMachineCloneSpec is set.
+func validateImageRef(fldPath *field.Path, spec *infrav1.VirtualMachineCloneSpec) field.ErrorList {
+	var allErrs field.ErrorList
+	switch {
+	case spec.Template == "" && spec.ImageRef == nil:
+		allErrs = append(allErrs, field.Required(fldPath.Child("template"), "either template or imageRef must be set"))
+	case spec.Template != "" && spec.ImageRef != nil:
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("imageRef"), "cannot be set if template is set"))
+	}
+	if spec.ImageRef != nil && spec.TemplateSource != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("templateSource"), "cannot be set if imageRef is set"))
+	}
+	return allErrs
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func TestVSphereVMImage_ValidateCreate(t *testing.T) {
+	g := NewWithT(t)
+	tests := []struct {
+		name    string
+		image   *infrav1.VSphereVMImage
+		wantErr bool
+	}{
+		{
+			name:  "successful VSphereVMImage creation with template",
+			image: createVSphereVMImage("ubuntu-2204-kube-v1.31.0", nil, nil),
+		},
+		{
+			name:  "successful VSphereVMImage creation with content library item",
+			image: createVSphereVMImage("", &infrav1.ContentLibraryItemReference{Library: "images", Name: "ubuntu-2204-kube-v1.31.0"}, []infrav1.VSphereVMImageChecksum{{Algorithm: infrav1.ChecksumAlgorithmSHA256, Value: "abc"}}),
+		},
+		{
+			name:    "neither template nor content library item set",
+			image:   createVSphereVMImage("", nil, nil),
+			wantErr: true,
+		},
+		{
+			name:    "both template and content library item set",
+			image:   createVSphereVMImage("ubuntu-2204-kube-v1.31.0", &infrav1.ContentLibraryItemReference{Library: "images", Name: "ubuntu-2204-kube-v1.31.0"}, nil),
+			wantErr: true,
+		},
+		{
+			name:    "checksums set with template",
+			image:   createVSphereVMImage("ubuntu-2204-kube-v1.31.0", nil, []infrav1.VSphereVMImageChecksum{{Algorithm: infrav1.ChecksumAlgorithmSHA256, Value: "abc"}}),
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(*testing.T) {
+			webhook := &VSphereVMImageWebhook{}
+			_, err := webhook.ValidateCreate(context.Background(), tc.image)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestVSphereVMImage_ValidateUpdate(t *testing.T) {
+	g := NewWithT(t)
+	tests := []struct {
+		name     string
+		oldImage *infrav1.VSphereVMImage
+		image    *infrav1.VSphereVMImage
+		wantErr  bool
+	}{
+		{
+			name:     "thumbprint can be updated",
+			oldImage: createVSphereVMImage("ubuntu-2204-kube-v1.31.0", nil, nil),
+			image: func() *infrav1.VSphereVMImage {
+				i := createVSphereVMImage("ubuntu-2204-kube-v1.31.0", nil, nil)
+				i.Spec.Thumbprint = "AA:BB:CC"
+				return i
+			}(),
+		},
+		{
+			name:     "template cannot be updated",
+			oldImage: createVSphereVMImage("ubuntu-2204-kube-v1.31.0", nil, nil),
+			image:    createVSphereVMImage("ubuntu-2204-kube-v1.32.0", nil, nil),
+			wantErr:  true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(*testing.T) {
+			webhook := &VSphereVMImageWebhook{}
+			_, err := webhook.ValidateUpdate(context.Background(), tc.oldImage, tc.image)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func createVSphereVMImage(template string, item *infrav1.ContentLibraryItemReference, checksums []infrav1.VSphereVMImageChecksum) *infrav1.VSphereVMImage {
+	return &infrav1.VSphereVMImage{
+		Spec: infrav1.VSphereVMImageSpec{
+			Server:             "foo.com",
+			Template:           template,
+			ContentLibraryItem: item,
+			Checksums:          checksums,
+		},
+	}
+}
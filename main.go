@@ -28,12 +28,14 @@ import (
 
 	perrors "github.com/pkg/errors"
 	"github.com/spf13/pflag"
+	"golang.org/x/time/rate"
 	"gopkg.in/fsnotify.v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
 	cliflag "k8s.io/component-base/cli/flag"
 	"k8s.io/component-base/logs"
 	logsv1 "k8s.io/component-base/logs/api/v1"
@@ -49,6 +51,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	ctrlmgr "sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
@@ -59,7 +62,9 @@ import (
 	"sigs.k8s.io/cluster-api-provider-vsphere/internal/webhooks"
 	vmwarewebhooks "sigs.k8s.io/cluster-api-provider-vsphere/internal/webhooks/vmware"
 	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/health"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/manager"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/record"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/session"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/version"
 )
@@ -81,16 +86,23 @@ var (
 	syncPeriod                  time.Duration
 	webhookOpts                 webhook.Options
 	watchNamespace              string
+	dependencyHealthAddr        string
 
 	clusterCacheConcurrency           int
 	vSphereClusterConcurrency         int
 	vSphereMachineConcurrency         int
 	vSphereMachineTemplateConcurrency int
+	vSphereMachinePoolConcurrency     int
+	vSphereIPPoolConcurrency          int
 	providerServiceAccountConcurrency int
 	serviceDiscoveryConcurrency       int
 	vSphereVMConcurrency              int
 	vSphereClusterIdentityConcurrency int
 	vSphereDeploymentZoneConcurrency  int
+	vSphereVMImageConcurrency         int
+
+	rateLimiterBaseDelay time.Duration
+	rateLimiterMaxDelay  time.Duration
 
 	managerOptions = capiflags.ManagerOptions{}
 
@@ -122,6 +134,12 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&vSphereMachineTemplateConcurrency, "vspheremachinetemplate-concurrency", 10,
 		"Number of vSphere machine templates to process simultaneously")
 
+	fs.IntVar(&vSphereMachinePoolConcurrency, "vspheremachinepool-concurrency", 10,
+		"Number of vSphere machine pools to process simultaneously")
+
+	fs.IntVar(&vSphereIPPoolConcurrency, "vsphereippool-concurrency", 10,
+		"Number of IP address claims of vSphere IP pools to process simultaneously")
+
 	fs.IntVar(&providerServiceAccountConcurrency, "providerserviceaccount-concurrency", 10,
 		"Number of provider service accounts to process simultaneously")
 
@@ -137,6 +155,21 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&vSphereDeploymentZoneConcurrency, "vspheredeploymentzone-concurrency", 10,
 		"Number of vSphere deployment zones to process simultaneously")
 
+	fs.IntVar(&vSphereVMImageConcurrency, "vspherevmimage-concurrency", 10,
+		"Number of vSphere VM images to process simultaneously")
+
+	fs.IntVar(&managerOpts.Shard.Count, "shard-count", 1,
+		"Number of shards the reconciliation is split into by namespace. Each shard is reconciled by the managers started with the same --shard-index, which elect their own leader.")
+
+	fs.IntVar(&managerOpts.Shard.Index, "shard-index", 0,
+		"Index of the shard reconciled by the manager, between 0 and --shard-count - 1.")
+
+	fs.DurationVar(&rateLimiterBaseDelay, "controller-rate-limiter-base-delay", 5*time.Millisecond,
+		"Delay after which an object is requeued by the controllers after the first failed reconcile. The delay is doubled with every consecutive failure.")
+
+	fs.DurationVar(&rateLimiterMaxDelay, "controller-rate-limiter-max-delay", 1000*time.Second,
+		"Maximum delay after which an object is requeued by the controllers after consecutive failed reconciles.")
+
 	fs.StringVar(
 		&managerOpts.PodName,
 		"pod-name",
@@ -150,6 +183,104 @@ func InitFlags(fs *pflag.FlagSet) {
 		"path to CAPV's credentials file",
 	)
 
+	fs.StringVar(
+		&managerOpts.VCenterProxyURL,
+		"vcenter-proxy-url",
+		"",
+		"URL of the HTTP or SOCKS5 proxy used to connect to vCenter, e.g. http://proxy.example.com:3128. Can be overridden by VSphereCluster.spec.proxy.",
+	)
+
+	fs.StringSliceVar(
+		&managerOpts.VCenterNoProxy,
+		"vcenter-no-proxy",
+		nil,
+		"Comma-separated list of hosts, domains, IP addresses and CIDRs which are connected to directly instead of through the vCenter proxy.",
+	)
+
+	fs.StringVar(
+		&managerOpts.VCenterCABundleFile,
+		"vcenter-ca-bundle-file",
+		"",
+		"path to a file with PEM encoded CA certificates used to verify the certificates of vCenter.",
+	)
+
+	fs.Float32Var(
+		&managerOpts.VCenterRateLimiter.QPS,
+		"vcenter-api-qps",
+		50,
+		"Maximum number of calls per second to the API of a vCenter, shared by all sessions to the vCenter. Rate limiting is disabled if set to 0.",
+	)
+
+	fs.IntVar(
+		&managerOpts.VCenterRateLimiter.Burst,
+		"vcenter-api-burst",
+		100,
+		"Maximum burst of calls to the API of a vCenter.",
+	)
+
+	fs.IntVar(
+		&managerOpts.VCenterRateLimiter.CircuitBreakerThreshold,
+		"vcenter-circuit-breaker-threshold",
+		5,
+		"Number of consecutive calls to the API of a vCenter failing because vCenter is busy or out of sessions after which calls are rejected for the circuit breaker cooldown. Circuit breaking is disabled if set to 0.",
+	)
+
+	fs.DurationVar(
+		&managerOpts.VCenterRateLimiter.CircuitBreakerCooldown,
+		"vcenter-circuit-breaker-cooldown",
+		30*time.Second,
+		"Time calls to the API of a vCenter are rejected once the circuit breaker opened.",
+	)
+
+	fs.DurationVar(
+		&managerOpts.EventAggregationWindow,
+		"event-aggregation-window",
+		record.DefaultAggregationWindow,
+		"Window in which identical events, e.g. the same vCenter fault, recorded for an object are aggregated into a single event with a count. Events are not aggregated if set to 0.",
+	)
+
+	fs.DurationVar(
+		&managerOpts.ResourceUsageRefreshInterval,
+		"resource-usage-refresh-interval",
+		5*time.Minute,
+		"Interval in which the CPU, memory and storage of the VMs of a VSphereCluster are aggregated into its status.resourceUsage. The resource usage is not reported if set to 0.",
+	)
+
+	fs.DurationVar(
+		&managerOpts.PreTerminateHookTimeout,
+		"pre-terminate-hook-timeout",
+		0,
+		"Time after the deletion of a VSphereMachine or VSphereVM after which its VM is deleted even if pre-terminate delete hooks remain. Deletion waits for the hooks to be removed indefinitely if set to 0.",
+	)
+
+	fs.DurationVar(
+		&managerOpts.VMDeletionFailureTimeout,
+		"vm-deletion-failure-timeout",
+		0,
+		"Time after the deletion of a VSphereVM after which its finalizer is removed if destroying the VM keeps failing and the VM is no longer found in vCenter by its UUID, e.g. because it is orphaned. Deletion is retried indefinitely if set to 0.",
+	)
+
+	fs.DurationVar(
+		&managerOpts.VMTaskTimeout,
+		"vm-task-timeout",
+		0,
+		"Time after which a clone or reconfigure task of a VSphereVM which is still queued or running is canceled, its partially cloned VM is destroyed and the operation is retried. Tasks are waited for indefinitely if set to 0.",
+	)
+
+	fs.IntVar(
+		&managerOpts.ClusterTeardownConcurrency,
+		"cluster-teardown-concurrency",
+		10,
+		"Maximum number of VMs of a deleted cluster which are destroyed at the same time. The worker VMs of a deleted cluster are always destroyed before its control plane VMs. The number of VMs is not limited if set to 0.",
+	)
+
+	fs.IntVar(
+		&managerOpts.ControlPlaneConcurrency,
+		"control-plane-concurrency",
+		0,
+		"Number of control plane VSphereMachines and VSphereVMs to process simultaneously in work queues separate from the ones of worker machines, so that control plane machines are not delayed by large worker scale-ups. Control plane machines share the work queues of worker machines if set to 0.",
+	)
+
 	fs.StringVar(
 		&managerOpts.NetworkProvider,
 		"network-provider",
@@ -157,6 +288,83 @@ func InitFlags(fs *pflag.FlagSet) {
 		"network provider to be used by Supervisor based clusters.",
 	)
 
+	fs.StringVar(
+		&managerOpts.ControlPlaneEndpointDNSProvider,
+		"control-plane-endpoint-dns-provider",
+		"",
+		"DNS provider used to publish DNS records for the control plane endpoint of Supervisor based clusters setting spec.controlPlaneEndpointDNS. Supported values: external-dns-webhook. DNS records are not published if not set.",
+	)
+
+	fs.StringVar(
+		&managerOpts.ControlPlaneEndpointDNSWebhookURL,
+		"control-plane-endpoint-dns-webhook-url",
+		"",
+		"URL of the external-dns webhook provider (e.g. route53, infoblox) used by the external-dns-webhook DNS provider.",
+	)
+
+	fs.StringSliceVar(
+		&managerOpts.SupervisorAPIServerEndpoints,
+		"supervisor-apiserver-endpoints",
+		nil,
+		"Comma-separated list of IP addresses or DNS names of the Supervisor API server advertised to Supervisor based workload clusters instead of the discovered endpoint. Multiple endpoints can be set for highly available Supervisors.",
+	)
+
+	fs.BoolVar(
+		&managerOpts.DryRun,
+		"dry-run",
+		false,
+		"Log the vSphere mutating operations, e.g. clone, reconfigure and power operations, instead of executing them. Can be enabled per object using the capv.cluster.x-k8s.io/dry-run annotation.",
+	)
+
+	fs.BoolVar(
+		&managerOpts.Tracing.Enabled,
+		"enable-tracing",
+		false,
+		"Export OpenTelemetry spans of the reconcilers and of the calls to vCenter via OTLP.",
+	)
+
+	fs.StringVar(
+		&managerOpts.Tracing.Endpoint,
+		"tracing-otlp-endpoint",
+		"localhost:4317",
+		"host:port of the OTLP gRPC endpoint the spans are exported to.",
+	)
+
+	fs.BoolVar(
+		&managerOpts.Tracing.Insecure,
+		"tracing-otlp-insecure",
+		false,
+		"Disable TLS for the connection to the OTLP endpoint.",
+	)
+
+	fs.Float64Var(
+		&managerOpts.Tracing.SamplingRatio,
+		"tracing-sampling-ratio",
+		1,
+		"Ratio of the traces which are sampled, between 0 and 1.",
+	)
+
+	fs.DurationVar(
+		&managerOpts.Diagnostics.Interval,
+		"diagnostics-interval",
+		0,
+		"Interval in which a reconcile profiling report with the queue depth and reconcile durations of each controller and the slowest reconciled objects is created. Reports are disabled if set to 0.",
+	)
+
+	fs.IntVar(
+		&managerOpts.Diagnostics.TopN,
+		"diagnostics-top-n",
+		10,
+		"Number of the objects with the slowest reconciles listed in a reconcile profiling report.",
+	)
+
+	fs.StringVar(
+		&managerOpts.Diagnostics.ConfigMapName,
+		"diagnostics-configmap",
+		"",
+		"Name of the ConfigMap in the namespace of the manager the reconcile profiling reports are written to. Reports are logged if not set.",
+	)
+
 	// Flags common between CAPI and CAPV
 
 	logsv1.AddFlags(logOptions, fs)
@@ -216,6 +424,10 @@ func InitFlags(fs *pflag.FlagSet) {
 		"The address the health endpoint binds to.",
 	)
 
+	fs.StringVar(&dependencyHealthAddr, "dependency-health-addr", "",
+		"The address the endpoint reporting the status of the CRDs and vCenters the manager depends on as JSON binds to. Disabled if empty.",
+	)
+
 	capiflags.AddManagerOptions(fs, &managerOptions)
 	feature.MutableGates.AddFlag(fs)
 }
@@ -224,6 +436,12 @@ func InitFlags(fs *pflag.FlagSet) {
 // +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
 // +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
 
+// Add RBAC for the VSphereClusterSettings applied by the defaulting webhooks.
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=vsphereclustersettings,verbs=get;list;watch
+
+// Add RBAC for the VSpherePlacementPolicies enforced by the validating webhooks.
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=vsphereplacementpolicies,verbs=get;list;watch
+
 func main() {
 	InitFlags(pflag.CommandLine)
 	pflag.CommandLine.SetNormalizeFunc(cliflag.WordSepNormalizeFunc)
@@ -259,13 +477,16 @@ func main() {
 		goruntime.SetBlockProfileRate(1)
 	}
 
-	setupLog.Info(fmt.Sprintf("Feature gates: %+v\n", feature.Gates))
+	setupLog.Info("Feature gates", "featureGates", fmt.Sprintf("%+v", feature.Gates))
 
 	managerOpts.Cache.SyncPeriod = &syncPeriod
 	managerOpts.LeaseDuration = &leaderElectionLeaseDuration
 	managerOpts.RenewDeadline = &leaderElectionRenewDeadline
 	managerOpts.RetryPeriod = &leaderElectionRetryPeriod
 
+	// The dependency checker is configured once the mode of the manager has been detected.
+	dependencyChecker := &health.DependencyChecker{}
+
 	// Create a function that adds all the controllers and webhooks to the manager.
 	addToManager := func(ctx context.Context, controllerCtx *capvcontext.ControllerManagerContext, mgr ctrlmgr.Manager) error {
 		clusterCache, err := setupClusterCache(ctx, mgr)
@@ -299,12 +520,17 @@ func main() {
 			return fmt.Errorf("neither supervisor nor govmomi CRDs detected: %w", kerrors.NewAggregate([]error{err, errGovmomi, errSupervisor}))
 		}
 
+		dependencyChecker.Client = mgr.GetClient()
+		dependencyChecker.RESTMapper = mgr.GetRESTMapper()
+		dependencyChecker.CRDs = requiredCRDs(isGovmomiCRDLoaded, isSupervisorCRDLoaded)
+		dependencyChecker.CheckVCenter = isGovmomiCRDLoaded
+
 		if isGovmomiCRDLoaded {
 			if err := setupVAPIControllers(ctx, controllerCtx, mgr, clusterCache); err != nil {
 				return fmt.Errorf("setupVAPIControllers: %w", err)
 			}
 		} else {
-			setupLog.Info(fmt.Sprintf("CRD for %s not loaded, skipping.", govmomiGVR.String()))
+			setupLog.Info("CRD not loaded, skipping", "resource", govmomiGVR.String())
 		}
 
 		if isSupervisorCRDLoaded {
@@ -312,7 +538,7 @@ func main() {
 				return fmt.Errorf("setupSupervisorControllers: %w", err)
 			}
 		} else {
-			setupLog.Info(fmt.Sprintf("CRD for %s not loaded, skipping.", supervisorGVR.String()))
+			setupLog.Info("CRD not loaded, skipping", "resource", supervisorGVR.String())
 		}
 
 		return nil
@@ -337,7 +563,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	setupChecks(mgr)
+	setupChecks(mgr, dependencyChecker)
 
 	setupLog.Info("Starting manager", "version", version.Get().String())
 	if err := mgr.Start(ctx); err != nil {
@@ -346,7 +572,7 @@ func main() {
 	}
 
 	// initialize notifier for capv-manager-bootstrap-credentials
-	watch, err := manager.InitializeWatch(mgr.GetControllerManagerContext(), &managerOpts)
+	watch, err := manager.InitializeWatch(ctx, &managerOpts)
 	if err != nil {
 		setupLog.Error(err, "failed to initialize watch on CAPV credentials file")
 		os.Exit(1)
@@ -358,19 +584,23 @@ func main() {
 }
 
 func setupVAPIControllers(ctx context.Context, controllerCtx *capvcontext.ControllerManagerContext, mgr ctrlmgr.Manager, clusterCache clustercache.ClusterCache) error {
+	if err := (&webhooks.VSphereClusterWebhook{Client: mgr.GetClient()}).SetupWebhookWithManager(mgr); err != nil {
+		return err
+	}
+
 	if err := (&webhooks.VSphereClusterTemplateWebhook{}).SetupWebhookWithManager(mgr); err != nil {
 		return err
 	}
 
-	if err := (&webhooks.VSphereMachineWebhook{}).SetupWebhookWithManager(mgr); err != nil {
+	if err := (&webhooks.VSphereMachineWebhook{Client: mgr.GetClient()}).SetupWebhookWithManager(mgr); err != nil {
 		return err
 	}
 
-	if err := (&webhooks.VSphereMachineTemplateWebhook{}).SetupWebhookWithManager(mgr); err != nil {
+	if err := (&webhooks.VSphereMachineTemplateWebhook{Client: mgr.GetClient()}).SetupWebhookWithManager(mgr); err != nil {
 		return err
 	}
 
-	if err := (&webhooks.VSphereVMWebhook{}).SetupWebhookWithManager(mgr); err != nil {
+	if err := (&webhooks.VSphereVMWebhook{Client: mgr.GetClient()}).SetupWebhookWithManager(mgr); err != nil {
 		return err
 	}
 
@@ -382,6 +612,24 @@ func setupVAPIControllers(ctx context.Context, controllerCtx *capvcontext.Contro
 		return err
 	}
 
+	if err := (&webhooks.VSpherePlacementPolicyWebhook{}).SetupWebhookWithManager(mgr); err != nil {
+		return err
+	}
+
+	if err := (&webhooks.VSphereForceDeletePolicyWebhook{}).SetupWebhookWithManager(mgr); err != nil {
+		return err
+	}
+
+	if err := (&webhooks.VSphereVMImageWebhook{}).SetupWebhookWithManager(mgr); err != nil {
+		return err
+	}
+
+	if feature.Gates.Enabled(feature.MachinePool) {
+		if err := (&webhooks.VSphereMachinePoolWebhook{Client: mgr.GetClient()}).SetupWebhookWithManager(mgr); err != nil {
+			return err
+		}
+	}
+
 	if err := controllers.AddClusterControllerToManager(ctx, controllerCtx, mgr, false, concurrency(vSphereClusterConcurrency)); err != nil {
 		return err
 	}
@@ -391,14 +639,46 @@ func setupVAPIControllers(ctx context.Context, controllerCtx *capvcontext.Contro
 	if err := controllers.AddVMControllerToManager(ctx, controllerCtx, mgr, clusterCache, concurrency(vSphereVMConcurrency)); err != nil {
 		return err
 	}
+	if feature.Gates.Enabled(feature.HostEvacuation) {
+		if err := controllers.AddHostEvacuationControllerToManager(ctx, controllerCtx, mgr, clusterCache, concurrency(vSphereVMConcurrency)); err != nil {
+			return err
+		}
+	}
+	if err := controllers.AddInfraHealthControllerToManager(ctx, controllerCtx, mgr, concurrency(vSphereClusterConcurrency)); err != nil {
+		return err
+	}
+	if controllerCtx.ResourceUsageRefreshInterval > 0 {
+		if err := controllers.AddResourceUsageControllerToManager(ctx, controllerCtx, mgr, concurrency(vSphereClusterConcurrency)); err != nil {
+			return err
+		}
+	}
+	if err := controllers.AddVSphereMachineTemplateControllerToManager(ctx, controllerCtx, mgr, concurrency(vSphereMachineTemplateConcurrency)); err != nil {
+		return err
+	}
+	if feature.Gates.Enabled(feature.MachinePool) {
+		if err := controllers.AddVSphereMachinePoolControllerToManager(ctx, controllerCtx, mgr, concurrency(vSphereMachinePoolConcurrency)); err != nil {
+			return err
+		}
+	}
+	if feature.Gates.Enabled(feature.VSphereIPPool) {
+		if err := controllers.AddVSphereIPPoolControllerToManager(ctx, controllerCtx, mgr, concurrency(vSphereIPPoolConcurrency)); err != nil {
+			return err
+		}
+	}
 	if err := controllers.AddVsphereClusterIdentityControllerToManager(ctx, controllerCtx, mgr, concurrency(vSphereClusterIdentityConcurrency)); err != nil {
 		return err
 	}
+	if err := controllers.AddVSphereVMImageControllerToManager(ctx, controllerCtx, mgr, concurrency(vSphereVMImageConcurrency)); err != nil {
+		return err
+	}
 
 	return controllers.AddVSphereDeploymentZoneControllerToManager(ctx, controllerCtx, mgr, concurrency(vSphereDeploymentZoneConcurrency))
 }
 
 func setupSupervisorControllers(ctx context.Context, controllerCtx *capvcontext.ControllerManagerContext, mgr ctrlmgr.Manager, clusterCache clustercache.ClusterCache) error {
+	if err := (&vmwarewebhooks.VSphereClusterWebhook{}).SetupWebhookWithManager(mgr); err != nil {
+		return err
+	}
 	if err := (&vmwarewebhooks.VSphereMachineTemplateWebhook{}).SetupWebhookWithManager(mgr); err != nil {
 		return err
 	}
@@ -424,18 +704,55 @@ func setupSupervisorControllers(ctx context.Context, controllerCtx *capvcontext.
 	return vmware.AddServiceDiscoveryControllerToManager(ctx, controllerCtx, mgr, clusterCache, concurrency(serviceDiscoveryConcurrency))
 }
 
-func setupChecks(mgr ctrlmgr.Manager) {
+func setupChecks(mgr ctrlmgr.Manager, dependencyChecker *health.DependencyChecker) {
 	if err := mgr.AddReadyzCheck("webhook", mgr.GetWebhookServer().StartedChecker()); err != nil {
 		setupLog.Error(err, "unable to create ready check")
 		os.Exit(1)
 	}
 
+	if err := mgr.AddReadyzCheck("dependencies", dependencyChecker.ReadyzCheck); err != nil {
+		setupLog.Error(err, "unable to create dependencies ready check")
+		os.Exit(1)
+	}
+
+	if dependencyHealthAddr != "" {
+		if err := mgr.Add(&health.Server{Checker: dependencyChecker, BindAddress: dependencyHealthAddr}); err != nil {
+			setupLog.Error(err, "unable to create dependency health server")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("webhook", mgr.GetWebhookServer().StartedChecker()); err != nil {
 		setupLog.Error(err, "unable to create health check")
 		os.Exit(1)
 	}
 }
 
+// requiredCRDs returns the resources of the CRDs which have to be served for the manager to be ready.
+func requiredCRDs(govmomiMode, supervisorMode bool) []schema.GroupVersionResource {
+	crds := []schema.GroupVersionResource{
+		clusterv1.GroupVersion.WithResource("clusters"),
+		clusterv1.GroupVersion.WithResource("machines"),
+	}
+	if govmomiMode {
+		crds = append(crds,
+			infrav1.GroupVersion.WithResource("vsphereclusters"),
+			infrav1.GroupVersion.WithResource("vspheremachines"),
+			infrav1.GroupVersion.WithResource("vspherevms"),
+			infrav1.GroupVersion.WithResource("vsphereclusteridentities"),
+			infrav1.GroupVersion.WithResource("vspheredeploymentzones"),
+			infrav1.GroupVersion.WithResource("vspherefailuredomains"),
+		)
+	}
+	if supervisorMode {
+		crds = append(crds,
+			vmwarev1.GroupVersion.WithResource("vsphereclusters"),
+			vmwarev1.GroupVersion.WithResource("vspheremachines"),
+		)
+	}
+	return crds
+}
+
 func isCRDDeployed(mgr ctrlmgr.Manager, gvr schema.GroupVersionResource) (bool, error) {
 	_, err := mgr.GetRESTMapper().KindFor(gvr)
 	if err != nil {
@@ -447,8 +764,19 @@ func isCRDDeployed(mgr ctrlmgr.Manager, gvr schema.GroupVersionResource) (bool,
 	return true, nil
 }
 
+// concurrency returns the options of a controller processing c objects simultaneously.
+// Every controller gets its own rate limiter, as rate limiters track the failures per object.
+// Requests for objects in namespaces of other shards are dropped by the work queue.
 func concurrency(c int) controller.Options {
-	return controller.Options{MaxConcurrentReconciles: c}
+	return controller.Options{
+		MaxConcurrentReconciles: c,
+		NewQueue:                managerOpts.Shard.NewQueue(nil),
+		RateLimiter: workqueue.NewTypedMaxOfRateLimiter(
+			workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](rateLimiterBaseDelay, rateLimiterMaxDelay),
+			// Same overall limit of 10 qps with a bucket size of 100 as the default controller rate limiter.
+			&workqueue.TypedBucketRateLimiter[reconcile.Request]{Limiter: rate.NewLimiter(rate.Limit(10), 100)},
+		),
+	}
 }
 
 func setupClusterCache(ctx context.Context, mgr ctrlmgr.Manager) (clustercache.ClusterCache, error) {
@@ -474,6 +802,8 @@ func setupClusterCache(ctx context.Context, mgr ctrlmgr.Manager) (clustercache.C
 					// Don't cache ConfigMaps & Secrets.
 					&corev1.ConfigMap{},
 					&corev1.Secret{},
+					// Don't cache Pods, they are only listed per Node when draining.
+					&corev1.Pod{},
 				},
 			},
 		},
@@ -21,6 +21,7 @@ import (
 	"context"
 
 	"github.com/stretchr/testify/mock"
+	"github.com/vmware/govmomi/vim25/types"
 
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/clustermodule"
 	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
@@ -44,3 +45,8 @@ func (f *CMService) Remove(ctx context.Context, clusterCtx *capvcontext.ClusterC
 	args := f.Called(ctx, clusterCtx, moduleUUID)
 	return args.Error(0)
 }
+
+func (f *CMService) AddMissingMembers(ctx context.Context, clusterCtx *capvcontext.ClusterContext, wrapper clustermodule.Wrapper, moduleUUID string, vmRefs []types.ManagedObjectReference) (int, error) {
+	args := f.Called(ctx, clusterCtx, wrapper, moduleUUID, vmRefs)
+	return args.Int(0), args.Error(1)
+}
@@ -20,6 +20,8 @@ package clustermodule
 import (
 	"context"
 
+	"github.com/vmware/govmomi/vim25/types"
+
 	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
 )
 
@@ -30,4 +32,8 @@ type Service interface {
 	DoesExist(ctx context.Context, clusterCtx *capvcontext.ClusterContext, wrapper Wrapper, moduleUUID string) (bool, error)
 
 	Remove(ctx context.Context, clusterCtx *capvcontext.ClusterContext, moduleUUID string) error
+
+	// AddMissingMembers adds the given VMs which are not members of the cluster module yet
+	// and returns the number of VMs added.
+	AddMissingMembers(ctx context.Context, clusterCtx *capvcontext.ClusterContext, wrapper Wrapper, moduleUUID string, vmRefs []types.ManagedObjectReference) (int, error)
 }
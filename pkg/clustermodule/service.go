@@ -88,23 +88,50 @@ func (s *service) Create(ctx context.Context, clusterCtx *capvcontext.ClusterCon
 }
 
 func (s *service) DoesExist(ctx context.Context, clusterCtx *capvcontext.ClusterContext, wrapper Wrapper, moduleUUID string) (bool, error) {
+	vCenterSession, err := s.fetchSessionForWrapper(ctx, clusterCtx, wrapper)
+	if err != nil {
+		return false, err
+	}
+
+	provider := clustermodules.NewProvider(vCenterSession.TagManager.Client)
+	return provider.DoesModuleExist(ctx, moduleUUID)
+}
+
+func (s *service) AddMissingMembers(ctx context.Context, clusterCtx *capvcontext.ClusterContext, wrapper Wrapper, moduleUUID string, vmRefs []types.ManagedObjectReference) (int, error) {
+	if len(vmRefs) == 0 {
+		return 0, nil
+	}
+
+	vCenterSession, err := s.fetchSessionForWrapper(ctx, clusterCtx, wrapper)
+	if err != nil {
+		return 0, err
+	}
+
+	provider := clustermodules.NewProvider(vCenterSession.TagManager.Client)
+	added, err := provider.AddMissingMoRefsToModule(ctx, moduleUUID, vmRefs)
+	if err != nil {
+		return 0, errors.Wrapf(err, "error adding members to cluster module %s", moduleUUID)
+	}
+	return len(added), nil
+}
+
+// fetchSessionForWrapper returns the vCenter session for the machine template of the object.
+func (s *service) fetchSessionForWrapper(ctx context.Context, clusterCtx *capvcontext.ClusterContext, wrapper Wrapper) (*session.Session, error) {
 	templateRef, err := s.fetchTemplateRef(ctx, wrapper)
 	if err != nil {
-		return false, errors.Wrapf(err, "error fetching template ref for object %s/%s", wrapper.GetNamespace(), wrapper.GetName())
+		return nil, errors.Wrapf(err, "error fetching template ref for object %s/%s", wrapper.GetNamespace(), wrapper.GetName())
 	}
 
 	template, err := s.fetchMachineTemplate(ctx, wrapper, templateRef.Name)
 	if err != nil {
-		return false, errors.Wrapf(err, "error fetching machine template for object %s/%s", wrapper.GetNamespace(), wrapper.GetName())
+		return nil, errors.Wrapf(err, "error fetching machine template for object %s/%s", wrapper.GetNamespace(), wrapper.GetName())
 	}
 
 	vCenterSession, err := s.fetchSessionForObject(ctx, clusterCtx, template)
 	if err != nil {
-		return false, errors.Wrapf(err, "error fetching session for object %s/%s", wrapper.GetNamespace(), wrapper.GetName())
+		return nil, errors.Wrapf(err, "error fetching session for object %s/%s", wrapper.GetNamespace(), wrapper.GetName())
 	}
-
-	provider := clustermodules.NewProvider(vCenterSession.TagManager.Client)
-	return provider.DoesModuleExist(ctx, moduleUUID)
+	return vCenterSession, nil
 }
 
 func (s *service) Remove(ctx context.Context, clusterCtx *capvcontext.ClusterContext, moduleUUID string) error {
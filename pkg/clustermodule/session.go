@@ -42,17 +42,24 @@ func (s *service) fetchSessionForObject(ctx context.Context, clusterCtx *capvcon
 func (s *service) newParams(clusterCtx capvcontext.ClusterContext) *session.Params {
 	return session.NewParams().
 		WithServer(clusterCtx.VSphereCluster.Spec.Server).
-		WithThumbprint(clusterCtx.VSphereCluster.Spec.Thumbprint)
+		WithThumbprint(clusterCtx.VSphereCluster.Spec.Thumbprint).
+		WithProxy(session.ProxyForCluster(clusterCtx.VSphereCluster, s.ControllerManagerContext.VCenterProxy))
 }
 
 func (s *service) fetchSession(ctx context.Context, clusterCtx *capvcontext.ClusterContext, params *session.Params) (*session.Session, error) {
+	tlsConfig, err := identity.GetTLSConfig(ctx, s.Client, clusterCtx.VSphereCluster, s.ControllerManagerContext.Namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get TLS configuration")
+	}
+	params = params.WithTLSConfig(tlsConfig)
+
 	if clusterCtx.VSphereCluster.Spec.IdentityRef != nil {
 		creds, err := identity.GetCredentials(ctx, s.Client, clusterCtx.VSphereCluster, s.ControllerManagerContext.Namespace)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to get credentials from IdentityRef")
 		}
 
-		params = params.WithUserInfo(creds.Username, creds.Password)
+		params = params.WithUserInfo(creds.Username, creds.Password).WithTokenSource(creds.TokenSource).WithAllowedTargets(creds.AllowedTargets)
 		return session.GetOrCreate(ctx, params)
 	}
 
@@ -17,14 +17,79 @@ limitations under the License.
 package clustermodule
 
 import (
+	"bytes"
 	"sort"
+	"strings"
+	"text/template"
+	"time"
 
 	"github.com/blang/semver"
+	"github.com/pkg/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
 	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
 )
 
+// DefaultNameTemplate is the template used to generate the names of the cluster modules
+// of MachineDeployments if no name template is defined.
+const DefaultNameTemplate = "{{ .machineDeployment.name }}"
+
+// GenerateName generates the name of the cluster module used by the object.
+// Objects for which the same name is generated share the cluster module.
+// The control plane always uses a dedicated cluster module named after the object.
+func GenerateName(vsphereCluster *infrav1.VSphereCluster, wrapper Wrapper) (string, error) {
+	if wrapper.IsControlPlane() {
+		return wrapper.GetName(), nil
+	}
+
+	nameTemplate := DefaultNameTemplate
+	if lifecycle := vsphereCluster.Spec.ClusterModuleLifecycle; lifecycle != nil && lifecycle.NameTemplate != nil {
+		nameTemplate = *lifecycle.NameTemplate
+	}
+
+	tpl, err := template.New("name").Option("missingkey=error").Parse(nameTemplate)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse cluster module name template %q", nameTemplate)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := tpl.Execute(buf, map[string]interface{}{
+		"cluster": map[string]interface{}{
+			"name": wrapper.GetLabels()[clusterv1.ClusterNameLabel],
+		},
+		"machineDeployment": map[string]interface{}{
+			"name":   wrapper.GetName(),
+			"labels": wrapper.GetLabels(),
+		},
+	}); err != nil {
+		return "", errors.Wrapf(err, "failed to render cluster module name template %q", nameTemplate)
+	}
+
+	name := strings.TrimSpace(buf.String())
+	if name == "" {
+		return "", errors.Errorf("cluster module name template %q generated an empty name", nameTemplate)
+	}
+	return name, nil
+}
+
+// GetName returns the name of the cluster module, which defaults to the
+// name of the target object for cluster modules created before names were introduced.
+func GetName(mod infrav1.ClusterModule) string {
+	if mod.Name != "" {
+		return mod.Name
+	}
+	return mod.TargetObjectName
+}
+
+// DeletionGracePeriod returns the duration for which unused cluster modules are kept.
+func DeletionGracePeriod(vsphereCluster *infrav1.VSphereCluster) time.Duration {
+	if lifecycle := vsphereCluster.Spec.ClusterModuleLifecycle; lifecycle != nil && lifecycle.DeletionGracePeriod != nil {
+		return lifecycle.DeletionGracePeriod.Duration
+	}
+	return 0
+}
+
 // Compare returns whether both the cluster module slices are the same.
 func Compare(oldMods, newMods []infrav1.ClusterModule) bool {
 	if len(oldMods) != len(newMods) {
@@ -21,6 +21,10 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
 	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
@@ -141,3 +145,77 @@ func Test_Compare(t *testing.T) {
 		})
 	}
 }
+
+func Test_GenerateName(t *testing.T) {
+	labels := map[string]string{clusterv1.ClusterNameLabel: "cluster", "pool": "gpu"}
+	md := NewWrapper(&clusterv1.MachineDeployment{
+		TypeMeta:   metav1.TypeMeta{Kind: "MachineDeployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "md-1", Labels: labels},
+	})
+	kcp := NewWrapper(&controlplanev1.KubeadmControlPlane{
+		TypeMeta:   metav1.TypeMeta{Kind: "KubeadmControlPlane"},
+		ObjectMeta: metav1.ObjectMeta{Name: "kcp", Labels: labels},
+	})
+
+	tests := []struct {
+		name         string
+		nameTemplate *string
+		wrapper      Wrapper
+		expectedName string
+		hasError     bool
+	}{
+		{
+			name:         "default template",
+			wrapper:      md,
+			expectedName: "md-1",
+		},
+		{
+			name:         "template using the cluster name",
+			nameTemplate: ptr.To("{{ .cluster.name }}-workers"),
+			wrapper:      md,
+			expectedName: "cluster-workers",
+		},
+		{
+			name:         "template using a label",
+			nameTemplate: ptr.To(`{{ index .machineDeployment.labels "pool" }}`),
+			wrapper:      md,
+			expectedName: "gpu",
+		},
+		{
+			name:         "control plane ignores the template",
+			nameTemplate: ptr.To("{{ .cluster.name }}-workers"),
+			wrapper:      kcp,
+			expectedName: "kcp",
+		},
+		{
+			name:         "template with an unknown key",
+			nameTemplate: ptr.To("{{ .machine.name }}"),
+			wrapper:      md,
+			hasError:     true,
+		},
+		{
+			name:         "template generating an empty name",
+			nameTemplate: ptr.To(`{{ index .machineDeployment.labels "unknown" }}`),
+			wrapper:      md,
+			hasError:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			vsphereCluster := &infrav1.VSphereCluster{}
+			if tt.nameTemplate != nil {
+				vsphereCluster.Spec.ClusterModuleLifecycle = &infrav1.ClusterModuleLifecycle{NameTemplate: tt.nameTemplate}
+			}
+
+			name, err := GenerateName(vsphereCluster, tt.wrapper)
+			if tt.hasError {
+				g.Expect(err).To(gomega.HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(name).To(gomega.Equal(tt.expectedName))
+		})
+	}
+}
@@ -18,13 +18,15 @@ package context
 
 import (
 	"sync"
+	"time"
 
-	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/session"
 )
 
 // ControllerManagerContext is the context of the controller that owns the
@@ -52,9 +54,6 @@ type ControllerManagerContext struct {
 	// Client is the controller manager's client.
 	Client client.Client
 
-	// Logger is the controller manager's logger.
-	Logger logr.Logger
-
 	// Scheme is the controller manager's API scheme.
 	Scheme *runtime.Scheme
 
@@ -66,12 +65,63 @@ type ControllerManagerContext struct {
 	// endpoints.
 	Password string
 
+	// VCenterProxy is the proxy used to connect to remote vSphere endpoints,
+	// unless a proxy is configured for the VSphereCluster.
+	VCenterProxy *session.Proxy
+
 	// NetworkProvider is the network provider used by Supervisor based clusters
 	NetworkProvider string
 
+	// ControlPlaneEndpointDNSProvider is the DNS provider used to publish DNS records
+	// for the control plane endpoint of Supervisor based clusters.
+	ControlPlaneEndpointDNSProvider string
+
+	// ControlPlaneEndpointDNSWebhookURL is the URL of the external-dns webhook provider.
+	ControlPlaneEndpointDNSWebhookURL string
+
+	// SupervisorAPIServerEndpoints are the endpoints of the Supervisor API server
+	// advertised to workload clusters of Supervisor based clusters. If empty, the
+	// endpoint is discovered.
+	SupervisorAPIServerEndpoints []string
+
 	// WatchFilterValue is used to filter incoming objects by label.
 	WatchFilterValue string
 
+	// DryRun is true when the vSphere mutating operations are logged instead
+	// of being executed.
+	DryRun bool
+
+	// EventAggregationWindow is the window in which identical events recorded
+	// for an object are aggregated.
+	EventAggregationWindow time.Duration
+
+	// ResourceUsageRefreshInterval is the interval in which the resource
+	// usage of the VMs of a VSphereCluster is refreshed.
+	ResourceUsageRefreshInterval time.Duration
+
+	// PreTerminateHookTimeout is the time after which the VM of a deleted
+	// VSphereMachine or VSphereVM is deleted even if pre-terminate delete
+	// hooks remain.
+	PreTerminateHookTimeout time.Duration
+
+	// VMDeletionFailureTimeout is the time after the deletion of a VSphereVM
+	// after which its finalizer is removed if the VM can't be destroyed and is
+	// no longer found by its UUID.
+	VMDeletionFailureTimeout time.Duration
+
+	// VMTaskTimeout is the time after which a clone or reconfigure task of a
+	// VSphereVM is canceled and retried.
+	VMTaskTimeout time.Duration
+
+	// ClusterTeardownConcurrency is the maximum number of VMs of a deleted
+	// cluster which are destroyed at the same time.
+	ClusterTeardownConcurrency int
+
+	// ControlPlaneConcurrency is the number of control plane VSphereMachines
+	// and VSphereVMs reconciled concurrently in work queues separate from the
+	// ones of worker machines. The work queues are shared if set to 0.
+	ControlPlaneConcurrency int
+
 	genericEventCache sync.Map
 }
 
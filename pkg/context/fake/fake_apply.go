@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"encoding/json"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// ServerSideApplyInterceptorFuncs returns interceptor funcs which emulate server-side apply
+// for the fake client, which does not support apply patches.
+// An applied object is created if it does not exist, otherwise it is merge patched: fields which are
+// not part of the applied object are preserved, but lists are replaced instead of being merged by key.
+func ServerSideApplyInterceptorFuncs() interceptor.Funcs {
+	return interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if patch.Type() != types.ApplyPatchType {
+				return c.Patch(ctx, obj, patch, opts...)
+			}
+
+			data, err := patch.Data(obj)
+			if err != nil {
+				return err
+			}
+			// The status and the server-side metadata are not applied.
+			applied := map[string]interface{}{}
+			if err := json.Unmarshal(data, &applied); err != nil {
+				return err
+			}
+			delete(applied, "status")
+			if metadata, ok := applied["metadata"].(map[string]interface{}); ok {
+				delete(metadata, "creationTimestamp")
+				delete(metadata, "resourceVersion")
+				delete(metadata, "managedFields")
+			}
+			if data, err = json.Marshal(applied); err != nil {
+				return err
+			}
+
+			existing, ok := obj.DeepCopyObject().(client.Object)
+			if !ok {
+				return c.Patch(ctx, obj, patch, opts...)
+			}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+				if !apierrors.IsNotFound(err) {
+					return err
+				}
+				obj.SetResourceVersion("")
+				return c.Create(ctx, obj)
+			}
+			return c.Patch(ctx, obj, client.RawPatch(types.MergePatchType, data))
+		},
+	}
+}
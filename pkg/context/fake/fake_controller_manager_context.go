@@ -25,7 +25,6 @@ import (
 	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
-	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
 	vmwarev1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/vmware/v1beta1"
@@ -48,13 +47,14 @@ func NewControllerManagerContext(initObjects ...client.Object) *capvcontext.Cont
 
 	clientWithObjects := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(
 		&infrav1.VSphereVM{},
+		&infrav1.VSphereVMImage{},
 		&vmwarev1.VSphereCluster{},
 		&clusterv1.Cluster{},
-	).WithObjects(initObjects...).Build()
+		&ipamv1.IPAddressClaim{},
+	).WithObjects(initObjects...).WithInterceptorFuncs(ServerSideApplyInterceptorFuncs()).Build()
 
 	return &capvcontext.ControllerManagerContext{
 		Client:                  clientWithObjects,
-		Logger:                  ctrllog.Log.WithName(ControllerManagerName),
 		Scheme:                  scheme,
 		Namespace:               ControllerManagerNamespace,
 		Name:                    ControllerManagerName,
@@ -0,0 +1,47 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context
+
+import (
+	"context"
+	"fmt"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/patch"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+// VIMMachinePoolContext is a Go context used with a VSphereMachinePool.
+type VIMMachinePoolContext struct {
+	Cluster            *clusterv1.Cluster
+	MachinePool        *expv1.MachinePool
+	VSphereCluster     *infrav1.VSphereCluster
+	VSphereMachinePool *infrav1.VSphereMachinePool
+	PatchHelper        *patch.Helper
+}
+
+// String returns VSphereMachinePoolGroupVersionKind VSphereMachinePoolNamespace/VSphereMachinePoolName.
+func (c *VIMMachinePoolContext) String() string {
+	return fmt.Sprintf("%s %s/%s", c.VSphereMachinePool.GroupVersionKind(), c.VSphereMachinePool.Namespace, c.VSphereMachinePool.Name)
+}
+
+// Patch updates the object and its status on the API server.
+func (c *VIMMachinePoolContext) Patch(ctx context.Context) error {
+	return c.PatchHelper.Patch(ctx, c.VSphereMachinePool)
+}
@@ -24,6 +24,7 @@ import (
 	"sigs.k8s.io/cluster-api/util/patch"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/internal/kubevip"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/session"
 )
 
@@ -35,6 +36,24 @@ type VMContext struct {
 	PatchHelper          *patch.Helper
 	Session              *session.Session
 	VSphereFailureDomain *infrav1.VSphereFailureDomain
+	// ClusterFolder is the name of the folder generated by the folder naming strategy
+	// of the VSphereCluster, which is created if it doesn't exist yet.
+	ClusterFolder string
+	// KubeVIP is the configuration of the kube-vip static pod added to the bootstrap data
+	// of a control plane VM when the control plane endpoint is managed by CAPV.
+	KubeVIP *kubevip.Config
+	// InfraPaused is true when the vSphere mutating operations for the VSphereVM
+	// are halted via the PausedInfraAnnotation.
+	InfraPaused bool
+	// DryRun is true when the vSphere mutating operations for the VSphereVM
+	// are logged instead of being executed.
+	DryRun bool
+	// DeleteProtected is true when the VM is left orphaned instead of being
+	// destroyed on deletion via the DeleteProtectionAnnotation.
+	DeleteProtected bool
+	// ClusterDeleting is true when the Cluster of the VSphereVM is being deleted,
+	// in which case the VM is destroyed as part of the ordered cluster teardown.
+	ClusterDeleting bool
 }
 
 // String returns VSphereVMGroupVersionKind VSphereVMNamespace/VSphereVMName.
@@ -0,0 +1,372 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package diagnostics periodically reports the reconcile profile of the controllers, i.e. their
+// queue depth, reconcile durations and slowest objects, to help tuning large installations.
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ReportKey is the key of the report in the data of the ConfigMap.
+const ReportKey = "report.json"
+
+// Options configures the reports.
+type Options struct {
+	// Interval is the interval in which reports are created. Reports are disabled if set to 0.
+	Interval time.Duration
+
+	// TopN is the number of the slowest reconciles listed in a report.
+	TopN int
+
+	// ConfigMapName is the name of the ConfigMap in the namespace of the manager the reports are
+	// written to. Reports are logged if not set.
+	ConfigMapName string
+}
+
+// Report is a snapshot of the reconcile profile of the controllers during an interval.
+type Report struct {
+	// Time is the time the report was created.
+	Time metav1.Time `json:"time"`
+
+	// Interval is the interval the report covers.
+	Interval metav1.Duration `json:"interval"`
+
+	// Controllers are the reconcile statistics of the controllers.
+	Controllers []ControllerReport `json:"controllers"`
+
+	// SlowestReconciles are the objects with the slowest reconciles during the interval, slowest first.
+	SlowestReconciles []ObjectReport `json:"slowestReconciles,omitempty"`
+}
+
+// ControllerReport are the reconcile statistics of a controller.
+type ControllerReport struct {
+	// Name is the name of the controller.
+	Name string `json:"name"`
+
+	// QueueDepth is the number of objects waiting in the work queue of the controller.
+	QueueDepth int64 `json:"queueDepth"`
+
+	// ActiveWorkers is the number of objects being reconciled.
+	ActiveWorkers int64 `json:"activeWorkers"`
+
+	// MaxConcurrentReconciles is the number of workers of the controller.
+	MaxConcurrentReconciles int64 `json:"maxConcurrentReconciles"`
+
+	// Reconciles is the number of reconciles during the interval.
+	Reconciles int64 `json:"reconciles"`
+
+	// Errors is the number of reconciles which failed during the interval.
+	Errors int64 `json:"errors"`
+
+	// AverageDuration is the average duration of the reconciles during the interval.
+	AverageDuration metav1.Duration `json:"averageDuration"`
+}
+
+// ObjectReport is the slowest reconcile of an object during an interval.
+type ObjectReport struct {
+	// Controller is the name of the controller.
+	Controller string `json:"controller"`
+
+	// Namespace is the namespace of the object.
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the object.
+	Name string `json:"name"`
+
+	// Duration is the duration of the slowest reconcile of the object.
+	Duration metav1.Duration `json:"duration"`
+
+	// Reconciles is the number of reconciles of the object during the interval.
+	Reconciles int64 `json:"reconciles"`
+}
+
+type objectKey struct {
+	controller string
+	request    reconcile.Request
+}
+
+type objectStats struct {
+	maxDuration time.Duration
+	reconciles  int64
+}
+
+// recorder records the durations of the reconciles of the objects. It only records them
+// while reports are enabled, so that it does not grow if they are never collected.
+type recorder struct {
+	enabled atomic.Bool
+	lock    sync.Mutex
+	objects map[objectKey]*objectStats
+}
+
+var defaultRecorder = &recorder{}
+
+func (r *recorder) observe(controller string, req reconcile.Request, duration time.Duration) {
+	if !r.enabled.Load() {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.objects == nil {
+		r.objects = map[objectKey]*objectStats{}
+	}
+	key := objectKey{controller: controller, request: req}
+	stats, ok := r.objects[key]
+	if !ok {
+		stats = &objectStats{}
+		r.objects[key] = stats
+	}
+	stats.reconciles++
+	if duration > stats.maxDuration {
+		stats.maxDuration = duration
+	}
+}
+
+// slowest returns the n objects with the slowest reconciles and resets the recorded durations.
+func (r *recorder) slowest(n int) []ObjectReport {
+	r.lock.Lock()
+	objects := r.objects
+	r.objects = nil
+	r.lock.Unlock()
+
+	reports := make([]ObjectReport, 0, len(objects))
+	for key, stats := range objects {
+		reports = append(reports, ObjectReport{
+			Controller: key.controller,
+			Namespace:  key.request.Namespace,
+			Name:       key.request.Name,
+			Duration:   metav1.Duration{Duration: stats.maxDuration},
+			Reconciles: stats.reconciles,
+		})
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].Duration.Duration != reports[j].Duration.Duration {
+			return reports[i].Duration.Duration > reports[j].Duration.Duration
+		}
+		return reports[i].Namespace+"/"+reports[i].Name < reports[j].Namespace+"/"+reports[j].Name
+	})
+	if len(reports) > n {
+		reports = reports[:n]
+	}
+	return reports
+}
+
+// ObserveReconciler wraps a reconciler to record the duration of the reconciles of each object,
+// so that the slowest objects of the controller are listed in the reports.
+func ObserveReconciler(controller string, r reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		start := time.Now()
+		defer func() {
+			defaultRecorder.observe(controller, req, time.Since(start))
+		}()
+		return r.Reconcile(ctx, req)
+	})
+}
+
+// controllerCounters are the cumulative reconcile counters of a controller, which are
+// compared between reports to compute the statistics of an interval.
+type controllerCounters struct {
+	reconciles int64
+	errors     int64
+	seconds    float64
+}
+
+// Collector periodically creates reports from the controller-runtime metrics and the
+// durations recorded by ObserveReconciler.
+type Collector struct {
+	client    client.Client
+	gatherer  prometheus.Gatherer
+	namespace string
+	options   Options
+	recorder  *recorder
+
+	last     time.Time
+	counters map[string]controllerCounters
+}
+
+// NewCollector returns a Collector gathering the metrics from the gatherer, e.g. the
+// controller-runtime metrics registry, and writing the reports to a ConfigMap in the namespace.
+func NewCollector(c client.Client, gatherer prometheus.Gatherer, namespace string, options Options) *Collector {
+	if options.TopN <= 0 {
+		options.TopN = 10
+	}
+	return &Collector{
+		client:    c,
+		gatherer:  gatherer,
+		namespace: namespace,
+		options:   options,
+		recorder:  defaultRecorder,
+	}
+}
+
+// Start implements manager.Runnable. It creates a report every interval until the context is done.
+func (c *Collector) Start(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx).WithName("diagnostics")
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	c.recorder.enabled.Store(true)
+	defer c.recorder.enabled.Store(false)
+
+	// The first report only sets the baseline of the counters.
+	if _, err := c.Report(time.Now()); err != nil {
+		log.Error(err, "Failed to create reconcile profiling report")
+	}
+	ticker := time.NewTicker(c.options.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			report, err := c.Report(now)
+			if err != nil {
+				log.Error(err, "Failed to create reconcile profiling report")
+				continue
+			}
+			if err := c.publish(ctx, report); err != nil {
+				log.Error(err, "Failed to publish reconcile profiling report")
+			}
+		}
+	}
+}
+
+// Report creates a report of the interval since the previous report.
+func (c *Collector) Report(now time.Time) (*Report, error) {
+	families, err := c.gatherer.Gather()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to gather metrics")
+	}
+
+	controllers := map[string]*ControllerReport{}
+	counters := map[string]controllerCounters{}
+	get := func(name string) *ControllerReport {
+		if controllers[name] == nil {
+			controllers[name] = &ControllerReport{Name: name}
+		}
+		return controllers[name]
+	}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			name := label(metric, "controller")
+			if name == "" {
+				continue
+			}
+			switch family.GetName() {
+			case "workqueue_depth":
+				get(name).QueueDepth = int64(metric.GetGauge().GetValue())
+			case "controller_runtime_active_workers":
+				get(name).ActiveWorkers = int64(metric.GetGauge().GetValue())
+			case "controller_runtime_max_concurrent_reconciles":
+				get(name).MaxConcurrentReconciles = int64(metric.GetGauge().GetValue())
+			case "controller_runtime_reconcile_total":
+				get(name)
+				total := counters[name]
+				total.reconciles += int64(metric.GetCounter().GetValue())
+				if label(metric, "result") == "error" {
+					total.errors += int64(metric.GetCounter().GetValue())
+				}
+				counters[name] = total
+			case "controller_runtime_reconcile_time_seconds":
+				get(name)
+				total := counters[name]
+				total.seconds = metric.GetHistogram().GetSampleSum()
+				counters[name] = total
+			}
+		}
+	}
+
+	report := &Report{
+		Time:              metav1.NewTime(now),
+		SlowestReconciles: c.recorder.slowest(c.options.TopN),
+	}
+	if !c.last.IsZero() {
+		report.Interval = metav1.Duration{Duration: now.Sub(c.last)}
+	}
+	for name, controller := range controllers {
+		current, previous := counters[name], c.counters[name]
+		// Counters are reset when the manager restarts.
+		if current.reconciles < previous.reconciles {
+			previous = controllerCounters{}
+		}
+		controller.Reconciles = current.reconciles - previous.reconciles
+		controller.Errors = current.errors - previous.errors
+		if controller.Reconciles > 0 {
+			seconds := (current.seconds - previous.seconds) / float64(controller.Reconciles)
+			controller.AverageDuration = metav1.Duration{Duration: time.Duration(seconds * float64(time.Second))}
+		}
+		report.Controllers = append(report.Controllers, *controller)
+	}
+	sort.Slice(report.Controllers, func(i, j int) bool { return report.Controllers[i].Name < report.Controllers[j].Name })
+
+	c.last = now
+	c.counters = counters
+	return report, nil
+}
+
+// publish writes the report to the ConfigMap, or logs it if no ConfigMap is configured.
+func (c *Collector) publish(ctx context.Context, report *Report) error {
+	if c.options.ConfigMapName == "" {
+		ctrl.LoggerFrom(ctx).Info("Reconcile profiling report", "report", report)
+		return nil
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal report")
+	}
+	configMap := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: c.namespace, Name: c.options.ConfigMapName}
+	if err := c.client.Get(ctx, key, configMap); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get ConfigMap %s", key)
+		}
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+			Data:       map[string]string{ReportKey: string(data)},
+		}
+		return errors.Wrapf(c.client.Create(ctx, configMap), "failed to create ConfigMap %s", key)
+	}
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[ReportKey] = string(data)
+	return errors.Wrapf(c.client.Update(ctx, configMap), "failed to update ConfigMap %s", key)
+}
+
+func label(metric *dto.Metric, name string) string {
+	for _, l := range metric.GetLabel() {
+		if l.GetName() == name {
+			return l.GetValue()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestCollector_Report(t *testing.T) {
+	g := NewWithT(t)
+
+	registry := prometheus.NewRegistry()
+	depth := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "workqueue_depth"}, []string{"name", "controller"})
+	total := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "controller_runtime_reconcile_total"}, []string{"controller", "result"})
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "controller_runtime_reconcile_time_seconds"}, []string{"controller"})
+	registry.MustRegister(depth, total, duration)
+
+	collector := NewCollector(nil, registry, "capv-system", Options{TopN: 2})
+	collector.recorder = &recorder{}
+	collector.recorder.enabled.Store(true)
+
+	// The first report sets the baseline of the counters.
+	total.WithLabelValues("vspherevm", "success").Add(10)
+	duration.WithLabelValues("vspherevm").Observe(100)
+	now := time.Now()
+	_, err := collector.Report(now)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The next report only covers the reconciles of the interval.
+	depth.WithLabelValues("vspherevm", "vspherevm").Set(42)
+	total.WithLabelValues("vspherevm", "success").Add(3)
+	total.WithLabelValues("vspherevm", "error").Add(1)
+	for _, seconds := range []float64{1, 2, 3, 6} {
+		duration.WithLabelValues("vspherevm").Observe(seconds)
+	}
+	for name, d := range map[string]time.Duration{"vm-1": time.Second, "vm-2": 5 * time.Second, "vm-3": 3 * time.Second} {
+		collector.recorder.observe("vspherevm", reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: name}}, d)
+	}
+	report, err := collector.Report(now.Add(time.Minute))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(report.Interval.Duration).To(Equal(time.Minute))
+	g.Expect(report.Controllers).To(HaveLen(1))
+	g.Expect(report.Controllers[0]).To(Equal(ControllerReport{
+		Name:            "vspherevm",
+		QueueDepth:      42,
+		Reconciles:      4,
+		Errors:          1,
+		AverageDuration: report.Controllers[0].AverageDuration,
+	}))
+	g.Expect(report.Controllers[0].AverageDuration.Duration).To(Equal(3 * time.Second))
+	g.Expect(report.SlowestReconciles).To(HaveLen(2))
+	g.Expect(report.SlowestReconciles[0].Name).To(Equal("vm-2"))
+	g.Expect(report.SlowestReconciles[1].Name).To(Equal("vm-3"))
+
+	// The recorded durations are reset after each report.
+	report, err = collector.Report(now.Add(2 * time.Minute))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(report.SlowestReconciles).To(BeEmpty())
+	g.Expect(report.Controllers[0].Reconciles).To(BeZero())
+}
+
+func TestObserveReconciler(t *testing.T) {
+	g := NewWithT(t)
+
+	defaultRecorder.enabled.Store(true)
+	defer defaultRecorder.enabled.Store(false)
+	defer defaultRecorder.slowest(0)
+
+	r := ObserveReconciler("vspheremachine", reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+		time.Sleep(10 * time.Millisecond)
+		return reconcile.Result{}, nil
+	}))
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "machine"}})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	slowest := defaultRecorder.slowest(10)
+	g.Expect(slowest).To(HaveLen(1))
+	g.Expect(slowest[0].Controller).To(Equal("vspheremachine"))
+	g.Expect(slowest[0].Duration.Duration).To(BeNumerically(">=", 10*time.Millisecond))
+}
+
+func TestCollector_publish(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	c := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+	collector := NewCollector(c, prometheus.NewRegistry(), "capv-system", Options{ConfigMapName: "capv-diagnostics"})
+
+	// The ConfigMap is created with the first report and updated with the next ones.
+	for _, reconciles := range []int64{1, 2} {
+		report := &Report{Controllers: []ControllerReport{{Name: "vspherevm", Reconciles: reconciles}}}
+		g.Expect(collector.publish(ctx, report)).To(Succeed())
+
+		configMap := &corev1.ConfigMap{}
+		g.Expect(c.Get(ctx, client.ObjectKey{Namespace: "capv-system", Name: "capv-diagnostics"}, configMap)).To(Succeed())
+		published := &Report{}
+		g.Expect(json.Unmarshal([]byte(configMap.Data[ReportKey]), published)).To(Succeed())
+		g.Expect(published.Controllers[0].Reconciles).To(Equal(reconciles))
+	}
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package health contains the checks of the dependencies of the manager.
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+const (
+	// CRDDependency is the type of the dependencies on the CRDs served by the API server.
+	CRDDependency = "CRD"
+	// VCenterDependency is the type of the dependencies on the vCenters reached with an identity.
+	VCenterDependency = "vCenter"
+
+	// managerCredentials is the name of the identity of the VSphereClusters without identityRef,
+	// which use the credentials of the manager.
+	managerCredentials = "manager credentials"
+)
+
+// DependencyStatus is the status of a single dependency of the manager.
+type DependencyStatus struct {
+	// Type is the type of the dependency, either CRD or vCenter.
+	Type string `json:"type"`
+
+	// Name is the resource of a CRD or the identity used to connect to vCenter.
+	Name string `json:"name"`
+
+	// Healthy is true if the dependency is available.
+	Healthy bool `json:"healthy"`
+
+	// Message describes why the dependency is not available.
+	Message string `json:"message,omitempty"`
+}
+
+// Report is the result of checking the dependencies of the manager.
+type Report struct {
+	// Ready is true if the manager can serve requests, i.e. all CRDs are served.
+	Ready bool `json:"ready"`
+
+	// Healthy is true if all dependencies are available, including vCenter.
+	Healthy bool `json:"healthy"`
+
+	// Dependencies are the statuses of all checked dependencies.
+	Dependencies []DependencyStatus `json:"dependencies"`
+}
+
+// failing returns the dependencies which are not healthy.
+func (r Report) failing() []DependencyStatus {
+	failing := []DependencyStatus{}
+	for _, dependency := range r.Dependencies {
+		if !dependency.Healthy {
+			failing = append(failing, dependency)
+		}
+	}
+	return failing
+}
+
+// DependencyChecker checks the availability of the CRDs used by the manager and the connectivity
+// to vCenter of every identity used by the VSphereClusters.
+//
+// The vCenter connectivity of an identity is taken from the VCenterAvailable condition of the
+// VSphereClusters using it, so checking it doesn't put load on vCenter. An identity is healthy as
+// long as one of its VSphereClusters did not fail to connect to vCenter.
+// The manager is ready if all CRDs are served. The vCenter connectivity is only reported and does not
+// affect the readiness: an unready manager takes the webhooks away, which would reject the changes
+// to the VSphereClusters needed to recover from an unreachable vCenter.
+type DependencyChecker struct {
+	// Client is used to list the VSphereClusters.
+	Client client.Reader
+
+	// RESTMapper is used to check if the CRDs are served.
+	RESTMapper meta.RESTMapper
+
+	// CRDs are the resources which have to be served by the API server.
+	CRDs []schema.GroupVersionResource
+
+	// CheckVCenter enables the checks of the vCenter connectivity of the VSphereClusters.
+	CheckVCenter bool
+}
+
+// Check checks all dependencies and returns the Report.
+func (c *DependencyChecker) Check(ctx context.Context) Report {
+	report := Report{Ready: true, Dependencies: c.checkCRDs()}
+	for _, status := range report.Dependencies {
+		report.Ready = report.Ready && status.Healthy
+	}
+
+	if c.CheckVCenter {
+		vCenterStatuses, err := c.checkVCenters(ctx)
+		if err != nil {
+			vCenterStatuses = []DependencyStatus{{Type: VCenterDependency, Name: "VSphereClusters", Message: err.Error()}}
+		}
+		report.Dependencies = append(report.Dependencies, vCenterStatuses...)
+	}
+
+	report.Healthy = len(report.failing()) == 0
+	return report
+}
+
+// checkCRDs returns the status of every CRD which has to be served by the API server.
+func (c *DependencyChecker) checkCRDs() []DependencyStatus {
+	statuses := []DependencyStatus{}
+	for _, gvr := range c.CRDs {
+		status := DependencyStatus{Type: CRDDependency, Name: gvr.String(), Healthy: true}
+		if _, err := c.RESTMapper.KindFor(gvr); err != nil {
+			status.Healthy = false
+			status.Message = err.Error()
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// checkVCenters returns the vCenter connectivity of every identity used by the VSphereClusters.
+func (c *DependencyChecker) checkVCenters(ctx context.Context) ([]DependencyStatus, error) {
+	vsphereClusters := &infrav1.VSphereClusterList{}
+	if err := c.Client.List(ctx, vsphereClusters); err != nil {
+		return nil, errors.Wrap(err, "failed to list VSphereClusters")
+	}
+
+	statuses := map[string]*DependencyStatus{}
+	for i := range vsphereClusters.Items {
+		vsphereCluster := &vsphereClusters.Items[i]
+		if !vsphereCluster.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		name := identityName(vsphereCluster)
+		status, ok := statuses[name]
+		if !ok {
+			status = &DependencyStatus{Type: VCenterDependency, Name: name}
+			statuses[name] = status
+		}
+		if status.Healthy {
+			continue
+		}
+		// VSphereClusters which were not reconciled yet don't make the identity unhealthy.
+		if !conditions.IsFalse(vsphereCluster, infrav1.VCenterAvailableCondition) {
+			status.Healthy = true
+			status.Message = ""
+			continue
+		}
+		if status.Message == "" {
+			status.Message = fmt.Sprintf("VSphereCluster %s/%s can't reach vCenter %s: %s", vsphereCluster.Namespace, vsphereCluster.Name,
+				vsphereCluster.Spec.Server, conditions.GetMessage(vsphereCluster, infrav1.VCenterAvailableCondition))
+		}
+	}
+
+	result := make([]DependencyStatus, 0, len(statuses))
+	for _, status := range statuses {
+		result = append(result, *status)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// identityName returns the name of the identity the VSphereCluster uses to connect to vCenter.
+func identityName(vsphereCluster *infrav1.VSphereCluster) string {
+	ref := vsphereCluster.Spec.IdentityRef
+	switch {
+	case ref == nil:
+		return managerCredentials
+	case ref.Kind == infrav1.SecretKind:
+		return fmt.Sprintf("%s %s/%s", ref.Kind, vsphereCluster.Namespace, ref.Name)
+	default:
+		return fmt.Sprintf("%s %s", ref.Kind, ref.Name)
+	}
+}
+
+// ReadyzCheck is a healthz.Checker failing if a CRD is not served.
+// The vCenter connectivity is not checked, see DependencyChecker.
+func (c *DependencyChecker) ReadyzCheck(_ *http.Request) error {
+	report := Report{Dependencies: c.checkCRDs()}
+	failing := report.failing()
+	if len(failing) == 0 {
+		return nil
+	}
+	names := []string{}
+	for _, dependency := range failing {
+		names = append(names, fmt.Sprintf("%s %s", dependency.Type, dependency.Name))
+	}
+	return errors.Errorf("failing dependencies: %s", strings.Join(names, ", "))
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func TestDependencyChecker_Check(t *testing.T) {
+	vsphereClusters := infrav1.GroupVersion.WithResource("vsphereclusters")
+	vsphereMachines := infrav1.GroupVersion.WithResource("vspheremachines")
+
+	vsphereCluster := func(name string, identityRef *infrav1.VSphereIdentityReference, vCenterAvailable *bool) *infrav1.VSphereCluster {
+		c := &infrav1.VSphereCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceDefault},
+			Spec:       infrav1.VSphereClusterSpec{Server: "vcenter.example.com", IdentityRef: identityRef},
+		}
+		switch {
+		case vCenterAvailable == nil:
+		case *vCenterAvailable:
+			conditions.MarkTrue(c, infrav1.VCenterAvailableCondition)
+		default:
+			conditions.MarkFalse(c, infrav1.VCenterAvailableCondition, infrav1.VCenterUnreachableReason, clusterv1.ConditionSeverityError, "connection refused")
+		}
+		return c
+	}
+	identityRef := &infrav1.VSphereIdentityReference{Kind: infrav1.VSphereClusterIdentityKind, Name: "tenant-a"}
+	available, unavailable := true, false
+
+	tests := []struct {
+		name         string
+		crds         []schema.GroupVersionResource
+		checkVCenter bool
+		objects      []client.Object
+		wantReady    bool
+		wantFailing  []DependencyStatus
+	}{
+		{
+			name:      "all CRDs are served",
+			crds:      []schema.GroupVersionResource{vsphereClusters},
+			wantReady: true,
+		},
+		{
+			name: "a CRD is not served",
+			crds: []schema.GroupVersionResource{vsphereClusters, vsphereMachines},
+			wantFailing: []DependencyStatus{
+				{Type: CRDDependency, Name: vsphereMachines.String()},
+			},
+		},
+		{
+			name:         "no VSphereClusters",
+			checkVCenter: true,
+			wantReady:    true,
+		},
+		{
+			name:         "VSphereClusters not reconciled yet",
+			checkVCenter: true,
+			objects:      []client.Object{vsphereCluster("c-1", nil, nil)},
+			wantReady:    true,
+		},
+		{
+			name:         "one of two identities can't reach vCenter",
+			checkVCenter: true,
+			objects: []client.Object{
+				vsphereCluster("c-1", nil, &available),
+				vsphereCluster("c-2", identityRef, &unavailable),
+			},
+			wantReady: true,
+			wantFailing: []DependencyStatus{
+				{Type: VCenterDependency, Name: "VSphereClusterIdentity tenant-a", Message: "VSphereCluster default/c-2 can't reach vCenter vcenter.example.com: connection refused"},
+			},
+		},
+		{
+			name:         "identity is healthy if one of its VSphereClusters can reach vCenter",
+			checkVCenter: true,
+			objects: []client.Object{
+				vsphereCluster("c-1", identityRef, &unavailable),
+				vsphereCluster("c-2", identityRef, &available),
+			},
+			wantReady: true,
+		},
+		{
+			// vCenter outages are reported, but must not make the manager unready.
+			name:         "no identity can reach vCenter",
+			checkVCenter: true,
+			objects: []client.Object{
+				vsphereCluster("c-1", nil, &unavailable),
+				vsphereCluster("c-2", identityRef, &unavailable),
+			},
+			wantReady: true,
+			wantFailing: []DependencyStatus{
+				{Type: VCenterDependency, Name: "VSphereClusterIdentity tenant-a", Message: "VSphereCluster default/c-2 can't reach vCenter vcenter.example.com: connection refused"},
+				{Type: VCenterDependency, Name: "manager credentials", Message: "VSphereCluster default/c-1 can't reach vCenter vcenter.example.com: connection refused"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			checker := newChecker(tt.objects...)
+			checker.CRDs = tt.crds
+			checker.CheckVCenter = tt.checkVCenter
+
+			report := checker.Check(context.Background())
+			g.Expect(report.Ready).To(Equal(tt.wantReady))
+			g.Expect(report.Healthy).To(Equal(len(tt.wantFailing) == 0))
+			failing := report.failing()
+			for i := range failing {
+				if failing[i].Type == CRDDependency {
+					failing[i].Message = ""
+				}
+			}
+			g.Expect(failing).To(Equal(append([]DependencyStatus{}, tt.wantFailing...)))
+		})
+	}
+}
+
+func TestDependencyChecker_ReadyzCheck(t *testing.T) {
+	g := NewWithT(t)
+
+	checker := newChecker()
+	checker.CRDs = []schema.GroupVersionResource{infrav1.GroupVersion.WithResource("vspheremachines")}
+
+	err := checker.ReadyzCheck(httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	g.Expect(err).To(MatchError(ContainSubstring("CRD infrastructure.cluster.x-k8s.io/v1beta1, Resource=vspheremachines")))
+
+	checker.CRDs = []schema.GroupVersionResource{infrav1.GroupVersion.WithResource("vsphereclusters")}
+	g.Expect(checker.ReadyzCheck(httptest.NewRequest(http.MethodGet, "/readyz", nil))).To(Succeed())
+
+	// An unreachable vCenter does not make the manager unready.
+	vsphereCluster := &infrav1.VSphereCluster{ObjectMeta: metav1.ObjectMeta{Name: "c-1", Namespace: "default"}}
+	conditions.MarkFalse(vsphereCluster, infrav1.VCenterAvailableCondition, infrav1.VCenterUnreachableReason, clusterv1.ConditionSeverityError, "connection refused")
+	checker = newChecker(vsphereCluster)
+	checker.CRDs = []schema.GroupVersionResource{infrav1.GroupVersion.WithResource("vsphereclusters")}
+	checker.CheckVCenter = true
+	g.Expect(checker.ReadyzCheck(httptest.NewRequest(http.MethodGet, "/readyz", nil))).To(Succeed())
+}
+
+// newChecker returns a DependencyChecker whose RESTMapper only serves VSphereClusters.
+func newChecker(objects ...client.Object) *DependencyChecker {
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+
+	restMapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{infrav1.GroupVersion})
+	restMapper.Add(infrav1.GroupVersion.WithKind("VSphereCluster"), meta.RESTScopeNamespace)
+
+	return &DependencyChecker{
+		Client:     fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+		RESTMapper: restMapper,
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// Server serves the Report of a DependencyChecker as JSON, for process supervisors and humans
+// which need to know which dependencies are failing. The readiness endpoint of the manager only
+// reports the names of its failing checks.
+//
+// GET /dependencies responds with 200 OK if all dependencies are healthy and 503 Service Unavailable otherwise.
+// Unlike the readiness endpoint, it reports vCenter outages, so it must not be used as a readiness probe.
+type Server struct {
+	// Checker is used to check the dependencies.
+	Checker *DependencyChecker
+
+	// BindAddress is the address the server listens on.
+	BindAddress string
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, so the server runs on all replicas.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// Start implements manager.Runnable.
+func (s *Server) Start(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx).WithName("dependency-health-server")
+
+	server := &http.Server{
+		Addr:              s.BindAddress,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return ctrl.LoggerInto(ctx, log)
+		},
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	log.Info("Starting dependency health server", "address", s.BindAddress)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return errors.Wrap(err, "failed to start dependency health server")
+	}
+	return nil
+}
+
+// Handler returns the handler serving the endpoints of the server.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /dependencies", s.handleDependencies)
+	return mux
+}
+
+func (s *Server) handleDependencies(w http.ResponseWriter, req *http.Request) {
+	report := s.Checker.Check(req.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	if !report.Healthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func TestServer_Dependencies(t *testing.T) {
+	unreachable := &infrav1.VSphereCluster{ObjectMeta: metav1.ObjectMeta{Name: "c-1", Namespace: metav1.NamespaceDefault}}
+	conditions.MarkFalse(unreachable, infrav1.VCenterAvailableCondition, infrav1.VCenterUnreachableReason, clusterv1.ConditionSeverityError, "connection refused")
+
+	tests := []struct {
+		name        string
+		crds        []schema.GroupVersionResource
+		objects     []client.Object
+		wantStatus  int
+		wantReady   bool
+		wantHealthy []bool
+	}{
+		{
+			name:        "healthy",
+			crds:        []schema.GroupVersionResource{infrav1.GroupVersion.WithResource("vsphereclusters")},
+			wantStatus:  http.StatusOK,
+			wantReady:   true,
+			wantHealthy: []bool{true},
+		},
+		{
+			name:        "not ready",
+			crds:        []schema.GroupVersionResource{infrav1.GroupVersion.WithResource("vspheremachines")},
+			wantStatus:  http.StatusServiceUnavailable,
+			wantHealthy: []bool{false},
+		},
+		{
+			name:        "vCenter unreachable",
+			crds:        []schema.GroupVersionResource{infrav1.GroupVersion.WithResource("vsphereclusters")},
+			objects:     []client.Object{unreachable},
+			wantStatus:  http.StatusServiceUnavailable,
+			wantReady:   true,
+			wantHealthy: []bool{true, false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			checker := newChecker(tt.objects...)
+			checker.CRDs = tt.crds
+			checker.CheckVCenter = true
+			recorder := httptest.NewRecorder()
+			(&Server{Checker: checker}).Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/dependencies", nil))
+			g.Expect(recorder.Code).To(Equal(tt.wantStatus))
+			g.Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+
+			report := Report{}
+			g.Expect(json.NewDecoder(recorder.Body).Decode(&report)).To(Succeed())
+			g.Expect(report.Ready).To(Equal(tt.wantReady))
+			g.Expect(report.Healthy).To(Equal(tt.wantStatus == http.StatusOK))
+			healthy := []bool{}
+			for _, dependency := range report.Dependencies {
+				healthy = append(healthy, dependency.Healthy)
+			}
+			g.Expect(healthy).To(Equal(tt.wantHealthy))
+		})
+	}
+}
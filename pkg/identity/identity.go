@@ -23,12 +23,15 @@ import (
 	"fmt"
 	"strings"
 
+	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/session"
 )
 
 const (
@@ -36,12 +39,25 @@ const (
 	UsernameKey = "username"
 	// PasswordKey is the key used for the password.
 	PasswordKey = "password"
+	// CACertificatesKey is the default key used for the CA certificates.
+	CACertificatesKey = "ca.crt"
+	// DefaultTokenAudience is the default audience of the ServiceAccount tokens exchanged for SAML tokens.
+	DefaultTokenAudience = "vcenter"
+
+	// tokenExpirationSeconds is the expiration of the ServiceAccount tokens, which are only used once
+	// to be exchanged for a SAML token.
+	tokenExpirationSeconds = 600
 )
 
 // Credentials are the user credentials used with the VSphere API.
+// If TokenSource is set, its tokens are exchanged for SAML tokens instead of using
+// the username and password.
 type Credentials struct {
-	Username string
-	Password string
+	Username    string
+	Password    string
+	TokenSource session.TokenSource
+	// AllowedTargets restricts the inventory the credentials can be used for, if set.
+	AllowedTargets *session.AllowedTargets
 }
 
 // GetCredentials returns the VCenter credentials for the VSphereCluster.
@@ -53,6 +69,7 @@ func GetCredentials(ctx context.Context, c client.Client, cluster *infrav1.VSphe
 	ref := cluster.Spec.IdentityRef
 	secret := &corev1.Secret{}
 	var secretKey client.ObjectKey
+	var allowedTargets *session.AllowedTargets
 
 	switch ref.Kind {
 	case infrav1.SecretKind:
@@ -93,6 +110,15 @@ func GetCredentials(ctx context.Context, c client.Client, cluster *infrav1.VSphe
 			return nil, fmt.Errorf("namespace %s is not allowed to use specifified identity", cluster.Namespace)
 		}
 
+		allowedTargets = session.NewAllowedTargets(identity.Spec.AllowedTargets)
+
+		if tokenExchange := identity.Spec.TokenExchange; tokenExchange != nil {
+			return &Credentials{
+				TokenSource:    NewServiceAccountTokenSource(c, client.ObjectKey{Namespace: controllerNamespace, Name: tokenExchange.ServiceAccountName}, tokenExchange.Audience),
+				AllowedTargets: allowedTargets,
+			}, nil
+		}
+
 		secretKey = client.ObjectKey{
 			Name:      identity.Spec.SecretName,
 			Namespace: controllerNamespace,
@@ -106,13 +132,101 @@ func GetCredentials(ctx context.Context, c client.Client, cluster *infrav1.VSphe
 	}
 
 	credentials := &Credentials{
-		Username: getData(secret, UsernameKey),
-		Password: getData(secret, PasswordKey),
+		Username:       getData(secret, UsernameKey),
+		Password:       getData(secret, PasswordKey),
+		AllowedTargets: allowedTargets,
 	}
 
 	return credentials, nil
 }
 
+// GetTLSConfig returns the TLS configuration for the connections to vCenter of the VSphereCluster.
+// The TLS configuration of the VSphereCluster takes precedence over the one of the referenced
+// VSphereClusterIdentity. It returns nil if neither of them set a TLS configuration.
+func GetTLSConfig(ctx context.Context, c client.Client, cluster *infrav1.VSphereCluster, controllerNamespace string) (*session.TLSConfig, error) {
+	if c == nil {
+		return nil, errors.New("kubernetes client is required")
+	}
+	if cluster == nil {
+		return nil, errors.New("vsphere cluster is required")
+	}
+
+	spec, secretNamespace := cluster.Spec.TLSConfig, cluster.Namespace
+	if spec == nil && cluster.Spec.IdentityRef != nil && cluster.Spec.IdentityRef.Kind == infrav1.VSphereClusterIdentityKind {
+		identity := &infrav1.VSphereClusterIdentity{}
+		if err := c.Get(ctx, client.ObjectKey{Name: cluster.Spec.IdentityRef.Name}, identity); err != nil {
+			return nil, err
+		}
+		spec, secretNamespace = identity.Spec.TLSConfig, controllerNamespace
+	}
+	if spec == nil {
+		return nil, nil
+	}
+
+	var caBundle []byte
+	if ref := spec.CASecretRef; ref != nil {
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: secretNamespace, Name: ref.Name}, secret); err != nil {
+			return nil, err
+		}
+		key := ref.Key
+		if key == "" {
+			key = CACertificatesKey
+		}
+		caBundle = secret.Data[key]
+		if len(caBundle) == 0 {
+			return nil, fmt.Errorf("secret %s/%s does not contain CA certificates in key %s", secretNamespace, ref.Name, key)
+		}
+	}
+	return session.NewTLSConfig(spec, caBundle)
+}
+
+// ServiceAccountTokenSource is a session.TokenSource requesting tokens of a ServiceAccount
+// using the TokenRequest API.
+type ServiceAccountTokenSource struct {
+	client         client.Client
+	serviceAccount client.ObjectKey
+	audience       string
+}
+
+// NewServiceAccountTokenSource returns a token source for the ServiceAccount. The audience
+// defaults to DefaultTokenAudience.
+func NewServiceAccountTokenSource(c client.Client, serviceAccount client.ObjectKey, audience string) *ServiceAccountTokenSource {
+	if audience == "" {
+		audience = DefaultTokenAudience
+	}
+	return &ServiceAccountTokenSource{
+		client:         c,
+		serviceAccount: serviceAccount,
+		audience:       audience,
+	}
+}
+
+// Key returns the ServiceAccount and the audience of the tokens.
+func (s *ServiceAccountTokenSource) Key() string {
+	return fmt.Sprintf("serviceaccount-%s-%s", s.serviceAccount, s.audience)
+}
+
+// Token requests a new token of the ServiceAccount.
+func (s *ServiceAccountTokenSource) Token(ctx context.Context) (string, error) {
+	serviceAccount := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: s.serviceAccount.Namespace,
+			Name:      s.serviceAccount.Name,
+		},
+	}
+	tokenRequest := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			Audiences:         []string{s.audience},
+			ExpirationSeconds: ptr.To[int64](tokenExpirationSeconds),
+		},
+	}
+	if err := s.client.SubResource("token").Create(ctx, serviceAccount, tokenRequest); err != nil {
+		return "", fmt.Errorf("failed to request token for ServiceAccount %s: %w", s.serviceAccount, err)
+	}
+	return tokenRequest.Status.Token, nil
+}
+
 func validateInputs(c client.Client, cluster *infrav1.VSphereCluster) error {
 	if c == nil {
 		return errors.New("kubernetes client is required")
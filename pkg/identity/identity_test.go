@@ -17,15 +17,22 @@ limitations under the License.
 package identity
 
 import (
+	"context"
+	"crypto/tls"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/manager"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/session"
 )
 
 var _ = Describe("GetCredentials", func() {
@@ -134,6 +141,69 @@ var _ = Describe("GetCredentials", func() {
 			Expect(creds.Password).To(Equal(getData(credentialSecret, PasswordKey)))
 		})
 
+		It("should return the allowed targets of the identity", func() {
+			credentialSecret := createSecret(manager.DefaultPodNamespace)
+			identity := createIdentity(credentialSecret.Name)
+			identity.Spec.AllowedTargets = &infrav1.AllowedTargets{
+				Datacenters:   []string{"dc0"},
+				ResourcePools: []string{"/dc0/host/cluster0/Resources/tenant-a"},
+			}
+			Expect(k8sclient.Update(ctx, identity)).To(Succeed())
+
+			labels := ns.Labels
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			labels["identity-authorized"] = "true"
+			ns.Labels = labels
+			Expect(k8sclient.Update(ctx, ns)).To(Succeed())
+
+			cluster.Spec = infrav1.VSphereClusterSpec{
+				IdentityRef: &infrav1.VSphereIdentityReference{
+					Kind: infrav1.VSphereClusterIdentityKind,
+					Name: identity.Name,
+				},
+			}
+			Expect(k8sclient.Update(ctx, cluster)).To(Succeed())
+
+			creds, err := GetCredentials(ctx, k8sclient, cluster, manager.DefaultPodNamespace)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(creds.AllowedTargets).To(Equal(&session.AllowedTargets{
+				Datacenters:   []string{"dc0"},
+				ResourcePools: []string{"/dc0/host/cluster0/Resources/tenant-a"},
+			}))
+		})
+
+		It("should return a token source for a token exchange", func() {
+			identity := createIdentity("")
+			identity.Spec.TokenExchange = &infrav1.VSphereTokenExchange{ServiceAccountName: "capv"}
+			Expect(k8sclient.Update(ctx, identity)).To(Succeed())
+
+			labels := ns.Labels
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			labels["identity-authorized"] = "true"
+			ns.Labels = labels
+			Expect(k8sclient.Update(ctx, ns)).To(Succeed())
+
+			cluster.Spec = infrav1.VSphereClusterSpec{
+				IdentityRef: &infrav1.VSphereIdentityReference{
+					Kind: infrav1.VSphereClusterIdentityKind,
+					Name: identity.Name,
+				},
+			}
+			Expect(k8sclient.Update(ctx, cluster)).To(Succeed())
+
+			creds, err := GetCredentials(ctx, k8sclient, cluster, manager.DefaultPodNamespace)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(creds.Username).To(BeEmpty())
+			Expect(creds.TokenSource).NotTo(BeNil())
+			Expect(creds.TokenSource.Key()).To(Equal("serviceaccount-" + manager.DefaultPodNamespace + "/capv-vcenter"))
+		})
+
 		It("should error if allowedNamespaces is set to nil", func() {
 			credentialSecret := createSecret(manager.DefaultPodNamespace)
 			identity := createIdentity(credentialSecret.Name)
@@ -206,6 +276,101 @@ var _ = Describe("GetCredentials", func() {
 	})
 })
 
+var _ = Describe("GetTLSConfig", func() {
+	var (
+		ns      *corev1.Namespace
+		cluster *infrav1.VSphereCluster
+	)
+
+	BeforeEach(func() {
+		ns = &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: "namespace-",
+			},
+		}
+		Expect(k8sclient.Create(ctx, ns)).To(Succeed())
+
+		cluster = &infrav1.VSphereCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: "cluster-",
+				Namespace:    ns.Name,
+			},
+		}
+		Expect(k8sclient.Create(ctx, cluster)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(k8sclient.Delete(ctx, ns)).To(Succeed())
+	})
+
+	It("should return nil if no TLS configuration is set", func() {
+		tlsConfig, err := GetTLSConfig(ctx, k8sclient, cluster, manager.DefaultPodNamespace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tlsConfig).To(BeNil())
+	})
+
+	It("should use the TLS configuration of the cluster", func() {
+		caSecret := createCASecret(cluster.Namespace, "custom.crt")
+		cluster.Spec.TLSConfig = &infrav1.VCenterTLSConfig{
+			CASecretRef: &infrav1.CASecretReference{Name: caSecret.Name, Key: "custom.crt"},
+			MinVersion:  infrav1.TLSVersion13,
+		}
+
+		tlsConfig, err := GetTLSConfig(ctx, k8sclient, cluster, manager.DefaultPodNamespace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tlsConfig.CABundle).To(Equal(caSecret.Data["custom.crt"]))
+		Expect(tlsConfig.MinVersion).To(Equal(uint16(tls.VersionTLS13)))
+		Expect(tlsConfig.InsecureSkipVerify).To(BeFalse())
+	})
+
+	It("should use the TLS configuration of the VSphereClusterIdentity with a secret in the controller namespace", func() {
+		credentialSecret := createSecret(manager.DefaultPodNamespace)
+		caSecret := createCASecret(manager.DefaultPodNamespace, CACertificatesKey)
+		identity := createIdentity(credentialSecret.Name)
+		identity.Spec.TLSConfig = &infrav1.VCenterTLSConfig{
+			CASecretRef: &infrav1.CASecretReference{Name: caSecret.Name},
+		}
+		Expect(k8sclient.Update(ctx, identity)).To(Succeed())
+
+		cluster.Spec.IdentityRef = &infrav1.VSphereIdentityReference{
+			Kind: infrav1.VSphereClusterIdentityKind,
+			Name: identity.Name,
+		}
+
+		tlsConfig, err := GetTLSConfig(ctx, k8sclient, cluster, manager.DefaultPodNamespace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tlsConfig.CABundle).To(Equal(caSecret.Data[CACertificatesKey]))
+	})
+
+	It("should prefer the TLS configuration of the cluster over the one of the VSphereClusterIdentity", func() {
+		credentialSecret := createSecret(manager.DefaultPodNamespace)
+		identity := createIdentity(credentialSecret.Name)
+		identity.Spec.TLSConfig = &infrav1.VCenterTLSConfig{MinVersion: infrav1.TLSVersion13}
+		Expect(k8sclient.Update(ctx, identity)).To(Succeed())
+
+		cluster.Spec.IdentityRef = &infrav1.VSphereIdentityReference{
+			Kind: infrav1.VSphereClusterIdentityKind,
+			Name: identity.Name,
+		}
+		cluster.Spec.TLSConfig = &infrav1.VCenterTLSConfig{InsecureSkipVerify: true}
+
+		tlsConfig, err := GetTLSConfig(ctx, k8sclient, cluster, manager.DefaultPodNamespace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tlsConfig.InsecureSkipVerify).To(BeTrue())
+		Expect(tlsConfig.MinVersion).To(BeZero())
+	})
+
+	It("should error if the CA secret does not contain the key", func() {
+		caSecret := createCASecret(cluster.Namespace, CACertificatesKey)
+		cluster.Spec.TLSConfig = &infrav1.VCenterTLSConfig{
+			CASecretRef: &infrav1.CASecretReference{Name: caSecret.Name, Key: "missing.crt"},
+		}
+
+		_, err := GetTLSConfig(ctx, k8sclient, cluster, manager.DefaultPodNamespace)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
 var _ = Describe("validateInputs", func() {
 	var (
 		ns      *corev1.Namespace
@@ -267,6 +432,20 @@ func createSecret(namespace string) *corev1.Secret {
 	return credentialSecret
 }
 
+func createCASecret(namespace, key string) *corev1.Secret {
+	caSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "ca-",
+			Namespace:    namespace,
+		},
+		Data: map[string][]byte{
+			key: []byte("-----BEGIN CERTIFICATE-----"),
+		},
+	}
+	Expect(k8sclient.Create(ctx, caSecret)).To(Succeed())
+	return caSecret
+}
+
 func createIdentity(secretName string) *infrav1.VSphereClusterIdentity {
 	identity := &infrav1.VSphereClusterIdentity{
 		ObjectMeta: metav1.ObjectMeta{
@@ -444,3 +623,25 @@ func TestIsOwnedByIdentityOrCluster(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceAccountTokenSource(t *testing.T) {
+	g := NewWithT(t)
+
+	var tokenRequest *authenticationv1.TokenRequest
+	c := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		SubResourceCreate: func(_ context.Context, _ client.Client, subResourceName string, obj client.Object, subResource client.Object, _ ...client.SubResourceCreateOption) error {
+			g.Expect(subResourceName).To(Equal("token"))
+			g.Expect(client.ObjectKeyFromObject(obj)).To(Equal(client.ObjectKey{Namespace: "capv-system", Name: "capv"}))
+			tokenRequest = subResource.(*authenticationv1.TokenRequest)
+			tokenRequest.Status.Token = "token"
+			return nil
+		},
+	}).Build()
+
+	tokenSource := NewServiceAccountTokenSource(c, client.ObjectKey{Namespace: "capv-system", Name: "capv"}, "")
+	token, err := tokenSource.Token(context.Background())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(token).To(Equal("token"))
+	g.Expect(tokenRequest.Spec.Audiences).To(Equal([]string{DefaultTokenAudience}))
+	g.Expect(tokenSource.Key()).To(Equal("serviceaccount-capv-system/capv-vcenter"))
+}
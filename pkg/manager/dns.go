@@ -0,0 +1,51 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/dns"
+)
+
+const (
+	// ExternalDNSWebhookDNSProvider identifies the DNS provider publishing records through an external-dns webhook provider.
+	ExternalDNSWebhookDNSProvider = "external-dns-webhook"
+)
+
+// GetControlPlaneEndpointDNSProvider will return a DNS provider instance used to publish DNS records
+// for control plane endpoints, or nil if no DNS provider is configured.
+func GetControlPlaneEndpointDNSProvider(ctx context.Context, dnsProvider, webhookURL string) (services.DNSProvider, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	switch dnsProvider {
+	case ExternalDNSWebhookDNSProvider:
+		if webhookURL == "" {
+			return nil, errors.Errorf("a webhook URL is required by the %s DNS provider", dnsProvider)
+		}
+		log.Info("Pick external-dns webhook DNS provider", "url", webhookURL)
+		return dns.ExternalDNSWebhookProvider(webhookURL, nil), nil
+	case "":
+		return nil, nil
+	default:
+		return nil, errors.Errorf("unknown DNS provider %q", dnsProvider)
+	}
+}
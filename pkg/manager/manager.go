@@ -30,15 +30,22 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlmgr "sigs.k8s.io/controller-runtime/pkg/manager"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	infrav1alpha3 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1alpha3"
 	infrav1alpha4 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1alpha4"
 	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	infrav1beta2 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta2"
 	vmwarev1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/vmware/v1beta1"
 	topologyv1 "sigs.k8s.io/cluster-api-provider-vsphere/internal/apis/topology/v1alpha1"
 	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/diagnostics"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/session"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/tracing"
 )
 
 // Manager is a CAPV controller manager.
@@ -54,11 +61,18 @@ func New(ctx context.Context, opts Options) (Manager, error) {
 	// Ensure the default options are set.
 	opts.defaults()
 
+	if err := opts.Shard.validate(); err != nil {
+		return nil, err
+	}
+	opts.LeaderElectionID = opts.Shard.leaderElectionID(opts.LeaderElectionID)
+
 	_ = clientgoscheme.AddToScheme(opts.Scheme)
 	_ = clusterv1.AddToScheme(opts.Scheme)
+	_ = expv1.AddToScheme(opts.Scheme)
 	_ = infrav1alpha3.AddToScheme(opts.Scheme)
 	_ = infrav1alpha4.AddToScheme(opts.Scheme)
 	_ = infrav1.AddToScheme(opts.Scheme)
+	_ = infrav1beta2.AddToScheme(opts.Scheme)
 	_ = controlplanev1.AddToScheme(opts.Scheme)
 	_ = bootstrapv1.AddToScheme(opts.Scheme)
 	_ = vmwarev1.AddToScheme(opts.Scheme)
@@ -75,20 +89,64 @@ func New(ctx context.Context, opts Options) (Manager, error) {
 		return nil, errors.Wrap(err, "unable to create manager")
 	}
 
+	vCenterProxy, err := opts.vCenterProxy()
+	if err != nil {
+		return nil, err
+	}
+	session.SetRateLimiterOptions(opts.VCenterRateLimiter)
+
+	supervisorAPIServerEndpoints, err := opts.supervisorAPIServerEndpoints()
+	if err != nil {
+		return nil, err
+	}
+
+	// Setup the tracing and flush the spans when the manager stops.
+	if opts.Tracing.SamplingRatio < 0 || opts.Tracing.SamplingRatio > 1 {
+		return nil, errors.Errorf("invalid tracing sampling ratio %v: must be between 0 and 1", opts.Tracing.SamplingRatio)
+	}
+	shutdownTracing, err := tracing.Setup(ctx, DefaultPodName, opts.Tracing)
+	if err != nil {
+		return nil, err
+	}
+	if err := mgr.Add(ctrlmgr.RunnableFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return shutdownTracing(context.Background())
+	})); err != nil {
+		return nil, errors.Wrap(err, "failed to add tracing to the manager")
+	}
+
+	// Setup the periodic reconcile profiling reports.
+	if opts.Diagnostics.Interval > 0 {
+		if err := mgr.Add(diagnostics.NewCollector(mgr.GetClient(), ctrlmetrics.Registry, opts.PodNamespace, opts.Diagnostics)); err != nil {
+			return nil, errors.Wrap(err, "failed to add diagnostics to the manager")
+		}
+	}
+
 	// Build the controller manager context.
 	controllerManagerContext := &capvcontext.ControllerManagerContext{
-		WatchNamespaces:         opts.Cache.DefaultNamespaces,
-		Namespace:               opts.PodNamespace,
-		Name:                    opts.PodName,
-		LeaderElectionID:        opts.LeaderElectionID,
-		LeaderElectionNamespace: opts.LeaderElectionNamespace,
-		Client:                  mgr.GetClient(),
-		Logger:                  opts.Logger,
-		Scheme:                  opts.Scheme,
-		Username:                opts.Username,
-		Password:                opts.Password,
-		NetworkProvider:         opts.NetworkProvider,
-		WatchFilterValue:        opts.WatchFilterValue,
+		WatchNamespaces:                   opts.Cache.DefaultNamespaces,
+		Namespace:                         opts.PodNamespace,
+		Name:                              opts.PodName,
+		LeaderElectionID:                  opts.LeaderElectionID,
+		LeaderElectionNamespace:           opts.LeaderElectionNamespace,
+		Client:                            mgr.GetClient(),
+		Scheme:                            opts.Scheme,
+		Username:                          opts.Username,
+		Password:                          opts.Password,
+		VCenterProxy:                      vCenterProxy,
+		NetworkProvider:                   opts.NetworkProvider,
+		ControlPlaneEndpointDNSProvider:   opts.ControlPlaneEndpointDNSProvider,
+		ControlPlaneEndpointDNSWebhookURL: opts.ControlPlaneEndpointDNSWebhookURL,
+		SupervisorAPIServerEndpoints:      supervisorAPIServerEndpoints,
+		WatchFilterValue:                  opts.WatchFilterValue,
+		DryRun:                            opts.DryRun,
+		EventAggregationWindow:            opts.EventAggregationWindow,
+		ResourceUsageRefreshInterval:      opts.ResourceUsageRefreshInterval,
+		PreTerminateHookTimeout:           opts.PreTerminateHookTimeout,
+		VMDeletionFailureTimeout:          opts.VMDeletionFailureTimeout,
+		VMTaskTimeout:                     opts.VMTaskTimeout,
+		ClusterTeardownConcurrency:        opts.ClusterTeardownConcurrency,
+		ControlPlaneConcurrency:           opts.ControlPlaneConcurrency,
 	}
 
 	// Add the requested items to the manager.
@@ -118,8 +176,9 @@ func UpdateCredentials(opts *Options) {
 
 // InitializeWatch adds a filesystem watcher for the capv credentials file.
 // In case of any update to the credentials file, the new credentials are passed to the capv manager context.
-func InitializeWatch(controllerManagerContext *capvcontext.ControllerManagerContext, managerOpts *Options) (watch *fsnotify.Watcher, err error) {
+func InitializeWatch(ctx context.Context, managerOpts *Options) (watch *fsnotify.Watcher, err error) {
 	capvCredentialsFile := managerOpts.CredentialsFile
+	log := ctrl.LoggerFrom(ctx).WithValues("credentialsFile", capvCredentialsFile)
 	updateEventCh := make(chan bool)
 	watch, err = fsnotify.NewWatcher()
 	if err != nil {
@@ -132,9 +191,9 @@ func InitializeWatch(controllerManagerContext *capvcontext.ControllerManagerCont
 		for {
 			select {
 			case err := <-watch.Errors:
-				controllerManagerContext.Logger.Error(err, "Received error on CAPV credential watcher")
+				log.Error(err, "Received error on CAPV credential watcher")
 			case event := <-watch.Events:
-				controllerManagerContext.Logger.Info(fmt.Sprintf("Received event %v on the credential file %s", event, capvCredentialsFile))
+				log.Info("Received event on the credential file", "event", event.String())
 				updateEventCh <- true
 			}
 		}
@@ -17,6 +17,7 @@ limitations under the License.
 package manager
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
@@ -25,8 +26,6 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
 	"gopkg.in/fsnotify.v1"
-
-	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/context/fake"
 )
 
 const (
@@ -38,6 +37,7 @@ const (
 
 func TestManager_FileWatch(t *testing.T) {
 	g := NewWithT(t)
+	ctx := context.Background()
 	contentFmt := `---
 username: '%s'
 password: '%s'
@@ -55,7 +55,7 @@ password: '%s'
 			Password:        password,
 		}
 
-		watch, err := InitializeWatch(fake.NewControllerManagerContext(), managerOptsTest)
+		watch, err := InitializeWatch(ctx, managerOptsTest)
 		// Match initial credentials
 		g.Expect(err).ToNot(HaveOccurred())
 		g.Expect(managerOptsTest.Username).To(Equal(username))
@@ -87,7 +87,7 @@ password: '%s'
 			Username:        username,
 			Password:        password,
 		}
-		watch, err := InitializeWatch(fake.NewControllerManagerContext(), managerOptsTest)
+		watch, err := InitializeWatch(ctx, managerOptsTest)
 		// Match initial credentials
 		g.Expect(err).ToNot(HaveOccurred())
 		g.Expect(managerOptsTest.Username).To(Equal(username))
@@ -122,7 +122,7 @@ password: '%s'
 			Username:        username,
 			Password:        password,
 		}
-		_, err = InitializeWatch(fake.NewControllerManagerContext(), managerOptsTest)
+		_, err = InitializeWatch(ctx, managerOptsTest)
 		// Match initial credentials
 		g.Expect(err).To(HaveOccurred())
 	})
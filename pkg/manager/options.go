@@ -20,8 +20,11 @@ import (
 	"context"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
@@ -29,6 +32,9 @@ import (
 	"sigs.k8s.io/yaml"
 
 	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/diagnostics"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/session"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/tracing"
 )
 
 // AddToManagerFunc is a function that can be optionally specified with
@@ -62,6 +68,55 @@ type Options struct {
 	// CredentialsFile is the file that contains credentials of CAPV
 	CredentialsFile string
 
+	// VCenterProxyURL is the URL of the HTTP or SOCKS5 proxy used to connect to
+	// vSphere endpoints. It can be overridden per VSphereCluster.
+	VCenterProxyURL string
+
+	// VCenterNoProxy is a list of hosts, domains, IP addresses and CIDRs which
+	// are connected to directly instead of through VCenterProxyURL.
+	VCenterNoProxy []string
+
+	// VCenterCABundleFile is the file that contains the PEM encoded CA certificates
+	// used to verify the certificates of vSphere endpoints.
+	VCenterCABundleFile string
+
+	// VCenterRateLimiter configures the rate limiting and circuit breaking of
+	// the calls to the vCenter API, shared by all sessions to the same vCenter.
+	VCenterRateLimiter session.RateLimiterOptions
+
+	// EventAggregationWindow is the window in which identical events recorded for
+	// an object by the VSphereCluster and VSphereVM controllers are aggregated.
+	EventAggregationWindow time.Duration
+
+	// ResourceUsageRefreshInterval is the interval in which the resource usage
+	// of the VMs of a VSphereCluster is refreshed.
+	ResourceUsageRefreshInterval time.Duration
+
+	// PreTerminateHookTimeout is the time after which the VM of a deleted
+	// VSphereMachine or VSphereVM is deleted even if pre-terminate delete hooks remain.
+	PreTerminateHookTimeout time.Duration
+
+	// VMDeletionFailureTimeout is the time after the deletion of a VSphereVM
+	// after which its finalizer is removed if the VM can't be destroyed and is
+	// no longer found by its UUID.
+	VMDeletionFailureTimeout time.Duration
+
+	// VMTaskTimeout is the time after which a clone or reconfigure task of a
+	// VSphereVM is canceled and retried.
+	VMTaskTimeout time.Duration
+
+	// ClusterTeardownConcurrency is the maximum number of VMs of a deleted
+	// cluster which are destroyed at the same time.
+	ClusterTeardownConcurrency int
+
+	// ControlPlaneConcurrency is the number of control plane VSphereMachines
+	// and VSphereVMs reconciled concurrently in separate work queues.
+	ControlPlaneConcurrency int
+
+	// Shard is the shard of namespaces reconciled by the manager. Each shard
+	// elects its own leader, so multiple managers reconcile concurrently.
+	Shard Shard
+
 	KubeConfig *rest.Config
 
 	// AddToManager is a function that can be optionally specified with
@@ -74,10 +129,36 @@ type Options struct {
 	// VIM based clusters and managers will not need to set this flag.
 	NetworkProvider string
 
+	// ControlPlaneEndpointDNSProvider is the DNS provider used to publish DNS records for the
+	// control plane endpoint of Supervisor based clusters requesting it.
+	// If not set, DNS records are not published.
+	ControlPlaneEndpointDNSProvider string
+
+	// ControlPlaneEndpointDNSWebhookURL is the URL of the external-dns webhook provider used
+	// when ControlPlaneEndpointDNSProvider is set to external-dns-webhook.
+	ControlPlaneEndpointDNSWebhookURL string
+
+	// SupervisorAPIServerEndpoints are the IP addresses or DNS names of the Supervisor
+	// API server which are advertised to workload clusters of Supervisor based clusters
+	// instead of the discovered endpoint, e.g. a VIP or an external DNS name.
+	// Multiple endpoints can be set for highly available Supervisors.
+	SupervisorAPIServerEndpoints []string
+
 	// WatchFilterValue is used to filter incoming objects by label.
 	//
 	// Defaults to the empty string and by that not filter anything.
 	WatchFilterValue string
+
+	// DryRun makes the VSphereVM controller log the vSphere mutating operations,
+	// e.g. clone, reconfigure and power operations, instead of executing them.
+	DryRun bool
+
+	// Tracing configures the OpenTelemetry tracing of the reconcilers and of the
+	// calls to vCenter.
+	Tracing tracing.Options
+
+	// Diagnostics configures the periodic reconcile profiling reports.
+	Diagnostics diagnostics.Options
 }
 
 func (o *Options) defaults() {
@@ -133,3 +214,40 @@ func (o *Options) readAndSetCredentials() {
 	o.Username = credentials["username"]
 	o.Password = credentials["password"]
 }
+
+// supervisorAPIServerEndpoints returns the validated SupervisorAPIServerEndpoints.
+func (o *Options) supervisorAPIServerEndpoints() ([]string, error) {
+	endpoints := make([]string, 0, len(o.SupervisorAPIServerEndpoints))
+	for _, endpoint := range o.SupervisorAPIServerEndpoints {
+		endpoint = strings.TrimSpace(endpoint)
+		if endpoint == "" {
+			continue
+		}
+		if validation.IsValidIP(nil, endpoint) != nil && len(validation.IsDNS1123Subdomain(endpoint)) > 0 {
+			return nil, errors.Errorf("invalid Supervisor API server endpoint %q: must be an IP address or a DNS name", endpoint)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints, nil
+}
+
+// vCenterProxy returns the proxy used to connect to vSphere endpoints,
+// or nil if neither a proxy nor a CA bundle is configured.
+func (o *Options) vCenterProxy() (*session.Proxy, error) {
+	if o.VCenterProxyURL == "" && o.VCenterCABundleFile == "" {
+		return nil, nil
+	}
+
+	proxy := &session.Proxy{
+		URL:     o.VCenterProxyURL,
+		NoProxy: o.VCenterNoProxy,
+	}
+	if o.VCenterCABundleFile != "" {
+		caBundle, err := os.ReadFile(o.VCenterCABundleFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read vCenter CA bundle file %s", o.VCenterCABundleFile)
+		}
+		proxy.CABundle = caBundle
+	}
+	return proxy, nil
+}
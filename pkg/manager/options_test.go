@@ -85,3 +85,47 @@ password: '%s'
 		})
 	}
 }
+
+func TestOptions_SupervisorAPIServerEndpoints(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoints []string
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:      "no endpoints",
+			endpoints: nil,
+			want:      []string{},
+		},
+		{
+			name:      "IP addresses and DNS names",
+			endpoints: []string{"10.0.0.100", " supervisor.example.com", "fd00::1", ""},
+			want:      []string{"10.0.0.100", "supervisor.example.com", "fd00::1"},
+		},
+		{
+			name:      "endpoint with port",
+			endpoints: []string{"10.0.0.100:6443"},
+			wantErr:   true,
+		},
+		{
+			name:      "URL",
+			endpoints: []string{"https://supervisor.example.com"},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			o := &Options{SupervisorAPIServerEndpoints: tt.endpoints}
+
+			got, err := o.supervisorAPIServerEndpoints()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
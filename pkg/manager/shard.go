@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Shard is the shard of namespaces reconciled by a manager, when the reconciliation
+// is sharded by namespace across multiple managers.
+type Shard struct {
+	// Count is the number of shards. Reconciliation is not sharded if Count is
+	// less than 2.
+	Count int
+
+	// Index is the index of the shard reconciled by the manager, between 0 and
+	// Count-1.
+	Index int
+}
+
+// Enabled returns true if the reconciliation is sharded.
+func (s Shard) Enabled() bool {
+	return s.Count > 1
+}
+
+// Contains returns true if objects in the namespace are reconciled by the shard.
+// Cluster-scoped objects are reconciled by the shard containing the empty namespace.
+func (s Shard) Contains(namespace string) bool {
+	if !s.Enabled() {
+		return true
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(namespace))
+	return int(h.Sum32()%uint32(s.Count)) == s.Index //nolint:gosec // Count is positive.
+}
+
+// NewQueue returns a function creating work queues which drop the requests for objects
+// in namespaces not contained in the shard. It can be used as controller.Options.NewQueue.
+func (s Shard) NewQueue(newQueue func(string, workqueue.TypedRateLimiter[reconcile.Request]) workqueue.TypedRateLimitingInterface[reconcile.Request]) func(string, workqueue.TypedRateLimiter[reconcile.Request]) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+	return func(controllerName string, rateLimiter workqueue.TypedRateLimiter[reconcile.Request]) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+		var queue workqueue.TypedRateLimitingInterface[reconcile.Request]
+		if newQueue != nil {
+			queue = newQueue(controllerName, rateLimiter)
+		} else {
+			queue = workqueue.NewTypedRateLimitingQueueWithConfig(rateLimiter, workqueue.TypedRateLimitingQueueConfig[reconcile.Request]{
+				Name: controllerName,
+			})
+		}
+		if !s.Enabled() {
+			return queue
+		}
+		return &shardQueue{TypedRateLimitingInterface: queue, shard: s}
+	}
+}
+
+func (s Shard) validate() error {
+	if s.Enabled() && (s.Index < 0 || s.Index >= s.Count) {
+		return errors.Errorf("invalid shard index %d: must be between 0 and %d", s.Index, s.Count-1)
+	}
+	return nil
+}
+
+// leaderElectionID returns the leader election ID of the shard, so one manager per
+// shard is elected.
+func (s Shard) leaderElectionID(id string) string {
+	if !s.Enabled() {
+		return id
+	}
+	return fmt.Sprintf("%s-shard-%d", id, s.Index)
+}
+
+// shardQueue is a work queue dropping the requests for objects in namespaces not
+// contained in the shard.
+type shardQueue struct {
+	workqueue.TypedRateLimitingInterface[reconcile.Request]
+	shard Shard
+}
+
+func (q *shardQueue) Add(item reconcile.Request) {
+	if q.shard.Contains(item.Namespace) {
+		q.TypedRateLimitingInterface.Add(item)
+	}
+}
+
+func (q *shardQueue) AddAfter(item reconcile.Request, duration time.Duration) {
+	if q.shard.Contains(item.Namespace) {
+		q.TypedRateLimitingInterface.AddAfter(item, duration)
+	}
+}
+
+func (q *shardQueue) AddRateLimited(item reconcile.Request) {
+	if q.shard.Contains(item.Namespace) {
+		q.TypedRateLimitingInterface.AddRateLimited(item)
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestShard_Contains(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(Shard{}.Contains("foo")).To(BeTrue())
+	g.Expect(Shard{Count: 1}.Contains("foo")).To(BeTrue())
+
+	// Every namespace is contained in exactly one shard.
+	shards := []Shard{{Count: 3, Index: 0}, {Count: 3, Index: 1}, {Count: 3, Index: 2}}
+	counts := make([]int, len(shards))
+	for i := range 100 {
+		namespace := fmt.Sprintf("namespace-%d", i)
+		contained := 0
+		for j, s := range shards {
+			if s.Contains(namespace) {
+				contained++
+				counts[j]++
+			}
+		}
+		g.Expect(contained).To(Equal(1), namespace)
+	}
+	for _, c := range counts {
+		g.Expect(c).To(BeNumerically(">", 0))
+	}
+}
+
+func TestShard_validate(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(Shard{}.validate()).To(Succeed())
+	g.Expect(Shard{Count: 2, Index: 1}.validate()).To(Succeed())
+	g.Expect(Shard{Count: 2, Index: 2}.validate()).ToNot(Succeed())
+	g.Expect(Shard{Count: 2, Index: -1}.validate()).ToNot(Succeed())
+}
+
+func TestShard_leaderElectionID(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(Shard{}.leaderElectionID("capv")).To(Equal("capv"))
+	g.Expect(Shard{Count: 3, Index: 2}.leaderElectionID("capv")).To(Equal("capv-shard-2"))
+}
+
+func TestShard_NewQueue(t *testing.T) {
+	g := NewWithT(t)
+
+	shard := Shard{Count: 2, Index: 0}
+	var inShard, notInShard string
+	for i := 0; inShard == "" || notInShard == ""; i++ {
+		namespace := fmt.Sprintf("namespace-%d", i)
+		if shard.Contains(namespace) {
+			inShard = namespace
+		} else {
+			notInShard = namespace
+		}
+	}
+
+	queue := shard.NewQueue(nil)("test", nil)
+	defer queue.ShutDown()
+	queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: notInShard, Name: "foo"}})
+	queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: inShard, Name: "foo"}})
+	g.Expect(queue.Len()).To(Equal(1))
+
+	item, _ := queue.Get()
+	g.Expect(item.Namespace).To(Equal(inShard))
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	ipamLabels = []string{"namespace", "pool_kind", "pool_name"}
+
+	ipAddressClaimBindDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "capv",
+		Subsystem: "ipam",
+		Name:      "ipaddressclaim_bind_duration_seconds",
+		Help:      "Time from the creation of an IPAddressClaim of a VSphereVM until an IPAddress is bound to it.",
+		Buckets:   []float64{1, 5, 10, 30, 60, 120, 300, 600, 1800, 3600},
+	}, ipamLabels)
+
+	ipAddressClaimsPendingDesc = prometheus.NewDesc(
+		"capv_ipam_ipaddressclaims_pending",
+		"Number of IPAddressClaims waiting for an IPAddress, per pool.",
+		ipamLabels, nil,
+	)
+
+	ipAddressClaimsExhaustedDesc = prometheus.NewDesc(
+		"capv_ipam_ipaddressclaims_pool_exhausted",
+		"Number of IPAddressClaims whose pool reports that no IP address is available, per pool.",
+		ipamLabels, nil,
+	)
+
+	// observedClaims holds the UIDs of the IPAddressClaims whose bind duration
+	// has been observed, so that it is only observed once per claim.
+	observedClaims sync.Map
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(ipAddressClaimBindDuration)
+}
+
+// ObserveIPAddressClaimBound observes the bind duration of an IPAddressClaim
+// with an IPAddress. The duration is measured until the Ready condition of the
+// claim became true, or until now if the IPAM provider does not set it.
+func ObserveIPAddressClaimBound(claim *ipamv1.IPAddressClaim) {
+	if claim.Status.AddressRef.Name == "" || claim.CreationTimestamp.IsZero() {
+		return
+	}
+	if _, observed := observedClaims.LoadOrStore(claim.UID, struct{}{}); observed {
+		return
+	}
+
+	boundAt := time.Now()
+	if conditions.IsTrue(claim, clusterv1.ReadyCondition) {
+		boundAt = conditions.GetLastTransitionTime(claim, clusterv1.ReadyCondition).Time
+	}
+	duration := boundAt.Sub(claim.CreationTimestamp.Time)
+	if duration < 0 {
+		duration = 0
+	}
+	ipAddressClaimBindDuration.WithLabelValues(claim.Namespace, claim.Spec.PoolRef.Kind, claim.Spec.PoolRef.Name).Observe(duration.Seconds())
+}
+
+// ForgetIPAddressClaim drops the state kept for an IPAddressClaim which is deleted.
+func ForgetIPAddressClaim(claim *ipamv1.IPAddressClaim) {
+	observedClaims.Delete(claim.UID)
+}
+
+// IsPoolExhausted reports whether the IPAM provider reports that the pool of
+// the IPAddressClaim has no IP address available.
+func IsPoolExhausted(claim *ipamv1.IPAddressClaim) bool {
+	return claim.Status.AddressRef.Name == "" &&
+		conditions.IsFalse(claim, clusterv1.ReadyCondition) &&
+		conditions.GetReason(claim, clusterv1.ReadyCondition) == ipamv1.PoolExhaustedReason
+}
+
+// ipAddressClaimsCollector collects the number of pending IPAddressClaims per
+// pool when the metrics are scraped.
+type ipAddressClaimsCollector struct {
+	reader client.Reader
+}
+
+// RegisterIPAddressClaimsCollector registers a collector which reports the number
+// of pending IPAddressClaims per pool, read with the given reader. Registering
+// more than once is a no-op.
+func RegisterIPAddressClaimsCollector(reader client.Reader) error {
+	err := ctrlmetrics.Registry.Register(&ipAddressClaimsCollector{reader: reader})
+	if alreadyRegistered := (prometheus.AlreadyRegisteredError{}); err != nil && !errors.As(err, &alreadyRegistered) {
+		return errors.Wrap(err, "failed to register IPAddressClaims collector")
+	}
+	return nil
+}
+
+// Describe implements prometheus.Collector.
+func (c *ipAddressClaimsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- ipAddressClaimsPendingDesc
+	ch <- ipAddressClaimsExhaustedDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *ipAddressClaimsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	claims := &ipamv1.IPAddressClaimList{}
+	if err := c.reader.List(ctx, claims); err != nil {
+		ctrl.Log.WithName("metrics").Error(err, "Failed to list IPAddressClaims")
+		return
+	}
+
+	type poolKey struct {
+		namespace, kind, name string
+	}
+	pending := map[poolKey]int{}
+	exhausted := map[poolKey]int{}
+	for i := range claims.Items {
+		claim := &claims.Items[i]
+		if claim.Status.AddressRef.Name != "" || !claim.DeletionTimestamp.IsZero() {
+			continue
+		}
+		key := poolKey{namespace: claim.Namespace, kind: claim.Spec.PoolRef.Kind, name: claim.Spec.PoolRef.Name}
+		pending[key]++
+		if IsPoolExhausted(claim) {
+			exhausted[key]++
+		}
+	}
+
+	for key, count := range pending {
+		ch <- prometheus.MustNewConstMetric(ipAddressClaimsPendingDesc, prometheus.GaugeValue, float64(count), key.namespace, key.kind, key.name)
+		ch <- prometheus.MustNewConstMetric(ipAddressClaimsExhaustedDesc, prometheus.GaugeValue, float64(exhausted[key]), key.namespace, key.kind, key.name)
+	}
+}
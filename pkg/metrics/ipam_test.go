@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func ipAddressClaim(name, poolName string) *ipamv1.IPAddressClaim {
+	return &ipamv1.IPAddressClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "default",
+			Name:              name,
+			UID:               types.UID(name),
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Minute)),
+		},
+		Spec: ipamv1.IPAddressClaimSpec{
+			PoolRef: corev1.TypedLocalObjectReference{Kind: "InClusterIPPool", Name: poolName},
+		},
+	}
+}
+
+func Test_ObserveIPAddressClaimBound(t *testing.T) {
+	g := NewWithT(t)
+
+	histogram := ipAddressClaimBindDuration.WithLabelValues("default", "InClusterIPPool", "observed-pool").(prometheus.Histogram)
+	sampleCount := func() uint64 {
+		metric := &dto.Metric{}
+		g.Expect(histogram.Write(metric)).To(Succeed())
+		return metric.GetHistogram().GetSampleCount()
+	}
+
+	claim := ipAddressClaim("claim", "observed-pool")
+	ObserveIPAddressClaimBound(claim)
+	g.Expect(sampleCount()).To(BeZero(), "a pending claim is not observed")
+
+	claim.Status.AddressRef.Name = "address"
+	ObserveIPAddressClaimBound(claim)
+	ObserveIPAddressClaimBound(claim)
+	g.Expect(sampleCount()).To(Equal(uint64(1)), "a bound claim is observed once")
+
+	ForgetIPAddressClaim(claim)
+	ObserveIPAddressClaimBound(claim)
+	g.Expect(sampleCount()).To(Equal(uint64(2)), "a forgotten claim is observed again")
+}
+
+func Test_ipAddressClaimsCollector(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(ipamv1.AddToScheme(scheme)).To(Succeed())
+
+	bound := ipAddressClaim("bound", "pool-1")
+	bound.Status.AddressRef.Name = "address"
+	exhausted := ipAddressClaim("exhausted", "pool-2")
+	conditions.MarkFalse(exhausted, clusterv1.ReadyCondition, ipamv1.PoolExhaustedReason, clusterv1.ConditionSeverityError, "")
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		bound,
+		ipAddressClaim("pending", "pool-1"),
+		exhausted,
+		ipAddressClaim("other-pending", "pool-2"),
+	).Build()
+
+	expected := `
+# HELP capv_ipam_ipaddressclaims_pending Number of IPAddressClaims waiting for an IPAddress, per pool.
+# TYPE capv_ipam_ipaddressclaims_pending gauge
+capv_ipam_ipaddressclaims_pending{namespace="default",pool_kind="InClusterIPPool",pool_name="pool-1"} 1
+capv_ipam_ipaddressclaims_pending{namespace="default",pool_kind="InClusterIPPool",pool_name="pool-2"} 2
+# HELP capv_ipam_ipaddressclaims_pool_exhausted Number of IPAddressClaims whose pool reports that no IP address is available, per pool.
+# TYPE capv_ipam_ipaddressclaims_pool_exhausted gauge
+capv_ipam_ipaddressclaims_pool_exhausted{namespace="default",pool_kind="InClusterIPPool",pool_name="pool-1"} 0
+capv_ipam_ipaddressclaims_pool_exhausted{namespace="default",pool_kind="InClusterIPPool",pool_name="pool-2"} 1
+`
+	g.Expect(testutil.CollectAndCompare(&ipAddressClaimsCollector{reader: c}, strings.NewReader(expected))).To(Succeed())
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package record provides an event recorder which aggregates identical events.
+package record
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/session"
+)
+
+// Reasons of the events recorded by the controllers.
+const (
+	// VCenterUnreachableReason is used when no session to vCenter can be created.
+	VCenterUnreachableReason = "VCenterUnreachable"
+
+	// VCenterThrottledReason is used when calls to vCenter are rejected because
+	// vCenter is overloaded.
+	VCenterThrottledReason = "VCenterThrottled"
+
+	// ReconcileFailedReason is used when reconciling an object failed.
+	ReconcileFailedReason = "ReconcileFailed"
+
+	// DeleteFailedReason is used when deleting an object failed.
+	DeleteFailedReason = "DeleteFailed"
+)
+
+// DefaultAggregationWindow is the default window in which identical events are aggregated.
+const DefaultAggregationWindow = 5 * time.Minute
+
+// ReasonForError returns the reason of the event for the given error, or the
+// given reason if the error has no specific reason.
+func ReasonForError(err error, reason string) string {
+	if errors.Is(err, session.ErrCircuitOpen) {
+		return VCenterThrottledReason
+	}
+	return reason
+}
+
+// eventKey identifies identical events of an object.
+type eventKey struct {
+	uid       types.UID
+	eventType string
+	reason    string
+	message   string
+}
+
+// aggregatedEvent is an event whose identical occurrences are counted.
+type aggregatedEvent struct {
+	object runtime.Object
+	// recorded is the time the event was last recorded.
+	recorded time.Time
+	// count is the number of occurrences which have not been recorded since.
+	count int
+}
+
+// AggregatingRecorder is a record.EventRecorder which records identical events of an object,
+// e.g. the same vCenter fault returned on every reconcile, at most once per window.
+// The occurrences within the window are counted and recorded with the next event after
+// the window passed, so the API server is not flooded with events during vCenter outages
+// affecting many objects.
+type AggregatingRecorder struct {
+	recorder record.EventRecorder
+	window   time.Duration
+	now      func() time.Time
+
+	mu     sync.Mutex
+	events map[eventKey]*aggregatedEvent
+	// pruned is the time the expired events were last pruned.
+	pruned time.Time
+}
+
+var _ record.EventRecorder = &AggregatingRecorder{}
+
+// NewAggregatingRecorder returns an AggregatingRecorder recording the events with the given
+// recorder. Events are not aggregated if the window is 0.
+func NewAggregatingRecorder(recorder record.EventRecorder, window time.Duration) *AggregatingRecorder {
+	return &AggregatingRecorder{
+		recorder: recorder,
+		window:   window,
+		now:      time.Now,
+		events:   map[eventKey]*aggregatedEvent{},
+	}
+}
+
+// Event implements record.EventRecorder.
+func (r *AggregatingRecorder) Event(object runtime.Object, eventType, reason, message string) {
+	if r.window <= 0 {
+		r.recorder.Event(object, eventType, reason, message)
+		return
+	}
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		r.recorder.Event(object, eventType, reason, message)
+		return
+	}
+	key := eventKey{uid: accessor.GetUID(), eventType: eventType, reason: reason, message: message}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	defer r.prune(now)
+
+	event, ok := r.events[key]
+	if ok && now.Sub(event.recorded) < r.window {
+		event.count++
+		return
+	}
+	if ok && event.count > 0 {
+		message = aggregatedMessage(message, event.count+1, now.Sub(event.recorded))
+	}
+	r.events[key] = &aggregatedEvent{object: object, recorded: now}
+	r.recorder.Event(object, eventType, reason, message)
+}
+
+// Eventf implements record.EventRecorder.
+func (r *AggregatingRecorder) Eventf(object runtime.Object, eventType, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventType, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// AnnotatedEventf implements record.EventRecorder. Annotated events are not aggregated.
+func (r *AggregatingRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventType, reason, messageFmt string, args ...interface{}) {
+	r.recorder.AnnotatedEventf(object, annotations, eventType, reason, messageFmt, args...)
+}
+
+// prune removes the events whose window passed. The occurrences of the removed events
+// which have not been recorded yet are recorded, so they don't get lost if the event
+// does not occur again. It has to be called with the lock held.
+func (r *AggregatingRecorder) prune(now time.Time) {
+	if now.Sub(r.pruned) < r.window {
+		return
+	}
+	r.pruned = now
+	for key, event := range r.events {
+		if now.Sub(event.recorded) < r.window {
+			continue
+		}
+		delete(r.events, key)
+		if event.count > 0 {
+			r.recorder.Event(event.object, key.eventType, key.reason, aggregatedMessage(key.message, event.count, now.Sub(event.recorded)))
+		}
+	}
+}
+
+// aggregatedMessage returns the message of an event which occurred count times since it
+// was last recorded.
+func aggregatedMessage(message string, count int, since time.Duration) string {
+	return fmt.Sprintf("%s (repeated %d times in the last %s)", message, count, since.Round(time.Second))
+}
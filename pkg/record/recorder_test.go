@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package record
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/session"
+)
+
+func events(fake *record.FakeRecorder) []string {
+	recorded := []string{}
+	for {
+		select {
+		case event := <-fake.Events:
+			recorded = append(recorded, event)
+		default:
+			return recorded
+		}
+	}
+}
+
+func TestAggregatingRecorder(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Now()
+	fake := record.NewFakeRecorder(100)
+	recorder := NewAggregatingRecorder(fake, time.Minute)
+	recorder.now = func() time.Time { return now }
+
+	vm1 := &infrav1.VSphereVM{ObjectMeta: metav1.ObjectMeta{UID: "vm-1"}}
+	vm2 := &infrav1.VSphereVM{ObjectMeta: metav1.ObjectMeta{UID: "vm-2"}}
+
+	// The first occurrence of an event is recorded, identical events within the window are not.
+	for i := 0; i < 3; i++ {
+		recorder.Eventf(vm1, corev1.EventTypeWarning, ReconcileFailedReason, "failed: %s", "fault")
+	}
+	recorder.Event(vm1, corev1.EventTypeWarning, ReconcileFailedReason, "failed: other fault")
+	recorder.Event(vm2, corev1.EventTypeWarning, ReconcileFailedReason, "failed: fault")
+	g.Expect(events(fake)).To(Equal([]string{
+		"Warning ReconcileFailed failed: fault",
+		"Warning ReconcileFailed failed: other fault",
+		"Warning ReconcileFailed failed: fault",
+	}))
+
+	// The next occurrence after the window records the count.
+	now = now.Add(30 * time.Second)
+	recorder.Event(vm1, corev1.EventTypeWarning, ReconcileFailedReason, "failed: fault")
+	now = now.Add(30 * time.Second)
+	recorder.Event(vm1, corev1.EventTypeWarning, ReconcileFailedReason, "failed: fault")
+	g.Expect(events(fake)).To(Equal([]string{
+		"Warning ReconcileFailed failed: fault (repeated 4 times in the last 1m0s)",
+	}))
+
+	// Occurrences which have not been recorded are recorded once the window passed.
+	recorder.Event(vm1, corev1.EventTypeWarning, ReconcileFailedReason, "failed: fault")
+	now = now.Add(2 * time.Minute)
+	recorder.Event(vm2, corev1.EventTypeNormal, "Created", "created")
+	g.Expect(events(fake)).To(ConsistOf(
+		"Warning ReconcileFailed failed: fault (repeated 1 times in the last 2m0s)",
+		"Normal Created created",
+	))
+	g.Expect(recorder.events).To(HaveLen(1))
+}
+
+func TestAggregatingRecorder_WithoutWindow(t *testing.T) {
+	g := NewWithT(t)
+
+	fake := record.NewFakeRecorder(100)
+	recorder := NewAggregatingRecorder(fake, 0)
+	vm := &infrav1.VSphereVM{ObjectMeta: metav1.ObjectMeta{UID: "vm"}}
+
+	recorder.Event(vm, corev1.EventTypeWarning, ReconcileFailedReason, "failed")
+	recorder.Event(vm, corev1.EventTypeWarning, ReconcileFailedReason, "failed")
+	g.Expect(events(fake)).To(HaveLen(2))
+}
+
+func TestReasonForError(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(ReasonForError(errors.Wrap(session.ErrCircuitOpen, "failed to clone VM"), ReconcileFailedReason)).To(Equal(VCenterThrottledReason))
+	g.Expect(ReasonForError(errors.New("failed to clone VM"), ReconcileFailedReason)).To(Equal(ReconcileFailedReason))
+}
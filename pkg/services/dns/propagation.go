@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"context"
+	"net"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services"
+)
+
+// Resolver resolves DNS names; it is implemented by net.Resolver.
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// IsPropagated returns true if the hostname of the record resolves to the addresses of its target.
+func IsPropagated(ctx context.Context, resolver Resolver, record services.DNSRecord) (bool, error) {
+	addresses, err := lookupHost(ctx, resolver, record.Hostname)
+	if err != nil || addresses == nil {
+		return false, err
+	}
+
+	expectedAddresses := []string{record.Target}
+	if net.ParseIP(record.Target) == nil {
+		if expectedAddresses, err = lookupHost(ctx, resolver, record.Target); err != nil || expectedAddresses == nil {
+			return false, err
+		}
+	}
+
+	return sets.New(addresses...).HasAll(expectedAddresses...), nil
+}
+
+// lookupHost returns the addresses of a host, or nil if the host does not exist (yet).
+func lookupHost(ctx context.Context, resolver Resolver, host string) ([]string, error) {
+	addresses, err := resolver.LookupHost(ctx, host)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to resolve %s", host)
+	}
+	return addresses, nil
+}
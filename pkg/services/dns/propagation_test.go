@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services"
+)
+
+type fakeResolver map[string][]string
+
+func (r fakeResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	addresses, ok := r[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return addresses, nil
+}
+
+func TestIsPropagated(t *testing.T) {
+	tests := []struct {
+		name     string
+		resolver fakeResolver
+		record   services.DNSRecord
+		want     bool
+	}{
+		{
+			name:     "hostname not resolvable yet",
+			resolver: fakeResolver{},
+			record:   services.DNSRecord{Hostname: "cp.example.com", Target: "10.0.0.1"},
+			want:     false,
+		},
+		{
+			name:     "hostname resolves to a stale address",
+			resolver: fakeResolver{"cp.example.com": {"10.0.0.2"}},
+			record:   services.DNSRecord{Hostname: "cp.example.com", Target: "10.0.0.1"},
+			want:     false,
+		},
+		{
+			name:     "hostname resolves to the target address",
+			resolver: fakeResolver{"cp.example.com": {"10.0.0.1"}},
+			record:   services.DNSRecord{Hostname: "cp.example.com", Target: "10.0.0.1"},
+			want:     true,
+		},
+		{
+			name:     "hostname resolves to the addresses of the target hostname",
+			resolver: fakeResolver{"cp.example.com": {"10.0.0.1", "10.0.0.2"}, "lb.example.com": {"10.0.0.1", "10.0.0.2"}},
+			record:   services.DNSRecord{Hostname: "cp.example.com", Target: "lb.example.com"},
+			want:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := IsPropagated(context.Background(), tt.resolver, tt.record)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dns contains DNS providers used to publish DNS records for control plane endpoints.
+package dns
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services"
+)
+
+const (
+	// webhookMediaType is the media type used by the external-dns webhook provider API.
+	webhookMediaType = "application/external.dns.webhook+json;version=1"
+
+	recordTypeA     = "A"
+	recordTypeAAAA  = "AAAA"
+	recordTypeCNAME = "CNAME"
+)
+
+// webhookEndpoint is a DNS record as defined by the external-dns webhook provider API.
+type webhookEndpoint struct {
+	DNSName    string   `json:"dnsName"`
+	Targets    []string `json:"targets"`
+	RecordType string   `json:"recordType"`
+	RecordTTL  int64    `json:"recordTTL,omitempty"`
+}
+
+// webhookChanges is a set of changes to DNS records as defined by the external-dns webhook provider API.
+type webhookChanges struct {
+	Create    []*webhookEndpoint `json:"Create,omitempty"`
+	UpdateOld []*webhookEndpoint `json:"UpdateOld,omitempty"`
+	UpdateNew []*webhookEndpoint `json:"UpdateNew,omitempty"`
+	Delete    []*webhookEndpoint `json:"Delete,omitempty"`
+}
+
+type webhookProvider struct {
+	url    string
+	client *http.Client
+}
+
+// ExternalDNSWebhookProvider returns a DNS provider publishing records through an
+// external-dns webhook provider (e.g. route53, infoblox) listening at the given URL.
+func ExternalDNSWebhookProvider(url string, client *http.Client) services.DNSProvider {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &webhookProvider{
+		url:    strings.TrimSuffix(url, "/"),
+		client: client,
+	}
+}
+
+func (p *webhookProvider) EnsureRecord(ctx context.Context, record services.DNSRecord) error {
+	desired := &webhookEndpoint{
+		DNSName:    normalizeHostname(record.Hostname),
+		Targets:    []string{record.Target},
+		RecordType: recordType(record.Target),
+		RecordTTL:  record.TTL,
+	}
+
+	endpoints, err := p.getRecords(ctx)
+	if err != nil {
+		return err
+	}
+
+	changes := &webhookChanges{}
+	for _, e := range endpoints {
+		if normalizeHostname(e.DNSName) != desired.DNSName || !isAddressRecordType(e.RecordType) {
+			continue
+		}
+		if e.RecordType != desired.RecordType {
+			// The record type can't be changed with an update, e.g. when moving from an IP to a hostname target.
+			changes.Delete = append(changes.Delete, e)
+			continue
+		}
+		if slices.Equal(e.Targets, desired.Targets) && e.RecordTTL == desired.RecordTTL {
+			return nil
+		}
+		changes.UpdateOld = append(changes.UpdateOld, e)
+		changes.UpdateNew = append(changes.UpdateNew, desired)
+	}
+
+	if len(changes.UpdateNew) == 0 {
+		changes.Create = append(changes.Create, desired)
+	}
+	return p.applyChanges(ctx, changes)
+}
+
+func (p *webhookProvider) DeleteRecord(ctx context.Context, hostname string) error {
+	endpoints, err := p.getRecords(ctx)
+	if err != nil {
+		return err
+	}
+
+	changes := &webhookChanges{}
+	for _, e := range endpoints {
+		if normalizeHostname(e.DNSName) == normalizeHostname(hostname) && isAddressRecordType(e.RecordType) {
+			changes.Delete = append(changes.Delete, e)
+		}
+	}
+	if len(changes.Delete) == 0 {
+		return nil
+	}
+	return p.applyChanges(ctx, changes)
+}
+
+func (p *webhookProvider) getRecords(ctx context.Context) ([]*webhookEndpoint, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url+"/records", http.NoBody)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request to get DNS records")
+	}
+	req.Header.Set("Accept", webhookMediaType)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get DNS records")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to get DNS records: %s", responseError(resp))
+	}
+
+	endpoints := []*webhookEndpoint{}
+	if err := json.NewDecoder(resp.Body).Decode(&endpoints); err != nil {
+		return nil, errors.Wrap(err, "failed to decode DNS records")
+	}
+	return endpoints, nil
+}
+
+func (p *webhookProvider) applyChanges(ctx context.Context, changes *webhookChanges) error {
+	body, err := json.Marshal(changes)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode DNS record changes")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url+"/records", bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to create request to apply DNS record changes")
+	}
+	req.Header.Set("Content-Type", webhookMediaType)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to apply DNS record changes")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return errors.Errorf("failed to apply DNS record changes: %s", responseError(resp))
+	}
+	return nil
+}
+
+func responseError(resp *http.Response) string {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if msg := strings.TrimSpace(string(body)); msg != "" {
+		return fmt.Sprintf("%s: %s", resp.Status, msg)
+	}
+	return resp.Status
+}
+
+// recordType returns the type of the DNS record pointing to the given target.
+func recordType(target string) string {
+	ip := net.ParseIP(target)
+	switch {
+	case ip == nil:
+		return recordTypeCNAME
+	case ip.To4() != nil:
+		return recordTypeA
+	default:
+		return recordTypeAAAA
+	}
+}
+
+// isAddressRecordType returns true for the types of DNS records which can be used for a control plane endpoint.
+func isAddressRecordType(recordType string) bool {
+	return recordType == recordTypeA || recordType == recordTypeAAAA || recordType == recordTypeCNAME
+}
+
+func normalizeHostname(hostname string) string {
+	return strings.ToLower(strings.TrimSuffix(hostname, "."))
+}
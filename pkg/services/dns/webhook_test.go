@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services"
+)
+
+// fakeWebhook is a minimal external-dns webhook provider storing records in memory.
+type fakeWebhook struct {
+	lock    sync.Mutex
+	records []*webhookEndpoint
+	changes []webhookChanges
+}
+
+func (f *fakeWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if r.URL.Path != "/records" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", webhookMediaType)
+		_ = json.NewEncoder(w).Encode(f.records)
+	case http.MethodPost:
+		changes := webhookChanges{}
+		if err := json.NewDecoder(r.Body).Decode(&changes); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.changes = append(f.changes, changes)
+
+		remove := slices.Concat(changes.UpdateOld, changes.Delete)
+		records := []*webhookEndpoint{}
+		for _, e := range f.records {
+			removed := false
+			for _, d := range remove {
+				if e.DNSName == d.DNSName && e.RecordType == d.RecordType {
+					removed = true
+				}
+			}
+			if !removed {
+				records = append(records, e)
+			}
+		}
+		f.records = append(append(records, changes.Create...), changes.UpdateNew...)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestExternalDNSWebhookProvider(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("creates, updates and deletes a record", func(t *testing.T) {
+		g := NewWithT(t)
+
+		webhook := &fakeWebhook{
+			records: []*webhookEndpoint{{DNSName: "other.example.com", Targets: []string{"10.0.0.1"}, RecordType: recordTypeA}},
+		}
+		server := httptest.NewServer(webhook)
+		defer server.Close()
+
+		provider := ExternalDNSWebhookProvider(server.URL, server.Client())
+
+		// Create.
+		g.Expect(provider.EnsureRecord(ctx, services.DNSRecord{Hostname: "cp.example.com", Target: "10.0.0.2", TTL: 60})).To(Succeed())
+		g.Expect(webhook.changes).To(HaveLen(1))
+		g.Expect(webhook.changes[0].Create).To(ConsistOf(&webhookEndpoint{DNSName: "cp.example.com", Targets: []string{"10.0.0.2"}, RecordType: recordTypeA, RecordTTL: 60}))
+
+		// No-op if the record is up to date.
+		g.Expect(provider.EnsureRecord(ctx, services.DNSRecord{Hostname: "cp.example.com.", Target: "10.0.0.2", TTL: 60})).To(Succeed())
+		g.Expect(webhook.changes).To(HaveLen(1))
+
+		// Update if the target changed.
+		g.Expect(provider.EnsureRecord(ctx, services.DNSRecord{Hostname: "cp.example.com", Target: "10.0.0.3", TTL: 60})).To(Succeed())
+		g.Expect(webhook.changes).To(HaveLen(2))
+		g.Expect(webhook.changes[1].UpdateOld).To(ConsistOf(&webhookEndpoint{DNSName: "cp.example.com", Targets: []string{"10.0.0.2"}, RecordType: recordTypeA, RecordTTL: 60}))
+		g.Expect(webhook.changes[1].UpdateNew).To(ConsistOf(&webhookEndpoint{DNSName: "cp.example.com", Targets: []string{"10.0.0.3"}, RecordType: recordTypeA, RecordTTL: 60}))
+
+		// Delete and create if the record type changed.
+		g.Expect(provider.EnsureRecord(ctx, services.DNSRecord{Hostname: "cp.example.com", Target: "lb.example.com", TTL: 60})).To(Succeed())
+		g.Expect(webhook.changes).To(HaveLen(3))
+		g.Expect(webhook.changes[2].Delete).To(HaveLen(1))
+		g.Expect(webhook.changes[2].Create).To(ConsistOf(&webhookEndpoint{DNSName: "cp.example.com", Targets: []string{"lb.example.com"}, RecordType: recordTypeCNAME, RecordTTL: 60}))
+
+		// Delete.
+		g.Expect(provider.DeleteRecord(ctx, "cp.example.com")).To(Succeed())
+		g.Expect(webhook.changes).To(HaveLen(4))
+		g.Expect(webhook.records).To(ConsistOf(&webhookEndpoint{DNSName: "other.example.com", Targets: []string{"10.0.0.1"}, RecordType: recordTypeA}))
+
+		// No-op if the record does not exist.
+		g.Expect(provider.DeleteRecord(ctx, "cp.example.com")).To(Succeed())
+		g.Expect(webhook.changes).To(HaveLen(4))
+	})
+
+	t.Run("returns an error if the webhook fails", func(t *testing.T) {
+		g := NewWithT(t)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "boom", http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		provider := ExternalDNSWebhookProvider(server.URL, server.Client())
+
+		err := provider.EnsureRecord(ctx, services.DNSRecord{Hostname: "cp.example.com", Target: "10.0.0.2"})
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("boom"))
+	})
+}
+
+func Test_recordType(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(recordType("10.0.0.1")).To(Equal(recordTypeA))
+	g.Expect(recordType("fd00::1")).To(Equal(recordTypeAAAA))
+	g.Expect(recordType("lb.example.com")).To(Equal(recordTypeCNAME))
+}
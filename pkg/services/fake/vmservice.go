@@ -40,3 +40,8 @@ func (v *VMService) DestroyVM(_ context.Context, vmCtx *capvcontext.VMContext) (
 	args := v.Called(vmCtx)
 	return args.Get(0).(reconcile.Result), args.Get(1).(infrav1.VirtualMachine), args.Error(2)
 }
+
+func (v *VMService) VMExists(_ context.Context, vmCtx *capvcontext.VMContext) (bool, error) {
+	args := v.Called(vmCtx)
+	return args.Bool(0), args.Error(1)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/alarm"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/session"
+)
+
+// TriggeredAlarm is an alarm which is triggered on a vSphere inventory object.
+type TriggeredAlarm struct {
+	// Name is the name of the alarm.
+	Name string
+
+	// Entity is the name of the inventory object the alarm is triggered on.
+	Entity string
+}
+
+// GetAlarmScope returns the inventory objects whose alarms affect the VM of the given VMContext,
+// i.e. its host, resource pool, folder and datastores.
+func GetAlarmScope(ctx context.Context, vmCtx *capvcontext.VMContext) ([]types.ManagedObjectReference, error) {
+	vmRef, err := findVM(ctx, vmCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	var vm mo.VirtualMachine
+	if err := vmCtx.Session.RetrieveOne(ctx, vmRef, []string{"runtime.host", "resourcePool", "parent", "datastore"}, &vm); err != nil {
+		return nil, errors.Wrapf(err, "failed to get properties of VM %s", vmRef.Value)
+	}
+
+	scope := []types.ManagedObjectReference{}
+	for _, ref := range []*types.ManagedObjectReference{vm.Runtime.Host, vm.ResourcePool, vm.Parent} {
+		if ref != nil {
+			scope = append(scope, *ref)
+		}
+	}
+	return append(scope, vm.Datastore...), nil
+}
+
+// GetCriticalAlarms returns the critical alarms which are triggered on the given inventory objects
+// or their descendants and have not been acknowledged, sorted by inventory object and alarm name.
+func GetCriticalAlarms(ctx context.Context, s *session.Session, entities []types.ManagedObjectReference) ([]TriggeredAlarm, error) {
+	manager, err := alarm.GetManager(s.Client.Client)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get alarm manager")
+	}
+
+	// The same alarm is reported by all ancestors of the inventory object it is triggered on.
+	states := map[string]alarm.StateInfo{}
+	for _, entity := range entities {
+		infos, err := manager.GetStateInfo(ctx, entity, alarm.StateInfoOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get triggered alarms of %s", entity)
+		}
+		for _, info := range infos {
+			if info.OverallStatus != types.ManagedEntityStatusRed || (info.Acknowledged != nil && *info.Acknowledged) {
+				continue
+			}
+			states[info.Key] = info
+		}
+	}
+	if len(states) == 0 {
+		return nil, nil
+	}
+
+	refs := []types.ManagedObjectReference{}
+	seen := map[types.ManagedObjectReference]bool{}
+	for _, state := range states {
+		if !seen[state.Entity] {
+			seen[state.Entity] = true
+			refs = append(refs, state.Entity)
+		}
+	}
+	var objs []mo.ManagedEntity
+	if err := s.Retrieve(ctx, refs, []string{"name"}, &objs); err != nil {
+		return nil, errors.Wrap(err, "failed to get names of inventory objects with triggered alarms")
+	}
+	names := map[types.ManagedObjectReference]string{}
+	for _, obj := range objs {
+		names[obj.Self] = obj.Name
+	}
+
+	alarms := make([]TriggeredAlarm, 0, len(states))
+	for _, state := range states {
+		triggered := TriggeredAlarm{Name: state.Alarm.Value, Entity: names[state.Entity]}
+		if state.Info != nil {
+			triggered.Name = state.Info.Name
+		}
+		if triggered.Entity == "" {
+			triggered.Entity = state.Entity.Value
+		}
+		alarms = append(alarms, triggered)
+	}
+	sort.Slice(alarms, func(i, j int) bool {
+		if alarms[i].Entity != alarms[j].Entity {
+			return alarms[i].Entity < alarms[j].Entity
+		}
+		return alarms[i].Name < alarms[j].Name
+	})
+	return alarms, nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/alarm"
+	"github.com/vmware/govmomi/event"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+)
+
+func TestGetCriticalAlarms(t *testing.T) {
+	g := NewWithT(t)
+	model := simulator.VPX()
+	model.Host = 0
+	model.ClusterHost = 2
+	g.Expect(model.Create()).To(Succeed())
+
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		authSession, err := getAuthSession(ctx, model.Service.Listen.Host)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		finder := find.NewFinder(c)
+		vm, err := finder.VirtualMachine(ctx, "DC0_C0_RP0_VM0")
+		g.Expect(err).ToNot(HaveOccurred())
+		var moVM mo.VirtualMachine
+		g.Expect(vm.Properties(ctx, vm.Reference(), []string{"config.uuid", "runtime.host", "resourcePool", "parent", "datastore"}, &moVM)).To(Succeed())
+
+		vmCtx := &capvcontext.VMContext{
+			Session: authSession,
+			VSphereVM: &infrav1.VSphereVM{
+				Spec: infrav1.VSphereVMSpec{BiosUUID: moVM.Config.Uuid},
+			},
+		}
+
+		scope, err := GetAlarmScope(ctx, vmCtx)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(scope).To(ConsistOf(append([]types.ManagedObjectReference{*moVM.Runtime.Host, *moVM.ResourcePool, *moVM.Parent}, moVM.Datastore...)))
+
+		alarms, err := GetCriticalAlarms(ctx, authSession, scope)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(alarms).To(BeEmpty())
+
+		hosts, err := finder.HostSystemList(ctx, "/DC0/host/DC0_C0/*")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(hosts).To(HaveLen(2))
+		otherHost := hosts[0].Reference()
+		if otherHost == *moVM.Runtime.Host {
+			otherHost = hosts[1].Reference()
+		}
+
+		manager, err := alarm.GetManager(c)
+		g.Expect(err).ToNot(HaveOccurred())
+		events := event.NewManager(c)
+		trigger := func(name, eventTypeID string, status types.ManagedEntityStatus, entity types.ManagedObjectReference) types.ManagedObjectReference {
+			ref, err := manager.CreateAlarm(ctx, entity, &types.AlarmSpec{
+				Name:    name,
+				Enabled: true,
+				Expression: &types.OrAlarmExpression{
+					Expression: []types.BaseAlarmExpression{
+						&types.EventAlarmExpression{EventTypeId: eventTypeID, ObjectType: entity.Type, Status: status},
+					},
+				},
+			})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(events.PostEvent(ctx, &types.EventEx{
+				EventTypeId: eventTypeID,
+				ObjectType:  entity.Type,
+				ObjectId:    entity.Value,
+			})).To(Succeed())
+			return *ref
+		}
+
+		trigger("Datastore usage on disk", "test.datastore.usage", types.ManagedEntityStatusRed, moVM.Datastore[0])
+		trigger("Host memory usage", "test.host.memory", types.ManagedEntityStatusYellow, *moVM.Runtime.Host)
+		trigger("Host connection and power state", "test.host.connection", types.ManagedEntityStatusRed, otherHost)
+		acknowledged := trigger("VM error", "test.vm.error", types.ManagedEntityStatusRed, vm.Reference())
+		g.Expect(manager.AcknowledgeAlarm(ctx, acknowledged, vm)).To(Succeed())
+
+		// Only unacknowledged critical alarms in the scope of the VM are returned.
+		alarms, err = GetCriticalAlarms(ctx, authSession, scope)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(alarms).To(Equal([]TriggeredAlarm{{Name: "Datastore usage on disk", Entity: "LocalDS_0"}}))
+		return nil
+	}, model)
+}
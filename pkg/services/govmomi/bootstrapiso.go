@@ -0,0 +1,194 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"bytes"
+	"context"
+	"path"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/fault"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/govmomi/nocloud"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/tracing"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/util"
+)
+
+// reconcileBootstrapISO attaches a NoCloud ISO image with the bootstrap data and the metadata to a VM
+// whose bootstrap data is delivered by ISO. The image is uploaded next to the files of the VM and
+// attached as a CD-ROM, which is connected when the VM is powered on. Once the image is attached,
+// it is not updated anymore, as cloud-init only reads it on the first boot.
+func (vms *VMService) reconcileBootstrapISO(ctx context.Context, virtualMachineCtx *virtualMachineContext) (bool, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	devices, isoPath, err := getBootstrapISOPath(ctx, virtualMachineCtx)
+	if err != nil {
+		return false, err
+	}
+	if bootstrapISOCdrom(devices, isoPath) != nil {
+		return true, nil
+	}
+
+	bootstrapData, _, err := vms.getCustomizedBootstrapData(ctx, &virtualMachineCtx.VMContext)
+	if err != nil {
+		return false, err
+	}
+	metadata, err := util.GetMachineMetadata(virtualMachineCtx.VSphereVM.Name, *virtualMachineCtx.VSphereVM, virtualMachineCtx.IPAMState, virtualMachineCtx.State.Network...)
+	if err != nil {
+		return false, err
+	}
+	image, err := nocloud.ISO(bootstrapData, metadata)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to create bootstrap ISO for vm %s", virtualMachineCtx)
+	}
+
+	if skipForDryRun(ctx, virtualMachineCtx, "bootstrap ISO attach", "path", isoPath.String()) {
+		return true, nil
+	}
+
+	log.Info("Uploading bootstrap ISO", "path", isoPath.String())
+	datastore, err := virtualMachineCtx.Session.Finder.Datastore(ctx, isoPath.Datastore)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to find datastore %s of vm %s", isoPath.Datastore, virtualMachineCtx)
+	}
+	upload := soap.DefaultUpload
+	upload.ContentLength = int64(len(image))
+	if err := datastore.Upload(ctx, bytes.NewReader(image), isoPath.Path, &upload); err != nil {
+		return false, errors.Wrapf(err, "failed to upload bootstrap ISO to %s", isoPath)
+	}
+
+	cdrom, operation, err := newBootstrapISOCdrom(devices, isoPath)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to attach bootstrap ISO to vm %s", virtualMachineCtx)
+	}
+
+	log.Info("Attaching bootstrap ISO", "path", isoPath.String())
+	task, err := virtualMachineCtx.Obj.Reconfigure(ctx, types.VirtualMachineConfigSpec{
+		DeviceChange: []types.BaseVirtualDeviceConfigSpec{
+			&types.VirtualDeviceConfigSpec{
+				Operation: operation,
+				Device:    cdrom,
+			},
+		},
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to attach bootstrap ISO to vm %s", virtualMachineCtx)
+	}
+	tracing.SetTaskDescription(ctx, task)
+	virtualMachineCtx.VSphereVM.Status.TaskRef = task.Reference().Value
+	log.Info("Wait for bootstrap ISO to be attached")
+	return false, nil
+}
+
+// deleteBootstrapISO deletes the NoCloud ISO image of a VM whose bootstrap data is delivered by ISO.
+// The VM has to be powered off.
+func (vms *VMService) deleteBootstrapISO(ctx context.Context, virtualMachineCtx *virtualMachineContext) error {
+	if virtualMachineCtx.VSphereVM.Spec.BootstrapDataDelivery != infrav1.BootstrapDataDeliveryNoCloudISO {
+		return nil
+	}
+
+	_, isoPath, err := getBootstrapISOPath(ctx, virtualMachineCtx)
+	if err != nil {
+		return err
+	}
+
+	datacenter, err := virtualMachineCtx.Session.Finder.DatacenterOrDefault(ctx, virtualMachineCtx.VSphereVM.Spec.Datacenter)
+	if err != nil {
+		return errors.Wrapf(err, "failed to find datacenter of vm %s", virtualMachineCtx)
+	}
+
+	ctrl.LoggerFrom(ctx).Info("Deleting bootstrap ISO", "path", isoPath.String())
+	fileManager := object.NewFileManager(virtualMachineCtx.Session.Client.Client)
+	task, err := fileManager.DeleteDatastoreFile(ctx, isoPath.String(), datacenter)
+	if err == nil {
+		err = task.Wait(ctx)
+	}
+	if err != nil && !fault.Is(err, &types.FileNotFound{}) {
+		return errors.Wrapf(err, "failed to delete bootstrap ISO %s", isoPath)
+	}
+	return nil
+}
+
+// getBootstrapISOPath returns the devices of the VM and the datastore path of its NoCloud ISO image,
+// which is stored in the directory of the VM.
+func getBootstrapISOPath(ctx context.Context, virtualMachineCtx *virtualMachineContext) (object.VirtualDeviceList, object.DatastorePath, error) {
+	var vm mo.VirtualMachine
+	if err := getVMProperties(ctx, virtualMachineCtx, []string{"config.hardware", "config.files"}, &vm); err != nil {
+		return nil, object.DatastorePath{}, errors.Wrapf(err, "failed to get configuration of vm %s", virtualMachineCtx)
+	}
+	if vm.Config == nil {
+		return nil, object.DatastorePath{}, errors.Errorf("failed to get configuration of vm %s", virtualMachineCtx)
+	}
+
+	var vmxPath object.DatastorePath
+	if !vmxPath.FromString(vm.Config.Files.VmPathName) {
+		return nil, object.DatastorePath{}, errors.Errorf("failed to parse path %q of vm %s", vm.Config.Files.VmPathName, virtualMachineCtx)
+	}
+	isoPath := object.DatastorePath{
+		Datastore: vmxPath.Datastore,
+		Path:      path.Join(path.Dir(vmxPath.Path), virtualMachineCtx.VSphereVM.Name+"-"+nocloud.VolumeID+".iso"),
+	}
+	return object.VirtualDeviceList(vm.Config.Hardware.Device), isoPath, nil
+}
+
+// bootstrapISOCdrom returns the CD-ROM the NoCloud ISO image is attached to, if any.
+func bootstrapISOCdrom(devices object.VirtualDeviceList, isoPath object.DatastorePath) *types.VirtualCdrom {
+	for _, device := range devices.SelectByType((*types.VirtualCdrom)(nil)) {
+		cdrom := device.(*types.VirtualCdrom)
+		if backing, ok := cdrom.Backing.(*types.VirtualCdromIsoBackingInfo); ok && backing.FileName == isoPath.String() {
+			return cdrom
+		}
+	}
+	return nil
+}
+
+// newBootstrapISOCdrom returns a CD-ROM with the NoCloud ISO image inserted, which is connected
+// when the VM is powered on, and the operation to apply it. An existing CD-ROM of the VM is reused,
+// otherwise a CD-ROM is added to an IDE or SATA controller.
+func newBootstrapISOCdrom(devices object.VirtualDeviceList, isoPath object.DatastorePath) (*types.VirtualCdrom, types.VirtualDeviceConfigSpecOperation, error) {
+	operation := types.VirtualDeviceConfigSpecOperationEdit
+	var cdrom *types.VirtualCdrom
+	if existing := devices.SelectByType((*types.VirtualCdrom)(nil)); len(existing) > 0 {
+		cdrom = existing[0].(*types.VirtualCdrom)
+	} else {
+		operation = types.VirtualDeviceConfigSpecOperationAdd
+		var controller types.BaseVirtualController
+		if ide, err := devices.FindIDEController(""); err == nil {
+			controller = ide
+		} else if controller, err = devices.FindSATAController(""); err != nil {
+			return nil, "", errors.New("no IDE or SATA controller with a free unit for a CD-ROM")
+		}
+		var err error
+		if cdrom, err = devices.CreateCdrom(controller); err != nil {
+			return nil, "", err
+		}
+	}
+
+	cdrom = devices.InsertIso(cdrom, isoPath.String())
+	cdrom.Connectable = &types.VirtualDeviceConnectInfo{
+		StartConnected:    true,
+		AllowGuestControl: true,
+	}
+	return cdrom, operation, nil
+}
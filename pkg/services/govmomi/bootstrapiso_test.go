@@ -0,0 +1,187 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func Test_reconcileBootstrapISO(t *testing.T) {
+	g := NewWithT(t)
+	model := simulator.VPX()
+	g.Expect(model.Create()).To(Succeed())
+
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		authSession, err := getAuthSession(ctx, model.Service.Listen.Host)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		finder := find.NewFinder(c)
+		vm, err := finder.VirtualMachine(ctx, "DC0_C0_RP0_VM0")
+		g.Expect(err).ToNot(HaveOccurred())
+		datastore, err := finder.Datastore(ctx, "LocalDS_0")
+		g.Expect(err).ToNot(HaveOccurred())
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "bootstrap"},
+			Data: map[string][]byte{
+				"value": []byte("#cloud-config\nruncmd:\n- echo hello\n"),
+			},
+		}
+
+		vmCtx := emptyVirtualMachineContext()
+		vmCtx.Client = fake.NewClientBuilder().WithObjects(secret).Build()
+		vmCtx.Session = authSession
+		vmCtx.Obj = vm
+		vmCtx.Ref = vm.Reference()
+		vmCtx.State = &infrav1.VirtualMachine{}
+		vmCtx.VSphereVM = &infrav1.VSphereVM{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "vm-0"},
+			Spec: infrav1.VSphereVMSpec{
+				BootstrapRef: &corev1.ObjectReference{Namespace: "default", Name: "bootstrap"},
+				VirtualMachineCloneSpec: infrav1.VirtualMachineCloneSpec{
+					BootstrapDataDelivery: infrav1.BootstrapDataDeliveryNoCloudISO,
+					Network: infrav1.NetworkSpec{
+						Devices: []infrav1.NetworkDeviceSpec{{NetworkName: "VM Network", DHCP4: true}},
+					},
+				},
+			},
+		}
+
+		vms := &VMService{}
+
+		_, isoPath, err := getBootstrapISOPath(ctx, vmCtx)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(isoPath.String()).To(Equal("[LocalDS_0] DC0_C0_RP0_VM0/vm-0-cidata.iso"))
+
+		t.Run("with Ignition bootstrap data", func(*testing.T) {
+			secret.Data["format"] = []byte(bootstrapv1.Ignition)
+			g.Expect(vmCtx.Client.Update(ctx, secret)).To(Succeed())
+			defer func() {
+				delete(secret.Data, "format")
+				g.Expect(vmCtx.Client.Update(ctx, secret)).To(Succeed())
+			}()
+
+			ok, err := vms.reconcileBootstrapISO(ctx, vmCtx)
+			g.Expect(err).To(MatchError(ContainSubstring("bootstrap data format ignition is not supported")))
+			g.Expect(ok).To(BeFalse())
+		})
+
+		t.Run("in dry-run mode", func(*testing.T) {
+			vmCtx.DryRun = true
+			defer func() {
+				vmCtx.DryRun = false
+				vmCtx.DryRunOperations = nil
+			}()
+
+			ok, err := vms.reconcileBootstrapISO(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeTrue())
+			g.Expect(vmCtx.VSphereVM.Status.TaskRef).To(BeEmpty())
+			g.Expect(vmCtx.DryRunOperations).To(ConsistOf("bootstrap ISO attach"))
+			_, err = datastore.Stat(ctx, isoPath.Path)
+			g.Expect(err).To(HaveOccurred())
+		})
+
+		t.Run("attaches the bootstrap ISO", func(*testing.T) {
+			ok, err := vms.reconcileBootstrapISO(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeFalse())
+			g.Expect(vmCtx.VSphereVM.Status.TaskRef).ToNot(BeEmpty())
+
+			task := object.NewTask(c, types.ManagedObjectReference{Type: morefTypeTask, Value: vmCtx.VSphereVM.Status.TaskRef})
+			g.Expect(task.Wait(ctx)).To(Succeed())
+			vmCtx.VSphereVM.Status.TaskRef = ""
+
+			_, err = datastore.Stat(ctx, isoPath.Path)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			var obj mo.VirtualMachine
+			g.Expect(vm.Properties(ctx, vm.Reference(), []string{"config.hardware"}, &obj)).To(Succeed())
+			cdrom := bootstrapISOCdrom(object.VirtualDeviceList(obj.Config.Hardware.Device), isoPath)
+			g.Expect(cdrom).ToNot(BeNil())
+			g.Expect(cdrom.Connectable.StartConnected).To(BeTrue())
+
+			// The attached ISO is not changed anymore.
+			ok, err = vms.reconcileBootstrapISO(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeTrue())
+			g.Expect(vmCtx.VSphereVM.Status.TaskRef).To(BeEmpty())
+		})
+
+		t.Run("deletes the bootstrap ISO", func(*testing.T) {
+			g.Expect(vms.deleteBootstrapISO(ctx, vmCtx)).To(Succeed())
+			_, err := datastore.Stat(ctx, isoPath.Path)
+			g.Expect(err).To(BeAssignableToTypeOf(object.DatastoreNoSuchFileError{}))
+
+			// A missing ISO is ignored.
+			g.Expect(vms.deleteBootstrapISO(ctx, vmCtx)).To(Succeed())
+		})
+
+		return nil
+	}, model)
+}
+
+func Test_newBootstrapISOCdrom(t *testing.T) {
+	isoPath := object.DatastorePath{Datastore: "ds", Path: "vm/vm-cidata.iso"}
+
+	t.Run("reuses an existing CD-ROM", func(t *testing.T) {
+		g := NewWithT(t)
+		existing := &types.VirtualCdrom{VirtualDevice: types.VirtualDevice{Key: 3000}}
+		devices := object.VirtualDeviceList{&types.VirtualIDEController{VirtualController: types.VirtualController{VirtualDevice: types.VirtualDevice{Key: 200}}}, existing}
+
+		cdrom, operation, err := newBootstrapISOCdrom(devices, isoPath)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(operation).To(Equal(types.VirtualDeviceConfigSpecOperationEdit))
+		g.Expect(cdrom.Key).To(Equal(int32(3000)))
+		g.Expect(cdrom.Backing).To(Equal(&types.VirtualCdromIsoBackingInfo{
+			VirtualDeviceFileBackingInfo: types.VirtualDeviceFileBackingInfo{FileName: "[ds] vm/vm-cidata.iso"},
+		}))
+	})
+
+	t.Run("adds a CD-ROM to a SATA controller", func(t *testing.T) {
+		g := NewWithT(t)
+		devices := object.VirtualDeviceList{&types.VirtualAHCIController{VirtualSATAController: types.VirtualSATAController{VirtualController: types.VirtualController{VirtualDevice: types.VirtualDevice{Key: 15000}}}}}
+
+		cdrom, operation, err := newBootstrapISOCdrom(devices, isoPath)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(operation).To(Equal(types.VirtualDeviceConfigSpecOperationAdd))
+		g.Expect(cdrom.ControllerKey).To(Equal(int32(15000)))
+		g.Expect(cdrom.Connectable.StartConnected).To(BeTrue())
+	})
+
+	t.Run("without a controller", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, _, err := newBootstrapISOCdrom(object.VirtualDeviceList{}, isoPath)
+		g.Expect(err).To(HaveOccurred())
+	})
+}
@@ -0,0 +1,254 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/yaml"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+)
+
+const (
+	// CloudInitWriteFilesKey is the key of the ConfigMap referenced by CloudInitCustomizationRef
+	// holding the files which are appended to the write_files of the bootstrap data.
+	CloudInitWriteFilesKey = "writeFiles"
+
+	// CloudInitPreCommandsKey is the key of the ConfigMap referenced by CloudInitCustomizationRef
+	// holding the commands which are run before the runcmd commands of the bootstrap data.
+	CloudInitPreCommandsKey = "preCommands"
+
+	// CloudInitPostCommandsKey is the key of the ConfigMap referenced by CloudInitCustomizationRef
+	// holding the commands which are run after the runcmd commands of the bootstrap data.
+	CloudInitPostCommandsKey = "postCommands"
+)
+
+// customizeBootstrapData merges the cloud-init customization referenced by the VSphereVM
+// into the bootstrap data.
+func customizeBootstrapData(ctx context.Context, vmCtx *capvcontext.VMContext, bootstrapData []byte, format bootstrapv1.Format) ([]byte, error) {
+	ref := vmCtx.VSphereVM.Spec.CloudInitCustomizationRef
+	if ref == nil || len(bootstrapData) == 0 {
+		return bootstrapData, nil
+	}
+	if format != bootstrapv1.CloudConfig {
+		return nil, errors.Errorf("cloud-init customization is not supported for bootstrap data format %q", format)
+	}
+
+	configMap := &corev1.ConfigMap{}
+	configMapKey := apitypes.NamespacedName{
+		Namespace: vmCtx.VSphereVM.Namespace,
+		Name:      ref.Name,
+	}
+	if err := vmCtx.Client.Get(ctx, configMapKey, configMap); err != nil {
+		return nil, errors.Wrapf(err, "failed to get cloud-init customization ConfigMap for %s", vmCtx)
+	}
+
+	return mergeCloudInitCustomization(bootstrapData, configMap.Data)
+}
+
+// mergeCloudInitCustomization merges the files and commands of the customization into
+// the cloud-config. The leading comments of the cloud-config, e.g. "## template: jinja"
+// and "#cloud-config", are preserved and the keys of the result are sorted, so the same
+// input always results in the same bootstrap data.
+func mergeCloudInitCustomization(cloudConfig []byte, customization map[string]string) ([]byte, error) {
+	header, body := splitCloudConfigHeader(cloudConfig)
+
+	config := map[string]interface{}{}
+	if err := yaml.Unmarshal(body, &config); err != nil {
+		return nil, errors.Wrap(err, "failed to parse cloud-config bootstrap data")
+	}
+	if config == nil {
+		config = map[string]interface{}{}
+	}
+
+	writeFiles, err := parseCustomizationList(customization, CloudInitWriteFilesKey)
+	if err != nil {
+		return nil, err
+	}
+	preCommands, err := parseCustomizationList(customization, CloudInitPreCommandsKey)
+	if err != nil {
+		return nil, err
+	}
+	postCommands, err := parseCustomizationList(customization, CloudInitPostCommandsKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(writeFiles) > 0 {
+		existing, err := getCloudConfigList(config, "write_files")
+		if err != nil {
+			return nil, err
+		}
+		config["write_files"] = append(existing, writeFiles...)
+	}
+	if len(preCommands) > 0 || len(postCommands) > 0 {
+		existing, err := getCloudConfigList(config, "runcmd")
+		if err != nil {
+			return nil, err
+		}
+		commands := make([]interface{}, 0, len(preCommands)+len(existing)+len(postCommands))
+		commands = append(commands, preCommands...)
+		commands = append(commands, existing...)
+		commands = append(commands, postCommands...)
+		config["runcmd"] = commands
+	}
+
+	merged, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal customized cloud-config bootstrap data")
+	}
+	return append(header, merged...), nil
+}
+
+// containerdProxyDropInPath is the path of the systemd drop-in configuring the proxy of containerd.
+const containerdProxyDropInPath = "/etc/systemd/system/containerd.service.d/http-proxy.conf"
+
+// addGuestNetworkSettings adds the NTP servers and the proxy of the network spec of the
+// VSphereVM to the bootstrap data.
+func addGuestNetworkSettings(vmCtx *capvcontext.VMContext, bootstrapData []byte, format bootstrapv1.Format) ([]byte, error) {
+	network := vmCtx.VSphereVM.Spec.Network
+	if (len(network.NTPServers) == 0 && network.Proxy == nil) || len(bootstrapData) == 0 {
+		return bootstrapData, nil
+	}
+	if format != bootstrapv1.CloudConfig {
+		return nil, errors.Errorf("NTP servers and proxy are not supported for bootstrap data format %q", format)
+	}
+	return mergeGuestNetworkSettings(bootstrapData, network)
+}
+
+// mergeGuestNetworkSettings merges the NTP servers and the proxy into the cloud-config.
+// The NTP servers replace the NTP servers of the cloud-config. The proxy is configured
+// for containerd by a systemd drop-in, containerd is restarted before the runcmd commands
+// of the cloud-config so the images of the bootstrap are pulled through the proxy.
+func mergeGuestNetworkSettings(cloudConfig []byte, network infrav1.NetworkSpec) ([]byte, error) {
+	header, body := splitCloudConfigHeader(cloudConfig)
+
+	config := map[string]interface{}{}
+	if err := yaml.Unmarshal(body, &config); err != nil {
+		return nil, errors.Wrap(err, "failed to parse cloud-config bootstrap data")
+	}
+	if config == nil {
+		config = map[string]interface{}{}
+	}
+
+	if len(network.NTPServers) > 0 {
+		ntp, ok := config["ntp"].(map[string]interface{})
+		if !ok {
+			ntp = map[string]interface{}{}
+		}
+		servers := make([]interface{}, 0, len(network.NTPServers))
+		for _, server := range network.NTPServers {
+			servers = append(servers, server)
+		}
+		ntp["enabled"] = true
+		ntp["servers"] = servers
+		config["ntp"] = ntp
+	}
+
+	if proxy := network.Proxy; proxy != nil {
+		writeFiles, err := getCloudConfigList(config, "write_files")
+		if err != nil {
+			return nil, err
+		}
+		config["write_files"] = append(writeFiles, map[string]interface{}{
+			"path":        containerdProxyDropInPath,
+			"owner":       "root:root",
+			"permissions": "0644",
+			"content":     containerdProxyDropIn(proxy),
+		})
+
+		commands, err := getCloudConfigList(config, "runcmd")
+		if err != nil {
+			return nil, err
+		}
+		config["runcmd"] = append([]interface{}{"systemctl daemon-reload", "systemctl restart containerd"}, commands...)
+	}
+
+	merged, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal cloud-config bootstrap data")
+	}
+	return append(header, merged...), nil
+}
+
+// containerdProxyDropIn returns the systemd drop-in setting the proxy environment variables of containerd.
+func containerdProxyDropIn(proxy *infrav1.GuestProxySpec) string {
+	var b strings.Builder
+	b.WriteString("[Service]\n")
+	if proxy.HTTPProxy != "" {
+		fmt.Fprintf(&b, "Environment=\"HTTP_PROXY=%s\"\n", proxy.HTTPProxy)
+	}
+	if proxy.HTTPSProxy != "" {
+		fmt.Fprintf(&b, "Environment=\"HTTPS_PROXY=%s\"\n", proxy.HTTPSProxy)
+	}
+	if len(proxy.NoProxy) > 0 {
+		fmt.Fprintf(&b, "Environment=\"NO_PROXY=%s\"\n", strings.Join(proxy.NoProxy, ","))
+	}
+	return b.String()
+}
+
+// splitCloudConfigHeader splits the leading comment lines from the cloud-config.
+func splitCloudConfigHeader(cloudConfig []byte) ([]byte, []byte) {
+	header := []byte{}
+	body := cloudConfig
+	for len(body) > 0 && bytes.HasPrefix(body, []byte("#")) {
+		line, rest, found := bytes.Cut(body, []byte("\n"))
+		header = append(header, line...)
+		header = append(header, '\n')
+		if !found {
+			rest = nil
+		}
+		body = rest
+	}
+	if len(header) == 0 {
+		header = []byte("#cloud-config\n")
+	}
+	return header, body
+}
+
+func parseCustomizationList(customization map[string]string, key string) ([]interface{}, error) {
+	value, ok := customization[key]
+	if !ok || strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	list := []interface{}{}
+	if err := yaml.Unmarshal([]byte(value), &list); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %q of cloud-init customization: expected a YAML list", key)
+	}
+	return list, nil
+}
+
+func getCloudConfigList(config map[string]interface{}, key string) ([]interface{}, error) {
+	value, ok := config[key]
+	if !ok || value == nil {
+		return nil, nil
+	}
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, errors.Errorf("failed to merge cloud-init customization: %q of the bootstrap data is not a list", key)
+	}
+	return list, nil
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+const kubeadmCloudConfig = `## template: jinja
+#cloud-config
+
+write_files:
+-   path: /etc/kubernetes/pki/ca.crt
+    owner: root:root
+    permissions: '0640'
+    content: |
+      ca
+runcmd:
+  - 'kubeadm init --config /run/kubeadm/kubeadm.yaml'
+users:
+  - name: capv
+`
+
+func Test_mergeCloudInitCustomization(t *testing.T) {
+	tests := []struct {
+		name          string
+		cloudConfig   string
+		customization map[string]string
+		expected      string
+		hasError      bool
+	}{
+		{
+			name:        "merges files and commands",
+			cloudConfig: kubeadmCloudConfig,
+			customization: map[string]string{
+				CloudInitWriteFilesKey: `
+- path: /etc/udev/rules.d/70-persistent-net.rules
+  content: |
+    rule
+`,
+				CloudInitPreCommandsKey:  `["udevadm trigger"]`,
+				CloudInitPostCommandsKey: `["systemctl restart multipathd"]`,
+			},
+			expected: `## template: jinja
+#cloud-config
+runcmd:
+- udevadm trigger
+- kubeadm init --config /run/kubeadm/kubeadm.yaml
+- systemctl restart multipathd
+users:
+- name: capv
+write_files:
+- content: |
+    ca
+  owner: root:root
+  path: /etc/kubernetes/pki/ca.crt
+  permissions: "0640"
+- content: |
+    rule
+  path: /etc/udev/rules.d/70-persistent-net.rules
+`,
+		},
+		{
+			name:        "adds the header and lists missing in the bootstrap data",
+			cloudConfig: "users:\n- name: capv\n",
+			customization: map[string]string{
+				CloudInitPostCommandsKey: `["echo done"]`,
+			},
+			expected: `#cloud-config
+runcmd:
+- echo done
+users:
+- name: capv
+`,
+		},
+		{
+			name:        "ignores empty keys",
+			cloudConfig: kubeadmCloudConfig,
+			customization: map[string]string{
+				CloudInitWriteFilesKey: "",
+			},
+			expected: `## template: jinja
+#cloud-config
+runcmd:
+- kubeadm init --config /run/kubeadm/kubeadm.yaml
+users:
+- name: capv
+write_files:
+- content: |
+    ca
+  owner: root:root
+  path: /etc/kubernetes/pki/ca.crt
+  permissions: "0640"
+`,
+		},
+		{
+			name:        "fails if a key is not a list",
+			cloudConfig: kubeadmCloudConfig,
+			customization: map[string]string{
+				CloudInitPreCommandsKey: "udevadm trigger",
+			},
+			hasError: true,
+		},
+		{
+			name:        "fails if the bootstrap data is not a list",
+			cloudConfig: "#cloud-config\nruncmd: kubeadm init\n",
+			customization: map[string]string{
+				CloudInitPreCommandsKey: `["udevadm trigger"]`,
+			},
+			hasError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			merged, err := mergeCloudInitCustomization([]byte(tt.cloudConfig), tt.customization)
+			if tt.hasError {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(merged)).To(Equal(tt.expected))
+		})
+	}
+}
+
+func Test_mergeGuestNetworkSettings(t *testing.T) {
+	tests := []struct {
+		name        string
+		cloudConfig string
+		network     infrav1.NetworkSpec
+		expected    string
+		hasError    bool
+	}{
+		{
+			name:        "adds the NTP servers and the proxy",
+			cloudConfig: kubeadmCloudConfig,
+			network: infrav1.NetworkSpec{
+				NTPServers: []string{"ntp1.example.com", "ntp2.example.com"},
+				Proxy: &infrav1.GuestProxySpec{
+					HTTPProxy:  "http://proxy.example.com:3128",
+					HTTPSProxy: "http://proxy.example.com:3128",
+					NoProxy:    []string{".example.com", "10.0.0.0/8"},
+				},
+			},
+			expected: `## template: jinja
+#cloud-config
+ntp:
+  enabled: true
+  servers:
+  - ntp1.example.com
+  - ntp2.example.com
+runcmd:
+- systemctl daemon-reload
+- systemctl restart containerd
+- kubeadm init --config /run/kubeadm/kubeadm.yaml
+users:
+- name: capv
+write_files:
+- content: |
+    ca
+  owner: root:root
+  path: /etc/kubernetes/pki/ca.crt
+  permissions: "0640"
+- content: |
+    [Service]
+    Environment="HTTP_PROXY=http://proxy.example.com:3128"
+    Environment="HTTPS_PROXY=http://proxy.example.com:3128"
+    Environment="NO_PROXY=.example.com,10.0.0.0/8"
+  owner: root:root
+  path: /etc/systemd/system/containerd.service.d/http-proxy.conf
+  permissions: "0644"
+`,
+		},
+		{
+			name:        "replaces the NTP servers of the bootstrap data",
+			cloudConfig: "#cloud-config\nntp:\n  enabled: false\n  ntp_client: chrony\n  servers:\n  - pool.ntp.org\n",
+			network: infrav1.NetworkSpec{
+				NTPServers: []string{"ntp.example.com"},
+			},
+			expected: `#cloud-config
+ntp:
+  enabled: true
+  ntp_client: chrony
+  servers:
+  - ntp.example.com
+`,
+		},
+		{
+			name:        "fails if runcmd of the bootstrap data is not a list",
+			cloudConfig: "#cloud-config\nruncmd: kubeadm init\n",
+			network: infrav1.NetworkSpec{
+				Proxy: &infrav1.GuestProxySpec{HTTPProxy: "http://proxy.example.com:3128"},
+			},
+			hasError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			merged, err := mergeGuestNetworkSettings([]byte(tt.cloudConfig), tt.network)
+			if tt.hasError {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(merged)).To(Equal(tt.expected))
+		})
+	}
+}
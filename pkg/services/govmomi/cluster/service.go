@@ -20,6 +20,7 @@ package cluster
 import (
 	"context"
 
+	"github.com/pkg/errors"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vim25/types"
 
@@ -50,3 +51,22 @@ func ListHostsFromGroup(ctx context.Context, ccr *object.ClusterComputeResource,
 	}
 	return refs, nil
 }
+
+// FindHostGroup returns the host group with the given name of a compute cluster.
+func FindHostGroup(ctx context.Context, computeClusterCtx computeClusterContext, clusterName, hostGroupName string) (*types.ClusterHostGroup, error) {
+	ccr, err := computeClusterCtx.GetSession().Finder.ClusterComputeResource(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterConfigInfoEx, err := ccr.Configuration(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range clusterConfigInfoEx.Group {
+		if clusterHostGroup, ok := group.(*types.ClusterHostGroup); ok && clusterHostGroup.Name == hostGroupName {
+			return clusterHostGroup, nil
+		}
+	}
+	return nil, errors.Errorf("cannot find host group %s", hostGroupName)
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// VerifyVSANFaultDomain checks whether all the hosts of a hostGroup belong to the given vSAN fault domain,
+// i.e. to the same site of a vSAN stretched cluster.
+func VerifyVSANFaultDomain(ctx context.Context, computeClusterCtx computeClusterContext, clusterName, hostGroupName, faultDomain string) error {
+	ccr, err := computeClusterCtx.GetSession().Finder.ClusterComputeResource(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+
+	refs, err := ListHostsFromGroup(ctx, ccr, hostGroupName)
+	if err != nil {
+		return errors.Wrapf(err, "unable to list hosts of host group %s", hostGroupName)
+	}
+	if len(refs) == 0 {
+		return errors.Errorf("host group %s has no hosts", hostGroupName)
+	}
+
+	hostRefs := make([]types.ManagedObjectReference, 0, len(refs))
+	for _, ref := range refs {
+		hostRefs = append(hostRefs, ref.Reference())
+	}
+	var hosts []mo.HostSystem
+	if err := property.DefaultCollector(ccr.Client()).Retrieve(ctx, hostRefs, []string{"name", "config.vsanHostConfig"}, &hosts); err != nil {
+		return errors.Wrapf(err, "unable to retrieve vSAN configuration of hosts of host group %s", hostGroupName)
+	}
+
+	for _, host := range hosts {
+		if hostFaultDomain := getVSANFaultDomain(host); hostFaultDomain != faultDomain {
+			return errors.Errorf("host %s belongs to vSAN fault domain %q instead of %q", host.Name, hostFaultDomain, faultDomain)
+		}
+	}
+	return nil
+}
+
+func getVSANFaultDomain(host mo.HostSystem) string {
+	if host.Config == nil || host.Config.VsanHostConfig == nil || host.Config.VsanHostConfig.FaultDomainInfo == nil {
+		return ""
+	}
+	return host.Config.VsanHostConfig.FaultDomainInfo.Name
+}
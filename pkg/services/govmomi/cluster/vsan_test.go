@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestVerifyVSANFaultDomain(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		g := NewWithT(t)
+		finder := find.NewFinder(c)
+		dc, err := finder.DefaultDatacenter(ctx)
+		g.Expect(err).NotTo(HaveOccurred())
+		finder.SetDatacenter(dc)
+
+		ccr, err := finder.ClusterComputeResource(ctx, "DC0_C0")
+		g.Expect(err).NotTo(HaveOccurred())
+		hosts, err := ccr.Hosts(ctx)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		// The first two hosts are in the preferred site of the stretched cluster, the other ones in the secondary site.
+		for i, host := range hosts {
+			faultDomain := "secondary"
+			if i < 2 {
+				faultDomain = "preferred"
+			}
+			simulator.Map.Get(host.Reference()).(*simulator.HostSystem).Config.VsanHostConfig = &types.VsanHostConfigInfo{
+				FaultDomainInfo: &types.VsanHostFaultDomainInfo{Name: faultDomain},
+			}
+		}
+
+		spec := &types.ClusterConfigSpecEx{
+			GroupSpec: []types.ClusterGroupSpec{
+				{
+					ArrayUpdateSpec: types.ArrayUpdateSpec{Operation: types.ArrayUpdateOperationAdd},
+					Info: &types.ClusterHostGroup{
+						ClusterGroupInfo: types.ClusterGroupInfo{Name: "preferred-hosts"},
+						Host:             []types.ManagedObjectReference{hosts[0].Reference(), hosts[1].Reference()},
+					},
+				},
+				{
+					ArrayUpdateSpec: types.ArrayUpdateSpec{Operation: types.ArrayUpdateOperationAdd},
+					Info: &types.ClusterHostGroup{
+						ClusterGroupInfo: types.ClusterGroupInfo{Name: "mixed-hosts"},
+						Host:             []types.ManagedObjectReference{hosts[1].Reference(), hosts[2].Reference()},
+					},
+				},
+				{
+					ArrayUpdateSpec: types.ArrayUpdateSpec{Operation: types.ArrayUpdateOperationAdd},
+					Info:            &types.ClusterHostGroup{ClusterGroupInfo: types.ClusterGroupInfo{Name: "empty"}},
+				},
+			},
+		}
+		task, err := ccr.Reconfigure(ctx, spec, true)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(task.Wait(ctx)).To(Succeed())
+
+		computeClusterCtx := testComputeClusterCtx{finder: finder}
+		g.Expect(VerifyVSANFaultDomain(ctx, computeClusterCtx, "DC0_C0", "preferred-hosts", "preferred")).To(Succeed())
+		g.Expect(VerifyVSANFaultDomain(ctx, computeClusterCtx, "DC0_C0", "preferred-hosts", "secondary")).To(MatchError(ContainSubstring(`instead of "secondary"`)))
+		g.Expect(VerifyVSANFaultDomain(ctx, computeClusterCtx, "DC0_C0", "mixed-hosts", "preferred")).To(HaveOccurred())
+		g.Expect(VerifyVSANFaultDomain(ctx, computeClusterCtx, "DC0_C0", "empty", "preferred")).To(MatchError(ContainSubstring("has no hosts")))
+	})
+}
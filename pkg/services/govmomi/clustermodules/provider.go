@@ -23,6 +23,7 @@ import (
 
 	"github.com/vmware/govmomi/vapi/cluster"
 	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
@@ -36,6 +37,7 @@ type Provider interface {
 
 	IsMoRefModuleMember(ctx context.Context, moduleID string, moRef types.ManagedObjectReference) (bool, error)
 	AddMoRefToModule(ctx context.Context, moduleID string, moRef types.ManagedObjectReference) error
+	AddMissingMoRefsToModule(ctx context.Context, moduleID string, moRefs []types.ManagedObjectReference) ([]types.ManagedObjectReference, error)
 	RemoveMoRefFromModule(ctx context.Context, moduleID string, moRef types.ManagedObjectReference) error
 }
 
@@ -140,6 +142,46 @@ func (cm *provider) AddMoRefToModule(ctx context.Context, moduleID string, moRef
 	return nil
 }
 
+// AddMissingMoRefsToModule adds the objects which are not members of the ClusterModule yet
+// and returns them.
+func (cm *provider) AddMissingMoRefsToModule(ctx context.Context, moduleID string, moRefs []types.ManagedObjectReference) ([]types.ManagedObjectReference, error) {
+	log := ctrl.LoggerFrom(ctx)
+	if len(moRefs) == 0 {
+		return nil, nil
+	}
+
+	moduleMembers, err := cm.manager.ListModuleMembers(ctx, moduleID)
+	if err != nil {
+		return nil, err
+	}
+	isMember := map[types.ManagedObjectReference]bool{}
+	for _, member := range moduleMembers {
+		isMember[member.Reference()] = true
+	}
+
+	missing := []types.ManagedObjectReference{}
+	for _, moRef := range moRefs {
+		if !isMember[moRef] {
+			missing = append(missing, moRef)
+		}
+	}
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	log.Info("Adding missing moRefs to the cluster module", "moRefs", missing)
+	members := make([]mo.Reference, 0, len(missing))
+	for _, moRef := range missing {
+		members = append(members, moRef)
+	}
+	if _, err := cm.manager.AddModuleMembers(ctx, moduleID, members...); err != nil {
+		return nil, err
+	}
+
+	log.Info("Added missing moRefs to the cluster module", "moRefs", missing)
+	return missing, nil
+}
+
 // RemoveMoRefFromModule removes the object from the ClusterModule.
 func (cm *provider) RemoveMoRefFromModule(ctx context.Context, moduleID string, moRef types.ManagedObjectReference) error {
 	log := ctrl.LoggerFrom(ctx)
@@ -30,6 +30,9 @@ type virtualMachineContext struct {
 	Obj       *object.VirtualMachine
 	State     *infrav1.VirtualMachine
 	IPAMState map[string]infrav1.NetworkDeviceSpec
+	// DryRunOperations are the vSphere mutating operations which have been
+	// skipped because the VSphereVM is reconciled in dry-run mode.
+	DryRunOperations []string
 }
 
 func (c *virtualMachineContext) String() string {
@@ -20,12 +20,14 @@ import (
 	"context"
 	"testing"
 
+	"github.com/vmware/govmomi/crypto"
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/simulator"
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/types"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-vsphere/internal/test/helpers/vcsim"
 	vmcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/context/fake"
@@ -193,4 +195,24 @@ func TestCreate(t *testing.T) {
 	if model.Machine+3 != model.Count().Machine {
 		t.Error("failed to clone vm")
 	}
+
+	t.Log("executing with encryption")
+	replaceFuncEncryption := func(vmContext *vmcontext.VMContext, vimClient *vim25.Client) {
+		keyManager, err := crypto.GetManagerKmip(vimClient)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := keyManager.RegisterKmsCluster(ctx, "key-provider", types.KmipClusterInfoKmsManagementTypeUnknown); err != nil {
+			t.Fatal(err)
+		}
+		if err := keyManager.MarkDefault(ctx, "key-provider"); err != nil {
+			t.Fatal(err)
+		}
+
+		vmContext.VSphereVM.Spec.Encryption = &infrav1.VirtualMachineEncryptionSpec{StoragePolicyName: "VM Encryption Policy"}
+	}
+	executeTest("encrypted", replaceFuncEncryption)
+	if model.Machine+4 != model.Count().Machine {
+		t.Error("failed to clone vm")
+	}
 }
@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// morefTypeVirtualMachine is the managed object type of custom attributes which apply to VMs.
+const morefTypeVirtualMachine = "VirtualMachine"
+
+// reconcileCustomAttributes sets the custom attributes of the VM to the values of
+// spec.customAttributes. Custom attribute definitions which don't exist yet are created.
+func (vms *VMService) reconcileCustomAttributes(ctx context.Context, virtualMachineCtx *virtualMachineContext) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	customAttributes := virtualMachineCtx.VSphereVM.Spec.CustomAttributes
+	if len(customAttributes) == 0 {
+		log.V(5).Info("No custom attributes defined. skipping custom attributes reconciliation")
+		return nil
+	}
+
+	manager, err := object.GetCustomFieldsManager(virtualMachineCtx.Session.Client.Client)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get custom fields manager to set custom attributes of VM %s", virtualMachineCtx.VSphereVM.Name)
+	}
+	fields, err := manager.Field(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to list custom attribute definitions")
+	}
+	keys := map[string]int32{}
+	for _, def := range fields {
+		if def.ManagedObjectType == "" || def.ManagedObjectType == morefTypeVirtualMachine {
+			keys[def.Name] = def.Key
+		}
+	}
+
+	var vm mo.VirtualMachine
+	if err := getVMProperties(ctx, virtualMachineCtx, []string{"customValue"}, &vm); err != nil {
+		return errors.Wrapf(err, "failed to get custom attributes of VM %s", virtualMachineCtx.VSphereVM.Name)
+	}
+	values := map[int32]string{}
+	for _, v := range vm.CustomValue {
+		if value, ok := v.(*types.CustomFieldStringValue); ok {
+			values[value.Key] = value.Value
+		}
+	}
+
+	// Sort the names to set the custom attributes in a stable order.
+	names := make([]string, 0, len(customAttributes))
+	for name := range customAttributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	outOfSync := []string{}
+	for _, name := range names {
+		key, ok := keys[name]
+		if ok {
+			if value, ok := values[key]; ok && value == customAttributes[name] {
+				continue
+			}
+		}
+		outOfSync = append(outOfSync, name)
+	}
+	if len(outOfSync) == 0 {
+		return nil
+	}
+	if skipForDryRun(ctx, virtualMachineCtx, "custom attributes update", "customAttributes", outOfSync) {
+		return nil
+	}
+
+	for _, name := range outOfSync {
+		key, ok := keys[name]
+		if !ok {
+			log.Info("Creating custom attribute definition", "customAttribute", name)
+			def, err := manager.Add(ctx, name, morefTypeVirtualMachine, nil, nil)
+			if err != nil {
+				return errors.Wrapf(err, "failed to create custom attribute definition %q", name)
+			}
+			key = def.Key
+		}
+		if err := manager.Set(ctx, virtualMachineCtx.Ref, key, customAttributes[name]); err != nil {
+			return errors.Wrapf(err, "failed to set custom attribute %q of VM %s", name, virtualMachineCtx.VSphereVM.Name)
+		}
+	}
+	log.Info("Updated custom attributes", "customAttributes", outOfSync)
+	return nil
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func Test_reconcileCustomAttributes(t *testing.T) {
+	g := NewWithT(t)
+	model := simulator.VPX()
+	g.Expect(model.Create()).To(Succeed())
+
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		authSession, err := getAuthSession(ctx, model.Service.Listen.Host)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		finder := find.NewFinder(c)
+		vm, err := finder.VirtualMachine(ctx, "DC0_C0_RP0_VM0")
+		g.Expect(err).ToNot(HaveOccurred())
+
+		manager, err := object.GetCustomFieldsManager(c)
+		g.Expect(err).ToNot(HaveOccurred())
+		// A custom attribute for hosts must not be used for VMs.
+		_, err = manager.Add(ctx, "owner", "HostSystem", nil, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		costCenter, err := manager.Add(ctx, "cost-center", "", nil, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		vmCtx := emptyVirtualMachineContext()
+		vmCtx.Session = authSession
+		vmCtx.Obj = vm
+		vmCtx.Ref = vm.Reference()
+		vmCtx.VSphereVM = &infrav1.VSphereVM{
+			Spec: infrav1.VSphereVMSpec{
+				VirtualMachineCloneSpec: infrav1.VirtualMachineCloneSpec{
+					CustomAttributes: map[string]string{
+						"owner":       "team-a",
+						"cost-center": "1234",
+					},
+				},
+			},
+		}
+
+		customAttributes := func() map[string]string {
+			fields, err := manager.Field(ctx)
+			g.Expect(err).ToNot(HaveOccurred())
+			var moVM mo.VirtualMachine
+			g.Expect(vm.Properties(ctx, vm.Reference(), []string{"customValue"}, &moVM)).To(Succeed())
+			values := map[string]string{}
+			for _, v := range moVM.CustomValue {
+				value := v.(*types.CustomFieldStringValue)
+				values[fields.ByKey(value.Key).Name] = value.Value
+			}
+			return values
+		}
+
+		vms := &VMService{}
+
+		t.Run("in dry-run mode", func(*testing.T) {
+			vmCtx.DryRun = true
+			defer func() { vmCtx.DryRun = false }()
+
+			g.Expect(vms.reconcileCustomAttributes(ctx, vmCtx)).To(Succeed())
+			g.Expect(vmCtx.DryRunOperations).To(ConsistOf("custom attributes update"))
+			g.Expect(customAttributes()).To(BeEmpty())
+		})
+
+		t.Run("sets the custom attributes", func(*testing.T) {
+			g.Expect(vms.reconcileCustomAttributes(ctx, vmCtx)).To(Succeed())
+			g.Expect(customAttributes()).To(Equal(vmCtx.VSphereVM.Spec.CustomAttributes))
+
+			fields, err := manager.Field(ctx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(fields.ByKey(costCenter.Key).Name).To(Equal("cost-center"))
+			owners := 0
+			for _, def := range fields {
+				if def.Name == "owner" {
+					owners++
+				}
+			}
+			g.Expect(owners).To(Equal(2))
+		})
+
+		t.Run("keeps the custom attributes in sync", func(*testing.T) {
+			g.Expect(manager.Set(ctx, vm.Reference(), costCenter.Key, "5678")).To(Succeed())
+
+			g.Expect(vms.reconcileCustomAttributes(ctx, vmCtx)).To(Succeed())
+			g.Expect(customAttributes()).To(Equal(vmCtx.VSphereVM.Spec.CustomAttributes))
+		})
+		return nil
+	}, model)
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/govmomi/vcenter"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/tracing"
+)
+
+// reconcileConfigurationDrift compares the configuration of the VM with the spec and handles
+// drift according to spec.driftPolicy. It returns false if the VM is being reconfigured to
+// revert the drift.
+func (vms *VMService) reconcileConfigurationDrift(ctx context.Context, virtualMachineCtx *virtualMachineContext) (bool, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	vsphereVM := virtualMachineCtx.VSphereVM
+	if vsphereVM.Spec.DriftPolicy == "" {
+		conditions.Delete(vsphereVM, infrav1.VMConfigurationSyncedCondition)
+		return true, nil
+	}
+
+	props := []string{"config.hardware", "config.extraConfig"}
+	if vsphereVM.Spec.Resources != nil {
+		props = append(props, "config.cpuAllocation", "config.memoryAllocation", "config.memoryReservationLockedToMax", "config.swapPlacement")
+	}
+	var vm mo.VirtualMachine
+	if err := getVMProperties(ctx, virtualMachineCtx, props, &vm); err != nil {
+		return false, errors.Wrapf(err, "failed to get configuration of vm %s", virtualMachineCtx)
+	}
+
+	drifted, spec, err := getConfigurationDrift(ctx, virtualMachineCtx, vm)
+	if err != nil {
+		return false, err
+	}
+	if len(drifted) == 0 {
+		conditions.MarkTrue(vsphereVM, infrav1.VMConfigurationSyncedCondition)
+		return true, nil
+	}
+
+	message := fmt.Sprintf("Configuration drifted from spec: %s", strings.Join(drifted, ", "))
+	log = log.WithValues("driftPolicy", vsphereVM.Spec.DriftPolicy, "drifted", drifted)
+	if vsphereVM.Spec.DriftPolicy != infrav1.VirtualMachineDriftPolicyEnforce || spec == nil {
+		log.Info("VM configuration drifted from spec")
+		conditions.MarkFalse(vsphereVM, infrav1.VMConfigurationSyncedCondition, infrav1.ConfigurationDriftedReason, clusterv1.ConditionSeverityWarning, message)
+		return true, nil
+	}
+
+	if skipForDryRun(ctx, virtualMachineCtx, "configuration drift revert", "drifted", drifted) {
+		conditions.MarkFalse(vsphereVM, infrav1.VMConfigurationSyncedCondition, infrav1.ConfigurationDriftedReason, clusterv1.ConditionSeverityWarning, message)
+		return true, nil
+	}
+
+	log.Info("Reverting VM configuration drift")
+	task, err := virtualMachineCtx.Obj.Reconfigure(ctx, *spec)
+	if err != nil {
+		conditions.MarkFalse(vsphereVM, infrav1.VMConfigurationSyncedCondition, infrav1.ConfigurationDriftedReason, clusterv1.ConditionSeverityWarning, "%s: %v", message, err)
+		return false, errors.Wrapf(err, "failed to trigger reconfigure op for vm %s", virtualMachineCtx)
+	}
+	conditions.MarkFalse(vsphereVM, infrav1.VMConfigurationSyncedCondition, infrav1.RevertingConfigurationDriftReason, clusterv1.ConditionSeverityInfo, message)
+	tracing.SetTaskDescription(ctx, task)
+	vsphereVM.Status.TaskRef = task.Reference().Value
+
+	log.Info("Wait for VM configuration drift to be reverted")
+	return false, nil
+}
+
+// getConfigurationDrift returns the sorted names of the spec fields the configuration of the
+// VM drifted from, along with the spec reverting the drift. The returned spec is nil if none
+// of the drifted fields can be reverted.
+func getConfigurationDrift(ctx context.Context, virtualMachineCtx *virtualMachineContext, vm mo.VirtualMachine) ([]string, *types.VirtualMachineConfigSpec, error) {
+	if vm.Config == nil {
+		return nil, nil, errors.Errorf("failed to get configuration of vm %s", virtualMachineCtx)
+	}
+
+	vmSpec := virtualMachineCtx.VSphereVM.Spec
+	hardware := vm.Config.Hardware
+	devices := object.VirtualDeviceList(hardware.Device)
+
+	drifted := []string{}
+	spec := &types.VirtualMachineConfigSpec{}
+	revert := false
+
+	// The defaults match the ones applied when cloning the VM.
+	numCPUs := vmSpec.NumCPUs
+	if numCPUs < 2 {
+		numCPUs = 2
+	}
+	// If in-place resize is allowed, the CPU and memory are kept in sync by reconcileResize. The
+	// cores per socket are kept when resizing, so they are only compared if explicitly set.
+	if hardware.NumCPU != numCPUs && !vmSpec.AllowInPlaceResize {
+		drifted = append(drifted, "numCPUs")
+		spec.NumCPUs = numCPUs
+		revert = true
+	}
+	numCoresPerSocket := vmSpec.NumCoresPerSocket
+	if numCoresPerSocket == 0 {
+		numCoresPerSocket = numCPUs
+	}
+	if hardware.NumCoresPerSocket != numCoresPerSocket && (!vmSpec.AllowInPlaceResize || vmSpec.NumCoresPerSocket != 0) {
+		drifted = append(drifted, "numCoresPerSocket")
+		spec.NumCoresPerSocket = numCoresPerSocket
+		revert = true
+	}
+	memMiB := vmSpec.MemoryMiB
+	if memMiB == 0 {
+		memMiB = 2048
+	}
+	if int64(hardware.MemoryMB) != memMiB && !vmSpec.AllowInPlaceResize {
+		drifted = append(drifted, "memoryMiB")
+		spec.MemoryMB = memMiB
+		revert = true
+	}
+
+	if resources := vmSpec.Resources; resources != nil {
+		if resources.CPU != nil {
+			if allocation := vcenter.VirtualMachineResourceAllocation(resources.CPU); !vcenter.VirtualMachineResourceAllocationEqual(vm.Config.CpuAllocation, allocation) {
+				drifted = append(drifted, "resources.cpu")
+				spec.CpuAllocation = allocation
+				revert = true
+			}
+		}
+		if resources.Memory != nil {
+			if allocation := vcenter.VirtualMachineResourceAllocation(resources.Memory); !vcenter.VirtualMachineResourceAllocationEqual(vm.Config.MemoryAllocation, allocation) {
+				drifted = append(drifted, "resources.memory")
+				spec.MemoryAllocation = allocation
+				revert = true
+			}
+		}
+		if resources.MemoryReservationLockedToMax && !ptr.Deref(vm.Config.MemoryReservationLockedToMax, false) {
+			drifted = append(drifted, "resources.memoryReservationLockedToMax")
+			spec.MemoryReservationLockedToMax = ptr.To(true)
+			revert = true
+		}
+		if resources.SwapPlacement != "" && vm.Config.SwapPlacement != string(resources.SwapPlacement) {
+			drifted = append(drifted, "resources.swapPlacement")
+			spec.SwapPlacement = string(resources.SwapPlacement)
+			revert = true
+		}
+	}
+
+	// Linked clones keep the size of the disk of the template, and disks can't be shrunk, so
+	// only disks smaller than the spec are considered drifted.
+	if disks := devices.SelectByType((*types.VirtualDisk)(nil)); len(disks) > 0 && vmSpec.DiskGiB > 0 && virtualMachineCtx.VSphereVM.Status.CloneMode != infrav1.LinkedClone {
+		disk := disks[0].(*types.VirtualDisk)
+		capacityKB := int64(vmSpec.DiskGiB) * 1024 * 1024
+		if disk.CapacityInKB < capacityKB {
+			drifted = append(drifted, "diskGiB")
+			disk.CapacityInKB = capacityKB
+			disk.CapacityInBytes = 0
+			spec.DeviceChange = append(spec.DeviceChange, &types.VirtualDeviceConfigSpec{
+				Operation: types.VirtualDeviceConfigSpecOperationEdit,
+				Device:    disk,
+			})
+			revert = true
+		}
+	}
+
+	nics := devices.SelectByType((*types.VirtualEthernetCard)(nil))
+	if len(nics) != len(vmSpec.Network.Devices) {
+		// Added or removed NICs are only reported, as reverting them would change the
+		// identity of the network devices in the guest.
+		drifted = append(drifted, "network.devices")
+	}
+	for i := range vmSpec.Network.Devices {
+		if i >= len(nics) {
+			break
+		}
+		networkName := vmSpec.Network.Devices[i].NetworkName
+		ref, err := virtualMachineCtx.Session.Finder.Network(ctx, networkName)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "unable to find network %q", networkName)
+		}
+		backing, err := ref.EthernetCardBackingInfo(ctx)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "unable to create new ethernet card backing info for network %q on %q", networkName, virtualMachineCtx)
+		}
+		nic := nics[i]
+		if networkBackingMatches(nic.GetVirtualDevice().Backing, backing) {
+			continue
+		}
+		drifted = append(drifted, fmt.Sprintf("network.devices[%d].networkName", i))
+		nic.GetVirtualDevice().Backing = backing
+		spec.DeviceChange = append(spec.DeviceChange, &types.VirtualDeviceConfigSpec{
+			Operation: types.VirtualDeviceConfigSpecOperationEdit,
+			Device:    nic,
+		})
+		revert = true
+	}
+
+	extraConfig := object.OptionValueList(vm.Config.ExtraConfig)
+	for key, value := range vmSpec.CustomVMXKeys {
+		if actual, ok := extraConfig.GetString(key); ok && actual == value {
+			continue
+		}
+		drifted = append(drifted, fmt.Sprintf("customVMXKeys[%s]", key))
+		spec.ExtraConfig = append(spec.ExtraConfig, &types.OptionValue{Key: key, Value: value})
+		revert = true
+	}
+
+	sort.Strings(drifted)
+	if !revert {
+		return drifted, nil, nil
+	}
+	return drifted, spec, nil
+}
+
+// networkBackingMatches returns true if the backing of a NIC connects it to the same network
+// as the expected backing.
+func networkBackingMatches(actual, expected types.BaseVirtualDeviceBackingInfo) bool {
+	switch expected := expected.(type) {
+	case *types.VirtualEthernetCardNetworkBackingInfo:
+		actual, ok := actual.(*types.VirtualEthernetCardNetworkBackingInfo)
+		if !ok {
+			return false
+		}
+		if actual.Network != nil && expected.Network != nil {
+			return *actual.Network == *expected.Network
+		}
+		return actual.DeviceName == expected.DeviceName
+	case *types.VirtualEthernetCardDistributedVirtualPortBackingInfo:
+		actual, ok := actual.(*types.VirtualEthernetCardDistributedVirtualPortBackingInfo)
+		return ok && actual.Port.PortgroupKey == expected.Port.PortgroupKey
+	case *types.VirtualEthernetCardOpaqueNetworkBackingInfo:
+		actual, ok := actual.(*types.VirtualEthernetCardOpaqueNetworkBackingInfo)
+		return ok && actual.OpaqueNetworkId == expected.OpaqueNetworkId && actual.OpaqueNetworkType == expected.OpaqueNetworkType
+	default:
+		// Backings of unknown types are not compared.
+		return true
+	}
+}
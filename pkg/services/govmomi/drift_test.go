@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func Test_reconcileConfigurationDrift(t *testing.T) {
+	g := NewWithT(t)
+	model := simulator.VPX()
+	g.Expect(model.Create()).To(Succeed())
+
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		authSession, err := getAuthSession(ctx, model.Service.Listen.Host)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		finder := find.NewFinder(c)
+		vm, err := finder.VirtualMachine(ctx, "DC0_C0_RP0_VM0")
+		g.Expect(err).ToNot(HaveOccurred())
+
+		var moVM mo.VirtualMachine
+		g.Expect(vm.Properties(ctx, vm.Reference(), []string{"config.hardware"}, &moVM)).To(Succeed())
+		nics := object.VirtualDeviceList(moVM.Config.Hardware.Device).SelectByType((*types.VirtualEthernetCard)(nil))
+		g.Expect(nics).ToNot(BeEmpty())
+
+		vmCtx := emptyVirtualMachineContext()
+		vmCtx.Session = authSession
+		vmCtx.Obj = vm
+		vmCtx.Ref = vm.Reference()
+		vmCtx.VSphereVM = &infrav1.VSphereVM{
+			Spec: infrav1.VSphereVMSpec{
+				VirtualMachineCloneSpec: infrav1.VirtualMachineCloneSpec{
+					NumCPUs:           2,
+					NumCoresPerSocket: 2,
+					MemoryMiB:         2048,
+					CustomVMXKeys:     map[string]string{"foo": "bar"},
+				},
+			},
+		}
+		for range nics {
+			vmCtx.VSphereVM.Spec.Network.Devices = append(vmCtx.VSphereVM.Spec.Network.Devices, infrav1.NetworkDeviceSpec{NetworkName: "DC0_DVPG0"})
+		}
+
+		vms := &VMService{}
+
+		t.Run("without a drift policy", func(*testing.T) {
+			ok, err := vms.reconcileConfigurationDrift(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeTrue())
+			g.Expect(conditions.Has(vmCtx.VSphereVM, infrav1.VMConfigurationSyncedCondition)).To(BeFalse())
+		})
+
+		t.Run("with the report drift policy", func(*testing.T) {
+			vmCtx.VSphereVM.Spec.DriftPolicy = infrav1.VirtualMachineDriftPolicyReport
+
+			ok, err := vms.reconcileConfigurationDrift(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeTrue())
+			g.Expect(vmCtx.VSphereVM.Status.TaskRef).To(BeEmpty())
+			g.Expect(conditions.GetReason(vmCtx.VSphereVM, infrav1.VMConfigurationSyncedCondition)).To(Equal(infrav1.ConfigurationDriftedReason))
+			g.Expect(conditions.GetMessage(vmCtx.VSphereVM, infrav1.VMConfigurationSyncedCondition)).To(Equal(
+				"Configuration drifted from spec: customVMXKeys[foo], memoryMiB, numCPUs, numCoresPerSocket"))
+		})
+
+		t.Run("with the enforce drift policy", func(*testing.T) {
+			vmCtx.VSphereVM.Spec.DriftPolicy = infrav1.VirtualMachineDriftPolicyEnforce
+
+			ok, err := vms.reconcileConfigurationDrift(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeFalse())
+			g.Expect(vmCtx.VSphereVM.Status.TaskRef).ToNot(BeEmpty())
+			g.Expect(conditions.GetReason(vmCtx.VSphereVM, infrav1.VMConfigurationSyncedCondition)).To(Equal(infrav1.RevertingConfigurationDriftReason))
+
+			task := object.NewTask(c, types.ManagedObjectReference{Type: morefTypeTask, Value: vmCtx.VSphereVM.Status.TaskRef})
+			g.Expect(task.Wait(ctx)).To(Succeed())
+			vmCtx.VSphereVM.Status.TaskRef = ""
+
+			ok, err = vms.reconcileConfigurationDrift(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeTrue())
+			g.Expect(conditions.IsTrue(vmCtx.VSphereVM, infrav1.VMConfigurationSyncedCondition)).To(BeTrue())
+		})
+
+		t.Run("with the enforce drift policy in dry-run mode", func(*testing.T) {
+			vmCtx.VSphereVM.Spec.MemoryMiB = 4096
+			vmCtx.DryRun = true
+			defer func() { vmCtx.DryRun = false }()
+
+			ok, err := vms.reconcileConfigurationDrift(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeTrue())
+			g.Expect(vmCtx.VSphereVM.Status.TaskRef).To(BeEmpty())
+			g.Expect(vmCtx.DryRunOperations).To(ConsistOf("configuration drift revert"))
+			g.Expect(conditions.GetMessage(vmCtx.VSphereVM, infrav1.VMConfigurationSyncedCondition)).To(Equal("Configuration drifted from spec: memoryMiB"))
+		})
+
+		t.Run("with resource allocations and the enforce drift policy", func(*testing.T) {
+			vmCtx.VSphereVM.Spec.MemoryMiB = 2048
+			vmCtx.VSphereVM.Spec.Resources = &infrav1.VirtualMachineResources{
+				CPU:                          &infrav1.VirtualMachineResourceAllocation{Shares: infrav1.ResourcePoolSharesLevelHigh, Reservation: 1000},
+				MemoryReservationLockedToMax: true,
+			}
+
+			ok, err := vms.reconcileConfigurationDrift(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeFalse())
+			g.Expect(conditions.GetMessage(vmCtx.VSphereVM, infrav1.VMConfigurationSyncedCondition)).To(Equal(
+				"Configuration drifted from spec: resources.cpu, resources.memoryReservationLockedToMax"))
+
+			task := object.NewTask(c, types.ManagedObjectReference{Type: morefTypeTask, Value: vmCtx.VSphereVM.Status.TaskRef})
+			g.Expect(task.Wait(ctx)).To(Succeed())
+			vmCtx.VSphereVM.Status.TaskRef = ""
+
+			ok, err = vms.reconcileConfigurationDrift(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeTrue())
+			g.Expect(conditions.IsTrue(vmCtx.VSphereVM, infrav1.VMConfigurationSyncedCondition)).To(BeTrue())
+		})
+		return nil
+	}, model)
+}
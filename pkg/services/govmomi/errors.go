@@ -18,13 +18,101 @@ package govmomi
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/fault"
 	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/vim25/types"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
 )
 
+// FaultError is an error returned by vCenter translated to a stable, machine-readable
+// reason which is used in conditions instead of the opaque fault message.
+type FaultError struct {
+	// Reason is the condition reason for the error, e.g. InsufficientResources.
+	Reason string
+
+	err error
+}
+
+// newFaultError returns a FaultError for err. If err does not wrap a known vCenter
+// fault, the FaultError uses defaultReason.
+func newFaultError(err error, defaultReason string) *FaultError {
+	var faultErr *FaultError
+	if errors.As(err, &faultErr) {
+		return faultErr
+	}
+	return &FaultError{Reason: faultReason(err, defaultReason), err: err}
+}
+
+func (e *FaultError) Error() string {
+	return e.err.Error()
+}
+
+func (e *FaultError) Unwrap() error {
+	return e.err
+}
+
+// faultReason returns the reason for the first known vCenter fault in the fault tree of err,
+// or defaultReason if there is none. err can be an error, a types.BaseMethodFault or
+// a types.HasLocalizedMethodFault like the error of a task.
+func faultReason(err any, defaultReason string) string {
+	if err == nil {
+		return defaultReason
+	}
+
+	if notFoundErr, ok := err.(error); ok {
+		var findErr *find.NotFoundError
+		if errors.As(notFoundErr, &findErr) {
+			return notFoundReason(findErr, defaultReason)
+		}
+	}
+
+	reason := defaultReason
+	fault.In(err, func(f types.BaseMethodFault, _ string, _ []types.LocalizableMessage) bool {
+		switch f.(type) {
+		case types.BaseInsufficientResourcesFault, *types.NoDiskSpace:
+			reason = infrav1.InsufficientResourcesReason
+		case *types.DuplicateName:
+			reason = infrav1.DuplicateNameReason
+		case types.BaseInvalidLogin, *types.NotAuthenticated:
+			reason = infrav1.InvalidLoginReason
+		case types.BaseNoPermission:
+			reason = infrav1.NoPermissionReason
+		case types.BaseInvalidState:
+			reason = infrav1.InvalidStateReason
+		default:
+			return false
+		}
+		return true
+	})
+	return reason
+}
+
+// notFoundReason returns the reason for an object which cannot be found by the finder.
+// The finder does not expose the kind of the object, so it is derived from the error message,
+// e.g. "network 'VM Network' not found".
+func notFoundReason(err *find.NotFoundError, defaultReason string) string {
+	switch kind, _, _ := strings.Cut(err.Error(), " '"); kind {
+	case "network":
+		return infrav1.NetworkNotFoundReason
+	case "datastore":
+		return infrav1.DatastoreNotFoundReason
+	case "folder":
+		return infrav1.FolderNotFoundReason
+	case "resource pool":
+		return infrav1.ResourcePoolNotFoundReason
+	default:
+		return defaultReason
+	}
+}
+
 // errNotFound is returned by the findVM function when a VM is not found.
 type errNotFound struct {
 	uuid            string
+	instanceUUID    string
 	byInventoryPath string
 }
 
@@ -32,6 +120,9 @@ func (e errNotFound) Error() string {
 	if e.byInventoryPath != "" {
 		return fmt.Sprintf("vm with inventory path %s not found", e.byInventoryPath)
 	}
+	if e.instanceUUID != "" {
+		return fmt.Sprintf("vm with instance uuid %s not found", e.instanceUUID)
+	}
 	return fmt.Sprintf("vm with bios uuid %s not found", e.uuid)
 }
 
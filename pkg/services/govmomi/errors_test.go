@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/task"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func Test_faultReason(t *testing.T) {
+	tests := []struct {
+		name string
+		err  any
+		want string
+	}{
+		{
+			name: "nil error",
+			err:  nil,
+			want: infrav1.CloningFailedReason,
+		},
+		{
+			name: "unknown error",
+			err:  errors.New("something went wrong"),
+			want: infrav1.CloningFailedReason,
+		},
+		{
+			name: "unknown vim fault",
+			err:  soap.WrapVimFault(&types.NotSupported{}),
+			want: infrav1.CloningFailedReason,
+		},
+		{
+			name: "insufficient resources",
+			err:  soap.WrapVimFault(&types.InsufficientResourcesFault{}),
+			want: infrav1.InsufficientResourcesReason,
+		},
+		{
+			name: "insufficient memory resources",
+			err:  soap.WrapVimFault(&types.InsufficientMemoryResourcesFault{}),
+			want: infrav1.InsufficientResourcesReason,
+		},
+		{
+			name: "insufficient storage space",
+			err:  soap.WrapVimFault(&types.InsufficientStorageSpace{}),
+			want: infrav1.InsufficientResourcesReason,
+		},
+		{
+			name: "no disk space",
+			err:  soap.WrapVimFault(&types.NoDiskSpace{}),
+			want: infrav1.InsufficientResourcesReason,
+		},
+		{
+			name: "duplicate name",
+			err:  soap.WrapVimFault(&types.DuplicateName{Name: "vm"}),
+			want: infrav1.DuplicateNameReason,
+		},
+		{
+			name: "invalid login",
+			err:  soap.WrapVimFault(&types.InvalidLogin{}),
+			want: infrav1.InvalidLoginReason,
+		},
+		{
+			name: "not authenticated",
+			err:  soap.WrapVimFault(&types.NotAuthenticated{}),
+			want: infrav1.InvalidLoginReason,
+		},
+		{
+			name: "no permission",
+			err:  soap.WrapVimFault(&types.NoPermission{PrivilegeId: "VirtualMachine.Provisioning.Clone"}),
+			want: infrav1.NoPermissionReason,
+		},
+		{
+			name: "invalid power state",
+			err:  soap.WrapVimFault(&types.InvalidPowerState{}),
+			want: infrav1.InvalidStateReason,
+		},
+		{
+			name: "wrapped vim fault",
+			err:  errors.Wrap(soap.WrapVimFault(&types.DuplicateName{Name: "vm"}), "failed to clone"),
+			want: infrav1.DuplicateNameReason,
+		},
+		{
+			name: "fault cause of a vim fault",
+			err: soap.WrapVimFault(&types.NotSupported{
+				RuntimeFault: types.RuntimeFault{MethodFault: types.MethodFault{
+					FaultCause: &types.LocalizedMethodFault{Fault: &types.InsufficientCpuResourcesFault{}},
+				}},
+			}),
+			want: infrav1.InsufficientResourcesReason,
+		},
+		{
+			name: "task error",
+			err:  task.Error{LocalizedMethodFault: &types.LocalizedMethodFault{Fault: &types.InsufficientHostCapacityFault{}}},
+			want: infrav1.InsufficientResourcesReason,
+		},
+		{
+			name: "task info error",
+			err:  &types.LocalizedMethodFault{Fault: &types.DuplicateName{Name: "vm"}},
+			want: infrav1.DuplicateNameReason,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(faultReason(tt.err, infrav1.CloningFailedReason)).To(Equal(tt.want))
+		})
+	}
+
+	t.Run("objects not found by the finder", func(t *testing.T) {
+		g := NewWithT(t)
+		simulator.Test(func(ctx context.Context, c *vim25.Client) {
+			finder := find.NewFinder(c)
+			datacenter, err := finder.DefaultDatacenter(ctx)
+			g.Expect(err).ToNot(HaveOccurred())
+			finder.SetDatacenter(datacenter)
+
+			_, err = finder.Network(ctx, "missing")
+			g.Expect(faultReason(errors.Wrap(err, "failed to find network"), infrav1.CloningFailedReason)).To(Equal(infrav1.NetworkNotFoundReason))
+			_, err = finder.Datastore(ctx, "missing")
+			g.Expect(faultReason(err, infrav1.CloningFailedReason)).To(Equal(infrav1.DatastoreNotFoundReason))
+			_, err = finder.Folder(ctx, "missing")
+			g.Expect(faultReason(err, infrav1.CloningFailedReason)).To(Equal(infrav1.FolderNotFoundReason))
+			_, err = finder.ResourcePool(ctx, "missing")
+			g.Expect(faultReason(err, infrav1.CloningFailedReason)).To(Equal(infrav1.ResourcePoolNotFoundReason))
+			_, err = finder.VirtualMachine(ctx, "missing")
+			g.Expect(faultReason(err, infrav1.CloningFailedReason)).To(Equal(infrav1.CloningFailedReason))
+		})
+	})
+}
+
+func Test_newFaultError(t *testing.T) {
+	g := NewWithT(t)
+
+	vimErr := soap.WrapVimFault(&types.InsufficientMemoryResourcesFault{})
+	faultErr := newFaultError(vimErr, infrav1.CloningFailedReason)
+	g.Expect(faultErr.Reason).To(Equal(infrav1.InsufficientResourcesReason))
+	g.Expect(faultErr.Error()).To(Equal(vimErr.Error()))
+	g.Expect(errors.Is(faultErr, vimErr)).To(BeTrue())
+
+	// An existing FaultError keeps its reason.
+	wrapped := errors.Wrap(faultErr, "failed to reconcile VM")
+	g.Expect(newFaultError(wrapped, infrav1.PoweringOnFailedReason)).To(BeIdenticalTo(faultErr))
+
+	// Unknown errors use the default reason.
+	g.Expect(newFaultError(errors.New("something went wrong"), infrav1.PoweringOnFailedReason).Reason).To(Equal(infrav1.PoweringOnFailedReason))
+}
@@ -19,7 +19,10 @@ package extra
 
 import (
 	"encoding/base64"
+	"sort"
+	"strings"
 
+	"github.com/pkg/errors"
 	"github.com/vmware/govmomi/vim25/types"
 )
 
@@ -31,15 +34,49 @@ const (
 	guestInfoIgnitionEncoding  = "guestinfo.ignition.config.data.encoding"
 	guestInfoCloudInitData     = "guestinfo.userdata"
 	guestInfoCloudInitEncoding = "guestinfo.userdata.encoding"
+	guestInfoMetadata          = "guestinfo.metadata"
+	guestInfoMetadataEncoding  = "guestinfo.metadata.encoding"
 )
 
+// reservedKeys are the keys of the extraConfig which are managed by CAPV to deliver
+// the bootstrap data and the metadata.
+var reservedKeys = []string{
+	guestInfoIgnitionData,
+	guestInfoIgnitionEncoding,
+	guestInfoCloudInitData,
+	guestInfoCloudInitEncoding,
+	guestInfoMetadata,
+	guestInfoMetadataEncoding,
+}
+
+// IsReservedKey returns true if the key of the extraConfig is managed by CAPV and
+// can't be set as a custom VMX key. Keys are compared case-insensitively, as vSphere
+// does.
+func IsReservedKey(key string) bool {
+	for _, reserved := range reservedKeys {
+		if strings.EqualFold(key, reserved) {
+			return true
+		}
+	}
+	return false
+}
+
 // SetCustomVMXKeys sets the custom VMX keys as
-// OptionValues in extraConfig.
+// OptionValues in extraConfig, sorted by key.
+// It returns an error if a key is reserved.
 func (e *Config) SetCustomVMXKeys(customKeys map[string]string) error {
-	for k, v := range customKeys {
+	keys := make([]string, 0, len(customKeys))
+	for k := range customKeys {
+		if IsReservedKey(k) {
+			return errors.Errorf("custom VMX key %q is managed by CAPV and can't be set", k)
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
 		*e = append(*e, &types.OptionValue{
 			Key:   k,
-			Value: v,
+			Value: customKeys[k],
 		})
 	}
 	return nil
@@ -56,11 +93,11 @@ func (e *Config) SetCloudInitUserData(data []byte) {
 func (e *Config) SetCloudInitMetadata(data []byte) {
 	*e = append(*e,
 		&types.OptionValue{
-			Key:   "guestinfo.metadata",
+			Key:   guestInfoMetadata,
 			Value: e.encode(data),
 		},
 		&types.OptionValue{
-			Key:   "guestinfo.metadata.encoding",
+			Key:   guestInfoMetadataEncoding,
 			Value: "base64",
 		},
 	)
@@ -62,6 +62,34 @@ var _ = Describe("Config_SetCustomVMXKeys", func() {
 			}
 		})
 	})
+
+	Context("we try to set a key managed by CAPV in the config", func() {
+		var config Config
+
+		It("returns an error and doesn't add any key", func() {
+			err := config.SetCustomVMXKeys(map[string]string{
+				"customKey1":         "customVal1",
+				"GuestInfo.UserData": "userdata",
+			})
+
+			Expect(err).To(HaveOccurred())
+			Expect(config).To(BeEmpty())
+		})
+	})
+})
+
+var _ = Describe("IsReservedKey", func() {
+	It("returns true for the keys of the bootstrap data and the metadata", func() {
+		for _, key := range []string{"guestinfo.userdata", "guestinfo.userdata.encoding", "guestinfo.metadata", "guestinfo.metadata.encoding", "guestinfo.ignition.config.data", "guestinfo.ignition.config.data.encoding", "GUESTINFO.METADATA"} {
+			Expect(IsReservedKey(key)).To(BeTrue(), key)
+		}
+	})
+
+	It("returns false for other keys", func() {
+		for _, key := range []string{"disk.EnableUUID", "guestinfo.hostname", "sched.cpu.latencySensitivity"} {
+			Expect(IsReservedKey(key)).To(BeFalse(), key)
+		}
+	})
 })
 
 var _ = Describe("Config_SetCloudInitUserData", func() {
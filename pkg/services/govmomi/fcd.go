@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/tracing"
+)
+
+// reconcileFirstClassDisks attaches the existing First Class Disks referenced by the data
+// disks of the VM, one at a time. It returns false while a First Class Disk is being attached.
+func (vms *VMService) reconcileFirstClassDisks(ctx context.Context, virtualMachineCtx *virtualMachineContext) (bool, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	fcds := existingFirstClassDisks(virtualMachineCtx.VSphereVM)
+	if len(fcds) == 0 {
+		return true, nil
+	}
+
+	attached, err := attachedFirstClassDisks(ctx, virtualMachineCtx)
+	if err != nil {
+		return false, err
+	}
+
+	vsphereVM := virtualMachineCtx.VSphereVM
+	for _, fcd := range fcds {
+		if attached[fcd.ID] {
+			continue
+		}
+
+		if skipForDryRun(ctx, virtualMachineCtx, "First Class Disk attachment", "id", fcd.ID) {
+			return true, nil
+		}
+
+		datastore, err := virtualMachineCtx.Session.Finder.Datastore(ctx, fcd.Datastore)
+		if err != nil {
+			conditions.MarkFalse(vsphereVM, infrav1.VMProvisionedCondition, infrav1.FirstClassDiskAttachFailedReason, clusterv1.ConditionSeverityWarning,
+				"Unable to find datastore %s of First Class Disk %s: %v", fcd.Datastore, fcd.ID, err)
+			return false, errors.Wrapf(err, "unable to find datastore %s of First Class Disk %s", fcd.Datastore, fcd.ID)
+		}
+
+		log.Info("Attaching First Class Disk to VM", "id", fcd.ID, "datastore", fcd.Datastore)
+		res, err := methods.AttachDisk_Task(ctx, virtualMachineCtx.Session.Client.Client, &types.AttachDisk_Task{
+			This:      virtualMachineCtx.Ref,
+			DiskId:    types.ID{Id: fcd.ID},
+			Datastore: datastore.Reference(),
+		})
+		if err != nil {
+			conditions.MarkFalse(vsphereVM, infrav1.VMProvisionedCondition, infrav1.FirstClassDiskAttachFailedReason, clusterv1.ConditionSeverityWarning,
+				"Failed to attach First Class Disk %s: %v", fcd.ID, err)
+			return false, errors.Wrapf(err, "failed to trigger attach op of First Class Disk %s for vm %s", fcd.ID, virtualMachineCtx)
+		}
+		task := object.NewTask(virtualMachineCtx.Session.Client.Client, res.Returnval)
+		conditions.MarkFalse(vsphereVM, infrav1.VMProvisionedCondition, infrav1.AttachingFirstClassDiskReason, clusterv1.ConditionSeverityInfo,
+			"Attaching First Class Disk %s", fcd.ID)
+		tracing.SetTaskDescription(ctx, task)
+		vsphereVM.Status.TaskRef = task.Reference().Value
+
+		log.Info("Wait for First Class Disk to be attached to VM")
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// detachFirstClassDisks detaches the existing First Class Disks referenced by the data disks
+// of the VM, so that they are retained when the VM is destroyed.
+func (vms *VMService) detachFirstClassDisks(ctx context.Context, virtualMachineCtx *virtualMachineContext) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	fcds := existingFirstClassDisks(virtualMachineCtx.VSphereVM)
+	if len(fcds) == 0 {
+		return nil
+	}
+
+	attached, err := attachedFirstClassDisks(ctx, virtualMachineCtx)
+	if err != nil {
+		return err
+	}
+
+	for _, fcd := range fcds {
+		if !attached[fcd.ID] {
+			continue
+		}
+		log.Info("Detaching First Class Disk from VM", "id", fcd.ID)
+		if err := virtualMachineCtx.Obj.DetachDisk(ctx, fcd.ID); err != nil {
+			return errors.Wrapf(err, "failed to detach First Class Disk %s from vm %s", fcd.ID, virtualMachineCtx)
+		}
+	}
+	return nil
+}
+
+// existingFirstClassDisks returns the existing First Class Disks referenced by the data disks of the VSphereVM.
+func existingFirstClassDisks(vsphereVM *infrav1.VSphereVM) []infrav1.FirstClassDiskReference {
+	var fcds []infrav1.FirstClassDiskReference
+	for _, disk := range vsphereVM.Spec.DataDisks {
+		if disk.ExistingFCD != nil {
+			fcds = append(fcds, *disk.ExistingFCD)
+		}
+	}
+	return fcds
+}
+
+// attachedFirstClassDisks returns the IDs of the First Class Disks attached to the VM.
+func attachedFirstClassDisks(ctx context.Context, virtualMachineCtx *virtualMachineContext) (map[string]bool, error) {
+	var vm mo.VirtualMachine
+	if err := getVMProperties(ctx, virtualMachineCtx, []string{"config.hardware"}, &vm); err != nil {
+		return nil, errors.Wrapf(err, "failed to get configuration of vm %s", virtualMachineCtx)
+	}
+	if vm.Config == nil {
+		return nil, errors.Errorf("failed to get configuration of vm %s", virtualMachineCtx)
+	}
+
+	attached := map[string]bool{}
+	for _, device := range object.VirtualDeviceList(vm.Config.Hardware.Device).SelectByType((*types.VirtualDisk)(nil)) {
+		if disk := device.(*types.VirtualDisk); disk.VDiskId != nil {
+			attached[disk.VDiskId.Id] = true
+		}
+	}
+	return attached, nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/govmomi/vslm"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func Test_reconcileFirstClassDisks(t *testing.T) {
+	g := NewWithT(t)
+	model := simulator.VPX()
+	g.Expect(model.Create()).To(Succeed())
+
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		authSession, err := getAuthSession(ctx, model.Service.Listen.Host)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		finder := find.NewFinder(c)
+		vm, err := finder.VirtualMachine(ctx, "DC0_C0_RP0_VM0")
+		g.Expect(err).ToNot(HaveOccurred())
+		datastore, err := finder.Datastore(ctx, "LocalDS_0")
+		g.Expect(err).ToNot(HaveOccurred())
+
+		m := vslm.NewObjectManager(c)
+		task, err := m.CreateDisk(ctx, types.VslmCreateSpec{
+			Name:         "fcd-0",
+			CapacityInMB: 1024,
+			BackingSpec: &types.VslmCreateSpecDiskFileBackingSpec{
+				VslmCreateSpecBackingSpec: types.VslmCreateSpecBackingSpec{Datastore: datastore.Reference()},
+			},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		res, err := task.WaitForResult(ctx)
+		g.Expect(err).ToNot(HaveOccurred())
+		fcdID := res.Result.(types.VStorageObject).Config.Id.Id
+
+		vmCtx := emptyVirtualMachineContext()
+		vmCtx.Session = authSession
+		vmCtx.Obj = vm
+		vmCtx.Ref = vm.Reference()
+		vmCtx.VSphereVM = &infrav1.VSphereVM{}
+
+		vms := &VMService{}
+
+		t.Run("without existing First Class Disks", func(*testing.T) {
+			ok, err := vms.reconcileFirstClassDisks(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeTrue())
+			g.Expect(vmCtx.VSphereVM.Status.TaskRef).To(BeEmpty())
+			g.Expect(vms.detachFirstClassDisks(ctx, vmCtx)).To(Succeed())
+		})
+
+		t.Run("with a First Class Disk on an unknown datastore", func(*testing.T) {
+			vmCtx.VSphereVM.Spec.DataDisks = []infrav1.VSphereDisk{
+				{Name: "data", ExistingFCD: &infrav1.FirstClassDiskReference{ID: fcdID, Datastore: "unknown"}},
+			}
+
+			ok, err := vms.reconcileFirstClassDisks(ctx, vmCtx)
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(ok).To(BeFalse())
+			g.Expect(vmCtx.VSphereVM.Status.TaskRef).To(BeEmpty())
+			g.Expect(conditions.GetReason(vmCtx.VSphereVM, infrav1.VMProvisionedCondition)).To(Equal(infrav1.FirstClassDiskAttachFailedReason))
+		})
+
+		t.Run("with a First Class Disk in dry-run mode", func(*testing.T) {
+			vmCtx.VSphereVM.Spec.DataDisks[0].ExistingFCD.Datastore = "LocalDS_0"
+			vmCtx.DryRun = true
+			defer func() { vmCtx.DryRun = false }()
+
+			ok, err := vms.reconcileFirstClassDisks(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeTrue())
+			g.Expect(vmCtx.VSphereVM.Status.TaskRef).To(BeEmpty())
+			g.Expect(vmCtx.DryRunOperations).To(ConsistOf("First Class Disk attachment"))
+		})
+
+		t.Run("with a First Class Disk", func(*testing.T) {
+			ok, err := vms.reconcileFirstClassDisks(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeFalse())
+			g.Expect(vmCtx.VSphereVM.Status.TaskRef).ToNot(BeEmpty())
+			g.Expect(conditions.GetReason(vmCtx.VSphereVM, infrav1.VMProvisionedCondition)).To(Equal(infrav1.AttachingFirstClassDiskReason))
+
+			task := object.NewTask(c, types.ManagedObjectReference{Type: morefTypeTask, Value: vmCtx.VSphereVM.Status.TaskRef})
+			g.Expect(task.Wait(ctx)).To(Succeed())
+
+			fcd, err := m.Retrieve(ctx, datastore, fcdID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(fcd.Config.ConsumerId).To(HaveLen(1))
+		})
+
+		return nil
+	}, model)
+}
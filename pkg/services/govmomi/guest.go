@@ -0,0 +1,63 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+// reconcileGuestInfo updates the GuestToolsRunning and GuestHeartbeatGreen
+// conditions on the VSphereVM using the guest information reported by vCenter.
+func (vms *VMService) reconcileGuestInfo(ctx context.Context, virtualMachineCtx *virtualMachineContext) error {
+	var o mo.VirtualMachine
+	if err := getVMProperties(ctx, virtualMachineCtx, []string{"guest.toolsRunningStatus", "guestHeartbeatStatus"}, &o); err != nil {
+		return errors.Wrapf(err, "unable to fetch guest info for vm %s", virtualMachineCtx)
+	}
+
+	toolsRunningStatus := ""
+	if o.Guest != nil {
+		toolsRunningStatus = o.Guest.ToolsRunningStatus
+	}
+	setGuestInfoConditions(virtualMachineCtx.VSphereVM, toolsRunningStatus, o.GuestHeartbeatStatus)
+	return nil
+}
+
+// setGuestInfoConditions sets the guest conditions on the VSphereVM from the
+// VMware Tools running status and the guest heartbeat status.
+func setGuestInfoConditions(vm *infrav1.VSphereVM, toolsRunningStatus string, heartbeatStatus types.ManagedEntityStatus) {
+	if toolsRunningStatus == string(types.VirtualMachineToolsRunningStatusGuestToolsRunning) {
+		conditions.MarkTrue(vm, infrav1.GuestToolsRunningCondition)
+	} else {
+		conditions.MarkFalse(vm, infrav1.GuestToolsRunningCondition, infrav1.GuestToolsNotRunningReason, clusterv1.ConditionSeverityInfo,
+			"VMware Tools running status is %q", toolsRunningStatus)
+	}
+
+	if heartbeatStatus == types.ManagedEntityStatusGreen {
+		conditions.MarkTrue(vm, infrav1.GuestHeartbeatGreenCondition)
+	} else {
+		conditions.MarkFalse(vm, infrav1.GuestHeartbeatGreenCondition, infrav1.GuestHeartbeatNotGreenReason, clusterv1.ConditionSeverityInfo,
+			"guest heartbeat status is %q", heartbeatStatus)
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func TestSetGuestInfoConditions(t *testing.T) {
+	tests := []struct {
+		name               string
+		toolsRunningStatus string
+		heartbeatStatus    types.ManagedEntityStatus
+		expectToolsRunning corev1.ConditionStatus
+		expectHeartbeat    corev1.ConditionStatus
+	}{
+		{
+			name:               "tools running and heartbeat green",
+			toolsRunningStatus: string(types.VirtualMachineToolsRunningStatusGuestToolsRunning),
+			heartbeatStatus:    types.ManagedEntityStatusGreen,
+			expectToolsRunning: corev1.ConditionTrue,
+			expectHeartbeat:    corev1.ConditionTrue,
+		},
+		{
+			name:               "tools running and heartbeat yellow",
+			toolsRunningStatus: string(types.VirtualMachineToolsRunningStatusGuestToolsRunning),
+			heartbeatStatus:    types.ManagedEntityStatusYellow,
+			expectToolsRunning: corev1.ConditionTrue,
+			expectHeartbeat:    corev1.ConditionFalse,
+		},
+		{
+			name:               "tools not running",
+			toolsRunningStatus: string(types.VirtualMachineToolsRunningStatusGuestToolsNotRunning),
+			heartbeatStatus:    types.ManagedEntityStatusGray,
+			expectToolsRunning: corev1.ConditionFalse,
+			expectHeartbeat:    corev1.ConditionFalse,
+		},
+		{
+			name:               "no guest info reported",
+			expectToolsRunning: corev1.ConditionFalse,
+			expectHeartbeat:    corev1.ConditionFalse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			vm := &infrav1.VSphereVM{}
+
+			setGuestInfoConditions(vm, tt.toolsRunningStatus, tt.heartbeatStatus)
+
+			g.Expect(conditions.Get(vm, infrav1.GuestToolsRunningCondition).Status).To(Equal(tt.expectToolsRunning))
+			g.Expect(conditions.Get(vm, infrav1.GuestHeartbeatGreenCondition).Status).To(Equal(tt.expectHeartbeat))
+		})
+	}
+}
+
+func Test_reconcileGuestInfo(t *testing.T) {
+	g := NewWithT(t)
+
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		finder := find.NewFinder(c)
+		vm, err := finder.VirtualMachine(ctx, "DC0_H0_VM0")
+		g.Expect(err).ToNot(HaveOccurred())
+
+		vmCtx := emptyVirtualMachineContext()
+		vmCtx.Obj = vm
+		vmCtx.VSphereVM = &infrav1.VSphereVM{}
+
+		vms := &VMService{}
+		g.Expect(vms.reconcileGuestInfo(ctx, vmCtx)).To(Succeed())
+		g.Expect(conditions.Has(vmCtx.VSphereVM, infrav1.GuestToolsRunningCondition)).To(BeTrue())
+		g.Expect(conditions.Has(vmCtx.VSphereVM, infrav1.GuestHeartbeatGreenCondition)).To(BeTrue())
+		return nil
+	})
+}
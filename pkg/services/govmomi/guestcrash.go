@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/event"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+const (
+	// defaultGuestCrashThreshold is the number of guest crashes within the window at which
+	// the guest operating system is considered unhealthy, if not set in the spec.
+	defaultGuestCrashThreshold = 3
+
+	// defaultGuestCrashWindow is the period of time in which the guest crashes are counted,
+	// if not set in the spec.
+	defaultGuestCrashWindow = time.Hour
+)
+
+// guestCrashEventTypes are the types of the vCenter events reporting a crash of the guest
+// operating system of a VM: VMware Tools reports crashes of the guest, e.g. kernel panics,
+// and vSphere HA VM monitoring resets VMs whose guest heartbeat is lost, e.g. because the
+// kernel is wedged. VmDasBeingResetEvent also matches VmDasBeingResetWithScreenshotEvent.
+var guestCrashEventTypes = []string{"VmGuestOSCrashedEvent", "VmDasBeingResetEvent"}
+
+// reconcileGuestCrashes updates the GuestOSHealthy condition on the VSphereVM by counting the
+// guest crashes reported in the vCenter events of the VM within the window of
+// spec.guestCrashDetection. Failing to query the events doesn't fail the reconcile, as the
+// condition is informational.
+func (vms *VMService) reconcileGuestCrashes(ctx context.Context, virtualMachineCtx *virtualMachineContext) {
+	log := ctrl.LoggerFrom(ctx)
+
+	vsphereVM := virtualMachineCtx.VSphereVM
+	detection := vsphereVM.Spec.GuestCrashDetection
+	if detection == nil {
+		conditions.Delete(vsphereVM, infrav1.GuestOSHealthyCondition)
+		return
+	}
+
+	threshold, window := guestCrashDetectionParameters(detection)
+	crashes, err := getGuestCrashEvents(ctx, virtualMachineCtx.Obj.Client(), virtualMachineCtx.Obj.Reference(), time.Now().Add(-window))
+	if err != nil {
+		log.Error(err, "Failed to get guest crash events of VM")
+		conditions.MarkUnknown(vsphereVM, infrav1.GuestOSHealthyCondition, infrav1.GuestCrashEventsUnavailableReason, "%v", err)
+		return
+	}
+
+	if setGuestOSHealthyCondition(vsphereVM, crashes, threshold, window) {
+		log.Info("Guest operating system of VM is crashing repeatedly", "crashes", len(crashes), "window", window)
+	}
+}
+
+// guestCrashDetectionParameters returns the threshold and the window of the guest crash
+// detection, using the defaults for the fields which are not set.
+func guestCrashDetectionParameters(detection *infrav1.GuestCrashDetection) (int, time.Duration) {
+	threshold := defaultGuestCrashThreshold
+	if detection.Threshold > 0 {
+		threshold = int(detection.Threshold)
+	}
+	window := defaultGuestCrashWindow
+	if detection.Window != nil && detection.Window.Duration > 0 {
+		window = detection.Window.Duration
+	}
+	return threshold, window
+}
+
+// getGuestCrashEvents returns the events reporting a guest crash of the VM created since the
+// given time.
+func getGuestCrashEvents(ctx context.Context, c *vim25.Client, vmRef types.ManagedObjectReference, since time.Time) ([]types.BaseEvent, error) {
+	events, err := event.NewManager(c).QueryEvents(ctx, types.EventFilterSpec{
+		Entity: &types.EventFilterSpecByEntity{
+			Entity:    vmRef,
+			Recursion: types.EventFilterSpecRecursionOptionSelf,
+		},
+		Time: &types.EventFilterSpecByTime{
+			BeginTime: &since,
+		},
+		EventTypeId: guestCrashEventTypes,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query events of vm %s", vmRef.Value)
+	}
+	return events, nil
+}
+
+// setGuestOSHealthyCondition sets the GuestOSHealthy condition on the VSphereVM from the guest
+// crash events within the window. It returns true if the number of crashes reached the threshold.
+func setGuestOSHealthyCondition(vm *infrav1.VSphereVM, crashes []types.BaseEvent, threshold int, window time.Duration) bool {
+	if len(crashes) < threshold {
+		conditions.MarkTrue(vm, infrav1.GuestOSHealthyCondition)
+		return false
+	}
+
+	// The latest crash is reported in the message to help with troubleshooting.
+	latest := crashes[0].GetEvent()
+	for _, crash := range crashes[1:] {
+		if e := crash.GetEvent(); e.CreatedTime.After(latest.CreatedTime) {
+			latest = e
+		}
+	}
+	conditions.MarkFalse(vm, infrav1.GuestOSHealthyCondition, infrav1.GuestOSCrashLoopingReason, clusterv1.ConditionSeverityWarning,
+		"Guest operating system crashed %d times within %s, latest at %s: %s",
+		len(crashes), window, latest.CreatedTime.UTC().Format(time.RFC3339), latest.FullFormattedMessage)
+	return true
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/event"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func TestGuestCrashDetectionParameters(t *testing.T) {
+	g := NewWithT(t)
+
+	threshold, window := guestCrashDetectionParameters(&infrav1.GuestCrashDetection{})
+	g.Expect(threshold).To(Equal(defaultGuestCrashThreshold))
+	g.Expect(window).To(Equal(defaultGuestCrashWindow))
+
+	threshold, window = guestCrashDetectionParameters(&infrav1.GuestCrashDetection{
+		Threshold: 5,
+		Window:    &metav1.Duration{Duration: 10 * time.Minute},
+	})
+	g.Expect(threshold).To(Equal(5))
+	g.Expect(window).To(Equal(10 * time.Minute))
+}
+
+func TestSetGuestOSHealthyCondition(t *testing.T) {
+	now := time.Now()
+	crash := func(created time.Time, message string) types.BaseEvent {
+		return &types.VmGuestOSCrashedEvent{VmEvent: types.VmEvent{Event: types.Event{CreatedTime: created, FullFormattedMessage: message}}}
+	}
+
+	tests := []struct {
+		name          string
+		crashes       []types.BaseEvent
+		expectStatus  corev1.ConditionStatus
+		expectMessage string
+	}{
+		{
+			name:         "no crashes",
+			expectStatus: corev1.ConditionTrue,
+		},
+		{
+			name:         "crashes below the threshold",
+			crashes:      []types.BaseEvent{crash(now, "crashed"), crash(now, "crashed")},
+			expectStatus: corev1.ConditionTrue,
+		},
+		{
+			name: "crashes reaching the threshold",
+			crashes: []types.BaseEvent{
+				crash(now.Add(-2*time.Minute), "first crash"),
+				crash(now, "latest crash"),
+				crash(now.Add(-time.Minute), "second crash"),
+			},
+			expectStatus:  corev1.ConditionFalse,
+			expectMessage: "latest crash",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			vm := &infrav1.VSphereVM{}
+
+			crashLooping := setGuestOSHealthyCondition(vm, tt.crashes, 3, time.Hour)
+
+			g.Expect(crashLooping).To(Equal(tt.expectStatus == corev1.ConditionFalse))
+			condition := conditions.Get(vm, infrav1.GuestOSHealthyCondition)
+			g.Expect(condition.Status).To(Equal(tt.expectStatus))
+			if tt.expectStatus == corev1.ConditionFalse {
+				g.Expect(condition.Reason).To(Equal(infrav1.GuestOSCrashLoopingReason))
+				g.Expect(condition.Message).To(ContainSubstring("crashed 3 times within 1h0m0s"))
+				g.Expect(condition.Message).To(ContainSubstring(tt.expectMessage))
+			}
+		})
+	}
+}
+
+func Test_reconcileGuestCrashes(t *testing.T) {
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		finder := find.NewFinder(c)
+		vm, err := finder.VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			return err
+		}
+		other, err := finder.VirtualMachine(ctx, "DC0_H0_VM1")
+		if err != nil {
+			return err
+		}
+
+		postCrash := func(g *WithT, ref types.ManagedObjectReference) {
+			g.Expect(event.NewManager(c).PostEvent(ctx, &types.VmGuestOSCrashedEvent{
+				VmEvent: types.VmEvent{Event: types.Event{Vm: &types.VmEventArgument{Vm: ref}}},
+			})).To(Succeed())
+		}
+
+		newVirtualMachineContext := func() *virtualMachineContext {
+			vmCtx := emptyVirtualMachineContext()
+			vmCtx.Obj = vm
+			vmCtx.VSphereVM = &infrav1.VSphereVM{
+				Spec: infrav1.VSphereVMSpec{
+					VirtualMachineCloneSpec: infrav1.VirtualMachineCloneSpec{
+						GuestCrashDetection: &infrav1.GuestCrashDetection{Threshold: 2},
+					},
+				},
+			}
+			return vmCtx
+		}
+
+		vms := &VMService{}
+
+		t.Run("removes the condition if the detection is disabled", func(t *testing.T) {
+			g := NewWithT(t)
+			vmCtx := newVirtualMachineContext()
+			conditions.MarkTrue(vmCtx.VSphereVM, infrav1.GuestOSHealthyCondition)
+			vmCtx.VSphereVM.Spec.GuestCrashDetection = nil
+
+			vms.reconcileGuestCrashes(ctx, vmCtx)
+			g.Expect(conditions.Has(vmCtx.VSphereVM, infrav1.GuestOSHealthyCondition)).To(BeFalse())
+		})
+
+		t.Run("ignores the crashes of other VMs", func(t *testing.T) {
+			g := NewWithT(t)
+			postCrash(g, other.Reference())
+			postCrash(g, other.Reference())
+			postCrash(g, vm.Reference())
+
+			vmCtx := newVirtualMachineContext()
+			vms.reconcileGuestCrashes(ctx, vmCtx)
+			g.Expect(conditions.IsTrue(vmCtx.VSphereVM, infrav1.GuestOSHealthyCondition)).To(BeTrue())
+		})
+
+		t.Run("marks the guest unhealthy if the crashes reach the threshold", func(t *testing.T) {
+			g := NewWithT(t)
+			postCrash(g, vm.Reference())
+
+			vmCtx := newVirtualMachineContext()
+			vms.reconcileGuestCrashes(ctx, vmCtx)
+			g.Expect(conditions.IsFalse(vmCtx.VSphereVM, infrav1.GuestOSHealthyCondition)).To(BeTrue())
+			g.Expect(conditions.GetReason(vmCtx.VSphereVM, infrav1.GuestOSHealthyCondition)).To(Equal(infrav1.GuestOSCrashLoopingReason))
+		})
+		return nil
+	})
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+)
+
+const (
+	// enterMaintenanceModeTaskID is the description id of the task putting a host into maintenance mode.
+	enterMaintenanceModeTaskID = "HostSystem.enterMaintenanceMode"
+)
+
+// vmMigrationTaskIDs are the description ids of the tasks migrating a VM to another host,
+// including the migrations initiated by DRS.
+var vmMigrationTaskIDs = map[string]bool{
+	"Drm.ExecuteVMotionLRO":   true,
+	"VirtualMachine.migrate":  true,
+	"VirtualMachine.relocate": true,
+}
+
+// HostEvacuationStatus describes whether a VM is affected by the evacuation of its ESXi host.
+type HostEvacuationStatus struct {
+	// InProgress is true if the VM is getting migrated or its host is entering or in maintenance mode.
+	// It is only determined for VMs with local storage.
+	InProgress bool
+
+	// Host is the name of the host the VM is currently running on.
+	// It is only determined for VMs with local storage.
+	Host string
+
+	// LocalStorage is true if at least one datastore of the VM is not shared with other hosts.
+	LocalStorage bool
+}
+
+// HostEvacuationCache caches whether hosts are being evacuated, so that the properties and
+// tasks of a host are retrieved once for all the VMs running on it instead of once per VM.
+type HostEvacuationCache struct {
+	ttl time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]hostEvacuation
+}
+
+type hostEvacuation struct {
+	name       string
+	evacuating bool
+	expiry     time.Time
+}
+
+// NewHostEvacuationCache returns a HostEvacuationCache keeping the state of a host for the given duration.
+func NewHostEvacuationCache(ttl time.Duration) *HostEvacuationCache {
+	return &HostEvacuationCache{
+		ttl:   ttl,
+		hosts: map[string]hostEvacuation{},
+	}
+}
+
+func (c *HostEvacuationCache) get(key string) (hostEvacuation, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	host, ok := c.hosts[key]
+	if !ok || time.Now().After(host.expiry) {
+		delete(c.hosts, key)
+		return hostEvacuation{}, false
+	}
+	return host, true
+}
+
+func (c *HostEvacuationCache) set(key string, host hostEvacuation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	host.expiry = time.Now().Add(c.ttl)
+	c.hosts[key] = host
+}
+
+// GetHostEvacuationStatus returns the HostEvacuationStatus of the VM of the given VMContext.
+// The state of the host of the VM is read from the cache, if not nil.
+func GetHostEvacuationStatus(ctx context.Context, vmCtx *capvcontext.VMContext, cache *HostEvacuationCache) (HostEvacuationStatus, error) {
+	status := HostEvacuationStatus{}
+
+	vmRef, err := findVM(ctx, vmCtx)
+	if err != nil {
+		return status, err
+	}
+
+	var vm mo.VirtualMachine
+	if err := vmCtx.Session.RetrieveOne(ctx, vmRef, []string{"runtime.host", "datastore", "recentTask"}, &vm); err != nil {
+		return status, errors.Wrapf(err, "failed to get properties of VM %s", vmRef.Value)
+	}
+	if vm.Runtime.Host == nil {
+		return status, errors.Errorf("failed to get host of VM %s", vmRef.Value)
+	}
+
+	if len(vm.Datastore) > 0 {
+		var datastores []mo.Datastore
+		if err := vmCtx.Session.Retrieve(ctx, vm.Datastore, []string{"summary.multipleHostAccess"}, &datastores); err != nil {
+			return status, errors.Wrapf(err, "failed to get datastores of VM %s", vmRef.Value)
+		}
+		for _, ds := range datastores {
+			if ds.Summary.MultipleHostAccess == nil || !*ds.Summary.MultipleHostAccess {
+				status.LocalStorage = true
+				break
+			}
+		}
+	}
+	// VMs on shared storage are moved by vMotion without any disruption.
+	if !status.LocalStorage {
+		return status, nil
+	}
+
+	host, err := getHostEvacuation(ctx, vmCtx, *vm.Runtime.Host, cache)
+	if err != nil {
+		return status, err
+	}
+	status.Host = host.name
+	if host.evacuating {
+		status.InProgress = true
+		return status, nil
+	}
+
+	status.InProgress, err = hasActiveTask(ctx, vmCtx, vm.RecentTask, vmMigrationTaskIDs)
+	return status, err
+}
+
+// getHostEvacuation returns whether the host is entering or in maintenance mode.
+func getHostEvacuation(ctx context.Context, vmCtx *capvcontext.VMContext, hostRef types.ManagedObjectReference, cache *HostEvacuationCache) (hostEvacuation, error) {
+	key := vmCtx.VSphereVM.Spec.Server + "/" + hostRef.Value
+	if cache != nil {
+		if host, ok := cache.get(key); ok {
+			return host, nil
+		}
+	}
+
+	var hostSystem mo.HostSystem
+	if err := vmCtx.Session.RetrieveOne(ctx, hostRef, []string{"name", "runtime.inMaintenanceMode", "recentTask"}, &hostSystem); err != nil {
+		return hostEvacuation{}, errors.Wrapf(err, "failed to get properties of host %s", hostRef.Value)
+	}
+	host := hostEvacuation{name: hostSystem.Name, evacuating: hostSystem.Runtime.InMaintenanceMode}
+	if !host.evacuating {
+		enteringMaintenanceMode, err := hasActiveTask(ctx, vmCtx, hostSystem.RecentTask, map[string]bool{enterMaintenanceModeTaskID: true})
+		if err != nil {
+			return hostEvacuation{}, err
+		}
+		host.evacuating = enteringMaintenanceMode
+	}
+
+	if cache != nil {
+		cache.set(key, host)
+	}
+	return host, nil
+}
+
+// hasActiveTask returns true if one of the given tasks matches the description ids
+// and is either queued or running. The tasks are retrieved in a single call.
+func hasActiveTask(ctx context.Context, vmCtx *capvcontext.VMContext, taskRefs []types.ManagedObjectReference, descriptionIDs map[string]bool) (bool, error) {
+	if len(taskRefs) == 0 {
+		return false, nil
+	}
+	var tasks []mo.Task
+	if err := vmCtx.Session.Retrieve(ctx, taskRefs, []string{"info"}, &tasks); err != nil {
+		return false, errors.Wrap(err, "failed to get tasks")
+	}
+	for _, task := range tasks {
+		if !descriptionIDs[task.Info.DescriptionId] {
+			continue
+		}
+		if task.Info.State == types.TaskInfoStateQueued || task.Info.State == types.TaskInfoStateRunning {
+			return true, nil
+		}
+	}
+	return false, nil
+}
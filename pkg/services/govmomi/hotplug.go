@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/govmomi/vcenter"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/tracing"
+)
+
+// reconcileNetworkDeviceHotPlug hot-adds NICs for the network devices appended to the spec
+// of an existing VM. It returns false if NICs are being added.
+func (vms *VMService) reconcileNetworkDeviceHotPlug(ctx context.Context, virtualMachineCtx *virtualMachineContext) (bool, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	var vm mo.VirtualMachine
+	if err := getVMProperties(ctx, virtualMachineCtx, []string{"config.hardware"}, &vm); err != nil {
+		return false, errors.Wrapf(err, "failed to get configuration of vm %s", virtualMachineCtx)
+	}
+	if vm.Config == nil {
+		return false, errors.Errorf("failed to get configuration of vm %s", virtualMachineCtx)
+	}
+
+	vsphereVM := virtualMachineCtx.VSphereVM
+	nics := object.VirtualDeviceList(vm.Config.Hardware.Device).SelectByType((*types.VirtualEthernetCard)(nil))
+	devices := vsphereVM.Spec.Network.Devices
+
+	switch {
+	case len(nics) > len(devices):
+		conditions.MarkFalse(vsphereVM, infrav1.VMNetworkDevicesSyncedCondition, infrav1.NetworkDeviceRemovalNotSupportedReason, clusterv1.ConditionSeverityWarning,
+			"VM has %d NICs but %d network devices are defined, NICs are not removed from existing VMs", len(nics), len(devices))
+		return true, nil
+	case len(nics) == len(devices):
+		// The condition is only set once network devices have been appended, and it is completed
+		// by reconcileNetworkDeviceGuestConfig once the guest picked up the hot-added NICs.
+		if reason := conditions.GetReason(vsphereVM, infrav1.VMNetworkDevicesSyncedCondition); reason == infrav1.NetworkDeviceRemovalNotSupportedReason || reason == infrav1.SRIOVNetworkDeviceHotPlugNotSupportedReason {
+			conditions.MarkTrue(vsphereVM, infrav1.VMNetworkDevicesSyncedCondition)
+		}
+		return true, nil
+	}
+
+	for i := len(nics); i < len(devices); i++ {
+		if devices[i].SRIOV != nil {
+			conditions.MarkFalse(vsphereVM, infrav1.VMNetworkDevicesSyncedCondition, infrav1.SRIOVNetworkDeviceHotPlugNotSupportedReason, clusterv1.ConditionSeverityWarning,
+				"Network device %d is an SR-IOV NIC, SR-IOV NICs are not hot-added to existing VMs", i)
+			return true, nil
+		}
+	}
+
+	spec := types.VirtualMachineConfigSpec{}
+	key := int32(-100)
+	for i := len(nics); i < len(devices); i++ {
+		deviceSpec, err := vcenter.NewNetworkDeviceSpec(ctx, &virtualMachineCtx.VMContext, &devices[i], key)
+		if err != nil {
+			return false, err
+		}
+		// The NIC is connected right away, as the VM is usually powered on.
+		nic := deviceSpec.GetVirtualDeviceConfigSpec().Device.GetVirtualDevice()
+		nic.Connectable = &types.VirtualDeviceConnectInfo{
+			StartConnected:    true,
+			Connected:         true,
+			AllowGuestControl: true,
+		}
+		spec.DeviceChange = append(spec.DeviceChange, deviceSpec)
+		key--
+	}
+
+	if skipForDryRun(ctx, virtualMachineCtx, "network device hot plug", "count", len(spec.DeviceChange)) {
+		return true, nil
+	}
+
+	log.Info("Hot-adding NICs to VM", "count", len(spec.DeviceChange))
+	task, err := virtualMachineCtx.Obj.Reconfigure(ctx, spec)
+	if err != nil {
+		conditions.MarkFalse(vsphereVM, infrav1.VMNetworkDevicesSyncedCondition, infrav1.HotAddingNetworkDevicesReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return false, errors.Wrapf(err, "failed to trigger reconfigure op for vm %s", virtualMachineCtx)
+	}
+	conditions.MarkFalse(vsphereVM, infrav1.VMNetworkDevicesSyncedCondition, infrav1.HotAddingNetworkDevicesReason, clusterv1.ConditionSeverityInfo,
+		"Hot-adding %d NICs", len(spec.DeviceChange))
+	tracing.SetTaskDescription(ctx, task)
+	vsphereVM.Status.TaskRef = task.Reference().Value
+
+	log.Info("Wait for NICs to be hot-added to VM")
+	return false, nil
+}
+
+// reconcileNetworkDeviceGuestConfig completes the hot plug of NICs once the network configuration
+// in the metadata of the VM has been updated, by rebooting the guest if the network device hot
+// plug policy requires it.
+func (vms *VMService) reconcileNetworkDeviceGuestConfig(ctx context.Context, virtualMachineCtx *virtualMachineContext) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	vsphereVM := virtualMachineCtx.VSphereVM
+	if conditions.GetReason(vsphereVM, infrav1.VMNetworkDevicesSyncedCondition) != infrav1.HotAddingNetworkDevicesReason {
+		return nil
+	}
+
+	if vsphereVM.Spec.NetworkDeviceHotPlugPolicy == infrav1.NetworkDeviceHotPlugPolicyReboot {
+		if skipForDryRun(ctx, virtualMachineCtx, "guest reboot") {
+			return nil
+		}
+
+		log.Info("Rebooting guest to apply the network configuration of the hot-added NICs")
+		if err := virtualMachineCtx.Obj.RebootGuest(ctx); err != nil {
+			conditions.MarkFalse(vsphereVM, infrav1.VMNetworkDevicesSyncedCondition, infrav1.HotAddingNetworkDevicesReason, clusterv1.ConditionSeverityWarning,
+				"Failed to reboot guest: %v", err)
+			return errors.Wrapf(err, "failed to reboot guest of vm %s", virtualMachineCtx)
+		}
+	}
+
+	conditions.MarkTrue(vsphereVM, infrav1.VMNetworkDevicesSyncedCondition)
+	return nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func Test_reconcileNetworkDeviceHotPlug(t *testing.T) {
+	g := NewWithT(t)
+	model := simulator.VPX()
+	g.Expect(model.Create()).To(Succeed())
+
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		authSession, err := getAuthSession(ctx, model.Service.Listen.Host)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		finder := find.NewFinder(c)
+		vm, err := finder.VirtualMachine(ctx, "DC0_C0_RP0_VM0")
+		g.Expect(err).ToNot(HaveOccurred())
+
+		countNICs := func() int {
+			var moVM mo.VirtualMachine
+			g.Expect(vm.Properties(ctx, vm.Reference(), []string{"config.hardware"}, &moVM)).To(Succeed())
+			return len(object.VirtualDeviceList(moVM.Config.Hardware.Device).SelectByType((*types.VirtualEthernetCard)(nil)))
+		}
+		nics := countNICs()
+		g.Expect(nics).ToNot(BeZero())
+
+		vmCtx := emptyVirtualMachineContext()
+		vmCtx.Session = authSession
+		vmCtx.Obj = vm
+		vmCtx.Ref = vm.Reference()
+		vmCtx.VSphereVM = &infrav1.VSphereVM{}
+		for range nics {
+			vmCtx.VSphereVM.Spec.Network.Devices = append(vmCtx.VSphereVM.Spec.Network.Devices, infrav1.NetworkDeviceSpec{NetworkName: "DC0_DVPG0"})
+		}
+
+		vms := &VMService{}
+
+		t.Run("without appended network devices", func(*testing.T) {
+			ok, err := vms.reconcileNetworkDeviceHotPlug(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeTrue())
+			g.Expect(conditions.Has(vmCtx.VSphereVM, infrav1.VMNetworkDevicesSyncedCondition)).To(BeFalse())
+
+			g.Expect(vms.reconcileNetworkDeviceGuestConfig(ctx, vmCtx)).To(Succeed())
+			g.Expect(conditions.Has(vmCtx.VSphereVM, infrav1.VMNetworkDevicesSyncedCondition)).To(BeFalse())
+		})
+
+		t.Run("with appended network devices in dry-run mode", func(*testing.T) {
+			vmCtx.VSphereVM.Spec.Network.Devices = append(vmCtx.VSphereVM.Spec.Network.Devices, infrav1.NetworkDeviceSpec{NetworkName: "VM Network"})
+			vmCtx.DryRun = true
+			defer func() { vmCtx.DryRun = false }()
+
+			ok, err := vms.reconcileNetworkDeviceHotPlug(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeTrue())
+			g.Expect(vmCtx.VSphereVM.Status.TaskRef).To(BeEmpty())
+			g.Expect(vmCtx.DryRunOperations).To(ConsistOf("network device hot plug"))
+			g.Expect(countNICs()).To(Equal(nics))
+		})
+
+		t.Run("with appended network devices", func(*testing.T) {
+			ok, err := vms.reconcileNetworkDeviceHotPlug(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeFalse())
+			g.Expect(vmCtx.VSphereVM.Status.TaskRef).ToNot(BeEmpty())
+			g.Expect(conditions.GetReason(vmCtx.VSphereVM, infrav1.VMNetworkDevicesSyncedCondition)).To(Equal(infrav1.HotAddingNetworkDevicesReason))
+
+			task := object.NewTask(c, types.ManagedObjectReference{Type: morefTypeTask, Value: vmCtx.VSphereVM.Status.TaskRef})
+			g.Expect(task.Wait(ctx)).To(Succeed())
+			vmCtx.VSphereVM.Status.TaskRef = ""
+			g.Expect(countNICs()).To(Equal(nics + 1))
+
+			ok, err = vms.reconcileNetworkDeviceHotPlug(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeTrue())
+			g.Expect(conditions.GetReason(vmCtx.VSphereVM, infrav1.VMNetworkDevicesSyncedCondition)).To(Equal(infrav1.HotAddingNetworkDevicesReason))
+
+			g.Expect(vms.reconcileNetworkDeviceGuestConfig(ctx, vmCtx)).To(Succeed())
+			g.Expect(conditions.IsTrue(vmCtx.VSphereVM, infrav1.VMNetworkDevicesSyncedCondition)).To(BeTrue())
+		})
+
+		t.Run("with appended SR-IOV network devices", func(*testing.T) {
+			vmCtx.VSphereVM.Spec.Network.Devices = append(vmCtx.VSphereVM.Spec.Network.Devices, infrav1.NetworkDeviceSpec{NetworkName: "VM Network", SRIOV: &infrav1.NetworkDeviceSRIOVSpec{}})
+			defer func() {
+				vmCtx.VSphereVM.Spec.Network.Devices = vmCtx.VSphereVM.Spec.Network.Devices[:len(vmCtx.VSphereVM.Spec.Network.Devices)-1]
+			}()
+
+			ok, err := vms.reconcileNetworkDeviceHotPlug(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeTrue())
+			g.Expect(vmCtx.VSphereVM.Status.TaskRef).To(BeEmpty())
+			g.Expect(conditions.GetReason(vmCtx.VSphereVM, infrav1.VMNetworkDevicesSyncedCondition)).To(Equal(infrav1.SRIOVNetworkDeviceHotPlugNotSupportedReason))
+			g.Expect(countNICs()).To(Equal(nics + 1))
+		})
+
+		t.Run("with removed network devices", func(*testing.T) {
+			vmCtx.VSphereVM.Spec.Network.Devices = vmCtx.VSphereVM.Spec.Network.Devices[:1]
+
+			ok, err := vms.reconcileNetworkDeviceHotPlug(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeTrue())
+			g.Expect(vmCtx.VSphereVM.Status.TaskRef).To(BeEmpty())
+			g.Expect(conditions.GetReason(vmCtx.VSphereVM, infrav1.VMNetworkDevicesSyncedCondition)).To(Equal(infrav1.NetworkDeviceRemovalNotSupportedReason))
+			g.Expect(countNICs()).To(Equal(nics + 1))
+		})
+		return nil
+	}, model)
+}
@@ -17,10 +17,13 @@ limitations under the License.
 package ipam
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/netip"
 
 	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
 )
 
 // prefixesAsStrings converts []netip.Prefix to []string.
@@ -50,8 +53,11 @@ func parseAddressWithPrefix(ipamAddress *ipamv1.IPAddress) (netip.Prefix, error)
 // parseGateway parses the gateway address on a ipamv1.IPAddress and ensures it
 // does not conflict with the gateway addresses parsed from other
 // ipamv1.IPAddresses on the current device. Gateway addresses must be the same
-// family as the address on the ipamv1.IPAddress. Gateway addresses of one
-// family must match the other addresses of the same family. A gateway address
+// family as the address on the ipamv1.IPAddress. Gateway addresses must match
+// the gateway of the other addresses in the same subnet, while addresses in
+// different subnets of the same family may have different gateways; in this
+// case the first gateway is used as the default gateway of the device and it
+// has to match the gateway configured on the device, if any. A gateway address
 // is optional. If it is not set this function returns `nil, nil`.
 func parseGateway(ipamAddress *ipamv1.IPAddress, addressWithPrefix netip.Prefix, ipamDeviceConfig ipamDeviceConfig) (*netip.Addr, error) {
 	if ipamAddress.Spec.Gateway == "" {
@@ -75,25 +81,25 @@ func parseGateway(ipamAddress *ipamv1.IPAddress, addressWithPrefix netip.Prefix,
 	}
 
 	if gatewayAddr.Is4() {
-		if areGatewaysMismatched(ipamDeviceConfig.NetworkSpecGateway4, ipamAddress.Spec.Gateway) {
+		if ipamDeviceConfig.IPAMConfigGateway4 == "" && areGatewaysMismatched(ipamDeviceConfig.NetworkSpecGateway4, ipamAddress.Spec.Gateway) {
 			return nil, fmt.Errorf("the IPv4 Gateway for IPAddress %s does not match the Gateway4 already configured on device (index %d)",
 				ipamAddress.Name,
 				ipamDeviceConfig.DeviceIndex,
 			)
 		}
-		if areGatewaysMismatched(ipamDeviceConfig.IPAMConfigGateway4, ipamAddress.Spec.Gateway) {
+		if areGatewaysMismatched(ipamDeviceConfig.IPAMConfigSubnetGateways[addressWithPrefix.Masked()], ipamAddress.Spec.Gateway) {
 			return nil, fmt.Errorf("the IPv4 IPAddresses assigned to the same device (index %d) do not have the same gateway",
 				ipamDeviceConfig.DeviceIndex,
 			)
 		}
 	} else {
-		if areGatewaysMismatched(ipamDeviceConfig.NetworkSpecGateway6, ipamAddress.Spec.Gateway) {
+		if ipamDeviceConfig.IPAMConfigGateway6 == "" && areGatewaysMismatched(ipamDeviceConfig.NetworkSpecGateway6, ipamAddress.Spec.Gateway) {
 			return nil, fmt.Errorf("the IPv6 Gateway for IPAddress %s does not match the Gateway6 already configured on device (index %d)",
 				ipamAddress.Name,
 				ipamDeviceConfig.DeviceIndex,
 			)
 		}
-		if areGatewaysMismatched(ipamDeviceConfig.IPAMConfigGateway6, ipamAddress.Spec.Gateway) {
+		if areGatewaysMismatched(ipamDeviceConfig.IPAMConfigSubnetGateways[addressWithPrefix.Masked()], ipamAddress.Spec.Gateway) {
 			return nil, fmt.Errorf("the IPv6 IPAddresses assigned to the same device (index %d) do not have the same gateway",
 				ipamDeviceConfig.DeviceIndex,
 			)
@@ -103,6 +109,44 @@ func parseGateway(ipamAddress *ipamv1.IPAddress, addressWithPrefix netip.Prefix,
 	return &gatewayAddr, nil
 }
 
+// parseRoutes parses the static routes set on a ipamv1.IPAddress with the
+// IPAddressRoutesAnnotation. Routes are optional. If the annotation is not set
+// this function returns `nil, nil`.
+func parseRoutes(ipamAddress *ipamv1.IPAddress) ([]infrav1.NetworkRouteSpec, error) {
+	value, ok := ipamAddress.Annotations[infrav1.IPAddressRoutesAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	var routes []infrav1.NetworkRouteSpec
+	if err := json.Unmarshal([]byte(value), &routes); err != nil {
+		return nil, fmt.Errorf("IPAddress %s/%s has invalid routes: %q",
+			ipamAddress.Namespace,
+			ipamAddress.Name,
+			value,
+		)
+	}
+
+	for _, route := range routes {
+		if _, err := netip.ParsePrefix(route.To); err != nil && route.To != "default" {
+			return nil, fmt.Errorf("IPAddress %s/%s has a route with invalid destination: %q",
+				ipamAddress.Namespace,
+				ipamAddress.Name,
+				route.To,
+			)
+		}
+		if _, err := netip.ParseAddr(route.Via); err != nil {
+			return nil, fmt.Errorf("IPAddress %s/%s has a route with invalid gateway: %q",
+				ipamAddress.Namespace,
+				ipamAddress.Name,
+				route.Via,
+			)
+		}
+	}
+
+	return routes, nil
+}
+
 // areGatewaysMismatched checks that a gateway for a device is equal to an
 // IPAddresses gateway. We can assume that IPAddresses will always have
 // gateways so we do not need to check for empty string. It is possible to
@@ -46,6 +46,10 @@ type ipamDeviceConfig struct {
 	IPAMConfigGateway4  string
 	NetworkSpecGateway6 string
 	IPAMConfigGateway6  string
+	// IPAMConfigSubnetGateways maps the subnets of the IPAM addresses
+	// parsed so far to their gateway.
+	IPAMConfigSubnetGateways map[netip.Prefix]string
+	IPAMConfigRoutes         []infrav1.NetworkRouteSpec
 }
 
 // BuildState checks if IPAddressClaims are satisfied and returns a map of NetworkDeviceSpec.
@@ -82,12 +86,28 @@ func BuildState(ctx context.Context, vmCtx capvcontext.VMContext, networkStatus
 				continue
 			}
 
+			routes, err := parseRoutes(ipamAddress)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
 			if gatewayAddr != nil {
-				if gatewayAddr.Is4() {
+				// The first gateway of each IP family is used as the default gateway
+				// of the device, additional subnets are reachable via routes.
+				if gatewayAddr.Is4() && ipamDeviceConfig.IPAMConfigGateway4 == "" {
 					ipamDeviceConfig.IPAMConfigGateway4 = ipamAddress.Spec.Gateway
-				} else {
+				}
+				if gatewayAddr.Is6() && ipamDeviceConfig.IPAMConfigGateway6 == "" {
 					ipamDeviceConfig.IPAMConfigGateway6 = ipamAddress.Spec.Gateway
 				}
+				ipamDeviceConfig.IPAMConfigSubnetGateways[addressWithPrefix.Masked()] = ipamAddress.Spec.Gateway
+			}
+
+			for _, route := range routes {
+				if !slices.Contains(ipamDeviceConfig.IPAMConfigRoutes, route) {
+					ipamDeviceConfig.IPAMConfigRoutes = append(ipamDeviceConfig.IPAMConfigRoutes, route)
+				}
 			}
 
 			addressWithPrefixes = append(addressWithPrefixes, addressWithPrefix)
@@ -98,6 +118,7 @@ func BuildState(ctx context.Context, vmCtx capvcontext.VMContext, networkStatus
 				IPAddrs:  prefixesAsStrings(addressWithPrefixes),
 				Gateway4: ipamDeviceConfig.IPAMConfigGateway4,
 				Gateway6: ipamDeviceConfig.IPAMConfigGateway6,
+				Routes:   ipamDeviceConfig.IPAMConfigRoutes,
 			}
 		}
 	}
@@ -131,11 +152,12 @@ func buildIPAMDeviceConfigs(ctx context.Context, vmCtx capvcontext.VMContext, ne
 		}
 
 		ipamDeviceConfig := ipamDeviceConfig{
-			IPAMAddresses:       []*ipamv1.IPAddress{},
-			MACAddress:          networkStatus[devIdx].MACAddr,
-			NetworkSpecGateway4: networkSpecDevice.Gateway4,
-			NetworkSpecGateway6: networkSpecDevice.Gateway6,
-			DeviceIndex:         devIdx,
+			IPAMAddresses:            []*ipamv1.IPAddress{},
+			MACAddress:               networkStatus[devIdx].MACAddr,
+			NetworkSpecGateway4:      networkSpecDevice.Gateway4,
+			NetworkSpecGateway6:      networkSpecDevice.Gateway6,
+			DeviceIndex:              devIdx,
+			IPAMConfigSubnetGateways: map[netip.Prefix]string{},
 		}
 
 		for poolRefIdx := range networkSpecDevice.AddressesFromPools {
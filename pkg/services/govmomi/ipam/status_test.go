@@ -156,7 +156,7 @@ func Test_buildIPAMDeviceConfigs(t *testing.T) {
 		}
 		g.Expect(vmCtx.Client.Get(ctx, ipAddrClaimKey, ipAddrClaim)).NotTo(gomega.HaveOccurred())
 		ipAddrClaim.Status.AddressRef.Name = "vsphereVM1-0-2-address2"
-		g.Expect(vmCtx.Client.Update(ctx, ipAddrClaim)).NotTo(gomega.HaveOccurred())
+		g.Expect(vmCtx.Client.Status().Update(ctx, ipAddrClaim)).NotTo(gomega.HaveOccurred())
 
 		// Only the last claim has been bound
 		_, err = buildIPAMDeviceConfigs(ctx, vmCtx, networkStatus)
@@ -172,7 +172,7 @@ func Test_buildIPAMDeviceConfigs(t *testing.T) {
 		}
 		g.Expect(vmCtx.Client.Get(ctx, ipAddrClaimKey, ipAddrClaim)).NotTo(gomega.HaveOccurred())
 		ipAddrClaim.Status.AddressRef.Name = "vsphereVM1-0-0-address0"
-		g.Expect(vmCtx.Client.Update(ctx, ipAddrClaim)).NotTo(gomega.HaveOccurred())
+		g.Expect(vmCtx.Client.Status().Update(ctx, ipAddrClaim)).NotTo(gomega.HaveOccurred())
 
 		ipAddrClaimKey = apitypes.NamespacedName{
 			Namespace: vmCtx.VSphereVM.Namespace,
@@ -180,7 +180,7 @@ func Test_buildIPAMDeviceConfigs(t *testing.T) {
 		}
 		g.Expect(vmCtx.Client.Get(ctx, ipAddrClaimKey, ipAddrClaim)).NotTo(gomega.HaveOccurred())
 		ipAddrClaim.Status.AddressRef.Name = "vsphereVM1-0-1-address1"
-		g.Expect(vmCtx.Client.Update(ctx, ipAddrClaim)).NotTo(gomega.HaveOccurred())
+		g.Expect(vmCtx.Client.Status().Update(ctx, ipAddrClaim)).NotTo(gomega.HaveOccurred())
 
 		// Now that claims are fulfilled, reconciling should update
 		// ipAddrs on network spec
@@ -371,7 +371,7 @@ func Test_BuildState(t *testing.T) {
 		}
 		g.Expect(vmCtx.Client.Get(ctx, ipAddrClaimKey, ipAddrClaim)).NotTo(gomega.HaveOccurred())
 		ipAddrClaim.Status.AddressRef.Name = "vsphereVM1-0-2-address2"
-		g.Expect(vmCtx.Client.Update(ctx, ipAddrClaim)).NotTo(gomega.HaveOccurred())
+		g.Expect(vmCtx.Client.Status().Update(ctx, ipAddrClaim)).NotTo(gomega.HaveOccurred())
 
 		// Only the last claim has been bound
 		_, err = BuildState(ctx, vmCtx, networkStatus)
@@ -387,7 +387,7 @@ func Test_BuildState(t *testing.T) {
 		}
 		g.Expect(vmCtx.Client.Get(ctx, ipAddrClaimKey, ipAddrClaim)).NotTo(gomega.HaveOccurred())
 		ipAddrClaim.Status.AddressRef.Name = "vsphereVM1-0-0-address0"
-		g.Expect(vmCtx.Client.Update(ctx, ipAddrClaim)).NotTo(gomega.HaveOccurred())
+		g.Expect(vmCtx.Client.Status().Update(ctx, ipAddrClaim)).NotTo(gomega.HaveOccurred())
 
 		ipAddrClaimKey = apitypes.NamespacedName{
 			Namespace: vmCtx.VSphereVM.Namespace,
@@ -395,7 +395,7 @@ func Test_BuildState(t *testing.T) {
 		}
 		g.Expect(vmCtx.Client.Get(ctx, ipAddrClaimKey, ipAddrClaim)).NotTo(gomega.HaveOccurred())
 		ipAddrClaim.Status.AddressRef.Name = "vsphereVM1-0-1-address1"
-		g.Expect(vmCtx.Client.Update(ctx, ipAddrClaim)).NotTo(gomega.HaveOccurred())
+		g.Expect(vmCtx.Client.Status().Update(ctx, ipAddrClaim)).NotTo(gomega.HaveOccurred())
 
 		// Now that claims are fulfilled, reconciling should update
 		// ipAddrs on network spec
@@ -521,7 +521,7 @@ func Test_BuildState(t *testing.T) {
 		g.Expect(vmCtx.Client.Get(ctx, ipAddrClaimKey, ipAddrClaim)).NotTo(gomega.HaveOccurred())
 
 		ipAddrClaim.Status.AddressRef.Name = "vsphereVM1-1-0-address"
-		g.Expect(vmCtx.Client.Update(ctx, ipAddrClaim)).NotTo(gomega.HaveOccurred())
+		g.Expect(vmCtx.Client.Status().Update(ctx, ipAddrClaim)).NotTo(gomega.HaveOccurred())
 
 		// Now that claims are fulfilled, reconciling should update
 		// ipAddrs on network spec
@@ -801,6 +801,60 @@ func Test_BuildState(t *testing.T) {
 			g.Expect(err).To(gomega.MatchError("the IPv6 IPAddresses assigned to the same device (index 0) do not have the same gateway"))
 		})
 
+		t.Run("when there are multiple IPAddresses for a device in different subnets with different Gateways", func(_ *testing.T) {
+			beforeWithClaimsAndAddressCreated()
+
+			address2.Spec.Address = "10.0.2.51"
+			address2.Spec.Gateway = "10.0.2.1"
+			g.Expect(vmCtx.Client.Update(ctx, address2)).NotTo(gomega.HaveOccurred())
+
+			state, err := BuildState(ctx, vmCtx, networkStatus)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(state[devMAC0].IPAddrs).To(gomega.Equal([]string{"10.0.1.50/24", "10.0.2.51/24"}))
+			g.Expect(state[devMAC0].Gateway4).To(gomega.Equal("10.0.0.1"))
+		})
+
+		t.Run("when a provider sets routes on the IPAddresses", func(_ *testing.T) {
+			beforeWithClaimsAndAddressCreated()
+
+			address1.Annotations = map[string]string{
+				infrav1.IPAddressRoutesAnnotation: `[{"to":"10.20.0.0/16","via":"10.0.0.1","metric":100}]`,
+			}
+			g.Expect(vmCtx.Client.Update(ctx, address1)).NotTo(gomega.HaveOccurred())
+			address2.Annotations = map[string]string{
+				infrav1.IPAddressRoutesAnnotation: `[{"to":"10.20.0.0/16","via":"10.0.0.1","metric":100},{"to":"10.30.0.0/16","via":"10.0.0.1"}]`,
+			}
+			g.Expect(vmCtx.Client.Update(ctx, address2)).NotTo(gomega.HaveOccurred())
+
+			state, err := BuildState(ctx, vmCtx, networkStatus)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(state[devMAC0].Routes).To(gomega.Equal([]infrav1.NetworkRouteSpec{
+				{To: "10.20.0.0/16", Via: "10.0.0.1", Metric: 100},
+				{To: "10.30.0.0/16", Via: "10.0.0.1"},
+			}))
+			g.Expect(state[devMAC1].Routes).To(gomega.BeEmpty())
+		})
+
+		t.Run("when a provider sets invalid routes on an IPAddress", func(_ *testing.T) {
+			beforeWithClaimsAndAddressCreated()
+
+			address1.Annotations = map[string]string{
+				infrav1.IPAddressRoutesAnnotation: "invalid-routes",
+			}
+			g.Expect(vmCtx.Client.Update(ctx, address1)).NotTo(gomega.HaveOccurred())
+
+			_, err := BuildState(ctx, vmCtx, networkStatus)
+			g.Expect(err).To(gomega.MatchError("IPAddress my-namespace/vsphereVM1-0-0 has invalid routes: \"invalid-routes\""))
+
+			address1.Annotations = map[string]string{
+				infrav1.IPAddressRoutesAnnotation: `[{"to":"10.20.0.0/16","via":"invalid-gateway"}]`,
+			}
+			g.Expect(vmCtx.Client.Update(ctx, address1)).NotTo(gomega.HaveOccurred())
+
+			_, err = BuildState(ctx, vmCtx, networkStatus)
+			g.Expect(err).To(gomega.MatchError("IPAddress my-namespace/vsphereVM1-0-0 has a route with invalid gateway: \"invalid-gateway\""))
+		})
+
 		t.Run("when a user specified gateway does not match the gateway provided by IPAM", func(_ *testing.T) {
 			beforeWithClaimsAndAddressCreated()
 
@@ -0,0 +1,184 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+)
+
+// reconcileNetworkProtocolProfileAddresses allocates the IPv4 addresses of the network devices
+// from the IP pools of vSphere Network Protocol Profiles and adds them to the IPAM state, so they
+// are rendered into the metadata like the addresses of IPAddressPools.
+// The allocations are identified by the VSphereVM's UID and the device index, so allocating the
+// address again returns the same address.
+func (vms *VMService) reconcileNetworkProtocolProfileAddresses(ctx context.Context, virtualMachineCtx *virtualMachineContext) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	devices := virtualMachineCtx.VSphereVM.Spec.Network.Devices
+	for i, device := range devices {
+		if device.AddressFromNetworkProtocolProfile == nil {
+			continue
+		}
+		if len(virtualMachineCtx.State.Network) <= i || virtualMachineCtx.State.Network[i].MACAddr == "" {
+			return errors.Errorf("waiting for device %d to have MAC address set", i)
+		}
+
+		dc, pool, err := findNetworkProtocolProfileIPPool(ctx, &virtualMachineCtx.VMContext, device)
+		if err != nil {
+			return err
+		}
+		if pool == nil {
+			return errors.Errorf("unable to find the IP pool of the network protocol profile for device %d on network %q", i, device.NetworkName)
+		}
+		if pool.Ipv4Config == nil || (pool.Ipv4Config.IpPoolEnabled != nil && !*pool.Ipv4Config.IpPoolEnabled) {
+			return errors.Errorf("IP pool %q has no enabled IPv4 range", pool.Name)
+		}
+		prefixLength, bits := net.IPMask(net.ParseIP(pool.Ipv4Config.Netmask).To4()).Size()
+		if bits == 0 {
+			return errors.Errorf("IP pool %q has an invalid IPv4 netmask %q", pool.Name, pool.Ipv4Config.Netmask)
+		}
+
+		c := virtualMachineCtx.Session.Client.Client
+		res, err := methods.AllocateIpv4Address(ctx, c, &types.AllocateIpv4Address{
+			This:         *c.ServiceContent.IpPoolManager,
+			Dc:           dc.Reference(),
+			PoolId:       pool.Id,
+			AllocationId: networkProtocolProfileAllocationID(virtualMachineCtx.VSphereVM, i),
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to allocate an IPv4 address for device %d from IP pool %q", i, pool.Name)
+		}
+		address := fmt.Sprintf("%s/%d", res.Returnval, prefixLength)
+		log.V(4).Info("Allocated address from network protocol profile", "deviceIndex", i, "ipPool", pool.Name, "address", address)
+
+		if virtualMachineCtx.IPAMState == nil {
+			virtualMachineCtx.IPAMState = map[string]infrav1.NetworkDeviceSpec{}
+		}
+		macAddr := virtualMachineCtx.State.Network[i].MACAddr
+		state := virtualMachineCtx.IPAMState[macAddr]
+		state.IPAddrs = append(state.IPAddrs, address)
+		// The gateway of the device spec takes precedence, like for IPAddressPools.
+		if state.Gateway4 == "" {
+			state.Gateway4 = device.Gateway4
+		}
+		if state.Gateway4 == "" {
+			state.Gateway4 = pool.Ipv4Config.Gateway
+		}
+		if state.Gateway6 == "" {
+			state.Gateway6 = device.Gateway6
+		}
+		if len(device.Nameservers) == 0 {
+			state.Nameservers = pool.Ipv4Config.Dns
+		}
+		if len(device.SearchDomains) == 0 && pool.DnsDomain != "" {
+			state.SearchDomains = []string{pool.DnsDomain}
+		}
+		virtualMachineCtx.IPAMState[macAddr] = state
+	}
+	return nil
+}
+
+// releaseNetworkProtocolProfileAddresses releases the IPv4 addresses of the network devices
+// allocated from the IP pools of vSphere Network Protocol Profiles. Addresses of IP pools
+// which no longer exist are not released.
+func (vms *VMService) releaseNetworkProtocolProfileAddresses(ctx context.Context, vmCtx *capvcontext.VMContext) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	for i, device := range vmCtx.VSphereVM.Spec.Network.Devices {
+		if device.AddressFromNetworkProtocolProfile == nil {
+			continue
+		}
+
+		dc, pool, err := findNetworkProtocolProfileIPPool(ctx, vmCtx, device)
+		if err != nil {
+			return err
+		}
+		if pool == nil {
+			log.Info("Unable to find the IP pool of the network protocol profile, skipping release of the address", "deviceIndex", i)
+			continue
+		}
+
+		c := vmCtx.Session.Client.Client
+		if _, err := methods.ReleaseIpAllocation(ctx, c, &types.ReleaseIpAllocation{
+			This:         *c.ServiceContent.IpPoolManager,
+			Dc:           dc.Reference(),
+			PoolId:       pool.Id,
+			AllocationId: networkProtocolProfileAllocationID(vmCtx.VSphereVM, i),
+		}); err != nil {
+			return errors.Wrapf(err, "failed to release the IPv4 address of device %d to IP pool %q", i, pool.Name)
+		}
+		log.Info("Released address to network protocol profile", "deviceIndex", i, "ipPool", pool.Name)
+	}
+	return nil
+}
+
+// findNetworkProtocolProfileIPPool returns the IP pool the address of the device is allocated from,
+// which is either the IP pool with the given name or the one associated with the network of the device.
+// It returns a nil pool if there is no such IP pool in the datacenter of the VM.
+func findNetworkProtocolProfileIPPool(ctx context.Context, vmCtx *capvcontext.VMContext, device infrav1.NetworkDeviceSpec) (*object.Datacenter, *types.IpPool, error) {
+	dc, err := vmCtx.Session.Finder.DatacenterOrDefault(ctx, vmCtx.VSphereVM.Spec.Datacenter)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "unable to find datacenter %q", vmCtx.VSphereVM.Spec.Datacenter)
+	}
+
+	c := vmCtx.Session.Client.Client
+	res, err := methods.QueryIpPools(ctx, c, &types.QueryIpPools{
+		This: *c.ServiceContent.IpPoolManager,
+		Dc:   dc.Reference(),
+	})
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to query the IP pools of datacenter %q", dc.InventoryPath)
+	}
+
+	if name := device.AddressFromNetworkProtocolProfile.IPPool; name != "" {
+		for i := range res.Returnval {
+			if res.Returnval[i].Name == name {
+				return dc, &res.Returnval[i], nil
+			}
+		}
+		return dc, nil, nil
+	}
+
+	network, err := vmCtx.Session.Finder.Network(ctx, device.NetworkName)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "unable to find network %q", device.NetworkName)
+	}
+	for i := range res.Returnval {
+		for _, association := range res.Returnval[i].NetworkAssociation {
+			if association.Network != nil && *association.Network == network.Reference() {
+				return dc, &res.Returnval[i], nil
+			}
+		}
+	}
+	return dc, nil, nil
+}
+
+// networkProtocolProfileAllocationID returns the ID of the allocation of the address of the device.
+func networkProtocolProfileAllocationID(vsphereVM *infrav1.VSphereVM, deviceIndex int) string {
+	return fmt.Sprintf("%s-%d", vsphereVM.UID, deviceIndex)
+}
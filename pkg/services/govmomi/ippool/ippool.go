@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ippool contains tools to allocate IP addresses from vCenter IP pools.
+package ippool
+
+import (
+	"context"
+	"net"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/fault"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/types"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/session"
+)
+
+// ErrPoolExhausted is returned when no IP address is available in an IP pool.
+var ErrPoolExhausted = errors.New("no IP address available in IP pool")
+
+// ErrPoolNotFound is returned when an IP pool doesn't exist in a datacenter.
+var ErrPoolNotFound = errors.New("IP pool not found")
+
+// Pool is an IP pool of a datacenter in vCenter.
+type Pool struct {
+	Datacenter types.ManagedObjectReference
+	Config     types.IpPool
+}
+
+// Address is an IP address allocated from an IP pool.
+type Address struct {
+	Address string
+	Prefix  int
+	Gateway string
+}
+
+// Find returns the IP pool with the given name in the datacenter.
+func Find(ctx context.Context, s *session.Session, datacenter, name string) (*Pool, error) {
+	dc, err := s.Finder.Datacenter(ctx, datacenter)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find datacenter %s", datacenter)
+	}
+
+	res, err := methods.QueryIpPools(ctx, s.Client.Client, &types.QueryIpPools{
+		This: *s.Client.ServiceContent.IpPoolManager,
+		Dc:   dc.Reference(),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query IP pools of datacenter %s", datacenter)
+	}
+	for _, pool := range res.Returnval {
+		if pool.Name == name {
+			return &Pool{Datacenter: dc.Reference(), Config: pool}, nil
+		}
+	}
+	return nil, errors.Wrapf(ErrPoolNotFound, "failed to find IP pool %s in datacenter %s", name, datacenter)
+}
+
+// Allocate allocates an IP address from the IP pool for the allocation ID. Allocating
+// again for the same allocation ID returns the same IP address. An IPv4 address is
+// allocated if the pool has an IPv4 configuration, an IPv6 address otherwise.
+func (p *Pool) Allocate(ctx context.Context, s *session.Session, allocationID string) (*Address, error) {
+	if config := p.Config.Ipv4Config; isEnabled(config) {
+		res, err := methods.AllocateIpv4Address(ctx, s.Client.Client, &types.AllocateIpv4Address{
+			This:         *s.Client.ServiceContent.IpPoolManager,
+			Dc:           p.Datacenter,
+			PoolId:       p.Config.Id,
+			AllocationId: allocationID,
+		})
+		if err != nil {
+			if p.Config.AvailableIpv4Addresses == 0 {
+				return nil, ErrPoolExhausted
+			}
+			return nil, errors.Wrapf(err, "failed to allocate IPv4 address from IP pool %s", p.Config.Name)
+		}
+		return newAddress(res.Returnval, config, net.IPv4len)
+	}
+
+	if config := p.Config.Ipv6Config; isEnabled(config) {
+		res, err := methods.AllocateIpv6Address(ctx, s.Client.Client, &types.AllocateIpv6Address{
+			This:         *s.Client.ServiceContent.IpPoolManager,
+			Dc:           p.Datacenter,
+			PoolId:       p.Config.Id,
+			AllocationId: allocationID,
+		})
+		if err != nil {
+			if p.Config.AvailableIpv6Addresses == 0 {
+				return nil, ErrPoolExhausted
+			}
+			return nil, errors.Wrapf(err, "failed to allocate IPv6 address from IP pool %s", p.Config.Name)
+		}
+		return newAddress(res.Returnval, config, net.IPv6len)
+	}
+
+	return nil, errors.Errorf("IP pool %s has no IPv4 or IPv6 range enabled", p.Config.Name)
+}
+
+// Release releases the IP address allocated from the IP pool for the allocation ID.
+// It is a no-op if no IP address is allocated for the allocation ID or if the IP pool
+// doesn't exist anymore.
+func (p *Pool) Release(ctx context.Context, s *session.Session, allocationID string) error {
+	_, err := methods.ReleaseIpAllocation(ctx, s.Client.Client, &types.ReleaseIpAllocation{
+		This:         *s.Client.ServiceContent.IpPoolManager,
+		Dc:           p.Datacenter,
+		PoolId:       p.Config.Id,
+		AllocationId: allocationID,
+	})
+	if err != nil && !fault.Is(err, &types.InvalidArgument{}) {
+		return errors.Wrapf(err, "failed to release IP address allocation %s from IP pool %s", allocationID, p.Config.Name)
+	}
+	return nil
+}
+
+// isEnabled returns true if IP addresses can be allocated from the range of the configuration.
+func isEnabled(config *types.IpPoolIpPoolConfigInfo) bool {
+	return config != nil && config.Range != "" && ptr.Deref(config.IpPoolEnabled, true)
+}
+
+func newAddress(address string, config *types.IpPoolIpPoolConfigInfo, length int) (*Address, error) {
+	netmask := net.ParseIP(config.Netmask)
+	if netmask == nil {
+		return nil, errors.Errorf("invalid netmask %q", config.Netmask)
+	}
+	if length == net.IPv4len {
+		netmask = netmask.To4()
+	}
+	prefix, bits := net.IPMask(netmask).Size()
+	if bits == 0 {
+		return nil, errors.Errorf("invalid netmask %q", config.Netmask)
+	}
+	return &Address{
+		Address: address,
+		Prefix:  prefix,
+		Gateway: config.Gateway,
+	}, nil
+}
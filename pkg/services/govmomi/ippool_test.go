@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func Test_NetworkProtocolProfileAddresses(t *testing.T) {
+	tests := []struct {
+		name       string
+		allocation infrav1.NetworkProtocolProfileAllocation
+	}{
+		{
+			name:       "IP pool by name",
+			allocation: infrav1.NetworkProtocolProfileAllocation{IPPool: "ip-pool"},
+		},
+		{
+			name: "IP pool associated with the network",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			model := simulator.VPX()
+			g.Expect(model.Create()).To(Succeed())
+
+			simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+				authSession, err := getAuthSession(ctx, model.Service.Listen.Host)
+				g.Expect(err).ToNot(HaveOccurred())
+
+				finder := find.NewFinder(c)
+				dc, err := finder.Datacenter(ctx, "DC0")
+				g.Expect(err).ToNot(HaveOccurred())
+				network, err := finder.Network(ctx, "/DC0/network/VM Network")
+				g.Expect(err).ToNot(HaveOccurred())
+				networkRef := network.Reference()
+
+				_, err = methods.UpdateIpPool(ctx, c, &types.UpdateIpPool{
+					This: *c.ServiceContent.IpPoolManager,
+					Dc:   dc.Reference(),
+					Pool: types.IpPool{
+						Id:        1,
+						Name:      "ip-pool",
+						DnsDomain: "example.com",
+						Ipv4Config: &types.IpPoolIpPoolConfigInfo{
+							SubnetAddress: "10.10.10.0",
+							Netmask:       "255.255.255.0",
+							Gateway:       "10.10.10.1",
+							Range:         "10.10.10.2#10",
+							Dns:           []string{"10.10.10.53"},
+						},
+						NetworkAssociation: []types.IpPoolAssociation{{Network: &networkRef, NetworkName: "VM Network"}},
+					},
+				})
+				g.Expect(err).ToNot(HaveOccurred())
+
+				vmCtx := emptyVirtualMachineContext()
+				vmCtx.Session = authSession
+				vmCtx.State = &infrav1.VirtualMachine{Network: []infrav1.NetworkStatus{{MACAddr: "00:50:56:00:00:01"}}}
+				vmCtx.VSphereVM = &infrav1.VSphereVM{
+					ObjectMeta: metav1.ObjectMeta{UID: "vm-uid"},
+					Spec: infrav1.VSphereVMSpec{
+						VirtualMachineCloneSpec: infrav1.VirtualMachineCloneSpec{
+							Datacenter: "DC0",
+							Network: infrav1.NetworkSpec{
+								Devices: []infrav1.NetworkDeviceSpec{{
+									NetworkName:                       "VM Network",
+									AddressFromNetworkProtocolProfile: &tt.allocation,
+								}},
+							},
+						},
+					},
+				}
+
+				vms := &VMService{}
+				g.Expect(vms.reconcileNetworkProtocolProfileAddresses(ctx, vmCtx)).To(Succeed())
+				state := vmCtx.IPAMState["00:50:56:00:00:01"]
+				g.Expect(state.IPAddrs).To(HaveLen(1))
+				g.Expect(state.IPAddrs[0]).To(HavePrefix("10.10.10."))
+				g.Expect(state.IPAddrs[0]).To(HaveSuffix("/24"))
+				g.Expect(state.Gateway4).To(Equal("10.10.10.1"))
+				g.Expect(state.Nameservers).To(ConsistOf("10.10.10.53"))
+				g.Expect(state.SearchDomains).To(ConsistOf("example.com"))
+
+				// The same address is returned for the allocation.
+				address := state.IPAddrs[0]
+				vmCtx.IPAMState = nil
+				g.Expect(vms.reconcileNetworkProtocolProfileAddresses(ctx, vmCtx)).To(Succeed())
+				g.Expect(vmCtx.IPAMState["00:50:56:00:00:01"].IPAddrs).To(ConsistOf(address))
+				g.Expect(allocatedIPv4Addresses(ctx, g, c, dc.Reference())).To(Equal(int32(1)))
+
+				g.Expect(vms.releaseNetworkProtocolProfileAddresses(ctx, &vmCtx.VMContext)).To(Succeed())
+				g.Expect(allocatedIPv4Addresses(ctx, g, c, dc.Reference())).To(BeZero())
+				return nil
+			}, model)
+		})
+	}
+}
+
+func allocatedIPv4Addresses(ctx context.Context, g *WithT, c *vim25.Client, dc types.ManagedObjectReference) int32 {
+	res, err := methods.QueryIpPools(ctx, c, &types.QueryIpPools{This: *c.ServiceContent.IpPoolManager, Dc: dc})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(res.Returnval).To(HaveLen(1))
+	return res.Returnval[0].AllocatedIpv4Addresses
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/pkg/errors"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/cluster-api-provider-vsphere/internal/kubevip"
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+)
+
+// kubeVIPManifestPath is the path of the kube-vip static pod manifest on the control plane nodes.
+const kubeVIPManifestPath = "/etc/kubernetes/manifests/kube-vip.yaml"
+
+// addKubeVIP adds kube-vip to the bootstrap data of a control plane VM when the control plane
+// endpoint of the cluster is managed by CAPV. Bootstrap data which already contains a kube-vip
+// static pod, e.g. from the KubeadmConfigSpec of the control plane, is not changed.
+func addKubeVIP(ctx context.Context, vmCtx *capvcontext.VMContext, bootstrapData []byte, format bootstrapv1.Format) ([]byte, error) {
+	config := vmCtx.KubeVIP
+	if config == nil || len(bootstrapData) == 0 {
+		return bootstrapData, nil
+	}
+	if format != bootstrapv1.CloudConfig {
+		return nil, errors.Errorf("adding kube-vip is not supported for bootstrap data format %q", format)
+	}
+	if bytes.Contains(bootstrapData, []byte(kubeVIPManifestPath)) {
+		ctrl.LoggerFrom(ctx).V(4).Info("Bootstrap data already contains a kube-vip static pod, skipping adding kube-vip")
+		return bootstrapData, nil
+	}
+	return mergeKubeVIP(bootstrapData, *config)
+}
+
+// mergeKubeVIP merges the kube-vip files into the cloud-config and runs the kube-vip
+// prepare script before the commands of the cloud-config, i.e. before kubeadm.
+func mergeKubeVIP(cloudConfig []byte, config kubevip.Config) ([]byte, error) {
+	writeFiles, err := yaml.Marshal(kubevip.FilesFor(config))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal kube-vip files")
+	}
+	preCommands, err := yaml.Marshal([]string{kubevip.PrepareScriptPath})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal kube-vip commands")
+	}
+	return mergeCloudInitCustomization(cloudConfig, map[string]string{
+		CloudInitWriteFilesKey:  string(writeFiles),
+		CloudInitPreCommandsKey: string(preCommands),
+	})
+}
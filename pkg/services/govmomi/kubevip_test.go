@@ -0,0 +1,109 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/cluster-api-provider-vsphere/internal/kubevip"
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+)
+
+func Test_addKubeVIP(t *testing.T) {
+	config := &kubevip.Config{Address: "10.0.0.10", Port: 6443, Interface: "eth0"}
+
+	tests := []struct {
+		name          string
+		config        *kubevip.Config
+		bootstrapData string
+		format        bootstrapv1.Format
+		unchanged     bool
+		hasError      bool
+	}{
+		{
+			name:          "does not change the bootstrap data if the control plane endpoint is not managed",
+			bootstrapData: kubeadmCloudConfig,
+			format:        bootstrapv1.CloudConfig,
+			unchanged:     true,
+		},
+		{
+			name:   "does not change the bootstrap data which already contains kube-vip",
+			config: config,
+			bootstrapData: kubeadmCloudConfig + `write_files:
+- path: /etc/kubernetes/manifests/kube-vip.yaml
+`,
+			format:    bootstrapv1.CloudConfig,
+			unchanged: true,
+		},
+		{
+			name:          "fails for Ignition bootstrap data",
+			config:        config,
+			bootstrapData: `{"ignition":{"version":"3.1.0"}}`,
+			format:        bootstrapv1.Ignition,
+			hasError:      true,
+		},
+		{
+			name:          "adds kube-vip to cloud-config bootstrap data",
+			config:        config,
+			bootstrapData: kubeadmCloudConfig,
+			format:        bootstrapv1.CloudConfig,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			vmCtx := &capvcontext.VMContext{KubeVIP: tt.config}
+
+			out, err := addKubeVIP(context.Background(), vmCtx, []byte(tt.bootstrapData), tt.format)
+			if tt.hasError {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			if tt.unchanged {
+				g.Expect(string(out)).To(Equal(tt.bootstrapData))
+				return
+			}
+
+			g.Expect(string(out)).To(HavePrefix("## template: jinja\n#cloud-config\n"))
+			cloudConfig := struct {
+				WriteFiles []bootstrapv1.File `json:"write_files"`
+				RunCmd     []string           `json:"runcmd"`
+			}{}
+			g.Expect(yaml.Unmarshal(out, &cloudConfig)).To(Succeed())
+			g.Expect(cloudConfig.RunCmd).To(Equal([]string{kubevip.PrepareScriptPath, "kubeadm init --config /run/kubeadm/kubeadm.yaml"}))
+
+			var manifest string
+			for _, file := range cloudConfig.WriteFiles {
+				if file.Path == kubeVIPManifestPath {
+					manifest = file.Content
+				}
+			}
+			g.Expect(manifest).ToNot(BeEmpty())
+			g.Expect(manifest).ToNot(ContainSubstring("${"))
+			g.Expect(manifest).To(ContainSubstring("value: 10.0.0.10"))
+			g.Expect(manifest).To(ContainSubstring("value: eth0"))
+			g.Expect(strings.Count(string(out), kubevip.PrepareScriptPath)).To(Equal(2))
+		})
+	}
+}
@@ -0,0 +1,285 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nocloud
+
+import (
+	"encoding/binary"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	sectorSize = 2048
+
+	// systemAreaSectors is the number of sectors preceding the volume descriptors.
+	systemAreaSectors = 16
+
+	// pathTableSize is the size of a path table with only the root directory.
+	pathTableSize = 10
+
+	// dirRecordBaseSize is the size of a directory record without its name.
+	dirRecordBaseSize = 33
+
+	volumeDescriptorPrimary       = 1
+	volumeDescriptorSupplementary = 2
+	volumeDescriptorTerminator    = 255
+
+	dirFlagDirectory = 0x02
+)
+
+// isoFile is a file in the root directory of an ISO image.
+type isoFile struct {
+	Name string
+	Data []byte
+}
+
+// dirEntry is a file as recorded in a directory of one of the volume descriptors.
+type dirEntry struct {
+	name   []byte
+	extent uint32
+	size   uint32
+}
+
+// writeISO returns an ISO 9660 image labelled with the volume ID, containing the files in
+// its root directory. The file names are recorded in Joliet extensions, as the primary volume
+// descriptor only allows names of up to 8.3 upper-case characters. The names recorded in
+// the primary volume descriptor are truncated accordingly and must not collide.
+func writeISO(volumeID string, files []isoFile) []byte {
+	// The volume descriptors are followed by the path tables and the root directory of the
+	// primary and of the Joliet volume descriptor, and then by the data of the files.
+	const (
+		primaryVolumeDescriptorSector = systemAreaSectors + iota
+		jolietVolumeDescriptorSector
+		terminatorSector
+		primaryLPathTableSector
+		primaryMPathTableSector
+		jolietLPathTableSector
+		jolietMPathTableSector
+		primaryRootSector
+	)
+
+	primaryEntries := make([]dirEntry, 0, len(files))
+	jolietEntries := make([]dirEntry, 0, len(files))
+	for _, f := range files {
+		primaryEntries = append(primaryEntries, dirEntry{name: []byte(primaryFileName(f.Name)), size: uint32(len(f.Data))})
+		jolietEntries = append(jolietEntries, dirEntry{name: ucs2(f.Name), size: uint32(len(f.Data))})
+	}
+
+	primaryRootSize := directorySize(primaryEntries)
+	jolietRootSector := uint32(primaryRootSector) + primaryRootSize/sectorSize
+	jolietRootSize := directorySize(jolietEntries)
+
+	sector := jolietRootSector + jolietRootSize/sectorSize
+	for i, f := range files {
+		primaryEntries[i].extent = sector
+		jolietEntries[i].extent = sector
+		sector += sectors(len(f.Data))
+	}
+	totalSectors := sector
+
+	image := make([]byte, int(totalSectors)*sectorSize)
+	at := func(sector uint32) []byte {
+		return image[int(sector)*sectorSize:]
+	}
+
+	writeVolumeDescriptor(at(primaryVolumeDescriptorSector), volumeDescriptorPrimary, volumeID, totalSectors,
+		primaryLPathTableSector, primaryMPathTableSector, primaryRootSector, primaryRootSize)
+	writeVolumeDescriptor(at(jolietVolumeDescriptorSector), volumeDescriptorSupplementary, volumeID, totalSectors,
+		jolietLPathTableSector, jolietMPathTableSector, jolietRootSector, jolietRootSize)
+	writeVolumeDescriptorHeader(at(terminatorSector), volumeDescriptorTerminator)
+
+	writePathTable(at(primaryLPathTableSector), binary.LittleEndian, primaryRootSector)
+	writePathTable(at(primaryMPathTableSector), binary.BigEndian, primaryRootSector)
+	writePathTable(at(jolietLPathTableSector), binary.LittleEndian, jolietRootSector)
+	writePathTable(at(jolietMPathTableSector), binary.BigEndian, jolietRootSector)
+
+	writeDirectory(at(primaryRootSector), primaryRootSector, primaryRootSize, primaryEntries)
+	writeDirectory(at(jolietRootSector), jolietRootSector, jolietRootSize, jolietEntries)
+
+	for i, f := range files {
+		copy(at(primaryEntries[i].extent), f.Data)
+	}
+	return image
+}
+
+// writeVolumeDescriptorHeader writes the type, the standard identifier and the version of a volume descriptor.
+func writeVolumeDescriptorHeader(b []byte, descriptorType byte) {
+	b[0] = descriptorType
+	copy(b[1:6], "CD001")
+	b[6] = 1
+}
+
+// writeVolumeDescriptor writes a primary or a Joliet supplementary volume descriptor.
+func writeVolumeDescriptor(b []byte, descriptorType byte, volumeID string, totalSectors, lPathTableSector, mPathTableSector, rootSector, rootSize uint32) {
+	joliet := descriptorType == volumeDescriptorSupplementary
+	text := func(field []byte, s string) {
+		if joliet {
+			for i := 0; i+1 < len(field); i += 2 {
+				field[i], field[i+1] = 0x00, ' '
+			}
+			copy(field, ucs2(s))
+			return
+		}
+		for i := range field {
+			field[i] = ' '
+		}
+		copy(field, s)
+	}
+
+	writeVolumeDescriptorHeader(b, descriptorType)
+	text(b[8:40], "")        // System identifier.
+	text(b[40:72], volumeID) // Volume identifier.
+	putBothEndian32(b[80:88], totalSectors)
+	if joliet {
+		// The escape sequence of UCS-2 level 3.
+		copy(b[88:91], "%/E")
+	}
+	putBothEndian16(b[120:124], 1) // Volume set size.
+	putBothEndian16(b[124:128], 1) // Volume sequence number.
+	putBothEndian16(b[128:132], sectorSize)
+	putBothEndian32(b[132:140], pathTableSize)
+	binary.LittleEndian.PutUint32(b[140:144], lPathTableSector)
+	binary.BigEndian.PutUint32(b[148:152], mPathTableSector)
+	writeDirRecord(b[156:190], []byte{0}, rootSector, rootSize, dirFlagDirectory)
+	text(b[190:318], "") // Volume set identifier.
+	text(b[318:446], "") // Publisher identifier.
+	text(b[446:574], "") // Data preparer identifier.
+	text(b[574:702], "") // Application identifier.
+	text(b[702:739], "") // Copyright file identifier.
+	text(b[739:776], "") // Abstract file identifier.
+	text(b[776:813], "") // Bibliographic file identifier.
+	for _, offset := range []int{813, 830, 847, 864} {
+		// The creation, modification, expiration and effective dates are not specified.
+		copy(b[offset:offset+16], "0000000000000000")
+	}
+	b[881] = 1 // File structure version.
+}
+
+// writePathTable writes a path table containing only the root directory.
+func writePathTable(b []byte, order binary.ByteOrder, rootSector uint32) {
+	b[0] = 1 // Length of the directory identifier.
+	order.PutUint32(b[2:6], rootSector)
+	order.PutUint16(b[6:8], 1) // The root directory is its own parent.
+}
+
+// writeDirectory writes the directory records of a root directory, which are sorted by name.
+// Records never span sectors.
+func writeDirectory(b []byte, sector, size uint32, entries []dirEntry) {
+	sorted := sortedEntries(entries)
+
+	offset := writeDirRecord(b, []byte{0}, sector, size, dirFlagDirectory)
+	offset += writeDirRecord(b[offset:], []byte{1}, sector, size, dirFlagDirectory)
+	for _, e := range sorted {
+		if offset%sectorSize+dirRecordSize(e.name) > sectorSize {
+			offset += sectorSize - offset%sectorSize
+		}
+		offset += writeDirRecord(b[offset:], e.name, e.extent, e.size, 0)
+	}
+}
+
+// directorySize returns the size of a root directory with the entries, rounded up to whole sectors.
+func directorySize(entries []dirEntry) uint32 {
+	offset := 2 * dirRecordSize([]byte{0})
+	for _, e := range sortedEntries(entries) {
+		if offset%sectorSize+dirRecordSize(e.name) > sectorSize {
+			offset += sectorSize - offset%sectorSize
+		}
+		offset += dirRecordSize(e.name)
+	}
+	return sectors(offset) * sectorSize
+}
+
+func sortedEntries(entries []dirEntry) []dirEntry {
+	sorted := append([]dirEntry(nil), entries...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return string(sorted[i].name) < string(sorted[j].name)
+	})
+	return sorted
+}
+
+// dirRecordSize returns the size of a directory record with the name, which is padded to an even size.
+func dirRecordSize(name []byte) int {
+	size := dirRecordBaseSize + len(name)
+	return size + size%2
+}
+
+// writeDirRecord writes a directory record and returns its size.
+func writeDirRecord(b []byte, name []byte, extent, size uint32, flags byte) int {
+	recordSize := dirRecordSize(name)
+	b[0] = byte(recordSize)
+	putBothEndian32(b[2:10], extent)
+	putBothEndian32(b[10:18], size)
+	// The recording date is 1970-01-01 00:00:00 UTC, so that images only depend on their content.
+	copy(b[18:25], []byte{70, 1, 1, 0, 0, 0, 0})
+	b[25] = flags
+	putBothEndian16(b[28:32], 1) // Volume sequence number.
+	b[32] = byte(len(name))
+	copy(b[33:], name)
+	return recordSize
+}
+
+// primaryFileName returns the name of a file in the primary volume descriptor, i.e. the name
+// truncated to 8.3 upper-case letters, digits and underscores followed by the file version.
+func primaryFileName(name string) string {
+	base, ext := name, ""
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		base, ext = name[:i], name[i+1:]
+	}
+	dChars := func(s string, maxLen int) string {
+		s = strings.Map(func(r rune) rune {
+			switch {
+			case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+				return r
+			case r >= 'a' && r <= 'z':
+				return r - 'a' + 'A'
+			default:
+				return '_'
+			}
+		}, s)
+		if len(s) > maxLen {
+			s = s[:maxLen]
+		}
+		return s
+	}
+	return dChars(base, 8) + "." + dChars(ext, 3) + ";1"
+}
+
+// ucs2 returns the string encoded as big-endian UCS-2, as used by Joliet.
+func ucs2(s string) []byte {
+	encoded := utf16.Encode([]rune(s))
+	b := make([]byte, 2*len(encoded))
+	for i, c := range encoded {
+		binary.BigEndian.PutUint16(b[2*i:], c)
+	}
+	return b
+}
+
+// sectors returns the number of sectors needed to store size bytes.
+func sectors(size int) uint32 {
+	return uint32((size + sectorSize - 1) / sectorSize)
+}
+
+func putBothEndian16(b []byte, v uint16) {
+	binary.LittleEndian.PutUint16(b[0:2], v)
+	binary.BigEndian.PutUint16(b[2:4], v)
+}
+
+func putBothEndian32(b []byte, v uint32) {
+	binary.LittleEndian.PutUint32(b[0:4], v)
+	binary.BigEndian.PutUint32(b[4:8], v)
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nocloud
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWriteISOWithMultiSectorDirectory(t *testing.T) {
+	g := NewWithT(t)
+
+	// The directory records of that many files span multiple sectors, their
+	// names in the primary volume descriptor are unique after the truncation.
+	files := []isoFile{}
+	want := map[string]string{}
+	for i := range 100 {
+		name := fmt.Sprintf("%03d-file-with-a-long-name", i)
+		files = append(files, isoFile{Name: name, Data: []byte(name)})
+		want[name] = name
+	}
+	files = append(files, isoFile{Name: "empty"})
+	want["empty"] = ""
+
+	image := writeISO(VolumeID, files)
+	g.Expect(directorySize(make([]dirEntry, 100))).To(BeNumerically(">", sectorSize))
+	g.Expect(jolietFiles(g, image)).To(Equal(want))
+}
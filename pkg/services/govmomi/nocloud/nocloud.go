@@ -0,0 +1,63 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package nocloud contains the NoCloud ISO images which deliver the bootstrap data and the
+// metadata to virtual machines which can't read them from the guestinfo.
+package nocloud
+
+import (
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// VolumeID is the volume ID by which cloud-init detects NoCloud ISO images.
+	VolumeID = "cidata"
+
+	userDataFileName      = "user-data"
+	metaDataFileName      = "meta-data"
+	networkConfigFileName = "network-config"
+)
+
+// ISO returns a NoCloud ISO image with the user data and the metadata, which has the format
+// of the metadata of the VMware datasource of cloud-init. The network configuration is moved
+// from the metadata to the network-config file, as the NoCloud datasource doesn't read it
+// from the metadata.
+func ISO(userData, metadata []byte) ([]byte, error) {
+	metadataMap := map[string]interface{}{}
+	if err := yaml.Unmarshal(metadata, &metadataMap); err != nil {
+		return nil, errors.Wrap(err, "failed to parse metadata")
+	}
+
+	files := []isoFile{{Name: userDataFileName, Data: userData}}
+
+	if network, ok := metadataMap["network"]; ok {
+		delete(metadataMap, "network")
+		networkConfig, err := yaml.Marshal(network)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal network configuration")
+		}
+		files = append(files, isoFile{Name: networkConfigFileName, Data: networkConfig})
+	}
+
+	metaData, err := yaml.Marshal(metadataMap)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal metadata")
+	}
+	files = append(files, isoFile{Name: metaDataFileName, Data: metaData})
+
+	return writeISO(VolumeID, files), nil
+}
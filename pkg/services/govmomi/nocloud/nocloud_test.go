@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nocloud
+
+import (
+	"encoding/binary"
+	"testing"
+	"unicode/utf16"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestISO(t *testing.T) {
+	g := NewWithT(t)
+
+	userData := []byte("#cloud-config\nruncmd:\n- echo hello\n")
+	metadata := []byte(`instance-id: "vm-1"
+local-hostname: "vm-1"
+wait-on-network:
+  ipv4: true
+  ipv6: false
+network:
+  version: 2
+  ethernets:
+    id0:
+      match:
+        macaddress: "00:50:56:a0:00:01"
+      set-name: "eth0"
+      dhcp4: true
+`)
+
+	image, err := ISO(userData, metadata)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(len(image) % sectorSize).To(Equal(0))
+
+	// The primary volume descriptor carries the volume ID cloud-init looks for.
+	primary := image[systemAreaSectors*sectorSize:]
+	g.Expect(primary[0]).To(Equal(byte(volumeDescriptorPrimary)))
+	g.Expect(string(primary[1:6])).To(Equal("CD001"))
+	g.Expect(string(primary[40:46])).To(Equal(VolumeID))
+
+	files := jolietFiles(g, image)
+	g.Expect(files).To(HaveLen(3))
+	g.Expect(files).To(HaveKeyWithValue(userDataFileName, string(userData)))
+	g.Expect(files).To(HaveKeyWithValue(metaDataFileName, `instance-id: vm-1
+local-hostname: vm-1
+wait-on-network:
+  ipv4: true
+  ipv6: false
+`))
+	g.Expect(files).To(HaveKeyWithValue(networkConfigFileName, `ethernets:
+  id0:
+    dhcp4: true
+    match:
+      macaddress: 00:50:56:a0:00:01
+    set-name: eth0
+version: 2
+`))
+}
+
+func TestISOWithoutNetwork(t *testing.T) {
+	g := NewWithT(t)
+
+	image, err := ISO([]byte("#cloud-config\n"), []byte(`instance-id: "vm-1"`))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	files := jolietFiles(g, image)
+	g.Expect(files).To(HaveLen(2))
+	g.Expect(files).ToNot(HaveKey(networkConfigFileName))
+}
+
+func TestISOWithInvalidMetadata(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := ISO([]byte("#cloud-config\n"), []byte("network: ["))
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestPrimaryFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "user-data", want: "USER_DAT.;1"},
+		{name: "meta-data", want: "META_DAT.;1"},
+		{name: "network-config", want: "NETWORK_.;1"},
+		{name: "vendor.data", want: "VENDOR.DAT;1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(primaryFileName(tt.name)).To(Equal(tt.want))
+		})
+	}
+}
+
+// jolietFiles returns the content of the files in the root directory of the Joliet volume descriptor by name.
+func jolietFiles(g *WithT, image []byte) map[string]string {
+	joliet := image[(systemAreaSectors+1)*sectorSize:]
+	g.Expect(joliet[0]).To(Equal(byte(volumeDescriptorSupplementary)))
+	g.Expect(string(joliet[88:91])).To(Equal("%/E"))
+
+	rootRecord := joliet[156:190]
+	rootExtent := binary.LittleEndian.Uint32(rootRecord[2:6])
+	rootSize := binary.LittleEndian.Uint32(rootRecord[10:14])
+	dir := image[rootExtent*sectorSize : rootExtent*sectorSize+rootSize]
+
+	files := map[string]string{}
+	for offset := 0; offset < len(dir); {
+		recordSize := int(dir[offset])
+		if recordSize == 0 {
+			// The rest of the sector is padding.
+			offset += sectorSize - offset%sectorSize
+			continue
+		}
+		record := dir[offset : offset+recordSize]
+		offset += recordSize
+		if record[25]&dirFlagDirectory != 0 {
+			continue
+		}
+
+		nameBytes := record[33 : 33+int(record[32])]
+		name := make([]uint16, len(nameBytes)/2)
+		for i := range name {
+			name[i] = binary.BigEndian.Uint16(nameBytes[2*i:])
+		}
+		extent := binary.LittleEndian.Uint32(record[2:6])
+		size := binary.LittleEndian.Uint32(record[10:14])
+		files[string(utf16.Decode(name))] = string(image[extent*sectorSize : extent*sectorSize+size])
+	}
+	return files
+}
@@ -30,12 +30,27 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
 )
 
+// getPowerState returns the power state of the VM, which may be read from the property cache of the session.
+// Use getCurrentPowerState to confirm the power state before changing it, as the cached power state can
+// lag behind power operations which just completed.
 func (vms *VMService) getPowerState(ctx context.Context, virtualMachineCtx *virtualMachineContext) (infrav1.VirtualMachinePowerState, error) {
+	var vm mo.VirtualMachine
+	if err := getVMProperties(ctx, virtualMachineCtx, []string{"runtime.powerState"}, &vm); err != nil {
+		return "", err
+	}
+	return toVirtualMachinePowerState(virtualMachineCtx, vm.Runtime.PowerState)
+}
+
+// getCurrentPowerState returns the power state of the VM retrieved from vCenter.
+func (vms *VMService) getCurrentPowerState(ctx context.Context, virtualMachineCtx *virtualMachineContext) (infrav1.VirtualMachinePowerState, error) {
 	powerState, err := virtualMachineCtx.Obj.PowerState(ctx)
 	if err != nil {
 		return "", err
 	}
+	return toVirtualMachinePowerState(virtualMachineCtx, powerState)
+}
 
+func toVirtualMachinePowerState(virtualMachineCtx *virtualMachineContext, powerState types.VirtualMachinePowerState) (infrav1.VirtualMachinePowerState, error) {
 	switch powerState {
 	case types.VirtualMachinePowerStatePoweredOn:
 		return infrav1.VirtualMachinePowerStatePoweredOn, nil
@@ -106,7 +121,7 @@ func (vms *VMService) triggerSoftPowerOff(ctx context.Context, virtualMachineCtx
 	}
 
 	var o mo.VirtualMachine
-	if err := virtualMachineCtx.Obj.Properties(ctx, virtualMachineCtx.Obj.Reference(), []string{"guest.guestStateChangeSupported"}, &o); err != nil {
+	if err := getVMProperties(ctx, virtualMachineCtx, []string{"guest.guestStateChangeSupported"}, &o); err != nil {
 		return false, err
 	}
 
@@ -0,0 +1,180 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package privileges checks the privileges of vCenter sessions on the objects used by CAPV.
+package privileges
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/session"
+)
+
+// Kind is the kind of vCenter object privileges are required on.
+type Kind string
+
+const (
+	// Datacenter is the datacenter the VMs are created in.
+	Datacenter Kind = "Datacenter"
+
+	// ComputeCluster is the compute cluster the VMs are placed on.
+	ComputeCluster Kind = "ComputeCluster"
+
+	// ResourcePool is the resource pool the VMs are created in.
+	ResourcePool Kind = "ResourcePool"
+
+	// Datastore is a datastore the disks of the VMs are placed on.
+	Datastore Kind = "Datastore"
+
+	// Network is a network the VMs are connected to.
+	Network Kind = "Network"
+
+	// Folder is the folder the VMs are created in.
+	Folder Kind = "Folder"
+)
+
+// Required are the minimum privileges CAPV requires per kind of vCenter object.
+// Privileges granted on a parent object and propagated to the object are taken into account.
+var Required = map[Kind][]string{
+	Datacenter: {
+		"System.Read",
+		"InventoryService.Tagging.AttachTag",
+		"VirtualMachine.Provisioning.DeployTemplate",
+		"VirtualMachine.Provisioning.Clone",
+	},
+	ComputeCluster: {
+		"System.Read",
+		"Resource.AssignVMToPool",
+	},
+	ResourcePool: {
+		"Resource.AssignVMToPool",
+	},
+	Datastore: {
+		"Datastore.AllocateSpace",
+		"Datastore.Browse",
+		"Datastore.FileManagement",
+	},
+	Network: {
+		"Network.Assign",
+	},
+	Folder: {
+		"VirtualMachine.Inventory.CreateFromExisting",
+		"VirtualMachine.Inventory.Delete",
+		"VirtualMachine.Config.AddNewDisk",
+		"VirtualMachine.Config.AdvancedConfig",
+		"VirtualMachine.Config.CPUCount",
+		"VirtualMachine.Config.EditDevice",
+		"VirtualMachine.Config.Memory",
+		"VirtualMachine.Config.Settings",
+		"VirtualMachine.Interact.PowerOff",
+		"VirtualMachine.Interact.PowerOn",
+	},
+}
+
+// Entity is a vCenter object privileges are required on.
+type Entity struct {
+	// Kind is the kind of the object.
+	Kind Kind
+
+	// Path is the inventory path of the object.
+	Path string
+
+	// Ref is the reference to the object.
+	Ref types.ManagedObjectReference
+}
+
+// Missing are the privileges missing on a vCenter object.
+type Missing struct {
+	Entity
+
+	// Privileges are the IDs of the missing privileges.
+	Privileges []string
+}
+
+// Check returns the required privileges the user of the session is missing on the entities.
+func Check(ctx context.Context, s *session.Session, entities []Entity) ([]Missing, error) {
+	if len(entities) == 0 {
+		return nil, nil
+	}
+
+	userSession, err := s.SessionManager.UserSession(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the user session")
+	}
+	if userSession == nil {
+		return nil, errors.New("failed to get the user session: not authenticated")
+	}
+
+	refs := make([]types.ManagedObjectReference, 0, len(entities))
+	privIDs := sets.New[string]()
+	for _, entity := range entities {
+		refs = append(refs, entity.Ref)
+		privIDs.Insert(Required[entity.Kind]...)
+	}
+
+	res, err := methods.HasPrivilegeOnEntities(ctx, s.Client.Client, &types.HasPrivilegeOnEntities{
+		This:      *s.Client.ServiceContent.AuthorizationManager,
+		Entity:    refs,
+		SessionId: userSession.Key,
+		PrivId:    sets.List(privIDs),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check privileges")
+	}
+	return missingPrivileges(entities, res.Returnval), nil
+}
+
+// missingPrivileges returns the required privileges of the entities which are not granted.
+func missingPrivileges(entities []Entity, granted []types.EntityPrivilege) []Missing {
+	grantedByRef := map[types.ManagedObjectReference]sets.Set[string]{}
+	for _, entityPrivilege := range granted {
+		privileges := sets.New[string]()
+		for _, availability := range entityPrivilege.PrivAvailability {
+			if availability.IsGranted {
+				privileges.Insert(availability.PrivId)
+			}
+		}
+		grantedByRef[entityPrivilege.Entity] = privileges
+	}
+
+	var missing []Missing
+	for _, entity := range entities {
+		required := sets.New(Required[entity.Kind]...)
+		notGranted := required.Difference(grantedByRef[entity.Ref])
+		if notGranted.Len() > 0 {
+			missing = append(missing, Missing{Entity: entity, Privileges: sets.List(notGranted)})
+		}
+	}
+	return missing
+}
+
+// Report returns a human readable report of the missing privileges.
+func Report(missing []Missing) string {
+	lines := make([]string, 0, len(missing))
+	for _, m := range missing {
+		lines = append(lines, fmt.Sprintf("%s %s: %s", m.Kind, m.Path, strings.Join(m.Privileges, ", ")))
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "; ")
+}
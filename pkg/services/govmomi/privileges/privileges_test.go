@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privileges
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/simulator"
+	_ "github.com/vmware/govmomi/vapi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/session"
+)
+
+func TestCheck(t *testing.T) {
+	model := simulator.VPX()
+	if err := model.Create(); err != nil {
+		t.Fatal(err)
+	}
+	defer model.Remove()
+
+	simulator.Run(func(ctx context.Context, _ *vim25.Client) error {
+		g := NewWithT(t)
+
+		password, _ := simulator.DefaultLogin.Password()
+		s, err := session.GetOrCreate(ctx, session.NewParams().
+			WithServer(fmt.Sprintf("http://%s", model.Service.Listen.Host)).
+			WithUserInfo(simulator.DefaultLogin.Username(), password).
+			WithDatacenter("*"))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		ds, err := s.Finder.Datastore(ctx, "LocalDS_0")
+		g.Expect(err).ToNot(HaveOccurred())
+		folder, err := s.Finder.Folder(ctx, "/DC0/vm")
+		g.Expect(err).ToNot(HaveOccurred())
+
+		missing, err := Check(ctx, s, []Entity{
+			{Kind: Datastore, Path: ds.InventoryPath, Ref: ds.Reference()},
+			{Kind: Folder, Path: folder.InventoryPath, Ref: folder.Reference()},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		// vcsim grants all privileges.
+		g.Expect(missing).To(BeEmpty())
+		return nil
+	}, model)
+}
+
+func TestMissingPrivileges(t *testing.T) {
+	g := NewWithT(t)
+
+	datastore := Entity{Kind: Datastore, Path: "/DC0/datastore/ds-1", Ref: types.ManagedObjectReference{Type: "Datastore", Value: "datastore-1"}}
+	network := Entity{Kind: Network, Path: "/DC0/network/VM Network", Ref: types.ManagedObjectReference{Type: "Network", Value: "network-1"}}
+
+	missing := missingPrivileges([]Entity{datastore, network}, []types.EntityPrivilege{
+		{
+			Entity: datastore.Ref,
+			PrivAvailability: []types.PrivilegeAvailability{
+				{PrivId: "Datastore.AllocateSpace", IsGranted: true},
+				{PrivId: "Datastore.Browse", IsGranted: false},
+				{PrivId: "Network.Assign", IsGranted: true},
+			},
+		},
+		{
+			Entity: network.Ref,
+			PrivAvailability: []types.PrivilegeAvailability{
+				{PrivId: "Datastore.AllocateSpace", IsGranted: false},
+				{PrivId: "Network.Assign", IsGranted: true},
+			},
+		},
+	})
+	g.Expect(missing).To(Equal([]Missing{
+		{Entity: datastore, Privileges: []string{"Datastore.Browse", "Datastore.FileManagement"}},
+	}))
+	g.Expect(Report(missing)).To(Equal("Datastore /DC0/datastore/ds-1: Datastore.Browse, Datastore.FileManagement"))
+}
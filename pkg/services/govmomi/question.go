@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+)
+
+// reconcileQuestionOfInFlightTask checks whether the VM of a VSphereVM with an in-flight task,
+// e.g. a power on, is blocked on a question, which prevents the task from completing.
+func (vms *VMService) reconcileQuestionOfInFlightTask(ctx context.Context, vmCtx *capvcontext.VMContext) error {
+	// Questions are only asked for VMs which have been created, i.e. not while cloning.
+	if vmCtx.VSphereVM.Spec.BiosUUID == "" {
+		return nil
+	}
+
+	vmRef, err := findVM(ctx, vmCtx)
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	_, err = vms.reconcileQuestion(ctx, &virtualMachineContext{
+		VMContext: *vmCtx,
+		Obj:       object.NewVirtualMachine(vmCtx.Session.Client.Client, vmRef),
+		Ref:       vmRef,
+	})
+	return err
+}
+
+// reconcileQuestion answers the question the VM is blocked on, if any, using the questionAnswers
+// of the spec. Questions which can't be answered are reported in the VMProvisioned condition.
+// It returns false if the VM is blocked on a question.
+func (vms *VMService) reconcileQuestion(ctx context.Context, virtualMachineCtx *virtualMachineContext) (bool, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	var o mo.VirtualMachine
+	if err := getVMProperties(ctx, virtualMachineCtx, []string{"runtime.question"}, &o); err != nil {
+		return false, errors.Wrapf(err, "failed to get pending question of vm %s", virtualMachineCtx)
+	}
+	question := o.Runtime.Question
+	if question == nil {
+		return true, nil
+	}
+
+	messageIDs := questionMessageIDs(question)
+	log = log.WithValues("questionID", question.Id, "messageIDs", messageIDs)
+
+	choice, ok := questionChoice(virtualMachineCtx.VSphereVM.Spec.QuestionAnswers, question)
+	if !ok {
+		reason := infrav1.VMQuestionPendingReason
+		if isOutOfSpaceQuestion(messageIDs) {
+			reason = infrav1.DatastoreOutOfSpaceReason
+		}
+		log.Info("VM is blocked on a question")
+		conditions.MarkFalse(virtualMachineCtx.VSphereVM, infrav1.VMProvisionedCondition, reason, clusterv1.ConditionSeverityWarning,
+			"VM is blocked on question %q: %s", strings.Join(messageIDs, ", "), question.Text)
+		return false, errors.Errorf("vm %s is blocked on question %q which must be answered in vCenter: %s", virtualMachineCtx, strings.Join(messageIDs, ", "), question.Text)
+	}
+
+	if skipForDryRun(ctx, virtualMachineCtx, "question answer", "questionID", question.Id, "choice", choice) {
+		return false, nil
+	}
+
+	log.Info("Answering VM question", "choice", choice)
+	if err := virtualMachineCtx.Obj.Answer(ctx, question.Id, choice); err != nil {
+		return false, errors.Wrapf(err, "failed to answer question %q of vm %s", question.Id, virtualMachineCtx)
+	}
+	return false, nil
+}
+
+// questionMessageIDs returns the IDs of the messages of a question.
+func questionMessageIDs(question *types.VirtualMachineQuestionInfo) []string {
+	messageIDs := make([]string, 0, len(question.Message))
+	for _, message := range question.Message {
+		messageIDs = append(messageIDs, message.Id)
+	}
+	return messageIDs
+}
+
+// questionChoice returns the key of the choice answering the question according to the answers,
+// matching the choice of an answer against both the key and the label of the choices of the question.
+func questionChoice(answers []infrav1.VirtualMachineQuestionAnswer, question *types.VirtualMachineQuestionInfo) (string, bool) {
+	for _, answer := range answers {
+		asked := false
+		for _, message := range question.Message {
+			if message.Id == answer.MessageID {
+				asked = true
+				break
+			}
+		}
+		if !asked {
+			continue
+		}
+
+		for _, choiceInfo := range question.Choice.ChoiceInfo {
+			description := choiceInfo.GetElementDescription()
+			if description.Key == answer.Choice || strings.EqualFold(strings.ReplaceAll(description.Label, "_", ""), answer.Choice) {
+				return description.Key, true
+			}
+		}
+	}
+	return "", false
+}
+
+// isOutOfSpaceQuestion returns true if a question is asked because a datastore ran out of space,
+// e.g. "msg.hbacommon.outofspace".
+func isOutOfSpaceQuestion(messageIDs []string) bool {
+	for _, id := range messageIDs {
+		if strings.Contains(strings.ToLower(id), "outofspace") {
+			return true
+		}
+	}
+	return false
+}
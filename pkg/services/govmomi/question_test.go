@@ -0,0 +1,154 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func uuidAlteredQuestion() *types.VirtualMachineQuestionInfo {
+	return &types.VirtualMachineQuestionInfo{
+		Id:   "1",
+		Text: "This virtual machine might have been moved or copied.",
+		Choice: types.ChoiceOption{
+			ChoiceInfo: []types.BaseElementDescription{
+				&types.ElementDescription{Key: "0", Description: types.Description{Label: "button.uuid.cancel"}},
+				&types.ElementDescription{Key: "1", Description: types.Description{Label: "button.uuid.movedTheVM"}},
+				&types.ElementDescription{Key: "2", Description: types.Description{Label: "button.uuid.copiedTheVM"}},
+			},
+		},
+		Message: []types.VirtualMachineMessage{{Id: "msg.uuid.altered"}},
+	}
+}
+
+func Test_questionChoice(t *testing.T) {
+	tests := []struct {
+		name       string
+		answers    []infrav1.VirtualMachineQuestionAnswer
+		wantChoice string
+		wantOK     bool
+	}{
+		{
+			name:   "no answers",
+			wantOK: false,
+		},
+		{
+			name:    "answer for another message",
+			answers: []infrav1.VirtualMachineQuestionAnswer{{MessageID: "msg.hbacommon.outofspace", Choice: "0"}},
+			wantOK:  false,
+		},
+		{
+			name:       "answer by key",
+			answers:    []infrav1.VirtualMachineQuestionAnswer{{MessageID: "msg.uuid.altered", Choice: "2"}},
+			wantChoice: "2",
+			wantOK:     true,
+		},
+		{
+			name:       "answer by label",
+			answers:    []infrav1.VirtualMachineQuestionAnswer{{MessageID: "msg.uuid.altered", Choice: "button.uuid.movedTheVM"}},
+			wantChoice: "1",
+			wantOK:     true,
+		},
+		{
+			name:    "answer with an unknown choice",
+			answers: []infrav1.VirtualMachineQuestionAnswer{{MessageID: "msg.uuid.altered", Choice: "button.uuid.unknown"}},
+			wantOK:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			choice, ok := questionChoice(tt.answers, uuidAlteredQuestion())
+			g.Expect(ok).To(Equal(tt.wantOK))
+			g.Expect(choice).To(Equal(tt.wantChoice))
+		})
+	}
+}
+
+func Test_reconcileQuestion(t *testing.T) {
+	tests := []struct {
+		name         string
+		question     *types.VirtualMachineQuestionInfo
+		wantOK       bool
+		expectReason string
+	}{
+		{
+			name:     "no pending question",
+			question: nil,
+			wantOK:   true,
+		},
+		{
+			name:         "pending question without answer",
+			question:     uuidAlteredQuestion(),
+			wantOK:       false,
+			expectReason: infrav1.VMQuestionPendingReason,
+		},
+		{
+			name: "datastore out of space",
+			question: &types.VirtualMachineQuestionInfo{
+				Id:      "2",
+				Text:    "There is no more space for virtual disk vm.vmdk.",
+				Message: []types.VirtualMachineMessage{{Id: "msg.hbacommon.outofspace"}},
+			},
+			wantOK:       false,
+			expectReason: infrav1.DatastoreOutOfSpaceReason,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+				finder := find.NewFinder(c)
+				vm, err := finder.VirtualMachine(ctx, "DC0_H0_VM0")
+				g.Expect(err).ToNot(HaveOccurred())
+
+				simulator.Map.Get(vm.Reference()).(*simulator.VirtualMachine).Runtime.Question = tt.question
+
+				vmCtx := emptyVirtualMachineContext()
+				vmCtx.Obj = vm
+				vmCtx.VSphereVM = &infrav1.VSphereVM{}
+
+				vms := &VMService{}
+				ok, err := vms.reconcileQuestion(ctx, vmCtx)
+				g.Expect(ok).To(Equal(tt.wantOK))
+				if tt.expectReason == "" {
+					g.Expect(err).ToNot(HaveOccurred())
+					g.Expect(conditions.Has(vmCtx.VSphereVM, infrav1.VMProvisionedCondition)).To(BeFalse())
+					return nil
+				}
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(conditions.GetReason(vmCtx.VSphereVM, infrav1.VMProvisionedCondition)).To(Equal(tt.expectReason))
+				g.Expect(conditions.GetMessage(vmCtx.VSphereVM, infrav1.VMProvisionedCondition)).To(ContainSubstring(tt.question.Text))
+				return nil
+			})
+		})
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/tracing"
+)
+
+// relocateVMTaskID is the description id of the task relocating a VM.
+const relocateVMTaskID = "VirtualMachine.relocate"
+
+// reconcileRelocation relocates the VM to the targets defined in spec.relocateTo using a
+// storage and/or compute vMotion. It returns true if the VM is located at the targets.
+func (vms *VMService) reconcileRelocation(ctx context.Context, virtualMachineCtx *virtualMachineContext) (bool, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	vsphereVM := virtualMachineCtx.VSphereVM
+	relocateTo := vsphereVM.Spec.RelocateTo
+	if relocateTo == nil {
+		return true, nil
+	}
+	log = log.WithValues("datastore", relocateTo.Datastore, "host", relocateTo.Host, "resourcePool", relocateTo.ResourcePool)
+
+	// Start tracking a new relocation whenever the targets change.
+	if vsphereVM.Status.Relocation == nil || vsphereVM.Status.Relocation.Target != *relocateTo {
+		vsphereVM.Status.Relocation = &infrav1.VSphereVMRelocationStatus{Target: *relocateTo}
+	}
+
+	spec, err := getRelocateSpec(ctx, virtualMachineCtx, *relocateTo)
+	if err != nil {
+		conditions.MarkFalse(vsphereVM, infrav1.VMRelocatedCondition, infrav1.RelocationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return false, err
+	}
+
+	// Nothing to do if the VM is already located at the targets.
+	if spec == nil {
+		if vsphereVM.Status.Relocation.CompletionTime == nil {
+			log.Info("VM is relocated")
+			vsphereVM.Status.Relocation.Progress = 100
+			vsphereVM.Status.Relocation.CompletionTime = &metav1.Time{Time: time.Now()}
+		}
+		conditions.MarkTrue(vsphereVM, infrav1.VMRelocatedCondition)
+		return true, nil
+	}
+
+	if skipForDryRun(ctx, virtualMachineCtx, "relocation", "datastore", relocateTo.Datastore, "host", relocateTo.Host, "resourcePool", relocateTo.ResourcePool) {
+		return true, nil
+	}
+
+	log.Info("Relocating VM")
+	task, err := virtualMachineCtx.Obj.Relocate(ctx, *spec, types.VirtualMachineMovePriorityDefaultPriority)
+	if err != nil {
+		conditions.MarkFalse(vsphereVM, infrav1.VMRelocatedCondition, infrav1.RelocationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return false, errors.Wrapf(err, "failed to trigger relocate op for vm %s", virtualMachineCtx)
+	}
+	conditions.MarkFalse(vsphereVM, infrav1.VMRelocatedCondition, infrav1.RelocatingReason, clusterv1.ConditionSeverityInfo, "")
+	vsphereVM.Status.Relocation.Progress = 0
+	vsphereVM.Status.Relocation.CompletionTime = nil
+	tracing.SetTaskDescription(ctx, task)
+	vsphereVM.Status.TaskRef = task.Reference().Value
+
+	log.Info("Wait for VM to be relocated")
+	return false, nil
+}
+
+// getRelocateSpec returns the spec for relocating the VM to the given targets, or nil if the
+// VM is already located at the targets.
+func getRelocateSpec(ctx context.Context, virtualMachineCtx *virtualMachineContext, relocateTo infrav1.VSphereVMRelocateTo) (*types.VirtualMachineRelocateSpec, error) {
+	var vm mo.VirtualMachine
+	if err := virtualMachineCtx.Obj.Properties(ctx, virtualMachineCtx.Ref, []string{"datastore", "resourcePool", "runtime.host"}, &vm); err != nil {
+		return nil, errors.Wrapf(err, "failed to get placement of vm %s", virtualMachineCtx)
+	}
+
+	finder := virtualMachineCtx.Session.Finder
+	spec := &types.VirtualMachineRelocateSpec{}
+	relocate := false
+
+	if relocateTo.Datastore != "" {
+		datastore, err := finder.Datastore(ctx, relocateTo.Datastore)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to find datastore %s to relocate vm %s to", relocateTo.Datastore, virtualMachineCtx)
+		}
+		spec.Datastore = types.NewReference(datastore.Reference())
+		if len(vm.Datastore) != 1 || vm.Datastore[0] != *spec.Datastore {
+			relocate = true
+		}
+	}
+
+	if relocateTo.Host != "" {
+		host, err := finder.HostSystem(ctx, relocateTo.Host)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to find host %s to relocate vm %s to", relocateTo.Host, virtualMachineCtx)
+		}
+		spec.Host = types.NewReference(host.Reference())
+		if vm.Runtime.Host == nil || *vm.Runtime.Host != *spec.Host {
+			relocate = true
+		}
+	}
+
+	if relocateTo.ResourcePool != "" {
+		pool, err := finder.ResourcePool(ctx, relocateTo.ResourcePool)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to find resource pool %s to relocate vm %s to", relocateTo.ResourcePool, virtualMachineCtx)
+		}
+		spec.Pool = types.NewReference(pool.Reference())
+		if vm.ResourcePool == nil || *vm.ResourcePool != *spec.Pool {
+			relocate = true
+		}
+	}
+
+	if !relocate {
+		return nil, nil
+	}
+	return spec, nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func Test_reconcileRelocation(t *testing.T) {
+	g := NewWithT(t)
+	model := simulator.VPX()
+	model.Datastore = 2
+	g.Expect(model.Create()).To(Succeed())
+
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		authSession, err := getAuthSession(ctx, model.Service.Listen.Host)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		finder := find.NewFinder(c)
+		vm, err := finder.VirtualMachine(ctx, "DC0_C0_RP0_VM0")
+		g.Expect(err).ToNot(HaveOccurred())
+
+		vmCtx := emptyVirtualMachineContext()
+		vmCtx.Session = authSession
+		vmCtx.Obj = vm
+		vmCtx.Ref = vm.Reference()
+		vmCtx.VSphereVM = &infrav1.VSphereVM{}
+
+		vms := &VMService{}
+
+		t.Run("without relocateTo", func(*testing.T) {
+			ok, err := vms.reconcileRelocation(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeTrue())
+			g.Expect(vmCtx.VSphereVM.Status.Relocation).To(BeNil())
+			g.Expect(conditions.Has(vmCtx.VSphereVM, infrav1.VMRelocatedCondition)).To(BeFalse())
+		})
+
+		t.Run("with an unknown datastore", func(*testing.T) {
+			vmCtx.VSphereVM.Spec.RelocateTo = &infrav1.VSphereVMRelocateTo{Datastore: "unknown"}
+			_, err := vms.reconcileRelocation(ctx, vmCtx)
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(conditions.GetReason(vmCtx.VSphereVM, infrav1.VMRelocatedCondition)).To(Equal(infrav1.RelocationFailedReason))
+		})
+
+		t.Run("with a new host and datastore", func(*testing.T) {
+			vmCtx.VSphereVM.Spec.RelocateTo = &infrav1.VSphereVMRelocateTo{Datastore: "LocalDS_1", Host: "DC0_C0_H2"}
+			vmCtx.VSphereVM.Status.TaskRef = ""
+
+			ok, err := vms.reconcileRelocation(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeFalse())
+			g.Expect(vmCtx.VSphereVM.Status.TaskRef).ToNot(BeEmpty())
+			g.Expect(vmCtx.VSphereVM.Status.Relocation.Target).To(Equal(*vmCtx.VSphereVM.Spec.RelocateTo))
+			g.Expect(conditions.GetReason(vmCtx.VSphereVM, infrav1.VMRelocatedCondition)).To(Equal(infrav1.RelocatingReason))
+
+			task := object.NewTask(c, types.ManagedObjectReference{Type: morefTypeTask, Value: vmCtx.VSphereVM.Status.TaskRef})
+			g.Expect(task.Wait(ctx)).To(Succeed())
+
+			ok, err = vms.reconcileRelocation(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeTrue())
+			g.Expect(vmCtx.VSphereVM.Status.Relocation.Progress).To(BeEquivalentTo(100))
+			g.Expect(vmCtx.VSphereVM.Status.Relocation.CompletionTime).ToNot(BeNil())
+			g.Expect(conditions.IsTrue(vmCtx.VSphereVM, infrav1.VMRelocatedCondition)).To(BeTrue())
+
+			host, err := vm.HostSystem(ctx)
+			g.Expect(err).ToNot(HaveOccurred())
+			hostName, err := host.ObjectName(ctx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(hostName).To(Equal("DC0_C0_H2"))
+		})
+		return nil
+	}, model)
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/tracing"
+)
+
+// reconcileResize reconfigures the CPU and memory of the VM to match the spec if
+// spec.allowInPlaceResize is set. It returns false if the VM is being resized.
+func (vms *VMService) reconcileResize(ctx context.Context, virtualMachineCtx *virtualMachineContext) (bool, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	vsphereVM := virtualMachineCtx.VSphereVM
+	if !vsphereVM.Spec.AllowInPlaceResize {
+		conditions.Delete(vsphereVM, infrav1.VMResizedCondition)
+		return true, nil
+	}
+
+	var vm mo.VirtualMachine
+	if err := virtualMachineCtx.Obj.Properties(ctx, virtualMachineCtx.Ref, []string{"config", "runtime.powerState"}, &vm); err != nil {
+		return false, errors.Wrapf(err, "failed to get configuration of vm %s", virtualMachineCtx)
+	}
+
+	spec, err := getResizeSpec(virtualMachineCtx, vm)
+	if err != nil {
+		// The VM keeps running with its current size, the resize is retried once the spec changes
+		// or hot plug is enabled.
+		log.Info("VM can't be resized in place", "reason", err.Error())
+		conditions.MarkFalse(vsphereVM, infrav1.VMResizedCondition, infrav1.ResizeNotSupportedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return true, nil
+	}
+	if spec == nil {
+		conditions.MarkTrue(vsphereVM, infrav1.VMResizedCondition)
+		return true, nil
+	}
+
+	log = log.WithValues("numCPUs", spec.NumCPUs, "memoryMiB", spec.MemoryMB)
+	if skipForDryRun(ctx, virtualMachineCtx, "resize", "numCPUs", spec.NumCPUs, "memoryMiB", spec.MemoryMB) {
+		return true, nil
+	}
+
+	log.Info("Resizing VM")
+	task, err := virtualMachineCtx.Obj.Reconfigure(ctx, *spec)
+	if err != nil {
+		conditions.MarkFalse(vsphereVM, infrav1.VMResizedCondition, infrav1.ResizeNotSupportedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return false, errors.Wrapf(err, "failed to trigger reconfigure op for vm %s", virtualMachineCtx)
+	}
+	conditions.MarkFalse(vsphereVM, infrav1.VMResizedCondition, infrav1.ResizingReason, clusterv1.ConditionSeverityInfo, "")
+	tracing.SetTaskDescription(ctx, task)
+	vsphereVM.Status.TaskRef = task.Reference().Value
+
+	log.Info("Wait for VM to be resized")
+	return false, nil
+}
+
+// getResizeSpec returns the spec resizing the CPU and memory of the VM to the spec, or nil if
+// the VM already has the expected size. It returns an error if the VM can't be resized in place.
+func getResizeSpec(virtualMachineCtx *virtualMachineContext, vm mo.VirtualMachine) (*types.VirtualMachineConfigSpec, error) {
+	if vm.Config == nil {
+		return nil, errors.Errorf("failed to get configuration of vm %s", virtualMachineCtx)
+	}
+
+	vmSpec := virtualMachineCtx.VSphereVM.Spec
+	hardware := vm.Config.Hardware
+	poweredOn := vm.Runtime.PowerState == types.VirtualMachinePowerStatePoweredOn
+
+	// The defaults match the ones applied when cloning the VM.
+	numCPUs := vmSpec.NumCPUs
+	if numCPUs < 2 {
+		numCPUs = 2
+	}
+	memMiB := vmSpec.MemoryMiB
+	if memMiB == 0 {
+		memMiB = 2048
+	}
+
+	spec := &types.VirtualMachineConfigSpec{}
+	resize := false
+	unsupported := []string{}
+
+	if hardware.NumCPU != numCPUs {
+		spec.NumCPUs = numCPUs
+		resize = true
+		switch {
+		case !poweredOn:
+		case numCPUs > hardware.NumCPU && !ptr.Deref(vm.Config.CpuHotAddEnabled, false):
+			unsupported = append(unsupported, "CPU hot add is not enabled")
+		case numCPUs < hardware.NumCPU && !ptr.Deref(vm.Config.CpuHotRemoveEnabled, false):
+			unsupported = append(unsupported, "CPU hot remove is not enabled")
+		case hardware.NumCoresPerSocket > 0 && numCPUs%hardware.NumCoresPerSocket != 0:
+			// Hot plug adds or removes whole sockets, the cores per socket can't be changed while powered on.
+			unsupported = append(unsupported, fmt.Sprintf("numCPUs is not a multiple of the %d cores per socket", hardware.NumCoresPerSocket))
+		}
+	}
+
+	if int64(hardware.MemoryMB) != memMiB {
+		spec.MemoryMB = memMiB
+		resize = true
+		switch {
+		case !poweredOn:
+		case memMiB < int64(hardware.MemoryMB):
+			unsupported = append(unsupported, "memory can't be decreased while powered on")
+		case !ptr.Deref(vm.Config.MemoryHotAddEnabled, false):
+			unsupported = append(unsupported, "memory hot add is not enabled")
+		}
+	}
+
+	if len(unsupported) > 0 {
+		return nil, errors.Errorf("unable to resize powered on vm from %d CPUs and %d MiB memory to %d CPUs and %d MiB memory: %s",
+			hardware.NumCPU, hardware.MemoryMB, numCPUs, memMiB, strings.Join(unsupported, ", "))
+	}
+	if !resize {
+		return nil, nil
+	}
+	return spec, nil
+}
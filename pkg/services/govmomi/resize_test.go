@@ -0,0 +1,142 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func Test_reconcileResize(t *testing.T) {
+	g := NewWithT(t)
+	model := simulator.VPX()
+	g.Expect(model.Create()).To(Succeed())
+
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		finder := find.NewFinder(c)
+		vm, err := finder.VirtualMachine(ctx, "DC0_C0_RP0_VM0")
+		g.Expect(err).ToNot(HaveOccurred())
+
+		// Align the VM with the defaults applied when cloning.
+		task, err := vm.Reconfigure(ctx, types.VirtualMachineConfigSpec{NumCPUs: 2, NumCoresPerSocket: 1, MemoryMB: 2048})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(task.Wait(ctx)).To(Succeed())
+
+		vmCtx := emptyVirtualMachineContext()
+		vmCtx.Obj = vm
+		vmCtx.Ref = vm.Reference()
+		vmCtx.VSphereVM = &infrav1.VSphereVM{
+			Spec: infrav1.VSphereVMSpec{
+				VirtualMachineCloneSpec: infrav1.VirtualMachineCloneSpec{
+					NumCPUs:   4,
+					MemoryMiB: 2048,
+				},
+			},
+		}
+
+		vms := &VMService{}
+
+		waitForResize := func() {
+			task := object.NewTask(c, types.ManagedObjectReference{Type: morefTypeTask, Value: vmCtx.VSphereVM.Status.TaskRef})
+			g.Expect(task.Wait(ctx)).To(Succeed())
+			vmCtx.VSphereVM.Status.TaskRef = ""
+		}
+
+		t.Run("without in-place resize", func(*testing.T) {
+			ok, err := vms.reconcileResize(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeTrue())
+			g.Expect(vmCtx.VSphereVM.Status.TaskRef).To(BeEmpty())
+			g.Expect(conditions.Has(vmCtx.VSphereVM, infrav1.VMResizedCondition)).To(BeFalse())
+		})
+
+		t.Run("without CPU hot add", func(*testing.T) {
+			vmCtx.VSphereVM.Spec.AllowInPlaceResize = true
+
+			ok, err := vms.reconcileResize(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeTrue())
+			g.Expect(vmCtx.VSphereVM.Status.TaskRef).To(BeEmpty())
+			g.Expect(conditions.GetReason(vmCtx.VSphereVM, infrav1.VMResizedCondition)).To(Equal(infrav1.ResizeNotSupportedReason))
+			g.Expect(conditions.GetMessage(vmCtx.VSphereVM, infrav1.VMResizedCondition)).To(ContainSubstring("CPU hot add is not enabled"))
+		})
+
+		t.Run("with CPU and memory hot add", func(*testing.T) {
+			task, err := vm.Reconfigure(ctx, types.VirtualMachineConfigSpec{CpuHotAddEnabled: ptr.To(true), MemoryHotAddEnabled: ptr.To(true)})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(task.Wait(ctx)).To(Succeed())
+			vmCtx.VSphereVM.Spec.MemoryMiB = 4096
+
+			ok, err := vms.reconcileResize(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeFalse())
+			g.Expect(vmCtx.VSphereVM.Status.TaskRef).ToNot(BeEmpty())
+			g.Expect(conditions.GetReason(vmCtx.VSphereVM, infrav1.VMResizedCondition)).To(Equal(infrav1.ResizingReason))
+			waitForResize()
+
+			ok, err = vms.reconcileResize(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeTrue())
+			g.Expect(conditions.IsTrue(vmCtx.VSphereVM, infrav1.VMResizedCondition)).To(BeTrue())
+
+			var moVM mo.VirtualMachine
+			g.Expect(vm.Properties(ctx, vm.Reference(), []string{"config.hardware"}, &moVM)).To(Succeed())
+			g.Expect(moVM.Config.Hardware.NumCPU).To(Equal(int32(4)))
+			g.Expect(moVM.Config.Hardware.MemoryMB).To(Equal(int32(4096)))
+		})
+
+		t.Run("decreasing memory of a powered on VM", func(*testing.T) {
+			vmCtx.VSphereVM.Spec.MemoryMiB = 2048
+
+			ok, err := vms.reconcileResize(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeTrue())
+			g.Expect(vmCtx.VSphereVM.Status.TaskRef).To(BeEmpty())
+			g.Expect(conditions.GetMessage(vmCtx.VSphereVM, infrav1.VMResizedCondition)).To(ContainSubstring("memory can't be decreased while powered on"))
+		})
+
+		t.Run("decreasing memory of a powered off VM", func(*testing.T) {
+			task, err := vm.PowerOff(ctx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(task.Wait(ctx)).To(Succeed())
+
+			ok, err := vms.reconcileResize(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeFalse())
+			g.Expect(conditions.GetReason(vmCtx.VSphereVM, infrav1.VMResizedCondition)).To(Equal(infrav1.ResizingReason))
+			waitForResize()
+
+			ok, err = vms.reconcileResize(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeTrue())
+			g.Expect(conditions.IsTrue(vmCtx.VSphereVM, infrav1.VMResizedCondition)).To(BeTrue())
+		})
+		return nil
+	}, model)
+}
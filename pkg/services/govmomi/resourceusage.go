@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/fault"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/session"
+)
+
+// ResourceUsage is the vSphere resource usage aggregated over VMs.
+type ResourceUsage struct {
+	// VMs is the number of VMs the resource usage is aggregated from.
+	VMs int32
+
+	// NumCPUs is the number of virtual CPUs of the VMs.
+	NumCPUs int64
+
+	// MemoryMiB is the memory of the VMs in MiB.
+	MemoryMiB int64
+
+	// StorageCommitted is the storage committed by the VMs on the datastores in bytes.
+	StorageCommitted int64
+}
+
+// Add adds the resource usage of other VMs.
+func (u *ResourceUsage) Add(other ResourceUsage) {
+	u.VMs += other.VMs
+	u.NumCPUs += other.NumCPUs
+	u.MemoryMiB += other.MemoryMiB
+	u.StorageCommitted += other.StorageCommitted
+}
+
+// GetResourceUsage returns the resource usage aggregated over the given VMs.
+// VMs which do not exist anymore, e.g. because they are being deleted, are ignored.
+func GetResourceUsage(ctx context.Context, s *session.Session, vms []types.ManagedObjectReference) (ResourceUsage, error) {
+	usage := ResourceUsage{}
+	for _, ref := range vms {
+		var vm mo.VirtualMachine
+		if err := s.RetrieveOne(ctx, ref, []string{"summary.config.numCpu", "summary.config.memorySizeMB", "summary.storage"}, &vm); err != nil {
+			if fault.Is(err, &types.ManagedObjectNotFound{}) {
+				continue
+			}
+			return ResourceUsage{}, errors.Wrapf(err, "failed to get resource usage of VM %s", ref.Value)
+		}
+
+		usage.VMs++
+		usage.NumCPUs += int64(vm.Summary.Config.NumCpu)
+		usage.MemoryMiB += int64(vm.Summary.Config.MemorySizeMB)
+		if vm.Summary.Storage != nil {
+			usage.StorageCommitted += vm.Summary.Storage.Committed
+		}
+	}
+	return usage, nil
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestGetResourceUsage(t *testing.T) {
+	g := NewWithT(t)
+	model := simulator.VPX()
+	model.Host = 0
+	g.Expect(model.Create()).To(Succeed())
+
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		authSession, err := getAuthSession(ctx, model.Service.Listen.Host)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		finder := find.NewFinder(c)
+		refs := []types.ManagedObjectReference{}
+		expected := ResourceUsage{}
+		for _, name := range []string{"DC0_C0_RP0_VM0", "DC0_C0_RP0_VM1"} {
+			vm, err := finder.VirtualMachine(ctx, name)
+			g.Expect(err).ToNot(HaveOccurred())
+			var moVM mo.VirtualMachine
+			g.Expect(vm.Properties(ctx, vm.Reference(), []string{"summary"}, &moVM)).To(Succeed())
+
+			refs = append(refs, vm.Reference())
+			expected.Add(ResourceUsage{
+				VMs:              1,
+				NumCPUs:          int64(moVM.Summary.Config.NumCpu),
+				MemoryMiB:        int64(moVM.Summary.Config.MemorySizeMB),
+				StorageCommitted: moVM.Summary.Storage.Committed,
+			})
+		}
+		g.Expect(expected.NumCPUs).To(BeNumerically(">", 0))
+		g.Expect(expected.MemoryMiB).To(BeNumerically(">", 0))
+
+		// VMs which do not exist are ignored.
+		refs = append(refs, types.ManagedObjectReference{Type: "VirtualMachine", Value: "vm-does-not-exist"})
+
+		usage, err := GetResourceUsage(ctx, authSession, refs)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(usage).To(Equal(expected))
+		return nil
+	}, model)
+}
@@ -21,13 +21,14 @@ import (
 	"encoding/base64"
 	"fmt"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/pbm"
 	pbmTypes "github.com/vmware/govmomi/pbm/types"
-	"github.com/vmware/govmomi/property"
 	"github.com/vmware/govmomi/vapi/rest"
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
@@ -40,6 +41,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/feature"
 	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/govmomi/cluster"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/govmomi/clustermodules"
@@ -47,6 +49,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/govmomi/ipam"
 	govmominet "sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/govmomi/net"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/govmomi/pci"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/tracing"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/util"
 )
 
@@ -58,7 +61,14 @@ type VMService struct{}
 //  2. Updating the VM with the bootstrap data, such as the cloud-init meta and user data, before...
 //  3. Powering on the VM, and finally...
 //  4. Returning the real-time state of the VM to the caller
-func (vms *VMService) ReconcileVM(ctx context.Context, vmCtx *capvcontext.VMContext) (vm infrav1.VirtualMachine, _ error) {
+func (vms *VMService) ReconcileVM(ctx context.Context, vmCtx *capvcontext.VMContext) (vm infrav1.VirtualMachine, reterr error) {
+	ctx, span := tracing.StartSpan(ctx, "VMService.ReconcileVM", vmCtx.VSphereVM)
+	defer func() {
+		tracing.EndSpan(span, reterr)
+	}()
+
+	log := ctrl.LoggerFrom(ctx)
+
 	// Initialize the result.
 	vm = infrav1.VirtualMachine{
 		Name:  vmCtx.VSphereVM.Name,
@@ -68,6 +78,10 @@ func (vms *VMService) ReconcileVM(ctx context.Context, vmCtx *capvcontext.VMCont
 	// If there is an in-flight task associated with this VM then do not
 	// reconcile the VM until the task is completed.
 	if inFlight, err := reconcileInFlightTask(ctx, vmCtx); err != nil || inFlight {
+		if err == nil {
+			// The task may never complete if the VM is blocked on a question.
+			err = vms.reconcileQuestionOfInFlightTask(ctx, vmCtx)
+		}
 		return vm, err
 	}
 
@@ -84,6 +98,13 @@ func (vms *VMService) ReconcileVM(ctx context.Context, vmCtx *capvcontext.VMCont
 			return vm, err
 		}
 
+		// A VSphereVM which should adopt a pre-existing VM is never cloned.
+		if isAdoptingVM(vmCtx.VSphereVM) && vmCtx.VSphereVM.Spec.BiosUUID == "" {
+			conditions.MarkFalse(vmCtx.VSphereVM, infrav1.VMProvisionedCondition, infrav1.VMToAdoptNotFoundReason, clusterv1.ConditionSeverityWarning, err.Error())
+			vm.State = infrav1.VirtualMachineStateNotFound
+			return vm, errors.Wrap(err, "failed to find VM to adopt")
+		}
+
 		// If the machine was not found by BIOS UUID, it could mean that the machine got deleted from vcenter directly,
 		// but sometimes this error is transient, for instance, if the storage was temporarily disconnected but
 		// later recovered, the machine will recover from this error.
@@ -93,6 +114,13 @@ func (vms *VMService) ReconcileVM(ctx context.Context, vmCtx *capvcontext.VMCont
 			return vm, err
 		}
 
+		// Do not clone the VM while the infrastructure is paused.
+		if vmCtx.InfraPaused {
+			log.Info("Infrastructure is paused, skipping VM creation")
+			conditions.MarkFalse(vmCtx.VSphereVM, infrav1.VMProvisionedCondition, infrav1.InfrastructurePausedReason, clusterv1.ConditionSeverityInfo, "")
+			return vm, nil
+		}
+
 		// Otherwise, this is a new machine and the VM should be created.
 		// NOTE: We are setting this condition only in case it does not exist, so we avoid to get flickering LastConditionTime
 		// in case of cloning errors or powering on errors.
@@ -101,7 +129,7 @@ func (vms *VMService) ReconcileVM(ctx context.Context, vmCtx *capvcontext.VMCont
 		}
 
 		// Get the bootstrap data.
-		bootstrapData, format, err := vms.getBootstrapData(ctx, vmCtx)
+		bootstrapData, format, err := vms.getCustomizedBootstrapData(ctx, vmCtx)
 		if err != nil {
 			conditions.MarkFalse(vmCtx.VSphereVM, infrav1.VMProvisionedCondition, infrav1.CloningFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 			return vm, err
@@ -110,8 +138,12 @@ func (vms *VMService) ReconcileVM(ctx context.Context, vmCtx *capvcontext.VMCont
 		// Create the VM.
 		err = createVM(ctx, vmCtx, bootstrapData, format)
 		if err != nil {
-			conditions.MarkFalse(vmCtx.VSphereVM, infrav1.VMProvisionedCondition, infrav1.CloningFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
-			return vm, err
+			faultErr := newFaultError(err, infrav1.CloningFailedReason)
+			conditions.MarkFalse(vmCtx.VSphereVM, infrav1.VMProvisionedCondition, faultErr.Reason, clusterv1.ConditionSeverityWarning, err.Error())
+			return vm, faultErr
+		}
+		if vmCtx.DryRun {
+			conditions.MarkFalse(vmCtx.VSphereVM, infrav1.VMProvisionedCondition, infrav1.DryRunReason, clusterv1.ConditionSeverityInfo, "Skipped operations: clone")
 		}
 		return vm, nil
 	}
@@ -129,8 +161,31 @@ func (vms *VMService) ReconcileVM(ctx context.Context, vmCtx *capvcontext.VMCont
 	}
 	vm.VMRef = vmRef.String()
 
+	adopted := isAdoptingVM(vmCtx.VSphereVM)
+	if adopted && vmCtx.VSphereVM.Spec.BiosUUID == "" {
+		log.Info("Adopting pre-existing VM", "vmRef", vmRef.Value)
+	}
+
 	vms.reconcileUUID(ctx, virtualMachineCtx)
 
+	// While the infrastructure is paused only the status of the VM is reconciled.
+	if vmCtx.InfraPaused {
+		return vm, vms.reconcilePausedVM(ctx, virtualMachineCtx)
+	}
+
+	// In dry-run mode the VM is not reported as ready while operations have been skipped.
+	defer func() {
+		if len(virtualMachineCtx.DryRunOperations) > 0 {
+			conditions.MarkFalse(vmCtx.VSphereVM, infrav1.VMProvisionedCondition, infrav1.DryRunReason, clusterv1.ConditionSeverityInfo,
+				"Skipped operations: %s", strings.Join(virtualMachineCtx.DryRunOperations, ", "))
+			vm.State = infrav1.VirtualMachineStatePending
+		}
+	}()
+
+	if ok, err := vms.reconcileQuestion(ctx, virtualMachineCtx); err != nil || !ok {
+		return vm, err
+	}
+
 	if ok, err := vms.reconcileHardwareVersion(ctx, virtualMachineCtx); err != nil || !ok {
 		return vm, err
 	}
@@ -139,6 +194,17 @@ func (vms *VMService) ReconcileVM(ctx context.Context, vmCtx *capvcontext.VMCont
 		return vm, err
 	}
 
+	// The NICs and disks of an adopted VM are not changed, as they are not created from the spec.
+	if !adopted {
+		if ok, err := vms.reconcileFirstClassDisks(ctx, virtualMachineCtx); err != nil || !ok {
+			return vm, err
+		}
+
+		if ok, err := vms.reconcileNetworkDeviceHotPlug(ctx, virtualMachineCtx); err != nil || !ok {
+			return vm, err
+		}
+	}
+
 	if err := vms.reconcileNetworkStatus(ctx, virtualMachineCtx); err != nil {
 		return vm, err
 	}
@@ -147,8 +213,15 @@ func (vms *VMService) ReconcileVM(ctx context.Context, vmCtx *capvcontext.VMCont
 		return vm, err
 	}
 
-	if ok, err := vms.reconcileMetadata(ctx, virtualMachineCtx); err != nil || !ok {
-		return vm, err
+	// The bootstrap data of an adopted VM is not changed, as the VM has been bootstrapped already.
+	if !adopted {
+		if vmCtx.VSphereVM.Spec.BootstrapDataDelivery == infrav1.BootstrapDataDeliveryNoCloudISO {
+			if ok, err := vms.reconcileBootstrapISO(ctx, virtualMachineCtx); err != nil || !ok {
+				return vm, err
+			}
+		} else if ok, err := vms.reconcileMetadata(ctx, virtualMachineCtx); err != nil || !ok {
+			return vm, err
+		}
 	}
 
 	if err := vms.reconcileStoragePolicy(ctx, virtualMachineCtx); err != nil {
@@ -167,6 +240,12 @@ func (vms *VMService) ReconcileVM(ctx context.Context, vmCtx *capvcontext.VMCont
 		return vm, err
 	}
 
+	if err := vms.reconcileGuestInfo(ctx, virtualMachineCtx); err != nil {
+		return vm, err
+	}
+
+	vms.reconcileGuestCrashes(ctx, virtualMachineCtx)
+
 	if err := vms.reconcileHostInfo(ctx, virtualMachineCtx); err != nil {
 		return vm, err
 	}
@@ -176,12 +255,42 @@ func (vms *VMService) ReconcileVM(ctx context.Context, vmCtx *capvcontext.VMCont
 		return vm, err
 	}
 
+	if err := vms.reconcileCustomAttributes(ctx, virtualMachineCtx); err != nil {
+		conditions.MarkFalse(vmCtx.VSphereVM, infrav1.VMProvisionedCondition, infrav1.CustomAttributesSyncFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		return vm, err
+	}
+
+	if err := vms.reconcileNetworkDeviceGuestConfig(ctx, virtualMachineCtx); err != nil {
+		return vm, err
+	}
+
+	if ok, err := vms.reconcileCustomVMXKeys(ctx, virtualMachineCtx); err != nil || !ok {
+		return vm, err
+	}
+
+	if ok, err := vms.reconcileResize(ctx, virtualMachineCtx); err != nil || !ok {
+		return vm, err
+	}
+
+	if ok, err := vms.reconcileConfigurationDrift(ctx, virtualMachineCtx); err != nil || !ok {
+		return vm, err
+	}
+
+	if ok, err := vms.reconcileRelocation(ctx, virtualMachineCtx); err != nil || !ok {
+		return vm, err
+	}
+
 	vm.State = infrav1.VirtualMachineStateReady
 	return vm, nil
 }
 
 // DestroyVM powers off and destroys a virtual machine.
-func (vms *VMService) DestroyVM(ctx context.Context, vmCtx *capvcontext.VMContext) (reconcile.Result, infrav1.VirtualMachine, error) {
+func (vms *VMService) DestroyVM(ctx context.Context, vmCtx *capvcontext.VMContext) (_ reconcile.Result, _ infrav1.VirtualMachine, reterr error) {
+	ctx, span := tracing.StartSpan(ctx, "VMService.DestroyVM", vmCtx.VSphereVM)
+	defer func() {
+		tracing.EndSpan(span, reterr)
+	}()
+
 	log := ctrl.LoggerFrom(ctx)
 
 	vm := infrav1.VirtualMachine{
@@ -207,12 +316,41 @@ func (vms *VMService) DestroyVM(ctx context.Context, vmCtx *capvcontext.VMContex
 		// If the VM's MoRef could not be found then the VM no longer exists. This
 		// is the desired state.
 		if isNotFound(err) || isFolderNotFound(err) {
+			// The addresses of a VM which has been deleted outside of CAPV are released too.
+			if !vmCtx.DryRun {
+				if err := vms.releaseNetworkProtocolProfileAddresses(ctx, vmCtx); err != nil {
+					return reconcile.Result{}, vm, err
+				}
+			}
 			vm.State = infrav1.VirtualMachineStateNotFound
 			return reconcile.Result{}, vm, nil
 		}
 		return reconcile.Result{}, vm, err
 	}
 
+	// Do not destroy a delete protected VM, but leave it orphaned. The VM is reported
+	// as not found, as it is no longer managed by the VSphereVM.
+	if vmCtx.DeleteProtected {
+		log.Info("VM is delete protected, leaving it orphaned", "vmRef", vmRef.Value)
+		unwatchVM(ctx, vmCtx, vmRef)
+		vm.State = infrav1.VirtualMachineStateNotFound
+		return reconcile.Result{}, vm, nil
+	}
+
+	// Do not destroy the VM while the infrastructure is paused.
+	if vmCtx.InfraPaused {
+		log.Info("Infrastructure is paused, waiting before destroying the VM")
+		conditions.MarkFalse(vmCtx.VSphereVM, infrav1.VMProvisionedCondition, infrav1.InfrastructurePausedReason, clusterv1.ConditionSeverityInfo, "")
+		return reconcile.Result{RequeueAfter: time.Minute}, vm, nil
+	}
+
+	// Do not destroy the VM in dry-run mode.
+	if vmCtx.DryRun {
+		log.Info("Dry run: skipping VM power off and deletion", "vmRef", vmRef.Value)
+		conditions.MarkFalse(vmCtx.VSphereVM, infrav1.VMProvisionedCondition, infrav1.DryRunReason, clusterv1.ConditionSeverityInfo, "Skipped operations: power off, destroy")
+		return reconcile.Result{RequeueAfter: time.Minute}, vm, nil
+	}
+
 	//
 	// At this point we know the VM exists, so it needs to be destroyed.
 	//
@@ -226,7 +364,7 @@ func (vms *VMService) DestroyVM(ctx context.Context, vmCtx *capvcontext.VMContex
 	}
 
 	// Shut down the VM
-	powerState, err := vms.getPowerState(ctx, virtualMachineCtx)
+	powerState, err := vms.getCurrentPowerState(ctx, virtualMachineCtx)
 	if err != nil {
 		return reconcile.Result{}, vm, err
 	}
@@ -249,6 +387,7 @@ func (vms *VMService) DestroyVM(ctx context.Context, vmCtx *capvcontext.VMContex
 			return reconcile.Result{}, vm, err
 		}
 
+		tracing.SetTaskDescription(ctx, task)
 		virtualMachineCtx.VSphereVM.Status.TaskRef = task.Reference().Value
 		if err = virtualMachineCtx.Patch(ctx); err != nil {
 			return reconcile.Result{}, vm, err
@@ -276,6 +415,20 @@ func (vms *VMService) DestroyVM(ctx context.Context, vmCtx *capvcontext.VMContex
 		vmCtx.VSphereVM.Status.ModuleUUID = nil
 	}
 
+	// Existing First Class Disks are detached, so they are not destroyed with the VM.
+	if err := vms.detachFirstClassDisks(ctx, virtualMachineCtx); err != nil {
+		return reconcile.Result{}, vm, err
+	}
+
+	// The NoCloud ISO image is not destroyed with the VM, as it is not owned by the VM.
+	if err := vms.deleteBootstrapISO(ctx, virtualMachineCtx); err != nil {
+		return reconcile.Result{}, vm, err
+	}
+
+	if err := vms.releaseNetworkProtocolProfileAddresses(ctx, vmCtx); err != nil {
+		return reconcile.Result{}, vm, err
+	}
+
 	// At this point the VM is not powered on and can be destroyed. Store the
 	// destroy task's reference and return a requeue error.
 	log.Info("Destroying vm")
@@ -283,11 +436,75 @@ func (vms *VMService) DestroyVM(ctx context.Context, vmCtx *capvcontext.VMContex
 	if err != nil {
 		return reconcile.Result{}, vm, err
 	}
+	tracing.SetTaskDescription(ctx, task)
 	vmCtx.VSphereVM.Status.TaskRef = task.Reference().Value
 	log.Info("Wait for VM to be destroyed")
 	return reconcile.Result{}, vm, nil
 }
 
+// VMExists reports whether the VM can be found by the instance UUID of the VSphereVM, or by its BIOS UUID
+// if known. Unlike when the VM is reconciled, the inventory path is not used to find the VM, as it also
+// matches VMs which are orphaned or whose files are gone.
+// With the InstanceUUIDLookup feature gate, the instance UUID recorded in the status is used once it is set.
+func (vms *VMService) VMExists(ctx context.Context, vmCtx *capvcontext.VMContext) (bool, error) {
+	if instanceUUID := vmCtx.VSphereVM.Status.InstanceUUID; instanceUUID != "" && feature.Gates.Enabled(feature.InstanceUUIDLookup) {
+		objRef, err := vmCtx.Session.FindByInstanceUUID(ctx, instanceUUID)
+		if err != nil {
+			return false, err
+		}
+		return objRef != nil, nil
+	}
+
+	objRef, err := vmCtx.Session.FindByInstanceUUID(ctx, string(vmCtx.VSphereVM.UID))
+	if err != nil {
+		return false, err
+	}
+	if objRef != nil {
+		return true, nil
+	}
+
+	biosUUID := vmCtx.VSphereVM.Spec.BiosUUID
+	if biosUUID == "" {
+		return false, nil
+	}
+	objRef, err = vmCtx.Session.FindByBIOSUUID(ctx, biosUUID)
+	if err != nil {
+		return false, err
+	}
+	return objRef != nil, nil
+}
+
+// reconcilePausedVM reconciles only the status of a VM whose vSphere mutating operations
+// are halted. The VM is reported as ready only if it is already powered on.
+func (vms *VMService) reconcilePausedVM(ctx context.Context, virtualMachineCtx *virtualMachineContext) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	if err := vms.reconcileNetworkStatus(ctx, virtualMachineCtx); err != nil {
+		return err
+	}
+
+	powerState, err := vms.getPowerState(ctx, virtualMachineCtx)
+	if err != nil {
+		return err
+	}
+	if powerState != infrav1.VirtualMachinePowerStatePoweredOn {
+		log.Info("Infrastructure is paused, skipping VM reconciliation", "powerState", powerState)
+		conditions.MarkFalse(virtualMachineCtx.VSphereVM, infrav1.VMProvisionedCondition, infrav1.InfrastructurePausedReason, clusterv1.ConditionSeverityInfo, "")
+		return nil
+	}
+
+	if err := vms.reconcileGuestInfo(ctx, virtualMachineCtx); err != nil {
+		return err
+	}
+
+	if err := vms.reconcileHostInfo(ctx, virtualMachineCtx); err != nil {
+		return err
+	}
+
+	virtualMachineCtx.State.State = infrav1.VirtualMachineStateReady
+	return nil
+}
+
 func (vms *VMService) reconcileNetworkStatus(ctx context.Context, virtualMachineCtx *virtualMachineContext) error {
 	netStatus, err := vms.getNetworkStatus(ctx, virtualMachineCtx)
 	if err != nil {
@@ -311,6 +528,9 @@ func (vms *VMService) reconcileIPAddresses(ctx context.Context, virtualMachineCt
 		return false, nil
 	}
 	virtualMachineCtx.IPAMState = ipamState
+	if err := vms.reconcileNetworkProtocolProfileAddresses(ctx, virtualMachineCtx); err != nil {
+		return false, err
+	}
 	return true, nil
 }
 
@@ -332,6 +552,10 @@ func (vms *VMService) reconcileMetadata(ctx context.Context, virtualMachineCtx *
 		return true, nil
 	}
 
+	if skipForDryRun(ctx, virtualMachineCtx, "metadata update", "metadata", string(newMetadata)) {
+		return true, nil
+	}
+
 	log.Info("Updating VM metadata")
 	taskRef, err := vms.setMetadata(ctx, virtualMachineCtx, newMetadata)
 	if err != nil {
@@ -343,6 +567,8 @@ func (vms *VMService) reconcileMetadata(ctx context.Context, virtualMachineCtx *
 	return false, nil
 }
 
+// reconcilePowerState reconciles the power state of the VM with the desired power state of the
+// VSphereVM, which defaults to powered on. It returns true if the VM is powered on.
 func (vms *VMService) reconcilePowerState(ctx context.Context, virtualMachineCtx *virtualMachineContext) (bool, error) {
 	log := ctrl.LoggerFrom(ctx)
 
@@ -350,36 +576,144 @@ func (vms *VMService) reconcilePowerState(ctx context.Context, virtualMachineCtx
 	if err != nil {
 		return false, err
 	}
-	switch powerState {
-	case infrav1.VirtualMachinePowerStatePoweredOff:
-		log.Info("Powering on VM")
-		task, err := virtualMachineCtx.Obj.PowerOn(ctx)
-		if err != nil {
-			conditions.MarkFalse(virtualMachineCtx.VSphereVM, infrav1.VMProvisionedCondition, infrav1.PoweringOnFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
-			return false, errors.Wrapf(err, "failed to trigger power on op for vm %s", virtualMachineCtx)
-		}
-		conditions.MarkFalse(virtualMachineCtx.VSphereVM, infrav1.VMProvisionedCondition, infrav1.PoweringOnReason, clusterv1.ConditionSeverityInfo, "")
-
-		// Update the VSphereVM.Status.TaskRef to track the power-on task.
-		virtualMachineCtx.VSphereVM.Status.TaskRef = task.Reference().Value
-		if err = virtualMachineCtx.Patch(ctx); err != nil {
+	// The cached power state can lag behind a power on which just completed, so it is confirmed before powering on the VM.
+	if powerState == infrav1.VirtualMachinePowerStatePoweredOff && feature.Gates.Enabled(feature.BatchedVCenterCalls) {
+		if powerState, err = vms.getCurrentPowerState(ctx, virtualMachineCtx); err != nil {
 			return false, err
 		}
+	}
 
-		// Once the VM is successfully powered on, a reconcile request should be
-		// triggered once the VM reports IP addresses are available.
-		reconcileVSphereVMWhenNetworkIsReady(ctx, virtualMachineCtx, task)
+	desiredPowerState := virtualMachineCtx.VSphereVM.Spec.PowerState
+	if desiredPowerState == "" {
+		desiredPowerState = infrav1.VirtualMachinePowerStatePoweredOn
+	}
 
-		log.Info("Wait for VM to be powered on")
-		return false, nil
-	case infrav1.VirtualMachinePowerStatePoweredOn:
+	switch {
+	case powerState == desiredPowerState && powerState == infrav1.VirtualMachinePowerStatePoweredOn:
+		// Forget the guest shutdown of a previous power off, so it is triggered again on the next power off.
+		conditions.Delete(virtualMachineCtx.VSphereVM, infrav1.GuestSoftPowerOffSucceededCondition)
 		log.Info("VM is powered on")
 		return true, nil
+	case powerState == desiredPowerState:
+		// Only set the GuestPowerOffCondition to true when the guest shutdown has been initiated.
+		if conditions.Has(virtualMachineCtx.VSphereVM, infrav1.GuestSoftPowerOffSucceededCondition) {
+			conditions.MarkTrue(virtualMachineCtx.VSphereVM, infrav1.GuestSoftPowerOffSucceededCondition)
+		}
+		reason := infrav1.PoweredOffReason
+		if powerState == infrav1.VirtualMachinePowerStateSuspended {
+			reason = infrav1.SuspendedReason
+		}
+		conditions.MarkFalse(virtualMachineCtx.VSphereVM, infrav1.VMProvisionedCondition, reason, clusterv1.ConditionSeverityInfo,
+			"VM is %s as requested by spec.powerState", powerState)
+		log.Info("VM is intentionally not powered on", "powerState", powerState)
+		return false, nil
+	case desiredPowerState == infrav1.VirtualMachinePowerStatePoweredOff:
+		return false, vms.powerOffVM(ctx, virtualMachineCtx, powerState)
+	case desiredPowerState == infrav1.VirtualMachinePowerStateSuspended && powerState == infrav1.VirtualMachinePowerStatePoweredOn:
+		return false, vms.suspendVM(ctx, virtualMachineCtx)
+	case powerState == infrav1.VirtualMachinePowerStatePoweredOff, powerState == infrav1.VirtualMachinePowerStateSuspended:
+		// A suspended VM is resumed by powering it on, and a powered off VM has to be powered on before it can be suspended.
+		return false, vms.powerOnVM(ctx, virtualMachineCtx)
 	default:
 		return false, errors.Errorf("unexpected power state %q for vm %s", powerState, virtualMachineCtx)
 	}
 }
 
+func (vms *VMService) powerOnVM(ctx context.Context, virtualMachineCtx *virtualMachineContext) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	if skipForDryRun(ctx, virtualMachineCtx, "power on") {
+		return nil
+	}
+
+	log.Info("Powering on VM")
+	task, err := powerOnVM(ctx, virtualMachineCtx)
+	if err != nil {
+		faultErr := newFaultError(err, infrav1.PoweringOnFailedReason)
+		conditions.MarkFalse(virtualMachineCtx.VSphereVM, infrav1.VMProvisionedCondition, faultErr.Reason, clusterv1.ConditionSeverityWarning, err.Error())
+		return errors.Wrapf(faultErr, "failed to trigger power on op for vm %s", virtualMachineCtx)
+	}
+	conditions.MarkFalse(virtualMachineCtx.VSphereVM, infrav1.VMProvisionedCondition, infrav1.PoweringOnReason, clusterv1.ConditionSeverityInfo, "")
+
+	tracing.SetTaskDescription(ctx, task)
+
+	// Update the VSphereVM.Status.TaskRef to track the power-on task.
+	virtualMachineCtx.VSphereVM.Status.TaskRef = task.Reference().Value
+	if err = virtualMachineCtx.Patch(ctx); err != nil {
+		return err
+	}
+
+	// Once the VM is successfully powered on, a reconcile request should be
+	// triggered once the VM reports IP addresses are available.
+	reconcileVSphereVMWhenNetworkIsReady(ctx, virtualMachineCtx, task)
+
+	log.Info("Wait for VM to be powered on")
+	return nil
+}
+
+// powerOffVM powers off the VM as requested by the power state of the VSphereVM.
+// The guest is shut down first according to the power off mode, like when the VM is destroyed.
+func (vms *VMService) powerOffVM(ctx context.Context, virtualMachineCtx *virtualMachineContext, powerState infrav1.VirtualMachinePowerState) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	if skipForDryRun(ctx, virtualMachineCtx, "power off") {
+		return nil
+	}
+
+	conditions.MarkFalse(virtualMachineCtx.VSphereVM, infrav1.VMProvisionedCondition, infrav1.PoweringOffReason, clusterv1.ConditionSeverityInfo, "")
+
+	// The guest of a suspended VM can't be shut down.
+	if powerState == infrav1.VirtualMachinePowerStatePoweredOn {
+		softPowerOffPending, err := vms.triggerSoftPowerOff(ctx, virtualMachineCtx)
+		if err != nil {
+			return err
+		}
+		if softPowerOffPending {
+			log.Info("Wait for the guest to shut down")
+			return nil
+		}
+	}
+
+	log.Info("Powering off VM")
+	task, err := virtualMachineCtx.Obj.PowerOff(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "failed to trigger power off op for vm %s", virtualMachineCtx)
+	}
+	tracing.SetTaskDescription(ctx, task)
+	virtualMachineCtx.VSphereVM.Status.TaskRef = task.Reference().Value
+	if err = virtualMachineCtx.Patch(ctx); err != nil {
+		return err
+	}
+
+	log.Info("Wait for VM to be powered off")
+	return nil
+}
+
+// suspendVM suspends the VM as requested by the power state of the VSphereVM.
+func (vms *VMService) suspendVM(ctx context.Context, virtualMachineCtx *virtualMachineContext) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	if skipForDryRun(ctx, virtualMachineCtx, "suspend") {
+		return nil
+	}
+
+	log.Info("Suspending VM")
+	task, err := virtualMachineCtx.Obj.Suspend(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "failed to trigger suspend op for vm %s", virtualMachineCtx)
+	}
+	conditions.MarkFalse(virtualMachineCtx.VSphereVM, infrav1.VMProvisionedCondition, infrav1.PoweringOffReason, clusterv1.ConditionSeverityInfo, "")
+
+	tracing.SetTaskDescription(ctx, task)
+	virtualMachineCtx.VSphereVM.Status.TaskRef = task.Reference().Value
+	if err = virtualMachineCtx.Patch(ctx); err != nil {
+		return err
+	}
+
+	log.Info("Wait for VM to be suspended")
+	return nil
+}
+
 func (vms *VMService) reconcileStoragePolicy(ctx context.Context, virtualMachineCtx *virtualMachineContext) error {
 	log := ctrl.LoggerFrom(ctx)
 
@@ -458,6 +792,9 @@ func (vms *VMService) reconcileStoragePolicy(ctx context.Context, virtualMachine
 
 	// If there are pending changes for Storage Policies, do it before moving next
 	if len(changes) > 0 {
+		if skipForDryRun(ctx, virtualMachineCtx, "storage policy reconfiguration", "storagePolicyName", virtualMachineCtx.VSphereVM.Spec.StoragePolicyName, "disks", len(changes)) {
+			return nil
+		}
 		task, err := virtualMachineCtx.Obj.Reconfigure(ctx, types.VirtualMachineConfigSpec{
 			VmProfile: []types.BaseVirtualMachineProfileSpec{
 				&types.VirtualMachineDefinedProfileSpec{ProfileId: storageProfileID},
@@ -467,13 +804,21 @@ func (vms *VMService) reconcileStoragePolicy(ctx context.Context, virtualMachine
 		if err != nil {
 			return errors.Wrapf(err, "unable to set storagePolicy on vm %s", virtualMachineCtx)
 		}
+		tracing.SetTaskDescription(ctx, task)
 		virtualMachineCtx.VSphereVM.Status.TaskRef = task.Reference().Value
 	}
 	return nil
 }
 
 func (vms *VMService) reconcileUUID(ctx context.Context, virtualMachineCtx *virtualMachineContext) {
-	virtualMachineCtx.State.BiosUUID = virtualMachineCtx.Obj.UUID(ctx)
+	var vm mo.VirtualMachine
+	if err := getVMProperties(ctx, virtualMachineCtx, []string{"config.uuid", "config.instanceUuid"}, &vm); err != nil || vm.Config == nil {
+		virtualMachineCtx.State.BiosUUID = ""
+		virtualMachineCtx.State.InstanceUUID = ""
+		return
+	}
+	virtualMachineCtx.State.BiosUUID = vm.Config.Uuid
+	virtualMachineCtx.State.InstanceUUID = vm.Config.InstanceUuid
 }
 
 func (vms *VMService) reconcileHardwareVersion(ctx context.Context, virtualMachineCtx *virtualMachineContext) (bool, error) {
@@ -481,7 +826,7 @@ func (vms *VMService) reconcileHardwareVersion(ctx context.Context, virtualMachi
 
 	if virtualMachineCtx.VSphereVM.Spec.HardwareVersion != "" {
 		var virtualMachine mo.VirtualMachine
-		if err := virtualMachineCtx.Obj.Properties(ctx, virtualMachineCtx.Obj.Reference(), []string{"config.version"}, &virtualMachine); err != nil {
+		if err := getVMProperties(ctx, virtualMachineCtx, []string{"config.version"}, &virtualMachine); err != nil {
 			return false, errors.Wrapf(err, "error getting guestInfo version information from VM %s", virtualMachineCtx.VSphereVM.Name)
 		}
 		toUpgrade, err := util.LessThan(virtualMachine.Config.Version, virtualMachineCtx.VSphereVM.Spec.HardwareVersion)
@@ -489,11 +834,15 @@ func (vms *VMService) reconcileHardwareVersion(ctx context.Context, virtualMachi
 			return false, errors.Wrapf(err, "failed to parse hardware version")
 		}
 		if toUpgrade {
+			if skipForDryRun(ctx, virtualMachineCtx, "hardware version upgrade", "fromVersion", virtualMachine.Config.Version, "toVersion", virtualMachineCtx.VSphereVM.Spec.HardwareVersion) {
+				return true, nil
+			}
 			log.Info("Upgrading hardware version", "fromVersion", virtualMachine.Config.Version, "toVersion", virtualMachineCtx.VSphereVM.Spec.HardwareVersion)
 			task, err := virtualMachineCtx.Obj.UpgradeVM(ctx, virtualMachineCtx.VSphereVM.Spec.HardwareVersion)
 			if err != nil {
 				return false, errors.Wrapf(err, "error trigging upgrade op for machine %s", virtualMachineCtx)
 			}
+			tracing.SetTaskDescription(ctx, task)
 			virtualMachineCtx.VSphereVM.Status.TaskRef = task.Reference().Value
 			return false, nil
 		}
@@ -533,6 +882,9 @@ func (vms *VMService) reconcilePCIDevices(ctx context.Context, virtualMachineCtx
 				"PCI devices removed after VM was powered on")
 			return errors.Errorf("missing PCI devices")
 		}
+		if skipForDryRun(ctx, virtualMachineCtx, "PCI devices attachment", "number", len(specsToBeAdded)) {
+			return nil
+		}
 		log.Info("PCI devices to be added", "number", len(specsToBeAdded))
 		if err := virtualMachineCtx.Obj.AddDevice(ctx, pci.ConstructDeviceSpecs(specsToBeAdded)...); err != nil {
 			return errors.Wrapf(err, "error adding pci devices for %q", virtualMachineCtx)
@@ -545,11 +897,10 @@ func (vms *VMService) getMetadata(ctx context.Context, virtualMachineCtx *virtua
 	var (
 		obj mo.VirtualMachine
 
-		pc    = property.DefaultCollector(virtualMachineCtx.Session.Client.Client)
 		props = []string{"config.extraConfig"}
 	)
 
-	if err := pc.RetrieveOne(ctx, virtualMachineCtx.Ref, props, &obj); err != nil {
+	if err := getVMProperties(ctx, virtualMachineCtx, props, &obj); err != nil {
 		return "", errors.Wrapf(err, "unable to fetch props %v for vm %s", props, virtualMachineCtx)
 	}
 	if obj.Config == nil {
@@ -601,7 +952,7 @@ func (vms *VMService) setMetadata(ctx context.Context, virtualMachineCtx *virtua
 	if err != nil {
 		return "", errors.Wrapf(err, "unable to set metadata on vm %s", virtualMachineCtx)
 	}
-
+	tracing.SetTaskDescription(ctx, task)
 	return task.Reference().Value, nil
 }
 
@@ -625,6 +976,38 @@ func (vms *VMService) getNetworkStatus(ctx context.Context, virtualMachineCtx *v
 	return apiNetStatus, nil
 }
 
+// getCustomizedBootstrapData returns the bootstrap data of a machine, with the cloud-init customization
+// and kube-vip merged into it, and the format of the data.
+func (vms *VMService) getCustomizedBootstrapData(ctx context.Context, vmCtx *capvcontext.VMContext) ([]byte, bootstrapv1.Format, error) {
+	bootstrapData, format, err := vms.getBootstrapData(ctx, vmCtx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if vmCtx.VSphereVM.Spec.BootstrapDataDelivery == infrav1.BootstrapDataDeliveryNoCloudISO && len(bootstrapData) > 0 && format != bootstrapv1.CloudConfig {
+		return nil, "", errors.Errorf("bootstrap data format %s is not supported with the %s bootstrap data delivery", format, infrav1.BootstrapDataDeliveryNoCloudISO)
+	}
+
+	// Merge the cloud-init customization into the bootstrap data.
+	bootstrapData, err = customizeBootstrapData(ctx, vmCtx, bootstrapData, format)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Add the NTP servers and the proxy of the network spec to the bootstrap data.
+	bootstrapData, err = addGuestNetworkSettings(vmCtx, bootstrapData, format)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Add kube-vip to the bootstrap data of control plane VMs serving a managed control plane endpoint.
+	bootstrapData, err = addKubeVIP(ctx, vmCtx, bootstrapData, format)
+	if err != nil {
+		return nil, "", err
+	}
+	return bootstrapData, format, nil
+}
+
 // getBootstrapData obtains a machine's bootstrap data from the relevant k8s secret and returns the
 // data and its format.
 func (vms *VMService) getBootstrapData(ctx context.Context, vmCtx *capvcontext.VMContext) ([]byte, bootstrapv1.Format, error) {
@@ -678,10 +1061,14 @@ func (vms *VMService) reconcileVMGroupInfo(ctx context.Context, virtualMachineCt
 	}
 
 	if !hasVM {
+		if skipForDryRun(ctx, virtualMachineCtx, "VM group membership", "vmGroup", topology.Hosts.VMGroupName) {
+			return true, nil
+		}
 		task, err := vmGroup.Add(ctx, virtualMachineCtx.Ref)
 		if err != nil {
 			return false, errors.Wrapf(err, "failed to add VM %s to VM group", virtualMachineCtx.VSphereVM.Name)
 		}
+		tracing.SetTaskDescription(ctx, task)
 		virtualMachineCtx.VSphereVM.Status.TaskRef = task.Reference().Value
 		log.Info("Wait for VM to be added to group")
 		return false, nil
@@ -697,6 +1084,23 @@ func (vms *VMService) reconcileTags(ctx context.Context, virtualMachineCtx *virt
 		return nil
 	}
 
+	if virtualMachineCtx.DryRun {
+		attachedTagIDs, err := virtualMachineCtx.Session.TagManager.ListAttachedTags(ctx, virtualMachineCtx.Ref)
+		if err != nil {
+			return errors.Wrapf(err, "failed to list tags attached to VM %s", virtualMachineCtx.VSphereVM.Name)
+		}
+		missingTagIDs := []string{}
+		for _, tagID := range virtualMachineCtx.VSphereVM.Spec.TagIDs {
+			if !slices.Contains(attachedTagIDs, tagID) {
+				missingTagIDs = append(missingTagIDs, tagID)
+			}
+		}
+		if len(missingTagIDs) > 0 {
+			skipForDryRun(ctx, virtualMachineCtx, "tags attachment", "tagIDs", missingTagIDs)
+		}
+		return nil
+	}
+
 	err := virtualMachineCtx.Session.TagManager.AttachMultipleTagsToObject(ctx, virtualMachineCtx.VSphereVM.Spec.TagIDs, virtualMachineCtx.Ref)
 	if err != nil {
 		return errors.Wrapf(err, "failed to attach tags %v to VM %s", virtualMachineCtx.VSphereVM.Spec.TagIDs, virtualMachineCtx.VSphereVM.Name)
@@ -714,6 +1118,17 @@ func (vms *VMService) reconcileClusterModuleMembership(ctx context.Context, virt
 
 		provider := clustermodules.NewProvider(virtualMachineCtx.Session.TagManager.Client)
 
+		if virtualMachineCtx.DryRun {
+			isMember, err := provider.IsMoRefModuleMember(ctx, *virtualMachineCtx.ClusterModuleInfo, virtualMachineCtx.Ref)
+			if err != nil {
+				return err
+			}
+			if !isMember {
+				skipForDryRun(ctx, virtualMachineCtx, "cluster module membership")
+			}
+			return nil
+		}
+
 		if err := provider.AddMoRefToModule(ctx, *virtualMachineCtx.ClusterModuleInfo, virtualMachineCtx.Ref); err != nil {
 			return err
 		}
@@ -721,3 +1136,15 @@ func (vms *VMService) reconcileClusterModuleMembership(ctx context.Context, virt
 	}
 	return nil
 }
+
+// skipForDryRun returns true if a vSphere mutating operation has to be skipped because the
+// VSphereVM is reconciled in dry-run mode. The skipped operation is logged together with
+// the changes it would apply and recorded in the VMProvisionedCondition.
+func skipForDryRun(ctx context.Context, virtualMachineCtx *virtualMachineContext, operation string, keysAndValues ...interface{}) bool {
+	if !virtualMachineCtx.DryRun {
+		return false
+	}
+	ctrl.LoggerFrom(ctx).Info(fmt.Sprintf("Dry run: skipping %s", operation), keysAndValues...)
+	virtualMachineCtx.DryRunOperations = append(virtualMachineCtx.DryRunOperations, operation)
+	return true
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	"github.com/vmware/govmomi/find"
@@ -27,12 +28,19 @@ import (
 	pbmsimulator "github.com/vmware/govmomi/pbm/simulator"
 	"github.com/vmware/govmomi/simulator"
 	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilfeature "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/feature"
 	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/session"
 )
@@ -230,3 +238,359 @@ func storagePolicyModel() (*simulator.Model, error) {
 	model.Host = 1
 	return model, nil
 }
+
+func Test_reconcilePausedVM(t *testing.T) {
+	for _, poweredOn := range []bool{true, false} {
+		t.Run(fmt.Sprintf("when VM powered on is %t", poweredOn), func(t *testing.T) {
+			g := NewWithT(t)
+			model := simulator.VPX()
+			g.Expect(model.Create()).To(Succeed())
+
+			simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+				authSession, err := getAuthSession(ctx, model.Service.Listen.Host)
+				g.Expect(err).ToNot(HaveOccurred())
+
+				finder := find.NewFinder(c)
+				vm, err := finder.VirtualMachine(ctx, "DC0_H0_VM0")
+				g.Expect(err).ToNot(HaveOccurred())
+				if !poweredOn {
+					vm, err = getPoweredoffVM(ctx, c)
+					g.Expect(err).ToNot(HaveOccurred())
+				}
+
+				vmCtx := emptyVirtualMachineContext()
+				vmCtx.Session = authSession
+				vmCtx.Obj = vm
+				vmCtx.Ref = vm.Reference()
+				vmCtx.State = &infrav1.VirtualMachine{State: infrav1.VirtualMachineStatePending}
+				vmCtx.VSphereVM = &infrav1.VSphereVM{}
+				vmCtx.InfraPaused = true
+
+				vms := &VMService{}
+				g.Expect(vms.reconcilePausedVM(ctx, vmCtx)).To(Succeed())
+				if poweredOn {
+					g.Expect(vmCtx.State.State).To(BeEquivalentTo(infrav1.VirtualMachineStateReady))
+					g.Expect(vmCtx.VSphereVM.Status.Host).ToNot(BeEmpty())
+				} else {
+					g.Expect(vmCtx.State.State).To(BeEquivalentTo(infrav1.VirtualMachineStatePending))
+					g.Expect(conditions.GetReason(vmCtx.VSphereVM, infrav1.VMProvisionedCondition)).To(Equal(infrav1.InfrastructurePausedReason))
+				}
+				return nil
+			}, model)
+		})
+	}
+}
+
+func Test_reconcilePowerState_DryRun(t *testing.T) {
+	g := NewWithT(t)
+	model := simulator.VPX()
+	g.Expect(model.Create()).To(Succeed())
+
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		vm, err := getPoweredoffVM(ctx, c)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		vmCtx := emptyVirtualMachineContext()
+		vmCtx.Obj = vm
+		vmCtx.Ref = vm.Reference()
+		vmCtx.VSphereVM = &infrav1.VSphereVM{}
+		vmCtx.DryRun = true
+
+		vms := &VMService{}
+		ok, err := vms.reconcilePowerState(ctx, vmCtx)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(ok).To(BeFalse())
+		g.Expect(vmCtx.DryRunOperations).To(ConsistOf("power on"))
+		g.Expect(vmCtx.VSphereVM.Status.TaskRef).To(BeEmpty())
+
+		powerState, err := vm.PowerState(ctx)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(powerState).To(Equal(types.VirtualMachinePowerStatePoweredOff))
+		return nil
+	}, model)
+}
+
+func Test_reconcilePowerState_PowerState(t *testing.T) {
+	tests := []struct {
+		name              string
+		poweredOn         bool
+		desiredPowerState infrav1.VirtualMachinePowerState
+		expectReady       bool
+		expectReason      string
+		expectPowerState  types.VirtualMachinePowerState
+	}{
+		{
+			name:             "powered on VM is ready by default",
+			poweredOn:        true,
+			expectReady:      true,
+			expectPowerState: types.VirtualMachinePowerStatePoweredOn,
+		},
+		{
+			name:              "powered on VM is powered off",
+			poweredOn:         true,
+			desiredPowerState: infrav1.VirtualMachinePowerStatePoweredOff,
+			expectReason:      infrav1.PoweringOffReason,
+			expectPowerState:  types.VirtualMachinePowerStatePoweredOff,
+		},
+		{
+			name:              "powered on VM is suspended",
+			poweredOn:         true,
+			desiredPowerState: infrav1.VirtualMachinePowerStateSuspended,
+			expectReason:      infrav1.PoweringOffReason,
+			expectPowerState:  types.VirtualMachinePowerStateSuspended,
+		},
+		{
+			name:              "powered off VM is reported as intentionally powered off",
+			desiredPowerState: infrav1.VirtualMachinePowerStatePoweredOff,
+			expectReason:      infrav1.PoweredOffReason,
+			expectPowerState:  types.VirtualMachinePowerStatePoweredOff,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			model := simulator.VPX()
+			g.Expect(model.Create()).To(Succeed())
+
+			simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+				vm, err := find.NewFinder(c).VirtualMachine(ctx, "DC0_H0_VM0")
+				g.Expect(err).ToNot(HaveOccurred())
+				if !tt.poweredOn {
+					vm, err = getPoweredoffVM(ctx, c)
+					g.Expect(err).ToNot(HaveOccurred())
+				}
+
+				vsphereVM := &infrav1.VSphereVM{
+					ObjectMeta: metav1.ObjectMeta{Name: "vm", Namespace: "default"},
+					Spec: infrav1.VSphereVMSpec{
+						PowerOffMode: infrav1.VirtualMachinePowerOpModeHard,
+						PowerState:   tt.desiredPowerState,
+					},
+				}
+				scheme := runtime.NewScheme()
+				g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+				fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(vsphereVM).WithStatusSubresource(vsphereVM).Build()
+				patchHelper, err := patch.NewHelper(vsphereVM, fakeClient)
+				g.Expect(err).ToNot(HaveOccurred())
+
+				vmCtx := emptyVirtualMachineContext()
+				vmCtx.Obj = vm
+				vmCtx.Ref = vm.Reference()
+				vmCtx.VSphereVM = vsphereVM
+				vmCtx.PatchHelper = patchHelper
+
+				vms := &VMService{}
+				ok, err := vms.reconcilePowerState(ctx, vmCtx)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(ok).To(Equal(tt.expectReady))
+				if tt.expectReason != "" {
+					g.Expect(conditions.GetReason(vmCtx.VSphereVM, infrav1.VMProvisionedCondition)).To(Equal(tt.expectReason))
+					g.Expect(conditions.GetSeverity(vmCtx.VSphereVM, infrav1.VMProvisionedCondition)).To(HaveValue(Equal(clusterv1.ConditionSeverityInfo)))
+				}
+
+				if vmCtx.VSphereVM.Status.TaskRef != "" {
+					task := object.NewTask(c, types.ManagedObjectReference{Type: "Task", Value: vmCtx.VSphereVM.Status.TaskRef})
+					g.Expect(task.Wait(ctx)).To(Succeed())
+				}
+				powerState, err := vm.PowerState(ctx)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(powerState).To(Equal(tt.expectPowerState))
+				return nil
+			}, model)
+		})
+	}
+}
+
+func Test_ReconcileVM_AdoptVM(t *testing.T) {
+	g := NewWithT(t)
+	model := simulator.VPX()
+	g.Expect(model.Create()).To(Succeed())
+
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		authSession, err := getAuthSession(ctx, model.Service.Listen.Host)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		finder := find.NewFinder(c)
+		vm, err := finder.VirtualMachine(ctx, "DC0_H0_VM0")
+		g.Expect(err).ToNot(HaveOccurred())
+		biosUUID := vm.UUID(ctx)
+
+		newVMContext := func(annotation string) *capvcontext.VMContext {
+			return &capvcontext.VMContext{
+				ControllerManagerContext: &capvcontext.ControllerManagerContext{},
+				Session:                  authSession,
+				VSphereVM: &infrav1.VSphereVM{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "adopted-vm",
+						Annotations: map[string]string{infrav1.AdoptVMAnnotation: annotation},
+					},
+				},
+			}
+		}
+
+		// The pre-existing VM is found by the BIOS UUID of the annotation.
+		vmRef, err := findVM(ctx, newVMContext(biosUUID))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(vmRef).To(Equal(vm.Reference()))
+
+		// A VM which should be adopted but can't be found is not cloned.
+		vmCtx := newVMContext("")
+		vms := &VMService{}
+		state, err := vms.ReconcileVM(ctx, vmCtx)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(state.State).To(Equal(infrav1.VirtualMachineStateNotFound))
+		g.Expect(conditions.GetReason(vmCtx.VSphereVM, infrav1.VMProvisionedCondition)).To(Equal(infrav1.VMToAdoptNotFoundReason))
+		g.Expect(vmCtx.VSphereVM.Status.TaskRef).To(BeEmpty())
+
+		// The pre-existing VM is found by the instance UUID of the annotation.
+		var moVM mo.VirtualMachine
+		g.Expect(vm.Properties(ctx, vm.Reference(), []string{"config.instanceUuid"}, &moVM)).To(Succeed())
+		vmCtx = newVMContext("")
+		vmCtx.VSphereVM.Annotations = map[string]string{infrav1.AdoptVMInstanceUUIDAnnotation: moVM.Config.InstanceUuid}
+		vmRef, err = findVM(ctx, vmCtx)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(vmRef).To(Equal(vm.Reference()))
+
+		// A VM which should be adopted by an unknown instance UUID is not cloned.
+		vmCtx.VSphereVM.Annotations[infrav1.AdoptVMInstanceUUIDAnnotation] = "00000000-0000-0000-0000-000000000000"
+		state, err = vms.ReconcileVM(ctx, vmCtx)
+		g.Expect(err).To(MatchError(ContainSubstring("instance uuid")))
+		g.Expect(state.State).To(Equal(infrav1.VirtualMachineStateNotFound))
+		g.Expect(conditions.GetReason(vmCtx.VSphereVM, infrav1.VMProvisionedCondition)).To(Equal(infrav1.VMToAdoptNotFoundReason))
+		return nil
+	}, model)
+}
+
+func Test_FindVM_InstanceUUIDLookup(t *testing.T) {
+	utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.InstanceUUIDLookup, true)
+
+	g := NewWithT(t)
+	model := simulator.VPX()
+	g.Expect(model.Create()).To(Succeed())
+
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		authSession, err := getAuthSession(ctx, model.Service.Listen.Host)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		finder := find.NewFinder(c)
+		vm, err := finder.VirtualMachine(ctx, "DC0_H0_VM0")
+		g.Expect(err).ToNot(HaveOccurred())
+		otherVM, err := finder.VirtualMachine(ctx, "DC0_H0_VM1")
+		g.Expect(err).ToNot(HaveOccurred())
+		var moVM mo.VirtualMachine
+		g.Expect(vm.Properties(ctx, vm.Reference(), []string{"config.instanceUuid"}, &moVM)).To(Succeed())
+
+		// The BIOS UUID of another VM and the name are ignored once the instance UUID is recorded.
+		vmCtx := &capvcontext.VMContext{
+			ControllerManagerContext: &capvcontext.ControllerManagerContext{},
+			Session:                  authSession,
+			VSphereVM: &infrav1.VSphereVM{
+				ObjectMeta: metav1.ObjectMeta{Name: "DC0_H0_VM1"},
+				Spec:       infrav1.VSphereVMSpec{BiosUUID: otherVM.UUID(ctx)},
+				Status:     infrav1.VSphereVMStatus{InstanceUUID: moVM.Config.InstanceUuid},
+			},
+		}
+		vmRef, err := findVM(ctx, vmCtx)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(vmRef).To(Equal(vm.Reference()))
+
+		// The VM is still found after it has been renamed.
+		task, err := vm.Rename(ctx, "renamed-vm")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(task.Wait(ctx)).To(Succeed())
+		vmRef, err = findVM(ctx, vmCtx)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(vmRef).To(Equal(vm.Reference()))
+		exists, err := (&VMService{}).VMExists(ctx, vmCtx)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(exists).To(BeTrue())
+
+		// A VM which can't be found by its recorded instance UUID is not found by other means.
+		vmCtx.VSphereVM.Status.InstanceUUID = "00000000-0000-0000-0000-000000000000"
+		_, err = findVM(ctx, vmCtx)
+		g.Expect(isNotFound(err)).To(BeTrue())
+		exists, err = (&VMService{}).VMExists(ctx, vmCtx)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(exists).To(BeFalse())
+		return nil
+	}, model)
+}
+
+func Test_DestroyVM_DeleteProtected(t *testing.T) {
+	g := NewWithT(t)
+	model := simulator.VPX()
+	g.Expect(model.Create()).To(Succeed())
+
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		authSession, err := getAuthSession(ctx, model.Service.Listen.Host)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		finder := find.NewFinder(c)
+		vm, err := finder.VirtualMachine(ctx, "DC0_H0_VM0")
+		g.Expect(err).ToNot(HaveOccurred())
+
+		vmCtx := &capvcontext.VMContext{
+			ControllerManagerContext: &capvcontext.ControllerManagerContext{},
+			Session:                  authSession,
+			VSphereVM: &infrav1.VSphereVM{
+				Spec: infrav1.VSphereVMSpec{BiosUUID: vm.UUID(ctx)},
+			},
+			DeleteProtected: true,
+		}
+
+		vms := &VMService{}
+		result, state, err := vms.DestroyVM(ctx, vmCtx)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.IsZero()).To(BeTrue())
+		g.Expect(state.State).To(Equal(infrav1.VirtualMachineStateNotFound))
+		g.Expect(vmCtx.VSphereVM.Status.TaskRef).To(BeEmpty())
+
+		// The VM is left orphaned.
+		powerState, err := vm.PowerState(ctx)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(powerState).To(Equal(types.VirtualMachinePowerStatePoweredOn))
+		return nil
+	}, model)
+}
+
+func Test_BatchedVCenterCalls(t *testing.T) {
+	utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.BatchedVCenterCalls, true)
+
+	g := NewWithT(t)
+	model := simulator.VPX()
+	g.Expect(model.Create()).To(Succeed())
+
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		authSession, err := getAuthSession(ctx, model.Service.Listen.Host)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		vm, err := getPoweredoffVM(ctx, c)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		vmCtx := emptyVirtualMachineContext()
+		vmCtx.Session = authSession
+		vmCtx.Obj = vm
+		vmCtx.Ref = vm.Reference()
+		vmCtx.VSphereVM = &infrav1.VSphereVM{}
+
+		// The properties of the VM are retrieved until they are cached.
+		vms := &VMService{}
+		g.Eventually(func() (infrav1.VirtualMachinePowerState, error) {
+			return vms.getPowerState(ctx, vmCtx)
+		}, 10*time.Second).Should(BeEquivalentTo(infrav1.VirtualMachinePowerStatePoweredOff))
+		cache, err := authSession.PropertyCache(ctx)
+		g.Expect(err).ToNot(HaveOccurred())
+		defer cache.Stop()
+		var o mo.VirtualMachine
+		g.Expect(cache.Get(vm.Reference(), []string{"runtime.powerState"}, &o)).To(BeTrue())
+
+		// The VM is powered on in a batch, and its power state is updated in the cache.
+		task, err := powerOnVM(ctx, vmCtx)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(task.Wait(ctx)).To(Succeed())
+		g.Eventually(func() (infrav1.VirtualMachinePowerState, error) {
+			return vms.getPowerState(ctx, vmCtx)
+		}, 10*time.Second).Should(BeEquivalentTo(infrav1.VirtualMachinePowerStatePoweredOn))
+		return nil
+	}, model)
+}
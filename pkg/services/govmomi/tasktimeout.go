@@ -0,0 +1,135 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/fault"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/tracing"
+)
+
+const (
+	// cloneVMTaskID is the description id of the task cloning a VM.
+	cloneVMTaskID = "VirtualMachine.clone"
+
+	// reconfigureVMTaskID is the description id of the task reconfiguring a VM.
+	reconfigureVMTaskID = "VirtualMachine.reconfigure"
+)
+
+// vmTaskTimeout returns the time after which a clone or reconfigure task of
+// the VSphereVM is canceled, or 0 if tasks are waited for indefinitely.
+func vmTaskTimeout(vmCtx *capvcontext.VMContext) time.Duration {
+	if vmCtx.ControllerManagerContext == nil {
+		return 0
+	}
+	return vmCtx.VMTaskTimeout
+}
+
+// isTimedOutTask reports whether the task is a clone or reconfigure task which
+// was queued longer ago than the VM task timeout.
+func isTimedOutTask(vmCtx *capvcontext.VMContext, task *mo.Task) bool {
+	timeout := vmTaskTimeout(vmCtx)
+	if timeout <= 0 {
+		return false
+	}
+	if task.Info.DescriptionId != cloneVMTaskID && task.Info.DescriptionId != reconfigureVMTaskID {
+		return false
+	}
+	return !task.Info.QueueTime.IsZero() && time.Since(task.Info.QueueTime) > timeout
+}
+
+// isCanceledTask reports whether the task failed because it was canceled.
+func isCanceledTask(task *mo.Task) bool {
+	return task.Info.Error != nil && fault.Is(task.Info.Error, &types.RequestCanceled{})
+}
+
+// cancelTimedOutTask cancels a queued or running clone or reconfigure task
+// which exceeds the VM task timeout, so that the operation is retried instead
+// of a stuck task blocking the VSphereVM indefinitely.
+func cancelTimedOutTask(ctx context.Context, vmCtx *capvcontext.VMContext, task *mo.Task) error {
+	if !isTimedOutTask(vmCtx, task) {
+		return nil
+	}
+	log := ctrl.LoggerFrom(ctx)
+
+	timeout := vmTaskTimeout(vmCtx)
+	conditions.MarkFalse(vmCtx.VSphereVM, infrav1.VMProvisionedCondition, infrav1.TaskTimedOutReason, clusterv1.ConditionSeverityWarning,
+		"%s task did not complete within %s", task.Info.DescriptionId, timeout)
+
+	if vmCtx.InfraPaused {
+		log.Info("Infrastructure is paused, skipping cancellation of timed out task")
+		return nil
+	}
+	if vmCtx.DryRun {
+		log.Info("Dry run: skipping cancellation of timed out task")
+		return nil
+	}
+	if !task.Info.Cancelable {
+		log.Info("Task timed out but can't be canceled, waiting for it to complete", "timeout", timeout)
+		return nil
+	}
+
+	log.Info("Canceling timed out task", "timeout", timeout)
+	if err := object.NewTask(vmCtx.Session.Client.Client, task.Reference()).Cancel(ctx); err != nil {
+		return errors.Wrapf(err, "failed to cancel timed out task %s", task.Reference().Value)
+	}
+	return nil
+}
+
+// cleanupTimedOutClone destroys the VM left behind by a clone task which was
+// canceled after timing out, so that the clone is retried from scratch. The
+// TaskRef is set to the destroy task.
+func cleanupTimedOutClone(ctx context.Context, vmCtx *capvcontext.VMContext, task *mo.Task) error {
+	// The VM of a VSphereVM with a BIOS UUID has been fully provisioned before
+	// and is never destroyed here.
+	if task.Info.DescriptionId != cloneVMTaskID || vmCtx.VSphereVM.Spec.BiosUUID != "" {
+		return nil
+	}
+	if !isTimedOutTask(vmCtx, task) || !isCanceledTask(task) || vmCtx.InfraPaused || vmCtx.DryRun {
+		return nil
+	}
+	log := ctrl.LoggerFrom(ctx)
+
+	objRef, err := vmCtx.Session.FindByInstanceUUID(ctx, string(vmCtx.VSphereVM.UID))
+	if err != nil {
+		return err
+	}
+	if objRef == nil {
+		return nil
+	}
+
+	log.Info("Destroying partially cloned VM of timed out clone task", "vmRef", objRef.Reference().Value)
+	destroyTask, err := object.NewVirtualMachine(vmCtx.Session.Client.Client, objRef.Reference()).Destroy(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "failed to destroy partially cloned VM %s", objRef.Reference().Value)
+	}
+	tracing.SetTaskDescription(ctx, destroyTask)
+	vmCtx.VSphereVM.Status.TaskRef = destroyTask.Reference().Value
+	return nil
+}
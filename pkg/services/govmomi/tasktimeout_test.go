@@ -0,0 +1,154 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+)
+
+func timedOutTask(state types.TaskInfoState, descriptionID string, age time.Duration) mo.Task {
+	task := baseTask(state, "")
+	task.Info.DescriptionId = descriptionID
+	task.Info.QueueTime = time.Now().Add(-age)
+	return task
+}
+
+func Test_isTimedOutTask(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		task    mo.Task
+		want    bool
+	}{
+		{"timeout disabled", 0, timedOutTask(types.TaskInfoStateRunning, cloneVMTaskID, time.Hour), false},
+		{"clone task within the timeout", time.Hour, timedOutTask(types.TaskInfoStateRunning, cloneVMTaskID, time.Minute), false},
+		{"clone task exceeding the timeout", time.Minute, timedOutTask(types.TaskInfoStateRunning, cloneVMTaskID, time.Hour), true},
+		{"reconfigure task exceeding the timeout", time.Minute, timedOutTask(types.TaskInfoStateQueued, reconfigureVMTaskID, time.Hour), true},
+		{"other task exceeding the timeout", time.Minute, timedOutTask(types.TaskInfoStateRunning, relocateVMTaskID, time.Hour), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			vmCtx := &capvcontext.VMContext{
+				ControllerManagerContext: &capvcontext.ControllerManagerContext{VMTaskTimeout: tt.timeout},
+			}
+			g.Expect(isTimedOutTask(vmCtx, &tt.task)).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_checkAndRetryTask_TimedOut(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("a timed out task which can't be canceled is waited for", func(t *testing.T) {
+		g := NewWithT(t)
+		vmCtx := &capvcontext.VMContext{
+			ControllerManagerContext: &capvcontext.ControllerManagerContext{VMTaskTimeout: time.Minute},
+			VSphereVM:                &infrav1.VSphereVM{Status: infrav1.VSphereVMStatus{TaskRef: "task-123"}},
+		}
+		task := timedOutTask(types.TaskInfoStateRunning, cloneVMTaskID, time.Hour)
+
+		reconciled, err := checkAndRetryTask(ctx, vmCtx, &task)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(reconciled).To(BeTrue())
+		g.Expect(vmCtx.VSphereVM.Status.TaskRef).To(Equal("task-123"))
+		g.Expect(conditions.GetReason(vmCtx.VSphereVM, infrav1.VMProvisionedCondition)).To(Equal(infrav1.TaskTimedOutReason))
+	})
+
+	t.Run("a canceled timed out task is retried", func(t *testing.T) {
+		g := NewWithT(t)
+		vmCtx := &capvcontext.VMContext{
+			ControllerManagerContext: &capvcontext.ControllerManagerContext{VMTaskTimeout: time.Minute},
+			VSphereVM:                &infrav1.VSphereVM{Status: infrav1.VSphereVMStatus{TaskRef: "task-123"}},
+		}
+		task := timedOutTask(types.TaskInfoStateError, reconfigureVMTaskID, time.Hour)
+		task.Info.Error = &types.LocalizedMethodFault{Fault: &types.RequestCanceled{}, LocalizedMessage: "The task was canceled by a user"}
+
+		reconciled, err := checkAndRetryTask(ctx, vmCtx, &task)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(reconciled).To(BeTrue())
+		g.Expect(conditions.GetReason(vmCtx.VSphereVM, infrav1.VMProvisionedCondition)).To(Equal(infrav1.TaskTimedOutReason))
+		g.Expect(vmCtx.VSphereVM.Status.RetryAfter.IsZero()).To(BeFalse())
+
+		vmCtx.VSphereVM.Status.RetryAfter = metav1.Time{Time: time.Now().Add(-time.Second)}
+		reconciled, err = checkAndRetryTask(ctx, vmCtx, &task)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(reconciled).To(BeTrue())
+		g.Expect(vmCtx.VSphereVM.Status.TaskRef).To(BeEmpty())
+	})
+}
+
+func Test_cleanupTimedOutClone(t *testing.T) {
+	g := NewWithT(t)
+	model := simulator.VPX()
+	g.Expect(model.Create()).To(Succeed())
+
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		authSession, err := getAuthSession(ctx, model.Service.Listen.Host)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		vm, err := find.NewFinder(c).VirtualMachine(ctx, "DC0_C0_RP0_VM0")
+		g.Expect(err).ToNot(HaveOccurred())
+		task, err := vm.PowerOff(ctx)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(task.Wait(ctx)).To(Succeed())
+		var obj mo.VirtualMachine
+		g.Expect(vm.Properties(ctx, vm.Reference(), []string{"config.instanceUuid"}, &obj)).To(Succeed())
+
+		vmCtx := &capvcontext.VMContext{
+			ControllerManagerContext: &capvcontext.ControllerManagerContext{VMTaskTimeout: time.Minute},
+			Session:                  authSession,
+			VSphereVM: &infrav1.VSphereVM{
+				ObjectMeta: metav1.ObjectMeta{UID: apitypes.UID(obj.Config.InstanceUuid)},
+			},
+		}
+		cloneTask := timedOutTask(types.TaskInfoStateError, cloneVMTaskID, time.Hour)
+		cloneTask.Info.Error = &types.LocalizedMethodFault{Fault: &types.RequestCanceled{}}
+
+		// The VM is not destroyed once it has been provisioned.
+		vmCtx.VSphereVM.Spec.BiosUUID = "bios-uuid"
+		g.Expect(cleanupTimedOutClone(ctx, vmCtx, &cloneTask)).To(Succeed())
+		g.Expect(vmCtx.VSphereVM.Status.TaskRef).To(BeEmpty())
+
+		vmCtx.VSphereVM.Spec.BiosUUID = ""
+		g.Expect(cleanupTimedOutClone(ctx, vmCtx, &cloneTask)).To(Succeed())
+		g.Expect(vmCtx.VSphereVM.Status.TaskRef).NotTo(BeEmpty())
+
+		destroyTask := object.NewTask(c, types.ManagedObjectReference{Type: morefTypeTask, Value: vmCtx.VSphereVM.Status.TaskRef})
+		g.Expect(destroyTask.Wait(ctx)).To(Succeed())
+		objRef, err := authSession.FindByInstanceUUID(ctx, obj.Config.InstanceUuid)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objRef).To(BeNil())
+		return nil
+	}, model)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/vcenter"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/session"
+)
+
+// ResolveImage returns the managed object ID of the VM template of a VSphereVMImage, after
+// verifying that the template exists and that the files of its content library item, if any,
+// match the checksums of the image.
+func ResolveImage(ctx context.Context, s *session.Session, spec infrav1.VSphereVMImageSpec) (string, error) {
+	if spec.ContentLibraryItem == nil {
+		tpl, err := FindTemplate(ctx, s, spec.Template)
+		if err != nil {
+			return "", errors.Wrapf(err, "unable to find template %q", spec.Template)
+		}
+		return tpl.Reference().Value, nil
+	}
+
+	itemRef := spec.ContentLibraryItem
+	libraryManager := library.NewManager(s.TagManager.Client)
+	lib, err := libraryManager.GetLibraryByName(ctx, itemRef.Library)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to find content library %q", itemRef.Library)
+	}
+	ids, err := libraryManager.FindLibraryItems(ctx, library.FindItem{LibraryID: lib.ID, Name: itemRef.Name})
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to find content library item %q in library %q", itemRef.Name, itemRef.Library)
+	}
+	if len(ids) == 0 {
+		return "", errors.Errorf("content library item %q not found in library %q", itemRef.Name, itemRef.Library)
+	}
+	item, err := libraryManager.GetLibraryItem(ctx, ids[0])
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to get content library item %q", itemRef.Name)
+	}
+	if item.Type != library.ItemTypeVMTX {
+		return "", errors.Errorf("content library item %q is of type %q, only %q items are supported", itemRef.Name, item.Type, library.ItemTypeVMTX)
+	}
+
+	if len(spec.Checksums) > 0 {
+		files, err := libraryManager.ListLibraryItemFiles(ctx, item.ID)
+		if err != nil {
+			return "", errors.Wrapf(err, "unable to list the files of content library item %q", itemRef.Name)
+		}
+		for _, checksum := range spec.Checksums {
+			if !hasFileWithChecksum(files, checksum) {
+				return "", errors.Errorf("no file of content library item %q matches the %s checksum %s", itemRef.Name, checksum.Algorithm, checksum.Value)
+			}
+		}
+	}
+
+	info, err := vcenter.NewManager(s.TagManager.Client).GetLibraryTemplateInfo(ctx, item.ID)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to get the VM template of content library item %q", itemRef.Name)
+	}
+	if info.VmTemplate == "" {
+		return "", errors.Errorf("content library item %q does not report its VM template", itemRef.Name)
+	}
+	return info.VmTemplate, nil
+}
+
+// hasFileWithChecksum returns true if one of the files has the checksum.
+func hasFileWithChecksum(files []library.File, checksum infrav1.VSphereVMImageChecksum) bool {
+	for _, file := range files {
+		if file.Checksum == nil {
+			continue
+		}
+		if strings.EqualFold(file.Checksum.Algorithm, string(checksum.Algorithm)) && strings.EqualFold(file.Checksum.Checksum, checksum.Value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/library"
+	_ "github.com/vmware/govmomi/vapi/simulator"
+	"github.com/vmware/govmomi/vapi/vcenter"
+	"github.com/vmware/govmomi/vim25"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/session"
+)
+
+func TestResolveImage(t *testing.T) {
+	model := simulator.VPX()
+	if err := model.Create(); err != nil {
+		t.Fatal(err)
+	}
+	defer model.Remove()
+
+	simulator.Run(func(ctx context.Context, _ *vim25.Client) error {
+		password, _ := simulator.DefaultLogin.Password()
+		s, err := session.GetOrCreate(ctx, session.NewParams().
+			WithServer(fmt.Sprintf("http://%s", model.Service.Listen.Host)).
+			WithUserInfo(simulator.DefaultLogin.Username(), password).
+			WithDatacenter("*"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		vm, err := s.Finder.VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ds, err := s.Finder.Datastore(ctx, "LocalDS_0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		pool, err := s.Finder.ResourcePool(ctx, "/DC0/host/DC0_H0/Resources")
+		if err != nil {
+			t.Fatal(err)
+		}
+		folder, err := s.Finder.Folder(ctx, "/DC0/vm")
+		if err != nil {
+			t.Fatal(err)
+		}
+		libraryID, err := library.NewManager(s.TagManager.Client).CreateLibrary(ctx, library.Library{
+			Name:    "node-images",
+			Type:    "LOCAL",
+			Storage: []library.StorageBacking{{DatastoreID: ds.Reference().Value, Type: "DATASTORE"}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := vcenter.NewManager(s.TagManager.Client).CreateTemplate(ctx, vcenter.Template{
+			Library:  libraryID,
+			Name:     "ubuntu-2204-kube-v1.31.0",
+			SourceVM: vm.Reference().Value,
+			Placement: &vcenter.Placement{
+				Folder:       folder.Reference().Value,
+				ResourcePool: pool.Reference().Value,
+			},
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		tests := []struct {
+			name         string
+			spec         infrav1.VSphereVMImageSpec
+			wantTemplate string
+			wantErr      string
+		}{
+			{
+				name:         "template",
+				spec:         infrav1.VSphereVMImageSpec{Template: "DC0_H0_VM0"},
+				wantTemplate: vm.Reference().Value,
+			},
+			{
+				name:    "template not found",
+				spec:    infrav1.VSphereVMImageSpec{Template: "missing"},
+				wantErr: `unable to find template "missing"`,
+			},
+			{
+				name: "content library not found",
+				spec: infrav1.VSphereVMImageSpec{
+					ContentLibraryItem: &infrav1.ContentLibraryItemReference{Library: "missing", Name: "ubuntu-2204-kube-v1.31.0"},
+				},
+				wantErr: `unable to find content library "missing"`,
+			},
+			{
+				name: "content library item not found",
+				spec: infrav1.VSphereVMImageSpec{
+					ContentLibraryItem: &infrav1.ContentLibraryItemReference{Library: "node-images", Name: "missing"},
+				},
+				wantErr: `content library item "missing" not found in library "node-images"`,
+			},
+			{
+				name: "content library item checksum mismatch",
+				spec: infrav1.VSphereVMImageSpec{
+					ContentLibraryItem: &infrav1.ContentLibraryItemReference{Library: "node-images", Name: "ubuntu-2204-kube-v1.31.0"},
+					Checksums:          []infrav1.VSphereVMImageChecksum{{Algorithm: infrav1.ChecksumAlgorithmSHA256, Value: "0123"}},
+				},
+				wantErr: `no file of content library item "ubuntu-2204-kube-v1.31.0" matches the SHA256 checksum 0123`,
+			},
+			{
+				// vcsim does not report the VM template of VM template library items.
+				name: "content library item without VM template",
+				spec: infrav1.VSphereVMImageSpec{
+					ContentLibraryItem: &infrav1.ContentLibraryItemReference{Library: "node-images", Name: "ubuntu-2204-kube-v1.31.0"},
+				},
+				wantErr: `content library item "ubuntu-2204-kube-v1.31.0" does not report its VM template`,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				g := NewWithT(t)
+				tpl, err := ResolveImage(ctx, s, tt.spec)
+				if tt.wantErr != "" {
+					g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+					return
+				}
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(tpl).To(Equal(tt.wantTemplate))
+			})
+		}
+		return nil
+	}, model)
+}
+
+func TestHasFileWithChecksum(t *testing.T) {
+	g := NewWithT(t)
+	files := []library.File{
+		{Name: "disk-0.vmdk"},
+		{Name: "node-image.ovf", Checksum: &library.Checksum{Algorithm: "SHA256", Checksum: "ABCDEF"}},
+	}
+	g.Expect(hasFileWithChecksum(files, infrav1.VSphereVMImageChecksum{Algorithm: infrav1.ChecksumAlgorithmSHA256, Value: "abcdef"})).To(BeTrue())
+	g.Expect(hasFileWithChecksum(files, infrav1.VSphereVMImageChecksum{Algorithm: infrav1.ChecksumAlgorithmSHA1, Value: "abcdef"})).To(BeFalse())
+	g.Expect(hasFileWithChecksum(files, infrav1.VSphereVMImageChecksum{Algorithm: infrav1.ChecksumAlgorithmSHA256, Value: "012345"})).To(BeFalse())
+}
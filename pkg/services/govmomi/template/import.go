@@ -0,0 +1,256 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"context"
+	"crypto/sha1" //nolint:gosec // SHA1 is used by OVF manifests.
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/ovf/importer"
+	"github.com/vmware/govmomi/vapi/library"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/session"
+)
+
+var (
+	// global import lock map against templates in map[server#datacenter#template]*sync.Mutex,
+	// to avoid importing the same template concurrently.
+	importLocks sync.Map
+
+	// httpClient is the client used to download OVA and OVF files.
+	httpClient = http.DefaultClient
+)
+
+// IsNotFound returns true if the error was returned by FindTemplate because
+// the template doesn't exist.
+func IsNotFound(err error) bool {
+	var notFoundErr *find.NotFoundError
+	return errors.As(err, &notFoundErr)
+}
+
+// ImportTemplate imports the template of the clone spec from its TemplateSource into the
+// datastore, folder and resource pool of the clone spec and marks it as template.
+// The OVA or OVF is downloaded and verified against the checksum before it is imported.
+// If the template was imported concurrently, the existing template is returned.
+func ImportTemplate(ctx context.Context, s *session.Session, spec infrav1.VirtualMachineCloneSpec) (*object.VirtualMachine, error) {
+	source := spec.TemplateSource
+	if source == nil {
+		return nil, errors.Errorf("unable to import template %q: templateSource is not set", spec.Template)
+	}
+	log := ctrl.LoggerFrom(ctx).WithValues("template", spec.Template, "url", source.URL)
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	lock, _ := importLocks.LoadOrStore(fmt.Sprintf("%s#%s#%s", spec.Server, spec.Datacenter, spec.Template), &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	// The template might have been imported while waiting for the lock.
+	tpl, err := FindTemplate(ctx, s, spec.Template)
+	if err == nil {
+		return tpl, nil
+	}
+	if !IsNotFound(err) {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "capv-template-")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create directory to download template")
+	}
+	defer os.RemoveAll(dir)
+
+	log.Info("Downloading template")
+	archive, fpath, err := downloadTemplateSource(ctx, dir, *source)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to download template %q", spec.Template)
+	}
+
+	datastore, err := s.Finder.DatastoreOrDefault(ctx, spec.Datastore)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get datastore to import template %q", spec.Template)
+	}
+	folder, err := s.Finder.FolderOrDefault(ctx, spec.Folder)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get folder to import template %q", spec.Template)
+	}
+	pool, err := s.Finder.ResourcePoolOrDefault(ctx, spec.ResourcePool)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get resource pool to import template %q", spec.Template)
+	}
+
+	name := path.Base(spec.Template)
+	imp := importer.Importer{
+		Log: func(msg string) (int, error) {
+			log.V(4).Info(strings.TrimSpace(msg))
+			return len(msg), nil
+		},
+		Name:         name,
+		Client:       s.Client.Client,
+		Finder:       s.Finder,
+		Datastore:    datastore,
+		ResourcePool: pool,
+		Folder:       folder,
+		Archive:      archive,
+	}
+
+	log.Info("Importing template")
+	ref, err := imp.Import(ctx, fpath, importer.Options{Name: &name})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to import template %q", spec.Template)
+	}
+
+	tpl = object.NewVirtualMachine(s.Client.Client, *ref)
+	if err := tpl.MarkAsTemplate(ctx); err != nil {
+		return nil, errors.Wrapf(err, "failed to mark imported template %q as template", spec.Template)
+	}
+	log.Info("Imported template")
+	return tpl, nil
+}
+
+// downloadTemplateSource downloads and verifies the OVA or the OVF and the files it
+// references into dir. It returns the archive and the path of the file to import.
+func downloadTemplateSource(ctx context.Context, dir string, source infrav1.VirtualMachineTemplateSource) (importer.Archive, string, error) {
+	algorithm, checksum, ok := strings.Cut(source.Checksum, ":")
+	if !ok {
+		return nil, "", errors.Errorf("invalid checksum %q: expected <algorithm>:<hex digest>", source.Checksum)
+	}
+
+	u, err := url.Parse(source.URL)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "invalid URL %q", source.URL)
+	}
+	fileName := path.Base(u.Path)
+
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".ova":
+		fpath := filepath.Join(dir, fileName)
+		if err := downloadFile(ctx, source.URL, fpath, algorithm, checksum); err != nil {
+			return nil, "", err
+		}
+		return &importer.TapeArchive{Path: fpath}, "*.ovf", nil
+	case ".ovf":
+		// The manifest is verified against the checksum and all other files against the manifest.
+		manifestName := strings.TrimSuffix(fileName, path.Ext(fileName)) + ".mf"
+		manifestPath := filepath.Join(dir, manifestName)
+		if err := downloadFile(ctx, resolveURL(u, manifestName), manifestPath, algorithm, checksum); err != nil {
+			return nil, "", err
+		}
+		manifest, err := readManifest(manifestPath)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, ok := manifest[fileName]; !ok {
+			return nil, "", errors.Errorf("manifest %s has no checksum for %s", manifestName, fileName)
+		}
+		for name, sum := range manifest {
+			if name != path.Base(name) {
+				return nil, "", errors.Errorf("manifest %s references file %q outside of the OVF directory", manifestName, name)
+			}
+			if err := downloadFile(ctx, resolveURL(u, name), filepath.Join(dir, name), sum.Algorithm, sum.Checksum); err != nil {
+				return nil, "", err
+			}
+		}
+		fpath := filepath.Join(dir, fileName)
+		return &importer.FileArchive{Path: fpath}, fpath, nil
+	default:
+		return nil, "", errors.Errorf("unsupported file %q: expected an .ova or .ovf file", fileName)
+	}
+}
+
+// resolveURL returns the URL of the file with the given name next to the file of u.
+func resolveURL(u *url.URL, name string) string {
+	return u.ResolveReference(&url.URL{Path: name}).String()
+}
+
+func readManifest(fpath string) (map[string]*library.Checksum, error) {
+	f, err := os.Open(filepath.Clean(fpath))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open manifest %s", filepath.Base(fpath))
+	}
+	defer f.Close()
+	manifest, err := library.ReadManifest(f)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read manifest %s", filepath.Base(fpath))
+	}
+	return manifest, nil
+}
+
+// downloadFile downloads the file at rawURL to fpath and verifies its checksum.
+func downloadFile(ctx context.Context, rawURL, fpath, algorithm, checksum string) error {
+	h, err := newHash(algorithm)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, http.NoBody)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create request for %s", rawURL)
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to download %s", rawURL)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return errors.Errorf("failed to download %s: %s", rawURL, res.Status)
+	}
+
+	f, err := os.Create(filepath.Clean(fpath))
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s", filepath.Base(fpath))
+	}
+	defer f.Close()
+	if _, err := io.Copy(io.MultiWriter(f, h), res.Body); err != nil {
+		return errors.Wrapf(err, "failed to download %s", rawURL)
+	}
+
+	if actual := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(actual, checksum) {
+		return errors.Errorf("checksum mismatch for %s: expected %s, got %s", rawURL, strings.ToLower(checksum), actual)
+	}
+	return nil
+}
+
+func newHash(algorithm string) (hash.Hash, error) {
+	switch strings.ToLower(algorithm) {
+	case "sha1":
+		return sha1.New(), nil //nolint:gosec // SHA1 is used by OVF manifests.
+	case "sha256":
+		return sha256.New(), nil
+	case "sha512":
+		return sha512.New(), nil
+	default:
+		return nil, errors.Errorf("unsupported checksum algorithm %q", algorithm)
+	}
+}
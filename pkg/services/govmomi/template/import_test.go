@@ -0,0 +1,214 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/simulator"
+	_ "github.com/vmware/govmomi/vapi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/session"
+)
+
+const testOVF = `<?xml version="1.0" encoding="UTF-8"?>
+<Envelope xmlns="http://schemas.dmtf.org/ovf/envelope/1"
+          xmlns:ovf="http://schemas.dmtf.org/ovf/envelope/1"
+          xmlns:rasd="http://schemas.dmtf.org/wbem/wscim/1/cim-schema/2/CIM_ResourceAllocationSettingData"
+          xmlns:vssd="http://schemas.dmtf.org/wbem/wscim/1/cim-schema/2/CIM_VirtualSystemSettingData">
+  <References/>
+  <VirtualSystem ovf:id="node-image">
+    <Info>A virtual machine</Info>
+    <Name>node-image</Name>
+    <VirtualHardwareSection>
+      <Info>Virtual hardware requirements</Info>
+      <System>
+        <vssd:ElementName>Virtual Hardware Family</vssd:ElementName>
+        <vssd:InstanceID>0</vssd:InstanceID>
+        <vssd:VirtualSystemType>vmx-13</vssd:VirtualSystemType>
+      </System>
+      <Item>
+        <rasd:AllocationUnits>hertz * 10^6</rasd:AllocationUnits>
+        <rasd:ElementName>2 virtual CPU(s)</rasd:ElementName>
+        <rasd:InstanceID>1</rasd:InstanceID>
+        <rasd:ResourceType>3</rasd:ResourceType>
+        <rasd:VirtualQuantity>2</rasd:VirtualQuantity>
+      </Item>
+      <Item>
+        <rasd:AllocationUnits>byte * 2^20</rasd:AllocationUnits>
+        <rasd:ElementName>2048MB of memory</rasd:ElementName>
+        <rasd:InstanceID>2</rasd:InstanceID>
+        <rasd:ResourceType>4</rasd:ResourceType>
+        <rasd:VirtualQuantity>2048</rasd:VirtualQuantity>
+      </Item>
+    </VirtualHardwareSection>
+  </VirtualSystem>
+</Envelope>
+`
+
+func sha256Sum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func newOVA(t *testing.T) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	w := tar.NewWriter(buf)
+	if err := w.WriteHeader(&tar.Header{Name: "node-image.ovf", Mode: 0o600, Size: int64(len(testOVF))}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte(testOVF)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestImportTemplate(t *testing.T) {
+	ova := newOVA(t)
+	manifest := []byte(fmt.Sprintf("SHA256(node-image.ovf)= %s\n", sha256Sum([]byte(testOVF))))
+	files := map[string][]byte{
+		"/images/node-image.ova": ova,
+		"/images/node-image.ovf": []byte(testOVF),
+		"/images/node-image.mf":  manifest,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		template string
+		source   infrav1.VirtualMachineTemplateSource
+		wantErr  string
+	}{
+		{
+			name:     "OVA",
+			template: "node-image-ova",
+			source: infrav1.VirtualMachineTemplateSource{
+				URL:      server.URL + "/images/node-image.ova",
+				Checksum: "sha256:" + sha256Sum(ova),
+			},
+		},
+		{
+			name:     "OVF with manifest",
+			template: "node-image-ovf",
+			source: infrav1.VirtualMachineTemplateSource{
+				URL:      server.URL + "/images/node-image.ovf",
+				Checksum: "sha256:" + sha256Sum(manifest),
+			},
+		},
+		{
+			name:     "OVA with checksum mismatch",
+			template: "node-image-mismatch",
+			source: infrav1.VirtualMachineTemplateSource{
+				URL:      server.URL + "/images/node-image.ova",
+				Checksum: "sha256:" + sha256Sum([]byte("foo")),
+			},
+			wantErr: "checksum mismatch",
+		},
+		{
+			name:     "missing OVA",
+			template: "node-image-missing",
+			source: infrav1.VirtualMachineTemplateSource{
+				URL:      server.URL + "/images/missing.ova",
+				Checksum: "sha256:" + sha256Sum(ova),
+			},
+			wantErr: "404 Not Found",
+		},
+		{
+			name:     "unsupported file",
+			template: "node-image-vmdk",
+			source: infrav1.VirtualMachineTemplateSource{
+				URL:      server.URL + "/images/node-image.vmdk",
+				Checksum: "sha256:" + sha256Sum(ova),
+			},
+			wantErr: "expected an .ova or .ovf file",
+		},
+	}
+
+	model := simulator.VPX()
+	if err := model.Create(); err != nil {
+		t.Fatal(err)
+	}
+	defer model.Remove()
+
+	simulator.Run(func(ctx context.Context, _ *vim25.Client) error {
+		password, _ := simulator.DefaultLogin.Password()
+		s, err := session.GetOrCreate(ctx, session.NewParams().
+			WithServer(fmt.Sprintf("http://%s", model.Service.Listen.Host)).
+			WithUserInfo(simulator.DefaultLogin.Username(), password).
+			WithDatacenter("*"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				g := NewWithT(t)
+				spec := infrav1.VirtualMachineCloneSpec{
+					Template:       tt.template,
+					TemplateSource: &tt.source,
+					Datastore:      "LocalDS_0",
+					Folder:         "/DC0/vm",
+					ResourcePool:   "/DC0/host/DC0_C0/Resources",
+				}
+
+				_, err := FindTemplate(ctx, s, tt.template)
+				g.Expect(IsNotFound(err)).To(BeTrue())
+
+				tpl, err := ImportTemplate(ctx, s, spec)
+				if tt.wantErr != "" {
+					g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+					return
+				}
+				g.Expect(err).ToNot(HaveOccurred())
+
+				var vm mo.VirtualMachine
+				g.Expect(tpl.Properties(ctx, tpl.Reference(), []string{"name", "config.template"}, &vm)).To(Succeed())
+				g.Expect(vm.Name).To(Equal(tt.template))
+				g.Expect(vm.Config.Template).To(BeTrue())
+
+				found, err := FindTemplate(ctx, s, tt.template)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(found.Reference()).To(Equal(tpl.Reference()))
+			})
+		}
+		return nil
+	}, model)
+}
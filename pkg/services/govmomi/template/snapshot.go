@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// BaseSnapshotName is the name of the snapshots taken of templates for linked clones.
+const BaseSnapshotName = "capv-base"
+
+// baseSnapshotLocks serializes taking base snapshots of the same template, as VSphereVMs
+// are reconciled concurrently.
+var baseSnapshotLocks sync.Map
+
+// EnsureBaseSnapshot returns the snapshot of the template to create linked clones from.
+// A base snapshot is taken if the template has no snapshot, or if the hardware of the template
+// changed after the current base snapshot was taken. Snapshots which are not base snapshots are
+// used as is. Templates are converted to virtual machines in the given resource pool while the
+// snapshot is taken, as snapshots can't be taken of templates.
+func EnsureBaseSnapshot(ctx context.Context, tpl *object.VirtualMachine, pool *object.ResourcePool) (*types.ManagedObjectReference, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	lock, _ := baseSnapshotLocks.LoadOrStore(tpl.Reference().Value, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	var vm mo.VirtualMachine
+	if err := tpl.Properties(ctx, tpl.Reference(), []string{"config.template", "config.hardware", "snapshot"}, &vm); err != nil {
+		return nil, errors.Wrapf(err, "error getting snapshot information for template %s", tpl.Reference())
+	}
+	if vm.Config == nil {
+		return nil, errors.Errorf("failed to get configuration of template %s", tpl.Reference())
+	}
+
+	if current := currentSnapshot(vm.Snapshot); current != nil {
+		if current.Name != BaseSnapshotName {
+			return &current.Snapshot, nil
+		}
+		var snapshot mo.VirtualMachineSnapshot
+		if err := tpl.Properties(ctx, current.Snapshot, []string{"config.hardware"}, &snapshot); err != nil {
+			return nil, errors.Wrapf(err, "error getting configuration of snapshot %s of template %s", current.Snapshot.Value, tpl.Reference())
+		}
+		if !hardwareChanged(snapshot.Config.Hardware, vm.Config.Hardware) {
+			return &current.Snapshot, nil
+		}
+		// Linked clones depend on the previous base snapshot, so it is kept.
+		log.Info("Template changed after the base snapshot was taken, taking a new base snapshot", "snapshot", current.Snapshot.Value)
+	} else {
+		log.Info("Template has no snapshot, taking a base snapshot")
+	}
+
+	if vm.Config.Template {
+		if err := tpl.MarkAsVirtualMachine(ctx, *pool, nil); err != nil {
+			return nil, errors.Wrapf(err, "failed to mark template %s as virtual machine to take a base snapshot", tpl.Reference())
+		}
+	}
+	snapshotRef, err := takeBaseSnapshot(ctx, tpl)
+	if vm.Config.Template {
+		if markErr := tpl.MarkAsTemplate(ctx); markErr != nil {
+			err = kerrors.NewAggregate([]error{err, errors.Wrapf(markErr, "failed to mark %s as template after taking a base snapshot", tpl.Reference())})
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Took base snapshot of template", "snapshot", snapshotRef.Value)
+	return snapshotRef, nil
+}
+
+func takeBaseSnapshot(ctx context.Context, tpl *object.VirtualMachine) (*types.ManagedObjectReference, error) {
+	task, err := tpl.CreateSnapshot(ctx, BaseSnapshotName, "Base snapshot for linked clones taken by Cluster API Provider vSphere", false, false)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to trigger snapshot op for template %s", tpl.Reference())
+	}
+	info, err := task.WaitForResult(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to take base snapshot of template %s", tpl.Reference())
+	}
+	snapshotRef, ok := info.Result.(types.ManagedObjectReference)
+	if !ok {
+		return nil, errors.Errorf("unexpected result %T of snapshot op for template %s", info.Result, tpl.Reference())
+	}
+	return &snapshotRef, nil
+}
+
+// currentSnapshot returns the snapshot tree of the current snapshot, or nil if there is none.
+func currentSnapshot(info *types.VirtualMachineSnapshotInfo) *types.VirtualMachineSnapshotTree {
+	if info == nil || info.CurrentSnapshot == nil {
+		return nil
+	}
+	var find func(trees []types.VirtualMachineSnapshotTree) *types.VirtualMachineSnapshotTree
+	find = func(trees []types.VirtualMachineSnapshotTree) *types.VirtualMachineSnapshotTree {
+		for i := range trees {
+			if trees[i].Snapshot == *info.CurrentSnapshot {
+				return &trees[i]
+			}
+			if tree := find(trees[i].ChildSnapshotList); tree != nil {
+				return tree
+			}
+		}
+		return nil
+	}
+	return find(info.RootSnapshotList)
+}
+
+// hardwareChanged returns true if the CPU, memory, disks or network devices differ.
+func hardwareChanged(snapshot, template types.VirtualHardware) bool {
+	if snapshot.NumCPU != template.NumCPU || snapshot.NumCoresPerSocket != template.NumCoresPerSocket || snapshot.MemoryMB != template.MemoryMB {
+		return true
+	}
+	snapshotDevices, templateDevices := object.VirtualDeviceList(snapshot.Device), object.VirtualDeviceList(template.Device)
+	nic := (*types.VirtualEthernetCard)(nil)
+	if len(snapshotDevices.SelectByType(nic)) != len(templateDevices.SelectByType(nic)) {
+		return true
+	}
+	return !slices.Equal(diskCapacities(snapshotDevices), diskCapacities(templateDevices))
+}
+
+func diskCapacities(devices object.VirtualDeviceList) []int64 {
+	var capacities []int64
+	for _, disk := range devices.SelectByType((*types.VirtualDisk)(nil)) {
+		capacities = append(capacities, disk.(*types.VirtualDisk).CapacityInKB)
+	}
+	return capacities
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestEnsureBaseSnapshot(t *testing.T) {
+	g := NewWithT(t)
+	model := simulator.VPX()
+	g.Expect(model.Create()).To(Succeed())
+
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		finder := find.NewFinder(c)
+		tpl, err := finder.VirtualMachine(ctx, "DC0_C0_RP0_VM0")
+		g.Expect(err).ToNot(HaveOccurred())
+		pool, err := finder.ResourcePool(ctx, "DC0_C0/Resources")
+		g.Expect(err).ToNot(HaveOccurred())
+
+		task, err := tpl.PowerOff(ctx)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(task.Wait(ctx)).To(Succeed())
+		g.Expect(tpl.MarkAsTemplate(ctx)).To(Succeed())
+
+		isTemplate := func() bool {
+			var vm mo.VirtualMachine
+			g.Expect(tpl.Properties(ctx, tpl.Reference(), []string{"config.template"}, &vm)).To(Succeed())
+			return vm.Config.Template
+		}
+
+		var baseSnapshot *types.ManagedObjectReference
+
+		t.Run("takes a base snapshot of a template without snapshot", func(*testing.T) {
+			baseSnapshot, err = EnsureBaseSnapshot(ctx, tpl, pool)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(baseSnapshot).ToNot(BeNil())
+			g.Expect(isTemplate()).To(BeTrue())
+
+			snapshot, err := tpl.FindSnapshot(ctx, BaseSnapshotName)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(*snapshot).To(Equal(*baseSnapshot))
+		})
+
+		t.Run("reuses the base snapshot of an unchanged template", func(*testing.T) {
+			snapshot, err := EnsureBaseSnapshot(ctx, tpl, pool)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(*snapshot).To(Equal(*baseSnapshot))
+		})
+
+		t.Run("takes a new base snapshot of a changed template", func(*testing.T) {
+			g.Expect(tpl.MarkAsVirtualMachine(ctx, *pool, nil)).To(Succeed())
+			task, err := tpl.Reconfigure(ctx, types.VirtualMachineConfigSpec{MemoryMB: 4096})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(task.Wait(ctx)).To(Succeed())
+			g.Expect(tpl.MarkAsTemplate(ctx)).To(Succeed())
+
+			snapshot, err := EnsureBaseSnapshot(ctx, tpl, pool)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(*snapshot).ToNot(Equal(*baseSnapshot))
+			g.Expect(isTemplate()).To(BeTrue())
+			baseSnapshot = snapshot
+		})
+
+		t.Run("uses snapshots which are not base snapshots as is", func(*testing.T) {
+			g.Expect(tpl.MarkAsVirtualMachine(ctx, *pool, nil)).To(Succeed())
+			task, err := tpl.CreateSnapshot(ctx, "custom", "", false, false)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(task.Wait(ctx)).To(Succeed())
+			g.Expect(tpl.MarkAsTemplate(ctx)).To(Succeed())
+
+			snapshot, err := EnsureBaseSnapshot(ctx, tpl, pool)
+			g.Expect(err).ToNot(HaveOccurred())
+			custom, err := tpl.FindSnapshot(ctx, "custom")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(*snapshot).To(Equal(*custom))
+		})
+		return nil
+	}, model)
+}
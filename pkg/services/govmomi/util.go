@@ -34,6 +34,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/event"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/feature"
 	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/govmomi/net"
 )
@@ -56,15 +57,38 @@ func sanitizeIPAddrs(ctx context.Context, ipAddrs []string) []string {
 }
 
 // findVM searches for a VM in one of two ways:
-//  1. If the BIOS UUID is available, then it is used to find the VM.
+//  1. If the BIOS UUID is available, then it is used to find the VM. The BIOS UUID
+//     of a pre-existing VM to adopt can be given in the AdoptVMAnnotation.
 //  2. Lacking the BIOS UUID, the VM is queried by its instance UUID,
-//     which was assigned the value of the VSphereVM resource's UID string.
+//     which was assigned the value of the VSphereVM resource's UID string, or
+//     which is given in the AdoptVMInstanceUUIDAnnotation for a pre-existing VM to adopt.
 //  3. If it is not found by instance UUID, fallback to an inventory path search
 //     using the vm folder path and the VSphereVM name
 func findVM(ctx context.Context, vmCtx *capvcontext.VMContext) (types.ManagedObjectReference, error) {
 	log := ctrl.LoggerFrom(ctx)
 
-	if biosUUID := vmCtx.VSphereVM.Spec.BiosUUID; biosUUID != "" {
+	// Once the instance UUID of the VM is recorded, it is the only identity used to look
+	// up the VM: unlike the BIOS UUID it is unique within a vCenter and unlike the name
+	// it is not changed by renames, so the name is only used until the VM is created.
+	if instanceUUID := vmCtx.VSphereVM.Status.InstanceUUID; instanceUUID != "" && feature.Gates.Enabled(feature.InstanceUUIDLookup) {
+		objRef, err := vmCtx.Session.FindByInstanceUUID(ctx, instanceUUID)
+		if err != nil {
+			return types.ManagedObjectReference{}, err
+		}
+		if objRef == nil {
+			log.Info("VM not found by instance uuid", "instanceUUID", instanceUUID)
+			return types.ManagedObjectReference{}, errNotFound{instanceUUID: instanceUUID}
+		}
+		log.Info("VM found by instance uuid", "vmRef", objRef.Reference())
+		return objRef.Reference(), nil
+	}
+
+	biosUUID := vmCtx.VSphereVM.Spec.BiosUUID
+	if biosUUID == "" {
+		// A pre-existing VM to adopt can be identified by its BIOS UUID.
+		biosUUID = vmCtx.VSphereVM.Annotations[infrav1.AdoptVMAnnotation]
+	}
+	if biosUUID != "" {
 		objRef, err := vmCtx.Session.FindByBIOSUUID(ctx, biosUUID)
 		if err != nil {
 			return types.ManagedObjectReference{}, err
@@ -77,6 +101,21 @@ func findVM(ctx context.Context, vmCtx *capvcontext.VMContext) (types.ManagedObj
 		return objRef.Reference(), nil
 	}
 
+	// A pre-existing VM to adopt can be identified by its instance UUID, it is not
+	// looked up by its inventory path as the instance UUID identifies it unambiguously.
+	if instanceUUID := vmCtx.VSphereVM.Annotations[infrav1.AdoptVMInstanceUUIDAnnotation]; instanceUUID != "" {
+		objRef, err := vmCtx.Session.FindByInstanceUUID(ctx, instanceUUID)
+		if err != nil {
+			return types.ManagedObjectReference{}, err
+		}
+		if objRef == nil {
+			log.Info("VM to adopt not found by instance uuid", "instanceUUID", instanceUUID)
+			return types.ManagedObjectReference{}, errNotFound{instanceUUID: instanceUUID}
+		}
+		log.Info("VM to adopt found by instance uuid", "vmRef", objRef.Reference())
+		return objRef.Reference(), nil
+	}
+
 	instanceUUID := string(vmCtx.VSphereVM.UID)
 	objRef, err := vmCtx.Session.FindByInstanceUUID(ctx, instanceUUID)
 	if err != nil {
@@ -120,6 +159,55 @@ func getTask(ctx context.Context, vmCtx *capvcontext.VMContext) *mo.Task {
 	return &obj
 }
 
+// getVMProperties retrieves properties of the VM. With the BatchedVCenterCalls feature gate,
+// cached properties are read from the property cache of the session, which keeps the properties
+// of the VMs of the session up to date with a single PropertyCollector.
+func getVMProperties(ctx context.Context, virtualMachineCtx *virtualMachineContext, props []string, dst *mo.VirtualMachine) error {
+	if feature.Gates.Enabled(feature.BatchedVCenterCalls) {
+		ok, err := getCachedVMProperties(ctx, virtualMachineCtx, props, dst)
+		if err != nil {
+			ctrl.LoggerFrom(ctx).V(4).Info("Failed to get VM properties from the property cache", "err", err.Error())
+		}
+		if ok {
+			return nil
+		}
+	}
+	return virtualMachineCtx.Obj.Properties(ctx, virtualMachineCtx.Obj.Reference(), props, dst)
+}
+
+// getCachedVMProperties reads properties of the VM from the property cache of the session.
+// It returns false if the properties are not cached yet, and starts watching the VM so
+// that they are for the next reconciles.
+func getCachedVMProperties(ctx context.Context, virtualMachineCtx *virtualMachineContext, props []string, dst *mo.VirtualMachine) (bool, error) {
+	cache, err := virtualMachineCtx.Session.PropertyCache(ctx)
+	if err != nil {
+		return false, err
+	}
+	if ok, err := cache.Get(virtualMachineCtx.Obj.Reference(), props, dst); err != nil || ok {
+		return ok, err
+	}
+	return false, cache.Watch(ctx, virtualMachineCtx.Obj.Reference())
+}
+
+// unwatchVM stops watching the properties of a VM which is no longer managed, e.g. because it is left orphaned.
+func unwatchVM(ctx context.Context, vmCtx *capvcontext.VMContext, vmRef types.ManagedObjectReference) {
+	if !feature.Gates.Enabled(feature.BatchedVCenterCalls) {
+		return
+	}
+	if cache, err := vmCtx.Session.PropertyCache(ctx); err == nil {
+		cache.Unwatch(ctx, vmRef)
+	}
+}
+
+// powerOnVM powers on the VM. With the BatchedVCenterCalls feature gate, VMs of the session
+// which are powered on concurrently are powered on with a single PowerOnMultiVM_Task.
+func powerOnVM(ctx context.Context, virtualMachineCtx *virtualMachineContext) (*object.Task, error) {
+	if feature.Gates.Enabled(feature.BatchedVCenterCalls) {
+		return virtualMachineCtx.Session.PowerOnVM(ctx, virtualMachineCtx.Obj.Reference())
+	}
+	return virtualMachineCtx.Obj.PowerOn(ctx)
+}
+
 // reconcileInFlightTask determines if a task associated to the VSphereVM object
 // is in flight or not.
 func reconcileInFlightTask(ctx context.Context, vmCtx *capvcontext.VMContext) (bool, error) {
@@ -148,13 +236,22 @@ func checkAndRetryTask(ctx context.Context, vmCtx *capvcontext.VMContext, task *
 
 	// Otherwise the course of action is determined by the state of the task.
 	log = log.WithValues("taskRef", task.Reference().Value, "taskState", task.Info.State, "taskDescriptionID", task.Info.DescriptionId)
-	ctx = ctrl.LoggerInto(ctx, log) //nolint:ineffassign,staticcheck // ensure the logger is up-to-date in ctx, even if we currently don't use ctx below.
+	ctx = ctrl.LoggerInto(ctx, log)
 	switch task.Info.State {
 	case types.TaskInfoStateQueued:
 		log.Info("Task found: Task is still pending")
+		if err := cancelTimedOutTask(ctx, vmCtx, task); err != nil {
+			return true, err
+		}
 		return true, nil
 	case types.TaskInfoStateRunning:
 		log.Info("Task found: Task is still running")
+		if err := cancelTimedOutTask(ctx, vmCtx, task); err != nil {
+			return true, err
+		}
+		if task.Info.DescriptionId == relocateVMTaskID && vmCtx.VSphereVM.Status.Relocation != nil {
+			vmCtx.VSphereVM.Status.Relocation.Progress = task.Info.Progress
+		}
 		return true, nil
 	case types.TaskInfoStateSuccess:
 		log.Info("Task found: Task is a success")
@@ -164,13 +261,21 @@ func checkAndRetryTask(ctx context.Context, vmCtx *capvcontext.VMContext, task *
 		log.Info("Task found: Task failed")
 
 		// NOTE: When a task fails there is no simple way to understand which operation is failing (e.g. cloning or powering on)
-		// so we are reporting failures using a dedicated reason until we find a better solution.
+		// so we are reporting failures using the reason of the vCenter fault, or a dedicated reason if the fault is unknown.
 		var errorMessage string
+		reason := infrav1.TaskFailure
 
 		if task.Info.Error != nil {
 			errorMessage = task.Info.Error.LocalizedMessage
+			reason = faultReason(task.Info.Error, infrav1.TaskFailure)
+		}
+		if isTimedOutTask(vmCtx, task) && isCanceledTask(task) {
+			reason = infrav1.TaskTimedOutReason
+		}
+		conditions.MarkFalse(vmCtx.VSphereVM, infrav1.VMProvisionedCondition, reason, clusterv1.ConditionSeverityInfo, errorMessage)
+		if task.Info.DescriptionId == relocateVMTaskID {
+			conditions.MarkFalse(vmCtx.VSphereVM, infrav1.VMRelocatedCondition, infrav1.RelocationFailedReason, clusterv1.ConditionSeverityWarning, errorMessage)
 		}
-		conditions.MarkFalse(vmCtx.VSphereVM, infrav1.VMProvisionedCondition, infrav1.TaskFailure, clusterv1.ConditionSeverityInfo, errorMessage)
 
 		// Instead of directly requeuing the failed task, wait for the RetryAfter duration to pass
 		// before resetting the taskRef from the VSphereVM status.
@@ -179,6 +284,12 @@ func checkAndRetryTask(ctx context.Context, vmCtx *capvcontext.VMContext, task *
 		} else {
 			vmCtx.VSphereVM.Status.TaskRef = ""
 			vmCtx.VSphereVM.Status.RetryAfter = metav1.Time{}
+
+			// A clone canceled because it timed out may leave a partially cloned VM
+			// behind, which is destroyed before the clone is retried.
+			if err := cleanupTimedOutClone(ctx, vmCtx, task); err != nil {
+				return true, err
+			}
 		}
 		return true, nil
 	default:
@@ -593,3 +704,12 @@ func waitForIPAddresses(
 
 	return chanIPAddresses, chanErrs
 }
+
+// isAdoptingVM returns true if the VSphereVM adopts a pre-existing VM instead of cloning a new one.
+func isAdoptingVM(vsphereVM *infrav1.VSphereVM) bool {
+	if _, ok := vsphereVM.Annotations[infrav1.AdoptVMAnnotation]; ok {
+		return true
+	}
+	_, ok := vsphereVM.Annotations[infrav1.AdoptVMInstanceUUIDAnnotation]
+	return ok
+}
@@ -21,22 +21,28 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"path"
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/fault"
+	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/pbm"
 	pbmTypes "github.com/vmware/govmomi/pbm/types"
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
+	"go.opentelemetry.io/otel/attribute"
 	"k8s.io/utils/ptr"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/feature"
 	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/govmomi/extra"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/govmomi/template"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/tracing"
 )
 
 const (
@@ -52,19 +58,27 @@ const (
 // Clone kicks off a clone operation on vCenter to create a new virtual machine. This function does not wait for
 // the virtual machine to be created on the vCenter, which can be resolved by waiting on the task reference stored
 // in VMContext.VSphereVM.Status.TaskRef.
-func Clone(ctx context.Context, vmCtx *capvcontext.VMContext, bootstrapData []byte, format bootstrapv1.Format) error {
+func Clone(ctx context.Context, vmCtx *capvcontext.VMContext, bootstrapData []byte, format bootstrapv1.Format) (reterr error) {
+	ctx, span := tracing.StartSpan(ctx, "vcenter.Clone", vmCtx.VSphereVM, attribute.String("vsphere.template", vmCtx.VSphereVM.Spec.Template))
+	defer func() {
+		tracing.EndSpan(span, reterr)
+	}()
+
 	log := ctrl.LoggerFrom(ctx)
 
 	vmCtx = &capvcontext.VMContext{
 		ControllerManagerContext: vmCtx.ControllerManagerContext,
 		VSphereVM:                vmCtx.VSphereVM,
+		ClusterFolder:            vmCtx.ClusterFolder,
 		Session:                  vmCtx.Session,
 		PatchHelper:              vmCtx.PatchHelper,
+		DryRun:                   vmCtx.DryRun,
 	}
 	log.Info("Starting clone process")
 
 	var extraConfig extra.Config
-	if len(bootstrapData) > 0 {
+	// Bootstrap data delivered by a NoCloud ISO image is attached after the VM has been cloned.
+	if len(bootstrapData) > 0 && vmCtx.VSphereVM.Spec.BootstrapDataDelivery != infrav1.BootstrapDataDeliveryNoCloudISO {
 		log.Info("Applied bootstrap data to VM clone spec")
 		switch format {
 		case bootstrapv1.CloudConfig:
@@ -81,18 +95,44 @@ func Clone(ctx context.Context, vmCtx *capvcontext.VMContext, bootstrapData []by
 	}
 	tpl, err := template.FindTemplate(ctx, vmCtx.GetSession(), vmCtx.VSphereVM.Spec.Template)
 	if err != nil {
-		return err
+		if vmCtx.VSphereVM.Spec.TemplateSource == nil || !template.IsNotFound(err) {
+			return err
+		}
+		if vmCtx.DryRun {
+			log.Info("Dry run: skipping template import and clone", "template", vmCtx.VSphereVM.Spec.Template, "url", vmCtx.VSphereVM.Spec.TemplateSource.URL)
+			return nil
+		}
+		if tpl, err = template.ImportTemplate(ctx, vmCtx.GetSession(), vmCtx.VSphereVM.Spec.VirtualMachineCloneSpec); err != nil {
+			return err
+		}
 	}
 
 	// If a linked clone is requested then a MoRef for a snapshot must be
 	// found with which to perform the linked clone.
 	var snapshotRef *types.ManagedObjectReference
 
-	if vmCtx.VSphereVM.Spec.CloneMode == "" || vmCtx.VSphereVM.Spec.CloneMode == infrav1.LinkedClone {
+	// Encrypted virtual machines cannot be created as linked clones of an unencrypted template,
+	// so a full clone is used unless a linked clone was explicitly requested.
+	if (vmCtx.VSphereVM.Spec.CloneMode == "" && vmCtx.VSphereVM.Spec.Encryption == nil) || vmCtx.VSphereVM.Spec.CloneMode == infrav1.LinkedClone {
 		log.Info("Linked clone requested")
-		// If the name of a snapshot was not provided then find the template's
-		// current snapshot.
-		if snapshotName := vmCtx.VSphereVM.Spec.Snapshot; snapshotName == "" {
+		snapshotName := vmCtx.VSphereVM.Spec.Snapshot
+		switch {
+		case feature.Gates.Enabled(feature.TemplateSnapshotManagement) && snapshotName == "" && !vmCtx.DryRun:
+			// Take a base snapshot of the template if it has none or if the template changed.
+			log.Info("Ensuring base snapshot of template")
+			pool, err := vmCtx.Session.Finder.ResourcePoolOrDefault(ctx, vmCtx.VSphereVM.Spec.ResourcePool)
+			if err != nil {
+				return errors.Wrapf(err, "unable to get resource pool for %q", vmCtx)
+			}
+			if err := vmCtx.Session.CheckResourcePool(pool); err != nil {
+				return err
+			}
+			if snapshotRef, err = template.EnsureBaseSnapshot(ctx, tpl, pool); err != nil {
+				return errors.Wrapf(err, "error ensuring base snapshot of template %s", vmCtx.VSphereVM.Spec.Template)
+			}
+		case snapshotName == "":
+			// If the name of a snapshot was not provided then find the template's
+			// current snapshot.
 			log.Info("Searching for current snapshot")
 			var vm mo.VirtualMachine
 			if err := tpl.Properties(ctx, tpl.Reference(), []string{"snapshot"}, &vm); err != nil {
@@ -101,7 +141,7 @@ func Clone(ctx context.Context, vmCtx *capvcontext.VMContext, bootstrapData []by
 			if vm.Snapshot != nil {
 				snapshotRef = vm.Snapshot.CurrentSnapshot
 			}
-		} else {
+		default:
 			log.Info("Searching for snapshot by name", "snapshotName", snapshotName)
 			var err error
 			snapshotRef, err = tpl.FindSnapshot(ctx, snapshotName)
@@ -123,12 +163,12 @@ func Clone(ctx context.Context, vmCtx *capvcontext.VMContext, bootstrapData []by
 		diskMoveType = linkCloneDiskMoveType
 	}
 
-	folder, err := vmCtx.Session.Finder.FolderOrDefault(ctx, vmCtx.VSphereVM.Spec.Folder)
+	folder, err := getOrCreateFolder(ctx, vmCtx)
 	if err != nil {
 		return errors.Wrapf(err, "unable to get folder for %q", vmCtx)
 	}
 
-	pool, err := vmCtx.Session.Finder.ResourcePoolOrDefault(ctx, vmCtx.VSphereVM.Spec.ResourcePool)
+	pool, err := getOrCreateResourcePool(ctx, vmCtx)
 	if err != nil {
 		return errors.Wrapf(err, "unable to get resource pool for %q", vmCtx)
 	}
@@ -211,10 +251,21 @@ func Clone(ctx context.Context, vmCtx *capvcontext.VMContext, bootstrapData []by
 		Snapshot: snapshotRef,
 	}
 
-	// For PCI devices, the memory for the VM needs to be reserved
-	// We can replace this once we have another way of reserving memory option
-	// exposed via the API types.
-	if len(vmCtx.VSphereVM.Spec.PciDevices) > 0 {
+	// Keep the vAppConfig of the template if vApp properties are requested.
+	if len(vmCtx.VSphereVM.Spec.VAppConfig) > 0 {
+		templateVAppConfig, err := getTemplateVAppConfig(ctx, tpl)
+		if err != nil {
+			return err
+		}
+		log.Info("Applied vApp properties to VM clone spec")
+		spec.Config.VAppConfig = getVAppConfigSpec(templateVAppConfig, vmCtx.VSphereVM.Spec.VAppConfig)
+		spec.Config.VAppConfigRemoved = nil
+	}
+
+	ApplyVirtualMachineResources(spec.Config, vmCtx.VSphereVM.Spec.Resources)
+
+	// For PCI devices and SR-IOV NICs, the memory for the VM needs to be reserved.
+	if len(vmCtx.VSphereVM.Spec.PciDevices) > 0 || hasSRIOVNetworkDevice(vmCtx.VSphereVM.Spec.Network.Devices) {
 		spec.Config.MemoryReservationLockedToMax = ptr.To(true)
 	}
 
@@ -304,12 +355,31 @@ func Clone(ctx context.Context, vmCtx *capvcontext.VMContext, bootstrapData []by
 	spec.Location.Disk = getDiskLocators(disks, *datastoreRef, isLinkedClone)
 	spec.Location.Datastore = datastoreRef
 
-	log.Info(fmt.Sprintf("Cloning Machine with clone mode %s", vmCtx.VSphereVM.Status.CloneMode))
+	if vmCtx.VSphereVM.Spec.Encryption != nil {
+		encryption, err := getEncryptionSpec(ctx, vmCtx.Session.Client.Client, vmCtx.VSphereVM.Spec.Encryption)
+		if err != nil {
+			return errors.Wrapf(err, "unable to get encryption spec for %q", vmCtx)
+		}
+		// The encryption storage policy has to be applied to the VM home and all disks.
+		spec.Config.Crypto = encryption.crypto
+		spec.Location.Profile = encryption.profile
+		for i := range spec.Location.Disk {
+			spec.Location.Disk[i].Profile = encryption.profile
+		}
+	}
+
+	if vmCtx.DryRun {
+		log.Info("Dry run: skipping clone", "template", vmCtx.VSphereVM.Spec.Template, "cloneSpec", redactCloneSpec(spec))
+		return nil
+	}
+
+	log.Info("Cloning Machine", "cloneMode", vmCtx.VSphereVM.Status.CloneMode)
 	task, err := tpl.Clone(ctx, folder, vmCtx.VSphereVM.Name, spec)
 	if err != nil {
 		return errors.Wrapf(err, "error trigging clone op for machine %s", vmCtx)
 	}
 
+	tracing.SetTaskDescription(ctx, task)
 	vmCtx.VSphereVM.Status.TaskRef = task.Reference().Value
 
 	// patch the vsphereVM early to ensure that the task is
@@ -321,6 +391,60 @@ func Clone(ctx context.Context, vmCtx *capvcontext.VMContext, bootstrapData []by
 	return nil
 }
 
+// redactCloneSpec returns a copy of the clone spec without the values of the extra config,
+// as they contain the bootstrap data of the VM.
+func redactCloneSpec(spec types.VirtualMachineCloneSpec) types.VirtualMachineCloneSpec {
+	config := *spec.Config
+	config.ExtraConfig = make([]types.BaseOptionValue, 0, len(spec.Config.ExtraConfig))
+	for _, ec := range spec.Config.ExtraConfig {
+		if optVal := ec.GetOptionValue(); optVal != nil {
+			config.ExtraConfig = append(config.ExtraConfig, &types.OptionValue{Key: optVal.Key, Value: "<redacted>"})
+		}
+	}
+	spec.Config = &config
+	return spec
+}
+
+// getOrCreateFolder returns the folder of the VM. The folder generated by the folder naming strategy
+// of the VSphereCluster is created in its parent folder if it doesn't exist yet.
+func getOrCreateFolder(ctx context.Context, vmCtx *capvcontext.VMContext) (*object.Folder, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	folderPath := vmCtx.VSphereVM.Spec.Folder
+	folder, err := vmCtx.Session.Finder.FolderOrDefault(ctx, folderPath)
+	if err == nil || vmCtx.ClusterFolder == "" || path.Base(folderPath) != vmCtx.ClusterFolder {
+		return folder, err
+	}
+	if _, ok := err.(*find.NotFoundError); !ok {
+		return nil, err
+	}
+
+	parentPath := path.Dir(folderPath)
+	if parentPath == "." {
+		parentPath = ""
+	}
+	parent, err := vmCtx.Session.Finder.FolderOrDefault(ctx, parentPath)
+	if err != nil {
+		return nil, err
+	}
+	if vmCtx.DryRun {
+		log.Info("Dry run: skipping creation of cluster folder", "folder", vmCtx.ClusterFolder, "parent", parent.InventoryPath)
+		return parent, nil
+	}
+
+	log.Info("Creating cluster folder", "folder", vmCtx.ClusterFolder, "parent", parent.InventoryPath)
+	folder, err = parent.CreateFolder(ctx, vmCtx.ClusterFolder)
+	if err != nil {
+		// The folder was created concurrently for another VM of the cluster.
+		if fault.Is(err, &types.DuplicateName{}) {
+			return vmCtx.Session.Finder.Folder(ctx, path.Join(parent.InventoryPath, vmCtx.ClusterFolder))
+		}
+		return nil, errors.Wrapf(err, "failed to create folder %s in %s", vmCtx.ClusterFolder, parent.InventoryPath)
+	}
+	folder.InventoryPath = path.Join(parent.InventoryPath, vmCtx.ClusterFolder)
+	return folder, nil
+}
+
 func newVMFlagInfo() *types.VirtualMachineFlagInfo {
 	diskUUIDEnabled := true
 	return &types.VirtualMachineFlagInfo{
@@ -431,6 +555,10 @@ func createDataDisks(ctx context.Context, dataDiskDefs []infrav1.VSphereDisk, de
 	}
 
 	for i, dataDisk := range dataDiskDefs {
+		// Existing First Class Disks are attached after the VM has been cloned.
+		if dataDisk.ExistingFCD != nil {
+			continue
+		}
 		log.V(2).Info("Adding disk", "name", dataDisk.Name, "spec", dataDisk)
 
 		dev := &types.VirtualDisk{
@@ -516,11 +644,12 @@ func (a *unitNumberAssigner) assign() (int32, error) {
 	return -1, fmt.Errorf("all unit numbers are already in-use")
 }
 
-const ethCardType = "vmxnet3"
+const (
+	ethCardType      = "vmxnet3"
+	sriovEthCardType = "sriov"
+)
 
 func getNetworkSpecs(ctx context.Context, vmCtx *capvcontext.VMContext, devices object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
-	log := ctrl.LoggerFrom(ctx)
-
 	deviceSpecs := []types.BaseVirtualDeviceConfigSpec{}
 
 	// Remove any existing NICs
@@ -534,44 +663,84 @@ func getNetworkSpecs(ctx context.Context, vmCtx *capvcontext.VMContext, devices
 	// Add new NICs based on the machine config.
 	key := int32(-100)
 	for i := range vmCtx.VSphereVM.Spec.Network.Devices {
-		netSpec := &vmCtx.VSphereVM.Spec.Network.Devices[i]
-		ref, err := vmCtx.Session.Finder.Network(ctx, netSpec.NetworkName)
-		if err != nil {
-			return nil, errors.Wrapf(err, "unable to find network %q", netSpec.NetworkName)
-		}
-		backing, err := ref.EthernetCardBackingInfo(ctx)
+		deviceSpec, err := NewNetworkDeviceSpec(ctx, vmCtx, &vmCtx.VSphereVM.Spec.Network.Devices[i], key)
 		if err != nil {
-			return nil, errors.Wrapf(err, "unable to create new ethernet card backing info for network %q on %q", netSpec.NetworkName, vmCtx)
+			return nil, err
 		}
-		dev, err := object.EthernetCardTypes().CreateEthernetCard(ethCardType, backing)
-		if err != nil {
-			return nil, errors.Wrapf(err, "unable to create new ethernet card %q for network %q on %q", ethCardType, netSpec.NetworkName, vmCtx)
+		deviceSpecs = append(deviceSpecs, deviceSpec)
+		key--
+	}
+
+	return deviceSpecs, nil
+}
+
+// hasSRIOVNetworkDevice returns true if one of the network devices is an SR-IOV NIC,
+// which like PCI devices requires the memory of the VM to be reserved.
+func hasSRIOVNetworkDevice(devices []infrav1.NetworkDeviceSpec) bool {
+	for i := range devices {
+		if devices[i].SRIOV != nil {
+			return true
 		}
+	}
+	return false
+}
 
-		// Get the actual NIC object. This is safe to assert without a check
-		// because "object.EthernetCardTypes().CreateEthernetCard" returns a
-		// "types.BaseVirtualEthernetCard" as a "types.BaseVirtualDevice".
-		nic := dev.(types.BaseVirtualEthernetCard).GetVirtualEthernetCard()
+// NewNetworkDeviceSpec returns the spec adding a NIC for the network device to a VM. The
+// key is a temporary device key, which must be negative and unique within a reconfiguration.
+func NewNetworkDeviceSpec(ctx context.Context, vmCtx *capvcontext.VMContext, netSpec *infrav1.NetworkDeviceSpec, key int32) (types.BaseVirtualDeviceConfigSpec, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	ref, err := vmCtx.Session.Finder.Network(ctx, netSpec.NetworkName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to find network %q", netSpec.NetworkName)
+	}
+	backing, err := ref.EthernetCardBackingInfo(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to create new ethernet card backing info for network %q on %q", netSpec.NetworkName, vmCtx)
+	}
+	cardType := ethCardType
+	if netSpec.SRIOV != nil {
+		cardType = sriovEthCardType
+	}
+	dev, err := object.EthernetCardTypes().CreateEthernetCard(cardType, backing)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to create new ethernet card %q for network %q on %q", cardType, netSpec.NetworkName, vmCtx)
+	}
 
-		if netSpec.MACAddr != "" {
-			nic.MacAddress = netSpec.MACAddr
-			// Please see https://www.vmware.com/support/developer/converter-sdk/conv60_apireference/vim.vm.device.VirtualEthernetCard.html#addressType
-			// for the valid values for this field.
-			nic.AddressType = string(types.VirtualEthernetCardMacTypeManual)
-			log.V(4).Info("Configured manual MAC address", "macAddress", nic.MacAddress)
+	if sriovNIC, ok := dev.(*types.VirtualSriovEthernetCard); ok {
+		// Without a physical function, vCenter assigns one from the SR-IOV
+		// device pool of the network when the VM is powered on.
+		if pf := netSpec.SRIOV.PhysicalFunction; pf != "" {
+			sriovNIC.SriovBacking = &types.VirtualSriovEthernetCardSriovBackingInfo{
+				PhysicalFunctionBacking: &types.VirtualPCIPassthroughDeviceBackingInfo{Id: pf},
+			}
 		}
+		// Jumbo frames require the guest to be allowed to change the MTU of the virtual function.
+		if netSpec.MTU != nil {
+			sriovNIC.AllowGuestOSMtuChange = ptr.To(true)
+		}
+	}
 
-		// Assign a temporary device key to ensure that a unique one will be
-		// generated when the device is created.
-		nic.Key = key
+	// Get the actual NIC object. This is safe to assert without a check
+	// because "object.EthernetCardTypes().CreateEthernetCard" returns a
+	// "types.BaseVirtualEthernetCard" as a "types.BaseVirtualDevice".
+	nic := dev.(types.BaseVirtualEthernetCard).GetVirtualEthernetCard()
 
-		deviceSpecs = append(deviceSpecs, &types.VirtualDeviceConfigSpec{
-			Device:    dev,
-			Operation: types.VirtualDeviceConfigSpecOperationAdd,
-		})
-		log.V(4).Info("Created network device", "ethCardType", ethCardType, "networkSpec", netSpec)
-		key--
+	if netSpec.MACAddr != "" {
+		nic.MacAddress = netSpec.MACAddr
+		// Please see https://www.vmware.com/support/developer/converter-sdk/conv60_apireference/vim.vm.device.VirtualEthernetCard.html#addressType
+		// for the valid values for this field.
+		nic.AddressType = string(types.VirtualEthernetCardMacTypeManual)
+		log.V(4).Info("Configured manual MAC address", "macAddress", nic.MacAddress)
 	}
 
-	return deviceSpecs, nil
+	// Assign a temporary device key to ensure that a unique one will be
+	// generated when the device is created.
+	nic.Key = key
+
+	log.V(4).Info("Created network device", "ethCardType", ethCardType, "networkSpec", netSpec)
+	return &types.VirtualDeviceConfigSpec{
+		Device:    dev,
+		Operation: types.VirtualDeviceConfigSpecOperationAdd,
+	}, nil
 }
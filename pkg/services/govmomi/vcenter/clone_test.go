@@ -325,6 +325,162 @@ func validateDiskSpec(t *testing.T, device types.BaseVirtualDeviceConfigSpec, cl
 	}
 }
 
+func TestGetOrCreateFolder(t *testing.T) {
+	model, session, server := initSimulator(t)
+	t.Cleanup(model.Remove)
+	t.Cleanup(server.Close)
+
+	testCases := []struct {
+		name          string
+		folder        string
+		clusterFolder string
+		dryRun        bool
+		expectedPath  string
+		expectErr     bool
+	}{
+		{
+			name:         "existing folder",
+			folder:       "/DC0/vm",
+			expectedPath: "/DC0/vm",
+		},
+		{
+			name:      "missing folder without cluster folder",
+			folder:    "/DC0/vm/missing",
+			expectErr: true,
+		},
+		{
+			name:          "missing folder which is not the cluster folder",
+			folder:        "/DC0/vm/missing/cluster",
+			clusterFolder: "cluster",
+			expectErr:     true,
+		},
+		{
+			name:          "missing cluster folder in dry run",
+			folder:        "/DC0/vm/cluster",
+			clusterFolder: "cluster",
+			dryRun:        true,
+			expectedPath:  "/DC0/vm",
+		},
+		{
+			name:          "missing cluster folder",
+			folder:        "/DC0/vm/cluster",
+			clusterFolder: "cluster",
+			expectedPath:  "/DC0/vm/cluster",
+		},
+		{
+			name:          "existing cluster folder",
+			folder:        "/DC0/vm/cluster",
+			clusterFolder: "cluster",
+			expectedPath:  "/DC0/vm/cluster",
+		},
+		{
+			name:          "missing cluster folder in the default folder",
+			folder:        "other",
+			clusterFolder: "other",
+			expectedPath:  "/DC0/vm/other",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			vmCtx := &capvcontext.VMContext{
+				VSphereVM: &infrav1.VSphereVM{
+					Spec: infrav1.VSphereVMSpec{
+						VirtualMachineCloneSpec: infrav1.VirtualMachineCloneSpec{
+							Folder: tc.folder,
+						},
+					},
+				},
+				ClusterFolder: tc.clusterFolder,
+				Session:       session,
+				DryRun:        tc.dryRun,
+			}
+
+			folder, err := getOrCreateFolder(ctx.TODO(), vmCtx)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if folder.InventoryPath != tc.expectedPath {
+				t.Fatalf("Expected folder %q, got %q", tc.expectedPath, folder.InventoryPath)
+			}
+		})
+	}
+}
+
+func TestNewNetworkDeviceSpec(t *testing.T) {
+	model, session, server := initSimulator(t)
+	t.Cleanup(model.Remove)
+	t.Cleanup(server.Close)
+
+	mtu := int64(9000)
+	testCases := []struct {
+		name                      string
+		device                    infrav1.NetworkDeviceSpec
+		expectSRIOV               bool
+		expectPhysicalFunction    string
+		expectAllowGuestMTUChange bool
+	}{
+		{
+			name:   "vmxnet3 NIC",
+			device: infrav1.NetworkDeviceSpec{NetworkName: "VM Network", MTU: &mtu},
+		},
+		{
+			name:        "SR-IOV NIC with automatic physical function",
+			device:      infrav1.NetworkDeviceSpec{NetworkName: "VM Network", SRIOV: &infrav1.NetworkDeviceSRIOVSpec{}},
+			expectSRIOV: true,
+		},
+		{
+			name:                      "SR-IOV NIC with physical function and MTU",
+			device:                    infrav1.NetworkDeviceSpec{NetworkName: "VM Network", MTU: &mtu, SRIOV: &infrav1.NetworkDeviceSRIOVSpec{PhysicalFunction: "0000:3b:00.0"}},
+			expectSRIOV:               true,
+			expectPhysicalFunction:    "0000:3b:00.0",
+			expectAllowGuestMTUChange: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			vmCtx := &capvcontext.VMContext{
+				VSphereVM: &infrav1.VSphereVM{},
+				Session:   session,
+			}
+
+			deviceSpec, err := NewNetworkDeviceSpec(ctx.TODO(), vmCtx, &tc.device, -100)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			dev := deviceSpec.GetVirtualDeviceConfigSpec().Device
+			sriovNIC, isSRIOV := dev.(*types.VirtualSriovEthernetCard)
+			if isSRIOV != tc.expectSRIOV {
+				t.Fatalf("Expected SR-IOV NIC %t, got %T", tc.expectSRIOV, dev)
+			}
+			if !isSRIOV {
+				if _, ok := dev.(*types.VirtualVmxnet3); !ok {
+					t.Fatalf("Expected vmxnet3 NIC, got %T", dev)
+				}
+				return
+			}
+
+			physicalFunction := ""
+			if sriovNIC.SriovBacking != nil {
+				physicalFunction = sriovNIC.SriovBacking.PhysicalFunctionBacking.Id
+			}
+			if physicalFunction != tc.expectPhysicalFunction {
+				t.Fatalf("Expected physical function %q, got %q", tc.expectPhysicalFunction, physicalFunction)
+			}
+			if allow := sriovNIC.AllowGuestOSMtuChange != nil && *sriovNIC.AllowGuestOSMtuChange; allow != tc.expectAllowGuestMTUChange {
+				t.Fatalf("Expected allowGuestOSMtuChange %t, got %t", tc.expectAllowGuestMTUChange, allow)
+			}
+		})
+	}
+}
+
 func initSimulator(t *testing.T) (*simulator.Model, *session.Session, *simulator.Server) {
 	t.Helper()
 
@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vcenter
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/crypto"
+	"github.com/vmware/govmomi/pbm"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+// encryptionSpec holds the specs required to create an encrypted virtual machine.
+type encryptionSpec struct {
+	profile []types.BaseVirtualMachineProfileSpec
+	crypto  types.BaseCryptoSpec
+}
+
+// getEncryptionSpec resolves the encryption storage policy and the requested or the default
+// key provider of vCenter.
+// The encryption key is not set in the crypto spec, so that vCenter generates it while the
+// virtual machine is cloned. This avoids leaving unused keys in the key provider when a clone fails.
+// It returns an error if vCenter does not have a key provider configured.
+func getEncryptionSpec(ctx context.Context, client *vim25.Client, encryption *infrav1.VirtualMachineEncryptionSpec) (*encryptionSpec, error) {
+	pbmClient, err := pbm.NewClient(ctx, client)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create pbm client")
+	}
+
+	profileID, err := pbmClient.ProfileIDByName(ctx, encryption.StoragePolicyName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get encryption storage policy %s", encryption.StoragePolicyName)
+	}
+
+	keyManager, err := crypto.GetManagerKmip(client)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get crypto manager")
+	}
+
+	providerID := encryption.KeyProviderID
+	if providerID == "" {
+		providerID, err = keyManager.GetDefaultKmsClusterID(ctx, nil, true)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to get default key provider")
+		}
+		if providerID == "" {
+			return nil, errors.New("unable to encrypt virtual machine: no default key provider configured in vCenter")
+		}
+	} else {
+		valid, err := keyManager.IsValidProvider(ctx, providerID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to validate key provider %s", providerID)
+		}
+		if !valid {
+			return nil, errors.Errorf("unable to encrypt virtual machine: key provider %s not found in vCenter", providerID)
+		}
+	}
+
+	return &encryptionSpec{
+		profile: []types.BaseVirtualMachineProfileSpec{
+			&types.VirtualMachineDefinedProfileSpec{ProfileId: profileID},
+		},
+		crypto: &types.CryptoSpecEncrypt{
+			CryptoKeyId: types.CryptoKeyId{
+				ProviderId: &types.KeyProviderId{Id: providerID},
+			},
+		},
+	}, nil
+}
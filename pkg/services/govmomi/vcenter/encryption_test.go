@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vcenter
+
+import (
+	ctx "context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/crypto"
+	_ "github.com/vmware/govmomi/pbm/simulator" // run init func to register the pbm endpoints.
+	"github.com/vmware/govmomi/vim25/types"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func TestGetEncryptionSpec(t *testing.T) {
+	g := NewWithT(t)
+
+	model, session, server := initSimulator(t)
+	t.Cleanup(model.Remove)
+	t.Cleanup(server.Close)
+	client := session.Client.Client
+
+	keyManager, err := crypto.GetManagerKmip(client)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	encryption := &infrav1.VirtualMachineEncryptionSpec{StoragePolicyName: "VM Encryption Policy"}
+
+	// Encryption fails without a key provider.
+	_, err = getEncryptionSpec(ctx.TODO(), client, encryption)
+	g.Expect(err).To(MatchError(ContainSubstring("unable to get default key provider")))
+
+	g.Expect(keyManager.RegisterKmsCluster(ctx.TODO(), "default-provider", types.KmipClusterInfoKmsManagementTypeUnknown)).To(Succeed())
+	g.Expect(keyManager.MarkDefault(ctx.TODO(), "default-provider")).To(Succeed())
+	g.Expect(keyManager.RegisterKmsCluster(ctx.TODO(), "other-provider", types.KmipClusterInfoKmsManagementTypeUnknown)).To(Succeed())
+
+	t.Run("with the default key provider", func(t *testing.T) {
+		g := NewWithT(t)
+		spec, err := getEncryptionSpec(ctx.TODO(), client, encryption)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(spec.profile).To(HaveLen(1))
+		g.Expect(spec.profile[0].(*types.VirtualMachineDefinedProfileSpec).ProfileId).ToNot(BeEmpty())
+		g.Expect(spec.crypto).To(Equal(&types.CryptoSpecEncrypt{
+			CryptoKeyId: types.CryptoKeyId{ProviderId: &types.KeyProviderId{Id: "default-provider"}},
+		}))
+	})
+
+	t.Run("with a key provider", func(t *testing.T) {
+		g := NewWithT(t)
+		spec, err := getEncryptionSpec(ctx.TODO(), client, &infrav1.VirtualMachineEncryptionSpec{
+			StoragePolicyName: "VM Encryption Policy",
+			KeyProviderID:     "other-provider",
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(spec.crypto).To(Equal(&types.CryptoSpecEncrypt{
+			CryptoKeyId: types.CryptoKeyId{ProviderId: &types.KeyProviderId{Id: "other-provider"}},
+		}))
+	})
+
+	t.Run("with an unknown key provider", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := getEncryptionSpec(ctx.TODO(), client, &infrav1.VirtualMachineEncryptionSpec{
+			StoragePolicyName: "VM Encryption Policy",
+			KeyProviderID:     "unknown-provider",
+		})
+		g.Expect(err).To(MatchError(ContainSubstring("key provider unknown-provider not found")))
+	})
+
+	t.Run("with an unknown storage policy", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := getEncryptionSpec(ctx.TODO(), client, &infrav1.VirtualMachineEncryptionSpec{StoragePolicyName: "unknown"})
+		g.Expect(err).To(MatchError(ContainSubstring("unable to get encryption storage policy unknown")))
+	})
+
+	// The key is generated by vCenter during the clone, so no key is left behind in the key provider.
+	keys, err := keyManager.ListKeys(ctx.TODO(), nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(keys).To(BeEmpty())
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vcenter
+
+import (
+	"context"
+	"path"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/fault"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+)
+
+// getOrCreateResourcePool returns the resource pool of the VM. The child resource pool of the
+// clone spec is created in the resource pool of the clone spec if it doesn't exist yet, and its
+// allocations are updated if they changed.
+func getOrCreateResourcePool(ctx context.Context, vmCtx *capvcontext.VMContext) (*object.ResourcePool, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	parent, err := vmCtx.Session.Finder.ResourcePoolOrDefault(ctx, vmCtx.VSphereVM.Spec.ResourcePool)
+	if err != nil {
+		return nil, err
+	}
+	// The child resource pool is a descendant of its parent, so checking the parent is sufficient.
+	if err := vmCtx.Session.CheckResourcePool(parent); err != nil {
+		return nil, err
+	}
+	childSpec := vmCtx.VSphereVM.Spec.ChildResourcePool
+	if childSpec == nil {
+		return parent, nil
+	}
+	configSpec := resourcePoolConfigSpec(childSpec)
+
+	childPath := path.Join(parent.InventoryPath, childSpec.Name)
+	pool, err := vmCtx.Session.Finder.ResourcePool(ctx, childPath)
+	if err == nil {
+		return pool, updateResourcePoolConfig(ctx, vmCtx, pool, configSpec)
+	}
+	if _, ok := err.(*find.NotFoundError); !ok {
+		return nil, err
+	}
+	if vmCtx.DryRun {
+		log.Info("Dry run: skipping creation of child resource pool", "resourcePool", childSpec.Name, "parent", parent.InventoryPath)
+		return parent, nil
+	}
+
+	log.Info("Creating child resource pool", "resourcePool", childSpec.Name, "parent", parent.InventoryPath)
+	pool, err = parent.Create(ctx, childSpec.Name, configSpec)
+	if err != nil {
+		// The resource pool was created concurrently for another VM.
+		if fault.Is(err, &types.DuplicateName{}) {
+			return vmCtx.Session.Finder.ResourcePool(ctx, childPath)
+		}
+		return nil, errors.Wrapf(err, "failed to create resource pool %s in %s", childSpec.Name, parent.InventoryPath)
+	}
+	pool.InventoryPath = childPath
+	return pool, nil
+}
+
+// updateResourcePoolConfig updates the CPU and memory allocations of a child resource pool
+// which differ from the ones of the clone spec.
+func updateResourcePoolConfig(ctx context.Context, vmCtx *capvcontext.VMContext, pool *object.ResourcePool, configSpec types.ResourceConfigSpec) error {
+	var obj mo.ResourcePool
+	if err := pool.Properties(ctx, pool.Reference(), []string{"config"}, &obj); err != nil {
+		return errors.Wrapf(err, "failed to get the config of resource pool %s", pool.InventoryPath)
+	}
+	if allocationEqual(obj.Config.CpuAllocation, configSpec.CpuAllocation) &&
+		allocationEqual(obj.Config.MemoryAllocation, configSpec.MemoryAllocation) {
+		return nil
+	}
+
+	log := ctrl.LoggerFrom(ctx)
+	if vmCtx.DryRun {
+		log.Info("Dry run: skipping update of child resource pool allocations", "resourcePool", pool.InventoryPath)
+		return nil
+	}
+	log.Info("Updating child resource pool allocations", "resourcePool", pool.InventoryPath)
+	if err := pool.UpdateConfig(ctx, "", &configSpec); err != nil {
+		return errors.Wrapf(err, "failed to update the config of resource pool %s", pool.InventoryPath)
+	}
+	return nil
+}
+
+// resourcePoolConfigSpec returns the config spec of a child resource pool.
+func resourcePoolConfigSpec(spec *infrav1.ChildResourcePoolSpec) types.ResourceConfigSpec {
+	return types.ResourceConfigSpec{
+		CpuAllocation:    resourceAllocation(spec.CPU),
+		MemoryAllocation: resourceAllocation(spec.Memory),
+	}
+}
+
+func resourceAllocation(allocation *infrav1.ResourcePoolAllocation) types.ResourceAllocationInfo {
+	info := types.ResourceAllocationInfo{
+		Reservation:           ptr.To[int64](0),
+		ExpandableReservation: ptr.To(true),
+		Limit:                 ptr.To[int64](-1),
+		Shares:                &types.SharesInfo{Level: types.SharesLevelNormal},
+	}
+	if allocation == nil {
+		return info
+	}
+	info.Reservation = ptr.To(allocation.Reservation)
+	if allocation.Limit != nil {
+		info.Limit = ptr.To(*allocation.Limit)
+	}
+	if allocation.Shares != "" {
+		info.Shares.Level = types.SharesLevel(allocation.Shares)
+	}
+	return info
+}
+
+func allocationEqual(current, desired types.ResourceAllocationInfo) bool {
+	return ptr.Deref(current.Reservation, 0) == ptr.Deref(desired.Reservation, 0) &&
+		ptr.Deref(current.Limit, -1) == ptr.Deref(desired.Limit, -1) &&
+		current.Shares != nil && current.Shares.Level == desired.Shares.Level
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vcenter
+
+import (
+	ctx "context"
+	"testing"
+
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"k8s.io/utils/ptr"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+)
+
+func TestGetOrCreateResourcePool(t *testing.T) {
+	model, session, server := initSimulator(t)
+	t.Cleanup(model.Remove)
+	t.Cleanup(server.Close)
+
+	childSpec := &infrav1.ChildResourcePoolSpec{
+		Name: "md-0",
+		CPU:  &infrav1.ResourcePoolAllocation{Shares: infrav1.ResourcePoolSharesLevelHigh, Limit: ptr.To[int64](4000)},
+	}
+
+	testCases := []struct {
+		name              string
+		childResourcePool *infrav1.ChildResourcePoolSpec
+		dryRun            bool
+		expectedPath      string
+		expectedShares    types.SharesLevel
+		expectedLimit     int64
+	}{
+		{
+			name:         "no child resource pool",
+			expectedPath: "/DC0/host/DC0_C0/Resources",
+		},
+		{
+			name:              "missing child resource pool in dry-run mode",
+			childResourcePool: childSpec,
+			dryRun:            true,
+			expectedPath:      "/DC0/host/DC0_C0/Resources",
+		},
+		{
+			name:              "missing child resource pool",
+			childResourcePool: childSpec,
+			expectedPath:      "/DC0/host/DC0_C0/Resources/md-0",
+			expectedShares:    types.SharesLevelHigh,
+			expectedLimit:     4000,
+		},
+		{
+			name:              "existing child resource pool with changed allocations",
+			childResourcePool: &infrav1.ChildResourcePoolSpec{Name: "md-0"},
+			expectedPath:      "/DC0/host/DC0_C0/Resources/md-0",
+			expectedShares:    types.SharesLevelNormal,
+			expectedLimit:     -1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			vmCtx := &capvcontext.VMContext{
+				VSphereVM: &infrav1.VSphereVM{
+					Spec: infrav1.VSphereVMSpec{
+						VirtualMachineCloneSpec: infrav1.VirtualMachineCloneSpec{
+							ResourcePool:      "/DC0/host/DC0_C0/Resources",
+							ChildResourcePool: tc.childResourcePool,
+						},
+					},
+				},
+				Session: session,
+				DryRun:  tc.dryRun,
+			}
+
+			pool, err := getOrCreateResourcePool(ctx.TODO(), vmCtx)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if pool.InventoryPath != tc.expectedPath {
+				t.Fatalf("Expected resource pool %q, got %q", tc.expectedPath, pool.InventoryPath)
+			}
+			if tc.expectedShares == "" {
+				return
+			}
+
+			var obj mo.ResourcePool
+			if err := pool.Properties(ctx.TODO(), pool.Reference(), []string{"config"}, &obj); err != nil {
+				t.Fatalf("Failed to get resource pool config: %v", err)
+			}
+			cpu := obj.Config.CpuAllocation
+			if cpu.Shares.Level != tc.expectedShares {
+				t.Fatalf("Expected CPU shares %q, got %q", tc.expectedShares, cpu.Shares.Level)
+			}
+			if ptr.Deref(cpu.Limit, 0) != tc.expectedLimit {
+				t.Fatalf("Expected CPU limit %d, got %d", tc.expectedLimit, ptr.Deref(cpu.Limit, 0))
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vcenter
+
+import (
+	"github.com/vmware/govmomi/vim25/types"
+	"k8s.io/utils/ptr"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+// ApplyVirtualMachineResources sets the resource allocation of the spec.resources of a
+// VSphereVM in the config spec of its VM. Allocations not set in the spec are left untouched,
+// so that the VM keeps the allocation of its template.
+func ApplyVirtualMachineResources(config *types.VirtualMachineConfigSpec, resources *infrav1.VirtualMachineResources) {
+	if resources == nil {
+		return
+	}
+	if resources.CPU != nil {
+		config.CpuAllocation = VirtualMachineResourceAllocation(resources.CPU)
+	}
+	if resources.Memory != nil {
+		config.MemoryAllocation = VirtualMachineResourceAllocation(resources.Memory)
+	}
+	if resources.MemoryReservationLockedToMax {
+		config.MemoryReservationLockedToMax = ptr.To(true)
+	}
+	if resources.SwapPlacement != "" {
+		config.SwapPlacement = string(resources.SwapPlacement)
+	}
+}
+
+// VirtualMachineResourceAllocation returns the allocation of a resource to a VM.
+// Like for child resource pools, fields which are not set are reset to their defaults.
+func VirtualMachineResourceAllocation(allocation *infrav1.VirtualMachineResourceAllocation) *types.ResourceAllocationInfo {
+	info := &types.ResourceAllocationInfo{
+		Reservation: ptr.To(allocation.Reservation),
+		Limit:       ptr.To[int64](-1),
+		Shares:      &types.SharesInfo{Level: types.SharesLevelNormal},
+	}
+	if allocation.Limit != nil {
+		info.Limit = ptr.To(*allocation.Limit)
+	}
+	if allocation.Shares != "" {
+		info.Shares.Level = types.SharesLevel(allocation.Shares)
+	}
+	return info
+}
+
+// VirtualMachineResourceAllocationEqual returns true if the current allocation of a resource to
+// a VM matches the desired one.
+func VirtualMachineResourceAllocationEqual(current, desired *types.ResourceAllocationInfo) bool {
+	if current == nil {
+		return false
+	}
+	return ptr.Deref(current.Reservation, 0) == ptr.Deref(desired.Reservation, 0) &&
+		ptr.Deref(current.Limit, -1) == ptr.Deref(desired.Limit, -1) &&
+		current.Shares != nil && current.Shares.Level == desired.Shares.Level
+}
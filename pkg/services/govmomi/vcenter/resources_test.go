@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vcenter
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/vim25/types"
+	"k8s.io/utils/ptr"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func TestApplyVirtualMachineResources(t *testing.T) {
+	t.Run("without resources", func(t *testing.T) {
+		g := NewWithT(t)
+		config := &types.VirtualMachineConfigSpec{}
+		ApplyVirtualMachineResources(config, nil)
+		g.Expect(config).To(Equal(&types.VirtualMachineConfigSpec{}))
+	})
+
+	t.Run("with resources", func(t *testing.T) {
+		g := NewWithT(t)
+		config := &types.VirtualMachineConfigSpec{}
+		ApplyVirtualMachineResources(config, &infrav1.VirtualMachineResources{
+			CPU:                          &infrav1.VirtualMachineResourceAllocation{Shares: infrav1.ResourcePoolSharesLevelHigh, Reservation: 2000},
+			MemoryReservationLockedToMax: true,
+			SwapPlacement:                infrav1.VirtualMachineSwapPlacementHostLocal,
+		})
+		g.Expect(config.CpuAllocation).To(Equal(&types.ResourceAllocationInfo{
+			Reservation: ptr.To[int64](2000),
+			Limit:       ptr.To[int64](-1),
+			Shares:      &types.SharesInfo{Level: types.SharesLevelHigh},
+		}))
+		g.Expect(config.MemoryAllocation).To(BeNil())
+		g.Expect(config.MemoryReservationLockedToMax).To(Equal(ptr.To(true)))
+		g.Expect(config.SwapPlacement).To(Equal("hostLocal"))
+	})
+}
+
+func TestVirtualMachineResourceAllocationEqual(t *testing.T) {
+	g := NewWithT(t)
+	desired := VirtualMachineResourceAllocation(&infrav1.VirtualMachineResourceAllocation{Limit: ptr.To[int64](4096)})
+
+	g.Expect(VirtualMachineResourceAllocationEqual(nil, desired)).To(BeFalse())
+	g.Expect(VirtualMachineResourceAllocationEqual(&types.ResourceAllocationInfo{
+		Reservation: ptr.To[int64](0),
+		Limit:       ptr.To[int64](4096),
+		Shares:      &types.SharesInfo{Level: types.SharesLevelNormal, Shares: 20480},
+	}, desired)).To(BeTrue())
+	g.Expect(VirtualMachineResourceAllocationEqual(&types.ResourceAllocationInfo{
+		Reservation: ptr.To[int64](0),
+		Limit:       ptr.To[int64](-1),
+		Shares:      &types.SharesInfo{Level: types.SharesLevelNormal},
+	}, desired)).To(BeFalse())
+}
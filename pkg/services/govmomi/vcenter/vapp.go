@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vcenter
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// vAppPropertyTransport is the OVF environment transport used if the template
+// does not define one, so the vApp properties are visible to the guest.
+const vAppPropertyTransport = "com.vmware.guestInfo"
+
+// getTemplateVAppConfig returns the vApp configuration of the template,
+// which is nil if the template has no vApp configuration.
+func getTemplateVAppConfig(ctx context.Context, tpl *object.VirtualMachine) (*types.VmConfigInfo, error) {
+	var vm mo.VirtualMachine
+	if err := tpl.Properties(ctx, tpl.Reference(), []string{"config.vAppConfig"}, &vm); err != nil {
+		return nil, errors.Wrapf(err, "error getting vApp configuration of template %s", tpl.Reference())
+	}
+	if vm.Config == nil || vm.Config.VAppConfig == nil {
+		return nil, nil
+	}
+	return vm.Config.VAppConfig.GetVmConfigInfo(), nil
+}
+
+// getVAppConfigSpec returns the spec to set the vApp properties on the cloned virtual machine.
+// Properties which exist in the vApp configuration of the template are updated,
+// all other properties are added as string properties.
+func getVAppConfigSpec(templateConfig *types.VmConfigInfo, properties map[string]string) *types.VmConfigSpec {
+	existing := map[string]types.VAppPropertyInfo{}
+	var nextKey int32
+	if templateConfig != nil {
+		for _, property := range templateConfig.Property {
+			existing[property.Id] = property
+			if property.Key >= nextKey {
+				nextKey = property.Key + 1
+			}
+		}
+	}
+
+	// Sort the IDs so the keys of added properties are stable.
+	ids := make([]string, 0, len(properties))
+	for id := range properties {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	spec := &types.VmConfigSpec{}
+	for _, id := range ids {
+		if property, ok := existing[id]; ok {
+			spec.Property = append(spec.Property, types.VAppPropertySpec{
+				ArrayUpdateSpec: types.ArrayUpdateSpec{Operation: types.ArrayUpdateOperationEdit},
+				Info: &types.VAppPropertyInfo{
+					Key:   property.Key,
+					Id:    id,
+					Value: properties[id],
+				},
+			})
+			continue
+		}
+		spec.Property = append(spec.Property, types.VAppPropertySpec{
+			ArrayUpdateSpec: types.ArrayUpdateSpec{Operation: types.ArrayUpdateOperationAdd},
+			Info: &types.VAppPropertyInfo{
+				Key:              nextKey,
+				Id:               id,
+				Type:             "string",
+				UserConfigurable: types.NewBool(true),
+				Value:            properties[id],
+			},
+		})
+		nextKey++
+	}
+
+	if templateConfig == nil || len(templateConfig.OvfEnvironmentTransport) == 0 {
+		spec.OvfEnvironmentTransport = []string{vAppPropertyTransport}
+	}
+	return spec
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vcenter
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestGetVAppConfigSpec(t *testing.T) {
+	t.Run("template without vApp configuration", func(t *testing.T) {
+		g := NewWithT(t)
+		spec := getVAppConfigSpec(nil, map[string]string{"b": "2", "a": "1"})
+
+		g.Expect(spec.OvfEnvironmentTransport).To(ConsistOf(vAppPropertyTransport))
+		g.Expect(spec.Property).To(HaveLen(2))
+		for i, id := range []string{"a", "b"} {
+			g.Expect(spec.Property[i].Operation).To(Equal(types.ArrayUpdateOperationAdd))
+			g.Expect(spec.Property[i].Info.Key).To(Equal(int32(i)))
+			g.Expect(spec.Property[i].Info.Id).To(Equal(id))
+			g.Expect(spec.Property[i].Info.Type).To(Equal("string"))
+		}
+		g.Expect(spec.Property[0].Info.Value).To(Equal("1"))
+		g.Expect(spec.Property[1].Info.Value).To(Equal("2"))
+	})
+
+	t.Run("template with vApp properties", func(t *testing.T) {
+		g := NewWithT(t)
+		templateConfig := &types.VmConfigInfo{
+			OvfEnvironmentTransport: []string{"iso"},
+			Property: []types.VAppPropertyInfo{
+				{Key: 3, Id: "hostname", Type: "string", Value: "template"},
+				{Key: 7, Id: "dns", Type: "string"},
+			},
+		}
+		spec := getVAppConfigSpec(templateConfig, map[string]string{"hostname": "vm", "ntp": "pool.ntp.org"})
+
+		// The transport of the template is kept.
+		g.Expect(spec.OvfEnvironmentTransport).To(BeEmpty())
+		g.Expect(spec.Property).To(HaveLen(2))
+		g.Expect(spec.Property[0].Operation).To(Equal(types.ArrayUpdateOperationEdit))
+		g.Expect(spec.Property[0].Info.Key).To(Equal(int32(3)))
+		g.Expect(spec.Property[0].Info.Id).To(Equal("hostname"))
+		g.Expect(spec.Property[0].Info.Value).To(Equal("vm"))
+		g.Expect(spec.Property[1].Operation).To(Equal(types.ArrayUpdateOperationAdd))
+		g.Expect(spec.Property[1].Info.Key).To(Equal(int32(8)))
+		g.Expect(spec.Property[1].Info.Id).To(Equal("ntp"))
+		g.Expect(spec.Property[1].Info.Value).To(Equal("pool.ntp.org"))
+	})
+}
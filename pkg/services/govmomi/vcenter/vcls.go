@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vcenter
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+const (
+	// VCLSFolderName is the name of the folder vCenter creates for the vSphere Cluster Services (vCLS)
+	// system VMs of a datacenter.
+	VCLSFolderName = "vCLS"
+
+	// vclsExtensionKey and vclsManagedByType identify the ESX Agent Manager which owns the vCLS VMs.
+	vclsExtensionKey  = "com.vmware.vim.eam"
+	vclsManagedByType = "cluster-agent"
+)
+
+// VCLSVMProperties are the VM properties required by IsVCLSVM.
+var VCLSVMProperties = []string{"summary.config.name", "summary.config.managedBy"}
+
+// IsVCLSVM returns true if the VM is a vSphere Cluster Services (vCLS) system VM.
+// vCLS VMs are deployed and owned by vCenter on every DRS cluster since vSphere 7.0 U1,
+// they must never be modified, moved or deleted.
+func IsVCLSVM(vm mo.VirtualMachine) bool {
+	if managedBy := vm.Summary.Config.ManagedBy; managedBy != nil {
+		return managedBy.ExtensionKey == vclsExtensionKey && managedBy.Type == vclsManagedByType
+	}
+	// Fall back to the naming scheme of the vCLS VMs if the managedBy info is not available,
+	// e.g. because the user lacks the privileges to read it.
+	name := vm.Summary.Config.Name
+	return strings.HasPrefix(name, "vCLS-") || strings.HasPrefix(name, "vCLS (")
+}
+
+// IsVCLSInventoryPath returns true if the inventory path refers to the folder of the vCLS VMs.
+func IsVCLSInventoryPath(inventoryPath string) bool {
+	return inventoryPath == VCLSFolderName || strings.HasSuffix(inventoryPath, "/"+VCLSFolderName)
+}
+
+// NonVCLSVMs returns the given VMs without the vCLS system VMs.
+func NonVCLSVMs(ctx context.Context, client *govmomi.Client, refs []types.ManagedObjectReference) ([]types.ManagedObjectReference, error) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+
+	var vms []mo.VirtualMachine
+	if err := client.Retrieve(ctx, refs, VCLSVMProperties, &vms); err != nil {
+		return nil, errors.Wrap(err, "failed to get properties of VMs")
+	}
+	nonVCLSVMs := []types.ManagedObjectReference{}
+	for _, vm := range vms {
+		if !IsVCLSVM(vm) {
+			nonVCLSVMs = append(nonVCLSVMs, vm.Reference())
+		}
+	}
+	return nonVCLSVMs, nil
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vcenter
+
+import (
+	ctx "context"
+	"testing"
+
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestIsVCLSVM(t *testing.T) {
+	testCases := []struct {
+		name     string
+		vm       mo.VirtualMachine
+		expected bool
+	}{
+		{
+			name: "VM managed by the ESX Agent Manager as cluster agent",
+			vm: mo.VirtualMachine{Summary: types.VirtualMachineSummary{Config: types.VirtualMachineConfigSummary{
+				Name:      "vCLS-4c4c4544-0046-3810-8044-b7c04f4d5931",
+				ManagedBy: &types.ManagedByInfo{ExtensionKey: "com.vmware.vim.eam", Type: "cluster-agent"},
+			}}},
+			expected: true,
+		},
+		{
+			name: "VM with vCLS name managed by another extension",
+			vm: mo.VirtualMachine{Summary: types.VirtualMachineSummary{Config: types.VirtualMachineConfigSummary{
+				Name:      "vCLS-my-vm",
+				ManagedBy: &types.ManagedByInfo{ExtensionKey: "com.example.backup", Type: "vm"},
+			}}},
+			expected: false,
+		},
+		{
+			name: "VM with vCLS uuid name without managedBy info",
+			vm: mo.VirtualMachine{Summary: types.VirtualMachineSummary{Config: types.VirtualMachineConfigSummary{
+				Name: "vCLS-4c4c4544-0046-3810-8044-b7c04f4d5931",
+			}}},
+			expected: true,
+		},
+		{
+			name: "VM with legacy vCLS name without managedBy info",
+			vm: mo.VirtualMachine{Summary: types.VirtualMachineSummary{Config: types.VirtualMachineConfigSummary{
+				Name: "vCLS (1)",
+			}}},
+			expected: true,
+		},
+		{
+			name: "regular VM",
+			vm: mo.VirtualMachine{Summary: types.VirtualMachineSummary{Config: types.VirtualMachineConfigSummary{
+				Name: "cluster-md-0-7d9f5",
+			}}},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := IsVCLSVM(tc.vm); actual != tc.expected {
+				t.Fatalf("Expected %t, got %t", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestIsVCLSInventoryPath(t *testing.T) {
+	testCases := map[string]bool{
+		"/DC0/vm/vCLS":         true,
+		"vCLS":                 true,
+		"/DC0/vm/vCLS-cluster": false,
+		"/DC0/vm/capv/vCLSx":   false,
+		"/DC0/vm":              false,
+	}
+	for inventoryPath, expected := range testCases {
+		if actual := IsVCLSInventoryPath(inventoryPath); actual != expected {
+			t.Fatalf("Expected %t for %q, got %t", expected, inventoryPath, actual)
+		}
+	}
+}
+
+func TestNonVCLSVMs(t *testing.T) {
+	model, session, server := initSimulator(t)
+	t.Cleanup(model.Remove)
+	t.Cleanup(server.Close)
+
+	vms, err := session.Finder.VirtualMachineList(ctx.TODO(), "*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vms) < 2 {
+		t.Fatalf("Expected at least 2 VMs, got %d", len(vms))
+	}
+
+	vclsVM := vms[0]
+	task, err := vclsVM.Reconfigure(ctx.TODO(), types.VirtualMachineConfigSpec{
+		ManagedBy: &types.ManagedByInfo{ExtensionKey: "com.vmware.vim.eam", Type: "cluster-agent"},
+	})
+	if err == nil {
+		err = task.Wait(ctx.TODO())
+	}
+	if err != nil {
+		t.Fatalf("Failed to reconfigure VM: %v", err)
+	}
+
+	refs := []types.ManagedObjectReference{}
+	for _, vm := range vms {
+		refs = append(refs, vm.Reference())
+	}
+	nonVCLSVMs, err := NonVCLSVMs(ctx.TODO(), session.Client, refs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(nonVCLSVMs) != len(vms)-1 {
+		t.Fatalf("Expected %d VMs, got %d", len(vms)-1, len(nonVCLSVMs))
+	}
+	for _, ref := range nonVCLSVMs {
+		if ref == vclsVM.Reference() {
+			t.Fatalf("Expected vCLS VM %s to be filtered out", ref.Value)
+		}
+	}
+
+	empty, err := NonVCLSVMs(ctx.TODO(), session.Client, nil)
+	if err != nil || len(empty) != 0 {
+		t.Fatalf("Expected no VMs and no error, got %v and %v", empty, err)
+	}
+}
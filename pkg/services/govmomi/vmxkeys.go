@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/govmomi/extra"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/tracing"
+)
+
+// reconcileCustomVMXKeys sets the extraConfig of the VM to the values of spec.customVMXKeys
+// which were changed in the spec or out-of-band after the VM was cloned. Keys removed from
+// the spec are left untouched. With the report drift policy the keys are only reported as
+// drifted by reconcileConfigurationDrift. It returns false if the VM is being reconfigured.
+func (vms *VMService) reconcileCustomVMXKeys(ctx context.Context, virtualMachineCtx *virtualMachineContext) (bool, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	vsphereVM := virtualMachineCtx.VSphereVM
+	if len(vsphereVM.Spec.CustomVMXKeys) == 0 || vsphereVM.Spec.DriftPolicy == infrav1.VirtualMachineDriftPolicyReport {
+		return true, nil
+	}
+
+	var vm mo.VirtualMachine
+	if err := getVMProperties(ctx, virtualMachineCtx, []string{"config.extraConfig"}, &vm); err != nil {
+		return false, errors.Wrapf(err, "failed to get extraConfig of vm %s", virtualMachineCtx)
+	}
+	if vm.Config == nil {
+		return false, errors.Errorf("failed to get extraConfig of vm %s", virtualMachineCtx)
+	}
+
+	outOfSync := getOutOfSyncCustomVMXKeys(vsphereVM.Spec.CustomVMXKeys, vm.Config.ExtraConfig)
+	if len(outOfSync) == 0 {
+		return true, nil
+	}
+	if skipForDryRun(ctx, virtualMachineCtx, "custom VMX keys update", "customVMXKeys", outOfSync) {
+		return true, nil
+	}
+
+	var extraConfig extra.Config
+	values := map[string]string{}
+	for _, key := range outOfSync {
+		values[key] = vsphereVM.Spec.CustomVMXKeys[key]
+	}
+	if err := extraConfig.SetCustomVMXKeys(values); err != nil {
+		return false, err
+	}
+
+	log.Info("Updating custom VMX keys", "customVMXKeys", outOfSync)
+	task, err := virtualMachineCtx.Obj.Reconfigure(ctx, types.VirtualMachineConfigSpec{
+		ExtraConfig: extraConfig,
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to trigger reconfigure op to update custom VMX keys of vm %s", virtualMachineCtx)
+	}
+	tracing.SetTaskDescription(ctx, task)
+	vsphereVM.Status.TaskRef = task.Reference().Value
+	return false, nil
+}
+
+// getOutOfSyncCustomVMXKeys returns the sorted custom VMX keys whose value in the extraConfig
+// of the VM differs from the spec.
+func getOutOfSyncCustomVMXKeys(customVMXKeys map[string]string, extraConfig []types.BaseOptionValue) []string {
+	values := object.OptionValueList(extraConfig)
+	outOfSync := []string{}
+	for key, value := range customVMXKeys {
+		if actual, ok := values.GetString(key); ok && actual == value {
+			continue
+		}
+		outOfSync = append(outOfSync, key)
+	}
+	sort.Strings(outOfSync)
+	return outOfSync
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package govmomi
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func TestGetOutOfSyncCustomVMXKeys(t *testing.T) {
+	g := NewWithT(t)
+
+	extraConfig := []types.BaseOptionValue{
+		&types.OptionValue{Key: "disk.EnableUUID", Value: "TRUE"},
+		&types.OptionValue{Key: "sched.cpu.latencySensitivity", Value: "normal"},
+	}
+	g.Expect(getOutOfSyncCustomVMXKeys(map[string]string{
+		"disk.EnableUUID":              "TRUE",
+		"sched.cpu.latencySensitivity": "high",
+		"svga.present":                 "FALSE",
+	}, extraConfig)).To(Equal([]string{"sched.cpu.latencySensitivity", "svga.present"}))
+	g.Expect(getOutOfSyncCustomVMXKeys(map[string]string{"disk.EnableUUID": "TRUE"}, extraConfig)).To(BeEmpty())
+}
+
+func Test_reconcileCustomVMXKeys(t *testing.T) {
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		finder := find.NewFinder(c)
+		vm, err := finder.VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			return err
+		}
+
+		newVirtualMachineContext := func(driftPolicy infrav1.VirtualMachineDriftPolicy) *virtualMachineContext {
+			vmCtx := emptyVirtualMachineContext()
+			vmCtx.Obj = vm
+			vmCtx.Ref = vm.Reference()
+			vmCtx.VSphereVM = &infrav1.VSphereVM{
+				Spec: infrav1.VSphereVMSpec{
+					VirtualMachineCloneSpec: infrav1.VirtualMachineCloneSpec{
+						CustomVMXKeys: map[string]string{"disk.EnableUUID": "TRUE"},
+						DriftPolicy:   driftPolicy,
+					},
+				},
+			}
+			return vmCtx
+		}
+		getValue := func(g *WithT, key string) (string, bool) {
+			var o mo.VirtualMachine
+			g.Expect(vm.Properties(ctx, vm.Reference(), []string{"config.extraConfig"}, &o)).To(Succeed())
+			return object.OptionValueList(o.Config.ExtraConfig).GetString(key)
+		}
+
+		vms := &VMService{}
+
+		t.Run("does not update the keys with the report drift policy", func(t *testing.T) {
+			g := NewWithT(t)
+			vmCtx := newVirtualMachineContext(infrav1.VirtualMachineDriftPolicyReport)
+
+			ok, err := vms.reconcileCustomVMXKeys(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeTrue())
+			_, found := getValue(g, "disk.EnableUUID")
+			g.Expect(found).To(BeFalse())
+		})
+
+		t.Run("updates the keys which are out of sync", func(t *testing.T) {
+			g := NewWithT(t)
+			vmCtx := newVirtualMachineContext("")
+
+			ok, err := vms.reconcileCustomVMXKeys(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeFalse())
+			g.Expect(vmCtx.VSphereVM.Status.TaskRef).ToNot(BeEmpty())
+
+			task := object.NewTask(c, types.ManagedObjectReference{Type: morefTypeTask, Value: vmCtx.VSphereVM.Status.TaskRef})
+			g.Expect(task.Wait(ctx)).To(Succeed())
+			value, _ := getValue(g, "disk.EnableUUID")
+			g.Expect(value).To(Equal("TRUE"))
+
+			vmCtx = newVirtualMachineContext("")
+			ok, err = vms.reconcileCustomVMXKeys(ctx, vmCtx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(BeTrue())
+			g.Expect(vmCtx.VSphereVM.Status.TaskRef).To(BeEmpty())
+		})
+
+		t.Run("fails for keys managed by CAPV", func(t *testing.T) {
+			g := NewWithT(t)
+			vmCtx := newVirtualMachineContext("")
+			vmCtx.VSphereVM.Spec.CustomVMXKeys = map[string]string{"guestinfo.userdata": "foo"}
+
+			_, err := vms.reconcileCustomVMXKeys(ctx, vmCtx)
+			g.Expect(err).To(HaveOccurred())
+		})
+		return nil
+	})
+}
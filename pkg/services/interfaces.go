@@ -50,6 +50,9 @@ type VirtualMachineService interface {
 
 	// DestroyVM powers off and removes a VM from the inventory.
 	DestroyVM(ctx context.Context, vmCtx *capvcontext.VMContext) (reconcile.Result, infrav1.VirtualMachine, error)
+
+	// VMExists reports whether the VM can be found by its instance UUID, or by its BIOS UUID if known.
+	VMExists(ctx context.Context, vmCtx *capvcontext.VMContext) (bool, error)
 }
 
 // ControlPlaneEndpointService is a service for reconciling load balanced control plane endpoints.
@@ -91,3 +94,25 @@ type NetworkProvider interface {
 	// VerifyNetworkStatus verifies the status of the network after vnet creation
 	VerifyNetworkStatus(ctx context.Context, clusterCtx *vmware.ClusterContext, obj runtime.Object) error
 }
+
+// DNSRecord is a DNS record published for a control plane endpoint.
+type DNSRecord struct {
+	// Hostname is the fully qualified DNS name of the record.
+	Hostname string
+
+	// Target is the IP address or the hostname the record points to.
+	Target string
+
+	// TTL is the time to live in seconds of the record; the default TTL of the DNS provider is used if 0.
+	TTL int64
+}
+
+// DNSProvider publishes DNS records for control plane endpoints.
+type DNSProvider interface {
+	// EnsureRecord creates the record, or updates it if it already exists with different targets.
+	// This operation should be idempotent.
+	EnsureRecord(ctx context.Context, record DNSRecord) error
+
+	// DeleteRecord deletes the record with the given hostname, if it exists.
+	DeleteRecord(ctx context.Context, hostname string) error
+}
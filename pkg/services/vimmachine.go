@@ -20,6 +20,7 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -29,6 +30,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	clusterutilv1 "sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/conditions"
@@ -38,6 +40,7 @@ import (
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
 	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/tracing"
 	infrautilv1 "sigs.k8s.io/cluster-api-provider-vsphere/pkg/util"
 )
 
@@ -152,6 +155,29 @@ func (v *VimMachineService) ReconcileNormal(ctx context.Context, machineCtx capv
 		return false, err
 	}
 
+	// Assign a failure domain before the VSphereVM is created, so the placement of
+	// an existing VSphereVM never changes.
+	if vsphereVM == nil {
+		assigned, err := v.reconcileFailureDomain(ctx, vimMachineCtx)
+		if err != nil {
+			return false, err
+		}
+		if assigned {
+			// Requeue to persist the failure domain before creating the VSphereVM.
+			return true, nil
+		}
+
+		if vimMachineCtx.VSphereMachine.Spec.ImageRef != nil {
+			tpl, err := v.getImageTemplate(ctx, vimMachineCtx)
+			if err != nil {
+				return false, err
+			}
+			if tpl == "" {
+				return true, nil
+			}
+		}
+	}
+
 	log = log.WithValues("VSphereVM", klog.KObj(vsphereVM))
 	ctx = ctrl.LoggerInto(ctx, log)
 	vm, err := v.createOrPatchVSphereVM(ctx, vimMachineCtx, vsphereVM)
@@ -159,6 +185,19 @@ func (v *VimMachineService) ReconcileNormal(ctx context.Context, machineCtx capv
 		return false, err
 	}
 
+	// Reflect the in-place resize of the VSphereVM, as it is triggered by changes to the VSphereMachine.
+	if resized := conditions.Get(vm, infrav1.VMResizedCondition); resized != nil {
+		conditions.Set(vimMachineCtx.VSphereMachine, resized)
+	} else {
+		conditions.Delete(vimMachineCtx.VSphereMachine, infrav1.VMResizedCondition)
+	}
+	// Reflect the hot plug of network devices, as it is triggered by changes to the VSphereMachine.
+	if synced := conditions.Get(vm, infrav1.VMNetworkDevicesSyncedCondition); synced != nil {
+		conditions.Set(vimMachineCtx.VSphereMachine, synced)
+	} else {
+		conditions.Delete(vimMachineCtx.VSphereMachine, infrav1.VMNetworkDevicesSyncedCondition)
+	}
+
 	// Waits the VM's ready state.
 	if !vm.Status.Ready {
 		log.Info("Waiting for VSphereVM to become ready")
@@ -197,10 +236,14 @@ func (v *VimMachineService) GetHostInfo(ctx context.Context, machineCtx capvcont
 		return "", errors.New("received unexpected VIMMachineContext type")
 	}
 
+	vmName, err := generateVMObjectName(vimMachineCtx, vimMachineCtx.Machine.Name)
+	if err != nil {
+		return "", err
+	}
 	vsphereVM := &infrav1.VSphereVM{}
 	if err := v.Client.Get(ctx, client.ObjectKey{
 		Namespace: vimMachineCtx.VSphereMachine.Namespace,
-		Name:      generateVMObjectName(vimMachineCtx, vimMachineCtx.Machine.Name),
+		Name:      vmName,
 	}, vsphereVM); err != nil {
 		return "", err
 	}
@@ -214,10 +257,14 @@ func (v *VimMachineService) GetHostInfo(ctx context.Context, machineCtx capvcont
 
 func (v *VimMachineService) findVSphereVM(ctx context.Context, vimMachineCtx *capvcontext.VIMMachineContext) (*infrav1.VSphereVM, error) {
 	// Get ready to find the associated VSphereVM resource.
+	vmName, err := generateVMObjectName(vimMachineCtx, vimMachineCtx.Machine.Name)
+	if err != nil {
+		return nil, err
+	}
 	vm := &infrav1.VSphereVM{}
 	vmKey := types.NamespacedName{
 		Namespace: vimMachineCtx.VSphereMachine.Namespace,
-		Name:      generateVMObjectName(vimMachineCtx, vimMachineCtx.Machine.Name),
+		Name:      vmName,
 	}
 	// Attempt to find the associated VSphereVM resource.
 	if err := v.Client.Get(ctx, vmKey, vm); err != nil {
@@ -300,11 +347,15 @@ func (v *VimMachineService) reconcileNetwork(ctx context.Context, vimMachineCtx
 
 func (v *VimMachineService) createOrPatchVSphereVM(ctx context.Context, vimMachineCtx *capvcontext.VIMMachineContext, vsphereVM *infrav1.VSphereVM) (*infrav1.VSphereVM, error) {
 	log := ctrl.LoggerFrom(ctx)
+	vmName, err := generateVMObjectName(vimMachineCtx, vimMachineCtx.Machine.Name)
+	if err != nil {
+		return nil, err
+	}
 	// Create or update the VSphereVM resource.
 	vm := &infrav1.VSphereVM{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: vimMachineCtx.VSphereMachine.Namespace,
-			Name:      generateVMObjectName(vimMachineCtx, vimMachineCtx.Machine.Name),
+			Name:      vmName,
 		},
 	}
 	mutateFn := func() (err error) {
@@ -327,6 +378,12 @@ func (v *VimMachineService) createOrPatchVSphereVM(ctx context.Context, vimMachi
 			Namespace:  vimMachineCtx.Machine.ObjectMeta.Namespace,
 		}
 
+		// Link the reconciles of a new VSphereVM to the trace of the VSphereMachine reconcile
+		// which created it, so a machine creation can be traced across controllers.
+		if vm.CreationTimestamp.IsZero() {
+			tracing.SetTraceParentAnnotation(ctx, vm)
+		}
+
 		// Initialize the VSphereVM's labels map if it is nil.
 		if vm.Labels == nil {
 			vm.Labels = map[string]string{}
@@ -342,10 +399,35 @@ func (v *VimMachineService) createOrPatchVSphereVM(ctx context.Context, vimMachi
 			vm.Labels[clusterv1.MachineControlPlaneLabel] = val
 		}
 
+		// Propagate the annotations controlling the adoption and the deletion of the VM.
+		for _, annotation := range []string{infrav1.AdoptVMAnnotation, infrav1.AdoptVMInstanceUUIDAnnotation, infrav1.DeleteProtectionAnnotation} {
+			if val, ok := vimMachineCtx.VSphereMachine.Annotations[annotation]; ok {
+				if vm.Annotations == nil {
+					vm.Annotations = map[string]string{}
+				}
+				vm.Annotations[annotation] = val
+			}
+		}
+
 		// Copy the VSphereMachine's VM clone spec into the VSphereVM's
 		// clone spec.
+		template := vm.Spec.Template
 		vimMachineCtx.VSphereMachine.Spec.VirtualMachineCloneSpec.DeepCopyInto(&vm.Spec.VirtualMachineCloneSpec)
 
+		// The VM template of the referenced VSphereVMImage is resolved when the VSphereVM is created,
+		// so that existing VSphereVMs are not affected by changes of the image.
+		if vimMachineCtx.VSphereMachine.Spec.ImageRef != nil {
+			if template == "" {
+				if template, err = v.getImageTemplate(ctx, vimMachineCtx); err != nil {
+					return err
+				}
+				if template == "" {
+					return errors.Errorf("VSphereVMImage %s is not ready", vimMachineCtx.VSphereMachine.Spec.ImageRef.Name)
+				}
+			}
+			vm.Spec.Template = template
+		}
+
 		// If Failure Domain is present on CAPI machine, use that to override the vm clone spec.
 		if overrideFunc, ok := v.generateOverrideFunc(ctx, vimMachineCtx); ok {
 			overrideFunc(vm)
@@ -354,7 +436,7 @@ func (v *VimMachineService) createOrPatchVSphereVM(ctx context.Context, vimMachi
 		// Several of the VSphereVM's clone spec properties can be derived
 		// from multiple places. The order is:
 		//
-		//   1. From the Machine.Spec.FailureDomain
+		//   1. From the Machine.Spec.FailureDomain or VSphereMachine.Status.FailureDomain
 		//   2. From the VSphereMachine.Spec (the DeepCopyInto above)
 		//   3. From the VSphereCluster.Spec
 		if vm.Spec.Server == "" {
@@ -363,11 +445,28 @@ func (v *VimMachineService) createOrPatchVSphereVM(ctx context.Context, vimMachi
 		if vm.Spec.Thumbprint == "" {
 			vm.Spec.Thumbprint = vimMachineCtx.VSphereCluster.Spec.Thumbprint
 		}
+		for name, value := range vimMachineCtx.VSphereCluster.Spec.CustomAttributes {
+			if vm.Spec.CustomAttributes == nil {
+				vm.Spec.CustomAttributes = map[string]string{}
+			}
+			if _, ok := vm.Spec.CustomAttributes[name]; !ok {
+				vm.Spec.CustomAttributes[name] = value
+			}
+		}
 		if vsphereVM != nil {
 			vm.Spec.BiosUUID = vsphereVM.Spec.BiosUUID
+			// The folder naming strategy only applies to VMs created after it was set.
+			vm.Spec.Folder = vsphereVM.Spec.Folder
+		} else {
+			folder, err := infrautilv1.ClusterFolder(vimMachineCtx.VSphereCluster, vm.Spec.Folder)
+			if err != nil {
+				return err
+			}
+			vm.Spec.Folder = folder
 		}
 		vm.Spec.PowerOffMode = vimMachineCtx.VSphereMachine.Spec.PowerOffMode
 		vm.Spec.GuestSoftPowerOffTimeout = vimMachineCtx.VSphereMachine.Spec.GuestSoftPowerOffTimeout
+		vm.Spec.GuestReadinessGates = vimMachineCtx.VSphereMachine.Spec.GuestReadinessGates
 		return nil
 	}
 
@@ -391,21 +490,125 @@ func (v *VimMachineService) createOrPatchVSphereVM(ctx context.Context, vimMachi
 	return vm, nil
 }
 
-// generateVMObjectName returns a new VM object name in specific cases, otherwise return the same
-// passed in the parameter.
-func generateVMObjectName(vimMachineCtx *capvcontext.VIMMachineContext, machineName string) string {
+// getImageTemplate returns the VM template of the VSphereVMImage referenced by the VSphereMachine.
+// It returns an empty template if the image doesn't exist or has not been verified yet.
+func (v *VimMachineService) getImageTemplate(ctx context.Context, vimMachineCtx *capvcontext.VIMMachineContext) (string, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	image := &infrav1.VSphereVMImage{}
+	imageKey := client.ObjectKey{Namespace: vimMachineCtx.VSphereMachine.Namespace, Name: vimMachineCtx.VSphereMachine.Spec.ImageRef.Name}
+	if err := v.Client.Get(ctx, imageKey, image); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return "", errors.Wrapf(err, "failed to get VSphereVMImage %s", imageKey.Name)
+		}
+		log.Info("Waiting for VSphereVMImage to be created", "VSphereVMImage", klog.KRef(imageKey.Namespace, imageKey.Name))
+		conditions.MarkFalse(vimMachineCtx.VSphereMachine, infrav1.VMProvisionedCondition, infrav1.WaitingForVMImageReason, clusterv1.ConditionSeverityInfo, "VSphereVMImage %s not found", imageKey.Name)
+		return "", nil
+	}
+	if !image.Status.Ready || image.Status.Template == "" {
+		log.Info("Waiting for VSphereVMImage to be verified", "VSphereVMImage", klog.KObj(image))
+		conditions.MarkFalse(vimMachineCtx.VSphereMachine, infrav1.VMProvisionedCondition, infrav1.WaitingForVMImageReason, clusterv1.ConditionSeverityInfo, "VSphereVMImage %s is not ready", image.Name)
+		return "", nil
+	}
+	server := vimMachineCtx.VSphereMachine.Spec.Server
+	if server == "" {
+		server = vimMachineCtx.VSphereCluster.Spec.Server
+	}
+	if image.Spec.Server != server {
+		return "", errors.Errorf("VSphereVMImage %s is stored on vCenter %s, not on vCenter %s of the VSphereMachine", image.Name, image.Spec.Server, server)
+	}
+	return image.Status.Template, nil
+}
+
+// generateVMObjectName returns the VM object name generated by the naming strategy of the
+// VSphereMachine, which defaults to the Machine name.
+func generateVMObjectName(vimMachineCtx *capvcontext.VIMMachineContext, machineName string) (string, error) {
+	name, err := infrautilv1.GenerateVSphereVMName(machineName, vimMachineCtx.VSphereMachine.Spec.NamingStrategy)
+	if err != nil {
+		return "", err
+	}
 	// Windows VM names must have 15 characters length at max.
-	if vimMachineCtx.VSphereMachine.Spec.OS == infrav1.Windows && len(machineName) > 15 {
-		return strings.TrimSuffix(machineName[0:9], "-") + "-" + machineName[len(machineName)-5:]
+	if vimMachineCtx.VSphereMachine.Spec.OS == infrav1.Windows && len(name) > 15 {
+		return strings.TrimSuffix(name[0:9], "-") + "-" + name[len(name)-5:], nil
+	}
+	return name, nil
+}
+
+// reconcileFailureDomain assigns a VSphereDeploymentZone to a control plane machine without a failure
+// domain, so control plane machines are spread across the ready VSphereDeploymentZones suitable for
+// control plane machines without configuring failure domains on the Cluster.
+// The zone with the fewest control plane machines is chosen and recorded in the VSphereMachine status.
+// It returns true if a failure domain has been assigned.
+func (v *VimMachineService) reconcileFailureDomain(ctx context.Context, vimMachineCtx *capvcontext.VIMMachineContext) (bool, error) {
+	log := ctrl.LoggerFrom(ctx)
+	if vimMachineCtx.Machine.Spec.FailureDomain != nil || vimMachineCtx.VSphereMachine.Status.FailureDomain != nil {
+		return false, nil
+	}
+	if !infrautilv1.IsControlPlaneMachine(vimMachineCtx.Machine) {
+		return false, nil
+	}
+
+	deploymentZoneList := &infrav1.VSphereDeploymentZoneList{}
+	if err := v.Client.List(ctx, deploymentZoneList); err != nil {
+		return false, errors.Wrap(err, "failed to list VSphereDeploymentZones")
 	}
-	return machineName
+	machinesPerZone := map[string]int{}
+	for _, zone := range deploymentZoneList.Items {
+		if !zone.DeletionTimestamp.IsZero() || !ptr.Deref(zone.Status.Ready, false) || !ptr.Deref(zone.Spec.ControlPlane, true) {
+			continue
+		}
+		if zone.Spec.Server != vimMachineCtx.VSphereCluster.Spec.Server {
+			continue
+		}
+		machinesPerZone[zone.Name] = 0
+	}
+	if len(machinesPerZone) == 0 {
+		return false, nil
+	}
+
+	vsphereMachineList := &infrav1.VSphereMachineList{}
+	if err := v.Client.List(ctx, vsphereMachineList,
+		client.InNamespace(vimMachineCtx.VSphereMachine.Namespace),
+		client.MatchingLabels{clusterv1.ClusterNameLabel: vimMachineCtx.Machine.Spec.ClusterName}); err != nil {
+		return false, errors.Wrap(err, "failed to list VSphereMachines")
+	}
+	for _, vsphereMachine := range vsphereMachineList.Items {
+		if vsphereMachine.Name == vimMachineCtx.VSphereMachine.Name || !vsphereMachine.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if !infrautilv1.IsControlPlaneMachine(&vsphereMachine) || vsphereMachine.Status.FailureDomain == nil {
+			continue
+		}
+		if _, ok := machinesPerZone[*vsphereMachine.Status.FailureDomain]; ok {
+			machinesPerZone[*vsphereMachine.Status.FailureDomain]++
+		}
+	}
+
+	zones := make([]string, 0, len(machinesPerZone))
+	for zone := range machinesPerZone {
+		zones = append(zones, zone)
+	}
+	sort.Slice(zones, func(i, j int) bool {
+		if machinesPerZone[zones[i]] != machinesPerZone[zones[j]] {
+			return machinesPerZone[zones[i]] < machinesPerZone[zones[j]]
+		}
+		return zones[i] < zones[j]
+	})
+
+	log.Info("Assigning failure domain to control plane machine", "failureDomain", zones[0])
+	vimMachineCtx.VSphereMachine.Status.FailureDomain = ptr.To(zones[0])
+	return true, nil
 }
 
 // generateOverrideFunc returns a function which can override the values in the VSphereVM Spec
-// with the values from the FailureDomain (if any) set on the owner CAPI machine.
+// with the values from the FailureDomain (if any) set on the owner CAPI machine, or assigned
+// to the VSphereMachine if the CAPI machine has no failure domain.
 func (v *VimMachineService) generateOverrideFunc(ctx context.Context, vimMachineCtx *capvcontext.VIMMachineContext) (func(vm *infrav1.VSphereVM), bool) {
 	log := ctrl.LoggerFrom(ctx)
 	failureDomainName := vimMachineCtx.Machine.Spec.FailureDomain
+	if failureDomainName == nil {
+		failureDomainName = vimMachineCtx.VSphereMachine.Status.FailureDomain
+	}
 	if failureDomainName == nil {
 		return nil, false
 	}
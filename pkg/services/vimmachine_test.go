@@ -30,6 +30,7 @@ import (
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/context/fake"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/util"
 )
@@ -108,6 +109,22 @@ func Test_VimMachineService_GenerateOverrideFunc(t *testing.T) {
 		g.Expect(ok).To(BeTrue())
 	})
 
+	t.Run("generates an override function when a Failure Domain is assigned to the VSphereMachine", func(t *testing.T) {
+		g := NewWithT(t)
+		controllerManagerContext := fake.NewControllerManagerContext(deplZone("one"), deplZone("two"), failureDomain("one"), failureDomain("two"))
+		machineCtx := fake.NewMachineContext(ctx, fake.NewClusterContext(ctx, controllerManagerContext), controllerManagerContext)
+		machineCtx.VSphereMachine.Status.FailureDomain = ptr.To("zone-two")
+		vimMachineService := &VimMachineService{controllerManagerContext.Client}
+
+		vm := &infrav1.VSphereVM{}
+		overrideFunc, ok := vimMachineService.generateOverrideFunc(ctx, machineCtx)
+		g.Expect(ok).To(BeTrue())
+
+		overrideFunc(vm)
+		g.Expect(vm.Spec.Server).To(Equal("server-two"))
+		g.Expect(vm.Spec.Datacenter).To(Equal("dc-two"))
+	})
+
 	t.Run("uses the deployment zone placement constraint & failure domains topology for VM values", func(t *testing.T) {
 		g := NewWithT(t)
 		controllerManagerContext := fake.NewControllerManagerContext(deplZone("one"), deplZone("two"), failureDomain("one"), failureDomain("two"))
@@ -314,6 +331,104 @@ func Test_VimMachineService_GenerateOverrideFunc(t *testing.T) {
 	})
 }
 
+func Test_VimMachineService_reconcileFailureDomain(t *testing.T) {
+	deplZone := func(name string, ready, controlPlane bool) *infrav1.VSphereDeploymentZone {
+		return &infrav1.VSphereDeploymentZone{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: infrav1.VSphereDeploymentZoneSpec{
+				Server:        fake.VCenterURL,
+				FailureDomain: fmt.Sprintf("fd-%s", name),
+				ControlPlane:  ptr.To(controlPlane),
+			},
+			Status: infrav1.VSphereDeploymentZoneStatus{Ready: ptr.To(ready)},
+		}
+	}
+
+	controlPlaneVSphereMachine := func(name string, failureDomain *string) *infrav1.VSphereMachine {
+		return &infrav1.VSphereMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: fake.Namespace,
+				Name:      name,
+				Labels: map[string]string{
+					clusterv1.ClusterNameLabel:         fake.Clusterv1a2Name,
+					clusterv1.MachineControlPlaneLabel: "",
+				},
+			},
+			Status: infrav1.VSphereMachineStatus{FailureDomain: failureDomain},
+		}
+	}
+
+	newMachineCtx := func(objects ...ctrlclient.Object) (*VimMachineService, *capvcontext.VIMMachineContext) {
+		controllerManagerContext := fake.NewControllerManagerContext(objects...)
+		machineCtx := fake.NewMachineContext(ctx, fake.NewClusterContext(ctx, controllerManagerContext), controllerManagerContext)
+		machineCtx.Machine.Spec.ClusterName = fake.Clusterv1a2Name
+		machineCtx.Machine.Labels = map[string]string{clusterv1.MachineControlPlaneLabel: ""}
+		return &VimMachineService{controllerManagerContext.Client}, machineCtx
+	}
+
+	t.Run("assigns the zone with the fewest control plane machines", func(t *testing.T) {
+		g := NewWithT(t)
+		vimMachineService, machineCtx := newMachineCtx(
+			deplZone("zone-a", true, true), deplZone("zone-b", true, true), deplZone("zone-c", true, true),
+			controlPlaneVSphereMachine("cp-1", ptr.To("zone-a")),
+			controlPlaneVSphereMachine("cp-2", ptr.To("zone-c")),
+		)
+
+		assigned, err := vimMachineService.reconcileFailureDomain(ctx, machineCtx)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(assigned).To(BeTrue())
+		g.Expect(machineCtx.VSphereMachine.Status.FailureDomain).To(Equal(ptr.To("zone-b")))
+
+		_, ok := vimMachineService.generateOverrideFunc(ctx, machineCtx)
+		g.Expect(ok).To(BeFalse(), "the VSphereFailureDomain of the zone does not exist")
+	})
+
+	t.Run("ignores zones which are not ready or not suitable for control plane machines", func(t *testing.T) {
+		g := NewWithT(t)
+		vimMachineService, machineCtx := newMachineCtx(
+			deplZone("zone-a", false, true), deplZone("zone-b", true, false), deplZone("zone-c", true, true),
+			controlPlaneVSphereMachine("cp-1", ptr.To("zone-c")),
+		)
+
+		assigned, err := vimMachineService.reconcileFailureDomain(ctx, machineCtx)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(assigned).To(BeTrue())
+		g.Expect(machineCtx.VSphereMachine.Status.FailureDomain).To(Equal(ptr.To("zone-c")))
+	})
+
+	t.Run("does not assign a zone to machines with a failure domain", func(t *testing.T) {
+		g := NewWithT(t)
+		vimMachineService, machineCtx := newMachineCtx(deplZone("zone-a", true, true))
+		machineCtx.Machine.Spec.FailureDomain = ptr.To("zone-b")
+
+		assigned, err := vimMachineService.reconcileFailureDomain(ctx, machineCtx)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(assigned).To(BeFalse())
+		g.Expect(machineCtx.VSphereMachine.Status.FailureDomain).To(BeNil())
+	})
+
+	t.Run("does not assign a zone to worker machines", func(t *testing.T) {
+		g := NewWithT(t)
+		vimMachineService, machineCtx := newMachineCtx(deplZone("zone-a", true, true))
+		machineCtx.Machine.Labels = nil
+
+		assigned, err := vimMachineService.reconcileFailureDomain(ctx, machineCtx)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(assigned).To(BeFalse())
+		g.Expect(machineCtx.VSphereMachine.Status.FailureDomain).To(BeNil())
+	})
+
+	t.Run("does not assign a zone without suitable zones", func(t *testing.T) {
+		g := NewWithT(t)
+		vimMachineService, machineCtx := newMachineCtx(deplZone("zone-a", false, true))
+
+		assigned, err := vimMachineService.reconcileFailureDomain(ctx, machineCtx)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(assigned).To(BeFalse())
+		g.Expect(machineCtx.VSphereMachine.Status.FailureDomain).To(BeNil())
+	})
+}
+
 func Test_mergeNetworkConfigurationToNetworkDeviceSpec(t *testing.T) {
 	t.Run("all fields from NetworkConfiguration are overridden", func(t *testing.T) {
 		g := NewWithT(t)
@@ -495,6 +610,134 @@ func Test_VimMachineService_createOrPatchVSphereVM(t *testing.T) {
 		g.Expect(err).NotTo(HaveOccurred())
 		g.Expect(vmName).To(Equal(fakeLongClusterName))
 	})
+
+	t.Run("merges the custom attributes of the VSphereCluster and the VSphereMachine", func(t *testing.T) {
+		g := NewWithT(t)
+		controllerManagerContext := fake.NewControllerManagerContext(getVSphereVM(hostAddr, corev1.ConditionTrue), deplZone("one"), deplZone("two"), failureDomain("one"), failureDomain("two"))
+		machineCtx := fake.NewMachineContext(ctx, fake.NewClusterContext(ctx, controllerManagerContext), controllerManagerContext)
+		machineCtx.VSphereCluster.Spec.CustomAttributes = map[string]string{"owner": "team-a", "cost-center": "1234"}
+		machineCtx.VSphereMachine.Spec.CustomAttributes = map[string]string{"cost-center": "5678"}
+		machineCtx.Machine.SetName(fakeLongClusterName)
+		vimMachineService := &VimMachineService{controllerManagerContext.Client}
+
+		vm, err := vimMachineService.createOrPatchVSphereVM(ctx, machineCtx, getVSphereVM(hostAddr, corev1.ConditionTrue))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(vm.Spec.CustomAttributes).To(Equal(map[string]string{"owner": "team-a", "cost-center": "5678"}))
+		g.Expect(machineCtx.VSphereMachine.Spec.CustomAttributes).To(Equal(map[string]string{"cost-center": "5678"}))
+	})
+
+	t.Run("generates the VSphereVM name and folder with the naming strategies", func(t *testing.T) {
+		g := NewWithT(t)
+		controllerManagerContext := fake.NewControllerManagerContext()
+		machineCtx := fake.NewMachineContext(ctx, fake.NewClusterContext(ctx, controllerManagerContext), controllerManagerContext)
+		machineCtx.VSphereCluster.Spec.FolderNamingStrategy = &infrav1.VSphereFolderNamingStrategy{Template: "k8s-{{ .cluster.name }}"}
+		machineCtx.VSphereMachine.Spec.NamingStrategy = &infrav1.VSphereVMNamingStrategy{Template: ptr.To("{{ .machine.name }}-prod")}
+		machineCtx.VSphereMachine.Spec.Folder = "capv"
+		machineCtx.Machine.SetName(fakeLongClusterName)
+		vimMachineService := &VimMachineService{controllerManagerContext.Client}
+
+		vm, err := vimMachineService.createOrPatchVSphereVM(ctx, machineCtx, nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(vm.Name).To(Equal(fakeLongClusterName + "-prod"))
+		g.Expect(vm.Spec.Folder).To(Equal("capv/k8s-" + machineCtx.VSphereCluster.Name))
+
+		// The folder of existing VSphereVMs is kept.
+		machineCtx.VSphereCluster.Spec.FolderNamingStrategy = &infrav1.VSphereFolderNamingStrategy{Template: "other"}
+		vm, err = vimMachineService.createOrPatchVSphereVM(ctx, machineCtx, vm)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(vm.Spec.Folder).To(Equal("capv/k8s-" + machineCtx.VSphereCluster.Name))
+	})
+
+	t.Run("resolves the template of the VSphereVMImage when the VSphereVM is created", func(t *testing.T) {
+		g := NewWithT(t)
+		image := &infrav1.VSphereVMImage{
+			ObjectMeta: metav1.ObjectMeta{Namespace: fake.Namespace, Name: "ubuntu-2204-kube-v1.31.0"},
+			Spec:       infrav1.VSphereVMImageSpec{Server: "vcenter.example.com", Template: "ubuntu-2204-kube-v1.31.0"},
+			Status:     infrav1.VSphereVMImageStatus{Ready: true, Template: "vm-42"},
+		}
+		controllerManagerContext := fake.NewControllerManagerContext(image)
+		machineCtx := fake.NewMachineContext(ctx, fake.NewClusterContext(ctx, controllerManagerContext), controllerManagerContext)
+		machineCtx.VSphereCluster.Spec.Server = "vcenter.example.com"
+		machineCtx.VSphereMachine.Spec.Template = ""
+		machineCtx.VSphereMachine.Spec.ImageRef = &infrav1.VSphereVMImageReference{Name: image.Name}
+		machineCtx.Machine.SetName(fakeLongClusterName)
+		vimMachineService := &VimMachineService{controllerManagerContext.Client}
+
+		vm, err := vimMachineService.createOrPatchVSphereVM(ctx, machineCtx, nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(vm.Spec.Template).To(Equal("vm-42"))
+		g.Expect(vm.Spec.ImageRef.Name).To(Equal(image.Name))
+
+		// The template of existing VSphereVMs is kept when the image is promoted to another template.
+		image.Status.Template = "vm-43"
+		g.Expect(controllerManagerContext.Client.Status().Update(ctx, image)).To(Succeed())
+		vm, err = vimMachineService.createOrPatchVSphereVM(ctx, machineCtx, vm)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(vm.Spec.Template).To(Equal("vm-42"))
+	})
+}
+
+func Test_VimMachineService_getImageTemplate(t *testing.T) {
+	image := func(ready bool, server string) *infrav1.VSphereVMImage {
+		return &infrav1.VSphereVMImage{
+			ObjectMeta: metav1.ObjectMeta{Namespace: fake.Namespace, Name: "node-image"},
+			Spec:       infrav1.VSphereVMImageSpec{Server: server, Template: "node-image"},
+			Status:     infrav1.VSphereVMImageStatus{Ready: ready, Template: "vm-42"},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		image        *infrav1.VSphereVMImage
+		wantTemplate string
+		wantErr      bool
+		wantWaiting  bool
+	}{
+		{
+			name:         "returns the template of a ready image",
+			image:        image(true, "vcenter.example.com"),
+			wantTemplate: "vm-42",
+		},
+		{
+			name:        "waits for the image to be created",
+			wantWaiting: true,
+		},
+		{
+			name:        "waits for the image to be verified",
+			image:       image(false, "vcenter.example.com"),
+			wantWaiting: true,
+		},
+		{
+			name:    "fails for an image of another vCenter",
+			image:   image(true, "other.example.com"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var objs []ctrlclient.Object
+			if tt.image != nil {
+				objs = append(objs, tt.image)
+			}
+			controllerManagerContext := fake.NewControllerManagerContext(objs...)
+			machineCtx := fake.NewMachineContext(ctx, fake.NewClusterContext(ctx, controllerManagerContext), controllerManagerContext)
+			machineCtx.VSphereCluster.Spec.Server = "vcenter.example.com"
+			machineCtx.VSphereMachine.Spec.ImageRef = &infrav1.VSphereVMImageReference{Name: "node-image"}
+			vimMachineService := &VimMachineService{controllerManagerContext.Client}
+
+			tpl, err := vimMachineService.getImageTemplate(ctx, machineCtx)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(tpl).To(Equal(tt.wantTemplate))
+			if tt.wantWaiting {
+				g.Expect(conditions.GetReason(machineCtx.VSphereMachine, infrav1.VMProvisionedCondition)).To(Equal(infrav1.WaitingForVMImageReason))
+			}
+		})
+	}
 }
 
 func Test_VimMachineService_reconcileProviderID(t *testing.T) {
@@ -0,0 +1,396 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/klog/v2"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+	infrautilv1 "sigs.k8s.io/cluster-api-provider-vsphere/pkg/util"
+)
+
+// VimMachinePoolService reconciles the VSphereVMs which are the instances of a VSphereMachinePool.
+type VimMachinePoolService struct {
+	Client client.Client
+}
+
+// ReconcileNormal scales the instances of the VSphereMachinePool to the replicas of the
+// MachinePool, replaces outdated instances and updates the provider ID list and status
+// of the VSphereMachinePool.
+func (v *VimMachinePoolService) ReconcileNormal(ctx context.Context, poolCtx *capvcontext.VIMMachinePoolContext) error {
+	log := ctrl.LoggerFrom(ctx)
+	vsphereMachinePool := poolCtx.VSphereMachinePool
+
+	templateHash, err := MachinePoolTemplateHash(vsphereMachinePool.Spec.Template)
+	if err != nil {
+		return err
+	}
+
+	vms, err := v.getVSphereVMs(ctx, vsphereMachinePool)
+	if err != nil {
+		return err
+	}
+
+	desired := 1
+	if poolCtx.MachinePool.Spec.Replicas != nil {
+		desired = int(*poolCtx.MachinePool.Spec.Replicas)
+	}
+	maxSurge, maxUnavailable, err := resolveMaxSurgeAndUnavailable(vsphereMachinePool.Spec.Strategy.RollingUpdate, desired)
+	if err != nil {
+		return err
+	}
+
+	var upToDate, outdated []*infrav1.VSphereVM
+	deleting := 0
+	for _, vm := range vms {
+		switch {
+		case !vm.DeletionTimestamp.IsZero():
+			deleting++
+		case vm.Labels[infrav1.MachinePoolTemplateHashLabel] == templateHash:
+			upToDate = append(upToDate, vm)
+		default:
+			outdated = append(outdated, vm)
+		}
+	}
+
+	setInstancesReadyCondition(vsphereMachinePool, upToDate, outdated, desired)
+
+	toCreate, toDelete := planMachinePoolInstances(upToDate, outdated, deleting, desired, maxSurge, maxUnavailable, deletePolicy(vsphereMachinePool))
+
+	var errs []error
+	for _, vm := range toDelete {
+		log.Info("Deleting VSphereVM", "VSphereVM", klog.KObj(vm))
+		if err := v.Client.Delete(ctx, vm); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, errors.Wrapf(err, "failed to delete VSphereVM %s", klog.KObj(vm)))
+		}
+	}
+	for i := 0; i < toCreate; i++ {
+		vm, err := v.createVSphereVM(ctx, poolCtx, templateHash)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		log.Info("Created VSphereVM", "VSphereVM", klog.KObj(vm))
+	}
+
+	v.reconcileStatus(poolCtx, upToDate, outdated, desired)
+	return kerrors.NewAggregate(errs)
+}
+
+// ReconcileDelete deletes all instances of the VSphereMachinePool.
+// It returns true while there are instances which are not yet deleted.
+func (v *VimMachinePoolService) ReconcileDelete(ctx context.Context, poolCtx *capvcontext.VIMMachinePoolContext) (bool, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	vms, err := v.getVSphereVMs(ctx, poolCtx.VSphereMachinePool)
+	if err != nil {
+		return false, err
+	}
+
+	var errs []error
+	for _, vm := range vms {
+		if !vm.DeletionTimestamp.IsZero() {
+			continue
+		}
+		log.Info("Deleting VSphereVM", "VSphereVM", klog.KObj(vm))
+		if err := v.Client.Delete(ctx, vm); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, errors.Wrapf(err, "failed to delete VSphereVM %s", klog.KObj(vm)))
+		}
+	}
+	return len(vms) > 0, kerrors.NewAggregate(errs)
+}
+
+// MachinePoolTemplateHash returns a hash of the instance template of a VSphereMachinePool,
+// which is used to identify instances created from an outdated template.
+func MachinePoolTemplateHash(template infrav1.VSphereMachinePoolInstanceTemplate) (string, error) {
+	data, err := json.Marshal(template)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal VSphereMachinePool instance template")
+	}
+	hasher := fnv.New32a()
+	_, _ = hasher.Write(data)
+	return utilrand.SafeEncodeString(fmt.Sprint(hasher.Sum32())), nil
+}
+
+// getVSphereVMs returns the VSphereVMs controlled by the VSphereMachinePool.
+func (v *VimMachinePoolService) getVSphereVMs(ctx context.Context, vsphereMachinePool *infrav1.VSphereMachinePool) ([]*infrav1.VSphereVM, error) {
+	vmList := &infrav1.VSphereVMList{}
+	if err := v.Client.List(ctx, vmList,
+		client.InNamespace(vsphereMachinePool.Namespace),
+		client.MatchingLabels{infrav1.MachinePoolNameLabel: vsphereMachinePool.Name}); err != nil {
+		return nil, errors.Wrapf(err, "failed to list VSphereVMs for %s", klog.KObj(vsphereMachinePool))
+	}
+
+	vms := make([]*infrav1.VSphereVM, 0, len(vmList.Items))
+	for i := range vmList.Items {
+		vm := &vmList.Items[i]
+		if !metav1.IsControlledBy(vm, vsphereMachinePool) {
+			continue
+		}
+		vms = append(vms, vm)
+	}
+	return vms, nil
+}
+
+func (v *VimMachinePoolService) createVSphereVM(ctx context.Context, poolCtx *capvcontext.VIMMachinePoolContext, templateHash string) (*infrav1.VSphereVM, error) {
+	vsphereMachinePool := poolCtx.VSphereMachinePool
+
+	name, err := infrautilv1.GenerateMachinePoolInstanceName(vsphereMachinePool)
+	if err != nil {
+		return nil, err
+	}
+
+	vm := &infrav1.VSphereVM{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: vsphereMachinePool.Namespace,
+			Name:      name,
+			Labels: map[string]string{
+				clusterv1.ClusterNameLabel:           poolCtx.Cluster.Name,
+				infrav1.MachinePoolNameLabel:         vsphereMachinePool.Name,
+				infrav1.MachinePoolTemplateHashLabel: templateHash,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(vsphereMachinePool, infrav1.GroupVersion.WithKind("VSphereMachinePool")),
+			},
+		},
+	}
+
+	// Copy the instance template into the VSphereVM's clone spec.
+	template := vsphereMachinePool.Spec.Template
+	template.VirtualMachineCloneSpec.DeepCopyInto(&vm.Spec.VirtualMachineCloneSpec)
+	if vm.Spec.Server == "" {
+		vm.Spec.Server = poolCtx.VSphereCluster.Spec.Server
+	}
+	if vm.Spec.Thumbprint == "" {
+		vm.Spec.Thumbprint = poolCtx.VSphereCluster.Spec.Thumbprint
+	}
+	if vm.Spec.Folder, err = infrautilv1.ClusterFolder(poolCtx.VSphereCluster, vm.Spec.Folder); err != nil {
+		return nil, err
+	}
+	vm.Spec.PowerOffMode = template.PowerOffMode
+	vm.Spec.GuestSoftPowerOffTimeout = template.GuestSoftPowerOffTimeout.DeepCopy()
+	vm.Spec.GuestReadinessGates = append([]infrav1.GuestReadinessGate(nil), template.GuestReadinessGates...)
+
+	// Instruct the VSphereVM to use the bootstrap data of the MachinePool.
+	vm.Spec.BootstrapRef = &corev1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Name:       *poolCtx.MachinePool.Spec.Template.Spec.Bootstrap.DataSecretName,
+		Namespace:  poolCtx.MachinePool.Namespace,
+	}
+
+	if err := v.Client.Create(ctx, vm); err != nil {
+		return nil, errors.Wrapf(err, "failed to create VSphereVM %s", klog.KObj(vm))
+	}
+	return vm, nil
+}
+
+// reconcileStatus updates the provider ID list and the status of the VSphereMachinePool
+// from the VSphereVMs observed at the beginning of the reconciliation.
+func (v *VimMachinePoolService) reconcileStatus(poolCtx *capvcontext.VIMMachinePoolContext, upToDate, outdated []*infrav1.VSphereVM, desired int) {
+	vsphereMachinePool := poolCtx.VSphereMachinePool
+
+	instances := make([]infrav1.VSphereMachinePoolInstanceStatus, 0, len(upToDate)+len(outdated))
+	providerIDs := []string{}
+	readyUpToDate := 0
+	addInstance := func(vm *infrav1.VSphereVM, isUpToDate bool) {
+		instance := infrav1.VSphereMachinePoolInstanceStatus{
+			Name:     vm.Name,
+			Ready:    vm.Status.Ready,
+			UpToDate: isUpToDate,
+		}
+		if vm.Spec.BiosUUID != "" {
+			instance.ProviderID = infrautilv1.ConvertUUIDToProviderID(vm.Spec.BiosUUID)
+		}
+		if instance.Ready && instance.ProviderID != "" {
+			providerIDs = append(providerIDs, instance.ProviderID)
+			if isUpToDate {
+				readyUpToDate++
+			}
+		}
+		instances = append(instances, instance)
+	}
+	for _, vm := range upToDate {
+		addInstance(vm, true)
+	}
+	for _, vm := range outdated {
+		addInstance(vm, false)
+	}
+	sort.Slice(instances, func(i, j int) bool { return instances[i].Name < instances[j].Name })
+	sort.Strings(providerIDs)
+
+	vsphereMachinePool.Spec.ProviderIDList = providerIDs
+	vsphereMachinePool.Status.Instances = instances
+	vsphereMachinePool.Status.Replicas = int32(len(providerIDs))
+
+	// The VSphereMachinePool is ready once its initial instances are ready; it stays
+	// ready while it is scaled or its instances are replaced.
+	if !vsphereMachinePool.Status.Ready && readyUpToDate >= desired {
+		vsphereMachinePool.Status.Ready = true
+	}
+}
+
+func setInstancesReadyCondition(vsphereMachinePool *infrav1.VSphereMachinePool, upToDate, outdated []*infrav1.VSphereVM, desired int) {
+	ready := 0
+	for _, vm := range upToDate {
+		if vm.Status.Ready {
+			ready++
+		}
+	}
+
+	switch {
+	case len(outdated) > 0:
+		conditions.MarkFalse(vsphereMachinePool, infrav1.InstancesReadyCondition, infrav1.RollingUpdateInProgressReason, clusterv1.ConditionSeverityInfo,
+			"%d of %d instances are outdated", len(outdated), len(upToDate)+len(outdated))
+	case len(upToDate) < desired:
+		conditions.MarkFalse(vsphereMachinePool, infrav1.InstancesReadyCondition, clusterv1.ScalingUpReason, clusterv1.ConditionSeverityInfo,
+			"Scaling up from %d to %d instances", len(upToDate), desired)
+	case len(upToDate) > desired:
+		conditions.MarkFalse(vsphereMachinePool, infrav1.InstancesReadyCondition, clusterv1.ScalingDownReason, clusterv1.ConditionSeverityInfo,
+			"Scaling down from %d to %d instances", len(upToDate), desired)
+	case ready < desired:
+		conditions.MarkFalse(vsphereMachinePool, infrav1.InstancesReadyCondition, infrav1.WaitingForInstancesReadyReason, clusterv1.ConditionSeverityInfo,
+			"%d of %d instances are ready", ready, desired)
+	default:
+		conditions.MarkTrue(vsphereMachinePool, infrav1.InstancesReadyCondition)
+	}
+}
+
+// planMachinePoolInstances returns the number of VSphereVMs to create and the VSphereVMs to delete
+// in order to converge to the desired number of up-to-date instances, without exceeding
+// desired+maxSurge instances nor dropping below desired-maxUnavailable ready instances
+// while replacing outdated instances.
+func planMachinePoolInstances(upToDate, outdated []*infrav1.VSphereVM, deleting, desired, maxSurge, maxUnavailable int, policy infrav1.VSphereMachinePoolDeletePolicy) (int, []*infrav1.VSphereVM) {
+	var toDelete []*infrav1.VSphereVM
+
+	// Delete surplus up-to-date instances when scaling down.
+	upToDate = sortForDeletion(upToDate, policy)
+	if surplus := len(upToDate) - desired; surplus > 0 {
+		toDelete = append(toDelete, upToDate[:surplus]...)
+		upToDate = upToDate[surplus:]
+	}
+
+	ready := 0
+	for _, vm := range upToDate {
+		if vm.Status.Ready {
+			ready++
+		}
+	}
+	for _, vm := range outdated {
+		if vm.Status.Ready {
+			ready++
+		}
+	}
+
+	// Delete outdated instances; instances which are not ready can always be deleted,
+	// ready instances only as long as enough ready instances remain available.
+	minAvailable := desired - maxUnavailable
+	remainingOutdated := 0
+	for _, vm := range sortForDeletion(outdated, policy) {
+		if !vm.Status.Ready {
+			toDelete = append(toDelete, vm)
+			continue
+		}
+		if ready > minAvailable {
+			toDelete = append(toDelete, vm)
+			ready--
+			continue
+		}
+		remainingOutdated++
+	}
+
+	// Create up-to-date instances as long as the number of instances, including the ones
+	// being deleted, does not exceed desired+maxSurge.
+	toCreate := min(desired-len(upToDate), desired+maxSurge-len(upToDate)-remainingOutdated-deleting-len(toDelete))
+	return max(toCreate, 0), toDelete
+}
+
+// sortForDeletion returns the VSphereVMs in the order they should be deleted in:
+// VSphereVMs which are not ready first, followed by the order defined by the delete policy.
+func sortForDeletion(vms []*infrav1.VSphereVM, policy infrav1.VSphereMachinePoolDeletePolicy) []*infrav1.VSphereVM {
+	sorted := append([]*infrav1.VSphereVM(nil), vms...)
+	switch policy {
+	case infrav1.RandomVSphereMachinePoolDeletePolicy:
+		rand.Shuffle(len(sorted), func(i, j int) { sorted[i], sorted[j] = sorted[j], sorted[i] })
+	case infrav1.NewestVSphereMachinePoolDeletePolicy:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[j].CreationTimestamp.Before(&sorted[i].CreationTimestamp)
+		})
+	default:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].CreationTimestamp.Before(&sorted[j].CreationTimestamp)
+		})
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return !sorted[i].Status.Ready && sorted[j].Status.Ready
+	})
+	return sorted
+}
+
+func deletePolicy(vsphereMachinePool *infrav1.VSphereMachinePool) infrav1.VSphereMachinePoolDeletePolicy {
+	if rollingUpdate := vsphereMachinePool.Spec.Strategy.RollingUpdate; rollingUpdate != nil && rollingUpdate.DeletePolicy != "" {
+		return rollingUpdate.DeletePolicy
+	}
+	return infrav1.OldestVSphereMachinePoolDeletePolicy
+}
+
+// resolveMaxSurgeAndUnavailable returns the absolute values of MaxSurge and MaxUnavailable
+// for the desired number of instances. If both resolve to 0, MaxSurge is set to 1 so the
+// rolling update can make progress.
+func resolveMaxSurgeAndUnavailable(rollingUpdate *infrav1.VSphereMachinePoolRollingUpdate, desired int) (int, int, error) {
+	maxSurgeValue, maxUnavailableValue := intstr.FromInt32(1), intstr.FromInt32(0)
+	if rollingUpdate != nil {
+		if rollingUpdate.MaxSurge != nil {
+			maxSurgeValue = *rollingUpdate.MaxSurge
+		}
+		if rollingUpdate.MaxUnavailable != nil {
+			maxUnavailableValue = *rollingUpdate.MaxUnavailable
+		}
+	}
+
+	maxSurge, err := intstr.GetScaledValueFromIntOrPercent(&maxSurgeValue, desired, true)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to resolve maxSurge")
+	}
+	maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(&maxUnavailableValue, desired, false)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to resolve maxUnavailable")
+	}
+	if maxSurge == 0 && maxUnavailable == 0 {
+		maxSurge = 1
+	}
+	return maxSurge, min(maxUnavailable, desired), nil
+}
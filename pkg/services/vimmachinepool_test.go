@@ -0,0 +1,241 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/context/fake"
+)
+
+func Test_VimMachinePoolService_ReconcileNormal(t *testing.T) {
+	newPoolCtx := func(replicas int32) *capvcontext.VIMMachinePoolContext {
+		return &capvcontext.VIMMachinePoolContext{
+			Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cluster"}},
+			MachinePool: &expv1.MachinePool{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pool"},
+				Spec: expv1.MachinePoolSpec{
+					Replicas: ptr.To(replicas),
+					Template: clusterv1.MachineTemplateSpec{
+						Spec: clusterv1.MachineSpec{
+							Bootstrap: clusterv1.Bootstrap{DataSecretName: ptr.To("pool-bootstrap")},
+						},
+					},
+				},
+			},
+			VSphereCluster: &infrav1.VSphereCluster{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cluster"},
+				Spec:       infrav1.VSphereClusterSpec{Server: "vcenter.example.com", Thumbprint: "thumbprint"},
+			},
+			VSphereMachinePool: &infrav1.VSphereMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pool", UID: types.UID("pool-uid")},
+				Spec: infrav1.VSphereMachinePoolSpec{
+					Template: infrav1.VSphereMachinePoolInstanceTemplate{
+						VirtualMachineCloneSpec: infrav1.VirtualMachineCloneSpec{Template: "ubuntu", NumCPUs: 2},
+						PowerOffMode:            infrav1.VirtualMachinePowerOpModeHard,
+					},
+				},
+			},
+		}
+	}
+
+	newVSphereVM := func(poolCtx *capvcontext.VIMMachinePoolContext, name, templateHash string, ready bool, age time.Duration) *infrav1.VSphereVM {
+		vm := &infrav1.VSphereVM{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         "default",
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+				Labels: map[string]string{
+					infrav1.MachinePoolNameLabel:         poolCtx.VSphereMachinePool.Name,
+					infrav1.MachinePoolTemplateHashLabel: templateHash,
+				},
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(poolCtx.VSphereMachinePool, infrav1.GroupVersion.WithKind("VSphereMachinePool")),
+				},
+			},
+		}
+		if ready {
+			vm.Spec.BiosUUID = fmt.Sprintf("4230cf8e-52b4-5b5b-a2a9-%012d", int(age.Minutes()))
+			vm.Status.Ready = true
+		}
+		return vm
+	}
+
+	listVSphereVMs := func(g *WithT, c ctrlclient.Client) []infrav1.VSphereVM {
+		vmList := &infrav1.VSphereVMList{}
+		g.Expect(c.List(ctx, vmList, ctrlclient.InNamespace("default"))).To(Succeed())
+		return vmList.Items
+	}
+
+	t.Run("creates the desired number of instances", func(t *testing.T) {
+		g := NewWithT(t)
+		poolCtx := newPoolCtx(3)
+		controllerManagerContext := fake.NewControllerManagerContext()
+		vimMachinePoolService := &VimMachinePoolService{Client: controllerManagerContext.Client}
+
+		g.Expect(vimMachinePoolService.ReconcileNormal(ctx, poolCtx)).To(Succeed())
+
+		templateHash, err := MachinePoolTemplateHash(poolCtx.VSphereMachinePool.Spec.Template)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		vms := listVSphereVMs(g, controllerManagerContext.Client)
+		g.Expect(vms).To(HaveLen(3))
+		for _, vm := range vms {
+			g.Expect(vm.Name).To(HavePrefix("pool-"))
+			g.Expect(vm.Labels).To(HaveKeyWithValue(clusterv1.ClusterNameLabel, "cluster"))
+			g.Expect(vm.Labels).To(HaveKeyWithValue(infrav1.MachinePoolTemplateHashLabel, templateHash))
+			g.Expect(metav1.IsControlledBy(&vm, poolCtx.VSphereMachinePool)).To(BeTrue())
+			g.Expect(vm.Spec.Template).To(Equal("ubuntu"))
+			g.Expect(vm.Spec.Server).To(Equal("vcenter.example.com"))
+			g.Expect(vm.Spec.Thumbprint).To(Equal("thumbprint"))
+			g.Expect(vm.Spec.PowerOffMode).To(Equal(infrav1.VirtualMachinePowerOpModeHard))
+			g.Expect(vm.Spec.BootstrapRef.Name).To(Equal("pool-bootstrap"))
+		}
+
+		g.Expect(poolCtx.VSphereMachinePool.Status.Ready).To(BeFalse())
+		g.Expect(conditions.GetReason(poolCtx.VSphereMachinePool, infrav1.InstancesReadyCondition)).To(Equal(clusterv1.ScalingUpReason))
+	})
+
+	t.Run("reports ready instances in the provider ID list", func(t *testing.T) {
+		g := NewWithT(t)
+		poolCtx := newPoolCtx(2)
+		templateHash, err := MachinePoolTemplateHash(poolCtx.VSphereMachinePool.Spec.Template)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		controllerManagerContext := fake.NewControllerManagerContext(
+			newVSphereVM(poolCtx, "pool-a", templateHash, true, 2*time.Minute),
+			newVSphereVM(poolCtx, "pool-b", templateHash, true, 1*time.Minute),
+		)
+		vimMachinePoolService := &VimMachinePoolService{Client: controllerManagerContext.Client}
+
+		g.Expect(vimMachinePoolService.ReconcileNormal(ctx, poolCtx)).To(Succeed())
+
+		g.Expect(listVSphereVMs(g, controllerManagerContext.Client)).To(HaveLen(2))
+		g.Expect(poolCtx.VSphereMachinePool.Spec.ProviderIDList).To(Equal([]string{
+			"vsphere://4230cf8e-52b4-5b5b-a2a9-000000000001",
+			"vsphere://4230cf8e-52b4-5b5b-a2a9-000000000002",
+		}))
+		g.Expect(poolCtx.VSphereMachinePool.Status.Replicas).To(Equal(int32(2)))
+		g.Expect(poolCtx.VSphereMachinePool.Status.Instances).To(HaveLen(2))
+		g.Expect(poolCtx.VSphereMachinePool.Status.Ready).To(BeTrue())
+		g.Expect(conditions.IsTrue(poolCtx.VSphereMachinePool, infrav1.InstancesReadyCondition)).To(BeTrue())
+	})
+
+	t.Run("deletes the oldest instances when scaling down", func(t *testing.T) {
+		g := NewWithT(t)
+		poolCtx := newPoolCtx(1)
+		templateHash, err := MachinePoolTemplateHash(poolCtx.VSphereMachinePool.Spec.Template)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		controllerManagerContext := fake.NewControllerManagerContext(
+			newVSphereVM(poolCtx, "pool-a", templateHash, true, 3*time.Minute),
+			newVSphereVM(poolCtx, "pool-b", templateHash, true, 2*time.Minute),
+			newVSphereVM(poolCtx, "pool-c", templateHash, true, 1*time.Minute),
+		)
+		vimMachinePoolService := &VimMachinePoolService{Client: controllerManagerContext.Client}
+
+		g.Expect(vimMachinePoolService.ReconcileNormal(ctx, poolCtx)).To(Succeed())
+
+		vms := listVSphereVMs(g, controllerManagerContext.Client)
+		g.Expect(vms).To(HaveLen(1))
+		g.Expect(vms[0].Name).To(Equal("pool-c"))
+		g.Expect(conditions.GetReason(poolCtx.VSphereMachinePool, infrav1.InstancesReadyCondition)).To(Equal(clusterv1.ScalingDownReason))
+	})
+
+	t.Run("surges a new instance before replacing outdated ready instances", func(t *testing.T) {
+		g := NewWithT(t)
+		poolCtx := newPoolCtx(2)
+
+		controllerManagerContext := fake.NewControllerManagerContext(
+			newVSphereVM(poolCtx, "pool-a", "outdated", true, 2*time.Minute),
+			newVSphereVM(poolCtx, "pool-b", "outdated", true, 1*time.Minute),
+		)
+		vimMachinePoolService := &VimMachinePoolService{Client: controllerManagerContext.Client}
+
+		g.Expect(vimMachinePoolService.ReconcileNormal(ctx, poolCtx)).To(Succeed())
+
+		vms := listVSphereVMs(g, controllerManagerContext.Client)
+		g.Expect(vms).To(HaveLen(3))
+		g.Expect(conditions.GetReason(poolCtx.VSphereMachinePool, infrav1.InstancesReadyCondition)).To(Equal(infrav1.RollingUpdateInProgressReason))
+	})
+
+	t.Run("replaces outdated instances within maxUnavailable", func(t *testing.T) {
+		g := NewWithT(t)
+		poolCtx := newPoolCtx(2)
+		templateHash, err := MachinePoolTemplateHash(poolCtx.VSphereMachinePool.Spec.Template)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		controllerManagerContext := fake.NewControllerManagerContext(
+			newVSphereVM(poolCtx, "pool-a", "outdated", true, 3*time.Minute),
+			newVSphereVM(poolCtx, "pool-b", "outdated", true, 2*time.Minute),
+			newVSphereVM(poolCtx, "pool-c", templateHash, true, 1*time.Minute),
+		)
+		vimMachinePoolService := &VimMachinePoolService{Client: controllerManagerContext.Client}
+
+		g.Expect(vimMachinePoolService.ReconcileNormal(ctx, poolCtx)).To(Succeed())
+
+		// The oldest outdated instance is deleted as enough ready instances remain,
+		// the other one is kept until enough up-to-date instances are ready.
+		vms := listVSphereVMs(g, controllerManagerContext.Client)
+		names := []string{}
+		for _, vm := range vms {
+			names = append(names, vm.Name)
+		}
+		g.Expect(names).To(ConsistOf("pool-b", "pool-c"))
+	})
+}
+
+func Test_VimMachinePoolService_ReconcileDelete(t *testing.T) {
+	g := NewWithT(t)
+	vsphereMachinePool := &infrav1.VSphereMachinePool{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pool", UID: types.UID("pool-uid")},
+	}
+	vm := &infrav1.VSphereVM{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "pool-a",
+			Labels:    map[string]string{infrav1.MachinePoolNameLabel: "pool"},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(vsphereMachinePool, infrav1.GroupVersion.WithKind("VSphereMachinePool")),
+			},
+		},
+	}
+	controllerManagerContext := fake.NewControllerManagerContext(vm)
+	vimMachinePoolService := &VimMachinePoolService{Client: controllerManagerContext.Client}
+	poolCtx := &capvcontext.VIMMachinePoolContext{VSphereMachinePool: vsphereMachinePool}
+
+	remaining, err := vimMachinePoolService.ReconcileDelete(ctx, poolCtx)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(remaining).To(BeTrue())
+
+	remaining, err = vimMachinePoolService.ReconcileDelete(ctx, poolCtx)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(remaining).To(BeFalse())
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmoperator
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// FieldManager is the field manager used to server-side apply the resources managed for VM Operator.
+const FieldManager = "capv-vmoperator"
+
+// classicManager is the field manager of the fields set by CAPV with create and patch calls
+// before it started to use server-side apply.
+const classicManager = "manager"
+
+// apply creates or updates obj using server-side apply.
+// obj must only set the fields managed by CAPV: fields set by other controllers, e.g. volumes or annotations,
+// are preserved, and fields CAPV stops setting are removed unless they are also managed by another field manager.
+// CAPV forces the ownership of the fields it sets, so conflicts with other field managers are resolved in its favor:
+// changes made by users or other controllers to fields set by CAPV are reverted, as they were before CAPV used
+// server-side apply. Fields CAPV does not set are never part of obj and are not taken over.
+// On success obj is updated with the object returned by the API server.
+func apply(ctx context.Context, c client.Client, obj client.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return errors.Wrapf(err, "failed to get GroupVersionKind of %T", obj)
+	}
+
+	existing, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return errors.Errorf("failed to copy %T", obj)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get %s %s", gvk.Kind, klog.KObj(obj))
+		}
+	} else if err := cleanUpManagedFieldsForSSAAdoption(ctx, c, existing, gvk.GroupVersion().String()); err != nil {
+		return errors.Wrapf(err, "failed to clean up managed fields of %s %s", gvk.Kind, klog.KObj(obj))
+	}
+
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	obj.SetManagedFields(nil)
+	obj.SetResourceVersion("")
+
+	return c.Patch(ctx, obj, client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership)
+}
+
+// cleanUpManagedFieldsForSSAAdoption removes the managed fields entries of the classic manager from obj
+// the first time it is applied by CAPV, like ssa.CleanUpManagedFieldsForSSAAdoption in Cluster API.
+// Otherwise the fields CAPV set before it used server-side apply would stay co-owned by the classic manager,
+// and they would not be removed when CAPV stops setting them.
+// A seeding entry for FieldManager is added, so that the API server does not infer an entry for the
+// classic manager when the first apply is done on an object without managed fields.
+func cleanUpManagedFieldsForSSAAdoption(ctx context.Context, c client.Client, obj client.Object, apiVersion string) error {
+	for _, managedField := range obj.GetManagedFields() {
+		if managedField.Manager == FieldManager {
+			return nil
+		}
+	}
+
+	base, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return errors.Errorf("failed to copy %T", obj)
+	}
+
+	managedFields := make([]metav1.ManagedFieldsEntry, 0, len(obj.GetManagedFields())+1)
+	for _, managedField := range obj.GetManagedFields() {
+		if managedField.Manager == classicManager && managedField.Operation == metav1.ManagedFieldsOperationUpdate {
+			continue
+		}
+		managedFields = append(managedFields, managedField)
+	}
+
+	// The seeding entry cannot be empty, metadata.name is dropped from it with the first apply.
+	fieldsV1, err := json.Marshal(map[string]interface{}{
+		"f:metadata": map[string]interface{}{
+			"f:name": map[string]interface{}{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal seeding managed fields")
+	}
+	now := metav1.Now()
+	managedFields = append(managedFields, metav1.ManagedFieldsEntry{
+		Manager:    FieldManager,
+		Operation:  metav1.ManagedFieldsOperationApply,
+		APIVersion: apiVersion,
+		Time:       &now,
+		FieldsType: "FieldsV1",
+		FieldsV1:   &metav1.FieldsV1{Raw: fieldsV1},
+	})
+	obj.SetManagedFields(managedFields)
+
+	return c.Patch(ctx, obj, client.MergeFrom(base))
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmoperator
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/context/fake"
+)
+
+func Test_apply(t *testing.T) {
+	managedFields := func(obj client.Object) []string {
+		managers := []string{}
+		for _, managedField := range obj.GetManagedFields() {
+			managers = append(managers, managedField.Manager+"/"+string(managedField.Operation))
+		}
+		return managers
+	}
+	fieldsV1 := &metav1.FieldsV1{Raw: []byte(`{"f:spec":{}}`)}
+
+	t.Run("cleans up the managed fields of the classic manager on adoption", func(t *testing.T) {
+		g := NewWithT(t)
+		ctx := context.Background()
+
+		existing := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pvc",
+				Namespace: "default",
+				ManagedFields: []metav1.ManagedFieldsEntry{
+					{Manager: classicManager, Operation: metav1.ManagedFieldsOperationUpdate, FieldsType: "FieldsV1", FieldsV1: fieldsV1},
+					{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationUpdate, FieldsType: "FieldsV1", FieldsV1: fieldsV1},
+				},
+			},
+		}
+		c := fake.NewControllerManagerContext(existing).Client
+
+		g.Expect(apply(ctx, c, &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "pvc", Namespace: "default"}})).To(Succeed())
+
+		pvc := &corev1.PersistentVolumeClaim{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(existing), pvc)).To(Succeed())
+		g.Expect(managedFields(pvc)).To(ConsistOf("kubectl/Update", FieldManager+"/Apply"))
+	})
+
+	t.Run("keeps the managed fields of objects already applied", func(t *testing.T) {
+		g := NewWithT(t)
+		ctx := context.Background()
+
+		existing := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pvc",
+				Namespace: "default",
+				ManagedFields: []metav1.ManagedFieldsEntry{
+					{Manager: classicManager, Operation: metav1.ManagedFieldsOperationUpdate, FieldsType: "FieldsV1", FieldsV1: fieldsV1},
+					{Manager: FieldManager, Operation: metav1.ManagedFieldsOperationApply, FieldsType: "FieldsV1", FieldsV1: fieldsV1},
+				},
+			},
+		}
+		c := fake.NewControllerManagerContext(existing).Client
+
+		g.Expect(apply(ctx, c, &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "pvc", Namespace: "default"}})).To(Succeed())
+
+		pvc := &corev1.PersistentVolumeClaim{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(existing), pvc)).To(Succeed())
+		g.Expect(managedFields(pvc)).To(ConsistOf(classicManager+"/Update", FieldManager+"/Apply"))
+	})
+
+	t.Run("creates objects which do not exist", func(t *testing.T) {
+		g := NewWithT(t)
+		ctx := context.Background()
+		c := fake.NewControllerManagerContext().Client
+
+		g.Expect(apply(ctx, c, &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "pvc", Namespace: "default"}})).To(Succeed())
+		g.Expect(c.Get(ctx, client.ObjectKey{Name: "pvc", Namespace: "default"}, &corev1.PersistentVolumeClaim{})).To(Succeed())
+	})
+}
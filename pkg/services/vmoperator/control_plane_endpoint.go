@@ -22,9 +22,7 @@ import (
 
 	"github.com/pkg/errors"
 	vmoprv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/klog/v2"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -75,28 +73,19 @@ func (s *CPService) ReconcileControlPlaneEndpointService(ctx context.Context, cl
 		return nil, nil
 	}
 
-	vmService, err := s.getVMControlPlaneService(ctx, clusterCtx)
+	// Get the provider annotations for the ControlPlane Service.
+	annotations, err := netProvider.GetVMServiceAnnotations(ctx, clusterCtx)
 	if err != nil {
-		if !apierrors.IsNotFound(err) {
-			err = errors.Wrapf(err, "failed to check if VirtualMachineService exists")
-			conditions.MarkFalse(clusterCtx.VSphereCluster, vmwarev1.LoadBalancerReadyCondition, vmwarev1.LoadBalancerCreationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
-			return nil, err
-		}
-
-		// Get the provider annotations for the ControlPlane Service.
-		annotations, err := netProvider.GetVMServiceAnnotations(ctx, clusterCtx)
-		if err != nil {
-			err = errors.Wrapf(err, "failed to get provider VirtualMachineService annotations")
-			conditions.MarkFalse(clusterCtx.VSphereCluster, vmwarev1.LoadBalancerReadyCondition, vmwarev1.LoadBalancerCreationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
-			return nil, err
-		}
+		err = errors.Wrapf(err, "failed to get provider VirtualMachineService annotations")
+		conditions.MarkFalse(clusterCtx.VSphereCluster, vmwarev1.LoadBalancerReadyCondition, vmwarev1.LoadBalancerCreationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return nil, err
+	}
 
-		vmService, err = s.createVMControlPlaneService(ctx, clusterCtx, annotations)
-		if err != nil {
-			err = errors.Wrapf(err, "failed to create VirtualMachineService")
-			conditions.MarkFalse(clusterCtx.VSphereCluster, vmwarev1.LoadBalancerReadyCondition, vmwarev1.LoadBalancerCreationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
-			return nil, err
-		}
+	vmService, err := s.applyVMControlPlaneService(ctx, clusterCtx, annotations)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to apply VirtualMachineService")
+		conditions.MarkFalse(clusterCtx.VSphereCluster, vmwarev1.LoadBalancerReadyCondition, vmwarev1.LoadBalancerCreationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return nil, err
 	}
 
 	// See if the LB has a VIP assigned, and delay reconciliation until it does
@@ -153,75 +142,43 @@ func newVirtualMachineService(ctx *vmware.ClusterContext) *vmoprv1.VirtualMachin
 	}
 }
 
-func (s *CPService) createVMControlPlaneService(ctx context.Context, clusterCtx *vmware.ClusterContext, annotations map[string]string) (*vmoprv1.VirtualMachineService, error) {
+func (s *CPService) applyVMControlPlaneService(ctx context.Context, clusterCtx *vmware.ClusterContext, annotations map[string]string) (*vmoprv1.VirtualMachineService, error) {
 	// Note that the current implementation will only create a VirtualMachineService for a load balanced endpoint
 	serviceType := vmoprv1.VirtualMachineServiceTypeLoadBalancer
 
 	vmService := newVirtualMachineService(clusterCtx)
-
-	_, err := ctrlutil.CreateOrPatch(ctx, s.Client, vmService, func() error {
-		if vmService.Annotations == nil {
-			vmService.Annotations = annotations
-		} else {
-			for k, v := range annotations {
-				vmService.Annotations[k] = v
-			}
-		}
-		vmService.Annotations = annotations
-		vmService.Spec = vmoprv1.VirtualMachineServiceSpec{
-			Type: serviceType,
-			Ports: []vmoprv1.VirtualMachineServicePort{
-				{
-					Name:       controlPlaneServiceAPIServerPortName,
-					Protocol:   "TCP",
-					Port:       defaultAPIBindPort,
-					TargetPort: defaultAPIBindPort,
-				},
+	vmService.Annotations = annotations
+	vmService.Spec = vmoprv1.VirtualMachineServiceSpec{
+		Type: serviceType,
+		Ports: []vmoprv1.VirtualMachineServicePort{
+			{
+				Name:       controlPlaneServiceAPIServerPortName,
+				Protocol:   "TCP",
+				Port:       defaultAPIBindPort,
+				TargetPort: defaultAPIBindPort,
 			},
-			Selector: clusterRoleVMLabels(clusterCtx, true),
-		}
-
-		if err := ctrlutil.SetOwnerReference(
-			clusterCtx.VSphereCluster,
-			vmService,
-			s.Client.Scheme(),
-		); err != nil {
-			return errors.Wrapf(
-				err,
-				"error setting %s/%s as owner of %s/%s",
-				clusterCtx.VSphereCluster.Namespace,
-				clusterCtx.VSphereCluster.Name,
-				vmService.Namespace,
-				vmService.Name,
-			)
-		}
-
-		return nil
-	})
-	if err != nil {
-		return nil, err
+		},
+		Selector: clusterRoleVMLabels(clusterCtx, true),
 	}
 
-	return vmService, nil
-}
-
-func (s *CPService) getVMControlPlaneService(ctx context.Context, clusterCtx *vmware.ClusterContext) (*vmoprv1.VirtualMachineService, error) {
-	log := ctrl.LoggerFrom(ctx)
-
-	vmService := &vmoprv1.VirtualMachineService{}
-	vmServiceKey := client.ObjectKey{
-		Namespace: clusterCtx.Cluster.Namespace,
-		Name:      controlPlaneVMServiceName(clusterCtx),
+	if err := ctrlutil.SetOwnerReference(
+		clusterCtx.VSphereCluster,
+		vmService,
+		s.Client.Scheme(),
+	); err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"error setting %s/%s as owner of %s/%s",
+			clusterCtx.VSphereCluster.Namespace,
+			clusterCtx.VSphereCluster.Name,
+			vmService.Namespace,
+			vmService.Name,
+		)
 	}
-	if err := s.Client.Get(ctx, vmServiceKey, vmService); err != nil {
-		if !apierrors.IsNotFound(err) {
-			return nil, fmt.Errorf("failed to get VirtualMachineService %s: %v", vmServiceKey.Name, err)
-		}
 
-		log.Info("VirtualMachineService was not found", "VirtualMachineService", klog.KRef(vmServiceKey.Namespace, vmServiceKey.Name))
+	if err := apply(ctx, s.Client, vmService); err != nil {
 		return nil, err
 	}
-
 	return vmService, nil
 }
 
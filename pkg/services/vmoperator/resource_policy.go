@@ -21,7 +21,6 @@ import (
 
 	"github.com/pkg/errors"
 	vmoprv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -37,15 +36,9 @@ type RPService struct {
 // ReconcileResourcePolicy ensures that a VirtualMachineSetResourcePolicy exists for the cluster
 // Returns the name of a policy if it exists, otherwise returns an error.
 func (s *RPService) ReconcileResourcePolicy(ctx context.Context, clusterCtx *vmware.ClusterContext) (string, error) {
-	resourcePolicy, err := s.getVirtualMachineSetResourcePolicy(ctx, clusterCtx)
+	resourcePolicy, err := s.applyVirtualMachineSetResourcePolicy(ctx, clusterCtx)
 	if err != nil {
-		if !apierrors.IsNotFound(err) {
-			return "", errors.Errorf("unexpected error in getting the Resource policy: %+v", err)
-		}
-		resourcePolicy, err = s.createVirtualMachineSetResourcePolicy(ctx, clusterCtx)
-		if err != nil {
-			return "", errors.Errorf("failed to create Resource Policy: %+v", err)
-		}
+		return "", errors.Errorf("failed to apply Resource Policy: %+v", err)
 	}
 
 	return resourcePolicy.Name, nil
@@ -70,38 +63,35 @@ func (s *RPService) getVirtualMachineSetResourcePolicy(ctx context.Context, clus
 	return vmResourcePolicy, err
 }
 
-func (s *RPService) createVirtualMachineSetResourcePolicy(ctx context.Context, clusterCtx *vmware.ClusterContext) (*vmoprv1.VirtualMachineSetResourcePolicy, error) {
+func (s *RPService) applyVirtualMachineSetResourcePolicy(ctx context.Context, clusterCtx *vmware.ClusterContext) (*vmoprv1.VirtualMachineSetResourcePolicy, error) {
 	vmResourcePolicy := s.newVirtualMachineSetResourcePolicy(clusterCtx)
+	vmResourcePolicy.Spec = vmoprv1.VirtualMachineSetResourcePolicySpec{
+		ResourcePool: vmoprv1.ResourcePoolSpec{
+			Name: clusterCtx.Cluster.Name,
+		},
+		Folder: clusterCtx.Cluster.Name,
+		ClusterModuleGroups: []string{
+			ControlPlaneVMClusterModuleGroupName,
+			getMachineDeploymentNameForCluster(clusterCtx.Cluster),
+		},
+	}
+	// Ensure that the VirtualMachineSetResourcePolicy is owned by the VSphereCluster
+	if err := ctrlutil.SetOwnerReference(
+		clusterCtx.VSphereCluster,
+		vmResourcePolicy,
+		s.Client.Scheme(),
+	); err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"error setting %s/%s as owner of %s/%s",
+			clusterCtx.VSphereCluster.Namespace,
+			clusterCtx.VSphereCluster.Name,
+			vmResourcePolicy.Namespace,
+			vmResourcePolicy.Name,
+		)
+	}
 
-	_, err := ctrlutil.CreateOrPatch(ctx, s.Client, vmResourcePolicy, func() error {
-		vmResourcePolicy.Spec = vmoprv1.VirtualMachineSetResourcePolicySpec{
-			ResourcePool: vmoprv1.ResourcePoolSpec{
-				Name: clusterCtx.Cluster.Name,
-			},
-			Folder: clusterCtx.Cluster.Name,
-			ClusterModuleGroups: []string{
-				ControlPlaneVMClusterModuleGroupName,
-				getMachineDeploymentNameForCluster(clusterCtx.Cluster),
-			},
-		}
-		// Ensure that the VirtualMachineSetResourcePolicy is owned by the VSphereCluster
-		if err := ctrlutil.SetOwnerReference(
-			clusterCtx.VSphereCluster,
-			vmResourcePolicy,
-			s.Client.Scheme(),
-		); err != nil {
-			return errors.Wrapf(
-				err,
-				"error setting %s/%s as owner of %s/%s",
-				clusterCtx.VSphereCluster.Namespace,
-				clusterCtx.VSphereCluster.Name,
-				vmResourcePolicy.Namespace,
-				vmResourcePolicy.Name,
-			)
-		}
-		return nil
-	})
-	if err != nil {
+	if err := apply(ctx, s.Client, vmResourcePolicy); err != nil {
 		return nil, err
 	}
 	return vmResourcePolicy, nil
@@ -49,4 +49,21 @@ func TestRPService(t *testing.T) {
 		g.Expect(resourcePolicy.Spec.ResourcePool.Name).To(Equal(clusterName))
 		g.Expect(resourcePolicy.Spec.Folder).To(Equal(clusterName))
 	})
+
+	t.Run("Preserves fields set by other controllers", func(t *testing.T) {
+		g := NewWithT(t)
+		resourcePolicy, err := rpService.getVirtualMachineSetResourcePolicy(ctx, clusterCtx)
+		g.Expect(err).NotTo(HaveOccurred())
+		resourcePolicy.Annotations = map[string]string{"example.com/owner": "other-controller"}
+		resourcePolicy.Spec.Folder = "other-folder"
+		g.Expect(controllerCtx.Client.Update(ctx, resourcePolicy)).To(Succeed())
+
+		_, err = rpService.ReconcileResourcePolicy(ctx, clusterCtx)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		resourcePolicy, err = rpService.getVirtualMachineSetResourcePolicy(ctx, clusterCtx)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(resourcePolicy.Annotations).To(HaveKeyWithValue("example.com/owner", "other-controller"))
+		g.Expect(resourcePolicy.Spec.Folder).To(Equal(clusterName))
+	})
 }
@@ -21,6 +21,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"strings"
 	"text/template"
 
@@ -35,6 +36,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	v1beta2conditions "sigs.k8s.io/cluster-api/util/conditions/v1beta2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -171,7 +173,18 @@ func (v *VmopMachineService) ReconcileNormal(ctx context.Context, machineCtx cap
 		return false, errors.New("received unexpected SupervisorMachineContext type")
 	}
 
-	supervisorMachineCtx.VSphereMachine.Spec.FailureDomain = supervisorMachineCtx.Machine.Spec.FailureDomain
+	// The failure domain of the Machine takes precedence over the one
+	// of the VSphereMachine, which can be set e.g. in the VSphereMachineTemplate
+	// of a MachineDeployment.
+	// While a cluster is migrated from govmomi mode, its failure domains
+	// are VSphereDeploymentZones which have no meaning for VM Operator.
+	if fd := supervisorMachineCtx.Machine.Spec.FailureDomain; fd != nil {
+		if isMigratingFromGovmomi(supervisorMachineCtx.Cluster) {
+			log.V(4).Info("Ignoring failure domain of a cluster migrated from govmomi mode", "failureDomain", *fd)
+		} else {
+			supervisorMachineCtx.VSphereMachine.Spec.FailureDomain = fd
+		}
+	}
 
 	// If debug logging is enabled, report the number of vms in the cluster before and after the reconcile
 	if log.V(5).Enabled() {
@@ -213,6 +226,12 @@ func (v *VmopMachineService) ReconcileNormal(ctx context.Context, machineCtx cap
 		return false, err
 	}
 
+	// Surface the conditions of the VM Operator VirtualMachine on the VSphereMachine.
+	mirrorVMOperatorVMConditions(supervisorMachineCtx.VSphereMachine, vmOperatorVM)
+
+	// Surface the attach status of the volumes on the VSphereMachine.
+	reconcileVolumeStatus(supervisorMachineCtx.VSphereMachine, vmOperatorVM)
+
 	// Update the VM's state to Pending
 	supervisorMachineCtx.VSphereMachine.Status.VMStatus = vmwarev1.VirtualMachineStatePending
 
@@ -248,7 +267,7 @@ func (v *VmopMachineService) ReconcileNormal(ctx context.Context, machineCtx cap
 
 		// All the pre-requisites are in place but the machines is not yet created, report it.
 		conditions.MarkFalse(supervisorMachineCtx.VSphereMachine, infrav1.VMProvisionedCondition, vmwarev1.VMProvisionStartedReason, clusterv1.ConditionSeverityInfo, "")
-		log.Info(fmt.Sprintf("VM is not yet created: %s", supervisorMachineCtx))
+		log.Info("VM is not yet created")
 		return true, nil
 	}
 	// Mark the VM as created
@@ -256,7 +275,7 @@ func (v *VmopMachineService) ReconcileNormal(ctx context.Context, machineCtx cap
 
 	if vmOperatorVM.Status.PowerState != vmoprv1.VirtualMachinePowerStateOn {
 		conditions.MarkFalse(supervisorMachineCtx.VSphereMachine, infrav1.VMProvisionedCondition, vmwarev1.PoweringOnReason, clusterv1.ConditionSeverityInfo, "")
-		log.Info(fmt.Sprintf("VM is not yet powered on: %s", supervisorMachineCtx))
+		log.Info("VM is not yet powered on")
 		return true, nil
 	}
 	// Mark the VM as poweredOn
@@ -264,13 +283,13 @@ func (v *VmopMachineService) ReconcileNormal(ctx context.Context, machineCtx cap
 
 	if vmOperatorVM.Status.Network == nil || (vmOperatorVM.Status.Network.PrimaryIP4 == "" && vmOperatorVM.Status.Network.PrimaryIP6 == "") {
 		conditions.MarkFalse(supervisorMachineCtx.VSphereMachine, infrav1.VMProvisionedCondition, vmwarev1.WaitingForNetworkAddressReason, clusterv1.ConditionSeverityInfo, "")
-		log.Info(fmt.Sprintf("VM does not have an IP address: %s", supervisorMachineCtx))
+		log.Info("VM does not have an IP address")
 		return true, nil
 	}
 
 	if vmOperatorVM.Status.BiosUUID == "" {
 		conditions.MarkFalse(supervisorMachineCtx.VSphereMachine, infrav1.VMProvisionedCondition, vmwarev1.WaitingForBIOSUUIDReason, clusterv1.ConditionSeverityInfo, "")
-		log.Info(fmt.Sprintf("VM does not have a BIOS UUID: %s", supervisorMachineCtx))
+		log.Info("VM does not have a BIOS UUID")
 		return true, nil
 	}
 
@@ -294,21 +313,7 @@ const (
 	maxNameLength = 63
 )
 
-// Note: Inlining these functions from sprig to avoid introducing a dependency.
-var nameTemplateFuncs = map[string]any{
-	"trimSuffix": func(a, b string) string { return strings.TrimSuffix(b, a) },
-	"trunc": func(c int, s string) string {
-		if c < 0 && len(s)+c > 0 {
-			return s[len(s)+c:]
-		}
-		if c >= 0 && len(s) > c {
-			return s[:c]
-		}
-		return s
-	},
-}
-
-var nameTpl = template.New("name generator").Funcs(nameTemplateFuncs).Option("missingkey=error")
+var nameTpl = template.New("name generator").Funcs(infrautilv1.NameTemplateFuncs).Option("missingkey=error")
 
 // virtualMachineObjectKey returns the object key of the VirtualMachine.
 // Part of this is generating the name of the VirtualMachine based on the naming strategy.
@@ -403,112 +408,135 @@ func (v *VmopMachineService) reconcileVMOperatorVM(ctx context.Context, supervis
 		minHardwareVersion = int32(hwVersion)
 	}
 
-	_, err := ctrlutil.CreateOrPatch(ctx, v.Client, vmOperatorVM, func() error {
-		// Define a new VM Operator virtual machine.
-		// NOTE: Set field-by-field in order to preserve changes made directly
-		//  to the VirtualMachine spec by other sources (e.g. the cloud provider)
-		if vmOperatorVM.Spec.ImageName == "" {
-			vmOperatorVM.Spec.ImageName = supervisorMachineCtx.VSphereMachine.Spec.ImageName
-		}
-		if vmOperatorVM.Spec.ClassName == "" {
-			vmOperatorVM.Spec.ClassName = supervisorMachineCtx.VSphereMachine.Spec.ClassName
-		}
-		if vmOperatorVM.Spec.StorageClass == "" {
-			vmOperatorVM.Spec.StorageClass = supervisorMachineCtx.VSphereMachine.Spec.StorageClass
-		}
-		vmOperatorVM.Spec.PowerState = vmoprv1.VirtualMachinePowerStateOn
-		if supervisorMachineCtx.VSphereCluster.Status.ResourcePolicyName != "" {
-			if vmOperatorVM.Spec.Reserved == nil {
-				vmOperatorVM.Spec.Reserved = &vmoprv1.VirtualMachineReservedSpec{}
-			}
-			if vmOperatorVM.Spec.Reserved.ResourcePolicyName == "" {
-				vmOperatorVM.Spec.Reserved.ResourcePolicyName = supervisorMachineCtx.VSphereCluster.Status.ResourcePolicyName
-			}
-		}
-		if vmOperatorVM.Spec.Bootstrap == nil {
-			vmOperatorVM.Spec.Bootstrap = &vmoprv1.VirtualMachineBootstrapSpec{}
-		}
-		vmOperatorVM.Spec.Bootstrap.CloudInit = &vmoprv1.VirtualMachineBootstrapCloudInitSpec{
-			RawCloudConfig: &vmoprv1common.SecretKeySelector{
-				Name: dataSecretName,
-				Key:  "user-data",
+	// Define the desired state of the VM Operator VirtualMachine.
+	// NOTE: Only the fields managed by CAPV are set, fields set directly on the VirtualMachine
+	// by other sources (e.g. the cloud provider) are preserved by server-side apply.
+	desiredVM := &vmoprv1.VirtualMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      vmOperatorVM.Name,
+			Namespace: vmOperatorVM.Namespace,
+		},
+		Spec: vmoprv1.VirtualMachineSpec{
+			ImageName:          supervisorMachineCtx.VSphereMachine.Spec.ImageName,
+			ClassName:          supervisorMachineCtx.VSphereMachine.Spec.ClassName,
+			StorageClass:       supervisorMachineCtx.VSphereMachine.Spec.StorageClass,
+			PowerState:         vmoprv1.VirtualMachinePowerStateOn,
+			MinHardwareVersion: minHardwareVersion,
+			Bootstrap: &vmoprv1.VirtualMachineBootstrapSpec{
+				CloudInit: &vmoprv1.VirtualMachineBootstrapCloudInitSpec{
+					RawCloudConfig: &vmoprv1common.SecretKeySelector{
+						Name: dataSecretName,
+						Key:  "user-data",
+					},
+				},
 			},
+		},
+	}
+	if resourcePolicyName := getResourcePolicyName(supervisorMachineCtx); resourcePolicyName != "" {
+		desiredVM.Spec.Reserved = &vmoprv1.VirtualMachineReservedSpec{
+			ResourcePolicyName: resourcePolicyName,
 		}
-		if supervisorMachineCtx.VSphereMachine.Spec.PowerOffMode != "" {
-			var powerOffMode vmoprv1.VirtualMachinePowerOpMode
-			switch supervisorMachineCtx.VSphereMachine.Spec.PowerOffMode {
-			case vmwarev1.VirtualMachinePowerOpModeHard:
-				powerOffMode = vmoprv1.VirtualMachinePowerOpModeHard
-			case vmwarev1.VirtualMachinePowerOpModeSoft:
-				powerOffMode = vmoprv1.VirtualMachinePowerOpModeSoft
-			case vmwarev1.VirtualMachinePowerOpModeTrySoft:
-				powerOffMode = vmoprv1.VirtualMachinePowerOpModeTrySoft
-			default:
-				return fmt.Errorf("unable to map PowerOffMode %q to vm-operator equivalent", supervisorMachineCtx.VSphereMachine.Spec.PowerOffMode)
-			}
-			vmOperatorVM.Spec.PowerOffMode = powerOffMode
-		}
+	}
 
-		if vmOperatorVM.Spec.MinHardwareVersion == 0 {
-			vmOperatorVM.Spec.MinHardwareVersion = minHardwareVersion
-		}
+	// VM Operator does not allow to change these fields once the VirtualMachine has been created,
+	// so the values of an existing VirtualMachine are kept.
+	if vmOperatorVM.Spec.ImageName != "" {
+		desiredVM.Spec.ImageName = vmOperatorVM.Spec.ImageName
+	}
+	if vmOperatorVM.Spec.ClassName != "" {
+		desiredVM.Spec.ClassName = vmOperatorVM.Spec.ClassName
+	}
+	if vmOperatorVM.Spec.StorageClass != "" {
+		desiredVM.Spec.StorageClass = vmOperatorVM.Spec.StorageClass
+	}
+	if vmOperatorVM.Spec.MinHardwareVersion != 0 {
+		desiredVM.Spec.MinHardwareVersion = vmOperatorVM.Spec.MinHardwareVersion
+	}
+	if desiredVM.Spec.Reserved != nil && vmOperatorVM.Spec.Reserved != nil && vmOperatorVM.Spec.Reserved.ResourcePolicyName != "" {
+		desiredVM.Spec.Reserved.ResourcePolicyName = vmOperatorVM.Spec.Reserved.ResourcePolicyName
+	}
 
-		// VMOperator supports readiness probe and will add/remove endpoints to a
-		// VirtualMachineService based on the outcome of the readiness check.
-		// When creating the initial control plane node, we do not declare a probe
-		// in order to reduce the likelihood of a race between the VirtualMachineService
-		// endpoint additions and the kubeadm commands run on the VM itself.
-		// Once the initial control plane node is ready, we can re-add the probe so
-		// that subsequent machines do not attempt to speak to a kube-apiserver
-		// that is not yet ready.
-		// Not all network providers (for example, NSX-VPC) provide support for VM
-		// readiness probes. The flag PerformsVMReadinessProbe is used to determine
-		// whether a VM readiness probe should be conducted.
-		if v.ConfigureControlPlaneVMReadinessProbe && infrautilv1.IsControlPlaneMachine(supervisorMachineCtx.Machine) && supervisorMachineCtx.Cluster.Status.ControlPlaneReady {
-			vmOperatorVM.Spec.ReadinessProbe = &vmoprv1.VirtualMachineReadinessProbeSpec{
-				TCPSocket: &vmoprv1.TCPSocketAction{
-					Port: intstr.FromInt(defaultAPIBindPort),
-				},
-			}
+	if supervisorMachineCtx.VSphereMachine.Spec.PowerOffMode != "" {
+		var powerOffMode vmoprv1.VirtualMachinePowerOpMode
+		switch supervisorMachineCtx.VSphereMachine.Spec.PowerOffMode {
+		case vmwarev1.VirtualMachinePowerOpModeHard:
+			powerOffMode = vmoprv1.VirtualMachinePowerOpModeHard
+		case vmwarev1.VirtualMachinePowerOpModeSoft:
+			powerOffMode = vmoprv1.VirtualMachinePowerOpModeSoft
+		case vmwarev1.VirtualMachinePowerOpModeTrySoft:
+			powerOffMode = vmoprv1.VirtualMachinePowerOpModeTrySoft
+		default:
+			return fmt.Errorf("unable to map PowerOffMode %q to vm-operator equivalent", supervisorMachineCtx.VSphereMachine.Spec.PowerOffMode)
 		}
+		desiredVM.Spec.PowerOffMode = powerOffMode
+	}
+
+	// VMOperator supports readiness probe and will add/remove endpoints to a
+	// VirtualMachineService based on the outcome of the readiness check.
+	// When creating the initial control plane node, we do not declare a probe
+	// in order to reduce the likelihood of a race between the VirtualMachineService
+	// endpoint additions and the kubeadm commands run on the VM itself.
+	// Once the initial control plane node is ready, we can re-add the probe so
+	// that subsequent machines do not attempt to speak to a kube-apiserver
+	// that is not yet ready.
+	// Not all network providers (for example, NSX-VPC) provide support for VM
+	// readiness probes. The flag PerformsVMReadinessProbe is used to determine
+	// whether a VM readiness probe should be conducted.
+	// NOTE: Once set, the probe is kept even if the control plane is not reported as ready anymore.
+	if v.ConfigureControlPlaneVMReadinessProbe && infrautilv1.IsControlPlaneMachine(supervisorMachineCtx.Machine) &&
+		(supervisorMachineCtx.Cluster.Status.ControlPlaneReady || vmOperatorVM.Spec.ReadinessProbe != nil) {
+		desiredVM.Spec.ReadinessProbe = getVMReadinessProbe(supervisorMachineCtx.VSphereMachine.Spec.ReadinessProbe)
+	}
+
+	// Assign the user provided labels and annotations first, so that the ones
+	// managed by CAPV take precedence.
+	// NOTE: Keys removed from the VSphereMachine are removed from the VM as well,
+	// because server side apply drops fields which are not part of the applied object anymore.
+	var vmLabels map[string]string
+	if md := supervisorMachineCtx.VSphereMachine.Spec.VirtualMachineMetadata; md != nil {
+		vmLabels = maps.Clone(md.Labels)
+		desiredVM.Annotations = maps.Clone(md.Annotations)
+	}
+
+	// Assign the VM's labels.
+	desiredVM.Labels = getVMLabels(supervisorMachineCtx, vmLabels)
+
+	addResourcePolicyAnnotations(supervisorMachineCtx, desiredVM)
+
+	if err := v.addVolumes(ctx, supervisorMachineCtx, desiredVM); err != nil {
+		return err
+	}
 
-		// Assign the VM's labels.
-		vmOperatorVM.Labels = getVMLabels(supervisorMachineCtx, vmOperatorVM.Labels)
-
-		addResourcePolicyAnnotations(supervisorMachineCtx, vmOperatorVM)
-
-		if err := v.addVolumes(ctx, supervisorMachineCtx, vmOperatorVM); err != nil {
+	// Apply hooks to modify the VM spec
+	// The hooks are loosely typed so as to allow for different VirtualMachine backends
+	for _, vmModifier := range supervisorMachineCtx.VMModifiers {
+		modified, err := vmModifier(desiredVM)
+		if err != nil {
 			return err
 		}
-
-		// Apply hooks to modify the VM spec
-		// The hooks are loosely typed so as to allow for different VirtualMachine backends
-		for _, vmModifier := range supervisorMachineCtx.VMModifiers {
-			modified, err := vmModifier(vmOperatorVM)
-			if err != nil {
-				return err
-			}
-			typedModified, ok := modified.(*vmoprv1.VirtualMachine)
-			if !ok {
-				return fmt.Errorf("VM modifier returned result of the wrong type: %T", typedModified)
-			}
-			vmOperatorVM = typedModified
+		typedModified, ok := modified.(*vmoprv1.VirtualMachine)
+		if !ok {
+			return fmt.Errorf("VM modifier returned result of the wrong type: %T", typedModified)
 		}
+		desiredVM = typedModified
+	}
 
-		// Make sure the VSphereMachine owns the VM Operator VirtualMachine.
-		if err := ctrlutil.SetControllerReference(supervisorMachineCtx.VSphereMachine, vmOperatorVM, v.Client.Scheme()); err != nil {
-			return errors.Wrapf(err, "failed to mark %s %s/%s as owner of %s %s/%s",
-				supervisorMachineCtx.VSphereMachine.GroupVersionKind(),
-				supervisorMachineCtx.VSphereMachine.Namespace,
-				supervisorMachineCtx.VSphereMachine.Name,
-				vmOperatorVM.GroupVersionKind(),
-				vmOperatorVM.Namespace,
-				vmOperatorVM.Name)
-		}
+	// Make sure the VSphereMachine owns the VM Operator VirtualMachine.
+	if err := ctrlutil.SetControllerReference(supervisorMachineCtx.VSphereMachine, desiredVM, v.Client.Scheme()); err != nil {
+		return errors.Wrapf(err, "failed to mark %s %s/%s as owner of %s %s/%s",
+			supervisorMachineCtx.VSphereMachine.GroupVersionKind(),
+			supervisorMachineCtx.VSphereMachine.Namespace,
+			supervisorMachineCtx.VSphereMachine.Name,
+			desiredVM.GroupVersionKind(),
+			desiredVM.Namespace,
+			desiredVM.Name)
+	}
 
-		return nil
-	})
-	return err
+	if err := apply(ctx, v.Client, desiredVM); err != nil {
+		return err
+	}
+	desiredVM.DeepCopyInto(vmOperatorVM)
+	return nil
 }
 
 func (v *VmopMachineService) reconcileNetwork(supervisorMachineCtx *vmware.SupervisorMachineContext, vm *vmoprv1.VirtualMachine) bool {
@@ -578,6 +606,62 @@ func (v *VmopMachineService) getVirtualMachinesInCluster(ctx context.Context, su
 	return vms, nil
 }
 
+// vmOperatorVMMirroredConditions maps the conditions of the VM Operator VirtualMachine
+// to the VSphereMachine v1beta2 conditions they are surfaced as.
+var vmOperatorVMMirroredConditions = []struct {
+	source string
+	target string
+}{
+	{source: vmoprv1.VirtualMachineConditionClassReady, target: vmwarev1.VSphereMachineVirtualMachineClassReadyV1Beta2Condition},
+	{source: vmoprv1.VirtualMachineConditionImageReady, target: vmwarev1.VSphereMachineVirtualMachineImageReadyV1Beta2Condition},
+	{source: vmoprv1.VirtualMachineConditionVMSetResourcePolicyReady, target: vmwarev1.VSphereMachineVirtualMachineSetResourcePolicyReadyV1Beta2Condition},
+	{source: vmoprv1.VirtualMachineConditionBootstrapReady, target: vmwarev1.VSphereMachineVirtualMachineBootstrapReadyV1Beta2Condition},
+	{source: vmoprv1.VirtualMachineConditionStorageReady, target: vmwarev1.VSphereMachineVirtualMachineStorageReadyV1Beta2Condition},
+	{source: vmoprv1.VirtualMachineConditionNetworkReady, target: vmwarev1.VSphereMachineVirtualMachineNetworkReadyV1Beta2Condition},
+	{source: vmoprv1.VirtualMachineConditionPlacementReady, target: vmwarev1.VSphereMachineVirtualMachinePlacementReadyV1Beta2Condition},
+	{source: vmoprv1.VirtualMachineConditionCreated, target: vmwarev1.VSphereMachineVirtualMachineCreatedV1Beta2Condition},
+}
+
+// mirrorVMOperatorVMConditions surfaces the conditions of the VM Operator VirtualMachine as
+// v1beta2 conditions on the VSphereMachine.
+// NOTE: VM Operator only reports conditions which are relevant for the VirtualMachine, so conditions
+// which are not reported are removed from the VSphereMachine instead of being surfaced as unknown.
+func mirrorVMOperatorVMConditions(vsphereMachine *vmwarev1.VSphereMachine, vmOperatorVM *vmoprv1.VirtualMachine) {
+	for _, mirror := range vmOperatorVMMirroredConditions {
+		c := meta.FindStatusCondition(vmOperatorVM.Status.Conditions, mirror.source)
+		if c == nil {
+			if v1beta2conditions.Has(vsphereMachine, mirror.target) {
+				v1beta2conditions.Delete(vsphereMachine, mirror.target)
+			}
+			continue
+		}
+
+		// Reason is required for v1beta2 conditions, fall back to the status if VM Operator did not set one.
+		reason := c.Reason
+		if reason == "" {
+			reason = string(c.Status)
+		}
+
+		v1beta2conditions.Set(vsphereMachine, metav1.Condition{
+			Type:   mirror.target,
+			Status: c.Status,
+			// NOTE: preserving the original transition time of the VM Operator VirtualMachine condition.
+			LastTransitionTime: c.LastTransitionTime,
+			Reason:             reason,
+			Message:            c.Message,
+		})
+	}
+}
+
+// getResourcePolicyName returns the name of the VirtualMachineSetResourcePolicy for the VM,
+// which is the one of the VSphereMachine if set, otherwise the one of the VSphereCluster.
+func getResourcePolicyName(supervisorMachineCtx *vmware.SupervisorMachineContext) string {
+	if supervisorMachineCtx.VSphereMachine.Spec.ResourcePolicyName != "" {
+		return supervisorMachineCtx.VSphereMachine.Spec.ResourcePolicyName
+	}
+	return supervisorMachineCtx.VSphereCluster.Status.ResourcePolicyName
+}
+
 // Helper function to add annotations to indicate which tag vm-operator should add as well as which clusterModule VM
 // should be associated.
 func addResourcePolicyAnnotations(supervisorMachineCtx *vmware.SupervisorMachineContext, vm *vmoprv1.VirtualMachine) {
@@ -634,6 +718,13 @@ func (v *VmopMachineService) addVolumes(ctx context.Context, supervisorMachineCt
 		if volume.StorageClass == "" {
 			storageClassName = supervisorMachineCtx.VSphereMachine.Spec.StorageClass
 		}
+		if volume.BootDiskAntiAffinity && storageClassName == supervisorMachineCtx.VSphereMachine.Spec.StorageClass {
+			return errors.Errorf("volume %s requires boot disk anti-affinity but uses the storage class %q of the boot disk", volume.Name, storageClassName)
+		}
+		accessModes := volume.AccessModes
+		if len(accessModes) == 0 {
+			accessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+		}
 
 		pvc := &corev1.PersistentVolumeClaim{
 			ObjectMeta: metav1.ObjectMeta{
@@ -641,9 +732,7 @@ func (v *VmopMachineService) addVolumes(ctx context.Context, supervisorMachineCt
 				Namespace: supervisorMachineCtx.VSphereMachine.Namespace,
 			},
 			Spec: corev1.PersistentVolumeClaimSpec{
-				AccessModes: []corev1.PersistentVolumeAccessMode{
-					corev1.ReadWriteOnce,
-				},
+				AccessModes: accessModes,
 				Resources: corev1.VolumeResourceRequirements{
 					Requests: volume.Capacity,
 				},
@@ -670,23 +759,21 @@ func (v *VmopMachineService) addVolumes(ctx context.Context, supervisorMachineCt
 			}
 		}
 
-		if _, err := ctrlutil.CreateOrPatch(ctx, v.Client, pvc, func() error {
-			if err := ctrlutil.SetOwnerReference(
-				supervisorMachineCtx.VSphereMachine,
-				pvc,
-				v.Client.Scheme(),
-			); err != nil {
-				return errors.Wrapf(
-					err,
-					"error setting %s/%s as owner of %s/%s",
-					supervisorMachineCtx.VSphereMachine.Namespace,
-					supervisorMachineCtx.VSphereMachine.Name,
-					pvc.Namespace,
-					pvc.Name,
-				)
-			}
-			return nil
-		}); err != nil {
+		if err := ctrlutil.SetOwnerReference(
+			supervisorMachineCtx.VSphereMachine,
+			pvc,
+			v.Client.Scheme(),
+		); err != nil {
+			return errors.Wrapf(
+				err,
+				"error setting %s/%s as owner of %s/%s",
+				supervisorMachineCtx.VSphereMachine.Namespace,
+				supervisorMachineCtx.VSphereMachine.Name,
+				pvc.Namespace,
+				pvc.Name,
+			)
+		}
+		if err := apply(ctx, v.Client, pvc); err != nil {
 			return errors.Wrapf(
 				err,
 				"failed to create volume %s/%s",
@@ -700,6 +787,52 @@ func (v *VmopMachineService) addVolumes(ctx context.Context, supervisorMachineCt
 	return nil
 }
 
+// reconcileVolumeStatus sets the attach status of the volumes of the VSphereMachine
+// from the volume status of the VM Operator VirtualMachine.
+func reconcileVolumeStatus(machine *vmwarev1.VSphereMachine, vm *vmoprv1.VirtualMachine) {
+	if len(machine.Spec.Volumes) == 0 {
+		machine.Status.Volumes = nil
+		conditions.Delete(machine, vmwarev1.VolumesAttachedCondition)
+		return
+	}
+
+	vmVolumes := map[string]vmoprv1.VirtualMachineVolumeStatus{}
+	for _, volume := range vm.Status.Volumes {
+		vmVolumes[volume.Name] = volume
+	}
+
+	var pending, failed []string
+	machine.Status.Volumes = make([]vmwarev1.VSphereMachineVolumeStatus, 0, len(machine.Spec.Volumes))
+	for _, volume := range machine.Spec.Volumes {
+		status := vmwarev1.VSphereMachineVolumeStatus{
+			Name:      volume.Name,
+			ClaimName: volumeName(machine, volume),
+		}
+		if vmVolume, ok := vmVolumes[status.ClaimName]; ok {
+			status.Attached = vmVolume.Attached
+			status.Error = vmVolume.Error
+		}
+		switch {
+		case status.Error != "":
+			failed = append(failed, fmt.Sprintf("%s: %s", volume.Name, status.Error))
+		case !status.Attached:
+			pending = append(pending, volume.Name)
+		}
+		machine.Status.Volumes = append(machine.Status.Volumes, status)
+	}
+
+	switch {
+	case len(failed) > 0:
+		conditions.MarkFalse(machine, vmwarev1.VolumesAttachedCondition, vmwarev1.VolumeAttachmentFailedReason, clusterv1.ConditionSeverityWarning,
+			"Failed to attach volumes: %s", strings.Join(failed, "; "))
+	case len(pending) > 0:
+		conditions.MarkFalse(machine, vmwarev1.VolumesAttachedCondition, vmwarev1.WaitingForVolumeAttachmentReason, clusterv1.ConditionSeverityInfo,
+			"Waiting for volumes to be attached: %s", strings.Join(pending, ", "))
+	default:
+		conditions.MarkTrue(machine, vmwarev1.VolumesAttachedCondition)
+	}
+}
+
 // getVMLabels returns the labels applied to a VirtualMachine.
 func getVMLabels(supervisorMachineCtx *vmware.SupervisorMachineContext, vmLabels map[string]string) map[string]string {
 	if vmLabels == nil {
@@ -747,3 +880,42 @@ func getTopologyLabels(supervisorMachineCtx *vmware.SupervisorMachineContext) ma
 func getMachineDeploymentNameForCluster(cluster *clusterv1.Cluster) string {
 	return fmt.Sprintf("%s-workers-0", cluster.Name)
 }
+
+// isMigratingFromGovmomi returns true if the Cluster still references a govmomi-mode
+// VSphereCluster, which is the case while its machines are migrated to supervisor mode.
+func isMigratingFromGovmomi(cluster *clusterv1.Cluster) bool {
+	ref := cluster.Spec.InfrastructureRef
+	return ref != nil && ref.GroupVersionKind().Group == infrav1.GroupVersion.Group
+}
+
+// getVMReadinessProbe returns the readiness probe of a control plane VirtualMachine.
+// It defaults to a TCP probe on the API server bind port.
+func getVMReadinessProbe(probe *vmwarev1.VirtualMachineReadinessProbe) *vmoprv1.VirtualMachineReadinessProbeSpec {
+	if probe == nil {
+		probe = &vmwarev1.VirtualMachineReadinessProbe{}
+	}
+
+	readinessProbe := &vmoprv1.VirtualMachineReadinessProbeSpec{
+		PeriodSeconds:  probe.PeriodSeconds,
+		TimeoutSeconds: probe.TimeoutSeconds,
+	}
+	switch probe.Type {
+	case vmwarev1.VirtualMachineReadinessProbeTypeGuestHeartbeat:
+		threshold := vmoprv1.GreenHeartbeatStatus
+		if probe.GuestHeartbeatThreshold != "" {
+			threshold = vmoprv1.GuestHeartbeatStatus(probe.GuestHeartbeatThreshold)
+		}
+		readinessProbe.GuestHeartbeat = &vmoprv1.GuestHeartbeatAction{
+			ThresholdStatus: threshold,
+		}
+	default:
+		port := int32(defaultAPIBindPort)
+		if probe.Port != nil {
+			port = *probe.Port
+		}
+		readinessProbe.TCPSocket = &vmoprv1.TCPSocketAction{ //nolint:staticcheck
+			Port: intstr.FromInt32(port),
+		}
+	}
+	return readinessProbe
+}
@@ -31,9 +31,11 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	v1beta2conditions "sigs.k8s.io/cluster-api/util/conditions/v1beta2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
@@ -527,6 +529,105 @@ var _ = Describe("VirtualMachine tests", func() {
 			Expect(vmopVM.Spec.Volumes[0]).To(BeEquivalentTo(vmVolume))
 		})
 
+		Specify("Use the resource policy and the failure domain of the VSphereMachine", func() {
+			secretName := machine.GetName() + "-data"
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      secretName,
+					Namespace: machine.GetNamespace(),
+				},
+				Data: map[string][]byte{
+					"value": []byte(bootstrapData),
+				},
+			}
+			Expect(vmService.Client.Create(ctx, secret)).To(Succeed())
+			machine.Spec.Bootstrap.DataSecretName = &secretName
+
+			zone := "zone-b"
+			vsphereMachine.Spec.ResourcePolicyName = "guaranteed"
+			vsphereMachine.Spec.FailureDomain = &zone
+
+			By("VirtualMachine is created")
+			_, err = vmService.ReconcileNormal(ctx, supervisorMachineContext)
+			Expect(err).ToNot(HaveOccurred())
+
+			vmopVM = getReconciledVM(ctx, vmService, supervisorMachineContext)
+			Expect(vmopVM).ToNot(BeNil())
+			Expect(vmopVM.Spec.Reserved).ToNot(BeNil())
+			Expect(vmopVM.Spec.Reserved.ResourcePolicyName).To(Equal("guaranteed"))
+			Expect(vmopVM.Labels[kubeTopologyZoneLabelKey]).To(Equal(zone))
+		})
+
+		Specify("Propagate the VirtualMachine metadata of the VSphereMachine", func() {
+			secretName := machine.GetName() + "-data"
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      secretName,
+					Namespace: machine.GetNamespace(),
+				},
+				Data: map[string][]byte{
+					"value": []byte(bootstrapData),
+				},
+			}
+			Expect(vmService.Client.Create(ctx, secret)).To(Succeed())
+			machine.Spec.Bootstrap.DataSecretName = &secretName
+
+			vsphereMachine.Spec.VirtualMachineMetadata = &vmwarev1.VirtualMachineMetadata{
+				Labels: map[string]string{
+					"backup.example.com/policy": "daily",
+					clusterNameLabel:            "overridden",
+				},
+				Annotations: map[string]string{
+					"monitoring.example.com/scrape": "true",
+					ProviderTagsAnnotationKey:       "overridden",
+				},
+			}
+
+			By("VirtualMachine is created with the user provided metadata")
+			_, err = vmService.ReconcileNormal(ctx, supervisorMachineContext)
+			Expect(err).ToNot(HaveOccurred())
+
+			vmopVM = getReconciledVM(ctx, vmService, supervisorMachineContext)
+			Expect(vmopVM).ToNot(BeNil())
+			Expect(vmopVM.Labels).To(HaveKeyWithValue("backup.example.com/policy", "daily"))
+			Expect(vmopVM.Annotations).To(HaveKeyWithValue("monitoring.example.com/scrape", "true"))
+
+			By("Labels and annotations managed by CAPV take precedence")
+			Expect(vmopVM.Labels).To(HaveKeyWithValue(clusterNameLabel, clusterName))
+			Expect(vmopVM.Annotations).To(HaveKeyWithValue(ProviderTagsAnnotationKey, ControlPlaneVMVMAntiAffinityTagValue))
+
+			By("The VSphereMachine metadata is not modified")
+			Expect(vsphereMachine.Spec.VirtualMachineMetadata.Labels).To(HaveKeyWithValue(clusterNameLabel, "overridden"))
+		})
+
+		Specify("Ignore the failure domain of the Machine while the cluster is migrated from govmomi mode", func() {
+			secretName := machine.GetName() + "-data"
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      secretName,
+					Namespace: machine.GetNamespace(),
+				},
+				Data: map[string][]byte{
+					"value": []byte(bootstrapData),
+				},
+			}
+			Expect(vmService.Client.Create(ctx, secret)).To(Succeed())
+			machine.Spec.Bootstrap.DataSecretName = &secretName
+
+			deploymentZone := "deployment-zone"
+			machine.Spec.FailureDomain = &deploymentZone
+			cluster.Spec.InfrastructureRef.APIVersion = infrav1.GroupVersion.String()
+
+			By("VirtualMachine is created")
+			_, err = vmService.ReconcileNormal(ctx, supervisorMachineContext)
+			Expect(err).ToNot(HaveOccurred())
+
+			vmopVM = getReconciledVM(ctx, vmService, supervisorMachineContext)
+			Expect(vmopVM).ToNot(BeNil())
+			Expect(vsphereMachine.Spec.FailureDomain).To(BeNil())
+			Expect(vmopVM.Labels).ToNot(HaveKey(kubeTopologyZoneLabelKey))
+		})
+
 		Specify("Create and attach volumes", func() {
 			expectReconcileError = false
 			expectVMOpVM = true
@@ -545,6 +646,7 @@ var _ = Describe("VirtualMachine tests", func() {
 					Capacity: corev1.ResourceList{
 						corev1.ResourceStorage: resource.MustParse("6Gi"),
 					},
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOncePod},
 				},
 			}
 
@@ -573,6 +675,32 @@ var _ = Describe("VirtualMachine tests", func() {
 
 				Expect(vmopVM.Spec.Volumes[i]).To(BeEquivalentTo(vmVolume))
 			}
+
+			By("Checking the access modes of the PVCs")
+			pvc := &corev1.PersistentVolumeClaim{}
+			Expect(vmService.Client.Get(ctx, client.ObjectKey{Namespace: vsphereMachine.Namespace, Name: volumeName(vsphereMachine, vsphereMachine.Spec.Volumes[0])}, pvc)).To(Succeed())
+			Expect(pvc.Spec.AccessModes).To(Equal([]corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}))
+			Expect(vmService.Client.Get(ctx, client.ObjectKey{Namespace: vsphereMachine.Namespace, Name: volumeName(vsphereMachine, vsphereMachine.Spec.Volumes[1])}, pvc)).To(Succeed())
+			Expect(pvc.Spec.AccessModes).To(Equal([]corev1.PersistentVolumeAccessMode{corev1.ReadWriteOncePod}))
+
+			By("Checking that the volumes are reported as not attached")
+			Expect(vsphereMachine.Status.Volumes).To(HaveLen(2))
+			Expect(conditions.GetReason(vsphereMachine, vmwarev1.VolumesAttachedCondition)).To(Equal(vmwarev1.WaitingForVolumeAttachmentReason))
+		})
+
+		Specify("Reconcile fails for volumes with boot disk anti-affinity using the boot disk storage class", func() {
+			vsphereMachine.Spec.Volumes = []vmwarev1.VSphereMachineVolume{
+				{
+					Name: "etcd",
+					Capacity: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("1Gi"),
+					},
+					BootDiskAntiAffinity: true,
+				},
+			}
+
+			_, err = vmService.ReconcileNormal(ctx, supervisorMachineContext)
+			Expect(err).To(MatchError(ContainSubstring("requires boot disk anti-affinity")))
 		})
 	})
 
@@ -724,3 +852,155 @@ func Test_virtualMachineObjectKey(t *testing.T) {
 		})
 	}
 }
+
+func Test_mirrorVMOperatorVMConditions(t *testing.T) {
+	g := NewWithT(t)
+
+	vsphereMachine := &vmwarev1.VSphereMachine{}
+	v1beta2conditions.Set(vsphereMachine, metav1.Condition{
+		Type:   vmwarev1.VSphereMachineVirtualMachineBootstrapReadyV1Beta2Condition,
+		Status: metav1.ConditionFalse,
+		Reason: "NotReady",
+	})
+
+	vmOperatorVM := &vmoprv1.VirtualMachine{
+		Status: vmoprv1.VirtualMachineStatus{
+			Conditions: []metav1.Condition{
+				{
+					Type:   vmoprv1.VirtualMachineConditionClassReady,
+					Status: metav1.ConditionTrue,
+					Reason: string(metav1.ConditionTrue),
+				},
+				{
+					Type:    vmoprv1.VirtualMachineConditionImageReady,
+					Status:  metav1.ConditionFalse,
+					Reason:  "NotFound",
+					Message: "image not found",
+				},
+				{
+					Type:   vmoprv1.VirtualMachineConditionStorageReady,
+					Status: metav1.ConditionTrue,
+				},
+			},
+		},
+	}
+
+	mirrorVMOperatorVMConditions(vsphereMachine, vmOperatorVM)
+
+	c := v1beta2conditions.Get(vsphereMachine, vmwarev1.VSphereMachineVirtualMachineClassReadyV1Beta2Condition)
+	g.Expect(c).ToNot(BeNil())
+	g.Expect(c.Status).To(Equal(metav1.ConditionTrue))
+
+	c = v1beta2conditions.Get(vsphereMachine, vmwarev1.VSphereMachineVirtualMachineImageReadyV1Beta2Condition)
+	g.Expect(c).ToNot(BeNil())
+	g.Expect(c.Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(c.Reason).To(Equal("NotFound"))
+	g.Expect(c.Message).To(Equal("image not found"))
+
+	// The reason falls back to the status if VM Operator did not set a reason.
+	c = v1beta2conditions.Get(vsphereMachine, vmwarev1.VSphereMachineVirtualMachineStorageReadyV1Beta2Condition)
+	g.Expect(c).ToNot(BeNil())
+	g.Expect(c.Reason).To(Equal(string(metav1.ConditionTrue)))
+
+	// Conditions not reported by VM Operator are not surfaced.
+	g.Expect(v1beta2conditions.Has(vsphereMachine, vmwarev1.VSphereMachineVirtualMachineBootstrapReadyV1Beta2Condition)).To(BeFalse())
+	g.Expect(v1beta2conditions.Has(vsphereMachine, vmwarev1.VSphereMachineVirtualMachinePlacementReadyV1Beta2Condition)).To(BeFalse())
+}
+
+func Test_reconcileVolumeStatus(t *testing.T) {
+	g := NewWithT(t)
+
+	vsphereMachine := &vmwarev1.VSphereMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: "machine"},
+		Spec: vmwarev1.VSphereMachineSpec{
+			Volumes: []vmwarev1.VSphereMachineVolume{{Name: "etcd"}, {Name: "containerd"}},
+		},
+	}
+	vmOperatorVM := &vmoprv1.VirtualMachine{
+		Status: vmoprv1.VirtualMachineStatus{
+			Volumes: []vmoprv1.VirtualMachineVolumeStatus{
+				{Name: "machine-etcd", Attached: true},
+			},
+		},
+	}
+
+	reconcileVolumeStatus(vsphereMachine, vmOperatorVM)
+	g.Expect(vsphereMachine.Status.Volumes).To(Equal([]vmwarev1.VSphereMachineVolumeStatus{
+		{Name: "etcd", ClaimName: "machine-etcd", Attached: true},
+		{Name: "containerd", ClaimName: "machine-containerd"},
+	}))
+	g.Expect(conditions.GetReason(vsphereMachine, vmwarev1.VolumesAttachedCondition)).To(Equal(vmwarev1.WaitingForVolumeAttachmentReason))
+	g.Expect(conditions.GetMessage(vsphereMachine, vmwarev1.VolumesAttachedCondition)).To(Equal("Waiting for volumes to be attached: containerd"))
+
+	vmOperatorVM.Status.Volumes = append(vmOperatorVM.Status.Volumes, vmoprv1.VirtualMachineVolumeStatus{Name: "machine-containerd", Error: "no datastore available"})
+	reconcileVolumeStatus(vsphereMachine, vmOperatorVM)
+	g.Expect(conditions.GetReason(vsphereMachine, vmwarev1.VolumesAttachedCondition)).To(Equal(vmwarev1.VolumeAttachmentFailedReason))
+	g.Expect(conditions.GetMessage(vsphereMachine, vmwarev1.VolumesAttachedCondition)).To(Equal("Failed to attach volumes: containerd: no datastore available"))
+
+	vmOperatorVM.Status.Volumes[1] = vmoprv1.VirtualMachineVolumeStatus{Name: "machine-containerd", Attached: true}
+	reconcileVolumeStatus(vsphereMachine, vmOperatorVM)
+	g.Expect(conditions.IsTrue(vsphereMachine, vmwarev1.VolumesAttachedCondition)).To(BeTrue())
+
+	vsphereMachine.Spec.Volumes = nil
+	reconcileVolumeStatus(vsphereMachine, vmOperatorVM)
+	g.Expect(vsphereMachine.Status.Volumes).To(BeNil())
+	g.Expect(conditions.Has(vsphereMachine, vmwarev1.VolumesAttachedCondition)).To(BeFalse())
+}
+
+func Test_getVMReadinessProbe(t *testing.T) {
+	tests := []struct {
+		name  string
+		probe *vmwarev1.VirtualMachineReadinessProbe
+		want  *vmoprv1.VirtualMachineReadinessProbeSpec
+	}{
+		{
+			name:  "defaults to a TCP probe on the API server bind port",
+			probe: nil,
+			want: &vmoprv1.VirtualMachineReadinessProbeSpec{
+				TCPSocket: &vmoprv1.TCPSocketAction{Port: intstr.FromInt(defaultAPIBindPort)},
+			},
+		},
+		{
+			name: "TCP probe with a custom port, period and timeout",
+			probe: &vmwarev1.VirtualMachineReadinessProbe{
+				Type:           vmwarev1.VirtualMachineReadinessProbeTypeTCP,
+				Port:           ptr.To[int32](8443),
+				PeriodSeconds:  30,
+				TimeoutSeconds: 5,
+			},
+			want: &vmoprv1.VirtualMachineReadinessProbeSpec{
+				TCPSocket:      &vmoprv1.TCPSocketAction{Port: intstr.FromInt(8443)},
+				PeriodSeconds:  30,
+				TimeoutSeconds: 5,
+			},
+		},
+		{
+			name: "GuestHeartbeat probe defaults to the green threshold",
+			probe: &vmwarev1.VirtualMachineReadinessProbe{
+				Type: vmwarev1.VirtualMachineReadinessProbeTypeGuestHeartbeat,
+			},
+			want: &vmoprv1.VirtualMachineReadinessProbeSpec{
+				GuestHeartbeat: &vmoprv1.GuestHeartbeatAction{ThresholdStatus: vmoprv1.GreenHeartbeatStatus},
+			},
+		},
+		{
+			name: "GuestHeartbeat probe with the yellow threshold",
+			probe: &vmwarev1.VirtualMachineReadinessProbe{
+				Type:                    vmwarev1.VirtualMachineReadinessProbeTypeGuestHeartbeat,
+				GuestHeartbeatThreshold: vmwarev1.VirtualMachineGuestHeartbeatStatusYellow,
+				PeriodSeconds:           60,
+			},
+			want: &vmoprv1.VirtualMachineReadinessProbeSpec{
+				GuestHeartbeat: &vmoprv1.GuestHeartbeatAction{ThresholdStatus: vmoprv1.YellowHeartbeatStatus},
+				PeriodSeconds:  60,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(getVMReadinessProbe(tt.probe)).To(Equal(tt.want))
+		})
+	}
+}
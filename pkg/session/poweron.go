@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/task"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// powerOnBatchWindow is the time power on requests are collected before they are sent to vCenter.
+const powerOnBatchWindow = 500 * time.Millisecond
+
+// powerOnBatcher batches the power on of VMs of a datacenter into PowerOnMultiVM_Tasks.
+type powerOnBatcher struct {
+	datacenter *object.Datacenter
+
+	mu      sync.Mutex
+	pending []*powerOnRequest
+}
+
+type powerOnRequest struct {
+	ref  types.ManagedObjectReference
+	done chan powerOnResult
+}
+
+type powerOnResult struct {
+	task *object.Task
+	err  error
+}
+
+// powerOn adds a VM to the next batch and returns the task powering on the VM once the batch has been sent.
+func (b *powerOnBatcher) powerOn(ctx context.Context, ref types.ManagedObjectReference) (*object.Task, error) {
+	req := &powerOnRequest{ref: ref, done: make(chan powerOnResult, 1)}
+
+	b.mu.Lock()
+	b.pending = append(b.pending, req)
+	if len(b.pending) == 1 {
+		time.AfterFunc(powerOnBatchWindow, func() {
+			b.flush(context.WithoutCancel(ctx))
+		})
+	}
+	b.mu.Unlock()
+
+	select {
+	case res := <-req.done:
+		return res.task, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// flush powers on the VMs of the pending requests using a single PowerOnMultiVM_Task.
+func (b *powerOnBatcher) flush(ctx context.Context) {
+	b.mu.Lock()
+	reqs := b.pending
+	b.pending = nil
+	b.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	tasks, err := b.powerOnMultiVM(ctx, reqs)
+	for _, req := range reqs {
+		res := powerOnResult{err: err}
+		if err == nil {
+			res = tasks[req.ref]
+		}
+		req.done <- res
+	}
+}
+
+func (b *powerOnBatcher) powerOnMultiVM(ctx context.Context, reqs []*powerOnRequest) (map[types.ManagedObjectReference]powerOnResult, error) {
+	refs := make([]types.ManagedObjectReference, 0, len(reqs))
+	for _, req := range reqs {
+		refs = append(refs, req.ref)
+	}
+
+	multiTask, err := b.datacenter.PowerOnVM(ctx, refs)
+	if err != nil {
+		return nil, err
+	}
+	info, err := multiTask.WaitForResult(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result, ok := info.Result.(types.ClusterPowerOnVmResult)
+	if !ok {
+		return nil, errors.Errorf("unexpected result %T of PowerOnMultiVM_Task %s", info.Result, multiTask.Reference().Value)
+	}
+
+	tasks := map[types.ManagedObjectReference]powerOnResult{}
+	for _, ref := range refs {
+		tasks[ref] = powerOnResult{err: errors.Errorf("VM %s was not powered on by PowerOnMultiVM_Task %s", ref.Value, multiTask.Reference().Value)}
+	}
+	for _, attempted := range result.Attempted {
+		if attempted.Task == nil {
+			continue
+		}
+		tasks[attempted.Vm] = powerOnResult{task: object.NewTask(b.datacenter.Client(), *attempted.Task)}
+	}
+	for i := range result.NotAttempted {
+		notAttempted := result.NotAttempted[i]
+		// The fault is returned like the error of a task, so that its reason can be reported.
+		tasks[notAttempted.Vm] = powerOnResult{err: errors.Wrapf(task.Error{LocalizedMethodFault: &notAttempted.Fault}, "failed to power on VM %s", notAttempted.Vm.Value)}
+	}
+	return tasks, nil
+}
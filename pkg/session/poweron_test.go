@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestPowerOnBatcher(t *testing.T) {
+	g := NewWithT(t)
+
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		finder := find.NewFinder(c)
+		dc, err := finder.DefaultDatacenter(ctx)
+		g.Expect(err).ToNot(HaveOccurred())
+		finder.SetDatacenter(dc)
+
+		vms := []*object.VirtualMachine{}
+		for _, name := range []string{"DC0_H0_VM0", "DC0_H0_VM1", "DC0_C0_RP0_VM0"} {
+			vm, err := finder.VirtualMachine(ctx, name)
+			g.Expect(err).ToNot(HaveOccurred())
+			task, err := vm.PowerOff(ctx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(task.Wait(ctx)).To(Succeed())
+			vms = append(vms, vm)
+		}
+
+		// The VMs powered on concurrently are powered on in a single batch,
+		// and each of them gets the task powering it on.
+		b := &powerOnBatcher{datacenter: dc}
+		var wg sync.WaitGroup
+		for _, vm := range vms {
+			wg.Add(1)
+			go func(vm *object.VirtualMachine) {
+				defer wg.Done()
+				task, err := b.powerOn(ctx, vm.Reference())
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(task.Wait(ctx)).To(Succeed())
+			}(vm)
+		}
+		wg.Wait()
+
+		for _, vm := range vms {
+			powerState, err := vm.PowerState(ctx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(powerState).To(Equal(types.VirtualMachinePowerStatePoweredOn))
+		}
+	})
+}
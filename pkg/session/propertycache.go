@@ -0,0 +1,282 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// CachedVMProperties are the properties of VMs kept up to date by the PropertyCache.
+var CachedVMProperties = []string{
+	"config.extraConfig",
+	"config.hardware",
+	"config.instanceUuid",
+	"config.uuid",
+	"config.version",
+	"customValue",
+	"guest.guestStateChangeSupported",
+	"guest.net",
+	"guest.toolsRunningStatus",
+	"guestHeartbeatStatus",
+	"runtime.host",
+	"runtime.powerState",
+	"runtime.question",
+}
+
+// PropertyCache caches the properties of VMs using a PropertyCollector dedicated to the cache.
+// The properties of all the VMs watched by the cache are kept up to date by a single
+// WaitForUpdatesEx call, instead of being retrieved for each VM on every reconcile.
+type PropertyCache struct {
+	collector *property.Collector
+	cancel    context.CancelFunc
+
+	mu      sync.RWMutex
+	stopped bool
+	objects map[types.ManagedObjectReference]*cachedObject
+}
+
+type cachedObject struct {
+	filter *property.Filter
+	// ready is true once the initial values of the properties have been retrieved.
+	ready bool
+	// props are the values of the properties. Properties which have been removed have a nil value.
+	props map[string]types.AnyType
+}
+
+// newPropertyCache creates a PropertyCache and starts waiting for updates of the watched VMs.
+func newPropertyCache(ctx context.Context, client *vim25.Client) (*PropertyCache, error) {
+	collector, err := property.DefaultCollector(client).Create(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create PropertyCollector")
+	}
+
+	waitCtx, cancel := context.WithCancel(context.Background())
+	waitCtx = ctrl.LoggerInto(waitCtx, ctrl.LoggerFrom(ctx))
+	c := &PropertyCache{
+		collector: collector,
+		cancel:    cancel,
+		objects:   map[types.ManagedObjectReference]*cachedObject{},
+	}
+	go c.run(waitCtx)
+	return c, nil
+}
+
+// run applies the updates of the watched VMs to the cache until the cache is stopped
+// or waiting for updates fails, e.g. because the session expired.
+func (c *PropertyCache) run(ctx context.Context) {
+	log := ctrl.LoggerFrom(ctx)
+
+	err := c.collector.WaitForUpdatesEx(ctx, &property.WaitOptions{}, func(updates []types.ObjectUpdate) bool {
+		c.apply(ctx, updates)
+		return false
+	})
+	if err != nil {
+		log.Error(err, "Failed to wait for updates of the property cache")
+	}
+
+	c.mu.Lock()
+	c.stopped = true
+	c.objects = nil
+	c.mu.Unlock()
+
+	destroyCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := c.collector.Destroy(destroyCtx); err != nil {
+		log.V(4).Info("Failed to destroy the PropertyCollector of the property cache", "err", err.Error())
+	}
+}
+
+// apply applies updates of VMs to the cache.
+func (c *PropertyCache) apply(ctx context.Context, updates []types.ObjectUpdate) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+updates:
+	for _, update := range updates {
+		obj, ok := c.objects[update.Obj]
+		if !ok {
+			continue
+		}
+
+		// VMs which have been deleted are no longer watched.
+		if update.Kind == types.ObjectUpdateKindLeave {
+			c.unwatchLocked(ctx, update.Obj)
+			continue
+		}
+
+		for _, change := range update.ChangeSet {
+			switch change.Op {
+			case types.PropertyChangeOpAssign:
+				obj.props[change.Name] = change.Val
+			case types.PropertyChangeOpRemove, types.PropertyChangeOpIndirectRemove:
+				obj.props[change.Name] = nil
+			default:
+				// Nested changes are not expected as the filters do not request partial updates.
+				// The VM is watched again to get the values of its properties.
+				c.unwatchLocked(ctx, update.Obj)
+				continue updates
+			}
+		}
+	}
+}
+
+// Watch starts watching the properties of a VM and retrieves their initial values.
+func (c *PropertyCache) Watch(ctx context.Context, ref types.ManagedObjectReference) error {
+	c.mu.Lock()
+	if c.stopped {
+		c.mu.Unlock()
+		return errors.New("property cache is stopped")
+	}
+	if _, ok := c.objects[ref]; ok {
+		c.mu.Unlock()
+		return nil
+	}
+	// The object is added before the filter is created, as the initial values of its
+	// properties can be received before CreateFilter returns.
+	obj := &cachedObject{props: map[string]types.AnyType{}}
+	c.objects[ref] = obj
+	c.mu.Unlock()
+
+	spec := types.PropertyFilterSpec{
+		ObjectSet: []types.ObjectSpec{{Obj: ref}},
+		PropSet:   []types.PropertySpec{{Type: ref.Type, PathSet: CachedVMProperties}},
+	}
+	filter, err := c.collector.CreateFilter(ctx, types.CreateFilter{Spec: spec})
+	if err != nil {
+		c.Unwatch(ctx, ref)
+		return errors.Wrapf(err, "failed to watch properties of %s", ref.Value)
+	}
+
+	c.mu.Lock()
+	if c.objects[ref] != obj {
+		// The VM has been unwatched in the meantime.
+		c.mu.Unlock()
+		go destroyFilter(ctx, filter)
+		return nil
+	}
+	obj.filter = filter
+	c.mu.Unlock()
+
+	// The initial values of the properties are retrieved once the filter has been created,
+	// so that no later update is missed.
+	res, err := c.collector.RetrieveProperties(ctx, types.RetrieveProperties{SpecSet: []types.PropertyFilterSpec{spec}})
+	if err == nil && len(res.Returnval) == 0 {
+		err = errors.New("not found")
+	}
+	if err != nil {
+		c.Unwatch(ctx, ref)
+		return errors.Wrapf(err, "failed to retrieve properties of %s", ref.Value)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.objects[ref] != obj {
+		return nil
+	}
+	// Properties which have been updated in the meantime are at least as recent as the retrieved ones.
+	for _, prop := range res.Returnval[0].PropSet {
+		if _, ok := obj.props[prop.Name]; !ok {
+			obj.props[prop.Name] = prop.Val
+		}
+	}
+	obj.ready = true
+	return nil
+}
+
+// Unwatch stops watching the properties of a VM.
+func (c *PropertyCache) Unwatch(ctx context.Context, ref types.ManagedObjectReference) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.unwatchLocked(ctx, ref)
+}
+
+func (c *PropertyCache) unwatchLocked(ctx context.Context, ref types.ManagedObjectReference) {
+	obj, ok := c.objects[ref]
+	if !ok {
+		return
+	}
+	delete(c.objects, ref)
+	if obj.filter != nil {
+		go destroyFilter(ctx, obj.filter)
+	}
+}
+
+// Get loads the cached properties of a VM into dst, which must be a pointer to a mo.VirtualMachine.
+// It returns false if the properties are not cached, i.e. if the VM is not watched, its properties
+// have not been received yet or a property is not one of the CachedVMProperties.
+func (c *PropertyCache) Get(ref types.ManagedObjectReference, props []string, dst interface{}) (bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	obj, ok := c.objects[ref]
+	if !ok || !obj.ready {
+		return false, nil
+	}
+
+	content := types.ObjectContent{Obj: ref}
+	for _, name := range props {
+		if !isCachedVMProperty(name) {
+			return false, nil
+		}
+		// Properties without a value are unset, like when retrieving them.
+		if val := obj.props[name]; val != nil {
+			content.PropSet = append(content.PropSet, types.DynamicProperty{Name: name, Val: val})
+		}
+	}
+	if err := mo.LoadObjectContent([]types.ObjectContent{content}, dst); err != nil {
+		return false, errors.Wrapf(err, "failed to load cached properties of %s", ref.Value)
+	}
+	return true, nil
+}
+
+// Stop stops the cache.
+func (c *PropertyCache) Stop() {
+	c.cancel()
+}
+
+// isStopped returns true if the cache is stopped.
+func (c *PropertyCache) isStopped() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.stopped
+}
+
+func isCachedVMProperty(name string) bool {
+	for _, p := range CachedVMProperties {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+func destroyFilter(ctx context.Context, filter *property.Filter) {
+	destroyCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+	if err := filter.Destroy(destroyCtx); err != nil {
+		ctrl.LoggerFrom(ctx).V(4).Info("Failed to destroy PropertyFilter of the property cache", "err", err.Error())
+	}
+}
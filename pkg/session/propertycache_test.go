@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestPropertyCache(t *testing.T) {
+	g := NewWithT(t)
+
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		cache, err := newPropertyCache(ctx, c)
+		g.Expect(err).ToNot(HaveOccurred())
+		defer cache.Stop()
+
+		vm, err := find.NewFinder(c).VirtualMachine(ctx, "DC0_H0_VM0")
+		g.Expect(err).ToNot(HaveOccurred())
+		ref := vm.Reference()
+
+		// The properties of VMs which are not watched are not cached.
+		var o mo.VirtualMachine
+		g.Expect(cache.Get(ref, []string{"runtime.powerState"}, &o)).To(BeFalse())
+
+		g.Expect(cache.Watch(ctx, ref)).To(Succeed())
+		getPowerState := func() types.VirtualMachinePowerState {
+			var o mo.VirtualMachine
+			ok, err := cache.Get(ref, []string{"runtime.powerState", "config.uuid"}, &o)
+			g.Expect(err).ToNot(HaveOccurred())
+			if !ok {
+				return ""
+			}
+			g.Expect(o.Config.Uuid).ToNot(BeEmpty())
+			return o.Runtime.PowerState
+		}
+		g.Eventually(getPowerState, 10*time.Second).Should(Equal(types.VirtualMachinePowerStatePoweredOn))
+
+		// The cache is updated when the properties change.
+		task, err := vm.PowerOff(ctx)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(task.Wait(ctx)).To(Succeed())
+		g.Eventually(getPowerState, 10*time.Second).Should(Equal(types.VirtualMachinePowerStatePoweredOff))
+
+		// Properties which are not cached are not read from the cache.
+		g.Expect(cache.Get(ref, []string{"config.name"}, &o)).To(BeFalse())
+
+		cache.Unwatch(ctx, ref)
+		g.Expect(cache.Get(ref, []string{"runtime.powerState"}, &o)).To(BeFalse())
+
+		// The cache is stopped when it is stopped explicitly.
+		cache.Stop()
+		g.Eventually(cache.isStopped, 10*time.Second).Should(BeTrue())
+		g.Expect(cache.Watch(ctx, ref)).ToNot(Succeed())
+	})
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/vim25/soap"
+	"golang.org/x/net/http/httpproxy"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+// Proxy is the proxy used for the connections to vCenter.
+type Proxy struct {
+	// URL is the URL of the HTTP or SOCKS5 proxy.
+	URL string
+
+	// NoProxy is a list of hosts, domains, IP addresses and CIDRs which are
+	// connected to directly instead of through the proxy.
+	NoProxy []string
+
+	// CABundle is a PEM encoded bundle of CA certificates used to verify
+	// the certificate of vCenter, in addition to the CA bundle of the TLSConfig.
+	CABundle []byte
+}
+
+// NewProxy returns the Proxy for the given VCenterProxySpec or nil if spec is nil.
+func NewProxy(spec *infrav1.VCenterProxySpec) *Proxy {
+	if spec == nil {
+		return nil
+	}
+	return &Proxy{
+		URL:      spec.URL,
+		NoProxy:  spec.NoProxy,
+		CABundle: spec.CABundle,
+	}
+}
+
+// ProxyForCluster returns the proxy of the VSphereCluster if set, otherwise defaultProxy.
+func ProxyForCluster(vsphereCluster *infrav1.VSphereCluster, defaultProxy *Proxy) *Proxy {
+	if vsphereCluster == nil || vsphereCluster.Spec.Proxy == nil {
+		return defaultProxy
+	}
+	return NewProxy(vsphereCluster.Spec.Proxy)
+}
+
+// key returns a string identifying the proxy configuration in the session cache.
+func (p *Proxy) key() string {
+	if p == nil {
+		return ""
+	}
+	h := sha256.New()
+	h.Write([]byte(p.URL))
+	h.Write([]byte(strings.Join(p.NoProxy, ",")))
+	h.Write(p.CABundle)
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// configureRootCAs adds the CA bundles of the TLS configuration and of the proxy, in this order,
+// to the root CAs of the transport of the SOAP client, which is shared with the REST client.
+// The certificates of both bundles are trusted, neither of them replaces the other.
+func configureRootCAs(soapClient *soap.Client, proxy *Proxy, tlsConfig *TLSConfig) error {
+	config := soapClient.DefaultTransport().TLSClientConfig
+	if tlsConfig != nil {
+		if err := appendCABundle(config, tlsConfig.CABundle); err != nil {
+			return errors.Wrap(err, "invalid CA bundle of the TLS configuration")
+		}
+	}
+	if proxy != nil {
+		if err := appendCABundle(config, proxy.CABundle); err != nil {
+			return errors.Wrap(err, "invalid CA bundle of the proxy")
+		}
+	}
+	return nil
+}
+
+// appendCABundle adds the certificates of the PEM encoded caBundle to the root CAs of config.
+// The system root CAs are not used anymore once a CA bundle has been added.
+func appendCABundle(config *tls.Config, caBundle []byte) error {
+	if len(caBundle) == 0 {
+		return nil
+	}
+	if config.RootCAs == nil {
+		config.RootCAs = x509.NewCertPool()
+	}
+	if !config.RootCAs.AppendCertsFromPEM(caBundle) {
+		return errors.New("failed to parse CA bundle: no valid PEM encoded certificates found")
+	}
+	return nil
+}
+
+// configureTransport configures the transport of the SOAP client, which is shared with
+// the REST client, to connect through the proxy. It must be called after configureRootCAs,
+// as the root CAs are used to verify the vCenter certificate when connecting through the proxy.
+func configureTransport(soapClient *soap.Client, thumbprint string, proxy *Proxy) error {
+	if proxy == nil || proxy.URL == "" {
+		return nil
+	}
+	transport := soapClient.DefaultTransport()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse proxy URL %q", proxy.URL)
+	}
+	if proxyURL.Scheme != "http" && proxyURL.Scheme != "socks5" {
+		return errors.Errorf("unsupported proxy URL scheme %q: must be http or socks5", proxyURL.Scheme)
+	}
+
+	proxyFunc := (&httpproxy.Config{
+		HTTPProxy:  proxy.URL,
+		HTTPSProxy: proxy.URL,
+		NoProxy:    strings.Join(proxy.NoProxy, ","),
+	}).ProxyFunc()
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+
+	// When connecting through the proxy, the TLS connection to vCenter is established by the
+	// transport instead of the TLS dialer of the SOAP client, which is the one verifying
+	// the thumbprint. So the thumbprint has to be verified by the TLS config instead.
+	if thumbprint != "" && !transport.TLSClientConfig.InsecureSkipVerify {
+		transport.TLSClientConfig.InsecureSkipVerify = true
+		transport.TLSClientConfig.VerifyConnection = verifyConnection(thumbprint, transport.TLSClientConfig.RootCAs)
+	}
+	return nil
+}
+
+// verifyConnection returns a function which accepts the certificate of vCenter if it is either
+// trusted by rootCAs (or the system roots if nil) or matches the thumbprint.
+func verifyConnection(thumbprint string, rootCAs *x509.CertPool) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("no certificate presented by vCenter")
+		}
+		leaf := cs.PeerCertificates[0]
+
+		intermediates := x509.NewCertPool()
+		for _, cert := range cs.PeerCertificates[1:] {
+			intermediates.AddCert(cert)
+		}
+		if _, err := leaf.Verify(x509.VerifyOptions{
+			DNSName:       cs.ServerName,
+			Roots:         rootCAs,
+			Intermediates: intermediates,
+		}); err == nil {
+			return nil
+		}
+
+		if strings.EqualFold(thumbprint, soap.ThumbprintSHA1(leaf)) || strings.EqualFold(thumbprint, soap.ThumbprintSHA256(leaf)) {
+			return nil
+		}
+		return errors.Errorf("certificate of vCenter is not trusted and does not match thumbprint %s", thumbprint)
+	}
+}
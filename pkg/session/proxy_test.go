@@ -0,0 +1,191 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/vim25/soap"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func TestProxyForCluster(t *testing.T) {
+	g := NewWithT(t)
+
+	defaultProxy := &Proxy{URL: "http://default-proxy:3128"}
+	vsphereCluster := &infrav1.VSphereCluster{}
+	g.Expect(ProxyForCluster(nil, defaultProxy)).To(BeIdenticalTo(defaultProxy))
+	g.Expect(ProxyForCluster(vsphereCluster, defaultProxy)).To(BeIdenticalTo(defaultProxy))
+
+	vsphereCluster.Spec.Proxy = &infrav1.VCenterProxySpec{URL: "socks5://cluster-proxy:1080", NoProxy: []string{"10.0.0.0/8"}}
+	g.Expect(ProxyForCluster(vsphereCluster, defaultProxy)).To(Equal(&Proxy{URL: "socks5://cluster-proxy:1080", NoProxy: []string{"10.0.0.0/8"}}))
+
+	g.Expect((*Proxy)(nil).key()).To(BeEmpty())
+	g.Expect(defaultProxy.key()).ToNot(Equal((&Proxy{URL: "http://other-proxy:3128"}).key()))
+}
+
+func TestConfigureTransport(t *testing.T) {
+	serverURL, err := url.Parse("https://vcenter.example.com/sdk")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("proxy honors NoProxy", func(t *testing.T) {
+		g := NewWithT(t)
+		soapClient := soap.NewClient(serverURL, false)
+		g.Expect(configureTransport(soapClient, "", &Proxy{URL: "http://proxy:3128", NoProxy: []string{".internal"}})).To(Succeed())
+
+		transport := soapClient.DefaultTransport()
+		proxyURL, err := transport.Proxy(&http.Request{URL: serverURL})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(proxyURL.String()).To(Equal("http://proxy:3128"))
+
+		proxyURL, err = transport.Proxy(&http.Request{URL: &url.URL{Scheme: "https", Host: "vcenter.internal"}})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(proxyURL).To(BeNil())
+		g.Expect(transport.TLSClientConfig.VerifyConnection).To(BeNil())
+	})
+
+	t.Run("thumbprint is verified by the TLS config", func(t *testing.T) {
+		g := NewWithT(t)
+		soapClient := soap.NewClient(serverURL, false)
+		g.Expect(configureTransport(soapClient, "AA:BB", &Proxy{URL: "socks5://proxy:1080"})).To(Succeed())
+
+		transport := soapClient.DefaultTransport()
+		g.Expect(transport.TLSClientConfig.InsecureSkipVerify).To(BeTrue())
+		g.Expect(transport.TLSClientConfig.VerifyConnection).ToNot(BeNil())
+	})
+
+	t.Run("unsupported proxy scheme", func(t *testing.T) {
+		g := NewWithT(t)
+		soapClient := soap.NewClient(serverURL, false)
+		g.Expect(configureTransport(soapClient, "", &Proxy{URL: "https://proxy:3128"})).To(MatchError(ContainSubstring("unsupported proxy URL scheme")))
+	})
+}
+
+func TestConfigureRootCAs(t *testing.T) {
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer tlsServer.Close()
+	proxyServer := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer proxyServer.Close()
+	tlsCABundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tlsServer.Certificate().Raw})
+	proxyCABundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: proxyServer.Certificate().Raw})
+
+	serverURL, err := url.Parse(tlsServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("certificates of both CA bundles are trusted", func(t *testing.T) {
+		g := NewWithT(t)
+		soapClient := soap.NewClient(serverURL, false)
+		g.Expect(configureRootCAs(soapClient, &Proxy{CABundle: proxyCABundle}, &TLSConfig{CABundle: tlsCABundle})).To(Succeed())
+
+		for _, server := range []*httptest.Server{tlsServer, proxyServer} {
+			resp, err := soapClient.Client.Get(server.URL)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(resp.Body.Close()).To(Succeed())
+		}
+	})
+
+	t.Run("certificate not trusted without CA bundle", func(t *testing.T) {
+		g := NewWithT(t)
+		soapClient := soap.NewClient(serverURL, false)
+		g.Expect(configureRootCAs(soapClient, nil, &TLSConfig{})).To(Succeed())
+
+		_, err := soapClient.Client.Get(tlsServer.URL)
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("invalid CA bundle of the TLS configuration", func(t *testing.T) {
+		g := NewWithT(t)
+		soapClient := soap.NewClient(serverURL, false)
+		g.Expect(configureRootCAs(soapClient, nil, &TLSConfig{CABundle: []byte("not a certificate")})).To(MatchError(ContainSubstring("invalid CA bundle of the TLS configuration")))
+	})
+
+	t.Run("invalid CA bundle of the proxy", func(t *testing.T) {
+		g := NewWithT(t)
+		soapClient := soap.NewClient(serverURL, false)
+		g.Expect(configureRootCAs(soapClient, &Proxy{CABundle: []byte("not a certificate")}, nil)).To(MatchError(ContainSubstring("invalid CA bundle of the proxy")))
+	})
+}
+
+func TestVerifyConnection(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+	cert := server.Certificate()
+	state := tls.ConnectionState{ServerName: "example.com", PeerCertificates: []*x509.Certificate{cert}}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+
+	tests := []struct {
+		name       string
+		thumbprint string
+		rootCAs    *x509.CertPool
+		state      tls.ConnectionState
+		wantErr    bool
+	}{
+		{
+			name:       "SHA1 thumbprint matches",
+			thumbprint: soap.ThumbprintSHA1(cert),
+			state:      state,
+		},
+		{
+			name:       "SHA256 thumbprint matches",
+			thumbprint: soap.ThumbprintSHA256(cert),
+			state:      state,
+		},
+		{
+			name:       "certificate trusted by the CA bundle",
+			thumbprint: "AA:BB",
+			rootCAs:    roots,
+			state:      state,
+		},
+		{
+			name:       "thumbprint does not match",
+			thumbprint: "AA:BB",
+			state:      state,
+			wantErr:    true,
+		},
+		{
+			name:       "no certificate",
+			thumbprint: soap.ThumbprintSHA1(cert),
+			state:      tls.ConnectionState{ServerName: "example.com"},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := verifyConnection(tt.thumbprint, tt.rootCAs)(tt.state)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/vim25/soap"
+	"k8s.io/client-go/util/flowcontrol"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var (
+	// rateLimiterOptions are the options used for the rate limiters of all vCenters.
+	rateLimiterOptions RateLimiterOptions
+
+	// global rate limiter map against vCenter servers in map[server]*rateLimiter,
+	// shared by all sessions connected to the same vCenter.
+	rateLimiters sync.Map
+)
+
+// ErrCircuitOpen is returned for vCenter API calls which are rejected because the
+// circuit breaker for the vCenter is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open: vCenter is throttling API calls")
+
+// RateLimiterOptions configures the client-side rate limiting and circuit breaking of
+// the calls to the vCenter API.
+type RateLimiterOptions struct {
+	// QPS is the maximum number of API calls per second to a vCenter.
+	// Rate limiting is disabled if QPS is 0.
+	QPS float32
+
+	// Burst is the maximum number of API calls to a vCenter which are allowed
+	// to exceed QPS.
+	Burst int
+
+	// CircuitBreakerThreshold is the number of consecutive API calls failing because
+	// vCenter is busy or out of sessions after which the circuit breaker opens.
+	// Circuit breaking is disabled if CircuitBreakerThreshold is 0.
+	CircuitBreakerThreshold int
+
+	// CircuitBreakerCooldown is the time the circuit breaker stays open before
+	// a single API call is allowed to probe whether vCenter recovered.
+	CircuitBreakerCooldown time.Duration
+}
+
+// SetRateLimiterOptions sets the options of the rate limiters used for sessions
+// created afterwards. It is meant to be called once on startup.
+func SetRateLimiterOptions(opts RateLimiterOptions) {
+	rateLimiterOptions = opts
+	rateLimiters.Range(func(key, _ any) bool {
+		rateLimiters.Delete(key)
+		return true
+	})
+}
+
+// getRateLimiter returns the rate limiter for the given vCenter server, or nil if
+// neither rate limiting nor circuit breaking are enabled.
+func getRateLimiter(server string) *rateLimiter {
+	opts := rateLimiterOptions
+	if opts.QPS <= 0 && opts.CircuitBreakerThreshold <= 0 {
+		return nil
+	}
+	limiter, _ := rateLimiters.LoadOrStore(server, newRateLimiter(opts))
+	return limiter.(*rateLimiter)
+}
+
+// rateLimiter limits the rate of the API calls to a vCenter and stops calling
+// the API for a while if vCenter repeatedly reports it is overloaded.
+type rateLimiter struct {
+	limiter flowcontrol.RateLimiter
+	opts    RateLimiterOptions
+	now     func() time.Time
+
+	mu sync.Mutex
+	// failures is the number of consecutive throttled API calls.
+	failures int
+	// openUntil is the time until which the circuit breaker is open.
+	openUntil time.Time
+	// probing is true while a single API call probes a half-open circuit breaker.
+	probing bool
+}
+
+func newRateLimiter(opts RateLimiterOptions) *rateLimiter {
+	l := &rateLimiter{
+		opts: opts,
+		now:  time.Now,
+	}
+	if opts.QPS > 0 {
+		burst := opts.Burst
+		if burst < 1 {
+			burst = 1
+		}
+		l.limiter = flowcontrol.NewTokenBucketRateLimiter(opts.QPS, burst)
+	}
+	return l
+}
+
+// allow returns an error if the API call has to be rejected by the circuit breaker.
+func (l *rateLimiter) allow() error {
+	if l.opts.CircuitBreakerThreshold <= 0 {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.failures < l.opts.CircuitBreakerThreshold {
+		return nil
+	}
+	if l.now().Before(l.openUntil) || l.probing {
+		return ErrCircuitOpen
+	}
+	// The cooldown passed, let a single API call probe whether vCenter recovered.
+	l.probing = true
+	return nil
+}
+
+// done records the result of an API call in the circuit breaker.
+func (l *rateLimiter) done(ctx context.Context, err error) {
+	if l.opts.CircuitBreakerThreshold <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.probing = false
+	if !isThrottled(err) {
+		l.failures = 0
+		return
+	}
+	l.failures++
+	if l.failures >= l.opts.CircuitBreakerThreshold {
+		l.openUntil = l.now().Add(l.opts.CircuitBreakerCooldown)
+		ctrl.LoggerFrom(ctx).Info("vCenter is throttling API calls, opening circuit breaker",
+			"consecutiveFailures", l.failures, "cooldown", l.opts.CircuitBreakerCooldown, "err", err.Error())
+	}
+}
+
+// abort records an API call which was never made, so another API call can probe
+// a half-open circuit breaker.
+func (l *rateLimiter) abort() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.probing = false
+}
+
+// roundTripper returns a soap.RoundTripper which rate limits and circuit breaks
+// the API calls of the given RoundTripper.
+func (l *rateLimiter) roundTripper(rt soap.RoundTripper) soap.RoundTripper {
+	return &rateLimitedRoundTripper{RoundTripper: rt, limiter: l}
+}
+
+type rateLimitedRoundTripper struct {
+	soap.RoundTripper
+	limiter *rateLimiter
+}
+
+// RoundTrip implements soap.RoundTripper.
+func (rt *rateLimitedRoundTripper) RoundTrip(ctx context.Context, req, res soap.HasFault) error {
+	if err := rt.limiter.allow(); err != nil {
+		return err
+	}
+	if rt.limiter.limiter != nil {
+		if err := rt.limiter.limiter.Wait(ctx); err != nil {
+			rt.limiter.abort()
+			return errors.Wrap(err, "failed to wait for vCenter API rate limiter")
+		}
+	}
+	err := rt.RoundTripper.RoundTrip(ctx, req, res)
+	rt.limiter.done(ctx, err)
+	return err
+}
+
+// throttledMessages are the messages of the faults returned by vCenter when it is
+// busy or ran out of sessions.
+var throttledMessages = []string{
+	"server is busy",
+	"session limit",
+	"maximum number of sessions",
+	"too many sessions",
+}
+
+// isThrottled returns true if the error of an API call indicates that vCenter is
+// overloaded.
+func isThrottled(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := strings.ToLower(err.Error())
+	if soap.IsSoapFault(err) {
+		message = strings.ToLower(soap.ToSoapFault(err).String)
+	}
+	for _, status := range []int{http.StatusServiceUnavailable, http.StatusTooManyRequests} {
+		if strings.Contains(message, strings.ToLower(http.StatusText(status))) {
+			return true
+		}
+	}
+	for _, m := range throttledMessages {
+		if strings.Contains(message, m) {
+			return true
+		}
+	}
+	return false
+}
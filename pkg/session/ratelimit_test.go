@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+type fakeRoundTripper struct {
+	calls int
+	err   error
+}
+
+func (rt *fakeRoundTripper) RoundTrip(context.Context, soap.HasFault, soap.HasFault) error {
+	rt.calls++
+	return rt.err
+}
+
+func TestRateLimiter_CircuitBreaker(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	limiter := newRateLimiter(RateLimiterOptions{CircuitBreakerThreshold: 2, CircuitBreakerCooldown: time.Minute})
+	limiter.now = func() time.Time { return now }
+
+	fake := &fakeRoundTripper{err: errors.New("503 Service Unavailable")}
+	rt := limiter.roundTripper(fake)
+
+	// The circuit breaker opens after the threshold of consecutive throttled calls.
+	g.Expect(rt.RoundTrip(ctx, nil, nil)).To(MatchError(fake.err))
+	g.Expect(rt.RoundTrip(ctx, nil, nil)).To(MatchError(fake.err))
+	g.Expect(rt.RoundTrip(ctx, nil, nil)).To(MatchError(ErrCircuitOpen))
+	g.Expect(fake.calls).To(Equal(2))
+
+	// A single call probes vCenter after the cooldown and reopens the circuit breaker if it fails.
+	now = now.Add(time.Minute)
+	g.Expect(rt.RoundTrip(ctx, nil, nil)).To(MatchError(fake.err))
+	g.Expect(rt.RoundTrip(ctx, nil, nil)).To(MatchError(ErrCircuitOpen))
+	g.Expect(fake.calls).To(Equal(3))
+
+	// A successful probe closes the circuit breaker.
+	now = now.Add(time.Minute)
+	fake.err = nil
+	g.Expect(rt.RoundTrip(ctx, nil, nil)).To(Succeed())
+	g.Expect(rt.RoundTrip(ctx, nil, nil)).To(Succeed())
+	g.Expect(fake.calls).To(Equal(5))
+
+	// Other errors don't open the circuit breaker.
+	fake.err = errors.New("ServerFaultCode: ManagedObjectNotFound")
+	for i := 0; i < 3; i++ {
+		g.Expect(rt.RoundTrip(ctx, nil, nil)).To(MatchError(fake.err))
+	}
+	g.Expect(fake.calls).To(Equal(8))
+}
+
+func TestRateLimiter_RateLimit(t *testing.T) {
+	g := NewWithT(t)
+
+	limiter := newRateLimiter(RateLimiterOptions{QPS: 1, Burst: 1})
+	fake := &fakeRoundTripper{}
+	rt := limiter.roundTripper(fake)
+
+	g.Expect(rt.RoundTrip(context.Background(), nil, nil)).To(Succeed())
+
+	// The next call has to wait for the rate limiter, which is canceled.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	g.Expect(rt.RoundTrip(ctx, nil, nil)).ToNot(Succeed())
+	g.Expect(fake.calls).To(Equal(1))
+}
+
+func TestIsThrottled(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "no error",
+			err:  nil,
+			want: false,
+		},
+		{
+			name: "service unavailable",
+			err:  errors.New("503 Service Unavailable"),
+			want: true,
+		},
+		{
+			name: "server busy fault",
+			err:  soap.WrapSoapFault(&soap.Fault{String: "The server is busy, please try again later"}),
+			want: true,
+		},
+		{
+			name: "session limit fault",
+			err:  soap.WrapSoapFault(&soap.Fault{String: "The session limit has been exceeded"}),
+			want: true,
+		},
+		{
+			name: "other fault",
+			err:  soap.WrapSoapFault(&soap.Fault{String: "The object has already been deleted"}),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(isThrottled(tt.err)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestGetRateLimiter(t *testing.T) {
+	g := NewWithT(t)
+	defer SetRateLimiterOptions(RateLimiterOptions{})
+
+	SetRateLimiterOptions(RateLimiterOptions{})
+	g.Expect(getRateLimiter("vcenter")).To(BeNil())
+
+	SetRateLimiterOptions(RateLimiterOptions{QPS: 10, Burst: 10})
+	limiter := getRateLimiter("vcenter")
+	g.Expect(limiter).ToNot(BeNil())
+	g.Expect(getRateLimiter("vcenter")).To(BeIdenticalTo(limiter))
+	g.Expect(getRateLimiter("other-vcenter")).ToNot(BeIdenticalTo(limiter))
+}
@@ -24,6 +24,7 @@ import (
 	"net/netip"
 	"net/url"
 	"sync"
+	"time"
 
 	"github.com/blang/semver"
 	"github.com/pkg/errors"
@@ -31,10 +32,12 @@ import (
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/sts"
 	"github.com/vmware/govmomi/vapi/rest"
 	"github.com/vmware/govmomi/vapi/tags"
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
@@ -55,6 +58,16 @@ type Session struct {
 	Finder     *find.Finder
 	datacenter *object.Datacenter
 	TagManager *tags.Manager
+	// allowedTargets restricts the inventory the session can operate on, if set.
+	allowedTargets *AllowedTargets
+	// tokenExpiry is the expiry of the SAML token the session was created with, if any.
+	tokenExpiry time.Time
+
+	// batchMU protects the property cache and the power on batcher,
+	// which are created when they are first used.
+	batchMU        sync.Mutex
+	propertyCache  *PropertyCache
+	powerOnBatcher *powerOnBatcher
 }
 
 // Feature is a set of Features of the session.
@@ -67,11 +80,15 @@ func DefaultFeature() Feature {
 
 // Params are the parameters of a VCenter session.
 type Params struct {
-	server     string
-	datacenter string
-	userinfo   *url.Userinfo
-	thumbprint string
-	feature    Feature
+	server      string
+	datacenter  string
+	userinfo    *url.Userinfo
+	tokenSource TokenSource
+	thumbprint  string
+	proxy       *Proxy
+	tlsConfig   *TLSConfig
+	targets     *AllowedTargets
+	feature     Feature
 }
 
 // NewParams returns an empty set of parameters with default features.
@@ -99,12 +116,43 @@ func (p *Params) WithUserInfo(username, password string) *Params {
 	return p
 }
 
+// WithTokenSource adds a token source to parameters.
+// If tokenSource is not nil, tokens of the token source are exchanged for SAML
+// tokens to log in instead of using the userinfo.
+func (p *Params) WithTokenSource(tokenSource TokenSource) *Params {
+	p.tokenSource = tokenSource
+	return p
+}
+
 // WithThumbprint adds a thumbprint to parameters.
 func (p *Params) WithThumbprint(thumbprint string) *Params {
 	p.thumbprint = thumbprint
 	return p
 }
 
+// WithProxy adds a proxy to parameters.
+// If proxy is nil, vCenter is connected to directly or through the proxy
+// configured in the environment, e.g. via HTTPS_PROXY.
+func (p *Params) WithProxy(proxy *Proxy) *Params {
+	p.proxy = proxy
+	return p
+}
+
+// WithTLSConfig adds a TLS configuration to parameters.
+// If tlsConfig is nil, the certificate of vCenter is only verified
+// if a thumbprint is set.
+func (p *Params) WithTLSConfig(tlsConfig *TLSConfig) *Params {
+	p.tlsConfig = tlsConfig
+	return p
+}
+
+// WithAllowedTargets adds the allowed targets to parameters.
+// If targets is nil, the session can operate on the whole inventory.
+func (p *Params) WithAllowedTargets(targets *AllowedTargets) *Params {
+	p.targets = targets
+	return p
+}
+
 // WithFeatures adds features to parameters.
 func (p *Params) WithFeatures(feature Feature) *Params {
 	p.feature = feature
@@ -123,12 +171,27 @@ func GetOrCreate(ctx context.Context, params *Params) (*Session, error) {
 	sessionMU.Lock()
 	defer sessionMU.Unlock()
 
-	userPassword, _ := params.userinfo.Password()
-	h := sha256.New()
-	h.Write([]byte(userPassword))
-	hashedUserPassword := h.Sum(nil)
-	sessionKey := fmt.Sprintf("%s#%s#%s#%x", params.server, params.datacenter, params.userinfo.Username(),
-		hashedUserPassword)
+	var sessionKey string
+	if params.tokenSource != nil {
+		// Sessions are cached per token source, as each token is only used to log in once.
+		sessionKey = fmt.Sprintf("%s#%s#token-%s", params.server, params.datacenter, params.tokenSource.Key())
+	} else {
+		userPassword, _ := params.userinfo.Password()
+		h := sha256.New()
+		h.Write([]byte(userPassword))
+		hashedUserPassword := h.Sum(nil)
+		sessionKey = fmt.Sprintf("%s#%s#%s#%x", params.server, params.datacenter, params.userinfo.Username(),
+			hashedUserPassword)
+	}
+	if proxyKey := params.proxy.key(); proxyKey != "" {
+		sessionKey = fmt.Sprintf("%s#%s", sessionKey, proxyKey)
+	}
+	if tlsKey := params.tlsConfig.key(); tlsKey != "" {
+		sessionKey = fmt.Sprintf("%s#tls-%s", sessionKey, tlsKey)
+	}
+	if targetsKey := params.targets.key(); targetsKey != "" {
+		sessionKey = fmt.Sprintf("%s#targets-%s", sessionKey, targetsKey)
+	}
 	if cachedSession, ok := sessionCache.Load(sessionKey); ok {
 		s := cachedSession.(*Session)
 
@@ -144,10 +207,16 @@ func GetOrCreate(ctx context.Context, params *Params) (*Session, error) {
 			log.Error(err, "Failed to check if REST session is active")
 		}
 
-		if userSession != nil && tagManagerSession != nil {
+		tokenExpired := s.tokenExpired()
+		if userSession != nil && tagManagerSession != nil && !tokenExpired {
 			log.Info("Found active cached vSphere client session")
 			return s, nil
 		}
+		if tokenExpired {
+			log.Info("SAML token of the session expires, exchanging a new token")
+		}
+
+		s.stopPropertyCache()
 
 		log.Info("Logout the REST session because it is inactive")
 		if err := s.TagManager.Logout(ctx); err != nil {
@@ -164,6 +233,10 @@ func GetOrCreate(ctx context.Context, params *Params) (*Session, error) {
 		}
 	}
 
+	if params.targets != nil && len(params.targets.Datacenters) > 0 && params.datacenter == "" {
+		return nil, errors.New("failed to create vCenter session: a datacenter is required by the allowed targets of the identity")
+	}
+
 	// soap.ParseURL expects a valid URL. In the case of a bare, unbracketed
 	// IPv6 address (e.g fd00::1) ParseURL will fail. Surround unbracketed IPv6
 	// addresses with brackets.
@@ -182,18 +255,21 @@ func GetOrCreate(ctx context.Context, params *Params) (*Session, error) {
 	}
 
 	soapURL.User = params.userinfo
-	client, err := newClient(ctx, soapURL, params.thumbprint, params.feature)
+	client, signer, err := newClient(ctx, soapURL, params.thumbprint, params.proxy, params.tlsConfig, params.tokenSource, params.feature)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to create vCenter session")
 	}
 
-	session := Session{Client: client}
+	session := Session{Client: client, allowedTargets: params.targets}
+	if signer != nil {
+		session.tokenExpiry = signer.Lifetime.Expires
+	}
 	session.UserAgent = infrav1.GroupVersion.String()
 
 	// Assign the finder to the session.
 	session.Finder = find.NewFinder(session.Client.Client, false)
 	// Assign tag manager to the session.
-	manager, err := newManager(ctx, client.Client, soapURL.User, params.feature)
+	manager, err := newManager(ctx, client.Client, soapURL.User, signer, params.feature)
 	if err != nil {
 		log.Error(err, "Failed to create tags manager, will logout")
 		// Logout of previously logged session to not leak
@@ -218,6 +294,16 @@ func GetOrCreate(ctx context.Context, params *Params) (*Session, error) {
 			}
 			return nil, errors.Wrapf(err, "failed to create vCenter session: failed to find datacenter %q", params.datacenter)
 		}
+		if err := params.targets.checkDatacenter(dc); err != nil {
+			log.Error(err, "Datacenter is not allowed, will logout")
+			if errLogout := manager.Logout(ctx); errLogout != nil {
+				log.Error(errLogout, "Failed to logout of leading REST session")
+			}
+			if errLogout := client.Logout(ctx); errLogout != nil {
+				log.Error(errLogout, "Failed to logout of leading client session")
+			}
+			return nil, errors.Wrap(err, "failed to create vCenter session")
+		}
 		session.datacenter = dc
 		session.Finder.SetDatacenter(dc)
 	}
@@ -229,34 +315,67 @@ func GetOrCreate(ctx context.Context, params *Params) (*Session, error) {
 	return &session, nil
 }
 
-func newClient(ctx context.Context, url *url.URL, thumbprint string, _ Feature) (*govmomi.Client, error) {
-	insecure := thumbprint == ""
+// newClient creates a client logged in to vCenter. If tokenSource is not nil, a token of the token source is
+// exchanged for a SAML token to log in, and the signer of the SAML token is returned.
+func newClient(ctx context.Context, url *url.URL, thumbprint string, proxy *Proxy, tlsConfig *TLSConfig, tokenSource TokenSource, _ Feature) (*govmomi.Client, *sts.Signer, error) {
+	insecure := thumbprint == "" && (proxy == nil || len(proxy.CABundle) == 0)
+	if tlsConfig != nil {
+		insecure = tlsConfig.InsecureSkipVerify
+	}
 	soapClient := soap.NewClient(url, insecure)
-	if !insecure {
+	if thumbprint != "" {
 		soapClient.SetThumbprint(url.Host, thumbprint)
 	}
+	configureTLS(soapClient, tlsConfig)
+	if err := configureRootCAs(soapClient, proxy, tlsConfig); err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to create client: failed to configure root CAs")
+	}
+	if err := configureTransport(soapClient, thumbprint, proxy); err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to create client: failed to configure proxy")
+	}
 
 	vimClient, err := vim25.NewClient(ctx, soapClient)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to create client")
+		return nil, nil, errors.Wrapf(err, "failed to create client")
 	}
 	vimClient.UserAgent = "k8s-capv-useragent"
+	if limiter := getRateLimiter(url.Host); limiter != nil {
+		vimClient.RoundTripper = limiter.roundTripper(vimClient.RoundTripper)
+	}
 
 	c := &govmomi.Client{
 		Client:         vimClient,
 		SessionManager: session.NewManager(vimClient),
 	}
 
+	if tokenSource != nil {
+		signer, err := exchangeToken(ctx, vimClient, tokenSource)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to create client")
+		}
+		if err := c.SessionManager.LoginByToken(vimClient.WithHeader(ctx, soap.Header{Security: signer})); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to create client: failed to login by token")
+		}
+		return c, signer, nil
+	}
+
 	if err := c.Login(ctx, url.User); err != nil {
-		return nil, errors.Wrapf(err, "failed to create client: failed to login")
+		return nil, nil, errors.Wrapf(err, "failed to create client: failed to login")
 	}
 
-	return c, nil
+	return c, nil, nil
 }
 
 // newManager creates a Manager that encompasses the REST Client for the VSphere tagging API.
-func newManager(ctx context.Context, client *vim25.Client, user *url.Userinfo, _ Feature) (*tags.Manager, error) {
+// If signer is not nil, the REST client logs in with its SAML token instead of the userinfo.
+func newManager(ctx context.Context, client *vim25.Client, user *url.Userinfo, signer *sts.Signer, _ Feature) (*tags.Manager, error) {
 	rc := rest.NewClient(client)
+	if signer != nil {
+		if err := rc.LoginByToken(rc.WithSigner(ctx, signer)); err != nil {
+			return nil, errors.Wrapf(err, "failed to create tags manager: failed to login REST client by token")
+		}
+		return tags.NewManager(rc), nil
+	}
 	if err := rc.Login(ctx, user); err != nil {
 		return nil, errors.Wrapf(err, "failed to create tags manager: failed to login REST client")
 	}
@@ -281,11 +400,58 @@ func (s *Session) GetVersion() (infrav1.VCenterVersion, error) {
 func Clear() {
 	sessionCache.Range(func(_, s any) bool {
 		cachedSession := s.(*Session)
+		cachedSession.stopPropertyCache()
 		_ = cachedSession.Logout(context.Background())
 		return true
 	})
 }
 
+// PropertyCache returns the property cache of the session. The property cache is created
+// when it is first used, and recreated if it stopped, e.g. because waiting for updates failed.
+func (s *Session) PropertyCache(ctx context.Context) (*PropertyCache, error) {
+	s.batchMU.Lock()
+	defer s.batchMU.Unlock()
+
+	if s.propertyCache != nil && !s.propertyCache.isStopped() {
+		return s.propertyCache, nil
+	}
+	c, err := newPropertyCache(ctx, s.Client.Client)
+	if err != nil {
+		return nil, err
+	}
+	s.propertyCache = c
+	return c, nil
+}
+
+func (s *Session) stopPropertyCache() {
+	s.batchMU.Lock()
+	defer s.batchMU.Unlock()
+
+	if s.propertyCache != nil {
+		s.propertyCache.Stop()
+		s.propertyCache = nil
+	}
+}
+
+// PowerOnVM powers on a VM. The VMs powered on within a short time window are powered on
+// using a single PowerOnMultiVM_Task of the datacenter of the session, and the task powering
+// on the VM is returned. VMs of sessions without a datacenter or of standalone ESXi hosts are
+// powered on individually.
+func (s *Session) PowerOnVM(ctx context.Context, ref types.ManagedObjectReference) (*object.Task, error) {
+	if s.datacenter == nil || !s.Client.IsVC() {
+		return object.NewVirtualMachine(s.Client.Client, ref).PowerOn(ctx)
+	}
+
+	s.batchMU.Lock()
+	if s.powerOnBatcher == nil {
+		s.powerOnBatcher = &powerOnBatcher{datacenter: s.datacenter}
+	}
+	b := s.powerOnBatcher
+	s.batchMU.Unlock()
+
+	return b.powerOn(ctx, ref)
+}
+
 // FindByBIOSUUID finds an object by its BIOS UUID.
 //
 // To avoid comments about this function's name, please see the Golang
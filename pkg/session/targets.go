@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/object"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+// AllowedTargets restricts the vCenter inventory a session can operate on.
+type AllowedTargets struct {
+	// Datacenters are the names or inventory paths of the datacenters the session can be
+	// created for. All datacenters are allowed if empty.
+	Datacenters []string
+
+	// ResourcePools are the inventory paths of the resource pools, including their descendants,
+	// VMs can be created in. All resource pools are allowed if empty.
+	ResourcePools []string
+}
+
+// NewAllowedTargets returns the AllowedTargets for the given spec or nil if spec is nil.
+func NewAllowedTargets(spec *infrav1.AllowedTargets) *AllowedTargets {
+	if spec == nil {
+		return nil
+	}
+	return &AllowedTargets{
+		Datacenters:   spec.Datacenters,
+		ResourcePools: spec.ResourcePools,
+	}
+}
+
+func (t *AllowedTargets) key() string {
+	if t == nil {
+		return ""
+	}
+	h := sha256.New()
+	h.Write([]byte(strings.Join(t.Datacenters, ",")))
+	h.Write([]byte("#"))
+	h.Write([]byte(strings.Join(t.ResourcePools, ",")))
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// checkDatacenter returns an error if the datacenter is not allowed.
+func (t *AllowedTargets) checkDatacenter(dc *object.Datacenter) error {
+	if t == nil || len(t.Datacenters) == 0 {
+		return nil
+	}
+	for _, allowed := range t.Datacenters {
+		if allowed == dc.Name() || path.Clean(allowed) == dc.InventoryPath {
+			return nil
+		}
+	}
+	return errors.Errorf("datacenter %q is not in the allowed targets of the identity", dc.InventoryPath)
+}
+
+// checkResourcePool returns an error if the resource pool is not one of the allowed
+// resource pools or one of their descendants.
+func (t *AllowedTargets) checkResourcePool(pool *object.ResourcePool) error {
+	if t == nil || len(t.ResourcePools) == 0 {
+		return nil
+	}
+	for _, allowed := range t.ResourcePools {
+		allowed = path.Clean(allowed)
+		if pool.InventoryPath == allowed || strings.HasPrefix(pool.InventoryPath, allowed+"/") {
+			return nil
+		}
+	}
+	return errors.Errorf("resource pool %q is not in the allowed targets of the identity", pool.InventoryPath)
+}
+
+// CheckResourcePool returns an error if the identity of the session is not allowed to create
+// VMs in the resource pool. The inventory path of the resource pool must be set.
+func (s *Session) CheckResourcePool(pool *object.ResourcePool) error {
+	return s.allowedTargets.checkResourcePool(pool)
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"context"
+	"crypto/tls"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25/types"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func TestNewAllowedTargets(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(NewAllowedTargets(nil)).To(BeNil())
+	g.Expect(NewAllowedTargets(&infrav1.AllowedTargets{
+		Datacenters:   []string{"dc0"},
+		ResourcePools: []string{"/dc0/host/cluster0/Resources/tenant-a"},
+	})).To(Equal(&AllowedTargets{
+		Datacenters:   []string{"dc0"},
+		ResourcePools: []string{"/dc0/host/cluster0/Resources/tenant-a"},
+	}))
+}
+
+func TestAllowedTargets_checkDatacenter(t *testing.T) {
+	dc := object.NewDatacenter(nil, types.ManagedObjectReference{Type: "Datacenter", Value: "datacenter-1"})
+	dc.InventoryPath = "/folder/dc0"
+
+	tests := []struct {
+		name    string
+		targets *AllowedTargets
+		wantErr bool
+	}{
+		{
+			name:    "no allowed targets",
+			targets: nil,
+		},
+		{
+			name:    "no allowed datacenters",
+			targets: &AllowedTargets{ResourcePools: []string{"/folder/dc0/host/cluster0/Resources"}},
+		},
+		{
+			name:    "datacenter allowed by name",
+			targets: &AllowedTargets{Datacenters: []string{"dc1", "dc0"}},
+		},
+		{
+			name:    "datacenter allowed by inventory path",
+			targets: &AllowedTargets{Datacenters: []string{"/folder/dc0/"}},
+		},
+		{
+			name:    "datacenter not allowed",
+			targets: &AllowedTargets{Datacenters: []string{"dc1", "/folder/dc1"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := tt.targets.checkDatacenter(dc)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAllowedTargets_checkResourcePool(t *testing.T) {
+	targets := &AllowedTargets{ResourcePools: []string{"/dc0/host/cluster0/Resources/tenant-a"}}
+
+	tests := []struct {
+		name          string
+		targets       *AllowedTargets
+		inventoryPath string
+		wantErr       bool
+	}{
+		{
+			name:          "no allowed targets",
+			targets:       nil,
+			inventoryPath: "/dc0/host/cluster0/Resources",
+		},
+		{
+			name:          "allowed resource pool",
+			targets:       targets,
+			inventoryPath: "/dc0/host/cluster0/Resources/tenant-a",
+		},
+		{
+			name:          "descendant of allowed resource pool",
+			targets:       targets,
+			inventoryPath: "/dc0/host/cluster0/Resources/tenant-a/md-0",
+		},
+		{
+			name:          "parent of allowed resource pool",
+			targets:       targets,
+			inventoryPath: "/dc0/host/cluster0/Resources",
+			wantErr:       true,
+		},
+		{
+			name:          "sibling with common prefix",
+			targets:       targets,
+			inventoryPath: "/dc0/host/cluster0/Resources/tenant-ab",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			pool := object.NewResourcePool(nil, types.ManagedObjectReference{Type: "ResourcePool", Value: "resgroup-1"})
+			pool.InventoryPath = tt.inventoryPath
+
+			err := (&Session{allowedTargets: tt.targets}).CheckResourcePool(pool)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestGetOrCreate_AllowedTargets(t *testing.T) {
+	model := simulator.VPX()
+	model.Host = 0
+	if err := model.Create(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(model.Remove)
+	model.Service.TLS = new(tls.Config)
+	model.Service.RegisterEndpoints = true
+	server := model.Service.NewServer()
+	t.Cleanup(server.Close)
+	pass, _ := server.URL.User.Password()
+
+	tests := []struct {
+		name       string
+		datacenter string
+		targets    *AllowedTargets
+		wantErr    bool
+	}{
+		{
+			name:       "allowed datacenter",
+			datacenter: "DC0",
+			targets:    &AllowedTargets{Datacenters: []string{"DC0"}},
+		},
+		{
+			name:       "datacenter not allowed",
+			datacenter: "DC0",
+			targets:    &AllowedTargets{Datacenters: []string{"DC1"}},
+			wantErr:    true,
+		},
+		{
+			name:    "missing datacenter",
+			targets: &AllowedTargets{Datacenters: []string{"DC0"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			s, err := GetOrCreate(context.Background(), NewParams().
+				WithServer(server.URL.Host).
+				WithUserInfo(server.URL.User.Username(), pass).
+				WithDatacenter(tt.datacenter).
+				WithAllowedTargets(tt.targets))
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(s.allowedTargets).To(Equal(tt.targets))
+		})
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/vim25/soap"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+// TLSConfig is the TLS configuration used for the connections to vCenter.
+type TLSConfig struct {
+	// CABundle is a PEM encoded bundle of CA certificates used to verify
+	// the certificate of vCenter, in addition to the CA bundle of the Proxy.
+	// If both are empty, the system root CAs are used.
+	CABundle []byte
+
+	// InsecureSkipVerify disables the verification of the certificate of vCenter.
+	InsecureSkipVerify bool
+
+	// MinVersion is the minimum TLS version, e.g. tls.VersionTLS12.
+	// If 0, the default of the crypto/tls package is used.
+	MinVersion uint16
+}
+
+// NewTLSConfig returns the TLSConfig for the given VCenterTLSConfig and the CA certificates
+// referenced by it, or nil if spec is nil.
+func NewTLSConfig(spec *infrav1.VCenterTLSConfig, caBundle []byte) (*TLSConfig, error) {
+	if spec == nil {
+		return nil, nil
+	}
+	tlsConfig := &TLSConfig{
+		CABundle:           caBundle,
+		InsecureSkipVerify: spec.InsecureSkipVerify,
+	}
+	switch spec.MinVersion {
+	case "":
+	case infrav1.TLSVersion12:
+		tlsConfig.MinVersion = tls.VersionTLS12
+	case infrav1.TLSVersion13:
+		tlsConfig.MinVersion = tls.VersionTLS13
+	default:
+		return nil, errors.Errorf("unsupported minimum TLS version %q", spec.MinVersion)
+	}
+	return tlsConfig, nil
+}
+
+// key returns a string identifying the TLS configuration in the session cache.
+func (t *TLSConfig) key() string {
+	if t == nil {
+		return ""
+	}
+	h := sha256.New()
+	h.Write(t.CABundle)
+	h.Write([]byte(fmt.Sprintf("%t#%d", t.InsecureSkipVerify, t.MinVersion)))
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// configureTLS configures the TLS config of the transport of the SOAP client, which is
+// shared with the REST client. The CA bundle is added by configureRootCAs.
+func configureTLS(soapClient *soap.Client, tlsConfig *TLSConfig) {
+	if tlsConfig == nil {
+		return
+	}
+	config := soapClient.DefaultTransport().TLSClientConfig
+	config.InsecureSkipVerify = tlsConfig.InsecureSkipVerify
+	config.MinVersion = tlsConfig.MinVersion
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"crypto/tls"
+	"net/url"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/vim25/soap"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func TestNewTLSConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    *infrav1.VCenterTLSConfig
+		want    *TLSConfig
+		wantErr bool
+	}{
+		{
+			name: "nil spec",
+			spec: nil,
+			want: nil,
+		},
+		{
+			name: "default minimum version",
+			spec: &infrav1.VCenterTLSConfig{InsecureSkipVerify: true},
+			want: &TLSConfig{CABundle: []byte("ca"), InsecureSkipVerify: true},
+		},
+		{
+			name: "TLS 1.3",
+			spec: &infrav1.VCenterTLSConfig{MinVersion: infrav1.TLSVersion13},
+			want: &TLSConfig{CABundle: []byte("ca"), MinVersion: tls.VersionTLS13},
+		},
+		{
+			name:    "unsupported minimum version",
+			spec:    &infrav1.VCenterTLSConfig{MinVersion: "1.0"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			got, err := NewTLSConfig(tt.spec, []byte("ca"))
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestConfigureTLS(t *testing.T) {
+	g := NewWithT(t)
+
+	serverURL, err := url.Parse("https://vcenter.example.com/sdk")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	soapClient := soap.NewClient(serverURL, false)
+	configureTLS(soapClient, &TLSConfig{InsecureSkipVerify: true, MinVersion: tls.VersionTLS12})
+	g.Expect(soapClient.DefaultTransport().TLSClientConfig.InsecureSkipVerify).To(BeTrue())
+	g.Expect(soapClient.DefaultTransport().TLSClientConfig.MinVersion).To(Equal(uint16(tls.VersionTLS12)))
+}
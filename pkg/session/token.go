@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/sts"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+)
+
+const (
+	// tokenExchangePath is the path of the token exchange API of vCenter.
+	tokenExchangePath = "/api/vcenter/tokenservice/token-exchange"
+
+	tokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+	idTokenType            = "urn:ietf:params:oauth:token-type:id_token"
+	saml2TokenType         = "urn:ietf:params:oauth:token-type:saml2"
+
+	// tokenExpiryMargin is the time before the SAML token of a session expires
+	// after which the session is re-authenticated.
+	tokenExpiryMargin = time.Minute
+)
+
+// TokenSource provides the tokens which are exchanged for SAML tokens to log in to vCenter.
+type TokenSource interface {
+	// Key identifies the tokens of the token source, e.g. by the ServiceAccount they are issued for.
+	// It is used instead of the credentials to cache sessions.
+	Key() string
+
+	// Token returns a new token to exchange.
+	Token(ctx context.Context) (string, error)
+}
+
+// tokenExchangeRequest is the request of the token exchange API of vCenter.
+type tokenExchangeRequest struct {
+	GrantType          string `json:"grant_type"`
+	SubjectToken       string `json:"subject_token"`
+	SubjectTokenType   string `json:"subject_token_type"`
+	RequestedTokenType string `json:"requested_token_type"`
+}
+
+// tokenExchangeResponse is the response of the token exchange API of vCenter.
+type tokenExchangeResponse struct {
+	AccessToken     string `json:"access_token"`
+	IssuedTokenType string `json:"issued_token_type"`
+	TokenType       string `json:"token_type"`
+	ExpiresIn       int64  `json:"expires_in,omitempty"`
+}
+
+// exchangeToken exchanges a token of the token source for a SAML bearer token, which is
+// returned as signer for the login requests.
+func exchangeToken(ctx context.Context, c *vim25.Client, tokenSource TokenSource) (*sts.Signer, error) {
+	token, err := tokenSource.Token(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get token to exchange")
+	}
+
+	rc := rest.NewClient(c)
+	req := rc.Resource(tokenExchangePath).Request(http.MethodPost, tokenExchangeRequest{
+		GrantType:          tokenExchangeGrantType,
+		SubjectToken:       token,
+		SubjectTokenType:   idTokenType,
+		RequestedTokenType: saml2TokenType,
+	})
+	var res tokenExchangeResponse
+	if err := rc.Do(ctx, req, &res); err != nil {
+		return nil, errors.Wrapf(err, "failed to exchange token")
+	}
+	if res.AccessToken == "" {
+		return nil, errors.New("failed to exchange token: response contains no SAML token")
+	}
+
+	// SAML tokens are base64 encoded, optionally using the URL alphabet without padding.
+	samlToken, err := base64.StdEncoding.DecodeString(res.AccessToken)
+	if err != nil {
+		if samlToken, err = base64.RawURLEncoding.DecodeString(res.AccessToken); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode exchanged SAML token")
+		}
+	}
+
+	signer := &sts.Signer{Token: string(samlToken)}
+	signer.Lifetime.Created = time.Now()
+	if res.ExpiresIn > 0 {
+		signer.Lifetime.Expires = signer.Lifetime.Created.Add(time.Duration(res.ExpiresIn) * time.Second)
+	}
+	return signer, nil
+}
+
+// tokenExpired returns true if the session was created with a SAML token which expires
+// within the tokenExpiryMargin.
+func (s *Session) tokenExpired() bool {
+	return !s.tokenExpiry.IsZero() && time.Now().Add(tokenExpiryMargin).After(s.tokenExpiry)
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/simulator"
+	_ "github.com/vmware/govmomi/vapi/simulator" // run init func to register the REST API endpoints.
+)
+
+const testSAMLToken = `<saml2:Assertion xmlns:saml2="urn:oasis:names:tc:SAML:2.0:assertion" ID="_capv"><saml2:Subject><saml2:NameID>capv@vsphere.local</saml2:NameID></saml2:Subject></saml2:Assertion>`
+
+type testTokenSource struct {
+	token  string
+	issued int
+}
+
+func (s *testTokenSource) Key() string {
+	return "test-" + s.token
+}
+
+func (s *testTokenSource) Token(_ context.Context) (string, error) {
+	s.issued++
+	return s.token, nil
+}
+
+func TestGetOrCreateWithTokenSource(t *testing.T) {
+	g := NewWithT(t)
+
+	model := simulator.VPX()
+	g.Expect(model.Create()).To(Succeed())
+	t.Cleanup(model.Remove)
+	model.Service.TLS = new(tls.Config)
+	model.Service.RegisterEndpoints = true
+	server := model.Service.NewServer()
+	t.Cleanup(server.Close)
+
+	// The token exchange API is unauthenticated, so the handler is not registered with the REST API simulator.
+	model.Service.Handle(tokenExchangePath, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req tokenExchangeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.GrantType != tokenExchangeGrantType || req.SubjectToken != "valid" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(tokenExchangeResponse{
+			AccessToken:     base64.StdEncoding.EncodeToString([]byte(testSAMLToken)),
+			IssuedTokenType: saml2TokenType,
+			TokenType:       "Bearer",
+			ExpiresIn:       600,
+		})
+	}))
+
+	ctx := context.Background()
+
+	t.Run("logs in with an exchanged token", func(*testing.T) {
+		tokenSource := &testTokenSource{token: "valid"}
+		params := NewParams().WithServer(server.URL.Host).WithDatacenter("*").WithTokenSource(tokenSource)
+
+		s, err := GetOrCreate(ctx, params)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(tokenSource.issued).To(Equal(1))
+		g.Expect(s.tokenExpiry).To(BeTemporally("~", time.Now().Add(600*time.Second), time.Minute))
+		userSession, err := s.SessionManager.UserSession(ctx)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(userSession.UserName).To(Equal("capv@vsphere.local"))
+
+		// The cached session is used while the SAML token is valid.
+		cached, err := GetOrCreate(ctx, params)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(cached).To(BeIdenticalTo(s))
+		g.Expect(tokenSource.issued).To(Equal(1))
+
+		// A new token is exchanged once the SAML token expires.
+		s.tokenExpiry = time.Now()
+		renewed, err := GetOrCreate(ctx, params)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(renewed).ToNot(BeIdenticalTo(s))
+		g.Expect(tokenSource.issued).To(Equal(2))
+	})
+
+	t.Run("fails if the token is rejected", func(*testing.T) {
+		tokenSource := &testTokenSource{token: "invalid"}
+		params := NewParams().WithServer(server.URL.Host).WithDatacenter("*").WithTokenSource(tokenSource)
+
+		_, err := GetOrCreate(ctx, params)
+		g.Expect(err).To(MatchError(ContainSubstring("failed to exchange token")))
+	})
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing provides the OpenTelemetry tracing of the reconcilers and of the
+// calls to vCenter.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// TracerName is the name of the tracer used for the spans of CAPV.
+	TracerName = "sigs.k8s.io/cluster-api-provider-vsphere"
+
+	// TraceParentAnnotation is set on objects created by a reconciler to the trace context
+	// of the reconcile, so the reconciles of the object can be linked to the trace which created it.
+	TraceParentAnnotation = "vsphere.infrastructure.cluster.x-k8s.io/traceparent"
+
+	// taskDescriptionKey is the key of the vCenter task descriptions containing the trace context.
+	taskDescriptionKey = "capv.traceparent"
+
+	// operationIDPrefix is the prefix of the operation IDs sent to vCenter within a span.
+	operationIDPrefix = "capv"
+)
+
+// propagator propagates the trace context using the W3C Trace Context format.
+var propagator = propagation.TraceContext{}
+
+// Options configures the tracing.
+type Options struct {
+	// Enabled enables exporting the spans.
+	Enabled bool
+
+	// Endpoint is the host:port of the OTLP gRPC endpoint the spans are exported to.
+	Endpoint string
+
+	// Insecure disables TLS for the connection to the OTLP endpoint.
+	Insecure bool
+
+	// SamplingRatio is the ratio of the traces which are sampled.
+	SamplingRatio float64
+}
+
+// Setup sets up the global tracer provider exporting spans via OTLP and returns
+// a func to flush and shut it down. If tracing is not enabled, spans are not recorded.
+func Setup(ctx context.Context, serviceName string, opts Options) (func(context.Context) error, error) {
+	if !opts.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporterOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(opts.Endpoint)}
+	if opts.Insecure {
+		exporterOpts = append(exporterOpts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, exporterOpts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create OTLP trace exporter for %s", opts.Endpoint)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create tracing resource")
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SamplingRatio))),
+	)
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetTextMapPropagator(propagator)
+	return tracerProvider.Shutdown, nil
+}
+
+// StartSpan starts a span with the given name. If an object is given, the span
+// is annotated with the object and linked to the trace which created the object.
+// The operation ID of the calls to vCenter within the span is set to the trace context,
+// so the calls can be correlated with the span in the vCenter logs.
+func StartSpan(ctx context.Context, name string, obj metav1.Object, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	opts := []trace.SpanStartOption{}
+	if obj != nil {
+		attrs = append(attrs,
+			attribute.String("k8s.namespace.name", obj.GetNamespace()),
+			attribute.String("k8s.object.name", obj.GetName()),
+		)
+		if link, ok := linkFromAnnotation(obj); ok {
+			opts = append(opts, trace.WithLinks(link))
+		}
+	}
+	opts = append(opts, trace.WithAttributes(attrs...))
+
+	ctx, span := otel.Tracer(TracerName).Start(ctx, name, opts...)
+	if sc := span.SpanContext(); sc.IsValid() {
+		ctx = context.WithValue(ctx, types.ID{}, fmt.Sprintf("%s-%s-%s", operationIDPrefix, sc.TraceID(), sc.SpanID()))
+	}
+	return ctx, span
+}
+
+// EndSpan records the error, if any, and ends the span.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// SetTraceParentAnnotation sets the TraceParentAnnotation of an object to the trace context
+// of the span in the context, if the span is sampled. It is meant to be called when creating
+// objects, as changing the annotation on every reconcile would trigger new reconciles.
+func SetTraceParentAnnotation(ctx context.Context, obj metav1.Object) {
+	if !trace.SpanContextFromContext(ctx).IsSampled() {
+		return
+	}
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	traceParent := carrier.Get("traceparent")
+	if traceParent == "" {
+		return
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[TraceParentAnnotation] = traceParent
+	obj.SetAnnotations(annotations)
+}
+
+// linkFromAnnotation returns a link to the trace context stored in the TraceParentAnnotation of an object.
+func linkFromAnnotation(obj metav1.Object) (trace.Link, bool) {
+	traceParent, ok := obj.GetAnnotations()[TraceParentAnnotation]
+	if !ok {
+		return trace.Link{}, false
+	}
+	sc := trace.SpanContextFromContext(propagator.Extract(context.Background(), propagation.MapCarrier{"traceparent": traceParent}))
+	if !sc.IsValid() {
+		return trace.Link{}, false
+	}
+	return trace.Link{SpanContext: sc}, true
+}
+
+// SetTaskDescription adds the trace context of the span in the context to the description
+// of a vCenter task, so the task can be correlated with the span which triggered it.
+// It is best-effort: failures are only recorded on the span.
+func SetTaskDescription(ctx context.Context, task *object.Task) {
+	span := trace.SpanFromContext(ctx)
+	if !span.SpanContext().IsSampled() {
+		return
+	}
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	if err := task.SetDescription(ctx, types.LocalizableMessage{
+		Key:     taskDescriptionKey,
+		Message: fmt.Sprintf("traceparent=%s", carrier.Get("traceparent")),
+	}); err != nil {
+		span.RecordError(errors.Wrapf(err, "failed to set description of task %s", task.Reference().Value))
+		return
+	}
+	span.SetAttributes(attribute.String("vsphere.task", task.Reference().Value))
+}
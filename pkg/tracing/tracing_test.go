@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/vim25/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func setupRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(tracerProvider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+	})
+	return recorder
+}
+
+func TestStartSpan(t *testing.T) {
+	g := NewWithT(t)
+	recorder := setupRecorder(t)
+
+	// Start the span of the reconcile creating the object.
+	ctx, parent := StartSpan(context.Background(), "VSphereMachine.Reconcile", nil)
+	g.Expect(ctx.Value(types.ID{})).To(Equal(fmt.Sprintf("capv-%s-%s", parent.SpanContext().TraceID(), parent.SpanContext().SpanID())))
+
+	vm := &infrav1.VSphereVM{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "vm",
+		},
+	}
+	SetTraceParentAnnotation(ctx, vm)
+	g.Expect(vm.Annotations).To(HaveKey(TraceParentAnnotation))
+	EndSpan(parent, nil)
+
+	// Start the span of a reconcile of the object.
+	_, span := StartSpan(context.Background(), "VSphereVM.Reconcile", vm)
+	EndSpan(span, errors.New("failed"))
+
+	spans := recorder.Ended()
+	g.Expect(spans).To(HaveLen(2))
+	g.Expect(spans[1].Name()).To(Equal("VSphereVM.Reconcile"))
+	g.Expect(spans[1].Parent().IsValid()).To(BeFalse())
+	g.Expect(spans[1].Links()).To(HaveLen(1))
+	g.Expect(spans[1].Links()[0].SpanContext.TraceID()).To(Equal(parent.SpanContext().TraceID()))
+	g.Expect(spans[1].Links()[0].SpanContext.SpanID()).To(Equal(parent.SpanContext().SpanID()))
+	g.Expect(spans[1].Status().Code).To(Equal(codes.Error))
+	g.Expect(spans[1].Attributes()).To(ContainElements(
+		HaveField("Value.AsString()", "ns"),
+		HaveField("Value.AsString()", "vm"),
+	))
+}
+
+func TestStartSpan_Disabled(t *testing.T) {
+	g := NewWithT(t)
+
+	// With a noop tracer provider the spans are not recorded.
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(noop.NewTracerProvider())
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+	})
+
+	ctx, span := StartSpan(context.Background(), "VSphereMachine.Reconcile", nil)
+	g.Expect(span.SpanContext().IsValid()).To(BeFalse())
+	g.Expect(ctx.Value(types.ID{})).To(BeNil())
+
+	vm := &infrav1.VSphereVM{}
+	SetTraceParentAnnotation(ctx, vm)
+	g.Expect(vm.Annotations).To(BeEmpty())
+	EndSpan(span, nil)
+}
+
+func TestSetTraceParentAnnotation_Invalid(t *testing.T) {
+	g := NewWithT(t)
+	recorder := setupRecorder(t)
+
+	vm := &infrav1.VSphereVM{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{TraceParentAnnotation: "invalid"},
+		},
+	}
+	_, span := StartSpan(context.Background(), "VSphereVM.Reconcile", vm)
+	EndSpan(span, nil)
+
+	spans := recorder.Ended()
+	g.Expect(spans).To(HaveLen(1))
+	g.Expect(spans[0].Links()).To(BeEmpty())
+}
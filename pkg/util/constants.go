@@ -18,98 +18,81 @@ limitations under the License.
 package util
 
 const metadataFormat = `
-instance-id: "{{ .Hostname }}"
-local-hostname: "{{ .Hostname }}"
-wait-on-network:
-  ipv4: {{ .WaitForIPv4 }}
-  ipv6: {{ .WaitForIPv6 }}
-network:
-  version: 2
-  ethernets:
-    {{- range $i, $net := .Devices }}
-    id{{ $i }}:
-      match:
-        macaddress: "{{ $net.MACAddr }}"
-      {{- if $net.DeviceName }}
-      set-name: "{{ $net.DeviceName }}"
-      {{- else }}
-      set-name: "eth{{ $i }}"
-      {{- end }}
-      wakeonlan: true
-      dhcp4: {{ $net.DHCP4 }}
-      dhcp6: {{ $net.DHCP6 }}
-      accept-ra: {{ $net.DHCP6 }}   
-	  {{- if $net.DHCP4Overrides }}
+{{- define "interface" }}
+      dhcp4: {{ .DHCP4 }}
+      dhcp6: {{ .DHCP6 }}
+      accept-ra: {{ .DHCP6 }}   
+      {{- if .DHCP4Overrides }}
       dhcp4-overrides:
-	    {{- if $net.DHCP4Overrides.Hostname }}
-        hostname: "{{ $net.DHCP4Overrides.Hostname }}"
-	    {{- end }}
-	    {{- if $net.DHCP4Overrides.RouteMetric }}
-        route-metric: {{ $net.DHCP4Overrides.RouteMetric }}
-	    {{- end }}
-	    {{- if $net.DHCP4Overrides.SendHostname }}
-        send-hostname: {{ $net.DHCP4Overrides.SendHostname }}
-	    {{- end }}
-	    {{- if $net.DHCP4Overrides.UseDNS }}
-        use-dns: {{ $net.DHCP4Overrides.UseDNS }}
-	    {{- end }}
-	    {{- if $net.DHCP4Overrides.UseDomains }}
-        use-domains: {{ $net.DHCP4Overrides.UseDomains }}
-	    {{- end }}
-	    {{- if $net.DHCP4Overrides.UseHostname }}
-        use-hostname: {{ $net.DHCP4Overrides.UseHostname }}
-	    {{- end }}
-	    {{- if $net.DHCP4Overrides.UseMTU }}
-        use-mtu: {{ $net.DHCP4Overrides.UseMTU }}
-	    {{- end }}
-	    {{- if $net.DHCP4Overrides.UseNTP }}
-        use-ntp: {{ $net.DHCP4Overrides.UseNTP }}
-	    {{- end }}
-	    {{- if $net.DHCP4Overrides.UseRoutes }}
-        use-routes: "{{ $net.DHCP4Overrides.UseRoutes }}"
-	    {{- end }}
-	  {{- end }}
-	  {{- if $net.DHCP6Overrides }}
+        {{- if .DHCP4Overrides.Hostname }}
+        hostname: "{{ .DHCP4Overrides.Hostname }}"
+        {{- end }}
+        {{- if .DHCP4Overrides.RouteMetric }}
+        route-metric: {{ .DHCP4Overrides.RouteMetric }}
+        {{- end }}
+        {{- if .DHCP4Overrides.SendHostname }}
+        send-hostname: {{ .DHCP4Overrides.SendHostname }}
+        {{- end }}
+        {{- if .DHCP4Overrides.UseDNS }}
+        use-dns: {{ .DHCP4Overrides.UseDNS }}
+        {{- end }}
+        {{- if .DHCP4Overrides.UseDomains }}
+        use-domains: {{ .DHCP4Overrides.UseDomains }}
+        {{- end }}
+        {{- if .DHCP4Overrides.UseHostname }}
+        use-hostname: {{ .DHCP4Overrides.UseHostname }}
+        {{- end }}
+        {{- if .DHCP4Overrides.UseMTU }}
+        use-mtu: {{ .DHCP4Overrides.UseMTU }}
+        {{- end }}
+        {{- if .DHCP4Overrides.UseNTP }}
+        use-ntp: {{ .DHCP4Overrides.UseNTP }}
+        {{- end }}
+        {{- if .DHCP4Overrides.UseRoutes }}
+        use-routes: "{{ .DHCP4Overrides.UseRoutes }}"
+        {{- end }}
+      {{- end }}
+      {{- if .DHCP6Overrides }}
       dhcp6-overrides:
-	    {{- if $net.DHCP6Overrides.Hostname }}
-        hostname: "{{ $net.DHCP6Overrides.Hostname }}"
-	    {{- end }}
-	    {{- if $net.DHCP6Overrides.RouteMetric }}
-        route-metric: {{ $net.DHCP6Overrides.RouteMetric }}
-	    {{- end }}
-	    {{- if $net.DHCP6Overrides.SendHostname }}
-        send-hostname: {{ $net.DHCP6Overrides.SendHostname }}
-	    {{- end }}
-	    {{- if $net.DHCP6Overrides.UseDNS }}
-        use-dns: {{ $net.DHCP6Overrides.UseDNS }}
-	    {{- end }}
-	    {{- if $net.DHCP6Overrides.UseDomains }}
-        use-domains: {{ $net.DHCP6Overrides.UseDomains }}
-	    {{- end }}
-	    {{- if $net.DHCP6Overrides.UseHostname }}
-        use-hostname: {{ $net.DHCP6Overrides.UseHostname }}
-	    {{- end }}
-	    {{- if $net.DHCP6Overrides.UseMTU }}
-        use-mtu: {{ $net.DHCP6Overrides.UseMTU }}
-	    {{- end }}
-	    {{- if $net.DHCP6Overrides.UseNTP }}
-        use-ntp: {{ $net.DHCP6Overrides.UseNTP }}
-	    {{- end }}
-	    {{- if $net.DHCP6Overrides.UseRoutes }}
-        use-routes: "{{ $net.DHCP6Overrides.UseRoutes }}"
-	    {{- end }}
-	  {{- end }}
-      {{- if $net.IPAddrs }}
+        {{- if .DHCP6Overrides.Hostname }}
+        hostname: "{{ .DHCP6Overrides.Hostname }}"
+        {{- end }}
+        {{- if .DHCP6Overrides.RouteMetric }}
+        route-metric: {{ .DHCP6Overrides.RouteMetric }}
+        {{- end }}
+        {{- if .DHCP6Overrides.SendHostname }}
+        send-hostname: {{ .DHCP6Overrides.SendHostname }}
+        {{- end }}
+        {{- if .DHCP6Overrides.UseDNS }}
+        use-dns: {{ .DHCP6Overrides.UseDNS }}
+        {{- end }}
+        {{- if .DHCP6Overrides.UseDomains }}
+        use-domains: {{ .DHCP6Overrides.UseDomains }}
+        {{- end }}
+        {{- if .DHCP6Overrides.UseHostname }}
+        use-hostname: {{ .DHCP6Overrides.UseHostname }}
+        {{- end }}
+        {{- if .DHCP6Overrides.UseMTU }}
+        use-mtu: {{ .DHCP6Overrides.UseMTU }}
+        {{- end }}
+        {{- if .DHCP6Overrides.UseNTP }}
+        use-ntp: {{ .DHCP6Overrides.UseNTP }}
+        {{- end }}
+        {{- if .DHCP6Overrides.UseRoutes }}
+        use-routes: "{{ .DHCP6Overrides.UseRoutes }}"
+        {{- end }}
+      {{- end }}
+      {{- if .IPAddrs }}
       addresses:
-      {{- range $net.IPAddrs }}
+      {{- range .IPAddrs }}
       - "{{ . }}"
       {{- end }}
       {{- end }}
-      {{- if $net.Gateway4 }}
-      gateway4: "{{ $net.Gateway4 }}"
+      {{- if .Gateway4 }}
+      gateway4: "{{ .Gateway4 }}"
       {{- end }}
-      {{- if $net.Gateway6 }}
-      gateway6: "{{ $net.Gateway6 }}"
+      {{- if .Gateway6 }}
+      gateway6: "{{ .Gateway6 }}"
       {{- end }}
       {{- if .MTU }}
       mtu: {{ .MTU }}
@@ -122,22 +105,79 @@ network:
         metric: {{ .Metric }}
       {{- end }}
       {{- end }}
-      {{- if nameservers $net }}
+      {{- if nameservers . }}
       nameservers:
-        {{- if $net.Nameservers }}
+        {{- if .Nameservers }}
         addresses:
-        {{- range $net.Nameservers }}
+        {{- range .Nameservers }}
         - "{{ . }}"
         {{- end }}
         {{- end }}
-        {{- if $net.SearchDomains }}
+        {{- if .SearchDomains }}
         search:
-        {{- range $net.SearchDomains }}
+        {{- range .SearchDomains }}
         - "{{ . }}"
         {{- end }}
         {{- end }}
       {{- end }}
+{{- end }}
+instance-id: "{{ .Hostname }}"
+local-hostname: "{{ .Hostname }}"
+wait-on-network:
+  ipv4: {{ .WaitForIPv4 }}
+  ipv6: {{ .WaitForIPv6 }}
+network:
+  version: 2
+  ethernets:
+    {{- range $i, $net := .Devices }}
+    id{{ $i }}:
+      match:
+        macaddress: "{{ $net.MACAddr }}"
+      {{- if $net.DeviceName }}
+      set-name: "{{ $net.DeviceName }}"
+      {{- else }}
+      set-name: "eth{{ $i }}"
+      {{- end }}
+      wakeonlan: true
+      {{- if not $net.Bond }}
+      {{- template "interface" $net }}
+      {{- end }}
+    {{- end }}
+  {{- if .Bonds }}
+  bonds:
+    {{- range .Bonds }}
+    {{ .Name }}:
+      interfaces:
+      {{- range .Interfaces }}
+      - "{{ . }}"
+      {{- end }}
+      {{- if or .Parameters.Mode .Parameters.LACPRate .Parameters.MIIMonitorInterval .Parameters.TransmitHashPolicy }}
+      parameters:
+        {{- if .Parameters.Mode }}
+        mode: "{{ .Parameters.Mode }}"
+        {{- end }}
+        {{- if .Parameters.LACPRate }}
+        lacp-rate: "{{ .Parameters.LACPRate }}"
+        {{- end }}
+        {{- if .Parameters.MIIMonitorInterval }}
+        mii-monitor-interval: {{ .Parameters.MIIMonitorInterval }}
+        {{- end }}
+        {{- if .Parameters.TransmitHashPolicy }}
+        transmit-hash-policy: "{{ .Parameters.TransmitHashPolicy }}"
+        {{- end }}
+      {{- end }}
+      {{- template "interface" .Device }}
+    {{- end }}
+  {{- end }}
+  {{- if .VLANs }}
+  vlans:
+    {{- range .VLANs }}
+    {{ .Name }}:
+      id: {{ .ID }}
+      link: "{{ .Link }}"
+      {{- template "interface" .Device }}
     {{- end }}
+  {{- end }}
   {{- if .Routes }}
   routes:
   {{- range .Routes }}
@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+// DeprecatedField is a deprecated field set on an object, along with how to migrate away from it.
+type DeprecatedField struct {
+	// Path is the path of the deprecated field.
+	Path *field.Path
+
+	// Migration describes how to stop using the deprecated field.
+	Migration string
+}
+
+// Warning returns the admission warning for the deprecated field.
+func (d DeprecatedField) Warning() string {
+	return fmt.Sprintf("%s is deprecated and will be removed in a future release: %s", d.Path, d.Migration)
+}
+
+// NetworkSpecDeprecatedFields returns the deprecated fields set on a NetworkSpec.
+func NetworkSpecDeprecatedFields(fldPath *field.Path, network infrav1.NetworkSpec) []DeprecatedField {
+	var deprecated []DeprecatedField
+	if network.PreferredAPIServerCIDR != "" {
+		deprecated = append(deprecated, DeprecatedField{
+			Path:      fldPath.Child("preferredAPIServerCidr"),
+			Migration: "the field is no longer used, remove it; the control plane endpoint is configured on the VSphereCluster with spec.controlPlaneEndpoint",
+		})
+	}
+	return deprecated
+}
+
+// VSphereFailureDomainDeprecatedFields returns the deprecated fields set on a VSphereFailureDomain.
+func VSphereFailureDomainDeprecatedFields(failureDomain *infrav1.VSphereFailureDomain) []DeprecatedField {
+	var deprecated []DeprecatedField
+	specPath := field.NewPath("spec")
+	if ptr.Deref(failureDomain.Spec.Region.AutoConfigure, false) {
+		deprecated = append(deprecated, DeprecatedField{
+			Path:      specPath.Child("region", "autoConfigure"),
+			Migration: "create the tag category and attach the region tag to the vCenter objects beforehand and leave the field unset; as the spec is immutable, existing VSphereFailureDomains have to be recreated",
+		})
+	}
+	if ptr.Deref(failureDomain.Spec.Zone.AutoConfigure, false) {
+		deprecated = append(deprecated, DeprecatedField{
+			Path:      specPath.Child("zone", "autoConfigure"),
+			Migration: "create the tag category and attach the zone tag to the vCenter objects beforehand and leave the field unset; as the spec is immutable, existing VSphereFailureDomains have to be recreated",
+		})
+	}
+	return deprecated
+}
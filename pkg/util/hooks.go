@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+// PreTerminateDeleteHookObject is an object which supports pre-terminate delete hooks.
+type PreTerminateDeleteHookObject interface {
+	client.Object
+	conditions.Setter
+}
+
+// GetPreTerminateDeleteHooks returns the sorted pre-terminate delete hooks of the object, i.e. its
+// annotations with the pre-terminate delete hook prefix defined by Cluster API.
+func GetPreTerminateDeleteHooks(obj client.Object) []string {
+	var hooks []string
+	for annotation := range obj.GetAnnotations() {
+		if strings.HasPrefix(annotation, clusterv1.PreTerminateDeleteHookAnnotationPrefix) {
+			hooks = append(hooks, annotation)
+		}
+	}
+	sort.Strings(hooks)
+	return hooks
+}
+
+// ReconcilePreTerminateDeleteHooks returns true if the deletion of the object has to wait for its
+// pre-terminate delete hooks to be removed, and the time after which the hooks time out, if any.
+// Hooks time out once the object has been deleted for longer than the timeout, they never time
+// out if the timeout is 0. The PreTerminateDeleteHookSucceeded condition of the object is set
+// if the object has or had hooks.
+func ReconcilePreTerminateDeleteHooks(ctx context.Context, obj PreTerminateDeleteHookObject, timeout time.Duration, now time.Time) (bool, time.Duration) {
+	log := ctrl.LoggerFrom(ctx)
+
+	hooks := GetPreTerminateDeleteHooks(obj)
+	if len(hooks) == 0 {
+		if conditions.Has(obj, clusterv1.PreTerminateDeleteHookSucceededCondition) {
+			conditions.MarkTrue(obj, clusterv1.PreTerminateDeleteHookSucceededCondition)
+		}
+		return false, 0
+	}
+
+	if timeout > 0 && obj.GetDeletionTimestamp() != nil {
+		timeoutAt := obj.GetDeletionTimestamp().Add(timeout)
+		if !now.Before(timeoutAt) {
+			log.Info("Pre-terminate delete hooks timed out, continuing deletion", "hooks", hooks, "timeout", timeout)
+			conditions.MarkFalse(obj, clusterv1.PreTerminateDeleteHookSucceededCondition, infrav1.PreTerminateDeleteHookTimedOutReason, clusterv1.ConditionSeverityWarning,
+				"Timed out after %s waiting for hooks %s", timeout, strings.Join(hooks, ", "))
+			return false, 0
+		}
+		log.Info("Waiting for pre-terminate delete hooks to be removed", "hooks", hooks, "timeout", timeout)
+		conditions.MarkFalse(obj, clusterv1.PreTerminateDeleteHookSucceededCondition, clusterv1.WaitingExternalHookReason, clusterv1.ConditionSeverityInfo,
+			"Waiting for hooks %s", strings.Join(hooks, ", "))
+		return true, timeoutAt.Sub(now)
+	}
+
+	log.Info("Waiting for pre-terminate delete hooks to be removed", "hooks", hooks)
+	conditions.MarkFalse(obj, clusterv1.PreTerminateDeleteHookSucceededCondition, clusterv1.WaitingExternalHookReason, clusterv1.ConditionSeverityInfo,
+		"Waiting for hooks %s", strings.Join(hooks, ", "))
+	return true, 0
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func TestGetPreTerminateDeleteHooks(t *testing.T) {
+	g := NewWithT(t)
+	vm := &infrav1.VSphereVM{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+		clusterv1.PreTerminateDeleteHookAnnotationPrefix + "/storage": "",
+		clusterv1.PreTerminateDeleteHookAnnotationPrefix + "/cmdb":    "",
+		clusterv1.PreDrainDeleteHookAnnotationPrefix + "/drain":       "",
+		"foo": "bar",
+	}}}
+	g.Expect(GetPreTerminateDeleteHooks(vm)).To(Equal([]string{
+		clusterv1.PreTerminateDeleteHookAnnotationPrefix + "/cmdb",
+		clusterv1.PreTerminateDeleteHookAnnotationPrefix + "/storage",
+	}))
+	g.Expect(GetPreTerminateDeleteHooks(&infrav1.VSphereVM{})).To(BeEmpty())
+}
+
+func TestReconcilePreTerminateDeleteHooks(t *testing.T) {
+	ctx := context.Background()
+	deletedAt := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	hooks := map[string]string{clusterv1.PreTerminateDeleteHookAnnotationPrefix + "/cmdb": "cmdb-controller"}
+
+	newVM := func(annotations map[string]string) *infrav1.VSphereVM {
+		return &infrav1.VSphereVM{ObjectMeta: metav1.ObjectMeta{
+			Annotations:       annotations,
+			DeletionTimestamp: &metav1.Time{Time: deletedAt},
+		}}
+	}
+
+	t.Run("without hooks", func(t *testing.T) {
+		g := NewWithT(t)
+		vm := newVM(nil)
+		wait, _ := ReconcilePreTerminateDeleteHooks(ctx, vm, 0, deletedAt)
+		g.Expect(wait).To(BeFalse())
+		g.Expect(conditions.Has(vm, clusterv1.PreTerminateDeleteHookSucceededCondition)).To(BeFalse())
+	})
+
+	t.Run("with hooks and without timeout", func(t *testing.T) {
+		g := NewWithT(t)
+		vm := newVM(hooks)
+		wait, requeueAfter := ReconcilePreTerminateDeleteHooks(ctx, vm, 0, deletedAt.Add(time.Hour))
+		g.Expect(wait).To(BeTrue())
+		g.Expect(requeueAfter).To(BeZero())
+		g.Expect(conditions.GetReason(vm, clusterv1.PreTerminateDeleteHookSucceededCondition)).To(Equal(clusterv1.WaitingExternalHookReason))
+		g.Expect(conditions.GetMessage(vm, clusterv1.PreTerminateDeleteHookSucceededCondition)).To(ContainSubstring("/cmdb"))
+	})
+
+	t.Run("with hooks before the timeout", func(t *testing.T) {
+		g := NewWithT(t)
+		vm := newVM(hooks)
+		wait, requeueAfter := ReconcilePreTerminateDeleteHooks(ctx, vm, 10*time.Minute, deletedAt.Add(4*time.Minute))
+		g.Expect(wait).To(BeTrue())
+		g.Expect(requeueAfter).To(Equal(6 * time.Minute))
+		g.Expect(conditions.GetReason(vm, clusterv1.PreTerminateDeleteHookSucceededCondition)).To(Equal(clusterv1.WaitingExternalHookReason))
+	})
+
+	t.Run("with hooks after the timeout", func(t *testing.T) {
+		g := NewWithT(t)
+		vm := newVM(hooks)
+		wait, _ := ReconcilePreTerminateDeleteHooks(ctx, vm, 10*time.Minute, deletedAt.Add(10*time.Minute))
+		g.Expect(wait).To(BeFalse())
+		g.Expect(conditions.GetReason(vm, clusterv1.PreTerminateDeleteHookSucceededCondition)).To(Equal(infrav1.PreTerminateDeleteHookTimedOutReason))
+		g.Expect(conditions.GetSeverity(vm, clusterv1.PreTerminateDeleteHookSucceededCondition)).To(HaveValue(Equal(clusterv1.ConditionSeverityWarning)))
+	})
+
+	t.Run("hooks have been removed", func(t *testing.T) {
+		g := NewWithT(t)
+		vm := newVM(hooks)
+		ReconcilePreTerminateDeleteHooks(ctx, vm, 0, deletedAt)
+		vm.Annotations = nil
+		wait, _ := ReconcilePreTerminateDeleteHooks(ctx, vm, 0, deletedAt)
+		g.Expect(wait).To(BeFalse())
+		g.Expect(conditions.Get(vm, clusterv1.PreTerminateDeleteHookSucceededCondition).Status).To(Equal(corev1.ConditionTrue))
+	})
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+const (
+	// DefaultMachinePoolNamingTemplate is the template used to generate the names of the
+	// VSphereVMs of a VSphereMachinePool if no naming strategy is defined.
+	DefaultMachinePoolNamingTemplate = "{{ .machinePool.name }}-{{ .random }}"
+
+	// maxMachinePoolInstanceNameLength is the maximum length of the name of a VSphereVM
+	// of a VSphereMachinePool, so it can be used as hostname.
+	maxMachinePoolInstanceNameLength = 63
+	randomSuffixLength               = 5
+)
+
+// GenerateMachinePoolInstanceName generates the name of a new VSphereVM of the VSphereMachinePool
+// using the naming strategy of the VSphereMachinePool.
+func GenerateMachinePoolInstanceName(machinePool *infrav1.VSphereMachinePool) (string, error) {
+	namingTemplate := DefaultMachinePoolNamingTemplate
+	if machinePool.Spec.NamingStrategy != nil && machinePool.Spec.NamingStrategy.Template != nil {
+		namingTemplate = *machinePool.Spec.NamingStrategy.Template
+	}
+
+	tpl, err := template.New("name").Option("missingkey=error").Parse(namingTemplate)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse naming template %q", namingTemplate)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := tpl.Execute(buf, map[string]interface{}{
+		"machinePool": map[string]interface{}{
+			"name": machinePool.Name,
+		},
+		"random": rand.String(randomSuffixLength),
+	}); err != nil {
+		return "", errors.Wrapf(err, "failed to render naming template %q", namingTemplate)
+	}
+
+	name := buf.String()
+	if len(name) > maxMachinePoolInstanceNameLength {
+		name = name[:maxMachinePoolInstanceNameLength-randomSuffixLength] + rand.String(randomSuffixLength)
+	}
+	return name, nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/util"
+)
+
+func Test_GenerateMachinePoolInstanceName(t *testing.T) {
+	testCases := []struct {
+		name           string
+		poolName       string
+		namingStrategy *infrav1.VSphereMachinePoolNamingStrategy
+		wantPrefix     string
+		wantLength     int
+		wantErr        bool
+	}{
+		{
+			name:       "default naming template",
+			poolName:   "pool",
+			wantPrefix: "pool-",
+			wantLength: len("pool-") + 5,
+		},
+		{
+			name:     "custom naming template",
+			poolName: "pool",
+			namingStrategy: &infrav1.VSphereMachinePoolNamingStrategy{
+				Template: ptr.To("worker-{{ .machinePool.name }}-{{ .random }}"),
+			},
+			wantPrefix: "worker-pool-",
+			wantLength: len("worker-pool-") + 5,
+		},
+		{
+			name:     "name is trimmed to 63 characters",
+			poolName: strings.Repeat("a", 70),
+			namingStrategy: &infrav1.VSphereMachinePoolNamingStrategy{
+				Template: ptr.To("{{ .machinePool.name }}"),
+			},
+			wantPrefix: strings.Repeat("a", 58),
+			wantLength: 63,
+		},
+		{
+			name:     "invalid template",
+			poolName: "pool",
+			namingStrategy: &infrav1.VSphereMachinePoolNamingStrategy{
+				Template: ptr.To("{{ .machinePool.name "),
+			},
+			wantErr: true,
+		},
+		{
+			name:     "unknown key",
+			poolName: "pool",
+			namingStrategy: &infrav1.VSphereMachinePoolNamingStrategy{
+				Template: ptr.To("{{ .machine.name }}"),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			machinePool := &infrav1.VSphereMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: tc.poolName},
+				Spec: infrav1.VSphereMachinePoolSpec{
+					NamingStrategy: tc.namingStrategy,
+				},
+			}
+
+			name, err := util.GenerateMachinePoolInstanceName(machinePool)
+			if tc.wantErr {
+				g.Expect(err).To(gomega.HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(gomega.HaveOccurred())
+			g.Expect(name).To(gomega.HavePrefix(tc.wantPrefix))
+			g.Expect(name).To(gomega.HaveLen(tc.wantLength))
+		})
+	}
+}
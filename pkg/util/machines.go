@@ -80,6 +80,18 @@ func GetMachinePreferredIPAddress(machine *infrav1.VSphereMachine) (string, erro
 	return "", ErrNoMachineIPAddr
 }
 
+// parseIPAddr parses an IP address which is either in CIDR notation,
+// e.g. 192.168.0.1/24 or fd00::1/64, or a plain IP address.
+func parseIPAddr(ipStr string) (net.IP, bool) {
+	if ip, _, err := net.ParseCIDR(ipStr); err == nil {
+		return ip, true
+	}
+	if ip := net.ParseIP(ipStr); ip != nil {
+		return ip, true
+	}
+	return nil, false
+}
+
 // IsControlPlaneMachine returns true if the provided resource is
 // a member of the control plane.
 func IsControlPlaneMachine(machine metav1.Object) bool {
@@ -89,7 +101,7 @@ func IsControlPlaneMachine(machine metav1.Object) bool {
 
 // GetMachineMetadata the cloud-init metadata as a base-64 encoded
 // string for a given VSphereMachine.
-// IPAM state includes IP and Gateways that should be added to each device.
+// IPAM state includes IP, Gateways, Routes and DNS configuration that should be added to each device.
 func GetMachineMetadata(hostname string, vsphereVM infrav1.VSphereVM, ipamState map[string]infrav1.NetworkDeviceSpec, networkStatuses ...infrav1.NetworkStatus) ([]byte, error) {
 	// Create a copy of the devices and add their MAC addresses from a network status.
 	devices := make([]infrav1.NetworkDeviceSpec, max(len(vsphereVM.Spec.Network.Devices), len(networkStatuses)))
@@ -107,6 +119,9 @@ func GetMachineMetadata(hostname string, vsphereVM infrav1.VSphereVM, ipamState
 			devices[i].IPAddrs = append(devices[i].IPAddrs, state.IPAddrs...)
 			devices[i].Gateway4 = state.Gateway4
 			devices[i].Gateway6 = state.Gateway6
+			devices[i].Routes = append(devices[i].Routes, state.Routes...)
+			devices[i].Nameservers = append(devices[i].Nameservers, state.Nameservers...)
+			devices[i].SearchDomains = append(devices[i].SearchDomains, state.SearchDomains...)
 		}
 
 		if waitForIPv4 && waitForIPv6 {
@@ -114,11 +129,11 @@ func GetMachineMetadata(hostname string, vsphereVM infrav1.VSphereVM, ipamState
 			continue
 		}
 
-		// check static IPs
-		for _, ipStr := range vsphereVM.Spec.Network.Devices[i].IPAddrs {
-			ip := net.ParseIP(ipStr)
+		// check static IPs, including the ones allocated from IPAM pools
+		for _, ipStr := range devices[i].IPAddrs {
+			ip, ok := parseIPAddr(ipStr)
 			// check the IP family
-			if ip != nil {
+			if ok {
 				if ip.To4() == nil {
 					waitForIPv6 = true
 				} else {
@@ -142,6 +157,22 @@ func GetMachineMetadata(hostname string, vsphereVM infrav1.VSphereVM, ipamState
 		devices[i].MACAddr = status.MACAddr
 	}
 
+	// Add the bonds and VLAN sub-interfaces of the network devices.
+	bonds, vlans := getNetworkBondsAndVLANs(devices)
+	for _, vlan := range vlans {
+		for _, ipStr := range vlan.Device.IPAddrs {
+			if ip, ok := parseIPAddr(ipStr); ok {
+				if ip.To4() == nil {
+					waitForIPv6 = true
+				} else {
+					waitForIPv4 = true
+				}
+			}
+		}
+		waitForIPv4 = waitForIPv4 || vlan.Device.DHCP4
+		waitForIPv6 = waitForIPv6 || vlan.Device.DHCP6
+	}
+
 	buf := &bytes.Buffer{}
 	tpl := template.Must(template.New("t").Funcs(
 		template.FuncMap{
@@ -152,12 +183,16 @@ func GetMachineMetadata(hostname string, vsphereVM infrav1.VSphereVM, ipamState
 	if err := tpl.Execute(buf, struct {
 		Hostname    string
 		Devices     []infrav1.NetworkDeviceSpec
+		Bonds       []networkBond
+		VLANs       []networkVLAN
 		Routes      []infrav1.NetworkRouteSpec
 		WaitForIPv4 bool
 		WaitForIPv6 bool
 	}{
 		Hostname:    hostname, // note that hostname determines the Kubernetes node name
 		Devices:     devices,
+		Bonds:       bonds,
+		VLANs:       vlans,
 		Routes:      vsphereVM.Spec.Network.Routes,
 		WaitForIPv4: waitForIPv4,
 		WaitForIPv6: waitForIPv6,
@@ -193,6 +228,24 @@ func getVSphereMachineByName(ctx context.Context, c client.Client, namespace, na
 	return m, nil
 }
 
+// GetOwnerVSphereMachinePool returns the VSphereMachinePool object owning the current resource.
+func GetOwnerVSphereMachinePool(ctx context.Context, c client.Client, obj metav1.ObjectMeta) (*infrav1.VSphereMachinePool, error) {
+	for _, ref := range obj.OwnerReferences {
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			return nil, err
+		}
+		if ref.Kind == "VSphereMachinePool" && gv.Group == infrav1.GroupVersion.Group {
+			m := &infrav1.VSphereMachinePool{}
+			if err := c.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: obj.Namespace}, m); err != nil {
+				return nil, err
+			}
+			return m, nil
+		}
+	}
+	return nil, nil
+}
+
 const (
 	// ProviderIDPrefix is the string data prefixed to a BIOS UUID in order
 	// to build a provider ID.
@@ -258,3 +311,98 @@ func MachinesAsString(machines []*clusterv1.Machine) string {
 	}
 	return message
 }
+
+// networkBond is a bond of network devices in the cloud-init metadata.
+type networkBond struct {
+	// Name is the name of the bond interface.
+	Name string
+	// Interfaces are the netplan IDs of the bonded network devices.
+	Interfaces []string
+	// Parameters are the bond parameters.
+	Parameters infrav1.NetworkBondSpec
+	// Device is the IP configuration of the bond, i.e. the one of its first member.
+	Device infrav1.NetworkDeviceSpec
+}
+
+// networkVLAN is a VLAN sub-interface in the cloud-init metadata.
+type networkVLAN struct {
+	// Name is the name of the VLAN interface.
+	Name string
+	// ID is the VLAN ID.
+	ID int32
+	// Link is the netplan ID of the network device or bond the VLAN is created on.
+	Link string
+	// Device is the IP configuration of the VLAN interface.
+	Device infrav1.NetworkDeviceSpec
+}
+
+// getNetworkBondsAndVLANs returns the bonds and the VLAN sub-interfaces of the network devices.
+// The network devices are identified in the metadata by the netplan IDs id<index>.
+func getNetworkBondsAndVLANs(devices []infrav1.NetworkDeviceSpec) ([]networkBond, []networkVLAN) {
+	bonds := []networkBond{}
+	bondIndex := map[string]int{}
+	vlans := []networkVLAN{}
+	for i, device := range devices {
+		link := fmt.Sprintf("id%d", i)
+		name := device.DeviceName
+		if name == "" {
+			name = fmt.Sprintf("eth%d", i)
+		}
+		if device.Bond != nil {
+			j, ok := bondIndex[device.Bond.Name]
+			if ok {
+				bonds[j].Interfaces = append(bonds[j].Interfaces, link)
+				continue
+			}
+			bondIndex[device.Bond.Name] = len(bonds)
+			bonds = append(bonds, networkBond{
+				Name:       device.Bond.Name,
+				Interfaces: []string{link},
+				Parameters: *device.Bond,
+				Device:     device,
+			})
+			// The VLANs of a bond member are created on the bond.
+			link = device.Bond.Name
+			name = device.Bond.Name
+		}
+		for _, vlan := range device.VLANs {
+			vlanName := vlan.Name
+			if vlanName == "" {
+				vlanName = fmt.Sprintf("%s.%d", name, vlan.ID)
+			}
+			vlans = append(vlans, networkVLAN{
+				Name: vlanName,
+				ID:   vlan.ID,
+				Link: link,
+				Device: infrav1.NetworkDeviceSpec{
+					DHCP4:         vlan.DHCP4,
+					DHCP6:         vlan.DHCP6,
+					Gateway4:      vlan.Gateway4,
+					Gateway6:      vlan.Gateway6,
+					IPAddrs:       vlan.IPAddrs,
+					MTU:           vlan.MTU,
+					Nameservers:   vlan.Nameservers,
+					Routes:        vlan.Routes,
+					SearchDomains: vlan.SearchDomains,
+				},
+			})
+		}
+	}
+	return bonds, vlans
+}
+
+// IsSecondaryBondMember returns true if the network device at the given index is a member
+// of a bond, but not its first member. The IP configuration of a bond is the one of its
+// first member, so IP addresses are neither allocated nor reported for the other members.
+func IsSecondaryBondMember(devices []infrav1.NetworkDeviceSpec, index int) bool {
+	bond := devices[index].Bond
+	if bond == nil {
+		return false
+	}
+	for _, device := range devices[:index] {
+		if device.Bond != nil && device.Bond.Name == bond.Name {
+			return true
+		}
+	}
+	return false
+}
@@ -25,6 +25,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -823,7 +824,7 @@ instance-id: "test-vm"
 local-hostname: "test-vm"
 wait-on-network:
   ipv4: true
-  ipv6: false
+  ipv6: true
 network:
   version: 2
   ethernets:
@@ -857,6 +858,115 @@ network:
       addresses:
       - "fe80::3/64"
       gateway6: "fe80::1"
+`,
+		},
+		{
+			name: "dual-stack ipam state is used to render metadata",
+			machine: &infrav1.VSphereVM{
+				Spec: infrav1.VSphereVMSpec{
+					VirtualMachineCloneSpec: infrav1.VirtualMachineCloneSpec{
+						Network: infrav1.NetworkSpec{
+							Devices: []infrav1.NetworkDeviceSpec{
+								{
+									NetworkName: "network1",
+									MACAddr:     "00:00:00:00:00",
+								},
+							},
+						},
+					},
+				},
+			},
+			ipamState: map[string]infrav1.NetworkDeviceSpec{
+				"00:00:00:00:00": {
+					IPAddrs: []string{
+						"10.10.50.50/24",
+						"fd00:10:10::50/64",
+					},
+					Gateway4: "10.10.50.1",
+					Gateway6: "fd00:10:10::1",
+				},
+			},
+			expected: `
+instance-id: "test-vm"
+local-hostname: "test-vm"
+wait-on-network:
+  ipv4: true
+  ipv6: true
+network:
+  version: 2
+  ethernets:
+    id0:
+      match:
+        macaddress: "00:00:00:00:00"
+      set-name: "eth0"
+      wakeonlan: true
+      dhcp4: false
+      dhcp6: false
+      accept-ra: false
+      addresses:
+      - "10.10.50.50/24"
+      - "fd00:10:10::50/64"
+      gateway4: "10.10.50.1"
+      gateway6: "fd00:10:10::1"
+`,
+		},
+		{
+			name: "ipam state with multiple subnets and routes is used to render metadata",
+			machine: &infrav1.VSphereVM{
+				Spec: infrav1.VSphereVMSpec{
+					VirtualMachineCloneSpec: infrav1.VirtualMachineCloneSpec{
+						Network: infrav1.NetworkSpec{
+							Devices: []infrav1.NetworkDeviceSpec{
+								{
+									NetworkName: "network1",
+									MACAddr:     "00:00:00:00:00",
+								},
+							},
+						},
+					},
+				},
+			},
+			ipamState: map[string]infrav1.NetworkDeviceSpec{
+				"00:00:00:00:00": {
+					IPAddrs: []string{
+						"10.10.50.50/24",
+						"10.20.50.50/24",
+					},
+					Gateway4: "10.10.50.1",
+					Routes: []infrav1.NetworkRouteSpec{
+						{
+							To:     "10.30.0.0/16",
+							Via:    "10.20.50.1",
+							Metric: 100,
+						},
+					},
+				},
+			},
+			expected: `
+instance-id: "test-vm"
+local-hostname: "test-vm"
+wait-on-network:
+  ipv4: true
+  ipv6: false
+network:
+  version: 2
+  ethernets:
+    id0:
+      match:
+        macaddress: "00:00:00:00:00"
+      set-name: "eth0"
+      wakeonlan: true
+      dhcp4: false
+      dhcp6: false
+      accept-ra: false
+      addresses:
+      - "10.10.50.50/24"
+      - "10.20.50.50/24"
+      gateway4: "10.10.50.1"
+      routes:
+      - to: "10.30.0.0/16"
+        via: "10.20.50.1"
+        metric: 100
 `,
 		},
 		{
@@ -919,6 +1029,122 @@ network:
       dhcp4: false
       dhcp6: false
       accept-ra: false
+`,
+		},
+		{
+			name: "bond+vlans",
+			machine: &infrav1.VSphereVM{
+				Spec: infrav1.VSphereVMSpec{
+					VirtualMachineCloneSpec: infrav1.VirtualMachineCloneSpec{
+						Network: infrav1.NetworkSpec{
+							Devices: []infrav1.NetworkDeviceSpec{
+								{
+									NetworkName: "network1",
+									IPAddrs:     []string{"192.168.4.21/24"},
+									Gateway4:    "192.168.4.1",
+									Bond: &infrav1.NetworkBondSpec{
+										Name:               "bond0",
+										Mode:               infrav1.NetworkBondMode8023AD,
+										LACPRate:           "fast",
+										MIIMonitorInterval: ptr.To[int32](100),
+										TransmitHashPolicy: "layer3+4",
+									},
+									VLANs: []infrav1.NetworkVLANSpec{
+										{
+											ID:      100,
+											IPAddrs: []string{"10.0.100.10/24"},
+										},
+									},
+								},
+								{
+									NetworkName: "network2",
+									Bond: &infrav1.NetworkBondSpec{
+										Name:               "bond0",
+										Mode:               infrav1.NetworkBondMode8023AD,
+										LACPRate:           "fast",
+										MIIMonitorInterval: ptr.To[int32](100),
+										TransmitHashPolicy: "layer3+4",
+									},
+								},
+								{
+									NetworkName: "network3",
+									DeviceName:  "ens224",
+									VLANs: []infrav1.NetworkVLANSpec{
+										{
+											ID:    200,
+											Name:  "storage",
+											DHCP6: true,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			networkStatuses: []infrav1.NetworkStatus{
+				{MACAddr: "00:00:00:00:ab"},
+				{MACAddr: "00:00:00:00:cd"},
+				{MACAddr: "00:00:00:00:ef"},
+			},
+			expected: `
+instance-id: "test-vm"
+local-hostname: "test-vm"
+wait-on-network:
+  ipv4: true
+  ipv6: true
+network:
+  version: 2
+  ethernets:
+    id0:
+      match:
+        macaddress: "00:00:00:00:ab"
+      set-name: "eth0"
+      wakeonlan: true
+    id1:
+      match:
+        macaddress: "00:00:00:00:cd"
+      set-name: "eth1"
+      wakeonlan: true
+    id2:
+      match:
+        macaddress: "00:00:00:00:ef"
+      set-name: "ens224"
+      wakeonlan: true
+      dhcp4: false
+      dhcp6: false
+      accept-ra: false
+  bonds:
+    bond0:
+      interfaces:
+      - "id0"
+      - "id1"
+      parameters:
+        mode: "802.3ad"
+        lacp-rate: "fast"
+        mii-monitor-interval: 100
+        transmit-hash-policy: "layer3+4"
+      dhcp4: false
+      dhcp6: false
+      accept-ra: false
+      addresses:
+      - "192.168.4.21/24"
+      gateway4: "192.168.4.1"
+  vlans:
+    bond0.100:
+      id: 100
+      link: "bond0"
+      dhcp4: false
+      dhcp6: false
+      accept-ra: false
+      addresses:
+      - "10.0.100.10/24"
+    storage:
+      id: 200
+      link: "id2"
+      dhcp4: false
+      dhcp6: true
+      accept-ra: true
 `,
 		},
 	}
@@ -0,0 +1,134 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+const (
+	// maxVSphereVMNameLength is the maximum length of the name of a VSphereVM, so it can be used as hostname.
+	maxVSphereVMNameLength = 63
+
+	// maxFolderNameLength is the maximum length of the name of a folder in vCenter.
+	maxFolderNameLength = 80
+)
+
+// NameTemplateFuncs are the funcs available in the templates of naming strategies.
+// Note: Inlining these functions from sprig to avoid introducing a dependency.
+var NameTemplateFuncs = map[string]any{
+	"trimSuffix": func(a, b string) string { return strings.TrimSuffix(b, a) },
+	"trunc": func(c int, s string) string {
+		if c < 0 && len(s)+c > 0 {
+			return s[len(s)+c:]
+		}
+		if c >= 0 && len(s) > c {
+			return s[:c]
+		}
+		return s
+	},
+}
+
+// GenerateVSphereVMName generates the name of the VSphereVM of a Machine based on the naming strategy.
+func GenerateVSphereVMName(machineName string, namingStrategy *infrav1.VSphereVMNamingStrategy) (string, error) {
+	// Per default the name of the VSphereVM should be equal to the Machine name (this is the same as "{{ .machine.name }}")
+	if namingStrategy == nil || namingStrategy.Template == nil {
+		return machineName, nil
+	}
+
+	name, err := executeNameTemplate(*namingStrategy.Template, map[string]interface{}{
+		"machine": map[string]interface{}{
+			"name": machineName,
+		},
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to generate name for VSphereVM")
+	}
+
+	// Note: we're not adding a random suffix as the name has to be deterministic.
+	if len(name) > maxVSphereVMNameLength {
+		name = name[:maxVSphereVMNameLength]
+	}
+	return name, nil
+}
+
+// GenerateClusterFolderName generates the name of the folder of the virtual machines of the cluster
+// based on the folder naming strategy of the VSphereCluster. It returns an empty name if the
+// VSphereCluster has no folder naming strategy.
+func GenerateClusterFolderName(vsphereCluster *infrav1.VSphereCluster) (string, error) {
+	namingStrategy := vsphereCluster.Spec.FolderNamingStrategy
+	if namingStrategy == nil {
+		return "", nil
+	}
+
+	// The VSphereCluster usually has the name of the Cluster, which owns it once it is reconciled.
+	clusterName := vsphereCluster.Name
+	for _, ref := range vsphereCluster.OwnerReferences {
+		if ref.Kind == "Cluster" && strings.HasPrefix(ref.APIVersion, clusterv1.GroupVersion.Group+"/") {
+			clusterName = ref.Name
+		}
+	}
+	name, err := executeNameTemplate(namingStrategy.Template, map[string]interface{}{
+		"cluster": map[string]interface{}{
+			"name":      clusterName,
+			"namespace": vsphereCluster.Namespace,
+		},
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to generate name for folder")
+	}
+	if name == "" || strings.Contains(name, "/") {
+		return "", errors.Errorf("failed to generate name for folder: %q is not a valid folder name", name)
+	}
+
+	if len(name) > maxFolderNameLength {
+		name = name[:maxFolderNameLength]
+	}
+	return name, nil
+}
+
+// ClusterFolder returns the folder for a virtual machine of the cluster, which is the folder generated
+// by the folder naming strategy of the VSphereCluster inside the given folder. The given folder is
+// returned as is if the VSphereCluster has no folder naming strategy.
+func ClusterFolder(vsphereCluster *infrav1.VSphereCluster, folder string) (string, error) {
+	name, err := GenerateClusterFolderName(vsphereCluster)
+	if err != nil || name == "" {
+		return folder, err
+	}
+	return path.Join(folder, name), nil
+}
+
+func executeNameTemplate(nameTemplate string, data map[string]interface{}) (string, error) {
+	tpl, err := template.New("name generator").Funcs(NameTemplateFuncs).Option("missingkey=error").Parse(nameTemplate)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse template %q", nameTemplate)
+	}
+
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/util"
+)
+
+func Test_GenerateVSphereVMName(t *testing.T) {
+	testCases := []struct {
+		name           string
+		machineName    string
+		namingStrategy *infrav1.VSphereVMNamingStrategy
+		want           string
+		wantErr        bool
+	}{
+		{
+			name:        "default naming strategy",
+			machineName: "quick-start-d34gt4-md-0-wqc85-8nxwc-gfd5v",
+			want:        "quick-start-d34gt4-md-0-wqc85-8nxwc-gfd5v",
+		},
+		{
+			name:        "naming strategy with prefix and suffix",
+			machineName: "quick-start-md-0-gfd5v",
+			namingStrategy: &infrav1.VSphereVMNamingStrategy{
+				Template: ptr.To("vm-{{ .machine.name }}-prod"),
+			},
+			want: "vm-quick-start-md-0-gfd5v-prod",
+		},
+		{
+			name:        "naming strategy using trimSuffix and trunc",
+			machineName: "quick-start-d34gt4-md-0-wqc85-8nxwc-gfd5v",
+			namingStrategy: &infrav1.VSphereVMNamingStrategy{
+				Template: ptr.To("{{ if le (len .machine.name) 20 }}{{ .machine.name }}{{else}}{{ trimSuffix \"-\" (trunc 14 .machine.name) }}-{{ trunc -5 .machine.name }}{{end}}"),
+			},
+			want: "quick-start-d3-gfd5v",
+		},
+		{
+			name:        "name is truncated to 63 characters",
+			machineName: strings.Repeat("a", 70),
+			namingStrategy: &infrav1.VSphereVMNamingStrategy{
+				Template: ptr.To("{{ .machine.name }}"),
+			},
+			want: strings.Repeat("a", 63),
+		},
+		{
+			name:        "invalid template",
+			machineName: "machine",
+			namingStrategy: &infrav1.VSphereVMNamingStrategy{
+				Template: ptr.To("{{ .machine.name "),
+			},
+			wantErr: true,
+		},
+		{
+			name:        "unknown key",
+			machineName: "machine",
+			namingStrategy: &infrav1.VSphereVMNamingStrategy{
+				Template: ptr.To("{{ .cluster.name }}"),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			name, err := util.GenerateVSphereVMName(tc.machineName, tc.namingStrategy)
+			if tc.wantErr {
+				g.Expect(err).To(gomega.HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(gomega.HaveOccurred())
+			g.Expect(name).To(gomega.Equal(tc.want))
+		})
+	}
+}
+
+func Test_ClusterFolder(t *testing.T) {
+	clusterOwnerRef := metav1.OwnerReference{
+		APIVersion: clusterv1.GroupVersion.String(),
+		Kind:       "Cluster",
+		Name:       "owner",
+	}
+
+	testCases := []struct {
+		name           string
+		folder         string
+		ownerRefs      []metav1.OwnerReference
+		namingStrategy *infrav1.VSphereFolderNamingStrategy
+		want           string
+		wantErr        bool
+	}{
+		{
+			name:   "without folder naming strategy",
+			folder: "/DC0/vm/capv",
+			want:   "/DC0/vm/capv",
+		},
+		{
+			name:   "folder naming strategy",
+			folder: "/DC0/vm/capv",
+			namingStrategy: &infrav1.VSphereFolderNamingStrategy{
+				Template: "k8s-{{ .cluster.namespace }}-{{ .cluster.name }}",
+			},
+			want: "/DC0/vm/capv/k8s-default-cluster",
+		},
+		{
+			name: "folder naming strategy without folder",
+			namingStrategy: &infrav1.VSphereFolderNamingStrategy{
+				Template: "{{ .cluster.name }}",
+			},
+			want: "cluster",
+		},
+		{
+			name:      "cluster name from the owner reference",
+			folder:    "capv",
+			ownerRefs: []metav1.OwnerReference{clusterOwnerRef},
+			namingStrategy: &infrav1.VSphereFolderNamingStrategy{
+				Template: "{{ .cluster.name }}",
+			},
+			want: "capv/owner",
+		},
+		{
+			name:   "name is truncated to 80 characters",
+			folder: "capv",
+			namingStrategy: &infrav1.VSphereFolderNamingStrategy{
+				Template: strings.Repeat("a", 90),
+			},
+			want: "capv/" + strings.Repeat("a", 80),
+		},
+		{
+			name:   "name containing a slash",
+			folder: "capv",
+			namingStrategy: &infrav1.VSphereFolderNamingStrategy{
+				Template: "{{ .cluster.namespace }}/{{ .cluster.name }}",
+			},
+			wantErr: true,
+		},
+		{
+			name:   "unknown key",
+			folder: "capv",
+			namingStrategy: &infrav1.VSphereFolderNamingStrategy{
+				Template: "{{ .machine.name }}",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			vsphereCluster := &infrav1.VSphereCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "cluster",
+					Namespace:       "default",
+					OwnerReferences: tc.ownerRefs,
+				},
+				Spec: infrav1.VSphereClusterSpec{
+					FolderNamingStrategy: tc.namingStrategy,
+				},
+			}
+
+			folder, err := util.ClusterFolder(vsphereCluster, tc.folder)
+			if tc.wantErr {
+				g.Expect(err).To(gomega.HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(gomega.HaveOccurred())
+			g.Expect(folder).To(gomega.Equal(tc.want))
+		})
+	}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+// IsInfraPaused returns true if any of the given objects has the
+// PausedInfraAnnotation set. Nil objects are ignored.
+func IsInfraPaused(objs ...metav1.Object) bool {
+	return hasAnnotation(infrav1.PausedInfraAnnotation, objs...)
+}
+
+// IsDryRun returns true if any of the given objects has the
+// DryRunAnnotation set. Nil objects are ignored.
+func IsDryRun(objs ...metav1.Object) bool {
+	return hasAnnotation(infrav1.DryRunAnnotation, objs...)
+}
+
+// IsDeleteProtected returns true if any of the given objects has the
+// DeleteProtectionAnnotation set. Nil objects are ignored.
+func IsDeleteProtected(objs ...metav1.Object) bool {
+	return hasAnnotation(infrav1.DeleteProtectionAnnotation, objs...)
+}
+
+func hasAnnotation(annotation string, objs ...metav1.Object) bool {
+	for _, obj := range objs {
+		if obj == nil || reflect.ValueOf(obj).IsNil() {
+			continue
+		}
+		if _, ok := obj.GetAnnotations()[annotation]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
+)
+
+func TestIsInfraPaused(t *testing.T) {
+	paused := metav1.ObjectMeta{Annotations: map[string]string{infrav1.PausedInfraAnnotation: ""}}
+
+	t.Run("no objects are annotated", func(t *testing.T) {
+		g := NewWithT(t)
+		g.Expect(IsInfraPaused(&clusterv1.Cluster{}, &infrav1.VSphereVM{})).To(BeFalse())
+	})
+
+	t.Run("cluster is annotated", func(t *testing.T) {
+		g := NewWithT(t)
+		g.Expect(IsInfraPaused(&clusterv1.Cluster{ObjectMeta: paused}, &infrav1.VSphereVM{})).To(BeTrue())
+	})
+
+	t.Run("vm is annotated", func(t *testing.T) {
+		g := NewWithT(t)
+		g.Expect(IsInfraPaused(&clusterv1.Cluster{}, &infrav1.VSphereVM{ObjectMeta: paused})).To(BeTrue())
+	})
+
+	t.Run("nil objects are ignored", func(t *testing.T) {
+		g := NewWithT(t)
+		var cluster *clusterv1.Cluster
+		g.Expect(IsInfraPaused(cluster, nil)).To(BeFalse())
+	})
+}
+
+func TestIsDryRun(t *testing.T) {
+	dryRun := metav1.ObjectMeta{Annotations: map[string]string{infrav1.DryRunAnnotation: ""}}
+	paused := metav1.ObjectMeta{Annotations: map[string]string{infrav1.PausedInfraAnnotation: ""}}
+
+	t.Run("no objects are annotated", func(t *testing.T) {
+		g := NewWithT(t)
+		g.Expect(IsDryRun(&clusterv1.Cluster{}, &infrav1.VSphereVM{ObjectMeta: paused})).To(BeFalse())
+	})
+
+	t.Run("vsphere cluster is annotated", func(t *testing.T) {
+		g := NewWithT(t)
+		g.Expect(IsDryRun(&clusterv1.Cluster{}, &infrav1.VSphereCluster{ObjectMeta: dryRun}, &infrav1.VSphereVM{})).To(BeTrue())
+	})
+
+	t.Run("nil objects are ignored", func(t *testing.T) {
+		g := NewWithT(t)
+		var vsphereCluster *infrav1.VSphereCluster
+		g.Expect(IsDryRun(vsphereCluster, &infrav1.VSphereVM{})).To(BeFalse())
+	})
+}
+
+func TestIsDeleteProtected(t *testing.T) {
+	protected := metav1.ObjectMeta{Annotations: map[string]string{infrav1.DeleteProtectionAnnotation: ""}}
+
+	t.Run("no objects are annotated", func(t *testing.T) {
+		g := NewWithT(t)
+		g.Expect(IsDeleteProtected(&clusterv1.Cluster{}, &infrav1.VSphereCluster{}, &infrav1.VSphereVM{})).To(BeFalse())
+	})
+
+	t.Run("vm is annotated", func(t *testing.T) {
+		g := NewWithT(t)
+		g.Expect(IsDeleteProtected(&clusterv1.Cluster{}, &infrav1.VSphereCluster{}, &infrav1.VSphereVM{ObjectMeta: protected})).To(BeTrue())
+	})
+}
@@ -24,7 +24,6 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
-	"k8s.io/klog/v2"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -32,6 +31,7 @@ import (
 	vmwarev1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/vmware/v1beta1"
 	topologyv1 "sigs.k8s.io/cluster-api-provider-vsphere/internal/apis/topology/v1alpha1"
 	capvcontext "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context"
+	capvfake "sigs.k8s.io/cluster-api-provider-vsphere/pkg/context/fake"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/context/vmware"
 )
 
@@ -160,12 +160,11 @@ func CreateClusterContext(cluster *clusterv1.Cluster, vsphereCluster *vmwarev1.V
 			Cluster:        cluster,
 			VSphereCluster: vsphereCluster,
 		}, &capvcontext.ControllerManagerContext{
-			Logger: klog.Background().WithName("controller-manager-logger"),
 			Scheme: scheme,
 			Client: fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(
 				&vmoprv1.VirtualMachineService{},
 				&vmoprv1.VirtualMachine{},
-			).Build(),
+			).WithInterceptorFuncs(capvfake.ServerSideApplyInterceptorFuncs()).Build(),
 		}
 }
 
@@ -46,7 +46,7 @@ type AntiAffinitySpecInput struct {
 	SkipCleanup bool
 }
 
-var _ = Describe("Cluster creation with anti affined nodes", func() {
+var _ = DescribeWithFlavors("Cluster creation with anti affined nodes", func() {
 	const specName = "anti-affinity"
 	Setup(specName, func(testSpecificSettingsGetter func() testSettings) {
 		var namespace *corev1.Namespace
@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"fmt"
+
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/framework"
+	. "sigs.k8s.io/cluster-api/test/framework/ginkgoextensions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// capvProviderName is the value of the provider label clusterctl sets on all CAPV components.
+	capvProviderName = "infrastructure-vsphere"
+
+	// conversionCheckLabel is written through every served non-storage API version to verify
+	// the conversion webhooks are able to round-trip the objects created by the previous release.
+	conversionCheckLabel = "e2e.infrastructure.cluster.x-k8s.io/conversion-check"
+)
+
+// verifyCAPVAPIMigration is used as PostUpgrade hook of the clusterctl upgrade specs.
+// It verifies that after the provider upgrade:
+//   - the stored versions of all CAPV CRDs have been migrated to the current storage version,
+//   - all objects are readable and writable through every served API version (conversion webhooks),
+//   - the VSphereClusters and VSphereMachines created by the previous release are still ready.
+//
+// Note: the last parameter is the name of the management cluster and is not used.
+func verifyCAPVAPIMigration(managementClusterProxy framework.ClusterProxy, clusterNamespace, _ string) {
+	c := managementClusterProxy.GetClient()
+
+	crds := &apiextensionsv1.CustomResourceDefinitionList{}
+	Expect(c.List(ctx, crds, client.MatchingLabels{clusterv1.ProviderNameLabel: capvProviderName})).To(Succeed())
+	Expect(crds.Items).ToNot(BeEmpty(), "failed to find the CRDs of the CAPV provider")
+
+	for i := range crds.Items {
+		crd := &crds.Items[i]
+		storageVersion := crdStorageVersion(crd)
+		Expect(storageVersion).ToNot(BeEmpty(), "CRD %s has no storage version", crd.Name)
+
+		Byf("Verifying the stored versions of CRD %s have been migrated to %s", crd.Name, storageVersion)
+		Expect(crd.Status.StoredVersions).To(ConsistOf(storageVersion),
+			"CRD %s still has objects stored in versions other than %s", crd.Name, storageVersion)
+
+		verifyConversion(c, crd, storageVersion, clusterNamespace)
+	}
+
+	for _, kind := range []string{"VSphereCluster", "VSphereMachine"} {
+		kindCRDs := crdsForKind(crds.Items, kind)
+		Expect(kindCRDs).ToNot(BeEmpty(), "failed to find the CRD for %s", kind)
+
+		Byf("Verifying all %ss in namespace %s are still ready after the upgrade", kind, clusterNamespace)
+		Eventually(func(g Gomega) {
+			found := 0
+			for _, crd := range kindCRDs {
+				list := listObjects(g, c, crd, crdStorageVersion(crd), clusterNamespace)
+				for _, obj := range list.Items {
+					ready, _, err := unstructured.NestedBool(obj.Object, "status", "ready")
+					g.Expect(err).ToNot(HaveOccurred())
+					g.Expect(ready).To(BeTrue(), "%s %s is not ready", kind, obj.GetName())
+					found++
+				}
+			}
+			g.Expect(found).ToNot(BeZero(), "failed to find any %s in namespace %s", kind, clusterNamespace)
+		}, "3m", "10s").Should(Succeed())
+	}
+}
+
+// verifyConversion verifies all objects of the given CRD can be read and written through every served version.
+func verifyConversion(c client.Client, crd *apiextensionsv1.CustomResourceDefinition, storageVersion, namespace string) {
+	stored := listObjects(Default, c, crd, storageVersion, namespace)
+	storedUIDs := sets.New[types.UID]()
+	for _, obj := range stored.Items {
+		storedUIDs.Insert(obj.GetUID())
+	}
+
+	for _, version := range crd.Spec.Versions {
+		if !version.Served || version.Name == storageVersion {
+			continue
+		}
+
+		Byf("Verifying %s objects can be converted to and from %s", crd.Spec.Names.Kind, version.Name)
+		converted := listObjects(Default, c, crd, version.Name, namespace)
+		convertedUIDs := sets.New[types.UID]()
+		for _, obj := range converted.Items {
+			convertedUIDs.Insert(obj.GetUID())
+		}
+		Expect(convertedUIDs.Equal(storedUIDs)).To(BeTrue(),
+			"listing %s in version %s returned different objects than the storage version %s", crd.Spec.Names.Plural, version.Name, storageVersion)
+
+		for i := range converted.Items {
+			obj := &converted.Items[i]
+			before := getObject(c, crd, storageVersion, obj)
+
+			patch := fmt.Sprintf(`{"metadata":{"labels":{%q:%q}}}`, conversionCheckLabel, version.Name)
+			Expect(c.Patch(ctx, obj, client.RawPatch(types.MergePatchType, []byte(patch)))).To(Succeed(),
+				"failed to patch %s %s through version %s", crd.Spec.Names.Kind, obj.GetName(), version.Name)
+
+			after := getObject(c, crd, storageVersion, obj)
+			Expect(after.GetLabels()).To(HaveKeyWithValue(conversionCheckLabel, version.Name))
+			Expect(after.Object["spec"]).To(Equal(before.Object["spec"]),
+				"writing %s %s through version %s changed its spec", crd.Spec.Names.Kind, obj.GetName(), version.Name)
+		}
+	}
+}
+
+func listObjects(g Gomega, c client.Client, crd *apiextensionsv1.CustomResourceDefinition, version, namespace string) *unstructured.UnstructuredList {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(schema.GroupVersionKind{Group: crd.Spec.Group, Version: version, Kind: crd.Spec.Names.ListKind})
+
+	var opts []client.ListOption
+	if crd.Spec.Scope == apiextensionsv1.NamespaceScoped {
+		opts = append(opts, client.InNamespace(namespace))
+	}
+	g.Expect(c.List(ctx, list, opts...)).To(Succeed(), "failed to list %s in version %s", crd.Spec.Names.Plural, version)
+	return list
+}
+
+func getObject(c client.Client, crd *apiextensionsv1.CustomResourceDefinition, version string, obj client.Object) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(schema.GroupVersionKind{Group: crd.Spec.Group, Version: version, Kind: crd.Spec.Names.Kind})
+	Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), u)).To(Succeed())
+	return u
+}
+
+func crdStorageVersion(crd *apiextensionsv1.CustomResourceDefinition) string {
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			return version.Name
+		}
+	}
+	return ""
+}
+
+// crdsForKind returns the CRDs for kind. Both the govmomi and the supervisor API groups define
+// a VSphereCluster and a VSphereMachine.
+func crdsForKind(crds []apiextensionsv1.CustomResourceDefinition, kind string) []*apiextensionsv1.CustomResourceDefinition {
+	var res []*apiextensionsv1.CustomResourceDefinition
+	for i := range crds {
+		if crds[i].Spec.Names.Kind == kind {
+			res = append(res, &crds[i])
+		}
+	}
+	return res
+}
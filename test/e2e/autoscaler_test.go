@@ -17,38 +17,209 @@ limitations under the License.
 package e2e
 
 import (
+	"context"
+
 	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capi_e2e "sigs.k8s.io/cluster-api/test/e2e"
 	"sigs.k8s.io/cluster-api/test/framework"
+	. "sigs.k8s.io/cluster-api/test/framework/ginkgoextensions"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-var _ = Describe("When using the autoscaler with Cluster API using ClusterClass and scale to zero [supervisor] [ClusterClass]", func() {
+// autoscalerPreTerminateHook is the pre-terminate delete hook added to the VSphereMachine of
+// a Machine scaled down by the autoscaler to verify that its VM is kept until the hook is removed.
+const autoscalerPreTerminateHook = clusterv1.PreTerminateDeleteHookAnnotationPrefix + "/e2e-autoscaler"
+
+var _ = DescribeWithFlavors("When using the autoscaler with Cluster API using ClusterClass and scale to zero [supervisor] [ClusterClass]", func() {
 	const specName = "autoscaler" // aligned to CAPI
 	Setup(specName, func(testSpecificSettingsGetter func() testSettings) {
 		capi_e2e.AutoscalerSpec(ctx, func() capi_e2e.AutoscalerSpecInput {
+			infrastructureAPIGroup := "infrastructure.cluster.x-k8s.io"
+			if testMode == SupervisorTestMode {
+				infrastructureAPIGroup = "vmware.infrastructure.cluster.x-k8s.io"
+			}
 			return capi_e2e.AutoscalerSpecInput{
+				E2EConfig:                         e2eConfig,
+				ClusterctlConfigPath:              testSpecificSettingsGetter().ClusterctlConfigPath,
+				BootstrapClusterProxy:             bootstrapClusterProxy,
+				ArtifactFolder:                    artifactFolder,
+				SkipCleanup:                       skipCleanup,
+				Flavor:                            ptr.To(testSpecificSettingsGetter().FlavorForMode("topology-autoscaler")),
+				PostNamespaceCreated:              testSpecificSettingsGetter().PostNamespaceCreatedFunc,
+				InfrastructureAPIGroup:            infrastructureAPIGroup,
+				InfrastructureMachineTemplateKind: "vspheremachinetemplates",
+				AutoscalerVersion:                 "v1.31.0",
+				ScaleToAndFromZero:                true,
+				// We have no connectivity from the workload cluster to the kind management cluster in CI so we
+				// can't deploy the autoscaler to the workload cluster.
+				InstallOnManagementCluster: true,
+			}
+		})
+	})
+})
+
+// The in-memory workload clusters backing vcsim have no scheduler, so pods never become unschedulable
+// and the autoscaler never scales. This spec performs the same operations on the MachineDeployment
+// the autoscaler does instead, to verify CAPV behaves as the autoscaler expects.
+var _ = DescribeWithFlavors("When scaling a MachineDeployment like the autoscaler [vcsim] [supervisor] [ClusterClass]", func() {
+	const specName = "autoscaler-operations"
+	Setup(specName, func(testSpecificSettingsGetter func() testSettings) {
+		capi_e2e.QuickStartSpec(ctx, func() capi_e2e.QuickStartSpecInput {
+			return capi_e2e.QuickStartSpecInput{
 				E2EConfig:             e2eConfig,
 				ClusterctlConfigPath:  testSpecificSettingsGetter().ClusterctlConfigPath,
 				BootstrapClusterProxy: bootstrapClusterProxy,
 				ArtifactFolder:        artifactFolder,
 				SkipCleanup:           skipCleanup,
 				Flavor:                ptr.To(testSpecificSettingsGetter().FlavorForMode("topology-autoscaler")),
-				PostNamespaceCreated: func(managementClusterProxy framework.ClusterProxy, workloadClusterNamespace string) {
-					if testMode == GovmomiTestMode {
-						// This test is only implemented for supervisor
-						Skip("This test is only implemented for supervisor")
-					}
-					testSpecificSettingsGetter().PostNamespaceCreatedFunc(managementClusterProxy, workloadClusterNamespace)
+				PostNamespaceCreated:  testSpecificSettingsGetter().PostNamespaceCreatedFunc,
+				PostMachinesProvisioned: func(managementClusterProxy framework.ClusterProxy, namespace, clusterName string) {
+					verifyAutoscalerOperations(ctx, managementClusterProxy, specName, namespace, clusterName)
 				},
-				InfrastructureAPIGroup:            "vmware.infrastructure.cluster.x-k8s.io",
-				InfrastructureMachineTemplateKind: "vspheremachinetemplates",
-				AutoscalerVersion:                 "v1.31.0",
-				ScaleToAndFromZero:                true,
-				// We have no connectivity from the workload cluster to the kind management cluster in CI so we
-				// can't deploy the autoscaler to the workload cluster.
-				InstallOnManagementCluster: true,
+				ControlPlaneMachineCount: ptr.To[int64](1),
 			}
 		})
 	})
 })
+
+// verifyAutoscalerOperations verifies the capacity published for scaling from zero, then scales the
+// MachineDeployment of the cluster up and down the way the autoscaler does.
+func verifyAutoscalerOperations(ctx context.Context, proxy framework.ClusterProxy, specName, namespace, clusterName string) {
+	mgmtClient := proxy.GetClient()
+
+	cluster := framework.GetClusterByName(ctx, framework.GetClusterByNameInput{
+		Getter:    mgmtClient,
+		Name:      clusterName,
+		Namespace: namespace,
+	})
+	mds := framework.GetMachineDeploymentsByCluster(ctx, framework.GetMachineDeploymentsByClusterInput{
+		Lister:      mgmtClient,
+		ClusterName: clusterName,
+		Namespace:   namespace,
+	})
+	Expect(mds).To(HaveLen(1), "The autoscaler flavor is expected to have exactly one MachineDeployment")
+	md := mds[0]
+	Expect(md.Spec.Replicas).ToNot(BeNil())
+
+	By("Verifying the capacity of the VSphereMachineTemplate is published for scaling from zero")
+	templateRef := md.Spec.Template.Spec.InfrastructureRef
+	Eventually(func(g Gomega) {
+		template := &unstructured.Unstructured{}
+		template.SetAPIVersion(templateRef.APIVersion)
+		template.SetKind(templateRef.Kind)
+		g.Expect(mgmtClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: templateRef.Name}, template)).To(Succeed())
+
+		capacity, _, err := unstructured.NestedStringMap(template.Object, "status", "capacity")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(capacity).To(HaveKey(string(corev1.ResourceCPU)))
+		g.Expect(capacity).To(HaveKey(string(corev1.ResourceMemory)))
+	}, e2eConfig.GetIntervals(specName, "wait-controllers")...).Should(Succeed())
+
+	replicas := *md.Spec.Replicas
+
+	Byf("Scaling up MachineDeployment %s from %d to %d replicas", klog.KObj(md), replicas, replicas+1)
+	scaleMachineDeployment(ctx, mgmtClient, md, replicas+1, e2eConfig.GetIntervals(specName, "wait-controllers")...)
+	framework.WaitForMachineDeploymentNodesToExist(ctx, framework.WaitForMachineDeploymentNodesToExistInput{
+		Lister:            mgmtClient,
+		Cluster:           cluster,
+		MachineDeployment: md,
+	}, e2eConfig.GetIntervals(specName, "wait-worker-nodes")...)
+
+	machines := framework.GetMachinesByMachineDeployments(ctx, framework.GetMachinesByMachineDeploymentsInput{
+		Lister:            mgmtClient,
+		ClusterName:       clusterName,
+		Namespace:         namespace,
+		MachineDeployment: *md,
+	})
+	Expect(machines).ToNot(BeEmpty())
+	machine := machines[0]
+	infraRef := machine.Spec.InfrastructureRef
+	infraMachineKey := client.ObjectKey{Namespace: namespace, Name: infraRef.Name}
+	getInfraMachine := func(g Gomega) *unstructured.Unstructured {
+		infraMachine := &unstructured.Unstructured{}
+		infraMachine.SetAPIVersion(infraRef.APIVersion)
+		infraMachine.SetKind(infraRef.Kind)
+		g.Expect(mgmtClient.Get(ctx, infraMachineKey, infraMachine)).To(Succeed())
+		return infraMachine
+	}
+
+	Byf("Adding the pre-terminate delete hook %s to %s %s", autoscalerPreTerminateHook, infraRef.Kind, klog.KRef(namespace, infraRef.Name))
+	Eventually(func(g Gomega) {
+		infraMachine := getInfraMachine(g)
+		annotations := infraMachine.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[autoscalerPreTerminateHook] = ""
+		infraMachine.SetAnnotations(annotations)
+		g.Expect(mgmtClient.Update(ctx, infraMachine)).To(Succeed())
+	}, e2eConfig.GetIntervals(specName, "wait-controllers")...).Should(Succeed())
+
+	// The autoscaler marks the Machine of the Node it removes for deletion before scaling down.
+	Byf("Marking Machine %s for deletion", klog.KObj(&machine))
+	Eventually(func(g Gomega) {
+		m := &clusterv1.Machine{}
+		g.Expect(mgmtClient.Get(ctx, client.ObjectKeyFromObject(&machine), m)).To(Succeed())
+		patchHelper, err := patch.NewHelper(m, mgmtClient)
+		g.Expect(err).ToNot(HaveOccurred())
+		if m.Annotations == nil {
+			m.Annotations = map[string]string{}
+		}
+		m.Annotations[clusterv1.DeleteMachineAnnotation] = ""
+		g.Expect(patchHelper.Patch(ctx, m)).To(Succeed())
+	}, e2eConfig.GetIntervals(specName, "wait-controllers")...).Should(Succeed())
+
+	Byf("Scaling down MachineDeployment %s from %d to %d replicas", klog.KObj(md), replicas+1, replicas)
+	scaleMachineDeployment(ctx, mgmtClient, md, replicas, e2eConfig.GetIntervals(specName, "wait-controllers")...)
+
+	By("Verifying the VM is kept while the pre-terminate delete hook exists")
+	Eventually(func(g Gomega) {
+		infraMachine := getInfraMachine(g)
+		g.Expect(infraMachine.GetDeletionTimestamp()).ToNot(BeNil())
+		getter := conditions.UnstructuredGetter(infraMachine)
+		g.Expect(conditions.IsFalse(getter, clusterv1.PreTerminateDeleteHookSucceededCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(getter, clusterv1.PreTerminateDeleteHookSucceededCondition)).To(Equal(clusterv1.WaitingExternalHookReason))
+	}, e2eConfig.GetIntervals(specName, "wait-machine-deleted")...).Should(Succeed())
+
+	Byf("Removing the pre-terminate delete hook %s", autoscalerPreTerminateHook)
+	Eventually(func(g Gomega) {
+		infraMachine := getInfraMachine(g)
+		annotations := infraMachine.GetAnnotations()
+		delete(annotations, autoscalerPreTerminateHook)
+		infraMachine.SetAnnotations(annotations)
+		g.Expect(mgmtClient.Update(ctx, infraMachine)).To(Succeed())
+	}, e2eConfig.GetIntervals(specName, "wait-controllers")...).Should(Succeed())
+
+	Byf("Waiting for Machine %s to be deleted", klog.KObj(&machine))
+	Eventually(func() bool {
+		err := mgmtClient.Get(ctx, client.ObjectKeyFromObject(&machine), &clusterv1.Machine{})
+		return apierrors.IsNotFound(err)
+	}, e2eConfig.GetIntervals(specName, "wait-machine-deleted")...).Should(BeTrue())
+
+	framework.WaitForMachineDeploymentNodesToExist(ctx, framework.WaitForMachineDeploymentNodesToExistInput{
+		Lister:            mgmtClient,
+		Cluster:           cluster,
+		MachineDeployment: md,
+	}, e2eConfig.GetIntervals(specName, "wait-worker-nodes")...)
+}
+
+// scaleMachineDeployment sets the replicas of the MachineDeployment like the autoscaler does.
+// The replicas are not managed by the topology because the autoscaler flavor doesn't set them.
+func scaleMachineDeployment(ctx context.Context, c client.Client, md *clusterv1.MachineDeployment, replicas int32, intervals ...interface{}) {
+	Eventually(func(g Gomega) {
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(md), md)).To(Succeed())
+		patchHelper, err := patch.NewHelper(md, c)
+		g.Expect(err).ToNot(HaveOccurred())
+		md.Spec.Replicas = ptr.To(replicas)
+		g.Expect(patchHelper.Patch(ctx, md)).To(Succeed())
+	}, intervals...).Should(Succeed())
+}
@@ -17,11 +17,10 @@ limitations under the License.
 package e2e
 
 import (
-	. "github.com/onsi/ginkgo/v2"
 	capie2e "sigs.k8s.io/cluster-api/test/e2e"
 )
 
-var _ = Describe("When testing ClusterClass changes [vcsim] [supervisor] [ClusterClass]", func() {
+var _ = DescribeWithFlavors("When testing ClusterClass changes [vcsim] [supervisor] [ClusterClass]", func() {
 	const specName = "clusterclass-changes" // copied from CAPI
 	Setup(specName, func(testSpecificSettingsGetter func() testSettings) {
 		capie2e.ClusterClassChangesSpec(ctx, func() capie2e.ClusterClassChangesSpecInput {
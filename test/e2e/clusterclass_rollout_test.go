@@ -17,7 +17,6 @@ limitations under the License.
 package e2e
 
 import (
-	. "github.com/onsi/ginkgo/v2"
 	"k8s.io/utils/ptr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capie2e "sigs.k8s.io/cluster-api/test/e2e"
@@ -26,7 +25,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/constants"
 )
 
-var _ = Describe("When testing ClusterClass rollouts [vcsim] [supervisor] [ClusterClass]", func() {
+var _ = DescribeWithFlavors("When testing ClusterClass rollouts [vcsim] [supervisor] [ClusterClass]", func() {
 	const specName = "clusterclass-rollouts" // copied from CAPI
 	Setup(specName, func(testSpecificSettingsGetter func() testSettings) {
 		capie2e.ClusterClassRolloutSpec(ctx, func() capie2e.ClusterClassRolloutSpecInput {
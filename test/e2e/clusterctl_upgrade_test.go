@@ -20,7 +20,6 @@ import (
 	"context"
 	"fmt"
 
-	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	capi_e2e "sigs.k8s.io/cluster-api/test/e2e"
 	"sigs.k8s.io/cluster-api/test/framework"
@@ -40,7 +39,7 @@ var (
 )
 
 // Note: This test should be changed during "prepare main branch", it should test CAPV n-1 => current (and then corresponding CAPI versions if already available).
-var _ = Describe("When testing clusterctl upgrades using ClusterClass (CAPV 1.12=>current, CAPI 1.9=>1.10) on K8S latest ci mgmt cluster [vcsim] [supervisor] [ClusterClass]", func() {
+var _ = DescribeWithFlavors("When testing clusterctl upgrades using ClusterClass (CAPV 1.12=>current, CAPI 1.9=>1.10) on K8S latest ci mgmt cluster [vcsim] [supervisor] [ClusterClass]", func() {
 	const specName = "clusterctl-upgrade-1.12-current-latest-ci" // prefix (clusterctl-upgrade) copied from CAPI
 	Setup(specName, func(testSpecificSettingsGetter func() testSettings) {
 		capi_e2e.ClusterctlUpgradeSpec(ctx, func() capi_e2e.ClusterctlUpgradeSpecInput {
@@ -77,6 +76,9 @@ var _ = Describe("When testing clusterctl upgrades using ClusterClass (CAPV 1.12
 				// below otherwise there will be no VCSim instance created in the management cluster.
 				UseKindForManagementCluster:              true,
 				KindManagementClusterNewClusterProxyFunc: kindManagementClusterNewClusterProxyFunc,
+				// Verify CRD storage version migration, conversion webhooks and the health of the
+				// clusters created by the previous release.
+				PostUpgrade: verifyCAPVAPIMigration,
 			}
 		})
 	},
@@ -87,7 +89,7 @@ var _ = Describe("When testing clusterctl upgrades using ClusterClass (CAPV 1.12
 })
 
 // Note: This test should be changed during "prepare main branch", it should test CAPV n-1 => current (and then corresponding CAPI versions if already available).
-var _ = Describe("When testing clusterctl upgrades using ClusterClass (CAPV 1.12=>current, CAPI 1.9=>1.10) [vcsim] [supervisor] [ClusterClass]", func() {
+var _ = DescribeWithFlavors("When testing clusterctl upgrades using ClusterClass (CAPV 1.12=>current, CAPI 1.9=>1.10) [vcsim] [supervisor] [ClusterClass]", func() {
 	const specName = "clusterctl-upgrade-1.12-current" // prefix (clusterctl-upgrade) copied from CAPI
 	Setup(specName, func(testSpecificSettingsGetter func() testSettings) {
 		capi_e2e.ClusterctlUpgradeSpec(ctx, func() capi_e2e.ClusterctlUpgradeSpecInput {
@@ -122,6 +124,9 @@ var _ = Describe("When testing clusterctl upgrades using ClusterClass (CAPV 1.12
 				// below otherwise there will be no VCSim instance created in the management cluster.
 				UseKindForManagementCluster:              true,
 				KindManagementClusterNewClusterProxyFunc: kindManagementClusterNewClusterProxyFunc,
+				// Verify CRD storage version migration, conversion webhooks and the health of the
+				// clusters created by the previous release.
+				PostUpgrade: verifyCAPVAPIMigration,
 			}
 		})
 	},
@@ -132,7 +137,7 @@ var _ = Describe("When testing clusterctl upgrades using ClusterClass (CAPV 1.12
 })
 
 // Note: This test should be changed during "prepare main branch", it should test CAPV n-2 => current (and then corresponding CAPI versions if already available).
-var _ = Describe("When testing clusterctl upgrades using ClusterClass (CAPV 1.11=>current, CAPI 1.8=>1.10) [vcsim] [supervisor] [ClusterClass]", func() {
+var _ = DescribeWithFlavors("When testing clusterctl upgrades using ClusterClass (CAPV 1.11=>current, CAPI 1.8=>1.10) [vcsim] [supervisor] [ClusterClass]", func() {
 	const specName = "clusterctl-upgrade-1.11-current" // prefix (clusterctl-upgrade) copied from CAPI
 	Setup(specName, func(testSpecificSettingsGetter func() testSettings) {
 		capi_e2e.ClusterctlUpgradeSpec(ctx, func() capi_e2e.ClusterctlUpgradeSpecInput {
@@ -167,6 +172,9 @@ var _ = Describe("When testing clusterctl upgrades using ClusterClass (CAPV 1.11
 				// below otherwise there will be no VCSim instance created in the management cluster.
 				UseKindForManagementCluster:              true,
 				KindManagementClusterNewClusterProxyFunc: kindManagementClusterNewClusterProxyFunc,
+				// Verify CRD storage version migration, conversion webhooks and the health of the
+				// clusters created by the previous release.
+				PostUpgrade: verifyCAPVAPIMigration,
 			}
 		})
 	},
@@ -177,7 +185,7 @@ var _ = Describe("When testing clusterctl upgrades using ClusterClass (CAPV 1.11
 })
 
 // Note: This test should be changed during "prepare main branch", it should test CAPV n-3 => current (and then corresponding CAPI versions if already available).
-var _ = Describe("When testing clusterctl upgrades using ClusterClass (CAPV 1.10=>current, CAPI 1.7=>1.10) [vcsim] [supervisor] [ClusterClass]", func() {
+var _ = DescribeWithFlavors("When testing clusterctl upgrades using ClusterClass (CAPV 1.10=>current, CAPI 1.7=>1.10) [vcsim] [supervisor] [ClusterClass]", func() {
 	const specName = "clusterctl-upgrade-1.10-current" // prefix (clusterctl-upgrade) copied from CAPI
 	Setup(specName, func(testSpecificSettingsGetter func() testSettings) {
 		capi_e2e.ClusterctlUpgradeSpec(ctx, func() capi_e2e.ClusterctlUpgradeSpecInput {
@@ -212,6 +220,9 @@ var _ = Describe("When testing clusterctl upgrades using ClusterClass (CAPV 1.10
 				// below otherwise there will be no VCSim instance created in the management cluster.
 				UseKindForManagementCluster:              true,
 				KindManagementClusterNewClusterProxyFunc: kindManagementClusterNewClusterProxyFunc,
+				// Verify CRD storage version migration, conversion webhooks and the health of the
+				// clusters created by the previous release.
+				PostUpgrade: verifyCAPVAPIMigration,
 			}
 		})
 	},
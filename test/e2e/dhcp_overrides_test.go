@@ -50,7 +50,7 @@ type DHCPOverrides struct {
 	SendHostname *bool `yaml:"send-hostname"`
 }
 
-var _ = Describe("DHCPOverrides configuration test", func() {
+var _ = DescribeWithFlavors("DHCPOverrides configuration test", func() {
 	When("Creating a cluster with DHCPOverrides configured", func() {
 		const specName = "dhcp-overrides"
 		Setup("dhcp-overrides", func(testSpecificSettingsGetter func() testSettings) {
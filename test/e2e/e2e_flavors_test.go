@@ -0,0 +1,194 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	"sigs.k8s.io/cluster-api/test/framework"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// InfrastructureFlavorsVariable is the e2e config variable defining the matrix of infrastructure flavors
+	// the specs are run with in a single ginkgo invocation, e.g. "govmomi-vcenter,govmomi-vcsim".
+	// Every infrastructure flavor has the format <test mode>-<test target>.
+	InfrastructureFlavorsVariable = "E2E_INFRASTRUCTURE_FLAVORS"
+)
+
+// Test suite infrastructure flavor vars.
+var (
+	// infrastructureFlavors are the infrastructure flavors the specs are run with.
+	// If no flavor matrix is defined, it only contains the flavor detected from the focus of the test run.
+	infrastructureFlavors []infrastructureFlavor
+
+	// flavorMatrixEnabled is true if the infrastructure flavors are defined by a flavor matrix.
+	flavorMatrixEnabled bool
+
+	// bootstrapClusterProxies are the proxies to the bootstrap cluster for every test target.
+	bootstrapClusterProxies map[string]framework.ClusterProxy
+)
+
+// infrastructureFlavor is the combination of a test mode and a test target the specs are run with.
+type infrastructureFlavor struct {
+	Mode   string
+	Target string
+}
+
+func (f infrastructureFlavor) String() string {
+	return fmt.Sprintf("%s-%s", f.Mode, f.Target)
+}
+
+// supports returns true if the specs with the given text can be run with the infrastructure flavor.
+// Specs opt in to the supervisor test mode and to the vcsim test target using the [supervisor] and [vcsim] tags.
+func (f infrastructureFlavor) supports(text string) bool {
+	if f.Mode == SupervisorTestMode && !strings.Contains(text, "[supervisor]") {
+		return false
+	}
+	if f.Target == VCSimTestTarget && !strings.Contains(text, "[vcsim]") {
+		return false
+	}
+	return true
+}
+
+// loadInfrastructureFlavors loads the flavor matrix from the environment or from the e2e config files,
+// with later config files taking precedence over earlier ones.
+// NOTE: The flavor matrix has to be known when building the spec tree, which happens before the e2e config
+// is loaded by the test suite.
+func loadInfrastructureFlavors(configPaths ...string) ([]infrastructureFlavor, error) {
+	// Like for all the e2e config variables, environment variables take precedence.
+	matrix, ok := os.LookupEnv(InfrastructureFlavorsVariable)
+	if !ok {
+		for _, configPath := range configPaths {
+			if configPath == "" {
+				continue
+			}
+			data, err := os.ReadFile(configPath) //nolint:gosec
+			if err != nil {
+				return nil, fmt.Errorf("failed to read e2e config %q: %w", configPath, err)
+			}
+			config := struct {
+				Variables map[string]string `json:"variables,omitempty"`
+			}{}
+			if err := yaml.Unmarshal(data, &config); err != nil {
+				return nil, fmt.Errorf("failed to parse e2e config %q: %w", configPath, err)
+			}
+			if value, ok := config.Variables[InfrastructureFlavorsVariable]; ok {
+				matrix = value
+			}
+		}
+	}
+	return parseInfrastructureFlavors(matrix)
+}
+
+// parseInfrastructureFlavors parses a comma-separated list of infrastructure flavors.
+func parseInfrastructureFlavors(matrix string) ([]infrastructureFlavor, error) {
+	flavors := []infrastructureFlavor{}
+	for _, value := range strings.Split(matrix, ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		mode, target, _ := strings.Cut(value, "-")
+		if mode != GovmomiTestMode && mode != SupervisorTestMode {
+			return nil, fmt.Errorf("invalid infrastructure flavor %q: test mode must be one of %q, %q", value, GovmomiTestMode, SupervisorTestMode)
+		}
+		if target != VCenterTestTarget && target != VCSimTestTarget {
+			return nil, fmt.Errorf("invalid infrastructure flavor %q: test target must be one of %q, %q", value, VCenterTestTarget, VCSimTestTarget)
+		}
+		flavor := infrastructureFlavor{Mode: mode, Target: target}
+		if !slices.Contains(flavors, flavor) {
+			flavors = append(flavors, flavor)
+		}
+	}
+
+	// The vsphere provider is installed into the bootstrap cluster either using the govmomi or using the
+	// supervisor manifests, so all the infrastructure flavors of a single test run must use the same test mode.
+	for _, flavor := range flavors {
+		if flavor.Mode != flavors[0].Mode {
+			return nil, fmt.Errorf("invalid infrastructure flavors %q: all the infrastructure flavors must use the same test mode", matrix)
+		}
+	}
+	return flavors, nil
+}
+
+// infrastructureFlavorTestModes returns the test modes of the infrastructure flavors.
+func infrastructureFlavorTestModes() []string {
+	modes := []string{}
+	for _, flavor := range infrastructureFlavors {
+		if !slices.Contains(modes, flavor.Mode) {
+			modes = append(modes, flavor.Mode)
+		}
+	}
+	return modes
+}
+
+// infrastructureFlavorTestTargets returns the test targets of the infrastructure flavors.
+func infrastructureFlavorTestTargets() []string {
+	targets := []string{}
+	for _, flavor := range infrastructureFlavors {
+		if !slices.Contains(targets, flavor.Target) {
+			targets = append(targets, flavor.Target)
+		}
+	}
+	return targets
+}
+
+// hasTestMode returns true if any of the infrastructure flavors uses the given test mode.
+func hasTestMode(mode string) bool {
+	return slices.Contains(infrastructureFlavorTestModes(), mode)
+}
+
+// hasTestTarget returns true if any of the infrastructure flavors uses the given test target.
+func hasTestTarget(target string) bool {
+	return slices.Contains(infrastructureFlavorTestTargets(), target)
+}
+
+// useInfrastructureFlavor sets up the test suite vars for running a spec with the given infrastructure flavor.
+func useInfrastructureFlavor(flavor infrastructureFlavor) {
+	testMode = flavor.Mode
+	testTarget = flavor.Target
+	bootstrapClusterProxy = bootstrapClusterProxies[flavor.Target]
+}
+
+// DescribeWithFlavors is like Describe, but if a flavor matrix is defined it runs the specs once
+// with every infrastructure flavor of the matrix which is supported by the specs.
+// If no flavor matrix is defined, the specs are run with the infrastructure flavor detected from the focus.
+func DescribeWithFlavors(text string, body func()) bool {
+	return Describe(text, func() {
+		if !flavorMatrixEnabled {
+			body()
+			return
+		}
+
+		for _, flavor := range infrastructureFlavors {
+			if !flavor.supports(text) {
+				continue
+			}
+			Context(fmt.Sprintf("[flavor:%s]", flavor), func() {
+				BeforeEach(func() {
+					useInfrastructureFlavor(flavor)
+				})
+				body()
+			})
+		}
+	})
+}
@@ -143,7 +143,13 @@ func Setup(specName string, f func(testSpecificSettings func() testSettings), op
 
 		// Create a new clusterctl config file based on the passed file. The postNamespaceCreatedFunc
 		// may re-write the file to add some variables, but it needs to exist already before that.
-		testSpecificClusterctlConfigPath = fmt.Sprintf("%s-%s.yaml", strings.TrimSuffix(clusterctlConfigPath, ".yaml"), specName)
+		// When running with a flavor matrix, the same spec runs once per infrastructure flavor, so the
+		// infrastructure flavor is added to the file name to avoid specs overwriting each other's config.
+		testSpecificConfigName := specName
+		if flavorMatrixEnabled {
+			testSpecificConfigName = fmt.Sprintf("%s-%s-%s", specName, testMode, testTarget)
+		}
+		testSpecificClusterctlConfigPath = fmt.Sprintf("%s-%s.yaml", strings.TrimSuffix(clusterctlConfigPath, ".yaml"), testSpecificConfigName)
 		Byf("Writing a new clusterctl config to %s", testSpecificClusterctlConfigPath)
 		copyAndAmendClusterctlConfig(ctx, copyAndAmendClusterctlConfigInput{
 			ClusterctlConfigPath: clusterctlConfigPath,
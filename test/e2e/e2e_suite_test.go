@@ -175,6 +175,19 @@ func TestE2E(t *testing.T) {
 		testMode = SupervisorTestMode
 	}
 
+	// Detect the infrastructure flavor matrix; if defined, it takes precedence over the test target and the
+	// test mode detected from the focus.
+	flavors, err := loadInfrastructureFlavors(configPath, configOverridesPath)
+	g.Expect(err).ToNot(HaveOccurred(), "Invalid %s", InfrastructureFlavorsVariable)
+	if len(flavors) > 0 {
+		flavorMatrixEnabled = true
+		infrastructureFlavors = flavors
+		testMode = flavors[0].Mode
+		testTarget = flavors[0].Target
+	} else {
+		infrastructureFlavors = []infrastructureFlavor{{Mode: testMode, Target: testTarget}}
+	}
+
 	RunSpecs(t, "capv-e2e", suiteConfig, reporterConfig)
 }
 
@@ -184,6 +197,9 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 	// Before all ParallelNodes.
 	Byf("TestTarget: %s\n", testTarget)
 	Byf("TestMode: %s\n", testMode)
+	if flavorMatrixEnabled {
+		Byf("InfrastructureFlavors: %s\n", infrastructureFlavors)
+	}
 
 	Expect(configPath).To(BeAnExistingFile(), "Invalid test suite argument. e2e.config should be an existing file.")
 	Expect(os.MkdirAll(artifactFolder, 0755)).To(Succeed(), "Invalid test suite argument. Can't create e2e.artifacts-folder %q", artifactFolder) //nolint:gosec // Non-production code
@@ -193,7 +209,7 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 
 	Byf("Loading the e2e test configuration from %q", configPath)
 	var err error
-	e2eConfig, err = vsphereframework.LoadE2EConfig(ctx, configPath, configOverridesPath, testTarget, testMode)
+	e2eConfig, err = vsphereframework.LoadE2EConfig(ctx, configPath, configOverridesPath, infrastructureFlavorTestTargets(), infrastructureFlavorTestModes())
 	Expect(err).NotTo(HaveOccurred())
 
 	Byf("Creating a clusterctl local repository into %q", artifactFolder)
@@ -207,7 +223,7 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 	By("Initializing the bootstrap cluster")
 	vsphereframework.InitBootstrapCluster(ctx, bootstrapClusterProxy, e2eConfig, clusterctlConfigPath, artifactFolder)
 
-	if testTarget == VCSimTestTarget {
+	if hasTestTarget(VCSimTestTarget) {
 		createVCSimServer(bootstrapClusterProxy)
 	}
 
@@ -247,7 +263,7 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 
 	namespaces = map[*corev1.Namespace]context.CancelFunc{}
 
-	if testTarget == VCenterTestTarget {
+	if hasTestTarget(VCenterTestTarget) {
 		// Some of the tests targeting VCenter relies on an additional VSphere session to check test progress;
 		// such session is create once, and shared across many tests.
 		// Some changes will be requires to get this working with vcsim e.g. about how to get the credentials/vCenter info,
@@ -256,25 +272,26 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 	}
 
 	var err error
-	e2eConfig, err = vsphereframework.LoadE2EConfig(ctx, configPath, configOverridesPath, testTarget, testMode)
+	e2eConfig, err = vsphereframework.LoadE2EConfig(ctx, configPath, configOverridesPath, infrastructureFlavorTestTargets(), infrastructureFlavorTestModes())
 	Expect(err).NotTo(HaveOccurred())
 
-	clusterProxyOptions := []framework.Option{}
-	// vspherelog.MachineLogCollector tries to ssh to the machines to collect logs.
-	// This does not work when using vcsim because there are no real machines running ssh.
-	if testTarget != VCSimTestTarget {
-		clusterProxyOptions = append(clusterProxyOptions, framework.WithMachineLogCollector(&vspherelog.MachineLogCollector{
+	// Create a proxy to the bootstrap cluster for every test target; specs pick the one matching their
+	// infrastructure flavor.
+	bootstrapClusterProxies = map[string]framework.ClusterProxy{}
+	if hasTestTarget(VCenterTestTarget) {
+		// vspherelog.MachineLogCollector tries to ssh to the machines to collect logs.
+		// This does not work when using vcsim because there are no real machines running ssh.
+		bootstrapClusterProxies[VCenterTestTarget] = framework.NewClusterProxy("bootstrap", kubeconfigPath, initScheme(), framework.WithMachineLogCollector(&vspherelog.MachineLogCollector{
 			Client: vsphereClient,
 			Finder: vsphereFinder,
 		}))
 	}
-	if testTarget == VCSimTestTarget {
+	if hasTestTarget(VCSimTestTarget) {
 		// Use a custom cluster-proxy in VCSim mode to allow connections from the e2e test code (outside of the management cluster)
 		// to the fake kube-apiserver running in vcsim-controller.
-		bootstrapClusterProxy = vspherevcsim.NewClusterProxy("bootstrap", kubeconfigPath, initScheme(), clusterProxyOptions...)
-	} else {
-		bootstrapClusterProxy = framework.NewClusterProxy("bootstrap", kubeconfigPath, initScheme(), clusterProxyOptions...)
+		bootstrapClusterProxies[VCSimTestTarget] = vspherevcsim.NewClusterProxy("bootstrap", kubeconfigPath, initScheme())
 	}
+	bootstrapClusterProxy = bootstrapClusterProxies[testTarget]
 
 	ipClaimLabels := map[string]string{}
 	for _, s := range strings.Split(ipClaimLabelsRaw, ";") {
@@ -285,15 +302,14 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 	}
 
 	// Setup the in cluster address manager
-	switch testTarget {
-	case VCenterTestTarget:
+	if hasTestTarget(VCenterTestTarget) {
 		// Create the in cluster address manager
-		inClusterAddressManager, err = vsphereip.InClusterAddressManager(ctx, bootstrapClusterProxy.GetClient(), e2eIPPool, ipClaimLabels, skipCleanup)
+		inClusterAddressManager, err = vsphereip.InClusterAddressManager(ctx, bootstrapClusterProxies[VCenterTestTarget].GetClient(), e2eIPPool, ipClaimLabels, skipCleanup)
 		Expect(err).ToNot(HaveOccurred())
-
-	case VCSimTestTarget:
+	}
+	if hasTestTarget(VCSimTestTarget) {
 		// Create the in vcsim address manager
-		vcsimAddressManager, err = vsphereip.VCSIMAddressManager(bootstrapClusterProxy.GetClient(), ipClaimLabels, skipCleanup)
+		vcsimAddressManager, err = vsphereip.VCSIMAddressManager(bootstrapClusterProxies[VCSimTestTarget].GetClient(), ipClaimLabels, skipCleanup)
 		Expect(err).ToNot(HaveOccurred())
 	}
 })
@@ -307,25 +323,24 @@ var _ = SynchronizedAfterSuite(func() {
 	// After all ParallelNodes.
 	if !skipCleanup {
 		By("Cleaning up orphaned IPAddressClaims")
-		switch testTarget {
-		case VCenterTestTarget:
+		if hasTestTarget(VCenterTestTarget) {
 			// Cleanup the in cluster address manager
 			vSphereFolderName := e2eConfig.GetVariable("VSPHERE_FOLDER")
 			err := inClusterAddressManager.Teardown(ctx, vsphereip.MachineFolder(vSphereFolderName), vsphereip.VSphereClient(vsphereClient))
 			if err != nil {
 				Byf("Ignoring Teardown error: %v", err)
 			}
-
-		case VCSimTestTarget:
+		}
+		if hasTestTarget(VCSimTestTarget) {
 			// Cleanup the vcsim address manager
 			Expect(vcsimAddressManager.Teardown(ctx)).To(Succeed())
 
 			// cleanup the vcsim server
-			Expect(vspherevcsim.Delete(ctx, bootstrapClusterProxy.GetClient(), skipCleanup)).To(Succeed())
+			Expect(vspherevcsim.Delete(ctx, bootstrapClusterProxies[VCSimTestTarget].GetClient(), skipCleanup)).To(Succeed())
 		}
 	}
 
-	if testTarget == VCenterTestTarget {
+	if hasTestTarget(VCenterTestTarget) {
 		By("Cleaning up the vSphere session", terminateVSphereSession)
 	}
 
@@ -339,15 +354,15 @@ func initScheme() *runtime.Scheme {
 	sc := runtime.NewScheme()
 	framework.TryAddDefaultSchemes(sc)
 
-	if testTarget == VCSimTestTarget {
+	if hasTestTarget(VCSimTestTarget) {
 		_ = vcsimv1.AddToScheme(sc)
 	}
 
-	if testMode == GovmomiTestMode {
+	if hasTestMode(GovmomiTestMode) {
 		_ = infrav1.AddToScheme(sc)
 	}
 
-	if testMode == SupervisorTestMode {
+	if hasTestMode(SupervisorTestMode) {
 		_ = corev1.AddToScheme(sc)
 		_ = storagev1.AddToScheme(sc)
 		_ = topologyv1.AddToScheme(sc)
@@ -31,7 +31,7 @@ import (
 	capiutil "sigs.k8s.io/cluster-api/util"
 )
 
-var _ = Describe("Cluster creation with GPU devices as PCI passthrough [specialized-infra]", func() {
+var _ = DescribeWithFlavors("Cluster creation with GPU devices as PCI passthrough [specialized-infra]", func() {
 	const specName = "gpu-pci"
 	Setup(specName, func(testSpecificSettingsGetter func() testSettings) {
 		var (
@@ -42,7 +42,7 @@ type HardwareUpgradeSpecInput struct {
 	ToVersion string
 }
 
-var _ = Describe("Hardware version upgrade", func() {
+var _ = DescribeWithFlavors("Hardware version upgrade", func() {
 	const specName = "hw-upgrade"
 	Setup(specName, func(testSpecificSettingsGetter func() testSettings) {
 		var (
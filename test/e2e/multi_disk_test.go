@@ -37,7 +37,7 @@ type diskSpecInput struct {
 	clusterName string
 }
 
-var _ = Describe("Ensure govmomi mode is able to add additional disks to VMs", func() {
+var _ = DescribeWithFlavors("Ensure govmomi mode is able to add additional disks to VMs", func() {
 	const specName = "multi-disk"
 	Setup(specName, func(testSpecificSettingsGetter func() testSettings) {
 		capi_e2e.QuickStartSpec(ctx, func() capi_e2e.QuickStartSpecInput {
@@ -45,7 +45,7 @@ type MultiVCenterSpecInput struct {
 	Datacenter string
 }
 
-var _ = Describe("Cluster creation with multivc [specialized-infra]", func() {
+var _ = DescribeWithFlavors("Cluster creation with multivc [specialized-infra]", func() {
 	const specName = "multi-vc"
 	Setup(specName, func(testSpecificSettingsGetter func() testSettings) {
 		var namespace *corev1.Namespace
@@ -41,7 +41,7 @@ type NodeLabelingSpecInput struct {
 	PostNamespaceCreated func(managementClusterProxy framework.ClusterProxy, workloadClusterNamespace string)
 }
 
-var _ = Describe("Label nodes with ESXi host info", func() {
+var _ = DescribeWithFlavors("Label nodes with ESXi host info", func() {
 	const specName = "node-labeling"
 	Setup(specName, func(testSpecificSettingsGetter func() testSettings) {
 		var (
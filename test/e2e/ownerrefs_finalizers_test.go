@@ -48,11 +48,12 @@ import (
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/v1beta1"
 	vmwarev1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/vmware/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/hack/tools/pkg/move"
 	topologyv1 "sigs.k8s.io/cluster-api-provider-vsphere/internal/apis/topology/v1alpha1"
 	vcsimv1 "sigs.k8s.io/cluster-api-provider-vsphere/test/infrastructure/vcsim/api/v1alpha1"
 )
 
-var _ = Describe("Ensure OwnerReferences and Finalizers are resilient [vcsim] [supervisor]", func() {
+var _ = DescribeWithFlavors("Ensure OwnerReferences and Finalizers are resilient [vcsim] [supervisor]", func() {
 	const specName = "owner-reference"
 	Setup(specName, func(testSpecificSettingsGetter func() testSettings) {
 		capi_e2e.QuickStartSpec(ctx, func() capi_e2e.QuickStartSpecInput {
@@ -99,6 +100,13 @@ var _ = Describe("Ensure OwnerReferences and Finalizers are resilient [vcsim] [s
 						// Note: identity secret is not part of the object graph, so it requires an ad-hoc test.
 						checkClusterIdentitySecretOwnerRefAndFinalizer(ctx, proxy.GetClient())
 
+						// This check ensures that clusterctl move transfers all objects required by the cluster,
+						// including global objects like the VSphereClusterIdentity and the VSphereDeploymentZones.
+						By("Checking that clusterctl move would not leave objects behind")
+						findings, err := move.NewValidator(proxy.GetClient(), clusterIdentitySecretNamespace).Validate(ctx, namespace)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(findings).To(BeEmpty(), "Objects referenced by the cluster would be left behind by clusterctl move")
+
 						// Set up a periodic patch to ensure the DeploymentZone are reconciled.
 						// Note: this is required because DeploymentZone are not watching for clusters, and thus the DeploymentZone controller
 						// won't be triggered when we un-pause clusters after modifying objects ownerReferences & Finalizers to test resilience.
@@ -20,7 +20,6 @@ import (
 	"fmt"
 	"time"
 
-	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -31,7 +30,7 @@ import (
 	"sigs.k8s.io/cluster-api/test/framework/clusterctl"
 )
 
-var _ = Describe("Cluster Creation using Cluster API quick-start test [vcsim] [supervisor]", func() {
+var _ = DescribeWithFlavors("Cluster Creation using Cluster API quick-start test [vcsim] [supervisor]", func() {
 	const specName = "quick-start" // copied from CAPI
 	Setup(specName, func(testSpecificSettingsGetter func() testSettings) {
 		capi_e2e.QuickStartSpec(ctx, func() capi_e2e.QuickStartSpecInput {
@@ -48,7 +47,7 @@ var _ = Describe("Cluster Creation using Cluster API quick-start test [vcsim] [s
 	})
 })
 
-var _ = Describe("Cluster Creation using Cluster API quick-start test and MOID [vcsim]", func() {
+var _ = DescribeWithFlavors("Cluster Creation using Cluster API quick-start test and MOID [vcsim]", func() {
 	const specName = "quick-start-moid"
 	Setup(specName, func(testSpecificSettingsGetter func() testSettings) {
 		capi_e2e.QuickStartSpec(ctx, func() capi_e2e.QuickStartSpecInput {
@@ -65,7 +64,7 @@ var _ = Describe("Cluster Creation using Cluster API quick-start test and MOID [
 	}, WithMOID(true))
 })
 
-var _ = Describe("ClusterClass Creation using Cluster API quick-start test [vcsim] [supervisor] [PR-Blocking] [ClusterClass]", func() {
+var _ = DescribeWithFlavors("ClusterClass Creation using Cluster API quick-start test [vcsim] [supervisor] [PR-Blocking] [ClusterClass]", func() {
 	const specName = "quick-start-cluster-class" // prefix (quick-start) copied from CAPI
 	Setup(specName, func(testSpecificSettingsGetter func() testSettings) {
 		capi_e2e.QuickStartSpec(ctx, func() capi_e2e.QuickStartSpecInput {
@@ -83,7 +82,7 @@ var _ = Describe("ClusterClass Creation using Cluster API quick-start test [vcsi
 	})
 })
 
-var _ = Describe("Cluster creation with [Ignition] bootstrap [PR-Blocking]", func() {
+var _ = DescribeWithFlavors("Cluster creation with [Ignition] bootstrap [PR-Blocking]", func() {
 	const specName = "quick-start-ignition" // prefix (quick-start) copied from CAPI
 	Setup(specName, func(testSpecificSettingsGetter func() testSettings) {
 		capi_e2e.QuickStartSpec(ctx, func() capi_e2e.QuickStartSpecInput {
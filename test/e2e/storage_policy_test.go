@@ -43,7 +43,7 @@ type StoragePolicySpecInput struct {
 	Datacenter string
 }
 
-var _ = Describe("Cluster creation with storage policy", func() {
+var _ = DescribeWithFlavors("Cluster creation with storage policy", func() {
 	const specName = "storage-policy"
 	Setup(specName, func(testSpecificSettingsGetter func() testSettings) {
 		var namespace *corev1.Namespace
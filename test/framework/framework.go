@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	. "github.com/onsi/gomega"
@@ -44,7 +45,9 @@ type configOverrides struct {
 	Intervals map[string][]string `json:"intervals,omitempty"`
 }
 
-func LoadE2EConfig(ctx context.Context, configPath string, configOverridesPath, testTarget, testMode string) (*clusterctl.E2EConfig, error) {
+// LoadE2EConfig loads the e2e config, dropping the providers and images which are not required by the given
+// test targets and test modes.
+func LoadE2EConfig(ctx context.Context, configPath string, configOverridesPath string, testTargets, testModes []string) (*clusterctl.E2EConfig, error) {
 	config := clusterctl.LoadE2EConfig(ctx, clusterctl.LoadE2EConfigInput{ConfigPath: configPath})
 	if config == nil {
 		return nil, fmt.Errorf("cannot load E2E config found at %s", configPath)
@@ -71,7 +74,7 @@ func LoadE2EConfig(ctx context.Context, configPath string, configOverridesPath,
 		}
 	}
 
-	if testTarget == "vcenter" {
+	if !slices.Contains(testTargets, "vcsim") {
 		// In case we are not testing vcsim, then drop the vcsim controller from providers and images.
 		// This ensures that all the tests not yet allowing to explicitly set vsphere as target infra provider keep working.
 		Byf("Dropping vcsim provider from the e2e config")
@@ -88,8 +91,9 @@ func LoadE2EConfig(ctx context.Context, configPath string, configOverridesPath,
 				break
 			}
 		}
-	} else {
-		// In case we are testing with vcsim, then drop the in-cluster ipam provider from providers and images.
+	}
+	if !slices.Contains(testTargets, "vcenter") {
+		// In case we are testing with vcsim only, then drop the in-cluster ipam provider from providers and images.
 		Byf("Dropping in-cluster provider from the e2e config")
 		for i := range config.Providers {
 			if config.Providers[i].Name == "in-cluster" {
@@ -106,7 +110,7 @@ func LoadE2EConfig(ctx context.Context, configPath string, configOverridesPath,
 		}
 	}
 
-	if testMode == "govmomi" {
+	if !slices.Contains(testModes, "supervisor") {
 		// In case we are not testing supervisor, then drop the vm-operator controller from providers and images.
 		Byf("Dropping vm-operator from the e2e config")
 		for i := range config.Providers {
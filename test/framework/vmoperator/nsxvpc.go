@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmoperator
+
+import (
+	"context"
+	"embed"
+	"path"
+	"time"
+
+	"github.com/pkg/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// nsxVPCCRDs are the CRDs of the NSX-VPC network provider used by CAPV, i.e. SubnetSets, and by vm-operator, i.e. SubnetPorts.
+// NOTE: the CRDs are generated from github.com/vmware-tanzu/nsx-operator/pkg/apis/vpc/v1alpha1.
+//
+//go:embed crds/nsx-vpc/*.yaml
+var nsxVPCCRDs embed.FS
+
+// ReconcileNSXVPCCRDs installs the CRDs of the NSX-VPC network provider, so supervisor clusters using
+// the NSX-VPC network provider can be tested without an NSX deployment.
+// NOTE: This func is idempotent, it creates the CRDs if missing otherwise it uses existing ones.
+func ReconcileNSXVPCCRDs(ctx context.Context, c client.Client) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	files, err := nsxVPCCRDs.ReadDir("crds/nsx-vpc")
+	if err != nil {
+		return errors.Wrap(err, "failed to read NSX-VPC CRDs")
+	}
+
+	for _, file := range files {
+		data, err := nsxVPCCRDs.ReadFile(path.Join("crds/nsx-vpc", file.Name()))
+		if err != nil {
+			return errors.Wrapf(err, "failed to read NSX-VPC CRD %s", file.Name())
+		}
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := yaml.Unmarshal(data, crd); err != nil {
+			return errors.Wrapf(err, "failed to unmarshal NSX-VPC CRD %s", file.Name())
+		}
+
+		if err := c.Get(ctx, client.ObjectKeyFromObject(crd), crd); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return errors.Wrapf(err, "failed to get NSX-VPC CRD %s", crd.Name)
+			}
+			if err := c.Create(ctx, crd); err != nil {
+				return errors.Wrapf(err, "failed to create NSX-VPC CRD %s", crd.Name)
+			}
+			log.Info("Created NSX-VPC CRD", "CustomResourceDefinition", klog.KObj(crd))
+		}
+
+		// Wait for the CRD to be established, so the resources it defines can be used right after.
+		var retryError error
+		_ = wait.PollUntilContextTimeout(ctx, 250*time.Millisecond, 10*time.Second, true, func(ctx context.Context) (bool, error) {
+			retryError = nil
+			if err := c.Get(ctx, client.ObjectKeyFromObject(crd), crd); err != nil {
+				retryError = errors.Wrapf(err, "failed to get NSX-VPC CRD %s", crd.Name)
+				return false, nil
+			}
+			for _, condition := range crd.Status.Conditions {
+				if condition.Type == apiextensionsv1.Established && condition.Status == apiextensionsv1.ConditionTrue {
+					return true, nil
+				}
+			}
+			retryError = errors.Errorf("NSX-VPC CRD %s is not established yet", crd.Name)
+			return false, nil
+		})
+		if retryError != nil {
+			return retryError
+		}
+	}
+	return nil
+}
@@ -110,6 +110,13 @@ func ReconcileDependencies(ctx context.Context, c client.Client, dependenciesCon
 		config.Spec.OperatorRef = &vcsimv1.VMOperatorRef{Namespace: DefaultNamespace}
 	}
 
+	// Install the CRDs of the NSX-VPC network provider if required.
+	if config.Spec.NetworkProvider == vcsimv1.NSXVPCNetworkProvider {
+		if err := ReconcileNSXVPCCRDs(ctx, c); err != nil {
+			return err
+		}
+	}
+
 	// Get a Client to VCenter and get holds on the relevant objects that should already exist
 	params := session.NewParams().
 		WithServer(config.Spec.VCenter.ServerURL).
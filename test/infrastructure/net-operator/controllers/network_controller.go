@@ -0,0 +1,168 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	netopv1alpha1 "github.com/vmware-tanzu/net-operator-api/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	vmwarev1 "sigs.k8s.io/cluster-api-provider-vsphere/apis/vmware/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/services/network"
+	"sigs.k8s.io/cluster-api-provider-vsphere/test/framework/vmoperator"
+)
+
+// DefaultNetworkName is the name of the default Network created in the namespaces of VSphereClusters.
+const DefaultNetworkName = "default-vds-network"
+
+// NetworkReconciler simulates the VDS networks of a supervisor by creating a default Network in
+// the namespaces of VSphereClusters, backed by a VSphereDistributedNetwork for the distributed
+// port group of the vm-operator config.
+type NetworkReconciler struct {
+	Client client.Client
+
+	// Subnet is the simulated subnet of the networks. If nil, the networks use DHCP.
+	Subnet *SubnetAllocator
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+}
+
+// +kubebuilder:rbac:groups=vmware.infrastructure.cluster.x-k8s.io,resources=vsphereclusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=netoperator.vmware.com,resources=networks,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=netoperator.vmware.com,resources=vspheredistributednetworks,verbs=get;list;watch;create
+
+func (r *NetworkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Fetch the VSphereCluster instance
+	vSphereCluster := &vmwarev1.VSphereCluster{}
+	if err := r.Client.Get(ctx, req.NamespacedName, vSphereCluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !vSphereCluster.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	distributedNetwork, err := r.reconcileVSphereDistributedNetwork(ctx, vSphereCluster.Namespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	defaultNetwork := &netopv1alpha1.Network{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DefaultNetworkName,
+			Namespace: vSphereCluster.Namespace,
+			Labels: map[string]string{
+				network.CAPVDefaultNetworkLabel: "true",
+			},
+		},
+		Spec: netopv1alpha1.NetworkSpec{
+			Type: netopv1alpha1.NetworkTypeVDS,
+			ProviderRef: netopv1alpha1.NetworkProviderReference{
+				APIGroup:   netopv1alpha1.GroupName,
+				APIVersion: netopv1alpha1.SchemeGroupVersion.String(),
+				Kind:       "VSphereDistributedNetwork",
+				Name:       distributedNetwork.Name,
+			},
+		},
+	}
+	if err := r.Client.Create(ctx, defaultNetwork); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, errors.Wrapf(err, "failed to create Network %s", klog.KObj(defaultNetwork))
+	}
+	log.Info("Created default Network simulating a supervisor VDS network", "Network", klog.KObj(defaultNetwork))
+
+	return ctrl.Result{}, nil
+}
+
+// reconcileVSphereDistributedNetwork creates the VSphereDistributedNetwork backing the default Network of the namespace.
+func (r *NetworkReconciler) reconcileVSphereDistributedNetwork(ctx context.Context, namespace string) (*netopv1alpha1.VSphereDistributedNetwork, error) {
+	distributedNetwork := &netopv1alpha1.VSphereDistributedNetwork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("%s-%s", namespace, DefaultNetworkName),
+		},
+	}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(distributedNetwork), distributedNetwork); err == nil {
+		return distributedNetwork, nil
+	} else if !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	s, err := vmoperator.GetVCenterSession(ctx, r.Client)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get vcenter session")
+	}
+
+	distributedPortGroupName, err := vmoperator.GetDistributedPortGroup(ctx, r.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	distributedPortGroup, err := s.Finder.Network(ctx, distributedPortGroupName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get DistributedPortGroup %s", distributedPortGroupName)
+	}
+
+	distributedNetwork.Spec = netopv1alpha1.VSphereDistributedNetworkSpec{
+		PortGroupID:      distributedPortGroup.Reference().Value,
+		IPAssignmentMode: netopv1alpha1.IPAssignmentModeDHCP,
+		IPPools:          []netopv1alpha1.IPPoolReference{},
+	}
+	if r.Subnet != nil {
+		distributedNetwork.Spec.IPAssignmentMode = netopv1alpha1.IPAssignmentModeStaticPool
+		distributedNetwork.Spec.Gateway = r.Subnet.Gateway()
+		distributedNetwork.Spec.SubnetMask = r.Subnet.SubnetMask()
+	}
+	if err := r.Client.Create(ctx, distributedNetwork); err != nil && !apierrors.IsAlreadyExists(err) {
+		return nil, errors.Wrapf(err, "failed to create VSphereDistributedNetwork %s", distributedNetwork.Name)
+	}
+	return distributedNetwork, nil
+}
+
+// SetupWithManager will add watches for this controller.
+func (r *NetworkReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	predicateLog := ctrl.LoggerFrom(ctx).WithValues("controller", "network")
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		For(&vmwarev1.VSphereCluster{}).
+		Named("network").
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(mgr.GetScheme(), predicateLog, r.WatchFilterValue)).
+		Complete(r)
+
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+
+	return nil
+}
@@ -23,6 +23,7 @@ import (
 	netopv1alpha1 "github.com/vmware-tanzu/net-operator-api/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
@@ -37,6 +38,10 @@ import (
 type NetworkInterfaceReconciler struct {
 	Client client.Client
 
+	// Subnet is the simulated subnet from which the IP addresses of the NetworkInterfaces are
+	// allocated. If nil, no IP addresses are set and the VMs are expected to use DHCP.
+	Subnet *SubnetAllocator
+
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 }
@@ -54,6 +59,9 @@ func (r *NetworkInterfaceReconciler) Reconcile(ctx context.Context, req ctrl.Req
 	networkInterface := &netopv1alpha1.NetworkInterface{}
 	if err := r.Client.Get(ctx, req.NamespacedName, networkInterface); err != nil {
 		if apierrors.IsNotFound(err) {
+			if r.Subnet != nil {
+				r.Subnet.Release(req.NamespacedName)
+			}
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, err
@@ -89,15 +97,35 @@ func (r *NetworkInterfaceReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		}
 
 		networkInterface.Status.NetworkID = distributedPortGroup.Reference().Value
-		networkInterface.Status.Conditions = []netopv1alpha1.NetworkInterfaceCondition{
+	}
+
+	// NOTE: without a simulated subnet we are not setting networkInterface.Status.IPConfigs because we are using dhcp to assign ip in supervisor tests (or the vmIP reconciler with vcsim).
+	if r.Subnet != nil && len(networkInterface.Status.IPConfigs) == 0 {
+		addr, err := r.Subnet.Allocate(ctx, r.Client, req.NamespacedName)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		networkInterface.Status.IPConfigs = []netopv1alpha1.IPConfig{
 			{
-				Type:   netopv1alpha1.NetworkInterfaceReady,
-				Status: corev1.ConditionTrue,
+				IP:         addr.String(),
+				IPFamily:   r.Subnet.IPFamily(),
+				Gateway:    r.Subnet.Gateway(),
+				SubnetMask: r.Subnet.SubnetMask(),
 			},
 		}
+		networkInterface.Status.MacAddress = macAddressFor(addr)
+		log.Info("Allocated IP address from the simulated subnet", "ip", addr.String())
+	}
 
-		// NOTE: we are not setting networkInterface.Status.IPConfigs because we are using dhcp to assign ip in supervisor tests (or the vmIP reconciler with vcsim).
-
+	if len(networkInterface.Status.Conditions) == 0 {
+		networkInterface.Status.Conditions = []netopv1alpha1.NetworkInterfaceCondition{
+			{
+				Type:               netopv1alpha1.NetworkInterfaceReady,
+				Status:             corev1.ConditionTrue,
+				LastTransitionTime: metav1.Now(),
+			},
+		}
 		log.Info("Reconciling NetworkInterface status simulating successful net-operator reconcile")
 	}
 
@@ -0,0 +1,155 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"sync"
+
+	"github.com/pkg/errors"
+	netopv1alpha1 "github.com/vmware-tanzu/net-operator-api/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SubnetAllocator allocates the IP addresses of NetworkInterfaces from a simulated subnet.
+type SubnetAllocator struct {
+	prefix  netip.Prefix
+	gateway netip.Addr
+
+	lock        sync.Mutex
+	initialized bool
+	allocations map[types.NamespacedName]netip.Addr
+}
+
+// NewSubnetAllocator returns a SubnetAllocator for the subnet in CIDR notation.
+// If gateway is empty, the first address of the subnet is used as gateway.
+func NewSubnetAllocator(subnet, gateway string) (*SubnetAllocator, error) {
+	prefix, err := netip.ParsePrefix(subnet)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse subnet %q", subnet)
+	}
+	prefix = prefix.Masked()
+
+	gatewayAddr := prefix.Addr().Next()
+	if gateway != "" {
+		if gatewayAddr, err = netip.ParseAddr(gateway); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse gateway %q", gateway)
+		}
+	}
+	if !prefix.Contains(gatewayAddr) {
+		return nil, errors.Errorf("gateway %s is not in subnet %s", gatewayAddr, prefix)
+	}
+
+	return &SubnetAllocator{
+		prefix:      prefix,
+		gateway:     gatewayAddr,
+		allocations: map[types.NamespacedName]netip.Addr{},
+	}, nil
+}
+
+// Gateway returns the gateway of the subnet.
+func (a *SubnetAllocator) Gateway() string {
+	return a.gateway.String()
+}
+
+// SubnetMask returns the subnet mask of the subnet, e.g. 255.255.255.0.
+func (a *SubnetAllocator) SubnetMask() string {
+	bits := 32
+	if a.prefix.Addr().Is6() {
+		bits = 128
+	}
+	return net.IP(net.CIDRMask(a.prefix.Bits(), bits)).String()
+}
+
+// IPFamily returns the IP family of the subnet.
+func (a *SubnetAllocator) IPFamily() corev1.IPFamily {
+	if a.prefix.Addr().Is6() {
+		return corev1.IPv6Protocol
+	}
+	return corev1.IPv4Protocol
+}
+
+// Allocate returns the IP address allocated to the NetworkInterface, allocating the
+// first free address of the subnet if the NetworkInterface doesn't have one yet.
+// The allocations of the existing NetworkInterfaces are read with c on first use, so
+// that addresses are not allocated twice after a restart.
+func (a *SubnetAllocator) Allocate(ctx context.Context, c client.Reader, key types.NamespacedName) (netip.Addr, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if !a.initialized {
+		if err := a.init(ctx, c); err != nil {
+			return netip.Addr{}, err
+		}
+		a.initialized = true
+	}
+
+	if addr, ok := a.allocations[key]; ok {
+		return addr, nil
+	}
+
+	used := map[netip.Addr]bool{a.gateway: true}
+	for _, addr := range a.allocations {
+		used[addr] = true
+	}
+	// Skip the network address, and the broadcast address of IPv4 subnets.
+	for addr := a.prefix.Addr().Next(); a.prefix.Contains(addr); addr = addr.Next() {
+		if used[addr] || (addr.Is4() && !a.prefix.Contains(addr.Next())) {
+			continue
+		}
+		a.allocations[key] = addr
+		return addr, nil
+	}
+	return netip.Addr{}, errors.Errorf("no free IP address left in subnet %s", a.prefix)
+}
+
+// Release releases the IP address allocated to the NetworkInterface.
+func (a *SubnetAllocator) Release(key types.NamespacedName) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	delete(a.allocations, key)
+}
+
+func (a *SubnetAllocator) init(ctx context.Context, c client.Reader) error {
+	networkInterfaces := &netopv1alpha1.NetworkInterfaceList{}
+	if err := c.List(ctx, networkInterfaces); err != nil {
+		return errors.Wrap(err, "failed to list NetworkInterfaces")
+	}
+	for _, networkInterface := range networkInterfaces.Items {
+		for _, ipConfig := range networkInterface.Status.IPConfigs {
+			addr, err := netip.ParseAddr(ipConfig.IP)
+			if err != nil || !a.prefix.Contains(addr) {
+				continue
+			}
+			a.allocations[types.NamespacedName{Namespace: networkInterface.Namespace, Name: networkInterface.Name}] = addr
+		}
+	}
+	return nil
+}
+
+// macAddressFor returns a MAC address in the range of statically assigned VMware
+// MAC addresses (00:50:56:00:00:00 - 00:50:56:3f:ff:ff) derived from the IP address.
+func macAddressFor(addr netip.Addr) string {
+	b := addr.As16()
+	return fmt.Sprintf("00:50:56:%02x:%02x:%02x", b[13]&0x3f, b[14], b[15])
+}
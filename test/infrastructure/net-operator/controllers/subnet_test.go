@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	netopv1alpha1 "github.com/vmware-tanzu/net-operator-api/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_NewSubnetAllocator(t *testing.T) {
+	g := NewWithT(t)
+
+	a, err := NewSubnetAllocator("192.168.100.17/28", "")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(a.Gateway()).To(Equal("192.168.100.17"))
+	g.Expect(a.SubnetMask()).To(Equal("255.255.255.240"))
+	g.Expect(a.IPFamily()).To(Equal(corev1.IPv4Protocol))
+
+	a, err = NewSubnetAllocator("fd00::/64", "fd00::ffff")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(a.Gateway()).To(Equal("fd00::ffff"))
+	g.Expect(a.IPFamily()).To(Equal(corev1.IPv6Protocol))
+
+	_, err = NewSubnetAllocator("192.168.100.0", "")
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = NewSubnetAllocator("192.168.100.0/24", "192.168.101.1")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func Test_SubnetAllocator_Allocate(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(netopv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	existing := &netopv1alpha1.NetworkInterface{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "existing"},
+		Status: netopv1alpha1.NetworkInterfaceStatus{
+			IPConfigs: []netopv1alpha1.IPConfig{{IP: "10.0.0.2"}},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+
+	// 10.0.0.0/29 has the usable addresses 10.0.0.1 - 10.0.0.6, with 10.0.0.1 being the gateway.
+	a, err := NewSubnetAllocator("10.0.0.0/29", "")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	key := func(name string) types.NamespacedName { return types.NamespacedName{Namespace: "ns", Name: name} }
+
+	// Addresses of existing NetworkInterfaces are kept.
+	addr, err := a.Allocate(ctx, c, key("existing"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(addr.String()).To(Equal("10.0.0.2"))
+
+	for i, want := range []string{"10.0.0.3", "10.0.0.4", "10.0.0.5", "10.0.0.6"} {
+		addr, err := a.Allocate(ctx, c, key(string(rune('a'+i))))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(addr.String()).To(Equal(want))
+	}
+
+	// Allocations are stable.
+	addr, err = a.Allocate(ctx, c, key("a"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(addr.String()).To(Equal("10.0.0.3"))
+	g.Expect(macAddressFor(addr)).To(Equal("00:50:56:00:00:03"))
+
+	// The broadcast address is not allocated.
+	_, err = a.Allocate(ctx, c, key("exhausted"))
+	g.Expect(err).To(HaveOccurred())
+
+	// Released addresses are allocated again.
+	a.Release(key("b"))
+	addr, err = a.Allocate(ctx, c, key("exhausted"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(addr.String()).To(Equal("10.0.0.4"))
+}
@@ -76,12 +76,17 @@ var (
 	logOptions                  = logs.NewOptions()
 	// net operator specific flags.
 	networkInterfaceConcurrency int
+	networkConcurrency          int
+	subnet                      string
+	gateway                     string
+	createDefaultNetworks       bool
 )
 
 func init() {
 	// scheme used for operating on the management cluster.
 	_ = corev1.AddToScheme(scheme)
 	_ = netopv1alpha1.AddToScheme(scheme)
+	_ = vmwarev1.AddToScheme(scheme)
 }
 
 // InitFlags initializes the flags.
@@ -115,6 +120,18 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&networkInterfaceConcurrency, "network-interface-concurrency", 10,
 		"Number of NetworkInterface to process simultaneously")
 
+	fs.IntVar(&networkConcurrency, "network-concurrency", 10,
+		"Number of VSphereClusters to process simultaneously when creating default networks")
+
+	fs.StringVar(&subnet, "subnet", "",
+		"Simulated subnet in CIDR notation (e.g. 192.168.100.0/24) from which the IP addresses of NetworkInterfaces are allocated. If unspecified, no IP addresses are allocated and VMs are expected to use DHCP.")
+
+	fs.StringVar(&gateway, "gateway", "",
+		"Gateway of the simulated subnet. If unspecified, the first address of the subnet is used.")
+
+	fs.BoolVar(&createDefaultNetworks, "create-default-networks", false,
+		"Create a default VDS Network in the namespaces of VSphereClusters, simulating the networks of a supervisor.")
+
 	fs.DurationVar(&syncPeriod, "sync-period", 10*time.Minute,
 		"The minimum interval at which watched resources are reconciled (e.g. 15m)")
 
@@ -255,13 +272,34 @@ func setupIndexes(_ context.Context, _ ctrl.Manager, _ bool) {
 }
 
 func setupReconcilers(ctx context.Context, mgr ctrl.Manager, _ bool) {
+	var subnetAllocator *controllers.SubnetAllocator
+	if subnet != "" {
+		var err error
+		if subnetAllocator, err = controllers.NewSubnetAllocator(subnet, gateway); err != nil {
+			setupLog.Error(err, "invalid simulated subnet")
+			os.Exit(1)
+		}
+	}
+
 	if err := (&controllers.NetworkInterfaceReconciler{
 		Client:           mgr.GetClient(),
+		Subnet:           subnetAllocator,
 		WatchFilterValue: watchFilterValue,
 	}).SetupWithManager(ctx, mgr, concurrency(networkInterfaceConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "NetworkInterfaceReconciler")
 		os.Exit(1)
 	}
+
+	if createDefaultNetworks {
+		if err := (&controllers.NetworkReconciler{
+			Client:           mgr.GetClient(),
+			Subnet:           subnetAllocator,
+			WatchFilterValue: watchFilterValue,
+		}).SetupWithManager(ctx, mgr, concurrency(networkConcurrency)); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "NetworkReconciler")
+			os.Exit(1)
+		}
+	}
 }
 
 func setupWebhooks(_ ctrl.Manager, _ bool) {
@@ -76,9 +76,36 @@ type VCenterSimulatorModel struct {
 	// Default: 1
 	Datastore *int32 `json:"datastore,omitempty"`
 
+	// FailureDomains specifies the tags and the groups to create for testing failure domains.
+	// Note: this is not effective in supervisor mode.
+	FailureDomains *VCenterSimulatorFailureDomains `json:"failureDomains,omitempty"`
+
 	// TODO: consider if to add options for creating more folders, networks, custom storage policies
 }
 
+// VCenterSimulatorFailureDomains defines the tags and the groups to be created by the VCenterSimulator
+// matching the semantics of VSphereFailureDomains.
+// Every Datacenter is tagged with a region tag and every ClusterComputeResource with a zone tag,
+// using the Datacenter and the ClusterComputeResource name as tag names.
+type VCenterSimulatorFailureDomains struct {
+	// RegionTagCategory specifies the name of the tag category used for regions
+	// Default: k8s-region
+	RegionTagCategory *string `json:"regionTagCategory,omitempty"`
+
+	// ZoneTagCategory specifies the name of the tag category used for zones
+	// Default: k8s-zone
+	ZoneTagCategory *string `json:"zoneTagCategory,omitempty"`
+
+	// HostGroup specifies the number of host groups to create per ClusterComputeResource.
+	// The hosts of a ClusterComputeResource are distributed round-robin across its host groups, and
+	// are tagged with a zone tag named after their host group.
+	// For every host group a VM group and a mandatory VM-Host affinity rule are created too.
+	// Name prefix: HG for host groups, VMG for VM groups, R for VM-Host affinity rules
+	// For example: DC0_C0_HG0, DC0_C0_VMG0, DC0_C0_R0
+	// Default: 0
+	HostGroup *int32 `json:"hostGroup,omitempty"`
+}
+
 // VCenterSimulatorStatus defines the observed state of the VCenterSimulator.
 type VCenterSimulatorStatus struct {
 	// The vcsim server  url's host.
@@ -45,8 +45,24 @@ type VMOperatorDependenciesSpec struct {
 
 	// VirtualMachineClasses defines a list of VirtualMachineClasses to be bound to the namespace where this object is created.
 	VirtualMachineClasses []VirtualMachineClass `json:"virtualMachineClasses,omitempty"`
+
+	// NetworkProvider defines the network provider of the supervisor, either vsphere-network or NSX-VPC.
+	// With NSX-VPC, the NSX-VPC CRDs are installed and the status of SubnetSets and SubnetPorts is faked,
+	// simulating a successful reconcile by the nsx-operator.
+	// Defaults to vsphere-network.
+	// +kubebuilder:validation:Enum=vsphere-network;NSX-VPC
+	// +optional
+	NetworkProvider string `json:"networkProvider,omitempty"`
 }
 
+const (
+	// VSphereNetworkProvider is the vsphere-network network provider, faked by the net-operator.
+	VSphereNetworkProvider = "vsphere-network"
+
+	// NSXVPCNetworkProvider is the NSX-VPC network provider, faked by the vcsim controller.
+	NSXVPCNetworkProvider = "NSX-VPC"
+)
+
 // VMOperatorRef provide a reference to the running instance of vm-operator.
 type VMOperatorRef struct {
 	// Namespace where the vm-operator is running.
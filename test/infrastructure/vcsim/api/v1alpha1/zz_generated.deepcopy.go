@@ -392,6 +392,36 @@ func (in *VCenterSimulator) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VCenterSimulatorFailureDomains) DeepCopyInto(out *VCenterSimulatorFailureDomains) {
+	*out = *in
+	if in.RegionTagCategory != nil {
+		in, out := &in.RegionTagCategory, &out.RegionTagCategory
+		*out = new(string)
+		**out = **in
+	}
+	if in.ZoneTagCategory != nil {
+		in, out := &in.ZoneTagCategory, &out.ZoneTagCategory
+		*out = new(string)
+		**out = **in
+	}
+	if in.HostGroup != nil {
+		in, out := &in.HostGroup, &out.HostGroup
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VCenterSimulatorFailureDomains.
+func (in *VCenterSimulatorFailureDomains) DeepCopy() *VCenterSimulatorFailureDomains {
+	if in == nil {
+		return nil
+	}
+	out := new(VCenterSimulatorFailureDomains)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VCenterSimulatorList) DeepCopyInto(out *VCenterSimulatorList) {
 	*out = *in
@@ -457,6 +487,11 @@ func (in *VCenterSimulatorModel) DeepCopyInto(out *VCenterSimulatorModel) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.FailureDomains != nil {
+		in, out := &in.FailureDomains, &out.FailureDomains
+		*out = new(VCenterSimulatorFailureDomains)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VCenterSimulatorModel.
@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/drone/envsubst/v2"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	vcsimv1 "sigs.k8s.io/cluster-api-provider-vsphere/test/infrastructure/vcsim/api/v1alpha1"
+)
+
+// EnvSubstRequest is the body of a request to the envsubst endpoint of the EnvSubstServer.
+type EnvSubstRequest struct {
+	// Spec defines the VCenterSimulator, the ControlPlaneEndpoint and the cluster to resolve the variables for.
+	Spec vcsimv1.EnvVarSpec `json:"spec"`
+
+	// Template is the cluster template the variables are substituted in.
+	Template string `json:"template"`
+}
+
+// EnvSubstServer is an HTTP API resolving the variables of cluster templates the same way the EnvVar
+// reconciler does, so external test frameworks don't have to create and read EnvVar objects.
+//
+// It serves the following endpoints:
+//   - POST /api/v1/namespaces/{namespace}/variables with an EnvVarSpec as body returns the variables as a JSON object.
+//   - POST /api/v1/namespaces/{namespace}/envsubst with an EnvSubstRequest as body returns the template with the
+//     variables substituted like envsubst does.
+//
+// Namespaces omitted in the EnvVarSpec default to the namespace of the path. The server responds with 503
+// Service Unavailable as long as the VCenterSimulator or the ControlPlaneEndpoint are not ready.
+type EnvSubstServer struct {
+	// EnvVarReconciler is used to resolve the variables.
+	EnvVarReconciler *EnvVarReconciler
+
+	// BindAddress is the address the server listens on.
+	BindAddress string
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, so the server runs on all replicas.
+func (s *EnvSubstServer) NeedLeaderElection() bool {
+	return false
+}
+
+// Start implements manager.Runnable.
+func (s *EnvSubstServer) Start(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx).WithName("envsubst-server")
+
+	server := &http.Server{
+		Addr:              s.BindAddress,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return ctrl.LoggerInto(ctx, log)
+		},
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	log.Info("Starting envsubst server", "address", s.BindAddress)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return errors.Wrap(err, "failed to start envsubst server")
+	}
+	return nil
+}
+
+// Handler returns the handler serving the endpoints of the server.
+func (s *EnvSubstServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/namespaces/{namespace}/variables", s.handleVariables)
+	mux.HandleFunc("POST /api/v1/namespaces/{namespace}/envsubst", s.handleEnvSubst)
+	return mux
+}
+
+func (s *EnvSubstServer) handleVariables(w http.ResponseWriter, req *http.Request) {
+	spec := &vcsimv1.EnvVarSpec{}
+	if err := json.NewDecoder(req.Body).Decode(spec); err != nil {
+		http.Error(w, fmt.Sprintf("invalid EnvVarSpec: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	variables, status, err := s.variables(req.Context(), req.PathValue("namespace"), spec)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(variables)
+}
+
+func (s *EnvSubstServer) handleEnvSubst(w http.ResponseWriter, req *http.Request) {
+	envSubstRequest := &EnvSubstRequest{}
+	if err := json.NewDecoder(req.Body).Decode(envSubstRequest); err != nil {
+		http.Error(w, fmt.Sprintf("invalid EnvSubstRequest: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	variables, status, err := s.variables(req.Context(), req.PathValue("namespace"), &envSubstRequest.Spec)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	out, err := envsubst.Eval(envSubstRequest.Template, func(name string) string {
+		return variables[name]
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid template: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte(out))
+}
+
+// variables returns the variables for the EnvVarSpec and the HTTP status code to respond with on errors.
+func (s *EnvSubstServer) variables(ctx context.Context, namespace string, spec *vcsimv1.EnvVarSpec) (map[string]string, int, error) {
+	defaultEnvVarSpec(namespace, spec)
+
+	vCenterSimulator, controlPlaneEndpoint, err := s.EnvVarReconciler.getSources(ctx, spec)
+	if err != nil {
+		var apiStatus apierrors.APIStatus
+		switch {
+		case apierrors.IsNotFound(errors.Cause(err)):
+			return nil, http.StatusNotFound, err
+		case errors.As(err, &apiStatus):
+			return nil, http.StatusInternalServerError, err
+		default:
+			return nil, http.StatusBadRequest, err
+		}
+	}
+	if !sourcesReady(vCenterSimulator, controlPlaneEndpoint) {
+		return nil, http.StatusServiceUnavailable, errors.New("VCenterSimulator or ControlPlaneEndpoint not ready yet")
+	}
+
+	// The ssh authorized key is generated once per cluster.
+	variables, err := s.EnvVarReconciler.variables(ctx, klog.KRef(spec.Cluster.Namespace, spec.Cluster.Name).String(), spec, vCenterSimulator, controlPlaneEndpoint)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	return variables, http.StatusOK, nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	vcsimv1 "sigs.k8s.io/cluster-api-provider-vsphere/test/infrastructure/vcsim/api/v1alpha1"
+)
+
+func TestEnvSubstServer(t *testing.T) {
+	controlPlaneEndpoint := &vcsimv1.ControlPlaneEndpoint{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "cluster1",
+		},
+		Status: vcsimv1.ControlPlaneEndpointStatus{
+			Host: "10.0.0.1",
+			Port: 20000,
+		},
+	}
+	pendingControlPlaneEndpoint := &vcsimv1.ControlPlaneEndpoint{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "cluster2",
+		},
+	}
+
+	crclient := fake.NewClientBuilder().WithObjects(controlPlaneEndpoint, pendingControlPlaneEndpoint).WithScheme(scheme).Build()
+	server := httptest.NewServer((&EnvSubstServer{EnvVarReconciler: &EnvVarReconciler{Client: crclient}}).Handler())
+	defer server.Close()
+
+	post := func(path string, body any) (int, []byte) {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := http.Post(server.URL+path, "application/json", bytes.NewReader(data)) //nolint:noctx // Test code.
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		out, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.StatusCode, out
+	}
+
+	spec := vcsimv1.EnvVarSpec{
+		ControlPlaneEndpoint: vcsimv1.NamespacedRef{Name: "cluster1"},
+		Cluster:              vcsimv1.ClusterEnvVarSpec{Name: "cluster1"},
+	}
+
+	t.Run("returns the variables", func(t *testing.T) {
+		g := NewWithT(t)
+
+		status, out := post("/api/v1/namespaces/default/variables", spec)
+		g.Expect(status).To(Equal(http.StatusOK), string(out))
+
+		variables := map[string]string{}
+		g.Expect(json.Unmarshal(out, &variables)).To(Succeed())
+		g.Expect(variables).To(HaveKeyWithValue("CLUSTER_NAME", "cluster1"))
+		g.Expect(variables).To(HaveKeyWithValue("NAMESPACE", "default"))
+		g.Expect(variables).To(HaveKeyWithValue("CONTROL_PLANE_ENDPOINT_IP", "10.0.0.1"))
+		g.Expect(variables).To(HaveKeyWithValue("CONTROL_PLANE_ENDPOINT_PORT", "20000"))
+		g.Expect(variables).To(HaveKey("VSPHERE_SSH_AUTHORIZED_KEY"))
+	})
+
+	t.Run("substitutes the variables in the template", func(t *testing.T) {
+		g := NewWithT(t)
+
+		status, out := post("/api/v1/namespaces/default/envsubst", EnvSubstRequest{
+			Spec:     spec,
+			Template: "name: ${CLUSTER_NAME}\nendpoint: ${CONTROL_PLANE_ENDPOINT_IP}:${CONTROL_PLANE_ENDPOINT_PORT}\nmode: ${UNDEFINED:=default}\n",
+		})
+		g.Expect(status).To(Equal(http.StatusOK), string(out))
+		g.Expect(string(out)).To(Equal("name: cluster1\nendpoint: 10.0.0.1:20000\nmode: default\n"))
+	})
+
+	t.Run("fails if the ControlPlaneEndpoint does not exist", func(t *testing.T) {
+		g := NewWithT(t)
+
+		status, _ := post("/api/v1/namespaces/other/variables", spec)
+		g.Expect(status).To(Equal(http.StatusNotFound))
+	})
+
+	t.Run("fails if the ControlPlaneEndpoint is not ready", func(t *testing.T) {
+		g := NewWithT(t)
+
+		status, _ := post("/api/v1/namespaces/default/variables", vcsimv1.EnvVarSpec{
+			ControlPlaneEndpoint: vcsimv1.NamespacedRef{Name: "cluster2"},
+			Cluster:              vcsimv1.ClusterEnvVarSpec{Name: "cluster2"},
+		})
+		g.Expect(status).To(Equal(http.StatusServiceUnavailable))
+	})
+
+	t.Run("fails without a ControlPlaneEndpoint", func(t *testing.T) {
+		g := NewWithT(t)
+
+		status, _ := post("/api/v1/namespaces/default/variables", vcsimv1.EnvVarSpec{})
+		g.Expect(status).To(Equal(http.StatusBadRequest))
+	})
+}
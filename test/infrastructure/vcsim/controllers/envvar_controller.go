@@ -70,45 +70,15 @@ func (r *EnvVarReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ c
 		}
 		return ctrl.Result{}, err
 	}
-	if envVar.Spec.Cluster.Namespace == "" {
-		envVar.Spec.Cluster.Namespace = envVar.Namespace
-	}
-
-	// Fetch the VCenterSimulator instance
-	var vCenterSimulator *vcsimv1.VCenterSimulator
-	if envVar.Spec.VCenterSimulator != nil {
-		if envVar.Spec.VCenterSimulator.Name == "" {
-			return ctrl.Result{}, errors.New("Spec.VCenterSimulator.Name cannot be empty")
-		}
-		if envVar.Spec.VCenterSimulator.Namespace == "" {
-			envVar.Spec.VCenterSimulator.Namespace = envVar.Namespace
-		}
-
-		vCenterSimulator = &vcsimv1.VCenterSimulator{}
-		if err := r.Client.Get(ctx, client.ObjectKey{
-			Namespace: envVar.Spec.VCenterSimulator.Namespace,
-			Name:      envVar.Spec.VCenterSimulator.Name,
-		}, vCenterSimulator); err != nil {
-			return ctrl.Result{}, errors.Wrapf(err, "failed to get VCenter")
-		}
-		log = log.WithValues("VCenter", klog.KObj(vCenterSimulator))
-		ctx = ctrl.LoggerInto(ctx, log)
-	}
+	defaultEnvVarSpec(envVar.Namespace, &envVar.Spec)
 
-	// Fetch the ControlPlaneEndpoint instance
-	if envVar.Spec.ControlPlaneEndpoint.Name == "" {
-		return ctrl.Result{}, errors.New("Spec.ControlPlaneEndpoint.Name cannot be empty")
-	}
-	if envVar.Spec.ControlPlaneEndpoint.Namespace == "" {
-		envVar.Spec.ControlPlaneEndpoint.Namespace = envVar.Namespace
+	// Fetch the VCenterSimulator and the ControlPlaneEndpoint instances
+	vCenterSimulator, controlPlaneEndpoint, err := r.getSources(ctx, &envVar.Spec)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
-
-	controlPlaneEndpoint := &vcsimv1.ControlPlaneEndpoint{}
-	if err := r.Client.Get(ctx, client.ObjectKey{
-		Namespace: envVar.Spec.ControlPlaneEndpoint.Namespace,
-		Name:      envVar.Spec.ControlPlaneEndpoint.Name,
-	}, controlPlaneEndpoint); err != nil {
-		return ctrl.Result{}, errors.Wrapf(err, "failed to get ControlPlaneEndpoint")
+	if vCenterSimulator != nil {
+		log = log.WithValues("VCenter", klog.KObj(vCenterSimulator))
 	}
 	log = log.WithValues("ControlPlaneEndpoint", klog.KObj(controlPlaneEndpoint))
 	ctx = ctrl.LoggerInto(ctx, log)
@@ -139,12 +109,73 @@ func (r *EnvVarReconciler) reconcileNormal(ctx context.Context, envVar *vcsimv1.
 	log := ctrl.LoggerFrom(ctx)
 	log.Info("Reconciling VCSim EnvVar")
 
-	if controlPlaneEndpoint.Status.Host == "" {
+	if !sourcesReady(vCenterSimulator, controlPlaneEndpoint) {
 		return ctrl.Result{Requeue: true}, nil
 	}
-	if vCenterSimulator != nil && vCenterSimulator.Status.Host == "" {
-		return ctrl.Result{Requeue: true}, nil
+
+	variables, err := r.variables(ctx, klog.KObj(envVar).String(), &envVar.Spec, vCenterSimulator, controlPlaneEndpoint)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
+	envVar.Status.Variables = variables
+	return ctrl.Result{}, nil
+}
+
+// defaultEnvVarSpec defaults the namespaces of the EnvVarSpec to the given namespace.
+func defaultEnvVarSpec(namespace string, spec *vcsimv1.EnvVarSpec) {
+	if spec.Cluster.Namespace == "" {
+		spec.Cluster.Namespace = namespace
+	}
+	if spec.VCenterSimulator != nil && spec.VCenterSimulator.Namespace == "" {
+		spec.VCenterSimulator.Namespace = namespace
+	}
+	if spec.ControlPlaneEndpoint.Namespace == "" {
+		spec.ControlPlaneEndpoint.Namespace = namespace
+	}
+}
+
+// getSources returns the VCenterSimulator, if any, and the ControlPlaneEndpoint referenced by the EnvVarSpec.
+func (r *EnvVarReconciler) getSources(ctx context.Context, spec *vcsimv1.EnvVarSpec) (*vcsimv1.VCenterSimulator, *vcsimv1.ControlPlaneEndpoint, error) {
+	var vCenterSimulator *vcsimv1.VCenterSimulator
+	if spec.VCenterSimulator != nil {
+		if spec.VCenterSimulator.Name == "" {
+			return nil, nil, errors.New("Spec.VCenterSimulator.Name cannot be empty")
+		}
+
+		vCenterSimulator = &vcsimv1.VCenterSimulator{}
+		if err := r.Client.Get(ctx, client.ObjectKey{
+			Namespace: spec.VCenterSimulator.Namespace,
+			Name:      spec.VCenterSimulator.Name,
+		}, vCenterSimulator); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to get VCenter")
+		}
+	}
+
+	if spec.ControlPlaneEndpoint.Name == "" {
+		return nil, nil, errors.New("Spec.ControlPlaneEndpoint.Name cannot be empty")
+	}
+
+	controlPlaneEndpoint := &vcsimv1.ControlPlaneEndpoint{}
+	if err := r.Client.Get(ctx, client.ObjectKey{
+		Namespace: spec.ControlPlaneEndpoint.Namespace,
+		Name:      spec.ControlPlaneEndpoint.Name,
+	}, controlPlaneEndpoint); err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to get ControlPlaneEndpoint")
+	}
+	return vCenterSimulator, controlPlaneEndpoint, nil
+}
+
+// sourcesReady returns true if the hosts of the VCenterSimulator, if any, and of the ControlPlaneEndpoint are set.
+func sourcesReady(vCenterSimulator *vcsimv1.VCenterSimulator, controlPlaneEndpoint *vcsimv1.ControlPlaneEndpoint) bool {
+	if controlPlaneEndpoint.Status.Host == "" {
+		return false
+	}
+	return vCenterSimulator == nil || vCenterSimulator.Status.Host != ""
+}
+
+// sshAuthorizedKey returns the ssh authorized key for the given key, generating it if it doesn't exist yet.
+func (r *EnvVarReconciler) sshAuthorizedKey(ctx context.Context, key string) (string, error) {
+	log := ctrl.LoggerFrom(ctx)
 
 	r.lock.Lock()
 	defer r.lock.Unlock()
@@ -153,37 +184,46 @@ func (r *EnvVarReconciler) reconcileNormal(ctx context.Context, envVar *vcsimv1.
 		r.sshKeys = map[string]string{}
 	}
 
-	key := klog.KObj(envVar).String()
 	sshKey, ok := r.sshKeys[key]
 	if !ok {
 		bitSize := 4096
 
 		privateKey, err := generatePrivateKey(bitSize)
 		if err != nil {
-			return ctrl.Result{}, errors.Wrapf(err, "failed to generate private key")
+			return "", errors.Wrapf(err, "failed to generate private key")
 		}
 
 		publicKeyBytes, err := generatePublicKey(&privateKey.PublicKey)
 		if err != nil {
-			return ctrl.Result{}, errors.Wrapf(err, "failed to generate public key")
+			return "", errors.Wrapf(err, "failed to generate public key")
 		}
 
 		sshKey = string(publicKeyBytes)
 		r.sshKeys[key] = sshKey
 		log.Info("Created ssh authorized key")
 	}
+	return sshKey, nil
+}
+
+// variables returns the variables to use when creating the Cluster API cluster of the EnvVarSpec.
+// sshKeyID identifies the ssh authorized key generated for the cluster.
+func (r *EnvVarReconciler) variables(ctx context.Context, sshKeyID string, spec *vcsimv1.EnvVarSpec, vCenterSimulator *vcsimv1.VCenterSimulator, controlPlaneEndpoint *vcsimv1.ControlPlaneEndpoint) (map[string]string, error) {
+	sshKey, err := r.sshAuthorizedKey(ctx, sshKeyID)
+	if err != nil {
+		return nil, err
+	}
 
 	// Variables required only when the vcsim controller is used in combination with Tilt (E2E tests provide this value in other ways)
-	envVar.Status.Variables = map[string]string{
+	variables := map[string]string{
 		// Variables for machines ssh key
 		"VSPHERE_SSH_AUTHORIZED_KEY": sshKey,
 
 		// other variables required by the cluster template.
-		"NAMESPACE":                   envVar.Spec.Cluster.Namespace,
-		"CLUSTER_NAME":                envVar.Spec.Cluster.Name,
-		"KUBERNETES_VERSION":          ptr.Deref(envVar.Spec.Cluster.KubernetesVersion, "v1.28.0"),
-		"CONTROL_PLANE_MACHINE_COUNT": strconv.Itoa(int(ptr.Deref(envVar.Spec.Cluster.ControlPlaneMachines, 1))),
-		"WORKER_MACHINE_COUNT":        strconv.Itoa(int(ptr.Deref(envVar.Spec.Cluster.WorkerMachines, 1))),
+		"NAMESPACE":                   spec.Cluster.Namespace,
+		"CLUSTER_NAME":                spec.Cluster.Name,
+		"KUBERNETES_VERSION":          ptr.Deref(spec.Cluster.KubernetesVersion, "v1.28.0"),
+		"CONTROL_PLANE_MACHINE_COUNT": strconv.Itoa(int(ptr.Deref(spec.Cluster.ControlPlaneMachines, 1))),
+		"WORKER_MACHINE_COUNT":        strconv.Itoa(int(ptr.Deref(spec.Cluster.WorkerMachines, 1))),
 
 		// variables for the fake APIServer endpoint
 		"CONTROL_PLANE_ENDPOINT_IP":   controlPlaneEndpoint.Status.Host,
@@ -194,7 +234,7 @@ func (r *EnvVarReconciler) reconcileNormal(ctx context.Context, envVar *vcsimv1.
 	if r.SupervisorMode {
 		// variables for supervisor mode derived from the vCenterSimulator
 		for k, v := range vCenterSimulatorCommonVariables(vCenterSimulator) {
-			envVar.Status.Variables[k] = v
+			variables[k] = v
 		}
 
 		// Variables for supervisor mode derived from how do we setup dependency for vm-operator
@@ -202,40 +242,40 @@ func (r *EnvVarReconciler) reconcileNormal(ctx context.Context, envVar *vcsimv1.
 		dependenciesConfig := &vcsimv1.VMOperatorDependencies{ObjectMeta: metav1.ObjectMeta{Namespace: corev1.NamespaceDefault}}
 		dependenciesConfig.SetVCenterFromVCenterSimulator(vCenterSimulator)
 
-		if envVar.Spec.VMOperatorDependencies != nil {
+		if spec.VMOperatorDependencies != nil {
 			if err := r.Client.Get(ctx, client.ObjectKey{
-				Namespace: envVar.Spec.VMOperatorDependencies.Namespace,
-				Name:      envVar.Spec.VMOperatorDependencies.Name,
+				Namespace: spec.VMOperatorDependencies.Namespace,
+				Name:      spec.VMOperatorDependencies.Name,
 			}, dependenciesConfig); err != nil {
-				return ctrl.Result{}, errors.Wrapf(err, "failed to get VMOperatorDependencies")
+				return nil, errors.Wrapf(err, "failed to get VMOperatorDependencies")
 			}
 		}
 
 		for k, v := range vmOperatorDependenciesSupervisorVariables(dependenciesConfig) {
-			envVar.Status.Variables[k] = v
+			variables[k] = v
 		}
 
-		// variables for supervisor mode derived from envVar.Spec.Cluster
-		for k, v := range clusterEnvVarSpecSupervisorVariables(&envVar.Spec.Cluster) {
-			envVar.Status.Variables[k] = v
+		// variables for supervisor mode derived from spec.Cluster
+		for k, v := range clusterEnvVarSpecSupervisorVariables(&spec.Cluster) {
+			variables[k] = v
 		}
-		return ctrl.Result{}, nil
+		return variables, nil
 	}
 
 	// variables for govmomi mode derived from the vCenterSimulator
 	for k, v := range vCenterSimulatorCommonVariables(vCenterSimulator) {
-		envVar.Status.Variables[k] = v
+		variables[k] = v
 	}
 
-	// variables for govmomi mode derived from envVar.Spec.Cluster
-	replaceGovmomi, err := clusterEnvVarSpecGovmomiVariables(ctx, &envVar.Spec.Cluster, envVar.Spec.UseMOID, envVar.Status.Variables)
+	// variables for govmomi mode derived from spec.Cluster
+	replaceGovmomi, err := clusterEnvVarSpecGovmomiVariables(ctx, &spec.Cluster, spec.UseMOID, variables)
 	if err != nil {
-		return ctrl.Result{}, errors.Wrapf(err, "error getting govmomi vars")
+		return nil, errors.Wrapf(err, "error getting govmomi vars")
 	}
 	for k, v := range replaceGovmomi {
-		envVar.Status.Variables[k] = v
+		variables[k] = v
 	}
-	return ctrl.Result{}, nil
+	return variables, nil
 }
 
 // vCenterSimulatorSupervisorVariables returns name/value pairs for a VCenterSimulator to be used for clusterctl templates when testing both in supervisor and govmomi mode.
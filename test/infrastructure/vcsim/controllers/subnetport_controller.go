@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	nsxvpcv1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/vpc/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+type SubnetPortReconciler struct {
+	Client client.Client
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+}
+
+// +kubebuilder:rbac:groups=crd.nsx.vmware.com,resources=subnetports,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=crd.nsx.vmware.com,resources=subnetports/status,verbs=get;update;patch
+
+func (r *SubnetPortReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Fetch the SubnetPort instance
+	subnetPort := &nsxvpcv1.SubnetPort{}
+	if err := r.Client.Get(ctx, req.NamespacedName, subnetPort); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !subnetPort.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// Initialize the patch helper
+	patchHelper, err := patch.NewHelper(subnetPort, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Always attempt to Patch the SubnetPort object and status after each reconciliation.
+	defer func() {
+		if err := patchHelper.Patch(ctx, subnetPort); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	conditions, requeueAfter := reconcileNSXVPCReadyCondition(subnetPort.Status.Conditions, time.Now())
+	subnetPort.Status.Conditions = conditions
+	if requeueAfter > 0 {
+		log.Info("Reconciling SubnetPort status simulating nsx-operator provisioning the port")
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+
+	if subnetPort.Status.Attachment.ID == "" {
+		subnetPort.Status.Attachment.ID = string(subnetPort.UID)
+
+		// NOTE: we are not setting subnetPort.Status.NetworkInterfaceConfig.IPAddresses because the IPs of the VMs are assigned by vcsim.
+
+		log.Info("Reconciling SubnetPort status simulating successful nsx-operator reconcile")
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager will add watches for this controller.
+func (r *SubnetPortReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	predicateLog := ctrl.LoggerFrom(ctx).WithValues("controller", "subnetport")
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		For(&nsxvpcv1.SubnetPort{}).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(mgr.GetScheme(), predicateLog, r.WatchFilterValue)).
+		Complete(r)
+
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+	return nil
+}
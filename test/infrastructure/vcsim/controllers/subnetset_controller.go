@@ -0,0 +1,154 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	nsxvpcv1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/vpc/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+const (
+	// nsxVPCProvisioningDelay is the time SubnetSets and SubnetPorts are reported as not ready
+	// before being reported as ready, simulating the provisioning of the subnets in NSX.
+	nsxVPCProvisioningDelay = 2 * time.Second
+
+	// nsxVPCNotReadyReason is the reason of the Ready condition of SubnetSets and SubnetPorts which are being provisioned.
+	nsxVPCNotReadyReason = "Provisioning"
+
+	// NOTE: the subnets of the SubnetSets don't really matter, as the IPs of the VMs are assigned by vcsim.
+
+	subnetSetNetworkAddress = "10.246.0.0/24"
+	subnetSetGatewayAddress = "10.246.0.1"
+)
+
+type SubnetSetReconciler struct {
+	Client client.Client
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+}
+
+// +kubebuilder:rbac:groups=crd.nsx.vmware.com,resources=subnetsets,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=crd.nsx.vmware.com,resources=subnetsets/status,verbs=get;update;patch
+
+func (r *SubnetSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Fetch the SubnetSet instance
+	subnetSet := &nsxvpcv1.SubnetSet{}
+	if err := r.Client.Get(ctx, req.NamespacedName, subnetSet); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !subnetSet.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// Initialize the patch helper
+	patchHelper, err := patch.NewHelper(subnetSet, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Always attempt to Patch the SubnetSet object and status after each reconciliation.
+	defer func() {
+		if err := patchHelper.Patch(ctx, subnetSet); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	conditions, requeueAfter := reconcileNSXVPCReadyCondition(subnetSet.Status.Conditions, time.Now())
+	subnetSet.Status.Conditions = conditions
+	if requeueAfter > 0 {
+		log.Info("Reconciling SubnetSet status simulating nsx-operator provisioning the subnets")
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+
+	if len(subnetSet.Status.Subnets) == 0 {
+		subnetSet.Status.Subnets = []nsxvpcv1.SubnetInfo{
+			{
+				NetworkAddresses: []string{subnetSetNetworkAddress},
+				GatewayAddresses: []string{subnetSetGatewayAddress},
+			},
+		}
+		log.Info("Reconciling SubnetSet status simulating successful nsx-operator reconcile")
+	}
+	return ctrl.Result{}, nil
+}
+
+// reconcileNSXVPCReadyCondition fakes the transitions of the Ready condition of a SubnetSet or SubnetPort:
+// it is first reported as not ready, then as ready once the provisioning delay has passed.
+// It returns the time after which the condition has to be reconciled again, if it is not ready yet.
+func reconcileNSXVPCReadyCondition(conditions []nsxvpcv1.Condition, now time.Time) ([]nsxvpcv1.Condition, time.Duration) {
+	for i, condition := range conditions {
+		if condition.Type != nsxvpcv1.Ready {
+			continue
+		}
+		if condition.Status == corev1.ConditionTrue {
+			return conditions, 0
+		}
+		if readyAt := condition.LastTransitionTime.Add(nsxVPCProvisioningDelay); now.Before(readyAt) {
+			return conditions, readyAt.Sub(now)
+		}
+		conditions[i] = nsxvpcv1.Condition{
+			Type:               nsxvpcv1.Ready,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(now),
+		}
+		return conditions, 0
+	}
+
+	conditions = append(conditions, nsxvpcv1.Condition{
+		Type:               nsxvpcv1.Ready,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.NewTime(now),
+		Reason:             nsxVPCNotReadyReason,
+		Message:            "Simulating nsx-operator provisioning",
+	})
+	return conditions, nsxVPCProvisioningDelay
+}
+
+// SetupWithManager will add watches for this controller.
+func (r *SubnetSetReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	predicateLog := ctrl.LoggerFrom(ctx).WithValues("controller", "subnetset")
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		For(&nsxvpcv1.SubnetSet{}).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(mgr.GetScheme(), predicateLog, r.WatchFilterValue)).
+		Complete(r)
+
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	nsxvpcv1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/vpc/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_Reconcile_SubnetSet(t *testing.T) {
+	g := NewWithT(t)
+
+	subnetSet := &nsxvpcv1.SubnetSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "foo",
+			Name:      "bar",
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(subnetSet).WithStatusSubresource(subnetSet).Build()
+
+	r := SubnetSetReconciler{
+		Client: c,
+	}
+
+	// First reconcile should report the SubnetSet as being provisioned.
+	res, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(subnetSet)})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(BeNumerically(">", 0))
+
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(subnetSet), subnetSet)).To(Succeed())
+	g.Expect(subnetSet.Status.Conditions).To(HaveLen(1))
+	g.Expect(subnetSet.Status.Conditions[0].Type).To(Equal(nsxvpcv1.Ready))
+	g.Expect(subnetSet.Status.Conditions[0].Status).To(Equal(corev1.ConditionFalse))
+	g.Expect(subnetSet.Status.Conditions[0].Reason).To(Equal(nsxVPCNotReadyReason))
+	g.Expect(subnetSet.Status.Subnets).To(BeEmpty())
+
+	// Simulate the provisioning delay being passed.
+	subnetSet.Status.Conditions[0].LastTransitionTime = metav1.NewTime(time.Now().Add(-nsxVPCProvisioningDelay))
+	g.Expect(c.Status().Update(ctx, subnetSet)).To(Succeed())
+
+	// Second reconcile should report the SubnetSet as ready.
+	res, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(subnetSet)})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(res.IsZero()).To(BeTrue())
+
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(subnetSet), subnetSet)).To(Succeed())
+	g.Expect(subnetSet.Status.Conditions).To(HaveLen(1))
+	g.Expect(subnetSet.Status.Conditions[0].Status).To(Equal(corev1.ConditionTrue))
+	g.Expect(subnetSet.Status.Subnets).To(HaveLen(1))
+	g.Expect(subnetSet.Status.Subnets[0].NetworkAddresses).To(ConsistOf(subnetSetNetworkAddress))
+	g.Expect(subnetSet.Status.Subnets[0].GatewayAddresses).To(ConsistOf(subnetSetGatewayAddress))
+}
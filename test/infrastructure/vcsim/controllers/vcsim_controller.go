@@ -30,6 +30,8 @@ import (
 	_ "github.com/dougm/pretty" // NOTE: this is required to add commands vm.* to cli.Run
 	"github.com/pkg/errors"
 	"github.com/vmware/govmomi/cli"
+	_ "github.com/vmware/govmomi/cli/cluster/group"    // NOTE: this is required to add commands cluster.group.* to cli.Run
+	_ "github.com/vmware/govmomi/cli/cluster/rule"     // NOTE: this is required to add commands cluster.rule.* to cli.Run
 	_ "github.com/vmware/govmomi/cli/tags"             // NOTE: this is required to add commands tags.* to cli.Run
 	_ "github.com/vmware/govmomi/cli/tags/association" // NOTE: this is required to add commands tags.attach.* to cli.Run
 	_ "github.com/vmware/govmomi/cli/tags/category"    // NOTE: this is required to add commands tags.category.* to cli.Run
@@ -195,8 +197,8 @@ func (r *VCenterSimulatorReconciler) reconcileNormal(ctx context.Context, vCente
 		}
 
 		if !r.SupervisorMode {
-			// Create and attach tags for failure domain tests.
-			if err := createGovmomiFailureDomainTags(vCenterSimulator); err != nil {
+			// Create and attach tags, host groups, VM groups and VM-Host affinity rules for failure domain tests.
+			if err := createGovmomiFailureDomains(ctx, vCenterSimulator, model); err != nil {
 				return err
 			}
 		}
@@ -267,18 +269,60 @@ func createVMTemplates(ctx context.Context, vCenterSimulator *vcsimv1.VCenterSim
 	return nil
 }
 
-func createGovmomiFailureDomainTags(vCenterSimulator *vcsimv1.VCenterSimulator) error {
+func createGovmomiFailureDomains(ctx context.Context, vCenterSimulator *vcsimv1.VCenterSimulator, model *simulator.Model) error {
+	log := ctrl.LoggerFrom(ctx)
 	govcURLArg := fmt.Sprintf("-u=https://%s:%s@%s/sdk", vCenterSimulator.Status.Username, vCenterSimulator.Status.Password, vCenterSimulator.Status.Host)
 
-	datacenterName := vcsimhelpers.DatacenterName(0)
-	computeClusterName := vcsimhelpers.ClusterName(0, 0)
+	regionTagCategory := "k8s-region"
+	zoneTagCategory := "k8s-zone"
+	hostGroups := 0
+	if vCenterSimulator.Spec.Model != nil && vCenterSimulator.Spec.Model.FailureDomains != nil {
+		failureDomains := vCenterSimulator.Spec.Model.FailureDomains
+		regionTagCategory = ptr.Deref(failureDomains.RegionTagCategory, regionTagCategory)
+		zoneTagCategory = ptr.Deref(failureDomains.ZoneTagCategory, zoneTagCategory)
+		hostGroups = int(ptr.Deref(failureDomains.HostGroup, 0))
+	}
+
 	commands := [][]string{
-		{"tags.category.create", "-k=true", govcURLArg, "-t=Datacenter", "k8s-region"},
-		{"tags.category.create", "-k=true", govcURLArg, "k8s-zone"},
-		{"tags.create", "-k=true", govcURLArg, "-c", "k8s-region", datacenterName},
-		{"tags.create", "-k=true", govcURLArg, "-c", "k8s-zone", computeClusterName},
-		{"tags.attach", "-k=true", govcURLArg, "-c", "k8s-region", datacenterName, "/" + datacenterName},
-		{"tags.attach", "-k=true", govcURLArg, "-c", "k8s-zone", computeClusterName, "/" + datacenterName + "/host/" + computeClusterName},
+		{"tags.category.create", "-k=true", govcURLArg, "-t=Datacenter", regionTagCategory},
+		{"tags.category.create", "-k=true", govcURLArg, zoneTagCategory},
+	}
+
+	for dc := 0; dc < model.Datacenter; dc++ {
+		datacenterName := vcsimhelpers.DatacenterName(dc)
+		commands = append(commands,
+			[]string{"tags.create", "-k=true", govcURLArg, "-c", regionTagCategory, datacenterName},
+			[]string{"tags.attach", "-k=true", govcURLArg, "-c", regionTagCategory, datacenterName, "/" + datacenterName},
+		)
+
+		for c := 0; c < model.Cluster; c++ {
+			computeClusterName := vcsimhelpers.ClusterName(dc, c)
+			computeClusterPath := vcsimhelpers.ClusterPath(dc, c)
+			commands = append(commands,
+				[]string{"tags.create", "-k=true", govcURLArg, "-c", zoneTagCategory, computeClusterName},
+				[]string{"tags.attach", "-k=true", govcURLArg, "-c", zoneTagCategory, computeClusterName, computeClusterPath},
+			)
+
+			// Distribute the hosts of the cluster round-robin across its host groups.
+			hostGroupHosts := make([][]string, hostGroups)
+			for h := 0; h < model.ClusterHost && hostGroups > 0; h++ {
+				hostGroupHosts[h%hostGroups] = append(hostGroupHosts[h%hostGroups], vcsimhelpers.HostName(dc, c, h))
+			}
+
+			for hg := 0; hg < hostGroups; hg++ {
+				hostGroupName := vcsimhelpers.HostGroupName(dc, c, hg)
+				vmGroupName := vcsimhelpers.VMGroupName(dc, c, hg)
+				commands = append(commands,
+					append([]string{"cluster.group.create", "-k=true", govcURLArg, "-cluster", computeClusterPath, "-name", hostGroupName, "-host"}, hostGroupHosts[hg]...),
+					[]string{"cluster.group.create", "-k=true", govcURLArg, "-cluster", computeClusterPath, "-name", vmGroupName, "-vm"},
+					[]string{"cluster.rule.create", "-k=true", govcURLArg, "-cluster", computeClusterPath, "-name", vcsimhelpers.VMHostRuleName(dc, c, hg), "-enable", "-mandatory", "-vm-host", "-vm-group", vmGroupName, "-host-affine-group", hostGroupName},
+					[]string{"tags.create", "-k=true", govcURLArg, "-c", zoneTagCategory, hostGroupName},
+				)
+				for _, hostName := range hostGroupHosts[hg] {
+					commands = append(commands, []string{"tags.attach", "-k=true", govcURLArg, "-c", zoneTagCategory, hostGroupName, computeClusterPath + "/" + hostName})
+				}
+			}
+		}
 	}
 
 	for _, command := range commands {
@@ -288,6 +332,7 @@ func createGovmomiFailureDomainTags(vCenterSimulator *vcsimv1.VCenterSimulator)
 		}
 	}
 
+	log.Info("Created failure domains", "datacenters", model.Datacenter, "clusters", model.Cluster, "hostGroups", hostGroups)
 	return nil
 }
 
@@ -22,13 +22,16 @@ import (
 	_ "github.com/dougm/pretty"
 	. "github.com/onsi/gomega"
 	_ "github.com/vmware/govmomi/cli/vm"
+	vim25types "github.com/vmware/govmomi/vim25/types"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	vcsimhelpers "sigs.k8s.io/cluster-api-provider-vsphere/internal/test/helpers/vcsim"
 	"sigs.k8s.io/cluster-api-provider-vsphere/pkg/session"
 	vcsimv1 "sigs.k8s.io/cluster-api-provider-vsphere/test/infrastructure/vcsim/api/v1alpha1"
 )
@@ -115,3 +118,95 @@ func Test_Reconcile_Server(t *testing.T) {
 		g.Expect(r.vcsimInstances).ToNot(HaveKey(key))
 	}()
 }
+
+func Test_Reconcile_ServerWithFailureDomains(t *testing.T) {
+	g := NewWithT(t)
+
+	vCenterSimulator := &vcsimv1.VCenterSimulator{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "bar",
+			Finalizers: []string{
+				vcsimv1.VCenterFinalizer, // Adding this to move past the first reconcile
+			},
+		},
+		Spec: vcsimv1.VCenterSimulatorSpec{
+			Model: &vcsimv1.VCenterSimulatorModel{
+				Cluster:     ptr.To[int32](2),
+				ClusterHost: ptr.To[int32](3),
+				FailureDomains: &vcsimv1.VCenterSimulatorFailureDomains{
+					HostGroup: ptr.To[int32](2),
+				},
+			},
+		},
+	}
+
+	crclient := fake.NewClientBuilder().WithObjects(vCenterSimulator).WithStatusSubresource(vCenterSimulator).WithScheme(scheme).Build()
+	r := &VCenterSimulatorReconciler{
+		Client: crclient,
+	}
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(vCenterSimulator)})
+	g.Expect(err).ToNot(HaveOccurred())
+	defer func() {
+		g.Expect(crclient.Delete(ctx, vCenterSimulator)).To(Succeed())
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(vCenterSimulator)})
+		g.Expect(err).ToNot(HaveOccurred())
+	}()
+
+	g.Expect(crclient.Get(ctx, client.ObjectKeyFromObject(vCenterSimulator), vCenterSimulator)).To(Succeed())
+
+	params := session.NewParams().
+		WithServer(vCenterSimulator.Status.Host).
+		WithThumbprint(vCenterSimulator.Status.Thumbprint).
+		WithUserInfo(vCenterSimulator.Status.Username, vCenterSimulator.Status.Password)
+
+	s, err := session.GetOrCreate(ctx, params)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Check the groups and the rules of the second cluster.
+	ccr, err := s.Finder.ClusterComputeResource(ctx, vcsimhelpers.ClusterPath(0, 1))
+	g.Expect(err).ToNot(HaveOccurred())
+	clusterConfig, err := ccr.Configuration(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	groups := map[string]int{}
+	for _, group := range clusterConfig.Group {
+		hostGroup, ok := group.(*vim25types.ClusterHostGroup)
+		if !ok {
+			groups[group.GetClusterGroupInfo().Name] = 0
+			continue
+		}
+		groups[hostGroup.Name] = len(hostGroup.Host)
+	}
+	g.Expect(groups).To(Equal(map[string]int{
+		vcsimhelpers.HostGroupName(0, 1, 0): 2,
+		vcsimhelpers.HostGroupName(0, 1, 1): 1,
+		vcsimhelpers.VMGroupName(0, 1, 0):   0,
+		vcsimhelpers.VMGroupName(0, 1, 1):   0,
+	}))
+
+	rules := map[string]string{}
+	for _, rule := range clusterConfig.Rule {
+		if vmHostRule, ok := rule.(*vim25types.ClusterVmHostRuleInfo); ok {
+			rules[vmHostRule.VmGroupName] = vmHostRule.AffineHostGroupName
+		}
+	}
+	g.Expect(rules).To(Equal(map[string]string{
+		vcsimhelpers.VMGroupName(0, 1, 0): vcsimhelpers.HostGroupName(0, 1, 0),
+		vcsimhelpers.VMGroupName(0, 1, 1): vcsimhelpers.HostGroupName(0, 1, 1),
+	}))
+
+	// Check the zone tags of the second cluster and of its hosts.
+	clusterTags, err := s.TagManager.GetAttachedTags(ctx, ccr.Reference())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(clusterTags).To(HaveLen(1))
+	g.Expect(clusterTags[0].Name).To(Equal(vcsimhelpers.ClusterName(0, 1)))
+
+	host, err := s.Finder.HostSystem(ctx, vcsimhelpers.ClusterPath(0, 1)+"/"+vcsimhelpers.HostName(0, 1, 1))
+	g.Expect(err).ToNot(HaveOccurred())
+	hostTags, err := s.TagManager.GetAttachedTags(ctx, host.Reference())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(hostTags).To(HaveLen(1))
+	g.Expect(hostTags[0].Name).To(Equal(vcsimhelpers.HostGroupName(0, 1, 1)))
+}
@@ -18,6 +18,7 @@ package controllers
 
 import (
 	"context"
+	"sync"
 
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -37,10 +38,19 @@ type VMOperatorDependenciesReconciler struct {
 
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
+
+	// SetupNSXVPCReconcilers sets up the reconcilers faking nsx-operator behaviour for NSX-VPC objects.
+	// It is called once the NSX-VPC CRDs are installed, because those reconcilers can't start watching
+	// SubnetSets and SubnetPorts before then.
+	SetupNSXVPCReconcilers func(ctx context.Context) error
+
+	nsxVPCReconcilersLock  sync.Mutex
+	nsxVPCReconcilersSetup bool
 }
 
 // +kubebuilder:rbac:groups=vcsim.infrastructure.cluster.x-k8s.io,resources=vmoperatordependencies,verbs=get;list;watch;patch
 // +kubebuilder:rbac:groups=vcsim.infrastructure.cluster.x-k8s.io,resources=vmoperatordependencies/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch;create
 
 func (r *VMOperatorDependenciesReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
 	// Fetch the VMOperatorDependencies instance
@@ -84,10 +94,32 @@ func (r *VMOperatorDependenciesReconciler) reconcileNormal(ctx context.Context,
 		return err
 	}
 
+	if vmOperatorDependencies.Spec.NetworkProvider == vcsimv1.NSXVPCNetworkProvider {
+		if err := r.setupNSXVPCReconcilers(ctx); err != nil {
+			vmOperatorDependencies.Status.Ready = false
+			return err
+		}
+	}
+
 	vmOperatorDependencies.Status.Ready = true
 	return nil
 }
 
+func (r *VMOperatorDependenciesReconciler) setupNSXVPCReconcilers(ctx context.Context) error {
+	r.nsxVPCReconcilersLock.Lock()
+	defer r.nsxVPCReconcilersLock.Unlock()
+
+	if r.nsxVPCReconcilersSetup || r.SetupNSXVPCReconcilers == nil {
+		return nil
+	}
+
+	if err := r.SetupNSXVPCReconcilers(ctx); err != nil {
+		return errors.Wrap(err, "failed to setup NSX-VPC reconcilers")
+	}
+	r.nsxVPCReconcilersSetup = true
+	return nil
+}
+
 func (r *VMOperatorDependenciesReconciler) reconcileDelete(_ context.Context, _ *vcsimv1.VMOperatorDependencies) (ctrl.Result, error) {
 	// TODO: cleanup dependencies
 	return ctrl.Result{}, nil
@@ -28,11 +28,13 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/spf13/pflag"
+	nsxvpcv1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/vpc/v1alpha1"
 	vmoprv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -81,6 +83,7 @@ var (
 	restConfigQPS               float32
 	restConfigBurst             int
 	healthAddr                  string
+	envsubstAddr                string
 	managerOptions              = flags.ManagerOptions{}
 	logOptions                  = logs.NewOptions()
 	// vcsim specific flags.
@@ -90,6 +93,7 @@ var (
 	controlPlaneEndpointConcurrency   int
 	envsubstConcurrency               int
 	vmOperatorDependenciesConcurrency int
+	nsxVPCConcurrency                 int
 )
 
 func init() {
@@ -102,6 +106,8 @@ func init() {
 	_ = vmoprv1.AddToScheme(scheme)
 	_ = storagev1.AddToScheme(scheme)
 	_ = vmwarev1.AddToScheme(scheme)
+	_ = apiextensionsv1.AddToScheme(scheme)
+	_ = nsxvpcv1.AddToScheme(scheme)
 
 	// scheme used for operating in memory.
 	_ = corev1.AddToScheme(inmemoryScheme)
@@ -156,6 +162,9 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&vmOperatorDependenciesConcurrency, "vm-operator-dependencies-concurrency", 10,
 		"Number of VMOperatorDependencies to process simultaneously")
 
+	fs.IntVar(&nsxVPCConcurrency, "nsx-vpc-concurrency", 10,
+		"Number of SubnetSets and SubnetPorts to process simultaneously")
+
 	fs.DurationVar(&syncPeriod, "sync-period", 10*time.Minute,
 		"The minimum interval at which watched resources are reconciled (e.g. 15m)")
 
@@ -168,6 +177,9 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&healthAddr, "health-addr", ":9440",
 		"The address the health endpoint binds to.")
 
+	fs.StringVar(&envsubstAddr, "envsubst-bind-address", ":8085",
+		"The address the envsubst HTTP endpoint binds to. Set to an empty string to disable it.")
+
 	flags.AddManagerOptions(fs, &managerOptions)
 
 	feature.MutableGates.AddFlag(fs)
@@ -360,6 +372,20 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager, supervisorMode bool
 		if err := (&controllers.VMOperatorDependenciesReconciler{
 			Client:           mgr.GetClient(),
 			WatchFilterValue: watchFilterValue,
+			// NOTE: NSX-VPC reconcilers are setup only when a VMOperatorDependencies requires the NSX-VPC
+			// network provider, because the NSX-VPC CRDs are installed only at that stage.
+			SetupNSXVPCReconcilers: func(ctx context.Context) error {
+				if err := (&controllers.SubnetSetReconciler{
+					Client:           mgr.GetClient(),
+					WatchFilterValue: watchFilterValue,
+				}).SetupWithManager(ctx, mgr, concurrency(nsxVPCConcurrency)); err != nil {
+					return err
+				}
+				return (&controllers.SubnetPortReconciler{
+					Client:           mgr.GetClient(),
+					WatchFilterValue: watchFilterValue,
+				}).SetupWithManager(ctx, mgr, concurrency(nsxVPCConcurrency))
+			},
 		}).SetupWithManager(ctx, mgr, concurrency(vmOperatorDependenciesConcurrency)); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "VMOperatorDependenciesReconciler")
 			os.Exit(1)
@@ -376,15 +402,26 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager, supervisorMode bool
 		}
 	}
 
-	if err := (&controllers.EnvVarReconciler{
+	envVarReconciler := &controllers.EnvVarReconciler{
 		Client:           mgr.GetClient(),
 		SupervisorMode:   supervisorMode,
 		PodIP:            podIP,
 		WatchFilterValue: watchFilterValue,
-	}).SetupWithManager(ctx, mgr, concurrency(envsubstConcurrency)); err != nil {
+	}
+	if err := envVarReconciler.SetupWithManager(ctx, mgr, concurrency(envsubstConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "EnvVarReconciler")
 		os.Exit(1)
 	}
+
+	if envsubstAddr != "" {
+		if err := mgr.Add(&controllers.EnvSubstServer{
+			EnvVarReconciler: envVarReconciler,
+			BindAddress:      envsubstAddr,
+		}); err != nil {
+			setupLog.Error(err, "unable to create envsubst server")
+			os.Exit(1)
+		}
+	}
 }
 
 func setupWebhooks(_ ctrl.Manager, _ bool) {